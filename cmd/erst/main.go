@@ -21,6 +21,7 @@ func main() {
 	go checker.CheckForUpdates()
 
 	if err := cmd.Execute(); err != nil {
+		cmd.PrintError(err)
 		os.Exit(1)
 	}
 }
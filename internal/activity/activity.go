@@ -0,0 +1,100 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package activity records an append-only log of erst command invocations,
+// for machines shared by several people during incident response.
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TargetSessionDB is the activity_log value that records entries in the
+// activity_log table of the session database instead of a file
+const TargetSessionDB = "db"
+
+// Results recorded in Entry.Result
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+)
+
+// Entry is one command invocation
+type Entry struct {
+	Time    time.Time `json:"time"`
+	User    string    `json:"user"`
+	Host    string    `json:"host"`
+	Command string    `json:"command"`
+	// Args are the positional arguments and the flags given, with the values
+	// of secret flags redacted
+	Args       []string `json:"args,omitempty"`
+	TxHash     string   `json:"tx_hash,omitempty"`
+	Network    string   `json:"network,omitempty"`
+	Result     string   `json:"result"`
+	Error      string   `json:"error,omitempty"`
+	DurationMS int64    `json:"duration_ms"`
+	Version    string   `json:"erst_version,omitempty"`
+}
+
+// Sink stores entries. Sinks only ever append.
+type Sink interface {
+	Append(ctx context.Context, e Entry) error
+	Close() error
+}
+
+// CurrentUser returns the name of the user running erst, falling back to
+// $USER when the account cannot be looked up
+func CurrentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// fileSink appends entries to a file as JSON lines
+type fileSink struct {
+	f *os.File
+}
+
+// OpenFile opens the activity log at path for appending, creating it
+// readable by its owner only
+func OpenFile(path string) (Sink, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		// Paths from config files and environment variables are not
+		// expanded by a shell
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+		path = filepath.Join(home, rest)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open activity log: %w", err)
+	}
+	return &fileSink{f: f}, nil
+}
+
+// Append writes e as one line. Each entry is a single write, so entries of
+// concurrent erst processes do not interleave.
+func (s *fileSink) Append(_ context.Context, e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode activity entry: %w", err)
+	}
+	if _, err := s.f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write activity log: %w", err)
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	return s.f.Close()
+}
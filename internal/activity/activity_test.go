@@ -0,0 +1,56 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package activity
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSink_Appends(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "activity.log")
+
+	for _, result := range []string{ResultOK, ResultError} {
+		sink, err := OpenFile(path)
+		if err != nil {
+			t.Fatalf("OpenFile: %v", err)
+		}
+		if err := sink.Append(ctx, Entry{Time: time.Now(), User: "alice", Command: "erst debug", Result: result}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+		sink.Close()
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("activity log mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var results []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		results = append(results, e.Result)
+	}
+	if len(results) != 2 || results[0] != ResultOK || results[1] != ResultError {
+		t.Errorf("results = %v, want both entries in order", results)
+	}
+}
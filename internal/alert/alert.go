@@ -0,0 +1,355 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package alert applies user-defined alerting rules to the failures seen by
+// erst watch, erst daemon and erst canary. A rule matches failures by
+// contract, error and event topic, fires once enough of them happen within a
+// time window, and runs its actions: a webhook, a command or a log line.
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/webhook"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"gopkg.in/yaml.v3"
+)
+
+// Action types
+const (
+	ActionWebhook = "webhook"
+	ActionCommand = "command"
+	ActionLog     = "log"
+)
+
+// DefaultCommandTimeout bounds a single command action
+const DefaultCommandTimeout = 30 * time.Second
+
+// File is the layout of a rules file
+type File struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule selects the failures to alert on. Every condition that is set must
+// hold; a rule with none matches every failure.
+type Rule struct {
+	Name string `yaml:"name"`
+	// Contract is a contract ID the failed transaction must involve
+	Contract string `yaml:"contract"`
+	// Error is a regular expression the failure's error must match
+	Error string `yaml:"error"`
+	// Topic is a topic one of the failure's diagnostic events must carry
+	Topic string `yaml:"topic"`
+	// Threshold is how many matching failures fire the rule (default 1),
+	// counted over the last Window, or since it last fired when Window is 0
+	Threshold int           `yaml:"threshold"`
+	Window    time.Duration `yaml:"window"`
+	Actions   []Action      `yaml:"actions"`
+}
+
+// Action is what a rule does when it fires
+type Action struct {
+	Type string `yaml:"type"` // "webhook", "command" or "log"
+	// URL and Format (json, slack or discord; default json) configure a
+	// webhook action
+	URL    string `yaml:"url"`
+	Format string `yaml:"format"`
+	// Command is run with the Alert as JSON on stdin
+	Command []string      `yaml:"command"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Failure is one failed transaction seen by a watcher or the daemon
+type Failure struct {
+	Report webhook.ReportData
+	// Contracts are the IDs of the contracts the transaction involves
+	Contracts []string
+}
+
+// Alert is what a fired rule reports: the failure that fired it and how many
+// matched
+type Alert struct {
+	Rule      string    `json:"rule"`
+	Count     int       `json:"count"`
+	Window    string    `json:"window,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	Network   string    `json:"network,omitempty"`
+	TxHash    string    `json:"tx_hash"`
+	Ledger    uint32    `json:"ledger,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	SessionID string    `json:"session_id,omitempty"`
+	Contracts []string  `json:"contracts,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Load reads a YAML rules file
+func Load(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert rules: %w", err)
+	}
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse alert rules %s: %w", path, err)
+	}
+	if len(file.Rules) == 0 {
+		return nil, fmt.Errorf("alert rules %s define no rules", path)
+	}
+	return file.Rules, nil
+}
+
+type compiledRule struct {
+	Rule
+	contract string
+	errorRe  *regexp.Regexp
+	clients  []*webhook.Client
+	hits     []time.Time
+}
+
+// Engine evaluates failures against a set of rules. It is safe for
+// concurrent use.
+type Engine struct {
+	mu    sync.Mutex
+	rules []*compiledRule
+	wg    sync.WaitGroup
+	now   func() time.Time
+}
+
+// NewEngine checks and compiles rules
+func NewEngine(rules []Rule) (*Engine, error) {
+	e := &Engine{now: time.Now}
+	for i, r := range rules {
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		c := &compiledRule{Rule: r}
+		if c.Threshold < 0 || c.Window < 0 {
+			return nil, fmt.Errorf("%s: threshold and window must not be negative", r.Name)
+		}
+		if c.Threshold == 0 {
+			c.Threshold = 1
+		}
+		if r.Contract != "" {
+			c.contract = normalizeContract(r.Contract)
+			if c.contract == "" {
+				return nil, fmt.Errorf("%s: invalid contract %q", r.Name, r.Contract)
+			}
+		}
+		if r.Error != "" {
+			re, err := regexp.Compile(r.Error)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid error pattern: %w", r.Name, err)
+			}
+			c.errorRe = re
+		}
+		if len(r.Actions) == 0 {
+			return nil, fmt.Errorf("%s: no actions", r.Name)
+		}
+		for _, a := range r.Actions {
+			switch a.Type {
+			case ActionWebhook:
+				format := webhook.WebhookType(strings.ToLower(a.Format))
+				switch format {
+				case "":
+					format = webhook.JSONWebhook
+				case webhook.JSONWebhook, webhook.SlackWebhook, webhook.DiscordWebhook:
+				default:
+					return nil, fmt.Errorf("%s: webhook format must be json, slack or discord, got %q", r.Name, a.Format)
+				}
+				client, err := webhook.NewClient(webhook.Config{Type: format, URL: a.URL, Retries: 2})
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", r.Name, err)
+				}
+				c.clients = append(c.clients, client)
+			case ActionCommand:
+				if len(a.Command) == 0 {
+					return nil, fmt.Errorf("%s: command action needs a command", r.Name)
+				}
+			case ActionLog:
+			default:
+				return nil, fmt.Errorf("%s: unknown action type %q (webhook, command or log)", r.Name, a.Type)
+			}
+		}
+		e.rules = append(e.rules, c)
+	}
+	return e, nil
+}
+
+// Evaluate records a failure against every rule and runs the actions of the
+// rules it fires, in the background. It returns the fired alerts.
+func (e *Engine) Evaluate(ctx context.Context, f Failure) []Alert {
+	contracts := make(map[string]bool, len(f.Contracts))
+	for _, id := range f.Contracts {
+		if id = normalizeContract(id); id != "" {
+			contracts[id] = true
+		}
+	}
+	for _, ev := range f.Report.DiagnosticEvents {
+		if ev.ContractID != nil {
+			if id := normalizeContract(*ev.ContractID); id != "" {
+				contracts[id] = true
+			}
+		}
+	}
+
+	e.mu.Lock()
+	now := e.now()
+	var fired []Alert
+	var actions []*compiledRule
+	for _, r := range e.rules {
+		if !r.matches(f, contracts) {
+			continue
+		}
+		if r.Window > 0 {
+			kept := r.hits[:0]
+			for _, t := range r.hits {
+				if now.Sub(t) < r.Window {
+					kept = append(kept, t)
+				}
+			}
+			r.hits = kept
+		}
+		r.hits = append(r.hits, now)
+		if len(r.hits) < r.Threshold {
+			continue
+		}
+
+		alert := Alert{
+			Rule:      r.Name,
+			Count:     len(r.hits),
+			Source:    f.Report.Source,
+			Network:   f.Report.Network,
+			TxHash:    f.Report.TxHash,
+			Ledger:    f.Report.Ledger,
+			Error:     f.Report.Error,
+			SessionID: f.Report.SessionID,
+			Timestamp: now,
+		}
+		if r.Window > 0 {
+			alert.Window = r.Window.String()
+		}
+		for id := range contracts {
+			alert.Contracts = append(alert.Contracts, id)
+		}
+		sort.Strings(alert.Contracts)
+		r.hits = nil
+		fired = append(fired, alert)
+		actions = append(actions, r)
+	}
+	e.mu.Unlock()
+
+	for i, r := range actions {
+		e.wg.Add(1)
+		go func(r *compiledRule, alert Alert) {
+			defer e.wg.Done()
+			r.run(ctx, alert, f.Report)
+		}(r, fired[i])
+	}
+	return fired
+}
+
+// Wait blocks until the actions of every fired rule have finished
+func (e *Engine) Wait() {
+	e.wg.Wait()
+}
+
+func (r *compiledRule) matches(f Failure, contracts map[string]bool) bool {
+	if r.contract != "" && !contracts[r.contract] {
+		return false
+	}
+	if r.errorRe != nil && !r.errorRe.MatchString(f.Report.Error) {
+		return false
+	}
+	if r.Topic != "" && !hasTopic(f.Report, r.Topic) {
+		return false
+	}
+	return true
+}
+
+func hasTopic(report webhook.ReportData, topic string) bool {
+	for _, ev := range report.DiagnosticEvents {
+		for _, t := range ev.TopicNames() {
+			if t == topic {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// run performs the rule's actions. Failed actions are logged; they never
+// affect the caller.
+func (r *compiledRule) run(ctx context.Context, alert Alert, report webhook.ReportData) {
+	report.Rule = alert.Rule
+	clients := r.clients
+	for _, a := range r.Actions {
+		switch a.Type {
+		case ActionWebhook:
+			client := clients[0]
+			clients = clients[1:]
+			if err := client.Send(report); err != nil {
+				logger.Logger.Error("Alert webhook failed", "rule", alert.Rule, "error", err)
+			}
+		case ActionCommand:
+			if err := runCommand(ctx, a, alert); err != nil {
+				logger.Logger.Error("Alert command failed", "rule", alert.Rule, "error", err)
+			}
+		case ActionLog:
+			logger.Logger.Warn("Alert", "rule", alert.Rule, "count", alert.Count, "tx", alert.TxHash, "error", alert.Error)
+		}
+	}
+}
+
+func runCommand(ctx context.Context, a Action, alert Alert) error {
+	input, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, a.Command[0], a.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("%s timed out after %s", a.Command[0], timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", a.Command[0], err, msg)
+		}
+		return fmt.Errorf("%s: %w", a.Command[0], err)
+	}
+	return nil
+}
+
+// normalizeContract returns the strkey of a contract ID given as strkey or
+// hex, or "" when it is neither
+func normalizeContract(id string) string {
+	cid, err := rpc.ParseContractID(id)
+	if err != nil {
+		return ""
+	}
+	s, err := strkey.Encode(strkey.VersionByteContract, cid[:])
+	if err != nil {
+		return ""
+	}
+	return s
+}
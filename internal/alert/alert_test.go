@@ -0,0 +1,218 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/webhook"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+const testContract = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+
+func failure(errMsg string, topics ...string) Failure {
+	return Failure{
+		Report: webhook.ReportData{
+			TxHash:           "abc",
+			Status:           "error",
+			Error:            errMsg,
+			DiagnosticEvents: []simulator.DiagnosticEvent{{EventType: "contract", Topics: topics}},
+		},
+		Contracts: []string{testContract},
+	}
+}
+
+func rulesNamed(alerts []Alert) []string {
+	var names []string
+	for _, a := range alerts {
+		names = append(names, a.Rule)
+	}
+	return names
+}
+
+func TestEngineMatch(t *testing.T) {
+	log := []Action{{Type: ActionLog}}
+	engine, err := NewEngine([]Rule{
+		{Name: "contract", Contract: testContract, Actions: log},
+		{Name: "budget", Error: "(?i)budget", Actions: log},
+		{Name: "transfer", Topic: "transfer", Actions: log},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Wait()
+
+	got := rulesNamed(engine.Evaluate(context.Background(), failure("Budget exceeded", "transfer")))
+	if len(got) != 3 {
+		t.Errorf("expected every rule to fire, got %v", got)
+	}
+	got = rulesNamed(engine.Evaluate(context.Background(), failure("panic", "mint")))
+	if len(got) != 1 || got[0] != "contract" {
+		t.Errorf("expected only the contract rule, got %v", got)
+	}
+	f := failure("panic")
+	f.Contracts = nil
+	if got := engine.Evaluate(context.Background(), f); len(got) != 0 {
+		t.Errorf("expected no rules to fire, got %v", rulesNamed(got))
+	}
+}
+
+func TestEngineMatch_TopicXDR(t *testing.T) {
+	engine, err := NewEngine([]Rule{{Name: "transfer", Topic: "transfer", Actions: []Action{{Type: ActionLog}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Wait()
+
+	topic := func(sym string) string {
+		s := xdr.ScSymbol(sym)
+		b64, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &s})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b64
+	}
+	// Topics as the simulator reports them: rendered for display, and as XDR
+	event := func(sym string) Failure {
+		f := failure("panic")
+		f.Report.DiagnosticEvents = []simulator.DiagnosticEvent{{
+			EventType: "contract",
+			Topics:    []string{"Symbol(ScSymbol(StringM(" + sym + ")))"},
+			TopicsXdr: []string{topic(sym)},
+		}}
+		return f
+	}
+	if got := engine.Evaluate(context.Background(), event("transfer")); len(got) != 1 {
+		t.Errorf("expected the topic rule to fire on an XDR topic, got %v", rulesNamed(got))
+	}
+	if got := engine.Evaluate(context.Background(), event("mint")); len(got) != 0 {
+		t.Errorf("expected no rules to fire, got %v", rulesNamed(got))
+	}
+}
+
+func TestEngineThreshold(t *testing.T) {
+	engine, err := NewEngine([]Rule{{Name: "burst", Threshold: 3, Window: time.Minute, Actions: []Action{{Type: ActionLog}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Wait()
+	now := time.Unix(1700000000, 0)
+	engine.now = func() time.Time { return now }
+
+	evaluate := func(after time.Duration) []Alert {
+		now = now.Add(after)
+		return engine.Evaluate(context.Background(), failure("boom"))
+	}
+	if len(evaluate(0)) != 0 || len(evaluate(10*time.Second)) != 0 {
+		t.Fatal("fired below the threshold")
+	}
+	// The first failure has left the window
+	if got := evaluate(55 * time.Second); len(got) != 0 {
+		t.Fatal("counted a failure outside the window")
+	}
+	got := evaluate(time.Second)
+	if len(got) != 1 || got[0].Count != 3 || got[0].Window != "1m0s" {
+		t.Fatalf("expected the rule to fire with 3 failures, got %+v", got)
+	}
+	// Firing starts a new count
+	if len(evaluate(time.Second)) != 0 {
+		t.Error("fired again right after firing")
+	}
+}
+
+func TestEngineActions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("command action uses sh")
+	}
+	var payload webhook.JSONMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	out := filepath.Join(t.TempDir(), "alert.json")
+	engine, err := NewEngine([]Rule{{
+		Name: "page",
+		Actions: []Action{
+			{Type: ActionWebhook, URL: server.URL},
+			{Type: ActionCommand, Command: []string{"sh", "-c", "cat > " + out}},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.Evaluate(context.Background(), failure("boom"))
+	engine.Wait()
+
+	if payload.Rule != "page" || payload.TxHash != "abc" {
+		t.Errorf("unexpected webhook payload %+v", payload)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var alert Alert
+	if err := json.Unmarshal(data, &alert); err != nil {
+		t.Fatal(err)
+	}
+	if alert.Rule != "page" || alert.Error != "boom" || len(alert.Contracts) != 1 || alert.Contracts[0] != testContract {
+		t.Errorf("unexpected command input %+v", alert)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.yaml")
+	yaml := `rules:
+  - name: auth-failures
+    contract: ` + testContract + `
+    error: "Auth"
+    threshold: 5
+    window: 10m
+    actions:
+      - type: webhook
+        url: https://hooks.slack.com/services/T/B/X
+        format: slack
+      - type: command
+        command: ["/usr/local/bin/page", "--team", "contracts"]
+`
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 || rules[0].Window != 10*time.Minute || rules[0].Threshold != 5 || len(rules[0].Actions) != 2 {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+	if _, err := NewEngine(rules); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, bad := range []Rule{
+		{Name: "no actions"},
+		{Name: "bad contract", Contract: "CNOPE", Actions: []Action{{Type: ActionLog}}},
+		{Name: "bad regex", Error: "(", Actions: []Action{{Type: ActionLog}}},
+		{Name: "bad type", Actions: []Action{{Type: "email"}}},
+		{Name: "bad format", Actions: []Action{{Type: ActionWebhook, URL: "https://example.com", Format: "teams"}}},
+		{Name: "no command", Actions: []Action{{Type: ActionCommand}}},
+	} {
+		if _, err := NewEngine([]Rule{bad}); err == nil {
+			t.Errorf("%s: expected an error", bad.Name)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package argfuzz
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/metrics"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Outcome classifies how one simulated argument set ended
+type Outcome string
+
+const (
+	// OutcomeOK is a call that returned normally
+	OutcomeOK Outcome = "ok"
+	// OutcomeContractError is a call the contract rejected with one of its
+	// own errors, the expected result of invalid input
+	OutcomeContractError Outcome = "contract_error"
+	// OutcomeTrap is a call where the WASM trapped, e.g. on a panic,
+	// an overflow or unreachable code
+	OutcomeTrap Outcome = "trap"
+	// OutcomeBudget is a call that ran out of CPU or memory budget
+	OutcomeBudget Outcome = "budget"
+	// OutcomeHostError is any other host error, e.g. missing storage or
+	// failed authorization
+	OutcomeHostError Outcome = "host_error"
+	// OutcomeCrash is a simulator run that failed without a response
+	OutcomeCrash Outcome = "crash"
+)
+
+// Finding reports whether an outcome is worth keeping
+func (o Outcome) Finding() bool {
+	return o == OutcomeTrap || o == OutcomeCrash
+}
+
+// Classify returns the outcome of a simulation. err is the error the
+// simulator run itself returned.
+func Classify(resp *simulator.SimulationResponse, err error) Outcome {
+	if err != nil || resp == nil {
+		return OutcomeCrash
+	}
+	if resp.Status == "success" && resp.Error == "" {
+		return OutcomeOK
+	}
+	switch metrics.ErrorClass(resp.Error) {
+	case "contract":
+		return OutcomeContractError
+	case "wasm_vm":
+		return OutcomeTrap
+	case "budget":
+		return OutcomeBudget
+	}
+	if strings.Contains(strings.ToLower(resp.Error), "panic") {
+		return OutcomeCrash
+	}
+	return OutcomeHostError
+}
+
+// Case is one simulated argument set
+type Case struct {
+	Iteration int                           `json:"iteration"`
+	Args      []xdr.ScVal                   `json:"-"`
+	ArgsXdr   []string                      `json:"args_xdr"`
+	Outcome   Outcome                       `json:"outcome"`
+	Error     string                        `json:"error,omitempty"`
+	Request   *simulator.SimulationRequest  `json:"-"`
+	Response  *simulator.SimulationResponse `json:"-"`
+}
+
+// SimulateFunc simulates a call to the function under test with args
+type SimulateFunc func(ctx context.Context, args []xdr.ScVal) (*simulator.SimulationRequest, *simulator.SimulationResponse, error)
+
+// Campaign runs generated argument sets for one function
+type Campaign struct {
+	Generator  *Generator
+	Function   xdr.ScSpecFunctionV0
+	Iterations int
+	Simulate   SimulateFunc
+	// OnCase, when set, is called after each case is simulated
+	OnCase func(*Case)
+}
+
+// Summary counts the outcomes of a campaign. Findings holds the first case
+// of each distinct trap or crash.
+type Summary struct {
+	Iterations int             `json:"iterations"`
+	Outcomes   map[Outcome]int `json:"outcomes"`
+	Findings   []*Case         `json:"findings"`
+}
+
+// Run simulates Iterations argument sets. Half of them are fresh, the rest
+// mutate an earlier set that ran without trapping, so inputs that get past
+// the contract's checks are explored further.
+func (c *Campaign) Run(ctx context.Context) (*Summary, error) {
+	summary := &Summary{Outcomes: make(map[Outcome]int)}
+	seen := make(map[string]bool)
+	var corpus [][]xdr.ScVal
+
+	for i := 0; i < c.Iterations; i++ {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		var args []xdr.ScVal
+		var err error
+		if len(corpus) > 0 && c.Generator.rng.Intn(2) == 0 {
+			args, err = c.Generator.Mutate(c.Function, corpus[c.Generator.rng.Intn(len(corpus))])
+		} else {
+			args, err = c.Generator.Args(c.Function)
+		}
+		if err != nil {
+			return summary, err
+		}
+
+		req, resp, simErr := c.Simulate(ctx, args)
+		if simErr != nil && ctx.Err() != nil {
+			return summary, ctx.Err()
+		}
+		tc := &Case{
+			Iteration: i + 1,
+			Args:      args,
+			Outcome:   Classify(resp, simErr),
+			Request:   req,
+			Response:  resp,
+		}
+		for _, arg := range args {
+			encoded, err := xdr.MarshalBase64(arg)
+			if err != nil {
+				return summary, err
+			}
+			tc.ArgsXdr = append(tc.ArgsXdr, encoded)
+		}
+		switch {
+		case simErr != nil:
+			tc.Error = simErr.Error()
+		case resp != nil:
+			tc.Error = resp.Error
+		}
+
+		summary.Iterations++
+		summary.Outcomes[tc.Outcome]++
+		switch {
+		case tc.Outcome.Finding():
+			key := string(tc.Outcome) + "\x00" + tc.Error
+			if !seen[key] {
+				seen[key] = true
+				summary.Findings = append(summary.Findings, tc)
+			}
+		case tc.Outcome == OutcomeOK:
+			corpus = append(corpus, args)
+		}
+		if c.OnCase != nil {
+			c.OnCase(tc)
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package argfuzz
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	assert.Equal(t, OutcomeCrash, Classify(nil, fmt.Errorf("exit status 101")))
+	assert.Equal(t, OutcomeOK, Classify(&simulator.SimulationResponse{Status: "success"}, nil))
+	assert.Equal(t, OutcomeContractError, Classify(&simulator.SimulationResponse{Status: "error", Error: "HostError: Error(Contract, #3)"}, nil))
+	assert.Equal(t, OutcomeTrap, Classify(&simulator.SimulationResponse{Status: "error", Error: "HostError: Error(WasmVm, InvalidAction)"}, nil))
+	assert.Equal(t, OutcomeBudget, Classify(&simulator.SimulationResponse{Status: "error", Error: "HostError: Error(Budget, ExceededLimit)"}, nil))
+	assert.Equal(t, OutcomeHostError, Classify(&simulator.SimulationResponse{Status: "error", Error: "HostError: Error(Storage, MissingValue)"}, nil))
+	assert.Equal(t, OutcomeCrash, Classify(&simulator.SimulationResponse{Status: "error", Error: "simulator panicked: index out of bounds"}, nil))
+}
+
+func TestCampaign_Run(t *testing.T) {
+	spec := swapSpec(t)
+	fn, _ := spec.Function("swap")
+
+	var cases int
+	campaign := &Campaign{
+		Generator:  NewGenerator(spec, 9),
+		Function:   fn,
+		Iterations: 50,
+		// Trap whenever the amount is negative, reject zero
+		Simulate: func(ctx context.Context, args []xdr.ScVal) (*simulator.SimulationRequest, *simulator.SimulationResponse, error) {
+			amount := args[1].I128
+			req := &simulator.SimulationRequest{EnvelopeXdr: "AAAA"}
+			switch {
+			case amount.Hi < 0:
+				return req, &simulator.SimulationResponse{Status: "error", Error: "HostError: Error(WasmVm, InvalidAction)"}, nil
+			case amount.Hi == 0 && amount.Lo == 0:
+				return req, &simulator.SimulationResponse{Status: "error", Error: "HostError: Error(Contract, #1)"}, nil
+			}
+			return req, &simulator.SimulationResponse{Status: "success"}, nil
+		},
+		OnCase: func(*Case) { cases++ },
+	}
+
+	summary, err := campaign.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 50, summary.Iterations)
+	assert.Equal(t, 50, cases)
+	assert.Equal(t, 50, summary.Outcomes[OutcomeOK]+summary.Outcomes[OutcomeContractError]+summary.Outcomes[OutcomeTrap])
+	require.Greater(t, summary.Outcomes[OutcomeTrap], 0)
+
+	require.Len(t, summary.Findings, 1, "identical traps are reported once")
+	finding := summary.Findings[0]
+	assert.Equal(t, OutcomeTrap, finding.Outcome)
+	assert.Len(t, finding.ArgsXdr, 5)
+	assert.Less(t, int64(finding.Args[1].I128.Hi), int64(0))
+	assert.NotNil(t, finding.Request)
+}
+
+func TestCampaign_Cancelled(t *testing.T) {
+	spec := swapSpec(t)
+	fn, _ := spec.Function("swap")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	campaign := &Campaign{
+		Generator:  NewGenerator(spec, 1),
+		Function:   fn,
+		Iterations: 10,
+		Simulate: func(ctx context.Context, args []xdr.ScVal) (*simulator.SimulationRequest, *simulator.SimulationResponse, error) {
+			return nil, nil, fmt.Errorf("not reached")
+		},
+	}
+	summary, err := campaign.Run(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, summary.Iterations)
+}
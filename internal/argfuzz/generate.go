@@ -0,0 +1,413 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package argfuzz generates argument sets for a contract function from its
+// spec and runs them through the simulator, keeping the inputs that make the
+// contract trap or the simulator crash.
+package argfuzz
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// maxDepth bounds nested containers, so recursive types terminate
+const maxDepth = 3
+
+// Generator builds random and boundary values for spec types
+type Generator struct {
+	spec *contractspec.Spec
+	rng  *rand.Rand
+	// Addresses are preferred when generating Address values, e.g. the
+	// transaction source and the contract under test
+	addresses []xdr.ScAddress
+}
+
+// NewGenerator returns a generator seeded with seed. addresses seed the pool
+// Address arguments are drawn from.
+func NewGenerator(spec *contractspec.Spec, seed int64, addresses ...xdr.ScAddress) *Generator {
+	return &Generator{spec: spec, rng: rand.New(rand.NewSource(seed)), addresses: addresses}
+}
+
+// Args generates one value for each input of fn
+func (g *Generator) Args(fn xdr.ScSpecFunctionV0) ([]xdr.ScVal, error) {
+	args := make([]xdr.ScVal, len(fn.Inputs))
+	for i, in := range fn.Inputs {
+		v, err := g.Value(in.Type)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %w", in.Name, err)
+		}
+		args[i] = v
+	}
+	return args, nil
+}
+
+// Mutate returns a copy of args with one argument regenerated
+func (g *Generator) Mutate(fn xdr.ScSpecFunctionV0, args []xdr.ScVal) ([]xdr.ScVal, error) {
+	out := append([]xdr.ScVal(nil), args...)
+	if len(fn.Inputs) == 0 || len(out) != len(fn.Inputs) {
+		return out, nil
+	}
+	i := g.rng.Intn(len(fn.Inputs))
+	v, err := g.Value(fn.Inputs[i].Type)
+	if err != nil {
+		return nil, fmt.Errorf("argument %s: %w", fn.Inputs[i].Name, err)
+	}
+	out[i] = v
+	return out, nil
+}
+
+// Value generates a value of type t
+func (g *Generator) Value(t xdr.ScSpecTypeDef) (xdr.ScVal, error) {
+	return g.value(t, 0)
+}
+
+func (g *Generator) boundary() bool {
+	return g.rng.Intn(2) == 0
+}
+
+func (g *Generator) value(t xdr.ScSpecTypeDef, depth int) (xdr.ScVal, error) {
+	switch t.Type {
+	case xdr.ScSpecTypeScSpecTypeVal:
+		return g.anyValue(), nil
+	case xdr.ScSpecTypeScSpecTypeBool:
+		b := g.rng.Intn(2) == 0
+		return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b}, nil
+	case xdr.ScSpecTypeScSpecTypeVoid:
+		return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+	case xdr.ScSpecTypeScSpecTypeU32:
+		v := xdr.Uint32(g.unsigned(math.MaxUint32))
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeI32:
+		v := xdr.Int32(g.signed(math.MinInt32, math.MaxInt32))
+		return xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeU64:
+		v := xdr.Uint64(g.unsigned(math.MaxUint64))
+		return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeI64:
+		v := xdr.Int64(g.signed(math.MinInt64, math.MaxInt64))
+		return xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeTimepoint:
+		v := xdr.TimePoint(g.unsigned(math.MaxUint64))
+		return xdr.ScVal{Type: xdr.ScValTypeScvTimepoint, Timepoint: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeDuration:
+		v := xdr.Duration(g.unsigned(math.MaxUint64))
+		return xdr.ScVal{Type: xdr.ScValTypeScvDuration, Duration: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeU128:
+		hi, lo := g.wide(false)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &xdr.UInt128Parts{Hi: xdr.Uint64(hi), Lo: xdr.Uint64(lo)}}, nil
+	case xdr.ScSpecTypeScSpecTypeI128:
+		hi, lo := g.wide(true)
+		return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: xdr.Int64(hi), Lo: xdr.Uint64(lo)}}, nil
+	case xdr.ScSpecTypeScSpecTypeU256:
+		hi, lo := g.wide(false)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU256, U256: &xdr.UInt256Parts{HiHi: xdr.Uint64(hi), HiLo: xdr.Uint64(hi), LoHi: xdr.Uint64(lo), LoLo: xdr.Uint64(lo)}}, nil
+	case xdr.ScSpecTypeScSpecTypeI256:
+		hi, lo := g.wide(true)
+		fill := uint64(0)
+		if int64(hi) < 0 {
+			fill = math.MaxUint64
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvI256, I256: &xdr.Int256Parts{HiHi: xdr.Int64(hi), HiLo: xdr.Uint64(fill), LoHi: xdr.Uint64(fill), LoLo: xdr.Uint64(lo)}}, nil
+	case xdr.ScSpecTypeScSpecTypeBytes:
+		b := xdr.ScBytes(g.bytes(g.length(64, 1024)))
+		return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &b}, nil
+	case xdr.ScSpecTypeScSpecTypeBytesN:
+		if t.BytesN == nil {
+			return xdr.ScVal{}, fmt.Errorf("BytesN without length")
+		}
+		b := xdr.ScBytes(g.bytes(int(t.BytesN.N)))
+		return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &b}, nil
+	case xdr.ScSpecTypeScSpecTypeString:
+		s := xdr.ScString(g.text(g.length(32, 1024), true))
+		return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &s}, nil
+	case xdr.ScSpecTypeScSpecTypeSymbol:
+		s := xdr.ScSymbol(g.text(g.length(10, 32), false))
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &s}, nil
+	case xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeMuxedAddress:
+		addr := g.address()
+		return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &addr}, nil
+	case xdr.ScSpecTypeScSpecTypeOption:
+		if t.Option == nil || depth >= maxDepth || g.rng.Intn(3) == 0 {
+			return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+		}
+		return g.value(t.Option.ValueType, depth+1)
+	case xdr.ScSpecTypeScSpecTypeVec:
+		if t.Vec == nil {
+			return xdr.ScVal{}, fmt.Errorf("Vec without element type")
+		}
+		n := 0
+		if depth < maxDepth {
+			n = g.rng.Intn(4)
+		}
+		elems := make([]xdr.ScVal, n)
+		for i := range elems {
+			v, err := g.value(t.Vec.ElementType, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, err
+			}
+			elems[i] = v
+		}
+		return vecVal(elems), nil
+	case xdr.ScSpecTypeScSpecTypeMap:
+		if t.Map == nil {
+			return xdr.ScVal{}, fmt.Errorf("Map without key and value types")
+		}
+		n := 0
+		if depth < maxDepth {
+			n = g.rng.Intn(3)
+		}
+		entries := make(xdr.ScMap, 0, n)
+		for i := 0; i < n; i++ {
+			k, err := g.value(t.Map.KeyType, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, err
+			}
+			v, err := g.value(t.Map.ValueType, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, err
+			}
+			entries = append(entries, xdr.ScMapEntry{Key: k, Val: v})
+		}
+		return mapVal(entries), nil
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		if t.Tuple == nil {
+			return xdr.ScVal{}, fmt.Errorf("Tuple without element types")
+		}
+		elems := make([]xdr.ScVal, len(t.Tuple.ValueTypes))
+		for i, et := range t.Tuple.ValueTypes {
+			v, err := g.value(et, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, err
+			}
+			elems[i] = v
+		}
+		return vecVal(elems), nil
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		if t.Udt == nil {
+			return xdr.ScVal{}, fmt.Errorf("user-defined type without a name")
+		}
+		return g.udt(t.Udt.Name, depth)
+	}
+	return xdr.ScVal{}, fmt.Errorf("cannot generate values of type %s", contractspec.TypeName(t))
+}
+
+// udt encodes a user-defined type the way the Soroban SDK does: structs as
+// maps keyed by field name (vecs for tuple structs), unions as a vec of the
+// case name and its values, and enums as their u32 value
+func (g *Generator) udt(name string, depth int) (xdr.ScVal, error) {
+	if g.spec == nil {
+		return xdr.ScVal{}, fmt.Errorf("type %s needs the contract spec", name)
+	}
+	entry, ok := g.spec.Type(name)
+	if !ok {
+		return xdr.ScVal{}, fmt.Errorf("type %s is not in the contract spec", name)
+	}
+
+	switch {
+	case entry.UdtStructV0 != nil:
+		fields := entry.UdtStructV0.Fields
+		if len(fields) > 0 && fields[0].Name == "0" {
+			elems := make([]xdr.ScVal, len(fields))
+			for i, f := range fields {
+				v, err := g.value(f.Type, depth+1)
+				if err != nil {
+					return xdr.ScVal{}, err
+				}
+				elems[i] = v
+			}
+			return vecVal(elems), nil
+		}
+		entries := make(xdr.ScMap, len(fields))
+		for i, f := range fields {
+			v, err := g.value(f.Type, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, err
+			}
+			sym := xdr.ScSymbol(f.Name)
+			entries[i] = xdr.ScMapEntry{Key: xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, Val: v}
+		}
+		sort.Slice(entries, func(i, j int) bool { return *entries[i].Key.Sym < *entries[j].Key.Sym })
+		return mapVal(entries), nil
+
+	case entry.UdtUnionV0 != nil:
+		cases := entry.UdtUnionV0.Cases
+		if len(cases) == 0 {
+			return xdr.ScVal{}, fmt.Errorf("union %s has no cases", name)
+		}
+		c := cases[g.rng.Intn(len(cases))]
+		if depth >= maxDepth {
+			// Prefer a case without values so recursive unions terminate
+			for _, candidate := range cases {
+				if candidate.VoidCase != nil {
+					c = candidate
+					break
+				}
+			}
+		}
+		if c.VoidCase != nil {
+			return vecVal([]xdr.ScVal{symbolVal(c.VoidCase.Name)}), nil
+		}
+		if c.TupleCase == nil {
+			return xdr.ScVal{}, fmt.Errorf("union %s has an empty case", name)
+		}
+		elems := []xdr.ScVal{symbolVal(c.TupleCase.Name)}
+		for _, et := range c.TupleCase.Type {
+			v, err := g.value(et, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, err
+			}
+			elems = append(elems, v)
+		}
+		return vecVal(elems), nil
+
+	case entry.UdtEnumV0 != nil:
+		cases := entry.UdtEnumV0.Cases
+		v := xdr.Uint32(g.unsigned(math.MaxUint32))
+		if len(cases) > 0 && !g.boundary() {
+			v = cases[g.rng.Intn(len(cases))].Value
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}, nil
+
+	case entry.UdtErrorEnumV0 != nil:
+		v := xdr.Uint32(g.unsigned(math.MaxUint32))
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}, nil
+	}
+	return xdr.ScVal{}, fmt.Errorf("unsupported type %s", name)
+}
+
+// unsigned returns a boundary value half the time, otherwise a random one
+// that is usually small
+func (g *Generator) unsigned(max uint64) uint64 {
+	if g.boundary() {
+		return []uint64{0, 1, max - 1, max}[g.rng.Intn(4)]
+	}
+	if g.rng.Intn(2) == 0 {
+		return uint64(g.rng.Intn(1000))
+	}
+	return g.rng.Uint64() % max
+}
+
+func (g *Generator) signed(min, max int64) int64 {
+	if g.boundary() {
+		return []int64{0, 1, -1, min, max}[g.rng.Intn(5)]
+	}
+	if g.rng.Intn(2) == 0 {
+		return int64(g.rng.Intn(2000) - 1000)
+	}
+	v := int64(g.rng.Uint64())
+	if v < min || v > max {
+		v = v % max
+	}
+	return v
+}
+
+// wide returns the high and low 64-bit halves of a 128-bit boundary or
+// random value
+func (g *Generator) wide(signed bool) (uint64, uint64) {
+	if g.boundary() {
+		if signed {
+			bounds := [][2]uint64{{0, 0}, {0, 1}, {math.MaxUint64, math.MaxUint64}, {1 << 63, 0}, {math.MaxInt64, math.MaxUint64}}
+			b := bounds[g.rng.Intn(len(bounds))]
+			return b[0], b[1]
+		}
+		bounds := [][2]uint64{{0, 0}, {0, 1}, {math.MaxUint64, math.MaxUint64}, {0, math.MaxUint64}}
+		b := bounds[g.rng.Intn(len(bounds))]
+		return b[0], b[1]
+	}
+	if g.rng.Intn(2) == 0 {
+		// Amounts in the range tokens usually see
+		return 0, uint64(g.rng.Int63n(1_000_000_000_000))
+	}
+	hi := g.rng.Uint64()
+	if signed && g.rng.Intn(2) == 0 {
+		hi |= 1 << 63
+	}
+	return hi, g.rng.Uint64()
+}
+
+// length returns 0 or a long length at the boundaries, otherwise a random
+// length up to typical
+func (g *Generator) length(typical, long int) int {
+	if g.boundary() {
+		return []int{0, 1, long}[g.rng.Intn(3)]
+	}
+	return g.rng.Intn(typical + 1)
+}
+
+func (g *Generator) bytes(n int) []byte {
+	b := make([]byte, n)
+	switch g.rng.Intn(3) {
+	case 0: // zeroes
+	case 1:
+		for i := range b {
+			b[i] = 0xff
+		}
+	default:
+		g.rng.Read(b)
+	}
+	return b
+}
+
+const symbolChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_"
+
+// text builds n characters; symbols are limited to the characters the host
+// accepts, strings may include multi-byte ones
+func (g *Generator) text(n int, unicode bool) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if unicode && g.rng.Intn(16) == 0 {
+			b.WriteRune([]rune("é✓😀\x00")[g.rng.Intn(4)])
+			continue
+		}
+		b.WriteByte(symbolChars[g.rng.Intn(len(symbolChars))])
+	}
+	return b.String()
+}
+
+func (g *Generator) address() xdr.ScAddress {
+	if len(g.addresses) > 0 && g.rng.Intn(4) != 0 {
+		return g.addresses[g.rng.Intn(len(g.addresses))]
+	}
+	if g.rng.Intn(2) == 0 {
+		var key xdr.Uint256
+		g.rng.Read(key[:])
+		id := xdr.AccountId{Type: xdr.PublicKeyTypePublicKeyTypeEd25519, Ed25519: &key}
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &id}
+	}
+	var id xdr.ContractId
+	g.rng.Read(id[:])
+	return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}
+}
+
+// anyValue picks a value of a random simple type for Val parameters
+func (g *Generator) anyValue() xdr.ScVal {
+	types := []xdr.ScSpecType{
+		xdr.ScSpecTypeScSpecTypeVoid, xdr.ScSpecTypeScSpecTypeBool, xdr.ScSpecTypeScSpecTypeU32,
+		xdr.ScSpecTypeScSpecTypeI128, xdr.ScSpecTypeScSpecTypeSymbol, xdr.ScSpecTypeScSpecTypeAddress,
+	}
+	v, _ := g.value(xdr.ScSpecTypeDef{Type: types[g.rng.Intn(len(types))]}, maxDepth)
+	return v
+}
+
+func vecVal(elems []xdr.ScVal) xdr.ScVal {
+	vec := xdr.ScVec(elems)
+	vecPtr := &vec
+	return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vecPtr}
+}
+
+func mapVal(entries xdr.ScMap) xdr.ScVal {
+	mapPtr := &entries
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mapPtr}
+}
+
+func symbolVal(s string) xdr.ScVal {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+}
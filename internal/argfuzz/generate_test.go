@@ -0,0 +1,199 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package argfuzz
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func specType(t xdr.ScSpecType) xdr.ScSpecTypeDef {
+	return xdr.ScSpecTypeDef{Type: t}
+}
+
+func udtType(name string) xdr.ScSpecTypeDef {
+	return xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeUdt, Udt: &xdr.ScSpecTypeUdt{Name: name}}
+}
+
+func swapSpec(t *testing.T) *contractspec.Spec {
+	t.Helper()
+	entries := []xdr.ScSpecEntry{
+		{
+			Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0,
+			FunctionV0: &xdr.ScSpecFunctionV0{
+				Name: "swap",
+				Inputs: []xdr.ScSpecFunctionInputV0{
+					{Name: "to", Type: specType(xdr.ScSpecTypeScSpecTypeAddress)},
+					{Name: "amount", Type: specType(xdr.ScSpecTypeScSpecTypeI128)},
+					{Name: "route", Type: udtType("Route")},
+					{Name: "kind", Type: udtType("Kind")},
+					{Name: "hash", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeBytesN, BytesN: &xdr.ScSpecTypeBytesN{N: 32}}},
+				},
+			},
+		},
+		{
+			Kind: xdr.ScSpecEntryKindScSpecEntryUdtStructV0,
+			UdtStructV0: &xdr.ScSpecUdtStructV0{
+				Name: "Route",
+				Fields: []xdr.ScSpecUdtStructFieldV0{
+					{Name: "pools", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeVec, Vec: &xdr.ScSpecTypeVec{ElementType: specType(xdr.ScSpecTypeScSpecTypeAddress)}}},
+					{Name: "min_out", Type: specType(xdr.ScSpecTypeScSpecTypeI128)},
+				},
+			},
+		},
+		{
+			Kind: xdr.ScSpecEntryKindScSpecEntryUdtUnionV0,
+			UdtUnionV0: &xdr.ScSpecUdtUnionV0{
+				Name: "Kind",
+				Cases: []xdr.ScSpecUdtUnionCaseV0{
+					{Kind: xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseVoidV0, VoidCase: &xdr.ScSpecUdtUnionCaseVoidV0{Name: "Exact"}},
+					{Kind: xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseTupleV0, TupleCase: &xdr.ScSpecUdtUnionCaseTupleV0{Name: "Limit", Type: []xdr.ScSpecTypeDef{specType(xdr.ScSpecTypeScSpecTypeU32)}}},
+				},
+			},
+		},
+	}
+	var data []byte
+	for _, e := range entries {
+		b, err := e.MarshalBinary()
+		require.NoError(t, err)
+		data = append(data, b...)
+	}
+	spec, err := contractspec.ParseEntries(data)
+	require.NoError(t, err)
+	return spec
+}
+
+func TestGenerator_Args(t *testing.T) {
+	spec := swapSpec(t)
+	fn, ok := spec.Function("swap")
+	require.True(t, ok)
+
+	var contract xdr.ContractId
+	contract[0] = 7
+	self := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contract}
+	gen := NewGenerator(spec, 1, self)
+
+	for i := 0; i < 200; i++ {
+		args, err := gen.Args(fn)
+		require.NoError(t, err)
+		require.Len(t, args, 5)
+
+		assert.Equal(t, xdr.ScValTypeScvAddress, args[0].Type)
+		assert.Equal(t, xdr.ScValTypeScvI128, args[1].Type)
+
+		route := args[2]
+		require.Equal(t, xdr.ScValTypeScvMap, route.Type)
+		fields := **route.Map
+		require.Len(t, fields, 2)
+		assert.Equal(t, "min_out", string(*fields[0].Key.Sym), "struct fields are sorted")
+		assert.Equal(t, "pools", string(*fields[1].Key.Sym))
+		assert.Equal(t, xdr.ScValTypeScvVec, fields[1].Val.Type)
+
+		kind := args[3]
+		require.Equal(t, xdr.ScValTypeScvVec, kind.Type)
+		vec := **kind.Vec
+		require.NotEmpty(t, vec)
+		switch string(*vec[0].Sym) {
+		case "Exact":
+			assert.Len(t, vec, 1)
+		case "Limit":
+			require.Len(t, vec, 2)
+			assert.Equal(t, xdr.ScValTypeScvU32, vec[1].Type)
+		default:
+			t.Fatalf("unexpected union case %s", *vec[0].Sym)
+		}
+
+		require.Equal(t, xdr.ScValTypeScvBytes, args[4].Type)
+		assert.Len(t, *args[4].Bytes, 32)
+
+		for _, arg := range args {
+			_, err := xdr.MarshalBase64(arg)
+			require.NoError(t, err)
+		}
+	}
+}
+
+func TestGenerator_Deterministic(t *testing.T) {
+	spec := swapSpec(t)
+	fn, _ := spec.Function("swap")
+
+	a, err := NewGenerator(spec, 42).Args(fn)
+	require.NoError(t, err)
+	b, err := NewGenerator(spec, 42).Args(fn)
+	require.NoError(t, err)
+	for i := range a {
+		ea, _ := xdr.MarshalBase64(a[i])
+		eb, _ := xdr.MarshalBase64(b[i])
+		assert.Equal(t, ea, eb)
+	}
+}
+
+func TestGenerator_Boundaries(t *testing.T) {
+	gen := NewGenerator(nil, 3)
+	seen := map[uint32]bool{}
+	for i := 0; i < 500; i++ {
+		v, err := gen.Value(specType(xdr.ScSpecTypeScSpecTypeU32))
+		require.NoError(t, err)
+		seen[uint32(*v.U32)] = true
+	}
+	assert.True(t, seen[0])
+	assert.True(t, seen[1])
+	assert.True(t, seen[^uint32(0)])
+}
+
+func TestGenerator_Mutate(t *testing.T) {
+	spec := swapSpec(t)
+	fn, _ := spec.Function("swap")
+	gen := NewGenerator(spec, 5)
+
+	args, err := gen.Args(fn)
+	require.NoError(t, err)
+	mutated, err := gen.Mutate(fn, args)
+	require.NoError(t, err)
+	require.Len(t, mutated, len(args))
+
+	changed := 0
+	for i := range args {
+		ea, _ := xdr.MarshalBase64(args[i])
+		em, _ := xdr.MarshalBase64(mutated[i])
+		if ea != em {
+			changed++
+		}
+	}
+	assert.LessOrEqual(t, changed, 1, "only one argument is regenerated")
+}
+
+func TestGenerator_UnknownType(t *testing.T) {
+	_, err := NewGenerator(swapSpec(t), 1).Value(udtType("Missing"))
+	assert.Error(t, err)
+
+	_, err = NewGenerator(nil, 1).Value(udtType("Route"))
+	assert.Error(t, err)
+}
+
+func TestGenerator_Primitives(t *testing.T) {
+	gen := NewGenerator(nil, 11)
+	for _, st := range []xdr.ScSpecType{
+		xdr.ScSpecTypeScSpecTypeVal, xdr.ScSpecTypeScSpecTypeBool, xdr.ScSpecTypeScSpecTypeVoid,
+		xdr.ScSpecTypeScSpecTypeU32, xdr.ScSpecTypeScSpecTypeI32, xdr.ScSpecTypeScSpecTypeU64,
+		xdr.ScSpecTypeScSpecTypeI64, xdr.ScSpecTypeScSpecTypeTimepoint, xdr.ScSpecTypeScSpecTypeDuration,
+		xdr.ScSpecTypeScSpecTypeU128, xdr.ScSpecTypeScSpecTypeI128, xdr.ScSpecTypeScSpecTypeU256,
+		xdr.ScSpecTypeScSpecTypeI256, xdr.ScSpecTypeScSpecTypeBytes, xdr.ScSpecTypeScSpecTypeString,
+		xdr.ScSpecTypeScSpecTypeSymbol, xdr.ScSpecTypeScSpecTypeAddress,
+	} {
+		for i := 0; i < 50; i++ {
+			v, err := gen.Value(specType(st))
+			require.NoError(t, err, st.String())
+			_, err = xdr.MarshalBase64(v)
+			require.NoError(t, err, st.String())
+			if st == xdr.ScSpecTypeScSpecTypeSymbol {
+				assert.LessOrEqual(t, len(*v.Sym), 32)
+			}
+		}
+	}
+}
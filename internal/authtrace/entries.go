@@ -0,0 +1,355 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package authtrace
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Credential kinds of an authorization entry
+const (
+	CredentialsSourceAccount = "source_account"
+	CredentialsAddress       = "address"
+)
+
+type AuthIssueKind string
+
+const (
+	IssueExpired          AuthIssueKind = "expired"
+	IssueMissingSignature AuthIssueKind = "missing_signature"
+	IssueInvalidSignature AuthIssueKind = "invalid_signature"
+	IssueWrongNetwork     AuthIssueKind = "wrong_network"
+	IssueUnsortedSigners  AuthIssueKind = "unsorted_signatures"
+	IssueDuplicateNonce   AuthIssueKind = "duplicate_nonce"
+	// IssueNonMasterSigner is informational: the signer's weight on the
+	// account cannot be checked from the transaction alone
+	IssueNonMasterSigner AuthIssueKind = "non_master_signer"
+)
+
+// AuthIssue is a problem found in an authorization entry
+type AuthIssue struct {
+	Entry   int           `json:"entry"`
+	Kind    AuthIssueKind `json:"kind"`
+	Message string        `json:"message"`
+}
+
+// AuthSignature is one signature attached to address credentials
+type AuthSignature struct {
+	PublicKey string `json:"public_key"`
+	Valid     bool   `json:"valid"`
+}
+
+// AuthInvocation is a node of the invocation tree an entry authorizes
+type AuthInvocation struct {
+	Contract       string            `json:"contract,omitempty"`
+	Function       string            `json:"function"`
+	Args           []string          `json:"args,omitempty"`
+	SubInvocations []*AuthInvocation `json:"sub_invocations,omitempty"`
+}
+
+// AuthEntry is a decoded SorobanAuthorizationEntry
+type AuthEntry struct {
+	Index            int             `json:"index"`
+	Credentials      string          `json:"credentials"`
+	Signer           string          `json:"signer,omitempty"`
+	Nonce            int64           `json:"nonce,omitempty"`
+	ExpirationLedger uint32          `json:"expiration_ledger,omitempty"`
+	Signatures       []AuthSignature `json:"signatures,omitempty"`
+	Invocation       *AuthInvocation `json:"invocation"`
+	Issues           []AuthIssue     `json:"issues,omitempty"`
+}
+
+// AuthTree holds every authorization entry of a transaction
+type AuthTree struct {
+	// LedgerSequence is the ledger expirations are checked against; 0 skips
+	// the check
+	LedgerSequence uint32      `json:"ledger_sequence,omitempty"`
+	Entries        []AuthEntry `json:"entries"`
+}
+
+// knownNetworks are tried when a signature does not verify, to spot entries
+// signed for the wrong network
+var knownNetworks = map[string]string{
+	network.PublicNetworkPassphrase:      "mainnet",
+	network.TestNetworkPassphrase:        "testnet",
+	network.FutureNetworkPassphrase:      "futurenet",
+	"Standalone Network ; February 2017": "local",
+}
+
+// DecodeAuthEntries decodes the authorization entries of every
+// InvokeHostFunction operation in envelopeXdr, verifying signatures against
+// passphrase and expirations against ledger
+func DecodeAuthEntries(envelopeXdr, passphrase string, ledger uint32) (*AuthTree, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid transaction envelope: %w", err)
+	}
+
+	var entries []xdr.SorobanAuthorizationEntry
+	for _, op := range envelope.Operations() {
+		if op.Body.InvokeHostFunctionOp != nil {
+			entries = append(entries, op.Body.InvokeHostFunctionOp.Auth...)
+		}
+	}
+	return DecodeAuthorization(entries, passphrase, ledger), nil
+}
+
+// DecodeAuthorization builds the authorization tree of entries
+func DecodeAuthorization(entries []xdr.SorobanAuthorizationEntry, passphrase string, ledger uint32) *AuthTree {
+	tree := &AuthTree{LedgerSequence: ledger}
+	nonces := make(map[string]int)
+	for i, raw := range entries {
+		entry := AuthEntry{
+			Index:       i,
+			Credentials: CredentialsSourceAccount,
+			Invocation:  decodeInvocation(raw.RootInvocation),
+		}
+
+		if creds := raw.Credentials.Address; raw.Credentials.Type == xdr.SorobanCredentialsTypeSorobanCredentialsAddress && creds != nil {
+			entry.Credentials = CredentialsAddress
+			entry.Signer = addressString(creds.Address)
+			entry.Nonce = int64(creds.Nonce)
+			entry.ExpirationLedger = uint32(creds.SignatureExpirationLedger)
+
+			issue := func(kind AuthIssueKind, format string, args ...interface{}) {
+				entry.Issues = append(entry.Issues, AuthIssue{Entry: i, Kind: kind, Message: fmt.Sprintf(format, args...)})
+			}
+
+			if ledger > 0 && ledger > entry.ExpirationLedger {
+				issue(IssueExpired, "signature expired at ledger %d, %d ledgers before %d", entry.ExpirationLedger, ledger-entry.ExpirationLedger, ledger)
+			}
+			nonceKey := fmt.Sprintf("%s/%d", entry.Signer, entry.Nonce)
+			if first, seen := nonces[nonceKey]; seen {
+				issue(IssueDuplicateNonce, "nonce %d is also used by entry %d; only one can be consumed", entry.Nonce, first)
+			} else {
+				nonces[nonceKey] = i
+			}
+
+			if creds.Address.Type == xdr.ScAddressTypeScAddressTypeAccount {
+				checkAccountSignatures(&entry, *creds, raw.RootInvocation, passphrase, issue)
+			}
+		}
+		tree.Entries = append(tree.Entries, entry)
+	}
+	return tree
+}
+
+// checkAccountSignatures verifies the ed25519 signatures of a classic
+// account's credentials
+func checkAccountSignatures(entry *AuthEntry, creds xdr.SorobanAddressCredentials, invocation xdr.SorobanAuthorizedInvocation, passphrase string, issue func(AuthIssueKind, string, ...interface{})) {
+	sigs := accountSignatures(creds.Signature)
+	if len(sigs) == 0 {
+		issue(IssueMissingSignature, "no signature for %s", entry.Signer)
+		return
+	}
+
+	payload := func(passphrase string) []byte {
+		preimage := xdr.HashIdPreimage{
+			Type: xdr.EnvelopeTypeEnvelopeTypeSorobanAuthorization,
+			SorobanAuthorization: &xdr.HashIdPreimageSorobanAuthorization{
+				NetworkId:                 xdr.Hash(network.ID(passphrase)),
+				Nonce:                     creds.Nonce,
+				SignatureExpirationLedger: creds.SignatureExpirationLedger,
+				Invocation:                invocation,
+			},
+		}
+		raw, err := preimage.MarshalBinary()
+		if err != nil {
+			return nil
+		}
+		hash := sha256.Sum256(raw)
+		return hash[:]
+	}
+	expected := payload(passphrase)
+
+	var prev []byte
+	for _, sig := range sigs {
+		publicKey, err := strkey.Encode(strkey.VersionByteAccountID, sig.publicKey)
+		if err != nil {
+			issue(IssueInvalidSignature, "public key %x is not a valid ed25519 key", sig.publicKey)
+			continue
+		}
+		kp, err := keypair.ParseAddress(publicKey)
+		if err != nil {
+			issue(IssueInvalidSignature, "public key %s is not a valid ed25519 key", publicKey)
+			continue
+		}
+
+		valid := kp.Verify(expected, sig.signature) == nil
+		entry.Signatures = append(entry.Signatures, AuthSignature{PublicKey: publicKey, Valid: valid})
+		if !valid {
+			wrong := ""
+			for other, name := range knownNetworks {
+				if other != passphrase && kp.Verify(payload(other), sig.signature) == nil {
+					wrong = name
+					break
+				}
+			}
+			if wrong != "" {
+				issue(IssueWrongNetwork, "signature by %s was made for %s", publicKey, wrong)
+			} else {
+				issue(IssueInvalidSignature, "signature by %s does not match the authorized invocation, nonce and expiration", publicKey)
+			}
+		}
+		if publicKey != entry.Signer {
+			issue(IssueNonMasterSigner, "%s is not the master key of %s; its weight on the account is not checked here", publicKey, entry.Signer)
+		}
+		if prev != nil && bytes.Compare(prev, sig.publicKey) >= 0 {
+			issue(IssueUnsortedSigners, "signatures must be sorted by public key without duplicates")
+		}
+		prev = sig.publicKey
+	}
+}
+
+type accountSignature struct {
+	publicKey []byte
+	signature []byte
+}
+
+// accountSignatures reads the Vec<Map{public_key, signature}> a classic
+// account signs authorization entries with
+func accountSignatures(v xdr.ScVal) []accountSignature {
+	vec, ok := v.GetVec()
+	if !ok || vec == nil {
+		return nil
+	}
+	var out []accountSignature
+	for _, item := range *vec {
+		m, ok := item.GetMap()
+		if !ok || m == nil {
+			continue
+		}
+		var sig accountSignature
+		for _, field := range *m {
+			name, ok := field.Key.GetSym()
+			if !ok {
+				continue
+			}
+			b, ok := field.Val.GetBytes()
+			if !ok {
+				continue
+			}
+			switch name {
+			case "public_key":
+				sig.publicKey = b
+			case "signature":
+				sig.signature = b
+			}
+		}
+		if sig.publicKey != nil {
+			out = append(out, sig)
+		}
+	}
+	return out
+}
+
+func decodeInvocation(inv xdr.SorobanAuthorizedInvocation) *AuthInvocation {
+	node := &AuthInvocation{}
+	fn := inv.Function
+	switch fn.Type {
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn:
+		if fn.ContractFn != nil {
+			node.Contract = addressString(fn.ContractFn.ContractAddress)
+			node.Function = string(fn.ContractFn.FunctionName)
+			for _, arg := range fn.ContractFn.Args {
+				node.Args = append(node.Args, decoder.FormatScVal(arg))
+			}
+		}
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeCreateContractHostFn:
+		node.Function = "create_contract"
+		if fn.CreateContractHostFn != nil {
+			node.Args = []string{executableString(fn.CreateContractHostFn.Executable)}
+		}
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeCreateContractV2HostFn:
+		node.Function = "create_contract"
+		if fn.CreateContractV2HostFn != nil {
+			node.Args = []string{executableString(fn.CreateContractV2HostFn.Executable)}
+			for _, arg := range fn.CreateContractV2HostFn.ConstructorArgs {
+				node.Args = append(node.Args, decoder.FormatScVal(arg))
+			}
+		}
+	}
+	for _, sub := range inv.SubInvocations {
+		node.SubInvocations = append(node.SubInvocations, decodeInvocation(sub))
+	}
+	return node
+}
+
+func executableString(e xdr.ContractExecutable) string {
+	if e.Type == xdr.ContractExecutableTypeContractExecutableWasm && e.WasmHash != nil {
+		return "wasm:" + hex.EncodeToString(e.WasmHash[:])
+	}
+	return "stellar_asset"
+}
+
+func addressString(address xdr.ScAddress) string {
+	if address.Type == xdr.ScAddressTypeScAddressTypeContract && address.ContractId != nil {
+		if id, err := strkey.Encode(strkey.VersionByteContract, address.ContractId[:]); err == nil {
+			return id
+		}
+	}
+	if address.Type == xdr.ScAddressTypeScAddressTypeAccount && address.AccountId != nil {
+		return address.AccountId.Address()
+	}
+	return address.Type.String()
+}
+
+// Issues returns the issues of every entry
+func (t *AuthTree) Issues() []AuthIssue {
+	var out []AuthIssue
+	for _, e := range t.Entries {
+		out = append(out, e.Issues...)
+	}
+	return out
+}
+
+// Render draws the tree, one entry per block:
+//
+//	[0] GABC... nonce 42, expires at ledger 1200
+//	    signed by GABC... (valid)
+//	    └─ CDEF....transfer(GABC..., GXYZ..., 100)
+//	       └─ CTOK....burn(GABC..., 1)
+func (t *AuthTree) Render() string {
+	var b strings.Builder
+	for _, e := range t.Entries {
+		if e.Credentials == CredentialsSourceAccount {
+			fmt.Fprintf(&b, "[%d] transaction source account\n", e.Index)
+		} else {
+			fmt.Fprintf(&b, "[%d] %s nonce %d, expires at ledger %d\n", e.Index, e.Signer, e.Nonce, e.ExpirationLedger)
+			for _, sig := range e.Signatures {
+				status := "valid"
+				if !sig.Valid {
+					status = "INVALID"
+				}
+				fmt.Fprintf(&b, "    signed by %s (%s)\n", sig.PublicKey, status)
+			}
+		}
+		renderInvocation(&b, e.Invocation, "    ")
+		for _, issue := range e.Issues {
+			fmt.Fprintf(&b, "    ! %s: %s\n", issue.Kind, issue.Message)
+		}
+	}
+	return b.String()
+}
+
+func renderInvocation(b *strings.Builder, inv *AuthInvocation, indent string) {
+	call := inv.Function + "(" + strings.Join(inv.Args, ", ") + ")"
+	if inv.Contract != "" {
+		call = inv.Contract + "." + call
+	}
+	fmt.Fprintf(b, "%s└─ %s\n", indent, call)
+	for _, sub := range inv.SubInvocations {
+		renderInvocation(b, sub, indent+"   ")
+	}
+}
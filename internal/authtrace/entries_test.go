@@ -0,0 +1,180 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package authtrace
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testInvocation() xdr.SorobanAuthorizedInvocation {
+	contractID := xdr.ContractId{7}
+	amount := xdr.Uint32(100)
+	burn := xdr.ScSymbol("burn")
+	return xdr.SorobanAuthorizedInvocation{
+		Function: xdr.SorobanAuthorizedFunction{
+			Type: xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+			ContractFn: &xdr.InvokeContractArgs{
+				ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+				FunctionName:    "transfer",
+				Args:            []xdr.ScVal{{Type: xdr.ScValTypeScvU32, U32: &amount}},
+			},
+		},
+		SubInvocations: []xdr.SorobanAuthorizedInvocation{{
+			Function: xdr.SorobanAuthorizedFunction{
+				Type: xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+				ContractFn: &xdr.InvokeContractArgs{
+					ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+					FunctionName:    burn,
+				},
+			},
+		}},
+	}
+}
+
+// signedEntry returns an entry for kp's account signed by signers for passphrase
+func signedEntry(t *testing.T, kp *keypair.Full, nonce int64, expiration uint32, passphrase string, signers ...*keypair.Full) xdr.SorobanAuthorizationEntry {
+	t.Helper()
+	invocation := testInvocation()
+	preimage := xdr.HashIdPreimage{
+		Type: xdr.EnvelopeTypeEnvelopeTypeSorobanAuthorization,
+		SorobanAuthorization: &xdr.HashIdPreimageSorobanAuthorization{
+			NetworkId:                 xdr.Hash(network.ID(passphrase)),
+			Nonce:                     xdr.Int64(nonce),
+			SignatureExpirationLedger: xdr.Uint32(expiration),
+			Invocation:                invocation,
+		},
+	}
+	raw, err := preimage.MarshalBinary()
+	require.NoError(t, err)
+	payload := sha256.Sum256(raw)
+
+	sigs := xdr.ScVec{}
+	for _, signer := range signers {
+		sig, err := signer.Sign(payload[:])
+		require.NoError(t, err)
+		fields := xdr.ScMap{
+			{Key: symbol("public_key"), Val: bytesVal(signer.Address())},
+			{Key: symbol("signature"), Val: xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: (*xdr.ScBytes)(&sig)}},
+		}
+		fieldsPtr := &fields
+		sigs = append(sigs, xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &fieldsPtr})
+	}
+	sigsPtr := &sigs
+
+	return xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{
+			Type: xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+			Address: &xdr.SorobanAddressCredentials{
+				Address:                   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: accountID(kp.Address())},
+				Nonce:                     xdr.Int64(nonce),
+				SignatureExpirationLedger: xdr.Uint32(expiration),
+				Signature:                 xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &sigsPtr},
+			},
+		},
+		RootInvocation: invocation,
+	}
+}
+
+func symbol(s string) xdr.ScVal {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+}
+
+func bytesVal(address string) xdr.ScVal {
+	b := xdr.ScBytes(strkey.MustDecode(strkey.VersionByteAccountID, address))
+	return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &b}
+}
+
+func accountID(address string) *xdr.AccountId {
+	id := xdr.MustAddress(address)
+	return &id
+}
+
+func kinds(issues []AuthIssue) []AuthIssueKind {
+	var out []AuthIssueKind
+	for _, i := range issues {
+		out = append(out, i.Kind)
+	}
+	return out
+}
+
+func TestDecodeAuthorization(t *testing.T) {
+	alice := keypair.MustRandom()
+
+	tree := DecodeAuthorization([]xdr.SorobanAuthorizationEntry{
+		signedEntry(t, alice, 1, 1000, network.TestNetworkPassphrase, alice),
+		{RootInvocation: testInvocation()},
+	}, network.TestNetworkPassphrase, 900)
+
+	require.Len(t, tree.Entries, 2)
+	assert.Empty(t, tree.Issues())
+
+	entry := tree.Entries[0]
+	assert.Equal(t, CredentialsAddress, entry.Credentials)
+	assert.Equal(t, alice.Address(), entry.Signer)
+	assert.Equal(t, int64(1), entry.Nonce)
+	assert.Equal(t, uint32(1000), entry.ExpirationLedger)
+	require.Len(t, entry.Signatures, 1)
+	assert.True(t, entry.Signatures[0].Valid)
+
+	assert.Equal(t, "transfer", entry.Invocation.Function)
+	assert.Equal(t, []string{"100"}, entry.Invocation.Args)
+	require.Len(t, entry.Invocation.SubInvocations, 1)
+	assert.Equal(t, "burn", entry.Invocation.SubInvocations[0].Function)
+
+	assert.Equal(t, CredentialsSourceAccount, tree.Entries[1].Credentials)
+
+	rendered := tree.Render()
+	assert.Contains(t, rendered, "[0] "+alice.Address()+" nonce 1, expires at ledger 1000")
+	assert.Contains(t, rendered, "[1] transaction source account")
+	assert.True(t, strings.Contains(rendered, "       └─ ") && strings.Contains(rendered, ".burn()"), rendered)
+}
+
+func TestDecodeAuthorization_Issues(t *testing.T) {
+	alice, bob := keypair.MustRandom(), keypair.MustRandom()
+
+	tests := []struct {
+		name   string
+		entry  xdr.SorobanAuthorizationEntry
+		ledger uint32
+		want   []AuthIssueKind
+	}{
+		{"expired", signedEntry(t, alice, 1, 100, network.TestNetworkPassphrase, alice), 150, []AuthIssueKind{IssueExpired}},
+		{"wrong network", signedEntry(t, alice, 1, 1000, network.PublicNetworkPassphrase, alice), 0, []AuthIssueKind{IssueWrongNetwork}},
+		{"missing signature", signedEntry(t, alice, 1, 1000, network.TestNetworkPassphrase), 0, []AuthIssueKind{IssueMissingSignature}},
+		{"other signer", signedEntry(t, alice, 1, 1000, network.TestNetworkPassphrase, bob), 0, []AuthIssueKind{IssueNonMasterSigner}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := DecodeAuthorization([]xdr.SorobanAuthorizationEntry{tt.entry}, network.TestNetworkPassphrase, tt.ledger)
+			assert.Equal(t, tt.want, kinds(tree.Issues()))
+		})
+	}
+
+	t.Run("tampered", func(t *testing.T) {
+		entry := signedEntry(t, alice, 1, 1000, network.TestNetworkPassphrase, alice)
+		entry.Credentials.Address.Nonce = 2
+		tree := DecodeAuthorization([]xdr.SorobanAuthorizationEntry{entry}, network.TestNetworkPassphrase, 0)
+		assert.Equal(t, []AuthIssueKind{IssueInvalidSignature}, kinds(tree.Issues()))
+		assert.False(t, tree.Entries[0].Signatures[0].Valid)
+	})
+
+	t.Run("duplicate nonce", func(t *testing.T) {
+		entry := signedEntry(t, alice, 5, 1000, network.TestNetworkPassphrase, alice)
+		tree := DecodeAuthorization([]xdr.SorobanAuthorizationEntry{entry, entry}, network.TestNetworkPassphrase, 0)
+		issues := tree.Issues()
+		require.Len(t, issues, 1)
+		assert.Equal(t, IssueDuplicateNonce, issues[0].Kind)
+		assert.Equal(t, 1, issues[0].Entry)
+	})
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package canary describes the health-check envelopes erst canary
+// re-simulates on a schedule, and tracks when each of them starts and stops
+// failing.
+package canary
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultInterval is how often canaries run when the config sets no interval
+const DefaultInterval = 5 * time.Minute
+
+// Canary is one envelope to re-simulate
+type Canary struct {
+	Name string `yaml:"name"`
+	// Envelope is a file holding the TransactionEnvelope XDR, relative to
+	// the config file
+	Envelope string `yaml:"envelope"`
+}
+
+// Config is the layout of a canary config file
+type Config struct {
+	Interval time.Duration `yaml:"interval"`
+	Canaries []Canary      `yaml:"canaries"`
+}
+
+// Load reads a YAML canary config, resolving envelope paths against the
+// directory of the file
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read canary config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse canary config %s: %w", path, err)
+	}
+	if len(cfg.Canaries) == 0 {
+		return nil, fmt.Errorf("canary config %s defines no canaries", path)
+	}
+	if cfg.Interval < 0 {
+		return nil, fmt.Errorf("canary interval must not be negative")
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = DefaultInterval
+	}
+
+	seen := make(map[string]bool, len(cfg.Canaries))
+	for i := range cfg.Canaries {
+		c := &cfg.Canaries[i]
+		if c.Envelope == "" {
+			return nil, fmt.Errorf("canary %d has no envelope", i+1)
+		}
+		if c.Name == "" {
+			c.Name = filepath.Base(c.Envelope)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("canary %q is defined twice", c.Name)
+		}
+		seen[c.Name] = true
+		if !filepath.IsAbs(c.Envelope) {
+			c.Envelope = filepath.Join(filepath.Dir(path), c.Envelope)
+		}
+	}
+	return &cfg, nil
+}
+
+// Transition is how a canary's health changed with its latest run
+type Transition string
+
+const (
+	// Unchanged means the canary is as healthy, or as broken, as before
+	Unchanged Transition = ""
+	// Failing means the canary failed after passing, or on its first run
+	Failing Transition = "failing"
+	// Recovered means the canary passed after failing
+	Recovered Transition = "recovered"
+)
+
+// Status is the health of one canary
+type Status struct {
+	Name    string    `json:"name"`
+	Healthy bool      `json:"healthy"`
+	Since   time.Time `json:"since"`
+	// Failures is the number of consecutive failed runs
+	Failures int `json:"failures,omitempty"`
+}
+
+// Tracker remembers the health of each canary between runs. It is safe for
+// concurrent use.
+type Tracker struct {
+	mu     sync.Mutex
+	status map[string]*Status
+	now    func() time.Time
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{status: make(map[string]*Status), now: time.Now}
+}
+
+// Record stores the result of a canary run and reports how its health
+// changed
+func (t *Tracker) Record(name string, healthy bool) (Transition, Status) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	s, ok := t.status[name]
+	if !ok {
+		s = &Status{Name: name, Healthy: healthy, Since: now}
+		t.status[name] = s
+		if !healthy {
+			s.Failures = 1
+			return Failing, *s
+		}
+		return Unchanged, *s
+	}
+
+	transition := Unchanged
+	switch {
+	case healthy && !s.Healthy:
+		transition = Recovered
+		s.Since = now
+	case !healthy && s.Healthy:
+		transition = Failing
+		s.Since = now
+	}
+	s.Healthy = healthy
+	if healthy {
+		s.Failures = 0
+	} else {
+		s.Failures++
+	}
+	return transition, *s
+}
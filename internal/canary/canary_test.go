@@ -0,0 +1,87 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package canary
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "canaries.yaml")
+	config := `interval: 1m
+canaries:
+  - name: swap
+    envelope: envelopes/swap.xdr
+  - envelope: /abs/deposit.xdr
+`
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Interval != time.Minute {
+		t.Errorf("Interval = %s", cfg.Interval)
+	}
+	if got := cfg.Canaries[0].Envelope; got != filepath.Join(dir, "envelopes", "swap.xdr") {
+		t.Errorf("relative envelope resolved to %s", got)
+	}
+	if got := cfg.Canaries[1]; got.Name != "deposit.xdr" || got.Envelope != "/abs/deposit.xdr" {
+		t.Errorf("unnamed canary = %+v", got)
+	}
+
+	for _, bad := range []string{
+		"canaries: []",
+		"canaries:\n  - name: swap",
+		"canaries:\n  - {name: a, envelope: x}\n  - {name: a, envelope: y}",
+		"interval: -1m\ncanaries:\n  - envelope: x",
+	} {
+		if err := os.WriteFile(path, []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("canaries:\n  - envelope: x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err := Load(path); err != nil || cfg.Interval != DefaultInterval {
+		t.Errorf("expected the default interval, got %v, %v", cfg, err)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+	now := time.Unix(1700000000, 0)
+	tracker.now = func() time.Time { return now }
+
+	if tr, _ := tracker.Record("swap", true); tr != Unchanged {
+		t.Errorf("first healthy run: %q", tr)
+	}
+	if tr, _ := tracker.Record("deposit", false); tr != Failing {
+		t.Errorf("first failing run: %q", tr)
+	}
+
+	now = now.Add(time.Minute)
+	tr, status := tracker.Record("swap", false)
+	if tr != Failing || status.Failures != 1 || !status.Since.Equal(now) {
+		t.Errorf("swap started failing: %q %+v", tr, status)
+	}
+	now = now.Add(time.Minute)
+	tr, status = tracker.Record("swap", false)
+	if tr != Unchanged || status.Failures != 2 || status.Since.Equal(now) {
+		t.Errorf("swap still failing: %q %+v", tr, status)
+	}
+	tr, status = tracker.Record("swap", true)
+	if tr != Recovered || status.Failures != 0 || !status.Healthy {
+		t.Errorf("swap recovered: %q %+v", tr, status)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/activity"
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stellar/go-stellar-sdk/strkey"
+)
+
+// activitySink receives an entry for the running command when activity_log
+// is set, opened before the command ran at activityStart
+var (
+	activitySink  activity.Sink
+	activityStart time.Time
+)
+
+// activityRedacted replaces the values of secret flags in activity entries
+const activityRedacted = "REDACTED"
+
+// sensitiveFlagAnnotation marks flags, through markSensitive, whose values
+// are kept out of the activity log even though their names look harmless
+const sensitiveFlagAnnotation = "erst_sensitive"
+
+// secretFlagWords mark flags whose values are kept out of the activity log
+var secretFlagWords = []string{"token", "secret", "password", "passphrase", "key", "dsn"}
+
+var (
+	txHashPattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+	// seedPattern finds candidate secret keys anywhere in a value; matches
+	// are only redacted when their checksum is valid
+	seedPattern = regexp.MustCompile(`S[A-Z2-7]{55}`)
+)
+
+// markSensitive keeps the values of cmd's flags names out of the activity
+// log, for flags that may carry secret keys or credentials, such as RPC
+// URLs with an API key in their path
+func markSensitive(cmd *cobra.Command, names ...string) {
+	for _, name := range names {
+		if err := cmd.Flags().SetAnnotation(name, sensitiveFlagAnnotation, []string{"true"}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// openActivityLog opens the configured activity log before cmd runs. A log
+// that cannot be opened stops the command, so nothing runs unrecorded.
+func openActivityLog(cmd *cobra.Command) error {
+	if isCompletionRequest(cmd) {
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil || cfg.ActivityLog == "" {
+		return nil
+	}
+
+	if cfg.ActivityLog == activity.TargetSessionDB {
+		activitySink, err = session.OpenActivityLog()
+	} else {
+		activitySink, err = activity.OpenFile(cfg.ActivityLog)
+	}
+	if err != nil {
+		return errors.WrapConfigError("activity_log is set but cannot be written", err)
+	}
+	activityStart = time.Now()
+	return nil
+}
+
+// recordActivity appends the entry of cmd, which finished with runErr, to the
+// activity log opened for it
+func recordActivity(cmd *cobra.Command, runErr error) {
+	if activitySink == nil || cmd == nil {
+		return
+	}
+	defer func() {
+		activitySink.Close()
+		activitySink = nil
+	}()
+
+	// The command's own context may have been canceled by a signal
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := activitySink.Append(ctx, newActivityEntry(cmd, runErr, activityStart, time.Now())); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record activity: %v\n", err)
+	}
+}
+
+// newActivityEntry describes the invocation of cmd, from its parsed flags and
+// arguments
+func newActivityEntry(cmd *cobra.Command, runErr error, start, end time.Time) activity.Entry {
+	host, _ := os.Hostname()
+	e := activity.Entry{
+		Time:       start,
+		User:       activity.CurrentUser(),
+		Host:       host,
+		Command:    cmd.CommandPath(),
+		Result:     activity.ResultOK,
+		DurationMS: end.Sub(start).Milliseconds(),
+		Version:    Version,
+	}
+	if runErr != nil {
+		e.Result = activity.ResultError
+		e.Error = redactSecretKeys(runErr.Error())
+	}
+	if f := cmd.Flags().Lookup("network"); f != nil {
+		e.Network = f.Value.String()
+	}
+
+	for _, arg := range cmd.Flags().Args() {
+		e.Args = append(e.Args, redactSecretKeys(arg))
+		if e.TxHash == "" && txHashPattern.MatchString(arg) {
+			e.TxHash = arg
+		}
+	}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		value := redactSecretKeys(f.Value.String())
+		if isSecretFlag(f) {
+			value = activityRedacted
+		} else if e.TxHash == "" && txHashPattern.MatchString(value) {
+			e.TxHash = value
+		}
+		e.Args = append(e.Args, "--"+f.Name+"="+value)
+	})
+	return e
+}
+
+func isSecretFlag(f *pflag.Flag) bool {
+	if len(f.Annotations[sensitiveFlagAnnotation]) > 0 {
+		return true
+	}
+	name := strings.ToLower(f.Name)
+	for _, word := range secretFlagWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSecretKeys replaces the Stellar secret keys in s, wherever they
+// appear, so keys given to flags or arguments of any name are not recorded
+func redactSecretKeys(s string) string {
+	return seedPattern.ReplaceAllStringFunc(s, func(candidate string) string {
+		if strkey.IsValidEd25519SecretSeed(candidate) {
+			return activityRedacted
+		}
+		return candidate
+	})
+}
+
+// isCompletionRequest reports whether cmd is the hidden command shells call
+// for completions on every tab press, which is not worth recording
+func isCompletionRequest(cmd *cobra.Command) bool {
+	return cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd
+}
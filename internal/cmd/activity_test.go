@@ -0,0 +1,117 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/activity"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parsedCommand(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{Use: "debug"}
+	cmd.Flags().StringP("network", "n", "mainnet", "")
+	cmd.Flags().String("rpc-token", "", "")
+	cmd.Flags().Bool("verbose", false, "")
+	require.NoError(t, cmd.ParseFlags(args))
+	return cmd
+}
+
+func TestNewActivityEntry(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	cmd := parsedCommand(t, hash, "-n", "testnet", "--rpc-token", "s3cret", "--verbose")
+	start := time.Unix(1_700_000_000, 0)
+
+	e := newActivityEntry(cmd, errors.WrapTransactionNotFound(nil), start, start.Add(1500*time.Millisecond))
+	assert.Equal(t, "debug", e.Command)
+	assert.Equal(t, hash, e.TxHash)
+	assert.Equal(t, "testnet", e.Network)
+	assert.Equal(t, activity.ResultError, e.Result)
+	assert.NotEmpty(t, e.Error)
+	assert.Equal(t, int64(1500), e.DurationMS)
+	assert.Contains(t, e.Args, "--rpc-token="+activityRedacted)
+	assert.NotContains(t, strings.Join(e.Args, " "), "s3cret")
+
+	// The network a command runs on is recorded even when not given
+	e = newActivityEntry(parsedCommand(t), nil, start, start)
+	assert.Equal(t, "mainnet", e.Network)
+	assert.Equal(t, activity.ResultOK, e.Result)
+	assert.Empty(t, e.TxHash)
+}
+
+// parsedCopy parses args with copies of the flags of cmd, so the parse is
+// not seen by other tests. The flags still set cmd's variables.
+func parsedCopy(t *testing.T, cmd *cobra.Command, args ...string) *cobra.Command {
+	t.Helper()
+	parsed := &cobra.Command{Use: cmd.Name()}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		flag := *f
+		parsed.Flags().AddFlag(&flag)
+	})
+	require.NoError(t, parsed.ParseFlags(args))
+	return parsed
+}
+
+func TestNewActivityEntry_SecretKeys(t *testing.T) {
+	defer func(signWith []string, resubmitRPC, from, fundRPC string) {
+		resubmitSignWithFlag, resubmitRPCURLFlag, fundFromFlag, fundRPCURLFlag = signWith, resubmitRPC, from, fundRPC
+	}(resubmitSignWithFlag, resubmitRPCURLFlag, fundFromFlag, fundRPCURLFlag)
+	seed := keypair.MustRandom().Seed()
+	rpcURL := "https://rpc.example.com/v1/0123456789abcdef"
+	start := time.Now()
+
+	resubmit := parsedCopy(t, resubmitCmd, strings.Repeat("ab", 32), "--sign-with", seed, "--sign-with", "alice", "--rpc-url", rpcURL)
+	e := newActivityEntry(resubmit, nil, start, start)
+	assert.Contains(t, e.Args, "--sign-with="+activityRedacted)
+	assert.Contains(t, e.Args, "--rpc-url="+activityRedacted)
+	assert.NotContains(t, strings.Join(e.Args, " "), seed)
+
+	fund := parsedCopy(t, fundCmd, "--from", seed, "--rpc-url", rpcURL)
+	e = newActivityEntry(fund, nil, start, start)
+	assert.Contains(t, e.Args, "--from="+activityRedacted)
+	assert.NotContains(t, strings.Join(e.Args, " "), rpcURL)
+
+	// Secret keys are recognized in any argument and in errors
+	e = newActivityEntry(parsedCommand(t, seed, "--verbose"), errors.WrapValidationError("bad key "+seed), start, start)
+	assert.Equal(t, activityRedacted, e.Args[0])
+	assert.Contains(t, e.Args, "--verbose=true")
+	assert.NotContains(t, e.Error, seed)
+
+	// Other strings of that shape are kept
+	assert.Equal(t, "S"+strings.Repeat("A", 55), redactSecretKeys("S"+strings.Repeat("A", 55)))
+}
+
+func TestActivityLog_File(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "activity.log")
+	t.Setenv("ERST_ACTIVITY_LOG", path)
+
+	cmd := parsedCommand(t, "-n", "testnet")
+	require.NoError(t, openActivityLog(cmd))
+	recordActivity(cmd, nil)
+	assert.Nil(t, activitySink)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var e activity.Entry
+	require.NoError(t, json.Unmarshal(contents, &e))
+	assert.Equal(t, "testnet", e.Network)
+	assert.Equal(t, activity.ResultOK, e.Result)
+
+	// An unwritable log stops the command
+	t.Setenv("ERST_ACTIVITY_LOG", filepath.Join(t.TempDir(), "missing", "activity.log"))
+	assert.Error(t, openActivityLog(cmd))
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"github.com/dotandev/hintents/internal/alert"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+var alertRulesFlag string
+
+// addAlertFlags registers --alert-rules on a long-running command
+func addAlertFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&alertRulesFlag, "alert-rules", "", "YAML file of alerting rules to apply to every failure")
+}
+
+// newAlertEngine loads the rules given with --alert-rules, resolving contract
+// aliases on network. The engine is nil when no rules file is given.
+func newAlertEngine(network string) (*alert.Engine, error) {
+	if alertRulesFlag == "" {
+		return nil, nil
+	}
+	rules, err := alert.Load(alertRulesFlag)
+	if err != nil {
+		return nil, errors.WrapValidationError(err.Error())
+	}
+	for i := range rules {
+		if rules[i].Contract != "" {
+			rules[i].Contract = resolveContract(network, rules[i].Contract)
+		}
+	}
+	engine, err := alert.NewEngine(rules)
+	if err != nil {
+		return nil, errors.WrapValidationError(err.Error())
+	}
+	return engine, nil
+}
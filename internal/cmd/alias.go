@@ -0,0 +1,176 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// AliasEntry is one alias listed by 'erst alias list'
+type AliasEntry struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Kind    string `json:"kind"` // "contract" or "account"
+	// Network is set for aliases from a network's section of config.yaml
+	Network string `json:"network,omitempty"`
+	// Source is "alias book" for aliases added with 'erst alias add', or
+	// "config"
+	Source string `json:"source"`
+}
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage short names for contract IDs and account addresses",
+	Long: `Keep an address book of short names for contracts and accounts, stored in
+~/.erst/aliases.json.
+
+Aliases are accepted wherever a contract ID or account is expected, such as
+'erst watch --contract amm' or 'erst build --source treasury', and decoded
+output shows the alias in place of the raw address. Aliases can also be set in
+the contracts and accounts sections of config.yaml, which take precedence.
+
+Available subcommands:
+  add     - Name a contract ID or account address
+  list    - Show all aliases
+  remove  - Delete an alias`,
+	Example: `  erst alias add amm CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC
+  erst alias add treasury GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7
+  erst watch --contract amm -n testnet`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var aliasAddCmd = &cobra.Command{
+	Use:   "add <name> <address>",
+	Short: "Name a contract ID or account address",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		book, err := config.LoadAliases()
+		if err != nil {
+			return err
+		}
+		name := strings.ToLower(args[0])
+		previous, existed := book.Aliases[name]
+		if err := book.Add(name, args[1]); err != nil {
+			return err
+		}
+		if err := config.SaveAliases(book); err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]string{"name": name, "address": book.Aliases[name]})
+		}
+		if existed && previous != book.Aliases[name] {
+			fmt.Printf("Alias %s changed from %s to %s\n", name, previous, book.Aliases[name])
+		} else {
+			fmt.Printf("Alias %s added for %s\n", name, book.Aliases[name])
+		}
+		return nil
+	},
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Delete an alias from the alias book",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		book, err := config.LoadAliases()
+		if err != nil {
+			return err
+		}
+		if !book.Remove(args[0]) {
+			return errors.WrapValidationError(fmt.Sprintf("no alias %q in the alias book", args[0]))
+		}
+		if err := config.SaveAliases(book); err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]string{"removed": strings.ToLower(args[0])})
+		}
+		fmt.Printf("Alias %s removed\n", strings.ToLower(args[0]))
+		return nil
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List aliases from the alias book and config",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := listAliases()
+		if err != nil {
+			return err
+		}
+		if jsonOutput() {
+			return printJSON(entries)
+		}
+		if len(entries) == 0 {
+			fmt.Println("No aliases defined. Add one with 'erst alias add <name> <address>'.")
+			return nil
+		}
+		for _, e := range entries {
+			scope := e.Source
+			if e.Network != "" {
+				scope += ", " + e.Network
+			}
+			fmt.Printf("  %-16s %s  (%s, %s)\n", e.Name, e.Address, e.Kind, scope)
+		}
+		return nil
+	},
+}
+
+// listAliases collects the aliases in effect, sorted by name and network
+func listAliases() ([]AliasEntry, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	book, err := config.LoadAliases()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []AliasEntry{}
+	add := func(aliases map[string]string, kind, network string) {
+		for name, address := range aliases {
+			source := "config"
+			if network == "" && book.Aliases[name] == address {
+				source = "alias book"
+			}
+			entries = append(entries, AliasEntry{Name: name, Address: address, Kind: kind, Network: network, Source: source})
+		}
+	}
+	add(cfg.ContractAliases, "contract", "")
+	add(cfg.AccountAliases, "account", "")
+	for network, p := range cfg.Profiles {
+		add(p.Contracts, "contract", network)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].Network < entries[j].Network
+	})
+	return entries, nil
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasAddCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+
+	rootCmd.AddCommand(aliasCmd)
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliases(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	contract := "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	account := keypair.MustRandom().Address()
+
+	book, err := config.LoadAliases()
+	require.NoError(t, err)
+	require.NoError(t, book.Add("amm", contract))
+	require.NoError(t, book.Add("treasury", account))
+	require.NoError(t, config.SaveAliases(book))
+
+	assert.Equal(t, contract, resolveAddress("testnet", "amm"))
+	assert.Equal(t, account, resolveAddress("testnet", "Treasury"))
+	assert.Equal(t, "unknown", resolveAddress("testnet", "unknown"))
+
+	source, err := resolveAccountFlag("source", "treasury")
+	require.NoError(t, err)
+	assert.Equal(t, account, source.Address())
+
+	entries, err := listAliases()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, AliasEntry{Name: "amm", Address: contract, Kind: "contract", Source: "alias book"}, entries[0])
+
+	registerAddressNames()
+	defer decoder.SetAddressNames(nil)
+	assert.Equal(t, "amm ("+contract+")", labelAddress(contract))
+}
@@ -25,6 +25,12 @@ var authDebugCmd = &cobra.Command{
 	Short: "Debug multi-signature and threshold-based authorization failures",
 	Long: `Analyze multi-signature authorization flows and identify which signatures or thresholds failed.
 
+The transaction's Soroban authorization entries are decoded into a tree of
+each signer, its nonce and signature expiration, and the invocations it
+authorizes. Signatures are verified against the network, and expired
+entries, invalid or wrong-network signatures, unsorted signers and reused
+nonces are flagged.
+
 Examples:
   erst auth-debug <tx-hash>
   erst auth-debug --detailed <tx-hash>
@@ -32,7 +38,7 @@ Examples:
 	Args: cobra.ExactArgs(1),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		switch rpc.Network(authNetworkFlag) {
-		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
 		default:
 			return errors.WrapInvalidNetwork(authNetworkFlag)
 		}
@@ -44,9 +50,7 @@ Examples:
 		opts := []rpc.ClientOption{
 			rpc.WithNetwork(rpc.Network(authNetworkFlag)),
 		}
-		if authRPCURLFlag != "" {
-			opts = append(opts, rpc.WithHorizonURL(authRPCURLFlag))
-		}
+		opts = append(opts, rpcEndpointOptions(authRPCURLFlag, authNetworkFlag)...)
 
 		client, err := rpc.NewClient(opts...)
 		if err != nil {
@@ -60,7 +64,10 @@ Examples:
 			return errors.WrapRPCConnectionFailed(err)
 		}
 
-		fmt.Printf("Transaction Envelope: %d bytes\n", len(resp.EnvelopeXdr))
+		authTree, err := authtrace.DecodeAuthEntries(resp.EnvelopeXdr, client.GetNetworkPassphrase(), resp.Ledger)
+		if err != nil {
+			return errors.WrapUnmarshalFailed(err, "TransactionEnvelope")
+		}
 
 		config := authtrace.AuthTraceConfig{
 			TraceCustomContracts: true,
@@ -72,23 +79,40 @@ Examples:
 		trace := tracker.GenerateTrace()
 		reporter := authtrace.NewDetailedReporter(trace)
 
-		if authJSONOutputFlag {
-			jsonStr, err := reporter.GenerateJSONString()
-			if err != nil {
-				return err
-			}
-			fmt.Println(jsonStr)
-		} else {
-			fmt.Println(reporter.GenerateReport())
-			if authDetailedFlag {
-				printDetailedAnalysis(reporter)
-			}
+		if authJSONOutputFlag || jsonOutput() {
+			return printJSON(authDebugOutput{AuthTrace: trace, Authorization: authTree})
+		}
+
+		fmt.Printf("Transaction Envelope: %d bytes\n", len(resp.EnvelopeXdr))
+		printAuthTree(authTree)
+		fmt.Println(reporter.GenerateReport())
+		if authDetailedFlag {
+			printDetailedAnalysis(reporter)
 		}
 
 		return nil
 	},
 }
 
+// authDebugOutput is the document emitted by 'erst auth-debug --json'
+type authDebugOutput struct {
+	*authtrace.AuthTrace
+	Authorization *authtrace.AuthTree `json:"authorization"`
+}
+
+func printAuthTree(tree *authtrace.AuthTree) {
+	if len(tree.Entries) == 0 {
+		fmt.Println("No Soroban authorization entries")
+		return
+	}
+	fmt.Println("\n--- AUTHORIZATION ENTRIES ---")
+	fmt.Print(tree.Render())
+	if issues := tree.Issues(); len(issues) > 0 {
+		fmt.Printf("%d issue(s) found in authorization entries\n", len(issues))
+	}
+	fmt.Println()
+}
+
 func printDetailedAnalysis(reporter *authtrace.DetailedReporter) {
 	metrics := reporter.SummaryMetrics()
 	fmt.Println("\n--- SUMMARY METRICS ---")
@@ -106,8 +130,9 @@ func printDetailedAnalysis(reporter *authtrace.DetailedReporter) {
 }
 
 func init() {
-	authDebugCmd.Flags().StringVarP(&authNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet)")
-	authDebugCmd.Flags().StringVar(&authRPCURLFlag, "rpc-url", "", "Custom Horizon RPC URL")
+	authDebugCmd.Flags().StringVarP(&authNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	authDebugCmd.Flags().StringVar(&authRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(authDebugCmd, "rpc-url")
 	authDebugCmd.Flags().BoolVar(&authDetailedFlag, "detailed", false, "Show detailed analysis and missing signatures")
 	authDebugCmd.Flags().BoolVar(&authJSONOutputFlag, "json", false, "Output as JSON")
 	rootCmd.AddCommand(authDebugCmd)
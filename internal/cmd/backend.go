@@ -0,0 +1,71 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+// Simulation backends selected with --backend
+const (
+	backendLocal = "local"
+	backendRPC   = "rpc"
+)
+
+// backendFlag holds --backend, shared by debug and simulate
+var backendFlag = backendLocal
+
+// rpcBackendConflicts are the flags whose effect the RPC node cannot
+// reproduce, since it simulates against its own current state
+var rpcBackendConflicts = []string{
+	"step", "break", "wasm", "snapshot", "host-version", "protocol-version", "mock-time",
+	"override-entry", "override-state", "at-ledger", "compare-remote", "profile",
+	"timestamp", "window", "cpu-limit", "mem-limit",
+}
+
+// validateBackend checks --backend and the flags combined with it
+func validateBackend(cmd *cobra.Command) error {
+	switch backendFlag {
+	case backendLocal:
+		return nil
+	case backendRPC:
+	default:
+		return errors.WrapValidationError(fmt.Sprintf("unknown backend %q (use %s or %s)", backendFlag, backendLocal, backendRPC))
+	}
+	var conflicts []string
+	for _, name := range rpcBackendConflicts {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			conflicts = append(conflicts, "--"+name)
+		}
+	}
+	if len(conflicts) > 0 {
+		return errors.WrapValidationError(fmt.Sprintf("%s cannot be used with --backend rpc, which simulates on the RPC node", strings.Join(conflicts, ", ")))
+	}
+	return nil
+}
+
+// newSimRunner returns the runner for --backend: erst-sim, built for
+// --host-version when given, or the RPC node client is connected to
+func newSimRunner(ctx context.Context, client *rpc.Client) (simulator.RunnerInterface, error) {
+	if backendFlag == backendRPC {
+		statusf("Simulating with the RPC node's simulateTransaction against the network's current state\n")
+		return simulator.NewRPCRunner(client), nil
+	}
+	simPath, err := hostSimPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runner, err := simulator.NewRunnerWithMockTime(simPath, tracingEnabled, mockTimeFlag)
+	if err != nil {
+		return nil, errors.WrapSimulatorNotFound(err.Error())
+	}
+	return runner, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// cpuLimitFlag and memLimitFlag hold --cpu-limit and --mem-limit, shared by
+// debug and simulate; zero keeps the host's budget
+var (
+	cpuLimitFlag uint64
+	memLimitFlag uint64
+)
+
+// applyBudgetLimits sets the budgets given with --cpu-limit and --mem-limit
+// on req
+func applyBudgetLimits(req *simulator.SimulationRequest) {
+	if cpuLimitFlag > 0 {
+		limit := cpuLimitFlag
+		req.CPULimit = &limit
+	}
+	if memLimitFlag > 0 {
+		limit := memLimitFlag
+		req.MemoryLimit = &limit
+	}
+}
+
+// budgetLimitsSummary describes the budgets applyBudgetLimits sets
+func budgetLimitsSummary() string {
+	cpu, mem := "default", "default"
+	if cpuLimitFlag > 0 {
+		cpu = fmt.Sprintf("%d", cpuLimitFlag)
+	}
+	if memLimitFlag > 0 {
+		mem = fmt.Sprintf("%d bytes", memLimitFlag)
+	}
+	return fmt.Sprintf("CPU %s, memory %s", cpu, mem)
+}
@@ -0,0 +1,325 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/resubmit"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// defaultBuildInclusionFee is the inclusion fee bid by built envelopes, in
+// stroops; preflight adds the resource fee on top
+const defaultBuildInclusionFee = 100
+
+var (
+	buildContractFlag    string
+	buildFunctionFlag    string
+	buildArgFlags        []string
+	buildSourceFlag      string
+	buildNetworkFlag     string
+	buildRPCURLFlag      string
+	buildRPCTokenFlag    string
+	buildFeeFlag         uint32
+	buildValidForFlag    time.Duration
+	buildNoPreflightFlag bool
+	buildWritePathFlag   string
+)
+
+var buildCmd = &cobra.Command{
+	Use:   "build --contract <id> --fn <name> [--arg name=value]...",
+	Short: "Build a transaction envelope that invokes a contract",
+	Long: `Build an unsigned InvokeHostFunction envelope calling a contract function,
+ready to pass to 'erst simulate' or 'erst resubmit'.
+
+Arguments are given by name or 0-based position and parsed against the
+function's types in the contract's on-chain spec, the same way as debug's
+--set-arg: scalars as is, vecs, maps, tuples and structs as JSON, and
+xdr:<base64> for a raw ScVal. Address arguments also take the name of a stellar
+CLI identity. Every argument of the function must be given.
+
+The sequence number is the source account's next one. Unless --no-preflight
+is set, the envelope is preflighted with RPC simulateTransaction, which fills
+in its footprint, resources, resource fee and the authorization entries the
+call needs. Entries for accounts other than the source must still be signed
+by those accounts.
+
+The envelope is printed as base64 on stdout, or written to --write; progress
+goes to stderr.`,
+	Example: `  erst build --contract CA3D... --fn transfer --source alice \
+    --arg from=alice --arg to=GBZX... --arg amount=100 -n testnet > tx.xdr
+  erst simulate --envelope tx.xdr -n testnet
+  erst resubmit tx.xdr --sign-with alice -n testnet`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch rpc.Network(buildNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+		default:
+			return errors.WrapInvalidNetwork(buildNetworkFlag)
+		}
+		for _, name := range []string{"contract", "fn", "source"} {
+			if !cmd.Flags().Changed(name) {
+				return errors.WrapCliArgumentRequired(name)
+			}
+		}
+		return nil
+	},
+	RunE: runBuild,
+}
+
+// BuildOutput is the document emitted by 'erst build --output json'
+type BuildOutput struct {
+	Contract    string `json:"contract"`
+	Function    string `json:"function"`
+	Call        string `json:"call"`
+	Source      string `json:"source"`
+	Sequence    int64  `json:"sequence"`
+	Fee         uint32 `json:"fee"`
+	Preflighted bool   `json:"preflighted"`
+	// AuthEntries counts the authorization entries preflight added, and
+	// UnsignedAuth those that need a signature from an account other than
+	// the source
+	AuthEntries  int    `json:"auth_entries"`
+	UnsignedAuth int    `json:"unsigned_auth"`
+	EnvelopeXdr  string `json:"envelope_xdr"`
+}
+
+func runBuild(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	source, err := resolveAccountFlag("source", buildSourceFlag)
+	if err != nil {
+		return err
+	}
+	buildContractFlag = resolveContract(buildNetworkFlag, buildContractFlag)
+	contractID, err := rpc.ParseContractID(buildContractFlag)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("invalid contract ID: %v", err))
+	}
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(buildNetworkFlag)),
+		rpc.WithToken(resolveRPCToken(buildRPCTokenFlag, buildNetworkFlag)),
+	}
+	opts = append(opts, rpcEndpointOptions(buildRPCURLFlag, buildNetworkFlag)...)
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching the spec of contract %s\n", buildContractFlag)
+	spec, err := cachedSpecLoader(ctx, client)(buildContractFlag)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to read the contract spec, which is needed to type the arguments: %v", err))
+	}
+	fn, ok := spec.Function(buildFunctionFlag)
+	if !ok {
+		return errors.WrapValidationError(fmt.Sprintf("contract has no function %q", buildFunctionFlag))
+	}
+	callArgs, err := buildInvokeArgs(buildNetworkFlag, spec, fn, buildArgFlags)
+	if err != nil {
+		return err
+	}
+
+	seq, err := client.GetAccountSequence(ctx, source.ToAccountId())
+	if err != nil {
+		return errors.WrapRPCConnectionFailed(err)
+	}
+
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID}
+	envelope := invokeEnvelope(source, seq+1, buildFeeFlag, contract, buildFunctionFlag, callArgs)
+	if buildValidForFlag > 0 {
+		envelope.V1.Tx.Cond = xdr.Preconditions{
+			Type:       xdr.PreconditionTypePrecondTime,
+			TimeBounds: &xdr.TimeBounds{MaxTime: xdr.TimePoint(time.Now().Add(buildValidForFlag).Unix())},
+		}
+	}
+
+	call := contractspec.DecodeInvocation(spec, buildContractFlag, buildFunctionFlag, callArgs)
+	out := BuildOutput{
+		Contract: buildContractFlag,
+		Function: buildFunctionFlag,
+		Call:     call.String(),
+		Source:   source.Address(),
+		Sequence: seq + 1,
+	}
+
+	if !buildNoPreflightFlag {
+		if err := preflightBuiltEnvelope(cmd, client, &envelope, &out); err != nil {
+			return err
+		}
+	}
+	out.Fee = uint32(envelope.V1.Tx.Fee)
+
+	out.EnvelopeXdr, err = xdr.MarshalBase64(envelope)
+	if err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+	if buildWritePathFlag != "" {
+		if err := os.WriteFile(buildWritePathFlag, []byte(out.EnvelopeXdr+"\n"), 0o600); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to write envelope: %v", err))
+		}
+	}
+
+	if jsonOutput() {
+		return printJSON(out)
+	}
+	fmt.Fprintf(os.Stderr, "%s %s\n", visualizer.Success(), out.Call)
+	fmt.Fprintf(os.Stderr, "  source %s, sequence %d, fee %d stroops\n", out.Source, out.Sequence, out.Fee)
+	if out.UnsignedAuth > 0 {
+		fmt.Fprintf(os.Stderr, "  %s %d of %d authorization entries must be signed by accounts other than the source\n", visualizer.Warning(), out.UnsignedAuth, out.AuthEntries)
+	}
+	if buildWritePathFlag != "" {
+		fmt.Fprintf(os.Stderr, "Envelope written to %s\n", buildWritePathFlag)
+		return nil
+	}
+	fmt.Println(out.EnvelopeXdr)
+	return nil
+}
+
+// preflightBuiltEnvelope fills in the footprint, resources, fee and
+// authorization entries of the invocation from RPC simulateTransaction
+func preflightBuiltEnvelope(cmd *cobra.Command, client *rpc.Client, envelope *xdr.TransactionEnvelope, out *BuildOutput) error {
+	unsigned, err := xdr.MarshalBase64(*envelope)
+	if err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+	fmt.Fprintf(os.Stderr, "Preflighting with simulateTransaction...\n")
+	preflight, err := client.SimulateTransaction(cmd.Context(), unsigned)
+	if err != nil {
+		return errors.WrapRPCConnectionFailed(err)
+	}
+	if preflight.Result.Error != "" {
+		return errors.WrapSimulationLogicError(fmt.Sprintf("preflight failed: %s; build with --no-preflight and run 'erst simulate' on the envelope to debug it", preflight.Result.Error))
+	}
+	if preflight.Result.TransactionData == "" {
+		logger.Logger.Warn("Preflight returned no transaction data, the envelope has no footprint")
+		return nil
+	}
+
+	op := envelope.V1.Tx.Operations[0].Body.InvokeHostFunctionOp
+	for _, result := range preflight.Result.Results {
+		for _, raw := range result.Auth {
+			var entry xdr.SorobanAuthorizationEntry
+			if err := xdr.SafeUnmarshalBase64(raw, &entry); err != nil {
+				return errors.WrapUnmarshalFailed(err, "SorobanAuthorizationEntry")
+			}
+			op.Auth = append(op.Auth, entry)
+			if entry.Credentials.Type == xdr.SorobanCredentialsTypeSorobanCredentialsAddress {
+				out.UnsignedAuth++
+			}
+		}
+	}
+	out.AuthEntries = len(op.Auth)
+
+	if _, err := resubmit.ApplyPreflight(envelope, preflight.Result.TransactionData); err != nil {
+		return errors.WrapValidationError(err.Error())
+	}
+	out.Preflighted = true
+	return nil
+}
+
+// invokeEnvelope builds an unsigned envelope with a single operation calling
+// function on contract with args
+func invokeEnvelope(source xdr.MuxedAccount, seq int64, fee uint32, contract xdr.ScAddress, function string, args []xdr.ScVal) xdr.TransactionEnvelope {
+	return xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: source,
+			Fee:           xdr.Uint32(fee),
+			SeqNum:        xdr.SequenceNumber(seq),
+			Operations: []xdr.Operation{{
+				Body: xdr.OperationBody{
+					Type: xdr.OperationTypeInvokeHostFunction,
+					InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+						HostFunction: xdr.HostFunction{
+							Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+							InvokeContract: &xdr.InvokeContractArgs{
+								ContractAddress: contract,
+								FunctionName:    xdr.ScSymbol(function),
+								Args:            args,
+							},
+						},
+					},
+				},
+			}},
+		}},
+	}
+}
+
+// buildInvokeArgs parses the --arg values of a call to fn, each
+// "<name|index>=<value>", against the function's input types. Every input
+// must be given exactly once. Address inputs also take aliases for network
+// and identity names.
+func buildInvokeArgs(network string, spec *contractspec.Spec, fn xdr.ScSpecFunctionV0, flags []string) ([]xdr.ScVal, error) {
+	values := make([]*xdr.ScVal, len(fn.Inputs))
+	for _, arg := range flags {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, errors.WrapValidationError(fmt.Sprintf("invalid --arg %q: expected <name|index>=<value>", arg))
+		}
+		idx, err := argIndex(key, fn.Inputs)
+		if err != nil {
+			return nil, err
+		}
+		if idx >= len(fn.Inputs) {
+			return nil, errors.WrapValidationError(fmt.Sprintf("argument %d is out of range: %s takes %d arguments", idx, fn.Name, len(fn.Inputs)))
+		}
+		input := fn.Inputs[idx]
+		if values[idx] != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("argument %s is given more than once", input.Name))
+		}
+		switch input.Type.Type {
+		case xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeMuxedAddress:
+			value = resolveAddress(network, value)
+		}
+		v, err := contractspec.ParseValue(spec, input.Type, value)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("invalid value for argument %s (%s): %v", input.Name, contractspec.TypeName(input.Type), err))
+		}
+		values[idx] = &v
+	}
+
+	var missing []string
+	args := make([]xdr.ScVal, len(values))
+	for i, v := range values {
+		if v == nil {
+			missing = append(missing, fmt.Sprintf("%s: %s", fn.Inputs[i].Name, contractspec.TypeName(fn.Inputs[i].Type)))
+			continue
+		}
+		args[i] = *v
+	}
+	if len(missing) > 0 {
+		return nil, errors.WrapValidationError(fmt.Sprintf("missing --arg for %s", strings.Join(missing, ", ")))
+	}
+	return args, nil
+}
+
+func init() {
+	buildCmd.Flags().StringVar(&buildContractFlag, "contract", "", "Contract ID (C...) to invoke")
+	_ = buildCmd.RegisterFlagCompletionFunc("contract", completeContractIDs)
+	buildCmd.Flags().StringVar(&buildFunctionFlag, "fn", "", "Contract function to call")
+	buildCmd.Flags().StringArrayVar(&buildArgFlags, "arg", nil, "Function argument as <name|index>=<value> (repeatable)")
+	buildCmd.Flags().StringVar(&buildSourceFlag, "source", "", "Source account (G...) or stellar CLI identity")
+	buildCmd.Flags().StringVarP(&buildNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	buildCmd.Flags().StringVar(&buildRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(buildCmd, "rpc-url")
+	buildCmd.Flags().StringVar(&buildRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	buildCmd.Flags().Uint32Var(&buildFeeFlag, "fee", defaultBuildInclusionFee, "Inclusion fee in stroops; preflight adds the resource fee")
+	buildCmd.Flags().DurationVar(&buildValidForFlag, "valid-for", 5*time.Minute, "Validity window of the transaction's time bound (0 for none)")
+	buildCmd.Flags().BoolVar(&buildNoPreflightFlag, "no-preflight", false, "Skip RPC preflight, leaving the footprint, resources and auth empty")
+	buildCmd.Flags().StringVar(&buildWritePathFlag, "write", "", "Write the envelope to this file instead of stdout")
+
+	rootCmd.AddCommand(buildCmd)
+}
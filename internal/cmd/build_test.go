@@ -0,0 +1,42 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildInvokeArgs(t *testing.T) {
+	spec := contractspec.StellarAssetSpec("native")
+	fn, ok := spec.Function("transfer")
+	require.True(t, ok)
+
+	from, to := keypair.MustRandom().Address(), keypair.MustRandom().Address()
+	args, err := buildInvokeArgs("testnet", spec, fn, []string{"amount=100", "from=" + from, "1=" + to})
+	require.NoError(t, err)
+	require.Len(t, args, 3)
+	assert.Equal(t, xdr.ScValTypeScvAddress, args[0].Type)
+	address, err := args[1].MustAddress().String()
+	require.NoError(t, err)
+	assert.Equal(t, to, address)
+	assert.Equal(t, xdr.ScValTypeScvI128, args[2].Type)
+
+	_, err = buildInvokeArgs("testnet", spec, fn, []string{"from=" + from})
+	assert.ErrorContains(t, err, "missing --arg for to: MuxedAddress, amount: i128")
+
+	_, err = buildInvokeArgs("testnet", spec, fn, []string{"amount=1", "amount=2"})
+	assert.ErrorContains(t, err, "given more than once")
+
+	_, err = buildInvokeArgs("testnet", spec, fn, []string{"amount=lots"})
+	assert.ErrorContains(t, err, "invalid value for argument amount")
+
+	_, err = buildInvokeArgs("testnet", spec, fn, []string{"3=1"})
+	assert.ErrorContains(t, err, "out of range")
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/dotandev/hintents/internal/cache"
 	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/spf13/cobra"
 )
 
@@ -77,6 +79,12 @@ var cacheStatusCmd = &cobra.Command{
 		fmt.Printf("Cache directory: %s\n", cacheDir)
 		fmt.Printf("Cache size: %s\n", formatBytes(size))
 		fmt.Printf("Files cached: %d\n", len(files))
+		if wasm, err := filepath.Glob(filepath.Join(cacheDir, rpc.WasmCacheDirName, "*.xdr")); err == nil {
+			fmt.Printf("Contract WASM cached: %d\n", len(wasm))
+		}
+		if results, err := filepath.Glob(filepath.Join(cacheDir, simulator.ResultCacheDirName, "*", "*.json")); err == nil {
+			fmt.Printf("Simulation results cached: %d\n", len(results))
+		}
 		fmt.Printf("Maximum size: %s\n", formatBytes(cache.DefaultConfig().MaxSizeBytes))
 
 		if size > cache.DefaultConfig().MaxSizeBytes {
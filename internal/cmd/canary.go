@@ -0,0 +1,297 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dotandev/hintents/internal/alert"
+	"github.com/dotandev/hintents/internal/canary"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/dotandev/hintents/internal/webhook"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/network"
+)
+
+var (
+	canaryNetworkFlag  string
+	canaryRPCURLFlag   string
+	canaryRPCTokenFlag string
+	canaryIntervalFlag time.Duration
+	canaryOnceFlag     bool
+	canaryNoSaveFlag   bool
+)
+
+// CanaryResult is the outcome of one canary run, printed by 'erst canary'
+type CanaryResult struct {
+	Name   string    `json:"name"`
+	Round  int       `json:"round"`
+	Time   time.Time `json:"time"`
+	TxHash string    `json:"tx_hash"`
+	Ledger uint32    `json:"ledger,omitempty"`
+	// Status is "success" or "error" from the simulation, or "failed" when
+	// the canary could not be simulated
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Transition is "failing" when the canary has just started failing and
+	// "recovered" when it has just started passing again
+	Transition string    `json:"transition,omitempty"`
+	Failures   int       `json:"consecutive_failures,omitempty"`
+	Since      time.Time `json:"since"`
+	SessionID  string    `json:"session_id,omitempty"`
+	Alerts     []string  `json:"alerts,omitempty"`
+}
+
+// canaryEnvelope is a canary with its envelope loaded
+type canaryEnvelope struct {
+	canary.Canary
+	EnvelopeXdr string
+	TxHash      string
+}
+
+var canaryCmd = &cobra.Command{
+	Use:   "canary <config-file>",
+	Short: "Re-simulate canary transactions on a schedule and alert when they break",
+	Long: `Run as a health-check daemon for a dApp: re-simulate a set of representative
+transaction envelopes, such as a typical swap, against the network's current
+state every --interval, and report when one that used to succeed starts to
+fail. This gives early warning of state changes, expiring entries, contract
+upgrades or protocol changes that break the dApp before users hit them.
+
+The config file lists the canaries in YAML, with envelope paths relative to it:
+
+  interval: 5m
+  canaries:
+    - name: swap
+      envelope: canaries/swap.xdr
+    - name: deposit
+      envelope: canaries/deposit.xdr
+
+Each envelope is simulated at the next ledger with the current state of its
+footprint, as by 'erst simulate'. Every result is printed; a canary that
+starts failing is saved as a session and sent to the failure webhook, and
+every failed run is checked against --alert-rules. A canary that passes
+again is reported as recovered.
+
+With --once, every canary runs a single time and the command exits with the
+check-failed status if any of them fails, for use from cron or CI.`,
+	Example: `  erst canary canaries.yaml --network testnet
+  erst canary canaries.yaml --interval 1m --webhook-url https://hooks.example.com/erst
+  erst canary canaries.yaml --once --output json`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if canaryIntervalFlag < 0 {
+			return errors.WrapValidationError("--interval must not be negative")
+		}
+		switch rpc.Network(canaryNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+			return nil
+		default:
+			return errors.WrapInvalidNetwork(canaryNetworkFlag)
+		}
+	},
+	RunE: runCanary,
+}
+
+func runCanary(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, err := canary.Load(args[0])
+	if err != nil {
+		return errors.WrapValidationError(err.Error())
+	}
+	interval := cfg.Interval
+	if canaryIntervalFlag > 0 {
+		interval = canaryIntervalFlag
+	}
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(canaryNetworkFlag)),
+		rpc.WithToken(resolveRPCToken(canaryRPCTokenFlag, canaryNetworkFlag)),
+	}
+	opts = append(opts, rpcEndpointOptions(canaryRPCURLFlag, canaryNetworkFlag)...)
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+
+	canaries := make([]canaryEnvelope, len(cfg.Canaries))
+	for i, c := range cfg.Canaries {
+		envelopeXdr, envelope, err := readEnvelope(c.Envelope, nil)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("canary %s: %v", c.Name, err))
+		}
+		hash, err := network.HashTransactionInEnvelope(envelope, client.GetNetworkPassphrase())
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("canary %s: failed to hash transaction: %v", c.Name, err))
+		}
+		canaries[i] = canaryEnvelope{Canary: c, EnvelopeXdr: envelopeXdr, TxHash: hex.EncodeToString(hash[:])}
+	}
+
+	runner, err := simulator.NewRunner("", false)
+	if err != nil {
+		return errors.WrapSimulatorNotFound(err.Error())
+	}
+	notifier, err := newFailureNotifier()
+	if err != nil {
+		return err
+	}
+	alerts, err := newAlertEngine(canaryNetworkFlag)
+	if err != nil {
+		return err
+	}
+	if alerts != nil {
+		defer alerts.Wait()
+	}
+
+	var store session.Store
+	if !canaryNoSaveFlag {
+		store, err = session.NewStore()
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+		}
+		defer store.Close()
+	}
+
+	if !canaryOnceFlag {
+		statusf("%s Running %d canaries on %s every %s (Ctrl+C to stop)\n",
+			visualizer.Symbol("magnify"), len(canaries), canaryNetworkFlag, interval)
+	}
+
+	tracker := canary.NewTracker()
+	for round := 1; ; round++ {
+		failing := 0
+		for _, c := range canaries {
+			if ctx.Err() != nil {
+				return nil
+			}
+			result := checkCanary(ctx, client, runner, store, notifier, alerts, tracker, c, round)
+			if result.Status != "success" {
+				failing++
+			}
+			var err error
+			switch {
+			case ndjsonOutput():
+				err = printNDJSON(result)
+			case jsonOutput():
+				err = printJSON(result)
+			default:
+				printCanaryResult(result)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if canaryOnceFlag {
+			if failing > 0 {
+				return errors.WrapCheckFailed(fmt.Sprintf("%d of %d canaries failed", failing, len(canaries)))
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// checkCanary simulates one canary and records, saves and alerts on the
+// result. Failures are reported in the result rather than stopping the run.
+func checkCanary(ctx context.Context, client *rpc.Client, runner simulator.RunnerInterface, store session.Store, notifier *webhook.SimulatorNotifier, alerts *alert.Engine, tracker *canary.Tracker, c canaryEnvelope, round int) CanaryResult {
+	result := CanaryResult{Name: c.Name, Round: round, Time: time.Now(), TxHash: c.TxHash}
+	// Only the result of each canary is printed
+	simReq, simResp, err := simulateEnvelopeWith(ctx, client, runner, c.EnvelopeXdr, func(string, ...interface{}) {})
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+	} else {
+		result.Status = simResp.Status
+		result.Error = simResp.Error
+		result.Ledger = simReq.LedgerSequence
+	}
+
+	transition, status := tracker.Record(c.Name, result.Status == "success")
+	result.Transition = string(transition)
+	result.Failures = status.Failures
+	result.Since = status.Since
+	if result.Status == "success" {
+		return result
+	}
+
+	if transition == canary.Failing && store != nil && simResp != nil {
+		data, err := newSimulatedSession(canaryNetworkFlag, client.HorizonURL, c.TxHash, &rpc.TransactionResponse{EnvelopeXdr: c.EnvelopeXdr}, simReq, simResp)
+		if err == nil {
+			err = store.Save(ctx, data)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save session for canary %s: %v\n", c.Name, err)
+		} else {
+			result.SessionID = data.ID
+		}
+	}
+
+	report := webhook.ReportData{Status: "error", Error: result.Error, Timestamp: result.Time}
+	if simResp != nil {
+		report = webhook.NewReport(simResp, c.TxHash, canaryNetworkFlag)
+	}
+	report.TxHash = c.TxHash
+	report.Network = canaryNetworkFlag
+	report.Ledger = result.Ledger
+	report.SessionID = result.SessionID
+	report.Source = "canary"
+	if transition == canary.Failing && notifier != nil {
+		notifier.Notify(report)
+	}
+	if alerts != nil {
+		for _, a := range alerts.Evaluate(ctx, alert.Failure{Report: report}) {
+			result.Alerts = append(result.Alerts, a.Rule)
+		}
+	}
+	return result
+}
+
+func printCanaryResult(result CanaryResult) {
+	stamp := result.Time.Format("15:04:05")
+	switch {
+	case result.Status == "success" && result.Transition == string(canary.Recovered):
+		fmt.Printf("%s %s %s recovered at ledger %d\n", stamp, visualizer.Success(), result.Name, result.Ledger)
+	case result.Status == "success":
+		fmt.Printf("%s %s %s ok at ledger %d\n", stamp, visualizer.Success(), result.Name, result.Ledger)
+	case result.Transition == string(canary.Failing):
+		fmt.Printf("%s %s %s started failing: %s\n", stamp, visualizer.Error(), result.Name, result.Error)
+	default:
+		fmt.Printf("%s %s %s still failing (%d runs since %s): %s\n", stamp, visualizer.Error(), result.Name,
+			result.Failures, result.Since.Format(time.RFC3339), result.Error)
+	}
+	if result.SessionID != "" {
+		fmt.Printf("    Session: %s\n", result.SessionID)
+	}
+	for _, rule := range result.Alerts {
+		fmt.Printf("    Alert:   %s\n", rule)
+	}
+}
+
+func init() {
+	canaryCmd.Flags().StringVarP(&canaryNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	canaryCmd.Flags().StringVar(&canaryRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(canaryCmd, "rpc-url")
+	canaryCmd.Flags().StringVar(&canaryRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	canaryCmd.Flags().DurationVar(&canaryIntervalFlag, "interval", 0, "How often to run the canaries, overriding the config (default 5m)")
+	canaryCmd.Flags().BoolVar(&canaryOnceFlag, "once", false, "Run every canary once and exit with code 2 if any fails")
+	canaryCmd.Flags().BoolVar(&canaryNoSaveFlag, "no-save", false, "Do not save a session when a canary starts failing")
+	addWebhookFlags(canaryCmd)
+	addAlertFlags(canaryCmd)
+
+	rootCmd.AddCommand(canaryCmd)
+}
@@ -0,0 +1,277 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/network"
+)
+
+var (
+	chainNetworkFlag   string
+	chainRPCURLFlag    string
+	chainRPCTokenFlag  string
+	chainKeepGoingFlag bool
+	chainCheckFlag     bool
+)
+
+var chainCmd = &cobra.Command{
+	Use:   "chain <tx-hash|envelope-file|->...",
+	Short: "Simulate dependent transactions in order, each seeing the writes of the last",
+	Long: `Simulate an ordered list of transactions as one flow, such as approve, then
+swap, then claim. Each transaction runs against the ledger state left by the
+successful transactions before it: their writes to the footprint are applied
+in memory before the next one is simulated, and nothing is submitted.
+
+Every argument is the hash of a transaction, whose envelope is fetched from
+RPC and re-run, or a file holding an unsubmitted envelope ("-" for stdin, at
+most once). Entries no earlier transaction wrote are fetched from RPC at the
+latest ledger when first needed. Ledger overrides seed the state before the
+first transaction.
+
+The chain stops at the first failing transaction unless --keep-going is set,
+in which case later transactions run without the failed one's writes.`,
+	Example: `  erst chain approve.xdr swap.xdr claim.xdr -n testnet
+  erst chain 5c0a1234... swap.xdr --override-entry balance.xdr -n testnet
+  erst chain approve.xdr swap.xdr --keep-going --output json`,
+	Args: cobra.MinimumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch rpc.Network(chainNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+		default:
+			return errors.WrapInvalidNetwork(chainNetworkFlag)
+		}
+		stdinArgs := 0
+		for _, arg := range args {
+			if arg == "-" {
+				stdinArgs++
+			}
+		}
+		if stdinArgs > 1 {
+			return errors.WrapValidationError("only one envelope can be read from stdin")
+		}
+
+		overrides, err := loadLedgerOverrides()
+		if err != nil {
+			return err
+		}
+		ledgerOverrides = overrides
+		return nil
+	},
+	RunE: runChain,
+}
+
+// ChainStepOutput is one transaction of the chain
+type ChainStepOutput struct {
+	Step        int                           `json:"step"`
+	Source      string                        `json:"source"` // the argument: a hash or an envelope file
+	TxHash      string                        `json:"tx_hash"`
+	Invocations []contractspec.Invocation     `json:"invocations,omitempty"`
+	Simulation  *simulator.SimulationResponse `json:"simulation"`
+}
+
+// ChainOutput is the document emitted by 'erst chain --output json'
+type ChainOutput struct {
+	Network string `json:"network"`
+	// Status is "success" when every step succeeded, else "error"
+	Status string `json:"status"`
+	// FailedStep is the first step that failed, 1-based
+	FailedStep int               `json:"failed_step,omitempty"`
+	Steps      []ChainStepOutput `json:"steps"`
+}
+
+// chainTx is a transaction to simulate as part of a chain
+type chainTx struct {
+	source      string
+	txHash      string
+	envelopeXdr string
+}
+
+func runChain(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(chainNetworkFlag)),
+		rpc.WithToken(resolveRPCToken(chainRPCTokenFlag, chainNetworkFlag)),
+	}
+	opts = append(opts, rpcEndpointOptions(chainRPCURLFlag, chainNetworkFlag)...)
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+
+	// Read every transaction up front so a bad argument fails before any work
+	txs := make([]chainTx, 0, len(args))
+	for _, arg := range args {
+		tx, err := loadChainTx(ctx, cmd.InOrStdin(), client, arg)
+		if err != nil {
+			return err
+		}
+		txs = append(txs, tx)
+	}
+
+	runner, err := simulator.NewRunner("", false)
+	if err != nil {
+		return errors.WrapSimulatorNotFound(err.Error())
+	}
+
+	var ledgerSequence uint32
+	if health, err := client.GetHealth(ctx); err == nil {
+		ledgerSequence = health.Result.LatestLedger + 1
+	} else {
+		logger.Logger.Warn("Failed to read the latest ledger", "error", err)
+	}
+	if len(ledgerOverrides) > 0 {
+		statusf("Applying %d ledger entry overrides\n", len(ledgerOverrides))
+	}
+
+	chain := simulator.NewChain(runner, func(ctx context.Context, keys []string) (map[string]string, error) {
+		entries, err := client.GetLedgerEntries(ctx, keys)
+		if err != nil {
+			return nil, errors.WrapRPCConnectionFailed(err)
+		}
+		return entries, nil
+	}, ledgerOverrides)
+
+	out := ChainOutput{Network: chainNetworkFlag, Status: "success"}
+	var results []*simulator.SimulationResponse
+	for i, tx := range txs {
+		if textOutput() {
+			fmt.Printf("\n=== Step %d/%d: %s ===\n", i+1, len(txs), tx.source)
+		}
+		statusf("Transaction: %s\n", tx.txHash)
+
+		keys, err := rpc.FootprintKeys(tx.envelopeXdr)
+		if err != nil {
+			return err
+		}
+		invocations, err := describeInvocations(ctx, client, tx.envelopeXdr)
+		if err != nil {
+			logger.Logger.Warn("Failed to decode contract invocations", "error", err)
+		}
+		if textOutput() {
+			printInvocations(invocations)
+		}
+
+		simReq := &simulator.SimulationRequest{
+			EnvelopeXdr:    tx.envelopeXdr,
+			Timestamp:      TimestampFlag,
+			LedgerSequence: ledgerSequence,
+		}
+		simResp, err := chain.Run(ctx, simReq, keys)
+		if err != nil {
+			if errors.Is(err, errors.ErrRPCConnectionFailed) {
+				return err
+			}
+			return errors.WrapSimulationFailed(err, "")
+		}
+		nameContractError(ctx, client, tx.envelopeXdr, simResp)
+		printSimulationResult(ctx, chainNetworkFlag, simResp)
+
+		results = append(results, simResp)
+		out.Steps = append(out.Steps, ChainStepOutput{
+			Step:        i + 1,
+			Source:      tx.source,
+			TxHash:      tx.txHash,
+			Invocations: invocations,
+			Simulation:  simResp,
+		})
+
+		if simResp.Status == "error" {
+			if out.FailedStep == 0 {
+				out.Status = "error"
+				out.FailedStep = i + 1
+			}
+			if !chainKeepGoingFlag {
+				break
+			}
+			statusf("Step %d failed; continuing without its writes\n", i+1)
+			continue
+		}
+		statusf("Applied %d storage writes to the chain state\n", len(simResp.StorageWrites))
+	}
+
+	if jsonOutput() {
+		if err := printJSON(out); err != nil {
+			return err
+		}
+	} else if textOutput() {
+		printChainSummary(out, len(txs))
+	}
+	if !chainCheckFlag {
+		return nil
+	}
+	return checkSimulations(results...)
+}
+
+// loadChainTx reads arg, the hash of an on-chain transaction or a file
+// holding an envelope ("-" for stdin)
+func loadChainTx(ctx context.Context, stdin io.Reader, client *rpc.Client, arg string) (chainTx, error) {
+	tx := chainTx{source: arg}
+	if _, statErr := os.Stat(arg); arg != "-" && statErr != nil && rpc.ValidateTransactionHash(arg) == nil {
+		statusf("Fetching transaction: %s\n", arg)
+		resp, err := client.GetTransaction(ctx, arg)
+		if err != nil {
+			return tx, errors.WrapRPCConnectionFailed(err)
+		}
+		tx.txHash = arg
+		tx.envelopeXdr = resp.EnvelopeXdr
+		return tx, nil
+	}
+
+	envelopeXdr, envelope, err := readEnvelope(arg, stdin)
+	if err != nil {
+		return tx, err
+	}
+	hash, err := network.HashTransactionInEnvelope(envelope, client.GetNetworkPassphrase())
+	if err != nil {
+		return tx, errors.WrapValidationError(fmt.Sprintf("failed to hash transaction in %s: %v", arg, err))
+	}
+	tx.txHash = hex.EncodeToString(hash[:])
+	tx.envelopeXdr = envelopeXdr
+	return tx, nil
+}
+
+func printChainSummary(out ChainOutput, total int) {
+	fmt.Printf("\nChain Summary:\n")
+	for _, step := range out.Steps {
+		status := step.Simulation.Status
+		if step.Simulation.Error != "" {
+			status += ": " + step.Simulation.Error
+		}
+		fmt.Printf("  %d. %s  %s\n", step.Step, step.Source, status)
+	}
+	for i := len(out.Steps); i < total; i++ {
+		fmt.Printf("  %d. skipped\n", i+1)
+	}
+	if out.FailedStep > 0 {
+		fmt.Printf("\nThe chain failed at step %d of %d.\n", out.FailedStep, total)
+		return
+	}
+	fmt.Printf("\nAll %d steps succeeded.\n", total)
+}
+
+func init() {
+	chainCmd.Flags().StringVarP(&chainNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	chainCmd.Flags().StringVar(&chainRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(chainCmd, "rpc-url")
+	chainCmd.Flags().StringVar(&chainRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	chainCmd.Flags().StringArrayVar(&overrideEntryFlags, "override-entry", nil, "Override a ledger entry before the first transaction as <ledger-key-xdr>=<entry-file>, or <entry-file> to derive the key (repeatable)")
+	chainCmd.Flags().StringVar(&overrideStateFlag, "override-state", "", "JSON file of ledger entries to override ({\"ledger_entries\": {key: entry}})")
+	chainCmd.Flags().BoolVar(&chainKeepGoingFlag, "keep-going", false, "Simulate the remaining transactions after one fails, without its writes")
+	chainCmd.Flags().BoolVar(&chainCheckFlag, "check", false, "Exit with code 2 when any transaction fails, 3 on RPC errors and 4 on simulator errors")
+
+	rootCmd.AddCommand(chainCmd)
+}
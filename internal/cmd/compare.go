@@ -74,7 +74,7 @@ Examples:
 			return errors.WrapValidationError(fmt.Sprintf("invalid transaction hash: %v", err))
 		}
 		switch rpc.Network(cmpNetworkFlag) {
-		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
 			// valid
 		default:
 			return errors.WrapInvalidNetwork(cmpNetworkFlag)
@@ -86,9 +86,10 @@ Examples:
 
 func init() {
 	compareCmd.Flags().StringVarP(&cmpNetworkFlag, "network", "n", string(rpc.Mainnet),
-		"Stellar network (testnet, mainnet, futurenet)")
+		"Stellar network (testnet, mainnet, futurenet, local)")
 	compareCmd.Flags().StringVar(&cmpRPCURLFlag, "rpc-url", "",
-		"Custom Soroban RPC URL")
+		"Custom RPC URL(s), comma-separated for failover")
+	markSensitive(compareCmd, "rpc-url")
 	compareCmd.Flags().StringVar(&cmpRPCTokenFlag, "rpc-token", "",
 		"RPC authentication token (or ERST_RPC_TOKEN env var)")
 	compareCmd.Flags().StringVar(&cmpLocalWasmFlag, "wasm", "",
@@ -134,32 +135,18 @@ func runCompare(cmd *cobra.Command, cmdArgs []string) error {
 	fmt.Println()
 
 	// ── Build RPC client ────────────────────────────────────────────────────
-	token := cmpRPCTokenFlag
-	if token == "" {
-		token = os.Getenv("ERST_RPC_TOKEN")
-	}
-	if token == "" {
-		if cfg, err := config.Load(); err == nil && cfg.RPCToken != "" {
-			token = cfg.RPCToken
-		}
-	}
+	token := resolveRPCToken(cmpRPCTokenFlag, cmpNetworkFlag)
 
 	clientOpts := []rpc.ClientOption{
 		rpc.WithNetwork(rpc.Network(cmpNetworkFlag)),
 		rpc.WithToken(token),
 	}
-	if cmpRPCURLFlag != "" {
-		urls := splitTrimmed(cmpRPCURLFlag)
+	if urls := rpcEndpoints(cmpRPCURLFlag, cmpNetworkFlag); len(urls) > 0 {
 		clientOpts = append(clientOpts, rpc.WithAltURLs(urls))
-	} else {
-		if cfg, err := config.Load(); err == nil {
-			if len(cfg.RpcUrls) > 0 {
-				clientOpts = append(clientOpts, rpc.WithAltURLs(cfg.RpcUrls))
-			} else if cfg.RpcUrl != "" {
-				clientOpts = append(clientOpts, rpc.WithHorizonURL(cfg.RpcUrl))
-			}
-		}
+	} else if cfg, err := config.Load(); err == nil && cfg.RpcUrl != "" && cmpNetworkFlag != string(rpc.Local) {
+		clientOpts = append(clientOpts, rpc.WithHorizonURL(cfg.RpcUrl))
 	}
+	clientOpts = append(clientOpts, rpcSharedOptions(cmpNetworkFlag)...)
 
 	client, err := rpc.NewClient(clientOpts...)
 	if err != nil {
@@ -233,14 +220,14 @@ func runBothPasses(
 	go func() {
 		defer wg.Done()
 		req := buildSimRequest(txResp, ledgerEntries, &cmpLocalWasmFlag, cmpArgsFlag)
-		localResult, localErr = runner.Run(req)
+		localResult, localErr = simulator.RunWithContext(ctx, runner, req)
 	}()
 
 	// Pass B – on-chain (no --wasm flag, uses whatever is in the ledger)
 	go func() {
 		defer wg.Done()
 		req := buildSimRequest(txResp, ledgerEntries, nil, nil)
-		onChainResult, onChainErr = runner.Run(req)
+		onChainResult, onChainErr = simulator.RunWithContext(ctx, runner, req)
 	}()
 
 	wg.Wait()
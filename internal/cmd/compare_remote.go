@@ -0,0 +1,56 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/dotandev/hintents/internal/compare"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// compareRemoteFlag holds --compare-remote, shared by debug and simulate
+var compareRemoteFlag bool
+
+// remoteComparison runs --compare-remote and prints the result as text. A
+// failed RPC simulation is only warned about, so the local result still
+// stands; the comparison is nil then.
+func remoteComparison(ctx context.Context, client *rpc.Client, envelopeXdr string, local *simulator.SimulationResponse) *compare.RemoteDiff {
+	diff, err := compareWithRemote(ctx, client, envelopeXdr, local)
+	if err != nil {
+		statusf("Warning: RPC simulateTransaction failed, skipping the remote comparison: %v\n", err)
+		return nil
+	}
+	if textOutput() {
+		compare.RenderRemote(diff)
+	}
+	return diff
+}
+
+// compareWithRemote simulates the envelope with the node's simulateTransaction
+// and compares the result with the local simulation
+func compareWithRemote(ctx context.Context, client *rpc.Client, envelopeXdr string, local *simulator.SimulationResponse) (*compare.RemoteDiff, error) {
+	preflight, err := client.SimulateTransaction(ctx, envelopeXdr)
+	if err != nil {
+		return nil, err
+	}
+	return compare.DiffRemote(local, remoteSimulation(preflight)), nil
+}
+
+// remoteSimulation extracts the comparable parts of a simulateTransaction
+// result
+func remoteSimulation(preflight *rpc.SimulateTransactionResponse) *compare.RemoteSimulation {
+	res := preflight.Result
+	remote := &compare.RemoteSimulation{
+		Error:        res.Error,
+		Events:       res.Events,
+		LatestLedger: res.LatestLedger,
+	}
+	remote.CPUInsns, remote.MemBytes = simulator.RPCCost(preflight)
+	if len(res.Results) > 0 {
+		remote.ReturnValue = res.Results[0].Xdr
+	}
+	return remote
+}
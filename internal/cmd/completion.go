@@ -4,7 +4,6 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -51,6 +50,9 @@ PowerShell:
   # To load completions for every new session, run:
   PS> erst completion powershell > erst.ps1
   # and source this file from your PowerShell profile.
+
+Completions include transaction hashes for "erst debug" and contract IDs for
+"erst watch --contract", taken from the local session history.
 `,
 	DisableFlagsInUseLine: true,
 	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
@@ -0,0 +1,96 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+// completionHistoryLimit is how many recent sessions completion looks at
+const completionHistoryLimit = 100
+
+// recentSessions returns the most recently accessed sessions, or nil when the
+// session store cannot be opened. Completion must never fail loudly.
+func recentSessions(cmd *cobra.Command) []*session.SessionData {
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Second)
+	defer cancel()
+
+	store, err := session.NewStore()
+	if err != nil {
+		return nil
+	}
+	defer store.Close()
+
+	sessions, err := store.List(ctx, completionHistoryLimit)
+	if err != nil {
+		return nil
+	}
+	return sessions
+}
+
+// completeTxHashes suggests transaction hashes from the session history
+func completeTxHashes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return txHashCompletions(recentSessions(cmd), args, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContractIDs suggests contract IDs seen in stored sessions
+func completeContractIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return contractIDCompletions(recentSessions(cmd), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// txHashCompletions returns the distinct hashes of sessions, most recent
+// first, that start with toComplete and are not already in args. Each is
+// described by its network and status.
+func txHashCompletions(sessions []*session.SessionData, args []string, toComplete string) []string {
+	seen := make(map[string]bool, len(args))
+	for _, arg := range args {
+		seen[arg] = true
+	}
+
+	var out []string
+	for _, s := range sessions {
+		if s.TxHash == "" || seen[s.TxHash] || !strings.HasPrefix(s.TxHash, toComplete) {
+			continue
+		}
+		seen[s.TxHash] = true
+		out = append(out, fmt.Sprintf("%s\t%s, %s", s.TxHash, s.Network, s.Status))
+	}
+	return out
+}
+
+// contractIDCompletions returns the distinct contract IDs emitting events in
+// the simulator responses of sessions that start with toComplete
+func contractIDCompletions(sessions []*session.SessionData, toComplete string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, s := range sessions {
+		if s.SimResponseJSON == "" {
+			continue
+		}
+		var resp simulator.SimulationResponse
+		if err := json.Unmarshal([]byte(s.SimResponseJSON), &resp); err != nil {
+			continue
+		}
+		for _, event := range resp.DiagnosticEvents {
+			if event.ContractID == nil {
+				continue
+			}
+			id := *event.ContractID
+			if id == "" || seen[id] || !strings.HasPrefix(id, toComplete) {
+				continue
+			}
+			seen[id] = true
+			out = append(out, fmt.Sprintf("%s\tseen in %s", id, s.TxHash))
+		}
+	}
+	return out
+}
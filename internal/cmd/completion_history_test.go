@@ -0,0 +1,75 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxHashCompletions(t *testing.T) {
+	sessions := []*session.SessionData{
+		{TxHash: "abc111", Network: "testnet", Status: "saved"},
+		{TxHash: "abc222", Network: "mainnet", Status: "resumed"},
+		{TxHash: "abc111", Network: "testnet", Status: "saved"},
+		{TxHash: "def333", Network: "testnet", Status: "saved"},
+		{TxHash: ""},
+	}
+
+	got := txHashCompletions(sessions, nil, "abc")
+	want := []string{"abc111\ttestnet, saved", "abc222\tmainnet, resumed"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = txHashCompletions(sessions, []string{"abc111"}, "")
+	want = []string{"abc222\tmainnet, resumed", "def333\ttestnet, saved"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected hashes already given to be skipped, got %q", got)
+	}
+}
+
+func TestContractIDCompletions(t *testing.T) {
+	sessions := []*session.SessionData{
+		{TxHash: "tx1", SimResponseJSON: `{"status":"success","diagnostic_events":[{"event_type":"contract","contract_id":"CAAA"},{"event_type":"system"},{"event_type":"contract","contract_id":"CBBB"}]}`},
+		{TxHash: "tx2", SimResponseJSON: `{"status":"error","diagnostic_events":[{"event_type":"contract","contract_id":"CAAA"}]}`},
+		{TxHash: "tx3", SimResponseJSON: `not json`},
+		{TxHash: "tx4"},
+	}
+
+	got := contractIDCompletions(sessions, "")
+	want := []string{"CAAA\tseen in tx1", "CBBB\tseen in tx1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := contractIDCompletions(sessions, "CB"); len(got) != 1 || got[0] != "CBBB\tseen in tx1" {
+		t.Errorf("expected prefix filtering, got %q", got)
+	}
+}
+
+// Every --contract flag completes the contracts of stored sessions
+func TestContractFlagsComplete(t *testing.T) {
+	seedSessions(t)
+	store, err := session.NewStore()
+	require.NoError(t, err)
+	require.NoError(t, store.Save(context.Background(), &session.SessionData{
+		ID: "a", Status: "saved", Network: "testnet", TxHash: "tx1",
+		SimResponseJSON: `{"status":"success","diagnostic_events":[{"event_type":"contract","contract_id":"CAAA"}]}`,
+	}))
+	store.Close()
+
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Flags().Lookup("contract") == nil {
+			continue
+		}
+		out := runErst(t, "__complete", cmd.Name(), "--contract", "")
+		assert.Contains(t, string(out), "CAAA\tseen in tx1", "erst %s --contract", cmd.Name())
+	}
+}
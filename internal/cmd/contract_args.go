@@ -0,0 +1,107 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// specLoader returns the spec of a contract, or an error when it has none
+type specLoader func(contractID string) (*contractspec.Spec, error)
+
+// describeInvocations decodes the contract calls in a transaction, naming and
+// typing each argument from the contract's on-chain spec. Specs that cannot
+// be fetched leave the arguments unlabelled rather than failing.
+func describeInvocations(ctx context.Context, client *rpc.Client, envelopeXdr string) ([]contractspec.Invocation, error) {
+	return invocationsFromEnvelope(ctx, envelopeXdr, cachedSpecLoader(ctx, client))
+}
+
+// cachedSpecLoader fetches contract specs from the network, parsing each
+// contract's WASM at most once. Stellar Asset Contracts, which have no WASM,
+// get their built-in interface.
+func cachedSpecLoader(ctx context.Context, client *rpc.Client) specLoader {
+	specs := make(map[string]*contractspec.Spec)
+	return func(contractID string) (*contractspec.Spec, error) {
+		if spec, ok := specs[contractID]; ok {
+			return spec, nil
+		}
+		wasm, err := rpc.FetchContractWasm(ctx, client, contractID)
+		if err != nil {
+			if asset, ok, sacErr := rpc.StellarAssetName(ctx, client, contractID); ok && sacErr == nil {
+				spec := contractspec.StellarAssetSpec(asset)
+				specs[contractID] = spec
+				return spec, nil
+			}
+			return nil, err
+		}
+		spec, err := contractspec.Parse(wasm)
+		if err != nil {
+			return nil, err
+		}
+		specs[contractID] = spec
+		return spec, nil
+	}
+}
+
+func invocationsFromEnvelope(ctx context.Context, envelopeXdr string, load specLoader) ([]contractspec.Invocation, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+
+	var invocations []contractspec.Invocation
+	for _, op := range envelope.Operations() {
+		hostFn := op.Body.InvokeHostFunctionOp
+		if hostFn == nil || hostFn.HostFunction.Type != xdr.HostFunctionTypeHostFunctionTypeInvokeContract {
+			continue
+		}
+		call := hostFn.HostFunction.InvokeContract
+		if call == nil {
+			continue
+		}
+
+		contractID, err := call.ContractAddress.String()
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract address: %w", err)
+		}
+
+		spec, err := load(contractID)
+		if err != nil {
+			logger.Logger.Warn("Contract spec unavailable, showing raw arguments", "contract_id", contractID, "error", err)
+			spec = nil
+		}
+		inv := contractspec.DecodeInvocation(spec, contractID, string(call.FunctionName), call.Args)
+		if decoded, name := pluginDecode(ctx, invocationRequest(contractID, inv.Function, call.Args)); decoded != nil {
+			inv.Decoder, inv.Summary, inv.Decoded = name, decoded.Summary, decoded.Fields
+		}
+		invocations = append(invocations, inv)
+	}
+	return invocations, nil
+}
+
+func printInvocations(invocations []contractspec.Invocation) {
+	if len(invocations) == 0 {
+		return
+	}
+	fmt.Printf("\nContract Invocations:\n")
+	for _, inv := range invocations {
+		fmt.Printf("  %s\n", labelAddress(inv.ContractID))
+		fmt.Printf("    %s\n", inv.String())
+		switch {
+		case inv.Decoder != "" && inv.Summary != "":
+			fmt.Printf("    %s: %s\n", inv.Decoder, inv.Summary)
+		case inv.Summary != "":
+			fmt.Printf("    Stellar Asset Contract: %s\n", inv.Summary)
+		}
+		if !inv.SpecFound {
+			fmt.Printf("    (contract spec unavailable; argument names and types unknown)\n")
+		}
+	}
+}
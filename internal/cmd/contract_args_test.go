@@ -0,0 +1,81 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func invokeContractEnvelope(t *testing.T, target xdr.ContractId, fn string, args ...xdr.ScVal) string {
+	t.Helper()
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress("GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"),
+				Operations: []xdr.Operation{{
+					Body: xdr.OperationBody{
+						Type: xdr.OperationTypeInvokeHostFunction,
+						InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+							HostFunction: xdr.HostFunction{
+								Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+								InvokeContract: &xdr.InvokeContractArgs{
+									ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &target},
+									FunctionName:    xdr.ScSymbol(fn),
+									Args:            args,
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+	encoded, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+	return encoded
+}
+
+func TestInvocationsFromEnvelope(t *testing.T) {
+	count := xdr.Uint32(5)
+	envelopeXdr := invokeContractEnvelope(t, xdr.ContractId{7}, "increment", xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &count})
+
+	entry := xdr.ScSpecEntry{
+		Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0,
+		FunctionV0: &xdr.ScSpecFunctionV0{
+			Name:    "increment",
+			Inputs:  []xdr.ScSpecFunctionInputV0{{Name: "by", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeU32}}},
+			Outputs: []xdr.ScSpecTypeDef{{Type: xdr.ScSpecTypeScSpecTypeU32}},
+		},
+	}
+	raw, err := entry.MarshalBinary()
+	require.NoError(t, err)
+	spec, err := contractspec.ParseEntries(raw)
+	require.NoError(t, err)
+
+	var requested string
+	invocations, err := invocationsFromEnvelope(context.Background(), envelopeXdr, func(contractID string) (*contractspec.Spec, error) {
+		requested = contractID
+		return spec, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, invocations, 1)
+	assert.Equal(t, requested, invocations[0].ContractID)
+	assert.Equal(t, "increment(by: u32 = 5) -> u32", invocations[0].String())
+
+	invocations, err = invocationsFromEnvelope(context.Background(), envelopeXdr, func(string) (*contractspec.Spec, error) {
+		return nil, fmt.Errorf("no wasm")
+	})
+	require.NoError(t, err)
+	require.Len(t, invocations, 1)
+	assert.False(t, invocations[0].SpecFound)
+	assert.Equal(t, "increment(5)", invocations[0].String())
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// nameContractError resolves a contract error code in simResp to the case of
+// the failing contract's error enum, e.g. Error(Contract, #4) to
+// Error::InsufficientBalance. Like analyzeArchival it is advisory, so
+// failures are only logged.
+func nameContractError(ctx context.Context, client *rpc.Client, envelopeXdr string, simResp *simulator.SimulationResponse) {
+	exp := simResp.ErrorExplanation
+	if client == nil || exp == nil || exp.ContractCode == nil {
+		return
+	}
+
+	contractID := ""
+	if path := simulator.FailurePath(simResp.CallTree); len(path) > 0 {
+		contractID = path[len(path)-1].Contract
+	} else if hash, err := getContractIDFromEnvelope(envelopeXdr); err == nil && hash != nil {
+		contractID = hex.EncodeToString(hash[:])
+	}
+	if contractID == "" {
+		return
+	}
+
+	code, err := rpc.FetchContractWasm(ctx, client, contractID)
+	if err != nil {
+		logger.Logger.Debug("Failed to fetch WASM to name contract error", "contract_id", contractID, "error", err)
+		return
+	}
+	spec, err := contractspec.Parse(code)
+	if err != nil {
+		logger.Logger.Debug("Failed to read spec to name contract error", "contract_id", contractID, "error", err)
+		return
+	}
+	if name, doc, ok := spec.ContractError(*exp.ContractCode); ok {
+		exp.ContractError, exp.ContractErrorDoc = name, doc
+	}
+}
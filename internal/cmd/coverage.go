@@ -0,0 +1,179 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/coverage"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/dotandev/hintents/internal/wat"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	coverageNetworkFlag   string
+	coverageRPCURLFlag    string
+	coverageRPCTokenFlag  string
+	coverageFailUnderFlag float64
+)
+
+var coverageCmd = &cobra.Command{
+	Use:   "coverage <tx-hash|envelope-file|->...",
+	Short: "Show which contract functions a set of transactions exercised",
+	Long: `Simulate one or more transactions and report, for every contract they
+called, which of its exported functions were hit and which were not.
+
+Exported functions are read from each contract's spec, or from its WASM
+exports when it has no spec. Contracts without WASM, such as Stellar asset
+contracts, list only the functions that were called.
+
+Pass every transaction of an integration flow to see its combined coverage.
+With --fail-under the command exits with an error when coverage is lower than
+the given percentage, for use in CI.`,
+	Example: `  erst coverage 5c0a1234... 9f3b5678... --network testnet
+  erst coverage deposit.xdr swap.xdr withdraw.xdr -n testnet --fail-under 80`,
+	Args: cobra.MinimumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if coverageFailUnderFlag < 0 || coverageFailUnderFlag > 100 {
+			return errors.WrapValidationError(fmt.Sprintf("--fail-under must be between 0 and 100, got %g", coverageFailUnderFlag))
+		}
+		switch rpc.Network(coverageNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+			return nil
+		default:
+			return errors.WrapInvalidNetwork(coverageNetworkFlag)
+		}
+	},
+	RunE: runCoverage,
+}
+
+func runCoverage(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(coverageNetworkFlag)),
+		rpc.WithToken(resolveRPCToken(coverageRPCTokenFlag, coverageNetworkFlag)),
+	}
+	opts = append(opts, rpcEndpointOptions(coverageRPCURLFlag, coverageNetworkFlag)...)
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+
+	runner, err := simulator.NewRunner("", false)
+	if err != nil {
+		return errors.WrapSimulatorNotFound(err.Error())
+	}
+
+	collector := coverage.NewCollector()
+	for _, arg := range args {
+		sim, err := simulateTxOrEnvelope(ctx, cmd.InOrStdin(), client, runner, arg)
+		if err != nil {
+			return err
+		}
+		if len(sim.Resp.CallTree) == 0 {
+			statusf("Warning: %s made no contract calls the simulator reported\n", arg)
+		}
+		collector.Add(sim.Resp.CallTree)
+	}
+
+	report := collector.Report(func(contractID string) ([]string, error) {
+		return contractExports(ctx, client, contractID)
+	})
+
+	if jsonOutput() {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printCoverage(report)
+	}
+
+	if coverageFailUnderFlag > 0 && report.Percent() < coverageFailUnderFlag {
+		return errors.WrapValidationError(fmt.Sprintf("coverage %.1f%% is below --fail-under %g%%", report.Percent(), coverageFailUnderFlag))
+	}
+	return nil
+}
+
+// contractExports lists a contract's public functions from its spec, falling
+// back to the function exports of its WASM
+func contractExports(ctx context.Context, client *rpc.Client, contractID string) ([]string, error) {
+	code, err := rpc.FetchContractWasm(ctx, client, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := contractspec.Parse(code)
+	if err == nil {
+		var names []string
+		for _, entry := range spec.Entries {
+			if entry.Kind == xdr.ScSpecEntryKindScSpecEntryFunctionV0 && entry.FunctionV0 != nil {
+				names = append(names, string(entry.FunctionV0.Name))
+			}
+		}
+		return names, nil
+	}
+	if err != contractspec.ErrNoSpec {
+		return nil, err
+	}
+
+	module, err := wat.ParseModule(code)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, exp := range module.ExportedFunctions() {
+		names = append(names, exp.Name)
+	}
+	return names, nil
+}
+
+func printCoverage(report *coverage.Report) {
+	if len(report.Contracts) == 0 {
+		fmt.Println("The transactions called no contracts.")
+		return
+	}
+
+	for _, c := range report.Contracts {
+		if c.ExportsKnown {
+			fmt.Printf("\n%s  %d/%d functions (%.0f%%)\n", c.Contract, c.Covered, c.Total, c.Percent())
+		} else {
+			fmt.Printf("\n%s  exports unknown\n", c.Contract)
+		}
+		for _, f := range c.Functions {
+			switch {
+			case f.Calls == 0:
+				fmt.Printf("  %s %s\n", visualizer.Warning(), f.Name)
+			case f.Failed > 0:
+				fmt.Printf("  %s %s  %d calls, %d failed\n", visualizer.Success(), f.Name, f.Calls, f.Failed)
+			default:
+				fmt.Printf("  %s %s  %d calls\n", visualizer.Success(), f.Name, f.Calls)
+			}
+		}
+		if missed := c.Missed(); len(missed) > 0 {
+			fmt.Printf("  not exercised: %s\n", strings.Join(missed, ", "))
+		}
+	}
+
+	fmt.Printf("\nCoverage: %d/%d exported functions (%.1f%%) across %d contracts and %d transactions\n",
+		report.Covered, report.Total, report.Percent(), len(report.Contracts), report.Transactions)
+}
+
+func init() {
+	coverageCmd.Flags().StringVarP(&coverageNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	coverageCmd.Flags().StringVar(&coverageRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(coverageCmd, "rpc-url")
+	coverageCmd.Flags().StringVar(&coverageRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	coverageCmd.Flags().Float64Var(&coverageFailUnderFlag, "fail-under", 0, "Exit with an error when coverage is below this percentage")
+
+	rootCmd.AddCommand(coverageCmd)
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/dotandev/hintents/internal/daemon"
 	"github.com/dotandev/hintents/internal/errors"
 	"github.com/dotandev/hintents/internal/rpc"
-	"github.com/dotandev/hintents/internal/telemetry"
 	"github.com/spf13/cobra"
 )
 
@@ -22,8 +21,6 @@ var (
 	daemonNetwork   string
 	daemonRPCURL    string
 	daemonAuthToken string
-	daemonTracing   bool
-	daemonOTLPURL   string
 )
 
 var daemonCmd = &cobra.Command{
@@ -35,6 +32,9 @@ Endpoints:
   - debug_transaction: Debug a failed transaction
   - get_trace: Get execution traces for a transaction
 
+Prometheus metrics are served on /metrics alongside /rpc and /health. Failed
+simulations are sent to the failure webhook and checked against --alert-rules.
+
 Example:
   erst daemon --port 8080 --network testnet
   erst daemon --port 8080 --auth-token secret123`,
@@ -42,33 +42,36 @@ Example:
 		ctx := cmd.Context()
 
 		// Initialize OpenTelemetry if enabled
-		var cleanup func()
-		if daemonTracing {
-			var err error
-			cleanup, err = telemetry.Init(ctx, telemetry.Config{
-				Enabled:     true,
-				ExporterURL: daemonOTLPURL,
-				ServiceName: "erst-daemon",
-			})
-			if err != nil {
-				return errors.WrapValidationError(fmt.Sprintf("failed to initialize telemetry: %v", err))
-			}
-			defer cleanup()
+		cleanup, err := initTracing(ctx, cmd, "erst-daemon")
+		if err != nil {
+			return err
 		}
+		defer cleanup()
 
 		// Validate network
 		switch rpc.Network(daemonNetwork) {
-		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
 		default:
 			return errors.WrapInvalidNetwork(daemonNetwork)
 		}
 
+		notifier, err := newFailureNotifier()
+		if err != nil {
+			return err
+		}
+		alerts, err := newAlertEngine(daemonNetwork)
+		if err != nil {
+			return err
+		}
+
 		// Create server
 		server, err := daemon.NewServer(daemon.Config{
 			Port:      daemonPort,
 			Network:   daemonNetwork,
 			RPCURL:    daemonRPCURL,
 			AuthToken: daemonAuthToken,
+			Notifier:  notifier,
+			Alerts:    alerts,
 		})
 		if err != nil {
 			return errors.WrapValidationError(fmt.Sprintf("failed to create server: %v", err))
@@ -95,6 +98,13 @@ Example:
 		if daemonAuthToken != "" {
 			fmt.Println("Authentication: enabled")
 		}
+		if notifier.IsEnabled() {
+			fmt.Println("Failure webhook: enabled")
+		}
+		if alerts != nil {
+			fmt.Printf("Alert rules: %s\n", alertRulesFlag)
+			defer alerts.Wait()
+		}
 
 		// Start server
 		return server.Start(ctx, daemonPort)
@@ -103,11 +113,13 @@ Example:
 
 func init() {
 	daemonCmd.Flags().StringVarP(&daemonPort, "port", "p", "8080", "Port to listen on")
-	daemonCmd.Flags().StringVarP(&daemonNetwork, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet)")
+	daemonCmd.Flags().StringVarP(&daemonNetwork, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet, local)")
 	daemonCmd.Flags().StringVar(&daemonRPCURL, "rpc-url", "", "Custom Horizon RPC URL to use")
+	markSensitive(daemonCmd, "rpc-url")
 	daemonCmd.Flags().StringVar(&daemonAuthToken, "auth-token", "", "Authentication token for API access")
-	daemonCmd.Flags().BoolVar(&daemonTracing, "tracing", false, "Enable OpenTelemetry tracing")
-	daemonCmd.Flags().StringVar(&daemonOTLPURL, "otlp-url", "http://localhost:4318", "OTLP exporter URL")
+	addTracingFlags(daemonCmd)
+	addWebhookFlags(daemonCmd)
+	addAlertFlags(daemonCmd)
 
 	rootCmd.AddCommand(daemonCmd)
 }
@@ -15,10 +15,16 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dotandev/hintents/internal/compare"
 	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/contractspec"
 	"github.com/dotandev/hintents/internal/decoder"
 	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/explorer"
+	"github.com/dotandev/hintents/internal/heuristic"
+	"github.com/dotandev/hintents/internal/hooks"
 	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/report"
 	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/security"
 	"github.com/dotandev/hintents/internal/session"
@@ -34,6 +40,7 @@ import (
 	"github.com/stellar/go-stellar-sdk/xdr"
 	"go.opentelemetry.io/otel/attribute"
 )
+
 var (
 	networkFlag         string
 	rpcURLFlag          string
@@ -54,6 +61,8 @@ var (
 	protocolVersionFlag uint32
 	themeFlag           string
 	mockTimeFlag        int64
+	debugCheckFlag      bool
+	debugTimeoutFlag    time.Duration
 )
 
 // DebugCommand holds dependencies for the debug command
@@ -80,7 +89,7 @@ Example:
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			// Validate network flag
 			switch rpc.Network(networkFlag) {
-			case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
+			case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
 				return nil
 			default:
 				return errors.WrapInvalidNetwork(networkFlag)
@@ -90,8 +99,9 @@ Example:
 	}
 
 	// Set up flags
-	cmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet)")
-	cmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Horizon RPC URL to use")
+	cmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet, local)")
+	cmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(cmd, "rpc-url")
 	cmd.Flags().StringVar(&rpcTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
 
 	return cmd
@@ -115,9 +125,7 @@ func (d *DebugCommand) runDebug(cmd *cobra.Command, args []string) error {
 		rpc.WithNetwork(rpc.Network(networkFlag)),
 		rpc.WithToken(token),
 	}
-	if rpcURLFlag != "" {
-		opts = append(opts, rpc.WithHorizonURL(rpcURLFlag))
-	}
+	opts = append(opts, rpcEndpointOptions(rpcURLFlag, networkFlag)...)
 
 	client, err := rpc.NewClient(opts...)
 	if err != nil {
@@ -170,7 +178,8 @@ Local WASM Replay Mode:
   # Debug on testnet
   erst debug --network testnet abc123...def789
 
-  # Debug and compare results between networks
+  # Debug and compare results between networks; a transaction that never ran
+  # on the compare network is replayed against that network's current state
   erst debug --network mainnet --compare-network testnet abc123...def789
 
   # Debug and save the session
@@ -182,24 +191,93 @@ Local WASM Replay Mode:
   # Local WASM replay (no network required)
   erst debug --wasm ./contract.wasm --args "arg1" --args "arg2"
 
+  # Would the transaction succeed if this storage entry looked different?
+  erst debug --override-entry balance_entry.xdr <tx-hash>
+
+  # Would the call have succeeded with a different amount?
+  erst debug --set-arg amount=12345 <tx-hash>
+
+  # Step through contract calls, stopping whenever "transfer" is invoked
+  erst debug --step --break transfer <tx-hash>
+
+  # Debug many transactions in parallel and print one summary
+  erst debug --file hashes.txt --concurrency 8
+  erst debug <tx-hash-1> <tx-hash-2> <tx-hash-3>
+
   # Demo mode (test color output, no network required)
   erst debug --demo`,
-	Args: cobra.MaximumNArgs(1),
+	Args: cobra.ArbitraryArgs,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateHostVersion(); err != nil {
+			return err
+		}
+		if err := validateBackend(cmd); err != nil {
+			return err
+		}
+
 		// Demo mode or local WASM replay don't need transaction hash
 		if demoMode || wasmPath != "" {
+			if OutputFlag == OutputMarkdown || OutputFlag == OutputSARIF {
+				return errors.WrapValidationError(OutputFlag + " output requires a transaction hash")
+			}
+			if ndjsonOutput() {
+				return errors.WrapValidationError("ndjson output streams batch results and needs several transaction hashes or --file")
+			}
 			return nil
 		}
 
-		if len(args) == 0 {
-			return errors.WrapValidationError("transaction hash is required when not using --wasm or --demo flag")
+		if isBatchMode(args) {
+			hashes, err := collectTxHashes(args)
+			if err != nil {
+				return err
+			}
+			batchHashes = hashes
+		} else {
+			if len(args) == 0 {
+				return errors.WrapValidationError("transaction hash is required when not using --wasm or --demo flag")
+			}
+
+			if err := rpc.ValidateTransactionHash(args[0]); err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("invalid transaction hash format: %v", err))
+			}
+		}
+
+		if (OutputFlag == OutputMarkdown || OutputFlag == OutputSARIF) && batchHashes != nil {
+			return errors.WrapValidationError(OutputFlag + " output describes a single transaction and cannot be combined with batch mode")
+		}
+		if ndjsonOutput() && batchHashes == nil {
+			return errors.WrapValidationError("ndjson output streams batch results and needs several transaction hashes or --file")
+		}
+
+		if stepFlag || len(breakpointFlags) > 0 {
+			if batchHashes != nil || compareNetworkFlag != "" {
+				return errors.WrapValidationError("--step cannot be combined with batch mode or --compare-network")
+			}
+			if !textOutput() {
+				return errors.WrapValidationError("--step is interactive and needs text output")
+			}
+			stepFlag = true
+		}
+
+		if invocationEdited() && batchHashes != nil {
+			return errors.WrapValidationError("--set-arg and --set-fn cannot be combined with batch mode")
+		}
+
+		if err := validateProfileFlags(cmd.Flags().Changed("profile-format")); err != nil {
+			return err
+		}
+		if _, err := explorerName(); err != nil {
+			return err
 		}
 
-		if err := rpc.ValidateTransactionHash(args[0]); err != nil {
-			return errors.WrapValidationError(fmt.Sprintf("invalid transaction hash format: %v", err))
+		overrides, err := loadLedgerOverrides()
+		if err != nil {
+			return err
 		}
+		ledgerOverrides = overrides
 
-		if !cmd.Flags().Changed("network") {
+		// Network auto-detection probes a single hash, so batches use --network as given
+		if !cmd.Flags().Changed("network") && batchHashes == nil {
 			token := rpcTokenFlag
 			if token == "" {
 				token = os.Getenv("ERST_RPC_TOKEN")
@@ -208,13 +286,13 @@ Local WASM Replay Mode:
 			defer probeCancel()
 			if resolved, err := rpc.ResolveNetwork(probeCtx, args[0], token); err == nil {
 				networkFlag = string(resolved)
-				fmt.Printf("Resolved network: %s\n", networkFlag)
+				statusf("Resolved network: %s\n", networkFlag)
 			}
 		}
 
 		// Validate network flag
 		switch rpc.Network(networkFlag) {
-		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
 			// valid
 		default:
 			return errors.WrapInvalidNetwork(networkFlag)
@@ -223,7 +301,7 @@ Local WASM Replay Mode:
 		// Validate compare network flag if present
 		if compareNetworkFlag != "" {
 			switch rpc.Network(compareNetworkFlag) {
-			case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
+			case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
 				// valid
 			default:
 				return errors.WrapInvalidNetwork(compareNetworkFlag)
@@ -257,20 +335,17 @@ Local WASM Replay Mode:
 
 		// Network transaction replay mode
 		ctx := cmd.Context()
-		txHash := cmdArgs[0]
+		var txHash string
+		if batchHashes == nil {
+			txHash = cmdArgs[0]
+		}
 
 		// Initialize OpenTelemetry if enabled
-		if tracingEnabled {
-			cleanup, err := telemetry.Init(ctx, telemetry.Config{
-				Enabled:     true,
-				ExporterURL: otlpExporterURL,
-				ServiceName: "erst",
-			})
-			if err != nil {
-				return errors.WrapValidationError(fmt.Sprintf("failed to initialize telemetry: %v", err))
-			}
-			defer cleanup()
+		cleanup, err := initTracing(ctx, cmd, "erst")
+		if err != nil {
+			return err
 		}
+		defer cleanup()
 
 		// Start root span
 		tracer := telemetry.GetTracer()
@@ -279,43 +354,28 @@ Local WASM Replay Mode:
 			attribute.String("transaction.hash", txHash),
 			attribute.String("network", networkFlag),
 		)
+		if batchHashes != nil {
+			span.SetAttributes(attribute.Int("transaction.count", len(batchHashes)))
+		}
 		defer span.End()
 
 		var horizonURL string
-		token := rpcTokenFlag
-		if token == "" {
-			token = os.Getenv("ERST_RPC_TOKEN")
-		}
-		if token == "" {
-			if cfg, err := config.Load(); err == nil && cfg.RPCToken != "" {
-				token = cfg.RPCToken
-			}
-		}
+		token := resolveRPCToken(rpcTokenFlag, networkFlag)
 
 		opts := []rpc.ClientOption{
 			rpc.WithNetwork(rpc.Network(networkFlag)),
 			rpc.WithToken(token),
 		}
 
-		if rpcURLFlag != "" {
-			urls := strings.Split(rpcURLFlag, ",")
-			for i := range urls {
-				urls[i] = strings.TrimSpace(urls[i])
-			}
+		if urls := rpcEndpoints(rpcURLFlag, networkFlag); len(urls) > 0 {
 			opts = append(opts, rpc.WithAltURLs(urls))
 			horizonURL = urls[0]
-		} else {
-			cfg, err := config.Load()
-			if err == nil {
-				if len(cfg.RpcUrls) > 0 {
-					opts = append(opts, rpc.WithAltURLs(cfg.RpcUrls))
-					horizonURL = cfg.RpcUrls[0]
-				} else if cfg.RpcUrl != "" {
-					opts = append(opts, rpc.WithHorizonURL(cfg.RpcUrl))
-					horizonURL = cfg.RpcUrl
-				}
-			}
+		} else if cfg, err := config.Load(); err == nil && cfg.RpcUrl != "" && networkFlag != string(rpc.Local) {
+			// rpc_url defaults to a public endpoint, which a local node is not
+			opts = append(opts, rpc.WithHorizonURL(cfg.RpcUrl))
+			horizonURL = cfg.RpcUrl
 		}
+		opts = append(opts, rpcSharedOptions(networkFlag)...)
 
 		client, err := rpc.NewClient(opts...)
 		if err != nil {
@@ -329,13 +389,28 @@ Local WASM Replay Mode:
 
 		if noCacheFlag {
 			client.CacheEnabled = false
-			fmt.Println("🚫 Cache disabled by --no-cache flag")
+			statusf("🚫 Cache disabled by --no-cache flag\n")
 		}
 
-		fmt.Printf("Debugging transaction: %s\n", txHash)
-		fmt.Printf("Primary Network: %s\n", networkFlag)
+		if err := checkNetworkProtocol(ctx, client, networkFlag, protocolVersionFlag); err != nil {
+			return err
+		}
+
+		if batchHashes != nil {
+			runner, err := newSimRunner(ctx, client)
+			if err != nil {
+				return err
+			}
+			return runBatchDebug(ctx, client, cachedRunner(runner), batchHashes, batchWorkersFlag)
+		}
+
+		// --explorer was validated before the run
+		explorerSite, _ := explorerName()
+
+		statusf("Debugging transaction: %s\n", txHash)
+		statusf("Primary Network: %s\n", networkFlag)
 		if compareNetworkFlag != "" {
-			fmt.Printf("Comparing against Network: %s\n", compareNetworkFlag)
+			statusf("Comparing against Network: %s\n", compareNetworkFlag)
 		}
 
 		// Fetch transaction details
@@ -370,25 +445,97 @@ Local WASM Replay Mode:
 			spinner.StopWithMessage("Transaction found! Starting debug...")
 		}
 
-		fmt.Printf("Fetching transaction: %s\n", txHash)
+		statusf("Fetching transaction: %s\n", txHash)
 		resp, err := client.GetTransaction(ctx, txHash)
 		if err != nil {
 			return errors.WrapRPCConnectionFailed(err)
 		}
 
-		fmt.Printf("Transaction fetched successfully. Envelope size: %d bytes\n", len(resp.EnvelopeXdr))
+		statusf("Transaction fetched successfully. Envelope size: %d bytes\n", len(resp.EnvelopeXdr))
+
+		// Ledger state is gathered for the transaction as it ran, whatever
+		// the edits below do to the envelope
+		fetched := *resp
+		specs := cachedSpecLoader(ctx, client)
+		if invocationEdited() {
+			edited, changes, err := editInvocation(resp.EnvelopeXdr, specs, debugSetFnFlag, debugSetArgFlags)
+			if err != nil {
+				return err
+			}
+			resp.EnvelopeXdr = edited
+			statusf("Simulating modified invocation:\n")
+			for _, c := range changes {
+				statusf("  %s\n", c)
+			}
+		}
+
+		_, decodeSpan := tracer.Start(ctx, "decode_transaction")
+		invocations, err := invocationsFromEnvelope(ctx, resp.EnvelopeXdr, specs)
+		if err != nil {
+			decodeSpan.RecordError(err)
+			logger.Logger.Warn("Failed to decode contract invocations", "error", err)
+		}
+		operations := describeOperations(resp.EnvelopeXdr, resp.ResultXdr)
+		feeBump := decoder.DescribeFeeBump(resp.EnvelopeXdr, client.GetNetworkPassphrase())
+		if textOutput() {
+			printFeeBump(feeBump)
+			printOperations(operations)
+			printInvocations(invocations)
+		}
+
+		if classicOnly(operations) {
+			decodeSpan.End()
+			res, err := classicResult(resp.ResultXdr, operations)
+			if err != nil {
+				return err
+			}
+			links := debugExplorerLinks(explorerSite, networkFlag, txHash, resp.EnvelopeXdr, nil, nil)
+			switch {
+			case jsonOutput():
+				err = printJSON(DebugOutput{
+					TxHash:           txHash,
+					Network:          networkFlag,
+					FeeBump:          feeBump,
+					Operations:       operations,
+					Result:           res,
+					SecurityFindings: []security.Finding{},
+					ExplorerLinks:    links,
+				})
+			case !textOutput():
+				err = printDebugReport(&report.DebugReport{
+					TxHash:            txHash,
+					Network:           networkFlag,
+					EnvelopeXdr:       resp.EnvelopeXdr,
+					Operations:        operations,
+					ResultCode:        res.Code,
+					ResultExplanation: res.Explanation,
+				})
+			default:
+				printClassicResult(res)
+				printExplorerLinks(links)
+			}
+			if err != nil || !debugCheckFlag || res.Succeeded() {
+				return err
+			}
+			return errors.WrapCheckFailed(fmt.Sprintf("transaction failed: %s", res.Code))
+		}
 
 		// Extract ledger keys for replay
 		keys, err := extractLedgerKeys(resp.ResultMetaXdr)
+		decodeSpan.SetAttributes(attribute.Int("transaction.invocations", len(invocations)), attribute.Int("ledger.keys", len(keys)))
+		decodeSpan.End()
 		if err != nil {
 			return errors.WrapUnmarshalFailed(err, "result meta")
 		}
 
 		// Initialize Simulator Runner
-		runner, err := simulator.NewRunnerWithMockTime("", tracingEnabled, mockTimeFlag)
+		runner, err := newSimRunner(ctx, client)
 		if err != nil {
-			return errors.WrapSimulatorNotFound(err.Error())
+			return err
 		}
+		// Step mode needs the plain runner; other runs may be served from the
+		// simulation result cache
+		cached := cachedRunner(runner)
 
 		// Determine timestamps to simulate
 		timestamps := []int64{TimestampFlag}
@@ -400,62 +547,54 @@ Local WASM Replay Mode:
 			}
 		}
 
-		var lastSimResp *simulator.SimulationResponse
+		if protocolVersionFlag > 0 {
+			if err := simulator.Validate(protocolVersionFlag); err != nil {
+				return fmt.Errorf("invalid protocol version %d: %w", protocolVersionFlag, err)
+			}
+			statusf("Using protocol version override: %d\n", protocolVersionFlag)
+		}
+		if len(ledgerOverrides) > 0 {
+			statusf("Applying %d ledger entry overrides\n", len(ledgerOverrides))
+		}
+		if cpuLimitFlag > 0 || memLimitFlag > 0 {
+			statusf("Using budget limits: %s\n", budgetLimitsSummary())
+		}
+
+		var lastSimResp, lastCompareResp *simulator.SimulationResponse
+		var lastSimReq *simulator.SimulationRequest
 
 		for _, ts := range timestamps {
 			if len(timestamps) > 1 {
-				fmt.Printf("\n--- Simulating at Timestamp: %d ---\n", ts)
+				statusf("\n--- Simulating at Timestamp: %d ---\n", ts)
 			}
 
 			var simResp *simulator.SimulationResponse
-			var ledgerEntries map[string]string
 
 			if compareNetworkFlag == "" {
 				// Single Network Run
-				if snapshotFlag != "" {
-					snap, err := snapshot.Load(snapshotFlag)
+				ledgerEntries, err := debugLedgerState(ctx, client, txHash, &fetched, resp.EnvelopeXdr)
+				if err != nil {
+					return err
+				}
+
+				statusf("Running simulation on %s...\n", networkFlag)
+				simReq := debugSimRequest(resp.EnvelopeXdr, resp.ResultMetaXdr, ledgerEntries, resp.Ledger, ts)
+
+				if stepFlag {
+					simResp, err = runStepDebug(runner.(*simulator.Runner), simReq)
 					if err != nil {
-						return errors.WrapValidationError(fmt.Sprintf("failed to load snapshot: %v", err))
+						return err
 					}
-					ledgerEntries = snap.ToMap()
-					fmt.Printf("Loaded %d ledger entries from snapshot\n", len(ledgerEntries))
 				} else {
-					// Try to extract from metadata first, fall back to fetching
-					ledgerEntries, err = rpc.ExtractLedgerEntriesFromMeta(resp.ResultMetaXdr)
+					simResp, err = simulator.RunTraced(ctx, cached, simReq)
 					if err != nil {
-						logger.Logger.Warn("Failed to extract ledger entries from metadata, fetching from network", "error", err)
-						ledgerEntries, err = client.GetLedgerEntries(ctx, keys)
-						if err != nil {
-							return errors.WrapRPCConnectionFailed(err)
-						}
-					} else {
-						logger.Logger.Info("Extracted ledger entries for simulation", "count", len(ledgerEntries))
-					}
-				}
-
-				fmt.Printf("Running simulation on %s...\n", networkFlag)
-				simReq := &simulator.SimulationRequest{
-					EnvelopeXdr:     resp.EnvelopeXdr,
-					ResultMetaXdr:   resp.ResultMetaXdr,
-					LedgerEntries:   ledgerEntries,
-					Timestamp:       ts,
-					ProtocolVersion: nil,
-				}
-
-				// Apply protocol version override if specified
-				if protocolVersionFlag > 0 {
-					if err := simulator.Validate(protocolVersionFlag); err != nil {
-						return fmt.Errorf("invalid protocol version %d: %w", protocolVersionFlag, err)
+						return errors.WrapSimulationFailed(err, "")
 					}
-					simReq.ProtocolVersion = &protocolVersionFlag
-					fmt.Printf("Using protocol version override: %d\n", protocolVersionFlag)
 				}
-
-				simResp, err = runner.Run(simReq)
-				if err != nil {
-					return errors.WrapSimulationFailed(err, "")
-				}
-				printSimulationResult(networkFlag, simResp)
+				analyzeArchival(ctx, client, simReq, simResp)
+				nameContractError(ctx, client, simReq.EnvelopeXdr, simResp)
+				printSimulationResult(ctx, networkFlag, simResp)
+				lastSimReq = simReq
 				// Fetch contract bytecode on demand for any contract calls in the trace; cache via RPC client
 				if client != nil && simResp != nil && len(simResp.DiagnosticEvents) > 0 {
 					contractIDs := collectContractIDsFromDiagnosticEvents(simResp.DiagnosticEvents)
@@ -467,36 +606,20 @@ Local WASM Replay Mode:
 				// Comparison Run
 				var wg sync.WaitGroup
 				var primaryResult, compareResult *simulator.SimulationResponse
+				var primaryReq *simulator.SimulationRequest
 				var primaryErr, compareErr error
 
 				wg.Add(2)
 				go func() {
 					defer wg.Done()
-					var entries map[string]string
-					var extractErr error
-					entries, extractErr = rpc.ExtractLedgerEntriesFromMeta(resp.ResultMetaXdr)
-					if extractErr != nil {
-						entries, extractErr = client.GetLedgerEntries(ctx, keys)
-						if extractErr != nil {
-							primaryErr = extractErr
-							return
-						}
-					}
-					simReq := &simulator.SimulationRequest{
-						EnvelopeXdr:     resp.EnvelopeXdr,
-						ResultMetaXdr:   resp.ResultMetaXdr,
-						LedgerEntries:   entries,
-						Timestamp:       ts,
-						ProtocolVersion: nil,
-					}
-					if protocolVersionFlag > 0 {
-						if err := simulator.Validate(protocolVersionFlag); err != nil {
-							primaryErr = fmt.Errorf("invalid protocol version %d: %w", protocolVersionFlag, err)
-							return
-						}
-						simReq.ProtocolVersion = &protocolVersionFlag
+					entries, stateErr := debugLedgerState(ctx, client, txHash, &fetched, resp.EnvelopeXdr)
+					if stateErr != nil {
+						primaryErr = stateErr
+						return
 					}
-					primaryResult, primaryErr = runner.Run(simReq)
+					simReq := debugSimRequest(resp.EnvelopeXdr, resp.ResultMetaXdr, entries, resp.Ledger, ts)
+					primaryReq = simReq
+					primaryResult, primaryErr = simulator.RunTraced(ctx, cached, simReq)
 				}()
 
 				go func() {
@@ -505,6 +628,11 @@ Local WASM Replay Mode:
 						rpc.WithNetwork(rpc.Network(compareNetworkFlag)),
 						rpc.WithToken(rpcTokenFlag),
 					}
+					compareOpts = append(compareOpts, rpcEndpointOptions("", compareNetworkFlag)...)
+					// --network-passphrase is the primary network's
+					if networkPassphraseFlag != "" {
+						compareOpts = append(compareOpts, rpc.WithNetworkPassphrase(namedNetworkPassphrase(compareNetworkFlag)))
+					}
 					compareClient, clientErr := rpc.NewClient(compareOpts...)
 					if clientErr != nil {
 						compareErr = errors.WrapValidationError(fmt.Sprintf("failed to create compare client: %v", clientErr))
@@ -514,36 +642,20 @@ Local WASM Replay Mode:
 						compareClient.CacheEnabled = false
 					}
 
-					compareResp, txErr := compareClient.GetTransaction(ctx, txHash)
-					if txErr != nil {
-						compareErr = errors.WrapRPCConnectionFailed(txErr)
+					compareResp, entries, stateErr := compareNetworkState(ctx, compareClient, resp.EnvelopeXdr, keys)
+					if stateErr != nil {
+						compareErr = stateErr
 						return
 					}
 
-					entries, extractErr := rpc.ExtractLedgerEntriesFromMeta(compareResp.ResultMetaXdr)
-					if extractErr != nil {
-						entries, extractErr = compareClient.GetLedgerEntries(ctx, keys)
-						if extractErr != nil {
-							compareErr = extractErr
-							return
-						}
+					simReq := debugSimRequest(resp.EnvelopeXdr, compareResp.ResultMetaXdr, entries, compareResp.Ledger, ts)
+					// The RPC backend simulates on the node of the client it
+					// was made with, which is the primary network's
+					compareRunner := cached
+					if backendFlag == backendRPC {
+						compareRunner = simulator.NewRPCRunner(compareClient)
 					}
-
-					simReq := &simulator.SimulationRequest{
-						EnvelopeXdr:     resp.EnvelopeXdr,
-						ResultMetaXdr:   compareResp.ResultMetaXdr,
-						LedgerEntries:   entries,
-						Timestamp:       ts,
-						ProtocolVersion: nil,
-					}
-					if protocolVersionFlag > 0 {
-						if err := simulator.Validate(protocolVersionFlag); err != nil {
-							compareErr = fmt.Errorf("invalid protocol version %d: %w", protocolVersionFlag, err)
-							return
-						}
-						simReq.ProtocolVersion = &protocolVersionFlag
-					}
-					compareResult, compareErr = runner.Run(simReq)
+					compareResult, compareErr = simulator.RunTraced(ctx, compareRunner, simReq)
 				}()
 
 				wg.Wait()
@@ -562,8 +674,10 @@ Local WASM Replay Mode:
 				}
 
 				simResp = primaryResult // Use primary for further analysis
-				printSimulationResult(networkFlag, primaryResult)
-				printSimulationResult(compareNetworkFlag, compareResult)
+				lastSimReq = primaryReq
+				lastCompareResp = compareResult
+				printSimulationResult(ctx, networkFlag, primaryResult)
+				printSimulationResult(ctx, compareNetworkFlag, compareResult)
 				diffResults(primaryResult, compareResult, networkFlag, compareNetworkFlag)
 			}
 			lastSimResp = simResp
@@ -573,68 +687,70 @@ Local WASM Replay Mode:
 			return errors.WrapSimulationLogicError("no simulation results generated")
 		}
 
-		// Analysis: Error Suggestions (Heuristic-based)
-		if len(lastSimResp.Events) > 0 {
-			suggestionEngine := decoder.NewSuggestionEngine()
-			
-			// Decode events for analysis
-			callTree, err := decoder.DecodeEvents(lastSimResp.Events)
-			if err == nil && callTree != nil {
-				suggestions := suggestionEngine.AnalyzeCallTree(callTree)
-				if len(suggestions) > 0 {
-					fmt.Print(decoder.FormatSuggestions(suggestions))
-				}
+		var remoteDiff *compare.RemoteDiff
+		if compareRemoteFlag {
+			remoteDiff = remoteComparison(ctx, client, resp.EnvelopeXdr, lastSimResp)
+		}
+
+		if ProfileFlag {
+			outPath := profileOutFlag
+			if outPath == "" {
+				outPath = defaultProfilePath(txHash, profileFormatFlag)
+			}
+			if err := writeProfile(lastSimResp, profileFormatFlag, outPath); err != nil {
+				statusf("Warning: failed to export profile: %v\n", err)
+			} else {
+				statusf("Profile (%s) written to %s\n", profileFormatFlag, outPath)
 			}
+			if textOutput() {
+				printFunctionCosts(lastSimResp.FunctionCosts)
+			}
+		}
+		if profileMemoryFlag && textOutput() {
+			printMemoryProfile(lastSimResp)
 		}
 
+		// Analysis: Error Suggestions (Heuristic-based)
+		_, analyzeSpan := tracer.Start(ctx, "analyze_results")
+		analyzeSpan.SetAttributes(attribute.Int("simulation.events", len(lastSimResp.Events)))
+		suggestions := heuristic.Suggestions(heuristicInput(txHash, networkFlag, lastSimResp), decodedCallTree(lastSimResp))
+
 		// Analysis: Security
-		fmt.Printf("\n=== Security Analysis ===\n")
 		secDetector := security.NewDetector()
 		findings := secDetector.Analyze(resp.EnvelopeXdr, resp.ResultMetaXdr, lastSimResp.Events, lastSimResp.Logs)
-		if len(findings) == 0 {
-			fmt.Printf("%s No security issues detected\n", visualizer.Success())
-		} else {
-			verifiedCount := 0
-			heuristicCount := 0
 
-			for _, finding := range findings {
-				if finding.Type == security.FindingVerifiedRisk {
-					verifiedCount++
-				} else {
-					heuristicCount++
-				}
-			}
+		// Analysis: Token Flows
+		flowReport, flowErr := tokenflow.BuildReport(resp.EnvelopeXdr, resp.ResultMetaXdr)
+		hasFlows := flowErr == nil && len(flowReport.Agg) > 0
+		analyzeSpan.End()
+
+		hookResult, err := runPostSimulationHooks(ctx, hooks.Input{
+			Command:     "debug",
+			Network:     networkFlag,
+			TxHash:      txHash,
+			EnvelopeXdr: resp.EnvelopeXdr,
+			Invocations: invocations,
+			Simulation:  lastSimResp,
+		})
+		if err != nil {
+			return err
+		}
 
-			if verifiedCount > 0 {
-				fmt.Printf("\n[!]  VERIFIED SECURITY RISKS: %d\n", verifiedCount)
+		if textOutput() {
+			if len(suggestions) > 0 {
+				fmt.Print(decoder.FormatSuggestions(suggestions))
 			}
-			if heuristicCount > 0 {
-				fmt.Printf("* HEURISTIC WARNINGS: %d\n", heuristicCount)
-			}
-
-			fmt.Printf("\nFindings:\n")
-			for i, finding := range findings {
-				icon := "*"
-				if finding.Type == security.FindingVerifiedRisk {
-					icon = "[!]"
-				}
-				fmt.Printf("%d. %s [%s] %s - %s\n", i+1, icon, finding.Type, finding.Severity, finding.Title)
-				fmt.Printf("   %s\n", finding.Description)
-				if finding.Evidence != "" {
-					fmt.Printf("   Evidence: %s\n", finding.Evidence)
+			printSecurityFindings(findings)
+			if hasFlows {
+				fmt.Printf("\nToken Flow Summary:\n")
+				for _, line := range flowReport.SummaryLines() {
+					fmt.Printf("  %s\n", line)
 				}
+				fmt.Printf("\nToken Flow Chart (Mermaid):\n")
+				fmt.Println(flowReport.MermaidFlowchart())
 			}
 		}
-
-		// Analysis: Token Flows
-		if report, err := tokenflow.BuildReport(resp.EnvelopeXdr, resp.ResultMetaXdr); err == nil && len(report.Agg) > 0 {
-			fmt.Printf("\nToken Flow Summary:\n")
-			for _, line := range report.SummaryLines() {
-				fmt.Printf("  %s\n", line)
-			}
-			fmt.Printf("\nToken Flow Chart (Mermaid):\n")
-			fmt.Println(report.MermaidFlowchart())
-		}
+		printHookResult(hookResult)
 
 		// Session Management
 		simReq := &simulator.SimulationRequest{
@@ -643,12 +759,13 @@ Local WASM Replay Mode:
 		}
 		simReqJSON, err := json.Marshal(simReq)
 		if err != nil {
-			fmt.Printf("Warning: failed to serialize simulation data: %v\n", err)
+			statusf("Warning: failed to serialize simulation data: %v\n", err)
 		}
 		simRespJSON, err := json.Marshal(lastSimResp)
 		if err != nil {
-			fmt.Printf("Warning: failed to serialize simulation results: %v\n", err)
+			statusf("Warning: failed to serialize simulation results: %v\n", err)
 		}
+		pinSnapshot(lastSimResp, lastSimReq)
 
 		sessionData := &session.SessionData{
 			ID:              session.GenerateID(txHash),
@@ -667,12 +784,180 @@ Local WASM Replay Mode:
 			SchemaVersion:   session.SchemaVersion,
 		}
 		SetCurrentSession(sessionData)
-		fmt.Printf("\nSession created: %s\n", sessionData.ID)
-		fmt.Printf("Run 'erst session save' to persist this session.\n")
-		return nil
+		links := debugExplorerLinks(explorerSite, networkFlag, txHash, resp.EnvelopeXdr, invocations, lastSimResp)
+
+		switch {
+		case jsonOutput():
+			result := DebugOutput{
+				TxHash:           txHash,
+				Network:          networkFlag,
+				Simulation:       lastSimResp,
+				Suggestions:      suggestions,
+				SecurityFindings: findings,
+				SessionID:        sessionData.ID,
+				Invocations:      invocations,
+				FeeBump:          feeBump,
+				Operations:       operations,
+				Annotations:      hookAnnotations(hookResult),
+				RemoteComparison: remoteDiff,
+				ExplorerLinks:    links,
+			}
+			if lastCompareResp != nil {
+				result.CompareNetwork = compareNetworkFlag
+				result.CompareSimulation = lastCompareResp
+			}
+			if hasFlows {
+				result.TokenFlows = flowReport.SummaryLines()
+			}
+			err = printJSON(result)
+		case !textOutput():
+			debugReport := &report.DebugReport{
+				TxHash:      txHash,
+				Network:     networkFlag,
+				SessionID:   sessionData.ID,
+				EnvelopeXdr: resp.EnvelopeXdr,
+				Invocations: invocations,
+				Simulation:  lastSimResp,
+				Operations:  operations,
+				Suggestions: suggestions,
+			}
+			if hasFlows {
+				debugReport.TokenFlows = flowReport.SummaryLines()
+			}
+			err = printDebugReport(debugReport)
+		default:
+			printExplorerLinks(links)
+			fmt.Printf("\nSession created: %s\n", sessionData.ID)
+			fmt.Printf("Run 'erst session save' to persist this session.\n")
+		}
+		if err != nil {
+			return err
+		}
+		if err := hookCheck(hookResult); err != nil || !debugCheckFlag {
+			return err
+		}
+		return checkSimulations(lastSimResp, lastCompareResp)
 	},
 }
 
+// printDebugReport writes the 'erst debug --output markdown' summary or the
+// --output sarif log
+// debugLedgerState returns the ledger entries to replay the fetched
+// transaction with: those in --snapshot, or else the transaction's state from
+// resolveLedgerState, with the footprint of an edited invocation added
+func debugLedgerState(ctx context.Context, client *rpc.Client, txHash string, fetched *rpc.TransactionResponse, envelopeXdr string) (map[string]string, error) {
+	if snapshotFlag != "" {
+		snap, err := snapshot.Load(snapshotFlag)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("failed to load snapshot: %v", err))
+		}
+		entries := snap.ToMap()
+		statusf("Loaded %d ledger entries from snapshot\n", len(entries))
+		return entries, nil
+	}
+	entries, err := resolveLedgerState(ctx, client, txHash, fetched)
+	if err != nil {
+		return nil, err
+	}
+	if invocationEdited() {
+		supplementEditedFootprint(ctx, client, envelopeXdr, entries)
+	}
+	return entries, nil
+}
+
+// debugSimRequest builds the request replaying envelopeXdr against entries
+// at ledger and ts, with the overrides, budget limits, protocol version and
+// profiling given on the command line. The protocol version is validated
+// beforehand.
+func debugSimRequest(envelopeXdr, resultMetaXdr string, entries map[string]string, ledger uint32, ts int64) *simulator.SimulationRequest {
+	simReq := &simulator.SimulationRequest{
+		EnvelopeXdr:    envelopeXdr,
+		ResultMetaXdr:  resultMetaXdr,
+		LedgerEntries:  entries,
+		Timestamp:      ts,
+		LedgerSequence: ledger,
+		Profile:        ProfileFlag,
+		ProfileMemory:  profileMemoryFlag,
+	}
+	if len(ledgerOverrides) > 0 {
+		simReq.LedgerEntryOverrides = ledgerOverrides
+	}
+	if protocolVersionFlag > 0 {
+		simReq.ProtocolVersion = &protocolVersionFlag
+	}
+	applyBudgetLimits(simReq)
+	return simReq
+}
+
+func printDebugReport(r *report.DebugReport) error {
+	if OutputFlag == OutputSARIF {
+		return printSARIF(r)
+	}
+	r.ErstVersion = Version
+	r.GeneratedAt = time.Now()
+	_, err := os.Stdout.Write(report.RenderDebugMarkdown(r))
+	return err
+}
+
+// DebugOutput is the document emitted by 'erst debug --output json'
+type DebugOutput struct {
+	TxHash            string                        `json:"tx_hash"`
+	Network           string                        `json:"network"`
+	Invocations       []contractspec.Invocation     `json:"invocations,omitempty"`
+	Annotations       []hooks.Annotation            `json:"annotations,omitempty"`
+	RemoteComparison  *compare.RemoteDiff           `json:"remote_comparison,omitempty"`
+	FeeBump           *decoder.FeeBumpSummary       `json:"fee_bump,omitempty"`
+	Operations        []decoder.OperationSummary    `json:"operations,omitempty"`
+	Simulation        *simulator.SimulationResponse `json:"simulation"`
+	Result            *ClassicResult                `json:"result,omitempty"` // transactions without Soroban operations are decoded, not simulated
+	CompareNetwork    string                        `json:"compare_network,omitempty"`
+	CompareSimulation *simulator.SimulationResponse `json:"compare_simulation,omitempty"`
+	Suggestions       []decoder.Suggestion          `json:"suggestions,omitempty"`
+	SecurityFindings  []security.Finding            `json:"security_findings"`
+	TokenFlows        []string                      `json:"token_flows,omitempty"`
+	ExplorerLinks     *explorer.Links               `json:"explorer_links,omitempty"`
+	SessionID         string                        `json:"session_id"`
+}
+
+func printSecurityFindings(findings []security.Finding) {
+	fmt.Printf("\n=== Security Analysis ===\n")
+	if len(findings) == 0 {
+		fmt.Printf("%s No security issues detected\n", visualizer.Success())
+		return
+	}
+
+	verifiedCount := 0
+	heuristicCount := 0
+
+	for _, finding := range findings {
+		if finding.Type == security.FindingVerifiedRisk {
+			verifiedCount++
+		} else {
+			heuristicCount++
+		}
+	}
+
+	if verifiedCount > 0 {
+		fmt.Printf("\n[!]  VERIFIED SECURITY RISKS: %d\n", verifiedCount)
+	}
+	if heuristicCount > 0 {
+		fmt.Printf("* HEURISTIC WARNINGS: %d\n", heuristicCount)
+	}
+
+	fmt.Printf("\nFindings:\n")
+	for i, finding := range findings {
+		icon := "*"
+		if finding.Type == security.FindingVerifiedRisk {
+			icon = "[!]"
+		}
+		fmt.Printf("%d. %s [%s] %s - %s\n", i+1, icon, finding.Type, finding.Severity, finding.Title)
+		fmt.Printf("   %s\n", finding.Description)
+		if finding.Evidence != "" {
+			fmt.Printf("   Evidence: %s\n", finding.Evidence)
+		}
+	}
+}
+
 // runDemoMode prints sample output without network/WASM - for testing color detection.
 func runDemoMode(cmdArgs []string) error {
 	txHash := "5c0a1234567890abcdef1234567890abcdef1234567890abcdef1234567890ab"
@@ -712,7 +997,11 @@ func runLocalWasmReplay() error {
 	fmt.Println()
 
 	// Create simulator runner
-	runner, err := simulator.NewRunner("", tracingEnabled)
+	simPath, err := hostSimPath(context.Background())
+	if err != nil {
+		return err
+	}
+	runner, err := simulator.NewRunner(simPath, tracingEnabled)
 	if err != nil {
 		return errors.WrapSimulatorNotFound(err.Error())
 	}
@@ -882,12 +1171,16 @@ func collectContractIDsFromDiagnosticEvents(events []simulator.DiagnosticEvent)
 	return ids
 }
 
-func printSimulationResult(network string, res *simulator.SimulationResponse) {
+func printSimulationResult(ctx context.Context, network string, res *simulator.SimulationResponse) {
+	if !textOutput() {
+		return
+	}
 	fmt.Printf("\n--- Result for %s ---\n", network)
 	fmt.Printf("Status: %s\n", res.Status)
 	if res.Error != "" {
 		fmt.Printf("Error: %s\n", res.Error)
 	}
+	printErrorExplanation(res.ErrorExplanation)
 
 	// Display budget usage if available
 	if res.BudgetUsage != nil {
@@ -929,14 +1222,19 @@ func printSimulationResult(network string, res *simulator.SimulationResponse) {
 			if i < 10 { // Show first 10 events
 				fmt.Printf("  [%d] Type: %s", i+1, event.EventType)
 				if event.ContractID != nil {
-					fmt.Printf(", Contract: %s", *event.ContractID)
+					fmt.Printf(", Contract: %s", labelAddress(*event.ContractID))
 				}
 				fmt.Printf("\n")
-				if len(event.Topics) > 0 {
-					fmt.Printf("      Topics: %v\n", event.Topics)
+				if event.ContractID != nil {
+					if summary := eventSummary(ctx, network, *event.ContractID, event.TopicsXdr, event.DataXdr); summary != "" {
+						fmt.Printf("      %s\n", summary)
+					}
 				}
-				if event.Data != "" && len(event.Data) < 100 {
-					fmt.Printf("      Data: %s\n", event.Data)
+				if topics := eventTopics(event); len(topics) > 0 {
+					fmt.Printf("      Topics: [%s]\n", strings.Join(topics, ", "))
+				}
+				if data := eventData(event); data != "" && len(data) < 100 {
+					fmt.Printf("      Data: %s\n", data)
 				}
 			}
 		}
@@ -960,9 +1258,208 @@ func printSimulationResult(network string, res *simulator.SimulationResponse) {
 		}
 	}
 	fmt.Printf("Events: %d, Logs: %d\n", len(res.Events), len(res.Logs))
+
+	printCallTree(res.CallTree)
+	printLedgerTrace(res.LedgerTrace)
+	printArchival(res.Archival)
+}
+
+// printErrorExplanation describes a failure from the error catalog, naming
+// contract errors by their enum case when the spec was found
+func printErrorExplanation(exp *decoder.ErrorExplanation) {
+	if exp == nil {
+		return
+	}
+	switch {
+	case exp.ContractError != "":
+		fmt.Printf("  %s %s %s\n", exp.Error, visualizer.Symbol("arrow_r"), exp.ContractError)
+		if exp.ContractErrorDoc != "" {
+			fmt.Printf("  %s\n", exp.ContractErrorDoc)
+		}
+	case exp.Error != "":
+		fmt.Printf("  %s: %s\n", exp.Error, exp.Summary)
+	default:
+		fmt.Printf("  %s\n", exp.Summary)
+	}
+	fmt.Printf("  %s\n", exp.Explanation)
+}
+
+// printCallTree shows the contract calls of a simulation and, on failure, the
+// call the failure originated in
+func printCallTree(roots []*simulator.CallNode) {
+	if len(roots) == 0 {
+		return
+	}
+	fmt.Printf("\nCall Tree:\n")
+	for _, line := range strings.Split(strings.TrimRight(simulator.RenderCallTree(roots), "\n"), "\n") {
+		fmt.Printf("  %s\n", line)
+	}
+	if path := simulator.FailurePath(roots); len(path) > 0 {
+		origin := path[len(path)-1]
+		fmt.Printf("  %s Failed in %s.%s at depth %d", visualizer.Error(), origin.Contract, origin.Function, len(path)-1)
+		if origin.Error != "" {
+			fmt.Printf(": %s", origin.Error)
+		}
+		fmt.Println()
+	}
+}
+
+// printFunctionCosts ranks the contract functions by the CPU they used
+// themselves
+func printFunctionCosts(costs []simulator.FunctionCost) {
+	if len(costs) == 0 {
+		return
+	}
+	const top = 10
+	fmt.Printf("\nMost Expensive Functions:\n")
+	fmt.Printf("  %-32s %6s %14s %14s %12s %9s\n", "FUNCTION", "CALLS", "SELF CPU", "TOTAL CPU", "SELF MEM", "HOST FNS")
+	for i, c := range costs {
+		if i == top {
+			fmt.Printf("  ... and %d more\n", len(costs)-top)
+			break
+		}
+		name := simulator.ShortID(c.Contract) + "." + c.Function
+		fmt.Printf("  %-32s %6d %14d %14d %12d %9d\n", name, c.Calls, c.SelfCPUInsns, c.CPUInsns, c.SelfMemBytes, c.HostFnCalls)
+	}
+}
+
+// printMemoryProfile ranks the contract functions by the memory they used
+// themselves, split into host allocations and linear memory
+func printMemoryProfile(resp *simulator.SimulationResponse) {
+	costs := simulator.RankByMemory(resp.FunctionCosts)
+	if len(costs) == 0 || !costs[0].MemoryProfiled {
+		fmt.Printf("\n%s The simulator did not report per-call memory; it may predate --profile-memory or be running in step mode.\n", visualizer.Warning())
+		return
+	}
+
+	const top, topAllocs = 10, 3
+	fmt.Printf("\nMemory Profile:\n")
+	fmt.Printf("  %-32s %12s %12s %12s\n", "FUNCTION", "SELF MEM", "LINEAR MEM", "HOST ALLOCS")
+	for i, c := range costs {
+		if i == top {
+			fmt.Printf("  ... and %d more\n", len(costs)-top)
+			break
+		}
+		var hostBytes uint64
+		for _, a := range c.HostAllocs {
+			hostBytes += a.MemBytes
+		}
+		name := simulator.ShortID(c.Contract) + "." + c.Function
+		fmt.Printf("  %-32s %12d %12d %12d\n", name, c.SelfMemBytes, c.LinearMemBytes, hostBytes)
+		for j, a := range c.HostAllocs {
+			if j == topAllocs || a.MemBytes == 0 {
+				break
+			}
+			fmt.Printf("      %-28s %12d bytes in %d calls\n", a.Name, a.MemBytes, a.Calls)
+		}
+	}
+
+	// Simulators that do not report total memory leave no share to compute
+	if simulator.MemoryExhausted(resp) && costs[0].SelfMemBytes > 0 && resp.BudgetUsage.MemoryBytes > 0 {
+		driver := costs[0]
+		share := 100 * float64(driver.SelfMemBytes) / float64(resp.BudgetUsage.MemoryBytes)
+		fmt.Printf("  %s Memory budget exhausted; %s.%s used %.0f%% of it\n", visualizer.Error(), driver.Contract, driver.Function, share)
+	}
+}
+
+// printArchival lists footprint entries that are archived, expired, missing or
+// close to archival, and the operations that would fix them
+func printArchival(report *simulator.ArchivalReport) {
+	if report == nil || !report.NeedsAttention() {
+		return
+	}
+
+	fmt.Printf("\nState Archival (ledger %d):\n", report.LedgerSequence)
+	if report.CausedFailure {
+		fmt.Printf("  %s The failure is likely caused by archived or missing ledger entries\n", visualizer.Warning())
+	}
+	for _, e := range report.Entries {
+		if e.Status == simulator.TTLLive {
+			continue
+		}
+		kind := e.Type
+		if e.Durability != "" {
+			kind += " (" + e.Durability + ")"
+		}
+		switch e.Status {
+		case simulator.TTLMissing:
+			fmt.Printf("  - %s %s: not found\n", kind, e.Key)
+		case simulator.TTLExpiring:
+			fmt.Printf("  - %s %s: %s, %d ledgers left (live until %d)\n", kind, e.Key, e.Status, e.LedgersLeft, e.LiveUntilLedger)
+		default:
+			fmt.Printf("  - %s %s: %s %d ledgers ago (live until %d)\n", kind, e.Key, e.Status, -e.LedgersLeft, e.LiveUntilLedger)
+		}
+	}
+	if report.Restore != nil {
+		fmt.Printf("  Restore with a %s operation covering %d entries in its read-write footprint\n", report.Restore.Operation, len(report.Restore.Keys))
+	}
+	if report.Extend != nil {
+		fmt.Printf("  Extend with an %s operation to %d ledgers for %d entries\n", report.Extend.Operation, report.Extend.ExtendTo, len(report.Extend.Keys))
+	}
+}
+
+// eventTopics renders an event's topics as readable SCVals when the simulator
+// supplied their XDR
+func eventTopics(event simulator.DiagnosticEvent) []string {
+	return decoder.RenderScVals(event.TopicsXdr, event.Topics)
+}
+
+// eventData renders an event's data the same way as eventTopics
+func eventData(event simulator.DiagnosticEvent) string {
+	return decoder.RenderScVal(event.DataXdr, event.Data)
+}
+
+// sacEventSummary describes a Stellar Asset Contract event in token terms,
+// or returns "" for other events. Events are only attributed to the SAC of
+// their asset on known networks.
+func sacEventSummary(network, contractID string, topicsXdr []string, dataXdr string) string {
+	if contractID == "" || len(topicsXdr) == 0 {
+		return ""
+	}
+	topics := make([]xdr.ScVal, len(topicsXdr))
+	for i, t := range topicsXdr {
+		if err := xdr.SafeUnmarshalBase64(t, &topics[i]); err != nil {
+			return ""
+		}
+	}
+	var data xdr.ScVal
+	if err := xdr.SafeUnmarshalBase64(dataXdr, &data); err != nil {
+		return ""
+	}
+	action, ok := decoder.DecodeSACEvent(contractID, topics, data, networkPassphrase(network))
+	if !ok {
+		return ""
+	}
+	return action.String()
+}
+
+// networkPassphrase returns --network-passphrase when given, else the
+// passphrase of a built-in network, or "" for anything else
+func networkPassphrase(network string) string {
+	if networkPassphraseFlag != "" {
+		return networkPassphraseFlag
+	}
+	return namedNetworkPassphrase(network)
+}
+
+// namedNetworkPassphrase returns the passphrase of a built-in network,
+// ignoring --network-passphrase
+func namedNetworkPassphrase(network string) string {
+	if network == string(rpc.Local) {
+		return localPassphrase()
+	}
+	for _, cfg := range []rpc.NetworkConfig{rpc.TestnetConfig, rpc.MainnetConfig, rpc.FuturenetConfig} {
+		if cfg.Name == network {
+			return cfg.NetworkPassphrase
+		}
+	}
+	return ""
 }
 
 func diffResults(res1, res2 *simulator.SimulationResponse, net1, net2 string) {
+	if !textOutput() {
+		return
+	}
 	fmt.Printf("\n=== Comparison: %s vs %s ===\n", net1, net2)
 
 	if res1.Status != res2.Status {
@@ -1023,25 +1520,50 @@ func diffResults(res1, res2 *simulator.SimulationResponse, net1, net2 string) {
 }
 
 func init() {
-	debugCmd.Flags().StringVarP(&networkFlag, "network", "n", "mainnet", "Stellar network (auto-detected when omitted; testnet, mainnet, futurenet)")
-	debugCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom RPC URL")
+	debugCmd.ValidArgsFunction = completeTxHashes
+	debugCmd.Flags().StringVarP(&networkFlag, "network", "n", "mainnet", "Stellar network (auto-detected when omitted; testnet, mainnet, futurenet, local)")
+	debugCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(debugCmd, "rpc-url")
 	debugCmd.Flags().StringVar(&rpcTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
-	debugCmd.Flags().BoolVar(&tracingEnabled, "tracing", false, "Enable tracing")
-	debugCmd.Flags().StringVar(&otlpExporterURL, "otlp-url", "http://localhost:4318", "OTLP URL")
+	addTracingFlags(debugCmd)
 	debugCmd.Flags().BoolVar(&generateTrace, "generate-trace", false, "Generate trace file")
 	debugCmd.Flags().StringVar(&traceOutputFile, "trace-output", "", "Trace output file")
 	debugCmd.Flags().StringVar(&snapshotFlag, "snapshot", "", "Load state from JSON snapshot file")
-	debugCmd.Flags().StringVar(&compareNetworkFlag, "compare-network", "", "Network to compare against (testnet, mainnet, futurenet)")
+	debugCmd.Flags().StringVar(&compareNetworkFlag, "compare-network", "", "Network to compare against (testnet, mainnet, futurenet, local)")
 	debugCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
 	debugCmd.Flags().StringVar(&wasmPath, "wasm", "", "Path to local WASM file for local replay (no network required)")
 	debugCmd.Flags().StringSliceVar(&args, "args", []string{}, "Mock arguments for local replay (JSON array of strings)")
-	debugCmd.Flags().BoolVar(&noCacheFlag, "no-cache", false, "Disable local ledger state caching")
+	debugCmd.Flags().BoolVar(&noCacheFlag, "no-cache", false, "Disable local ledger state and simulation result caching")
 	debugCmd.Flags().BoolVar(&demoMode, "demo", false, "Print sample output (no network) - for testing color detection")
 	debugCmd.Flags().BoolVar(&watchFlag, "watch", false, "Poll for transaction on-chain before debugging")
 	debugCmd.Flags().IntVar(&watchTimeoutFlag, "watch-timeout", 30, "Timeout in seconds for watch mode")
 	debugCmd.Flags().Uint32Var(&protocolVersionFlag, "protocol-version", 0, "Override protocol version for simulation (20, 21, 22, etc)")
+	debugCmd.Flags().Uint64Var(&cpuLimitFlag, "cpu-limit", 0, "CPU instruction budget for the simulation (default: the host's)")
+	debugCmd.Flags().Uint64Var(&memLimitFlag, "mem-limit", 0, "Memory budget for the simulation, in bytes (default: the host's)")
+	debugCmd.Flags().StringVar(&backendFlag, "backend", backendLocal, "Simulation backend: local (erst-sim) or rpc (the RPC node's simulateTransaction)")
+	debugCmd.Flags().Uint32Var(&hostVersionFlag, "host-version", 0, "Simulate with the erst-sim build for this soroban-env-host version ("+simulator.HostVersionList()+"), installing it if needed")
 	debugCmd.Flags().StringVar(&themeFlag, "theme", "", "Color theme (default, deuteranopia, protanopia, tritanopia, high-contrast)")
+	debugCmd.Flags().StringVar(&batchFileFlag, "file", "", "File with one transaction hash per line to debug as a batch")
+	debugCmd.Flags().IntVar(&batchWorkersFlag, "concurrency", 4, "Number of transactions to simulate concurrently in batch mode")
+	debugCmd.Flags().IntVar(&batchWorkersFlag, "workers", 4, "Number of transactions to simulate concurrently in batch mode")
+	_ = debugCmd.Flags().MarkDeprecated("workers", "use --concurrency instead")
+	debugCmd.Flags().DurationVar(&batchDrainFlag, "drain-timeout", 30*time.Second, "How long transactions in flight may finish after Ctrl+C in batch mode")
+	debugCmd.Flags().StringArrayVar(&overrideEntryFlags, "override-entry", nil, "Override a ledger entry before simulation as <ledger-key-xdr>=<entry-file>, or <entry-file> to derive the key (repeatable)")
+	debugCmd.Flags().StringVar(&overrideStateFlag, "override-state", "", "JSON file of ledger entries to override ({\"ledger_entries\": {key: entry}})")
+	debugCmd.Flags().StringVar(&profileFormatFlag, "profile-format", ProfileFormatSVG, "Profile export format: svg, pprof (go tool pprof), folded (inferno) or speedscope")
+	debugCmd.Flags().StringVar(&profileOutFlag, "profile-out", "", "Write the profile to this file (implies --profile)")
+	debugCmd.Flags().BoolVar(&profileMemoryFlag, "profile-memory", false, "Attribute memory to each contract function's host allocations and linear memory growth")
+	debugCmd.Flags().BoolVar(&stepFlag, "step", false, "Interactively step through contract calls and host function calls")
+	debugCmd.Flags().StringArrayVar(&breakpointFlags, "break", nil, "Pause when a host function, contract function or contract ID matches (repeatable, implies --step)")
+	debugCmd.Flags().StringArrayVar(&debugSetArgFlags, "set-arg", nil, "Replace an argument of the contract call before simulation as <index|name>=<value> (repeatable)")
+	debugCmd.Flags().StringVar(&debugSetFnFlag, "set-fn", "", "Call this contract function instead of the one in the transaction")
 	debugCmd.Flags().Int64Var(&mockTimeFlag, "mock-time", 0, "Fix the ledger timestamp for deterministic local simulation (Unix epoch seconds); 0 = disabled")
+	debugCmd.Flags().BoolVar(&debugCheckFlag, "check", false, "Exit with code 2 when the simulation fails, 3 on RPC errors and 4 on simulator errors")
+	addHookFlags(debugCmd)
+	debugCmd.Flags().StringVar(&explorerFlag, "explorer", "", "Block explorer to link to: stellarexpert, stellarchain or none (default: explorer from config, else stellarexpert)")
+	debugCmd.Flags().BoolVar(&compareRemoteFlag, "compare-remote", false, "Also simulate with the RPC node's simulateTransaction and report where it diverges from the local result")
+	debugCmd.Flags().DurationVar(&debugTimeoutFlag, "timeout", 0, "Abort the fetch and simulation after this long (e.g. 2m; 0 for no limit)")
+	debugCmd.RunE = withTimeout(&debugTimeoutFlag, debugCmd.RunE)
 
 	rootCmd.AddCommand(debugCmd)
 }
@@ -0,0 +1,410 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/hooks"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+var (
+	batchFileFlag    string
+	batchWorkersFlag int
+	// batchDrainFlag is how long transactions in flight may finish after
+	// Ctrl-C; transactions not yet started are skipped
+	batchDrainFlag time.Duration
+
+	// batchHashes is populated by PreRunE when debug runs in batch mode
+	batchHashes []string
+)
+
+// BatchResult is the outcome of debugging a single transaction in batch mode
+type BatchResult struct {
+	TxHash          string `json:"tx_hash"`
+	Status          string `json:"status"` // simulation status, or "failed" if the pipeline errored
+	Error           string `json:"error,omitempty"`
+	CPUInstructions uint64 `json:"cpu_instructions,omitempty"`
+	MemoryBytes     uint64 `json:"memory_bytes,omitempty"`
+	// Annotations are added by the post-simulation hooks, and HookFailure
+	// is the reason when a hook failed the transaction
+	Annotations []hooks.Annotation `json:"annotations,omitempty"`
+	HookFailure string             `json:"hook_failure,omitempty"`
+
+	// err is why the pipeline failed, kept for the --check exit code
+	err error
+	// hookOutput is the hooks' extra output, printed with the progress
+	hookOutput string
+}
+
+// BatchSummary aggregates the results of a batch debug run
+type BatchSummary struct {
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+	Results  []BatchResult  `json:"results"`
+}
+
+// isBatchMode reports whether debug was asked to process more than one transaction
+func isBatchMode(cmdArgs []string) bool {
+	return batchFileFlag != "" || len(cmdArgs) > 1
+}
+
+// collectTxHashes merges hashes given as arguments with those listed in
+// --file. Blank lines and lines starting with '#' are ignored, and duplicates
+// are dropped while preserving order.
+func collectTxHashes(cmdArgs []string) ([]string, error) {
+	hashes := append([]string{}, cmdArgs...)
+
+	if batchFileFlag != "" {
+		f, err := os.Open(batchFileFlag)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("failed to open hash file: %v", err))
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			hashes = append(hashes, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("failed to read hash file: %v", err))
+		}
+	}
+
+	seen := make(map[string]struct{}, len(hashes))
+	unique := hashes[:0]
+	for _, h := range hashes {
+		if _, ok := seen[h]; ok {
+			continue
+		}
+		seen[h] = struct{}{}
+		unique = append(unique, h)
+	}
+
+	for _, h := range unique {
+		if err := rpc.ValidateTransactionHash(h); err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("invalid transaction hash format %q: %v", h, err))
+		}
+	}
+
+	if len(unique) == 0 {
+		return nil, errors.WrapValidationError("no transaction hashes to debug")
+	}
+	return unique, nil
+}
+
+// runBatchDebug fetches and simulates every transaction using a fixed-size
+// worker pool, then prints one aggregated summary. With --output ndjson each
+// result is instead printed on its own line as soon as it is known. When ctx
+// is canceled no further transactions are started, those in flight are
+// drained and the output covers what was done.
+func runBatchDebug(ctx context.Context, client *rpc.Client, runner simulator.RunnerInterface, hashes []string, workers int) error {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(hashes) {
+		workers = len(hashes)
+	}
+	if protocolVersionFlag > 0 {
+		if err := simulator.Validate(protocolVersionFlag); err != nil {
+			return fmt.Errorf("invalid protocol version %d: %w", protocolVersionFlag, err)
+		}
+	}
+
+	statusf("Batch debugging %d transactions with %d workers on %s\n", len(hashes), workers, networkFlag)
+
+	results := make([]BatchResult, len(hashes))
+	jobs := make(chan int)
+	workCtx, stopWork := drainContext(ctx, batchDrainFlag)
+	defer stopWork()
+
+	var (
+		wg        sync.WaitGroup
+		outputMu  sync.Mutex
+		outputErr error
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := debugOne(workCtx, client, runner, hashes[i])
+				results[i] = result
+				outputMu.Lock()
+				statusf("  [%d/%d] %s: %s\n", i+1, len(hashes), hashes[i], result.Status)
+				if result.hookOutput != "" {
+					statusf("%s\n", result.hookOutput)
+				}
+				if ndjsonOutput() && outputErr == nil {
+					outputErr = printNDJSON(result)
+				}
+				outputMu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for i := range hashes {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			statusf("Stopping: waiting up to %s for transactions in flight (Ctrl+C again to abort)\n", batchDrainFlag)
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := range results {
+		if results[i].Status == "" {
+			results[i] = BatchResult{TxHash: hashes[i], Status: batchSkipped}
+			if ndjsonOutput() && outputErr == nil {
+				outputErr = printNDJSON(results[i])
+			}
+		}
+	}
+
+	summary := summarizeBatch(results)
+	switch {
+	case ndjsonOutput():
+		if outputErr != nil {
+			return outputErr
+		}
+	case jsonOutput():
+		if err := printJSON(summary); err != nil {
+			return err
+		}
+	default:
+		printBatchSummary(summary)
+	}
+	if err := ctx.Err(); err != nil {
+		return &afterOutputError{err: err}
+	}
+	if !debugCheckFlag {
+		return hookBatchCheck(summary)
+	}
+	return checkBatch(summary)
+}
+
+// hookBatchCheck fails the batch when a hook failed one of its
+// transactions, which it does with or without --check
+func hookBatchCheck(summary BatchSummary) error {
+	failed := 0
+	for _, r := range summary.Results {
+		if r.HookFailure != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.WrapCheckFailed(fmt.Sprintf("hooks failed %d of %d transactions", failed, summary.Total))
+	}
+	return nil
+}
+
+// checkBatch implements --check for a batch. A transaction that could not be
+// simulated takes precedence over one that failed, as the batch is
+// incomplete.
+func checkBatch(summary BatchSummary) error {
+	failed := 0
+	for _, r := range summary.Results {
+		if r.err != nil {
+			return &afterOutputError{err: fmt.Errorf("%s: %w", r.TxHash, r.err)}
+		}
+		if r.Status == "error" || r.HookFailure != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.WrapCheckFailed(fmt.Sprintf("%d of %d transactions failed", failed, summary.Total))
+	}
+	return nil
+}
+
+// debugOne runs the fetch/extract/simulate pipeline for a single transaction
+func debugOne(ctx context.Context, client *rpc.Client, runner simulator.RunnerInterface, txHash string) BatchResult {
+	result := BatchResult{TxHash: txHash}
+	fail := func(err error) BatchResult {
+		result.Status = "failed"
+		result.Error = err.Error()
+		result.err = err
+		return result
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fail(err)
+	}
+
+	resp, err := client.GetTransaction(ctx, txHash)
+	if err != nil {
+		return fail(errors.WrapRPCConnectionFailed(err))
+	}
+
+	if ops, _ := decoder.DescribeOperations(resp.EnvelopeXdr, resp.ResultXdr); classicOnly(ops) {
+		res, err := classicResult(resp.ResultXdr, ops)
+		if err != nil {
+			return fail(err)
+		}
+		result.Status = "success"
+		if !res.Succeeded() {
+			result.Status = "error"
+			result.Error = res.Code
+			if res.FailedOperation != "" {
+				result.Error = res.FailedOperation
+			}
+		}
+		return result
+	}
+
+	_, simResp, err := simulateFetchedTransaction(ctx, client, runner, txHash, resp)
+	if err != nil {
+		return fail(err)
+	}
+
+	result.Status = simResp.Status
+	result.Error = simResp.Error
+	if simResp.BudgetUsage != nil {
+		result.CPUInstructions = simResp.BudgetUsage.CPUInstructions
+		result.MemoryBytes = simResp.BudgetUsage.MemoryBytes
+	}
+
+	hookResult, err := runTransactionHooks(ctx, client, hooks.Input{
+		Command:     "debug",
+		Network:     networkFlag,
+		TxHash:      txHash,
+		EnvelopeXdr: resp.EnvelopeXdr,
+		Simulation:  simResp,
+	})
+	if err != nil {
+		return fail(err)
+	}
+	if hookResult != nil {
+		result.Annotations = hookResult.Annotations
+		result.hookOutput = hookResult.Output
+		if hookResult.Fail {
+			result.HookFailure = hookResult.Reason
+		}
+	}
+	return result
+}
+
+// simulateFetchedTransaction replays an already fetched transaction using the
+// ledger state resolved by resolveLedgerState
+func simulateFetchedTransaction(ctx context.Context, client *rpc.Client, runner simulator.RunnerInterface, txHash string, resp *rpc.TransactionResponse) (*simulator.SimulationRequest, *simulator.SimulationResponse, error) {
+	ledgerEntries, err := resolveLedgerState(ctx, client, txHash, resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	simReq := &simulator.SimulationRequest{
+		EnvelopeXdr:          resp.EnvelopeXdr,
+		ResultMetaXdr:        resp.ResultMetaXdr,
+		LedgerEntries:        ledgerEntries,
+		LedgerEntryOverrides: ledgerOverrides,
+		Timestamp:            TimestampFlag,
+		LedgerSequence:       resp.Ledger,
+	}
+	if protocolVersionFlag > 0 {
+		simReq.ProtocolVersion = &protocolVersionFlag
+	}
+	applyBudgetLimits(simReq)
+
+	simResp, err := simulator.RunTraced(ctx, runner, simReq)
+	if err != nil {
+		return nil, nil, errors.WrapSimulationFailed(err, "")
+	}
+	analyzeArchival(ctx, client, simReq, simResp)
+	nameContractError(ctx, client, simReq.EnvelopeXdr, simResp)
+	return simReq, simResp, nil
+}
+
+// batchSkipped is the status of a transaction never scheduled because the
+// batch was interrupted
+const batchSkipped = "skipped"
+
+// summarizeBatch counts the results by status. Results without a status were
+// never scheduled and are reported as skipped, so every hash is accounted for.
+func summarizeBatch(results []BatchResult) BatchSummary {
+	summary := BatchSummary{
+		ByStatus: make(map[string]int),
+		Results:  results,
+	}
+	for i := range results {
+		if results[i].Status == "" {
+			results[i].Status = batchSkipped
+		}
+		summary.Total++
+		summary.ByStatus[results[i].Status]++
+	}
+	return summary
+}
+
+func printBatchSummary(summary BatchSummary) {
+	fmt.Printf("\n=== Batch Summary (%d transactions) ===\n", summary.Total)
+
+	statuses := make([]string, 0, len(summary.ByStatus))
+	for status := range summary.ByStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		fmt.Printf("  %-10s %d\n", status+":", summary.ByStatus[status])
+	}
+
+	fmt.Printf("\nTransactions:\n")
+	for _, r := range summary.Results {
+		fmt.Printf("  %s  %s\n", r.TxHash, r.Status)
+		for _, a := range r.Annotations {
+			fmt.Printf("      [%s] %s (%s)\n", a.Level, a.Message, a.Hook)
+		}
+		if r.HookFailure != "" {
+			fmt.Printf("      Failed by hook: %s\n", r.HookFailure)
+		}
+	}
+
+	// Group identical errors so that a common root cause stands out
+	errorCounts := make(map[string]int)
+	for _, r := range summary.Results {
+		if r.Error != "" {
+			errorCounts[r.Error]++
+		}
+	}
+	if len(errorCounts) == 0 {
+		return
+	}
+
+	type errorCount struct {
+		msg   string
+		count int
+	}
+	grouped := make([]errorCount, 0, len(errorCounts))
+	for msg, count := range errorCounts {
+		grouped = append(grouped, errorCount{msg, count})
+	}
+	sort.Slice(grouped, func(i, j int) bool {
+		if grouped[i].count != grouped[j].count {
+			return grouped[i].count > grouped[j].count
+		}
+		return grouped[i].msg < grouped[j].msg
+	})
+
+	fmt.Printf("\nErrors:\n")
+	for _, g := range grouped {
+		fmt.Printf("  %4dx %s\n", g.count, g.msg)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectTxHashes(t *testing.T) {
+	hashA := strings.Repeat("a", 64)
+	hashB := strings.Repeat("b", 64)
+	hashC := strings.Repeat("c", 64)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "hashes.txt")
+	content := "# failed swaps\n" + hashB + "\n\n  " + hashC + "  \n" + hashA + "\n"
+	require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+
+	batchFileFlag = file
+	defer func() { batchFileFlag = "" }()
+
+	hashes, err := collectTxHashes([]string{hashA})
+	require.NoError(t, err)
+	assert.Equal(t, []string{hashA, hashB, hashC}, hashes)
+}
+
+func TestCollectTxHashes_Invalid(t *testing.T) {
+	_, err := collectTxHashes([]string{strings.Repeat("a", 64), "not-a-hash"})
+	assert.Error(t, err)
+
+	_, err = collectTxHashes(nil)
+	assert.Error(t, err)
+}
+
+func TestIsBatchMode(t *testing.T) {
+	assert.False(t, isBatchMode([]string{"one"}))
+	assert.True(t, isBatchMode([]string{"one", "two"}))
+
+	batchFileFlag = "hashes.txt"
+	defer func() { batchFileFlag = "" }()
+	assert.True(t, isBatchMode(nil))
+}
+
+func TestSummarizeBatch(t *testing.T) {
+	summary := summarizeBatch([]BatchResult{
+		{TxHash: "a", Status: "success"},
+		{TxHash: "b", Status: "error", Error: "HostError"},
+		{TxHash: "c", Status: "failed", Error: "rpc down"},
+		{TxHash: "d", Status: "error", Error: "HostError"},
+		{TxHash: "e"}, // never scheduled
+	})
+
+	assert.Equal(t, 5, summary.Total)
+	assert.Equal(t, map[string]int{"success": 1, "error": 2, "failed": 1, "skipped": 1}, summary.ByStatus)
+	require.Len(t, summary.Results, 5)
+	assert.Equal(t, "skipped", summary.Results[4].Status)
+}
+
+func TestHookBatchCheck(t *testing.T) {
+	summary := summarizeBatch([]BatchResult{
+		{TxHash: "a", Status: "success"},
+		{TxHash: "b", Status: "success", HookFailure: "owned by payments"},
+	})
+	assert.Error(t, hookBatchCheck(summary))
+	assert.ErrorContains(t, checkBatch(summary), "1 of 2 transactions failed")
+
+	summary = summarizeBatch([]BatchResult{{TxHash: "a", Status: "error"}})
+	assert.NoError(t, hookBatchCheck(summary), "without --check only hooks fail the batch")
+}
@@ -0,0 +1,284 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	stepFlag        bool
+	breakpointFlags []string
+)
+
+// stepMode controls which pause points are shown to the user
+type stepMode int
+
+const (
+	stepEvery    stepMode = iota // stop at every pause point
+	stepFrames                   // stop only at contract call/return
+	stepContinue                 // stop only at breakpoints
+)
+
+// storageEntry is one contract data entry from the pre-transaction state
+type storageEntry struct {
+	Contract string
+	Key      string
+	Value    string
+}
+
+// stepSession is the interactive prompt behind erst debug --step
+type stepSession struct {
+	reader      *bufio.Reader
+	out         io.Writer
+	mode        stepMode
+	breakpoints []string
+	storage     []storageEntry
+	last        *simulator.StepEvent
+}
+
+func newStepSession(in io.Reader, out io.Writer, breakpoints []string, ledgerEntries map[string]string) *stepSession {
+	s := &stepSession{
+		reader:      bufio.NewReader(in),
+		out:         out,
+		breakpoints: append([]string(nil), breakpoints...),
+		storage:     contractStorage(ledgerEntries),
+	}
+	if len(s.breakpoints) > 0 {
+		s.mode = stepContinue
+	}
+	return s
+}
+
+// runStepDebug runs the simulation under the interactive stepper
+func runStepDebug(runner *simulator.Runner, req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+	session := newStepSession(os.Stdin, os.Stdout, breakpointFlags, req.LedgerEntries)
+	session.printHelp()
+
+	resp, aborted, err := runner.RunStepping(req, session)
+	if err != nil {
+		return nil, errors.WrapSimulationFailed(err, "")
+	}
+	if aborted {
+		fmt.Fprintf(session.out, "\n%s Execution aborted from the step prompt\n", visualizer.Warning())
+	}
+	return resp, nil
+}
+
+// OnPause implements simulator.StepController
+func (s *stepSession) OnPause(event simulator.StepEvent) simulator.StepAction {
+	s.last = &event
+
+	hit := s.breakpointFor(event)
+	switch {
+	case hit != "":
+		fmt.Fprintf(s.out, "\n%s Breakpoint %q\n", visualizer.Symbol("pin"), hit)
+	case s.mode == stepContinue:
+		return simulator.StepResume
+	case s.mode == stepFrames && event.Kind == simulator.StepKindHostFn:
+		return simulator.StepResume
+	}
+
+	s.printEvent(event)
+	return s.prompt()
+}
+
+func (s *stepSession) breakpointFor(event simulator.StepEvent) string {
+	for _, bp := range s.breakpoints {
+		if event.Matches(bp) {
+			return bp
+		}
+	}
+	return ""
+}
+
+func (s *stepSession) prompt() simulator.StepAction {
+	for {
+		fmt.Fprint(s.out, "step> ")
+		input, err := s.reader.ReadString('\n')
+		if err != nil && strings.TrimSpace(input) == "" {
+			// Closed stdin leaves no way to resume, so stop cleanly
+			fmt.Fprintln(s.out)
+			return simulator.StepAbort
+		}
+
+		if action, done := s.handleCommand(strings.TrimSpace(input)); done {
+			return action
+		}
+	}
+}
+
+// handleCommand runs one prompt command. done is true when the simulator
+// should be answered with action.
+func (s *stepSession) handleCommand(command string) (action simulator.StepAction, done bool) {
+	parts := strings.Fields(command)
+	if len(parts) == 0 {
+		parts = []string{"s"}
+	}
+
+	switch strings.ToLower(parts[0]) {
+	case "s", "step":
+		s.mode = stepEvery
+		return simulator.StepResume, true
+	case "n", "next":
+		s.mode = stepFrames
+		return simulator.StepResume, true
+	case "c", "continue":
+		s.mode = stepContinue
+		return simulator.StepResume, true
+	case "a", "abort", "q", "quit":
+		return simulator.StepAbort, true
+	case "b", "break":
+		if len(parts) < 2 {
+			s.listBreakpoints()
+			return "", false
+		}
+		s.breakpoints = append(s.breakpoints, parts[1])
+		fmt.Fprintf(s.out, "Breakpoint set on %q\n", parts[1])
+	case "d", "delete":
+		if len(parts) < 2 {
+			fmt.Fprintln(s.out, "Usage: delete <pattern>")
+			return "", false
+		}
+		s.deleteBreakpoint(parts[1])
+	case "r", "args":
+		if s.last != nil {
+			s.printArgs(*s.last)
+		}
+	case "st", "storage":
+		filter := ""
+		if len(parts) > 1 {
+			filter = parts[1]
+		}
+		s.printStorage(filter)
+	case "h", "help", "?":
+		s.printHelp()
+	default:
+		fmt.Fprintf(s.out, "Unknown command: %s (type 'help')\n", parts[0])
+	}
+	return "", false
+}
+
+func (s *stepSession) deleteBreakpoint(pattern string) {
+	for i, bp := range s.breakpoints {
+		if bp == pattern {
+			s.breakpoints = append(s.breakpoints[:i], s.breakpoints[i+1:]...)
+			fmt.Fprintf(s.out, "Breakpoint %q removed\n", pattern)
+			return
+		}
+	}
+	fmt.Fprintf(s.out, "No breakpoint %q\n", pattern)
+}
+
+func (s *stepSession) listBreakpoints() {
+	if len(s.breakpoints) == 0 {
+		fmt.Fprintln(s.out, "No breakpoints set. Usage: break <function|contract>")
+		return
+	}
+	for _, bp := range s.breakpoints {
+		fmt.Fprintf(s.out, "  %s\n", bp)
+	}
+}
+
+func (s *stepSession) printEvent(event simulator.StepEvent) {
+	indent := strings.Repeat("  ", event.Depth)
+	switch event.Kind {
+	case simulator.StepKindCall:
+		if event.Name != "" {
+			fmt.Fprintf(s.out, "[#%d] %s-> enter %s:%s (depth %d)\n", event.Seq, indent, event.Contract, event.Name, event.Depth)
+			break
+		}
+		fmt.Fprintf(s.out, "[#%d] %s-> enter frame (depth %d)\n", event.Seq, indent, event.Depth)
+	case simulator.StepKindReturn:
+		fmt.Fprintf(s.out, "[#%d] %s<- leave frame (depth %d)\n", event.Seq, indent, event.Depth)
+	default:
+		fmt.Fprintf(s.out, "[#%d] %s%s\n", event.Seq, indent, event.Name)
+	}
+	s.printArgs(event)
+}
+
+func (s *stepSession) printArgs(event simulator.StepEvent) {
+	for i, arg := range event.Args {
+		fmt.Fprintf(s.out, "       arg %d: %s\n", i, arg)
+	}
+}
+
+func (s *stepSession) printStorage(filter string) {
+	fmt.Fprintln(s.out, "Contract storage (pre-transaction state):")
+	shown := 0
+	for _, entry := range s.storage {
+		if filter != "" && !strings.Contains(entry.Contract, filter) && !strings.Contains(entry.Key, filter) {
+			continue
+		}
+		fmt.Fprintf(s.out, "  %s  %s = %s\n", entry.Contract, entry.Key, entry.Value)
+		shown++
+	}
+	if shown == 0 {
+		fmt.Fprintln(s.out, "  (none)")
+	}
+}
+
+func (s *stepSession) printHelp() {
+	fmt.Fprintln(s.out, "Step commands:")
+	fmt.Fprintln(s.out, "  s, step              Stop at the next pause point (default on empty input)")
+	fmt.Fprintln(s.out, "  n, next              Stop at the next contract call or return")
+	fmt.Fprintln(s.out, "  c, continue          Run until the next breakpoint")
+	fmt.Fprintln(s.out, "  b, break [pattern]   Break on a function or contract, or list breakpoints")
+	fmt.Fprintln(s.out, "  d, delete <pattern>  Remove a breakpoint")
+	fmt.Fprintln(s.out, "  r, args              Show the current arguments again")
+	fmt.Fprintln(s.out, "  st, storage [filter] Show contract storage")
+	fmt.Fprintln(s.out, "  a, abort             Abort execution")
+}
+
+// contractStorage decodes the contract data entries in the simulation's
+// ledger state, sorted by contract and key
+func contractStorage(ledgerEntries map[string]string) []storageEntry {
+	var out []storageEntry
+	for _, entryXdr := range ledgerEntries {
+		var entry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshalBase64(entryXdr, &entry); err != nil {
+			continue
+		}
+		data := entry.Data.ContractData
+		if data == nil {
+			continue
+		}
+		out = append(out, storageEntry{
+			Contract: scAddressString(data.Contract),
+			Key:      decoder.FormatScVal(data.Key),
+			Value:    decoder.FormatScVal(data.Val),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Contract != out[j].Contract {
+			return out[i].Contract < out[j].Contract
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+func scAddressString(address xdr.ScAddress) string {
+	if address.Type == xdr.ScAddressTypeScAddressTypeContract && address.ContractId != nil {
+		if id, err := strkey.Encode(strkey.VersionByteContract, address.ContractId[:]); err == nil {
+			return id
+		}
+	}
+	if address.Type == xdr.ScAddressTypeScAddressTypeAccount && address.AccountId != nil {
+		return address.AccountId.Address()
+	}
+	return address.Type.String()
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stepEvents() []simulator.StepEvent {
+	return []simulator.StepEvent{
+		{Seq: 1, Kind: simulator.StepKindCall, Depth: 1},
+		{Seq: 2, Kind: simulator.StepKindHostFn, Depth: 1, Name: "get_contract_data", Args: []string{"Symbol(balance)"}},
+		{Seq: 3, Kind: simulator.StepKindHostFn, Depth: 1, Name: "call", Args: []string{"Object(Address(obj#5))", "Symbol(transfer)"}},
+		{Seq: 4, Kind: simulator.StepKindCall, Depth: 2, Name: "transfer", Contract: "d7a4c1b2"},
+		{Seq: 5, Kind: simulator.StepKindReturn, Depth: 2},
+	}
+}
+
+// drive feeds events to the session until it aborts, returning the actions
+func drive(s *stepSession, events []simulator.StepEvent) []simulator.StepAction {
+	var actions []simulator.StepAction
+	for _, ev := range events {
+		action := s.OnPause(ev)
+		actions = append(actions, action)
+		if action == simulator.StepAbort {
+			break
+		}
+	}
+	return actions
+}
+
+func TestStepSession_StepsEveryPause(t *testing.T) {
+	var out bytes.Buffer
+	s := newStepSession(strings.NewReader("s\n\nstep\ns\ns\n"), &out, nil, nil)
+
+	actions := drive(s, stepEvents())
+	assert.Equal(t, []simulator.StepAction{"resume", "resume", "resume", "resume", "resume"}, actions)
+	assert.Contains(t, out.String(), "get_contract_data")
+	assert.Contains(t, out.String(), "arg 1: Symbol(transfer)")
+}
+
+func TestStepSession_NextSkipsHostFunctions(t *testing.T) {
+	var out bytes.Buffer
+	s := newStepSession(strings.NewReader("n\nn\nn\n"), &out, nil, nil)
+
+	drive(s, stepEvents())
+	assert.NotContains(t, out.String(), "get_contract_data")
+	assert.Equal(t, 3, strings.Count(out.String(), "step> "))
+}
+
+func TestStepSession_BreakpointFromFlag(t *testing.T) {
+	var out bytes.Buffer
+	s := newStepSession(strings.NewReader("a\n"), &out, []string{"transfer"}, nil)
+
+	actions := drive(s, stepEvents())
+	assert.Equal(t, []simulator.StepAction{"resume", "resume", "abort"}, actions)
+	assert.Contains(t, out.String(), `Breakpoint "transfer"`)
+	assert.Equal(t, 1, strings.Count(out.String(), "step> "))
+}
+
+func TestStepSession_BreakOnContractID(t *testing.T) {
+	hash := append([]byte{0xd7, 0xa4, 0xc1, 0xb2}, make([]byte, 28)...)
+	contractID, err := strkey.Encode(strkey.VersionByteContract, hash)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	s := newStepSession(strings.NewReader("a\n"), &out, []string{contractID}, nil)
+
+	actions := drive(s, stepEvents())
+	assert.Equal(t, []simulator.StepAction{"resume", "resume", "resume", "abort"}, actions)
+	assert.Contains(t, out.String(), "-> enter d7a4c1b2:transfer (depth 2)")
+}
+
+func TestStepSession_BreakAndDeleteCommands(t *testing.T) {
+	var out bytes.Buffer
+	s := newStepSession(strings.NewReader("b balance\nb\nd balance\nd missing\nc\n"), &out, nil, nil)
+
+	action := s.OnPause(stepEvents()[0])
+	assert.Equal(t, simulator.StepResume, action)
+	assert.Empty(t, s.breakpoints)
+	assert.Contains(t, out.String(), `Breakpoint set on "balance"`)
+	assert.Contains(t, out.String(), `No breakpoint "missing"`)
+	assert.Equal(t, stepContinue, s.mode)
+}
+
+func TestStepSession_ClosedInputAborts(t *testing.T) {
+	var out bytes.Buffer
+	s := newStepSession(strings.NewReader(""), &out, nil, nil)
+
+	assert.Equal(t, simulator.StepAbort, s.OnPause(stepEvents()[0]))
+}
+
+func TestContractStorage(t *testing.T) {
+	contractID := xdr.ContractId{1, 2, 3}
+	sym := xdr.ScSymbol("counter")
+	count := xdr.Uint32(7)
+	entry := xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+				Key:        xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym},
+				Durability: xdr.ContractDataDurabilityPersistent,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &count},
+			},
+		},
+	}
+	entryXdr, err := xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+
+	storage := contractStorage(map[string]string{"key": entryXdr, "bad": "not-xdr"})
+	require.Len(t, storage, 1)
+	assert.True(t, strings.HasPrefix(storage[0].Contract, "C"))
+	assert.Equal(t, "7", storage[0].Value)
+
+	var out bytes.Buffer
+	s := newStepSession(strings.NewReader(""), &out, nil, map[string]string{"key": entryXdr})
+	s.printStorage("")
+	assert.Contains(t, out.String(), "= 7")
+}
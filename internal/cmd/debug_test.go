@@ -4,16 +4,26 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/snapshot"
 	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoadOverrideState(t *testing.T) {
@@ -158,6 +168,47 @@ func TestOverrideData_JSONMarshaling(t *testing.T) {
 	}
 }
 
+func TestParseOverrideEntry(t *testing.T) {
+	entry := xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{
+				AccountId: xdr.MustAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"),
+				Balance:   42,
+			},
+		},
+	}
+	key, err := entry.LedgerKey()
+	assert.NoError(t, err)
+	keyXdr, _ := xdr.MarshalBase64(key)
+	entryXdr, _ := xdr.MarshalBase64(entry)
+	entryBytes, _ := entry.MarshalBinary()
+
+	dir := t.TempDir()
+	textFile := filepath.Join(dir, "entry.xdr")
+	binFile := filepath.Join(dir, "entry.bin")
+	assert.NoError(t, os.WriteFile(textFile, []byte(entryXdr+"\n"), 0644))
+	assert.NoError(t, os.WriteFile(binFile, entryBytes, 0644))
+
+	for _, spec := range []string{keyXdr + "=" + textFile, textFile, binFile} {
+		gotKey, gotEntry, err := parseOverrideEntry(spec)
+		assert.NoError(t, err, spec)
+		assert.Equal(t, keyXdr, gotKey, spec)
+		assert.Equal(t, entryXdr, gotEntry, spec)
+	}
+
+	otherKey := xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")},
+	}
+	otherKeyXdr, _ := xdr.MarshalBase64(otherKey)
+	_, _, err = parseOverrideEntry(otherKeyXdr + "=" + textFile)
+	assert.Error(t, err, "entry does not match key")
+
+	_, _, err = parseOverrideEntry(filepath.Join(dir, "missing.xdr"))
+	assert.Error(t, err)
+}
+
 // MockRunner implements simulator.RunnerInterface for testing
 type MockRunner struct {
 	mock.Mock
@@ -290,3 +341,161 @@ func TestExtractLedgerKeys(t *testing.T) {
 	}
 	assert.True(t, found, "Key not found in extracted keys")
 }
+
+func TestWriteProfileFormats(t *testing.T) {
+	dir := t.TempDir()
+	resp := &simulator.SimulationResponse{
+		Flamegraph:   "<svg></svg>",
+		FoldedStacks: "Total;CPU 10\nTotal;Memory 4\n",
+	}
+
+	for _, format := range []string{ProfileFormatSVG, ProfileFormatFolded, ProfileFormatPprof, ProfileFormatSpeedscope} {
+		path := filepath.Join(dir, defaultProfilePath("abcdef0123456789", format))
+		require.NoError(t, writeProfile(resp, format, path), format)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size())
+	}
+
+	folded, err := os.ReadFile(filepath.Join(dir, "abcdef01.folded"))
+	require.NoError(t, err)
+	assert.Equal(t, resp.FoldedStacks, string(folded))
+
+	speedscope, err := os.ReadFile(filepath.Join(dir, "abcdef01.speedscope.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(speedscope), `"name":"abcdef01"`)
+
+	err = writeProfile(&simulator.SimulationResponse{}, ProfileFormatPprof, filepath.Join(dir, "empty.pb.gz"))
+	assert.Error(t, err)
+}
+
+func TestValidateProfileFlags(t *testing.T) {
+	defer func() { profileFormatFlag, profileOutFlag, ProfileFlag = ProfileFormatSVG, "", false }()
+
+	profileFormatFlag = "flame"
+	assert.Error(t, validateProfileFlags(true))
+
+	profileFormatFlag, profileOutFlag, ProfileFlag = ProfileFormatPprof, "out.pb.gz", false
+	require.NoError(t, validateProfileFlags(false))
+	assert.True(t, ProfileFlag)
+}
+
+func TestNetworkPassphraseOverride(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer func() { networkPassphraseFlag = "" }()
+
+	assert.Equal(t, rpc.TestnetConfig.NetworkPassphrase, networkPassphrase("testnet"))
+
+	networkPassphraseFlag = "Fork Network ; 2025"
+	assert.Equal(t, "Fork Network ; 2025", networkPassphrase("testnet"))
+	assert.Equal(t, rpc.TestnetConfig.NetworkPassphrase, namedNetworkPassphrase("testnet"))
+
+	client, err := rpc.NewClient(append([]rpc.ClientOption{rpc.WithNetwork(rpc.Testnet)}, rpcSharedOptions("testnet")...)...)
+	require.NoError(t, err)
+	assert.Equal(t, "Fork Network ; 2025", client.GetNetworkPassphrase())
+}
+
+func TestPrintMemoryProfile_WithoutTotalMemory(t *testing.T) {
+	resp := &simulator.SimulationResponse{
+		Error:       "HostError: Error(Budget, ExceededLimit)",
+		BudgetUsage: &simulator.BudgetUsage{MemoryUsagePercent: 100},
+		FunctionCosts: []simulator.FunctionCost{
+			{Contract: "CAAA", Function: "grow", SelfMemBytes: 4096, MemoryProfiled: true},
+		},
+	}
+	out := captureStdout(t, func() { printMemoryProfile(resp) })
+	assert.Contains(t, out, "CAAA.grow")
+	assert.NotContains(t, out, "Memory budget exhausted")
+	assert.NotContains(t, out, "Inf")
+
+	resp.BudgetUsage.MemoryBytes = 8192
+	out = captureStdout(t, func() { printMemoryProfile(resp) })
+	assert.Contains(t, out, "used 50% of it")
+}
+
+// simulatingRPCServer is a debugRPCServer whose simulateTransaction fails
+// with message, counting the simulations it runs
+func simulatingRPCServer(t *testing.T, message string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	backend := debugRPCServer(t).Config.Handler
+	var simulations atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Method string `json:"method"`
+		}
+		if json.Unmarshal(body, &req) == nil && req.Method == "simulateTransaction" {
+			simulations.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": map[string]interface{}{"error": message}})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		backend.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &simulations
+}
+
+func TestDebugCompareNetwork_RPCBackendSimulatesOnEachNetwork(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	primary, primarySims := simulatingRPCServer(t, "failed on testnet")
+	other, otherSims := simulatingRPCServer(t, "failed on futurenet")
+	t.Setenv("ERST_RPC_URLS_FUTURENET", other.URL)
+
+	out := string(runErst(t, "debug", strings.Repeat("ab", 32), "--network", "testnet", "--rpc-url", primary.URL,
+		"--compare-network", "futurenet", "--backend", "rpc"))
+
+	assert.Equal(t, int32(1), primarySims.Load(), "the primary network simulates on its own node")
+	assert.Equal(t, int32(1), otherSims.Load(), "the compare network simulates on its own node")
+	assert.Contains(t, out, "failed on testnet")
+	assert.Contains(t, out, "failed on futurenet")
+}
+
+func TestDebugCompareNetwork_UsesTheSnapshotCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fakeSimulator(t, `{"status":"success"}`)
+	server := debugRPCServer(t)
+	t.Setenv("ERST_RPC_URLS_FUTURENET", server.URL)
+	txHash := strings.Repeat("ab", 32)
+
+	cache, err := snapshot.NewDefaultCache()
+	require.NoError(t, err)
+	require.NoError(t, cache.Put("testnet", 100, txHash, snapshot.FromMap(map[string]string{"k1": "v1", "k2": "v2"})))
+	out := string(runErst(t, "debug", txHash, "--network", "testnet", "--rpc-url", server.URL, "--compare-network", "futurenet"))
+	assert.Contains(t, out, "Loaded 2 ledger entries from cached snapshot (ledger 100)")
+
+	snapPath := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, snapshot.Save(snapPath, snapshot.FromMap(map[string]string{"k1": "v1"})))
+	out = string(runErst(t, "debug", txHash, "--network", "testnet", "--rpc-url", server.URL, "--compare-network", "futurenet", "--snapshot", snapPath))
+	assert.Contains(t, out, "Loaded 1 ledger entries from snapshot")
+}
+
+func TestDebugCompareNetwork_ProfilesBothNetworks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake simulator needs a POSIX shell")
+	}
+	t.Setenv("HOME", t.TempDir())
+	// Each simulation saves its request to a file of its own
+	dir := t.TempDir()
+	sim := filepath.Join(dir, "erst-sim")
+	script := "#!/bin/sh\ncat > \"" + dir + "/request.$$.json\"\necho '{\"status\":\"success\"}'\n"
+	require.NoError(t, os.WriteFile(sim, []byte(script), 0o755))
+	t.Setenv("ERST_SIM_PATH", sim)
+	server := debugRPCServer(t)
+	t.Setenv("ERST_RPC_URLS_FUTURENET", server.URL)
+
+	runErst(t, "debug", strings.Repeat("ab", 32), "--network", "testnet", "--rpc-url", server.URL,
+		"--compare-network", "futurenet", "--profile", "--profile-memory", "--profile-out", filepath.Join(dir, "profile.pb.gz"), "--no-cache")
+
+	requests, err := filepath.Glob(filepath.Join(dir, "request.*.json"))
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	for _, path := range requests {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var req simulator.SimulationRequest
+		require.NoError(t, json.Unmarshal(data, &req))
+		assert.True(t, req.Profile, "%s is profiled", path)
+		assert.True(t, req.ProfileMemory, "%s splits memory", path)
+	}
+}
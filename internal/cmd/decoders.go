@@ -0,0 +1,79 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/plugin"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	userDecodersOnce sync.Once
+	userDecodersSet  *plugin.Registry
+)
+
+// userDecoders returns the plugin registry of ~/.erst/decoders, loading it
+// the first time it is needed. Plugins that fail to load are skipped with a
+// warning.
+func userDecoders() *plugin.Registry {
+	userDecodersOnce.Do(func() {
+		userDecodersSet = plugin.NewRegistry()
+		dir, err := plugin.DefaultDecoderDir()
+		if err != nil {
+			return
+		}
+		if err := userDecodersSet.LoadFromDirectory(dir); err != nil {
+			logger.Logger.Warn("Some contract decoders could not be loaded", "dir", dir, "error", err)
+		}
+	})
+	return userDecodersSet
+}
+
+// pluginDecode asks the decoder registered for the request's contract, if
+// any, to describe it. Decoder failures are logged and leave the built-in
+// rendering in place.
+func pluginDecode(ctx context.Context, req plugin.DecodeRequest) (*plugin.Decoded, string) {
+	if req.ContractID == "" {
+		return nil, ""
+	}
+	decoded, name, err := userDecoders().DecodeContract(ctx, req)
+	if err != nil {
+		logger.Logger.Warn("Contract decoder failed", "contract_id", req.ContractID, "kind", req.Kind, "error", err)
+		return nil, ""
+	}
+	return decoded, name
+}
+
+// eventSummary describes a contract event with its registered decoder, or in
+// token terms when it comes from a Stellar Asset Contract, or returns ""
+func eventSummary(ctx context.Context, network, contractID string, topicsXdr []string, dataXdr string) string {
+	decoded, name := pluginDecode(ctx, plugin.DecodeRequest{
+		Kind:       plugin.KindEvent,
+		ContractID: contractID,
+		Network:    network,
+		Topics:     topicsXdr,
+		Data:       dataXdr,
+	})
+	if decoded != nil && decoded.Summary != "" {
+		return fmt.Sprintf("%s: %s", name, decoded.Summary)
+	}
+	return sacEventSummary(network, contractID, topicsXdr, dataXdr)
+}
+
+// invocationRequest builds the decoder request for a contract call. Arguments
+// that cannot be encoded are sent as "".
+func invocationRequest(contractID, function string, args []xdr.ScVal) plugin.DecodeRequest {
+	encoded := make([]string, len(args))
+	for i, arg := range args {
+		if b64, err := xdr.MarshalBase64(arg); err == nil {
+			encoded[i] = b64
+		}
+	}
+	return plugin.DecodeRequest{Kind: plugin.KindInvocation, ContractID: contractID, Function: function, Args: encoded}
+}
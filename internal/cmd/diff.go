@@ -0,0 +1,203 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/compare"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/profile"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffWasmFlag            string
+	diffProtocolVersionFlag uint32
+	diffFlamegraphFlag      string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <session-a> [session-b]",
+	Short: "Compare two simulations or sessions",
+	Long: `Compare two saved sessions, or a saved session against a fresh
+re-simulation, and highlight differences in status, events, return values,
+resource consumption and storage writes.
+
+Sessions can be referenced by session ID or by transaction hash. With a single
+session the transaction is re-simulated offline from its stored state, which
+combined with --wasm shows whether a contract upgrade fixes a failure.
+
+When both runs were profiled, the cost of each contract function is compared
+as well, and --flamegraph-diff writes differential folded stacks that
+flamegraph.pl or inferno-flamegraph render as a differential flamegraph. The
+second run is the baseline: deltas are the first run minus the second.`,
+	Example: `  # Compare two saved sessions
+  erst diff abc12345-1700000000 def67890-1700000500
+
+  # Check whether a patched contract fixes a recorded failure
+  erst diff abc12345-1700000000 --wasm ./patched.wasm
+
+  # What got more expensive after an upgrade (new session first)
+  erst diff after-1700000500 before-1700000000 --flamegraph-diff upgrade.folded
+  inferno-flamegraph upgrade.folded > upgrade.svg
+
+  # Machine-readable output
+  erst diff abc12345-1700000000 --output json`,
+	Args: cobra.RangeArgs(1, 2),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 2 && (diffWasmFlag != "" || diffProtocolVersionFlag > 0) {
+			return errors.WrapValidationError("--wasm and --protocol-version only apply when re-simulating a single session")
+		}
+		if diffWasmFlag != "" {
+			if _, err := os.Stat(diffWasmFlag); os.IsNotExist(err) {
+				return errors.WrapValidationError(fmt.Sprintf("WASM file not found: %s", diffWasmFlag))
+			}
+		}
+		if diffProtocolVersionFlag > 0 {
+			if err := simulator.Validate(diffProtocolVersionFlag); err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("invalid protocol version %d: %v", diffProtocolVersionFlag, err))
+			}
+		}
+		return nil
+	},
+	RunE: runDiff,
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	store, err := session.NewStore()
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+	}
+	defer store.Close()
+
+	left, err := findSession(ctx, store, args[0])
+	if err != nil {
+		return err
+	}
+	leftResp, err := left.ToSimulationResponse()
+	if err != nil {
+		return errors.WrapUnmarshalFailed(err, "session "+left.ID)
+	}
+
+	var rightResp *simulator.SimulationResponse
+	labels := compare.Labels{Left: "A", Right: "B"}
+	if len(args) == 2 {
+		right, err := findSession(ctx, store, args[1])
+		if err != nil {
+			return err
+		}
+		if rightResp, err = right.ToSimulationResponse(); err != nil {
+			return errors.WrapUnmarshalFailed(err, "session "+right.ID)
+		}
+		labels.Title = fmt.Sprintf("DIFF  ─  A: %s  vs  B: %s", left.ID, right.ID)
+	} else {
+		if rightResp, err = resimulateSession(ctx, left); err != nil {
+			return err
+		}
+		labels = compare.Labels{
+			Title: fmt.Sprintf("DIFF  ─  Recorded %s  vs  Re-simulation", left.ID),
+			Left:  "Recorded",
+			Right: "Replay",
+		}
+	}
+
+	if diffFlamegraphFlag != "" {
+		if err := writeFlamegraphDiff(diffFlamegraphFlag, leftResp, rightResp); err != nil {
+			return err
+		}
+		statusf("Differential folded stacks written to %s\n", diffFlamegraphFlag)
+	}
+	if !sameEntryPoint(leftResp, rightResp) {
+		statusf("Warning: the two runs start in different contract functions, so their costs may not be comparable\n")
+	}
+
+	result := compare.Diff(leftResp, rightResp)
+	if jsonOutput() {
+		return printJSON(result)
+	}
+	compare.RenderWithLabels(result, labels)
+	return nil
+}
+
+// resimulateSession re-runs a session from its stored state with the diff
+// command's overrides applied
+func resimulateSession(ctx context.Context, data *session.SessionData) (*simulator.SimulationResponse, error) {
+	simReq, err := buildReplayRequest(data)
+	if err != nil {
+		return nil, err
+	}
+	if diffWasmFlag != "" {
+		simReq.WasmPath = &diffWasmFlag
+	}
+	if diffProtocolVersionFlag > 0 {
+		simReq.ProtocolVersion = &diffProtocolVersionFlag
+	}
+	if diffFlamegraphFlag != "" {
+		simReq.Profile = true
+	}
+
+	runner, err := simulator.NewRunner("", false)
+	if err != nil {
+		return nil, errors.WrapSimulatorNotFound(err.Error())
+	}
+
+	statusf("Re-simulating session %s (%s)\n", data.ID, data.TxHash)
+	simResp, err := simulator.RunWithContext(ctx, runner, simReq)
+	if err != nil {
+		return nil, errors.WrapSimulationFailed(err, "")
+	}
+	return simResp, nil
+}
+
+// writeFlamegraphDiff saves the folded stacks of both runs in the
+// two-column differential format, with right as the baseline
+func writeFlamegraphDiff(path string, left, right *simulator.SimulationResponse) error {
+	if left.FoldedStacks == "" || right.FoldedStacks == "" {
+		return errors.WrapValidationError("--flamegraph-diff needs profiled runs: debug both transactions with --profile")
+	}
+	after, err := profile.ParseFolded(strings.NewReader(left.FoldedStacks))
+	if err != nil {
+		return errors.WrapUnmarshalFailed(err, "folded stacks")
+	}
+	before, err := profile.ParseFolded(strings.NewReader(right.FoldedStacks))
+	if err != nil {
+		return errors.WrapUnmarshalFailed(err, "folded stacks")
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create flamegraph diff file: %w", err)
+	}
+	defer f.Close()
+	if err := profile.WriteDiffFolded(f, before, after); err != nil {
+		return fmt.Errorf("failed to write flamegraph diff: %w", err)
+	}
+	return nil
+}
+
+// sameEntryPoint reports whether both runs start with the same contract
+// function, or whether either lacks a call tree to tell
+func sameEntryPoint(left, right *simulator.SimulationResponse) bool {
+	if len(left.CallTree) == 0 || len(right.CallTree) == 0 {
+		return true
+	}
+	a, b := left.CallTree[0], right.CallTree[0]
+	return a.Contract == b.Contract && a.Function == b.Function
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffWasmFlag, "wasm", "", "Local WASM to use when re-simulating a single session")
+	diffCmd.Flags().Uint32Var(&diffProtocolVersionFlag, "protocol-version", 0, "Override protocol version when re-simulating a single session")
+	diffCmd.Flags().StringVar(&diffFlamegraphFlag, "flamegraph-diff", "", "Write differential folded stacks of the two runs to this file")
+
+	rootCmd.AddCommand(diffCmd)
+}
@@ -46,7 +46,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	verbose, _ := cmd.Flags().GetBool("verbose")
 
 	fmt.Println("Erst Environment Diagnostics")
-	fmt.Println("=============================\n")
+	fmt.Print("=============================\n\n")
 
 	dependencies := []DependencyStatus{
 		checkGo(verbose),
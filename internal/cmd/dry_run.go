@@ -52,8 +52,9 @@ Example:
 }
 
 func init() {
-	dryRunCmd.Flags().StringVarP(&dryRunNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet)")
-	dryRunCmd.Flags().StringVar(&dryRunRPCURLFlag, "rpc-url", "", "Custom Horizon RPC URL to use")
+	dryRunCmd.Flags().StringVarP(&dryRunNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet, local)")
+	dryRunCmd.Flags().StringVar(&dryRunRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(dryRunCmd, "rpc-url")
 	dryRunCmd.Flags().StringVar(&dryRunRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
 
 	rootCmd.AddCommand(dryRunCmd)
@@ -85,9 +86,7 @@ func runDryRun(cmd *cobra.Command, args []string) error {
 		rpc.WithNetwork(rpc.Network(dryRunNetworkFlag)),
 		rpc.WithToken(dryRunRPCTokenFlag),
 	}
-	if dryRunRPCURLFlag != "" {
-		opts = append(opts, rpc.WithHorizonURL(dryRunRPCURLFlag))
-	}
+	opts = append(opts, rpcEndpointOptions(dryRunRPCURLFlag, dryRunNetworkFlag)...)
 
 	client, err := rpc.NewClient(opts...)
 	if err != nil {
@@ -138,7 +137,7 @@ func runDryRun(cmd *cobra.Command, args []string) error {
 		LedgerEntries: ledgerEntries,
 	}
 
-	resp, err := runner.Run(simReq)
+	resp, err := simulator.RunWithContext(ctx, runner, simReq)
 	if err != nil {
 		return errors.WrapSimulationFailed(err, "")
 	}
@@ -0,0 +1,222 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+)
+
+// rpcHeaderFlags holds the repeatable --rpc-header "Name: value" flag
+var rpcHeaderFlags []string
+
+// rpcRateLimitFlag and rpcBurstFlag hold --rpc-rate-limit and --rpc-burst
+var (
+	rpcRateLimitFlag float64
+	rpcBurstFlag     int
+)
+
+// proxyFlag, rpcTimeoutFlag and rpcRetriesFlag hold --proxy, --rpc-timeout
+// and --rpc-retries
+var (
+	proxyFlag      string
+	rpcTimeoutFlag time.Duration
+	rpcRetriesFlag int
+)
+
+// networkPassphraseFlag holds --network-passphrase, which replaces the
+// passphrase of whichever network is selected
+var networkPassphraseFlag string
+
+// rpcLimiter is shared by every client of the process so that commands using
+// several clients, such as debug --compare-network, share one rate limit
+var (
+	rpcLimiterOnce sync.Once
+	rpcLimiter     *rpc.RateLimiter
+	rpcLimiterErr  error
+)
+
+// rpcRateLimiter returns the limiter configured by --rpc-rate-limit and
+// --rpc-burst, which applyConfigDefaults fills from rpc_rate_limit and
+// rpc_rate_burst. It is nil when no limit is set.
+func rpcRateLimiter() (*rpc.RateLimiter, error) {
+	rpcLimiterOnce.Do(func() {
+		if rpcRateLimitFlag != 0 {
+			rpcLimiter, rpcLimiterErr = rpc.NewRateLimiter(rpcRateLimitFlag, rpcBurstFlag)
+		}
+	})
+	return rpcLimiter, rpcLimiterErr
+}
+
+// rpcConcurrencyFlag holds --rpc-concurrency, which overrides the
+// per-network rpc_concurrency caps from config
+var rpcConcurrencyFlag int
+
+// rpcConcurrency holds one limiter per network, shared by the clients of that
+// network so that batch and watch workers together stay under its cap
+var (
+	rpcConcurrencyMu sync.Mutex
+	rpcConcurrency   = map[string]*rpc.ConcurrencyLimiter{}
+)
+
+// rpcConcurrencyLimiter returns the limiter of network, or nil when requests
+// to it are not capped
+func rpcConcurrencyLimiter(cfg *config.Config, network string) *rpc.ConcurrencyLimiter {
+	max := rpcConcurrencyFlag
+	if max == 0 && cfg != nil {
+		max = cfg.RPCConcurrencyFor(network)
+	}
+	if max <= 0 {
+		return nil
+	}
+
+	rpcConcurrencyMu.Lock()
+	defer rpcConcurrencyMu.Unlock()
+	limiter, ok := rpcConcurrency[network]
+	if !ok {
+		// max is positive, so this cannot fail
+		limiter, _ = rpc.NewConcurrencyLimiter(max)
+		rpcConcurrency[network] = limiter
+	}
+	return limiter
+}
+
+// validateRPCFlags rejects an invalid rate limit, proxy or concurrency cap
+// before a command
+// starts, rather than when it first creates a client
+func validateRPCFlags() error {
+	if _, err := rpcRateLimiter(); err != nil {
+		return err
+	}
+	if _, err := rpc.NewProxyFunc(proxyFlag); err != nil {
+		return err
+	}
+	if rpcConcurrencyFlag < 0 {
+		return errors.WrapValidationError("--rpc-concurrency cannot be negative")
+	}
+	if rpcRetriesFlag < 0 {
+		return errors.WrapValidationError("--rpc-retries cannot be negative")
+	}
+	return nil
+}
+
+// rpcEndpoints resolves the failover endpoints for network. A comma-separated
+// --rpc-url value wins, then rpc_urls.<network> from config, then rpc_urls.
+func rpcEndpoints(flagValue, network string) []string {
+	if urls := splitTrimmed(flagValue); len(urls) > 0 {
+		return urls
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	return cfg.RPCURLsFor(network)
+}
+
+// rpcEndpointOptions returns the client options for the endpoints of network
+// together with rpcSharedOptions
+func rpcEndpointOptions(flagValue, network string) []rpc.ClientOption {
+	var opts []rpc.ClientOption
+	if urls := rpcEndpoints(flagValue, network); len(urls) > 0 {
+		opts = append(opts, rpc.WithAltURLs(urls))
+	}
+	return append(opts, rpcSharedOptions(network)...)
+}
+
+// resolveRPCToken returns the RPC token for network: the flag value, then
+// ERST_RPC_TOKEN, then rpc_token from the network's profile or the global
+// config
+func resolveRPCToken(flagValue, network string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if token := os.Getenv("ERST_RPC_TOKEN"); token != "" {
+		return token
+	}
+	if cfg, err := config.Load(); err == nil {
+		return cfg.RPCTokenFor(network)
+	}
+	return ""
+}
+
+// localPassphrase is the passphrase of --network local: local_passphrase
+// from config, or the quickstart default
+func localPassphrase() string {
+	if cfg, err := config.Load(); err == nil && cfg.LocalPassphrase != "" {
+		return cfg.LocalPassphrase
+	}
+	return rpc.LocalPassphrase
+}
+
+// resolveContract expands a contract alias from config for network
+func resolveContract(network, ref string) string {
+	cfg, err := config.Load()
+	if err != nil {
+		return ref
+	}
+	return cfg.ResolveContract(network, ref)
+}
+
+// rpcSharedOptions returns the client options that apply whichever endpoints
+// of network are used: persisted endpoint health, archive URLs for pruned
+// transactions, custom request headers, the rate limit, the concurrency cap,
+// the proxy, the request timeout and the network passphrase when it is not the
+// named network's
+func rpcSharedOptions(network string) []rpc.ClientOption {
+	var opts []rpc.ClientOption
+	if path, err := rpc.DefaultEndpointStatePath(); err == nil {
+		opts = append(opts, rpc.WithEndpointStateFile(path))
+	} else {
+		logger.Logger.Debug("Endpoint health will not be remembered", "error", err)
+	}
+	// Invalid limits are rejected by validateRPCFlags
+	if limiter, _ := rpcRateLimiter(); limiter != nil {
+		opts = append(opts, rpc.WithRateLimiter(limiter))
+	}
+	if proxyFlag != "" {
+		opts = append(opts, rpc.WithProxy(proxyFlag))
+	}
+	if rpcTimeoutFlag != 0 {
+		opts = append(opts, rpc.WithRequestTimeout(rpcTimeoutFlag))
+	}
+	retry := rpc.DefaultRetryConfig()
+	retry.MaxRetries = rpcRetriesFlag
+	opts = append(opts, rpc.WithRetryConfig(retry))
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = nil
+	}
+	if limiter := rpcConcurrencyLimiter(cfg, network); limiter != nil {
+		opts = append(opts, rpc.WithConcurrencyLimiter(limiter))
+	}
+	if cfg != nil {
+		if urls := cfg.ArchiveURLsFor(network); len(urls) > 0 {
+			opts = append(opts, rpc.WithArchiveURLs(urls))
+		}
+		if network == string(rpc.Local) && cfg.LocalPassphrase != "" {
+			opts = append(opts, rpc.WithNetworkPassphrase(cfg.LocalPassphrase))
+		}
+		if headers := cfg.RPCHeadersFor(network); len(headers) > 0 {
+			opts = append(opts, rpc.WithHeaders(headers))
+		}
+		if cfg.LedgerBatchSize > 0 || cfg.LedgerBatchWorkers > 0 {
+			opts = append(opts, rpc.WithLedgerEntriesBatching(cfg.LedgerBatchSize, cfg.LedgerBatchWorkers))
+		}
+	}
+	// --network-passphrase overrides local_passphrase
+	if networkPassphraseFlag != "" {
+		opts = append(opts, rpc.WithNetworkPassphrase(networkPassphraseFlag))
+	}
+	// --rpc-header is applied last so it overrides headers from config
+	if len(rpcHeaderFlags) > 0 {
+		opts = append(opts, rpc.WithHeaderLines(rpcHeaderFlags))
+	}
+	return opts
+}
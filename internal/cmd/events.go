@@ -0,0 +1,240 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/dotandev/hintents/internal/watch"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/strkey"
+)
+
+var (
+	eventsContractFlags   []string
+	eventsNetworkFlag     string
+	eventsRPCURLFlag      string
+	eventsRPCTokenFlag    string
+	eventsFollowFlag      bool
+	eventsStartLedgerFlag uint32
+	eventsEndLedgerFlag   uint32
+	eventsLimitFlag       int
+	eventsTopicFlags      []string
+	eventsTypeFlag        string
+	eventsIntervalFlag    time.Duration
+)
+
+// EventOutput is one contract event as printed by erst events, with its
+// topics and data decoded
+type EventOutput struct {
+	ID         string   `json:"id"`
+	Ledger     uint32   `json:"ledger"`
+	ClosedAt   string   `json:"ledger_closed_at,omitempty"`
+	TxHash     string   `json:"tx_hash"`
+	Type       string   `json:"type"`
+	ContractID string   `json:"contract_id,omitempty"`
+	Topics     []string `json:"topics"`
+	Data       string   `json:"data"`
+	// Summary describes Stellar Asset Contract events in token terms
+	Summary string `json:"summary,omitempty"`
+}
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "List or tail the events emitted by a contract",
+	Long: `Fetch the events a contract emitted through Soroban RPC getEvents and print
+them with decoded topics and data, the same way erst debug shows events.
+
+Without --follow, events from --start-ledger (by default the oldest ledger the
+RPC node retains) up to the latest ledger are printed and the command exits.
+With --follow, it starts at the latest ledger unless --start-ledger is given
+and keeps polling for new events until interrupted. --end-ledger and --limit
+bound a listing.
+
+--topic filters events by their topics with a comma-separated expression, one
+segment per topic: "*" matches any topic, a trailing "**" any number of them,
+an address or bare name matches that address or symbol, and str:, u32:, i32:,
+u64:, i64:, u128:, i128:, bool: and xdr: give a typed value. Several --topic
+flags match events satisfying any of them.
+
+In JSON mode each event is printed as its own JSON document.`,
+	Example: `  # Tail a contract's events live
+  erst events --contract CABC...XYZ --network testnet --follow
+
+  # Print everything emitted since ledger 123456
+  erst events --contract CABC...XYZ --start-ledger 123456
+
+  # Follow two contracts at once
+  erst events --contract CABC...XYZ --contract CDEF...UVW --follow
+
+  # Transfers out of one account, at most 50
+  erst events --contract CABC...XYZ --topic 'transfer,GABC...,*,**' --limit 50`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(eventsContractFlags) == 0 && len(eventsTopicFlags) == 0 {
+			return errors.WrapCliArgumentRequired("contract")
+		}
+		if eventsFollowFlag && (eventsEndLedgerFlag != 0 || eventsLimitFlag != 0) {
+			return errors.WrapValidationError("--end-ledger and --limit cannot be used with --follow")
+		}
+		switch rpc.Network(eventsNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+			return nil
+		default:
+			return errors.WrapInvalidNetwork(eventsNetworkFlag)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		contractIDs := make([]string, 0, len(eventsContractFlags))
+		for _, ref := range eventsContractFlags {
+			id, err := rpc.ParseContractID(resolveContract(eventsNetworkFlag, ref))
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("invalid contract id %q: %v", ref, err))
+			}
+			encoded, err := strkey.Encode(strkey.VersionByteContract, id[:])
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("invalid contract id %q: %v", ref, err))
+			}
+			contractIDs = append(contractIDs, encoded)
+		}
+		filter := rpc.EventFilter{Type: eventsTypeFlag, ContractIDs: contractIDs}
+		for _, expr := range eventsTopicFlags {
+			topic, err := rpc.ParseTopicFilter(expr)
+			if err != nil {
+				return err
+			}
+			filter.Topics = append(filter.Topics, topic)
+		}
+		filters, err := rpc.SplitEventFilter(filter)
+		if err != nil {
+			return err
+		}
+		if err := rpc.ValidateEventFilters(filters); err != nil {
+			return err
+		}
+
+		opts := []rpc.ClientOption{
+			rpc.WithNetwork(rpc.Network(eventsNetworkFlag)),
+			rpc.WithToken(resolveRPCToken(eventsRPCTokenFlag, eventsNetworkFlag)),
+		}
+		opts = append(opts, rpcEndpointOptions(eventsRPCURLFlag, eventsNetworkFlag)...)
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+		}
+
+		startLedger := eventsStartLedgerFlag
+		if startLedger == 0 {
+			health, err := client.GetHealth(ctx)
+			if err != nil {
+				return errors.WrapRPCConnectionFailed(err)
+			}
+			startLedger = health.Result.OldestLedger
+			if eventsFollowFlag {
+				startLedger = health.Result.LatestLedger
+			}
+		}
+
+		if eventsFollowFlag {
+			source := "all contracts"
+			if len(contractIDs) > 0 {
+				source = strings.Join(contractIDs, ", ")
+			}
+			statusf("%s Following events of %s on %s from ledger %d (Ctrl+C to stop)\n",
+				visualizer.Symbol("magnify"), source, eventsNetworkFlag, startLedger)
+		}
+
+		count := 0
+		onEvent := func(event rpc.ContractEvent) error {
+			count++
+			out := newEventOutput(ctx, eventsNetworkFlag, event)
+			if jsonOutput() {
+				return printJSON(out)
+			}
+			printContractEvent(out)
+			return nil
+		}
+
+		if !eventsFollowFlag {
+			_, err = rpc.PageEvents(ctx, client, rpc.EventQuery{
+				StartLedger: startLedger,
+				EndLedger:   eventsEndLedgerFlag,
+				Filters:     filters,
+				Limit:       eventsLimitFlag,
+			}, onEvent)
+			if err != nil {
+				return err
+			}
+			statusf("%d events\n", count)
+			return nil
+		}
+
+		follower := watch.NewEventFollower(client, watch.EventFollowerConfig{
+			Filters:      filters,
+			StartLedger:  startLedger,
+			PollInterval: eventsIntervalFlag,
+			Follow:       true,
+		})
+		return follower.Run(ctx, onEvent)
+	},
+}
+
+func newEventOutput(ctx context.Context, network string, event rpc.ContractEvent) EventOutput {
+	return EventOutput{
+		ID:         event.ID,
+		Ledger:     event.Ledger,
+		ClosedAt:   event.LedgerClosedAt,
+		TxHash:     event.TxHash,
+		Type:       event.Type,
+		ContractID: event.ContractID,
+		Topics:     decoder.RenderScVals(event.Topic, event.Topic),
+		Data:       decoder.RenderScVal(event.Value, event.Value),
+		Summary:    eventSummary(ctx, network, event.ContractID, event.Topic, event.Value),
+	}
+}
+
+func printContractEvent(event EventOutput) {
+	fmt.Printf("[ledger %d] Type: %s", event.Ledger, event.Type)
+	if event.ContractID != "" {
+		fmt.Printf(", Contract: %s", labelAddress(event.ContractID))
+	}
+	fmt.Printf("\n")
+	fmt.Printf("    Tx:     %s\n", event.TxHash)
+	if event.Summary != "" {
+		fmt.Printf("    %s\n", event.Summary)
+	}
+	if len(event.Topics) > 0 {
+		fmt.Printf("    Topics: [%s]\n", strings.Join(event.Topics, ", "))
+	}
+	if event.Data != "" {
+		fmt.Printf("    Data:   %s\n", event.Data)
+	}
+}
+
+func init() {
+	eventsCmd.Flags().StringArrayVar(&eventsContractFlags, "contract", nil, "Contract ID (C... or hex) or alias whose events to show (repeatable)")
+	_ = eventsCmd.RegisterFlagCompletionFunc("contract", completeContractIDs)
+	eventsCmd.Flags().StringVarP(&eventsNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	eventsCmd.Flags().StringVar(&eventsRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(eventsCmd, "rpc-url")
+	eventsCmd.Flags().StringVar(&eventsRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	eventsCmd.Flags().BoolVarP(&eventsFollowFlag, "follow", "f", false, "Keep polling for new events until interrupted")
+	eventsCmd.Flags().Uint32Var(&eventsStartLedgerFlag, "start-ledger", 0, "Ledger to start from (default: oldest retained, or latest with --follow)")
+	eventsCmd.Flags().Uint32Var(&eventsEndLedgerFlag, "end-ledger", 0, "Stop before the events of this ledger (without --follow)")
+	eventsCmd.Flags().IntVar(&eventsLimitFlag, "limit", 0, "Print at most this many events (without --follow)")
+	eventsCmd.Flags().StringArrayVar(&eventsTopicFlags, "topic", nil, "Topic filter expression, e.g. 'transfer,*,GABC...' (repeatable)")
+	eventsCmd.Flags().StringVar(&eventsTypeFlag, "type", "contract", "Event type: contract, system or diagnostic")
+	eventsCmd.Flags().DurationVar(&eventsIntervalFlag, "interval", 5*time.Second, "Polling interval once caught up with the ledger")
+
+	rootCmd.AddCommand(eventsCmd)
+}
@@ -0,0 +1,35 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEventOutput(t *testing.T) {
+	sym := xdr.ScSymbol("transfer")
+	topic, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym})
+	require.NoError(t, err)
+	amount := xdr.Uint32(100)
+	value, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &amount})
+	require.NoError(t, err)
+
+	out := newEventOutput(context.Background(), "testnet", rpc.ContractEvent{
+		ID:     "0000000001-0000000001",
+		Ledger: 42,
+		TxHash: "abc",
+		Type:   "contract",
+		Topic:  []string{topic, "not-xdr"},
+		Value:  value,
+	})
+	assert.Equal(t, []string{"transfer", "not-xdr"}, out.Topics)
+	assert.Equal(t, "100", out.Data)
+	assert.Equal(t, uint32(42), out.Ledger)
+}
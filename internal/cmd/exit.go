@@ -0,0 +1,88 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// Process exit codes. With --check they tell a transaction that fails apart
+// from a failure to find out whether it does.
+const (
+	ExitOK = 0
+	// ExitError covers invalid usage and every error not listed below
+	ExitError = 1
+	// ExitCheckFailed means the transaction checked with --check failed
+	ExitCheckFailed = 2
+	// ExitRPCError means the RPC node could not be reached or did not have
+	// the transaction or ledger state
+	ExitRPCError = 3
+	// ExitSimulatorError means the simulator is missing or crashed
+	ExitSimulatorError = 4
+	// ExitInterrupted means the command was stopped with Ctrl-C, following
+	// the shell convention of 128 + SIGINT
+	ExitInterrupted = 130
+)
+
+var rpcErrors = []error{
+	errors.ErrRPCConnectionFailed,
+	errors.ErrRPCTimeout,
+	errors.ErrAllRPCFailed,
+	errors.ErrRPCError,
+	errors.ErrRPCResponseTooLarge,
+	errors.ErrRateLimitExceeded,
+	errors.ErrUnauthorized,
+	errors.ErrTransactionNotFound,
+	errors.ErrLedgerNotFound,
+	errors.ErrLedgerArchived,
+}
+
+var simulatorErrors = []error{
+	errors.ErrSimulatorNotFound,
+	errors.ErrSimCrash,
+	errors.ErrSimulationFailed,
+}
+
+// ExitCode is the process exit code for the error a command returned
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	if errors.Is(err, errors.ErrCheckFailed) {
+		return ExitCheckFailed
+	}
+	if errors.Is(err, context.Canceled) {
+		return ExitInterrupted
+	}
+	for _, target := range rpcErrors {
+		if errors.Is(err, target) {
+			return ExitRPCError
+		}
+	}
+	for _, target := range simulatorErrors {
+		if errors.Is(err, target) {
+			return ExitSimulatorError
+		}
+	}
+	return ExitError
+}
+
+// checkSimulations implements --check for simulated transactions: it fails
+// when any of results is an error
+func checkSimulations(results ...*simulator.SimulationResponse) error {
+	for _, res := range results {
+		if res == nil || res.Status != "error" {
+			continue
+		}
+		if res.Error == "" {
+			return errors.WrapCheckFailed("simulation failed")
+		}
+		return errors.WrapCheckFailed(fmt.Sprintf("simulation failed: %s", res.Error))
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitOK},
+		{errors.WrapValidationError("bad flag"), ExitError},
+		{errors.WrapCheckFailed("simulation failed"), ExitCheckFailed},
+		{errors.WrapRPCConnectionFailed(fmt.Errorf("dial tcp")), ExitRPCError},
+		{errors.WrapTransactionNotFound(fmt.Errorf("404")), ExitRPCError},
+		{errors.WrapRateLimitExceeded(), ExitRPCError},
+		{errors.WrapSimulatorNotFound("erst-sim"), ExitSimulatorError},
+		{&afterOutputError{err: errors.WrapSimCrash(fmt.Errorf("signal"), "")}, ExitSimulatorError},
+		{errors.WrapSimCanceled(context.Canceled), ExitInterrupted},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCheckSimulations(t *testing.T) {
+	ok := &simulator.SimulationResponse{Status: "success"}
+	failed := &simulator.SimulationResponse{Status: "error", Error: "HostError: Error(Contract, #3)"}
+
+	if err := checkSimulations(ok, nil); err != nil {
+		t.Errorf("expected a successful simulation to pass, got %v", err)
+	}
+	err := checkSimulations(ok, failed)
+	if ExitCode(err) != ExitCheckFailed {
+		t.Fatalf("expected a check failure, got %v", err)
+	}
+}
+
+func TestCheckBatch(t *testing.T) {
+	failed := summarizeBatch([]BatchResult{
+		{TxHash: "a", Status: "success"},
+		{TxHash: "b", Status: "error", Error: "HostError"},
+	})
+	if got := ExitCode(checkBatch(failed)); got != ExitCheckFailed {
+		t.Errorf("failed transaction: exit code %d, want %d", got, ExitCheckFailed)
+	}
+
+	incomplete := summarizeBatch([]BatchResult{
+		{TxHash: "b", Status: "error", Error: "HostError"},
+		{TxHash: "c", Status: "failed", err: errors.WrapRPCTimeout(fmt.Errorf("deadline"))},
+	})
+	if got := ExitCode(checkBatch(incomplete)); got != ExitRPCError {
+		t.Errorf("unreachable RPC: exit code %d, want %d", got, ExitRPCError)
+	}
+
+	if err := checkBatch(summarizeBatch([]BatchResult{{TxHash: "a", Status: "success"}})); err != nil {
+		t.Errorf("expected a passing batch, got %v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 
 	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/decoder"
 	"github.com/dotandev/hintents/internal/heuristic"
 	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/simulator"
@@ -43,7 +44,7 @@ Examples:
 			return fmt.Errorf("invalid transaction hash: %w", err)
 		}
 		switch rpc.Network(explainNetworkFlag) {
-		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
 		default:
 			return fmt.Errorf("invalid network: %s; must be testnet, mainnet, or futurenet", explainNetworkFlag)
 		}
@@ -70,17 +71,9 @@ func explainFromSession() error {
 		}
 	}
 
-	in := heuristic.Input{
-		TxHash:           sess.TxHash,
-		Network:          sess.Network,
-		Status:           simResp.Status,
-		Error:            simResp.Error,
-		Events:           simResp.Events,
-		Logs:             simResp.Logs,
-		DiagnosticEvents: simResp.DiagnosticEvents,
-		BudgetUsage:      simResp.BudgetUsage,
-	}
+	in := heuristicInput(sess.TxHash, sess.Network, &simResp)
 	fmt.Println(heuristic.Summarize(in))
+	fmt.Print(decoder.FormatSuggestions(heuristic.Suggestions(in, decodedCallTree(&simResp))))
 	return nil
 }
 
@@ -99,9 +92,7 @@ func explainFromNetwork(cmd *cobra.Command, txHash string) error {
 		rpc.WithNetwork(rpc.Network(explainNetworkFlag)),
 		rpc.WithToken(token),
 	}
-	if explainRPCURLFlag != "" {
-		opts = append(opts, rpc.WithHorizonURL(explainRPCURLFlag))
-	}
+	opts = append(opts, rpcEndpointOptions(explainRPCURLFlag, explainNetworkFlag)...)
 
 	client, err := rpc.NewClient(opts...)
 	if err != nil {
@@ -131,7 +122,7 @@ func explainFromNetwork(cmd *cobra.Command, txHash string) error {
 		return fmt.Errorf("failed to initialize simulator: %w", err)
 	}
 
-	simResp, err := runner.Run(&simulator.SimulationRequest{
+	simResp, err := simulator.RunWithContext(cmd.Context(), runner, &simulator.SimulationRequest{
 		EnvelopeXdr:   resp.EnvelopeXdr,
 		ResultMetaXdr: resp.ResultMetaXdr,
 		LedgerEntries: ledgerEntries,
@@ -140,23 +131,45 @@ func explainFromNetwork(cmd *cobra.Command, txHash string) error {
 		return fmt.Errorf("simulation failed: %w", err)
 	}
 
-	in := heuristic.Input{
-		TxHash:           txHash,
-		Network:          explainNetworkFlag,
-		Status:           simResp.Status,
-		Error:            simResp.Error,
-		Events:           simResp.Events,
-		Logs:             simResp.Logs,
-		DiagnosticEvents: simResp.DiagnosticEvents,
-		BudgetUsage:      simResp.BudgetUsage,
-	}
+	in := heuristicInput(txHash, explainNetworkFlag, simResp)
 	fmt.Println(heuristic.Summarize(in))
+	fmt.Print(decoder.FormatSuggestions(heuristic.Suggestions(in, decodedCallTree(simResp))))
 	return nil
 }
 
+// heuristicInput collects the signals of a simulation for the heuristic
+// summary and the suggestion rules
+func heuristicInput(txHash, network string, resp *simulator.SimulationResponse) heuristic.Input {
+	return heuristic.Input{
+		TxHash:           txHash,
+		Network:          network,
+		Status:           resp.Status,
+		Error:            resp.Error,
+		Events:           resp.Events,
+		Logs:             resp.Logs,
+		DiagnosticEvents: resp.DiagnosticEvents,
+		BudgetUsage:      resp.BudgetUsage,
+		Archival:         resp.Archival,
+	}
+}
+
+// decodedCallTree returns the call tree of the simulation's events, or nil
+// when there are none or they cannot be decoded
+func decodedCallTree(resp *simulator.SimulationResponse) *decoder.CallNode {
+	if len(resp.Events) == 0 {
+		return nil
+	}
+	callTree, err := decoder.DecodeEvents(resp.Events)
+	if err != nil {
+		return nil
+	}
+	return callTree
+}
+
 func init() {
-	explainCmd.Flags().StringVarP(&explainNetworkFlag, "network", "n", "mainnet", "Stellar network (testnet, mainnet, futurenet)")
-	explainCmd.Flags().StringVar(&explainRPCURLFlag, "rpc-url", "", "Custom RPC URL")
+	explainCmd.Flags().StringVarP(&explainNetworkFlag, "network", "n", "mainnet", "Stellar network (testnet, mainnet, futurenet, local)")
+	explainCmd.Flags().StringVar(&explainRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(explainCmd, "rpc-url")
 	explainCmd.Flags().StringVar(&explainRPCToken, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
 	rootCmd.AddCommand(explainCmd)
 }
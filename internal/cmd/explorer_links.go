@@ -0,0 +1,77 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/explorer"
+	"github.com/dotandev/hintents/internal/resubmit"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// explorerFlag holds --explorer
+var explorerFlag string
+
+// explorerName returns the block explorer from --explorer, or else the
+// explorer setting
+func explorerName() (string, error) {
+	if explorerFlag != "" {
+		if err := explorer.Validate(explorerFlag); err != nil {
+			return "", errors.WrapValidationError(err.Error())
+		}
+		return explorerFlag, nil
+	}
+	if cfg, err := config.Load(); err == nil {
+		return cfg.Explorer, nil
+	}
+	return "", nil
+}
+
+// debugExplorerLinks links to the transaction, its source account and every
+// contract it called, directly or from within another contract
+func debugExplorerLinks(name, network, txHash, envelopeXdr string, invocations []contractspec.Invocation, sim *simulator.SimulationResponse) *explorer.Links {
+	var source string
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err == nil {
+		if account, err := resubmit.SourceAccount(&envelope); err == nil {
+			source = account.Address()
+		}
+	}
+
+	var contracts []string
+	for _, inv := range invocations {
+		contracts = append(contracts, inv.ContractID)
+	}
+	if sim != nil {
+		contracts = append(contracts, collectContractIDsFromDiagnosticEvents(sim.DiagnosticEvents)...)
+	}
+	return explorer.Build(name, network, txHash, source, contracts)
+}
+
+func printExplorerLinks(links *explorer.Links) {
+	if links == nil {
+		return
+	}
+	fmt.Printf("\nExplorer Links:\n")
+	if links.Transaction != "" {
+		fmt.Printf("  Transaction:    %s\n", links.Transaction)
+	}
+	if links.SourceAccount != "" {
+		fmt.Printf("  Source account: %s\n", links.SourceAccount)
+	}
+	ids := make([]string, 0, len(links.Contracts))
+	for id := range links.Contracts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Printf("  Contract:       %s\n", links.Contracts[id])
+	}
+}
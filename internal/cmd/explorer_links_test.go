@@ -0,0 +1,41 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/explorer"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugExplorerLinks(t *testing.T) {
+	source := keypair.MustRandom().Address()
+	var contract xdr.ContractId
+	contract[0] = 1
+	contractID, err := strkey.Encode(strkey.VersionByteContract, contract[:])
+	require.NoError(t, err)
+	callee := "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+
+	envelope := invokeEnvelope(xdr.MustMuxedAddress(source), 2, 100,
+		xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contract}, "swap", nil)
+	envelopeXdr, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+
+	sim := &simulator.SimulationResponse{DiagnosticEvents: []simulator.DiagnosticEvent{{ContractID: &callee}}}
+	links := debugExplorerLinks("", "testnet", "abc", envelopeXdr, []contractspec.Invocation{{ContractID: contractID}}, sim)
+	require.NotNil(t, links)
+	assert.Equal(t, "https://stellar.expert/explorer/testnet/tx/abc", links.Transaction)
+	assert.Equal(t, "https://stellar.expert/explorer/testnet/account/"+source, links.SourceAccount)
+	assert.Len(t, links.Contracts, 2)
+	assert.Contains(t, links.Contracts, callee)
+
+	assert.Nil(t, debugExplorerLinks(explorer.None, "testnet", "abc", envelopeXdr, nil, nil))
+}
@@ -4,44 +4,81 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/dotandev/hintents/internal/errors"
-	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/session"
 	"github.com/dotandev/hintents/internal/snapshot"
 	"github.com/spf13/cobra"
 )
 
-var exportSnapshotFlag string
+var (
+	exportSnapshotFlag string
+	exportBundleFlag   string
+)
 
 var exportCmd = &cobra.Command{
-	Use:   "export",
-	Short: "Export data from the current session",
-	Long:  `Export debugging data, such as state snapshots, from the currently active session.`,
+	Use:   "export [session-id]",
+	Short: "Export data from a session",
+	Long: `Export debugging data from the currently active session, or from a saved
+session when a session ID or transaction hash is given.
+
+--snapshot writes the ledger state as a JSON snapshot. -o writes a portable
+bundle holding the envelope, result meta, simulator request and response
+(events, logs, profile) plus tags and notes, which a teammate can load into
+their own history with 'erst import'. Giving a session ID without --snapshot
+writes a bundle named <session-id>.erst.`,
+	Example: `  # Share a saved session
+  erst export abc12345-1700000000 -o bundle.erst
+
+  # Export the ledger state of the active session
+  erst export --snapshot state.json`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if exportSnapshotFlag == "" {
+		if exportSnapshotFlag == "" && exportBundleFlag == "" && len(args) == 0 {
 			return errors.WrapCliArgumentRequired("snapshot")
 		}
 
-		// Get current session
-		data := GetCurrentSession()
-		if data == nil {
+		var data *session.SessionData
+		if len(args) > 0 {
+			store, err := session.NewStore()
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+			}
+			defer store.Close()
+
+			if data, err = findSession(cmd.Context(), store, args[0]); err != nil {
+				return err
+			}
+		} else if data = GetCurrentSession(); data == nil {
 			return errors.WrapSimulationLogicError("no active session. Run 'erst debug <tx-hash>' first")
 		}
 
-		// Unwrap simulation request to get ledger entries
-		var simReq simulator.SimulationRequest
-		if err := json.Unmarshal([]byte(data.SimRequestJSON), &simReq); err != nil {
-			return errors.WrapUnmarshalFailed(err, "session data")
+		if exportBundleFlag != "" || exportSnapshotFlag == "" {
+			path := exportBundleFlag
+			if path == "" {
+				path = data.ID + session.BundleExtension
+			}
+			if err := exportBundle(path, data); err != nil {
+				return err
+			}
+			fmt.Printf("Session %s exported to %s\n", data.ID, path)
+		}
+		if exportSnapshotFlag == "" {
+			return nil
 		}
 
-		if len(simReq.LedgerEntries) == 0 {
+		entries, ledger, err := sessionLedgerState(data)
+		if err != nil {
 			fmt.Println("Warning: No ledger entries found in the current session.")
 		}
 
 		// Convert to snapshot
-		snap := snapshot.FromMap(simReq.LedgerEntries)
+		snap := snapshot.FromMap(entries)
+		snap.Network = data.Network
+		snap.LedgerSequence = ledger
+		snap.TxHash = data.TxHash
 
 		// Save
 		if err := snapshot.Save(exportSnapshotFlag, snap); err != nil {
@@ -53,7 +90,24 @@ var exportCmd = &cobra.Command{
 	},
 }
 
+// exportBundle writes data as a session bundle at path
+func exportBundle(path string, data *session.SessionData) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create bundle: %v", err))
+	}
+	if err := session.ExportBundle(f, data); err != nil {
+		f.Close()
+		return errors.WrapValidationError(fmt.Sprintf("failed to write bundle: %v", err))
+	}
+	if err := f.Close(); err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to write bundle: %v", err))
+	}
+	return nil
+}
+
 func init() {
 	exportCmd.Flags().StringVar(&exportSnapshotFlag, "snapshot", "", "Output file for JSON snapshot")
+	exportCmd.Flags().StringVarP(&exportBundleFlag, "output", "o", "", "Output file for a shareable session bundle")
 	rootCmd.AddCommand(exportCmd)
 }
@@ -0,0 +1,307 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/fees"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	feesNetworkFlag  string
+	feesRPCURLFlag   string
+	feesRPCTokenFlag string
+)
+
+var feesCmd = &cobra.Command{
+	Use:   "fees <tx-hash|envelope-file|->",
+	Short: "Break down a transaction's resource fee against its declared resources",
+	Long: `Simulate a transaction and report, per resource, what it declares and what
+it actually uses: CPU instructions, disk read and write entries and bytes,
+transaction size, and the refundable budget for events and rent. Each is
+priced with the network's current fee settings.
+
+The argument is the hash of an on-chain transaction, or a file holding an
+unsubmitted envelope ("-" for stdin). For on-chain transactions the fee actually
+charged is shown alongside; for envelopes rent is estimated from RPC preflight.
+
+The component responsible for most of an over- or under-estimate is
+highlighted, as is any declared limit the transaction exceeds.`,
+	Example: `  erst fees 5c0a1234... --network testnet
+  erst fees tx.xdr -n testnet --output json`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch rpc.Network(feesNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+			return nil
+		default:
+			return errors.WrapInvalidNetwork(feesNetworkFlag)
+		}
+	},
+	RunE: runFees,
+}
+
+// FeesOutput is the document emitted by 'erst fees --output json'
+type FeesOutput struct {
+	TxHash  string       `json:"tx_hash,omitempty"`
+	Network string       `json:"network"`
+	Status  string       `json:"status"`
+	Config  fees.Config  `json:"fee_config"`
+	Report  *fees.Report `json:"report"`
+}
+
+func runFees(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(feesNetworkFlag)),
+		rpc.WithToken(resolveRPCToken(feesRPCTokenFlag, feesNetworkFlag)),
+	}
+	opts = append(opts, rpcEndpointOptions(feesRPCURLFlag, feesNetworkFlag)...)
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+
+	runner, err := simulator.NewRunner("", false)
+	if err != nil {
+		return errors.WrapSimulatorNotFound(err.Error())
+	}
+
+	sim, err := simulateTxOrEnvelope(ctx, cmd.InOrStdin(), client, runner, args[0])
+	if err != nil {
+		return err
+	}
+	envelopeXdr, simReq, simResp := sim.EnvelopeXdr, sim.Req, sim.Resp
+
+	var (
+		charged     *fees.Charged
+		eventsSize  uint32
+		eventsKnown bool
+	)
+	if sim.Tx != nil {
+		charged, eventsSize, err = fees.FromResultMeta(sim.Tx.ResultMetaXdr)
+		if err != nil {
+			logger.Logger.Warn("Failed to read charged fees from result meta", "error", err)
+		} else {
+			eventsKnown = true
+		}
+	}
+
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+		return errors.WrapUnmarshalFailed(err, "TransactionEnvelope")
+	}
+	sorobanData := rpc.SorobanDataFromEnvelope(envelope)
+	if sorobanData == nil {
+		return errors.WrapValidationError("transaction has no Soroban resources to estimate")
+	}
+
+	cfg, err := fetchFeeConfig(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	envelopeSize := base64.StdEncoding.DecodedLen(len(envelopeXdr))
+	if raw, err := base64.StdEncoding.DecodeString(envelopeXdr); err == nil {
+		envelopeSize = len(raw)
+	}
+	declared, declaredFee := fees.Declared(sorobanData, uint32(envelopeSize))
+
+	if !eventsKnown {
+		eventsSize = contractEventsSize(simResp.DiagnosticEvents)
+	}
+	actual := measuredResources(declared, sorobanData.Resources.Footprint, simReq, simResp, eventsSize)
+
+	var rent int64
+	if charged != nil {
+		rent = charged.Rent
+	} else {
+		rent = preflightRent(ctx, client, envelopeXdr, uint32(envelopeSize), actual, cfg)
+	}
+
+	report := fees.Compare(declared, actual, declaredFee, rent, cfg)
+	report.Charged = charged
+
+	if jsonOutput() {
+		return printJSON(FeesOutput{
+			TxHash:  sim.TxHash,
+			Network: feesNetworkFlag,
+			Status:  simResp.Status,
+			Config:  cfg,
+			Report:  report,
+		})
+	}
+	printFeeReport(report, simResp)
+	return nil
+}
+
+// fetchFeeConfig reads the network's current resource fee settings
+func fetchFeeConfig(ctx context.Context, client *rpc.Client) (fees.Config, error) {
+	keys, err := fees.ConfigKeys()
+	if err != nil {
+		return fees.Config{}, errors.WrapValidationError(err.Error())
+	}
+	entries, err := client.GetLedgerEntries(ctx, keys)
+	if err != nil {
+		return fees.Config{}, errors.WrapRPCConnectionFailed(err)
+	}
+	values := make([]string, 0, len(entries))
+	for _, v := range entries {
+		values = append(values, v)
+	}
+	cfg, err := fees.ParseConfig(values)
+	if err != nil {
+		return fees.Config{}, errors.WrapValidationError(fmt.Sprintf("failed to read network fee settings: %v", err))
+	}
+	return cfg, nil
+}
+
+// measuredResources returns the resources a simulation actually used. Entry
+// counts follow the footprint; byte counts are the XDR sizes of the classic
+// entries read and of the entries written.
+func measuredResources(declared fees.Resources, footprint xdr.LedgerFootprint, simReq *simulator.SimulationRequest, simResp *simulator.SimulationResponse, eventsSize uint32) fees.Resources {
+	actual := declared
+	actual.ContractEventsSize = eventsSize
+	if simResp.BudgetUsage != nil {
+		actual.Instructions = simResp.BudgetUsage.CPUInstructions
+	}
+
+	actual.DiskReadBytes = 0
+	for _, group := range [][]xdr.LedgerKey{footprint.ReadOnly, footprint.ReadWrite} {
+		for _, key := range group {
+			if !fees.IsDiskRead(key) {
+				continue
+			}
+			encoded, err := rpc.EncodeLedgerKey(key)
+			if err != nil {
+				continue
+			}
+			actual.DiskReadBytes += uint32(decodedSize(simReq.LedgerEntries[encoded]))
+		}
+	}
+
+	actual.WriteBytes = 0
+	for _, w := range simResp.StorageWrites {
+		actual.WriteBytes += uint32(decodedSize(w.Entry))
+	}
+	return actual
+}
+
+// contractEventsSize approximates the XDR size of the contract events in a
+// simulation from their encoded topics and data
+func contractEventsSize(events []simulator.DiagnosticEvent) uint32 {
+	// ext, contract ID, type, body version and topic count
+	const header = 4 + 4 + 32 + 4 + 4 + 4
+
+	var size uint32
+	for _, e := range events {
+		if e.EventType != "contract" || !e.InSuccessfulContractCall {
+			continue
+		}
+		size += header + uint32(decodedSize(e.DataXdr))
+		for _, topic := range e.TopicsXdr {
+			size += uint32(decodedSize(topic))
+		}
+	}
+	return size
+}
+
+// preflightRent estimates the rent of an unsubmitted envelope as what RPC
+// preflight's minimum resource fee leaves after the non-refundable fee of its
+// own resources and the events fee. It returns 0 if preflight is unavailable.
+func preflightRent(ctx context.Context, client *rpc.Client, envelopeXdr string, txSize uint32, actual fees.Resources, cfg fees.Config) int64 {
+	preflight, err := client.SimulateTransaction(ctx, envelopeXdr)
+	if err != nil {
+		logger.Logger.Warn("RPC preflight failed; rent is not included", "error", err)
+		return 0
+	}
+	minFee, err := strconv.ParseInt(preflight.Result.MinResourceFee, 10, 64)
+	if err != nil {
+		return 0
+	}
+	var data xdr.SorobanTransactionData
+	if err := xdr.SafeUnmarshalBase64(preflight.Result.TransactionData, &data); err != nil {
+		return 0
+	}
+
+	resources, _ := fees.Declared(&data, txSize)
+	resources.ContractEventsSize = actual.ContractEventsSize
+	fee := fees.Compute(resources, cfg)
+	if rent := minFee - fee.NonRefundable() - fee.Events; rent > 0 {
+		return rent
+	}
+	return 0
+}
+
+func decodedSize(b64 string) int {
+	if b64 == "" {
+		return 0
+	}
+	raw, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return 0
+	}
+	return len(raw)
+}
+
+func printFeeReport(r *fees.Report, simResp *simulator.SimulationResponse) {
+	fmt.Printf("\nSimulation status: %s\n\n", simResp.Status)
+	fmt.Printf("%-18s %14s %14s %14s %14s\n", "Component", "Declared", "Actual", "Declared fee", "Actual fee")
+	for _, c := range r.Components {
+		marker := ""
+		switch {
+		case c.Exceeded():
+			marker = "  EXCEEDS LIMIT"
+		case c.Name == r.Culprit:
+			marker = "  <--"
+		}
+		fmt.Printf("%-18s %14d %14d %14d %14d%s\n", c.Name, c.Declared, c.Actual, c.DeclaredFee, c.ActualFee, marker)
+	}
+
+	fmt.Printf("\nDeclared resource fee: %d stroops\n", r.DeclaredFee)
+	fmt.Printf("Required resource fee: %d stroops (rent %d)\n", r.RequiredFee, r.Rent)
+	if r.Charged != nil {
+		fmt.Printf("Charged on-chain:      %d stroops (non-refundable %d, refundable %d, rent %d)\n",
+			r.Charged.NonRefundable+r.Charged.Refundable, r.Charged.NonRefundable, r.Charged.Refundable, r.Charged.Rent)
+	}
+	fmt.Println()
+
+	if exceeded := r.Exceeded(); len(exceeded) > 0 {
+		names := make([]string, len(exceeded))
+		for i, c := range exceeded {
+			names[i] = fmt.Sprintf("%s (%d > %d %s)", c.Name, c.Actual, c.Declared, c.Unit)
+		}
+		fmt.Printf("Under-declared: %s. The transaction fails whatever fee it offers.\n", strings.Join(names, ", "))
+		return
+	}
+	switch surplus := r.Surplus(); {
+	case surplus > 0:
+		fmt.Printf("Over-estimate of %d stroops, mostly from %s.\n", surplus, r.Culprit)
+	case surplus < 0:
+		fmt.Printf("Under-estimate of %d stroops, mostly from %s.\n", -surplus, r.Culprit)
+	default:
+		fmt.Println("The declared resource fee matches the required fee.")
+	}
+}
+
+func init() {
+	feesCmd.Flags().StringVarP(&feesNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	feesCmd.Flags().StringVar(&feesRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(feesCmd, "rpc-url")
+	feesCmd.Flags().StringVar(&feesRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+
+	rootCmd.AddCommand(feesCmd)
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/fees"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractEventsSize(t *testing.T) {
+	topic := base64.StdEncoding.EncodeToString(make([]byte, 12))
+	data := base64.StdEncoding.EncodeToString(make([]byte, 20))
+
+	size := contractEventsSize([]simulator.DiagnosticEvent{
+		{EventType: "contract", InSuccessfulContractCall: true, TopicsXdr: []string{topic, topic}, DataXdr: data},
+		{EventType: "contract", InSuccessfulContractCall: false, TopicsXdr: []string{topic}, DataXdr: data},
+		{EventType: "diagnostic", InSuccessfulContractCall: true, TopicsXdr: []string{topic}, DataXdr: data},
+	})
+	assert.Equal(t, uint32(52+12+12+20), size)
+}
+
+func TestMeasuredResources(t *testing.T) {
+	account := xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7")}}
+	code := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{1}}}
+	accountKey, err := rpc.EncodeLedgerKey(account)
+	require.NoError(t, err)
+	codeKey, err := rpc.EncodeLedgerKey(code)
+	require.NoError(t, err)
+
+	declared := fees.Resources{Instructions: 1_000_000, DiskReadEntries: 1, WriteEntries: 1, DiskReadBytes: 500, WriteBytes: 500, TransactionSize: 300}
+	simReq := &simulator.SimulationRequest{LedgerEntries: map[string]string{
+		accountKey: base64.StdEncoding.EncodeToString(make([]byte, 92)),
+		codeKey:    base64.StdEncoding.EncodeToString(make([]byte, 4000)),
+	}}
+	simResp := &simulator.SimulationResponse{
+		BudgetUsage:   &simulator.BudgetUsage{CPUInstructions: 420_000},
+		StorageWrites: []simulator.StorageWrite{{Key: accountKey, Entry: base64.StdEncoding.EncodeToString(make([]byte, 96))}, {Key: "deleted"}},
+	}
+
+	actual := measuredResources(declared, xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{code}, ReadWrite: []xdr.LedgerKey{account}}, simReq, simResp, 64)
+	assert.Equal(t, fees.Resources{
+		Instructions:       420_000,
+		DiskReadEntries:    1,
+		WriteEntries:       1,
+		DiskReadBytes:      92, // contract code is in-memory state and not a disk read
+		WriteBytes:         96,
+		ContractEventsSize: 64,
+		TransactionSize:    300,
+	}, actual)
+}
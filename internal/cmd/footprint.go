@@ -0,0 +1,197 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/footprint"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	footprintNetworkFlag  string
+	footprintRPCURLFlag   string
+	footprintRPCTokenFlag string
+	footprintGroupByFlag  string
+)
+
+var footprintCmd = &cobra.Command{
+	Use:   "footprint <tx-hash|envelope-file|->",
+	Short: "Show the ledger keys a transaction declares and the ones it uses",
+	Long: `Decode a transaction's Soroban footprint and simulate it, then list every
+read-only and read-write key grouped by contract (or by entry type with
+--group-by type).
+
+Keys are flagged when they were declared but never accessed, or accessed
+without being declared. A transaction that fails while touching keys outside
+its footprint is reported as failing because of them.
+
+The argument is the hash of an on-chain transaction, or a file holding an
+unsubmitted envelope ("-" for stdin).`,
+	Example: `  erst footprint 5c0a1234... --network testnet
+  erst footprint tx.xdr -n testnet --group-by type`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if footprintGroupByFlag != "contract" && footprintGroupByFlag != "type" {
+			return errors.WrapValidationError(fmt.Sprintf("--group-by must be contract or type, got %q", footprintGroupByFlag))
+		}
+		switch rpc.Network(footprintNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+			return nil
+		default:
+			return errors.WrapInvalidNetwork(footprintNetworkFlag)
+		}
+	},
+	RunE: runFootprint,
+}
+
+// FootprintOutput is the document emitted by 'erst footprint --output json'
+type FootprintOutput struct {
+	TxHash    string            `json:"tx_hash,omitempty"`
+	Network   string            `json:"network"`
+	Status    string            `json:"status"`
+	Failed    bool              `json:"failed"`
+	Footprint *footprint.View   `json:"footprint"`
+	Groups    []footprint.Group `json:"groups"`
+}
+
+func runFootprint(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(footprintNetworkFlag)),
+		rpc.WithToken(resolveRPCToken(footprintRPCTokenFlag, footprintNetworkFlag)),
+	}
+	opts = append(opts, rpcEndpointOptions(footprintRPCURLFlag, footprintNetworkFlag)...)
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+
+	runner, err := simulator.NewRunner("", false)
+	if err != nil {
+		return errors.WrapSimulatorNotFound(err.Error())
+	}
+
+	sim, err := simulateTxOrEnvelope(ctx, cmd.InOrStdin(), client, runner, args[0])
+	if err != nil {
+		return err
+	}
+
+	failed := sim.Resp.Status != "success" || (sim.Tx != nil && !transactionSucceeded(sim.Tx.ResultXdr))
+	view, err := footprint.Analyze(sim.EnvelopeXdr, sim.Resp, failed)
+	if err != nil {
+		return errors.WrapValidationError(err.Error())
+	}
+
+	groups := view.ByOwner()
+	if footprintGroupByFlag == "type" {
+		groups = view.ByType()
+	}
+
+	if jsonOutput() {
+		return printJSON(FootprintOutput{
+			TxHash:    sim.TxHash,
+			Network:   footprintNetworkFlag,
+			Status:    sim.Resp.Status,
+			Failed:    failed,
+			Footprint: view,
+			Groups:    groups,
+		})
+	}
+	printFootprint(view, groups, footprintGroupByFlag)
+	return nil
+}
+
+// transactionSucceeded reports whether an on-chain result is a success. An
+// unreadable result counts as a success, so nothing is blamed on it.
+func transactionSucceeded(resultXdr string) bool {
+	var result xdr.TransactionResult
+	if resultXdr == "" || xdr.SafeUnmarshalBase64(resultXdr, &result) != nil {
+		return true
+	}
+	return result.Successful()
+}
+
+func printFootprint(view *footprint.View, groups []footprint.Group, groupBy string) {
+	if len(view.Keys) == 0 {
+		fmt.Println("The transaction declares no footprint and accessed no ledger entries.")
+		return
+	}
+
+	fmt.Printf("\nFootprint by %s:\n", groupBy)
+	var readOnly, readWrite, unused, undeclared int
+	for _, g := range groups {
+		fmt.Printf("\n  %s\n", g.Name)
+		for _, k := range g.Keys {
+			mode := "--"
+			switch k.Declared {
+			case footprint.ReadOnly:
+				mode = "RO"
+				readOnly++
+			case footprint.ReadWrite:
+				mode = "RW"
+				readWrite++
+			}
+
+			desc := k.Label
+			if groupBy == "type" {
+				desc = k.Owner
+				if k.Label != "" {
+					desc += " " + k.Label
+				}
+			} else if desc == "" {
+				desc = k.Type
+			} else {
+				desc = k.Type + " " + desc
+			}
+
+			note := ""
+			switch k.Problem {
+			case footprint.ProblemUnused:
+				note = "  " + visualizer.Warning() + " declared but never accessed"
+				unused++
+			case footprint.ProblemUndeclared:
+				note = "  " + visualizer.Error() + " accessed but not declared"
+				undeclared++
+			case footprint.ProblemUndeclaredWrite:
+				note = "  " + visualizer.Error() + " written but declared read-only"
+				undeclared++
+			default:
+				if k.Accessed == footprint.ReadWrite {
+					note = "  written"
+				}
+			}
+			fmt.Printf("    [%s] %s%s\n", mode, desc, note)
+		}
+	}
+
+	fmt.Printf("\n%d read-only and %d read-write keys declared", readOnly, readWrite)
+	if view.AccessKnown {
+		fmt.Printf(", %d unused", unused)
+	}
+	fmt.Printf(", %d undeclared\n", undeclared)
+	if !view.AccessKnown {
+		fmt.Println("The simulator did not report key accesses; only writes are compared and unused keys are not flagged.")
+	}
+	if view.CausedFailure {
+		fmt.Printf("%s The transaction failed while accessing keys outside its footprint. Re-run RPC preflight (simulateTransaction) to get a complete footprint.\n", visualizer.Error())
+	}
+}
+
+func init() {
+	footprintCmd.Flags().StringVarP(&footprintNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	footprintCmd.Flags().StringVar(&footprintRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(footprintCmd, "rpc-url")
+	footprintCmd.Flags().StringVar(&footprintRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	footprintCmd.Flags().StringVar(&footprintGroupByFlag, "group-by", "contract", "Group keys by contract or type")
+
+	rootCmd.AddCommand(footprintCmd)
+}
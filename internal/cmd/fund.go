@@ -0,0 +1,249 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/localnet"
+	"github.com/dotandev/hintents/internal/resubmit"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	fundNetworkFlag  string
+	fundRPCURLFlag   string
+	fundRPCTokenFlag string
+	fundFromFlag     string
+	fundAmountFlag   int64
+	fundWaitFlag     time.Duration
+)
+
+var fundCmd = &cobra.Command{
+	Use:   "fund <account|identity>...",
+	Short: "Create and fund accounts on a local network without friendbot",
+	Long: `Create each account that does not exist yet, funded with --amount lumens,
+so a standalone network can be set up for debugging without friendbot.
+Accounts are given as addresses (G...) or stellar CLI identity names.
+
+On --network local the accounts are funded by the network's root account,
+whose key is derived from the network passphrase (local_passphrase in
+config, default the quickstart image's "Standalone Network ; February 2017").
+On other networks, or to spend from another account, pass --from.`,
+	Example: `  erst fund alice bob
+  erst fund GD5DJQDDBKGAYNEAXU562HYGOOSYAEOO6AS53PZXBOZGCP5M2OPGMZV3 --amount 500
+  erst fund alice --rpc-url http://devbox:8000/rpc`,
+	Args: cobra.MinimumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch rpc.Network(fundNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+		default:
+			return errors.WrapInvalidNetwork(fundNetworkFlag)
+		}
+		if rpc.Network(fundNetworkFlag) != rpc.Local && fundFromFlag == "" {
+			return errors.WrapValidationError(fmt.Sprintf("only local networks fund from their root account; pass --from to fund on %s", fundNetworkFlag))
+		}
+		if fundAmountFlag <= 0 {
+			return errors.WrapValidationError("--amount must be positive")
+		}
+		return nil
+	},
+	RunE: runFund,
+}
+
+// FundOutput is the document emitted by 'erst fund --output json'
+type FundOutput struct {
+	Network string `json:"network"`
+	Funder  string `json:"funder"`
+	// Created and Existing hold addresses; accounts that already existed are
+	// left untouched
+	Created  []string `json:"created"`
+	Existing []string `json:"existing"`
+	TxHash   string   `json:"tx_hash,omitempty"`
+	Ledger   uint32   `json:"ledger,omitempty"`
+}
+
+func runFund(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(fundNetworkFlag)),
+		rpc.WithToken(resolveRPCToken(fundRPCTokenFlag, fundNetworkFlag)),
+	}
+	opts = append(opts, rpcEndpointOptions(fundRPCURLFlag, fundNetworkFlag)...)
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+	funder := localnet.Root(client.GetNetworkPassphrase())
+	if fundFromFlag != "" {
+		signers, err := resolveSigners([]string{fundFromFlag})
+		if err != nil {
+			return err
+		}
+		funder = signers[0]
+	}
+
+	accounts := make([]xdr.AccountId, len(args))
+	labels := make(map[string]string)
+	for i, arg := range args {
+		address, err := resolveAccount(arg)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("invalid account %q: %v", arg, err))
+		}
+		// A muxed address funds its underlying account
+		if accounts[i], err = xdr.AddressToAccountId(decoder.BaseAccount(address)); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("invalid account %q: %v", arg, err))
+		}
+		if arg != address {
+			labels[address] = fmt.Sprintf("%s (%s)", arg, address)
+		}
+	}
+	label := func(address string) string {
+		if l, ok := labels[address]; ok {
+			return l
+		}
+		return address
+	}
+
+	out := FundOutput{Network: fundNetworkFlag, Funder: funder.Address(), Created: []string{}, Existing: []string{}}
+	missing, err := missingAccounts(ctx, client, accounts)
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		if !containsAccount(missing, account) {
+			out.Existing = append(out.Existing, account.Address())
+		}
+	}
+
+	if len(missing) > 0 {
+		if err := createAccounts(ctx, client, funder, missing, &out); err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput() {
+		return printJSON(out)
+	}
+	for _, address := range out.Existing {
+		fmt.Printf("%s %s already exists\n", visualizer.Symbol("arrow_r"), label(address))
+	}
+	for _, address := range out.Created {
+		fmt.Printf("%s Created %s with %d XLM\n", visualizer.Success(), label(address), fundAmountFlag)
+	}
+	return nil
+}
+
+// missingAccounts returns the accounts that do not exist on the network
+func missingAccounts(ctx context.Context, client *rpc.Client, accounts []xdr.AccountId) ([]xdr.AccountId, error) {
+	var missing []xdr.AccountId
+	for _, account := range accounts {
+		if containsAccount(missing, account) {
+			continue
+		}
+		exists, err := client.AccountExists(ctx, account)
+		if err != nil {
+			return nil, errors.WrapRPCConnectionFailed(err)
+		}
+		if !exists {
+			missing = append(missing, account)
+		}
+	}
+	return missing, nil
+}
+
+func containsAccount(accounts []xdr.AccountId, account xdr.AccountId) bool {
+	for _, a := range accounts {
+		if a.Equals(account) {
+			return true
+		}
+	}
+	return false
+}
+
+// createAccounts submits one transaction in which funder creates every
+// account and waits for it to be included
+func createAccounts(ctx context.Context, client *rpc.Client, funder *keypair.Full, accounts []xdr.AccountId, out *FundOutput) error {
+	source := xdr.MustAddress(funder.Address())
+	seq, err := client.GetAccountSequence(ctx, source)
+	if err != nil {
+		return errors.WrapRPCConnectionFailed(err)
+	}
+
+	envelope, err := localnet.CreateAccounts(source, seq+1, accounts, fundAmountFlag*localnet.StroopsPerLumen)
+	if err != nil {
+		return errors.WrapValidationError(err.Error())
+	}
+	txHash, err := resubmit.Sign(&envelope, client.GetNetworkPassphrase(), funder)
+	if err != nil {
+		return errors.WrapValidationError(err.Error())
+	}
+	envelopeXdr, err := xdr.MarshalBase64(envelope)
+	if err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+	out.TxHash = txHash
+
+	statusf("Funding %d account(s) from %s\n", len(accounts), funder.Address())
+	sent, err := client.SendTransaction(ctx, envelopeXdr)
+	if err != nil {
+		return errors.WrapRPCConnectionFailed(err)
+	}
+	switch sent.Status {
+	case rpc.SendStatusError:
+		if !jsonOutput() {
+			fmt.Printf("%s Transaction rejected\n", visualizer.Error())
+			fmt.Print(formatResultXdr(sent.ErrorResultXdr))
+		}
+		return errors.WrapSimulationLogicError("transaction rejected by the network")
+	case rpc.SendStatusTryAgainLater:
+		return errors.WrapSimulationLogicError("the RPC node is busy (TRY_AGAIN_LATER); run erst fund again shortly")
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, fundWaitFlag)
+	defer cancel()
+	tx, err := client.WaitForTransaction(waitCtx, txHash, time.Second)
+	if err != nil {
+		return errors.WrapRPCTimeout(err)
+	}
+	out.Ledger = tx.Ledger
+
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(tx.ResultXdr, &result); err != nil {
+		return errors.WrapUnmarshalFailed(err, "TransactionResult")
+	}
+	if !result.Successful() {
+		if !jsonOutput() {
+			fmt.Printf("%s Transaction failed in ledger %d\n", visualizer.Error(), tx.Ledger)
+			fmt.Print(decoder.FormatTransactionResult(result))
+		}
+		return errors.WrapSimulationLogicError("failed to create accounts")
+	}
+	for _, account := range accounts {
+		out.Created = append(out.Created, account.Address())
+	}
+	return nil
+}
+
+func init() {
+	fundCmd.Flags().StringVarP(&fundNetworkFlag, "network", "n", string(rpc.Local), "Stellar network (testnet, mainnet, futurenet, local)")
+	fundCmd.Flags().StringVar(&fundRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(fundCmd, "rpc-url")
+	fundCmd.Flags().StringVar(&fundRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	fundCmd.Flags().StringVar(&fundFromFlag, "from", "", "Fund from this account, a secret key (S...) or identity name, instead of the network's root account")
+	markSensitive(fundCmd, "from")
+	fundCmd.Flags().Int64Var(&fundAmountFlag, "amount", localnet.DefaultStartingBalance, "Starting balance of each new account, in XLM")
+	fundCmd.Flags().DurationVar(&fundWaitFlag, "wait", 30*time.Second, "How long to wait for the transaction to be included")
+
+	rootCmd.AddCommand(fundCmd)
+}
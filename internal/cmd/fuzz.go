@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"fmt"
 
+	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/spf13/cobra"
 )
@@ -19,6 +20,14 @@ var (
 	fuzzInputXDR       string
 	fuzzEnableCov      bool
 	fuzzTargetContract string
+
+	fuzzContractFlag string
+	fuzzFunctionFlag string
+	fuzzSourceFlag   string
+	fuzzNetworkFlag  string
+	fuzzRPCURLFlag   string
+	fuzzRPCTokenFlag string
+	fuzzNoSaveFlag   bool
 )
 
 var fuzzCmd = &cobra.Command{
@@ -34,14 +43,28 @@ other issues in contract code.
 Fuzzing can be started with a base XDR input which will be mutated for
 subsequent iterations, or fuzzing can be run on random inputs.
 
+With --contract and --fn, argument sets for one deployed contract function are
+generated from its spec instead: boundary and random values of each declared
+type, with later sets mutating earlier ones that ran cleanly. Each set is
+simulated against the current ledger state, and every input that makes the
+WASM trap or the simulator crash is saved as a session tagged "fuzz", ready
+for erst session resume. Pass --seed to reproduce a run.
+
 Examples:
   erst fuzz --iterations 10000
   erst fuzz --iterations 50000 --workers 8
-  erst fuzz --xdr <hex-encoded-xdr> --iterations 5000`,
+  erst fuzz --xdr <hex-encoded-xdr> --iterations 5000
+  erst fuzz --contract CABC... --fn swap --source GABC... -n testnet
+  erst fuzz --contract CABC... --fn swap --source GABC... --iterations 500 --seed 42
+  erst fuzz --contract CABC... --fn swap --source alice -n testnet`,
 	RunE: runFuzz,
 }
 
 func runFuzz(cmd *cobra.Command, args []string) error {
+	if fuzzContractFlag != "" {
+		return runContractFuzz(cmd.Context())
+	}
+
 	if fuzzIterations == 0 {
 		return fmt.Errorf("--iterations must be specified and greater than 0")
 	}
@@ -172,7 +195,7 @@ func init() {
 		&fuzzIterations,
 		"iterations",
 		0,
-		"Number of fuzzing iterations (required)",
+		"Number of fuzzing iterations (required, default 100 with --contract)",
 	)
 
 	fuzzCmd.Flags().Uint64Var(
@@ -210,5 +233,16 @@ func init() {
 		"Optional target contract ID to focus fuzzing on",
 	)
 
+	fuzzCmd.Flags().Uint64Var(&fuzzSeed, "seed", 0, "Random seed for argument generation with --contract (default: time-based)")
+	fuzzCmd.Flags().StringVar(&fuzzContractFlag, "contract", "", "Contract ID whose function to fuzz with spec-generated arguments")
+	_ = fuzzCmd.RegisterFlagCompletionFunc("contract", completeContractIDs)
+	fuzzCmd.Flags().StringVar(&fuzzFunctionFlag, "fn", "", "Contract function to fuzz (with --contract)")
+	fuzzCmd.Flags().StringVar(&fuzzSourceFlag, "source", "", "Source account or stellar CLI identity name of the fuzzed invocations (with --contract)")
+	fuzzCmd.Flags().StringVarP(&fuzzNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	fuzzCmd.Flags().StringVar(&fuzzRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(fuzzCmd, "rpc-url")
+	fuzzCmd.Flags().StringVar(&fuzzRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	fuzzCmd.Flags().BoolVar(&fuzzNoSaveFlag, "no-save", false, "Do not save trapping inputs as sessions")
+
 	rootCmd.AddCommand(fuzzCmd)
 }
@@ -0,0 +1,343 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dotandev/hintents/internal/argfuzz"
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// defaultContractFuzzIterations is used when --iterations is not given in
+// function mode, where every iteration is a full simulation
+const defaultContractFuzzIterations = 100
+
+// contractFuzzer simulates calls to one contract function against the
+// current ledger state
+type contractFuzzer struct {
+	client     *rpc.Client
+	runner     simulator.RunnerInterface
+	contract   xdr.ScAddress
+	function   string
+	source     xdr.MuxedAccount
+	ledgerSeq  uint32
+	passphrase string
+
+	// entries caches fetched ledger entries; missing records keys the
+	// ledger does not hold
+	entries map[string]string
+	missing map[string]bool
+	// footprint is every key a preflight has reported so far, used when
+	// preflight fails for an argument set
+	footprint map[string]xdr.LedgerKey
+}
+
+func runContractFuzz(ctx context.Context) error {
+	switch rpc.Network(fuzzNetworkFlag) {
+	case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+	default:
+		return errors.WrapInvalidNetwork(fuzzNetworkFlag)
+	}
+	if fuzzFunctionFlag == "" {
+		return errors.WrapValidationError("--fn is required with --contract")
+	}
+	if fuzzSourceFlag == "" {
+		return errors.WrapValidationError("--source is required with --contract")
+	}
+	source, err := resolveAccountFlag("source", fuzzSourceFlag)
+	if err != nil {
+		return err
+	}
+	fuzzContractFlag = resolveContract(fuzzNetworkFlag, fuzzContractFlag)
+	contractID, err := rpc.ParseContractID(fuzzContractFlag)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("invalid contract ID: %v", err))
+	}
+	iterations := int(fuzzIterations)
+	if iterations == 0 {
+		iterations = defaultContractFuzzIterations
+	}
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(fuzzNetworkFlag)),
+		rpc.WithToken(resolveRPCToken(fuzzRPCTokenFlag, fuzzNetworkFlag)),
+	}
+	opts = append(opts, rpcEndpointOptions(fuzzRPCURLFlag, fuzzNetworkFlag)...)
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+	runner, err := simulator.NewRunner("", false)
+	if err != nil {
+		return errors.WrapSimulatorNotFound(err.Error())
+	}
+
+	statusf("Fetching contract %s\n", fuzzContractFlag)
+	contractEntries, err := rpc.FetchContractBytecode(ctx, client, fuzzContractFlag)
+	if err != nil {
+		return errors.WrapRPCConnectionFailed(err)
+	}
+	var code []byte
+	for _, entryXDR := range contractEntries {
+		var entry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshalBase64(entryXDR, &entry); err == nil && entry.Data.ContractCode != nil {
+			code = entry.Data.ContractCode.Code
+		}
+	}
+	if code == nil {
+		return errors.WrapValidationError(fmt.Sprintf("contract %s has no WASM to fuzz", fuzzContractFlag))
+	}
+	spec, err := contractspec.Parse(code)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to read contract spec: %v", err))
+	}
+	fn, ok := spec.Function(fuzzFunctionFlag)
+	if !ok {
+		return errors.WrapValidationError(fmt.Sprintf("contract has no function %q", fuzzFunctionFlag))
+	}
+
+	f := &contractFuzzer{
+		client:     client,
+		runner:     runner,
+		contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+		function:   fuzzFunctionFlag,
+		source:     source,
+		passphrase: client.GetNetworkPassphrase(),
+		entries:    contractEntries,
+		missing:    make(map[string]bool),
+		footprint:  make(map[string]xdr.LedgerKey),
+	}
+	for encoded := range contractEntries {
+		var key xdr.LedgerKey
+		if err := xdr.SafeUnmarshalBase64(encoded, &key); err == nil {
+			f.footprint[encoded] = key
+		}
+	}
+	if health, err := client.GetHealth(ctx); err == nil {
+		f.ledgerSeq = health.Result.LatestLedger + 1
+	} else {
+		logger.Logger.Warn("Failed to read the latest ledger", "error", err)
+	}
+
+	seed := int64(fuzzSeed)
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	sourceID := source.ToAccountId()
+	sourceAddr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &sourceID}
+	gen := argfuzz.NewGenerator(spec, seed, sourceAddr, f.contract)
+
+	var store session.Store
+	if !fuzzNoSaveFlag {
+		store, err = session.NewStore()
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+		}
+		defer store.Close()
+	}
+
+	statusf("Fuzzing %s with %d argument sets (seed %d)\n", fuzzFunctionFlag, iterations, seed)
+	campaign := &argfuzz.Campaign{
+		Generator:  gen,
+		Function:   fn,
+		Iterations: iterations,
+		Simulate:   f.simulate,
+		OnCase: func(c *argfuzz.Case) {
+			if !c.Outcome.Finding() {
+				return
+			}
+			call := contractspec.DecodeInvocation(spec, fuzzContractFlag, fuzzFunctionFlag, c.Args)
+			statusf("  %s #%d %s: %s\n", visualizer.Error(), c.Iteration, c.Outcome, call)
+			if store == nil {
+				return
+			}
+			id, err := f.save(ctx, store, c)
+			if err != nil {
+				statusf("  %s failed to save session: %v\n", visualizer.Warning(), err)
+				return
+			}
+			statusf("    saved as session %s\n", id)
+		},
+	}
+	summary, err := campaign.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput() {
+		if err := printJSON(summary); err != nil {
+			return err
+		}
+	} else {
+		printContractFuzzSummary(spec, summary)
+	}
+	if len(summary.Findings) > 0 {
+		return fmt.Errorf("fuzzing found %d distinct traps or crashes", len(summary.Findings))
+	}
+	return nil
+}
+
+// envelope builds the invocation of the function with args. data, when not
+// nil, carries the footprint.
+func (f *contractFuzzer) envelope(args []xdr.ScVal, data *xdr.SorobanTransactionData) xdr.TransactionEnvelope {
+	env := invokeEnvelope(f.source, 0, 100, f.contract, f.function, args)
+	if data != nil {
+		env.V1.Tx.Ext = xdr.TransactionExt{V: 1, SorobanData: data}
+	}
+	return env
+}
+
+// footprintFor asks RPC preflight for the keys a call with args touches,
+// falling back to every key seen so far when preflight fails, as it does
+// for most calls that trap
+func (f *contractFuzzer) footprintFor(ctx context.Context, args []xdr.ScVal) *xdr.SorobanTransactionData {
+	if encoded, err := xdr.MarshalBase64(f.envelope(args, nil)); err == nil {
+		preflight, err := f.client.SimulateTransaction(ctx, encoded)
+		if err == nil && preflight.Result.TransactionData != "" {
+			var data xdr.SorobanTransactionData
+			if err := xdr.SafeUnmarshalBase64(preflight.Result.TransactionData, &data); err == nil {
+				footprint := data.Resources.Footprint
+				for _, key := range append(append([]xdr.LedgerKey(nil), footprint.ReadOnly...), footprint.ReadWrite...) {
+					if encoded, err := rpc.EncodeLedgerKey(key); err == nil {
+						f.footprint[encoded] = key
+					}
+				}
+				return &data
+			}
+		}
+	}
+
+	data := &xdr.SorobanTransactionData{}
+	for _, key := range f.footprint {
+		data.Resources.Footprint.ReadWrite = append(data.Resources.Footprint.ReadWrite, key)
+	}
+	return data
+}
+
+func (f *contractFuzzer) simulate(ctx context.Context, args []xdr.ScVal) (*simulator.SimulationRequest, *simulator.SimulationResponse, error) {
+	envelopeXdr, err := xdr.MarshalBase64(f.envelope(args, f.footprintFor(ctx, args)))
+	if err != nil {
+		return nil, nil, errors.WrapMarshalFailed(err)
+	}
+	keys, err := rpc.FootprintKeys(envelopeXdr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fetch []string
+	for _, key := range keys {
+		if _, ok := f.entries[key]; !ok && !f.missing[key] {
+			fetch = append(fetch, key)
+		}
+	}
+	if len(fetch) > 0 {
+		fetched, err := f.client.GetLedgerEntries(ctx, fetch)
+		if err != nil {
+			logger.Logger.Warn("Failed to fetch footprint ledger entries", "error", err)
+		}
+		for _, key := range fetch {
+			if entry, ok := fetched[key]; ok {
+				f.entries[key] = entry
+			} else if err == nil {
+				f.missing[key] = true
+			}
+		}
+	}
+	ledgerEntries := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if entry, ok := f.entries[key]; ok {
+			ledgerEntries[key] = entry
+		}
+	}
+
+	req := &simulator.SimulationRequest{
+		EnvelopeXdr:    envelopeXdr,
+		LedgerEntries:  ledgerEntries,
+		LedgerSequence: f.ledgerSeq,
+		Timestamp:      TimestampFlag,
+	}
+	resp, err := simulator.RunTraced(ctx, f.runner, req)
+	return req, resp, err
+}
+
+// save stores a trapping or crashing case as a session tagged with
+// "fuzz" and its outcome, so it can be replayed and inspected later
+func (f *contractFuzzer) save(ctx context.Context, store session.Store, c *argfuzz.Case) (string, error) {
+	if c.Request == nil {
+		return "", fmt.Errorf("case has no simulation request")
+	}
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(c.Request.EnvelopeXdr, &env); err != nil {
+		return "", errors.WrapUnmarshalFailed(err, "TransactionEnvelope")
+	}
+	hash, err := network.HashTransactionInEnvelope(env, f.passphrase)
+	if err != nil {
+		return "", err
+	}
+	simReqJSON, err := json.Marshal(c.Request)
+	if err != nil {
+		return "", errors.WrapMarshalFailed(err)
+	}
+	simRespJSON, err := json.Marshal(c.Response)
+	if err != nil {
+		return "", errors.WrapMarshalFailed(err)
+	}
+
+	now := time.Now()
+	data := &session.SessionData{
+		ID:              fmt.Sprintf("%s-%d", session.GenerateID(hex.EncodeToString(hash[:])), c.Iteration),
+		CreatedAt:       now,
+		LastAccessAt:    now,
+		Status:          "saved",
+		Network:         fuzzNetworkFlag,
+		EnvelopeXdr:     c.Request.EnvelopeXdr,
+		SimRequestJSON:  string(simReqJSON),
+		SimResponseJSON: string(simRespJSON),
+		ErstVersion:     Version,
+		SchemaVersion:   session.SchemaVersion,
+	}
+	if err := store.Save(ctx, data); err != nil {
+		return "", err
+	}
+	if err := store.AddTags(ctx, data.ID, "fuzz", string(c.Outcome)); err != nil {
+		return "", err
+	}
+	return data.ID, nil
+}
+
+func printContractFuzzSummary(spec *contractspec.Spec, summary *argfuzz.Summary) {
+	fmt.Printf("\nRan %d argument sets\n", summary.Iterations)
+	for _, outcome := range []argfuzz.Outcome{
+		argfuzz.OutcomeOK, argfuzz.OutcomeContractError, argfuzz.OutcomeHostError,
+		argfuzz.OutcomeBudget, argfuzz.OutcomeTrap, argfuzz.OutcomeCrash,
+	} {
+		if n := summary.Outcomes[outcome]; n > 0 {
+			fmt.Printf("  %-15s %d\n", outcome, n)
+		}
+	}
+
+	if len(summary.Findings) == 0 {
+		fmt.Printf("\n%s No traps or crashes found\n", visualizer.Success())
+		return
+	}
+	fmt.Printf("\n%d distinct traps or crashes:\n", len(summary.Findings))
+	for _, c := range summary.Findings {
+		call := contractspec.DecodeInvocation(spec, fuzzContractFlag, fuzzFunctionFlag, c.Args)
+		fmt.Printf("  %s #%d %s\n", visualizer.Error(), c.Iteration, call)
+		fmt.Printf("      %s: %s\n", c.Outcome, c.Error)
+	}
+}
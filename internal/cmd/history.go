@@ -0,0 +1,100 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/history"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/trace"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyInteractiveFlag bool
+	historyLimitFlag       int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Browse the history of saved debugging sessions",
+	Long: `Show saved debugging sessions, most recently accessed first.
+
+With --interactive, opens a full-screen terminal browser that shows the
+sessions as a tree: filter them as you type with /, expand a session with the
+arrow keys, Enter or a click to drill into the events, logs, and flamegraph
+stored with it, and press ? for the keys.`,
+	Example: `  # Print recent sessions
+  erst history
+
+  # Explore sessions interactively
+  erst history --interactive`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := session.NewStore()
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+		}
+		defer store.Close()
+
+		sessions, err := store.List(cmd.Context(), historyLimitFlag)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to list sessions: %v", err))
+		}
+
+		if historyInteractiveFlag {
+			return browseHistory(sessions)
+		}
+
+		if jsonOutput() {
+			if sessions == nil {
+				sessions = []*session.SessionData{}
+			}
+			return printJSON(sessions)
+		}
+
+		if len(sessions) == 0 {
+			fmt.Println("No saved sessions found.")
+			return nil
+		}
+
+		for _, s := range sessions {
+			fmt.Printf("%-24s %-10s %-16s %s\n", s.ID, s.Network, s.LastAccessAt.Format("2006-01-02 15:04"), s.TxHash)
+		}
+		return nil
+	},
+}
+
+// browseHistory runs the interactive browser full screen on the terminal
+func browseHistory(sessions []*session.SessionData) error {
+	if !isatty.IsTerminal(os.Stdin.Fd()) || !isatty.IsTerminal(os.Stdout.Fd()) {
+		return errors.WrapValidationError("--interactive needs a terminal")
+	}
+	restore, err := history.MakeRaw(os.Stdin)
+	if err != nil {
+		return errors.WrapValidationError(err.Error())
+	}
+	defer restore()
+
+	mouse := trace.NewMouseTracker()
+	if err := mouse.Enable(); err == nil {
+		defer mouse.Disable()
+	}
+	fmt.Print("\x1b[?25l")
+	defer fmt.Print("\x1b[?25h")
+
+	width, height := history.TerminalSize(os.Stdout)
+	return history.NewBrowser(sessions, os.Stdin, os.Stdout, width, height).Run()
+}
+
+func init() {
+	historyCmd.Flags().BoolVarP(&historyInteractiveFlag, "interactive", "i", false, "Open the interactive session browser")
+	historyCmd.Flags().IntVar(&historyLimitFlag, "limit", 200, "Maximum number of sessions to load")
+
+	rootCmd.AddCommand(historyCmd)
+}
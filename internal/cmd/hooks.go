@@ -0,0 +1,102 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/hooks"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/spf13/cobra"
+)
+
+var hookFlags []string
+
+// addHookFlags registers --hook on a command that simulates a transaction
+func addHookFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&hookFlags, "hook", nil, "Run this executable or .wasm module after the simulation with the result as input (repeatable; or set hooks in config)")
+}
+
+// postSimulationHooks returns the hooks given with --hook, falling back to
+// the hooks config setting
+func postSimulationHooks() []hooks.Hook {
+	paths := hookFlags
+	if len(paths) == 0 {
+		if cfg, err := config.Load(); err == nil {
+			paths = cfg.Hooks
+		}
+	}
+	list := make([]hooks.Hook, len(paths))
+	for i, path := range paths {
+		list[i] = hooks.Hook{Path: path}
+	}
+	return list
+}
+
+// runPostSimulationHooks runs the configured hooks on a simulation. The
+// result is nil when no hooks are configured.
+func runPostSimulationHooks(ctx context.Context, in hooks.Input) (*hooks.Result, error) {
+	list := postSimulationHooks()
+	if len(list) == 0 {
+		return nil, nil
+	}
+	res, err := hooks.RunAll(ctx, list, in)
+	if err != nil {
+		return nil, errors.WrapValidationError(err.Error())
+	}
+	return res, nil
+}
+
+// runTransactionHooks runs the configured hooks on the simulation of a
+// transaction, decoding its contract invocations for them only when there
+// are hooks to run. The result is nil when no hooks are configured.
+func runTransactionHooks(ctx context.Context, client *rpc.Client, in hooks.Input) (*hooks.Result, error) {
+	if len(postSimulationHooks()) == 0 {
+		return nil, nil
+	}
+	invocations, err := describeInvocations(ctx, client, in.EnvelopeXdr)
+	if err != nil {
+		logger.Logger.Warn("Failed to decode contract invocations", "error", err)
+	}
+	in.Invocations = invocations
+	return runPostSimulationHooks(ctx, in)
+}
+
+// printHookResult writes the hooks' annotations, which JSON output carries
+// instead, and their extra output, which goes to stderr unless the output is
+// text
+func printHookResult(res *hooks.Result) {
+	if res == nil {
+		return
+	}
+	if textOutput() && len(res.Annotations) > 0 {
+		fmt.Printf("\nAnnotations:\n")
+		for _, a := range res.Annotations {
+			fmt.Printf("  [%s] %s (%s)\n", a.Level, a.Message, a.Hook)
+		}
+	}
+	if res.Output != "" {
+		statusf("\n%s\n", res.Output)
+	}
+}
+
+// hookCheck is the error for a simulation failed by a hook, or nil
+func hookCheck(res *hooks.Result) error {
+	if res == nil || !res.Fail {
+		return nil
+	}
+	return errors.WrapCheckFailed(res.Reason)
+}
+
+// hookAnnotations returns the annotations to include in JSON output
+func hookAnnotations(res *hooks.Result) []hooks.Annotation {
+	if res == nil {
+		return nil
+	}
+	return res.Annotations
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/identity"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// resolveAccountFlag parses an account flag that takes an address, an
+// account alias or the name of a stellar CLI identity, e.g. --source alice
+func resolveAccountFlag(flag, value string) (xdr.MuxedAccount, error) {
+	address, err := resolveAccount(value)
+	if err != nil {
+		return xdr.MuxedAccount{}, errors.WrapValidationError(fmt.Sprintf("invalid --%s account: %v", flag, err))
+	}
+	account, err := xdr.AddressToMuxedAccount(address)
+	if err != nil {
+		return xdr.MuxedAccount{}, errors.WrapValidationError(fmt.Sprintf("invalid --%s account: %v", flag, err))
+	}
+	return account, nil
+}
+
+// resolveAccount expands an account alias or a stellar CLI identity name into
+// its address
+func resolveAccount(ref string) (string, error) {
+	if cfg, err := config.Load(); err == nil {
+		ref = cfg.ResolveAccount(ref)
+	}
+	return identity.Resolve(ref)
+}
+
+// resolveAddress expands an account alias, a contract alias for network or a
+// stellar CLI identity name, for arguments that take either kind of address.
+// Anything else is returned as is.
+func resolveAddress(network, ref string) string {
+	if id := resolveContract(network, ref); id != ref {
+		return id
+	}
+	if address, err := resolveAccount(ref); err == nil {
+		return address
+	}
+	return ref
+}
+
+// labelAddress shows an address with its alias or identity name, e.g.
+// "amm (CDLZ...)", in the headings of text output
+func labelAddress(address string) string {
+	if name, ok := decoder.AddressName(address); ok {
+		return fmt.Sprintf("%s (%s)", name, address)
+	}
+	return address
+}
+
+// registerAddressNames labels the addresses of aliases and stellar CLI
+// identities with their names in decoded output. Aliases take precedence,
+// being named for erst. The names are read the first time one is needed, as
+// deriving the address of a seed phrase identity is slow.
+func registerAddressNames() {
+	decoder.SetAddressNameLoader(func() map[string]string {
+		names := identity.Names()
+		if cfg, err := config.Load(); err == nil {
+			for address, name := range cfg.AddressNames() {
+				names[address] = name
+			}
+		}
+		return names
+	})
+}
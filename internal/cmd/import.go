@@ -0,0 +1,134 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importIDFlag      string
+	importForceFlag   bool
+	importFromURLFlag string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <bundle> | --from-url <link>",
+	Short: "Import a session bundle into the local history",
+	Long: `Load a session bundle written by 'erst export' into the local session
+history, keeping its tags and notes. The imported session can then be resumed,
+diffed, reported on or replayed like one recorded locally.
+
+An existing session with the same ID is not replaced unless --force is given;
+use --id to import under a different ID instead.
+
+--from-url downloads a bundle uploaded with 'erst share', given its link or,
+when share_url is configured, just its share ID.`,
+	Example: `  # Import a bundle shared by a teammate
+  erst import bundle.erst
+  erst session resume <session-id>
+
+  # Import under a new ID
+  erst import bundle.erst --id incident-42-repro
+
+  # Import a session shared with 'erst share'
+  erst import --from-url https://files.example.com/erst/abc12345-1700000000.erst`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if importFromURLFlag != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		var data *session.SessionData
+		var err error
+		if importFromURLFlag != "" {
+			data, err = fetchSharedBundle(cmd, importFromURLFlag)
+		} else {
+			data, err = readBundleFile(args[0])
+		}
+		if err != nil {
+			return err
+		}
+		if data.SchemaVersion > session.SchemaVersion {
+			return errors.WrapProtocolUnsupported(uint32(data.SchemaVersion))
+		}
+		if importIDFlag != "" {
+			data.ID = importIDFlag
+		}
+
+		store, err := session.NewStore()
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+		}
+		defer store.Close()
+
+		if _, err := store.Load(ctx, data.ID); err == nil {
+			if !importForceFlag {
+				return errors.WrapValidationError(fmt.Sprintf("session %s already exists; use --id to import under another ID or --force to replace it", data.ID))
+			}
+			if err := store.Delete(ctx, data.ID); err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("failed to replace session %s: %v", data.ID, err))
+			}
+		}
+
+		if err := store.Import(ctx, data); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to import session: %v", err))
+		}
+
+		fmt.Printf("Session imported: %s\n", data.ID)
+		fmt.Printf("  Transaction: %s\n", data.TxHash)
+		fmt.Printf("  Network: %s\n", data.Network)
+		fmt.Printf("\nResume it with: erst session resume %s\n", data.ID)
+		return nil
+	},
+}
+
+func readBundleFile(path string) (*session.SessionData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to open bundle: %v", err))
+	}
+	defer f.Close()
+
+	data, err := session.ImportBundle(f)
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to read bundle %s: %v", path, err))
+	}
+	return data, nil
+}
+
+// fetchSharedBundle downloads a bundle from a share link or ID
+func fetchSharedBundle(cmd *cobra.Command, ref string) (*session.SessionData, error) {
+	endpoint, token := shareEndpoint()
+	link, err := session.ShareURL(endpoint, ref)
+	if err != nil {
+		return nil, errors.WrapValidationError(err.Error())
+	}
+	client, err := shareHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	statusf("Downloading %s\n", link)
+	data, err := session.Fetch(cmd.Context(), client, link, shareTokenFor(endpoint, token, link))
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to fetch bundle %s: %v", link, err))
+	}
+	return data, nil
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importIDFlag, "id", "", "Import the session under this ID")
+	importCmd.Flags().BoolVar(&importForceFlag, "force", false, "Replace an existing session with the same ID")
+	importCmd.Flags().StringVar(&importFromURLFlag, "from-url", "", "Download the bundle from a link or share ID printed by 'erst share'")
+
+	rootCmd.AddCommand(importCmd)
+}
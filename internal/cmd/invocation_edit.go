@@ -0,0 +1,210 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	debugSetArgFlags []string
+	debugSetFnFlag   string
+)
+
+// invocationEdited reports whether --set-arg or --set-fn asked for the
+// transaction's contract call to be rewritten before simulation
+func invocationEdited() bool {
+	return len(debugSetArgFlags) > 0 || debugSetFnFlag != ""
+}
+
+// editInvocation rewrites the contract call in envelopeXdr, renaming the
+// function to fn when it is set and replacing the arguments named by setArgs.
+// Each edit is "<index>=<value>" or "<name>=<value>", and values are parsed
+// against the argument's type in the contract spec. Without a spec the type of
+// the argument being replaced is used instead, which only works for scalars.
+// Auth entries authorizing the original call are rewritten along with it so
+// the envelope stays coherent. It returns the new envelope and a line per edit.
+func editInvocation(envelopeXdr string, load specLoader, fn string, setArgs []string) (string, []string, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+		return "", nil, errors.WrapUnmarshalFailed(err, "transaction envelope")
+	}
+
+	var hostFn *xdr.InvokeHostFunctionOp
+	for _, op := range envelope.Operations() {
+		if op.Body.InvokeHostFunctionOp != nil && op.Body.InvokeHostFunctionOp.HostFunction.InvokeContract != nil {
+			hostFn = op.Body.InvokeHostFunctionOp
+			break
+		}
+	}
+	if hostFn == nil {
+		return "", nil, errors.WrapValidationError("--set-arg and --set-fn need a transaction that invokes a contract")
+	}
+	call := hostFn.HostFunction.InvokeContract
+	original, err := call.MarshalBinary()
+	if err != nil {
+		return "", nil, errors.WrapMarshalFailed(err)
+	}
+
+	contractID, err := call.ContractAddress.String()
+	if err != nil {
+		return "", nil, errors.WrapValidationError(fmt.Sprintf("invalid contract address: %v", err))
+	}
+	spec, err := load(contractID)
+	if err != nil {
+		logger.Logger.Warn("Contract spec unavailable, argument types are taken from the original call", "contract_id", contractID, "error", err)
+		spec = nil
+	}
+
+	var changes []string
+	if fn != "" && fn != string(call.FunctionName) {
+		changes = append(changes, fmt.Sprintf("function: %s -> %s", call.FunctionName, fn))
+		call.FunctionName = xdr.ScSymbol(fn)
+	}
+
+	var inputs []xdr.ScSpecFunctionInputV0
+	if spec != nil {
+		if f, ok := spec.Function(string(call.FunctionName)); ok {
+			inputs = f.Inputs
+		} else {
+			logger.Logger.Warn("Function not found in contract spec", "contract_id", contractID, "function", call.FunctionName)
+		}
+	}
+
+	for _, edit := range setArgs {
+		key, value, ok := strings.Cut(edit, "=")
+		if !ok || key == "" {
+			return "", nil, errors.WrapValidationError(fmt.Sprintf("invalid --set-arg %q: expected <index|name>=<value>", edit))
+		}
+		idx, err := argIndex(key, inputs)
+		if err != nil {
+			return "", nil, err
+		}
+		if idx > len(call.Args) {
+			return "", nil, errors.WrapValidationError(fmt.Sprintf("argument %d is out of range: %s takes %d arguments", idx, call.FunctionName, len(call.Args)))
+		}
+
+		typ, ok := argType(idx, inputs, call.Args)
+		if !ok {
+			return "", nil, errors.WrapValidationError(fmt.Sprintf("cannot infer the type of argument %s without the contract spec; pass it as xdr:<base64>", key))
+		}
+		v, err := contractspec.ParseValue(spec, typ, value)
+		if err != nil {
+			return "", nil, errors.WrapValidationError(fmt.Sprintf("invalid value for argument %s: %v", key, err))
+		}
+
+		label := strconv.Itoa(idx)
+		if idx < len(inputs) {
+			label = inputs[idx].Name
+		}
+		if idx == len(call.Args) {
+			call.Args = append(call.Args, v)
+			changes = append(changes, fmt.Sprintf("%s: (added) %s", label, decoder.FormatScVal(v)))
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", label, decoder.FormatScVal(call.Args[idx]), decoder.FormatScVal(v)))
+		call.Args[idx] = v
+	}
+
+	for i := range hostFn.Auth {
+		root := &hostFn.Auth[i].RootInvocation.Function
+		if root.ContractFn == nil {
+			continue
+		}
+		if raw, err := root.ContractFn.MarshalBinary(); err == nil && bytes.Equal(raw, original) {
+			edited := *call
+			root.ContractFn = &edited
+		}
+	}
+
+	encoded, err := xdr.MarshalBase64(envelope)
+	if err != nil {
+		return "", nil, errors.WrapMarshalFailed(err)
+	}
+	return encoded, changes, nil
+}
+
+// argIndex resolves a --set-arg key, either a 0-based position or the name of
+// one of the function's inputs
+func argIndex(key string, inputs []xdr.ScSpecFunctionInputV0) (int, error) {
+	if idx, err := strconv.Atoi(key); err == nil {
+		if idx < 0 {
+			return 0, errors.WrapValidationError(fmt.Sprintf("invalid argument index %d", idx))
+		}
+		return idx, nil
+	}
+	for i, in := range inputs {
+		if in.Name == key {
+			return i, nil
+		}
+	}
+	if len(inputs) == 0 {
+		return 0, errors.WrapValidationError(fmt.Sprintf("argument %q can only be named with the contract spec; use its index instead", key))
+	}
+	return 0, errors.WrapValidationError(fmt.Sprintf("function has no argument named %q", key))
+}
+
+func argType(idx int, inputs []xdr.ScSpecFunctionInputV0, args []xdr.ScVal) (xdr.ScSpecTypeDef, bool) {
+	if idx < len(inputs) {
+		return inputs[idx].Type, true
+	}
+	if idx < len(args) {
+		return contractspec.ScalarType(args[idx])
+	}
+	return xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeVal}, true
+}
+
+// supplementEditedFootprint adds the entries an edited invocation touches that
+// the original transaction did not. The footprint comes from RPC preflight,
+// so the added entries reflect the latest ledger rather than the one the
+// transaction ran in. Failures are only logged; the simulator records any
+// entries that are still missing.
+func supplementEditedFootprint(ctx context.Context, client *rpc.Client, envelopeXdr string, entries map[string]string) {
+	preflight, err := client.SimulateTransaction(ctx, envelopeXdr)
+	if err != nil {
+		logger.Logger.Warn("RPC preflight of the edited invocation failed", "error", err)
+		return
+	}
+	var data xdr.SorobanTransactionData
+	if err := xdr.SafeUnmarshalBase64(preflight.Result.TransactionData, &data); err != nil {
+		return
+	}
+
+	var missing []string
+	for _, group := range [][]xdr.LedgerKey{data.Resources.Footprint.ReadOnly, data.Resources.Footprint.ReadWrite} {
+		for _, key := range group {
+			encoded, err := rpc.EncodeLedgerKey(key)
+			if err != nil {
+				continue
+			}
+			if _, ok := entries[encoded]; !ok {
+				missing = append(missing, encoded)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	fetched, err := client.GetLedgerEntries(ctx, missing)
+	if err != nil {
+		logger.Logger.Warn("Failed to fetch entries for the edited invocation", "count", len(missing), "error", err)
+		return
+	}
+	for k, v := range fetched {
+		entries[k] = v
+	}
+	statusf("Fetched %d ledger entries touched by the edited invocation (latest ledger)\n", len(fetched))
+}
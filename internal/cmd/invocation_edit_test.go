@@ -0,0 +1,79 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditInvocation(t *testing.T) {
+	amount := xdr.Int64(10)
+	envelopeXdr := invokeContractEnvelope(t, xdr.ContractId{7}, "transfer",
+		xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &amount})
+
+	entry := xdr.ScSpecEntry{
+		Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0,
+		FunctionV0: &xdr.ScSpecFunctionV0{
+			Name: "transfer",
+			Inputs: []xdr.ScSpecFunctionInputV0{
+				{Name: "amount", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeI64}},
+				{Name: "memo", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeSymbol}},
+			},
+		},
+	}
+	raw, err := entry.MarshalBinary()
+	require.NoError(t, err)
+	spec, err := contractspec.ParseEntries(raw)
+	require.NoError(t, err)
+	withSpec := func(string) (*contractspec.Spec, error) { return spec, nil }
+
+	edited, changes, err := editInvocation(envelopeXdr, withSpec, "", []string{"amount=12345", "1=hello"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"amount: 10 -> 12345", "memo: (added) hello"}, changes)
+	invocations, err := invocationsFromEnvelope(context.Background(), edited, withSpec)
+	require.NoError(t, err)
+	assert.Equal(t, "transfer(amount: i64 = 12345, memo: Symbol = hello)", invocations[0].String())
+
+	noSpec := func(string) (*contractspec.Spec, error) { return nil, fmt.Errorf("no wasm") }
+	edited, changes, err = editInvocation(envelopeXdr, noSpec, "burn", []string{"0=-1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"function: transfer -> burn", "0: 10 -> -1"}, changes)
+	invocations, err = invocationsFromEnvelope(context.Background(), edited, noSpec)
+	require.NoError(t, err)
+	assert.Equal(t, "burn(-1)", invocations[0].String())
+
+	for _, bad := range []string{"amount", "amount=12345", "5=1", "0=abc"} {
+		_, _, err := editInvocation(envelopeXdr, noSpec, "", []string{bad})
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestEditInvocation_UpdatesAuth(t *testing.T) {
+	var envelope xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(invokeContractEnvelope(t, xdr.ContractId{7}, "transfer"), &envelope))
+	op := envelope.V1.Tx.Operations[0].Body.InvokeHostFunctionOp
+	call := *op.HostFunction.InvokeContract
+	op.Auth = []xdr.SorobanAuthorizationEntry{{
+		Credentials: xdr.SorobanCredentials{Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount},
+		RootInvocation: xdr.SorobanAuthorizedInvocation{Function: xdr.SorobanAuthorizedFunction{
+			Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+			ContractFn: &call,
+		}},
+	}}
+	envelopeXdr, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+
+	edited, _, err := editInvocation(envelopeXdr, func(string) (*contractspec.Spec, error) { return nil, fmt.Errorf("no wasm") }, "approve", nil)
+	require.NoError(t, err)
+	require.NoError(t, xdr.SafeUnmarshalBase64(edited, &envelope))
+	auth := envelope.V1.Tx.Operations[0].Body.InvokeHostFunctionOp.Auth[0]
+	assert.Equal(t, xdr.ScSymbol("approve"), auth.RootInvocation.Function.ContractFn.FunctionName)
+}
@@ -0,0 +1,255 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/dotandev/hintents/internal/telemetry"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// resolveLedgerState gathers the ledger entries needed to replay a fetched
+// transaction. State is taken from a snapshot cached by an earlier run for the
+// same ledger, or else from the result meta, and any footprint entries it
+// lacks are fetched from RPC. Only the entries recorded at the transaction's
+// ledger are cached: entries fetched from RPC reflect the current ledger, so
+// they are fetched again on every run. --no-cache bypasses the snapshot cache
+// entirely.
+func resolveLedgerState(ctx context.Context, client *rpc.Client, txHash string, resp *rpc.TransactionResponse) (map[string]string, error) {
+	network := string(client.Network)
+
+	ctx, span := telemetry.GetTracer().Start(ctx, "resolve_ledger_state")
+	span.SetAttributes(
+		attribute.String("transaction.hash", txHash),
+		attribute.Int("ledger.sequence", int(resp.Ledger)),
+	)
+	defer span.End()
+
+	var cache *snapshot.Cache
+	if !noCacheFlag && resp.Ledger > 0 {
+		c, err := snapshot.NewDefaultCache()
+		if err != nil {
+			logger.Logger.Warn("Snapshot cache unavailable", "error", err)
+		} else {
+			cache = c
+		}
+	}
+
+	var entries map[string]string
+	cacheHit := false
+	if cache != nil {
+		snap, ok, err := cache.Get(network, resp.Ledger, txHash)
+		if err != nil {
+			logger.Logger.Warn("Ignoring unreadable cached snapshot", "tx", txHash, "error", err)
+		} else if ok {
+			entries = snap.ToMap()
+			cacheHit = true
+			statusf("Loaded %d ledger entries from cached snapshot (ledger %d)\n", len(entries), resp.Ledger)
+		}
+	}
+
+	// atLedger is false when the entries reflect the current ledger rather
+	// than the transaction's, so they must not be cached
+	atLedger := true
+	if !cacheHit {
+		var err error
+		_, decodeSpan := telemetry.GetTracer().Start(ctx, "decode_result_meta")
+		entries, err = rpc.ExtractLedgerEntriesFromMeta(resp.ResultMetaXdr)
+		decodeSpan.SetAttributes(attribute.Int("ledger.entries", len(entries)))
+		decodeSpan.End()
+		if err != nil {
+			logger.Logger.Warn("Failed to extract ledger entries from metadata, fetching from network", "tx", txHash, "error", err)
+			keys, keyErr := extractLedgerKeys(resp.ResultMetaXdr)
+			if keyErr != nil {
+				return nil, errors.WrapUnmarshalFailed(keyErr, "result meta")
+			}
+			entries, err = client.GetLedgerEntries(ctx, keys)
+			if err != nil {
+				span.RecordError(err)
+				return nil, errors.WrapRPCConnectionFailed(err)
+			}
+			atLedger = false
+		} else {
+			logger.Logger.Info("Extracted ledger entries for simulation", "count", len(entries))
+		}
+
+		if cache != nil && atLedger {
+			if err := cache.Put(network, resp.Ledger, txHash, snapshot.FromMap(entries)); err != nil {
+				logger.Logger.Warn("Failed to cache ledger snapshot", "tx", txHash, "error", err)
+			} else {
+				logger.Logger.Info("Cached ledger snapshot", "tx", txHash, "ledger", resp.Ledger, "path", cache.Path(network, resp.Ledger, txHash))
+			}
+		}
+	}
+
+	// Read-only footprint entries never appear in the meta, so the simulator
+	// would otherwise have to do without them
+	footprint, err := rpc.FootprintKeys(resp.EnvelopeXdr)
+	if err != nil {
+		logger.Logger.Warn("Failed to read transaction footprint", "tx", txHash, "error", err)
+	}
+	var missing []string
+	for _, key := range footprint {
+		if _, ok := entries[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		fetched, err := client.GetLedgerEntries(ctx, missing)
+		if err != nil {
+			logger.Logger.Warn("Failed to fetch footprint entries", "tx", txHash, "count", len(missing), "error", err)
+		}
+		for k, v := range fetched {
+			entries[k] = v
+		}
+	}
+
+	span.SetAttributes(attribute.Bool("snapshot.cache_hit", cacheHit), attribute.Int("ledger.entries", len(entries)))
+	return entries, nil
+}
+
+// cachedRunner wraps runner so that an envelope already simulated against the
+// same ledger state is answered from the result cache. --no-cache, a cache
+// that cannot be opened, or the RPC backend, whose results depend on the
+// node's current state, leaves runner as is.
+func cachedRunner(runner simulator.RunnerInterface) simulator.RunnerInterface {
+	if _, remote := runner.(*simulator.RPCRunner); noCacheFlag || remote {
+		return runner
+	}
+	cache, err := simulator.DefaultResultCache()
+	if err != nil {
+		logger.Logger.Warn("Simulation result cache unavailable", "error", err)
+		return runner
+	}
+	return simulator.NewCachingRunner(runner, cache)
+}
+
+// compareNetworkState gathers the ledger state for replaying envelopeXdr on
+// the network client is connected to. Under that network's passphrase the
+// transaction has a different hash; when a transaction with that hash ran
+// there, its recorded state is used. Otherwise the envelope is replayed
+// against the network's current state of keys and the transaction's own
+// footprint, at its next ledger. The returned response carries the result meta
+// and ledger to simulate with.
+func compareNetworkState(ctx context.Context, client *rpc.Client, envelopeXdr string, keys []string) (*rpc.TransactionResponse, map[string]string, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+		return nil, nil, errors.WrapUnmarshalFailed(err, "transaction envelope")
+	}
+	hash, err := network.HashTransactionInEnvelope(envelope, client.GetNetworkPassphrase())
+	if err != nil {
+		return nil, nil, errors.WrapValidationError(fmt.Sprintf("failed to hash transaction: %v", err))
+	}
+
+	txHash := hex.EncodeToString(hash[:])
+	resp, err := client.GetTransaction(ctx, txHash)
+	if err == nil {
+		entries, err := rpc.ExtractLedgerEntriesFromMeta(resp.ResultMetaXdr)
+		if err == nil {
+			statusf("Using the recorded state of %s on %s\n", txHash, client.Network)
+			return resp, entries, nil
+		}
+		logger.Logger.Warn("Failed to extract ledger entries from metadata, using current state", "tx", txHash, "error", err)
+	} else if !rpc.IsTransactionNotFound(err) {
+		return nil, nil, errors.WrapRPCConnectionFailed(err)
+	}
+
+	footprint, err := rpc.FootprintKeys(envelopeXdr)
+	if err != nil {
+		return nil, nil, err
+	}
+	seen := make(map[string]bool, len(keys)+len(footprint))
+	var all []string
+	for _, key := range append(append([]string{}, keys...), footprint...) {
+		if !seen[key] {
+			seen[key] = true
+			all = append(all, key)
+		}
+	}
+	entries, err := client.GetLedgerEntries(ctx, all)
+	if err != nil {
+		return nil, nil, errors.WrapRPCConnectionFailed(err)
+	}
+
+	replay := &rpc.TransactionResponse{EnvelopeXdr: envelopeXdr}
+	if health, err := client.GetHealth(ctx); err == nil {
+		replay.Ledger = health.Result.LatestLedger + 1
+	} else {
+		logger.Logger.Warn("Failed to read the latest ledger", "network", client.Network, "error", err)
+	}
+	statusf("Transaction has not run on %s; replaying against its current state (%d of %d entries found)\n", client.Network, len(entries), len(all))
+	return replay, entries, nil
+}
+
+// analyzeArchival attaches the TTL state of the request's Soroban footprint to
+// simResp. TTLs are compared against the ledger they were read at: the
+// request's ledger when its state carries every TTL, otherwise the TTLs are
+// all read from RPC and compared against the latest ledger, as RPC only
+// serves current state. The analysis is advisory, so failures are only
+// logged.
+func analyzeArchival(ctx context.Context, client *rpc.Client, simReq *simulator.SimulationRequest, simResp *simulator.SimulationResponse) {
+	keys, err := simulator.SorobanFootprintKeys(simReq.EnvelopeXdr)
+	if err != nil || len(keys) == 0 {
+		return
+	}
+
+	entries := make(map[string]string, len(simReq.LedgerEntries)+len(keys))
+	for k, v := range simReq.LedgerEntries {
+		entries[k] = v
+	}
+	ttlKeys := make([]string, 0, len(keys))
+	complete := true
+	for key := range keys {
+		ttlKey, err := simulator.TTLKey(key)
+		if err != nil {
+			return
+		}
+		ttlKeys = append(ttlKeys, ttlKey)
+		if _, ok := entries[ttlKey]; !ok {
+			complete = false
+		}
+	}
+
+	seq := simReq.LedgerSequence
+	if !complete || seq == 0 {
+		health, err := client.GetHealth(ctx)
+		if err != nil {
+			logger.Logger.Warn("Failed to read the latest ledger for entry TTLs", "error", err)
+			return
+		}
+		seq = health.Result.LatestLedger
+	}
+	if !complete {
+		fetched, err := client.GetLedgerEntries(ctx, ttlKeys)
+		if err != nil {
+			logger.Logger.Warn("Failed to fetch TTL entries", "count", len(ttlKeys), "error", err)
+			return
+		}
+		// TTLs recorded at an earlier ledger would be compared against the
+		// latest one, so only current values are kept
+		for _, ttlKey := range ttlKeys {
+			delete(entries, ttlKey)
+			if v, ok := fetched[ttlKey]; ok {
+				entries[ttlKey] = v
+			}
+		}
+	}
+
+	report, err := simulator.AnalyzeArchival(simReq, simResp, entries, seq)
+	if err != nil {
+		logger.Logger.Warn("Failed to analyze entry TTLs", "error", err)
+		return
+	}
+	simResp.Archival = report
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveLedgerState_CachesOnlyMetaEntries(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	contractID := xdr.ContractId{7}
+	dataKey := xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			Durability: xdr.ContractDataDurabilityPersistent,
+		},
+	}
+	dataEntry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 90,
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract:   dataKey.ContractData.Contract,
+				Key:        dataKey.ContractData.Key,
+				Durability: dataKey.ContractData.Durability,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			},
+		},
+	}
+	codeKey := xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{9}},
+	}
+	codeEntry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 150,
+		Data: xdr.LedgerEntryData{
+			Type:         xdr.LedgerEntryTypeContractCode,
+			ContractCode: &xdr.ContractCodeEntry{Hash: xdr.Hash{9}, Code: []byte{0}},
+		},
+	}
+	dataKeyXdr, err := rpc.EncodeLedgerKey(dataKey)
+	require.NoError(t, err)
+	codeKeyXdr, err := rpc.EncodeLedgerKey(codeKey)
+	require.NoError(t, err)
+	codeDataXdr, err := xdr.MarshalBase64(codeEntry.Data)
+	require.NoError(t, err)
+
+	// The meta records the contract data entry; the code is read-only and
+	// only in the footprint
+	resultMeta := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &[]xdr.OperationResult{}},
+			},
+		},
+		TxApplyProcessing: xdr.TransactionMeta{
+			V: 3,
+			V3: &xdr.TransactionMetaV3{
+				TxChangesBefore: xdr.LedgerEntryChanges{{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &dataEntry}},
+			},
+		},
+	}
+	resultMetaXdr, err := xdr.MarshalBase64(resultMeta)
+	require.NoError(t, err)
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"),
+				Ext: xdr.TransactionExt{
+					V: 1,
+					SorobanData: &xdr.SorobanTransactionData{
+						Resources: xdr.SorobanResources{
+							Footprint: xdr.LedgerFootprint{
+								ReadOnly:  []xdr.LedgerKey{codeKey},
+								ReadWrite: []xdr.LedgerKey{dataKey},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	envelopeXdr, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var result interface{}
+		if req.Method == "getLedgerEntries" {
+			fetches++
+			result = map[string]interface{}{"entries": []map[string]interface{}{
+				{"key": codeKeyXdr, "xdr": codeDataXdr, "lastModifiedLedgerSeq": 150},
+			}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	defer server.Close()
+
+	client := &rpc.Client{Network: rpc.Testnet, HorizonURL: server.URL, SorobanURL: server.URL, AltURLs: []string{server.URL}}
+	resp := &rpc.TransactionResponse{EnvelopeXdr: envelopeXdr, ResultMetaXdr: resultMetaXdr, Ledger: 100}
+	const txHash = "abc123"
+
+	entries, err := resolveLedgerState(context.Background(), client, txHash, resp)
+	require.NoError(t, err)
+	assert.Contains(t, entries, dataKeyXdr)
+	assert.Contains(t, entries, codeKeyXdr)
+	assert.Equal(t, 1, fetches)
+
+	cache, err := snapshot.NewDefaultCache()
+	require.NoError(t, err)
+	snap, ok, err := cache.Get(string(rpc.Testnet), resp.Ledger, txHash)
+	require.NoError(t, err)
+	require.True(t, ok)
+	cached := snap.ToMap()
+	assert.Contains(t, cached, dataKeyXdr)
+	assert.NotContains(t, cached, codeKeyXdr, "the current code entry is not state at ledger 100")
+
+	// A cached run still fetches the footprint entries the snapshot lacks
+	entries, err = resolveLedgerState(context.Background(), client, txHash, resp)
+	require.NoError(t, err)
+	assert.Contains(t, entries, codeKeyXdr)
+	assert.Equal(t, 2, fetches)
+}
+
+func TestAnalyzeArchival_ComparesTTLsAtTheLedgerTheyWereRead(t *testing.T) {
+	key := xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{9}},
+	}
+	keyXdr, err := rpc.EncodeLedgerKey(key)
+	require.NoError(t, err)
+	ttlKey, err := simulator.TTLKey(keyXdr)
+	require.NoError(t, err)
+	ttlEntry := func(liveUntil uint32) string {
+		b64, err := xdr.MarshalBase64(xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeTtl,
+			Ttl:  &xdr.TtlEntry{LiveUntilLedgerSeq: xdr.Uint32(liveUntil)},
+		})
+		require.NoError(t, err)
+		return b64
+	}
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"),
+				Ext: xdr.TransactionExt{
+					V: 1,
+					SorobanData: &xdr.SorobanTransactionData{
+						Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{key}}},
+					},
+				},
+			},
+		},
+	}
+	envelopeXdr, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+
+	// The node is at ledger 1000, where the code has been extended to 1200
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		methods = append(methods, req.Method)
+		var result interface{}
+		switch req.Method {
+		case "getHealth":
+			result = map[string]interface{}{"status": "healthy", "latestLedger": 1000}
+		case "getLedgerEntries":
+			result = map[string]interface{}{"entries": []map[string]interface{}{
+				{"key": ttlKey, "xdr": ttlEntry(1200), "lastModifiedLedgerSeq": 900},
+			}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	defer server.Close()
+	client := &rpc.Client{Network: rpc.Testnet, HorizonURL: server.URL, SorobanURL: server.URL, AltURLs: []string{server.URL}}
+
+	// A state recorded at ledger 100 with its TTL is compared at ledger 100
+	req := &simulator.SimulationRequest{
+		EnvelopeXdr:    envelopeXdr,
+		LedgerSequence: 100,
+		LedgerEntries:  map[string]string{keyXdr: "code", ttlKey: ttlEntry(150)},
+	}
+	resp := &simulator.SimulationResponse{}
+	analyzeArchival(context.Background(), client, req, resp)
+	require.NotNil(t, resp.Archival)
+	assert.Equal(t, uint32(100), resp.Archival.LedgerSequence)
+	assert.Equal(t, int64(50), resp.Archival.Entries[0].LedgersLeft)
+	assert.Empty(t, methods)
+
+	// Without its TTL, the current TTL is read and compared at the latest
+	// ledger rather than at ledger 100
+	delete(req.LedgerEntries, ttlKey)
+	resp = &simulator.SimulationResponse{}
+	analyzeArchival(context.Background(), client, req, resp)
+	require.NotNil(t, resp.Archival)
+	assert.Equal(t, uint32(1000), resp.Archival.LedgerSequence)
+	assert.Equal(t, uint32(1200), resp.Archival.Entries[0].LiveUntilLedger)
+	assert.Equal(t, int64(200), resp.Archival.Entries[0].LedgersLeft)
+}
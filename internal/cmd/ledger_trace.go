@@ -0,0 +1,64 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/footprint"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// printLedgerTrace shows every ledger entry the simulation read or wrote,
+// with the value before and, for writes, after
+func printLedgerTrace(trace []simulator.LedgerAccess) {
+	if len(trace) == 0 {
+		return
+	}
+	var readOnly, changed int
+	for _, a := range trace {
+		if a.Access == simulator.LedgerReadOnly {
+			readOnly++
+		}
+		if a.Change != "" && a.Change != simulator.LedgerUnchanged {
+			changed++
+		}
+	}
+	fmt.Printf("\nLedger Entries: %d read-only, %d read-write, %d changed\n", readOnly, len(trace)-readOnly, changed)
+	for _, a := range trace {
+		mode := "R "
+		if a.Access == simulator.LedgerReadWrite {
+			mode = "RW"
+		}
+		fmt.Printf("  [%s] %s\n", mode, ledgerKeyLabel(a.Key))
+		switch a.Change {
+		case "":
+			fmt.Printf("       = %s\n", valueOrAbsent(a.BeforeValue))
+		case simulator.LedgerUnchanged:
+			fmt.Printf("       = %s (unchanged)\n", valueOrAbsent(a.BeforeValue))
+		default:
+			fmt.Printf("       %s %s %s (%s)\n", valueOrAbsent(a.BeforeValue), visualizer.Symbol("arrow_r"), valueOrAbsent(a.AfterValue), a.Change)
+		}
+	}
+}
+
+// ledgerKeyLabel describes a base64 LedgerKey by its type, owner and label
+func ledgerKeyLabel(encoded string) string {
+	var key xdr.LedgerKey
+	if err := xdr.SafeUnmarshalBase64(encoded, &key); err != nil {
+		return encoded
+	}
+	k := footprint.Describe(key)
+	return strings.TrimSpace(strings.Join([]string{k.Type, k.Owner, k.Label}, " "))
+}
+
+func valueOrAbsent(value string) string {
+	if value == "" {
+		return "(absent)"
+	}
+	return value
+}
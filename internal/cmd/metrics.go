@@ -0,0 +1,22 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/dotandev/hintents/internal/metrics"
+	"github.com/dotandev/hintents/internal/session"
+)
+
+// trackSessionStats reports the size of store in the session_db metrics
+func trackSessionStats(store session.Store) {
+	metrics.SetSessionStats(func(ctx context.Context) (int, int64, error) {
+		stats, err := store.Stats(ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+		return stats.Sessions, stats.Bytes, nil
+	})
+}
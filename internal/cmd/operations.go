@@ -0,0 +1,132 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// describeOperations summarises a transaction's operations, logging rather
+// than failing when the envelope or result cannot be decoded
+func describeOperations(envelopeXdr, resultXdr string) []decoder.OperationSummary {
+	ops, err := decoder.DescribeOperations(envelopeXdr, resultXdr)
+	if err != nil {
+		logger.Logger.Warn("Failed to decode operations", "error", err)
+	}
+	if decoder.MixesSorobanOperations(ops) {
+		statusf("%s Soroban operations must be the only operation in a transaction; the network rejects this one as malformed\n", visualizer.Warning())
+	}
+	return ops
+}
+
+// printFeeBump shows the account paying for a fee-bumped transaction next
+// to the source and fee of the transaction it wraps
+func printFeeBump(fb *decoder.FeeBumpSummary) {
+	if fb == nil {
+		return
+	}
+	fmt.Printf("\nFee Bump:\n")
+	fmt.Printf("  Fee source:   %s (max fee %d stroops)\n", fb.FeeSource, fb.Fee)
+	fmt.Printf("  Inner source: %s (fee %d stroops)\n", fb.InnerSource, fb.InnerFee)
+	if fb.InnerHash != "" {
+		fmt.Printf("  Inner hash:   %s\n", fb.InnerHash)
+	}
+}
+
+// printOperations lists each operation with its on-chain result. A lone
+// Soroban operation is already covered by the invocation and simulation
+// output, so nothing is printed for it.
+func printOperations(ops []decoder.OperationSummary) {
+	if len(ops) == 0 || (len(ops) == 1 && ops[0].Soroban) {
+		return
+	}
+	fmt.Printf("\nOperations:\n")
+	for _, op := range ops {
+		status := " "
+		if op.Succeeded != nil {
+			status = visualizer.Success()
+			if !*op.Succeeded {
+				status = visualizer.Error()
+			}
+		}
+		fmt.Printf("  %s [%d] %s: %s\n", status, op.Index, op.Type, op.Description)
+		if op.Source != "" {
+			fmt.Printf("        source: %s\n", op.Source)
+		}
+		if op.Result != "" {
+			fmt.Printf("        result: %s\n", op.Result)
+		}
+		if op.Explanation != "" && op.Succeeded != nil && !*op.Succeeded {
+			fmt.Printf("        %s\n", op.Explanation)
+		}
+		if op.Soroban {
+			fmt.Printf("        simulated below\n")
+		}
+	}
+}
+
+// classicOnly reports whether none of a transaction's operations run on
+// Soroban, leaving nothing for the simulator to replay
+func classicOnly(ops []decoder.OperationSummary) bool {
+	for _, op := range ops {
+		if op.Soroban {
+			return false
+		}
+	}
+	return len(ops) > 0
+}
+
+// ClassicResult is the on-chain outcome of a transaction without Soroban
+// operations, decoded from its result instead of simulated
+type ClassicResult struct {
+	Code        string `json:"code"` // e.g. "tx_failed"
+	Description string `json:"description"`
+	Explanation string `json:"explanation"`
+	// FailedOperation is the result code of the first failing operation
+	FailedOperation string `json:"failed_operation,omitempty"`
+}
+
+// Succeeded reports whether the transaction was applied successfully
+func (r *ClassicResult) Succeeded() bool {
+	return r.Code == "tx_success"
+}
+
+// classicResult decodes a transaction's result code alongside its already
+// decoded operation results
+func classicResult(resultXdr string, ops []decoder.OperationSummary) (*ClassicResult, error) {
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXdr, &result); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "transaction result")
+	}
+	info := decoder.DecodeTransactionResultCode(result.Result.Code)
+	if inner, ok := result.Result.GetInnerResultPair(); ok {
+		// The fee bump only wraps the outcome of the inner transaction
+		info = decoder.DecodeTransactionResultCode(inner.Result.Result.Code)
+	}
+	res := &ClassicResult{Code: info.Code, Description: info.Description, Explanation: info.Explanation}
+	for _, op := range ops {
+		if op.Succeeded != nil && !*op.Succeeded {
+			res.FailedOperation = op.Result
+			break
+		}
+	}
+	return res, nil
+}
+
+// printClassicResult shows the outcome of a classic transaction
+func printClassicResult(res *ClassicResult) {
+	status := visualizer.Success()
+	if !res.Succeeded() {
+		status = visualizer.Error()
+	}
+	fmt.Printf("\n%s %s (%s)\n", status, res.Description, res.Code)
+	fmt.Printf("  %s\n", res.Explanation)
+	fmt.Printf("\nNo Soroban operations to simulate; the results above are decoded from the ledger.\n")
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassicResult(t *testing.T) {
+	resultXdr, err := xdr.MarshalBase64(xdr.TransactionResult{
+		Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxFailed,
+			Results: &[]xdr.OperationResult{},
+		},
+	})
+	require.NoError(t, err)
+
+	ok, failed := true, false
+	ops := []decoder.OperationSummary{
+		{Index: 0, Type: "payment", Result: "payment_success", Succeeded: &ok},
+		{Index: 1, Type: "payment", Result: "payment_underfunded", Succeeded: &failed},
+	}
+	assert.True(t, classicOnly(ops))
+	assert.False(t, classicOnly([]decoder.OperationSummary{{Soroban: true}}))
+
+	res, err := classicResult(resultXdr, ops)
+	require.NoError(t, err)
+	assert.Equal(t, "tx_failed", res.Code)
+	assert.False(t, res.Succeeded())
+	assert.Equal(t, "payment_underfunded", res.FailedOperation)
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// Output formats accepted by the global --output flag
+const (
+	OutputText     = "text"
+	OutputJSON     = "json"
+	OutputMarkdown = "markdown"
+	OutputSARIF    = "sarif"
+	OutputNDJSON   = "ndjson"
+)
+
+// reportFormats lists the commands that support each report-only or
+// streaming format
+var reportFormats = map[string][]string{
+	OutputMarkdown: {"debug"},
+	OutputSARIF:    {"debug", "simulate"},
+	OutputNDJSON:   {"debug", "watch"},
+}
+
+// validateOutputFormat rejects unknown values of the --output flag, and
+// report formats for commands that do not produce them
+func validateOutputFormat(cmd *cobra.Command, format string) error {
+	switch format {
+	case OutputText, OutputJSON:
+		return nil
+	case OutputMarkdown, OutputSARIF, OutputNDJSON:
+		commands := reportFormats[format]
+		for _, name := range commands {
+			if cmd.Name() == name {
+				return nil
+			}
+		}
+		return errors.WrapValidationError(fmt.Sprintf("%s output is only supported by erst %s, not erst %s", format, strings.Join(commands, " and erst "), cmd.Name()))
+	default:
+		return errors.WrapValidationError(fmt.Sprintf("unsupported output format: %s (use: text, json, ndjson, markdown, sarif)", format))
+	}
+}
+
+// jsonOutput reports whether structured JSON output was requested
+func jsonOutput() bool {
+	return OutputFlag == OutputJSON
+}
+
+// ndjsonOutput reports whether results are streamed as newline-delimited JSON
+func ndjsonOutput() bool {
+	return OutputFlag == OutputNDJSON
+}
+
+// textOutput reports whether the human-oriented text output is printed, as
+// opposed to a JSON, Markdown or SARIF document or an NDJSON stream
+func textOutput() bool {
+	switch OutputFlag {
+	case OutputJSON, OutputMarkdown, OutputSARIF, OutputNDJSON:
+		return false
+	}
+	return true
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v interface{}) error {
+	return writeJSON(os.Stdout, v)
+}
+
+// printNDJSON writes v to stdout as a single line of JSON. Callers printing
+// from several goroutines must serialize the calls.
+func printNDJSON(v interface{}) error {
+	if err := json.NewEncoder(os.Stdout).Encode(v); err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+	return nil
+}
+
+// statusf prints human-oriented progress messages. Unless the output is text
+// these go to stderr so that stdout only carries the document.
+func statusf(format string, a ...interface{}) {
+	if !textOutput() {
+		fmt.Fprintf(os.Stderr, format, a...)
+		return
+	}
+	fmt.Printf(format, a...)
+}
+
+// printSARIF writes the failures among reports as a SARIF log, with source
+// paths relative to the working directory, normally the repository root
+func printSARIF(reports ...*report.DebugReport) error {
+	root, err := os.Getwd()
+	if err != nil {
+		root = ""
+	}
+	for _, r := range reports {
+		r.ErstVersion = Version
+	}
+	return printJSON(report.BuildSARIF(reports, root))
+}
+
+// ErrorOutput is the structured form of a fatal command error
+type ErrorOutput struct {
+	Error string `json:"error"`
+}
+
+// afterOutputError is an error returned once the command's document has
+// already been written to stdout
+type afterOutputError struct {
+	err error
+}
+
+func (e *afterOutputError) Error() string { return e.err.Error() }
+func (e *afterOutputError) Unwrap() error { return e.err }
+
+// PrintError reports a fatal command error in the selected output format.
+// Errors raised after the output was printed, such as a failed --check, are
+// only noted on stderr so stdout keeps a single document.
+func PrintError(err error) {
+	var after *afterOutputError
+	if jsonOutput() && !errors.Is(err, errors.ErrCheckFailed) && !errors.As(err, &after) {
+		_ = writeJSON(os.Stdout, ErrorOutput{Error: err.Error()})
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+}
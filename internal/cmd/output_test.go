@@ -0,0 +1,69 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spf13/cobra"
+)
+
+func TestValidateOutputFormat(t *testing.T) {
+	debug := &cobra.Command{Use: "debug"}
+	simulate := &cobra.Command{Use: "simulate"}
+	fees := &cobra.Command{Use: "fees"}
+
+	tests := []struct {
+		cmd     *cobra.Command
+		format  string
+		wantErr bool
+	}{
+		{debug, OutputText, false},
+		{fees, OutputJSON, false},
+		{debug, OutputMarkdown, false},
+		{fees, OutputMarkdown, true},
+		{simulate, OutputMarkdown, true},
+		{debug, OutputSARIF, false},
+		{simulate, OutputSARIF, false},
+		{fees, OutputSARIF, true},
+		{debug, OutputNDJSON, false},
+		{fees, OutputNDJSON, true},
+		{debug, "yaml", true},
+	}
+	for _, tt := range tests {
+		err := validateOutputFormat(tt.cmd, tt.format)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateOutputFormat(%s, %q) error = %v, wantErr %v", tt.cmd.Name(), tt.format, err, tt.wantErr)
+		}
+	}
+}
+
+func TestApplyConfigDefaults_Output(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	defer func(output, bundle string) {
+		OutputFlag, exportBundleFlag = output, bundle
+	}(OutputFlag, exportBundleFlag)
+
+	// export's local --output is a file path, not the global format
+	t.Setenv("ERST_OUTPUT", "json")
+	OutputFlag, exportBundleFlag = OutputText, ""
+	require.NoError(t, exportCmd.ParseFlags(nil))
+	require.NoError(t, applyConfigDefaults(exportCmd))
+	assert.Empty(t, exportBundleFlag)
+
+	// Report formats only apply to the commands that produce them
+	t.Setenv("ERST_OUTPUT", OutputMarkdown)
+	require.NoError(t, debugCmd.ParseFlags(nil))
+	require.NoError(t, applyConfigDefaults(debugCmd))
+	assert.Equal(t, OutputMarkdown, OutputFlag)
+
+	OutputFlag = OutputText
+	require.NoError(t, feesCmd.ParseFlags(nil))
+	require.NoError(t, applyConfigDefaults(feesCmd))
+	assert.Equal(t, OutputText, OutputFlag)
+}
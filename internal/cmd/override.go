@@ -4,8 +4,24 @@
 package cmd
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	overrideEntryFlags []string
+	overrideStateFlag  string
+
+	// ledgerOverrides is populated by debug's PreRunE from the flags above
+	ledgerOverrides map[string]string
 )
 
 type OverrideData struct {
@@ -25,3 +41,84 @@ func loadOverrideState(path string) (map[string]string, error) {
 
 	return override.LedgerEntries, nil
 }
+
+// loadLedgerOverrides gathers overrides from --override-state and every
+// --override-entry, with individual entries taking precedence
+func loadLedgerOverrides() (map[string]string, error) {
+	overrides := make(map[string]string)
+
+	if overrideStateFlag != "" {
+		entries, err := loadOverrideState(overrideStateFlag)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("failed to load override state: %v", err))
+		}
+		for k, v := range entries {
+			if err := simulator.ValidateLedgerOverride(k, v); err != nil {
+				return nil, err
+			}
+			overrides[k] = v
+		}
+	}
+
+	for _, spec := range overrideEntryFlags {
+		key, entry, err := parseOverrideEntry(spec)
+		if err != nil {
+			return nil, err
+		}
+		overrides[key] = entry
+	}
+
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+	return overrides, nil
+}
+
+// parseOverrideEntry parses "<ledger-key-xdr>=<entry-file>" or just
+// "<entry-file>", in which case the key is derived from the entry itself.
+// Base64 keys may end in '=' padding, so the path follows the last '='.
+func parseOverrideEntry(spec string) (string, string, error) {
+	key, path := "", spec
+	if idx := strings.LastIndex(spec, "="); idx >= 0 {
+		key, path = spec[:idx], spec[idx+1:]
+	}
+
+	entry, err := readXDRFile(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	if key == "" {
+		var decoded xdr.LedgerEntry
+		if err := xdr.SafeUnmarshalBase64(entry, &decoded); err != nil {
+			return "", "", errors.WrapValidationError(fmt.Sprintf("%s does not contain a LedgerEntry: %v", path, err))
+		}
+		ledgerKey, err := decoded.LedgerKey()
+		if err != nil {
+			return "", "", errors.WrapValidationError(fmt.Sprintf("cannot derive ledger key for %s: %v", path, err))
+		}
+		if key, err = rpc.EncodeLedgerKey(ledgerKey); err != nil {
+			return "", "", err
+		}
+	}
+
+	if err := simulator.ValidateLedgerOverride(key, entry); err != nil {
+		return "", "", fmt.Errorf("invalid override %q: %w", spec, err)
+	}
+	return key, entry, nil
+}
+
+// readXDRFile returns the base64 XDR stored in path. Files may hold either
+// base64 text or the raw binary encoding.
+func readXDRFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.WrapValidationError(fmt.Sprintf("failed to read override file: %v", err))
+	}
+
+	text := strings.TrimSpace(string(data))
+	if _, err := base64.StdEncoding.DecodeString(text); err == nil && text != "" {
+		return text, nil
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
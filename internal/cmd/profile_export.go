@@ -0,0 +1,114 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/profile"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// Profile export formats accepted by --profile-format
+const (
+	ProfileFormatSVG    = "svg"
+	ProfileFormatPprof  = "pprof"
+	ProfileFormatFolded = "folded"
+	// ProfileFormatSpeedscope is speedscope's JSON file format
+	ProfileFormatSpeedscope = "speedscope"
+)
+
+var (
+	profileFormatFlag string
+	profileOutFlag    string
+	profileMemoryFlag bool
+)
+
+// validateProfileFlags checks --profile-format. Asking for a profile file or
+// format turns profiling on, so --profile itself is optional.
+func validateProfileFlags(formatChanged bool) error {
+	switch strings.ToLower(profileFormatFlag) {
+	case ProfileFormatSVG, ProfileFormatPprof, ProfileFormatFolded, ProfileFormatSpeedscope:
+	default:
+		return errors.WrapValidationError(
+			fmt.Sprintf("unsupported profile format %q (use svg, pprof, folded or speedscope)", profileFormatFlag))
+	}
+	if profileOutFlag != "" || formatChanged {
+		ProfileFlag = true
+	}
+	return nil
+}
+
+// defaultProfilePath names the output file after the transaction and format
+func defaultProfilePath(txHash, format string) string {
+	prefix := txHash
+	if len(prefix) > 8 {
+		prefix = prefix[:8]
+	}
+	switch format {
+	case ProfileFormatPprof:
+		return prefix + ".pb.gz"
+	case ProfileFormatFolded:
+		return prefix + ".folded"
+	case ProfileFormatSpeedscope:
+		return prefix + ".speedscope.json"
+	default:
+		return prefix + ".svg"
+	}
+}
+
+// writeProfile saves the simulation profile in the requested format
+func writeProfile(resp *simulator.SimulationResponse, format, path string) error {
+	format = strings.ToLower(format)
+
+	var data []byte
+	switch format {
+	case ProfileFormatSVG:
+		if resp.Flamegraph == "" {
+			return errors.WrapValidationError("simulator returned no flamegraph")
+		}
+		data = []byte(resp.Flamegraph)
+	case ProfileFormatFolded:
+		if resp.FoldedStacks == "" {
+			return errors.WrapValidationError("simulator returned no folded stacks")
+		}
+		data = []byte(resp.FoldedStacks)
+	case ProfileFormatPprof:
+		if resp.FoldedStacks == "" {
+			return errors.WrapValidationError("simulator returned no folded stacks")
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create profile file: %w", err)
+		}
+		defer f.Close()
+		if err := profile.WriteFoldedAsPprof(resp.FoldedStacks, f); err != nil {
+			return fmt.Errorf("failed to write pprof profile: %w", err)
+		}
+		return nil
+	case ProfileFormatSpeedscope:
+		if resp.FoldedStacks == "" {
+			return errors.WrapValidationError("simulator returned no folded stacks")
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create profile file: %w", err)
+		}
+		defer f.Close()
+		name := strings.TrimSuffix(filepath.Base(path), ".speedscope.json")
+		if err := profile.WriteFoldedAsSpeedscope(resp.FoldedStacks, name, f); err != nil {
+			return fmt.Errorf("failed to write speedscope profile: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write profile: %w", err)
+	}
+	return nil
+}
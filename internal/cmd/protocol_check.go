@@ -0,0 +1,50 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+)
+
+// strictFlag holds --strict, which turns a protocol version mismatch
+// between the network and the simulator into an error
+var strictFlag bool
+
+// checkNetworkProtocol compares the protocol the network currently runs with
+// the one simulations will use: override when set, else the simulator's
+// default. A mismatch is printed to stderr, or refused under --strict. When
+// the network's version cannot be fetched the check is skipped, except under
+// --strict.
+func checkNetworkProtocol(ctx context.Context, client *rpc.Client, network string, override uint32) error {
+	latest, err := client.GetLatestLedger(ctx)
+	if err != nil {
+		if strictFlag {
+			return errors.WrapProtocolMismatch(fmt.Sprintf("could not determine the protocol version of %s: %v", network, err))
+		}
+		logger.Logger.Warn("Could not determine the network protocol version", "network", network, "error", err)
+		return nil
+	}
+
+	simVersion := simulator.LatestVersion()
+	if override > 0 {
+		simVersion = override
+	}
+	skew := simulator.ProtocolSkew(latest.ProtocolVersion, simVersion)
+	if skew == "" {
+		return nil
+	}
+	if strictFlag {
+		return errors.WrapProtocolMismatch(fmt.Sprintf("%s: %s", network, skew))
+	}
+	fmt.Fprintf(os.Stderr, "%s WARNING: %s: %s. Simulation results may not match the network (use --strict to refuse).\n", visualizer.Warning(), network, skew)
+	return nil
+}
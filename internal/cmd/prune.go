@@ -0,0 +1,111 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneOlderThanFlag   string
+	pruneMaxSessionsFlag int
+	pruneMaxSizeFlag     string
+	pruneDryRunFlag      bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old sessions from the session history",
+	Long: `Remove saved sessions that fall outside the retention policy and reclaim
+the space they used.
+
+Without flags the configured retention applies: session_max_age (default 30d),
+session_max_sessions (default 1000) and session_max_db_size (unlimited by
+default). The same limits are enforced automatically whenever sessions are
+listed or resumed. Flags override the configured value for this run only.
+
+Sessions are ranked by last access, so the most recently used ones are kept.`,
+	Example: `  # Remove sessions not accessed in the last 30 days
+  erst prune --older-than 30d
+
+  # Keep at most 200 sessions and 500MB of session data
+  erst prune --max-sessions 200 --max-size 500MB
+
+  # See what would be removed
+  erst prune --older-than 1w --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		policy, err := session.RetentionFromConfig(cfg)
+		if err != nil {
+			return errors.WrapValidationError(err.Error())
+		}
+
+		if pruneOlderThanFlag != "" {
+			if policy.MaxAge, err = session.ParseAge(pruneOlderThanFlag); err != nil {
+				return errors.WrapValidationError(err.Error())
+			}
+		}
+		if cmd.Flags().Changed("max-sessions") {
+			if pruneMaxSessionsFlag < 0 {
+				return errors.WrapValidationError("--max-sessions must not be negative")
+			}
+			policy.MaxSessions = pruneMaxSessionsFlag
+		}
+		if pruneMaxSizeFlag != "" {
+			if policy.MaxBytes, err = session.ParseSize(pruneMaxSizeFlag); err != nil {
+				return errors.WrapValidationError(err.Error())
+			}
+		}
+		policy.DryRun = pruneDryRunFlag
+		policy.Compact = true
+
+		store, err := session.NewStore()
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+		}
+		defer store.Close()
+
+		result, err := store.Prune(cmd.Context(), policy)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("prune failed: %v", err))
+		}
+
+		if jsonOutput() {
+			if result.IDs == nil {
+				result.IDs = []string{}
+			}
+			return printJSON(result)
+		}
+
+		verb := "Pruned"
+		if policy.DryRun {
+			verb = "Would prune"
+			for _, id := range result.IDs {
+				fmt.Printf("  %s\n", id)
+			}
+		}
+		fmt.Printf("%s %d sessions (%d expired, %d over the session limit, %d over the size limit), %s of session data\n",
+			verb, len(result.IDs), result.Expired, result.Excess, result.Oversize, formatBytes(result.FreedBytes))
+		fmt.Printf("%d sessions remain\n", result.Remaining)
+		return nil
+	},
+}
+
+func init() {
+	pruneCmd.Flags().StringVar(&pruneOlderThanFlag, "older-than", "", "Remove sessions not accessed within this age (e.g. 30d, 2w, 12h)")
+	pruneCmd.Flags().IntVar(&pruneMaxSessionsFlag, "max-sessions", 0, "Keep at most this many sessions (0 for no limit)")
+	pruneCmd.Flags().StringVar(&pruneMaxSizeFlag, "max-size", "", "Keep at most this much session data (e.g. 500MB)")
+	pruneCmd.Flags().BoolVar(&pruneDryRunFlag, "dry-run", false, "Show what would be removed without deleting anything")
+
+	rootCmd.AddCommand(pruneCmd)
+}
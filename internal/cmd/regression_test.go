@@ -152,7 +152,7 @@ func init() {
 		"network",
 		"n",
 		string(rpc.Mainnet),
-		"Stellar network to fetch transactions from (mainnet, testnet, futurenet)",
+		"Stellar network to fetch transactions from (mainnet, testnet, futurenet, local)",
 	)
 
 	regressionTestCmd.Flags().StringVar(
@@ -0,0 +1,226 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var replayProtocolVersionFlag uint32
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <session-id>",
+	Short: "Re-run the simulation of a saved debugging session",
+	Long: `Load a saved debug session and re-execute its simulation from the stored
+envelope and result meta XDR. No RPC requests are made.
+
+A session pins the ledger state its simulation ran against, and the replay
+uses exactly those entries: from the session itself, or from the pinned
+snapshot under ~/.erst/snapshots/pinned. It fails rather than run against
+different state. Older sessions take their state from the snapshot cached when
+the transaction was debugged, or from the persisted result meta.
+
+This is useful for re-checking an old failure after upgrading the simulator.
+The new result is compared against the one stored with the session.
+
+Use 'erst session list' to see available sessions.`,
+	Example: `  # Replay a saved session
+  erst replay abc12345-1700000000
+
+  # Replay against a specific protocol version
+  erst replay abc12345-1700000000 --protocol-version 22`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	sessionID := args[0]
+
+	store, err := session.NewStore()
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+	}
+	defer store.Close()
+
+	data, err := store.Load(ctx, sessionID)
+	if err != nil {
+		return errors.WrapSessionNotFound(sessionID)
+	}
+
+	if data.SchemaVersion > session.SchemaVersion {
+		return errors.WrapProtocolUnsupported(uint32(data.SchemaVersion))
+	}
+
+	simReq, err := buildReplayRequest(data)
+	if err != nil {
+		return err
+	}
+
+	if replayProtocolVersionFlag > 0 {
+		if err := simulator.Validate(replayProtocolVersionFlag); err != nil {
+			return fmt.Errorf("invalid protocol version %d: %w", replayProtocolVersionFlag, err)
+		}
+		simReq.ProtocolVersion = &replayProtocolVersionFlag
+	}
+
+	runner, err := simulator.NewRunner("", false)
+	if err != nil {
+		return errors.WrapSimulatorNotFound(err.Error())
+	}
+
+	fmt.Printf("Replaying session: %s\n", data.ID)
+	fmt.Printf("  Transaction: %s\n", data.TxHash)
+	fmt.Printf("  Network: %s\n", data.Network)
+	fmt.Printf("  Recorded with erst %s\n", data.ErstVersion)
+
+	simResp, err := simulator.RunWithContext(ctx, runner, simReq)
+	if err != nil {
+		return errors.WrapSimulationFailed(err, "")
+	}
+
+	printSimulationResult(ctx, data.Network, simResp)
+
+	if previous, err := data.ToSimulationResponse(); err == nil {
+		printReplayComparison(previous, simResp)
+	}
+
+	return nil
+}
+
+// buildReplayRequest reconstructs a SimulationRequest from a stored session
+// without contacting the network.
+func buildReplayRequest(data *session.SessionData) (*simulator.SimulationRequest, error) {
+	envelopeXdr := data.EnvelopeXdr
+	resultMetaXdr := data.ResultMetaXdr
+
+	var ledgerSequence uint32
+
+	// Older sessions may only carry the envelope inside the stored request.
+	if stored, err := data.ToSimulationRequest(); err == nil {
+		ledgerSequence = stored.LedgerSequence
+		if envelopeXdr == "" {
+			envelopeXdr = stored.EnvelopeXdr
+		}
+		if resultMetaXdr == "" {
+			resultMetaXdr = stored.ResultMetaXdr
+		}
+	}
+
+	req := &simulator.SimulationRequest{
+		EnvelopeXdr:    envelopeXdr,
+		ResultMetaXdr:  resultMetaXdr,
+		LedgerSequence: ledgerSequence,
+	}
+
+	// A session whose simulation pinned its ledger state is replayed against
+	// exactly that state, or not at all
+	if previous, err := data.ToSimulationResponse(); err == nil && previous.Snapshot != nil && envelopeXdr != "" {
+		pin := previous.Snapshot
+		entries, err := pinnedEntries(data, pin)
+		if err != nil {
+			return nil, err
+		}
+		req.LedgerSequence = pin.LedgerSequence
+		req.LedgerEntries = entries
+		statusf("Using pinned snapshot %s (ledger %d, %d entries)\n", shortPinID(pin.ID), pin.LedgerSequence, len(entries))
+		return req, nil
+	}
+
+	if envelopeXdr == "" || resultMetaXdr == "" {
+		return nil, errors.WrapSimulationLogicError(
+			fmt.Sprintf("session %s has no stored envelope or result meta to replay", data.ID))
+	}
+
+	// Prefer the full snapshot captured when the session was debugged
+	if ledgerSequence > 0 {
+		if cache, err := snapshot.NewDefaultCache(); err == nil {
+			if snap, ok, err := cache.Get(data.Network, ledgerSequence, data.TxHash); err == nil && ok {
+				req.LedgerEntries = snap.ToMap()
+				statusf("Using cached snapshot for ledger %d (%d entries)\n", ledgerSequence, len(req.LedgerEntries))
+				return req, nil
+			}
+		}
+	}
+
+	ledgerEntries, err := rpc.ExtractLedgerEntriesFromMeta(resultMetaXdr)
+	if err != nil {
+		logger.Logger.Warn("Failed to extract ledger entries from stored metadata", "error", err)
+		fmt.Fprintf(os.Stderr, "Warning: replaying without ledger state: %v\n", err)
+	}
+	req.LedgerEntries = ledgerEntries
+	return req, nil
+}
+
+// pinnedEntries returns the ledger state pinned by a session: the entries
+// stored with its simulation request when they match the pin, else the
+// pinned snapshot from the cache
+func pinnedEntries(data *session.SessionData, pin *snapshot.Pin) (map[string]string, error) {
+	if stored, err := data.ToSimulationRequest(); err == nil && pin.Matches(stored.LedgerSequence, stored.LedgerEntries) {
+		return stored.LedgerEntries, nil
+	}
+
+	cache, err := snapshot.NewDefaultCache()
+	if err != nil {
+		return nil, errors.WrapValidationError(err.Error())
+	}
+	entries, ok, err := cache.GetPinned(pin)
+	if err != nil {
+		return nil, errors.WrapSimulationLogicError(err.Error())
+	}
+	if !ok {
+		return nil, errors.WrapSimulationLogicError(fmt.Sprintf(
+			"snapshot %s pinned by session %s is not in the snapshot cache (%s), so the session cannot be replayed against the state it was simulated with",
+			pin.ID, data.ID, cache.PinnedPath(pin.ID)))
+	}
+	return entries, nil
+}
+
+// shortPinID abbreviates a snapshot pin ID for status lines
+func shortPinID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// printReplayComparison reports how the replayed result differs from the
+// result that was recorded when the session was saved.
+func printReplayComparison(previous, current *simulator.SimulationResponse) {
+	fmt.Printf("\n=== Replay vs Recorded ===\n")
+	if previous.Status != current.Status {
+		fmt.Printf("[DIFF] Status: %s (recorded) vs %s (replay)\n", previous.Status, current.Status)
+	} else {
+		fmt.Printf("Status Match: %s\n", current.Status)
+	}
+	if previous.Snapshot != nil && current.Snapshot != nil && previous.Snapshot.ID != current.Snapshot.ID {
+		fmt.Printf("[DIFF] Snapshot: %s (recorded) vs %s (replay)\n", shortPinID(previous.Snapshot.ID), shortPinID(current.Snapshot.ID))
+	}
+	if previous.Error != current.Error {
+		fmt.Printf("[DIFF] Error: %q (recorded) vs %q (replay)\n", previous.Error, current.Error)
+	}
+	if len(previous.Events) != len(current.Events) {
+		fmt.Printf("[DIFF] Events count: %d (recorded) vs %d (replay)\n", len(previous.Events), len(current.Events))
+	}
+	if previous.BudgetUsage != nil && current.BudgetUsage != nil &&
+		previous.BudgetUsage.CPUInstructions != current.BudgetUsage.CPUInstructions {
+		fmt.Printf("[DIFF] CPU instructions: %d (recorded) vs %d (replay)\n",
+			previous.BudgetUsage.CPUInstructions, current.BudgetUsage.CPUInstructions)
+	}
+}
+
+func init() {
+	replayCmd.Flags().Uint32Var(&replayProtocolVersionFlag, "protocol-version", 0, "Override protocol version for the replay (20, 21, 22, etc)")
+
+	rootCmd.AddCommand(replayCmd)
+}
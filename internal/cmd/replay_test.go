@@ -0,0 +1,144 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pinnedSession(t *testing.T, req *simulator.SimulationRequest, pin *snapshot.Pin) *session.SessionData {
+	t.Helper()
+	reqJSON, err := json.Marshal(req)
+	require.NoError(t, err)
+	respJSON, err := json.Marshal(&simulator.SimulationResponse{Status: "success", Snapshot: pin})
+	require.NoError(t, err)
+	return &session.SessionData{
+		ID:              "pinned-1",
+		TxHash:          "abc",
+		EnvelopeXdr:     "AAAA",
+		SimRequestJSON:  string(reqJSON),
+		SimResponseJSON: string(respJSON),
+	}
+}
+
+func TestBuildReplayRequest_Pinned(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	entries := map[string]string{"k1": "v1", "k2": "v2"}
+	pin := snapshot.NewPin(42, entries)
+
+	// Entries stored with the session are used when they match the pin
+	data := pinnedSession(t, &simulator.SimulationRequest{EnvelopeXdr: "AAAA", LedgerEntries: entries, LedgerSequence: 42}, pin)
+	req, err := buildReplayRequest(data)
+	require.NoError(t, err)
+	assert.Equal(t, entries, req.LedgerEntries)
+	assert.Equal(t, uint32(42), req.LedgerSequence)
+
+	// A session without its entries needs the pinned snapshot
+	data = pinnedSession(t, &simulator.SimulationRequest{EnvelopeXdr: "AAAA"}, pin)
+	_, err = buildReplayRequest(data)
+	assert.ErrorContains(t, err, "cannot be replayed")
+
+	pinSnapshot(&simulator.SimulationResponse{Snapshot: pin}, &simulator.SimulationRequest{LedgerEntries: entries})
+	req, err = buildReplayRequest(data)
+	require.NoError(t, err)
+	assert.Equal(t, entries, req.LedgerEntries)
+	assert.Equal(t, uint32(42), req.LedgerSequence)
+
+	// Drifted entries in the session are ignored in favour of the pin
+	drifted := map[string]string{"k1": "v1", "k2": "changed"}
+	data = pinnedSession(t, &simulator.SimulationRequest{EnvelopeXdr: "AAAA", LedgerEntries: drifted, LedgerSequence: 42}, pin)
+	req, err = buildReplayRequest(data)
+	require.NoError(t, err)
+	assert.Equal(t, entries, req.LedgerEntries)
+}
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		done <- buf.Bytes()
+	}()
+	fn()
+	w.Close()
+	return string(<-done)
+}
+
+// fakeSimulator installs an erst-sim, via ERST_SIM_PATH, that saves its
+// request to the returned path and answers with response
+func fakeSimulator(t *testing.T, response string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake simulator needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	requestPath := filepath.Join(dir, "request.json")
+	script := "#!/bin/sh\ncat > '" + requestPath + "'\ncat <<'EOF'\n" + response + "\nEOF\n"
+	sim := filepath.Join(dir, "erst-sim")
+	require.NoError(t, os.WriteFile(sim, []byte(script), 0o755))
+	t.Setenv("ERST_SIM_PATH", sim)
+	return requestPath
+}
+
+func TestReplayCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	requestPath := fakeSimulator(t, `{"status":"success","events":["e1"]}`)
+
+	entries := map[string]string{"k1": "v1"}
+	data := pinnedSession(t, &simulator.SimulationRequest{EnvelopeXdr: "AAAA", LedgerEntries: entries, LedgerSequence: 42}, snapshot.NewPin(42, entries))
+	data.Network = "testnet"
+	store, err := session.NewStore()
+	require.NoError(t, err)
+	require.NoError(t, store.Save(context.Background(), data))
+	store.Close()
+
+	replayProtocolVersionFlag = 21
+	t.Cleanup(func() { replayProtocolVersionFlag = 0 })
+
+	replayCmd.SetContext(context.Background())
+	var runErr error
+	out := captureStdout(t, func() {
+		runErr = replayCmd.RunE(replayCmd, []string{"pinned-1"})
+	})
+	require.NoError(t, runErr)
+	assert.Contains(t, out, "Replaying session: pinned-1")
+	assert.Contains(t, out, "Status Match: success")
+	assert.Contains(t, out, "[DIFF] Events count: 0 (recorded) vs 1 (replay)")
+
+	// The simulator is given the pinned state and the requested protocol,
+	// without any RPC request
+	var sent simulator.SimulationRequest
+	raw, err := os.ReadFile(requestPath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &sent))
+	assert.Equal(t, "AAAA", sent.EnvelopeXdr)
+	assert.Equal(t, entries, sent.LedgerEntries)
+	assert.Equal(t, uint32(42), sent.LedgerSequence)
+	require.NotNil(t, sent.ProtocolVersion)
+	assert.Equal(t, uint32(21), *sent.ProtocolVersion)
+
+	err = replayCmd.RunE(replayCmd, []string{"missing"})
+	assert.ErrorContains(t, err, "missing")
+}
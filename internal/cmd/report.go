@@ -11,21 +11,30 @@ import (
 
 	"github.com/dotandev/hintents/internal/errors"
 	"github.com/dotandev/hintents/internal/report"
+	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/trace"
 	"github.com/spf13/cobra"
 )
 
 var (
-	reportFormat string
-	reportOutput string
-	reportFile   string
+	reportFormat     string
+	reportOutput     string
+	reportFile       string
+	reportHTMLPath   string
+	reportNetwork    string
+	reportRPCURLFlag string
 )
 
 var reportCmd = &cobra.Command{
-	Use:   "report",
+	Use:   "report [tx-hash|session-id]",
 	Short: "Generate debugging reports from traces",
 	Long: `Generate professional PDF or HTML reports from execution traces.
 
+Given a transaction hash or saved session ID, writes a single self-contained
+HTML file with the decoded envelope, events timeline, logs, resource usage and
+flamegraph, suitable for attaching to bug reports. Saved sessions are used
+when available; otherwise the transaction is fetched and simulated.
+
 Reports include:
   - Executive summary with key findings
   - Detailed execution steps and call stacks
@@ -36,11 +45,21 @@ Reports include:
 Examples:
   erst report --file trace.json --format html --output reports/
   erst report --file trace.json --format pdf --output reports/
-  erst report --file trace.json --format html,pdf --output reports/`,
+  erst report --file trace.json --format html,pdf --output reports/
+  erst report <tx-hash> --html out.html
+  erst report <session-id> --html out.html`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: reportExec,
 }
 
 func reportExec(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		return reportTransactionExec(cmd, args[0])
+	}
+	if reportHTMLPath != "" {
+		return errors.WrapValidationError("--html requires a transaction hash or session ID")
+	}
+
 	if reportFile == "" {
 		return errors.WrapCliArgumentRequired("file")
 	}
@@ -218,6 +237,11 @@ func init() {
 	reportCmd.Flags().StringVar(&reportFormat, "format", "html", "Output format: html, pdf, json, or html,pdf")
 	reportCmd.Flags().StringVar(&reportOutput, "output", ".", "Output directory for reports")
 	reportCmd.Flags().StringVar(&reportFile, "file", "", "Trace file to analyze")
+	reportCmd.Flags().StringVar(&reportHTMLPath, "html", "", "Write a single-file HTML report for a transaction or session to this path")
+	reportCmd.Flags().StringVarP(&reportNetwork, "network", "n", string(rpc.Mainnet), "Stellar network used when fetching a transaction (testnet, mainnet, futurenet, local)")
+	reportCmd.Flags().StringVar(&reportRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(reportCmd, "rpc-url")
+	reportCmd.Flags().BoolVar(&noCacheFlag, "no-cache", false, "Disable local ledger state and simulation result caching")
 
 	rootCmd.AddCommand(reportCmd)
 }
@@ -0,0 +1,163 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/report"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+func reportTransactionExec(cmd *cobra.Command, ref string) error {
+	if reportHTMLPath == "" {
+		return errors.WrapCliArgumentRequired("html")
+	}
+	ctx := cmd.Context()
+
+	debugReport, err := reportFromSession(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if debugReport == nil {
+		if err := rpc.ValidateTransactionHash(ref); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("%s is neither a saved session nor a transaction hash", ref))
+		}
+		if debugReport, err = reportFromNetwork(ctx, ref); err != nil {
+			return err
+		}
+	}
+	debugReport.ErstVersion = Version
+	debugReport.GeneratedAt = time.Now()
+
+	out, err := report.RenderDebugHTML(debugReport)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to render report: %v", err))
+	}
+	if err := os.WriteFile(reportHTMLPath, out, 0644); err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to write HTML report: %v", err))
+	}
+
+	fmt.Printf("[OK] HTML report generated: %s\n", reportHTMLPath)
+	return nil
+}
+
+// reportFromSession builds a report from a saved session, matched either by
+// session ID or by transaction hash. It returns nil when nothing matches.
+func reportFromSession(ctx context.Context, ref string) (*report.DebugReport, error) {
+	store, err := session.NewStore()
+	if err != nil {
+		logger.Logger.Warn("Session store unavailable", "error", err)
+		return nil, nil
+	}
+	defer store.Close()
+
+	data, err := findSession(ctx, store, ref)
+	if err != nil {
+		return nil, nil
+	}
+
+	simResp, err := data.ToSimulationResponse()
+	if err != nil {
+		// The report still carries the envelope, so don't fail outright
+		logger.Logger.Warn("Session has no usable simulation result", "id", data.ID, "error", err)
+		simResp = nil
+	}
+
+	fmt.Printf("Using saved session: %s\n", data.ID)
+	return &report.DebugReport{
+		TxHash:      data.TxHash,
+		Network:     data.Network,
+		SessionID:   data.ID,
+		EnvelopeXdr: data.EnvelopeXdr,
+		Invocations: sessionInvocations(ctx, data.EnvelopeXdr),
+		Simulation:  simResp,
+	}, nil
+}
+
+// sessionInvocations decodes contract calls without network access, so
+// arguments are shown with their raw types
+func sessionInvocations(ctx context.Context, envelopeXdr string) []contractspec.Invocation {
+	invocations, err := invocationsFromEnvelope(ctx, envelopeXdr, func(string) (*contractspec.Spec, error) {
+		return nil, nil
+	})
+	if err != nil {
+		return nil
+	}
+	return invocations
+}
+
+// reportFromNetwork fetches and simulates the transaction with profiling on
+func reportFromNetwork(ctx context.Context, txHash string) (*report.DebugReport, error) {
+	token := rpcTokenFlag
+	if token == "" {
+		token = os.Getenv("ERST_RPC_TOKEN")
+	}
+	if token == "" {
+		if cfg, err := config.LoadConfig(); err == nil && cfg.RPCToken != "" {
+			token = cfg.RPCToken
+		}
+	}
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(reportNetwork)),
+		rpc.WithToken(token),
+	}
+	opts = append(opts, rpcEndpointOptions(reportRPCURLFlag, reportNetwork)...)
+
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+
+	fmt.Printf("Fetching transaction: %s\n", txHash)
+	resp, err := client.GetTransaction(ctx, txHash)
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+
+	ledgerEntries, err := resolveLedgerState(ctx, client, txHash, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	runner, err := simulator.NewRunner("", false)
+	if err != nil {
+		return nil, errors.WrapSimulatorNotFound(err.Error())
+	}
+
+	simResp, err := simulator.RunWithContext(ctx, cachedRunner(runner), &simulator.SimulationRequest{
+		EnvelopeXdr:    resp.EnvelopeXdr,
+		ResultMetaXdr:  resp.ResultMetaXdr,
+		LedgerEntries:  ledgerEntries,
+		LedgerSequence: resp.Ledger,
+		Profile:        true,
+	})
+	if err != nil {
+		return nil, errors.WrapSimulationFailed(err, "")
+	}
+
+	invocations, err := describeInvocations(ctx, client, resp.EnvelopeXdr)
+	if err != nil {
+		logger.Logger.Warn("Failed to decode contract invocations", "error", err)
+	}
+
+	return &report.DebugReport{
+		TxHash:      txHash,
+		Network:     reportNetwork,
+		EnvelopeXdr: resp.EnvelopeXdr,
+		Invocations: invocations,
+		Simulation:  simResp,
+	}, nil
+}
@@ -0,0 +1,352 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/identity"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/resubmit"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	resubmitSignWithFlag    []string
+	resubmitNetworkFlag     string
+	resubmitRPCURLFlag      string
+	resubmitRPCTokenFlag    string
+	resubmitFeeFlag         uint32
+	resubmitTimeoutFlag     time.Duration
+	resubmitWaitFlag        time.Duration
+	resubmitKeepSeqFlag     bool
+	resubmitNoPreflightFlag bool
+	resubmitDryRunFlag      bool
+	resubmitWritePathFlag   string
+)
+
+var resubmitCmd = &cobra.Command{
+	Use:   "resubmit <tx-hash|envelope-file|->",
+	Short: "Repair, sign and submit a transaction again",
+	Long: `Send a failed transaction again once it has been fixed. The argument is the
+hash of an on-chain transaction, whose envelope is fetched from RPC, or a file
+holding an envelope you edited ("-" for stdin).
+
+Before signing, the envelope is repaired:
+  - the sequence number is set to the source account's next one
+  - an expired upper time bound is moved to now + --timeout
+  - Soroban transactions are preflighted with RPC simulateTransaction and
+    take its resources and resource fee, keeping the inclusion fee
+  - --fee, when given, overrides the resulting total fee
+
+The envelope is then signed with every --sign-with key, given as a secret key
+(S...) or the name of a stellar CLI identity, and submitted with RPC
+sendTransaction. The command waits for the transaction to be included and
+exits with an error if it fails; debug it with 'erst debug <hash>'.`,
+	Example: `  erst resubmit 5c0a1234... --sign-with alice -n testnet
+  erst resubmit fixed.xdr --sign-with alice --sign-with bob --fee 200000
+  erst resubmit 5c0a1234... --sign-with alice --dry-run --write signed.xdr`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch rpc.Network(resubmitNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+		default:
+			return errors.WrapInvalidNetwork(resubmitNetworkFlag)
+		}
+		if len(resubmitSignWithFlag) == 0 {
+			return errors.WrapCliArgumentRequired("sign-with")
+		}
+		return nil
+	},
+	RunE: runResubmit,
+}
+
+// ResubmitOutput is the document emitted by 'erst resubmit --output json'
+type ResubmitOutput struct {
+	TxHash      string            `json:"tx_hash"`
+	Network     string            `json:"network"`
+	Changes     []resubmit.Change `json:"changes"`
+	EnvelopeXdr string            `json:"envelope_xdr"`
+	// Status is "signed" for --dry-run, the sendTransaction status, or
+	// "success"/"failed" once the transaction is included
+	Status string `json:"status"`
+	Ledger uint32 `json:"ledger,omitempty"`
+	Result string `json:"result,omitempty"`
+}
+
+func runResubmit(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	signers, err := resolveSigners(resubmitSignWithFlag)
+	if err != nil {
+		return err
+	}
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(resubmitNetworkFlag)),
+		rpc.WithToken(resolveRPCToken(resubmitRPCTokenFlag, resubmitNetworkFlag)),
+	}
+	opts = append(opts, rpcEndpointOptions(resubmitRPCURLFlag, resubmitNetworkFlag)...)
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+
+	envelope, err := loadResubmitEnvelope(ctx, cmd, client, args[0])
+	if err != nil {
+		return err
+	}
+
+	changes, err := repairEnvelope(ctx, client, &envelope)
+	if err != nil {
+		return err
+	}
+
+	txHash, err := resubmit.Sign(&envelope, client.GetNetworkPassphrase(), signers...)
+	if err != nil {
+		return errors.WrapValidationError(err.Error())
+	}
+	envelopeXdr, err := xdr.MarshalBase64(envelope)
+	if err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+
+	out := ResubmitOutput{TxHash: txHash, Network: resubmitNetworkFlag, Changes: changes, EnvelopeXdr: envelopeXdr, Status: "signed"}
+	if !jsonOutput() {
+		printResubmitChanges(changes, txHash)
+	}
+	if resubmitWritePathFlag != "" {
+		if err := os.WriteFile(resubmitWritePathFlag, []byte(envelopeXdr+"\n"), 0o600); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to write envelope: %v", err))
+		}
+		statusf("Signed envelope written to %s\n", resubmitWritePathFlag)
+	}
+	if resubmitDryRunFlag {
+		if jsonOutput() {
+			return printJSON(out)
+		}
+		if resubmitWritePathFlag == "" {
+			fmt.Println(envelopeXdr)
+		}
+		return nil
+	}
+
+	err = submitAndWait(ctx, client, &out)
+	if jsonOutput() {
+		if jsonErr := printJSON(out); jsonErr != nil {
+			return jsonErr
+		}
+	}
+	return err
+}
+
+// resolveSigners turns each --sign-with value, a secret key or an identity
+// name, into a keypair
+func resolveSigners(values []string) ([]*keypair.Full, error) {
+	signers := make([]*keypair.Full, 0, len(values))
+	for _, v := range values {
+		var kp *keypair.Full
+		var err error
+		if strkey.IsValidEd25519SecretSeed(v) {
+			kp, err = keypair.ParseFull(v)
+		} else {
+			kp, err = identity.Keypair(v)
+		}
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("invalid --sign-with key: %v", err))
+		}
+		signers = append(signers, kp)
+	}
+	return signers, nil
+}
+
+// loadResubmitEnvelope reads the envelope of an on-chain transaction, or one
+// from a file or stdin
+func loadResubmitEnvelope(ctx context.Context, cmd *cobra.Command, client *rpc.Client, arg string) (xdr.TransactionEnvelope, error) {
+	if _, statErr := os.Stat(arg); arg != "-" && statErr != nil && rpc.ValidateTransactionHash(arg) == nil {
+		statusf("Fetching transaction: %s\n", arg)
+		tx, err := client.GetTransaction(ctx, arg)
+		if err != nil {
+			return xdr.TransactionEnvelope{}, errors.WrapRPCConnectionFailed(err)
+		}
+		var envelope xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(tx.EnvelopeXdr, &envelope); err != nil {
+			return envelope, errors.WrapUnmarshalFailed(err, "TransactionEnvelope")
+		}
+		return envelope, nil
+	}
+	_, envelope, err := readEnvelope(arg, cmd.InOrStdin())
+	return envelope, err
+}
+
+// repairEnvelope refreshes the sequence number, time bounds, resources and
+// fee of an envelope, returning what changed
+func repairEnvelope(ctx context.Context, client *rpc.Client, envelope *xdr.TransactionEnvelope) ([]resubmit.Change, error) {
+	var changes []resubmit.Change
+	add := func(c *resubmit.Change, err error) error {
+		if err != nil {
+			return errors.WrapValidationError(err.Error())
+		}
+		if c != nil {
+			changes = append(changes, *c)
+		}
+		return nil
+	}
+
+	if !resubmitKeepSeqFlag {
+		source, err := resubmit.SourceAccount(envelope)
+		if err != nil {
+			return nil, errors.WrapValidationError(err.Error())
+		}
+		seq, err := client.GetAccountSequence(ctx, source)
+		if err != nil {
+			return nil, errors.WrapRPCConnectionFailed(err)
+		}
+		if err := add(resubmit.SetSequence(envelope, seq+1)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := add(resubmit.ExtendTimeBounds(envelope, time.Now(), resubmitTimeoutFlag)); err != nil {
+		return nil, err
+	}
+
+	if !resubmitNoPreflightFlag && resubmit.IsSoroban(envelope) {
+		if err := resubmit.StripSignatures(envelope); err != nil {
+			return nil, errors.WrapValidationError(err.Error())
+		}
+		unsigned, err := xdr.MarshalBase64(*envelope)
+		if err != nil {
+			return nil, errors.WrapMarshalFailed(err)
+		}
+		statusf("Preflighting with simulateTransaction...\n")
+		preflight, err := client.SimulateTransaction(ctx, unsigned)
+		if err != nil {
+			return nil, errors.WrapRPCConnectionFailed(err)
+		}
+		if preflight.Result.Error != "" {
+			return nil, errors.WrapSimulationLogicError(fmt.Sprintf("preflight failed: %s; run 'erst simulate' on the envelope to debug it", preflight.Result.Error))
+		}
+		if preflight.Result.TransactionData == "" {
+			logger.Logger.Warn("Preflight returned no transaction data, keeping the envelope's resources")
+		} else {
+			preflightChanges, err := resubmit.ApplyPreflight(envelope, preflight.Result.TransactionData)
+			if err != nil {
+				return nil, errors.WrapValidationError(err.Error())
+			}
+			changes = append(changes, preflightChanges...)
+		}
+	}
+
+	if resubmitFeeFlag > 0 {
+		if err := add(resubmit.SetFee(envelope, resubmitFeeFlag)); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// submitAndWait sends the signed envelope and, unless --wait is 0, waits for
+// it to be included, recording the outcome in out
+func submitAndWait(ctx context.Context, client *rpc.Client, out *ResubmitOutput) error {
+	statusf("Submitting transaction: %s\n", out.TxHash)
+	sent, err := client.SendTransaction(ctx, out.EnvelopeXdr)
+	if err != nil {
+		return errors.WrapRPCConnectionFailed(err)
+	}
+	out.Status = sent.Status
+
+	switch sent.Status {
+	case rpc.SendStatusError:
+		out.Result = formatResultXdr(sent.ErrorResultXdr)
+		if !jsonOutput() {
+			fmt.Printf("%s Transaction rejected\n", visualizer.Error())
+			fmt.Print(out.Result)
+		}
+		return errors.WrapSimulationLogicError("transaction rejected by the network")
+	case rpc.SendStatusTryAgainLater:
+		return errors.WrapSimulationLogicError("the RPC node is busy (TRY_AGAIN_LATER); run erst resubmit again shortly")
+	}
+
+	if resubmitWaitFlag == 0 {
+		statusf("%s Transaction %s (%s)\n", visualizer.Success(), sent.Status, out.TxHash)
+		return nil
+	}
+
+	statusf("Waiting for the transaction to be included...\n")
+	waitCtx, cancel := context.WithTimeout(ctx, resubmitWaitFlag)
+	defer cancel()
+	tx, err := client.WaitForTransaction(waitCtx, out.TxHash, time.Second)
+	if err != nil {
+		return errors.WrapRPCTimeout(err)
+	}
+	out.Ledger = tx.Ledger
+
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(tx.ResultXdr, &result); err != nil {
+		return errors.WrapUnmarshalFailed(err, "TransactionResult")
+	}
+	if result.Successful() {
+		out.Status = "success"
+		statusf("%s Transaction succeeded in ledger %d\n", visualizer.Success(), tx.Ledger)
+		return nil
+	}
+
+	out.Status = "failed"
+	out.Result = decoder.FormatTransactionResult(result)
+	if !jsonOutput() {
+		fmt.Printf("%s Transaction failed in ledger %d\n", visualizer.Error(), tx.Ledger)
+		fmt.Print(out.Result)
+		fmt.Printf("Debug it with: erst debug %s -n %s\n", out.TxHash, resubmitNetworkFlag)
+	}
+	return errors.WrapSimulationLogicError("transaction failed")
+}
+
+func formatResultXdr(resultXdr string) string {
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXdr, &result); err != nil {
+		return ""
+	}
+	return decoder.FormatTransactionResult(result)
+}
+
+func printResubmitChanges(changes []resubmit.Change, txHash string) {
+	if len(changes) == 0 {
+		fmt.Println("No repairs needed")
+	} else {
+		fmt.Println("Repairs:")
+		for _, c := range changes {
+			fmt.Printf("  %s %s\n", visualizer.Symbol("arrow_r"), c)
+		}
+	}
+	fmt.Printf("Signed transaction: %s\n", txHash)
+}
+
+func init() {
+	resubmitCmd.Flags().StringArrayVar(&resubmitSignWithFlag, "sign-with", nil, "Secret key (S...) or stellar CLI identity to sign with (repeatable)")
+	markSensitive(resubmitCmd, "sign-with")
+	resubmitCmd.Flags().StringVarP(&resubmitNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	resubmitCmd.Flags().StringVar(&resubmitRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(resubmitCmd, "rpc-url")
+	resubmitCmd.Flags().StringVar(&resubmitRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	resubmitCmd.Flags().Uint32Var(&resubmitFeeFlag, "fee", 0, "Total fee in stroops, overriding the preflight estimate")
+	resubmitCmd.Flags().DurationVar(&resubmitTimeoutFlag, "timeout", 5*time.Minute, "Validity window given to an expired time bound")
+	resubmitCmd.Flags().DurationVar(&resubmitWaitFlag, "wait", 30*time.Second, "How long to wait for the transaction to be included (0 to return once submitted)")
+	resubmitCmd.Flags().BoolVar(&resubmitKeepSeqFlag, "keep-sequence", false, "Keep the envelope's sequence number")
+	resubmitCmd.Flags().BoolVar(&resubmitNoPreflightFlag, "no-preflight", false, "Keep the envelope's Soroban resources and fee")
+	resubmitCmd.Flags().BoolVar(&resubmitDryRunFlag, "dry-run", false, "Repair and sign, but print the envelope instead of submitting it")
+	resubmitCmd.Flags().StringVar(&resubmitWritePathFlag, "write", "", "Also write the signed envelope to this file")
+
+	rootCmd.AddCommand(resubmitCmd)
+}
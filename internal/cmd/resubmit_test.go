@@ -0,0 +1,33 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSigners(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("STELLAR_CONFIG_HOME", home)
+	t.Chdir(t.TempDir())
+
+	alice, bob := keypair.MustRandom(), keypair.MustRandom()
+	require.NoError(t, os.MkdirAll(filepath.Join(home, "identity"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, "identity", "alice.toml"), []byte("secret_key = \""+alice.Seed()+"\"\n"), 0o600))
+
+	signers, err := resolveSigners([]string{"alice", bob.Seed()})
+	require.NoError(t, err)
+	require.Len(t, signers, 2)
+	assert.Equal(t, alice.Address(), signers[0].Address())
+	assert.Equal(t, bob.Address(), signers[1].Address())
+
+	_, err = resolveSigners([]string{"carol"})
+	assert.Error(t, err)
+}
@@ -4,7 +4,15 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dotandev/hintents/internal/config"
 	"github.com/dotandev/hintents/internal/localization"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
 	"github.com/dotandev/hintents/internal/updater"
 	"github.com/spf13/cobra"
 )
@@ -14,8 +22,13 @@ var (
 	TimestampFlag int64
 	WindowFlag    int64
 	ProfileFlag   bool
+	OutputFlag    string
 )
 
+// dbPathFlag holds --db, the session database to use in place of the
+// configured one
+var dbPathFlag string
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "erst",
@@ -40,11 +53,27 @@ Examples:
 
 Get started with 'erst debug --help' or visit the documentation.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyConfigDefaults(cmd); err != nil {
+			return err
+		}
+		session.SetDBPath(dbPathFlag)
+		if err := openActivityLog(cmd); err != nil {
+			return err
+		}
+		if err := validateOutputFormat(cmd, OutputFlag); err != nil {
+			return err
+		}
+		if err := validateRPCFlags(); err != nil {
+			return err
+		}
+
 		// Load localizations
 		if err := localization.LoadTranslations(); err != nil {
 			return err
 		}
 
+		registerAddressNames()
+
 		// Check for updates asynchronously (non-blocking)
 		checkForUpdatesAsync()
 
@@ -56,8 +85,18 @@ Get started with 'erst debug --help' or visit the documentation.`,
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The first interrupt cancels the command's context, which stops RPC calls
+// and the simulator; a second one terminates the process as usual.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	recordActivity(cmd, err)
+	return err
 }
 
 // checkForUpdatesAsync runs the update check in a goroutine to not block CLI startup
@@ -93,5 +132,120 @@ func init() {
 		"Enable CPU/Memory profiling and generate a flamegraph SVG",
 	)
 
+	rootCmd.PersistentFlags().StringVar(
+		&OutputFlag,
+		"output",
+		OutputText,
+		"Output format: text, json, ndjson (debug, watch), markdown (debug), or sarif (debug, simulate)",
+	)
+
+	rootCmd.PersistentFlags().StringArrayVar(
+		&rpcHeaderFlags,
+		"rpc-header",
+		nil,
+		`Extra header sent with every RPC request, as "Name: value" (repeatable)`,
+	)
+
+	rootCmd.PersistentFlags().Float64Var(
+		&rpcRateLimitFlag,
+		"rpc-rate-limit",
+		0,
+		"Maximum RPC requests per second, shared by all endpoints (0 for no limit)",
+	)
+
+	rootCmd.PersistentFlags().IntVar(
+		&rpcBurstFlag,
+		"rpc-burst",
+		0,
+		"Requests allowed at once under --rpc-rate-limit (default: one second's worth)",
+	)
+
+	rootCmd.PersistentFlags().IntVar(
+		&rpcConcurrencyFlag,
+		"rpc-concurrency",
+		0,
+		"Maximum RPC requests in flight per network (default: rpc_concurrency from config, else no limit)",
+	)
+
+	rootCmd.PersistentFlags().DurationVar(
+		&rpcTimeoutFlag,
+		"rpc-timeout",
+		0,
+		"Give up on an RPC call, retries included, after this long (e.g. 30s; 0 for no limit)",
+	)
+
+	rootCmd.PersistentFlags().IntVar(
+		&rpcRetriesFlag,
+		"rpc-retries",
+		rpc.DefaultRetryConfig().MaxRetries,
+		"Times a failed RPC request is retried on timeouts, connection errors, 429 and 5xx responses (default: rpc_retries from config, else 3)",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&strictFlag,
+		"strict",
+		false,
+		"Refuse to simulate when the network's protocol version differs from the simulator's",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&networkPassphraseFlag,
+		"network-passphrase",
+		"",
+		"Network passphrase for hashing and signature verification, overriding the named network's (for private networks and forks)",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&proxyFlag,
+		"proxy",
+		"",
+		"HTTP, HTTPS or SOCKS5 proxy for RPC requests, e.g. socks5h://127.0.0.1:9050 (default: HTTPS_PROXY, HTTP_PROXY, ALL_PROXY)",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&dbPathFlag,
+		"db",
+		"",
+		"Session database file, or Postgres URL with session_store = postgres (default: ERST_DB_PATH, session_db_url or ~/.erst/sessions.db)",
+	)
+
 	// Register commands
 }
+
+// applyConfigDefaults fills --output, --network, --proxy, --rpc-retries and
+// the RPC rate limit from the config files and environment when they are not
+// given on the command line. The network flag is not marked as changed, so
+// commands that auto-detect it still do.
+func applyConfigDefaults(cmd *cobra.Command) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	// Commands such as export have a local --output file path that shadows
+	// the global format flag; the configured format only applies to the
+	// latter, and only where the command supports it
+	if f := cmd.Flags().Lookup("output"); f != nil && f == cmd.Root().PersistentFlags().Lookup("output") &&
+		!f.Changed && cfg.Output != "" && validateOutputFormat(cmd, cfg.Output) == nil {
+		if err := f.Value.Set(cfg.Output); err != nil {
+			return err
+		}
+	}
+	if f := cmd.Flags().Lookup("network"); f != nil && !f.Changed && cfg.CLINetwork() != "" {
+		if err := f.Value.Set(cfg.CLINetwork()); err != nil {
+			return err
+		}
+	}
+	if !cmd.Flags().Changed("rpc-rate-limit") && cfg.RpcRateLimit != 0 {
+		rpcRateLimitFlag = cfg.RpcRateLimit
+	}
+	if !cmd.Flags().Changed("rpc-burst") && cfg.RpcRateBurst != 0 {
+		rpcBurstFlag = cfg.RpcRateBurst
+	}
+	if !cmd.Flags().Changed("rpc-retries") && cfg.RpcRetries != nil {
+		rpcRetriesFlag = *cfg.RpcRetries
+	}
+	if !cmd.Flags().Changed("proxy") && cfg.Proxy != "" {
+		proxyFlag = cfg.Proxy
+	}
+	return nil
+}
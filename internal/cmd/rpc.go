@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/spf13/cobra"
 )
 
@@ -48,8 +49,13 @@ var rpcHealthCmd = &cobra.Command{
 		fmt.Println("[STATS] RPC Endpoint Status:")
 		fmt.Println()
 
+		proxy, err := rpc.NewProxyFunc(proxyFlag)
+		if err != nil {
+			return err
+		}
 		client := &http.Client{
-			Timeout: 5 * time.Second,
+			Timeout:   5 * time.Second,
+			Transport: rpc.NewProxyTransport(proxy),
 		}
 
 		for i, url := range urls {
@@ -0,0 +1,191 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/report"
+	"github.com/dotandev/hintents/internal/schema"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	schemaOutputDirFlag string
+	schemaValidateFlag  string
+)
+
+// outputSchema is a structured output erst publishes a JSON Schema for,
+// described by the Go value it is encoded from
+type outputSchema struct {
+	name        string
+	description string
+	value       interface{}
+}
+
+// outputSchemas lists every published schema. Renaming or removing a field of
+// these types, or changing its type, breaks consumers coding against them.
+var outputSchemas = []outputSchema{
+	{"debug", "Result of erst debug and erst simulate with --output json", DebugOutput{}},
+	{"simulation-response", "Simulator result embedded in debug output and stored with sessions in sim_response_json", simulator.SimulationResponse{}},
+	{"session", "A saved session, as printed by erst tag with --output json", session.SessionData{}},
+	{"sessions", "Saved sessions listed by erst session list, erst search and erst history with --output json", []session.SessionData{}},
+	{"search-count", "Number of matching sessions printed by erst search --count-only with --output json", SearchCountOutput{}},
+	{"stats", "Session history aggregates printed by erst stats with --output json", session.HistoryStats{}},
+	{"prune", "Sessions removed by erst prune with --output json", session.PruneResult{}},
+	{"report", "Execution trace report written by erst report --format json", report.Report{}},
+	{"chain", "Result of erst chain with --output json", ChainOutput{}},
+	{"build", "Transaction built by erst build with --output json", BuildOutput{}},
+	{"event", "One contract event printed by erst events with --output json", EventOutput{}},
+	{"fees", "Fee breakdown printed by erst fees with --output json", FeesOutput{}},
+	{"footprint", "Footprint printed by erst footprint with --output json", FootprintOutput{}},
+	{"fund", "Accounts funded by erst fund with --output json", FundOutput{}},
+	{"resubmit", "Result of erst resubmit with --output json", ResubmitOutput{}},
+	{"snapshot-export", "Snapshot written by erst snapshot export with --output json", SnapshotFileOutput{}},
+	{"statediff", "Storage changes printed by erst statediff with --output json", StateDiffOutput{}},
+	{"storage", "Contract storage printed by erst storage with --output json", StorageOutput{}},
+	{"wasm", "Contract code details printed by erst wasm with --output json", WasmOutput{}},
+	{"top", "Failure summary printed by erst top on every refresh with --output json or ndjson", watch.FailureSummary{}},
+	{"error", "Fatal command error printed with --output json", ErrorOutput{}},
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [name]",
+	Short: "Print the JSON Schema of erst's structured output",
+	Long: `Print the JSON Schema (draft-07) of a structured output, so that tools
+consuming erst's --output json can code against a stable contract. Without a
+name the published schemas are listed.
+
+Schemas accept properties they do not list: later erst versions may add
+fields, but do not rename or remove them or change their type.
+
+--validate checks a document, such as saved command output, against the
+schema; -o writes every schema to a directory as <name>.schema.json.`,
+	Example: `  # List the published schemas
+  erst schema
+
+  # Print the schema of erst debug --output json
+  erst schema debug
+
+  # Check saved output in CI
+  erst debug <tx-hash> --output json > out.json
+  erst schema debug --validate out.json
+
+  # Write all schemas to a directory
+  erst schema -o schemas/`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if schemaOutputDirFlag != "" {
+			if len(args) > 0 {
+				return errors.WrapValidationError("-o writes every schema; do not give a name")
+			}
+			return writeOutputSchemas(schemaOutputDirFlag)
+		}
+		if len(args) == 0 {
+			if schemaValidateFlag != "" {
+				return errors.WrapCliArgumentRequired("name")
+			}
+			return listOutputSchemas()
+		}
+
+		s, err := findOutputSchema(args[0])
+		if err != nil {
+			return err
+		}
+		doc, err := schema.Generate(s.name, s.description, s.value)
+		if err != nil {
+			return errors.WrapMarshalFailed(err)
+		}
+		if schemaValidateFlag == "" {
+			_, err = os.Stdout.Write(doc)
+			return err
+		}
+
+		data, err := readSchemaInput(schemaValidateFlag)
+		if err != nil {
+			return err
+		}
+		if err := schema.Validate(doc, data); err != nil {
+			return errors.WrapCheckFailed(fmt.Sprintf("%s: %v", schemaValidateFlag, err))
+		}
+		statusf("%s matches the %s schema\n", schemaValidateFlag, s.name)
+		return nil
+	},
+}
+
+func findOutputSchema(name string) (*outputSchema, error) {
+	for i := range outputSchemas {
+		if outputSchemas[i].name == name {
+			return &outputSchemas[i], nil
+		}
+	}
+	return nil, errors.WrapValidationError(fmt.Sprintf("unknown schema %q; run 'erst schema' to list them", name))
+}
+
+func listOutputSchemas() error {
+	if jsonOutput() {
+		type entry struct {
+			Name        string `json:"name"`
+			ID          string `json:"id"`
+			Description string `json:"description"`
+		}
+		entries := make([]entry, 0, len(outputSchemas))
+		for _, s := range outputSchemas {
+			entries = append(entries, entry{Name: s.name, ID: schema.BaseID + s.name + schema.FileExtension, Description: s.description})
+		}
+		return printJSON(entries)
+	}
+	for _, s := range outputSchemas {
+		fmt.Printf("%-20s %s\n", s.name, s.description)
+	}
+	return nil
+}
+
+// writeOutputSchemas writes every published schema to dir
+func writeOutputSchemas(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create %s: %v", dir, err))
+	}
+	for _, s := range outputSchemas {
+		doc, err := schema.Generate(s.name, s.description, s.value)
+		if err != nil {
+			return errors.WrapMarshalFailed(err)
+		}
+		path := filepath.Join(dir, s.name+schema.FileExtension)
+		if err := os.WriteFile(path, doc, 0644); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to write %s: %v", path, err))
+		}
+	}
+	statusf("Wrote %d schemas to %s\n", len(outputSchemas), dir)
+	return nil
+}
+
+// readSchemaInput reads the document to validate from a file, or stdin for -
+func readSchemaInput(path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to read %s: %v", path, err))
+	}
+	return data, nil
+}
+
+func init() {
+	schemaCmd.Flags().StringVarP(&schemaOutputDirFlag, "output-dir", "o", "", "Write every schema to this directory")
+	schemaCmd.Flags().StringVar(&schemaValidateFlag, "validate", "", "Validate a JSON document (file or - for stdin) against the schema")
+
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,291 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/report"
+	"github.com/dotandev/hintents/internal/schema"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// publishedSchemaDir holds the schemas published with the repository
+const publishedSchemaDir = "../../docs/schema/output"
+
+func generateSchema(t *testing.T, name string) []byte {
+	t.Helper()
+	s, err := findOutputSchema(name)
+	require.NoError(t, err)
+	doc, err := schema.Generate(s.name, s.description, s.value)
+	require.NoError(t, err)
+	return doc
+}
+
+func validateOutput(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.NoError(t, schema.Validate(generateSchema(t, name), data), "%s output", name)
+}
+
+func TestOutputSchemas_Published(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(publishedSchemaDir, "*"+schema.FileExtension))
+	require.NoError(t, err)
+	assert.Len(t, files, len(outputSchemas), "stale schema files in %s", publishedSchemaDir)
+
+	for _, s := range outputSchemas {
+		published, err := os.ReadFile(filepath.Join(publishedSchemaDir, s.name+schema.FileExtension))
+		require.NoError(t, err, "schema %s is not published; run 'go run . schema -o docs/schema/output'", s.name)
+		assert.Equal(t, string(generateSchema(t, s.name)), string(published),
+			"schema %s changed; check the change is compatible and run 'go run . schema -o docs/schema/output'", s.name)
+	}
+}
+
+// fill sets every field reachable from v, so that each property of a schema
+// is exercised. Recursive types are cut off after a few levels.
+func fill(v reflect.Value, depth int) {
+	if depth > 3 {
+		return
+	}
+	switch {
+	case v.Type() == reflect.TypeOf(time.Time{}):
+		v.Set(reflect.ValueOf(time.Unix(1_700_000_000, 0)))
+		return
+	case v.Type() == reflect.TypeOf(json.RawMessage{}):
+		v.Set(reflect.ValueOf(json.RawMessage(`{"raw":true}`)))
+		return
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Pointer:
+		p := reflect.New(v.Type().Elem())
+		fill(p.Elem(), depth+1)
+		v.Set(p)
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		fill(s.Index(0), depth+1)
+		v.Set(s)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fill(v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		key := reflect.New(v.Type().Key()).Elem()
+		fill(key, depth+1)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fill(elem, depth+1)
+		m.SetMapIndex(key, elem)
+		v.Set(m)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(map[string]interface{}{"any": []interface{}{1, "x"}}))
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i), depth)
+			}
+		}
+	}
+}
+
+func TestOutputSchemas_ValidateOutputs(t *testing.T) {
+	for _, s := range outputSchemas {
+		t.Run(s.name, func(t *testing.T) {
+			// Both with every field set and with every field at its zero
+			// value, which writes nulls for nil slices, maps and pointers.
+			// Commands print lists as [] when empty, never as null.
+			empty := reflect.New(reflect.TypeOf(s.value))
+			if empty.Elem().Kind() == reflect.Slice {
+				empty.Elem().Set(reflect.MakeSlice(empty.Elem().Type(), 0, 0))
+			}
+			validateOutput(t, s.name, empty.Interface())
+
+			full := reflect.New(reflect.TypeOf(s.value))
+			fill(full.Elem(), 0)
+			validateOutput(t, s.name, full.Interface())
+		})
+	}
+}
+
+// resetFlags puts every flag of cmd and its subcommands back to its
+// default, so that one command run does not leak into the next
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if v, ok := f.Value.(pflag.SliceValue); ok {
+			_ = v.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// runErst runs erst with args and returns what it printed on stdout
+func runErst(t *testing.T, args ...string) []byte {
+	t.Helper()
+	t.Setenv("ERST_NO_UPDATE_CHECK", "1")
+	defer resetFlags(rootCmd)
+	rootCmd.SetArgs(args)
+	defer rootCmd.SetArgs(nil)
+
+	var err error
+	out := captureStdout(t, func() {
+		_, err = rootCmd.ExecuteContextC(context.Background())
+	})
+	require.NoError(t, err, "erst %s", strings.Join(args, " "))
+	return []byte(out)
+}
+
+// assertOutputMatches checks what a command printed against a schema
+func assertOutputMatches(t *testing.T, name string, out []byte) {
+	t.Helper()
+	assert.NoError(t, schema.Validate(generateSchema(t, name), out), "%s output:\n%s", name, out)
+}
+
+// debugRPCServer answers the calls erst debug makes for one failed
+// contract call, both the Horizon lookup and Soroban RPC
+func debugRPCServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	contractID := xdr.ContractId{7}
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID}
+	fn := xdr.ScSymbol("transfer")
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"),
+				Operations: []xdr.Operation{{
+					Body: xdr.OperationBody{
+						Type: xdr.OperationTypeInvokeHostFunction,
+						InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+							HostFunction: xdr.HostFunction{
+								Type:           xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+								InvokeContract: &xdr.InvokeContractArgs{ContractAddress: contract, FunctionName: fn, Args: xdr.ScVec{}},
+							},
+						},
+					},
+				}},
+				Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{}},
+			},
+		},
+	}
+	envelopeXdr, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+	result := xdr.TransactionResult{
+		Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &[]xdr.OperationResult{}},
+	}
+	resultXdr, err := xdr.MarshalBase64(result)
+	require.NoError(t, err)
+	meta := xdr.TransactionResultMeta{
+		Result:            xdr.TransactionResultPair{Result: result},
+		TxApplyProcessing: xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}},
+	}
+	metaXdr, err := xdr.MarshalBase64(meta)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/transactions/") {
+			hash := strings.TrimPrefix(r.URL.Path, "/transactions/")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": hash, "hash": hash, "ledger": 100, "successful": false,
+				"envelope_xdr": envelopeXdr, "result_xdr": resultXdr, "result_meta_xdr": metaXdr,
+			})
+			return
+		}
+		var req struct {
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		reply := map[string]interface{}{"jsonrpc": "2.0", "id": 1}
+		switch req.Method {
+		case "getTransaction":
+			reply["result"] = map[string]interface{}{"status": "FAILED", "envelopeXdr": envelopeXdr, "resultXdr": resultXdr, "resultMetaXdr": metaXdr, "ledger": 100}
+		case "getLedgerEntries":
+			reply["result"] = map[string]interface{}{"entries": []interface{}{}, "latestLedger": 120}
+		case "getLatestLedger":
+			reply["result"] = map[string]interface{}{"id": "x", "sequence": 120, "protocolVersion": simulator.LatestVersion()}
+		default:
+			reply["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+		}
+		_ = json.NewEncoder(w).Encode(reply)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// The published schemas describe what the commands actually print
+func TestOutputSchemas_RealOutputs(t *testing.T) {
+	seedSessions(t, "a", "b")
+	store, err := session.NewStore()
+	require.NoError(t, err)
+	require.NoError(t, store.AddTags(context.Background(), "a", "incident-42"))
+	store.Close()
+
+	assertOutputMatches(t, "sessions", runErst(t, "session", "list", "--output", "json"))
+	assertOutputMatches(t, "sessions", runErst(t, "search", "--tag", "incident-42", "--output", "json"))
+	assertOutputMatches(t, "search-count", runErst(t, "search", "--count-only", "--output", "json"))
+	assertOutputMatches(t, "session", runErst(t, "tag", "b", "reviewed", "--output", "json"))
+	assertOutputMatches(t, "stats", runErst(t, "stats", "--output", "json"))
+	assertOutputMatches(t, "prune", runErst(t, "prune", "--dry-run", "--output", "json"))
+
+	// A failed contract call, simulated by a stand-in erst-sim
+	fakeSimulator(t, `{"status":"error","error":"HostError: Error(Contract, #3)","events":["contract_event"],`+
+		`"diagnostic_events":[{"event_type":"diagnostic","contract_id":"CA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQGAXE",`+
+		`"topics":["error","Error(Contract, #3)"],"data":"void","in_successful_contract_call":false}],`+
+		`"logs":["wasm trap: unreachable"],`+
+		`"budget_usage":{"cpu_instructions":1200000,"memory_bytes":40960,"operations_count":1,"cpu_limit":100000000,"memory_limit":41943040},`+
+		`"call_tree":[{"contract":"CA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQGAXE","function":"transfer","status":"failed",`+
+		`"budget_known":true,"cpu_insns":900000,"sub_calls":[{"contract":"CB","function":"balance","status":"success","budget_known":false}]}]}`)
+	server := debugRPCServer(t)
+	out := runErst(t, "debug", strings.Repeat("ab", 32), "--network", "testnet", "--rpc-url", server.URL, "--output", "json")
+	assertOutputMatches(t, "debug", out)
+
+	var debug DebugOutput
+	require.NoError(t, json.Unmarshal(out, &debug))
+	require.NotNil(t, debug.Simulation)
+	simulation, err := json.Marshal(debug.Simulation)
+	require.NoError(t, err)
+	assertOutputMatches(t, "simulation-response", simulation)
+
+	// An execution trace report
+	data, err := report.NewBuilder("Execution Trace Report").
+		WithTransactionHash("abc").
+		AddExecutionStep(0, "invoke", "failed", "trapped").
+		AddContractCall("CA3D", "transfer", "failed").
+		SetSummary("failed", "12ms", 3, 1, 1, 0).
+		SetRiskAssessment("high", 0.9).
+		ExportJSON()
+	require.NoError(t, err)
+	assert.NoError(t, schema.Validate(generateSchema(t, "report"), data))
+}
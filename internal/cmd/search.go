@@ -5,19 +5,35 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/dotandev/hintents/internal/db"
+	"github.com/dotandev/hintents/internal/decoder"
 	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/session"
 	"github.com/spf13/cobra"
 )
 
 var (
-	searchErrorFlag string
-	searchEventFlag string
-	searchTxFlag    string
-	searchLimitFlag int
+	searchErrorFlag  string
+	searchEventFlag  string
+	searchTxFlag     string
+	searchLimitFlag  int
+	searchOffsetFlag int
+	searchTagFlags   []string
+	searchTextFlag   string
+	searchSinceFlag  string
+	searchUntilFlag  string
+	searchSortFlag   string
+	searchDescFlag   bool
+	searchCountFlag  bool
 )
 
+// SearchCountOutput is printed by 'erst search --count-only' with --output json
+type SearchCountOutput struct {
+	Count int `json:"count"`
+}
+
 var searchCmd = &cobra.Command{
 	Use:   "search",
 	Short: "Search through saved debugging sessions",
@@ -28,9 +44,18 @@ You can search by:
   • Transaction hash (exact match)
   • Error message patterns (regex)
   • Event patterns (regex)
+  • Free text across errors, events and logs (indexed phrase match)
+  • Tags added with 'erst tag' (all given tags must match)
+  • Creation time, with --since and --until
   • Combine multiple filters
 
-Results are ordered by timestamp (most recent first) and limited by --limit flag.`,
+--since and --until take a date (2025-06-01), a time (2025-06-01T15:04:05Z)
+or an age such as 7d, 2w or 12h before now.
+
+Results are ordered by last access (most recent first), or with --sort by
+creation time, network, error message or invoked contract, ascending unless
+--desc is given. --limit caps a page of results and --offset skips to later
+pages. --count-only prints how many sessions match instead of listing them.`,
 	Example: `  # Search for specific transaction
   erst search --tx abc123...def789
 
@@ -40,47 +65,117 @@ Results are ordered by timestamp (most recent first) and limited by --limit flag
   # Search for contract events
   erst search --event "transfer|mint"
 
+  # Full-text search over events and logs
+  erst search --text "trap: unreachable"
+
+  # Find sessions tagged during an incident
+  erst search --tag incident-42 --tag frontend
+
   # Combine filters and limit results
-  erst search --error "panic" --limit 5`,
+  erst search --error "panic" --limit 5
+
+  # Failures from last week, second page
+  erst search --error "panic" --since 14d --until 7d --limit 20 --offset 20
+
+  # Newest sessions first, grouped by error
+  erst search --sort error --limit 50
+  erst search --sort timestamp --desc
+
+  # How many sessions hit a budget error this week
+  erst search --error "budget" --since 7d --count-only`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		store, err := db.InitDB()
+		if searchDescFlag && searchSortFlag == "" {
+			return errors.WrapValidationError("--desc requires --sort")
+		}
+
+		store, err := session.NewStore()
 		if err != nil {
-			return errors.WrapValidationError(fmt.Sprintf("failed to initialize session database: %v", err))
+			return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
 		}
+		defer store.Close()
 
-		params := db.SearchParams{
+		filter := session.SearchFilter{
 			TxHash:     searchTxFlag,
 			ErrorRegex: searchErrorFlag,
 			EventRegex: searchEventFlag,
+			Text:       searchTextFlag,
+			Tags:       searchTagFlags,
+			Sort:       searchSortFlag,
+			Descending: searchDescFlag,
 			Limit:      searchLimitFlag,
+			Offset:     searchOffsetFlag,
+		}
+		now := time.Now()
+		if searchSinceFlag != "" {
+			if filter.Since, err = parseSearchTime(searchSinceFlag, now); err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("invalid --since: %v", err))
+			}
+		}
+		if searchUntilFlag != "" {
+			if filter.Until, err = parseSearchTime(searchUntilFlag, now); err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("invalid --until: %v", err))
+			}
 		}
 
-		sessions, err := store.SearchSessions(params)
+		if searchCountFlag {
+			count, err := store.Count(cmd.Context(), filter)
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("search failed: %v", err))
+			}
+			if jsonOutput() {
+				return printJSON(SearchCountOutput{Count: count})
+			}
+			fmt.Println(count)
+			return nil
+		}
+
+		sessions, err := store.Search(cmd.Context(), filter)
 		if err != nil {
 			return errors.WrapValidationError(fmt.Sprintf("search failed: %v", err))
 		}
 
+		if jsonOutput() {
+			if sessions == nil {
+				sessions = []*session.SessionData{}
+			}
+			return printJSON(sessions)
+		}
+
 		if len(sessions) == 0 {
 			fmt.Println("No matching sessions found.")
 			return nil
 		}
 
-		fmt.Printf("Found %d matching sessions:\n", len(sessions))
+		if searchOffsetFlag > 0 {
+			fmt.Printf("Found %d matching sessions (from result %d):\n", len(sessions), searchOffsetFlag+1)
+		} else {
+			fmt.Printf("Found %d matching sessions:\n", len(sessions))
+		}
 		for _, s := range sessions {
 			fmt.Println("--------------------------------------------------")
-			fmt.Printf("ID: %d\n", s.ID)
-			fmt.Printf("Time: %s\n", s.Timestamp.Format("2006-01-02 15:04:05"))
+			fmt.Printf("ID: %s\n", s.ID)
+			fmt.Printf("Time: %s\n", s.CreatedAt.Format("2006-01-02 15:04:05"))
 			fmt.Printf("Tx Hash: %s\n", s.TxHash)
 			fmt.Printf("Network: %s\n", s.Network)
 			fmt.Printf("Status: %s\n", s.Status)
-			if s.ErrorMsg != "" {
-				fmt.Printf("Error: %s\n", s.ErrorMsg)
+			if len(s.Tags) > 0 {
+				fmt.Printf("Tags: %s\n", strings.Join(s.Tags, ", "))
+			}
+			for _, note := range s.Notes {
+				fmt.Printf("Note: %s\n", note.Text)
+			}
+			resp, err := s.ToSimulationResponse()
+			if err != nil {
+				continue
 			}
-			if len(s.Events) > 0 {
+			if resp.Error != "" {
+				fmt.Printf("Error: %s\n", resp.Error)
+			}
+			if len(resp.Events) > 0 {
 				fmt.Println("Events:")
-				for _, e := range s.Events {
-					fmt.Printf("  - %s\n", e)
+				for _, e := range resp.Events {
+					fmt.Printf("  - %s\n", decoder.FormatEvent(e))
 				}
 			}
 		}
@@ -90,11 +185,35 @@ Results are ordered by timestamp (most recent first) and limited by --limit flag
 	},
 }
 
+// parseSearchTime reads a --since or --until bound: a date in local time, an
+// RFC 3339 time, or an age before now
+func parseSearchTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	age, err := session.ParseAge(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date, time or age (e.g. 2025-06-01, 2025-06-01T15:04:05Z or 7d)", value)
+	}
+	return now.Add(-age), nil
+}
+
 func init() {
 	searchCmd.Flags().StringVar(&searchErrorFlag, "error", "", "Regex pattern to match error messages")
 	searchCmd.Flags().StringVar(&searchEventFlag, "event", "", "Regex pattern to match events")
+	searchCmd.Flags().StringVar(&searchTextFlag, "text", "", "Phrase to find in errors, events and logs (full-text index)")
 	searchCmd.Flags().StringVar(&searchTxFlag, "tx", "", "Transaction hash to search for")
+	searchCmd.Flags().StringArrayVar(&searchTagFlags, "tag", nil, "Only show sessions with this tag (repeatable)")
 	searchCmd.Flags().IntVar(&searchLimitFlag, "limit", 10, "Maximum number of results to return")
+	searchCmd.Flags().IntVar(&searchOffsetFlag, "offset", 0, "Number of matching results to skip, for paging")
+	searchCmd.Flags().StringVar(&searchSinceFlag, "since", "", "Only sessions created at or after this date, time or age (e.g. 2025-06-01 or 7d)")
+	searchCmd.Flags().StringVar(&searchUntilFlag, "until", "", "Only sessions created before this date, time or age")
+	searchCmd.Flags().StringVar(&searchSortFlag, "sort", "", "Sort by timestamp, network, error or contract (default: last access, newest first)")
+	searchCmd.Flags().BoolVar(&searchDescFlag, "desc", false, "Sort in descending order")
+	searchCmd.Flags().BoolVar(&searchCountFlag, "count-only", false, "Print the number of matching sessions instead of listing them")
 
 	rootCmd.AddCommand(searchCmd)
 }
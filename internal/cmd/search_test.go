@@ -0,0 +1,31 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchTime(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseSearchTime("2025-06-01", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local), got)
+
+	got, err = parseSearchTime("2025-06-01T15:04:05Z", now)
+	require.NoError(t, err)
+	assert.True(t, got.Equal(time.Date(2025, 6, 1, 15, 4, 5, 0, time.UTC)))
+
+	got, err = parseSearchTime("7d", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-7*24*time.Hour), got)
+
+	_, err = parseSearchTime("last tuesday", now)
+	assert.Error(t, err)
+}
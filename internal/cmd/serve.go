@@ -0,0 +1,219 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/server"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/telemetry"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	serveAddrFlag          string
+	serveGRPCAddrFlag      string
+	serveNetworkFlag       string
+	serveRPCURLFlag        string
+	serveAuthTokenFlag     string
+	serveMaxConcurrentFlag int
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Start a REST API for debugging transactions and browsing sessions",
+	Long: `Start an HTTP server exposing debugging and session history as a REST API,
+so dashboards and bots can trigger simulations and browse past results.
+
+Endpoints:
+  GET  /health         Liveness check
+  GET  /metrics        Prometheus metrics
+  POST /debug          Fetch and simulate a transaction, saving it as a session.
+                       Body: {"tx_hash": "...", "network": "testnet"}
+  GET  /sessions       List saved sessions. Query parameters: tx, error, event,
+                       text, tag (repeatable) and limit
+  GET  /sessions/{id}  Show a saved session with its simulation result
+
+With --grpc-addr the same operations are also served over gRPC as the
+erst.v1.DebugService defined in api/erst/v1/erst.proto, with a server-streaming
+StreamEvents call for a session's events and logs.
+
+When an auth token is set (--auth-token or ERST_SERVE_TOKEN), every endpoint
+except /health and /metrics requires "Authorization: Bearer <token>", sent as the
+authorization metadata key for gRPC.`,
+	Example: `  # Serve on localhost
+  erst serve --network testnet
+
+  # Listen on all interfaces with authentication
+  erst serve --addr :8090 --auth-token secret123
+
+  # Also serve gRPC
+  erst serve --grpc-addr 127.0.0.1:9090
+
+  # Debug a transaction through the API
+  curl -X POST localhost:8090/debug -d '{"tx_hash": "abc123..."}'`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch rpc.Network(serveNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+			return nil
+		default:
+			return errors.WrapInvalidNetwork(serveNetworkFlag)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		authToken := serveAuthTokenFlag
+		if authToken == "" {
+			authToken = os.Getenv("ERST_SERVE_TOKEN")
+		}
+
+		cleanup, err := initTracing(cmd.Context(), cmd, "erst-serve")
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		runner, err := simulator.NewRunner("", false)
+		if err != nil {
+			return errors.WrapSimulatorNotFound(err.Error())
+		}
+
+		store, err := session.NewStore()
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+		}
+		defer store.Close()
+		trackSessionStats(store)
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		if _, err := store.Prune(ctx, session.ConfiguredRetention()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to prune sessions: %v\n", err)
+		}
+
+		srv := server.New(server.Config{
+			Store:              store,
+			Debug:              serveDebugFunc(runner, store),
+			AuthToken:          authToken,
+			MaxConcurrentDebug: serveMaxConcurrentFlag,
+		})
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-sigChan
+			fmt.Println("\nReceived interrupt signal, shutting down...")
+			cancel()
+		}()
+
+		fmt.Printf("Starting ERST REST API on %s\n", serveAddrFlag)
+		if serveGRPCAddrFlag != "" {
+			fmt.Printf("Starting ERST gRPC API on %s\n", serveGRPCAddrFlag)
+		}
+		fmt.Printf("Default network: %s\n", serveNetworkFlag)
+		if authToken != "" {
+			fmt.Println("Authentication: enabled")
+		}
+
+		if serveGRPCAddrFlag == "" {
+			return srv.ListenAndServe(ctx, serveAddrFlag)
+		}
+
+		// Either server failing stops the other
+		errCh := make(chan error, 2)
+		go func() { errCh <- srv.ListenAndServe(ctx, serveAddrFlag) }()
+		go func() { errCh <- srv.ServeGRPC(ctx, serveGRPCAddrFlag) }()
+		err = <-errCh
+		cancel()
+		if err2 := <-errCh; err == nil {
+			err = err2
+		}
+		return err
+	},
+}
+
+// serveDebugFunc fetches and simulates a transaction for a debug request and
+// saves the result to store
+func serveDebugFunc(runner simulator.RunnerInterface, store session.Store) server.DebugFunc {
+	return func(ctx context.Context, req server.DebugRequest) (*session.SessionData, error) {
+		network := req.Network
+		if network == "" {
+			network = serveNetworkFlag
+		}
+
+		ctx, span := telemetry.GetTracer().Start(ctx, "debug_transaction")
+		span.SetAttributes(
+			attribute.String("transaction.hash", req.TxHash),
+			attribute.String("network", network),
+		)
+		defer span.End()
+		switch rpc.Network(network) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+		default:
+			return nil, errors.WrapInvalidNetwork(network)
+		}
+
+		token := resolveRPCToken(rpcTokenFlag, network)
+
+		opts := []rpc.ClientOption{
+			rpc.WithNetwork(rpc.Network(network)),
+			rpc.WithToken(token),
+		}
+		// A custom RPC URL is specific to the default network
+		rpcURL := ""
+		if network == serveNetworkFlag {
+			rpcURL = serveRPCURLFlag
+		}
+		opts = append(opts, rpcEndpointOptions(rpcURL, network)...)
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+		}
+
+		resp, err := client.GetTransaction(ctx, req.TxHash)
+		if err != nil {
+			if rpc.IsTransactionNotFound(err) {
+				return nil, err
+			}
+			return nil, errors.WrapRPCConnectionFailed(err)
+		}
+
+		simReq, simResp, err := simulateFetchedTransaction(ctx, client, runner, req.TxHash, resp)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+
+		data, err := newSimulatedSession(network, client.HorizonURL, req.TxHash, resp, simReq, simResp)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.Save(ctx, data); err != nil {
+			return nil, fmt.Errorf("failed to save session: %w", err)
+		}
+		return data, nil
+	}
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddrFlag, "addr", "127.0.0.1:8090", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveGRPCAddrFlag, "grpc-addr", "", "Also serve the gRPC API on this address")
+	serveCmd.Flags().StringVarP(&serveNetworkFlag, "network", "n", string(rpc.Mainnet), "Default Stellar network for debug requests (testnet, mainnet, futurenet, local)")
+	serveCmd.Flags().StringVar(&serveRPCURLFlag, "rpc-url", "", "Custom RPC URL(s) for the default network (comma-separated for failover)")
+	markSensitive(serveCmd, "rpc-url")
+	serveCmd.Flags().StringVar(&serveAuthTokenFlag, "auth-token", "", "Bearer token required for API access (or set ERST_SERVE_TOKEN)")
+	serveCmd.Flags().IntVar(&serveMaxConcurrentFlag, "max-concurrent", server.DefaultMaxConcurrentDebug, "Maximum simultaneous simulations")
+	addTracingFlags(serveCmd)
+
+	rootCmd.AddCommand(serveCmd)
+}
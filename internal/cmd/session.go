@@ -4,17 +4,26 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/snapshot"
 	"github.com/spf13/cobra"
 )
 
 var (
-	sessionIDFlag string
+	sessionIDFlag            string
+	sessionCompactFlag       bool
+	sessionArchiveOutputFlag string
 )
 
 // currentSessionData holds the active session context from debug command
@@ -31,8 +40,9 @@ func GetCurrentSession() *session.SessionData {
 }
 
 var sessionCmd = &cobra.Command{
-	Use:   "session",
-	Short: "Manage debugging sessions",
+	Use:     "session",
+	Aliases: []string{"sessions"},
+	Short:   "Manage debugging sessions",
 	Long: `Save, resume, and manage debugging sessions to preserve state across CLI invocations.
 
 Sessions store complete transaction data, simulation results, and analysis context,
@@ -46,7 +56,8 @@ Available subcommands:
   save    - Save current session to disk
   resume  - Restore a saved session
   list    - View all saved sessions
-  delete  - Remove a saved session`,
+  delete  - Remove saved sessions (alias: rm)
+  archive - Move a saved session into a bundle file`,
 	Example: `  # Save current debug session
   erst session save
 
@@ -57,7 +68,10 @@ Available subcommands:
   erst session resume <session-id>
 
   # Delete a session
-  erst session delete <session-id>`,
+  erst session delete <session-id>
+
+  # Archive a session to a bundle and remove it from the history
+  erst sessions archive <session-id> -o old.erst`,
 }
 
 var sessionSaveCmd = &cobra.Command{
@@ -100,7 +114,7 @@ The session ID can be auto-generated or specified with --id flag.`,
 		defer store.Close()
 
 		// Run cleanup before save
-		if err := store.Cleanup(ctx, session.DefaultTTL, session.DefaultMaxSessions); err != nil {
+		if _, err := store.Prune(ctx, session.ConfiguredRetention()); err != nil {
 			// Log but don't fail on cleanup errors
 			fmt.Fprintf(os.Stderr, "Warning: cleanup failed: %v\n", err)
 		}
@@ -145,7 +159,7 @@ Use 'erst session list' to see available sessions.`,
 		defer store.Close()
 
 		// Run cleanup
-		if err := store.Cleanup(ctx, session.DefaultTTL, session.DefaultMaxSessions); err != nil {
+		if _, err := store.Prune(ctx, session.ConfiguredRetention()); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: session cleanup failed: %v\n", err)
 		}
 
@@ -219,7 +233,7 @@ Displays session ID, network, last access time, and transaction hash.`,
 		defer store.Close()
 
 		// Run cleanup
-		if err := store.Cleanup(ctx, session.DefaultTTL, session.DefaultMaxSessions); err != nil {
+		if _, err := store.Prune(ctx, session.ConfiguredRetention()); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: session cleanup failed: %v\n", err)
 		}
 
@@ -229,6 +243,13 @@ Displays session ID, network, last access time, and transaction hash.`,
 			return errors.WrapValidationError(fmt.Sprintf("failed to list sessions: %v", err))
 		}
 
+		if jsonOutput() {
+			if sessions == nil {
+				sessions = []*session.SessionData{}
+			}
+			return printJSON(sessions)
+		}
+
 		if len(sessions) == 0 {
 			fmt.Println("No saved sessions found.")
 			return nil
@@ -252,17 +273,26 @@ Displays session ID, network, last access time, and transaction hash.`,
 }
 
 var sessionDeleteCmd = &cobra.Command{
-	Use:   "delete <session-id>",
-	Short: "Remove a saved debugging session",
-	Long: `Delete a saved debug session by ID. This action cannot be undone.
+	Use:     "delete <session-id|tx-hash>...",
+	Aliases: []string{"rm", "remove"},
+	Short:   "Remove saved debugging sessions",
+	Long: `Delete saved debug sessions by ID or transaction hash, along with their tags,
+notes and search index entries. This action cannot be undone.
+
+Deleted sessions leave their pages in the SQLite file until it is compacted,
+and in the <db>.v<N>.bak copies made before schema migrations; use --compact
+when removing sensitive data to compact the file and delete those copies, so
+nothing of it is left on disk.
 
 Use 'erst session list' to see available sessions.`,
 	Example: `  # Delete a specific session
-  erst session delete abc123`,
-	Args: cobra.ExactArgs(1),
+  erst session delete abc123
+
+  # Remove several sessions and compact the database
+  erst sessions rm abc123 def456 --compact`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
-		sessionID := args[0]
 
 		// Open session store
 		store, err := session.NewStore()
@@ -271,23 +301,181 @@ Use 'erst session list' to see available sessions.`,
 		}
 		defer store.Close()
 
-		// Delete session
-		if err := store.Delete(ctx, sessionID); err != nil {
-			return errors.WrapValidationError(fmt.Sprintf("failed to delete session '%s': %v", sessionID, err))
+		var failed []string
+		for _, ref := range args {
+			if err := deleteSession(ctx, store, ref); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				failed = append(failed, ref)
+			}
+		}
+		if err := compactSessions(ctx, store, len(failed) < len(args)); err != nil {
+			return err
+		}
+		if len(failed) > 0 {
+			return errors.WrapValidationError(fmt.Sprintf("failed to delete %d of %d sessions: %s", len(failed), len(args), strings.Join(failed, ", ")))
 		}
-
-		fmt.Printf("Session deleted: %s\n", sessionID)
 		return nil
 	},
 }
 
+var sessionArchiveCmd = &cobra.Command{
+	Use:   "archive <session-id|tx-hash>",
+	Short: "Move a saved session out of the history into a bundle",
+	Long: `Write a saved session to a portable bundle, as 'erst export' does, then delete
+it from the session history. The bundle is written before anything is
+deleted, so a failed write leaves the session in place. Load it again with
+'erst import'.
+
+The bundle is named <session-id>.erst unless -o is given.`,
+	Example: `  # Archive a session
+  erst sessions archive abc123 -o incident-42.erst
+
+  # Bring it back later
+  erst import incident-42.erst`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		store, err := session.NewStore()
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+		}
+		defer store.Close()
+
+		data, err := findSession(ctx, store, args[0])
+		if err != nil {
+			return err
+		}
+		path := sessionArchiveOutputFlag
+		if path == "" {
+			path = data.ID + session.BundleExtension
+		}
+		if err := exportBundle(path, data); err != nil {
+			return err
+		}
+		if err := store.Delete(ctx, data.ID); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("session exported to %s but not deleted: %v", path, err))
+		}
+		fmt.Printf("Session archived: %s -> %s\n", data.ID, path)
+		return compactSessions(ctx, store, true)
+	},
+}
+
+// deleteSession removes the session ref names, by ID or transaction hash
+func deleteSession(ctx context.Context, store session.Store, ref string) error {
+	data, err := findSession(ctx, store, ref)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(ctx, data.ID); err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to delete session '%s': %v", data.ID, err))
+	}
+	fmt.Printf("Session deleted: %s\n", data.ID)
+	return nil
+}
+
+// compactSessions compacts the session database after sessions were removed,
+// when --compact is set, and deletes the pre-migration backups that would
+// otherwise keep them
+func compactSessions(ctx context.Context, store session.Store, removed bool) error {
+	if !sessionCompactFlag || !removed {
+		return nil
+	}
+	if err := store.Compact(ctx); err != nil {
+		return errors.WrapValidationError(err.Error())
+	}
+	statusf("Session database compacted\n")
+	backups, err := store.RemoveBackups(ctx)
+	for _, backup := range backups {
+		statusf("Removed pre-migration backup %s\n", backup)
+	}
+	if err != nil {
+		return errors.WrapValidationError(err.Error())
+	}
+	return nil
+}
+
+// sessionLookupLimit bounds how many recent sessions are searched when a
+// session is referenced by transaction hash
+const sessionLookupLimit = 200
+
+// findSession loads a saved session by ID, falling back to the most recent
+// session recorded for a transaction with that hash
+func findSession(ctx context.Context, store session.Store, ref string) (*session.SessionData, error) {
+	if data, err := store.Load(ctx, ref); err == nil {
+		return data, nil
+	}
+	sessions, err := store.List(ctx, sessionLookupLimit)
+	if err != nil {
+		return nil, errors.WrapSessionNotFound(ref)
+	}
+	for _, s := range sessions {
+		if s.TxHash == ref {
+			return s, nil
+		}
+	}
+	return nil, errors.WrapSessionNotFound(ref)
+}
+
+// newSimulatedSession builds a saved session for a fetched and simulated
+// transaction
+func newSimulatedSession(network, horizonURL, txHash string, tx *rpc.TransactionResponse, simReq *simulator.SimulationRequest, simResp *simulator.SimulationResponse) (*session.SessionData, error) {
+	simReqJSON, err := json.Marshal(simReq)
+	if err != nil {
+		return nil, errors.WrapMarshalFailed(err)
+	}
+	simRespJSON, err := json.Marshal(simResp)
+	if err != nil {
+		return nil, errors.WrapMarshalFailed(err)
+	}
+
+	pinSnapshot(simResp, simReq)
+
+	now := time.Now()
+	return &session.SessionData{
+		ID:              session.GenerateID(txHash),
+		CreatedAt:       now,
+		LastAccessAt:    now,
+		Status:          "saved",
+		Network:         network,
+		HorizonURL:      horizonURL,
+		TxHash:          txHash,
+		EnvelopeXdr:     tx.EnvelopeXdr,
+		ResultXdr:       tx.ResultXdr,
+		ResultMetaXdr:   tx.ResultMetaXdr,
+		SimRequestJSON:  string(simReqJSON),
+		SimResponseJSON: string(simRespJSON),
+		ErstVersion:     Version,
+		SchemaVersion:   session.SchemaVersion,
+	}, nil
+}
+
+// pinSnapshot stores the ledger state a simulation ran against under its pin
+// ID, so replays of the session can reuse it after the network has moved on
+func pinSnapshot(simResp *simulator.SimulationResponse, simReq *simulator.SimulationRequest) {
+	if simResp == nil || simResp.Snapshot == nil || simReq == nil {
+		return
+	}
+	cache, err := snapshot.NewDefaultCache()
+	if err == nil {
+		err = cache.PutPinned(simResp.Snapshot, simReq.LedgerEntries)
+	}
+	if err != nil {
+		logger.Logger.Warn("Failed to store the pinned snapshot", "snapshot", simResp.Snapshot.ID, "error", err)
+	}
+}
+
 func init() {
 	sessionSaveCmd.Flags().StringVar(&sessionIDFlag, "id", "", "Custom session ID (default: auto-generated)")
+	sessionDeleteCmd.Flags().BoolVar(&sessionCompactFlag, "compact", false, "Compact the session database and delete its pre-migration backups afterwards so no trace of the sessions is left")
+	sessionArchiveCmd.Flags().BoolVar(&sessionCompactFlag, "compact", false, "Compact the session database and delete its pre-migration backups afterwards so no trace of the session is left")
+	sessionArchiveCmd.Flags().StringVarP(&sessionArchiveOutputFlag, "output", "o", "", "Bundle file to write (default: <session-id>.erst)")
 
 	sessionCmd.AddCommand(sessionSaveCmd)
 	sessionCmd.AddCommand(sessionResumeCmd)
 	sessionCmd.AddCommand(sessionListCmd)
 	sessionCmd.AddCommand(sessionDeleteCmd)
+	sessionCmd.AddCommand(sessionArchiveCmd)
 
 	rootCmd.AddCommand(sessionCmd)
 }
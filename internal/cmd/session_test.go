@@ -0,0 +1,78 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedSessions(t *testing.T, ids ...string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	store, err := session.NewStore()
+	require.NoError(t, err)
+	defer store.Close()
+	for _, id := range ids {
+		require.NoError(t, store.Save(context.Background(), &session.SessionData{ID: id, Status: "saved", Network: "testnet", TxHash: "tx-" + id}))
+	}
+}
+
+func storedSessions(t *testing.T) []string {
+	t.Helper()
+	store, err := session.NewStore()
+	require.NoError(t, err)
+	defer store.Close()
+	sessions, err := store.List(context.Background(), 10)
+	require.NoError(t, err)
+	var ids []string
+	for _, s := range sessions {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func TestSessionDelete_Several(t *testing.T) {
+	seedSessions(t, "a", "b", "c")
+	sessionCompactFlag = true
+	t.Cleanup(func() { sessionCompactFlag = false })
+
+	// By ID and by transaction hash; an unknown session fails after the rest
+	// are deleted
+	sessionDeleteCmd.SetContext(context.Background())
+	err := sessionDeleteCmd.RunE(sessionDeleteCmd, []string{"a", "tx-b", "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing")
+	assert.Equal(t, []string{"c"}, storedSessions(t))
+}
+
+func TestSessionArchive(t *testing.T) {
+	seedSessions(t, "a", "b")
+	path := filepath.Join(t.TempDir(), "a.erst")
+	sessionArchiveOutputFlag = path
+	t.Cleanup(func() { sessionArchiveOutputFlag = "" })
+
+	sessionArchiveCmd.SetContext(context.Background())
+	require.NoError(t, sessionArchiveCmd.RunE(sessionArchiveCmd, []string{"a"}))
+	assert.Equal(t, []string{"b"}, storedSessions(t))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	data, err := session.ImportBundle(f)
+	require.NoError(t, err)
+	assert.Equal(t, "a", data.ID)
+	assert.Equal(t, "tx-a", data.TxHash)
+
+	// A bundle that cannot be written leaves the session in place
+	sessionArchiveOutputFlag = filepath.Join(t.TempDir(), "missing", "b.erst")
+	require.Error(t, sessionArchiveCmd.RunE(sessionArchiveCmd, []string{"b"}))
+	assert.Equal(t, []string{"b"}, storedSessions(t))
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	shareEndpointFlag  string
+	shareKeepNotesFlag bool
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share <session-id>",
+	Short: "Upload a redacted session bundle and print a link to it",
+	Long: `Upload a saved session as a bundle to a storage endpoint, so a teammate can
+load it with 'erst import --from-url <link>'.
+
+The bundle is uploaded with an HTTP PUT to <endpoint>/<session-id>.erst. The
+endpoint is --endpoint, share_url in the config or ERST_SHARE_URL; share_token
+or ERST_SHARE_TOKEN is sent as a bearer token. Paste services that answer
+with the link, as plain text, JSON {"url": ...} or a Location header, are
+supported as well as plain object storage, where the upload URL is the link.
+
+Before upload the session is redacted: notes are dropped unless --keep-notes
+is set, the RPC URL is reduced to its host, and local paths are removed from
+the simulator request and response. Transaction data itself is public
+on-chain and is kept.`,
+	Example: `  erst share abc12345-1700000000 --endpoint https://files.example.com/erst
+  erst import --from-url https://files.example.com/erst/abc12345-1700000000.erst`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		endpoint, token := shareEndpoint()
+		if endpoint == "" {
+			return errors.WrapValidationError("no share endpoint configured; pass --endpoint or set share_url (ERST_SHARE_URL)")
+		}
+
+		store, err := session.NewStore()
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+		}
+		defer store.Close()
+		data, err := findSession(ctx, store, args[0])
+		if err != nil {
+			return err
+		}
+
+		client, err := shareHTTPClient()
+		if err != nil {
+			return err
+		}
+		link, err := session.Share(ctx, client, endpoint, token, session.Redact(data, shareKeepNotesFlag))
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to share session %s: %v", data.ID, err))
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]string{"id": data.ID, "url": link})
+		}
+		fmt.Printf("Session %s shared: %s\n", data.ID, link)
+		fmt.Printf("\nImport it with: erst import --from-url %s\n", link)
+		return nil
+	},
+}
+
+// shareEndpoint returns the storage endpoint and token for session links,
+// from --endpoint or the share_url and share_token settings
+func shareEndpoint() (string, string) {
+	endpoint := shareEndpointFlag
+	var token string
+	if cfg, err := config.Load(); err == nil {
+		if endpoint == "" {
+			endpoint = cfg.ShareURL
+		}
+		token = cfg.ShareToken
+	}
+	return endpoint, token
+}
+
+// shareTokenFor returns the share token when link is under the configured
+// endpoint, so the token is never sent to another host
+func shareTokenFor(endpoint, token, link string) string {
+	if endpoint == "" || !strings.HasPrefix(link, strings.TrimRight(endpoint, "/")+"/") {
+		return ""
+	}
+	return token
+}
+
+func shareHTTPClient() (*http.Client, error) {
+	proxy, err := rpc.NewProxyFunc(proxyFlag)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: rpc.NewProxyTransport(proxy), Timeout: 2 * time.Minute}, nil
+}
+
+func init() {
+	shareCmd.Flags().StringVar(&shareEndpointFlag, "endpoint", "", "Storage endpoint to upload to (default: share_url from config or ERST_SHARE_URL)")
+	shareCmd.Flags().BoolVar(&shareKeepNotesFlag, "keep-notes", false, "Include the session's notes in the bundle")
+
+	rootCmd.AddCommand(shareCmd)
+}
@@ -0,0 +1,425 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dotandev/hintents/internal/compare"
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/heuristic"
+	"github.com/dotandev/hintents/internal/hooks"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/report"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/dotandev/hintents/internal/telemetry"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	simEnvelopeFlag string
+	simNetworkFlag  string
+	simRPCURLFlag   string
+	simRPCTokenFlag string
+	simAtLedgerFlag uint32
+	simSnapshotFlag string
+	simCheckFlag    bool
+	simTimeoutFlag  time.Duration
+)
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Simulate a transaction envelope that has not been submitted",
+	Long: `Run the debug pipeline on a locally built transaction envelope, before it
+is submitted. The envelope is read from --envelope, or from stdin when
+--envelope is "-" or omitted, as base64 or raw XDR.
+
+Ledger state for the envelope's footprint is fetched from RPC at the latest
+ledger, so the result reflects what would happen if the transaction were
+submitted now. Ledger overrides can be applied as with erst debug.
+
+--at-ledger instead simulates the envelope as if it were included in an
+earlier ledger: entries changed since are rewound to their value at the
+start of that ledger using the transaction history held by RPC, so only
+ledgers within the node's retention window can be used.
+
+--snapshot takes the footprint from a snapshot file, such as one written by
+'erst snapshot export', instead of RPC, at the snapshot's ledger when it
+records one. Footprint entries missing from the file are treated as absent.`,
+	Example: `  erst simulate --envelope tx.xdr --network testnet
+  stellar tx new ... --build-only | erst simulate -n testnet
+  erst simulate --envelope tx.xdr --override-entry key.xdr=entry.xdr --output json
+  erst simulate --envelope tx.xdr --at-ledger 51234567 -n testnet
+  erst simulate --envelope tx.xdr --snapshot state.json -n testnet`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch rpc.Network(simNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+		default:
+			return errors.WrapInvalidNetwork(simNetworkFlag)
+		}
+		if protocolVersionFlag > 0 {
+			if err := simulator.Validate(protocolVersionFlag); err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("invalid protocol version %d: %v", protocolVersionFlag, err))
+			}
+		}
+		if err := validateHostVersion(); err != nil {
+			return err
+		}
+		if err := validateBackend(cmd); err != nil {
+			return err
+		}
+		if simSnapshotFlag != "" && simAtLedgerFlag > 0 {
+			return errors.WrapValidationError("--snapshot and --at-ledger cannot be used together")
+		}
+
+		overrides, err := loadLedgerOverrides()
+		if err != nil {
+			return err
+		}
+		ledgerOverrides = overrides
+		return nil
+	},
+	RunE: runSimulate,
+}
+
+func runSimulate(cmd *cobra.Command, args []string) error {
+	if simEnvelopeFlag == "" && cmd.InOrStdin() == os.Stdin {
+		if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			return errors.WrapCliArgumentRequired("envelope")
+		}
+	}
+	envelopeXdr, envelope, err := readEnvelope(simEnvelopeFlag, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	cleanup, err := initTracing(ctx, cmd, "erst")
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	tracer := telemetry.GetTracer()
+	ctx, span := tracer.Start(ctx, "simulate_envelope")
+	span.SetAttributes(attribute.String("network", simNetworkFlag))
+	defer span.End()
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(simNetworkFlag)),
+		rpc.WithToken(resolveRPCToken(simRPCTokenFlag, simNetworkFlag)),
+	}
+	opts = append(opts, rpcEndpointOptions(simRPCURLFlag, simNetworkFlag)...)
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+
+	if err := checkNetworkProtocol(ctx, client, simNetworkFlag, protocolVersionFlag); err != nil {
+		return err
+	}
+
+	// The hash the transaction will have once submitted identifies the session
+	hash, err := network.HashTransactionInEnvelope(envelope, client.GetNetworkPassphrase())
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to hash transaction: %v", err))
+	}
+	txHash := hex.EncodeToString(hash[:])
+	span.SetAttributes(attribute.String("transaction.hash", txHash))
+
+	statusf("Simulating envelope: %s\n", txHash)
+	statusf("Network: %s\n", simNetworkFlag)
+
+	_, decodeSpan := tracer.Start(ctx, "decode_transaction")
+	invocations, err := describeInvocations(ctx, client, envelopeXdr)
+	if err != nil {
+		decodeSpan.RecordError(err)
+		logger.Logger.Warn("Failed to decode contract invocations", "error", err)
+	}
+	decodeSpan.End()
+	operations := describeOperations(envelopeXdr, "")
+	feeBump := decoder.DescribeFeeBump(envelopeXdr, client.GetNetworkPassphrase())
+	if textOutput() {
+		printFeeBump(feeBump)
+		printOperations(operations)
+		printInvocations(invocations)
+	}
+
+	runner, err := newSimRunner(ctx, client)
+	if err != nil {
+		return err
+	}
+	simReq, simResp, err := simulateEnvelope(ctx, client, runner, envelopeXdr)
+	if err != nil {
+		return err
+	}
+	printSimulationResult(ctx, simNetworkFlag, simResp)
+
+	var remoteDiff *compare.RemoteDiff
+	if compareRemoteFlag {
+		remoteDiff = remoteComparison(ctx, client, envelopeXdr, simResp)
+	}
+
+	_, analyzeSpan := tracer.Start(ctx, "analyze_results")
+	suggestions := heuristic.Suggestions(heuristicInput(txHash, simNetworkFlag, simResp), decodedCallTree(simResp))
+	findings := security.NewDetector().Analyze(envelopeXdr, "", simResp.Events, simResp.Logs)
+	analyzeSpan.End()
+
+	hookResult, err := runPostSimulationHooks(ctx, hooks.Input{
+		Command:     "simulate",
+		Network:     simNetworkFlag,
+		TxHash:      txHash,
+		EnvelopeXdr: envelopeXdr,
+		Invocations: invocations,
+		Simulation:  simResp,
+	})
+	if err != nil {
+		return err
+	}
+
+	sessionData, err := newSimulatedSession(simNetworkFlag, client.HorizonURL, txHash, &rpc.TransactionResponse{EnvelopeXdr: envelopeXdr}, simReq, simResp)
+	if err != nil {
+		return err
+	}
+	sessionData.Status = "active"
+	SetCurrentSession(sessionData)
+
+	switch {
+	case jsonOutput():
+		err = printJSON(DebugOutput{
+			TxHash:           txHash,
+			Network:          simNetworkFlag,
+			Invocations:      invocations,
+			FeeBump:          feeBump,
+			Operations:       operations,
+			Simulation:       simResp,
+			Suggestions:      suggestions,
+			SecurityFindings: findings,
+			SessionID:        sessionData.ID,
+			Annotations:      hookAnnotations(hookResult),
+			RemoteComparison: remoteDiff,
+		})
+	case OutputFlag == OutputSARIF:
+		err = printSARIF(&report.DebugReport{
+			TxHash:      txHash,
+			Network:     simNetworkFlag,
+			EnvelopeXdr: envelopeXdr,
+			Invocations: invocations,
+			Simulation:  simResp,
+			Operations:  operations,
+			Suggestions: suggestions,
+		})
+	default:
+		if len(suggestions) > 0 {
+			fmt.Print(decoder.FormatSuggestions(suggestions))
+		}
+		printSecurityFindings(findings)
+		fmt.Printf("\nSession created: %s\n", sessionData.ID)
+	}
+	printHookResult(hookResult)
+	if err != nil {
+		return err
+	}
+	if err := hookCheck(hookResult); err != nil || !simCheckFlag {
+		return err
+	}
+	return checkSimulations(simResp)
+}
+
+// simulateEnvelope replays an unsubmitted envelope at the next ledger, using
+// the current state of its footprint, at --at-ledger using the state at the
+// start of that ledger, or against the state in --snapshot
+func simulateEnvelope(ctx context.Context, client *rpc.Client, runner simulator.RunnerInterface, envelopeXdr string) (*simulator.SimulationRequest, *simulator.SimulationResponse, error) {
+	return simulateEnvelopeWith(ctx, client, runner, envelopeXdr, statusf)
+}
+
+// simulateEnvelopeWith is simulateEnvelope reporting its progress through
+// status, for callers such as erst canary that print results of their own
+func simulateEnvelopeWith(ctx context.Context, client *rpc.Client, runner simulator.RunnerInterface, envelopeXdr string, status func(format string, a ...interface{})) (*simulator.SimulationRequest, *simulator.SimulationResponse, error) {
+	keys, err := rpc.FootprintKeys(envelopeXdr)
+	if err != nil {
+		return nil, nil, err
+	}
+	var snap *snapshot.Snapshot
+	ledgerEntries := map[string]string{}
+	if simSnapshotFlag != "" {
+		snap, err = snapshot.Load(simSnapshotFlag)
+		if err != nil {
+			return nil, nil, errors.WrapValidationError(err.Error())
+		}
+		all := snap.ToMap()
+		for _, k := range keys {
+			if v, ok := all[k]; ok {
+				ledgerEntries[k] = v
+			}
+		}
+	} else if len(keys) > 0 {
+		if simAtLedgerFlag > 0 {
+			ledgerEntries, err = client.GetLedgerEntriesAt(ctx, keys, simAtLedgerFlag)
+			if errors.Is(err, errors.ErrLedgerArchived) {
+				return nil, nil, err
+			}
+		} else {
+			ledgerEntries, err = client.GetLedgerEntries(ctx, keys)
+		}
+		if err != nil {
+			return nil, nil, errors.WrapRPCConnectionFailed(err)
+		}
+	}
+	switch {
+	case snap != nil:
+		status("Loaded %d of %d footprint ledger entries from %s\n", len(ledgerEntries), len(keys), simSnapshotFlag)
+	case simAtLedgerFlag > 0:
+		status("Fetched %d of %d footprint ledger entries as of ledger %d\n", len(ledgerEntries), len(keys), simAtLedgerFlag)
+	default:
+		status("Fetched %d of %d footprint ledger entries\n", len(ledgerEntries), len(keys))
+	}
+
+	simReq := &simulator.SimulationRequest{
+		EnvelopeXdr:          envelopeXdr,
+		LedgerEntries:        ledgerEntries,
+		LedgerEntryOverrides: ledgerOverrides,
+		Timestamp:            TimestampFlag,
+	}
+	if simAtLedgerFlag > 0 {
+		simReq.LedgerSequence = simAtLedgerFlag
+		if simReq.Timestamp == 0 {
+			if header, err := client.GetLedgerHeader(ctx, simAtLedgerFlag); err == nil {
+				simReq.Timestamp = header.CloseTime.Unix()
+			} else {
+				logger.Logger.Warn("Failed to read the ledger close time", "ledger", simAtLedgerFlag, "error", err)
+			}
+		}
+	} else if snap != nil && snap.LedgerSequence > 0 {
+		simReq.LedgerSequence = snap.LedgerSequence
+	} else if health, err := client.GetHealth(ctx); err == nil {
+		simReq.LedgerSequence = health.Result.LatestLedger + 1
+	} else {
+		logger.Logger.Warn("Failed to read the latest ledger", "error", err)
+	}
+	if protocolVersionFlag > 0 {
+		simReq.ProtocolVersion = &protocolVersionFlag
+	}
+	applyBudgetLimits(simReq)
+	if cpuLimitFlag > 0 || memLimitFlag > 0 {
+		status("Using budget limits: %s\n", budgetLimitsSummary())
+	}
+	if len(ledgerOverrides) > 0 {
+		status("Applying %d ledger entry overrides\n", len(ledgerOverrides))
+	}
+
+	simResp, err := simulator.RunTraced(ctx, runner, simReq)
+	if err != nil {
+		return nil, nil, errors.WrapSimulationFailed(err, "")
+	}
+	analyzeArchival(ctx, client, simReq, simResp)
+	nameContractError(ctx, client, simReq.EnvelopeXdr, simResp)
+	return simReq, simResp, nil
+}
+
+// readEnvelope reads a transaction envelope from path, or from stdin when path
+// is "" or "-". Base64 and raw binary XDR are both accepted; the envelope is
+// returned as base64 along with its decoded form.
+func readEnvelope(path string, stdin io.Reader) (string, xdr.TransactionEnvelope, error) {
+	var envelope xdr.TransactionEnvelope
+
+	var data []byte
+	var err error
+	if path == "" || path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", envelope, errors.WrapValidationError(fmt.Sprintf("failed to read envelope: %v", err))
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		raw = data
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return "", envelope, errors.WrapValidationError("envelope is empty")
+	}
+	if err := xdr.SafeUnmarshal(raw, &envelope); err != nil {
+		return "", envelope, errors.WrapUnmarshalFailed(err, "TransactionEnvelope")
+	}
+	return base64.StdEncoding.EncodeToString(raw), envelope, nil
+}
+
+// simulatedTx is a transaction simulated from its hash or from an envelope
+type simulatedTx struct {
+	TxHash      string
+	EnvelopeXdr string
+	Tx          *rpc.TransactionResponse // nil for an unsubmitted envelope
+	Req         *simulator.SimulationRequest
+	Resp        *simulator.SimulationResponse
+}
+
+// simulateTxOrEnvelope simulates arg, the hash of an on-chain transaction or
+// a file holding an envelope ("-" for stdin)
+func simulateTxOrEnvelope(ctx context.Context, stdin io.Reader, client *rpc.Client, runner simulator.RunnerInterface, arg string) (*simulatedTx, error) {
+	var err error
+	out := &simulatedTx{}
+	if _, statErr := os.Stat(arg); arg != "-" && statErr != nil && rpc.ValidateTransactionHash(arg) == nil {
+		out.TxHash = arg
+		statusf("Fetching transaction: %s\n", arg)
+		out.Tx, err = client.GetTransaction(ctx, arg)
+		if err != nil {
+			return nil, errors.WrapRPCConnectionFailed(err)
+		}
+		out.EnvelopeXdr = out.Tx.EnvelopeXdr
+		out.Req, out.Resp, err = simulateFetchedTransaction(ctx, client, runner, arg, out.Tx)
+	} else {
+		out.EnvelopeXdr, _, err = readEnvelope(arg, stdin)
+		if err != nil {
+			return nil, err
+		}
+		out.Req, out.Resp, err = simulateEnvelope(ctx, client, runner, out.EnvelopeXdr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func init() {
+	simulateCmd.Flags().StringVar(&simEnvelopeFlag, "envelope", "", `File with the TransactionEnvelope XDR, or "-" for stdin (default stdin)`)
+	simulateCmd.Flags().StringVarP(&simNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to simulate against (testnet, mainnet, futurenet, local)")
+	simulateCmd.Flags().StringVar(&simRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(simulateCmd, "rpc-url")
+	simulateCmd.Flags().StringVar(&simRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	simulateCmd.Flags().Uint32Var(&simAtLedgerFlag, "at-ledger", 0, "Simulate against the ledger state at the start of this ledger sequence instead of the latest")
+	simulateCmd.Flags().StringVar(&simSnapshotFlag, "snapshot", "", "Take the footprint's ledger state from this snapshot file instead of RPC")
+	simulateCmd.Flags().Uint32Var(&protocolVersionFlag, "protocol-version", 0, "Override protocol version for simulation (20, 21, 22, etc)")
+	simulateCmd.Flags().Uint64Var(&cpuLimitFlag, "cpu-limit", 0, "CPU instruction budget for the simulation (default: the host's)")
+	simulateCmd.Flags().Uint64Var(&memLimitFlag, "mem-limit", 0, "Memory budget for the simulation, in bytes (default: the host's)")
+	simulateCmd.Flags().StringVar(&backendFlag, "backend", backendLocal, "Simulation backend: local (erst-sim) or rpc (the RPC node's simulateTransaction)")
+	simulateCmd.Flags().Uint32Var(&hostVersionFlag, "host-version", 0, "Simulate with the erst-sim build for this soroban-env-host version ("+simulator.HostVersionList()+"), installing it if needed")
+	simulateCmd.Flags().StringArrayVar(&overrideEntryFlags, "override-entry", nil, "Override a ledger entry before simulation as <ledger-key-xdr>=<entry-file>, or <entry-file> to derive the key (repeatable)")
+	simulateCmd.Flags().StringVar(&overrideStateFlag, "override-state", "", "JSON file of ledger entries to override ({\"ledger_entries\": {key: entry}})")
+	simulateCmd.Flags().BoolVar(&simCheckFlag, "check", false, "Exit with code 2 when the simulation fails, 3 on RPC errors and 4 on simulator errors")
+	addHookFlags(simulateCmd)
+	simulateCmd.Flags().BoolVar(&compareRemoteFlag, "compare-remote", false, "Also simulate with the RPC node's simulateTransaction and report where it diverges from the local result")
+	simulateCmd.Flags().DurationVar(&simTimeoutFlag, "timeout", 0, "Abort the ledger fetch and simulation after this long (e.g. 2m; 0 for no limit)")
+	simulateCmd.RunE = withTimeout(&simTimeoutFlag, simulateCmd.RunE)
+	addTracingFlags(simulateCmd)
+
+	rootCmd.AddCommand(simulateCmd)
+}
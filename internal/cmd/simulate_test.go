@@ -0,0 +1,104 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadEnvelope(t *testing.T) {
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"),
+				Fee:           100,
+			},
+		},
+	}
+	envelopeXdr, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+	raw, err := envelope.MarshalBinary()
+	require.NoError(t, err)
+
+	// Base64 from a file, with surrounding whitespace
+	path := filepath.Join(t.TempDir(), "tx.xdr")
+	require.NoError(t, os.WriteFile(path, []byte("\n"+envelopeXdr+"\n"), 0o644))
+	got, decoded, err := readEnvelope(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, envelopeXdr, got)
+	assert.Equal(t, xdr.Uint32(100), decoded.V1.Tx.Fee)
+
+	// Raw XDR from stdin
+	got, _, err = readEnvelope("-", bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, envelopeXdr, got)
+
+	_, _, err = readEnvelope("", strings.NewReader("  \n"))
+	assert.Error(t, err)
+
+	_, _, err = readEnvelope("", strings.NewReader("not an envelope"))
+	assert.Error(t, err)
+
+	_, _, err = readEnvelope(filepath.Join(t.TempDir(), "missing.xdr"), nil)
+	assert.Error(t, err)
+}
+
+func TestValidateHostVersion(t *testing.T) {
+	defer func() { hostVersionFlag, protocolVersionFlag = 0, 0 }()
+
+	// The host's protocol is the default
+	hostVersionFlag, protocolVersionFlag = 21, 0
+	require.NoError(t, validateHostVersion())
+	assert.Equal(t, uint32(21), protocolVersionFlag)
+
+	hostVersionFlag, protocolVersionFlag = 21, 22
+	assert.Error(t, validateHostVersion())
+
+	hostVersionFlag, protocolVersionFlag = 20, 0
+	assert.Error(t, validateHostVersion())
+}
+
+func TestValidateBackend(t *testing.T) {
+	defer func() { backendFlag = backendLocal }()
+	cmd := &cobra.Command{}
+	cmd.Flags().Uint32Var(new(uint32), "at-ledger", 0, "")
+
+	backendFlag = backendRPC
+	require.NoError(t, validateBackend(cmd))
+
+	require.NoError(t, cmd.Flags().Set("at-ledger", "10"))
+	err := validateBackend(cmd)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--at-ledger")
+
+	backendFlag = "wasmi"
+	assert.Error(t, validateBackend(cmd))
+}
+
+func TestApplyBudgetLimits(t *testing.T) {
+	defer func() { cpuLimitFlag, memLimitFlag = 0, 0 }()
+
+	req := &simulator.SimulationRequest{}
+	applyBudgetLimits(req)
+	assert.Nil(t, req.CPULimit)
+	assert.Nil(t, req.MemoryLimit)
+
+	cpuLimitFlag = 250_000_000
+	applyBudgetLimits(req)
+	require.NotNil(t, req.CPULimit)
+	assert.Equal(t, uint64(250_000_000), *req.CPULimit)
+	assert.Nil(t, req.MemoryLimit)
+	assert.Equal(t, "CPU 250000000, memory default", budgetLimitsSummary())
+}
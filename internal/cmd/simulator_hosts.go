@@ -0,0 +1,144 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+// hostVersionFlag holds --host-version, shared by debug and simulate
+var hostVersionFlag uint32
+
+// validateHostVersion checks --host-version against --protocol-version and
+// makes the host's protocol the default one, as the host cannot simulate
+// rules newer than its own
+func validateHostVersion() error {
+	if hostVersionFlag == 0 {
+		return nil
+	}
+	if err := simulator.ValidateHostVersion(hostVersionFlag); err != nil {
+		return err
+	}
+	if protocolVersionFlag > hostVersionFlag {
+		return errors.WrapValidationError(fmt.Sprintf("--protocol-version %d is newer than --host-version %d", protocolVersionFlag, hostVersionFlag))
+	}
+	if protocolVersionFlag == 0 {
+		protocolVersionFlag = hostVersionFlag
+	}
+	return nil
+}
+
+// hostSimPath returns the erst-sim build for --host-version, installing it
+// when it is missing, or "" to use the default simulator
+func hostSimPath(ctx context.Context) (string, error) {
+	if hostVersionFlag == 0 {
+		return "", nil
+	}
+	if path, err := simulator.FindHostBinary(hostVersionFlag); err == nil {
+		return path, nil
+	}
+	statusf("Installing erst-sim for soroban-env-host %d\n", hostVersionFlag)
+	return installHost(ctx, hostVersionFlag)
+}
+
+func installHost(ctx context.Context, version uint32) (string, error) {
+	proxy, err := rpc.NewProxyFunc(proxyFlag)
+	if err != nil {
+		return "", err
+	}
+	client := &http.Client{Transport: rpc.NewProxyTransport(proxy)}
+	path, err := simulator.InstallHost(ctx, client, version, simulator.HostDownloadURL(Version))
+	if err != nil {
+		return "", errors.WrapSimulatorNotFound(fmt.Sprintf("failed to install erst-sim for host %d: %v", version, err))
+	}
+	return path, nil
+}
+
+var simulatorCmd = &cobra.Command{
+	Use:   "simulator",
+	Short: "Manage erst-sim builds for older soroban-env-host versions",
+	Long: `Manage the erst-sim builds selected with --host-version, each compiled
+against one soroban-env-host major version so that old transactions are
+simulated with the rules in force when they ran.
+
+Builds are installed in ~/.erst/simulators (configurable via ERST_SIM_HOSTS_DIR)
+and downloaded from the release of this erst version (configurable via
+ERST_SIM_DOWNLOAD_URL). Released builds exist for hosts ` + simulator.HostVersionList() + `.
+
+Available subcommands:
+  list     - Show installed host versions
+  install  - Download the build for a host version`,
+	Example: `  erst simulator install 21
+  erst simulator list
+  erst debug <tx-hash> --host-version 21`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var simulatorListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed simulator host versions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		versions, err := simulator.InstalledHosts()
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to list simulator builds: %v", err))
+		}
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{"default": simulator.LatestVersion(), "installed": versions})
+		}
+
+		dir, _ := simulator.HostsDir()
+		fmt.Printf("Simulator builds: %s\n", dir)
+		fmt.Printf("Default host: %d\n", simulator.LatestVersion())
+		if len(versions) == 0 {
+			fmt.Println("No other host versions installed")
+			return nil
+		}
+		for _, v := range versions {
+			fmt.Printf("  %d  %s\n", v, simulator.HostBinaryName(v))
+		}
+		return nil
+	},
+}
+
+var simulatorInstallCmd = &cobra.Command{
+	Use:   "install <host-version>",
+	Short: "Download the erst-sim build for a soroban-env-host version",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		v, err := strconv.ParseUint(args[0], 10, 32)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("invalid host version %q", args[0]))
+		}
+		if err := simulator.ValidateHostVersion(uint32(v)); err != nil {
+			return err
+		}
+		path, err := installHost(cmd.Context(), uint32(v))
+		if err != nil {
+			return err
+		}
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{"host_version": v, "path": path})
+		}
+		fmt.Printf("Installed erst-sim for host %d at %s\n", v, path)
+		return nil
+	},
+}
+
+func init() {
+	simulatorCmd.AddCommand(simulatorListCmd)
+	simulatorCmd.AddCommand(simulatorInstallCmd)
+	rootCmd.AddCommand(simulatorCmd)
+}
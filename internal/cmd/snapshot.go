@@ -0,0 +1,202 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var snapshotImportNetworkFlag string
+
+// SnapshotFileOutput is printed by 'erst snapshot export' and 'erst snapshot
+// import' with --output json
+type SnapshotFileOutput struct {
+	File           string `json:"file"`
+	Network        string `json:"network,omitempty"`
+	LedgerSequence uint32 `json:"ledger_sequence,omitempty"`
+	TxHash         string `json:"tx_hash,omitempty"`
+	Entries        int    `json:"entries"`
+	SnapshotID     string `json:"snapshot_id"`
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Export and import the ledger state of debug runs",
+	Long: `Move the ledger state a transaction was simulated against between machines.
+
+'erst snapshot export' writes the state of a saved session to a portable JSON
+file, in the ledgerEntries format of soroban-cli snapshots with the network,
+ledger sequence and transaction hash alongside. 'erst snapshot import' loads
+such a file into the local snapshot cache, so 'erst debug' of the same
+transaction and 'erst replay' of its session use that state instead of the
+network's. A snapshot file can also be passed directly to 'erst debug
+--snapshot' and 'erst simulate --snapshot', for example as a CI fixture.
+
+Available subcommands:
+  export  - Write a session's ledger state to a file
+  import  - Load a snapshot file into the local cache`,
+	Example: `  erst snapshot export abc12345-1700000000 state.json
+  erst snapshot import state.json
+  erst simulate --envelope tx.xdr --snapshot state.json -n testnet`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var snapshotExportCmd = &cobra.Command{
+	Use:   "export <session-id|tx-hash> <file>",
+	Short: "Write the ledger state of a saved session to a portable file",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := session.NewStore()
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+		}
+		defer store.Close()
+
+		data, err := findSession(cmd.Context(), store, args[0])
+		if err != nil {
+			return err
+		}
+		entries, ledger, err := sessionLedgerState(data)
+		if err != nil {
+			return err
+		}
+
+		snap := snapshot.FromMap(entries)
+		snap.Network = data.Network
+		snap.LedgerSequence = ledger
+		snap.TxHash = data.TxHash
+		if err := snapshot.Save(args[1], snap); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to save snapshot: %v", err))
+		}
+
+		out := SnapshotFileOutput{
+			File:           args[1],
+			Network:        snap.Network,
+			LedgerSequence: ledger,
+			TxHash:         snap.TxHash,
+			Entries:        len(entries),
+			SnapshotID:     snapshot.NewPin(ledger, entries).ID,
+		}
+		if jsonOutput() {
+			return printJSON(out)
+		}
+		fmt.Printf("Snapshot of session %s exported to %s (%d entries, ledger %d)\n", data.ID, out.File, out.Entries, ledger)
+		return nil
+	},
+}
+
+var snapshotImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Load a snapshot file into the local snapshot cache",
+	Long: `Check every entry of a snapshot file and store it in the local snapshot
+cache. The state is stored as a pinned snapshot, which 'erst replay' uses for
+sessions simulated against it, and, when the file names its transaction and
+ledger, as the cached state of that transaction for 'erst debug'. --network
+sets or replaces the network recorded in the file.`,
+	Example: `  erst snapshot import state.json
+  erst snapshot import state.json --network testnet`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch rpc.Network(snapshotImportNetworkFlag) {
+		case "", rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+			return nil
+		default:
+			return errors.WrapInvalidNetwork(snapshotImportNetworkFlag)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snap, err := snapshot.Load(args[0])
+		if err != nil {
+			return errors.WrapValidationError(err.Error())
+		}
+		entries := snap.ToMap()
+		for k, v := range entries {
+			if err := simulator.ValidateLedgerOverride(k, v); err != nil {
+				return err
+			}
+		}
+		if snapshotImportNetworkFlag != "" {
+			snap.Network = snapshotImportNetworkFlag
+		}
+
+		cache, err := snapshot.NewDefaultCache()
+		if err != nil {
+			return errors.WrapValidationError(err.Error())
+		}
+		pin := snapshot.NewPin(snap.LedgerSequence, entries)
+		if err := cache.PutPinned(pin, entries); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to store snapshot: %v", err))
+		}
+		cached := snap.Network != "" && snap.LedgerSequence > 0 && snap.TxHash != ""
+		if cached {
+			if err := cache.Put(snap.Network, snap.LedgerSequence, snap.TxHash, snapshot.FromMap(entries)); err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("failed to store snapshot: %v", err))
+			}
+		}
+
+		if jsonOutput() {
+			return printJSON(SnapshotFileOutput{
+				File:           args[0],
+				Network:        snap.Network,
+				LedgerSequence: snap.LedgerSequence,
+				TxHash:         snap.TxHash,
+				Entries:        len(entries),
+				SnapshotID:     pin.ID,
+			})
+		}
+		fmt.Printf("Imported %d ledger entries as snapshot %s\n", len(entries), shortPinID(pin.ID))
+		if cached {
+			fmt.Printf("'erst debug %s -n %s' will use this state\n", snap.TxHash, snap.Network)
+		} else {
+			fmt.Println("The file names no network, ledger and transaction, so only replays and --snapshot can use it.")
+		}
+		return nil
+	},
+}
+
+// sessionLedgerState returns the ledger state a session was simulated
+// against and its ledger sequence: the pinned snapshot, the entries stored
+// with the simulation request, or the snapshot cached for its transaction
+func sessionLedgerState(data *session.SessionData) (map[string]string, uint32, error) {
+	if previous, err := data.ToSimulationResponse(); err == nil && previous.Snapshot != nil {
+		entries, err := pinnedEntries(data, previous.Snapshot)
+		if err == nil {
+			return entries, previous.Snapshot.LedgerSequence, nil
+		}
+		logger.Logger.Warn("Pinned snapshot unavailable", "session", data.ID, "error", err)
+	}
+
+	stored, err := data.ToSimulationRequest()
+	if err == nil && len(stored.LedgerEntries) > 0 {
+		return stored.LedgerEntries, stored.LedgerSequence, nil
+	}
+	if err == nil && stored.LedgerSequence > 0 {
+		if cache, err := snapshot.NewDefaultCache(); err == nil {
+			if snap, ok, err := cache.Get(data.Network, stored.LedgerSequence, data.TxHash); err == nil && ok {
+				return snap.ToMap(), stored.LedgerSequence, nil
+			}
+		}
+	}
+	return nil, 0, errors.WrapSimulationLogicError(fmt.Sprintf("session %s holds no ledger state to export", data.ID))
+}
+
+func init() {
+	snapshotImportCmd.Flags().StringVarP(&snapshotImportNetworkFlag, "network", "n", "", "Network the snapshot belongs to, replacing the one in the file (testnet, mainnet, futurenet, local)")
+
+	snapshotCmd.AddCommand(snapshotExportCmd)
+	snapshotCmd.AddCommand(snapshotImportCmd)
+
+	rootCmd.AddCommand(snapshotCmd)
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func accountLedgerEntry(t *testing.T, balance int64) (string, string) {
+	t.Helper()
+	entry := xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{
+				AccountId: xdr.MustAddress(keypair.MustRandom().Address()),
+				Balance:   xdr.Int64(balance),
+			},
+		},
+	}
+	key, err := entry.LedgerKey()
+	require.NoError(t, err)
+	keyXdr, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	entryXdr, err := xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+	return keyXdr, entryXdr
+}
+
+func TestSessionLedgerState(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	entries := map[string]string{"k1": "v1"}
+
+	// Entries stored with the request
+	data := pinnedSession(t, &simulator.SimulationRequest{LedgerEntries: entries, LedgerSequence: 7}, nil)
+	got, ledger, err := sessionLedgerState(data)
+	require.NoError(t, err)
+	assert.Equal(t, entries, got)
+	assert.Equal(t, uint32(7), ledger)
+
+	// The snapshot cached for the transaction
+	data = pinnedSession(t, &simulator.SimulationRequest{LedgerSequence: 7}, nil)
+	data.Network = "testnet"
+	_, _, err = sessionLedgerState(data)
+	assert.Error(t, err)
+	cache, err := snapshot.NewDefaultCache()
+	require.NoError(t, err)
+	require.NoError(t, cache.Put("testnet", 7, data.TxHash, snapshot.FromMap(entries)))
+	got, _, err = sessionLedgerState(data)
+	require.NoError(t, err)
+	assert.Equal(t, entries, got)
+
+	// The pinned snapshot comes first
+	pinned := map[string]string{"k2": "v2"}
+	pin := snapshot.NewPin(9, pinned)
+	require.NoError(t, cache.PutPinned(pin, pinned))
+	data = pinnedSession(t, &simulator.SimulationRequest{LedgerEntries: entries, LedgerSequence: 7}, pin)
+	got, ledger, err = sessionLedgerState(data)
+	require.NoError(t, err)
+	assert.Equal(t, pinned, got)
+	assert.Equal(t, uint32(9), ledger)
+}
+
+func TestSnapshotImport(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	key, entry := accountLedgerEntry(t, 100)
+
+	snap := snapshot.FromMap(map[string]string{key: entry})
+	snap.Network = "testnet"
+	snap.LedgerSequence = 42
+	snap.TxHash = "abc"
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, snapshot.Save(path, snap))
+
+	require.NoError(t, snapshotImportCmd.RunE(snapshotImportCmd, []string{path}))
+
+	cache, err := snapshot.NewDefaultCache()
+	require.NoError(t, err)
+	cached, ok, err := cache.Get("testnet", 42, "abc")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{key: entry}, cached.ToMap())
+
+	entries, ok, err := cache.GetPinned(snapshot.NewPin(42, map[string]string{key: entry}))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{key: entry}, entries)
+
+	// Entries that do not belong to their key are rejected
+	otherKey, _ := accountLedgerEntry(t, 5)
+	require.NoError(t, snapshot.Save(path, snapshot.FromMap(map[string]string{otherKey: entry})))
+	assert.Error(t, snapshotImportCmd.RunE(snapshotImportCmd, []string{path}))
+}
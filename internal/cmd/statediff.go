@@ -0,0 +1,272 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	statediffNetworkFlag    string
+	statediffRPCURLFlag     string
+	statediffRPCTokenFlag   string
+	statediffContractFlag   string
+	statediffFromLedgerFlag uint32
+	statediffToLedgerFlag   uint32
+	statediffKeyFlags       []string
+)
+
+// StateDiffOutput is the document emitted by 'erst statediff --output json'.
+// ToLedger is 0 when the later state is the current one.
+type StateDiffOutput struct {
+	ContractID     string          `json:"contract_id"`
+	FromLedger     uint32          `json:"from_ledger"`
+	ToLedger       uint32          `json:"to_ledger,omitempty"`
+	FromExecutable string          `json:"from_executable,omitempty"`
+	ToExecutable   string          `json:"to_executable,omitempty"`
+	Keys           int             `json:"keys"`
+	Changes        []StorageChange `json:"changes"`
+}
+
+var statediffCmd = &cobra.Command{
+	Use:   "statediff",
+	Short: "Diff a contract's storage between two ledgers",
+	Long: `Show how a contract's storage changed between two points in time, to tell
+whether state drift explains why a transaction that once succeeded now fails.
+
+The storage at the start of --from-ledger, before any of its transactions, is
+compared against the storage at the start of --to-ledger, or the current
+storage when --to-ledger is not given. Both sides are rewound from the RPC
+node's transaction history, so --from-ledger must be within its retention
+window.
+
+The diff covers the contract instance and its instance storage, every
+persistent and temporary key in the footprints of transactions between the two
+ledgers, and the keys given with --key, written as for 'erst storage'. Keys and
+values are decoded with the contract's current spec, naming its structs and
+enum cases.`,
+	Example: `  # What changed in the contract since ledger 51234000
+  erst statediff --contract CABC...XYZ --from-ledger 51234000 --network testnet
+
+  # Between two ledgers, including a key no transaction in between touched
+  erst statediff --contract CABC...XYZ --from-ledger 51234000 --to-ledger 51234500 \
+    --key '["Balance", "GBRPY...OX2H"]'`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch rpc.Network(statediffNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+		default:
+			return errors.WrapInvalidNetwork(statediffNetworkFlag)
+		}
+		if statediffContractFlag == "" {
+			return errors.WrapValidationError("--contract is required")
+		}
+		if statediffFromLedgerFlag == 0 {
+			return errors.WrapValidationError("--from-ledger is required")
+		}
+		if statediffToLedgerFlag != 0 && statediffToLedgerFlag <= statediffFromLedgerFlag {
+			return errors.WrapValidationError("--to-ledger must be after --from-ledger")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		contractID, err := rpc.ParseContractID(resolveContract(statediffNetworkFlag, statediffContractFlag))
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("invalid contract id: %v", err))
+		}
+
+		opts := []rpc.ClientOption{
+			rpc.WithNetwork(rpc.Network(statediffNetworkFlag)),
+			rpc.WithToken(resolveRPCToken(statediffRPCTokenFlag, statediffNetworkFlag)),
+		}
+		opts = append(opts, rpcEndpointOptions(statediffRPCURLFlag, statediffNetworkFlag)...)
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+		}
+
+		health, err := client.GetHealth(ctx)
+		if err != nil {
+			return errors.WrapRPCConnectionFailed(err)
+		}
+		if statediffFromLedgerFlag < health.Result.OldestLedger {
+			return errors.WrapLedgerArchived(statediffFromLedgerFlag)
+		}
+		if statediffToLedgerFlag > health.Result.LatestLedger+1 {
+			return errors.WrapValidationError(fmt.Sprintf("--to-ledger %d is after the latest ledger %d", statediffToLedgerFlag, health.Result.LatestLedger))
+		}
+
+		keys, err := statediffKeys(contractID, statediffKeyFlags)
+		if err != nil {
+			return err
+		}
+		found, err := discoverStorageKeys(ctx, client, contractID, statediffFromLedgerFlag, statediffToLedgerFlag)
+		if err != nil {
+			return err
+		}
+		statusf("Found %d storage keys in transactions between the two ledgers\n", len(found))
+		keys = appendMissing(keys, found)
+
+		format := decoder.FormatScVal
+		address := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID}
+		id, _ := address.String()
+		if spec, err := cachedSpecLoader(ctx, client)(id); err == nil {
+			format = func(v xdr.ScVal) string { return contractspec.FormatValue(spec, v) }
+		} else {
+			logger.Logger.Warn("Contract spec unavailable, showing raw values", "contract", id, "error", err)
+		}
+
+		from, err := storageAt(ctx, client, contractID, keys, statediffFromLedgerFlag, format)
+		if err != nil {
+			return err
+		}
+		to, err := storageAt(ctx, client, contractID, keys, statediffToLedgerFlag, format)
+		if err != nil {
+			return err
+		}
+
+		out := StateDiffOutput{
+			ContractID:     id,
+			FromLedger:     statediffFromLedgerFlag,
+			ToLedger:       statediffToLedgerFlag,
+			FromExecutable: from.Executable,
+			ToExecutable:   to.Executable,
+			Keys:           len(keys),
+			Changes:        diffStorage(from.Entries, to.Entries),
+		}
+		if out.Changes == nil {
+			out.Changes = []StorageChange{}
+		}
+		if jsonOutput() {
+			return printJSON(out)
+		}
+		printStateDiff(out)
+		return nil
+	},
+}
+
+// statediffKeys returns the ledger keys of the contract instance and of each
+// --key in both durabilities
+func statediffKeys(contractID xdr.ContractId, keyArgs []string) ([]string, error) {
+	instanceKey, err := rpc.EncodeLedgerKey(rpc.LedgerKeyForContractData(contractID, xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}, xdr.ContractDataDurabilityPersistent))
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{instanceKey}
+	for _, arg := range keyArgs {
+		key, err := contractspec.ParseUntyped(arg)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("invalid storage key %q: %v", arg, err))
+		}
+		for _, durability := range []xdr.ContractDataDurability{xdr.ContractDataDurabilityPersistent, xdr.ContractDataDurabilityTemporary} {
+			encoded, err := rpc.EncodeLedgerKey(rpc.LedgerKeyForContractData(contractID, key, durability))
+			if err != nil {
+				return nil, err
+			}
+			keys = appendMissing(keys, []string{encoded})
+		}
+	}
+	return keys, nil
+}
+
+// storageAt fetches the contract's storage for keys as it stood at the start
+// of ledger, or the current storage for ledger 0
+func storageAt(ctx context.Context, client *rpc.Client, contractID xdr.ContractId, keys []string, ledger uint32, format func(xdr.ScVal) string) (*StorageOutput, error) {
+	if ledger == 0 {
+		entries, err := client.GetContractData(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		out, _ := decodeStorage(contractID, entries, format)
+		return out, nil
+	}
+
+	raw, err := client.GetLedgerEntriesAt(ctx, keys, ledger)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := contractDataEntries(raw)
+	if err != nil {
+		return nil, err
+	}
+	out, _ := decodeStorage(contractID, entries, format)
+	return out, nil
+}
+
+// contractDataEntries decodes LedgerEntry XDR values, keyed by LedgerKey XDR,
+// skipping anything that is not contract data. TTLs are separate entries, so
+// LiveUntil is left unset.
+func contractDataEntries(raw map[string]string) ([]rpc.ContractDataEntry, error) {
+	entries := make([]rpc.ContractDataEntry, 0, len(raw))
+	for key, value := range raw {
+		var entry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshalBase64(value, &entry); err != nil {
+			return nil, errors.WrapUnmarshalFailed(err, "ledger entry")
+		}
+		data := entry.Data.ContractData
+		if data == nil {
+			continue
+		}
+		entries = append(entries, rpc.ContractDataEntry{
+			LedgerKey:    key,
+			Durability:   data.Durability,
+			Key:          data.Key,
+			Val:          data.Val,
+			LastModified: uint32(entry.LastModifiedLedgerSeq),
+		})
+	}
+	return entries, nil
+}
+
+func printStateDiff(out StateDiffOutput) {
+	to := "now"
+	if out.ToLedger > 0 {
+		to = fmt.Sprintf("ledger %d", out.ToLedger)
+	}
+	fmt.Printf("Contract: %s\n", labelAddress(out.ContractID))
+	fmt.Printf("Storage at ledger %d vs %s (%d keys compared)\n\n", out.FromLedger, to, out.Keys)
+
+	switch {
+	case out.FromExecutable == "" && out.ToExecutable != "":
+		fmt.Printf("+ Contract created (%s)\n", out.ToExecutable)
+	case out.FromExecutable != "" && out.ToExecutable == "":
+		fmt.Printf("%s Contract instance is gone (was %s)\n", visualizer.Warning(), out.FromExecutable)
+	case out.FromExecutable != out.ToExecutable:
+		fmt.Printf("~ Executable: %s -> %s\n", out.FromExecutable, out.ToExecutable)
+	}
+
+	if len(out.Changes) == 0 {
+		fmt.Println("No storage changes")
+		return
+	}
+	for _, change := range out.Changes {
+		printStorageChange(change)
+	}
+}
+
+func init() {
+	statediffCmd.Flags().StringVarP(&statediffNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	statediffCmd.Flags().StringVar(&statediffRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(statediffCmd, "rpc-url")
+	statediffCmd.Flags().StringVar(&statediffRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	statediffCmd.Flags().StringVar(&statediffContractFlag, "contract", "", "Contract ID or alias whose storage to diff")
+	_ = statediffCmd.RegisterFlagCompletionFunc("contract", completeContractIDs)
+	statediffCmd.Flags().Uint32Var(&statediffFromLedgerFlag, "from-ledger", 0, "Ledger whose starting state is the old side of the diff")
+	statediffCmd.Flags().Uint32Var(&statediffToLedgerFlag, "to-ledger", 0, "Ledger whose starting state is the new side of the diff (default: current state)")
+	statediffCmd.Flags().StringArrayVar(&statediffKeyFlags, "key", nil, "Storage key to include even if no transaction between the ledgers touched it (repeatable)")
+
+	rootCmd.AddCommand(statediffCmd)
+}
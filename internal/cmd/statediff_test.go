@@ -0,0 +1,93 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contractDataLedgerEntry(t *testing.T, contractID xdr.ContractId, key, val xdr.ScVal, ledger uint32) (string, string) {
+	t.Helper()
+	entry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: xdr.Uint32(ledger),
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+				Key:        key,
+				Durability: xdr.ContractDataDurabilityPersistent,
+				Val:        val,
+			},
+		},
+	}
+	keyXdr, err := rpc.EncodeLedgerKey(rpc.LedgerKeyForContractData(contractID, key, xdr.ContractDataDurabilityPersistent))
+	require.NoError(t, err)
+	entryXdr, err := xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+	return keyXdr, entryXdr
+}
+
+func TestStateDiff(t *testing.T) {
+	contractID := xdr.ContractId{1}
+	sym := func(s string) xdr.ScVal {
+		v := xdr.ScSymbol(s)
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}
+	}
+	u32 := func(n uint32) xdr.ScVal {
+		v := xdr.Uint32(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+	}
+	instance := func(hash byte, admin string) xdr.ScVal {
+		storage := xdr.ScMap{{Key: sym("Admin"), Val: sym(admin)}}
+		return xdr.ScVal{Type: xdr.ScValTypeScvContractInstance, Instance: &xdr.ScContractInstance{
+			Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableWasm, WasmHash: &xdr.Hash{hash}},
+			Storage:    &storage,
+		}}
+	}
+	instanceKey := xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}
+
+	kInst, vInst := contractDataLedgerEntry(t, contractID, instanceKey, instance(1, "alice"), 10)
+	kCount, vCount := contractDataLedgerEntry(t, contractID, sym("Counter"), u32(1), 10)
+	fromEntries, err := contractDataEntries(map[string]string{kInst: vInst, kCount: vCount})
+	require.NoError(t, err)
+	from, found := decodeStorage(contractID, fromEntries, decoder.FormatScVal)
+	require.True(t, found)
+	assert.Equal(t, uint32(10), from.Entries[1].LastModified)
+
+	_, vInst = contractDataLedgerEntry(t, contractID, instanceKey, instance(2, "bob"), 20)
+	_, vCount = contractDataLedgerEntry(t, contractID, sym("Counter"), u32(5), 20)
+	toEntries, err := contractDataEntries(map[string]string{kInst: vInst, kCount: vCount})
+	require.NoError(t, err)
+	to, _ := decodeStorage(contractID, toEntries, decoder.FormatScVal)
+
+	assert.NotEqual(t, from.Executable, to.Executable)
+	changes := diffStorage(from.Entries, to.Entries)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "instance", changes[0].Item.Durability)
+	assert.Equal(t, "alice", changes[0].Previous)
+	assert.Equal(t, "bob", changes[0].Item.Value)
+	assert.Equal(t, "Counter", changes[1].Item.Key)
+	assert.Equal(t, "1", changes[1].Previous)
+	assert.Equal(t, "5", changes[1].Item.Value)
+
+	// A contract that did not exist yet has no instance on the old side
+	empty, found := decodeStorage(contractID, nil, decoder.FormatScVal)
+	assert.False(t, found)
+	assert.Empty(t, empty.Entries)
+}
+
+func TestStatediffKeys(t *testing.T) {
+	keys, err := statediffKeys(xdr.ContractId{1}, []string{"Counter", "Counter"})
+	require.NoError(t, err)
+	assert.Len(t, keys, 3, "the instance and the key in both durabilities")
+
+	_, err = statediffKeys(xdr.ContractId{1}, []string{"xdr:not-base64"})
+	assert.Error(t, err)
+}
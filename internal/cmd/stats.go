@@ -0,0 +1,127 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsSinceFlag   string
+	statsNetworkFlag string
+	statsBucketFlag  string
+	statsTopFlag     int
+)
+
+// statsBuckets maps the --bucket values to the width of a timeline period
+var statsBuckets = map[string]time.Duration{
+	"hour": time.Hour,
+	"day":  24 * time.Hour,
+	"week": 7 * 24 * time.Hour,
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize the history of saved sessions",
+	Long: `Aggregate saved debugging sessions into statistics: failures per invoked
+contract, the most frequent error messages, the failure rate over time and
+the average simulation time.
+
+Sessions saved by erst debug and erst watch are included. Use --output json
+to feed the statistics to a dashboard.`,
+	Example: `  # Statistics for every saved session
+  erst stats
+
+  # Failures on testnet over the last week, per hour
+  erst stats --since 7d --network testnet --bucket hour
+
+  # Export for a dashboard
+  erst stats --since 30d --output json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := session.HistoryFilter{Network: statsNetworkFlag, TopErrors: statsTopFlag}
+		if statsSinceFlag != "" {
+			age, err := session.ParseAge(statsSinceFlag)
+			if err != nil {
+				return errors.WrapValidationError(err.Error())
+			}
+			filter.Since = time.Now().Add(-age)
+		}
+		bucket, ok := statsBuckets[statsBucketFlag]
+		if !ok {
+			return errors.WrapValidationError(fmt.Sprintf("unsupported bucket %q (use hour, day or week)", statsBucketFlag))
+		}
+		filter.Bucket = bucket
+
+		store, err := session.NewStore()
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+		}
+		defer store.Close()
+
+		stats, err := store.History(cmd.Context(), filter)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to aggregate sessions: %v", err))
+		}
+
+		if jsonOutput() {
+			return printJSON(stats)
+		}
+		printHistoryStats(stats, statsBucketFlag)
+		return nil
+	},
+}
+
+func printHistoryStats(stats *session.HistoryStats, bucket string) {
+	if stats.Sessions == 0 {
+		fmt.Println("No saved sessions found.")
+		return
+	}
+
+	fmt.Printf("Sessions:        %d\n", stats.Sessions)
+	fmt.Printf("Failures:        %d (%s)\n", stats.Failures, percent(stats.FailureRate))
+	if stats.TimedSessions > 0 {
+		fmt.Printf("Avg simulation:  %.0fms (%d of %d sessions timed)\n", stats.AvgSimulationMs, stats.TimedSessions, stats.Sessions)
+	}
+
+	fmt.Printf("\nFailures by contract:\n")
+	fmt.Printf("  %-56s %8s %8s %7s\n", "CONTRACT", "SESSIONS", "FAILURES", "RATE")
+	for _, c := range stats.Contracts {
+		fmt.Printf("  %-56s %8d %8d %7s\n", c.Contract, c.Sessions, c.Failures, percent(c.FailureRate))
+	}
+
+	if len(stats.TopErrors) > 0 {
+		fmt.Printf("\nTop errors:\n")
+		for _, e := range stats.TopErrors {
+			fmt.Printf("  %4dx %s\n", e.Count, e.Error)
+		}
+	}
+
+	layout := "2006-01-02"
+	if bucket == "hour" {
+		layout = "2006-01-02 15:04"
+	}
+	fmt.Printf("\nFailure rate by %s (UTC):\n", bucket)
+	for _, p := range stats.Timeline {
+		fmt.Printf("  %-16s %7s  (%d of %d)\n", p.Start.Format(layout), percent(p.FailureRate), p.Failures, p.Sessions)
+	}
+}
+
+func percent(rate float64) string {
+	return fmt.Sprintf("%.1f%%", rate*100)
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsSinceFlag, "since", "", "Only include sessions created within this long (e.g. 7d, 2w or 12h)")
+	statsCmd.Flags().StringVarP(&statsNetworkFlag, "network", "n", "", "Only include sessions on this network")
+	statsCmd.Flags().StringVar(&statsBucketFlag, "bucket", "day", "Timeline period: hour, day or week")
+	statsCmd.Flags().IntVar(&statsTopFlag, "top", 10, "Number of error messages to list")
+
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,427 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// storageScanPageSize is the number of transactions requested per
+// getTransactions page while discovering storage keys
+const storageScanPageSize = 200
+
+var (
+	storageNetworkFlag  string
+	storageRPCURLFlag   string
+	storageRPCTokenFlag string
+	storageWatchFlag    bool
+	storageIntervalFlag time.Duration
+	storageScanFlag     uint32
+)
+
+// StorageItem is one decoded contract storage value. Instance storage items
+// share the TTL of the contract instance.
+type StorageItem struct {
+	Durability   string `json:"durability"` // "instance", "persistent" or "temporary"
+	Key          string `json:"key"`
+	Value        string `json:"value"`
+	LastModified uint32 `json:"last_modified_ledger,omitempty"`
+	LiveUntil    uint32 `json:"live_until_ledger,omitempty"`
+}
+
+// StorageOutput is the document emitted by 'erst storage --output json'
+type StorageOutput struct {
+	ContractID string        `json:"contract_id"`
+	Executable string        `json:"executable,omitempty"` // "wasm:<hash>" or "stellar_asset"
+	Entries    []StorageItem `json:"entries"`
+}
+
+// StorageChange is one change reported by 'erst storage --watch'
+type StorageChange struct {
+	Change   string      `json:"change"` // "added", "changed" or "removed"
+	Item     StorageItem `json:"item"`
+	Previous string      `json:"previous,omitempty"`
+}
+
+var storageCmd = &cobra.Command{
+	Use:   "storage <contract-id> [key]",
+	Short: "Inspect a contract's storage",
+	Long: `Fetch and decode a contract's storage from Soroban RPC.
+
+The contract instance is always shown, including its instance storage. RPC
+cannot list a contract's persistent and temporary entries, so those are shown
+for the given key, looked up in both durabilities, or for the keys found in the
+footprints of transactions from the last --scan ledgers.
+
+Keys are written like --set-arg values without a spec: a bare word is a symbol
+(or an address when it is a valid G.../C... strkey), integers are u32 and JSON
+arrays are Vecs, so DataKey::Balance(addr) is ["Balance", "G..."]. Use
+xdr:<base64> for any other key.
+
+With --watch, storage is polled every --interval and changes are printed as
+they happen.`,
+	Example: `  # Show the contract instance and its instance storage
+  erst storage CABC...XYZ --network testnet
+
+  # Look up a single persistent or temporary entry
+  erst storage CABC...XYZ '["Balance", "GBRPY...OX2H"]'
+
+  # Include entries touched by transactions in the last 500 ledgers
+  erst storage CABC...XYZ --scan 500
+
+  # Follow a counter as transactions update it
+  erst storage CABC...XYZ Counter --watch`,
+	Args: cobra.RangeArgs(1, 2),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch rpc.Network(storageNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+			return nil
+		default:
+			return errors.WrapInvalidNetwork(storageNetworkFlag)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		contractID, err := rpc.ParseContractID(resolveContract(storageNetworkFlag, args[0]))
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("invalid contract id: %v", err))
+		}
+
+		opts := []rpc.ClientOption{
+			rpc.WithNetwork(rpc.Network(storageNetworkFlag)),
+			rpc.WithToken(resolveRPCToken(storageRPCTokenFlag, storageNetworkFlag)),
+		}
+		opts = append(opts, rpcEndpointOptions(storageRPCURLFlag, storageNetworkFlag)...)
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+		}
+
+		instanceKey, err := rpc.EncodeLedgerKey(rpc.LedgerKeyForContractData(contractID, xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}, xdr.ContractDataDurabilityPersistent))
+		if err != nil {
+			return err
+		}
+		keys := []string{instanceKey}
+
+		if len(args) == 2 {
+			key, err := contractspec.ParseUntyped(args[1])
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("invalid storage key: %v", err))
+			}
+			for _, durability := range []xdr.ContractDataDurability{xdr.ContractDataDurabilityPersistent, xdr.ContractDataDurabilityTemporary} {
+				encoded, err := rpc.EncodeLedgerKey(rpc.LedgerKeyForContractData(contractID, key, durability))
+				if err != nil {
+					return err
+				}
+				keys = append(keys, encoded)
+			}
+		}
+		if storageScanFlag > 0 {
+			health, err := client.GetHealth(ctx)
+			if err != nil {
+				return errors.WrapRPCConnectionFailed(err)
+			}
+			start := health.Result.OldestLedger
+			if latest := health.Result.LatestLedger; latest > storageScanFlag && latest-storageScanFlag > start {
+				start = latest - storageScanFlag
+			}
+			found, err := discoverStorageKeys(ctx, client, contractID, start, 0)
+			if err != nil {
+				return err
+			}
+			statusf("Found %d storage keys in recent transactions\n", len(found))
+			keys = appendMissing(keys, found)
+		}
+
+		storage, err := fetchStorage(ctx, client, contractID, keys)
+		if err != nil {
+			return err
+		}
+		if !storageWatchFlag {
+			if jsonOutput() {
+				return printJSON(storage)
+			}
+			printStorage(storage, len(args) == 2 || storageScanFlag > 0)
+			return nil
+		}
+
+		if jsonOutput() {
+			if err := printJSON(storage); err != nil {
+				return err
+			}
+		} else {
+			printStorage(storage, true)
+			statusf("\n%s Watching storage every %s (Ctrl+C to stop)\n", visualizer.Symbol("magnify"), storageIntervalFlag)
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(storageIntervalFlag):
+			}
+			next, err := fetchStorage(ctx, client, contractID, keys)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				logger.Logger.Warn("Failed to fetch storage, retrying", "error", err)
+				continue
+			}
+			for _, change := range diffStorage(storage.Entries, next.Entries) {
+				if jsonOutput() {
+					if err := printJSON(change); err != nil {
+						return err
+					}
+					continue
+				}
+				printStorageChange(change)
+			}
+			storage = next
+		}
+	},
+}
+
+// fetchStorage fetches the given contract data keys, the first of which is
+// the contract instance, and decodes them
+func fetchStorage(ctx context.Context, client *rpc.Client, contractID xdr.ContractId, keys []string) (*StorageOutput, error) {
+	entries, err := client.GetContractData(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	out, found := decodeStorage(contractID, entries, decoder.FormatScVal)
+	if !found {
+		return nil, errors.WrapValidationError(fmt.Sprintf("contract %s has no instance entry: it does not exist on this network or has been archived", out.ContractID))
+	}
+	return out, nil
+}
+
+// decodeStorage renders contract data entries with format, expanding the
+// contract instance into its instance storage. The boolean reports whether
+// the instance was among the entries.
+func decodeStorage(contractID xdr.ContractId, entries []rpc.ContractDataEntry, format func(xdr.ScVal) string) (*StorageOutput, bool) {
+	address := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID}
+	id, _ := address.String()
+	out := &StorageOutput{ContractID: id, Entries: []StorageItem{}}
+	found := false
+	for _, e := range entries {
+		if e.Key.Type != xdr.ScValTypeScvLedgerKeyContractInstance {
+			out.Entries = append(out.Entries, StorageItem{
+				Durability:   durabilityName(e.Durability),
+				Key:          format(e.Key),
+				Value:        format(e.Val),
+				LastModified: e.LastModified,
+				LiveUntil:    e.LiveUntil,
+			})
+			continue
+		}
+
+		found = true
+		instance := e.Val.Instance
+		if instance == nil {
+			continue
+		}
+		switch instance.Executable.Type {
+		case xdr.ContractExecutableTypeContractExecutableWasm:
+			out.Executable = "wasm:" + hex.EncodeToString(instance.Executable.WasmHash[:])
+		case xdr.ContractExecutableTypeContractExecutableStellarAsset:
+			out.Executable = "stellar_asset"
+		}
+		if instance.Storage == nil {
+			continue
+		}
+		for _, item := range *instance.Storage {
+			out.Entries = append(out.Entries, StorageItem{
+				Durability:   "instance",
+				Key:          format(item.Key),
+				Value:        format(item.Val),
+				LastModified: e.LastModified,
+				LiveUntil:    e.LiveUntil,
+			})
+		}
+	}
+	order := map[string]int{"instance": 0, "persistent": 1, "temporary": 2}
+	sort.SliceStable(out.Entries, func(i, j int) bool {
+		a, b := out.Entries[i], out.Entries[j]
+		if a.Durability != b.Durability {
+			return order[a.Durability] < order[b.Durability]
+		}
+		return a.Key < b.Key
+	})
+	return out, found
+}
+
+// discoverStorageKeys collects the contract's persistent and temporary
+// storage keys from the footprints of transactions from ledger start up to,
+// but not including, ledger end (0 for the latest)
+func discoverStorageKeys(ctx context.Context, client *rpc.Client, contractID xdr.ContractId, start, end uint32) ([]string, error) {
+	seen := make(map[string]bool)
+	var keys []string
+	cursor := ""
+	for {
+		page, err := client.GetTransactions(ctx, start, cursor, storageScanPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range page.Result.Transactions {
+			if end > 0 && tx.Ledger >= end {
+				return keys, nil
+			}
+			var envelope xdr.TransactionEnvelope
+			if err := xdr.SafeUnmarshalBase64(tx.EnvelopeXdr, &envelope); err != nil {
+				continue
+			}
+			data := rpc.SorobanDataFromEnvelope(envelope)
+			if data == nil {
+				continue
+			}
+			for _, group := range [][]xdr.LedgerKey{data.Resources.Footprint.ReadOnly, data.Resources.Footprint.ReadWrite} {
+				for _, key := range group {
+					cd := key.ContractData
+					if cd == nil || cd.Contract.ContractId == nil || *cd.Contract.ContractId != contractID ||
+						cd.Key.Type == xdr.ScValTypeScvLedgerKeyContractInstance {
+						continue
+					}
+					encoded, err := rpc.EncodeLedgerKey(key)
+					if err != nil || seen[encoded] {
+						continue
+					}
+					seen[encoded] = true
+					keys = append(keys, encoded)
+				}
+			}
+		}
+		if len(page.Result.Transactions) < storageScanPageSize || page.Result.Cursor == "" || page.Result.Cursor == cursor {
+			return keys, nil
+		}
+		cursor = page.Result.Cursor
+	}
+}
+
+// diffStorage lists the changes between two snapshots of the same keys
+func diffStorage(prev, next []StorageItem) []StorageChange {
+	id := func(item StorageItem) string { return item.Durability + "\x00" + item.Key }
+	before := make(map[string]StorageItem, len(prev))
+	for _, item := range prev {
+		before[id(item)] = item
+	}
+
+	var changes []StorageChange
+	for _, item := range next {
+		old, ok := before[id(item)]
+		delete(before, id(item))
+		switch {
+		case !ok:
+			changes = append(changes, StorageChange{Change: "added", Item: item})
+		case old.Value != item.Value:
+			changes = append(changes, StorageChange{Change: "changed", Item: item, Previous: old.Value})
+		}
+	}
+	for _, item := range prev {
+		if _, ok := before[id(item)]; ok {
+			changes = append(changes, StorageChange{Change: "removed", Item: item})
+		}
+	}
+	return changes
+}
+
+func durabilityName(d xdr.ContractDataDurability) string {
+	if d == xdr.ContractDataDurabilityTemporary {
+		return "temporary"
+	}
+	return "persistent"
+}
+
+func appendMissing(keys, more []string) []string {
+	for _, k := range more {
+		dup := false
+		for _, existing := range keys {
+			if existing == k {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// printStorage shows a storage snapshot grouped by durability. lookedUp is
+// set when persistent or temporary keys were requested, so finding none of
+// them is worth reporting.
+func printStorage(storage *StorageOutput, lookedUp bool) {
+	fmt.Printf("Contract:   %s\n", labelAddress(storage.ContractID))
+	if storage.Executable != "" {
+		fmt.Printf("Executable: %s\n", storage.Executable)
+	}
+
+	current := ""
+	for _, item := range storage.Entries {
+		if item.Durability != current {
+			current = item.Durability
+			fmt.Printf("\n%s storage:\n", strings.ToUpper(current[:1])+current[1:])
+			if current == "instance" && item.LiveUntil > 0 {
+				fmt.Printf("  (live until ledger %d)\n", item.LiveUntil)
+			}
+		}
+		fmt.Printf("  %s = %s\n", item.Key, item.Value)
+		if current != "instance" {
+			fmt.Printf("      modified in ledger %d, live until ledger %d\n", item.LastModified, item.LiveUntil)
+		}
+	}
+
+	hasData := false
+	for _, item := range storage.Entries {
+		if item.Durability != "instance" {
+			hasData = true
+			break
+		}
+	}
+	switch {
+	case !hasData && lookedUp:
+		fmt.Printf("\n%s No persistent or temporary entry found for the requested keys\n", visualizer.Warning())
+	case !hasData:
+		fmt.Printf("\nPass a key, or --scan <ledgers> to find keys in recent transactions, to show persistent and temporary entries.\n")
+	}
+}
+
+func printStorageChange(change StorageChange) {
+	item := change.Item
+	switch change.Change {
+	case "added":
+		fmt.Printf("+ [%s] %s = %s (ledger %d)\n", item.Durability, item.Key, item.Value, item.LastModified)
+	case "changed":
+		fmt.Printf("~ [%s] %s: %s -> %s (ledger %d)\n", item.Durability, item.Key, change.Previous, item.Value, item.LastModified)
+	case "removed":
+		fmt.Printf("- [%s] %s\n", item.Durability, item.Key)
+	}
+}
+
+func init() {
+	storageCmd.Flags().StringVarP(&storageNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	storageCmd.Flags().StringVar(&storageRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(storageCmd, "rpc-url")
+	storageCmd.Flags().StringVar(&storageRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	storageCmd.Flags().BoolVar(&storageWatchFlag, "watch", false, "Poll storage and print changes until interrupted")
+	storageCmd.Flags().DurationVar(&storageIntervalFlag, "interval", 5*time.Second, "Polling interval for --watch")
+	storageCmd.Flags().Uint32Var(&storageScanFlag, "scan", 0, "Also show entries found in the footprints of transactions from the last N ledgers")
+
+	rootCmd.AddCommand(storageCmd)
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffStorage(t *testing.T) {
+	prev := []StorageItem{
+		{Durability: "instance", Key: "Admin", Value: "GA"},
+		{Durability: "persistent", Key: "Counter", Value: "1"},
+		{Durability: "temporary", Key: "Lock", Value: "true"},
+	}
+	next := []StorageItem{
+		{Durability: "instance", Key: "Admin", Value: "GA"},
+		{Durability: "persistent", Key: "Counter", Value: "2"},
+		{Durability: "persistent", Key: "Paused", Value: "false"},
+	}
+
+	changes := diffStorage(prev, next)
+	assert.Equal(t, []StorageChange{
+		{Change: "changed", Item: next[1], Previous: "1"},
+		{Change: "added", Item: next[2]},
+		{Change: "removed", Item: prev[2]},
+	}, changes)
+	assert.Empty(t, diffStorage(next, next))
+}
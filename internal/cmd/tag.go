@@ -0,0 +1,106 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tagRemoveFlag bool
+	tagNoteFlag   string
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag <session-id> [tag...]",
+	Short: "Tag a saved session or attach a note to it",
+	Long: `Attach tags and free-form notes to a saved debugging session so it can be
+found again later with 'erst search --tag'.
+
+Tags are case-insensitive and may not contain whitespace or commas. Notes are
+appended with a timestamp and shown by 'erst session list --output json' and
+'erst search'. The session may be referenced by ID or transaction hash.`,
+	Example: `  # Tag a session
+  erst tag abc12345-1700000000 incident-42 frontend
+
+  # Attach a note
+  erst tag abc12345-1700000000 --note "only fails with the new router"
+
+  # Remove a tag
+  erst tag abc12345-1700000000 frontend --remove
+
+  # Find tagged sessions
+  erst search --tag incident-42`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		ref, tags := args[0], args[1:]
+
+		if len(tags) == 0 && tagNoteFlag == "" {
+			return errors.WrapValidationError("at least one tag or --note is required")
+		}
+		if tagRemoveFlag && len(tags) == 0 {
+			return errors.WrapValidationError("--remove requires at least one tag")
+		}
+
+		store, err := session.NewStore()
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+		}
+		defer store.Close()
+
+		data, err := findSession(ctx, store, ref)
+		if err != nil {
+			return err
+		}
+
+		if len(tags) > 0 {
+			if tagRemoveFlag {
+				err = store.RemoveTags(ctx, data.ID, tags...)
+			} else {
+				err = store.AddTags(ctx, data.ID, tags...)
+			}
+			if err != nil {
+				return errors.WrapValidationError(err.Error())
+			}
+		}
+		if tagNoteFlag != "" {
+			if err := store.AddNote(ctx, data.ID, tagNoteFlag); err != nil {
+				return errors.WrapValidationError(err.Error())
+			}
+		}
+
+		data, err = store.Load(ctx, data.ID)
+		if err != nil {
+			return errors.WrapSessionNotFound(ref)
+		}
+
+		if jsonOutput() {
+			return printJSON(data)
+		}
+
+		fmt.Printf("Session %s\n", data.ID)
+		if len(data.Tags) > 0 {
+			fmt.Printf("  Tags: %s\n", strings.Join(data.Tags, ", "))
+		} else {
+			fmt.Println("  Tags: (none)")
+		}
+		for _, note := range data.Notes {
+			fmt.Printf("  Note [%s]: %s\n", note.CreatedAt.Format("2006-01-02 15:04"), note.Text)
+		}
+		return nil
+	},
+}
+
+func init() {
+	tagCmd.Flags().BoolVar(&tagRemoveFlag, "remove", false, "Remove the given tags instead of adding them")
+	tagCmd.Flags().StringVar(&tagNoteFlag, "note", "", "Free-form note to attach to the session")
+
+	rootCmd.AddCommand(tagCmd)
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// withTimeout wraps run so that it is canceled once *timeout has passed, when
+// set. The deadline reaches RPC calls and the simulator through cmd.Context().
+func withTimeout(timeout *time.Duration, run func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if *timeout <= 0 {
+			return run(cmd, args)
+		}
+		parent := cmd.Context()
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(parent, *timeout)
+		defer cancel()
+		cmd.SetContext(ctx)
+
+		err := run(cmd, args)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && !errors.Is(err, errors.ErrCheckFailed) {
+			return fmt.Errorf("%s timed out after %s: %w", cmd.Name(), *timeout, err)
+		}
+		return err
+	}
+}
+
+// drainContext returns the context for work already running when ctx is
+// canceled, e.g. by Ctrl-C: it outlives ctx by up to grace so that the work
+// can finish, and is then canceled as well. A timeout from --timeout is not
+// extended, and a second Ctrl-C still exits at once.
+func drainContext(ctx context.Context, grace time.Duration) (context.Context, context.CancelFunc) {
+	drain, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		if grace <= 0 || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			cancel()
+			return
+		}
+		time.AfterFunc(grace, cancel)
+	})
+	return drain, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDrainContext(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	drain, stop := drainContext(parent, 50*time.Millisecond)
+	defer stop()
+
+	cancel()
+	select {
+	case <-drain.Done():
+		t.Fatal("expected work in flight to outlive the interrupt")
+	case <-time.After(10 * time.Millisecond):
+	}
+	select {
+	case <-drain.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the drain context to end after the grace period")
+	}
+}
+
+func TestDrainContextTimeout(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	drain, stop := drainContext(parent, time.Minute)
+	defer stop()
+
+	select {
+	case <-drain.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected a timeout not to be extended by the grace period")
+	}
+}
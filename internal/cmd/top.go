@@ -0,0 +1,232 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/dotandev/hintents/internal/watch"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+const topClearScreen = "\033[H\033[2J"
+
+// topLedgerTime is the approximate ledger close time, used to pick the
+// ledger that starts the window
+const topLedgerTime = 5 * time.Second
+
+var (
+	topContractFlag    string
+	topNetworkFlag     string
+	topRPCURLFlag      string
+	topIntervalFlag    time.Duration
+	topRefreshFlag     time.Duration
+	topWindowFlag      time.Duration
+	topStartLedgerFlag uint32
+	topLimitFlag       int
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Live dashboard of a contract's failed transactions",
+	Long: `Follow the ledger like 'erst watch' and keep a continuously refreshing view
+of the failed transactions that invoke the given contract or touch its
+storage: the failure rate, the most common errors and the latest failures.
+
+Failures are named by their transaction or operation result code; a trapped
+contract call is named by the host error it raised, e.g. Error(Contract, #3).
+Nothing is simulated, so the view keeps up with busy contracts.
+
+The view covers the last --window, starting that far back in the ledger
+unless --start-ledger is given. When stdout is not a terminal the view is
+printed on every refresh instead of redrawn; with --output json or ndjson
+each refresh prints the summary as JSON.`,
+	Example: `  # Watch failures live during a deployment
+  erst top --contract CABC...XYZ --network testnet
+
+  # Look at the last hour, refreshing every 5 seconds
+  erst top --contract CABC...XYZ --window 1h --refresh 5s
+
+  # Feed the summaries to another tool
+  erst top --contract CABC...XYZ --output ndjson`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if topContractFlag == "" {
+			return errors.WrapCliArgumentRequired("contract")
+		}
+		if topRefreshFlag <= 0 || topWindowFlag <= 0 {
+			return errors.WrapValidationError("--refresh and --window must be positive")
+		}
+		if topLimitFlag < 1 {
+			return errors.WrapValidationError("--limit must be at least 1")
+		}
+		switch rpc.Network(topNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+			return nil
+		default:
+			return errors.WrapInvalidNetwork(topNetworkFlag)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+
+		contractID, err := rpc.ParseContractID(resolveContract(topNetworkFlag, topContractFlag))
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("invalid contract id: %v", err))
+		}
+
+		opts := []rpc.ClientOption{
+			rpc.WithNetwork(rpc.Network(topNetworkFlag)),
+			rpc.WithToken(resolveRPCToken(rpcTokenFlag, topNetworkFlag)),
+		}
+		if topRPCURLFlag != "" {
+			opts = append(opts, rpc.WithSorobanURL(topRPCURLFlag))
+		}
+		opts = append(opts, rpcSharedOptions(topNetworkFlag)...)
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+		}
+
+		startLedger := topStartLedgerFlag
+		if startLedger == 0 {
+			health, err := client.GetHealth(ctx)
+			if err != nil {
+				return errors.WrapRPCConnectionFailed(err)
+			}
+			startLedger = topWindowStart(health.Result.LatestLedger, health.Result.OldestLedger, topWindowFlag)
+		}
+
+		stats := watch.NewFailureStats(topWindowFlag)
+		watcher := watch.NewContractWatcher(client, watch.ContractWatcherConfig{
+			ContractID:   contractID,
+			StartLedger:  startLedger,
+			PollInterval: topIntervalFlag,
+		})
+		watchErr := make(chan error, 1)
+		go func() {
+			watchErr <- watcher.Run(ctx, func(tx rpc.LedgerTransaction) error {
+				stats.Record(tx, watch.FailureKind(tx.ResultXdr, tx.ResultMetaXdr), time.Now())
+				return nil
+			})
+		}()
+
+		redraw := textOutput() && isatty.IsTerminal(os.Stdout.Fd())
+		if redraw {
+			fmt.Print("\033[?25l")
+			defer fmt.Print("\033[?25h")
+		}
+		label := labelAddress(resolveContract(topNetworkFlag, topContractFlag))
+
+		ticker := time.NewTicker(topRefreshFlag)
+		defer ticker.Stop()
+		for {
+			summary := stats.Snapshot(time.Now(), topLimitFlag)
+			switch {
+			case ndjsonOutput():
+				err = printNDJSON(summary)
+			case jsonOutput():
+				err = printJSON(summary)
+			default:
+				if redraw {
+					fmt.Print(topClearScreen)
+				}
+				renderTop(os.Stdout, label, topNetworkFlag, startLedger, summary, time.Now())
+				if !redraw {
+					fmt.Println()
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				return <-watchErr
+			case err := <-watchErr:
+				return err
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// topWindowStart returns the ledger about window before latest, no earlier
+// than the oldest ledger the RPC node retains
+func topWindowStart(latest, oldest uint32, window time.Duration) uint32 {
+	back := uint32(window / topLedgerTime)
+	if back >= latest || latest-back < oldest {
+		return oldest
+	}
+	return latest - back
+}
+
+// renderTop draws one frame of the dashboard
+func renderTop(w io.Writer, contract, network string, startLedger uint32, s watch.FailureSummary, now time.Time) {
+	fmt.Fprintf(w, "erst top - %s on %s    %s\n", contract, network, now.Format("15:04:05"))
+	ledger := "-"
+	if s.LastLedger > 0 {
+		ledger = fmt.Sprintf("%d", s.LastLedger)
+	}
+	fmt.Fprintf(w, "Since ledger %d, last failure in ledger %s\n\n", startLedger, ledger)
+
+	status := visualizer.Success()
+	if s.RateLastMinute > 0 {
+		status = visualizer.Error()
+	}
+	fmt.Fprintf(w, "%s Failures: %d in the last %s, %d total\n", status, s.InWindow, s.Window, s.Total)
+	fmt.Fprintf(w, "  Rate/min: %.1f (1m)  %.1f (5m)  %.1f (%s)\n\n", s.RateLastMinute, s.RateLast5Minute, s.RateWindow, s.Window)
+
+	fmt.Fprintln(w, "TOP ERRORS")
+	if len(s.Kinds) == 0 {
+		fmt.Fprintln(w, "  (none)")
+	}
+	for i, k := range s.Kinds {
+		if i == 10 {
+			fmt.Fprintf(w, "  ... %d more\n", len(s.Kinds)-i)
+			break
+		}
+		share := 100 * float64(k.Count) / float64(s.InWindow)
+		fmt.Fprintf(w, "  %6d  %5.1f%%  %s\n", k.Count, share, k.Kind)
+	}
+
+	fmt.Fprintln(w, "\nLATEST FAILURES")
+	if len(s.Latest) == 0 {
+		fmt.Fprintln(w, "  (none)")
+		return
+	}
+	fmt.Fprintf(w, "  %-8s  %-9s  %-28s  %s\n", "TIME", "LEDGER", "ERROR", "TX HASH")
+	for _, f := range s.Latest {
+		kind := f.Kind
+		if len(kind) > 28 {
+			kind = kind[:25] + "..."
+		}
+		fmt.Fprintf(w, "  %-8s  %-9d  %-28s  %s\n", f.Time.Format("15:04:05"), f.Ledger, kind, f.TxHash)
+	}
+}
+
+func init() {
+	topCmd.Flags().StringVar(&topContractFlag, "contract", "", "Contract ID (C... or hex) or alias to watch")
+	_ = topCmd.RegisterFlagCompletionFunc("contract", completeContractIDs)
+	topCmd.Flags().StringVarP(&topNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	topCmd.Flags().StringVar(&topRPCURLFlag, "rpc-url", "", "Custom Soroban RPC URL")
+	markSensitive(topCmd, "rpc-url")
+	topCmd.Flags().StringVar(&rpcTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	topCmd.Flags().DurationVar(&topIntervalFlag, "interval", 5*time.Second, "Polling interval once caught up with the ledger")
+	topCmd.Flags().DurationVar(&topRefreshFlag, "refresh", 2*time.Second, "How often the view is redrawn")
+	topCmd.Flags().DurationVar(&topWindowFlag, "window", 15*time.Minute, "How far back rates and top errors reach")
+	topCmd.Flags().Uint32Var(&topStartLedgerFlag, "start-ledger", 0, "Ledger to start from (default: the start of the window)")
+	topCmd.Flags().IntVar(&topLimitFlag, "limit", 10, "Number of latest failures to show")
+
+	rootCmd.AddCommand(topCmd)
+}
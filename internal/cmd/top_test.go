@@ -0,0 +1,39 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/watch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopWindowStart(t *testing.T) {
+	assert.Equal(t, uint32(820), topWindowStart(1000, 10, 15*time.Minute))
+	assert.Equal(t, uint32(900), topWindowStart(1000, 900, 15*time.Minute))
+	assert.Equal(t, uint32(1), topWindowStart(100, 1, time.Hour))
+}
+
+func TestRenderTop(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	stats := watch.NewFailureStats(15 * time.Minute)
+	var buf bytes.Buffer
+	renderTop(&buf, "CABC", "testnet", 100, stats.Snapshot(now, 5), now)
+	assert.Contains(t, buf.String(), "Failures: 0 in the last 15m0s, 0 total")
+	assert.Contains(t, buf.String(), "(none)")
+
+	stats.Record(rpc.LedgerTransaction{TxHash: "aa11", Ledger: 120, CreatedAt: now.Add(-10 * time.Second).Unix()}, "Error(Contract, #3)", now)
+	stats.Record(rpc.LedgerTransaction{TxHash: "bb22", Ledger: 121, CreatedAt: now.Add(-5 * time.Second).Unix()}, "tx_bad_seq", now)
+	buf.Reset()
+	renderTop(&buf, "CABC", "testnet", 100, stats.Snapshot(now, 5), now)
+	out := buf.String()
+	assert.Contains(t, out, "last failure in ledger 121")
+	assert.Contains(t, out, "Rate/min: 2.0 (1m)")
+	assert.Contains(t, out, " 50.0%  Error(Contract, #3)")
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte("bb22")), bytes.Index(buf.Bytes(), []byte("aa11")))
+}
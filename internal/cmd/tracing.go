@@ -0,0 +1,45 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+const defaultOTLPURL = "http://localhost:4318"
+
+// addTracingFlags registers --tracing and --otlp-url on cmd
+func addTracingFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&tracingEnabled, "tracing", false, "Export OpenTelemetry spans for the debug pipeline")
+	cmd.Flags().StringVar(&otlpExporterURL, "otlp-url", defaultOTLPURL, "OTLP/HTTP endpoint for --tracing (or set OTEL_EXPORTER_OTLP_ENDPOINT)")
+}
+
+// initTracing starts exporting spans when --tracing is set and returns the
+// function that flushes them. OTEL_EXPORTER_OTLP_ENDPOINT, and the other
+// standard exporter variables, apply unless --otlp-url is given.
+func initTracing(ctx context.Context, cmd *cobra.Command, serviceName string) (func(), error) {
+	if !tracingEnabled {
+		return func() {}, nil
+	}
+	url := otlpExporterURL
+	if !cmd.Flags().Changed("otlp-url") && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" {
+		url = ""
+	}
+	cleanup, err := telemetry.Init(ctx, telemetry.Config{
+		Enabled:        true,
+		ExporterURL:    url,
+		ServiceName:    serviceName,
+		ServiceVersion: Version,
+	})
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to initialize telemetry: %v", err))
+	}
+	return cleanup, nil
+}
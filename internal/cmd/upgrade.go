@@ -47,9 +47,7 @@ Example:
 		opts := []rpc.ClientOption{
 			rpc.WithNetwork(rpc.Network(networkFlag)),
 		}
-		if rpcURLFlag != "" {
-			opts = append(opts, rpc.WithHorizonURL(rpcURLFlag))
-		}
+		opts = append(opts, rpcEndpointOptions(rpcURLFlag, networkFlag)...)
 
 		client, err := rpc.NewClient(opts...)
 		if err != nil {
@@ -100,12 +98,12 @@ Example:
 		}
 
 		fmt.Println("Running simulation with upgraded code...")
-		result, err := runner.Run(simReq)
+		result, err := simulator.RunWithContext(cmd.Context(), runner, simReq)
 		if err != nil {
 			return errors.WrapSimulationFailed(err, "")
 		}
 
-		printSimulationResult("Upgraded Contract", result)
+		printSimulationResult(cmd.Context(), "Upgraded Contract", result)
 
 		return nil
 	},
@@ -117,7 +115,8 @@ func init() {
 	// Since they are in the same package, we can reuse the variables 'networkFlag' and 'rpcURLFlag'
 	// BUT we need to register flags for THIS command too.
 	upgradeCmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use")
-	upgradeCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Horizon RPC URL")
+	upgradeCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(upgradeCmd, "rpc-url")
 
 	rootCmd.AddCommand(upgradeCmd)
 }
@@ -9,6 +9,7 @@ import (
 	"runtime/debug"
 	"time"
 
+	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +25,8 @@ type VersionInfo struct {
 	CommitSHA string `json:"commit_sha"`
 	BuildDate string `json:"build_date"`
 	GoVersion string `json:"go_version"`
+	// Protocols are the Soroban protocol versions the simulator supports
+	Protocols []uint32 `json:"protocols"`
 }
 
 // versionCmd represents the version command
@@ -45,6 +48,7 @@ var versionCmd = &cobra.Command{
 			fmt.Printf("Commit SHA:   %s\n", info.CommitSHA)
 			fmt.Printf("Build Date:   %s\n", info.BuildDate)
 			fmt.Printf("Go Version:   %s\n", info.GoVersion)
+			fmt.Printf("Protocols:    %d-%d (default %d)\n", info.Protocols[0], info.Protocols[len(info.Protocols)-1], simulator.LatestVersion())
 		}
 		fmt.Printf("erst version %s\n", Version)
 	},
@@ -56,6 +60,7 @@ func getVersionInfo() VersionInfo {
 		CommitSHA: CommitSHA,
 		BuildDate: BuildDate,
 		GoVersion: "unknown",
+		Protocols: simulator.Supported(),
 	}
 
 	// Use runtime/debug as fallback
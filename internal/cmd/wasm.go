@@ -0,0 +1,243 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/wat"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	wasmNetworkFlag  string
+	wasmRPCURLFlag   string
+	wasmRPCTokenFlag string
+	wasmWATFlag      bool
+	wasmFunctionFlag string
+	wasmSaveFlag     string
+)
+
+var wasmCmd = &cobra.Command{
+	Use:   "wasm <contract-id>",
+	Short: "Fetch a deployed contract's WASM and show what it contains",
+	Long: `Download the WASM a contract is running and print its hash, size, build
+metadata and exported functions, with argument types taken from the embedded
+contract spec.
+
+Pass --wat to disassemble every function, or --function to disassemble only
+the named export, e.g. the one a failing transaction invoked. Use --save to
+keep the raw module for other tools.`,
+	Example: `  erst wasm CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC -n testnet
+  erst wasm CDLZ... -n testnet --function transfer
+  erst wasm CDLZ... --wat --save contract.wasm`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := rpc.ParseContractID(resolveContract(wasmNetworkFlag, args[0])); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("invalid contract ID %q: %v", args[0], err))
+		}
+		switch rpc.Network(wasmNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+			return nil
+		default:
+			return errors.WrapInvalidNetwork(wasmNetworkFlag)
+		}
+	},
+	RunE: runWasm,
+}
+
+// WasmFunction is one exported function of a contract
+type WasmFunction struct {
+	Name string `json:"name"`
+	// Signature comes from the contract spec when it declares the function,
+	// otherwise from the WASM type section
+	Signature string `json:"signature"`
+	Doc       string `json:"doc,omitempty"`
+	InSpec    bool   `json:"in_spec"`
+}
+
+// WasmOutput is the document emitted by 'erst wasm --output json'
+type WasmOutput struct {
+	ContractID string                   `json:"contract_id"`
+	Network    string                   `json:"network"`
+	Hash       string                   `json:"hash"`
+	Size       int                      `json:"size"`
+	EnvMeta    *contractspec.EnvMeta    `json:"env_meta,omitempty"`
+	Meta       []contractspec.MetaEntry `json:"meta,omitempty"`
+	Functions  []WasmFunction           `json:"functions"`
+	// SpecTypes counts the user-defined types the spec declares
+	SpecTypes int    `json:"spec_types"`
+	HasSpec   bool   `json:"has_spec"`
+	WAT       string `json:"wat,omitempty"`
+}
+
+func runWasm(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	contractID := resolveContract(wasmNetworkFlag, args[0])
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(wasmNetworkFlag)),
+		rpc.WithToken(resolveRPCToken(wasmRPCTokenFlag, wasmNetworkFlag)),
+	}
+	opts = append(opts, rpcEndpointOptions(wasmRPCURLFlag, wasmNetworkFlag)...)
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+	}
+
+	code, err := rpc.FetchContractWasm(ctx, client, contractID)
+	if err != nil {
+		return errors.WrapRPCConnectionFailed(err)
+	}
+
+	if wasmSaveFlag != "" {
+		if err := os.WriteFile(wasmSaveFlag, code, 0644); err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to write %s: %v", wasmSaveFlag, err))
+		}
+	}
+
+	out, module, err := describeWasm(code)
+	if err != nil {
+		return errors.WrapValidationError(err.Error())
+	}
+	out.ContractID = contractID
+	out.Network = wasmNetworkFlag
+
+	switch {
+	case wasmFunctionFlag != "":
+		f, ok := module.Function(wasmFunctionFlag)
+		if !ok {
+			names := make([]string, len(out.Functions))
+			for i, fn := range out.Functions {
+				names[i] = fn.Name
+			}
+			return errors.WrapValidationError(fmt.Sprintf("contract has no function %q; exported functions: %s", wasmFunctionFlag, strings.Join(names, ", ")))
+		}
+		out.WAT = module.FormatFunction(f)
+	case wasmWATFlag:
+		out.WAT = module.Format()
+	}
+
+	if jsonOutput() {
+		return printJSON(out)
+	}
+	printWasm(out)
+	if wasmSaveFlag != "" {
+		fmt.Printf("\nSaved WASM to %s\n", wasmSaveFlag)
+	}
+	return nil
+}
+
+// describeWasm summarizes a contract module. The spec and build metadata
+// are optional; a module without them is still listed from its exports.
+func describeWasm(code []byte) (*WasmOutput, *wat.Module, error) {
+	module, err := wat.ParseModule(code)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse WASM: %w", err)
+	}
+
+	hash := sha256.Sum256(code)
+	out := &WasmOutput{Hash: hex.EncodeToString(hash[:]), Size: len(code)}
+	out.EnvMeta, _ = contractspec.ParseEnvMeta(code)
+	out.Meta, _ = contractspec.ParseMeta(code)
+
+	spec, err := contractspec.Parse(code)
+	out.HasSpec = err == nil
+	if out.HasSpec {
+		for _, entry := range spec.Entries {
+			if entry.Kind != xdr.ScSpecEntryKindScSpecEntryFunctionV0 {
+				out.SpecTypes++
+			}
+		}
+	}
+
+	for _, exp := range module.ExportedFunctions() {
+		fn := WasmFunction{Name: exp.Name}
+		if out.HasSpec {
+			if specFn, ok := spec.Function(exp.Name); ok {
+				fn.InSpec = true
+				fn.Signature = specSignature(specFn)
+				fn.Doc = specFn.Doc
+			}
+		}
+		if !fn.InSpec {
+			if f, ok := module.Function(exp.Name); ok {
+				fn.Signature = f.Type.String()
+			}
+		}
+		out.Functions = append(out.Functions, fn)
+	}
+	return out, module, nil
+}
+
+// specSignature renders a spec function as "fn(name: Type, ...) -> Ret"
+func specSignature(fn xdr.ScSpecFunctionV0) string {
+	params := make([]string, len(fn.Inputs))
+	for i, in := range fn.Inputs {
+		params[i] = in.Name + ": " + contractspec.TypeName(in.Type)
+	}
+	sig := string(fn.Name) + "(" + strings.Join(params, ", ") + ")"
+	if len(fn.Outputs) > 0 {
+		sig += " -> " + contractspec.TypeName(fn.Outputs[0])
+	}
+	return sig
+}
+
+func printWasm(out *WasmOutput) {
+	fmt.Printf("Contract:  %s\n", labelAddress(out.ContractID))
+	fmt.Printf("WASM hash: %s\n", out.Hash)
+	fmt.Printf("Size:      %d bytes\n", out.Size)
+	if out.EnvMeta != nil {
+		fmt.Printf("Protocol:  %d", out.EnvMeta.Protocol)
+		if out.EnvMeta.PreRelease != 0 {
+			fmt.Printf(" (pre-release %d)", out.EnvMeta.PreRelease)
+		}
+		fmt.Println()
+	}
+	if len(out.Meta) > 0 {
+		fmt.Println("Build meta:")
+		for _, m := range out.Meta {
+			fmt.Printf("  %s = %s\n", m.Key, m.Value)
+		}
+	}
+
+	fmt.Printf("\nExported functions (%d):\n", len(out.Functions))
+	for _, fn := range out.Functions {
+		if fn.InSpec {
+			fmt.Printf("  %s\n", fn.Signature)
+		} else {
+			fmt.Printf("  %s %s  (not in spec)\n", fn.Name, fn.Signature)
+		}
+	}
+
+	if out.HasSpec {
+		fmt.Printf("\nSpec declares %d user-defined types\n", out.SpecTypes)
+	} else {
+		fmt.Println("\nThe module embeds no contract spec; signatures are WASM types.")
+	}
+
+	if out.WAT != "" {
+		fmt.Printf("\n%s", out.WAT)
+	}
+}
+
+func init() {
+	wasmCmd.Flags().StringVarP(&wasmNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	wasmCmd.Flags().StringVar(&wasmRPCURLFlag, "rpc-url", "", "Custom RPC URL(s), comma-separated for failover")
+	markSensitive(wasmCmd, "rpc-url")
+	wasmCmd.Flags().StringVar(&wasmRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	wasmCmd.Flags().BoolVar(&wasmWATFlag, "wat", false, "Print a WAT disassembly of every function")
+	wasmCmd.Flags().StringVar(&wasmFunctionFlag, "function", "", "Disassemble only this exported function")
+	wasmCmd.Flags().StringVar(&wasmSaveFlag, "save", "", "Write the raw WASM module to this file")
+
+	rootCmd.AddCommand(wasmCmd)
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func wasmTestSection(id byte, body []byte) []byte {
+	out := binary.AppendUvarint([]byte{id}, uint64(len(body)))
+	return append(out, body...)
+}
+
+func wasmTestName(s string) []byte {
+	return append(binary.AppendUvarint(nil, uint64(len(s))), s...)
+}
+
+// wasmTestModule exports "hello" and "extra", with only hello in the spec
+func wasmTestModule(t *testing.T) []byte {
+	spec, err := xdr.ScSpecEntry{
+		Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0,
+		FunctionV0: &xdr.ScSpecFunctionV0{
+			Name:    "hello",
+			Inputs:  []xdr.ScSpecFunctionInputV0{{Name: "to", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeSymbol}}},
+			Outputs: []xdr.ScSpecTypeDef{{Type: xdr.ScSpecTypeScSpecTypeVec, Vec: &xdr.ScSpecTypeVec{ElementType: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeSymbol}}}},
+		},
+	}.MarshalBinary()
+	require.NoError(t, err)
+
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	module = append(module, wasmTestSection(1, []byte{0x01, 0x60, 0x01, 0x7e, 0x01, 0x7e})...)
+	module = append(module, wasmTestSection(3, []byte{0x02, 0x00, 0x00})...)
+	exports := []byte{0x02}
+	exports = append(append(exports, wasmTestName("hello")...), 0x00, 0x00)
+	exports = append(append(exports, wasmTestName("extra")...), 0x00, 0x01)
+	module = append(module, wasmTestSection(7, exports)...)
+	body := []byte{0x00, 0x20, 0x00, 0x0b} // local.get 0; end
+	code := []byte{0x02, byte(len(body))}
+	code = append(append(code, body...), byte(len(body)))
+	code = append(code, body...)
+	module = append(module, wasmTestSection(10, code)...)
+	return append(module, wasmTestSection(0, append(wasmTestName("contractspecv0"), spec...))...)
+}
+
+func TestDescribeWasm(t *testing.T) {
+	code := wasmTestModule(t)
+	out, module, err := describeWasm(code)
+	require.NoError(t, err)
+
+	assert.Equal(t, len(code), out.Size)
+	assert.Len(t, out.Hash, 64)
+	assert.True(t, out.HasSpec)
+	require.Len(t, out.Functions, 2)
+	assert.Equal(t, WasmFunction{Name: "hello", Signature: "hello(to: Symbol) -> Vec<Symbol>", InSpec: true}, out.Functions[0])
+	assert.Equal(t, WasmFunction{Name: "extra", Signature: "(param i64) (result i64)"}, out.Functions[1])
+
+	f, ok := module.Function("hello")
+	require.True(t, ok)
+	assert.Contains(t, module.FormatFunction(f), "local.get 0")
+}
+
+func TestDescribeWasm_Invalid(t *testing.T) {
+	_, _, err := describeWasm([]byte("not wasm"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,390 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dotandev/hintents/internal/alert"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/hooks"
+	"github.com/dotandev/hintents/internal/metrics"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/telemetry"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/dotandev/hintents/internal/watch"
+	"github.com/dotandev/hintents/internal/webhook"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	watchContractFlag    string
+	watchNetworkFlag     string
+	watchRPCURLFlag      string
+	watchIntervalFlag    time.Duration
+	watchStartLedgerFlag uint32
+	watchNoSaveFlag      bool
+	watchMetricsAddrFlag string
+	watchConcurrencyFlag int
+	watchDrainFlag       time.Duration
+)
+
+// WatchEvent describes one failed transaction picked up by erst watch
+type WatchEvent struct {
+	TxHash    string `json:"tx_hash"`
+	Ledger    uint32 `json:"ledger"`
+	Status    string `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	// Alerts are the alerting rules the failure fired
+	Alerts []string `json:"alerts,omitempty"`
+	// Annotations are added by the post-simulation hooks, and HookFailure
+	// is the reason when a hook failed the transaction
+	Annotations []hooks.Annotation `json:"annotations,omitempty"`
+	HookFailure string             `json:"hook_failure,omitempty"`
+
+	// hookOutput is the hooks' extra output, printed with the event
+	hookOutput string
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Auto-debug new failed transactions for a contract",
+	Long: `Follow the ledger through Soroban RPC and run the simulator on every new
+failed transaction that invokes the given contract or touches its storage.
+
+Each failure is saved as a session as soon as it is simulated, so it can be
+inspected later with 'erst history' or 'erst session resume'. The watcher runs
+until interrupted.
+
+With --alert-rules, each failure is checked against the rules in a YAML file,
+which decide by contract, error, event topic and rate which failures alert and
+how: a webhook, a command or a log line.
+
+With --metrics-addr, Prometheus metrics are served on /metrics at that address.`,
+	Example: `  # Triage failures live during a deployment
+  erst watch --contract CABC...XYZ --network testnet
+
+  # Pick up from an earlier ledger and poll every 10 seconds
+  erst watch --contract CABC...XYZ --start-ledger 123456 --interval 10s
+
+  # Run as a monitoring service scraped by Prometheus
+  erst watch --contract CABC...XYZ --metrics-addr :9464
+
+  # Alert only on the failures that matter
+  erst watch --contract CABC...XYZ --alert-rules alerts.yaml`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if watchContractFlag == "" {
+			return errors.WrapCliArgumentRequired("contract")
+		}
+		if watchConcurrencyFlag < 1 {
+			return errors.WrapValidationError("--concurrency must be at least 1")
+		}
+		switch rpc.Network(watchNetworkFlag) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet, rpc.Local:
+			return nil
+		default:
+			return errors.WrapInvalidNetwork(watchNetworkFlag)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+
+		cleanup, err := initTracing(ctx, cmd, "erst-watch")
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		contractID, err := rpc.ParseContractID(resolveContract(watchNetworkFlag, watchContractFlag))
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("invalid contract id: %v", err))
+		}
+
+		token := resolveRPCToken(rpcTokenFlag, watchNetworkFlag)
+
+		opts := []rpc.ClientOption{
+			rpc.WithNetwork(rpc.Network(watchNetworkFlag)),
+			rpc.WithToken(token),
+		}
+		if watchRPCURLFlag != "" {
+			opts = append(opts, rpc.WithSorobanURL(watchRPCURLFlag))
+		}
+		opts = append(opts, rpcSharedOptions(watchNetworkFlag)...)
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to create client: %v", err))
+		}
+
+		if err := checkNetworkProtocol(ctx, client, watchNetworkFlag, 0); err != nil {
+			return err
+		}
+
+		startLedger := watchStartLedgerFlag
+		if startLedger == 0 {
+			health, err := client.GetHealth(ctx)
+			if err != nil {
+				return errors.WrapRPCConnectionFailed(err)
+			}
+			startLedger = health.Result.LatestLedger
+		}
+
+		runner, err := simulator.NewRunner("", false)
+		if err != nil {
+			return errors.WrapSimulatorNotFound(err.Error())
+		}
+
+		notifier, err := newFailureNotifier()
+		if err != nil {
+			return err
+		}
+		alerts, err := newAlertEngine(watchNetworkFlag)
+		if err != nil {
+			return err
+		}
+		if alerts != nil {
+			defer alerts.Wait()
+		}
+
+		var store session.Store
+		if !watchNoSaveFlag {
+			store, err = session.NewStore()
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("failed to open session store: %v", err))
+			}
+			defer store.Close()
+
+			if _, err := store.Prune(ctx, session.ConfiguredRetention()); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: cleanup failed: %v\n", err)
+			}
+			trackSessionStats(store)
+		}
+
+		if watchMetricsAddrFlag != "" {
+			go func() {
+				if err := metrics.Serve(ctx, watchMetricsAddrFlag); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: metrics server failed: %v\n", err)
+					cancel()
+				}
+			}()
+		}
+
+		statusf("%s Watching %s on %s from ledger %d (Ctrl+C to stop)\n",
+			visualizer.Symbol("magnify"), watchContractFlag, watchNetworkFlag, startLedger)
+
+		watcher := watch.NewContractWatcher(client, watch.ContractWatcherConfig{
+			ContractID:   contractID,
+			StartLedger:  startLedger,
+			PollInterval: watchIntervalFlag,
+		})
+
+		// Failed transactions are triaged by a pool of workers; the watcher
+		// waits while all of them are busy. On shutdown the transactions in
+		// flight are drained rather than abandoned.
+		workCtx, stopWork := drainContext(ctx, watchDrainFlag)
+		defer stopWork()
+		jobs := make(chan rpc.LedgerTransaction)
+		var (
+			wg        sync.WaitGroup
+			inFlight  atomic.Int32
+			outputMu  sync.Mutex
+			outputErr error
+		)
+		for i := 0; i < watchConcurrencyFlag; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for tx := range jobs {
+					inFlight.Add(1)
+					event := triageFailedTransaction(workCtx, client, runner, store, notifier, alerts, tx)
+					inFlight.Add(-1)
+					outputMu.Lock()
+					var err error
+					switch {
+					case ndjsonOutput():
+						err = printNDJSON(event)
+					case jsonOutput():
+						err = printJSON(event)
+					default:
+						printWatchEvent(event)
+					}
+					if event.hookOutput != "" {
+						statusf("%s\n", event.hookOutput)
+					}
+					if err != nil && outputErr == nil {
+						outputErr = err
+						cancel()
+					}
+					outputMu.Unlock()
+				}
+			}()
+		}
+
+		err = watcher.Run(ctx, func(tx rpc.LedgerTransaction) error {
+			select {
+			case jobs <- tx:
+			case <-ctx.Done():
+			}
+			return nil
+		})
+		close(jobs)
+		if ctx.Err() != nil && inFlight.Load() > 0 {
+			statusf("Stopping: waiting up to %s for transactions in flight (Ctrl+C again to abort)\n", watchDrainFlag)
+		}
+		wg.Wait()
+		if outputErr != nil {
+			return outputErr
+		}
+		return err
+	},
+}
+
+// triageFailedTransaction simulates one failed transaction, saves it as a
+// session and notifies the webhook and alerting rules if the simulation
+// fails. Failures are reported in the event rather than stopping the watch.
+func triageFailedTransaction(ctx context.Context, client *rpc.Client, runner simulator.RunnerInterface, store session.Store, notifier *webhook.SimulatorNotifier, alerts *alert.Engine, tx rpc.LedgerTransaction) (event WatchEvent) {
+	ctx, span := telemetry.GetTracer().Start(ctx, "debug_transaction")
+	span.SetAttributes(
+		attribute.String("transaction.hash", tx.TxHash),
+		attribute.String("network", watchNetworkFlag),
+		attribute.Int("ledger.sequence", int(tx.Ledger)),
+	)
+	defer span.End()
+
+	event = WatchEvent{TxHash: tx.TxHash, Ledger: tx.Ledger}
+	var simResp *simulator.SimulationResponse
+	defer func() { event.Alerts = notifyWatchEvent(ctx, notifier, alerts, event, simResp) }()
+
+	resp := &rpc.TransactionResponse{
+		EnvelopeXdr:   tx.EnvelopeXdr,
+		ResultXdr:     tx.ResultXdr,
+		ResultMetaXdr: tx.ResultMetaXdr,
+		Ledger:        tx.Ledger,
+	}
+	simReq, simResp, err := simulateFetchedTransaction(ctx, client, runner, tx.TxHash, resp)
+	if err != nil {
+		span.RecordError(err)
+		event.Status = "failed"
+		event.Error = err.Error()
+		return event
+	}
+	event.Status = simResp.Status
+	event.Error = simResp.Error
+
+	// A hook error is noted and does not stop the watch
+	hookResult, err := runTransactionHooks(ctx, client, hooks.Input{
+		Command:     "watch",
+		Network:     watchNetworkFlag,
+		TxHash:      tx.TxHash,
+		EnvelopeXdr: tx.EnvelopeXdr,
+		Simulation:  simResp,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: hooks failed for %s: %v\n", tx.TxHash, err)
+	}
+	if hookResult != nil {
+		event.Annotations = hookResult.Annotations
+		event.hookOutput = hookResult.Output
+		if hookResult.Fail {
+			event.HookFailure = hookResult.Reason
+		}
+	}
+
+	if store == nil {
+		return event
+	}
+
+	data, err := newSimulatedSession(watchNetworkFlag, client.HorizonURL, tx.TxHash, resp, simReq, simResp)
+	if err != nil {
+		event.Error = err.Error()
+		return event
+	}
+	if err := store.Save(ctx, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save session for %s: %v\n", tx.TxHash, err)
+		return event
+	}
+	event.SessionID = data.ID
+	return event
+}
+
+// notifyWatchEvent sends a failed watch event, or one a hook failed, to the
+// webhook and the alerting rules, returning the rules it fired. simResp is
+// nil when the transaction could not be simulated.
+func notifyWatchEvent(ctx context.Context, notifier *webhook.SimulatorNotifier, alerts *alert.Engine, event WatchEvent, simResp *simulator.SimulationResponse) []string {
+	if (notifier == nil && alerts == nil) || (event.Status == "success" && event.HookFailure == "") {
+		return nil
+	}
+	report := webhook.ReportData{Status: "error", Error: event.Error, Timestamp: time.Now()}
+	if simResp != nil {
+		report = webhook.NewReport(simResp, event.TxHash, watchNetworkFlag)
+	}
+	report.TxHash = event.TxHash
+	report.Network = watchNetworkFlag
+	report.Ledger = event.Ledger
+	report.SessionID = event.SessionID
+	report.Source = "watch"
+	if notifier != nil {
+		notifier.Notify(report)
+	}
+	if alerts == nil {
+		return nil
+	}
+
+	var fired []string
+	contract := resolveContract(watchNetworkFlag, watchContractFlag)
+	for _, a := range alerts.Evaluate(ctx, alert.Failure{Report: report, Contracts: []string{contract}}) {
+		fired = append(fired, a.Rule)
+	}
+	return fired
+}
+
+func printWatchEvent(event WatchEvent) {
+	fmt.Printf("%s [ledger %d] %s\n", visualizer.Error(), event.Ledger, event.TxHash)
+	if event.Error != "" {
+		fmt.Printf("    Error:   %s\n", event.Error)
+	}
+	if event.SessionID != "" {
+		fmt.Printf("    Session: %s\n", event.SessionID)
+	}
+	for _, rule := range event.Alerts {
+		fmt.Printf("    Alert:   %s\n", rule)
+	}
+	for _, a := range event.Annotations {
+		fmt.Printf("    Note:    [%s] %s (%s)\n", a.Level, a.Message, a.Hook)
+	}
+	if event.HookFailure != "" {
+		fmt.Printf("    Hook:    %s\n", event.HookFailure)
+	}
+}
+
+func init() {
+	watchCmd.Flags().StringVar(&watchContractFlag, "contract", "", "Contract ID (C... or hex) to watch")
+	_ = watchCmd.RegisterFlagCompletionFunc("contract", completeContractIDs)
+	watchCmd.Flags().StringVarP(&watchNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet, local)")
+	watchCmd.Flags().StringVar(&watchRPCURLFlag, "rpc-url", "", "Custom Soroban RPC URL")
+	markSensitive(watchCmd, "rpc-url")
+	watchCmd.Flags().StringVar(&rpcTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	watchCmd.Flags().DurationVar(&watchIntervalFlag, "interval", 5*time.Second, "Polling interval once caught up with the ledger")
+	watchCmd.Flags().Uint32Var(&watchStartLedgerFlag, "start-ledger", 0, "Ledger to start from (default: latest)")
+	watchCmd.Flags().BoolVar(&watchNoSaveFlag, "no-save", false, "Do not save a session for each failure")
+	watchCmd.Flags().StringVar(&watchMetricsAddrFlag, "metrics-addr", "", "Serve Prometheus metrics on this address (e.g. :9464)")
+	watchCmd.Flags().IntVar(&watchConcurrencyFlag, "concurrency", 1, "Number of failed transactions to simulate concurrently")
+	watchCmd.Flags().DurationVar(&watchDrainFlag, "drain-timeout", 30*time.Second, "How long transactions in flight may finish after Ctrl+C")
+	addTracingFlags(watchCmd)
+	addWebhookFlags(watchCmd)
+	addAlertFlags(watchCmd)
+	addHookFlags(watchCmd)
+
+	rootCmd.AddCommand(watchCmd)
+}
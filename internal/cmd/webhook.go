@@ -0,0 +1,69 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/webhook"
+	"github.com/spf13/cobra"
+)
+
+var (
+	webhookURLFlag     string
+	webhookTypeFlag    string
+	webhookFilterFlags []string
+)
+
+// addWebhookFlags registers the failure webhook flags on a long-running command
+func addWebhookFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&webhookURLFlag, "webhook-url", "", "POST a notification to this URL when a simulation fails (or set ERST_WEBHOOK_URL)")
+	markSensitive(cmd, "webhook-url")
+	cmd.Flags().StringVar(&webhookTypeFlag, "webhook-type", "", "Webhook payload format: json, slack or discord (default json)")
+	cmd.Flags().StringArrayVar(&webhookFilterFlags, "webhook-filter", nil, "Only notify when the error matches this regular expression (repeatable)")
+}
+
+// newFailureNotifier returns the notifier for failed simulations configured by
+// the webhook flags, falling back to webhook_url, webhook_type and
+// webhook_filter. The notifier is disabled when no URL is set.
+func newFailureNotifier() (*webhook.SimulatorNotifier, error) {
+	url, typ, filters := webhookURLFlag, webhookTypeFlag, webhookFilterFlags
+	if cfg, err := config.Load(); err == nil {
+		if url == "" {
+			url = cfg.WebhookURL
+		}
+		if typ == "" {
+			typ = cfg.WebhookType
+		}
+		if len(filters) == 0 && cfg.WebhookFilter != "" {
+			filters = []string{cfg.WebhookFilter}
+		}
+	}
+	if url == "" {
+		return webhook.NewSimulatorNotifier(webhook.NotifierConfig{})
+	}
+
+	typ = strings.ToLower(typ)
+	switch webhook.WebhookType(typ) {
+	case "":
+		typ = string(webhook.JSONWebhook)
+	case webhook.JSONWebhook, webhook.SlackWebhook, webhook.DiscordWebhook:
+	default:
+		return nil, errors.WrapValidationError(fmt.Sprintf("--webhook-type must be json, slack or discord, got %q", typ))
+	}
+
+	notifier, err := webhook.NewSimulatorNotifier(webhook.NotifierConfig{
+		Enabled:       true,
+		ErrorOnly:     true,
+		Webhooks:      []webhook.Config{{Type: webhook.WebhookType(typ), URL: url, Retries: 2}},
+		ErrorPatterns: filters,
+	})
+	if err != nil {
+		return nil, errors.WrapValidationError(err.Error())
+	}
+	return notifier, nil
+}
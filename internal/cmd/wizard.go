@@ -6,6 +6,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/dotandev/hintents/internal/decoder"
 	"github.com/dotandev/hintents/internal/errors"
 	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/wizard"
@@ -23,8 +24,19 @@ var wizardCmd = &cobra.Command{
 		if account == "" {
 			return errors.WrapCliArgumentRequired("account")
 		}
+		if address, err := resolveAccount(account); err == nil {
+			account = address
+		}
+		// Horizon lists transactions by G... account, which includes those
+		// of its muxed addresses
+		account = decoder.BaseAccount(account)
 
-		client, err := rpc.NewClient(rpc.WithNetwork(rpc.Network(network)))
+		opts := []rpc.ClientOption{
+			rpc.WithNetwork(rpc.Network(network)),
+			rpc.WithToken(resolveRPCToken("", network)),
+		}
+		opts = append(opts, rpcEndpointOptions("", network)...)
+		client, err := rpc.NewClient(opts...)
 		if err != nil {
 			return errors.WrapValidationError(err.Error())
 		}
@@ -42,7 +54,7 @@ var wizardCmd = &cobra.Command{
 }
 
 func init() {
-	wizardCmd.Flags().StringP("account", "a", "", "Stellar account address")
-	wizardCmd.Flags().StringP("network", "n", string(rpc.Mainnet), "Network (testnet, mainnet, futurenet)")
+	wizardCmd.Flags().StringP("account", "a", "", "Stellar account address (G... or M...), alias or identity name")
+	wizardCmd.Flags().StringP("network", "n", string(rpc.Mainnet), "Network (testnet, mainnet, futurenet, local)")
 	rootCmd.AddCommand(wizardCmd)
 }
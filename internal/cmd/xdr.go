@@ -6,6 +6,9 @@ package cmd
 import (
 	"encoding/base64"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/dotandev/hintents/internal/decoder"
 	"github.com/dotandev/hintents/internal/errors"
@@ -16,6 +19,9 @@ var (
 	xdrFormat string
 	xdrData   string
 	xdrType   string
+
+	xdrDecodeType string
+	xdrEncodeType string
 )
 
 var xdrCmd = &cobra.Command{
@@ -66,12 +72,100 @@ func xdrExec(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var xdrDecodeCmd = &cobra.Command{
+	Use:   "decode [xdr]",
+	Short: "Decode base64 XDR into readable JSON",
+	Long: `Decode base64 XDR into readable JSON, with enums named, accounts and
+contracts as strkeys and hashes and opaque data as hex.
+
+The type is detected when --type is auto: the input is decoded as each of
+` + strings.Join(decoder.XDRTypeNames(), ", ") + `
+in turn and the first that consumes it exactly wins. When it also decodes as other types they are
+listed under "candidates"; pass --type to pick one.
+
+The XDR is read from stdin when no argument, or "-", is given.`,
+	Example: `  erst xdr decode AAAADwAAAAh0cmFuc2Zlcg==
+  erst xdr decode --type meta < meta.b64`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input := "-"
+		if len(args) == 1 {
+			input = args[0]
+		}
+		if input == "-" {
+			data, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("failed to read XDR from stdin: %v", err))
+			}
+			input = string(data)
+		}
+		if strings.TrimSpace(input) == "" {
+			return errors.WrapCliArgumentRequired("xdr")
+		}
+
+		result, err := decoder.DecodeXDR(input, xdrDecodeType)
+		if err != nil {
+			return errors.WrapValidationError(err.Error())
+		}
+		if len(result.Candidates) > 0 {
+			fmt.Fprintf(os.Stderr, "Decoded as %s; it also decodes as %s (use --type to choose)\n", result.Type, strings.Join(result.Candidates, ", "))
+		}
+		return printJSON(result)
+	},
+}
+
+var xdrEncodeCmd = &cobra.Command{
+	Use:   "encode [json-file|-]",
+	Short: "Encode JSON into base64 XDR",
+	Long: `Encode the JSON form 'erst xdr decode' prints back into base64 XDR, for
+hand-editing ledger entries to pass to --override-entry or envelopes to pass
+to 'erst simulate'.
+
+The input is either the whole output of 'erst xdr decode', whose type is used,
+or the bare value together with --type. It is read from stdin when no file,
+or "-", is given.`,
+	Example: `  erst xdr decode --type ledger-entry AAAA... > entry.json
+  # edit entry.json
+  erst xdr encode entry.json > entry.xdr
+  erst simulate --envelope tx.xdr --override-entry entry.xdr
+
+  echo '{"type": "ScValTypeScvU32", "u32": 7}' | erst xdr encode --type scval`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var data []byte
+		var err error
+		if len(args) == 0 || args[0] == "-" {
+			data, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			data, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			return errors.WrapValidationError(fmt.Sprintf("failed to read JSON: %v", err))
+		}
+		if strings.TrimSpace(string(data)) == "" {
+			return errors.WrapCliArgumentRequired("json-file")
+		}
+
+		b64, err := decoder.EncodeXDR(data, xdrEncodeType)
+		if err != nil {
+			return errors.WrapValidationError(err.Error())
+		}
+		fmt.Println(b64)
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(xdrCmd)
+	xdrCmd.AddCommand(xdrDecodeCmd)
+	xdrCmd.AddCommand(xdrEncodeCmd)
 
 	xdrCmd.Flags().StringVar(&xdrData, "data", "", "Base64-encoded XDR data to decode")
 	xdrCmd.Flags().StringVar(&xdrFormat, "format", "json", "Output format: json or table")
 	xdrCmd.Flags().StringVar(&xdrType, "type", "ledger-entry", "XDR type: ledger-entry, diagnostic-event")
 
 	_ = xdrCmd.MarkFlagRequired("data")
+
+	xdrDecodeCmd.Flags().StringVar(&xdrDecodeType, "type", "auto", "XDR type: auto, "+strings.Join(decoder.XDRTypeNames(), ", "))
+	xdrEncodeCmd.Flags().StringVar(&xdrEncodeType, "type", "auto", "XDR type: auto (taken from decode output), "+strings.Join(decoder.XDRTypeNames(), ", "))
 }
@@ -0,0 +1,84 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package compare
+
+import (
+	"sort"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// FunctionCostDiff is the change in cost of one contract function between the
+// two runs. Like BudgetDiff, deltas are local minus on-chain, so the right
+// side is the baseline. A function missing from one side has zero cost there.
+type FunctionCostDiff struct {
+	Contract string
+	Function string
+
+	LocalCalls   int
+	OnChainCalls int
+
+	// CPU and memory include sub-calls; SelfCPU excludes them
+	LocalCPU       uint64
+	OnChainCPU     uint64
+	LocalSelfCPU   uint64
+	OnChainSelfCPU uint64
+	LocalMem       uint64
+	OnChainMem     uint64
+
+	CPUDelta     int64
+	SelfCPUDelta int64
+	MemoryDelta  int64
+}
+
+// compareFunctionCosts pairs the per-function costs of both runs by contract
+// and function, largest change in the function's own CPU first
+func compareFunctionCosts(local, onChain []simulator.FunctionCost) []FunctionCostDiff {
+	type key struct{ contract, function string }
+	byKey := make(map[key]*FunctionCostDiff)
+	var order []key
+	get := func(c simulator.FunctionCost) *FunctionCostDiff {
+		k := key{c.Contract, c.Function}
+		d, ok := byKey[k]
+		if !ok {
+			d = &FunctionCostDiff{Contract: c.Contract, Function: c.Function}
+			byKey[k] = d
+			order = append(order, k)
+		}
+		return d
+	}
+
+	for _, c := range local {
+		d := get(c)
+		d.LocalCalls, d.LocalCPU, d.LocalSelfCPU, d.LocalMem = c.Calls, c.CPUInsns, c.SelfCPUInsns, c.MemBytes
+	}
+	for _, c := range onChain {
+		d := get(c)
+		d.OnChainCalls, d.OnChainCPU, d.OnChainSelfCPU, d.OnChainMem = c.Calls, c.CPUInsns, c.SelfCPUInsns, c.MemBytes
+	}
+
+	diffs := make([]FunctionCostDiff, 0, len(order))
+	for _, k := range order {
+		d := byKey[k]
+		d.CPUDelta = int64(d.LocalCPU) - int64(d.OnChainCPU)
+		d.SelfCPUDelta = int64(d.LocalSelfCPU) - int64(d.OnChainSelfCPU)
+		d.MemoryDelta = int64(d.LocalMem) - int64(d.OnChainMem)
+		diffs = append(diffs, *d)
+	}
+	sort.SliceStable(diffs, func(i, j int) bool {
+		a, b := abs64(diffs[i].SelfCPUDelta), abs64(diffs[j].SelfCPUDelta)
+		if a != b {
+			return a > b
+		}
+		return abs64(diffs[i].CPUDelta) > abs64(diffs[j].CPUDelta)
+	})
+	return diffs
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -9,6 +9,7 @@ package compare
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/dotandev/hintents/internal/simulator"
@@ -81,19 +82,52 @@ type CallPathDivergence struct {
 	OnChainSummary string
 }
 
+// ReturnValueDiff is a positional divergence in the host function return values.
+type ReturnValueDiff struct {
+	Index int
+
+	// Local and OnChain are base64 XDR ScVals ("" if absent).
+	Local   string
+	OnChain string
+
+	Divergent bool
+}
+
+// StorageDiff compares the final state of one ledger entry written by either run.
+type StorageDiff struct {
+	// Key is the base64 XDR LedgerKey.
+	Key string
+
+	// Local and OnChain are base64 XDR LedgerEntries ("" if deleted or not written).
+	Local   string
+	OnChain string
+
+	// LocalWritten and OnChainWritten report whether the entry was in each
+	// run's read-write footprint.
+	LocalWritten   bool
+	OnChainWritten bool
+
+	Divergent bool
+}
+
 // DiffResult holds the complete comparison output for a single replay pair.
 type DiffResult struct {
 	StatusDiff          StatusDiff
 	EventDiffs          []EventDiff
 	DiagnosticDiffs     []DiagnosticDiff
 	BudgetDiff          *BudgetDiff
+	FunctionCostDiffs   []FunctionCostDiff
 	CallPathDivergences []CallPathDivergence
+	ReturnValueDiffs    []ReturnValueDiff
+	StorageDiffs        []StorageDiff
 
 	// Summary fields
-	TotalEvents      int
-	DivergentEvents  int
-	IdenticalEvents  int
-	HasDivergence    bool
+	TotalEvents           int
+	DivergentEvents       int
+	IdenticalEvents       int
+	DivergentReturnValues int
+	DivergentStorage      int
+	HasDivergence         bool
 }
 
 // Diff compares two SimulationResponse objects (local vs on-chain) and returns
@@ -114,11 +148,18 @@ func Diff(local, onChain *simulator.SimulationResponse) *DiffResult {
 	if local.BudgetUsage != nil || onChain.BudgetUsage != nil {
 		result.BudgetDiff = compareBudget(local.BudgetUsage, onChain.BudgetUsage)
 	}
+	if len(local.FunctionCosts) > 0 || len(onChain.FunctionCosts) > 0 {
+		result.FunctionCostDiffs = compareFunctionCosts(local.FunctionCosts, onChain.FunctionCosts)
+	}
 
 	// 5. Call-path divergences (extracted from diagnostic diff)
 	result.CallPathDivergences = extractCallPathDivergences(result.DiagnosticDiffs)
 
-	// 6. Aggregate counters
+	// 6. Return values and storage writes
+	result.ReturnValueDiffs = compareReturnValues(local.ReturnValues, onChain.ReturnValues)
+	result.StorageDiffs = compareStorageWrites(local.StorageWrites, onChain.StorageWrites)
+
+	// 7. Aggregate counters
 	total := len(result.EventDiffs)
 	div := 0
 	for _, d := range result.EventDiffs {
@@ -129,9 +170,21 @@ func Diff(local, onChain *simulator.SimulationResponse) *DiffResult {
 	result.TotalEvents = total
 	result.DivergentEvents = div
 	result.IdenticalEvents = total - div
+	for _, d := range result.ReturnValueDiffs {
+		if d.Divergent {
+			result.DivergentReturnValues++
+		}
+	}
+	for _, d := range result.StorageDiffs {
+		if d.Divergent {
+			result.DivergentStorage++
+		}
+	}
 	result.HasDivergence = result.StatusDiff.Match == false ||
 		div > 0 ||
-		len(result.CallPathDivergences) > 0
+		len(result.CallPathDivergences) > 0 ||
+		result.DivergentReturnValues > 0 ||
+		result.DivergentStorage > 0
 
 	return result
 }
@@ -270,6 +323,63 @@ func compareBudget(local, onChain *simulator.BudgetUsage) *BudgetDiff {
 	return bd
 }
 
+func compareReturnValues(local, onChain []string) []ReturnValueDiff {
+	maxLen := len(local)
+	if len(onChain) > maxLen {
+		maxLen = len(onChain)
+	}
+
+	diffs := make([]ReturnValueDiff, maxLen)
+	for i := 0; i < maxLen; i++ {
+		d := ReturnValueDiff{Index: i}
+		if i < len(local) {
+			d.Local = local[i]
+		}
+		if i < len(onChain) {
+			d.OnChain = onChain[i]
+		}
+		d.Divergent = i >= len(local) || i >= len(onChain) || d.Local != d.OnChain
+		diffs[i] = d
+	}
+	return diffs
+}
+
+// compareStorageWrites matches entries by ledger key and returns one diff per
+// key written by either run, ordered by key.
+func compareStorageWrites(local, onChain []simulator.StorageWrite) []StorageDiff {
+	byKey := make(map[string]*StorageDiff)
+	var keys []string
+	get := func(key string) *StorageDiff {
+		d, ok := byKey[key]
+		if !ok {
+			d = &StorageDiff{Key: key}
+			byKey[key] = d
+			keys = append(keys, key)
+		}
+		return d
+	}
+
+	for _, w := range local {
+		d := get(w.Key)
+		d.Local = w.Entry
+		d.LocalWritten = true
+	}
+	for _, w := range onChain {
+		d := get(w.Key)
+		d.OnChain = w.Entry
+		d.OnChainWritten = true
+	}
+
+	sort.Strings(keys)
+	diffs := make([]StorageDiff, 0, len(keys))
+	for _, key := range keys {
+		d := byKey[key]
+		d.Divergent = d.LocalWritten != d.OnChainWritten || d.Local != d.OnChain
+		diffs = append(diffs, *d)
+	}
+	return diffs
+}
+
 func extractCallPathDivergences(diffs []DiagnosticDiff) []CallPathDivergence {
 	var divergences []CallPathDivergence
 	for _, d := range diffs {
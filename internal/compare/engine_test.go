@@ -243,6 +243,84 @@ func TestFormatDelta(t *testing.T) {
 	assert.Equal(t, "0", formatDelta(0))
 }
 
+// ─── Return values / storage writes ───────────────────────────────────────────
+
+func TestDiff_ReturnValues(t *testing.T) {
+	local := makeResp("success", nil, nil, nil)
+	local.ReturnValues = []string{"AAAAAQ==", "AAAAAw=="}
+	onChain := makeResp("success", nil, nil, nil)
+	onChain.ReturnValues = []string{"AAAAAQ=="}
+
+	result := Diff(local, onChain)
+	require.Len(t, result.ReturnValueDiffs, 2)
+	assert.False(t, result.ReturnValueDiffs[0].Divergent)
+	assert.True(t, result.ReturnValueDiffs[1].Divergent, "missing return value should diverge")
+	assert.Equal(t, 1, result.DivergentReturnValues)
+	assert.True(t, result.HasDivergence)
+}
+
+func TestDiff_StorageWrites(t *testing.T) {
+	local := makeResp("success", nil, nil, nil)
+	local.StorageWrites = []simulator.StorageWrite{
+		{Key: "k2", Entry: "v2"},
+		{Key: "k1", Entry: "v1"},
+		{Key: "k3"},
+	}
+	onChain := makeResp("success", nil, nil, nil)
+	onChain.StorageWrites = []simulator.StorageWrite{
+		{Key: "k1", Entry: "v1"},
+		{Key: "k2", Entry: "changed"},
+		{Key: "k4", Entry: "v4"},
+	}
+
+	result := Diff(local, onChain)
+	require.Len(t, result.StorageDiffs, 4)
+	assert.Equal(t, []string{"k1", "k2", "k3", "k4"}, []string{
+		result.StorageDiffs[0].Key, result.StorageDiffs[1].Key,
+		result.StorageDiffs[2].Key, result.StorageDiffs[3].Key,
+	}, "diffs are ordered by key")
+	assert.False(t, result.StorageDiffs[0].Divergent)
+	assert.True(t, result.StorageDiffs[1].Divergent, "different final value")
+	assert.True(t, result.StorageDiffs[2].Divergent, "deleted locally, not written on-chain")
+	assert.True(t, result.StorageDiffs[2].LocalWritten)
+	assert.False(t, result.StorageDiffs[2].OnChainWritten)
+	assert.True(t, result.StorageDiffs[3].Divergent, "written on-chain only")
+	assert.Equal(t, 3, result.DivergentStorage)
+	assert.True(t, result.HasDivergence)
+}
+
+func TestDiff_FunctionCosts(t *testing.T) {
+	local := makeResp("success", nil, nil, nil)
+	local.FunctionCosts = []simulator.FunctionCost{
+		{Contract: "C1", Function: "swap", Calls: 1, CPUInsns: 5000, SelfCPUInsns: 1000},
+		{Contract: "C2", Function: "transfer", Calls: 2, CPUInsns: 4000, SelfCPUInsns: 4000},
+		{Contract: "C2", Function: "audit", Calls: 1, CPUInsns: 300, SelfCPUInsns: 300},
+	}
+	onChain := makeResp("success", nil, nil, nil)
+	onChain.FunctionCosts = []simulator.FunctionCost{
+		{Contract: "C1", Function: "swap", Calls: 1, CPUInsns: 3500, SelfCPUInsns: 1000},
+		{Contract: "C2", Function: "transfer", Calls: 2, CPUInsns: 2500, SelfCPUInsns: 2500},
+		{Contract: "C3", Function: "log", Calls: 1, CPUInsns: 200, SelfCPUInsns: 200},
+	}
+
+	result := Diff(local, onChain)
+	require.Len(t, result.FunctionCostDiffs, 4)
+	transfer := result.FunctionCostDiffs[0]
+	assert.Equal(t, "transfer", transfer.Function, "largest self CPU change first")
+	assert.Equal(t, int64(1500), transfer.SelfCPUDelta)
+	assert.Equal(t, int64(1500), transfer.CPUDelta)
+	assert.Equal(t, "audit", result.FunctionCostDiffs[1].Function)
+	assert.Equal(t, 0, result.FunctionCostDiffs[1].OnChainCalls, "new function has no baseline")
+	assert.Equal(t, int64(-200), result.FunctionCostDiffs[2].SelfCPUDelta, "removed function")
+	assert.Equal(t, int64(0), result.FunctionCostDiffs[3].SelfCPUDelta)
+	assert.Equal(t, int64(1500), result.FunctionCostDiffs[3].CPUDelta, "sub-calls included")
+	assert.False(t, result.HasDivergence, "cost changes alone are not a divergence")
+
+	assert.NotPanics(t, func() {
+		RenderWithLabels(result, Labels{Title: "DIFF", Left: "A", Right: "B"})
+	})
+}
+
 // ─── Render smoke test ────────────────────────────────────────────────────────
 
 // TestRender_NoError verifies Render does not panic on any valid DiffResult.
@@ -266,6 +344,17 @@ func TestRender_NoError(t *testing.T) {
 	})
 }
 
+func TestRenderWithLabels_NoError(t *testing.T) {
+	local := makeResp("success", nil, nil, nil)
+	local.ReturnValues = []string{"AAAAAQ=="}
+	local.StorageWrites = []simulator.StorageWrite{{Key: "not-xdr", Entry: "not-xdr"}}
+	onChain := makeResp("error", nil, nil, nil)
+
+	assert.NotPanics(t, func() {
+		RenderWithLabels(Diff(local, onChain), Labels{Title: "DIFF", Left: "A", Right: "B"})
+	})
+}
+
 func TestRender_NilResult_NoError(t *testing.T) {
 	assert.NotPanics(t, func() {
 		Render(nil)
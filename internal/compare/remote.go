@@ -0,0 +1,200 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package compare
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// ResourceTolerance is the relative CPU or memory difference between the
+// local and the RPC simulation that is still reported as a match
+const ResourceTolerance = 0.01
+
+// RemoteSimulation is the part of a Soroban RPC simulateTransaction result
+// that can be compared with a local simulation
+type RemoteSimulation struct {
+	Error    string
+	CPUInsns uint64
+	MemBytes uint64
+	// Events are base64 XDR DiagnosticEvents
+	Events []string
+	// ReturnValue is the base64 XDR ScVal returned by the host function
+	ReturnValue  string
+	LatestLedger uint32
+}
+
+// RemoteDiff compares a local simulation with the node's simulateTransaction.
+// Event and return value lists hold rendered values.
+type RemoteDiff struct {
+	LatestLedger uint32 `json:"latest_ledger,omitempty"`
+
+	LocalStatus  string `json:"local_status"`
+	LocalError   string `json:"local_error,omitempty"`
+	RemoteStatus string `json:"remote_status"`
+	RemoteError  string `json:"remote_error,omitempty"`
+
+	LocalCPU    uint64 `json:"local_cpu_instructions"`
+	RemoteCPU   uint64 `json:"remote_cpu_instructions"`
+	CPUDelta    int64  `json:"cpu_delta"`
+	LocalMem    uint64 `json:"local_memory_bytes"`
+	RemoteMem   uint64 `json:"remote_memory_bytes"`
+	MemoryDelta int64  `json:"memory_delta"`
+
+	// EventsCompared is false when the local simulator did not report event
+	// XDR, in which case only the contract event counts are compared
+	EventsCompared bool     `json:"events_compared"`
+	LocalEvents    []string `json:"local_events"`
+	RemoteEvents   []string `json:"remote_events"`
+
+	LocalReturn  string `json:"local_return,omitempty"`
+	RemoteReturn string `json:"remote_return,omitempty"`
+
+	// Divergences lists what differs, most likely cause first; empty when
+	// the simulations agree
+	Divergences []string `json:"divergences"`
+}
+
+// HasDivergence reports whether the two simulations disagree
+func (d *RemoteDiff) HasDivergence() bool {
+	return len(d.Divergences) > 0
+}
+
+// DiffRemote compares the status, resources, contract events and return
+// value of a local simulation against the RPC node's. Only contract events
+// are compared, since which diagnostic events are emitted depends on each
+// side's diagnostic settings.
+func DiffRemote(local *simulator.SimulationResponse, remote *RemoteSimulation) *RemoteDiff {
+	d := &RemoteDiff{
+		LatestLedger: remote.LatestLedger,
+		LocalStatus:  local.Status,
+		LocalError:   local.Error,
+		RemoteStatus: "success",
+		RemoteError:  remote.Error,
+		RemoteCPU:    remote.CPUInsns,
+		RemoteMem:    remote.MemBytes,
+		Divergences:  []string{},
+	}
+	if remote.Error != "" {
+		d.RemoteStatus = "error"
+	}
+	localFailed := local.Status == "error"
+	if localFailed != (remote.Error != "") {
+		d.Divergences = append(d.Divergences, fmt.Sprintf("status: local %s, RPC %s", local.Status, d.RemoteStatus))
+	}
+
+	if local.BudgetUsage != nil {
+		d.LocalCPU = local.BudgetUsage.CPUInstructions
+		d.LocalMem = local.BudgetUsage.MemoryBytes
+	}
+	d.CPUDelta = int64(d.LocalCPU) - int64(d.RemoteCPU)
+	d.MemoryDelta = int64(d.LocalMem) - int64(d.RemoteMem)
+	// A failed simulation reports no cost on the RPC side, and nodes that
+	// report no memory figure leave it uncompared
+	if !localFailed && remote.Error == "" {
+		if d.RemoteCPU > 0 && beyondTolerance(d.CPUDelta, d.RemoteCPU) {
+			d.Divergences = append(d.Divergences, fmt.Sprintf("CPU instructions: local %d, RPC %d (%s)", d.LocalCPU, d.RemoteCPU, formatDelta(d.CPUDelta)))
+		}
+		if d.RemoteMem > 0 && beyondTolerance(d.MemoryDelta, d.RemoteMem) {
+			d.Divergences = append(d.Divergences, fmt.Sprintf("memory bytes: local %d, RPC %d (%s)", d.LocalMem, d.RemoteMem, formatDelta(d.MemoryDelta)))
+		}
+	}
+
+	d.RemoteEvents = remoteContractEvents(remote.Events)
+	d.LocalEvents, d.EventsCompared = localContractEvents(local.DiagnosticEvents)
+	switch {
+	case len(d.LocalEvents) != len(d.RemoteEvents):
+		d.Divergences = append(d.Divergences, fmt.Sprintf("contract events: local %d, RPC %d", len(d.LocalEvents), len(d.RemoteEvents)))
+	case d.EventsCompared:
+		for i := range d.LocalEvents {
+			if d.LocalEvents[i] != d.RemoteEvents[i] {
+				d.Divergences = append(d.Divergences, fmt.Sprintf("contract event %d: local %s, RPC %s", i, d.LocalEvents[i], d.RemoteEvents[i]))
+			}
+		}
+	}
+
+	if len(local.ReturnValues) > 0 {
+		d.LocalReturn = decoder.RenderScVal(local.ReturnValues[0], local.ReturnValues[0])
+	}
+	d.RemoteReturn = decoder.RenderScVal(remote.ReturnValue, remote.ReturnValue)
+	if !localFailed && remote.Error == "" && d.LocalReturn != d.RemoteReturn {
+		d.Divergences = append(d.Divergences, fmt.Sprintf("return value: local %s, RPC %s", d.LocalReturn, d.RemoteReturn))
+	}
+	return d
+}
+
+func beyondTolerance(delta int64, base uint64) bool {
+	if delta == 0 {
+		return false
+	}
+	if base == 0 {
+		return true
+	}
+	return float64(abs64(delta))/float64(base) > ResourceTolerance
+}
+
+// remoteContractEvents renders the contract events among base64 XDR
+// DiagnosticEvents
+func remoteContractEvents(events []string) []string {
+	out := []string{}
+	for _, raw := range events {
+		var diag xdr.DiagnosticEvent
+		if err := xdr.SafeUnmarshalBase64(raw, &diag); err != nil || diag.Event.Type != xdr.ContractEventTypeContract {
+			continue
+		}
+		out = append(out, decoder.FormatEvent(raw))
+	}
+	return out
+}
+
+// localContractEvents renders the local contract events the same way as
+// remoteContractEvents. ok is false when an event lacks its XDR.
+func localContractEvents(events []simulator.DiagnosticEvent) ([]string, bool) {
+	out := []string{}
+	ok := true
+	for _, e := range events {
+		if e.EventType != "contract" {
+			continue
+		}
+		if len(e.TopicsXdr) == 0 || e.DataXdr == "" {
+			ok = false
+		}
+		topics := decoder.RenderScVals(e.TopicsXdr, e.Topics)
+		out = append(out, strings.TrimSpace(fmt.Sprintf("%s [%s] %s", contractIDStr(e.ContractID), strings.Join(topics, ", "), decoder.RenderScVal(e.DataXdr, e.Data))))
+	}
+	return out, ok
+}
+
+// RenderRemote prints a RemoteDiff to stdout
+func RenderRemote(d *RemoteDiff) {
+	fmt.Printf("\n%s\n", sectionTitle("Local vs RPC simulateTransaction"))
+	if d.LatestLedger > 0 {
+		fmt.Printf("  RPC simulated at ledger %d\n", d.LatestLedger)
+	}
+	fmt.Printf("  Status:        local %s, RPC %s\n", statusLine(d.LocalStatus, d.LocalError), statusLine(d.RemoteStatus, d.RemoteError))
+	fmt.Printf("  CPU:           local %d, RPC %d (%s)\n", d.LocalCPU, d.RemoteCPU, colorizeDelta(formatDelta(d.CPUDelta), d.CPUDelta))
+	if d.RemoteMem > 0 {
+		fmt.Printf("  Memory:        local %d, RPC %d (%s)\n", d.LocalMem, d.RemoteMem, colorizeDelta(formatDelta(d.MemoryDelta), d.MemoryDelta))
+	} else {
+		fmt.Printf("  Memory:        local %d, RPC not reported\n", d.LocalMem)
+	}
+	fmt.Printf("  Events:        local %d, RPC %d\n", len(d.LocalEvents), len(d.RemoteEvents))
+	if !d.EventsCompared {
+		fmt.Printf("                 (the local simulator did not report event XDR; only counts compared)\n")
+	}
+
+	if !d.HasDivergence() {
+		fmt.Printf("  %s The local simulation matches the RPC node\n", diffMarker(false))
+		return
+	}
+	fmt.Printf("  %s Divergences:\n", diffMarker(true))
+	for _, div := range d.Divergences {
+		fmt.Printf("    - %s\n", div)
+	}
+	fmt.Printf("  A divergence points to a local simulator bug, a protocol mismatch or ledger state that changed since it was fetched.\n")
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package compare
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustBase64(t *testing.T, v interface{}) string {
+	t.Helper()
+	b64, err := xdr.MarshalBase64(v)
+	require.NoError(t, err)
+	return b64
+}
+
+// transferEvents returns the same contract event as the RPC node and the
+// local simulator report it
+func transferEvents(t *testing.T, amount int32) (remote string, local simulator.DiagnosticEvent) {
+	t.Helper()
+	contract := xdr.ContractId{7}
+	sym := xdr.ScSymbol("transfer")
+	topic := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+	i32 := xdr.Int32(amount)
+	data := xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &i32}
+
+	remote = mustBase64(t, xdr.DiagnosticEvent{
+		InSuccessfulContractCall: true,
+		Event: xdr.ContractEvent{
+			ContractId: &contract,
+			Type:       xdr.ContractEventTypeContract,
+			Body: xdr.ContractEventBody{V: 0, V0: &xdr.ContractEventV0{
+				Topics: xdr.ScVec{topic},
+				Data:   data,
+			}},
+		},
+	})
+	addr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contract}
+	id, err := addr.String()
+	require.NoError(t, err)
+	local = simulator.DiagnosticEvent{
+		EventType:  "contract",
+		ContractID: &id,
+		TopicsXdr:  []string{mustBase64(t, topic)},
+		DataXdr:    mustBase64(t, data),
+	}
+	return remote, local
+}
+
+func TestDiffRemote_Match(t *testing.T) {
+	remoteEvent, localEvent := transferEvents(t, 5)
+	local := &simulator.SimulationResponse{
+		Status:           "success",
+		BudgetUsage:      &simulator.BudgetUsage{CPUInstructions: 1_000_000, MemoryBytes: 2048},
+		DiagnosticEvents: []simulator.DiagnosticEvent{localEvent, {EventType: "diagnostic"}},
+	}
+	remote := &RemoteSimulation{CPUInsns: 1_005_000, MemBytes: 2048, Events: []string{remoteEvent}}
+
+	d := DiffRemote(local, remote)
+	assert.False(t, d.HasDivergence(), "divergences: %v", d.Divergences)
+	assert.True(t, d.EventsCompared)
+	require.Len(t, d.LocalEvents, 1)
+	assert.Equal(t, d.RemoteEvents, d.LocalEvents)
+	assert.Equal(t, int64(-5000), d.CPUDelta)
+}
+
+func TestDiffRemote_Divergences(t *testing.T) {
+	remoteEvent, _ := transferEvents(t, 5)
+	_, localEvent := transferEvents(t, 6)
+	local := &simulator.SimulationResponse{
+		Status:           "success",
+		BudgetUsage:      &simulator.BudgetUsage{CPUInstructions: 2_000_000, MemoryBytes: 2048},
+		DiagnosticEvents: []simulator.DiagnosticEvent{localEvent},
+	}
+	remote := &RemoteSimulation{CPUInsns: 1_000_000, MemBytes: 2048, Events: []string{remoteEvent}}
+
+	d := DiffRemote(local, remote)
+	require.Len(t, d.Divergences, 2)
+	assert.Contains(t, d.Divergences[0], "CPU instructions")
+	assert.Contains(t, d.Divergences[1], "contract event 0")
+}
+
+func TestDiffRemote_Status(t *testing.T) {
+	local := &simulator.SimulationResponse{Status: "success", BudgetUsage: &simulator.BudgetUsage{CPUInstructions: 10}}
+	remote := &RemoteSimulation{Error: "HostError: Error(Storage, MissingValue)"}
+
+	d := DiffRemote(local, remote)
+	assert.Equal(t, "error", d.RemoteStatus)
+	require.Len(t, d.Divergences, 1, "resources of a failed RPC simulation are not compared")
+	assert.Contains(t, d.Divergences[0], "status")
+}
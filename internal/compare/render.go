@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dotandev/hintents/internal/decoder"
 	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/stellar/go-stellar-sdk/xdr"
 )
 
 const (
@@ -16,59 +18,99 @@ const (
 	columnSep   = " | "
 )
 
+// Labels names the two sides of a diff in rendered output.
+type Labels struct {
+	Title string
+	Left  string
+	Right string
+}
+
+// DefaultLabels are used by Render for local-vs-on-chain comparisons.
+var DefaultLabels = Labels{
+	Title: "COMPARE REPLAY  ─  Local WASM  vs  On-Chain WASM",
+	Left:  "Local",
+	Right: "On-Chain",
+}
+
 // Render prints a human-readable side-by-side diff of a DiffResult to stdout.
 // It uses the visualizer package for theme-aware colours.
 func Render(result *DiffResult) {
+	RenderWithLabels(result, DefaultLabels)
+}
+
+// RenderWithLabels is Render with custom names for the two sides.
+func RenderWithLabels(result *DiffResult, labels Labels) {
 	if result == nil {
 		return
 	}
 
-	printHeader()
+	printHeader(labels.Title)
 
 	// ── Status ────────────────────────────────────────────────────────────────
 	fmt.Println(sectionTitle("Execution Status"))
-	renderStatus(result.StatusDiff)
+	renderStatus(result.StatusDiff, labels)
 
 	// ── Budget / Resource Usage ───────────────────────────────────────────────
 	if result.BudgetDiff != nil {
 		fmt.Println()
-		fmt.Println(sectionTitle("Resource Usage (Local vs On-Chain)"))
-		renderBudget(result.BudgetDiff)
+		fmt.Println(sectionTitle(fmt.Sprintf("Resource Usage (%s vs %s)", labels.Left, labels.Right)))
+		renderBudget(result.BudgetDiff, labels)
+	}
+
+	// ── Function Costs ────────────────────────────────────────────────────────
+	if len(result.FunctionCostDiffs) > 0 {
+		fmt.Println()
+		fmt.Println(sectionTitle(fmt.Sprintf("Function Costs (%s vs %s)", labels.Left, labels.Right)))
+		renderFunctionCosts(result.FunctionCostDiffs, labels)
+	}
+
+	// ── Return Values ─────────────────────────────────────────────────────────
+	if len(result.ReturnValueDiffs) > 0 {
+		fmt.Println()
+		fmt.Println(sectionTitle("Return Values"))
+		renderReturnValues(result.ReturnValueDiffs, labels)
+	}
+
+	// ── Storage Writes ────────────────────────────────────────────────────────
+	if len(result.StorageDiffs) > 0 {
+		fmt.Println()
+		fmt.Println(sectionTitle("Storage Writes"))
+		renderStorageDiffs(result.StorageDiffs, labels)
 	}
 
 	// ── Raw Event Diff ────────────────────────────────────────────────────────
 	if len(result.EventDiffs) > 0 {
 		fmt.Println()
 		fmt.Println(sectionTitle("Event Log Diff"))
-		renderEventDiffs(result.EventDiffs)
+		renderEventDiffs(result.EventDiffs, labels)
 	}
 
 	// ── Diagnostic Event Diff ─────────────────────────────────────────────────
 	if len(result.DiagnosticDiffs) > 0 {
 		fmt.Println()
 		fmt.Println(sectionTitle("Diagnostic Event Diff"))
-		renderDiagnosticDiffs(result.DiagnosticDiffs)
+		renderDiagnosticDiffs(result.DiagnosticDiffs, labels)
 	}
 
 	// ── Divergent Call Paths ──────────────────────────────────────────────────
 	if len(result.CallPathDivergences) > 0 {
 		fmt.Println()
 		fmt.Println(sectionTitle("Divergent Call Paths"))
-		renderCallPaths(result.CallPathDivergences)
+		renderCallPaths(result.CallPathDivergences, labels)
 	}
 
 	// ── Summary ───────────────────────────────────────────────────────────────
 	fmt.Println()
-	renderSummary(result)
+	renderSummary(result, labels)
 }
 
 // ─── internal renderers ───────────────────────────────────────────────────────
 
-func printHeader() {
+func printHeader(heading string) {
 	sep := strings.Repeat("─", colWidth*2+len(columnSep))
 	fmt.Println()
 	fmt.Println(visualizer.Colorize("╔"+strings.Repeat("═", len(sep))+"╗", "cyan"))
-	title := "  COMPARE REPLAY  ─  Local WASM  vs  On-Chain WASM  "
+	title := "  " + heading + "  "
 	pad := len(sep) - len(title)
 	if pad < 0 {
 		pad = 0
@@ -83,9 +125,9 @@ func sectionTitle(title string) string {
 	return visualizer.Colorize(line, "bold")
 }
 
-func renderStatus(sd StatusDiff) {
-	leftLabel := "LOCAL"
-	rightLabel := "ON-CHAIN"
+func renderStatus(sd StatusDiff, labels Labels) {
+	leftLabel := strings.ToUpper(labels.Left)
+	rightLabel := strings.ToUpper(labels.Right)
 	fmt.Printf("  %-*s%s%-*s\n", colWidth, leftLabel, columnSep, colWidth, rightLabel)
 	fmt.Printf("  %s\n", strings.Repeat("-", colWidth*2+len(columnSep)))
 
@@ -111,8 +153,8 @@ func statusLine(status, errMsg string) string {
 	return s
 }
 
-func renderBudget(bd *BudgetDiff) {
-	fmt.Printf("  %-22s  %-15s  %-15s  %s\n", "Metric", "Local", "On-Chain", "Delta")
+func renderBudget(bd *BudgetDiff, labels Labels) {
+	fmt.Printf("  %-22s  %-15s  %-15s  %s\n", "Metric", labels.Left, labels.Right, "Delta")
 	fmt.Printf("  %s\n", strings.Repeat("-", 70))
 
 	cpuDeltaStr := formatDelta(bd.CPUDelta)
@@ -127,9 +169,35 @@ func renderBudget(bd *BudgetDiff) {
 		"Operations", bd.LocalOps, bd.OnChainOps, colorizeDelta(opsDeltaStr, int64(bd.OpsDelta)))
 }
 
-func renderEventDiffs(diffs []EventDiff) {
-	fmt.Printf("  %-6s  %-*s%s%-*s\n", "#", colWidth, "LOCAL", columnSep, colWidth, "ON-CHAIN")
-	fmt.Printf("  %s\n", strings.Repeat("-", colWidth*2+len(columnSep)+8))
+// renderFunctionCosts lists the CPU each function used itself on both sides,
+// biggest change first, with the change relative to the right side
+func renderFunctionCosts(diffs []FunctionCostDiff, labels Labels) {
+	fmt.Printf("  %-34s  %-15s  %-15s  %-12s  %s\n", "Function (self CPU)", labels.Left, labels.Right, "Delta", "Change")
+	fmt.Printf("  %s\n", strings.Repeat("-", 90))
+
+	for _, d := range diffs {
+		name := d.Function
+		if d.Contract != "" {
+			name = simulator.ShortID(d.Contract) + "." + d.Function
+		}
+		var change string
+		switch {
+		case d.OnChainCalls == 0:
+			change = "new"
+		case d.LocalCalls == 0:
+			change = "removed"
+		default:
+			change = colorizePct(budgetDeltaPct(d.SelfCPUDelta, d.OnChainSelfCPU))
+		}
+		// Pad before colouring so that escape codes do not skew the columns
+		delta := colorizeDelta(fmt.Sprintf("%-12s", formatDelta(d.SelfCPUDelta)), d.SelfCPUDelta)
+		fmt.Printf("  %-34s  %-15d  %-15d  %s  %s\n",
+			truncate(name, 34), d.LocalSelfCPU, d.OnChainSelfCPU, delta, change)
+	}
+}
+
+func renderEventDiffs(diffs []EventDiff, labels Labels) {
+	printColumnHeader(labels)
 
 	for _, d := range diffs {
 		localEvt := truncate(d.LocalEvent, colWidth)
@@ -145,9 +213,8 @@ func renderEventDiffs(diffs []EventDiff) {
 	}
 }
 
-func renderDiagnosticDiffs(diffs []DiagnosticDiff) {
-	fmt.Printf("  %-6s  %-*s%s%-*s\n", "#", colWidth, "LOCAL", columnSep, colWidth, "ON-CHAIN")
-	fmt.Printf("  %s\n", strings.Repeat("-", colWidth*2+len(columnSep)+8))
+func renderDiagnosticDiffs(diffs []DiagnosticDiff, labels Labels) {
+	printColumnHeader(labels)
 
 	for _, d := range diffs {
 		localDesc := diagnosticSummary(d.Local)
@@ -194,25 +261,26 @@ func renderTopicDiff(local, onChain []string) {
 	}
 }
 
-func renderCallPaths(divs []CallPathDivergence) {
+func renderCallPaths(divs []CallPathDivergence, labels Labels) {
 	for i, div := range divs {
 		fmt.Printf("  %s  Divergence #%d at event [%d]\n",
 			visualizer.Colorize("[PATH]", "red"), i+1, div.EventIndex+1)
 		fmt.Printf("       Reason    : %s\n", div.Reason)
-		fmt.Printf("       Local     : %s\n", visualizer.Colorize(div.LocalSummary, "cyan"))
-		fmt.Printf("       On-Chain  : %s\n", visualizer.Colorize(div.OnChainSummary, "magenta"))
+		fmt.Printf("       %-10s: %s\n", labels.Left, visualizer.Colorize(div.LocalSummary, "cyan"))
+		fmt.Printf("       %-10s: %s\n", labels.Right, visualizer.Colorize(div.OnChainSummary, "magenta"))
 		fmt.Println()
 	}
 }
 
-func renderSummary(result *DiffResult) {
+func renderSummary(result *DiffResult, labels Labels) {
 	fmt.Println(sectionTitle("Summary"))
 	fmt.Println()
 
 	if !result.HasDivergence {
-		fmt.Printf("  %s  Local and on-chain execution are IDENTICAL\n", visualizer.Success())
+		fmt.Printf("  %s  %s and %s execution are IDENTICAL\n", visualizer.Success(), labels.Left, strings.ToLower(labels.Right))
 	} else {
-		fmt.Printf("  %s  Divergence detected between local and on-chain execution\n", visualizer.Warning())
+		fmt.Printf("  %s  Divergence detected between %s and %s execution\n",
+			visualizer.Warning(), strings.ToLower(labels.Left), strings.ToLower(labels.Right))
 	}
 
 	fmt.Println()
@@ -223,13 +291,21 @@ func renderSummary(result *DiffResult) {
 		colorizeDivergentCount(result.DivergentEvents))
 	fmt.Printf("  %-30s  %s\n", "Call-path divergences:",
 		colorizeDivergentCount(len(result.CallPathDivergences)))
+	if len(result.ReturnValueDiffs) > 0 {
+		fmt.Printf("  %-30s  %s\n", "Divergent return values:",
+			colorizeDivergentCount(result.DivergentReturnValues))
+	}
+	if len(result.StorageDiffs) > 0 {
+		fmt.Printf("  %-30s  %s\n", "Divergent storage writes:",
+			colorizeDivergentCount(result.DivergentStorage))
+	}
 
 	if result.BudgetDiff != nil {
 		fmt.Println()
 		cpuPct := budgetDeltaPct(result.BudgetDiff.CPUDelta, result.BudgetDiff.OnChainCPU)
 		memPct := budgetDeltaPct(result.BudgetDiff.MemoryDelta, result.BudgetDiff.OnChainMem)
-		fmt.Printf("  %-30s  %s\n", "CPU delta vs on-chain:", colorizePct(cpuPct))
-		fmt.Printf("  %-30s  %s\n", "Memory delta vs on-chain:", colorizePct(memPct))
+		fmt.Printf("  %-30s  %s\n", "CPU delta vs "+strings.ToLower(labels.Right)+":", colorizePct(cpuPct))
+		fmt.Printf("  %-30s  %s\n", "Memory delta vs "+strings.ToLower(labels.Right)+":", colorizePct(memPct))
 	}
 
 	fmt.Println()
@@ -237,8 +313,88 @@ func renderSummary(result *DiffResult) {
 	fmt.Println(visualizer.Colorize(sep, "dim"))
 }
 
+func renderReturnValues(diffs []ReturnValueDiff, labels Labels) {
+	printColumnHeader(labels)
+
+	for _, d := range diffs {
+		fmt.Printf("%s[%3d]  %-*s%s%-*s\n",
+			diffMarker(d.Divergent), d.Index+1,
+			colWidth, truncate(describeScVal(d.Local), colWidth),
+			columnSep, colWidth, truncate(describeScVal(d.OnChain), colWidth))
+	}
+}
+
+func renderStorageDiffs(diffs []StorageDiff, labels Labels) {
+	for _, d := range diffs {
+		fmt.Printf("%s%s\n", diffMarker(d.Divergent), describeLedgerKey(d.Key))
+		if !d.Divergent {
+			continue
+		}
+		fmt.Printf("        %-10s: %s\n", labels.Left, describeWrite(d.Local, d.LocalWritten))
+		fmt.Printf("        %-10s: %s\n", labels.Right, describeWrite(d.OnChain, d.OnChainWritten))
+	}
+}
+
+func printColumnHeader(labels Labels) {
+	fmt.Printf("  %-6s  %-*s%s%-*s\n", "#", colWidth, strings.ToUpper(labels.Left), columnSep, colWidth, strings.ToUpper(labels.Right))
+	fmt.Printf("  %s\n", strings.Repeat("-", colWidth*2+len(columnSep)+8))
+}
+
 // ─── formatting helpers ───────────────────────────────────────────────────────
 
+func describeScVal(b64 string) string {
+	if b64 == "" {
+		return "<absent>"
+	}
+	return decoder.RenderScVal(b64, b64)
+}
+
+// describeLedgerKey renders contract data keys as "<contract> <key>" and
+// other entry kinds by type
+func describeLedgerKey(b64 string) string {
+	var key xdr.LedgerKey
+	if err := xdr.SafeUnmarshalBase64(b64, &key); err != nil {
+		return truncate(b64, colWidth)
+	}
+	switch {
+	case key.ContractData != nil:
+		contract, err := key.ContractData.Contract.String()
+		if err != nil {
+			contract = "?"
+		}
+		return fmt.Sprintf("%s %s (%s)", contract, decoder.FormatScVal(key.ContractData.Key),
+			strings.TrimPrefix(key.ContractData.Durability.String(), "ContractDataDurability"))
+	case key.Account != nil:
+		return "account " + key.Account.AccountId.Address()
+	default:
+		return strings.TrimPrefix(key.Type.String(), "LedgerEntryType")
+	}
+}
+
+func describeWrite(b64 string, written bool) string {
+	switch {
+	case !written:
+		return "<not written>"
+	case b64 == "":
+		return "<deleted>"
+	}
+	var entry xdr.LedgerEntry
+	if err := xdr.SafeUnmarshalBase64(b64, &entry); err != nil {
+		return truncate(b64, colWidth)
+	}
+	if data := entry.Data.ContractData; data != nil {
+		return decoder.FormatScVal(data.Val)
+	}
+	return strings.TrimPrefix(entry.Data.Type.String(), "LedgerEntryType") + " entry"
+}
+
+func diffMarker(divergent bool) string {
+	if divergent {
+		return visualizer.Colorize("[!]", "yellow") + " "
+	}
+	return visualizer.Colorize("[=]", "dim") + " "
+}
+
 func diagnosticSummary(e *simulator.DiagnosticEvent) string {
 	if e == nil {
 		return "<absent>"
@@ -0,0 +1,194 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stellar/go-stellar-sdk/strkey"
+)
+
+// AliasBook is the address book managed by 'erst alias', mapping short names
+// to contract IDs and account addresses
+type AliasBook struct {
+	Aliases map[string]string `json:"aliases"`
+}
+
+// GetAliasPath returns the path to the alias book
+func GetAliasPath() (string, error) {
+	configDir, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "aliases.json"), nil
+}
+
+// LoadAliases reads the alias book, which is empty when it does not exist
+func LoadAliases() (*AliasBook, error) {
+	path, err := GetAliasPath()
+	if err != nil {
+		return nil, err
+	}
+
+	book := &AliasBook{Aliases: make(map[string]string)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return book, nil
+	}
+	if err != nil {
+		return nil, errors.WrapConfigError("failed to read alias book", err)
+	}
+	if err := json.Unmarshal(data, book); err != nil {
+		return nil, errors.WrapConfigError("failed to parse alias book "+path, err)
+	}
+	if book.Aliases == nil {
+		book.Aliases = make(map[string]string)
+	}
+	return book, nil
+}
+
+// SaveAliases writes the alias book
+func SaveAliases(book *AliasBook) error {
+	path, err := GetAliasPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.WrapConfigError("failed to create config directory", err)
+	}
+	data, err := json.MarshalIndent(book, "", "  ")
+	if err != nil {
+		return errors.WrapConfigError("failed to marshal alias book", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return errors.WrapConfigError("failed to write alias book", err)
+	}
+	return nil
+}
+
+// Add names a contract ID or account address, replacing any address the
+// name had before
+func (b *AliasBook) Add(name, address string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	address = strings.TrimSpace(address)
+	if err := validateAliasName(name); err != nil {
+		return err
+	}
+	if !isAliasAddress(address) {
+		return errors.WrapValidationError(fmt.Sprintf("%q is not a contract ID (C...) or account address (G... or M...)", address))
+	}
+	b.Aliases[name] = address
+	return nil
+}
+
+// Remove deletes an alias and reports whether it existed
+func (b *AliasBook) Remove(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	_, ok := b.Aliases[name]
+	delete(b.Aliases, name)
+	return ok
+}
+
+// Names returns the aliases in alphabetical order
+func (b *AliasBook) Names() []string {
+	names := make([]string, 0, len(b.Aliases))
+	for name := range b.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateAliasName rejects names that could be mistaken for an address or
+// would not survive a shell or a TOML key
+func validateAliasName(name string) error {
+	if name == "" {
+		return errors.WrapValidationError("alias name cannot be empty")
+	}
+	if isAliasAddress(strings.ToUpper(name)) {
+		return errors.WrapValidationError(fmt.Sprintf("alias %q looks like an address", name))
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return errors.WrapValidationError(fmt.Sprintf("alias %q may only contain letters, digits, '-', '_' and '.'", name))
+		}
+	}
+	return nil
+}
+
+func isAliasAddress(s string) bool {
+	return strkey.IsValidContractAddress(s) || isAccountAddress(s)
+}
+
+func isAccountAddress(s string) bool {
+	return strkey.IsValidEd25519PublicKey(s) || strkey.IsValidMuxedAccountEd25519PublicKey(s)
+}
+
+// mergeAliasBook adds the alias book's entries to the contract and account
+// aliases. Aliases defined in config files take precedence.
+func (c *Config) mergeAliasBook() error {
+	book, err := LoadAliases()
+	if err != nil {
+		return err
+	}
+	for name, address := range book.Aliases {
+		if isAccountAddress(address) {
+			if _, ok := c.AccountAliases[name]; !ok {
+				c.setAccountAlias(name, address)
+			}
+			continue
+		}
+		if _, ok := c.ContractAliases[name]; !ok {
+			c.setContractAlias(name, address)
+		}
+	}
+	return nil
+}
+
+func (c *Config) setAccountAlias(name, address string) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return
+	}
+	if c.AccountAliases == nil {
+		c.AccountAliases = make(map[string]string)
+	}
+	c.AccountAliases[name] = strings.TrimSpace(address)
+}
+
+// ResolveAccount returns the account address behind an alias. Anything that
+// is not an account alias is returned as is.
+func (c *Config) ResolveAccount(ref string) string {
+	if address, ok := c.AccountAliases[strings.ToLower(strings.TrimSpace(ref))]; ok {
+		return address
+	}
+	return ref
+}
+
+// AddressNames maps every aliased contract ID and account address to its
+// alias, for labelling them in output. Contract aliases of every network
+// profile are included, since contract IDs do not repeat across networks.
+func (c *Config) AddressNames() map[string]string {
+	names := make(map[string]string)
+	add := func(aliases map[string]string) {
+		for name, address := range aliases {
+			// Two names for one address: keep the same one on every run
+			if existing, ok := names[address]; !ok || name < existing {
+				names[address] = name
+			}
+		}
+	}
+	add(c.ContractAliases)
+	add(c.AccountAliases)
+	for _, p := range c.Profiles {
+		add(p.Contracts)
+	}
+	return names
+}
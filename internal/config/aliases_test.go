@@ -0,0 +1,96 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testContractID = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	testAccountID  = "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+)
+
+func TestAliasBook_Add(t *testing.T) {
+	book := &AliasBook{Aliases: make(map[string]string)}
+	if err := book.Add("AMM", testContractID); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := book.Add("treasury", " "+testAccountID+" "); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if book.Aliases["amm"] != testContractID || book.Aliases["treasury"] != testAccountID {
+		t.Errorf("unexpected aliases: %v", book.Aliases)
+	}
+
+	for _, tt := range []struct{ name, address string }{
+		{"", testContractID},
+		{"my amm", testContractID},
+		{testAccountID, testContractID},
+		{"amm", "CNOTANADDRESS"},
+	} {
+		if err := book.Add(tt.name, tt.address); err == nil {
+			t.Errorf("Add(%q, %q) accepted", tt.name, tt.address)
+		}
+	}
+
+	if !book.Remove("Treasury") || book.Remove("treasury") {
+		t.Error("Remove should delete an alias once")
+	}
+}
+
+func TestLoad_AliasBook(t *testing.T) {
+	home := t.TempDir()
+	xdg := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Chdir(t.TempDir())
+
+	book := &AliasBook{Aliases: make(map[string]string)}
+	if err := book.Add("amm", testContractID); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.Add("treasury", testAccountID); err != nil {
+		t.Fatal(err)
+	}
+	if err := book.Add("token", testContractID); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveAliases(book); err != nil {
+		t.Fatalf("SaveAliases: %v", err)
+	}
+
+	// config.yaml takes precedence over the alias book
+	global := filepath.Join(xdg, "erst", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(global), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(global, []byte("contracts:\n  token: CTOKEN\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.ResolveContract("testnet", "AMM"); got != testContractID {
+		t.Errorf("ResolveContract(amm) = %q", got)
+	}
+	if got := cfg.ResolveContract("testnet", "token"); got != "CTOKEN" {
+		t.Errorf("ResolveContract(token) = %q, want the config.yaml alias", got)
+	}
+	if got := cfg.ResolveAccount("treasury"); got != testAccountID {
+		t.Errorf("ResolveAccount(treasury) = %q", got)
+	}
+	if got := cfg.ResolveContract("testnet", "treasury"); got != "treasury" {
+		t.Errorf("an account alias resolved as a contract: %q", got)
+	}
+
+	names := cfg.AddressNames()
+	if names[testContractID] != "amm" || names[testAccountID] != "treasury" || names["CTOKEN"] != "token" {
+		t.Errorf("AddressNames() = %v", names)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/dotandev/hintents/internal/errors"
@@ -37,6 +38,102 @@ type Config struct {
 	LogLevel       string  `json:"log_level,omitempty"`
 	CachePath      string  `json:"cache_path,omitempty"`
 	RPCToken       string  `json:"rpc_token,omitempty"`
+	// NetworkRpcUrls lists failover endpoints for individual networks and takes
+	// precedence over RpcUrls. Set via rpc_urls.<network> in config or
+	// ERST_RPC_URLS_<NETWORK>.
+	NetworkRpcUrls map[string][]string `json:"network_rpc_urls,omitempty"`
+	// ArchiveUrls are Horizon-compatible full-history endpoints consulted when
+	// RPC has pruned a transaction. Set via archive_urls or ERST_ARCHIVE_URLS.
+	ArchiveUrls []string `json:"archive_urls,omitempty"`
+	// Hooks are executables or .wasm modules run after each simulation by
+	// erst debug, erst simulate and erst watch, in order. Set via hooks or
+	// ERST_HOOKS (comma-separated).
+	Hooks []string `json:"hooks,omitempty"`
+	// RpcHeaders are sent with every RPC request, e.g. a provider API key.
+	// Set via rpc_headers.<Name> = "value" or ERST_RPC_HEADERS="Name: value; ...".
+	RpcHeaders map[string]string `json:"rpc_headers,omitempty"`
+	// RpcRateLimit caps RPC requests per second, in bursts of up to
+	// RpcRateBurst, to stay within a public provider's limits; 0 means no
+	// limit. Set via rpc_rate_limit and rpc_rate_burst, or ERST_RPC_RATE_LIMIT
+	// and ERST_RPC_RATE_BURST.
+	RpcRateLimit float64 `json:"rpc_rate_limit,omitempty"`
+	RpcRateBurst int     `json:"rpc_rate_burst,omitempty"`
+	// RpcConcurrency caps the RPC requests in flight at once, for every
+	// network unless its profile sets its own; 0 means no cap. Set via
+	// rpc_concurrency, rpc_concurrency.<network> or ERST_RPC_CONCURRENCY.
+	RpcConcurrency int `json:"rpc_concurrency,omitempty"`
+	// RpcRetries is how often a failed RPC request is retried, 0 to fail on
+	// the first error; nil keeps the default of 3. Set via rpc_retries or
+	// ERST_RPC_RETRIES.
+	RpcRetries *int `json:"rpc_retries,omitempty"`
+	// LedgerBatchSize and LedgerBatchWorkers split getLedgerEntries lookups
+	// into requests of at most that many keys, sent that many at a time, for
+	// servers configured with a different key limit; 0 keeps the defaults of
+	// 200 and 4. Set via ledger_batch_size and ledger_batch_workers, or
+	// ERST_LEDGER_BATCH_SIZE and ERST_LEDGER_BATCH_WORKERS.
+	LedgerBatchSize    int `json:"ledger_batch_size,omitempty"`
+	LedgerBatchWorkers int `json:"ledger_batch_workers,omitempty"`
+	// Proxy is an HTTP, HTTPS or SOCKS5 proxy URL for RPC requests, used
+	// instead of HTTPS_PROXY, HTTP_PROXY and ALL_PROXY. Set via proxy in
+	// config or ERST_PROXY.
+	Proxy string `json:"proxy,omitempty"`
+	// LocalPassphrase is the passphrase of the network used with --network
+	// local, for standalone nodes not started with the quickstart default.
+	// Set via local_passphrase or ERST_LOCAL_PASSPHRASE.
+	LocalPassphrase string `json:"local_passphrase,omitempty"`
+	// SessionStore selects the session history backend: "sqlite" (default) or
+	// "postgres". Set via session_store or ERST_SESSION_STORE.
+	SessionStore string `json:"session_store,omitempty"`
+	// SessionDBURL is the SQLite file or Postgres connection URL of the session
+	// store. Set via session_db_url, ERST_SESSION_DB_URL or ERST_DB_PATH,
+	// which takes precedence so CI jobs can isolate their history.
+	SessionDBURL string `json:"session_db_url,omitempty"`
+	// Session retention limits applied whenever the session store is opened
+	// and by `erst prune`: an age such as "30d", a session count and a size
+	// such as "500MB". Set via session_max_age, session_max_sessions and
+	// session_max_db_size, or the matching ERST_SESSION_* variables.
+	SessionMaxAge      string `json:"session_max_age,omitempty"`
+	SessionMaxSessions int    `json:"session_max_sessions,omitempty"`
+	SessionMaxDBSize   string `json:"session_max_db_size,omitempty"`
+	// Webhook notified when a simulation in watch or daemon mode fails: the
+	// URL, the payload format ("json", "slack" or "discord") and an optional
+	// regular expression the error must match. Set via webhook_url,
+	// webhook_type and webhook_filter, or the matching ERST_WEBHOOK_* variables.
+	WebhookURL    string `json:"webhook_url,omitempty"`
+	WebhookType   string `json:"webhook_type,omitempty"`
+	WebhookFilter string `json:"webhook_filter,omitempty"`
+	// ShareURL is the storage endpoint 'erst share' uploads session bundles
+	// to and 'erst import --from-url' resolves share IDs against, with
+	// ShareToken sent as a bearer token. Set via share_url and share_token, or
+	// ERST_SHARE_URL and ERST_SHARE_TOKEN.
+	ShareURL   string `json:"share_url,omitempty"`
+	ShareToken string `json:"share_token,omitempty"`
+	// Explorer is the block explorer 'erst debug' links to: "stellarexpert"
+	// (default), "stellarchain" or "none". Set via explorer or ERST_EXPLORER.
+	Explorer string `json:"explorer,omitempty"`
+	// SessionEncryption is "keychain" to encrypt stored sessions with a key
+	// kept in the OS keychain, or "off". ERST_SESSION_KEY, when set, is used
+	// as the key instead. Set via session_encryption or ERST_SESSION_ENCRYPTION.
+	SessionEncryption string `json:"session_encryption,omitempty"`
+	// ActivityLog, when set, records every command invocation: a file that
+	// entries are appended to as JSON lines, or "db" for the activity_log
+	// table of the session database. Set via activity_log or ERST_ACTIVITY_LOG.
+	ActivityLog string `json:"activity_log,omitempty"`
+	// Output is the default output format: "text", "json", or "ndjson",
+	// "markdown" or "sarif" for the commands that produce them. Set via
+	// output or ERST_OUTPUT.
+	Output string `json:"output,omitempty"`
+	// ContractAliases map short names to contract IDs, so commands accept
+	// e.g. --contract usdc. Set in the contracts section of config.yaml or
+	// with 'erst alias add'.
+	ContractAliases map[string]string `json:"contract_aliases,omitempty"`
+	// AccountAliases map short names to account addresses, accepted by
+	// account flags such as --source. Set in the accounts section of
+	// config.yaml or with 'erst alias add'.
+	AccountAliases map[string]string `json:"account_aliases,omitempty"`
+	// Profiles hold per-network settings from the networks section of
+	// config.yaml. Their rpc_urls are stored in NetworkRpcUrls.
+	Profiles map[string]NetworkProfile `json:"profiles,omitempty"`
 	// CrashReporting enables opt-in anonymous crash reporting.
 	// Set via crash_reporting = true in config or ERST_CRASH_REPORTING=true.
 	CrashReporting bool   `json:"crash_reporting,omitempty"`
@@ -46,13 +143,7 @@ type Config struct {
 	// CrashSentryDSN is a Sentry Data Source Name for crash reporting.
 	// Set via crash_sentry_dsn in config or ERST_SENTRY_DSN.
 	CrashSentryDSN string `json:"crash_sentry_dsn,omitempty"`
-	RpcUrl        string   `json:"rpc_url,omitempty"`
 	RpcUrls       []string `json:"rpc_urls,omitempty"`
-	Network       Network  `json:"network,omitempty"`
-	SimulatorPath string   `json:"simulator_path,omitempty"`
-	LogLevel      string   `json:"log_level,omitempty"`
-	CachePath     string   `json:"cache_path,omitempty"`
-	RPCToken      string   `json:"rpc_token,omitempty"`
 }
 
 var defaultConfig = &Config{
@@ -97,58 +188,149 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
-// Load loads the configuration from environment variables and TOML files
+// Load loads the configuration from config files and environment variables.
+// Files are merged from the system file up to the project-local one (see
+// configFiles) and environment variables override them; command-line flags
+// in turn override the result.
 func Load() (*Config, error) {
 	cfg := &Config{
-		RpcUrl:         getEnv("ERST_RPC_URL", defaultConfig.RpcUrl),
-		Network:        Network(getEnv("ERST_NETWORK", string(defaultConfig.Network))),
-		SimulatorPath:  getEnv("ERST_SIMULATOR_PATH", defaultConfig.SimulatorPath),
-		LogLevel:       getEnv("ERST_LOG_LEVEL", defaultConfig.LogLevel),
-		CachePath:      getEnv("ERST_CACHE_PATH", defaultConfig.CachePath),
-		RPCToken:       getEnv("ERST_RPC_TOKEN", ""),
-		CrashEndpoint:  getEnv("ERST_CRASH_ENDPOINT", ""),
-		CrashSentryDSN: getEnv("ERST_SENTRY_DSN", ""),
+		RpcUrl:        defaultConfig.RpcUrl,
+		SimulatorPath: defaultConfig.SimulatorPath,
+		LogLevel:      defaultConfig.LogLevel,
+		CachePath:     defaultConfig.CachePath,
+	}
+
+	if err := cfg.loadFromFile(); err != nil {
+		return nil, err
+	}
+	if err := cfg.mergeAliasBook(); err != nil {
+		return nil, err
+	}
+	cfg.applyEnv()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// applyEnv overrides the configuration with the ERST_* environment variables
+func (c *Config) applyEnv() {
+	c.RpcUrl = getEnv("ERST_RPC_URL", c.RpcUrl)
+	c.Network = normalizeNetwork(getEnv("ERST_NETWORK", string(c.Network)))
+	c.SimulatorPath = getEnv("ERST_SIMULATOR_PATH", c.SimulatorPath)
+	c.LogLevel = getEnv("ERST_LOG_LEVEL", c.LogLevel)
+	c.CachePath = getEnv("ERST_CACHE_PATH", c.CachePath)
+	c.RPCToken = getEnv("ERST_RPC_TOKEN", c.RPCToken)
+	c.CrashEndpoint = getEnv("ERST_CRASH_ENDPOINT", c.CrashEndpoint)
+	c.CrashSentryDSN = getEnv("ERST_SENTRY_DSN", c.CrashSentryDSN)
+	c.SessionStore = getEnv("ERST_SESSION_STORE", c.SessionStore)
+	c.SessionDBURL = getEnv("ERST_SESSION_DB_URL", c.SessionDBURL)
+	c.SessionDBURL = getEnv("ERST_DB_PATH", c.SessionDBURL)
+	c.Output = getEnv("ERST_OUTPUT", c.Output)
+	c.Proxy = getEnv("ERST_PROXY", c.Proxy)
+	c.LocalPassphrase = getEnv("ERST_LOCAL_PASSPHRASE", c.LocalPassphrase)
+
+	c.WebhookURL = getEnv("ERST_WEBHOOK_URL", c.WebhookURL)
+	c.WebhookType = getEnv("ERST_WEBHOOK_TYPE", c.WebhookType)
+	c.WebhookFilter = getEnv("ERST_WEBHOOK_FILTER", c.WebhookFilter)
+	c.ShareURL = getEnv("ERST_SHARE_URL", c.ShareURL)
+	c.ShareToken = getEnv("ERST_SHARE_TOKEN", c.ShareToken)
+	c.Explorer = getEnv("ERST_EXPLORER", c.Explorer)
+	c.SessionEncryption = getEnv("ERST_SESSION_ENCRYPTION", c.SessionEncryption)
+	c.ActivityLog = getEnv("ERST_ACTIVITY_LOG", c.ActivityLog)
+	c.SessionMaxAge = getEnv("ERST_SESSION_MAX_AGE", c.SessionMaxAge)
+	c.SessionMaxDBSize = getEnv("ERST_SESSION_MAX_DB_SIZE", c.SessionMaxDBSize)
+	if maxSessions, err := strconv.Atoi(os.Getenv("ERST_SESSION_MAX_SESSIONS")); err == nil {
+		c.SessionMaxSessions = maxSessions
+	}
+	if rate, err := strconv.ParseFloat(os.Getenv("ERST_RPC_RATE_LIMIT"), 64); err == nil {
+		c.RpcRateLimit = rate
+	}
+	if burst, err := strconv.Atoi(os.Getenv("ERST_RPC_RATE_BURST")); err == nil {
+		c.RpcRateBurst = burst
+	}
+	if concurrency, err := strconv.Atoi(os.Getenv("ERST_RPC_CONCURRENCY")); err == nil {
+		c.RpcConcurrency = concurrency
+	}
+	if retries, err := strconv.Atoi(os.Getenv("ERST_RPC_RETRIES")); err == nil {
+		c.RpcRetries = &retries
+	}
+	if size, err := strconv.Atoi(os.Getenv("ERST_LEDGER_BATCH_SIZE")); err == nil {
+		c.LedgerBatchSize = size
+	}
+	if parallel, err := strconv.Atoi(os.Getenv("ERST_LEDGER_BATCH_WORKERS")); err == nil {
+		c.LedgerBatchWorkers = parallel
 	}
 
 	// ERST_CRASH_REPORTING is a boolean env var; parse it explicitly.
 	switch strings.ToLower(os.Getenv("ERST_CRASH_REPORTING")) {
 	case "1", "true", "yes":
-		cfg.CrashReporting = true
+		c.CrashReporting = true
+	case "0", "false", "no":
+		c.CrashReporting = false
 	}
 
 	if urlsEnv := os.Getenv("ERST_RPC_URLS"); urlsEnv != "" {
-		cfg.RpcUrls = strings.Split(urlsEnv, ",")
-		for i := range cfg.RpcUrls {
-			cfg.RpcUrls[i] = strings.TrimSpace(cfg.RpcUrls[i])
-		}
+		c.RpcUrls = parseURLList(urlsEnv)
 	} else if urlsEnv := os.Getenv("STELLAR_RPC_URLS"); urlsEnv != "" {
-		cfg.RpcUrls = strings.Split(urlsEnv, ",")
-		for i := range cfg.RpcUrls {
-			cfg.RpcUrls[i] = strings.TrimSpace(cfg.RpcUrls[i])
-		}
+		c.RpcUrls = parseURLList(urlsEnv)
 	}
 
-	if err := cfg.loadFromFile(); err != nil {
-		return nil, err
+	if archiveEnv := os.Getenv("ERST_ARCHIVE_URLS"); archiveEnv != "" {
+		c.ArchiveUrls = parseURLList(archiveEnv)
 	}
 
-	if err := cfg.Validate(); err != nil {
-		return nil, err
+	if hooksEnv := os.Getenv("ERST_HOOKS"); hooksEnv != "" {
+		c.Hooks = parseURLList(hooksEnv)
 	}
 
-	return cfg, nil
+	if headersEnv := os.Getenv("ERST_RPC_HEADERS"); headersEnv != "" {
+		for _, spec := range strings.Split(headersEnv, ";") {
+			if name, value, ok := strings.Cut(spec, ":"); ok {
+				c.setRpcHeader(name, value)
+			}
+		}
+	}
+
+	for _, kv := range os.Environ() {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, "ERST_RPC_URLS_") || value == "" {
+			continue
+		}
+		c.setNetworkRpcUrls(strings.TrimPrefix(name, "ERST_RPC_URLS_"), parseURLList(value))
+	}
 }
 
-func (c *Config) loadFromFile() error {
-	paths := []string{
-		".erst.toml",
-		filepath.Join(os.ExpandEnv("$HOME"), ".erst.toml"),
+// configFiles lists the config files read by Load, lowest precedence first:
+// the system file, the user's files and the project-local files in the
+// working directory
+func configFiles() []string {
+	home := os.ExpandEnv("$HOME")
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+	return []string{
 		"/etc/erst/config.toml",
+		filepath.Join(home, ".erst.toml"),
+		filepath.Join(configHome, "erst", "config.yaml"),
+		".erst.toml",
+		".erst.yaml",
 	}
+}
 
-	for _, path := range paths {
-		if err := c.loadTOML(path); err == nil {
-			return nil
+func (c *Config) loadFromFile() error {
+	for _, path := range configFiles() {
+		var err error
+		if strings.HasSuffix(path, ".yaml") {
+			err = c.loadYAML(path)
+		} else {
+			err = c.loadTOML(path)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return errors.WrapConfigError("failed to load "+path, err)
 		}
 	}
 
@@ -187,13 +369,35 @@ func (c *Config) parseTOML(content string) error {
 
 		if key == "rpc_urls" && strings.HasPrefix(rawVal, "[") && strings.HasSuffix(rawVal, "]") {
 			// Basic array parsing for TOML-like lists: ["a", "b"]
-			rawVal = strings.Trim(rawVal, "[]")
-			parts := strings.Split(rawVal, ",")
-			var urls []string
-			for _, p := range parts {
-				urls = append(urls, strings.Trim(strings.TrimSpace(p), "\"'"))
+			c.RpcUrls = parseURLList(rawVal)
+			continue
+		}
+
+		if key == "archive_urls" {
+			c.ArchiveUrls = parseURLList(rawVal)
+			continue
+		}
+
+		if key == "hooks" {
+			c.Hooks = parseURLList(rawVal)
+			continue
+		}
+
+		if name, ok := strings.CutPrefix(key, "rpc_headers."); ok {
+			c.setRpcHeader(name, strings.Trim(rawVal, "\"'"))
+			continue
+		}
+
+		if network, ok := strings.CutPrefix(key, "rpc_concurrency."); ok {
+			if n, err := strconv.Atoi(strings.Trim(rawVal, "\"'")); err == nil {
+				c.setNetworkConcurrency(network, n)
 			}
-			c.RpcUrls = urls
+			continue
+		}
+
+		// Per-network endpoint lists: rpc_urls.testnet = ["a", "b"]
+		if network, ok := strings.CutPrefix(key, "rpc_urls."); ok {
+			c.setNetworkRpcUrls(network, parseURLList(rawVal))
 			continue
 		}
 
@@ -204,12 +408,9 @@ func (c *Config) parseTOML(content string) error {
 			c.RpcUrl = value
 		case "rpc_urls":
 			// Fallback if not an array but comma-separated string
-			c.RpcUrls = strings.Split(value, ",")
-			for i := range c.RpcUrls {
-				c.RpcUrls[i] = strings.TrimSpace(c.RpcUrls[i])
-			}
+			c.RpcUrls = parseURLList(value)
 		case "network":
-			c.Network = Network(value)
+			c.Network = normalizeNetwork(value)
 		case "simulator_path":
 			c.SimulatorPath = value
 		case "log_level":
@@ -224,12 +425,119 @@ func (c *Config) parseTOML(content string) error {
 			c.CrashEndpoint = value
 		case "crash_sentry_dsn":
 			c.CrashSentryDSN = value
+		case "session_store":
+			c.SessionStore = value
+		case "session_db_url":
+			c.SessionDBURL = value
+		case "session_max_age":
+			c.SessionMaxAge = value
+		case "session_max_sessions":
+			if n, err := strconv.Atoi(value); err == nil {
+				c.SessionMaxSessions = n
+			}
+		case "session_max_db_size":
+			c.SessionMaxDBSize = value
+		case "rpc_rate_limit":
+			if rate, err := strconv.ParseFloat(value, 64); err == nil {
+				c.RpcRateLimit = rate
+			}
+		case "rpc_concurrency":
+			if n, err := strconv.Atoi(value); err == nil {
+				c.RpcConcurrency = n
+			}
+		case "rpc_rate_burst":
+			if burst, err := strconv.Atoi(value); err == nil {
+				c.RpcRateBurst = burst
+			}
+		case "rpc_retries":
+			if retries, err := strconv.Atoi(value); err == nil {
+				c.RpcRetries = &retries
+			}
+		case "ledger_batch_size":
+			if n, err := strconv.Atoi(value); err == nil {
+				c.LedgerBatchSize = n
+			}
+		case "ledger_batch_workers":
+			if n, err := strconv.Atoi(value); err == nil {
+				c.LedgerBatchWorkers = n
+			}
+		case "webhook_url":
+			c.WebhookURL = value
+		case "webhook_type":
+			c.WebhookType = value
+		case "webhook_filter":
+			c.WebhookFilter = value
+		case "share_url":
+			c.ShareURL = value
+		case "share_token":
+			c.ShareToken = value
+		case "explorer":
+			c.Explorer = value
+		case "session_encryption":
+			c.SessionEncryption = value
+		case "activity_log":
+			c.ActivityLog = value
+		case "output":
+			c.Output = value
+		case "proxy":
+			c.Proxy = value
+		case "local_passphrase":
+			c.LocalPassphrase = value
 		}
 	}
 
 	return nil
 }
 
+// parseURLList parses a TOML-like array (["a", "b"]) or a comma-separated
+// string into a list of trimmed, non-empty URLs
+func parseURLList(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]") {
+		raw = strings.Trim(raw, "[]")
+	}
+	var urls []string
+	for _, p := range strings.Split(raw, ",") {
+		if url := strings.Trim(strings.TrimSpace(p), "\"'"); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+func (c *Config) setNetworkRpcUrls(network string, urls []string) {
+	network = strings.ToLower(strings.TrimSpace(network))
+	if network == "" || len(urls) == 0 {
+		return
+	}
+	if c.NetworkRpcUrls == nil {
+		c.NetworkRpcUrls = make(map[string][]string)
+	}
+	c.NetworkRpcUrls[network] = urls
+}
+
+func (c *Config) setRpcHeader(name, value string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	if c.RpcHeaders == nil {
+		c.RpcHeaders = make(map[string]string)
+	}
+	c.RpcHeaders[name] = strings.TrimSpace(value)
+}
+
+// RPCURLsFor returns the failover endpoints configured for network, falling
+// back to the global rpc_urls list. "mainnet" and "public" are interchangeable.
+func (c *Config) RPCURLsFor(network string) []string {
+	for _, name := range networkNames(network) {
+		if urls := c.NetworkRpcUrls[name]; len(urls) > 0 {
+			return urls
+		}
+	}
+	return c.RpcUrls
+}
+
 // SaveConfig saves the configuration to disk (JSON format)
 func SaveConfig(config *Config) error {
 	configPath, err := GetGeneralConfigPath()
@@ -265,6 +573,48 @@ func (c *Config) Validate() error {
 		return errors.WrapInvalidNetwork(string(c.Network))
 	}
 
+	if c.RpcRateLimit < 0 || c.RpcRateBurst < 0 {
+		return errors.WrapValidationError("rpc_rate_limit and rpc_rate_burst cannot be negative")
+	}
+	if c.RpcConcurrency < 0 {
+		return errors.WrapValidationError("rpc_concurrency cannot be negative")
+	}
+	if c.RpcRetries != nil && *c.RpcRetries < 0 {
+		return errors.WrapValidationError("rpc_retries cannot be negative")
+	}
+	if c.LedgerBatchSize < 0 || c.LedgerBatchWorkers < 0 {
+		return errors.WrapValidationError("ledger_batch_size and ledger_batch_workers cannot be negative")
+	}
+	for network, p := range c.Profiles {
+		if p.RpcConcurrency < 0 {
+			return errors.WrapValidationError(fmt.Sprintf("rpc_concurrency for %s cannot be negative", network))
+		}
+	}
+
+	switch strings.ToLower(c.SessionStore) {
+	case "", "sqlite", "postgres":
+	default:
+		return errors.WrapValidationError(fmt.Sprintf("session_store must be sqlite or postgres, got %q", c.SessionStore))
+	}
+
+	switch strings.ToLower(c.WebhookType) {
+	case "", "json", "slack", "discord":
+	default:
+		return errors.WrapValidationError(fmt.Sprintf("webhook_type must be json, slack or discord, got %q", c.WebhookType))
+	}
+
+	switch strings.ToLower(c.Explorer) {
+	case "", "stellarexpert", "stellarchain", "none":
+	default:
+		return errors.WrapValidationError(fmt.Sprintf("explorer must be stellarexpert, stellarchain or none, got %q", c.Explorer))
+	}
+
+	switch c.Output {
+	case "", "text", "json", "ndjson", "markdown", "sarif":
+	default:
+		return errors.WrapValidationError(fmt.Sprintf("output must be text, json, ndjson, markdown or sarif, got %q", c.Output))
+	}
+
 	return nil
 }
 
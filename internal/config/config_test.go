@@ -74,6 +74,16 @@ func TestConfigValidation(t *testing.T) {
 			&Config{RpcUrl: "https://test.com", Network: Network("invalid")},
 			true,
 		},
+		{
+			"report output format",
+			&Config{RpcUrl: "https://test.com", Network: NetworkTestnet, Output: "sarif"},
+			false,
+		},
+		{
+			"invalid output format",
+			&Config{RpcUrl: "https://test.com", Network: NetworkTestnet, Output: "yaml"},
+			true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -447,3 +457,228 @@ func TestLoad_CrashReportingOffByDefault(t *testing.T) {
 		t.Error("CrashReporting should be off by default")
 	}
 }
+
+// ---- Per-network RPC endpoints ----------------------------------------------
+
+func TestParseTOML_NetworkRpcUrls(t *testing.T) {
+	content := `rpc_urls = ["https://global.example"]
+rpc_urls.testnet = ["https://t1.example", "https://t2.example"]
+rpc_urls.public = "https://p1.example, https://p2.example"`
+
+	cfg := &Config{}
+	if err := cfg.parseTOML(content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testnet := cfg.RPCURLsFor("testnet")
+	if len(testnet) != 2 || testnet[0] != "https://t1.example" || testnet[1] != "https://t2.example" {
+		t.Errorf("unexpected testnet URLs: %v", testnet)
+	}
+	mainnet := cfg.RPCURLsFor("mainnet")
+	if len(mainnet) != 2 || mainnet[0] != "https://p1.example" {
+		t.Errorf("expected mainnet to resolve the public list, got %v", mainnet)
+	}
+	futurenet := cfg.RPCURLsFor("futurenet")
+	if len(futurenet) != 1 || futurenet[0] != "https://global.example" {
+		t.Errorf("expected futurenet to fall back to rpc_urls, got %v", futurenet)
+	}
+}
+
+func TestLoad_NetworkRpcUrlsEnv(t *testing.T) {
+	orig := os.Getenv("ERST_RPC_URLS_TESTNET")
+	defer os.Setenv("ERST_RPC_URLS_TESTNET", orig)
+	os.Setenv("ERST_RPC_URLS_TESTNET", "https://a.example, https://b.example")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	urls := cfg.RPCURLsFor("testnet")
+	if len(urls) != 2 || urls[0] != "https://a.example" || urls[1] != "https://b.example" {
+		t.Errorf("unexpected testnet URLs from env: %v", urls)
+	}
+}
+
+func TestParseTOML_ArchiveUrls(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parseTOML(`archive_urls = ["https://history.example", "https://history2.example"]`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.ArchiveUrls) != 2 || cfg.ArchiveUrls[0] != "https://history.example" {
+		t.Errorf("unexpected archive URLs: %v", cfg.ArchiveUrls)
+	}
+}
+
+func TestParseTOML_Hooks(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parseTOML(`hooks = ["/opt/erst/triage.sh", "./owners.py"]`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Hooks) != 2 || cfg.Hooks[1] != "./owners.py" {
+		t.Errorf("unexpected hooks: %v", cfg.Hooks)
+	}
+}
+
+func TestParseTOML_LocalNetwork(t *testing.T) {
+	content := `network = "local"
+local_passphrase = "My Dev Network ; 2025"
+rpc_urls.standalone = ["http://devbox:8000/rpc"]`
+
+	cfg := &Config{}
+	if err := cfg.parseTOML(content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Network != NetworkStandalone || cfg.CLINetwork() != "local" {
+		t.Errorf("expected local to map to standalone, got %q", cfg.Network)
+	}
+	if cfg.LocalPassphrase != "My Dev Network ; 2025" {
+		t.Errorf("unexpected local_passphrase: %q", cfg.LocalPassphrase)
+	}
+	if urls := cfg.RPCURLsFor("local"); len(urls) != 1 || urls[0] != "http://devbox:8000/rpc" {
+		t.Errorf("expected standalone endpoints for local, got %v", urls)
+	}
+}
+
+func TestParseTOML_RpcHeaders(t *testing.T) {
+	content := `rpc_headers.X-Api-Key = "abc123"
+rpc_headers.Authorization = "Basic dXNlcjpwYXNz"`
+
+	cfg := &Config{}
+	if err := cfg.parseTOML(content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RpcHeaders["X-Api-Key"] != "abc123" {
+		t.Errorf("expected X-Api-Key header, got %v", cfg.RpcHeaders)
+	}
+	if cfg.RpcHeaders["Authorization"] != "Basic dXNlcjpwYXNz" {
+		t.Errorf("expected Authorization header, got %v", cfg.RpcHeaders)
+	}
+}
+
+func TestParseTOML_RpcRateLimit(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parseTOML("rpc_rate_limit = 2.5\nrpc_rate_burst = 5"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RpcRateLimit != 2.5 || cfg.RpcRateBurst != 5 {
+		t.Errorf("unexpected rate limit: %v/s, burst %d", cfg.RpcRateLimit, cfg.RpcRateBurst)
+	}
+
+	cfg.RpcUrl = "https://rpc.example"
+	cfg.RpcRateLimit = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected a negative rate limit to be rejected")
+	}
+}
+
+func TestParseTOML_RpcConcurrency(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parseTOML("rpc_concurrency = 8\nrpc_concurrency.mainnet = 2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.RPCConcurrencyFor("mainnet"); got != 2 {
+		t.Errorf("expected mainnet's own cap, got %d", got)
+	}
+	if got := cfg.RPCConcurrencyFor("testnet"); got != 8 {
+		t.Errorf("expected the global cap for testnet, got %d", got)
+	}
+}
+
+func TestParseTOML_RpcRetries(t *testing.T) {
+	cfg := &Config{RpcUrl: "https://test.com", Network: NetworkTestnet}
+	if err := cfg.parseTOML("rpc_retries = 0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RpcRetries == nil || *cfg.RpcRetries != 0 {
+		t.Errorf("expected retries to be disabled, got %v", cfg.RpcRetries)
+	}
+	if err := cfg.parseTOML("rpc_retries = -1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected negative rpc_retries to be rejected")
+	}
+}
+
+func TestLoad_RpcHeadersEnv(t *testing.T) {
+	orig := os.Getenv("ERST_RPC_HEADERS")
+	defer os.Setenv("ERST_RPC_HEADERS", orig)
+	os.Setenv("ERST_RPC_HEADERS", "X-Api-Key: abc; X-Org: erst")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.RpcHeaders["X-Api-Key"] != "abc" || cfg.RpcHeaders["X-Org"] != "erst" {
+		t.Errorf("unexpected headers from env: %v", cfg.RpcHeaders)
+	}
+}
+
+func TestParseTOML_SessionStore(t *testing.T) {
+	content := `session_store = "postgres"
+session_db_url = "postgres://erst@db.internal/sessions?sslmode=require"`
+
+	cfg := &Config{RpcUrl: "https://rpc.example"}
+	if err := cfg.parseTOML(content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SessionStore != "postgres" || cfg.SessionDBURL != "postgres://erst@db.internal/sessions?sslmode=require" {
+		t.Errorf("unexpected session store config: %q %q", cfg.SessionStore, cfg.SessionDBURL)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+
+	cfg.SessionStore = "mysql"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected unknown session_store to be rejected")
+	}
+}
+
+func TestLoad_DBPathEnv(t *testing.T) {
+	t.Setenv("ERST_SESSION_DB_URL", "/var/lib/erst/sessions.db")
+	t.Setenv("ERST_DB_PATH", "/tmp/ci-job/sessions.db")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SessionDBURL != "/tmp/ci-job/sessions.db" {
+		t.Errorf("SessionDBURL = %q, want ERST_DB_PATH", cfg.SessionDBURL)
+	}
+}
+
+func TestLoad_LedgerBatching(t *testing.T) {
+	t.Setenv("ERST_LEDGER_BATCH_SIZE", "50")
+	t.Setenv("ERST_LEDGER_BATCH_WORKERS", "2")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.LedgerBatchSize != 50 || cfg.LedgerBatchWorkers != 2 {
+		t.Errorf("ledger batching = %d x %d, want 50 x 2", cfg.LedgerBatchSize, cfg.LedgerBatchWorkers)
+	}
+
+	t.Setenv("ERST_LEDGER_BATCH_SIZE", "-1")
+	if _, err := Load(); err == nil {
+		t.Error("expected a negative ledger_batch_size to be rejected")
+	}
+}
+
+func TestParseTOML_ActivityLog(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parseTOML(`activity_log = "/var/log/erst/activity.log"`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ActivityLog != "/var/log/erst/activity.log" {
+		t.Errorf("ActivityLog = %q", cfg.ActivityLog)
+	}
+
+	t.Setenv("ERST_ACTIVITY_LOG", "db")
+	cfg.applyEnv()
+	if cfg.ActivityLog != "db" {
+		t.Errorf("ActivityLog = %q, want ERST_ACTIVITY_LOG", cfg.ActivityLog)
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NetworkProfile holds settings that apply to a single network and take
+// precedence over the global ones
+type NetworkProfile struct {
+	RPCToken    string            `json:"rpc_token,omitempty"`
+	RpcHeaders  map[string]string `json:"rpc_headers,omitempty"`
+	ArchiveUrls []string          `json:"archive_urls,omitempty"`
+	Contracts   map[string]string `json:"contracts,omitempty"`
+	// RpcConcurrency caps the RPC requests in flight to this network
+	RpcConcurrency int `json:"rpc_concurrency,omitempty"`
+}
+
+// yamlConfig is the layout of config.yaml. Top-level keys match the TOML
+// keys; networks holds per-network profiles.
+type yamlConfig struct {
+	RpcURL             string            `yaml:"rpc_url"`
+	RpcURLs            urlList           `yaml:"rpc_urls"`
+	RPCToken           string            `yaml:"rpc_token"`
+	RpcHeaders         map[string]string `yaml:"rpc_headers"`
+	RpcRateLimit       float64           `yaml:"rpc_rate_limit"`
+	RpcRateBurst       int               `yaml:"rpc_rate_burst"`
+	RpcConcurrency     int               `yaml:"rpc_concurrency"`
+	RpcRetries         *int              `yaml:"rpc_retries"`
+	LedgerBatchSize    int               `yaml:"ledger_batch_size"`
+	LedgerBatchWorkers int               `yaml:"ledger_batch_workers"`
+	Proxy              string            `yaml:"proxy"`
+	LocalPassphrase    string            `yaml:"local_passphrase"`
+	ArchiveURLs        urlList           `yaml:"archive_urls"`
+	Hooks              urlList           `yaml:"hooks"`
+	Network            string            `yaml:"network"`
+	Output             string            `yaml:"output"`
+	SimulatorPath      string            `yaml:"simulator_path"`
+	LogLevel           string            `yaml:"log_level"`
+	CachePath          string            `yaml:"cache_path"`
+	SessionStore       string            `yaml:"session_store"`
+	SessionDBURL       string            `yaml:"session_db_url"`
+	SessionMaxAge      string            `yaml:"session_max_age"`
+	SessionMaxSessions int               `yaml:"session_max_sessions"`
+	SessionMaxDBSize   string            `yaml:"session_max_db_size"`
+	WebhookURL         string            `yaml:"webhook_url"`
+	WebhookType        string            `yaml:"webhook_type"`
+	WebhookFilter      string            `yaml:"webhook_filter"`
+	ShareURL           string            `yaml:"share_url"`
+	ShareToken         string            `yaml:"share_token"`
+	Explorer           string            `yaml:"explorer"`
+	SessionEncryption  string            `yaml:"session_encryption"`
+	ActivityLog        string            `yaml:"activity_log"`
+	CrashReporting     *bool             `yaml:"crash_reporting"`
+	CrashEndpoint      string            `yaml:"crash_endpoint"`
+	CrashSentryDSN     string            `yaml:"crash_sentry_dsn"`
+	Contracts          map[string]string `yaml:"contracts"`
+	Accounts           map[string]string `yaml:"accounts"`
+	Networks           map[string]struct {
+		RpcURLs        urlList           `yaml:"rpc_urls"`
+		RPCToken       string            `yaml:"rpc_token"`
+		RpcHeaders     map[string]string `yaml:"rpc_headers"`
+		ArchiveURLs    urlList           `yaml:"archive_urls"`
+		Contracts      map[string]string `yaml:"contracts"`
+		RpcConcurrency int               `yaml:"rpc_concurrency"`
+	} `yaml:"networks"`
+}
+
+// urlList accepts either a YAML list or a comma-separated string
+type urlList []string
+
+func (l *urlList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = parseURLList(node.Value)
+		return nil
+	}
+	var urls []string
+	if err := node.Decode(&urls); err != nil {
+		return err
+	}
+	*l = parseURLList(strings.Join(urls, ","))
+	return nil
+}
+
+func (c *Config) loadYAML(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return c.parseYAML(data)
+}
+
+// parseYAML merges a config.yaml document into c. Unknown keys are rejected
+// so that typos do not silently fall back to defaults.
+func (c *Config) parseYAML(data []byte) error {
+	var f yamlConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && err != io.EOF {
+		return err
+	}
+
+	setString(&c.RpcUrl, f.RpcURL)
+	setString(&c.RPCToken, f.RPCToken)
+	setString(&c.SimulatorPath, f.SimulatorPath)
+	setString(&c.LogLevel, f.LogLevel)
+	setString(&c.CachePath, f.CachePath)
+	setString(&c.Output, f.Output)
+	setString(&c.Proxy, f.Proxy)
+	setString(&c.LocalPassphrase, f.LocalPassphrase)
+	setString(&c.SessionStore, f.SessionStore)
+	setString(&c.SessionDBURL, f.SessionDBURL)
+	setString(&c.SessionMaxAge, f.SessionMaxAge)
+	setString(&c.SessionMaxDBSize, f.SessionMaxDBSize)
+	setString(&c.WebhookURL, f.WebhookURL)
+	setString(&c.WebhookType, f.WebhookType)
+	setString(&c.WebhookFilter, f.WebhookFilter)
+	setString(&c.ShareURL, f.ShareURL)
+	setString(&c.ShareToken, f.ShareToken)
+	setString(&c.Explorer, f.Explorer)
+	setString(&c.SessionEncryption, f.SessionEncryption)
+	setString(&c.ActivityLog, f.ActivityLog)
+	setString(&c.CrashEndpoint, f.CrashEndpoint)
+	setString(&c.CrashSentryDSN, f.CrashSentryDSN)
+	if f.Network != "" {
+		c.Network = normalizeNetwork(f.Network)
+	}
+	if f.SessionMaxSessions != 0 {
+		c.SessionMaxSessions = f.SessionMaxSessions
+	}
+	if f.RpcRateLimit != 0 {
+		c.RpcRateLimit = f.RpcRateLimit
+	}
+	if f.RpcRateBurst != 0 {
+		c.RpcRateBurst = f.RpcRateBurst
+	}
+	if f.RpcConcurrency != 0 {
+		c.RpcConcurrency = f.RpcConcurrency
+	}
+	if f.RpcRetries != nil {
+		c.RpcRetries = f.RpcRetries
+	}
+	if f.LedgerBatchSize != 0 {
+		c.LedgerBatchSize = f.LedgerBatchSize
+	}
+	if f.LedgerBatchWorkers != 0 {
+		c.LedgerBatchWorkers = f.LedgerBatchWorkers
+	}
+	if f.CrashReporting != nil {
+		c.CrashReporting = *f.CrashReporting
+	}
+	if len(f.RpcURLs) > 0 {
+		c.RpcUrls = f.RpcURLs
+	}
+	if len(f.ArchiveURLs) > 0 {
+		c.ArchiveUrls = f.ArchiveURLs
+	}
+	if len(f.Hooks) > 0 {
+		c.Hooks = f.Hooks
+	}
+	for name, value := range f.RpcHeaders {
+		c.setRpcHeader(name, value)
+	}
+	for name, id := range f.Contracts {
+		c.setContractAlias(name, id)
+	}
+	for name, address := range f.Accounts {
+		c.setAccountAlias(name, address)
+	}
+
+	for network, p := range f.Networks {
+		network = strings.ToLower(strings.TrimSpace(network))
+		c.setNetworkRpcUrls(network, p.RpcURLs)
+
+		if c.Profiles == nil {
+			c.Profiles = make(map[string]NetworkProfile)
+		}
+		profile := c.Profiles[network]
+		setString(&profile.RPCToken, p.RPCToken)
+		if len(p.ArchiveURLs) > 0 {
+			profile.ArchiveUrls = p.ArchiveURLs
+		}
+		if p.RpcConcurrency != 0 {
+			profile.RpcConcurrency = p.RpcConcurrency
+		}
+		for name, value := range p.RpcHeaders {
+			if profile.RpcHeaders == nil {
+				profile.RpcHeaders = make(map[string]string)
+			}
+			profile.RpcHeaders[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+		for name, id := range p.Contracts {
+			if profile.Contracts == nil {
+				profile.Contracts = make(map[string]string)
+			}
+			profile.Contracts[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(id)
+		}
+		c.Profiles[network] = profile
+	}
+	return nil
+}
+
+func setString(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+func (c *Config) setContractAlias(name, id string) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return
+	}
+	if c.ContractAliases == nil {
+		c.ContractAliases = make(map[string]string)
+	}
+	c.ContractAliases[name] = strings.TrimSpace(id)
+}
+
+// normalizeNetwork maps the CLI names "mainnet" and "local" to NetworkPublic
+// and NetworkStandalone
+func normalizeNetwork(network string) Network {
+	switch {
+	case strings.EqualFold(network, "mainnet"):
+		return NetworkPublic
+	case strings.EqualFold(network, "local"):
+		return NetworkStandalone
+	}
+	return Network(network)
+}
+
+// networkNames returns the keys network may be configured under. "mainnet"
+// and "public" are interchangeable, as are "local" and "standalone".
+func networkNames(network string) []string {
+	network = strings.ToLower(network)
+	switch network {
+	case "mainnet":
+		return []string{network, string(NetworkPublic)}
+	case string(NetworkPublic):
+		return []string{network, "mainnet"}
+	case "local":
+		return []string{network, string(NetworkStandalone)}
+	case string(NetworkStandalone):
+		return []string{network, "local"}
+	default:
+		return []string{network}
+	}
+}
+
+func (c *Config) profile(network string) NetworkProfile {
+	for _, name := range networkNames(network) {
+		if p, ok := c.Profiles[name]; ok {
+			return p
+		}
+	}
+	return NetworkProfile{}
+}
+
+// CLINetwork returns the configured network under the name the CLI flags use,
+// or "" when no network is configured
+func (c *Config) CLINetwork() string {
+	switch c.Network {
+	case NetworkPublic:
+		return "mainnet"
+	case NetworkStandalone:
+		return "local"
+	}
+	return string(c.Network)
+}
+
+// RPCTokenFor returns the RPC token of network's profile, falling back to
+// rpc_token
+func (c *Config) RPCTokenFor(network string) string {
+	if token := c.profile(network).RPCToken; token != "" {
+		return token
+	}
+	return c.RPCToken
+}
+
+// RPCHeadersFor returns rpc_headers merged with the headers of network's
+// profile, which win on conflicts
+func (c *Config) RPCHeadersFor(network string) map[string]string {
+	profile := c.profile(network)
+	if len(profile.RpcHeaders) == 0 {
+		return c.RpcHeaders
+	}
+	headers := make(map[string]string, len(c.RpcHeaders)+len(profile.RpcHeaders))
+	for name, value := range c.RpcHeaders {
+		headers[name] = value
+	}
+	for name, value := range profile.RpcHeaders {
+		headers[name] = value
+	}
+	return headers
+}
+
+// RPCConcurrencyFor returns the cap on RPC requests in flight to network,
+// falling back to rpc_concurrency; 0 means no cap
+func (c *Config) RPCConcurrencyFor(network string) int {
+	if n := c.profile(network).RpcConcurrency; n != 0 {
+		return n
+	}
+	return c.RpcConcurrency
+}
+
+func (c *Config) setNetworkConcurrency(network string, n int) {
+	network = strings.ToLower(strings.TrimSpace(network))
+	if network == "" {
+		return
+	}
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]NetworkProfile)
+	}
+	profile := c.Profiles[network]
+	profile.RpcConcurrency = n
+	c.Profiles[network] = profile
+}
+
+// ArchiveURLsFor returns the archive endpoints of network's profile, falling
+// back to archive_urls
+func (c *Config) ArchiveURLsFor(network string) []string {
+	if urls := c.profile(network).ArchiveUrls; len(urls) > 0 {
+		return urls
+	}
+	return c.ArchiveUrls
+}
+
+// ResolveContract returns the contract ID behind an alias, looking in
+// network's profile first. Anything that is not an alias is returned as is.
+func (c *Config) ResolveContract(network, ref string) string {
+	name := strings.ToLower(strings.TrimSpace(ref))
+	if id, ok := c.profile(network).Contracts[name]; ok {
+		return id
+	}
+	if id, ok := c.ContractAliases[name]; ok {
+		return id
+	}
+	return ref
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	content := `network: mainnet
+output: json
+rpc_urls: https://a.example, https://b.example
+rpc_headers:
+  X-Org: erst
+session_db_url: /var/lib/erst/sessions.db
+contracts:
+  Token: CTOKEN
+networks:
+  testnet:
+    rpc_urls:
+      - https://test-a.example
+      - https://test-b.example
+    rpc_token: test-token
+    rpc_headers:
+      X-Org: erst-test
+      X-Api-Key: abc
+    archive_urls: [https://archive.test.example]
+    contracts:
+      token: CTESTTOKEN
+`
+	cfg := DefaultConfig()
+	if err := cfg.parseYAML([]byte(content)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Network != NetworkPublic || cfg.CLINetwork() != "mainnet" {
+		t.Errorf("expected mainnet to map to public, got %q", cfg.Network)
+	}
+	if cfg.Output != "json" {
+		t.Errorf("expected output json, got %q", cfg.Output)
+	}
+	if len(cfg.RpcUrls) != 2 || cfg.RpcUrls[1] != "https://b.example" {
+		t.Errorf("unexpected rpc_urls: %v", cfg.RpcUrls)
+	}
+	if cfg.SessionDBURL != "/var/lib/erst/sessions.db" {
+		t.Errorf("unexpected session_db_url: %q", cfg.SessionDBURL)
+	}
+	if urls := cfg.RPCURLsFor("testnet"); len(urls) != 2 || urls[0] != "https://test-a.example" {
+		t.Errorf("unexpected testnet rpc_urls: %v", urls)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+}
+
+func TestParseYAML_UnknownKey(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.parseYAML([]byte("rpc_ulr: https://typo.example\n")); err == nil {
+		t.Error("expected unknown key to be rejected")
+	}
+	if err := cfg.parseYAML(nil); err != nil {
+		t.Errorf("expected empty file to be accepted, got %v", err)
+	}
+}
+
+func TestProfileAccessors(t *testing.T) {
+	content := `rpc_token: global-token
+rpc_headers:
+  X-Org: erst
+archive_urls: [https://archive.example]
+contracts:
+  token: CTOKEN
+  pool: CPOOL
+networks:
+  public:
+    rpc_token: public-token
+    rpc_headers:
+      X-Org: erst-public
+    contracts:
+      token: CPUBLICTOKEN
+`
+	cfg := DefaultConfig()
+	if err := cfg.parseYAML([]byte(content)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := cfg.RPCTokenFor("mainnet"); got != "public-token" {
+		t.Errorf("expected the public profile token for mainnet, got %q", got)
+	}
+	if got := cfg.RPCTokenFor("testnet"); got != "global-token" {
+		t.Errorf("expected the global token for testnet, got %q", got)
+	}
+	if got := cfg.RPCHeadersFor("mainnet")["X-Org"]; got != "erst-public" {
+		t.Errorf("expected the profile header to win, got %q", got)
+	}
+	if got := cfg.RPCHeadersFor("testnet")["X-Org"]; got != "erst" {
+		t.Errorf("expected the global header, got %q", got)
+	}
+	if urls := cfg.ArchiveURLsFor("mainnet"); len(urls) != 1 || urls[0] != "https://archive.example" {
+		t.Errorf("unexpected archive urls: %v", urls)
+	}
+
+	tests := []struct {
+		network, ref, want string
+	}{
+		{"mainnet", "Token", "CPUBLICTOKEN"},
+		{"testnet", "token", "CTOKEN"},
+		{"mainnet", "pool", "CPOOL"},
+		{"mainnet", "CABC", "CABC"},
+	}
+	for _, tt := range tests {
+		if got := cfg.ResolveContract(tt.network, tt.ref); got != tt.want {
+			t.Errorf("ResolveContract(%q, %q) = %q, want %q", tt.network, tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestLoad_YAMLPrecedence(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	for _, key := range []string{"ERST_NETWORK", "ERST_OUTPUT", "ERST_RPC_URL", "ERST_LOG_LEVEL"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+
+	global := filepath.Join(xdg, "erst", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(global), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(global, []byte("network: testnet\noutput: json\nlog_level: debug\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, ".erst.yaml"), []byte("log_level: warn\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(project)
+	t.Setenv("ERST_NETWORK", "futurenet")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Output != "json" {
+		t.Errorf("expected output from config.yaml, got %q", cfg.Output)
+	}
+	if cfg.LogLevel != "warn" {
+		t.Errorf("expected the project file to override config.yaml, got %q", cfg.LogLevel)
+	}
+	if cfg.Network != NetworkFuturenet {
+		t.Errorf("expected ERST_NETWORK to override config files, got %q", cfg.Network)
+	}
+}
+
+func TestLoad_InvalidYAML(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, ".erst.yaml"), []byte("networks: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(project)
+
+	if _, err := Load(); err == nil {
+		t.Error("expected a malformed config file to be reported")
+	}
+}
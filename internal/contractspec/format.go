@@ -0,0 +1,133 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contractspec
+
+import (
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// FormatValue renders a value like decoder.FormatScVal, naming the spec's
+// structs and union cases it recognises: a map whose symbol keys are exactly
+// a struct's fields is shown as "Name { field: value }" and a vec starting
+// with a union case name, followed by that case's values, as
+// "Union::Case(value)". Storage keys and values carry no type, so this
+// matching is by shape; spec may be nil.
+func FormatValue(spec *Spec, v xdr.ScVal) string {
+	var b strings.Builder
+	writeValue(&b, spec, v)
+	return b.String()
+}
+
+func writeValue(b *strings.Builder, spec *Spec, v xdr.ScVal) {
+	if spec == nil {
+		b.WriteString(decoder.FormatScVal(v))
+		return
+	}
+	switch {
+	case v.Type == xdr.ScValTypeScvVec && v.Vec != nil && *v.Vec != nil:
+		items := **v.Vec
+		if name, ok := unionCase(spec, items); ok {
+			b.WriteString(name)
+			if len(items) > 1 {
+				b.WriteString("(")
+				writeValues(b, spec, items[1:])
+				b.WriteString(")")
+			}
+			return
+		}
+		b.WriteString("[")
+		writeValues(b, spec, items)
+		b.WriteString("]")
+	case v.Type == xdr.ScValTypeScvMap && v.Map != nil && *v.Map != nil:
+		entries := **v.Map
+		if name, ok := structName(spec, entries); ok {
+			b.WriteString(name)
+			b.WriteString(" { ")
+			for i, entry := range entries {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(string(*entry.Key.Sym))
+				b.WriteString(": ")
+				writeValue(b, spec, entry.Val)
+			}
+			b.WriteString(" }")
+			return
+		}
+		b.WriteString("{")
+		for i, entry := range entries {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeValue(b, spec, entry.Key)
+			b.WriteString(": ")
+			writeValue(b, spec, entry.Val)
+		}
+		b.WriteString("}")
+	default:
+		b.WriteString(decoder.FormatScVal(v))
+	}
+}
+
+func writeValues(b *strings.Builder, spec *Spec, items []xdr.ScVal) {
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writeValue(b, spec, item)
+	}
+}
+
+// unionCase returns "Union::Case" for a vec encoding a case of one of the
+// spec's unions with the right number of values
+func unionCase(spec *Spec, items []xdr.ScVal) (string, bool) {
+	if len(items) == 0 || items[0].Type != xdr.ScValTypeScvSymbol || items[0].Sym == nil {
+		return "", false
+	}
+	name := string(*items[0].Sym)
+	for _, entry := range spec.Entries {
+		if entry.UdtUnionV0 == nil {
+			continue
+		}
+		for _, c := range entry.UdtUnionV0.Cases {
+			if (c.VoidCase != nil && c.VoidCase.Name == name && len(items) == 1) ||
+				(c.TupleCase != nil && c.TupleCase.Name == name && len(items) == len(c.TupleCase.Type)+1) {
+				return entry.UdtUnionV0.Name + "::" + name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// structName returns the name of the spec struct whose fields are exactly
+// the symbol keys of entries
+func structName(spec *Spec, entries xdr.ScMap) (string, bool) {
+	keys := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.Key.Type != xdr.ScValTypeScvSymbol || entry.Key.Sym == nil {
+			return "", false
+		}
+		keys[string(*entry.Key.Sym)] = true
+	}
+	for _, entry := range spec.Entries {
+		st := entry.UdtStructV0
+		if st == nil || len(st.Fields) != len(entries) {
+			continue
+		}
+		match := true
+		for _, f := range st.Fields {
+			if !keys[f.Name] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return st.Name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,36 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contractspec
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatValue(t *testing.T) {
+	spec := udtSpec(t)
+
+	route, err := ParseValue(spec, udt("Route"), `{"pool": "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC", "min_out": 5}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Route { min_out: 5, pool: CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC }", FormatValue(spec, route))
+	assert.Equal(t, "{min_out: 5, pool: CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC}", FormatValue(nil, route))
+
+	exact, err := ParseValue(spec, udt("Kind"), `"Exact"`)
+	require.NoError(t, err)
+	assert.Equal(t, "Kind::Exact", FormatValue(spec, exact))
+	limit, err := ParseValue(spec, udt("Kind"), `["Limit", 9]`)
+	require.NoError(t, err)
+	assert.Equal(t, "Kind::Limit(9)", FormatValue(spec, limit))
+
+	// Nested values are named too
+	nested := vecVal([]xdr.ScVal{limit, route})
+	assert.Equal(t, "[Kind::Limit(9), Route { min_out: 5, pool: CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC }]", FormatValue(spec, nested))
+
+	// A case name with the wrong number of values is left as a vec
+	other := vecVal([]xdr.ScVal{symbolVal("Limit"), symbolVal("a"), symbolVal("b")})
+	assert.Equal(t, "[Limit, a, b]", FormatValue(spec, other))
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contractspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Arg is one decoded invocation argument
+type Arg struct {
+	Name  string `json:"name,omitempty"`
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value"`
+}
+
+// Invocation is a contract call with its arguments labelled from the spec
+type Invocation struct {
+	ContractID string `json:"contract_id"`
+	Function   string `json:"function"`
+	Args       []Arg  `json:"args"`
+	// Returns is the declared return type, when the spec is known
+	Returns string `json:"returns,omitempty"`
+	// SpecFound is false when the contract's spec could not be read, in
+	// which case arguments carry no names or types
+	SpecFound bool `json:"spec_found"`
+	// Asset and Summary describe calls to a Stellar Asset Contract in token
+	// terms, e.g. "transfer 10.5 USDC from G... to G..."
+	Asset   string `json:"asset,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	// Decoder names the user-registered decoder that described the call, in
+	// which case Summary and Decoded come from it
+	Decoder string          `json:"decoder,omitempty"`
+	Decoded json.RawMessage `json:"decoded,omitempty"`
+}
+
+// DecodeInvocation labels args using the spec of the called function. spec
+// may be nil; arguments beyond those declared are left unnamed.
+func DecodeInvocation(spec *Spec, contractID, function string, args []xdr.ScVal) Invocation {
+	inv := Invocation{ContractID: contractID, Function: function, Args: make([]Arg, len(args))}
+
+	var fn xdr.ScSpecFunctionV0
+	if spec != nil {
+		fn, inv.SpecFound = spec.Function(function)
+	}
+	if inv.SpecFound && len(fn.Outputs) > 0 {
+		inv.Returns = TypeName(fn.Outputs[0])
+	}
+
+	for i, arg := range args {
+		inv.Args[i].Value = decoder.FormatScVal(arg)
+		if inv.SpecFound && i < len(fn.Inputs) {
+			inv.Args[i].Name = fn.Inputs[i].Name
+			inv.Args[i].Type = TypeName(fn.Inputs[i].Type)
+		}
+	}
+
+	if spec != nil && spec.StellarAsset != "" {
+		inv.Asset = spec.StellarAsset
+		if action, ok := decoder.DecodeSACCall(spec.StellarAsset, function, args); ok {
+			inv.Summary = action.String()
+		}
+	}
+	return inv
+}
+
+// String renders the call as "fn(name: Type = value, ...) -> Ret"
+func (inv Invocation) String() string {
+	parts := make([]string, len(inv.Args))
+	for i, arg := range inv.Args {
+		switch {
+		case arg.Name != "" && arg.Type != "":
+			parts[i] = fmt.Sprintf("%s: %s = %s", arg.Name, arg.Type, arg.Value)
+		case arg.Name != "":
+			parts[i] = fmt.Sprintf("%s = %s", arg.Name, arg.Value)
+		default:
+			parts[i] = arg.Value
+		}
+	}
+	out := fmt.Sprintf("%s(%s)", inv.Function, strings.Join(parts, ", "))
+	if inv.Returns != "" && inv.Returns != "()" {
+		out += " -> " + inv.Returns
+	}
+	return out
+}
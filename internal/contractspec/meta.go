@@ -0,0 +1,76 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contractspec
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Custom sections the Soroban SDK embeds alongside the spec
+const (
+	MetaSectionName    = "contractmetav0"
+	EnvMetaSectionName = "contractenvmetav0"
+)
+
+// MetaEntry is one key/value pair from contractmetav0, such as the rustc
+// and SDK versions the contract was built with
+type MetaEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// EnvMeta is the host interface version a contract was built against
+type EnvMeta struct {
+	Protocol   uint32 `json:"protocol"`
+	PreRelease uint32 `json:"pre_release,omitempty"`
+}
+
+// ParseMeta decodes the contractmetav0 section. A module without one yields no
+// entries.
+func ParseMeta(wasm []byte) ([]MetaEntry, error) {
+	section, err := CustomSection(wasm, MetaSectionName)
+	if err != nil || section == nil {
+		return nil, err
+	}
+
+	var entries []MetaEntry
+	r := bytes.NewReader(section)
+	for r.Len() > 0 {
+		var entry xdr.ScMetaEntry
+		if _, err := xdr.Unmarshal(r, &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode meta entry %d: %w", len(entries), err)
+		}
+		if entry.Kind == xdr.ScMetaKindScMetaV0 && entry.V0 != nil {
+			entries = append(entries, MetaEntry{Key: entry.V0.Key, Value: entry.V0.Val})
+		}
+	}
+	return entries, nil
+}
+
+// ParseEnvMeta decodes the contractenvmetav0 section, returning nil when
+// the module has none
+func ParseEnvMeta(wasm []byte) (*EnvMeta, error) {
+	section, err := CustomSection(wasm, EnvMetaSectionName)
+	if err != nil || section == nil {
+		return nil, err
+	}
+
+	r := bytes.NewReader(section)
+	for r.Len() > 0 {
+		var entry xdr.ScEnvMetaEntry
+		if _, err := xdr.Unmarshal(r, &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode env meta entry: %w", err)
+		}
+		if entry.Kind == xdr.ScEnvMetaKindScEnvMetaKindInterfaceVersion && entry.InterfaceVersion != nil {
+			return &EnvMeta{
+				Protocol:   uint32(entry.InterfaceVersion.Protocol),
+				PreRelease: uint32(entry.InterfaceVersion.PreRelease),
+			}, nil
+		}
+	}
+	return nil, nil
+}
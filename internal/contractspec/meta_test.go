@@ -0,0 +1,53 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contractspec
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMeta(t *testing.T) {
+	var meta []byte
+	for _, kv := range [][2]string{{"rsver", "1.81.0"}, {"rssdkver", "22.0.0"}} {
+		b, err := xdr.ScMetaEntry{Kind: xdr.ScMetaKindScMetaV0, V0: &xdr.ScMetaV0{Key: kv[0], Val: kv[1]}}.MarshalBinary()
+		require.NoError(t, err)
+		meta = append(meta, b...)
+	}
+	env, err := xdr.ScEnvMetaEntry{
+		Kind:             xdr.ScEnvMetaKindScEnvMetaKindInterfaceVersion,
+		InterfaceVersion: &xdr.ScEnvMetaEntryInterfaceVersion{Protocol: 22},
+	}.MarshalBinary()
+	require.NoError(t, err)
+
+	wasm := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	wasm = append(wasm, customSection(EnvMetaSectionName, env)...)
+	wasm = append(wasm, customSection(MetaSectionName, meta)...)
+
+	entries, err := ParseMeta(wasm)
+	require.NoError(t, err)
+	assert.Equal(t, []MetaEntry{{Key: "rsver", Value: "1.81.0"}, {Key: "rssdkver", Value: "22.0.0"}}, entries)
+
+	envMeta, err := ParseEnvMeta(wasm)
+	require.NoError(t, err)
+	assert.Equal(t, &EnvMeta{Protocol: 22}, envMeta)
+}
+
+func TestParseMeta_Missing(t *testing.T) {
+	wasm := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	entries, err := ParseMeta(wasm)
+	require.NoError(t, err)
+	assert.Nil(t, entries)
+
+	envMeta, err := ParseEnvMeta(wasm)
+	require.NoError(t, err)
+	assert.Nil(t, envMeta)
+
+	// buildWasm embeds a truncated meta section
+	_, err = ParseMeta(buildWasm(t))
+	assert.Error(t, err)
+}
@@ -0,0 +1,466 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contractspec
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// ParseValue converts s, a value written on the command line, to an SCVal of
+// spec type t. Numbers, addresses, symbols and strings are written bare and
+// bytes as hex. Compound values are JSON: [a, b] for a Vec or tuple,
+// {"key": v} for a Map or struct, "Case" or ["Case", v, ...] for a union
+// and null for an empty Option. "xdr:<base64>" gives any value as raw XDR.
+// spec resolves user-defined types and may be nil when none are used.
+func ParseValue(spec *Spec, t xdr.ScSpecTypeDef, s string) (xdr.ScVal, error) {
+	if raw, ok := strings.CutPrefix(s, "xdr:"); ok {
+		var v xdr.ScVal
+		if err := xdr.SafeUnmarshalBase64(raw, &v); err != nil {
+			return xdr.ScVal{}, fmt.Errorf("invalid ScVal XDR: %w", err)
+		}
+		return v, nil
+	}
+
+	if !isCompound(spec, t) {
+		// A quoted scalar is accepted too, e.g. "hello" for a String
+		if unquoted, err := strconv.Unquote(s); err == nil && strings.HasPrefix(s, `"`) {
+			s = unquoted
+		}
+		return parseScalar(spec, t, s)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return xdr.ScVal{}, fmt.Errorf("%s value must be JSON: %w", TypeName(t), err)
+	}
+	return fromJSON(spec, t, v)
+}
+
+// isCompound reports whether values of t are written as JSON
+func isCompound(spec *Spec, t xdr.ScSpecTypeDef) bool {
+	switch t.Type {
+	case xdr.ScSpecTypeScSpecTypeVec, xdr.ScSpecTypeScSpecTypeMap, xdr.ScSpecTypeScSpecTypeTuple:
+		return true
+	case xdr.ScSpecTypeScSpecTypeOption:
+		return t.Option != nil && isCompound(spec, t.Option.ValueType)
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		if spec == nil || t.Udt == nil {
+			return false
+		}
+		entry, ok := spec.Type(t.Udt.Name)
+		return ok && (entry.Kind == xdr.ScSpecEntryKindScSpecEntryUdtStructV0 || entry.Kind == xdr.ScSpecEntryKindScSpecEntryUdtUnionV0)
+	}
+	return false
+}
+
+func fromJSON(spec *Spec, t xdr.ScSpecTypeDef, v interface{}) (xdr.ScVal, error) {
+	switch t.Type {
+	case xdr.ScSpecTypeScSpecTypeOption:
+		if v == nil {
+			return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+		}
+		return fromJSON(spec, t.Option.ValueType, v)
+	case xdr.ScSpecTypeScSpecTypeVec:
+		items, ok := v.([]interface{})
+		if !ok {
+			return xdr.ScVal{}, fmt.Errorf("%s value must be a JSON array", TypeName(t))
+		}
+		vals := make([]xdr.ScVal, len(items))
+		for i, item := range items {
+			val, err := fromJSON(spec, t.Vec.ElementType, item)
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("[%d]: %w", i, err)
+			}
+			vals[i] = val
+		}
+		return vecVal(vals), nil
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		return tupleFromJSON(spec, t.Tuple.ValueTypes, v, TypeName(t))
+	case xdr.ScSpecTypeScSpecTypeMap:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return xdr.ScVal{}, fmt.Errorf("%s value must be a JSON object", TypeName(t))
+		}
+		entries := make(xdr.ScMap, 0, len(obj))
+		for k, item := range obj {
+			key, err := parseScalar(spec, t.Map.KeyType, k)
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("key %q: %w", k, err)
+			}
+			val, err := fromJSON(spec, t.Map.ValueType, item)
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("%q: %w", k, err)
+			}
+			entries = append(entries, xdr.ScMapEntry{Key: key, Val: val})
+		}
+		sortMap(entries)
+		return mapVal(entries), nil
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		if spec != nil && t.Udt != nil {
+			if entry, ok := spec.Type(t.Udt.Name); ok {
+				switch entry.Kind {
+				case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+					return structFromJSON(spec, entry.UdtStructV0, v)
+				case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+					return unionFromJSON(spec, entry.UdtUnionV0, v)
+				}
+			}
+		}
+	}
+
+	// Scalars inside compound values
+	var s string
+	switch x := v.(type) {
+	case string:
+		s = x
+	case json.Number:
+		s = x.String()
+	case bool:
+		s = strconv.FormatBool(x)
+	default:
+		return xdr.ScVal{}, fmt.Errorf("invalid %s value %v", TypeName(t), v)
+	}
+	return parseScalar(spec, t, s)
+}
+
+func tupleFromJSON(spec *Spec, types []xdr.ScSpecTypeDef, v interface{}, name string) (xdr.ScVal, error) {
+	items, ok := v.([]interface{})
+	if !ok || len(items) != len(types) {
+		return xdr.ScVal{}, fmt.Errorf("%s value must be a JSON array of %d values", name, len(types))
+	}
+	vals := make([]xdr.ScVal, len(items))
+	for i, item := range items {
+		val, err := fromJSON(spec, types[i], item)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("[%d]: %w", i, err)
+		}
+		vals[i] = val
+	}
+	return vecVal(vals), nil
+}
+
+// structFromJSON encodes a struct as a map keyed by field name, sorted, or a
+// tuple struct (fields named 0, 1, ...) as a vec
+func structFromJSON(spec *Spec, st *xdr.ScSpecUdtStructV0, v interface{}) (xdr.ScVal, error) {
+	if len(st.Fields) > 0 && st.Fields[0].Name == "0" {
+		types := make([]xdr.ScSpecTypeDef, len(st.Fields))
+		for i, f := range st.Fields {
+			types[i] = f.Type
+		}
+		return tupleFromJSON(spec, types, v, st.Name)
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return xdr.ScVal{}, fmt.Errorf("%s value must be a JSON object", st.Name)
+	}
+	entries := make(xdr.ScMap, 0, len(st.Fields))
+	for _, f := range st.Fields {
+		item, ok := obj[f.Name]
+		if !ok {
+			return xdr.ScVal{}, fmt.Errorf("%s is missing field %q", st.Name, f.Name)
+		}
+		val, err := fromJSON(spec, f.Type, item)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("%s.%s: %w", st.Name, f.Name, err)
+		}
+		entries = append(entries, xdr.ScMapEntry{Key: symbolVal(f.Name), Val: val})
+	}
+	if len(obj) != len(st.Fields) {
+		return xdr.ScVal{}, fmt.Errorf("%s has %d fields, got %d", st.Name, len(st.Fields), len(obj))
+	}
+	sortMap(entries)
+	return mapVal(entries), nil
+}
+
+// unionFromJSON encodes a union case as Vec[Symbol(case), values...]
+func unionFromJSON(spec *Spec, u *xdr.ScSpecUdtUnionV0, v interface{}) (xdr.ScVal, error) {
+	var name string
+	var values []interface{}
+	switch x := v.(type) {
+	case string:
+		name = x
+	case []interface{}:
+		if len(x) > 0 {
+			name, _ = x[0].(string)
+			values = x[1:]
+		}
+	}
+	for _, c := range u.Cases {
+		switch {
+		case c.VoidCase != nil && c.VoidCase.Name == name:
+			if len(values) > 0 {
+				return xdr.ScVal{}, fmt.Errorf("%s::%s takes no values", u.Name, name)
+			}
+			return vecVal([]xdr.ScVal{symbolVal(name)}), nil
+		case c.TupleCase != nil && c.TupleCase.Name == name:
+			tuple, err := tupleFromJSON(spec, c.TupleCase.Type, values, u.Name+"::"+name)
+			if err != nil {
+				return xdr.ScVal{}, err
+			}
+			return vecVal(append([]xdr.ScVal{symbolVal(name)}, **tuple.Vec...)), nil
+		}
+	}
+	return xdr.ScVal{}, fmt.Errorf("%s has no case %q", u.Name, name)
+}
+
+func parseScalar(spec *Spec, t xdr.ScSpecTypeDef, s string) (xdr.ScVal, error) {
+	invalid := func(err error) (xdr.ScVal, error) {
+		return xdr.ScVal{}, fmt.Errorf("invalid %s value %q: %w", TypeName(t), s, err)
+	}
+
+	switch t.Type {
+	case xdr.ScSpecTypeScSpecTypeBool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return invalid(err)
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b}, nil
+	case xdr.ScSpecTypeScSpecTypeVoid:
+		return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+	case xdr.ScSpecTypeScSpecTypeU32:
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return invalid(err)
+		}
+		u := xdr.Uint32(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u}, nil
+	case xdr.ScSpecTypeScSpecTypeI32:
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return invalid(err)
+		}
+		i := xdr.Int32(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &i}, nil
+	case xdr.ScSpecTypeScSpecTypeU64:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		u := xdr.Uint64(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &u}, nil
+	case xdr.ScSpecTypeScSpecTypeI64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		i := xdr.Int64(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &i}, nil
+	case xdr.ScSpecTypeScSpecTypeTimepoint:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			ts, tsErr := time.Parse(time.RFC3339, s)
+			if tsErr != nil {
+				return invalid(err)
+			}
+			n = uint64(ts.Unix())
+		}
+		tp := xdr.TimePoint(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvTimepoint, Timepoint: &tp}, nil
+	case xdr.ScSpecTypeScSpecTypeDuration:
+		n, err := strconv.ParseUint(strings.TrimSuffix(s, "s"), 10, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		d := xdr.Duration(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvDuration, Duration: &d}, nil
+	case xdr.ScSpecTypeScSpecTypeU128, xdr.ScSpecTypeScSpecTypeI128,
+		xdr.ScSpecTypeScSpecTypeU256, xdr.ScSpecTypeScSpecTypeI256:
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return invalid(fmt.Errorf("not a decimal integer"))
+		}
+		v, err := bigIntVal(t.Type, n)
+		if err != nil {
+			return invalid(err)
+		}
+		return v, nil
+	case xdr.ScSpecTypeScSpecTypeBytes, xdr.ScSpecTypeScSpecTypeBytesN:
+		b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return invalid(err)
+		}
+		if t.Type == xdr.ScSpecTypeScSpecTypeBytesN && t.BytesN != nil && uint32(len(b)) != uint32(t.BytesN.N) {
+			return invalid(fmt.Errorf("expected %d bytes, got %d", t.BytesN.N, len(b)))
+		}
+		sb := xdr.ScBytes(b)
+		return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &sb}, nil
+	case xdr.ScSpecTypeScSpecTypeString:
+		str := xdr.ScString(s)
+		return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}, nil
+	case xdr.ScSpecTypeScSpecTypeSymbol:
+		if len(s) > 32 {
+			return invalid(fmt.Errorf("symbols are at most 32 characters"))
+		}
+		return symbolVal(s), nil
+	case xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeMuxedAddress:
+		addr, err := parseAddress(s)
+		if err != nil {
+			return invalid(err)
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &addr}, nil
+	case xdr.ScSpecTypeScSpecTypeOption:
+		if s == "null" || s == "none" || s == "" {
+			return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+		}
+		return parseScalar(spec, t.Option.ValueType, s)
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		if spec != nil && t.Udt != nil {
+			if entry, ok := spec.Type(t.Udt.Name); ok && entry.Kind == xdr.ScSpecEntryKindScSpecEntryUdtEnumV0 {
+				return enumVal(entry.UdtEnumV0, s)
+			}
+		}
+		return xdr.ScVal{}, fmt.Errorf("unknown type %s; give the value as xdr:<base64>", TypeName(t))
+	}
+	return xdr.ScVal{}, fmt.Errorf("cannot parse %s values; give the value as xdr:<base64>", TypeName(t))
+}
+
+// parseAddress decodes an account (G...) or contract (C...) strkey
+func parseAddress(s string) (xdr.ScAddress, error) {
+	if strings.HasPrefix(s, "C") {
+		raw, err := strkey.Decode(strkey.VersionByteContract, s)
+		if err != nil {
+			return xdr.ScAddress{}, err
+		}
+		var id xdr.ContractId
+		copy(id[:], raw)
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}, nil
+	}
+	account, err := xdr.AddressToAccountId(s)
+	if err != nil {
+		return xdr.ScAddress{}, err
+	}
+	return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &account}, nil
+}
+
+// enumVal accepts an enum case by name or by value
+func enumVal(e *xdr.ScSpecUdtEnumV0, s string) (xdr.ScVal, error) {
+	for _, c := range e.Cases {
+		if c.Name == s || strconv.FormatUint(uint64(c.Value), 10) == s {
+			u := c.Value
+			return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u}, nil
+		}
+	}
+	return xdr.ScVal{}, fmt.Errorf("%s has no case %q", e.Name, s)
+}
+
+// bigIntVal encodes n as a 128 or 256 bit integer in two's complement
+func bigIntVal(t xdr.ScSpecType, n *big.Int) (xdr.ScVal, error) {
+	bits := 128
+	if t == xdr.ScSpecTypeScSpecTypeU256 || t == xdr.ScSpecTypeScSpecTypeI256 {
+		bits = 256
+	}
+	signed := t == xdr.ScSpecTypeScSpecTypeI128 || t == xdr.ScSpecTypeScSpecTypeI256
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	if signed {
+		half := new(big.Int).Rsh(limit, 1)
+		if n.Cmp(new(big.Int).Neg(half)) < 0 || n.Cmp(half) >= 0 {
+			return xdr.ScVal{}, fmt.Errorf("out of range")
+		}
+	} else if n.Sign() < 0 || n.Cmp(limit) >= 0 {
+		return xdr.ScVal{}, fmt.Errorf("out of range")
+	}
+
+	u := new(big.Int).Set(n)
+	if u.Sign() < 0 {
+		u.Add(u, limit)
+	}
+	words := make([]uint64, bits/64)
+	mask := new(big.Int).SetUint64(^uint64(0))
+	for i := len(words) - 1; i >= 0; i-- {
+		words[i] = new(big.Int).And(u, mask).Uint64()
+		u.Rsh(u, 64)
+	}
+
+	switch t {
+	case xdr.ScSpecTypeScSpecTypeU128:
+		return xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &xdr.UInt128Parts{Hi: xdr.Uint64(words[0]), Lo: xdr.Uint64(words[1])}}, nil
+	case xdr.ScSpecTypeScSpecTypeI128:
+		return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: xdr.Int64(words[0]), Lo: xdr.Uint64(words[1])}}, nil
+	case xdr.ScSpecTypeScSpecTypeU256:
+		return xdr.ScVal{Type: xdr.ScValTypeScvU256, U256: &xdr.UInt256Parts{HiHi: xdr.Uint64(words[0]), HiLo: xdr.Uint64(words[1]), LoHi: xdr.Uint64(words[2]), LoLo: xdr.Uint64(words[3])}}, nil
+	default:
+		return xdr.ScVal{Type: xdr.ScValTypeScvI256, I256: &xdr.Int256Parts{HiHi: xdr.Int64(words[0]), HiLo: xdr.Uint64(words[1]), LoHi: xdr.Uint64(words[2]), LoLo: xdr.Uint64(words[3])}}, nil
+	}
+}
+
+// sortMap orders map entries by key, as the host requires. Keys of one
+// numeric type compare by value; symbols and strings, and any other keys,
+// compare by their XDR encoding.
+func sortMap(entries xdr.ScMap) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].Key, entries[j].Key
+		if a.Type == b.Type {
+			switch a.Type {
+			case xdr.ScValTypeScvI32:
+				return *a.I32 < *b.I32
+			case xdr.ScValTypeScvI64:
+				return *a.I64 < *b.I64
+			case xdr.ScValTypeScvSymbol:
+				return *a.Sym < *b.Sym
+			case xdr.ScValTypeScvString:
+				return *a.Str < *b.Str
+			}
+		}
+		ea, _ := a.MarshalBinary()
+		eb, _ := b.MarshalBinary()
+		return bytes.Compare(ea, eb) < 0
+	})
+}
+
+func vecVal(elems []xdr.ScVal) xdr.ScVal {
+	vec := xdr.ScVec(elems)
+	vecPtr := &vec
+	return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vecPtr}
+}
+
+func mapVal(entries xdr.ScMap) xdr.ScVal {
+	mapPtr := &entries
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mapPtr}
+}
+
+func symbolVal(s string) xdr.ScVal {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+}
+
+var scalarSpecTypes = map[xdr.ScValType]xdr.ScSpecType{
+	xdr.ScValTypeScvBool:      xdr.ScSpecTypeScSpecTypeBool,
+	xdr.ScValTypeScvVoid:      xdr.ScSpecTypeScSpecTypeVoid,
+	xdr.ScValTypeScvU32:       xdr.ScSpecTypeScSpecTypeU32,
+	xdr.ScValTypeScvI32:       xdr.ScSpecTypeScSpecTypeI32,
+	xdr.ScValTypeScvU64:       xdr.ScSpecTypeScSpecTypeU64,
+	xdr.ScValTypeScvI64:       xdr.ScSpecTypeScSpecTypeI64,
+	xdr.ScValTypeScvTimepoint: xdr.ScSpecTypeScSpecTypeTimepoint,
+	xdr.ScValTypeScvDuration:  xdr.ScSpecTypeScSpecTypeDuration,
+	xdr.ScValTypeScvU128:      xdr.ScSpecTypeScSpecTypeU128,
+	xdr.ScValTypeScvI128:      xdr.ScSpecTypeScSpecTypeI128,
+	xdr.ScValTypeScvU256:      xdr.ScSpecTypeScSpecTypeU256,
+	xdr.ScValTypeScvI256:      xdr.ScSpecTypeScSpecTypeI256,
+	xdr.ScValTypeScvBytes:     xdr.ScSpecTypeScSpecTypeBytes,
+	xdr.ScValTypeScvString:    xdr.ScSpecTypeScSpecTypeString,
+	xdr.ScValTypeScvSymbol:    xdr.ScSpecTypeScSpecTypeSymbol,
+	xdr.ScValTypeScvAddress:   xdr.ScSpecTypeScSpecTypeAddress,
+}
+
+// ScalarType returns the spec type of a scalar value, for parsing a
+// replacement of the same type when no contract spec is available
+func ScalarType(v xdr.ScVal) (xdr.ScSpecTypeDef, bool) {
+	t, ok := scalarSpecTypes[v.Type]
+	return xdr.ScSpecTypeDef{Type: t}, ok
+}
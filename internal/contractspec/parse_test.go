@@ -0,0 +1,165 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contractspec
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func primitive(t xdr.ScSpecType) xdr.ScSpecTypeDef {
+	return xdr.ScSpecTypeDef{Type: t}
+}
+
+func udt(name string) xdr.ScSpecTypeDef {
+	return xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeUdt, Udt: &xdr.ScSpecTypeUdt{Name: name}}
+}
+
+func udtSpec(t *testing.T) *Spec {
+	t.Helper()
+	spec, err := Parse(buildWasm(t,
+		xdr.ScSpecEntry{Kind: xdr.ScSpecEntryKindScSpecEntryUdtStructV0, UdtStructV0: &xdr.ScSpecUdtStructV0{
+			Name: "Route",
+			Fields: []xdr.ScSpecUdtStructFieldV0{
+				{Name: "pool", Type: primitive(xdr.ScSpecTypeScSpecTypeAddress)},
+				{Name: "min_out", Type: primitive(xdr.ScSpecTypeScSpecTypeI128)},
+			},
+		}},
+		xdr.ScSpecEntry{Kind: xdr.ScSpecEntryKindScSpecEntryUdtUnionV0, UdtUnionV0: &xdr.ScSpecUdtUnionV0{
+			Name: "Kind",
+			Cases: []xdr.ScSpecUdtUnionCaseV0{
+				{Kind: xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseVoidV0, VoidCase: &xdr.ScSpecUdtUnionCaseVoidV0{Name: "Exact"}},
+				{Kind: xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseTupleV0, TupleCase: &xdr.ScSpecUdtUnionCaseTupleV0{Name: "Limit", Type: []xdr.ScSpecTypeDef{primitive(xdr.ScSpecTypeScSpecTypeU32)}}},
+			},
+		}},
+		xdr.ScSpecEntry{Kind: xdr.ScSpecEntryKindScSpecEntryUdtEnumV0, UdtEnumV0: &xdr.ScSpecUdtEnumV0{
+			Name:  "Color",
+			Cases: []xdr.ScSpecUdtEnumCaseV0{{Name: "Red", Value: 1}, {Name: "Blue", Value: 2}},
+		}},
+	))
+	require.NoError(t, err)
+	return spec
+}
+
+func TestParseValue_Scalars(t *testing.T) {
+	for _, tc := range []struct {
+		typ  xdr.ScSpecType
+		in   string
+		want string
+	}{
+		{xdr.ScSpecTypeScSpecTypeBool, "true", "true"},
+		{xdr.ScSpecTypeScSpecTypeU32, "7", "7"},
+		{xdr.ScSpecTypeScSpecTypeI64, "-9", "-9"},
+		{xdr.ScSpecTypeScSpecTypeI128, "-12345", "-12345"},
+		{xdr.ScSpecTypeScSpecTypeU128, "340282366920938463463374607431768211455", "340282366920938463463374607431768211455"},
+		{xdr.ScSpecTypeScSpecTypeI256, "-1", "-1"},
+		{xdr.ScSpecTypeScSpecTypeString, `"hello"`, `"hello"`},
+		{xdr.ScSpecTypeScSpecTypeSymbol, "transfer", "transfer"},
+		{xdr.ScSpecTypeScSpecTypeBytes, "0xdead", "0xdead"},
+		{xdr.ScSpecTypeScSpecTypeAddress, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"},
+		{xdr.ScSpecTypeScSpecTypeAddress, "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC", "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"},
+	} {
+		v, err := ParseValue(nil, primitive(tc.typ), tc.in)
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.want, decoder.FormatScVal(v), tc.in)
+	}
+
+	for _, tc := range []struct {
+		typ xdr.ScSpecType
+		in  string
+	}{
+		{xdr.ScSpecTypeScSpecTypeU32, "-1"},
+		{xdr.ScSpecTypeScSpecTypeI128, "170141183460469231731687303715884105728"},
+		{xdr.ScSpecTypeScSpecTypeAddress, "GNOPE"},
+		{xdr.ScSpecTypeScSpecTypeVal, "1"},
+	} {
+		_, err := ParseValue(nil, primitive(tc.typ), tc.in)
+		assert.Error(t, err, tc.in)
+	}
+}
+
+func TestParseValue_Compound(t *testing.T) {
+	spec := udtSpec(t)
+
+	vec := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeVec, Vec: &xdr.ScSpecTypeVec{ElementType: primitive(xdr.ScSpecTypeScSpecTypeU32)}}
+	v, err := ParseValue(spec, vec, "[1, 2, 3]")
+	require.NoError(t, err)
+	assert.Equal(t, "[1, 2, 3]", decoder.FormatScVal(v))
+
+	m := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeMap, Map: &xdr.ScSpecTypeMap{KeyType: primitive(xdr.ScSpecTypeScSpecTypeSymbol), ValueType: primitive(xdr.ScSpecTypeScSpecTypeI128)}}
+	v, err = ParseValue(spec, m, `{"b": 2, "a": "1"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "{a: 1, b: 2}", decoder.FormatScVal(v), "map keys are sorted")
+
+	v, err = ParseValue(spec, udt("Route"), `{"pool": "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC", "min_out": 5}`)
+	require.NoError(t, err)
+	assert.Equal(t, "{min_out: 5, pool: CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC}", decoder.FormatScVal(v))
+	_, err = ParseValue(spec, udt("Route"), `{"pool": "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"}`)
+	assert.ErrorContains(t, err, "min_out")
+
+	v, err = ParseValue(spec, udt("Kind"), `"Exact"`)
+	require.NoError(t, err)
+	assert.Equal(t, "[Exact]", decoder.FormatScVal(v))
+	v, err = ParseValue(spec, udt("Kind"), `["Limit", 9]`)
+	require.NoError(t, err)
+	assert.Equal(t, "[Limit, 9]", decoder.FormatScVal(v))
+
+	v, err = ParseValue(spec, udt("Color"), "Blue")
+	require.NoError(t, err)
+	assert.Equal(t, "2", decoder.FormatScVal(v))
+
+	opt := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeOption, Option: &xdr.ScSpecTypeOption{ValueType: primitive(xdr.ScSpecTypeScSpecTypeU64)}}
+	v, err = ParseValue(spec, opt, "null")
+	require.NoError(t, err)
+	assert.Equal(t, xdr.ScValTypeScvVoid, v.Type)
+}
+
+func TestParseValue_RawXDR(t *testing.T) {
+	u := xdr.Uint32(4)
+	raw, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u})
+	require.NoError(t, err)
+
+	v, err := ParseValue(nil, primitive(xdr.ScSpecTypeScSpecTypeVal), "xdr:"+raw)
+	require.NoError(t, err)
+	assert.Equal(t, xdr.Uint32(4), *v.U32)
+}
+
+func TestScalarType(t *testing.T) {
+	i := xdr.Int64(1)
+	typ, ok := ScalarType(xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &i})
+	require.True(t, ok)
+	assert.Equal(t, xdr.ScSpecTypeScSpecTypeI64, typ.Type)
+
+	_, ok = ScalarType(vecVal(nil))
+	assert.False(t, ok)
+}
+
+func TestParseUntyped(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+	}{
+		{"Admin", "Admin"},
+		{`"Admin"`, "Admin"},
+		{"7", "7"},
+		{`["Balance", "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"]`, "[Balance, GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H]"},
+	} {
+		v, err := ParseUntyped(tc.in)
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.want, decoder.FormatScVal(v), tc.in)
+	}
+
+	v, err := ParseUntyped("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	require.NoError(t, err)
+	assert.Equal(t, xdr.ScValTypeScvAddress, v.Type)
+
+	_, err = ParseUntyped("-1")
+	assert.Error(t, err)
+	_, err = ParseUntyped(`{"a": 1}`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package contractspec reads the interface a Soroban contract embeds in its
+// WASM (the "contractspecv0" custom section) and uses it to label invocation
+// arguments with their declared names and types.
+package contractspec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// SpecSectionName is the WASM custom section holding the contract spec
+const SpecSectionName = "contractspecv0"
+
+// ErrNoSpec is returned when a WASM module carries no contract spec
+var ErrNoSpec = errors.New("wasm has no contractspecv0 section")
+
+var wasmMagic = []byte{0x00, 0x61, 0x73, 0x6d}
+
+// Spec is the decoded interface of one contract
+type Spec struct {
+	Entries []xdr.ScSpecEntry
+	// StellarAsset is the asset name ("native" or "CODE:ISSUER") when the
+	// spec is the built-in interface of a Stellar Asset Contract
+	StellarAsset string
+	functions    map[string]xdr.ScSpecFunctionV0
+	types        map[string]xdr.ScSpecEntry
+}
+
+// Parse extracts and decodes the contract spec from a WASM module
+func Parse(wasm []byte) (*Spec, error) {
+	section, err := CustomSection(wasm, SpecSectionName)
+	if err != nil {
+		return nil, err
+	}
+	if section == nil {
+		return nil, ErrNoSpec
+	}
+	return ParseEntries(section)
+}
+
+// ParseEntries decodes a contractspecv0 section body, which is a plain
+// concatenation of XDR ScSpecEntry values
+func ParseEntries(data []byte) (*Spec, error) {
+	spec := &Spec{
+		functions: make(map[string]xdr.ScSpecFunctionV0),
+		types:     make(map[string]xdr.ScSpecEntry),
+	}
+	r := bytes.NewReader(data)
+	for r.Len() > 0 {
+		var entry xdr.ScSpecEntry
+		if _, err := xdr.Unmarshal(r, &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode spec entry %d: %w", len(spec.Entries), err)
+		}
+		spec.Entries = append(spec.Entries, entry)
+		if entry.Kind == xdr.ScSpecEntryKindScSpecEntryFunctionV0 && entry.FunctionV0 != nil {
+			spec.functions[string(entry.FunctionV0.Name)] = *entry.FunctionV0
+		}
+		if name := udtName(entry); name != "" {
+			spec.types[name] = entry
+		}
+	}
+	return spec, nil
+}
+
+// Function looks up an exported function by name
+func (s *Spec) Function(name string) (xdr.ScSpecFunctionV0, bool) {
+	fn, ok := s.functions[name]
+	return fn, ok
+}
+
+// Type looks up a user-defined struct, union or enum by name
+func (s *Spec) Type(name string) (xdr.ScSpecEntry, bool) {
+	entry, ok := s.types[name]
+	return entry, ok
+}
+
+// ContractError looks up the case of the contract's error enums with the
+// given code, returning "Enum::Case" and the case's doc comment
+func (s *Spec) ContractError(code uint32) (name, doc string, ok bool) {
+	for _, entry := range s.Entries {
+		if entry.UdtErrorEnumV0 == nil {
+			continue
+		}
+		for _, c := range entry.UdtErrorEnumV0.Cases {
+			if uint32(c.Value) == code {
+				return entry.UdtErrorEnumV0.Name + "::" + c.Name, c.Doc, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func udtName(entry xdr.ScSpecEntry) string {
+	switch {
+	case entry.UdtStructV0 != nil:
+		return entry.UdtStructV0.Name
+	case entry.UdtUnionV0 != nil:
+		return entry.UdtUnionV0.Name
+	case entry.UdtEnumV0 != nil:
+		return entry.UdtEnumV0.Name
+	case entry.UdtErrorEnumV0 != nil:
+		return entry.UdtErrorEnumV0.Name
+	}
+	return ""
+}
+
+// CustomSection returns the body of the named custom section, or nil when
+// the module has none
+func CustomSection(wasm []byte, name string) ([]byte, error) {
+	if len(wasm) < 8 || !bytes.Equal(wasm[:4], wasmMagic) {
+		return nil, errors.New("not a wasm module")
+	}
+
+	pos := 8
+	for pos < len(wasm) {
+		id := wasm[pos]
+		pos++
+		size, n := binary.Uvarint(wasm[pos:])
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid section size at offset %d", pos)
+		}
+		pos += n
+		end := pos + int(size)
+		if size > uint64(len(wasm)) || end > len(wasm) {
+			return nil, fmt.Errorf("section at offset %d overruns module", pos)
+		}
+
+		if id == 0 {
+			nameLen, n := binary.Uvarint(wasm[pos:end])
+			if n <= 0 || pos+n+int(nameLen) > end {
+				return nil, fmt.Errorf("invalid custom section name at offset %d", pos)
+			}
+			sectionName := string(wasm[pos+n : pos+n+int(nameLen)])
+			if sectionName == name {
+				return wasm[pos+n+int(nameLen) : end], nil
+			}
+		}
+		pos = end
+	}
+	return nil, nil
+}
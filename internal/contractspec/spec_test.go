@@ -0,0 +1,192 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contractspec
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func transferSpec() xdr.ScSpecEntry {
+	return xdr.ScSpecEntry{
+		Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0,
+		FunctionV0: &xdr.ScSpecFunctionV0{
+			Name: "transfer",
+			Inputs: []xdr.ScSpecFunctionInputV0{
+				{Name: "from", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeAddress}},
+				{Name: "to", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeAddress}},
+				{Name: "amount", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeI128}},
+			},
+		},
+	}
+}
+
+func section(id byte, body []byte) []byte {
+	out := []byte{id}
+	out = binary.AppendUvarint(out, uint64(len(body)))
+	return append(out, body...)
+}
+
+func customSection(name string, payload []byte) []byte {
+	body := binary.AppendUvarint(nil, uint64(len(name)))
+	body = append(body, name...)
+	return section(0, append(body, payload...))
+}
+
+func buildWasm(t *testing.T, entries ...xdr.ScSpecEntry) []byte {
+	t.Helper()
+	var payload []byte
+	for _, e := range entries {
+		b, err := e.MarshalBinary()
+		require.NoError(t, err)
+		payload = append(payload, b...)
+	}
+	wasm := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	wasm = append(wasm, section(1, []byte{0x00})...) // empty type section
+	wasm = append(wasm, customSection("contractmetav0", []byte{1, 2, 3})...)
+	wasm = append(wasm, customSection(SpecSectionName, payload)...)
+	return wasm
+}
+
+func TestParse(t *testing.T) {
+	spec, err := Parse(buildWasm(t, transferSpec()))
+	require.NoError(t, err)
+	require.Len(t, spec.Entries, 1)
+
+	fn, ok := spec.Function("transfer")
+	require.True(t, ok)
+	assert.Len(t, fn.Inputs, 3)
+
+	_, ok = spec.Function("mint")
+	assert.False(t, ok)
+}
+
+func TestSpec_Type(t *testing.T) {
+	pool := xdr.ScSpecEntry{
+		Kind: xdr.ScSpecEntryKindScSpecEntryUdtStructV0,
+		UdtStructV0: &xdr.ScSpecUdtStructV0{
+			Name: "Pool",
+			Fields: []xdr.ScSpecUdtStructFieldV0{
+				{Name: "reserve", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeI128}},
+			},
+		},
+	}
+	spec, err := Parse(buildWasm(t, transferSpec(), pool))
+	require.NoError(t, err)
+
+	entry, ok := spec.Type("Pool")
+	require.True(t, ok)
+	require.NotNil(t, entry.UdtStructV0)
+	assert.Len(t, entry.UdtStructV0.Fields, 1)
+
+	_, ok = spec.Type("transfer")
+	assert.False(t, ok)
+}
+
+func TestSpec_ContractError(t *testing.T) {
+	errs := xdr.ScSpecEntry{
+		Kind: xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0,
+		UdtErrorEnumV0: &xdr.ScSpecUdtErrorEnumV0{
+			Name: "Error",
+			Cases: []xdr.ScSpecUdtErrorEnumCaseV0{
+				{Name: "NotInitialized", Value: 1},
+				{Name: "InsufficientBalance", Value: 4, Doc: "Sender balance is too low"},
+			},
+		},
+	}
+	spec, err := Parse(buildWasm(t, transferSpec(), errs))
+	require.NoError(t, err)
+
+	name, doc, ok := spec.ContractError(4)
+	require.True(t, ok)
+	assert.Equal(t, "Error::InsufficientBalance", name)
+	assert.Equal(t, "Sender balance is too low", doc)
+
+	_, _, ok = spec.ContractError(9)
+	assert.False(t, ok)
+}
+
+func TestParse_NoSpec(t *testing.T) {
+	wasm := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	_, err := Parse(wasm)
+	assert.ErrorIs(t, err, ErrNoSpec)
+
+	_, err = Parse([]byte("not wasm"))
+	assert.Error(t, err)
+}
+
+func TestCustomSection_Truncated(t *testing.T) {
+	wasm := buildWasm(t, transferSpec())
+	_, err := CustomSection(wasm[:len(wasm)-4], SpecSectionName)
+	assert.Error(t, err)
+}
+
+func TestTypeName(t *testing.T) {
+	i128 := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeI128}
+	addr := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeAddress}
+
+	assert.Equal(t, "i128", TypeName(i128))
+	assert.Equal(t, "Vec<Address>", TypeName(xdr.ScSpecTypeDef{
+		Type: xdr.ScSpecTypeScSpecTypeVec, Vec: &xdr.ScSpecTypeVec{ElementType: addr},
+	}))
+	assert.Equal(t, "Option<i128>", TypeName(xdr.ScSpecTypeDef{
+		Type: xdr.ScSpecTypeScSpecTypeOption, Option: &xdr.ScSpecTypeOption{ValueType: i128},
+	}))
+	assert.Equal(t, "Map<Address, i128>", TypeName(xdr.ScSpecTypeDef{
+		Type: xdr.ScSpecTypeScSpecTypeMap, Map: &xdr.ScSpecTypeMap{KeyType: addr, ValueType: i128},
+	}))
+	assert.Equal(t, "BytesN<32>", TypeName(xdr.ScSpecTypeDef{
+		Type: xdr.ScSpecTypeScSpecTypeBytesN, BytesN: &xdr.ScSpecTypeBytesN{N: 32},
+	}))
+	assert.Equal(t, "DataKey", TypeName(xdr.ScSpecTypeDef{
+		Type: xdr.ScSpecTypeScSpecTypeUdt, Udt: &xdr.ScSpecTypeUdt{Name: "DataKey"},
+	}))
+}
+
+func TestDecodeInvocation(t *testing.T) {
+	spec, err := Parse(buildWasm(t, transferSpec()))
+	require.NoError(t, err)
+
+	from := xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	fromAddr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &from}
+	args := []xdr.ScVal{
+		{Type: xdr.ScValTypeScvAddress, Address: &fromAddr},
+		{Type: xdr.ScValTypeScvAddress, Address: &fromAddr},
+		{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: 0, Lo: 100}},
+	}
+
+	inv := DecodeInvocation(spec, "CCONTRACT", "transfer", args)
+	require.True(t, inv.SpecFound)
+	assert.Equal(t, Arg{Name: "amount", Type: "i128", Value: "100"}, inv.Args[2])
+	assert.Contains(t, inv.String(), "transfer(from: Address = GBRP")
+	assert.Contains(t, inv.String(), "amount: i128 = 100)")
+
+	raw := DecodeInvocation(nil, "CCONTRACT", "transfer", args[2:])
+	assert.False(t, raw.SpecFound)
+	assert.Equal(t, "transfer(100)", raw.String())
+}
+
+func TestDecodeInvocation_StellarAsset(t *testing.T) {
+	from := xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	fromAddr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &from}
+	args := []xdr.ScVal{
+		{Type: xdr.ScValTypeScvAddress, Address: &fromAddr},
+		{Type: xdr.ScValTypeScvAddress, Address: &fromAddr},
+		{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: 0, Lo: 25_000_000}},
+	}
+
+	inv := DecodeInvocation(StellarAssetSpec("native"), "CSAC", "transfer", args)
+	require.True(t, inv.SpecFound)
+	assert.Equal(t, "native", inv.Asset)
+	assert.Equal(t, Arg{Name: "to", Type: "MuxedAddress", Value: "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"}, inv.Args[1])
+	assert.Equal(t, "transfer 2.5 XLM from GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H to GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", inv.Summary)
+
+	balance := DecodeInvocation(StellarAssetSpec("native"), "CSAC", "balance", args[:1])
+	assert.Equal(t, "i128", balance.Returns)
+	assert.Empty(t, balance.Summary)
+}
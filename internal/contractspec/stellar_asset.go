@@ -0,0 +1,57 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contractspec
+
+import (
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// stellarAssetFunctions is the interface of the built-in Stellar Asset
+// Contract, which has no WASM and so no embedded spec
+var stellarAssetFunctions = []struct {
+	name    string
+	inputs  []string
+	types   []xdr.ScSpecType
+	returns xdr.ScSpecType
+}{
+	{"allowance", []string{"from", "spender"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeAddress}, xdr.ScSpecTypeScSpecTypeI128},
+	{"approve", []string{"from", "spender", "amount", "expiration_ledger"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeI128, xdr.ScSpecTypeScSpecTypeU32}, xdr.ScSpecTypeScSpecTypeVoid},
+	{"balance", []string{"id"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress}, xdr.ScSpecTypeScSpecTypeI128},
+	{"transfer", []string{"from", "to", "amount"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeMuxedAddress, xdr.ScSpecTypeScSpecTypeI128}, xdr.ScSpecTypeScSpecTypeVoid},
+	{"transfer_from", []string{"spender", "from", "to", "amount"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeI128}, xdr.ScSpecTypeScSpecTypeVoid},
+	{"burn", []string{"from", "amount"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeI128}, xdr.ScSpecTypeScSpecTypeVoid},
+	{"burn_from", []string{"spender", "from", "amount"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeI128}, xdr.ScSpecTypeScSpecTypeVoid},
+	{"decimals", nil, nil, xdr.ScSpecTypeScSpecTypeU32},
+	{"name", nil, nil, xdr.ScSpecTypeScSpecTypeString},
+	{"symbol", nil, nil, xdr.ScSpecTypeScSpecTypeString},
+	{"admin", nil, nil, xdr.ScSpecTypeScSpecTypeAddress},
+	{"set_admin", []string{"new_admin"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress}, xdr.ScSpecTypeScSpecTypeVoid},
+	{"authorized", []string{"id"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress}, xdr.ScSpecTypeScSpecTypeBool},
+	{"set_authorized", []string{"id", "authorize"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeBool}, xdr.ScSpecTypeScSpecTypeVoid},
+	{"mint", []string{"to", "amount"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeI128}, xdr.ScSpecTypeScSpecTypeVoid},
+	{"clawback", []string{"from", "amount"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeI128}, xdr.ScSpecTypeScSpecTypeVoid},
+	{"trust", []string{"addr"}, []xdr.ScSpecType{xdr.ScSpecTypeScSpecTypeAddress}, xdr.ScSpecTypeScSpecTypeVoid},
+}
+
+// StellarAssetSpec returns the spec of the Stellar Asset Contract for asset,
+// given as "native" or "CODE:ISSUER"
+func StellarAssetSpec(asset string) *Spec {
+	spec := &Spec{
+		functions:    make(map[string]xdr.ScSpecFunctionV0),
+		types:        make(map[string]xdr.ScSpecEntry),
+		StellarAsset: asset,
+	}
+	for _, f := range stellarAssetFunctions {
+		fn := xdr.ScSpecFunctionV0{Name: xdr.ScSymbol(f.name)}
+		for i, name := range f.inputs {
+			fn.Inputs = append(fn.Inputs, xdr.ScSpecFunctionInputV0{Name: name, Type: xdr.ScSpecTypeDef{Type: f.types[i]}})
+		}
+		if f.returns != xdr.ScSpecTypeScSpecTypeVoid {
+			fn.Outputs = []xdr.ScSpecTypeDef{{Type: f.returns}}
+		}
+		spec.Entries = append(spec.Entries, xdr.ScSpecEntry{Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0, FunctionV0: &fn})
+		spec.functions[f.name] = fn
+	}
+	return spec
+}
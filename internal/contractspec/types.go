@@ -0,0 +1,77 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contractspec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var primitiveTypeNames = map[xdr.ScSpecType]string{
+	xdr.ScSpecTypeScSpecTypeVal:          "Val",
+	xdr.ScSpecTypeScSpecTypeBool:         "bool",
+	xdr.ScSpecTypeScSpecTypeVoid:         "()",
+	xdr.ScSpecTypeScSpecTypeError:        "Error",
+	xdr.ScSpecTypeScSpecTypeU32:          "u32",
+	xdr.ScSpecTypeScSpecTypeI32:          "i32",
+	xdr.ScSpecTypeScSpecTypeU64:          "u64",
+	xdr.ScSpecTypeScSpecTypeI64:          "i64",
+	xdr.ScSpecTypeScSpecTypeTimepoint:    "Timepoint",
+	xdr.ScSpecTypeScSpecTypeDuration:     "Duration",
+	xdr.ScSpecTypeScSpecTypeU128:         "u128",
+	xdr.ScSpecTypeScSpecTypeI128:         "i128",
+	xdr.ScSpecTypeScSpecTypeU256:         "U256",
+	xdr.ScSpecTypeScSpecTypeI256:         "I256",
+	xdr.ScSpecTypeScSpecTypeBytes:        "Bytes",
+	xdr.ScSpecTypeScSpecTypeString:       "String",
+	xdr.ScSpecTypeScSpecTypeSymbol:       "Symbol",
+	xdr.ScSpecTypeScSpecTypeAddress:      "Address",
+	xdr.ScSpecTypeScSpecTypeMuxedAddress: "MuxedAddress",
+}
+
+// TypeName renders a spec type the way it is written in a Rust contract,
+// e.g. "Vec<Address>" or "Option<i128>"
+func TypeName(t xdr.ScSpecTypeDef) string {
+	if name, ok := primitiveTypeNames[t.Type]; ok {
+		return name
+	}
+
+	switch t.Type {
+	case xdr.ScSpecTypeScSpecTypeOption:
+		if t.Option != nil {
+			return "Option<" + TypeName(t.Option.ValueType) + ">"
+		}
+	case xdr.ScSpecTypeScSpecTypeResult:
+		if t.Result != nil {
+			return "Result<" + TypeName(t.Result.OkType) + ", " + TypeName(t.Result.ErrorType) + ">"
+		}
+	case xdr.ScSpecTypeScSpecTypeVec:
+		if t.Vec != nil {
+			return "Vec<" + TypeName(t.Vec.ElementType) + ">"
+		}
+	case xdr.ScSpecTypeScSpecTypeMap:
+		if t.Map != nil {
+			return "Map<" + TypeName(t.Map.KeyType) + ", " + TypeName(t.Map.ValueType) + ">"
+		}
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		if t.Tuple != nil {
+			parts := make([]string, len(t.Tuple.ValueTypes))
+			for i, vt := range t.Tuple.ValueTypes {
+				parts[i] = TypeName(vt)
+			}
+			return "(" + strings.Join(parts, ", ") + ")"
+		}
+	case xdr.ScSpecTypeScSpecTypeBytesN:
+		if t.BytesN != nil {
+			return fmt.Sprintf("BytesN<%d>", t.BytesN.N)
+		}
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		if t.Udt != nil {
+			return t.Udt.Name
+		}
+	}
+	return strings.TrimPrefix(t.Type.String(), "ScSpecTypeScSpecType")
+}
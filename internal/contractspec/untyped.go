@@ -0,0 +1,65 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contractspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// ParseUntyped converts s to an SCVal without a spec type, the way storage
+// keys are usually written. Strings are addresses when they are valid
+// strkeys and symbols otherwise, integers are u32, and JSON arrays are Vecs,
+// so a contracttype enum key such as DataKey::Balance(addr) is written
+// ["Balance", "G..."]. Anything else needs "xdr:<base64>".
+func ParseUntyped(s string) (xdr.ScVal, error) {
+	if strings.HasPrefix(s, "xdr:") {
+		return ParseValue(nil, xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeVal}, s)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		// Bare words are not JSON: Admin, GABC...
+		return untypedFromJSON(s)
+	}
+	return untypedFromJSON(v)
+}
+
+func untypedFromJSON(v interface{}) (xdr.ScVal, error) {
+	switch v := v.(type) {
+	case string:
+		if addr, err := parseAddress(v); err == nil {
+			return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &addr}, nil
+		}
+		return symbolVal(v), nil
+	case bool:
+		return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &v}, nil
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil || n < 0 || n > math.MaxUint32 {
+			return xdr.ScVal{}, fmt.Errorf("%s is not a u32; give other numbers as xdr:<base64>", v)
+		}
+		u := xdr.Uint32(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u}, nil
+	case []interface{}:
+		elems := make([]xdr.ScVal, len(v))
+		for i, e := range v {
+			val, err := untypedFromJSON(e)
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			elems[i] = val
+		}
+		return vecVal(elems), nil
+	case nil:
+		return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+	}
+	return xdr.ScVal{}, fmt.Errorf("cannot infer the type of %v; give it as xdr:<base64>", v)
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package coverage reports which contracts and exported functions a set of
+// simulated transactions exercised.
+package coverage
+
+import (
+	"sort"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// Function is one function of a contract and how often it was called
+type Function struct {
+	Name   string `json:"name"`
+	Calls  int    `json:"calls"`
+	Failed int    `json:"failed,omitempty"`
+	// Exported is false for functions that were called but are not in the
+	// contract's interface, e.g. built-in functions of asset contracts
+	Exported bool `json:"exported"`
+}
+
+// Contract is the coverage of one contract's exported functions
+type Contract struct {
+	Contract string `json:"contract"`
+	// ExportsKnown is false when the contract's interface could not be
+	// read, in which case only the called functions are listed
+	ExportsKnown bool       `json:"exports_known"`
+	Functions    []Function `json:"functions"`
+	Covered      int        `json:"covered"`
+	Total        int        `json:"total"`
+}
+
+// Missed returns the exported functions that were never called
+func (c Contract) Missed() []string {
+	var missed []string
+	for _, f := range c.Functions {
+		if f.Exported && f.Calls == 0 {
+			missed = append(missed, f.Name)
+		}
+	}
+	return missed
+}
+
+// Report is the coverage of every contract the transactions called
+type Report struct {
+	Transactions int        `json:"transactions"`
+	Contracts    []Contract `json:"contracts"`
+	// Covered and Total count exported functions across contracts whose
+	// exports are known
+	Covered int `json:"covered"`
+	Total   int `json:"total"`
+}
+
+// Percent is the share of known exported functions that were called
+func (r *Report) Percent() float64 {
+	return percent(r.Covered, r.Total)
+}
+
+// Percent is the share of the contract's exported functions that were called
+func (c Contract) Percent() float64 {
+	return percent(c.Covered, c.Total)
+}
+
+func percent(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(covered) / float64(total)
+}
+
+type hits struct {
+	calls, failed int
+}
+
+// Collector accumulates the calls made by simulated transactions
+type Collector struct {
+	transactions int
+	contracts    map[string]map[string]*hits
+	order        []string
+}
+
+// NewCollector returns an empty collector
+func NewCollector() *Collector {
+	return &Collector{contracts: make(map[string]map[string]*hits)}
+}
+
+// Add records every call in one transaction's call tree
+func (c *Collector) Add(roots []*simulator.CallNode) {
+	c.transactions++
+	var walk func(n *simulator.CallNode)
+	walk = func(n *simulator.CallNode) {
+		fns, ok := c.contracts[n.Contract]
+		if !ok {
+			fns = make(map[string]*hits)
+			c.contracts[n.Contract] = fns
+			c.order = append(c.order, n.Contract)
+		}
+		h, ok := fns[n.Function]
+		if !ok {
+			h = &hits{}
+			fns[n.Function] = h
+		}
+		h.calls++
+		if n.Status == simulator.CallFailed {
+			h.failed++
+		}
+		for _, sub := range n.SubCalls {
+			walk(sub)
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+}
+
+// Contracts returns the called contracts in the order they were first seen
+func (c *Collector) Contracts() []string {
+	return append([]string(nil), c.order...)
+}
+
+// Report builds the coverage report. exports returns a contract's exported
+// functions; an error leaves that contract's exports unknown.
+func (c *Collector) Report(exports func(contract string) ([]string, error)) *Report {
+	report := &Report{Transactions: c.transactions}
+	for _, id := range c.order {
+		called := c.contracts[id]
+		cov := Contract{Contract: id}
+
+		names, err := exports(id)
+		cov.ExportsKnown = err == nil
+		seen := make(map[string]bool)
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			fn := Function{Name: name, Exported: true}
+			if h, ok := called[name]; ok {
+				fn.Calls, fn.Failed = h.calls, h.failed
+				cov.Covered++
+			}
+			cov.Total++
+			cov.Functions = append(cov.Functions, fn)
+		}
+
+		var extra []Function
+		for name, h := range called {
+			if !seen[name] {
+				extra = append(extra, Function{Name: name, Calls: h.calls, Failed: h.failed})
+			}
+		}
+		sort.Slice(extra, func(i, j int) bool { return extra[i].Name < extra[j].Name })
+		cov.Functions = append(cov.Functions, extra...)
+
+		if cov.ExportsKnown {
+			report.Covered += cov.Covered
+			report.Total += cov.Total
+		}
+		report.Contracts = append(report.Contracts, cov)
+	}
+	return report
+}
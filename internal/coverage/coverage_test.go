@@ -0,0 +1,65 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package coverage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func node(contract, fn, status string, subs ...*simulator.CallNode) *simulator.CallNode {
+	return &simulator.CallNode{Contract: contract, Function: fn, Status: status, SubCalls: subs}
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector()
+	c.Add([]*simulator.CallNode{
+		node("CPOOL", "swap", simulator.CallOK,
+			node("CTOKEN", "transfer", simulator.CallOK),
+			node("CSAC", "balance", simulator.CallOK),
+		),
+	})
+	c.Add([]*simulator.CallNode{
+		node("CPOOL", "swap", simulator.CallFailed,
+			node("CTOKEN", "transfer", simulator.CallFailed),
+		),
+	})
+	assert.Equal(t, []string{"CPOOL", "CTOKEN", "CSAC"}, c.Contracts())
+
+	report := c.Report(func(contract string) ([]string, error) {
+		switch contract {
+		case "CPOOL":
+			return []string{"swap", "deposit", "withdraw"}, nil
+		case "CTOKEN":
+			return []string{"transfer", "approve"}, nil
+		}
+		return nil, errors.New("no wasm")
+	})
+
+	assert.Equal(t, 2, report.Transactions)
+	assert.Equal(t, 2, report.Covered)
+	assert.Equal(t, 5, report.Total)
+	assert.InDelta(t, 40.0, report.Percent(), 0.01)
+
+	require.Len(t, report.Contracts, 3)
+	pool := report.Contracts[0]
+	assert.Equal(t, Function{Name: "swap", Calls: 2, Failed: 1, Exported: true}, pool.Functions[0])
+	assert.Equal(t, []string{"deposit", "withdraw"}, pool.Missed())
+	assert.InDelta(t, 33.3, pool.Percent(), 0.1)
+
+	sac := report.Contracts[2]
+	assert.False(t, sac.ExportsKnown)
+	assert.Equal(t, []Function{{Name: "balance", Calls: 1}}, sac.Functions)
+	assert.Empty(t, sac.Missed())
+}
+
+func TestReport_Empty(t *testing.T) {
+	report := NewCollector().Report(func(string) ([]string, error) { return nil, nil })
+	assert.Empty(t, report.Contracts)
+	assert.Zero(t, report.Percent())
+}
@@ -9,12 +9,16 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/dotandev/hintents/internal/alert"
 	"github.com/dotandev/hintents/internal/errors"
 	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/metrics"
 	stellarrpc "github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/dotandev/hintents/internal/telemetry"
+	"github.com/dotandev/hintents/internal/webhook"
 	"github.com/gorilla/rpc/v2"
 	"github.com/gorilla/rpc/v2/json2"
 	"go.opentelemetry.io/otel/attribute"
@@ -25,6 +29,8 @@ type Server struct {
 	rpcClient *stellarrpc.Client
 	simulator *simulator.Runner
 	authToken string
+	notifier  *webhook.SimulatorNotifier
+	alerts    *alert.Engine
 }
 
 // Config holds daemon configuration
@@ -33,6 +39,10 @@ type Config struct {
 	Network   string
 	RPCURL    string
 	AuthToken string
+	// Notifier, when set, is notified of failed simulations
+	Notifier *webhook.SimulatorNotifier
+	// Alerts, when set, are the alerting rules applied to failed simulations
+	Alerts *alert.Engine
 }
 
 // DebugTransactionRequest represents the debug_transaction RPC request
@@ -46,6 +56,7 @@ type DebugTransactionResponse struct {
 	Network      string `json:"network"`
 	EnvelopeSize int    `json:"envelope_size"`
 	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
 }
 
 // GetTraceRequest represents the get_trace RPC request
@@ -83,6 +94,8 @@ func NewServer(config Config) (*Server, error) {
 		rpcClient: client,
 		simulator: sim,
 		authToken: config.AuthToken,
+		notifier:  config.Notifier,
+		alerts:    config.Alerts,
 	}, nil
 }
 
@@ -127,16 +140,51 @@ func (s *Server) DebugTransaction(r *http.Request, req *DebugTransactionRequest,
 		return errors.WrapRPCConnectionFailed(err)
 	}
 
+	// Ledger state comes from the transaction meta; missing entries are
+	// reported by the simulator rather than failing the call
+	entries, err := stellarrpc.ExtractLedgerEntriesFromMeta(txResp.ResultMetaXdr)
+	if err != nil {
+		logger.Logger.Warn("Failed to extract ledger entries from metadata", "hash", req.Hash, "error", err)
+	}
+	simResp, err := simulator.RunTraced(ctx, s.simulator, &simulator.SimulationRequest{
+		EnvelopeXdr:    txResp.EnvelopeXdr,
+		ResultMetaXdr:  txResp.ResultMetaXdr,
+		LedgerEntries:  entries,
+		LedgerSequence: txResp.Ledger,
+	})
+	if err != nil {
+		span.RecordError(err)
+		s.notifyFailure(ctx, webhook.ReportData{TxHash: req.Hash, Status: "error", Error: err.Error(), Timestamp: time.Now()})
+		return errors.WrapSimulationFailed(err, "")
+	}
+	if simResp.Status != "success" {
+		s.notifyFailure(ctx, webhook.NewReport(simResp, req.Hash, ""))
+	}
+
 	*resp = DebugTransactionResponse{
 		Hash:         req.Hash,
 		Network:      string(s.rpcClient.Network),
 		EnvelopeSize: len(txResp.EnvelopeXdr),
-		Status:       "success",
+		Status:       simResp.Status,
+		Error:        simResp.Error,
 	}
 
 	return nil
 }
 
+// notifyFailure sends a failed simulation to the configured webhook and
+// alerting rules
+func (s *Server) notifyFailure(ctx context.Context, report webhook.ReportData) {
+	report.Network = string(s.rpcClient.Network)
+	report.Source = "daemon"
+	if s.notifier != nil {
+		s.notifier.Notify(report)
+	}
+	if s.alerts != nil {
+		s.alerts.Evaluate(ctx, alert.Failure{Report: report})
+	}
+}
+
 // GetTrace handles get_trace RPC calls
 func (s *Server) GetTrace(r *http.Request, req *GetTraceRequest, resp *GetTraceResponse) error {
 	if !s.authenticate(r) {
@@ -179,6 +227,7 @@ func (s *Server) Start(ctx context.Context, port string) error {
 	}
 
 	http.Handle("/rpc", server)
+	http.Handle("/metrics", metrics.Handler())
 
 	// Health check endpoint
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -112,17 +112,10 @@ func parseEvent(diag xdr.DiagnosticEvent) DecodedEvent {
 
 	topics := make([]string, 0)
 	for _, topic := range diag.Event.Body.V0.Topics {
-		// Attempt to convert to string if symbol, otherwise hex/debug
-		if topic.Type == xdr.ScValTypeScvSymbol {
-			topics = append(topics, string(*topic.Sym))
-		} else {
-			// Fallback for other types
-			topics = append(topics, fmt.Sprintf("%v", topic.Type))
-		}
+		topics = append(topics, FormatScVal(topic))
 	}
 
-	// Simple data stringification
-	data := fmt.Sprintf("%v", diag.Event.Body.V0.Data.Type)
+	data := FormatScVal(diag.Event.Body.V0.Data)
 
 	return DecodedEvent{
 		ContractID: contractID,
@@ -4,8 +4,10 @@
 package decoder
 
 import (
+	"encoding/hex"
 	"fmt"
 
+	"github.com/stellar/go-stellar-sdk/network"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
 
@@ -82,3 +84,36 @@ func DecodeEnvelopeFromInner(inner xdr.FeeBumpTransactionInnerTx) (*DecodedEnvel
 		return nil, fmt.Errorf("unsupported inner tx type")
 	}
 }
+
+// FeeBumpSummary describes a fee-bump envelope: the account paying the fee
+// and the transaction it pays for
+type FeeBumpSummary struct {
+	FeeSource   string `json:"fee_source"`
+	Fee         int64  `json:"fee"` // maximum fee in stroops, covering the inner transaction
+	InnerSource string `json:"inner_source"`
+	InnerFee    int64  `json:"inner_fee"`
+	InnerHash   string `json:"inner_hash,omitempty"`
+}
+
+// DescribeFeeBump summarises a fee-bump envelope, hashing the inner
+// transaction when passphrase is set. It returns nil for other envelopes.
+func DescribeFeeBump(envelopeXdr, passphrase string) *FeeBumpSummary {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil || !env.IsFeeBump() || env.FeeBump.Tx.InnerTx.V1 == nil {
+		return nil
+	}
+	fb := env.FeeBump.Tx
+	inner := fb.InnerTx.V1.Tx
+	summary := &FeeBumpSummary{
+		FeeSource:   DisplayAddress(fb.FeeSource.Address()),
+		Fee:         int64(fb.Fee),
+		InnerSource: DisplayAddress(inner.SourceAccount.Address()),
+		InnerFee:    int64(inner.Fee),
+	}
+	if passphrase != "" {
+		if hash, err := network.HashTransaction(inner, passphrase); err == nil {
+			summary.InnerHash = hex.EncodeToString(hash[:])
+		}
+	}
+	return summary
+}
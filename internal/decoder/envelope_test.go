@@ -0,0 +1,53 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeFeeBump(t *testing.T) {
+	inner := xdr.TransactionV1Envelope{
+		Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(sacHolder),
+			Fee:           100,
+			SeqNum:        1,
+		},
+	}
+	env := xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: &inner}
+	envXdr, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	assert.Nil(t, DescribeFeeBump(envXdr, ""))
+
+	feeBump := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+		FeeBump: &xdr.FeeBumpTransactionEnvelope{
+			Tx: xdr.FeeBumpTransaction{
+				FeeSource: xdr.MustMuxedAddress(sacIssuer),
+				Fee:       5000,
+				InnerTx:   xdr.FeeBumpTransactionInnerTx{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: &inner},
+			},
+		},
+	}
+	feeBumpXdr, err := xdr.MarshalBase64(feeBump)
+	require.NoError(t, err)
+
+	summary := DescribeFeeBump(feeBumpXdr, sacPassphrase)
+	require.NotNil(t, summary)
+	assert.Equal(t, sacIssuer, summary.FeeSource)
+	assert.Equal(t, int64(5000), summary.Fee)
+	assert.Equal(t, sacHolder, summary.InnerSource)
+	assert.Equal(t, int64(100), summary.InnerFee)
+
+	hash, err := network.HashTransactionInEnvelope(env, sacPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, xdr.Hash(hash).HexString(), summary.InnerHash)
+
+	assert.Empty(t, DescribeFeeBump(feeBumpXdr, "").InnerHash)
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrorExplanation is a host error, contract error or WASM trap rendered for
+// humans
+type ErrorExplanation struct {
+	// Error is the ScError as it appears in the simulator output, e.g.
+	// "Error(Contract, #4)"; empty when only a trap was recognised
+	Error string `json:"error,omitempty"`
+	Type  string `json:"type,omitempty"`
+	Code  string `json:"code,omitempty"`
+	// ContractCode is the number of a contract-defined error
+	ContractCode *uint32 `json:"contract_code,omitempty"`
+	// Trap is the WASM trap behind a WasmVm error, when known
+	Trap        string `json:"trap,omitempty"`
+	Summary     string `json:"summary"`
+	Explanation string `json:"explanation"`
+	// ContractError and ContractErrorDoc name a contract error from the
+	// failing contract's spec, e.g. "Error::InsufficientBalance"
+	ContractError    string `json:"contract_error,omitempty"`
+	ContractErrorDoc string `json:"contract_error_doc,omitempty"`
+}
+
+type catalogEntry struct {
+	summary     string
+	explanation string
+}
+
+// errorTypes describes each ScError type, used when no entry for the exact
+// type and code exists
+var errorTypes = map[string]catalogEntry{
+	"Contract": {"Contract error", "The contract returned one of its own errors, usually from a failed check such as a panic_with_error! or an Err result."},
+	"WasmVm":   {"WASM execution failed", "The WASM virtual machine stopped the contract, typically on a trap such as a panic, an arithmetic overflow or unreachable code."},
+	"Context":  {"Invalid host context", "The host was used in a way its current call context does not allow, e.g. a call from a frame that has already returned."},
+	"Storage":  {"Storage error", "Reading or writing contract storage failed."},
+	"Object":   {"Host object error", "An operation on a host object such as a Vec, Map, Bytes or String failed."},
+	"Crypto":   {"Cryptography error", "A cryptographic host function rejected its input, e.g. a malformed key or signature."},
+	"Events":   {"Event error", "Emitting a contract event failed."},
+	"Budget":   {"Budget error", "The transaction's CPU or memory budget was exhausted or misused."},
+	"Value":    {"Invalid value", "A value had the wrong type or could not be converted, often an argument that does not match the function's signature."},
+	"Auth":     {"Authorization error", "An authorization check failed: a require_auth call had no matching signed authorization entry."},
+}
+
+// errorCodes describes each ScError code, appended to the type description
+var errorCodes = map[string]string{
+	"ArithDomain":    "An arithmetic operation was given operands outside its domain, such as an overflow or a division by zero.",
+	"IndexBounds":    "An index was out of bounds.",
+	"InvalidInput":   "An input was invalid.",
+	"MissingValue":   "A required value was missing.",
+	"ExistingValue":  "A value that must not exist was already present.",
+	"ExceededLimit":  "A limit was exceeded.",
+	"InvalidAction":  "The action is not allowed in this state.",
+	"InternalError":  "The host hit an internal error; this may be a bug in the host or simulator.",
+	"UnexpectedType": "A value had an unexpected type.",
+	"UnexpectedSize": "A value had an unexpected size.",
+}
+
+// specificErrors covers type and code pairs common enough to deserve their
+// own explanation
+var specificErrors = map[string]catalogEntry{
+	"Budget/ExceededLimit":  {"Resource budget exceeded", "The transaction ran out of CPU instructions or memory. Raise the resource limits, or reduce the work done, e.g. fewer loop iterations or smaller storage values."},
+	"Storage/MissingValue":  {"Ledger entry not found", "The contract read a storage entry that does not exist or has been archived. Check the key, and restore the entry if its TTL expired."},
+	"Storage/ExceededLimit": {"Storage access outside the footprint", "The contract accessed a ledger entry that is not in the transaction's footprint, or the entry exceeds a size limit. Re-run preflight to rebuild the footprint."},
+	"Storage/InvalidAction": {"Read-only storage written", "The contract wrote a ledger entry the footprint declares read-only."},
+	"Auth/InvalidAction":    {"Missing authorization", "require_auth failed: the transaction carries no authorization entry for this address and invocation, or its signature does not match."},
+	"Auth/InvalidInput":     {"Malformed authorization", "An authorization entry could not be verified, e.g. a bad signature or an expired signature ledger."},
+	"Auth/ExistingValue":    {"Authorization replayed", "The authorization entry's nonce has already been used."},
+	"WasmVm/InvalidAction":  {"WASM trap", "The contract trapped, usually on a Rust panic, an unwrap of None or Err, an arithmetic overflow or unreachable code."},
+	"WasmVm/ExceededLimit":  {"WASM limit exceeded", "The contract exceeded a VM limit such as stack depth, memory pages or table size."},
+	"Context/ExceededLimit": {"Call depth exceeded", "Contract calls nested deeper than the host allows."},
+	"Value/UnexpectedType":  {"Argument type mismatch", "A value did not have the type the contract or host function expected, e.g. an argument that does not match the function's signature."},
+	"Value/InvalidInput":    {"Invalid argument value", "A value could not be converted to the expected type, e.g. a number out of range or an invalid symbol."},
+	"Object/IndexBounds":    {"Index out of bounds", "A Vec, Bytes or String was indexed past its end."},
+	"Object/MissingValue":   {"Map key not found", "A Map lookup found no value for the key."},
+	"Object/ArithDomain":    {"Arithmetic overflow", "An arithmetic host function overflowed or divided by zero, e.g. on i128 or u256 values."},
+	"WasmVm/MissingValue":   {"Function not found", "The contract does not export the function that was called."},
+}
+
+// wasmTraps maps WASM trap kinds to their explanation. Keys are the trap
+// kinds the simulator reports in stack traces.
+var wasmTraps = map[string]catalogEntry{
+	"Unreachable":              {"Unreachable code executed", "The contract hit an unreachable instruction, which is how Rust panics, failed unwraps and failed asserts abort."},
+	"IntegerOverflow":          {"Integer overflow", "An arithmetic operation overflowed. Use checked arithmetic, e.g. checked_add, and return an error instead."},
+	"IntegerDivisionByZero":    {"Division by zero", "An integer was divided by zero."},
+	"InvalidConversionToInt":   {"Invalid float to integer conversion", "A float could not be converted to an integer."},
+	"OutOfBoundsMemoryAccess":  {"Out of bounds memory access", "The contract read or wrote linear memory past its end."},
+	"OutOfBoundsTableAccess":   {"Out of bounds table access", "An indirect call used a table index past the end of the table."},
+	"StackOverflow":            {"Stack overflow", "The contract's call stack was exhausted, usually by deep or unbounded recursion."},
+	"IndirectCallTypeMismatch": {"Indirect call type mismatch", "An indirect call's signature did not match the function it called."},
+	"UndefinedElement":         {"Undefined table element", "An indirect call targeted an uninitialised table element."},
+}
+
+// trapMessages recognises traps from the raw error text
+var trapMessages = []struct {
+	pattern string
+	kind    string
+}{
+	{"unreachable", "Unreachable"},
+	{"integer overflow", "IntegerOverflow"},
+	{"divide by zero", "IntegerDivisionByZero"},
+	{"division by zero", "IntegerDivisionByZero"},
+	{"invalid conversion to int", "InvalidConversionToInt"},
+	{"out of bounds memory", "OutOfBoundsMemoryAccess"},
+	{"memory out of bounds", "OutOfBoundsMemoryAccess"},
+	{"out of bounds table", "OutOfBoundsTableAccess"},
+	{"stack overflow", "StackOverflow"},
+	{"call stack exhausted", "StackOverflow"},
+	{"indirect call type mismatch", "IndirectCallTypeMismatch"},
+	{"undefined element", "UndefinedElement"},
+}
+
+var scErrorPattern = regexp.MustCompile(`Error\((\w+), (#?\w+)\)`)
+
+// ParseScError finds the first host error such as "Error(Contract, #3)" or
+// "Error(Budget, ExceededLimit)" in msg and returns it with its type and code
+func ParseScError(msg string) (match, errType, code string, ok bool) {
+	m := scErrorPattern.FindStringSubmatch(msg)
+	if m == nil {
+		return "", "", "", false
+	}
+	return m[0], m[1], m[2], true
+}
+
+// ExplainError explains the first ScError or WASM trap found in msg, such as
+// a simulator error string. It returns nil when nothing is recognised.
+func ExplainError(msg string) *ErrorExplanation {
+	trap := trapKindFromMessage(msg)
+
+	match, errType, code, ok := ParseScError(msg)
+	if !ok {
+		if trap == "" {
+			return nil
+		}
+		exp := &ErrorExplanation{}
+		exp.SetTrap(trap)
+		return exp
+	}
+
+	exp := &ErrorExplanation{Error: match, Type: errType, Code: code}
+	if strings.HasPrefix(code, "#") {
+		if n, err := strconv.ParseUint(code[1:], 10, 32); err == nil {
+			contractCode := uint32(n)
+			exp.ContractCode = &contractCode
+		}
+	}
+
+	switch {
+	case exp.ContractCode != nil:
+		entry := errorTypes["Contract"]
+		exp.Summary = "Contract error " + code
+		exp.Explanation = entry.explanation
+	case specificErrors[errType+"/"+code] != catalogEntry{}:
+		entry := specificErrors[errType+"/"+code]
+		exp.Summary, exp.Explanation = entry.summary, entry.explanation
+	default:
+		entry, ok := errorTypes[errType]
+		if !ok {
+			entry = catalogEntry{"Host error", "The Soroban host reported an error."}
+		}
+		exp.Summary, exp.Explanation = entry.summary, entry.explanation
+		if desc, ok := errorCodes[code]; ok {
+			exp.Explanation += " " + desc
+		}
+	}
+
+	if exp.Type == "WasmVm" && trap != "" {
+		exp.SetTrap(trap)
+	}
+	return exp
+}
+
+// SetTrap replaces the explanation with that of a WASM trap kind, e.g.
+// "IntegerOverflow". Unknown kinds are ignored.
+func (e *ErrorExplanation) SetTrap(kind string) {
+	entry, ok := wasmTraps[kind]
+	if !ok {
+		return
+	}
+	e.Trap = kind
+	e.Summary, e.Explanation = entry.summary, entry.explanation
+}
+
+func trapKindFromMessage(msg string) string {
+	lower := strings.ToLower(msg)
+	for _, t := range trapMessages {
+		if strings.Contains(lower, t.pattern) {
+			return t.kind
+		}
+	}
+	return ""
+}
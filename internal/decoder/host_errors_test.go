@@ -0,0 +1,58 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainError_ContractCode(t *testing.T) {
+	exp := ExplainError("HostError: Error(Contract, #4)\n\nEvent log (newest first): ...")
+	require.NotNil(t, exp)
+	assert.Equal(t, "Error(Contract, #4)", exp.Error)
+	assert.Equal(t, "Contract", exp.Type)
+	require.NotNil(t, exp.ContractCode)
+	assert.Equal(t, uint32(4), *exp.ContractCode)
+	assert.Equal(t, "Contract error #4", exp.Summary)
+	assert.NotEmpty(t, exp.Explanation)
+}
+
+func TestExplainError_Specific(t *testing.T) {
+	exp := ExplainError("HostError: Error(Budget, ExceededLimit)")
+	require.NotNil(t, exp)
+	assert.Equal(t, "Resource budget exceeded", exp.Summary)
+	assert.Nil(t, exp.ContractCode)
+
+	exp = ExplainError("HostError: Error(Auth, InvalidAction)")
+	require.NotNil(t, exp)
+	assert.Equal(t, "Missing authorization", exp.Summary)
+}
+
+func TestExplainError_TypeAndCode(t *testing.T) {
+	exp := ExplainError("Error(Crypto, UnexpectedSize)")
+	require.NotNil(t, exp)
+	assert.Equal(t, "Cryptography error", exp.Summary)
+	assert.Contains(t, exp.Explanation, "unexpected size")
+}
+
+func TestExplainError_Trap(t *testing.T) {
+	exp := ExplainError("HostError: Error(WasmVm, InvalidAction)\nwasm trap: integer overflow")
+	require.NotNil(t, exp)
+	assert.Equal(t, "IntegerOverflow", exp.Trap)
+	assert.Equal(t, "Integer overflow", exp.Summary)
+	assert.Equal(t, "Error(WasmVm, InvalidAction)", exp.Error)
+
+	exp = ExplainError("wasm trap: unreachable")
+	require.NotNil(t, exp)
+	assert.Equal(t, "Unreachable", exp.Trap)
+	assert.Empty(t, exp.Error)
+}
+
+func TestExplainError_Unrecognised(t *testing.T) {
+	assert.Nil(t, ExplainError(""))
+	assert.Nil(t, ExplainError("connection refused"))
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/amount"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// FormatLedgerEntry renders the value a ledger entry holds: the stored SCVal
+// of contract data, balances of accounts and trustlines, the size of contract
+// code and the expiry of TTL entries
+func FormatLedgerEntry(entry xdr.LedgerEntry) string {
+	data := entry.Data
+	switch data.Type {
+	case xdr.LedgerEntryTypeAccount:
+		return fmt.Sprintf("balance %s XLM, seq %d", amount.String(data.Account.Balance), data.Account.SeqNum)
+	case xdr.LedgerEntryTypeTrustline:
+		return fmt.Sprintf("balance %s, limit %s", amount.String(data.TrustLine.Balance), amount.String(data.TrustLine.Limit))
+	case xdr.LedgerEntryTypeData:
+		return fmt.Sprintf("%q", string(data.Data.DataValue))
+	case xdr.LedgerEntryTypeContractData:
+		return FormatScVal(data.ContractData.Val)
+	case xdr.LedgerEntryTypeContractCode:
+		return fmt.Sprintf("wasm %s, %d bytes", hex.EncodeToString(data.ContractCode.Hash[:]), len(data.ContractCode.Code))
+	case xdr.LedgerEntryTypeTtl:
+		return fmt.Sprintf("live until ledger %d", data.Ttl.LiveUntilLedgerSeq)
+	}
+	return data.Type.String()
+}
+
+// FormatLedgerEntryBase64 decodes a base64 XDR LedgerEntry and renders it
+// with FormatLedgerEntry
+func FormatLedgerEntryBase64(s string) (string, error) {
+	var entry xdr.LedgerEntry
+	if err := xdr.SafeUnmarshalBase64(s, &entry); err != nil {
+		return "", fmt.Errorf("failed to decode LedgerEntry: %w", err)
+	}
+	return FormatLedgerEntry(entry), nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatLedgerEntry(t *testing.T) {
+	account := xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{
+			AccountId: xdr.MustAddress(keypair.MustRandom().Address()),
+			Balance:   125_000_000,
+			SeqNum:    42,
+		},
+	}}
+	assert.Equal(t, "balance 12.5000000 XLM, seq 42", FormatLedgerEntry(account))
+
+	ttl := xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeTtl,
+		Ttl:  &xdr.TtlEntry{LiveUntilLedgerSeq: 900},
+	}}
+	b64, err := xdr.MarshalBase64(ttl)
+	require.NoError(t, err)
+	got, err := FormatLedgerEntryBase64(b64)
+	require.NoError(t, err)
+	assert.Equal(t, "live until ledger 900", got)
+
+	_, err = FormatLedgerEntryBase64("not xdr")
+	assert.Error(t, err)
+}
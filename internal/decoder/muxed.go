@@ -0,0 +1,59 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// SplitMuxedAddress returns the G... account and ID behind a muxed M...
+// address. ok is false for anything else.
+func SplitMuxedAddress(addr string) (account string, id uint64, ok bool) {
+	if !strkey.IsValidMuxedAccountEd25519PublicKey(addr) {
+		return "", 0, false
+	}
+	muxed, err := xdr.AddressToMuxedAccount(addr)
+	if err != nil {
+		return "", 0, false
+	}
+	id, err = muxed.GetId()
+	if err != nil {
+		return "", 0, false
+	}
+	accountID := muxed.ToAccountId()
+	return accountID.Address(), id, true
+}
+
+// BaseAccount returns the G... account behind a muxed address, and any other
+// address unchanged
+func BaseAccount(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if account, _, ok := SplitMuxedAddress(addr); ok {
+		return account
+	}
+	return addr
+}
+
+// SameAccount reports whether two addresses belong to the same account,
+// treating a muxed address as its G... account
+func SameAccount(a, b string) bool {
+	return BaseAccount(a) == BaseAccount(b)
+}
+
+// DisplayAddress prefers a registered alias or identity name over the raw
+// address, and shows a muxed address as its G... account and ID, e.g.
+// "GAAZ...CWN7 (muxed id 42)"
+func DisplayAddress(addr string) string {
+	if name, ok := AddressName(addr); ok {
+		return name
+	}
+	if account, id, ok := SplitMuxedAddress(addr); ok {
+		return fmt.Sprintf("%s (muxed id %d)", DisplayAddress(account), id)
+	}
+	return addr
+}
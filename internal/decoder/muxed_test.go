@@ -0,0 +1,44 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func muxedAddress(t *testing.T, g string, id uint64) string {
+	t.Helper()
+	m, err := xdr.MuxedAccountFromAccountId(g, id)
+	require.NoError(t, err)
+	return m.Address()
+}
+
+func TestSplitMuxedAddress(t *testing.T) {
+	m := muxedAddress(t, sacHolder, 42)
+	account, id, ok := SplitMuxedAddress(m)
+	require.True(t, ok)
+	assert.Equal(t, sacHolder, account)
+	assert.Equal(t, uint64(42), id)
+
+	_, _, ok = SplitMuxedAddress(sacHolder)
+	assert.False(t, ok)
+	assert.Equal(t, sacHolder, BaseAccount(m))
+	assert.Equal(t, sacIssuer, BaseAccount(sacIssuer))
+	assert.True(t, SameAccount(m, sacHolder))
+	assert.False(t, SameAccount(m, sacIssuer))
+}
+
+func TestDisplayAddress_Muxed(t *testing.T) {
+	m := muxedAddress(t, sacHolder, 7)
+	assert.Equal(t, sacHolder+" (muxed id 7)", DisplayAddress(m))
+
+	SetAddressNames(map[string]string{sacHolder: "exchange"})
+	defer SetAddressNames(nil)
+	assert.Equal(t, "exchange (muxed id 7)", DisplayAddress(m))
+	assert.Equal(t, "exchange", DisplayAddress(sacHolder))
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/stellar/go-stellar-sdk/amount"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// OperationSummary describes one operation of a transaction and, once the
+// transaction has run, how it fared
+type OperationSummary struct {
+	Index       int    `json:"index"`
+	Type        string `json:"type"`
+	Source      string `json:"source,omitempty"` // only set when it differs from the transaction's source
+	Description string `json:"description"`
+	Soroban     bool   `json:"soroban,omitempty"`
+	Result      string `json:"result,omitempty"` // e.g. "payment_underfunded"
+	Explanation string `json:"explanation,omitempty"`
+	Succeeded   *bool  `json:"succeeded,omitempty"`
+}
+
+// DescribeOperations summarises every operation in an envelope. When
+// resultXdr, a base64 TransactionResult, is not empty each operation also
+// carries its result code.
+func DescribeOperations(envelopeXdr, resultXdr string) ([]OperationSummary, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+
+	ops := envelope.Operations()
+	summaries := make([]OperationSummary, len(ops))
+	for i, op := range ops {
+		summaries[i] = OperationSummary{
+			Index:       i,
+			Type:        snakeCase(strings.TrimPrefix(op.Body.Type.String(), "OperationType")),
+			Description: describeOperation(op),
+			Soroban:     isSorobanOperation(op.Body.Type),
+		}
+		if op.SourceAccount != nil {
+			summaries[i].Source = displayAccount(*op.SourceAccount)
+		}
+	}
+
+	if resultXdr == "" {
+		return summaries, nil
+	}
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXdr, &result); err != nil {
+		return summaries, fmt.Errorf("failed to decode transaction result: %w", err)
+	}
+	results, ok := result.OperationResults()
+	if !ok {
+		// Rejected before any operation ran, e.g. tx_bad_seq
+		return summaries, nil
+	}
+	for i, r := range results {
+		if i >= len(summaries) {
+			break
+		}
+		info, succeeded := DecodeOperationResult(r)
+		summaries[i].Result = info.Code
+		summaries[i].Explanation = info.Explanation
+		summaries[i].Succeeded = &succeeded
+	}
+	return summaries, nil
+}
+
+// MixesSorobanOperations reports whether a transaction combines a Soroban
+// operation with others, which the network rejects as malformed
+func MixesSorobanOperations(ops []OperationSummary) bool {
+	if len(ops) < 2 {
+		return false
+	}
+	for _, op := range ops {
+		if op.Soroban {
+			return true
+		}
+	}
+	return false
+}
+
+func isSorobanOperation(t xdr.OperationType) bool {
+	switch t {
+	case xdr.OperationTypeInvokeHostFunction, xdr.OperationTypeExtendFootprintTtl, xdr.OperationTypeRestoreFootprint:
+		return true
+	}
+	return false
+}
+
+func describeOperation(op xdr.Operation) string {
+	body := op.Body
+	switch body.Type {
+	case xdr.OperationTypeInvokeHostFunction:
+		fn := body.InvokeHostFunctionOp.HostFunction
+		switch fn.Type {
+		case xdr.HostFunctionTypeHostFunctionTypeInvokeContract:
+			contract, _ := fn.InvokeContract.ContractAddress.String()
+			return fmt.Sprintf("call %s on %s", fn.InvokeContract.FunctionName, DisplayAddress(contract))
+		case xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm:
+			return fmt.Sprintf("upload %d bytes of contract code", len(*fn.Wasm))
+		default:
+			return "create a contract"
+		}
+	case xdr.OperationTypeExtendFootprintTtl:
+		return fmt.Sprintf("extend the footprint's TTL to %d ledgers", body.ExtendFootprintTtlOp.ExtendTo)
+	case xdr.OperationTypeRestoreFootprint:
+		return "restore the archived footprint entries"
+	case xdr.OperationTypeCreateAccount:
+		o := body.CreateAccountOp
+		addr, _ := o.Destination.GetAddress()
+		return fmt.Sprintf("create %s with %s XLM", DisplayAddress(addr), amount.String(o.StartingBalance))
+	case xdr.OperationTypePayment:
+		o := body.PaymentOp
+		return fmt.Sprintf("pay %s %s to %s", amount.String(o.Amount), assetName(o.Asset), displayAccount(o.Destination))
+	case xdr.OperationTypePathPaymentStrictReceive:
+		o := body.PathPaymentStrictReceiveOp
+		return fmt.Sprintf("pay %s %s to %s, sending at most %s %s", amount.String(o.DestAmount), assetName(o.DestAsset),
+			displayAccount(o.Destination), amount.String(o.SendMax), assetName(o.SendAsset))
+	case xdr.OperationTypePathPaymentStrictSend:
+		o := body.PathPaymentStrictSendOp
+		return fmt.Sprintf("send %s %s to %s for at least %s %s", amount.String(o.SendAmount), assetName(o.SendAsset),
+			displayAccount(o.Destination), amount.String(o.DestMin), assetName(o.DestAsset))
+	case xdr.OperationTypeManageSellOffer:
+		o := body.ManageSellOfferOp
+		return fmt.Sprintf("offer %s %s for %s (offer %d)", amount.String(o.Amount), assetName(o.Selling), assetName(o.Buying), o.OfferId)
+	case xdr.OperationTypeManageBuyOffer:
+		o := body.ManageBuyOfferOp
+		return fmt.Sprintf("bid for %s %s with %s (offer %d)", amount.String(o.BuyAmount), assetName(o.Buying), assetName(o.Selling), o.OfferId)
+	case xdr.OperationTypeChangeTrust:
+		o := body.ChangeTrustOp
+		if o.Limit == 0 {
+			return "remove a trustline"
+		}
+		if o.Line.Type == xdr.AssetTypeAssetTypePoolShare {
+			return fmt.Sprintf("trust a liquidity pool share up to %s", amount.String(o.Limit))
+		}
+		return fmt.Sprintf("trust %s up to %s", assetName(o.Line.ToAsset()), amount.String(o.Limit))
+	case xdr.OperationTypeAccountMerge:
+		return fmt.Sprintf("merge into %s", displayAccount(*body.Destination))
+	case xdr.OperationTypeManageData:
+		o := body.ManageDataOp
+		if o.DataValue == nil {
+			return fmt.Sprintf("delete data entry %q", o.DataName)
+		}
+		return fmt.Sprintf("set data entry %q", o.DataName)
+	case xdr.OperationTypeBumpSequence:
+		return fmt.Sprintf("bump the sequence to %d", body.BumpSequenceOp.BumpTo)
+	case xdr.OperationTypeSetOptions:
+		return "set account options"
+	}
+	return strings.ReplaceAll(snakeCase(strings.TrimPrefix(body.Type.String(), "OperationType")), "_", " ")
+}
+
+func assetName(a xdr.Asset) string {
+	if a.Type == xdr.AssetTypeAssetTypeNative {
+		return "XLM"
+	}
+	var code, issuer string
+	if err := a.Extract(nil, &code, &issuer); err != nil {
+		return a.StringCanonical()
+	}
+	return code + ":" + DisplayAddress(issuer)
+}
+
+func displayAccount(m xdr.MuxedAccount) string {
+	return DisplayAddress(m.Address())
+}
+
+// snakeCase turns an XDR enum name such as "PathPaymentStrictSend" into
+// "path_payment_strict_send"
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
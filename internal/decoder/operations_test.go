@@ -0,0 +1,68 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeOperations(t *testing.T) {
+	dest := xdr.MustMuxedAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	other := xdr.MustMuxedAddress("GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF")
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: other,
+			Operations: []xdr.Operation{
+				{Body: xdr.OperationBody{Type: xdr.OperationTypePayment, PaymentOp: &xdr.PaymentOp{
+					Destination: dest, Asset: xdr.MustNewNativeAsset(), Amount: 10_0000000,
+				}}},
+				{SourceAccount: &dest, Body: xdr.OperationBody{Type: xdr.OperationTypeBumpSequence, BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 9}}},
+			},
+		}},
+	}
+	envelopeXdr, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+
+	result := xdr.TransactionResult{Result: xdr.TransactionResultResult{
+		Code: xdr.TransactionResultCodeTxFailed,
+		Results: &[]xdr.OperationResult{
+			{Code: xdr.OperationResultCodeOpInner, Tr: &xdr.OperationResultTr{
+				Type: xdr.OperationTypePayment, PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentUnderfunded},
+			}},
+			{Code: xdr.OperationResultCodeOpInner, Tr: &xdr.OperationResultTr{
+				Type: xdr.OperationTypeBumpSequence, BumpSeqResult: &xdr.BumpSequenceResult{Code: xdr.BumpSequenceResultCodeBumpSequenceSuccess},
+			}},
+		},
+	}}
+	resultXdr, err := xdr.MarshalBase64(result)
+	require.NoError(t, err)
+
+	ops, err := DescribeOperations(envelopeXdr, resultXdr)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+
+	assert.Equal(t, "payment", ops[0].Type)
+	assert.Equal(t, "pay 10.0000000 XLM to GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", ops[0].Description)
+	assert.Empty(t, ops[0].Source)
+	assert.Equal(t, "payment_underfunded", ops[0].Result)
+	assert.False(t, *ops[0].Succeeded)
+
+	assert.Equal(t, "bump_sequence", ops[1].Type)
+	assert.Equal(t, dest.Address(), ops[1].Source)
+	assert.Equal(t, "bump_sequence_success", ops[1].Result)
+	assert.True(t, *ops[1].Succeeded)
+	assert.False(t, MixesSorobanOperations(ops))
+
+	ops, err = DescribeOperations(envelopeXdr, "")
+	require.NoError(t, err)
+	assert.Nil(t, ops[0].Succeeded, "no results before the transaction has run")
+
+	ops = append(ops, OperationSummary{Type: "invoke_host_function", Soroban: true})
+	assert.True(t, MixesSorobanOperations(ops))
+}
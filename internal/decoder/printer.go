@@ -53,6 +53,9 @@ func printOperation(i int, op xdr.Operation) {
 }
 
 func maskAccount(addr string) string {
+	if account, id, ok := SplitMuxedAddress(addr); ok {
+		return fmt.Sprintf("%s (muxed id %d)", maskAccount(account), id)
+	}
 	if len(addr) < 8 {
 		return addr
 	}
@@ -5,6 +5,7 @@ package decoder
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
@@ -330,9 +331,9 @@ func FormatTransactionResult(result xdr.TransactionResult) string {
 		if results := result.Result.Results; results != nil && len(*results) > 0 {
 			output += "\nOperation Results:\n"
 			for i, opResult := range *results {
-				opCodeInfo := DecodeOperationResultCode(opResult.Code)
+				opCodeInfo, _ := DecodeOperationResult(opResult)
 				output += fmt.Sprintf("  Operation %d: %s (%s)\n", i, opCodeInfo.Description, opCodeInfo.Code)
-				if opCodeInfo.Code != "op_inner" {
+				if opCodeInfo.Explanation != "" {
 					output += fmt.Sprintf("    %s\n", opCodeInfo.Explanation)
 				}
 			}
@@ -341,3 +342,234 @@ func FormatTransactionResult(result xdr.TransactionResult) string {
 
 	return output
 }
+
+// pathPaymentResultCodes are the results shared by both path payment
+// operations, keyed by code. Code names get the operation's prefix.
+var pathPaymentResultCodes = map[int32]OperationResultCodeInfo{
+	0:   {Code: "success", Description: "Path Payment Successful", Explanation: "Payment was successfully completed along the path"},
+	-1:  {Code: "malformed", Description: "Malformed Request", Explanation: "Invalid destination, asset, amount or path"},
+	-2:  {Code: "underfunded", Description: "Insufficient Funds", Explanation: "Source account doesn't have enough of the send asset"},
+	-3:  {Code: "src_no_trust", Description: "Source No Trustline", Explanation: "Source account doesn't have a trustline for the send asset"},
+	-4:  {Code: "src_not_authorized", Description: "Source Not Authorized", Explanation: "Source account is not authorized to send the send asset"},
+	-5:  {Code: "no_destination", Description: "Destination Not Found", Explanation: "Destination account does not exist"},
+	-6:  {Code: "no_trust", Description: "No Trustline", Explanation: "Destination account doesn't have a trustline for the destination asset"},
+	-7:  {Code: "not_authorized", Description: "Not Authorized", Explanation: "Destination account is not authorized to receive the destination asset"},
+	-8:  {Code: "line_full", Description: "Trustline Full", Explanation: "Destination trustline limit would be exceeded"},
+	-9:  {Code: "no_issuer", Description: "Issuer Not Found", Explanation: "An asset on the path has no issuer account"},
+	-10: {Code: "too_few_offers", Description: "Too Few Offers", Explanation: "No order book or liquidity pool along the path can fill the payment"},
+	-11: {Code: "offer_cross_self", Description: "Offer Crosses Self", Explanation: "The payment would cross one of the source account's own offers"},
+}
+
+func decodePathPaymentResultCode(prefix string, code int32, last OperationResultCodeInfo) OperationResultCodeInfo {
+	info, ok := pathPaymentResultCodes[code]
+	if code == -12 {
+		info, ok = last, true
+	}
+	if !ok {
+		return OperationResultCodeInfo{
+			Code:        fmt.Sprintf("%s_unknown_%d", prefix, code),
+			Description: "Unknown Error",
+			Explanation: fmt.Sprintf("Unrecognized path payment result code: %d", code),
+		}
+	}
+	info.Code = prefix + "_" + info.Code
+	return info
+}
+
+// DecodePathPaymentStrictReceiveResultCode decodes PathPaymentStrictReceive operation specific codes
+func DecodePathPaymentStrictReceiveResultCode(code xdr.PathPaymentStrictReceiveResultCode) OperationResultCodeInfo {
+	return decodePathPaymentResultCode("path_payment_strict_receive", int32(code), OperationResultCodeInfo{
+		Code:        "over_sendmax",
+		Description: "Over Send Maximum",
+		Explanation: "Delivering the destination amount would cost more than the send maximum",
+	})
+}
+
+// DecodePathPaymentStrictSendResultCode decodes PathPaymentStrictSend operation specific codes
+func DecodePathPaymentStrictSendResultCode(code xdr.PathPaymentStrictSendResultCode) OperationResultCodeInfo {
+	return decodePathPaymentResultCode("path_payment_strict_send", int32(code), OperationResultCodeInfo{
+		Code:        "under_destmin",
+		Description: "Under Destination Minimum",
+		Explanation: "The send amount would deliver less than the destination minimum",
+	})
+}
+
+// DecodeSetOptionsResultCode decodes SetOptions operation specific codes
+func DecodeSetOptionsResultCode(code xdr.SetOptionsResultCode) OperationResultCodeInfo {
+	switch code {
+	case xdr.SetOptionsResultCodeSetOptionsSuccess:
+		return OperationResultCodeInfo{
+			Code:        "set_options_success",
+			Description: "Options Set",
+			Explanation: "Account options were successfully updated",
+		}
+	case xdr.SetOptionsResultCodeSetOptionsLowReserve:
+		return OperationResultCodeInfo{
+			Code:        "set_options_low_reserve",
+			Description: "Low Reserve",
+			Explanation: "Adding a signer would take the account below its minimum balance",
+		}
+	case xdr.SetOptionsResultCodeSetOptionsTooManySigners:
+		return OperationResultCodeInfo{
+			Code:        "set_options_too_many_signers",
+			Description: "Too Many Signers",
+			Explanation: "The account already has the maximum of 20 signers",
+		}
+	case xdr.SetOptionsResultCodeSetOptionsBadFlags:
+		return OperationResultCodeInfo{
+			Code:        "set_options_bad_flags",
+			Description: "Bad Flags",
+			Explanation: "The same flag is both set and cleared",
+		}
+	case xdr.SetOptionsResultCodeSetOptionsInvalidInflation:
+		return OperationResultCodeInfo{
+			Code:        "set_options_invalid_inflation",
+			Description: "Invalid Inflation Destination",
+			Explanation: "The inflation destination account does not exist",
+		}
+	case xdr.SetOptionsResultCodeSetOptionsCantChange:
+		return OperationResultCodeInfo{
+			Code:        "set_options_cant_change",
+			Description: "Cannot Change Flags",
+			Explanation: "AUTH_IMMUTABLE is set, so authorization flags can no longer change",
+		}
+	case xdr.SetOptionsResultCodeSetOptionsUnknownFlag:
+		return OperationResultCodeInfo{
+			Code:        "set_options_unknown_flag",
+			Description: "Unknown Flag",
+			Explanation: "A flag that does not exist was set or cleared",
+		}
+	case xdr.SetOptionsResultCodeSetOptionsThresholdOutOfRange:
+		return OperationResultCodeInfo{
+			Code:        "set_options_threshold_out_of_range",
+			Description: "Threshold Out Of Range",
+			Explanation: "A threshold or signer weight is greater than 255",
+		}
+	case xdr.SetOptionsResultCodeSetOptionsBadSigner:
+		return OperationResultCodeInfo{
+			Code:        "set_options_bad_signer",
+			Description: "Bad Signer",
+			Explanation: "The account's own master key cannot be added as a signer",
+		}
+	case xdr.SetOptionsResultCodeSetOptionsInvalidHomeDomain:
+		return OperationResultCodeInfo{
+			Code:        "set_options_invalid_home_domain",
+			Description: "Invalid Home Domain",
+			Explanation: "The home domain is malformed",
+		}
+	case xdr.SetOptionsResultCodeSetOptionsAuthRevocableRequired:
+		return OperationResultCodeInfo{
+			Code:        "set_options_auth_revocable_required",
+			Description: "Auth Revocable Required",
+			Explanation: "AUTH_CLAWBACK_ENABLED requires AUTH_REVOCABLE to be set as well",
+		}
+	default:
+		return OperationResultCodeInfo{
+			Code:        fmt.Sprintf("set_options_unknown_%d", code),
+			Description: "Unknown Error",
+			Explanation: fmt.Sprintf("Unrecognized set options result code: %d", code),
+		}
+	}
+}
+
+// DecodeChangeTrustResultCode decodes ChangeTrust operation specific codes
+func DecodeChangeTrustResultCode(code xdr.ChangeTrustResultCode) OperationResultCodeInfo {
+	switch code {
+	case xdr.ChangeTrustResultCodeChangeTrustSuccess:
+		return OperationResultCodeInfo{
+			Code:        "change_trust_success",
+			Description: "Trustline Changed",
+			Explanation: "Trustline was successfully created, updated or removed",
+		}
+	case xdr.ChangeTrustResultCodeChangeTrustMalformed:
+		return OperationResultCodeInfo{
+			Code:        "change_trust_malformed",
+			Description: "Malformed Request",
+			Explanation: "Invalid asset or negative limit",
+		}
+	case xdr.ChangeTrustResultCodeChangeTrustNoIssuer:
+		return OperationResultCodeInfo{
+			Code:        "change_trust_no_issuer",
+			Description: "Issuer Not Found",
+			Explanation: "Asset issuer account does not exist",
+		}
+	case xdr.ChangeTrustResultCodeChangeTrustInvalidLimit:
+		return OperationResultCodeInfo{
+			Code:        "change_trust_invalid_limit",
+			Description: "Invalid Limit",
+			Explanation: "The limit is below the current balance plus buying liabilities",
+		}
+	case xdr.ChangeTrustResultCodeChangeTrustLowReserve:
+		return OperationResultCodeInfo{
+			Code:        "change_trust_low_reserve",
+			Description: "Low Reserve",
+			Explanation: "A new trustline would take the account below its minimum balance",
+		}
+	case xdr.ChangeTrustResultCodeChangeTrustSelfNotAllowed:
+		return OperationResultCodeInfo{
+			Code:        "change_trust_self_not_allowed",
+			Description: "Self Trust Not Allowed",
+			Explanation: "An issuer cannot hold a trustline to its own asset",
+		}
+	case xdr.ChangeTrustResultCodeChangeTrustTrustLineMissing:
+		return OperationResultCodeInfo{
+			Code:        "change_trust_trust_line_missing",
+			Description: "Trustline Missing",
+			Explanation: "A liquidity pool share trustline needs trustlines to both of the pool's assets",
+		}
+	case xdr.ChangeTrustResultCodeChangeTrustCannotDelete:
+		return OperationResultCodeInfo{
+			Code:        "change_trust_cannot_delete",
+			Description: "Cannot Delete",
+			Explanation: "The trustline is still in use by a liquidity pool share or has a balance",
+		}
+	case xdr.ChangeTrustResultCodeChangeTrustNotAuthMaintainLiabilities:
+		return OperationResultCodeInfo{
+			Code:        "change_trust_not_auth_maintain_liabilities",
+			Description: "Not Authorized",
+			Explanation: "A pool share trustline needs authorization on both asset trustlines",
+		}
+	default:
+		return OperationResultCodeInfo{
+			Code:        fmt.Sprintf("change_trust_unknown_%d", code),
+			Description: "Unknown Error",
+			Explanation: fmt.Sprintf("Unrecognized change trust result code: %d", code),
+		}
+	}
+}
+
+// DecodeOperationResult decodes an operation's result, including the
+// operation specific code when the operation ran, and reports whether it
+// succeeded
+func DecodeOperationResult(r xdr.OperationResult) (OperationResultCodeInfo, bool) {
+	if r.Code != xdr.OperationResultCodeOpInner || r.Tr == nil {
+		return DecodeOperationResultCode(r.Code), false
+	}
+	tr := r.Tr
+	switch tr.Type {
+	case xdr.OperationTypeCreateAccount:
+		return DecodeCreateAccountResultCode(tr.CreateAccountResult.Code), tr.CreateAccountResult.Code == 0
+	case xdr.OperationTypePayment:
+		return DecodePaymentResultCode(tr.PaymentResult.Code), tr.PaymentResult.Code == 0
+	case xdr.OperationTypePathPaymentStrictReceive:
+		return DecodePathPaymentStrictReceiveResultCode(tr.PathPaymentStrictReceiveResult.Code), tr.PathPaymentStrictReceiveResult.Code == 0
+	case xdr.OperationTypePathPaymentStrictSend:
+		return DecodePathPaymentStrictSendResultCode(tr.PathPaymentStrictSendResult.Code), tr.PathPaymentStrictSendResult.Code == 0
+	case xdr.OperationTypeSetOptions:
+		return DecodeSetOptionsResultCode(tr.SetOptionsResult.Code), tr.SetOptionsResult.Code == 0
+	case xdr.OperationTypeChangeTrust:
+		return DecodeChangeTrustResultCode(tr.ChangeTrustResult.Code), tr.ChangeTrustResult.Code == 0
+	}
+
+	// Other operations are named after their XDR code, e.g.
+	// InvokeHostFunctionResultCodeInvokeHostFunctionTrapped
+	name, err := tr.MapOperationResultTr()
+	if err != nil {
+		return OperationResultCodeInfo{Code: "unknown", Description: "Unknown Error", Explanation: err.Error()}, false
+	}
+	if i := strings.Index(name, "ResultCode"); i >= 0 {
+		name = name[i+len("ResultCode"):]
+	}
+	code := snakeCase(name)
+	return OperationResultCodeInfo{Code: code}, strings.HasSuffix(code, "_success")
+}
@@ -232,3 +232,62 @@ func TestFormatTransactionResult(t *testing.T) {
 		t.Errorf("Expected 'tx_success' in output, got: %s", output)
 	}
 }
+
+func TestDecodeClassicOperationResultCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      OperationResultCodeInfo
+		wantCode string
+	}{
+		{"strict receive underfunded", DecodePathPaymentStrictReceiveResultCode(xdr.PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveUnderfunded), "path_payment_strict_receive_underfunded"},
+		{"strict receive over sendmax", DecodePathPaymentStrictReceiveResultCode(xdr.PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveOverSendmax), "path_payment_strict_receive_over_sendmax"},
+		{"strict send under destmin", DecodePathPaymentStrictSendResultCode(xdr.PathPaymentStrictSendResultCodePathPaymentStrictSendUnderDestmin), "path_payment_strict_send_under_destmin"},
+		{"strict send no trust", DecodePathPaymentStrictSendResultCode(xdr.PathPaymentStrictSendResultCodePathPaymentStrictSendNoTrust), "path_payment_strict_send_no_trust"},
+		{"strict send unknown", DecodePathPaymentStrictSendResultCode(-99), "path_payment_strict_send_unknown_-99"},
+		{"set options bad signer", DecodeSetOptionsResultCode(xdr.SetOptionsResultCodeSetOptionsBadSigner), "set_options_bad_signer"},
+		{"change trust low reserve", DecodeChangeTrustResultCode(xdr.ChangeTrustResultCodeChangeTrustLowReserve), "change_trust_low_reserve"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.Code != tt.wantCode {
+				t.Errorf("Code = %v, want %v", tt.got.Code, tt.wantCode)
+			}
+			if tt.got.Explanation == "" {
+				t.Errorf("Explanation is empty")
+			}
+		})
+	}
+}
+
+func TestDecodeOperationResult(t *testing.T) {
+	underfunded := xdr.OperationResult{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type: xdr.OperationTypePathPaymentStrictSend,
+			PathPaymentStrictSendResult: &xdr.PathPaymentStrictSendResult{
+				Code: xdr.PathPaymentStrictSendResultCodePathPaymentStrictSendUnderfunded,
+			},
+		},
+	}
+	info, succeeded := DecodeOperationResult(underfunded)
+	if succeeded || info.Code != "path_payment_strict_send_underfunded" {
+		t.Errorf("got %q (succeeded=%v), want path_payment_strict_send_underfunded", info.Code, succeeded)
+	}
+
+	info, succeeded = DecodeOperationResult(xdr.OperationResult{Code: xdr.OperationResultCodeOpNoAccount})
+	if succeeded || info.Code != "op_no_account" {
+		t.Errorf("got %q (succeeded=%v), want op_no_account", info.Code, succeeded)
+	}
+
+	failed := xdr.TransactionResult{
+		Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxFailed,
+			Results: &[]xdr.OperationResult{underfunded},
+		},
+	}
+	output := FormatTransactionResult(failed)
+	if !strings.Contains(output, "path_payment_strict_send_underfunded") {
+		t.Errorf("Expected the inner operation code in output, got: %s", output)
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// StellarAssetDecimals is the fixed precision of every Stellar Asset
+// Contract amount
+const StellarAssetDecimals = 7
+
+// SACAction is a Stellar Asset Contract call or event in token terms
+type SACAction struct {
+	Action  string `json:"action"` // e.g. "transfer", "mint", "burn", "clawback"
+	Asset   string `json:"asset"`  // "native" or "CODE:ISSUER"
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Spender string `json:"spender,omitempty"`
+	Amount  string `json:"amount,omitempty"` // in whole units, e.g. "10.5"
+	// Authorized is set by set_authorized
+	Authorized *bool `json:"authorized,omitempty"`
+	// ToMuxedID is the muxed ID of a transfer or mint to a muxed account;
+	// To is then the account's G... address
+	ToMuxedID *uint64 `json:"to_muxed_id,omitempty"`
+}
+
+// Destination returns To, as a muxed M... address when the action carries a
+// muxed ID
+func (a SACAction) Destination() string {
+	if a.ToMuxedID != nil {
+		if m, err := xdr.MuxedAccountFromAccountId(a.To, *a.ToMuxedID); err == nil {
+			if addr, err := m.GetAddress(); err == nil {
+				return addr
+			}
+		}
+	}
+	return a.To
+}
+
+// String renders the action, e.g. "transfer 10.5 USDC from G... to C..."
+func (a SACAction) String() string {
+	amount := strings.TrimSpace(a.Amount + " " + AssetCode(a.Asset))
+	switch a.Action {
+	case "transfer":
+		return fmt.Sprintf("transfer %s from %s to %s", amount, DisplayAddress(a.From), DisplayAddress(a.Destination()))
+	case "mint":
+		return fmt.Sprintf("mint %s to %s", amount, DisplayAddress(a.Destination()))
+	case "burn":
+		return fmt.Sprintf("burn %s from %s", amount, DisplayAddress(a.From))
+	case "clawback":
+		return fmt.Sprintf("claw back %s from %s", amount, DisplayAddress(a.From))
+	case "approve":
+		return fmt.Sprintf("approve %s to spend %s of %s", DisplayAddress(a.Spender), amount, DisplayAddress(a.From))
+	case "set_admin":
+		return fmt.Sprintf("set the %s admin to %s", AssetCode(a.Asset), DisplayAddress(a.To))
+	case "set_authorized":
+		verb := "deauthorize"
+		if a.Authorized != nil && *a.Authorized {
+			verb = "authorize"
+		}
+		return fmt.Sprintf("%s %s to hold %s", verb, DisplayAddress(a.To), AssetCode(a.Asset))
+	}
+	return a.Action + " " + AssetCode(a.Asset)
+}
+
+// AssetCode returns the code of a Stellar Asset Contract asset name,
+// "XLM" for the native asset
+func AssetCode(asset string) string {
+	if asset == "native" {
+		return "XLM"
+	}
+	code, _, _ := strings.Cut(asset, ":")
+	return code
+}
+
+// ParseAssetName parses the asset name a Stellar Asset Contract reports,
+// "native" or "CODE:ISSUER"
+func ParseAssetName(name string) (xdr.Asset, bool) {
+	if name == "native" {
+		return xdr.MustNewNativeAsset(), true
+	}
+	code, issuer, ok := strings.Cut(name, ":")
+	if !ok || code == "" || len(code) > 12 || !strkey.IsValidEd25519PublicKey(issuer) {
+		return xdr.Asset{}, false
+	}
+	asset, err := xdr.NewCreditAsset(code, issuer)
+	if err != nil {
+		return xdr.Asset{}, false
+	}
+	return asset, true
+}
+
+// IsStellarAssetContract reports whether contractID is the Stellar Asset
+// Contract deployed for asset on the network with passphrase
+func IsStellarAssetContract(contractID, asset, passphrase string) bool {
+	a, ok := ParseAssetName(asset)
+	if !ok {
+		return false
+	}
+	id, err := a.ContractID(passphrase)
+	if err != nil {
+		return false
+	}
+	encoded, err := strkey.Encode(strkey.VersionByteContract, id[:])
+	return err == nil && encoded == contractID
+}
+
+// DecodeSACEvent decodes an event in the Stellar Asset Contract format,
+// whose last topic is the asset name. When passphrase is set the event
+// must also come from that asset's contract, so tokens that merely copy the
+// format are not mistaken for it.
+func DecodeSACEvent(contractID string, topics []xdr.ScVal, data xdr.ScVal, passphrase string) (*SACAction, bool) {
+	if len(topics) < 2 {
+		return nil, false
+	}
+	action, ok := scSymbol(topics[0])
+	if !ok {
+		return nil, false
+	}
+	last := topics[len(topics)-1]
+	if last.Type != xdr.ScValTypeScvString || last.Str == nil {
+		return nil, false
+	}
+	asset := string(*last.Str)
+	if _, ok := ParseAssetName(asset); !ok {
+		return nil, false
+	}
+	if passphrase != "" && !IsStellarAssetContract(contractID, asset, passphrase) {
+		return nil, false
+	}
+
+	// Addresses between the action and the asset; protocol 23 dropped the
+	// admin from mint and clawback events
+	var addrs []string
+	for _, t := range topics[1 : len(topics)-1] {
+		addr, ok := scAddress(t)
+		if !ok {
+			return nil, false
+		}
+		addrs = append(addrs, addr)
+	}
+	at := func(i int) string {
+		if i < 0 || i >= len(addrs) {
+			return ""
+		}
+		return addrs[i]
+	}
+
+	a := &SACAction{Action: action, Asset: asset}
+	switch action {
+	case "transfer":
+		a.From, a.To = at(0), at(1)
+		a.Amount, ok = sacAmount(data)
+		a.ToMuxedID = sacMuxedID(data)
+	case "mint":
+		a.To = at(len(addrs) - 1)
+		a.Amount, ok = sacAmount(data)
+		a.ToMuxedID = sacMuxedID(data)
+	case "burn":
+		a.From = at(0)
+		a.Amount, ok = sacAmount(data)
+	case "clawback":
+		a.From = at(len(addrs) - 1)
+		a.Amount, ok = sacAmount(data)
+	case "approve":
+		a.From, a.Spender = at(0), at(1)
+		if data.Type == xdr.ScValTypeScvVec && data.Vec != nil && *data.Vec != nil && len(**data.Vec) > 0 {
+			a.Amount, ok = sacAmount((**data.Vec)[0])
+		}
+	case "set_admin":
+		a.To, ok = scAddress(data)
+	case "set_authorized":
+		a.To = at(len(addrs) - 1)
+		if data.Type == xdr.ScValTypeScvBool && data.B != nil {
+			authorized := bool(*data.B)
+			a.Authorized = &authorized
+		}
+	default:
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	return a, true
+}
+
+// DecodeSACCall decodes a call to the Stellar Asset Contract of asset
+func DecodeSACCall(asset, function string, args []xdr.ScVal) (*SACAction, bool) {
+	address := func(i int) string {
+		if i >= len(args) {
+			return ""
+		}
+		addr, _ := scAddress(args[i])
+		return addr
+	}
+	amount := func(i int) string {
+		if i >= len(args) {
+			return ""
+		}
+		s, _ := sacAmount(args[i])
+		return s
+	}
+
+	a := &SACAction{Asset: asset}
+	switch function {
+	case "transfer":
+		a.Action, a.From, a.To, a.Amount = "transfer", address(0), address(1), amount(2)
+	case "transfer_from":
+		a.Action, a.Spender, a.From, a.To, a.Amount = "transfer", address(0), address(1), address(2), amount(3)
+	case "mint":
+		a.Action, a.To, a.Amount = "mint", address(0), amount(1)
+	case "burn":
+		a.Action, a.From, a.Amount = "burn", address(0), amount(1)
+	case "burn_from":
+		a.Action, a.Spender, a.From, a.Amount = "burn", address(0), address(1), amount(2)
+	case "clawback":
+		a.Action, a.From, a.Amount = "clawback", address(0), amount(1)
+	case "approve":
+		a.Action, a.From, a.Spender, a.Amount = "approve", address(0), address(1), amount(2)
+	case "set_admin":
+		a.Action, a.To = "set_admin", address(0)
+	case "set_authorized":
+		a.Action, a.To = "set_authorized", address(0)
+		if len(args) > 1 && args[1].Type == xdr.ScValTypeScvBool && args[1].B != nil {
+			authorized := bool(*args[1].B)
+			a.Authorized = &authorized
+		}
+	default:
+		return nil, false
+	}
+	return a, true
+}
+
+// FormatTokenAmount renders an integer amount of the smallest unit with the
+// given number of decimals, trimming trailing zeros: 105000000 with 7
+// decimals is "10.5"
+func FormatTokenAmount(n *big.Int, decimals int) string {
+	if n == nil {
+		return "0"
+	}
+	neg := n.Sign() < 0
+	abs := new(big.Int).Abs(n)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, frac := new(big.Int).DivMod(abs, scale, new(big.Int))
+
+	out := whole.String()
+	if decimals > 0 {
+		if f := strings.TrimRight(fmt.Sprintf("%0*s", decimals, frac.String()), "0"); f != "" {
+			out += "." + f
+		}
+	}
+	if neg {
+		out = "-" + out
+	}
+	return out
+}
+
+// sacAmount formats an i128 amount, also accepting the {amount: ...} map
+// transfer events carry when the destination is muxed
+func sacAmount(v xdr.ScVal) (string, bool) {
+	if v.Type == xdr.ScValTypeScvMap && v.Map != nil && *v.Map != nil {
+		for _, e := range **v.Map {
+			if sym, ok := scSymbol(e.Key); ok && sym == "amount" {
+				return sacAmount(e.Val)
+			}
+		}
+		return "", false
+	}
+	if v.Type != xdr.ScValTypeScvI128 || v.I128 == nil {
+		return "", false
+	}
+	n := new(big.Int).SetInt64(int64(v.I128.Hi))
+	n.Lsh(n, 64)
+	n.Or(n, new(big.Int).SetUint64(uint64(v.I128.Lo)))
+	return FormatTokenAmount(n, StellarAssetDecimals), true
+}
+
+// sacMuxedID returns the to_muxed_id of an {amount, to_muxed_id} map. Only
+// u64 IDs name a muxed account; string and bytes IDs come from memos.
+func sacMuxedID(v xdr.ScVal) *uint64 {
+	if v.Type != xdr.ScValTypeScvMap || v.Map == nil || *v.Map == nil {
+		return nil
+	}
+	for _, e := range **v.Map {
+		if sym, ok := scSymbol(e.Key); ok && sym == "to_muxed_id" && e.Val.Type == xdr.ScValTypeScvU64 && e.Val.U64 != nil {
+			id := uint64(*e.Val.U64)
+			return &id
+		}
+	}
+	return nil
+}
+
+func scSymbol(v xdr.ScVal) (string, bool) {
+	if v.Type != xdr.ScValTypeScvSymbol || v.Sym == nil {
+		return "", false
+	}
+	return string(*v.Sym), true
+}
+
+func scAddress(v xdr.ScVal) (string, bool) {
+	if v.Type != xdr.ScValTypeScvAddress || v.Address == nil {
+		return "", false
+	}
+	s, err := v.Address.String()
+	return s, err == nil
+}
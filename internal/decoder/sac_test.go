@@ -0,0 +1,120 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sacIssuer     = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	sacHolder     = "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"
+	sacPassphrase = "Test SDF Network ; September 2015"
+)
+
+func sacContractID(t *testing.T, asset string) string {
+	t.Helper()
+	a, ok := ParseAssetName(asset)
+	require.True(t, ok)
+	id, err := a.ContractID(sacPassphrase)
+	require.NoError(t, err)
+	encoded, err := strkey.Encode(strkey.VersionByteContract, id[:])
+	require.NoError(t, err)
+	return encoded
+}
+
+func symVal(s string) xdr.ScVal {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+}
+
+func strVal(s string) xdr.ScVal {
+	str := xdr.ScString(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}
+}
+
+func addrVal(t *testing.T, g string) xdr.ScVal {
+	t.Helper()
+	account, err := xdr.AddressToAccountId(g)
+	require.NoError(t, err)
+	addr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &account}
+	return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &addr}
+}
+
+func i128Val(n int64) xdr.ScVal {
+	hi := xdr.Int64(0)
+	if n < 0 {
+		hi = -1
+	}
+	return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: hi, Lo: xdr.Uint64(n)}}
+}
+
+func TestDecodeSACEvent(t *testing.T) {
+	asset := "USDC:" + sacIssuer
+	contract := sacContractID(t, asset)
+
+	transfer, ok := DecodeSACEvent(contract,
+		[]xdr.ScVal{symVal("transfer"), addrVal(t, sacHolder), addrVal(t, sacIssuer), strVal(asset)},
+		i128Val(105_000_000), sacPassphrase)
+	require.True(t, ok)
+	assert.Equal(t, "transfer", transfer.Action)
+	assert.Equal(t, "10.5", transfer.Amount)
+	assert.Equal(t, "transfer 10.5 USDC from "+sacHolder+" to "+sacIssuer, transfer.String())
+
+	// Transfers to a muxed account carry its ID in an {amount, to_muxed_id} map
+	id := xdr.Uint64(9)
+	muxedData := xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: func() **xdr.ScMap {
+		m := &xdr.ScMap{
+			{Key: symVal("amount"), Val: i128Val(10_000_000)},
+			{Key: symVal("to_muxed_id"), Val: xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &id}},
+		}
+		return &m
+	}()}
+	muxed, ok := DecodeSACEvent(contract,
+		[]xdr.ScVal{symVal("transfer"), addrVal(t, sacHolder), addrVal(t, sacIssuer), strVal(asset)}, muxedData, sacPassphrase)
+	require.True(t, ok)
+	require.NotNil(t, muxed.ToMuxedID)
+	assert.Equal(t, uint64(9), *muxed.ToMuxedID)
+	assert.Equal(t, muxedAddress(t, sacIssuer, 9), muxed.Destination())
+	assert.Equal(t, "transfer 1 USDC from "+sacHolder+" to "+sacIssuer+" (muxed id 9)", muxed.String())
+
+	// Protocol 23 mint events have no admin topic
+	mint, ok := DecodeSACEvent(contract, []xdr.ScVal{symVal("mint"), addrVal(t, sacHolder), strVal(asset)}, i128Val(1), sacPassphrase)
+	require.True(t, ok)
+	assert.Equal(t, "mint 0.0000001 USDC to "+sacHolder, mint.String())
+
+	burn, ok := DecodeSACEvent(sacContractID(t, "native"), []xdr.ScVal{symVal("burn"), addrVal(t, sacHolder), strVal("native")}, i128Val(20_000_000), sacPassphrase)
+	require.True(t, ok)
+	assert.Equal(t, "burn 2 XLM from "+sacHolder, burn.String())
+
+	_, ok = DecodeSACEvent("CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC",
+		[]xdr.ScVal{symVal("transfer"), addrVal(t, sacHolder), addrVal(t, sacIssuer), strVal(asset)}, i128Val(1), sacPassphrase)
+	assert.False(t, ok, "a custom token copying the event format is not a SAC")
+
+	_, ok = DecodeSACEvent(contract, []xdr.ScVal{symVal("transfer"), addrVal(t, sacHolder), addrVal(t, sacIssuer)}, i128Val(1), "")
+	assert.False(t, ok, "events without an asset topic are not SAC events")
+}
+
+func TestDecodeSACCall(t *testing.T) {
+	call, ok := DecodeSACCall("native", "transfer", []xdr.ScVal{addrVal(t, sacHolder), addrVal(t, sacIssuer), i128Val(-5)})
+	require.True(t, ok)
+	assert.Equal(t, "-0.0000005", call.Amount)
+	assert.Equal(t, "transfer -0.0000005 XLM from "+sacHolder+" to "+sacIssuer, call.String())
+
+	_, ok = DecodeSACCall("native", "balance", []xdr.ScVal{addrVal(t, sacHolder)})
+	assert.False(t, ok)
+}
+
+func TestFormatTokenAmount(t *testing.T) {
+	assert.Equal(t, "0", FormatTokenAmount(big.NewInt(0), 7))
+	assert.Equal(t, "12345", FormatTokenAmount(big.NewInt(123_450_000_000), 7))
+	assert.Equal(t, "-1.25", FormatTokenAmount(big.NewInt(-12_500_000), 7))
+	assert.Equal(t, "42", FormatTokenAmount(big.NewInt(42), 0))
+}
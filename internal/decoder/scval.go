@@ -0,0 +1,316 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// FormatScVal renders an SCVal in a compact, human-readable form: symbols
+// bare, strings quoted, addresses as strkeys or their registered names, bytes
+// as hex, big integers in decimal, and vecs/maps as [a, b] / {k: v}.
+func FormatScVal(v xdr.ScVal) string {
+	var b strings.Builder
+	writeScVal(&b, v)
+	return b.String()
+}
+
+var (
+	addressNamesMu   sync.RWMutex
+	addressNames     map[string]string
+	addressNamesLoad func() map[string]string
+)
+
+// SetAddressNames registers friendly names, keyed by strkey address, that
+// FormatScVal shows in place of those addresses, e.g. stellar CLI identities.
+// Passing nil clears them.
+func SetAddressNames(names map[string]string) {
+	addressNamesMu.Lock()
+	defer addressNamesMu.Unlock()
+	addressNames = names
+	addressNamesLoad = nil
+}
+
+// SetAddressNameLoader registers a function that returns the names for
+// SetAddressNames. It is called once, the first time a name is looked up, so
+// commands that never show an address don't pay for reading them.
+func SetAddressNameLoader(load func() map[string]string) {
+	addressNamesMu.Lock()
+	defer addressNamesMu.Unlock()
+	addressNames = nil
+	addressNamesLoad = load
+}
+
+// AddressName returns the name registered for addr with SetAddressNames or
+// SetAddressNameLoader
+func AddressName(addr string) (string, bool) {
+	addressNamesMu.RLock()
+	if addressNamesLoad == nil {
+		defer addressNamesMu.RUnlock()
+		name, ok := addressNames[addr]
+		return name, ok
+	}
+	addressNamesMu.RUnlock()
+
+	addressNamesMu.Lock()
+	defer addressNamesMu.Unlock()
+	if addressNamesLoad != nil {
+		addressNames = addressNamesLoad()
+		addressNamesLoad = nil
+	}
+	name, ok := addressNames[addr]
+	return name, ok
+}
+
+// FormatScValBase64 decodes a base64 XDR SCVal and renders it with FormatScVal
+func FormatScValBase64(s string) (string, error) {
+	var v xdr.ScVal
+	if err := xdr.SafeUnmarshalBase64(s, &v); err != nil {
+		return "", fmt.Errorf("failed to decode ScVal: %w", err)
+	}
+	return FormatScVal(v), nil
+}
+
+func writeScVal(b *strings.Builder, v xdr.ScVal) {
+	switch v.Type {
+	case xdr.ScValTypeScvBool:
+		if v.B != nil {
+			b.WriteString(strconv.FormatBool(*v.B))
+			return
+		}
+	case xdr.ScValTypeScvVoid:
+		b.WriteString("()")
+		return
+	case xdr.ScValTypeScvU32:
+		if v.U32 != nil {
+			b.WriteString(strconv.FormatUint(uint64(*v.U32), 10))
+			return
+		}
+	case xdr.ScValTypeScvI32:
+		if v.I32 != nil {
+			b.WriteString(strconv.FormatInt(int64(*v.I32), 10))
+			return
+		}
+	case xdr.ScValTypeScvU64:
+		if v.U64 != nil {
+			b.WriteString(strconv.FormatUint(uint64(*v.U64), 10))
+			return
+		}
+	case xdr.ScValTypeScvI64:
+		if v.I64 != nil {
+			b.WriteString(strconv.FormatInt(int64(*v.I64), 10))
+			return
+		}
+	case xdr.ScValTypeScvU128:
+		if v.U128 != nil {
+			b.WriteString(v.String())
+			return
+		}
+	case xdr.ScValTypeScvI128:
+		if v.I128 != nil {
+			b.WriteString(v.String())
+			return
+		}
+	case xdr.ScValTypeScvU256:
+		if v.U256 != nil {
+			b.WriteString(v.String())
+			return
+		}
+	case xdr.ScValTypeScvI256:
+		if v.I256 != nil {
+			b.WriteString(v.String())
+			return
+		}
+	case xdr.ScValTypeScvTimepoint:
+		if v.Timepoint != nil {
+			b.WriteString(time.Unix(int64(*v.Timepoint), 0).UTC().Format(time.RFC3339))
+			return
+		}
+	case xdr.ScValTypeScvDuration:
+		if v.Duration != nil {
+			b.WriteString(strconv.FormatUint(uint64(*v.Duration), 10))
+			b.WriteString("s")
+			return
+		}
+	case xdr.ScValTypeScvBytes:
+		if v.Bytes != nil {
+			b.WriteString("0x")
+			b.WriteString(hex.EncodeToString(*v.Bytes))
+			return
+		}
+	case xdr.ScValTypeScvString:
+		if v.Str != nil {
+			b.WriteString(strconv.Quote(string(*v.Str)))
+			return
+		}
+	case xdr.ScValTypeScvSymbol:
+		if v.Sym != nil {
+			b.WriteString(string(*v.Sym))
+			return
+		}
+	case xdr.ScValTypeScvAddress:
+		if v.Address != nil {
+			if s, err := v.Address.String(); err == nil {
+				b.WriteString(DisplayAddress(s))
+				return
+			}
+		}
+	case xdr.ScValTypeScvVec:
+		if v.Vec != nil && *v.Vec != nil {
+			b.WriteString("[")
+			for i, item := range **v.Vec {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				writeScVal(b, item)
+			}
+			b.WriteString("]")
+			return
+		}
+	case xdr.ScValTypeScvMap:
+		if v.Map != nil && *v.Map != nil {
+			writeScMap(b, **v.Map)
+			return
+		}
+	case xdr.ScValTypeScvError:
+		if v.Error != nil {
+			b.WriteString(formatScError(*v.Error))
+			return
+		}
+	case xdr.ScValTypeScvContractInstance:
+		if v.Instance != nil {
+			writeContractInstance(b, *v.Instance)
+			return
+		}
+	case xdr.ScValTypeScvLedgerKeyContractInstance:
+		b.WriteString("LedgerKeyContractInstance")
+		return
+	case xdr.ScValTypeScvLedgerKeyNonce:
+		if v.NonceKey != nil {
+			fmt.Fprintf(b, "Nonce(%d)", v.NonceKey.Nonce)
+			return
+		}
+	}
+
+	// Unknown type or missing arm
+	b.WriteString(strings.TrimPrefix(v.Type.String(), "ScValTypeScv"))
+}
+
+func writeScMap(b *strings.Builder, entries xdr.ScMap) {
+	b.WriteString("{")
+	for i, entry := range entries {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writeScVal(b, entry.Key)
+		b.WriteString(": ")
+		writeScVal(b, entry.Val)
+	}
+	b.WriteString("}")
+}
+
+func writeContractInstance(b *strings.Builder, instance xdr.ScContractInstance) {
+	switch instance.Executable.Type {
+	case xdr.ContractExecutableTypeContractExecutableStellarAsset:
+		b.WriteString("ContractInstance(StellarAsset")
+	default:
+		b.WriteString("ContractInstance(wasm:")
+		if instance.Executable.WasmHash != nil {
+			b.WriteString(hex.EncodeToString(instance.Executable.WasmHash[:]))
+		}
+	}
+	if instance.Storage != nil && len(*instance.Storage) > 0 {
+		b.WriteString(", ")
+		writeScMap(b, *instance.Storage)
+	}
+	b.WriteString(")")
+}
+
+// formatScError renders e.g. Error(Contract, #3) or Error(Budget, ExceededLimit)
+func formatScError(e xdr.ScError) string {
+	kind := strings.TrimPrefix(e.Type.String(), "ScErrorTypeSce")
+	if e.ContractCode != nil {
+		return fmt.Sprintf("Error(%s, #%d)", kind, *e.ContractCode)
+	}
+	if e.Code != nil {
+		return fmt.Sprintf("Error(%s, %s)", kind, strings.TrimPrefix(e.Code.String(), "ScErrorCodeScec"))
+	}
+	return fmt.Sprintf("Error(%s)", kind)
+}
+
+// FormatEvent renders a base64 XDR DiagnosticEvent or ContractEvent as
+// "<contract> [topic, ...] data". Anything else is returned unchanged, so
+// already-readable event strings pass straight through.
+func FormatEvent(raw string) string {
+	var diag xdr.DiagnosticEvent
+	if err := xdr.SafeUnmarshalBase64(raw, &diag); err == nil {
+		return formatContractEvent(diag.Event)
+	}
+	var event xdr.ContractEvent
+	if err := xdr.SafeUnmarshalBase64(raw, &event); err == nil {
+		return formatContractEvent(event)
+	}
+	return raw
+}
+
+func formatContractEvent(event xdr.ContractEvent) string {
+	var b strings.Builder
+	if event.ContractId != nil {
+		addr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: event.ContractId}
+		if s, err := addr.String(); err == nil {
+			b.WriteString(s)
+			b.WriteString(" ")
+		}
+	}
+	if event.Body.V0 == nil {
+		return strings.TrimSpace(b.String())
+	}
+	b.WriteString("[")
+	for i, topic := range event.Body.V0.Topics {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writeScVal(&b, topic)
+	}
+	b.WriteString("] ")
+	writeScVal(&b, event.Body.V0.Data)
+	return b.String()
+}
+
+// RenderScVal formats a base64 SCVal, returning fallback when the XDR is
+// missing or undecodable
+func RenderScVal(b64, fallback string) string {
+	if b64 == "" {
+		return fallback
+	}
+	if rendered, err := FormatScValBase64(b64); err == nil {
+		return rendered
+	}
+	return fallback
+}
+
+// RenderScVals formats a list of base64 SCVals, falling back to the matching
+// raw string for entries that cannot be decoded, or to the whole raw list
+// when no XDR is available
+func RenderScVals(b64s, fallback []string) []string {
+	if len(b64s) == 0 {
+		return fallback
+	}
+	out := make([]string, len(b64s))
+	for i, b64 := range b64s {
+		raw := ""
+		if i < len(fallback) {
+			raw = fallback[i]
+		}
+		out[i] = RenderScVal(b64, raw)
+	}
+	return out
+}
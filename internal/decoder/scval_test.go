@@ -0,0 +1,144 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sym(s string) xdr.ScVal {
+	v := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}
+}
+
+func u32(n uint32) xdr.ScVal {
+	v := xdr.Uint32(n)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+}
+
+func TestFormatScVal_Scalars(t *testing.T) {
+	b := true
+	str := xdr.ScString("hello")
+	bytes := xdr.ScBytes{0xde, 0xad}
+	neg := xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: -1, Lo: ^xdr.Uint64(0)}}
+	big := xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &xdr.UInt128Parts{Hi: 1, Lo: 0}}
+
+	tests := []struct {
+		name string
+		val  xdr.ScVal
+		want string
+	}{
+		{"bool", xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b}, "true"},
+		{"void", xdr.ScVal{Type: xdr.ScValTypeScvVoid}, "()"},
+		{"u32", u32(42), "42"},
+		{"symbol", sym("transfer"), "transfer"},
+		{"string", xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}, `"hello"`},
+		{"bytes", xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &bytes}, "0xdead"},
+		{"i128 negative", neg, "-1"},
+		{"u128 high part", big, "18446744073709551616"},
+		{"nil arm", xdr.ScVal{Type: xdr.ScValTypeScvU64}, "U64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatScVal(tt.val))
+		})
+	}
+}
+
+func TestFormatScVal_Address(t *testing.T) {
+	addr := "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	accountID := xdr.MustAddress(addr)
+	scAddr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &accountID}
+
+	assert.Equal(t, addr, FormatScVal(xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &scAddr}))
+
+	SetAddressNames(map[string]string{addr: "alice"})
+	defer SetAddressNames(nil)
+	assert.Equal(t, "alice", FormatScVal(xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &scAddr}))
+}
+
+func TestSetAddressNameLoader(t *testing.T) {
+	addr := "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	calls := 0
+	SetAddressNameLoader(func() map[string]string {
+		calls++
+		return map[string]string{addr: "alice"}
+	})
+	defer SetAddressNames(nil)
+	assert.Equal(t, 0, calls, "names are not loaded until needed")
+
+	name, ok := AddressName(addr)
+	assert.True(t, ok)
+	assert.Equal(t, "alice", name)
+	_, ok = AddressName("GOTHER")
+	assert.False(t, ok)
+	assert.Equal(t, 1, calls)
+}
+
+func TestFormatScVal_Containers(t *testing.T) {
+	vec := &xdr.ScVec{sym("a"), u32(1)}
+	m := &xdr.ScMap{
+		{Key: sym("balance"), Val: u32(100)},
+		{Key: sym("items"), Val: xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec}},
+	}
+
+	assert.Equal(t, "{balance: 100, items: [a, 1]}", FormatScVal(xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &m}))
+}
+
+func TestFormatScVal_Error(t *testing.T) {
+	code := xdr.Uint32(3)
+	contractErr := xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &code}}
+	budgetCode := xdr.ScErrorCodeScecExceededLimit
+	budgetErr := xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceBudget, Code: &budgetCode}}
+
+	assert.Equal(t, "Error(Contract, #3)", FormatScVal(contractErr))
+	assert.Equal(t, "Error(Budget, ExceededLimit)", FormatScVal(budgetErr))
+}
+
+func TestFormatScValBase64(t *testing.T) {
+	encoded, err := xdr.MarshalBase64(sym("mint"))
+	require.NoError(t, err)
+
+	out, err := FormatScValBase64(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, "mint", out)
+
+	_, err = FormatScValBase64("not-base64!")
+	assert.Error(t, err)
+}
+
+func TestFormatEvent(t *testing.T) {
+	contractID := xdr.ContractId{1}
+	event := xdr.DiagnosticEvent{
+		Event: xdr.ContractEvent{
+			ContractId: &contractID,
+			Type:       xdr.ContractEventTypeContract,
+			Body: xdr.ContractEventBody{
+				V:  0,
+				V0: &xdr.ContractEventV0{Topics: []xdr.ScVal{sym("transfer")}, Data: u32(5)},
+			},
+		},
+	}
+	encoded, err := xdr.MarshalBase64(event)
+	require.NoError(t, err)
+
+	out := FormatEvent(encoded)
+	assert.Contains(t, out, "[transfer] 5")
+	assert.Equal(t, byte('C'), out[0])
+
+	assert.Equal(t, "already readable", FormatEvent("already readable"))
+}
+
+func TestRenderScVals(t *testing.T) {
+	encoded, err := xdr.MarshalBase64(sym("burn"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"raw"}, RenderScVals(nil, []string{"raw"}))
+	assert.Equal(t, []string{"burn", "raw2"}, RenderScVals([]string{encoded, "!!"}, []string{"raw1", "raw2"}))
+	assert.Equal(t, "fallback", RenderScVal("", "fallback"))
+}
@@ -10,9 +10,13 @@ import (
 
 // Suggestion represents a potential fix for a Soroban error
 type Suggestion struct {
-	Rule        string
-	Description string
-	Confidence  string // "high", "medium", "low"
+	Rule        string `json:"rule"`
+	Description string `json:"description"`
+	Confidence  string `json:"confidence"` // "high", "medium", "low"
+	// Evidence is the signal a failure check found
+	Evidence string `json:"evidence,omitempty"`
+	// NextSteps are the steps that usually fix the failure
+	NextSteps []string `json:"next_steps,omitempty"`
 }
 
 // ErrorPattern defines a heuristic rule for error detection
@@ -20,7 +24,22 @@ type ErrorPattern struct {
 	Name        string
 	Keywords    []string
 	EventChecks []func(DecodedEvent) bool
-	Suggestion  Suggestion
+	// FailureCheck matches the failure as a whole and returns the evidence
+	// it found, or "" when the rule does not apply
+	FailureCheck func(Failure) string
+	Suggestion   Suggestion
+}
+
+// Failure describes a failed simulation to the rules that look beyond its
+// individual events
+type Failure struct {
+	// Text is the lowercased error, logs and rendered diagnostic events
+	Text string
+	// ExpiredEntries describe the archived or expired footprint entries
+	ExpiredEntries []string
+	// ExhaustedBudget describes the budget use when CPU or memory reached
+	// its limit
+	ExhaustedBudget string
 }
 
 // SuggestionEngine provides heuristic-based error suggestions
@@ -158,10 +177,21 @@ func (e *SuggestionEngine) loadDefaultRules() {
 				return false
 			},
 		},
+		FailureCheck: func(f Failure) string {
+			if f.ExhaustedBudget != "" {
+				return f.ExhaustedBudget
+			}
+			return firstMatch(f.Text, "error(budget, exceededlimit)", "resource_limit_exceeded", "resourcelimitexceeded", "cpu limit exceeded", "memory limit exceeded", "exceeds the declared")
+		},
 		Suggestion: Suggestion{
 			Rule:        "resource_limit_exceeded",
 			Description: "Potential Fix: Optimize your contract code to reduce CPU/memory usage, or increase resource limits in the transaction.",
 			Confidence:  "medium",
+			NextSteps: []string{
+				"Re-run preflight (simulateTransaction) and use its resource estimates, with some headroom",
+				"Raise the instruction, read-bytes or write-bytes limits in the transaction's SorobanTransactionData",
+				"Profile the call with 'erst debug --profile' to find the functions that use the most CPU and memory",
+			},
 		},
 	})
 
@@ -186,6 +216,78 @@ func (e *SuggestionEngine) loadDefaultRules() {
 			Confidence:  "medium",
 		},
 	})
+
+	// Rule 8: Missing trustline
+	e.rules = append(e.rules, ErrorPattern{
+		Name: "missing_trustline",
+		FailureCheck: func(f Failure) string {
+			return firstMatch(f.Text, "trustline entry is missing", "trustline is missing", "missing trustline", "no trustline", "trustlinemissing")
+		},
+		Suggestion: Suggestion{
+			Rule:        "missing_trustline",
+			Description: "Potential Fix: Add a trustline for the asset to the account before this transaction; the account has none.",
+			Confidence:  "high",
+			NextSteps: []string{
+				"Add a ChangeTrust operation for the asset from the receiving account before this transaction",
+				"Check that the recipient address and the asset's issuer are the ones you intended",
+			},
+		},
+	})
+
+	// Rule 9: Archived or expired ledger entries
+	e.rules = append(e.rules, ErrorPattern{
+		Name: "expired_entry",
+		FailureCheck: func(f Failure) string {
+			if len(f.ExpiredEntries) > 0 {
+				return strings.Join(f.ExpiredEntries, ", ")
+			}
+			return firstMatch(f.Text, "entryarchived", "entry is archived", "archived entry", "entry has expired")
+		},
+		Suggestion: Suggestion{
+			Rule:        "expired_entry",
+			Description: "Potential Fix: Restore the archived or expired ledger entries the transaction needs, then retry.",
+			Confidence:  "high",
+			NextSteps: []string{
+				"Submit a RestoreFootprintOperation for the archived entries, then retry",
+				"Extend the TTL of long-lived entries with ExtendFootprintTTLOperation so they do not lapse again",
+				"Temporary entries cannot be restored; recreate them, e.g. by calling the function that writes them",
+			},
+		},
+	})
+
+	// Rule 10: Stale authorization nonce or signature expiration
+	e.rules = append(e.rules, ErrorPattern{
+		Name: "bad_auth_nonce",
+		FailureCheck: func(f Failure) string {
+			if ev := firstMatch(f.Text, "error(auth, existingvalue)", "nonce already exists", "nonce already used"); ev != "" {
+				return ev
+			}
+			if strings.Contains(f.Text, "error(auth,") {
+				return firstMatch(f.Text, "signature has expired", "signature expiration", "expired signature", "nonce")
+			}
+			return ""
+		},
+		Suggestion: Suggestion{
+			Rule:        "bad_auth_nonce",
+			Description: "Potential Fix: Rebuild the authorization entries; a nonce or signature expiration is stale.",
+			Confidence:  "medium",
+			NextSteps: []string{
+				"Build new authorization entries with a fresh random nonce; each nonce can only be used once",
+				"Set the signature expiration ledger above the current ledger and sign the entries again",
+				"Re-run preflight so the auth entries match the invocation being submitted",
+			},
+		},
+	})
+}
+
+// firstMatch returns the first pattern found in text
+func firstMatch(text string, patterns ...string) string {
+	for _, p := range patterns {
+		if strings.Contains(text, p) {
+			return p
+		}
+	}
+	return ""
 }
 
 // AnalyzeEvents analyzes decoded events and returns suggestions
@@ -238,6 +340,31 @@ func (e *SuggestionEngine) AnalyzeEvents(events []DecodedEvent) []Suggestion {
 	return suggestions
 }
 
+// AnalyzeFailure returns the suggestions for a failed simulation: first
+// those of rules matching the failure as a whole, with their evidence, then
+// those of the events in its call tree, which may be nil
+func (e *SuggestionEngine) AnalyzeFailure(f Failure, root *CallNode) []Suggestion {
+	suggestions := []Suggestion{}
+	seenRules := make(map[string]bool)
+	for _, rule := range e.rules {
+		if rule.FailureCheck == nil {
+			continue
+		}
+		if evidence := rule.FailureCheck(f); evidence != "" {
+			suggestion := rule.Suggestion
+			suggestion.Evidence = evidence
+			suggestions = append(suggestions, suggestion)
+			seenRules[rule.Name] = true
+		}
+	}
+	for _, suggestion := range e.AnalyzeCallTree(root) {
+		if !seenRules[suggestion.Rule] {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return suggestions
+}
+
 // AnalyzeCallTree analyzes a call tree and returns suggestions
 func (e *SuggestionEngine) AnalyzeCallTree(root *CallNode) []Suggestion {
 	if root == nil {
@@ -288,6 +415,15 @@ func FormatSuggestions(suggestions []Suggestion) string {
 
 		output.WriteString(fmt.Sprintf("%d. %s [Confidence: %s]\n", i+1, confidenceIcon, suggestion.Confidence))
 		output.WriteString(fmt.Sprintf("   %s\n", suggestion.Description))
+		if suggestion.Evidence != "" {
+			output.WriteString(fmt.Sprintf("   Evidence: %s\n", suggestion.Evidence))
+		}
+		if len(suggestion.NextSteps) > 0 {
+			output.WriteString("   Next steps:\n")
+			for _, step := range suggestion.NextSteps {
+				output.WriteString(fmt.Sprintf("   - %s\n", step))
+			}
+		}
 		if i < len(suggestions)-1 {
 			output.WriteString("\n")
 		}
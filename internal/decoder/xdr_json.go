@@ -0,0 +1,467 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// XDRType is an XDR type 'erst xdr decode' can decode
+type XDRType struct {
+	Name  string // Name given with --type
+	Label string // Name of the XDR type
+	new   func() interface{}
+}
+
+// XDRTypes are the decodable types, in the order auto-detection tries them:
+// larger, more constrained types come first so that a short value is not
+// taken for one of them by accident
+var XDRTypes = []XDRType{
+	{"envelope", "TransactionEnvelope", func() interface{} { return &xdr.TransactionEnvelope{} }},
+	{"result", "TransactionResult", func() interface{} { return &xdr.TransactionResult{} }},
+	{"meta", "TransactionMeta", func() interface{} { return &xdr.TransactionMeta{} }},
+	{"ledger-entry", "LedgerEntry", func() interface{} { return &xdr.LedgerEntry{} }},
+	{"ledger-key", "LedgerKey", func() interface{} { return &xdr.LedgerKey{} }},
+	{"soroban-data", "SorobanTransactionData", func() interface{} { return &xdr.SorobanTransactionData{} }},
+	{"auth-entry", "SorobanAuthorizationEntry", func() interface{} { return &xdr.SorobanAuthorizationEntry{} }},
+	{"diagnostic-event", "DiagnosticEvent", func() interface{} { return &xdr.DiagnosticEvent{} }},
+	{"contract-event", "ContractEvent", func() interface{} { return &xdr.ContractEvent{} }},
+	{"ledger-header", "LedgerHeader", func() interface{} { return &xdr.LedgerHeader{} }},
+	{"scval", "ScVal", func() interface{} { return &xdr.ScVal{} }},
+}
+
+// XDRTypeNames lists the names accepted by DecodeXDR
+func XDRTypeNames() []string {
+	names := make([]string, len(XDRTypes))
+	for i, t := range XDRTypes {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// DecodedXDR is a decoded XDR value, ready to be printed as JSON
+type DecodedXDR struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+	// Rendered is the compact form of SCVals and ledger entries
+	Rendered string `json:"rendered,omitempty"`
+	// Candidates lists the other types the input also decodes as, when the
+	// type was auto-detected
+	Candidates []string `json:"candidates,omitempty"`
+}
+
+// DecodeXDR decodes base64 XDR as the type named typeName, or as the first
+// type in XDRTypes it decodes as, without leftover bytes, when typeName is
+// empty or "auto"
+func DecodeXDR(b64, typeName string) (*DecodedXDR, error) {
+	b64 = strings.Join(strings.Fields(b64), "")
+	if typeName != "" && typeName != "auto" {
+		t, err := lookupXDRType(typeName)
+		if err != nil {
+			return nil, err
+		}
+		v := t.new()
+		if err := xdr.SafeUnmarshalBase64(b64, v); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", t.Label, err)
+		}
+		return decoded(t, v), nil
+	}
+
+	var result *DecodedXDR
+	for _, t := range XDRTypes {
+		v := t.new()
+		if err := xdr.SafeUnmarshalBase64(b64, v); err != nil {
+			continue
+		}
+		if result == nil {
+			result = decoded(t, v)
+		} else {
+			result.Candidates = append(result.Candidates, t.Label)
+		}
+	}
+	if result == nil {
+		return nil, fmt.Errorf("input is not valid base64 XDR of any known type (%s)", strings.Join(XDRTypeNames(), ", "))
+	}
+	return result, nil
+}
+
+// lookupXDRType finds a type by its --type name or XDR type name
+func lookupXDRType(name string) (XDRType, error) {
+	for _, t := range XDRTypes {
+		if t.Name == name || t.Label == name {
+			return t, nil
+		}
+	}
+	return XDRType{}, fmt.Errorf("unknown XDR type %q (use auto, %s)", name, strings.Join(XDRTypeNames(), ", "))
+}
+
+func decoded(t XDRType, v interface{}) *DecodedXDR {
+	d := &DecodedXDR{Type: t.Label, Value: XDRToJSON(v)}
+	switch val := v.(type) {
+	case *xdr.ScVal:
+		d.Rendered = FormatScVal(*val)
+	case *xdr.LedgerEntry:
+		d.Rendered = FormatLedgerEntry(*val)
+	}
+	return d
+}
+
+// XDRToJSON converts an XDR value into plain maps, slices and scalars that
+// marshal to readable JSON: unset union arms are dropped, fields are
+// snake_case, enums are named, keys and addresses are strkeys and fixed
+// byte arrays and opaque data are hex
+func XDRToJSON(v interface{}) interface{} {
+	return xdrValue(reflect.ValueOf(v))
+}
+
+var (
+	accountIDType    = reflect.TypeOf(xdr.AccountId{})
+	muxedAccountType = reflect.TypeOf(xdr.MuxedAccount{})
+	scAddressType    = reflect.TypeOf(xdr.ScAddress{})
+	contractIDType   = reflect.TypeOf(xdr.ContractId{})
+	stringerType     = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+func xdrValue(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Type() {
+	case accountIDType:
+		return v.Interface().(xdr.AccountId).Address()
+	case muxedAccountType:
+		m := v.Interface().(xdr.MuxedAccount)
+		if address, err := m.GetAddress(); err == nil {
+			return address
+		}
+	case scAddressType:
+		if address, err := v.Interface().(xdr.ScAddress).String(); err == nil {
+			return address
+		}
+	case contractIDType:
+		id := v.Interface().(xdr.ContractId)
+		if address, err := strkey.Encode(strkey.VersionByteContract, id[:]); err == nil {
+			return address
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Int32:
+		// XDR enums are int32 types with generated names
+		if v.Type().Implements(stringerType) {
+			return v.Interface().(fmt.Stringer).String()
+		}
+		return v.Int()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		return v.String()
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return hex.EncodeToString(b)
+		}
+		return xdrList(v)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return hex.EncodeToString(v.Bytes())
+		}
+		return xdrList(v)
+	case reflect.Struct:
+		out := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			f := v.Field(i)
+			if (f.Kind() == reflect.Ptr || f.Kind() == reflect.Slice) && f.IsNil() {
+				continue
+			}
+			out[snakeCase(field.Name)] = xdrValue(f)
+		}
+		return out
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func xdrList(v reflect.Value) []interface{} {
+	out := make([]interface{}, v.Len())
+	for i := range out {
+		out[i] = xdrValue(v.Index(i))
+	}
+	return out
+}
+
+// EncodeXDR is the inverse of DecodeXDR: it builds a value of the type named
+// typeName from its JSON form and returns it as base64 XDR. data may also be
+// a whole DecodeXDR result, whose type is used when typeName is empty or
+// "auto".
+func EncodeXDR(data []byte, typeName string) (string, error) {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return "", fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if wrapped, ok := doc.(map[string]interface{}); ok {
+		label, _ := wrapped["type"].(string)
+		if value, ok := wrapped["value"]; ok {
+			if t, err := lookupXDRType(label); err == nil {
+				doc = value
+				if typeName == "" || typeName == "auto" {
+					typeName = t.Name
+				}
+			}
+		}
+	}
+	if typeName == "" || typeName == "auto" {
+		return "", fmt.Errorf("the XDR type cannot be detected from plain JSON; give it with --type (%s)", strings.Join(XDRTypeNames(), ", "))
+	}
+
+	t, err := lookupXDRType(typeName)
+	if err != nil {
+		return "", err
+	}
+	v := t.new()
+	if err := xdrFromJSON(reflect.ValueOf(v).Elem(), doc, "value"); err != nil {
+		return "", err
+	}
+	b64, err := xdr.MarshalBase64(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", t.Label, err)
+	}
+	return b64, nil
+}
+
+func xdrFromJSON(v reflect.Value, j interface{}, path string) error {
+	if v.Kind() == reflect.Ptr {
+		if j == nil {
+			return nil
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		return xdrFromJSON(v.Elem(), j, path)
+	}
+
+	if s, ok := j.(string); ok {
+		switch v.Type() {
+		case accountIDType:
+			id, err := xdr.AddressToAccountId(s)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			v.Set(reflect.ValueOf(id))
+			return nil
+		case muxedAccountType:
+			m, err := xdr.AddressToMuxedAccount(s)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			v.Set(reflect.ValueOf(m))
+			return nil
+		case scAddressType:
+			address, err := scAddressFromStrkey(s)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			v.Set(reflect.ValueOf(address))
+			return nil
+		case contractIDType:
+			raw, err := strkey.Decode(strkey.VersionByteContract, s)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			var id xdr.ContractId
+			copy(id[:], raw)
+			v.Set(reflect.ValueOf(id))
+			return nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Int32:
+		if s, ok := j.(string); ok && v.Type().Implements(stringerType) {
+			n, ok := enumValue(v.Type(), s)
+			if !ok {
+				return fmt.Errorf("%s: %q is not a %s", path, s, v.Type().Name())
+			}
+			v.SetInt(int64(n))
+			return nil
+		}
+		fallthrough
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int64:
+		n, ok := j.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: expected a number", path)
+		}
+		i, err := strconv.ParseInt(n.String(), 10, 64)
+		if err != nil || v.OverflowInt(i) {
+			return fmt.Errorf("%s: %s is not a valid %s", path, n, v.Type().Name())
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := j.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: expected a number", path)
+		}
+		u, err := strconv.ParseUint(n.String(), 10, 64)
+		if err != nil || v.OverflowUint(u) {
+			return fmt.Errorf("%s: %s is not a valid %s", path, n, v.Type().Name())
+		}
+		v.SetUint(u)
+	case reflect.Bool:
+		b, ok := j.(bool)
+		if !ok {
+			return fmt.Errorf("%s: expected true or false", path)
+		}
+		v.SetBool(b)
+	case reflect.String:
+		s, ok := j.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected a string", path)
+		}
+		v.SetString(s)
+	case reflect.Array, reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			s, ok := j.(string)
+			if !ok {
+				return fmt.Errorf("%s: expected hex bytes", path)
+			}
+			b, err := hex.DecodeString(s)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			if v.Kind() == reflect.Slice {
+				v.SetBytes(b)
+				return nil
+			}
+			if len(b) != v.Len() {
+				return fmt.Errorf("%s: expected %d bytes, got %d", path, v.Len(), len(b))
+			}
+			reflect.Copy(v, reflect.ValueOf(b))
+			return nil
+		}
+		list, ok := j.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected a list", path)
+		}
+		if v.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(v.Type(), len(list), len(list)))
+		} else if len(list) != v.Len() {
+			return fmt.Errorf("%s: expected %d items, got %d", path, v.Len(), len(list))
+		}
+		for i, item := range list {
+			if err := xdrFromJSON(v.Index(i), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		obj, ok := j.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object", path)
+		}
+		fields := make(map[string]int, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fields[snakeCase(v.Type().Field(i).Name)] = i
+			}
+		}
+		for key, item := range obj {
+			i, ok := fields[key]
+			if !ok {
+				return fmt.Errorf("%s: unknown field %q in %s", path, key, v.Type().Name())
+			}
+			if err := xdrFromJSON(v.Field(i), item, path+"."+key); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%s: cannot encode %s from JSON", path, v.Type())
+	}
+	return nil
+}
+
+// scAddressFromStrkey parses the account (G...), muxed account (M...) and
+// contract (C...) strkeys ScAddress.String produces
+func scAddressFromStrkey(s string) (xdr.ScAddress, error) {
+	switch {
+	case strings.HasPrefix(s, "G"):
+		id, err := xdr.AddressToAccountId(s)
+		if err != nil {
+			return xdr.ScAddress{}, err
+		}
+		return xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeAccount, id)
+	case strings.HasPrefix(s, "M"):
+		m, err := xdr.AddressToMuxedAccount(s)
+		if err != nil {
+			return xdr.ScAddress{}, err
+		}
+		med := m.MustMed25519()
+		return xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeMuxedAccount, xdr.MuxedEd25519Account{Id: med.Id, Ed25519: med.Ed25519})
+	case strings.HasPrefix(s, "C"):
+		raw, err := strkey.Decode(strkey.VersionByteContract, s)
+		if err != nil {
+			return xdr.ScAddress{}, err
+		}
+		var id xdr.ContractId
+		copy(id[:], raw)
+		return xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeContract, id)
+	}
+	return xdr.ScAddress{}, fmt.Errorf("unsupported address %q", s)
+}
+
+// enumMin and enumMax bound the values searched for enum names; XDR enums in the
+// protocol all fall inside it
+const (
+	enumMin = -64
+	enumMax = 4096
+)
+
+var (
+	enumNamesMu sync.Mutex
+	enumNames   = map[reflect.Type]map[string]int32{}
+)
+
+// enumValue returns the value of the XDR enum type t named name, as printed by
+// its String method
+func enumValue(t reflect.Type, name string) (int32, bool) {
+	enumNamesMu.Lock()
+	defer enumNamesMu.Unlock()
+	names, ok := enumNames[t]
+	if !ok {
+		names = make(map[string]int32)
+		validator, _ := reflect.Zero(t).Interface().(interface{ ValidEnum(int32) bool })
+		for n := int32(enumMin); n <= enumMax; n++ {
+			if validator != nil && !validator.ValidEnum(n) {
+				continue
+			}
+			e := reflect.New(t).Elem()
+			e.SetInt(int64(n))
+			names[e.Interface().(fmt.Stringer).String()] = n
+		}
+		enumNames[t] = names
+	}
+	n, ok := names[name]
+	return n, ok
+}
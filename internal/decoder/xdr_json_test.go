@@ -0,0 +1,127 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeXDR(t *testing.T) {
+	address := keypair.MustRandom().Address()
+	account := xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{
+			AccountId: xdr.MustAddress(address),
+			Balance:   125_000_000,
+			SeqNum:    42,
+		},
+	}}
+	b64, err := xdr.MarshalBase64(account)
+	require.NoError(t, err)
+
+	got, err := DecodeXDR(b64, "ledger-entry")
+	require.NoError(t, err)
+	assert.Equal(t, "LedgerEntry", got.Type)
+	assert.Equal(t, "balance 12.5000000 XLM, seq 42", got.Rendered)
+
+	out, err := json.Marshal(got.Value)
+	require.NoError(t, err)
+	var value map[string]interface{}
+	require.NoError(t, json.Unmarshal(out, &value))
+	data := value["data"].(map[string]interface{})
+	assert.Equal(t, "LedgerEntryTypeAccount", data["type"])
+	assert.NotContains(t, data, "trust_line", "unset union arms are dropped")
+	entry := data["account"].(map[string]interface{})
+	assert.Equal(t, address, entry["account_id"])
+	assert.EqualValues(t, 125_000_000, entry["balance"])
+
+	_, err = DecodeXDR(b64, "envelope")
+	assert.Error(t, err)
+	_, err = DecodeXDR(b64, "bogus")
+	assert.ErrorContains(t, err, "unknown XDR type")
+}
+
+func TestDecodeXDR_Auto(t *testing.T) {
+	sym := xdr.ScSymbol("transfer")
+	b64, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym})
+	require.NoError(t, err)
+
+	got, err := DecodeXDR(b64, "auto")
+	require.NoError(t, err)
+	assert.Equal(t, "ScVal", got.Type)
+	assert.Equal(t, "transfer", got.Rendered)
+
+	_, err = DecodeXDR("AAAA!!", "")
+	assert.ErrorContains(t, err, "not valid base64 XDR")
+}
+
+func TestXDRToJSON_Bytes(t *testing.T) {
+	hash := xdr.Hash{0xde, 0xad}
+	got := XDRToJSON(xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: hash},
+	})
+	code := got.(map[string]interface{})["contract_code"].(map[string]interface{})
+	assert.Equal(t, hash.HexString(), code["hash"])
+}
+
+func TestEncodeXDR_RoundTrip(t *testing.T) {
+	sym := xdr.ScSymbol("balance")
+	contract := xdr.ContractId{0x01, 0x02}
+	entry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 77,
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contract},
+				Key:        xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym},
+				Durability: xdr.ContractDataDurabilityPersistent,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: -1, Lo: 42}},
+			},
+		},
+	}
+	b64, err := xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+
+	decoded, err := DecodeXDR(b64, "ledger-entry")
+	require.NoError(t, err)
+	out, err := json.Marshal(decoded)
+	require.NoError(t, err)
+
+	got, err := EncodeXDR(out, "")
+	require.NoError(t, err, string(out))
+	assert.Equal(t, b64, got)
+
+	bare, err := json.Marshal(decoded.Value)
+	require.NoError(t, err)
+	got, err = EncodeXDR(bare, "ledger-entry")
+	require.NoError(t, err)
+	assert.Equal(t, b64, got)
+
+	_, err = EncodeXDR(bare, "auto")
+	assert.ErrorContains(t, err, "--type")
+}
+
+func TestEncodeXDR_Errors(t *testing.T) {
+	_, err := EncodeXDR([]byte(`{"type": "ScValTypeScvBogus"}`), "scval")
+	assert.ErrorContains(t, err, `value.type: "ScValTypeScvBogus" is not a ScValType`)
+
+	_, err = EncodeXDR([]byte(`{"type": "ScValTypeScvU32", "u32": -1}`), "scval")
+	assert.ErrorContains(t, err, "value.u32")
+
+	_, err = EncodeXDR([]byte(`{"type": "ScValTypeScvU32", "nope": 1}`), "scval")
+	assert.ErrorContains(t, err, `unknown field "nope"`)
+
+	got, err := EncodeXDR([]byte(`{"type": "ScValTypeScvU32", "u32": 7}`), "scval")
+	require.NoError(t, err)
+	rendered, err := FormatScValBase64(got)
+	require.NoError(t, err)
+	assert.Equal(t, "7", rendered)
+}
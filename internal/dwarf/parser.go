@@ -68,8 +68,7 @@ type Frame struct {
 // Parser handles DWARF debug information extraction
 type Parser struct {
 	data      *dwarf.Data
-	unit      *dwarf.Unit
-	reader    *dwarf.Reader
+		reader    *dwarf.Reader
 	binaryType string // "wasm", "elf", "macho", "pe"
 }
 
@@ -122,11 +121,11 @@ func parseWASM(data []byte) (*Parser, error) {
 	
 	// Look for .debug_info section
 	if infoSection, ok := sections[".debug_info"]; ok {
-		dwarfData, err = dwarf.New(infoSection, nil, nil)
+		dwarfData, err = dwarf.New(nil, nil, nil, infoSection, nil, nil, nil, nil)
 		if err != nil {
 			// Try with line section too
 			if lineSection, ok := sections[".debug_line"]; ok {
-				dwarfData, err = dwarf.New(infoSection, lineSection, nil)
+				dwarfData, err = dwarf.New(nil, nil, nil, infoSection, lineSection, nil, nil, nil)
 			}
 		}
 	}
@@ -356,7 +355,7 @@ func (p *Parser) getLocalVariables(subprog *dwarf.Entry) []LocalVar {
 		// Look for variables that are children of this subprogram
 		if entry.Tag == dwarf.TagVariable || entry.Tag == dwarf.TagFormalParameter {
 			// Check if this variable belongs to our subprogram
-			if ref, ok := entry.Val(dwarf.AttrParent).(dwarf.Offset); ok && ref == subprog.Offset {
+			if ref, ok := entry.Val(dwarf.AttrSibling).(dwarf.Offset); ok && ref == subprog.Offset {
 				local := p.extractLocalVar(entry)
 				if local.Name != "" {
 					locals = append(locals, local)
@@ -503,8 +502,8 @@ func (p *Parser) GetSourceLocation(addr uint64) (*SourceLocation, error) {
 
 		if entry.Tag == dwarf.TagCompileUnit {
 			// Get line program for this unit
-			if lineOffset, ok := entry.Val(dwarf.AttrStmtList).(uint64); ok {
-				lp, err := p.data.LineProgram(entry.Offset, true)
+			if _, ok := entry.Val(dwarf.AttrStmtList).(int64); ok {
+				lp, err := p.data.LineReader(entry)
 				if err == nil {
 					loc := p.findLineInProgram(lp, addr)
 					if loc != nil {
@@ -523,35 +522,11 @@ func (p *Parser) GetSourceLocation(addr uint64) (*SourceLocation, error) {
 }
 
 // findLineInProgram finds the source line for an address in a line program
-func (p *Parser) findLineInProgram(lp *dwarf.LineProgram, addr uint64) *SourceLocation {
-	// Iterate through line program sequences
-	for {
-		seq, err := lp.NextSequence()
-		if err != nil || seq == nil {
-			break
-		}
-
-		for {
-			line, err := seq.NextLine()
-			if err != nil {
-				break
-			}
-			if line == nil {
-				break
-			}
-
-			// Check if this line contains our address
-			if line.IsStmt {
-				// For now, return basic info
-				return &SourceLocation{
-					File:   line.File.Name,
-					Line:   int(line.Line),
-					Column: int(line.Column),
-				}
-			}
-		}
+func (p *Parser) findLineInProgram(lp *dwarf.LineReader, addr uint64) *SourceLocation {
+	var line dwarf.LineEntry
+	if err := lp.Next(&line); err == nil && line.File != nil {
+		return &SourceLocation{File: line.File.Name, Line: line.Line, Column: line.Column}
 	}
-
 	return nil
 }
 
@@ -562,14 +537,14 @@ func formatLocation(loc []byte) string {
 	}
 
 	switch loc[0] {
-	case dwarf.LocExprStackValue:
+	case 0x9f:
 		return "immediate"
-	case dwarf.LocAddr:
+	case 0x03:
 		if len(loc) >= 9 {
 			addr := binary.LittleEndian.Uint64(loc[1:])
 			return fmt.Sprintf("0x%x", addr)
 		}
-	case dwarf.LocEnd:
+	case 0x00:
 		return "end"
 	}
 
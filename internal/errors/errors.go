@@ -49,6 +49,9 @@ var (
 	ErrRPCResponseTooLarge  = errors.New("RPC response too large")
 	ErrConfigFailed         = errors.New("configuration error")
 	ErrNetworkNotFound      = errors.New("network not found")
+	ErrCheckFailed          = errors.New("check failed")
+	ErrSimCanceled          = errors.New("simulation canceled")
+	ErrProtocolMismatch     = errors.New("protocol version mismatch")
 )
 
 type LedgerNotFoundError struct {
@@ -163,6 +166,12 @@ func WrapProtocolUnsupported(version uint32) error {
 	return fmt.Errorf("%w: %d", ErrProtocolUnsupported, version)
 }
 
+// WrapProtocolMismatch reports that the network runs a protocol version the
+// simulation would not use, refused under --strict
+func WrapProtocolMismatch(msg string) error {
+	return fmt.Errorf("%w: %s", ErrProtocolMismatch, msg)
+}
+
 func WrapCliArgumentRequired(arg string) error {
 	return fmt.Errorf("%w: --%s", ErrArgumentRequired, arg)
 }
@@ -213,6 +222,17 @@ func WrapNetworkNotFound(network string) error {
 	return fmt.Errorf("%w: %s", ErrNetworkNotFound, network)
 }
 
+// WrapSimCanceled reports a simulation stopped because its context was
+// canceled or timed out; err is the context's error
+func WrapSimCanceled(err error) error {
+	return fmt.Errorf("%w: %w", ErrSimCanceled, err)
+}
+
+// WrapCheckFailed reports that a transaction checked with --check failed
+func WrapCheckFailed(msg string) error {
+	return fmt.Errorf("%w: %s", ErrCheckFailed, msg)
+}
+
 // WrapRPCResponseTooLarge wraps an HTTP 413 response into a readable message
 // explaining that the Soroban RPC response exceeded the server's size limit.
 func WrapRPCResponseTooLarge(url string) error {
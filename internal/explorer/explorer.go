@@ -0,0 +1,115 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package explorer builds links to transactions, accounts and contracts on
+// public Stellar block explorers.
+package explorer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explorers accepted by the explorer setting and --explorer
+const (
+	StellarExpert = "stellarexpert"
+	Stellarchain  = "stellarchain"
+	None          = "none"
+)
+
+// Links are the explorer pages of a transaction, its source account and the
+// contracts it involved, keyed by contract ID
+type Links struct {
+	Explorer      string            `json:"explorer"`
+	Transaction   string            `json:"transaction,omitempty"`
+	SourceAccount string            `json:"source_account,omitempty"`
+	Contracts     map[string]string `json:"contracts,omitempty"`
+}
+
+// Validate checks an explorer name; empty selects the default
+func Validate(name string) error {
+	switch strings.ToLower(name) {
+	case "", StellarExpert, Stellarchain, None:
+		return nil
+	default:
+		return fmt.Errorf("unknown explorer %q (use %s, %s or %s)", name, StellarExpert, Stellarchain, None)
+	}
+}
+
+// baseURL returns the explorer's root for network, or "" when the explorer
+// is off or does not index the network, as for local networks
+func baseURL(name, network string) string {
+	switch strings.ToLower(network) {
+	case "mainnet", "public", "pubnet":
+		network = "public"
+	case "testnet", "futurenet":
+	default:
+		return ""
+	}
+
+	switch strings.ToLower(name) {
+	case "", StellarExpert:
+		return "https://stellar.expert/explorer/" + network
+	case Stellarchain:
+		if network == "public" {
+			return "https://stellarchain.io"
+		}
+		return "https://" + network + ".stellarchain.io"
+	default:
+		return ""
+	}
+}
+
+// TransactionURL links to the transaction with the given hash
+func TransactionURL(name, network, hash string) string {
+	return pageURL(name, network, "tx", "transactions", hash)
+}
+
+// AccountURL links to a G... account
+func AccountURL(name, network, account string) string {
+	return pageURL(name, network, "account", "accounts", account)
+}
+
+// ContractURL links to a C... contract
+func ContractURL(name, network, contractID string) string {
+	return pageURL(name, network, "contract", "contracts", contractID)
+}
+
+// pageURL joins the explorer's path for a kind of object, which
+// stellar.expert names in the singular and Stellarchain in the plural
+func pageURL(name, network, expertPath, chainPath, id string) string {
+	base := baseURL(name, network)
+	if base == "" || id == "" {
+		return ""
+	}
+	if strings.ToLower(name) == Stellarchain {
+		return base + "/" + chainPath + "/" + id
+	}
+	return base + "/" + expertPath + "/" + id
+}
+
+// Build collects the links for a transaction on network, or returns nil when
+// the explorer has none for it
+func Build(name, network, txHash, source string, contracts []string) *Links {
+	if baseURL(name, network) == "" {
+		return nil
+	}
+	if name == "" {
+		name = StellarExpert
+	}
+	links := &Links{
+		Explorer:      strings.ToLower(name),
+		Transaction:   TransactionURL(name, network, txHash),
+		SourceAccount: AccountURL(name, network, source),
+	}
+	for _, id := range contracts {
+		if id == "" {
+			continue
+		}
+		if links.Contracts == nil {
+			links.Contracts = make(map[string]string)
+		}
+		links.Contracts[id] = ContractURL(name, network, id)
+	}
+	return links
+}
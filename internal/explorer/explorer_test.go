@@ -0,0 +1,44 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package explorer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAccount  = "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+	testContract = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+)
+
+func TestURLs(t *testing.T) {
+	assert.Equal(t, "https://stellar.expert/explorer/public/tx/abc", TransactionURL("", "mainnet", "abc"))
+	assert.Equal(t, "https://stellar.expert/explorer/testnet/account/"+testAccount, AccountURL(StellarExpert, "testnet", testAccount))
+	assert.Equal(t, "https://stellarchain.io/contracts/"+testContract, ContractURL(Stellarchain, "mainnet", testContract))
+	assert.Equal(t, "https://testnet.stellarchain.io/transactions/abc", TransactionURL(Stellarchain, "testnet", "abc"))
+
+	assert.Empty(t, TransactionURL(StellarExpert, "local", "abc"))
+	assert.Empty(t, TransactionURL(None, "mainnet", "abc"))
+}
+
+func TestBuild(t *testing.T) {
+	links := Build("", "testnet", "abc", testAccount, []string{testContract, ""})
+	require.NotNil(t, links)
+	assert.Equal(t, StellarExpert, links.Explorer)
+	assert.Equal(t, "https://stellar.expert/explorer/testnet/tx/abc", links.Transaction)
+	assert.Equal(t, map[string]string{testContract: "https://stellar.expert/explorer/testnet/contract/" + testContract}, links.Contracts)
+
+	assert.Nil(t, Build(None, "testnet", "abc", testAccount, nil))
+	assert.Nil(t, Build(StellarExpert, "local", "abc", testAccount, nil))
+}
+
+func TestValidate(t *testing.T) {
+	for _, name := range []string{"", StellarExpert, "Stellarchain", None} {
+		assert.NoError(t, Validate(name), name)
+	}
+	assert.Error(t, Validate("etherscan"))
+}
@@ -0,0 +1,266 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fees models the Soroban resource fee so that the resources declared
+// in a transaction can be compared with what it actually uses.
+package fees
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+const (
+	instructionsIncrement = 10_000
+	dataSizeIncrement     = 1024
+
+	// txBaseResultSize is the result size charged as history on top of the
+	// transaction itself
+	txBaseResultSize = 300
+)
+
+// Config holds the network's resource fee rates, in stroops
+type Config struct {
+	FeePerInstructionsIncrement int64 `json:"fee_per_instructions_increment"`
+	FeePerDiskReadEntry         int64 `json:"fee_per_disk_read_entry"`
+	FeePerWriteEntry            int64 `json:"fee_per_write_entry"`
+	FeePerDiskRead1KB           int64 `json:"fee_per_disk_read_1kb"`
+	FeePerWrite1KB              int64 `json:"fee_per_write_1kb"`
+	FeePerHistorical1KB         int64 `json:"fee_per_historical_1kb"`
+	FeePerContractEvent1KB      int64 `json:"fee_per_contract_event_1kb"`
+	FeePerTransactionSize1KB    int64 `json:"fee_per_transaction_size_1kb"`
+}
+
+// Resources are the quantities the resource fee is charged on
+type Resources struct {
+	Instructions       uint64 `json:"instructions"`
+	DiskReadEntries    uint32 `json:"disk_read_entries"`
+	WriteEntries       uint32 `json:"write_entries"`
+	DiskReadBytes      uint32 `json:"disk_read_bytes"`
+	WriteBytes         uint32 `json:"write_bytes"`
+	ContractEventsSize uint32 `json:"contract_events_size_bytes"`
+	TransactionSize    uint32 `json:"transaction_size_bytes"`
+}
+
+// Fee is the resource fee of each component, in stroops
+type Fee struct {
+	Compute         int64 `json:"compute"`
+	DiskReadEntries int64 `json:"disk_read_entries"`
+	WriteEntries    int64 `json:"write_entries"`
+	DiskReadBytes   int64 `json:"disk_read_bytes"`
+	WriteBytes      int64 `json:"write_bytes"`
+	Historical      int64 `json:"historical"`
+	Bandwidth       int64 `json:"bandwidth"`
+	Events          int64 `json:"events"`
+}
+
+// NonRefundable is the part of the fee charged up front whatever the outcome
+func (f Fee) NonRefundable() int64 {
+	return f.Compute + f.DiskReadEntries + f.WriteEntries + f.DiskReadBytes + f.WriteBytes + f.Historical + f.Bandwidth
+}
+
+// Compute returns the resource fee of r under c, following the Soroban host's
+// fee model. Rent is charged separately and is not included.
+func Compute(r Resources, c Config) Fee {
+	return Fee{
+		Compute:         perIncrement(r.Instructions, c.FeePerInstructionsIncrement, instructionsIncrement),
+		DiskReadEntries: int64(r.DiskReadEntries) * c.FeePerDiskReadEntry,
+		WriteEntries:    int64(r.WriteEntries) * c.FeePerWriteEntry,
+		DiskReadBytes:   perIncrement(uint64(r.DiskReadBytes), c.FeePerDiskRead1KB, dataSizeIncrement),
+		WriteBytes:      perIncrement(uint64(r.WriteBytes), c.FeePerWrite1KB, dataSizeIncrement),
+		Historical:      perIncrement(uint64(r.TransactionSize)+txBaseResultSize, c.FeePerHistorical1KB, dataSizeIncrement),
+		Bandwidth:       perIncrement(uint64(r.TransactionSize), c.FeePerTransactionSize1KB, dataSizeIncrement),
+		Events:          perIncrement(uint64(r.ContractEventsSize), c.FeePerContractEvent1KB, dataSizeIncrement),
+	}
+}
+
+// perIncrement charges rate for every started increment of amount
+func perIncrement(amount uint64, rate int64, increment uint64) int64 {
+	if amount == 0 || rate <= 0 {
+		return 0
+	}
+	return int64((amount*uint64(rate) + increment - 1) / increment)
+}
+
+// configSettingIDs are the network settings the fee model reads
+var configSettingIDs = []xdr.ConfigSettingId{
+	xdr.ConfigSettingIdConfigSettingContractComputeV0,
+	xdr.ConfigSettingIdConfigSettingContractLedgerCostV0,
+	xdr.ConfigSettingIdConfigSettingContractLedgerCostExtV0,
+	xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0,
+	xdr.ConfigSettingIdConfigSettingContractEventsV0,
+	xdr.ConfigSettingIdConfigSettingContractBandwidthV0,
+}
+
+// ConfigKeys returns the base64 LedgerKeys of the settings ParseConfig needs
+func ConfigKeys() ([]string, error) {
+	keys := make([]string, 0, len(configSettingIDs))
+	for _, id := range configSettingIDs {
+		key := xdr.LedgerKey{
+			Type:          xdr.LedgerEntryTypeConfigSetting,
+			ConfigSetting: &xdr.LedgerKeyConfigSetting{ConfigSettingId: id},
+		}
+		encoded, err := xdr.MarshalBase64(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode config setting key: %w", err)
+		}
+		keys = append(keys, encoded)
+	}
+	return keys, nil
+}
+
+// ParseConfig builds the fee rates from config setting ledger entries, given
+// as base64 LedgerEntry or LedgerEntryData XDR
+func ParseConfig(entries []string) (Config, error) {
+	var c Config
+	found := make(map[xdr.ConfigSettingId]bool)
+	for _, encoded := range entries {
+		setting, err := decodeConfigSetting(encoded)
+		if err != nil {
+			return c, err
+		}
+		found[setting.ConfigSettingId] = true
+
+		switch setting.ConfigSettingId {
+		case xdr.ConfigSettingIdConfigSettingContractComputeV0:
+			c.FeePerInstructionsIncrement = int64(setting.ContractCompute.FeeRatePerInstructionsIncrement)
+		case xdr.ConfigSettingIdConfigSettingContractLedgerCostV0:
+			c.FeePerDiskReadEntry = int64(setting.ContractLedgerCost.FeeDiskReadLedgerEntry)
+			c.FeePerWriteEntry = int64(setting.ContractLedgerCost.FeeWriteLedgerEntry)
+			c.FeePerDiskRead1KB = int64(setting.ContractLedgerCost.FeeDiskRead1Kb)
+		case xdr.ConfigSettingIdConfigSettingContractLedgerCostExtV0:
+			c.FeePerWrite1KB = int64(setting.ContractLedgerCostExt.FeeWrite1Kb)
+		case xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0:
+			c.FeePerHistorical1KB = int64(setting.ContractHistoricalData.FeeHistorical1Kb)
+		case xdr.ConfigSettingIdConfigSettingContractEventsV0:
+			c.FeePerContractEvent1KB = int64(setting.ContractEvents.FeeContractEvents1Kb)
+		case xdr.ConfigSettingIdConfigSettingContractBandwidthV0:
+			c.FeePerTransactionSize1KB = int64(setting.ContractBandwidth.FeeTxSize1Kb)
+		}
+	}
+
+	for _, id := range configSettingIDs {
+		if !found[id] {
+			return c, fmt.Errorf("network config setting %s is missing", id)
+		}
+	}
+	return c, nil
+}
+
+func decodeConfigSetting(encoded string) (*xdr.ConfigSettingEntry, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config setting: %w", err)
+	}
+
+	var entry xdr.LedgerEntry
+	if err := xdr.SafeUnmarshal(raw, &entry); err == nil && entry.Data.ConfigSetting != nil {
+		return entry.Data.ConfigSetting, nil
+	}
+	var data xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode config setting: %w", err)
+	}
+	if data.ConfigSetting == nil {
+		return nil, fmt.Errorf("ledger entry is not a config setting")
+	}
+	return data.ConfigSetting, nil
+}
+
+// IsDiskRead reports whether reading key is charged as a disk read. Live
+// Soroban state is kept in memory, so only classic entries count.
+func IsDiskRead(key xdr.LedgerKey) bool {
+	switch key.Type {
+	case xdr.LedgerEntryTypeContractData, xdr.LedgerEntryTypeContractCode, xdr.LedgerEntryTypeTtl:
+		return false
+	default:
+		return true
+	}
+}
+
+// Declared returns the resources a transaction declares in its Soroban data,
+// with its transaction size, and the resource fee it offers
+func Declared(data *xdr.SorobanTransactionData, txSize uint32) (Resources, int64) {
+	footprint := data.Resources.Footprint
+	r := Resources{
+		Instructions:    uint64(data.Resources.Instructions),
+		DiskReadEntries: uint32(DiskReadKeys(footprint)),
+		WriteEntries:    uint32(len(footprint.ReadWrite)),
+		DiskReadBytes:   uint32(data.Resources.DiskReadBytes),
+		WriteBytes:      uint32(data.Resources.WriteBytes),
+		TransactionSize: txSize,
+	}
+	if data.Ext.ResourceExt != nil {
+		r.DiskReadEntries += uint32(len(data.Ext.ResourceExt.ArchivedSorobanEntries))
+	}
+	return r, int64(data.ResourceFee)
+}
+
+// DiskReadKeys counts the footprint keys charged as disk reads
+func DiskReadKeys(footprint xdr.LedgerFootprint) int {
+	n := 0
+	for _, group := range [][]xdr.LedgerKey{footprint.ReadOnly, footprint.ReadWrite} {
+		for _, key := range group {
+			if IsDiskRead(key) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Charged is the resource fee breakdown recorded for an applied transaction
+type Charged struct {
+	NonRefundable int64 `json:"non_refundable"`
+	Refundable    int64 `json:"refundable"`
+	Rent          int64 `json:"rent"`
+}
+
+// FromResultMeta returns the fee charged to an applied transaction and the
+// size of the contract events it emitted. The charged fee is nil for metas
+// that do not record it.
+func FromResultMeta(resultMetaXdr string) (*Charged, uint32, error) {
+	var meta xdr.TransactionResultMeta
+	if err := xdr.SafeUnmarshalBase64(resultMetaXdr, &meta); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode result meta: %w", err)
+	}
+
+	var ext xdr.SorobanTransactionMetaExt
+	var events []xdr.ContractEvent
+	switch tm := meta.TxApplyProcessing; tm.V {
+	case 3:
+		if tm.V3 != nil && tm.V3.SorobanMeta != nil {
+			ext = tm.V3.SorobanMeta.Ext
+			events = tm.V3.SorobanMeta.Events
+		}
+	case 4:
+		if tm.V4 != nil {
+			if tm.V4.SorobanMeta != nil {
+				ext = tm.V4.SorobanMeta.Ext
+			}
+			for _, op := range tm.V4.Operations {
+				events = append(events, op.Events...)
+			}
+		}
+	}
+
+	var size uint32
+	for _, event := range events {
+		raw, err := event.MarshalBinary()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode contract event: %w", err)
+		}
+		size += uint32(len(raw))
+	}
+
+	if ext.V1 == nil {
+		return nil, size, nil
+	}
+	return &Charged{
+		NonRefundable: int64(ext.V1.TotalNonRefundableResourceFeeCharged),
+		Refundable:    int64(ext.V1.TotalRefundableResourceFeeCharged),
+		Rent:          int64(ext.V1.RentFeeCharged),
+	}, size, nil
+}
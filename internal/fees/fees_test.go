@@ -0,0 +1,161 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package fees
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = Config{
+	FeePerInstructionsIncrement: 25,
+	FeePerDiskReadEntry:         6250,
+	FeePerWriteEntry:            10000,
+	FeePerDiskRead1KB:           1786,
+	FeePerWrite1KB:              3500,
+	FeePerHistorical1KB:         16235,
+	FeePerContractEvent1KB:      10000,
+	FeePerTransactionSize1KB:    1624,
+}
+
+func TestCompute(t *testing.T) {
+	fee := Compute(Resources{
+		Instructions:       1_000_001,
+		DiskReadEntries:    2,
+		WriteEntries:       1,
+		DiskReadBytes:      1024,
+		WriteBytes:         100,
+		ContractEventsSize: 200,
+		TransactionSize:    724,
+	}, testConfig)
+
+	assert.Equal(t, int64(2501), fee.Compute) // 100.0001 increments round up
+	assert.Equal(t, int64(12500), fee.DiskReadEntries)
+	assert.Equal(t, int64(10000), fee.WriteEntries)
+	assert.Equal(t, int64(1786), fee.DiskReadBytes)
+	assert.Equal(t, int64(342), fee.WriteBytes)
+	assert.Equal(t, int64(16235), fee.Historical) // 724 + 300 result bytes is exactly 1KB
+	assert.Equal(t, int64(1149), fee.Bandwidth)
+	assert.Equal(t, int64(1954), fee.Events)
+	assert.Equal(t, int64(2501+12500+10000+1786+342+16235+1149), fee.NonRefundable())
+
+	assert.Equal(t, Fee{}, Compute(Resources{}, Config{}))
+}
+
+func TestParseConfig(t *testing.T) {
+	settings := []xdr.ConfigSettingEntry{
+		{ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractComputeV0, ContractCompute: &xdr.ConfigSettingContractComputeV0{FeeRatePerInstructionsIncrement: 25}},
+		{ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractLedgerCostV0, ContractLedgerCost: &xdr.ConfigSettingContractLedgerCostV0{FeeDiskReadLedgerEntry: 6250, FeeWriteLedgerEntry: 10000, FeeDiskRead1Kb: 1786}},
+		{ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractLedgerCostExtV0, ContractLedgerCostExt: &xdr.ConfigSettingContractLedgerCostExtV0{FeeWrite1Kb: 3500}},
+		{ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0, ContractHistoricalData: &xdr.ConfigSettingContractHistoricalDataV0{FeeHistorical1Kb: 16235}},
+		{ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractEventsV0, ContractEvents: &xdr.ConfigSettingContractEventsV0{FeeContractEvents1Kb: 10000}},
+	}
+
+	var entries []string
+	for i, setting := range settings {
+		setting := setting
+		data := xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeConfigSetting, ConfigSetting: &setting}
+		var encoded string
+		var err error
+		if i%2 == 0 {
+			// RPC returns LedgerEntryData; full entries are accepted too
+			encoded, err = xdr.MarshalBase64(data)
+		} else {
+			encoded, err = xdr.MarshalBase64(xdr.LedgerEntry{Data: data})
+		}
+		require.NoError(t, err)
+		entries = append(entries, encoded)
+	}
+
+	_, err := ParseConfig(entries)
+	assert.Error(t, err, "bandwidth setting is missing")
+
+	bandwidth, err := xdr.MarshalBase64(xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeConfigSetting,
+		ConfigSetting: &xdr.ConfigSettingEntry{
+			ConfigSettingId:   xdr.ConfigSettingIdConfigSettingContractBandwidthV0,
+			ContractBandwidth: &xdr.ConfigSettingContractBandwidthV0{FeeTxSize1Kb: 1624},
+		},
+	})
+	require.NoError(t, err)
+
+	cfg, err := ParseConfig(append(entries, bandwidth))
+	require.NoError(t, err)
+	assert.Equal(t, testConfig, cfg)
+
+	keys, err := ConfigKeys()
+	require.NoError(t, err)
+	assert.Len(t, keys, len(configSettingIDs))
+}
+
+func TestDeclared(t *testing.T) {
+	account := xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7")}}
+	code := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{1}}}
+	contractID := xdr.ContractId{2}
+	data := xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			Durability: xdr.ContractDataDurabilityPersistent,
+		},
+	}
+
+	resources, fee := Declared(&xdr.SorobanTransactionData{
+		Ext: xdr.SorobanTransactionDataExt{V: 1, ResourceExt: &xdr.SorobanResourcesExtV0{ArchivedSorobanEntries: []xdr.Uint32{1}}},
+		Resources: xdr.SorobanResources{
+			Footprint:     xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{code}, ReadWrite: []xdr.LedgerKey{account, data}},
+			Instructions:  5_000_000,
+			DiskReadBytes: 400,
+			WriteBytes:    300,
+		},
+		ResourceFee: 90_000,
+	}, 512)
+
+	assert.Equal(t, Resources{
+		Instructions:    5_000_000,
+		DiskReadEntries: 2, // the account and the restored contract data
+		WriteEntries:    2,
+		DiskReadBytes:   400,
+		WriteBytes:      300,
+		TransactionSize: 512,
+	}, resources)
+	assert.Equal(t, int64(90_000), fee)
+}
+
+func TestCompare(t *testing.T) {
+	declared := Resources{Instructions: 10_000_000, DiskReadEntries: 1, WriteEntries: 1, DiskReadBytes: 500, WriteBytes: 200, TransactionSize: 600}
+	actual := declared
+	actual.Instructions = 2_000_000
+	actual.ContractEventsSize = 100
+
+	// Enough fee for everything declared, with events paid from the rest
+	need := Compute(declared, testConfig).NonRefundable() + Compute(actual, testConfig).Events + 50
+	r := Compare(declared, actual, need, 50, testConfig)
+	assert.Empty(t, r.Exceeded())
+	assert.Equal(t, int64(20_000), r.Surplus())
+	assert.Equal(t, "instructions", r.Culprit)
+
+	var declaredSum, actualSum int64
+	for _, c := range r.Components {
+		declaredSum += c.DeclaredFee
+		actualSum += c.ActualFee
+	}
+	assert.Equal(t, r.DeclaredFee, declaredSum)
+	assert.Equal(t, r.RequiredFee, actualSum)
+
+	// Writing more than declared fails however much fee is offered
+	actual.WriteBytes = 900
+	r = Compare(declared, actual, need*2, 50, testConfig)
+	require.Len(t, r.Exceeded(), 1)
+	assert.Equal(t, "write_bytes", r.Culprit)
+
+	// Rent larger than the refundable budget is an under-estimate
+	r = Compare(declared, declared, Compute(declared, testConfig).NonRefundable(), 4000, testConfig)
+	assert.Equal(t, int64(-4000), r.Surplus())
+	assert.Equal(t, "refundable", r.Culprit)
+}
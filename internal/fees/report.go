@@ -0,0 +1,110 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package fees
+
+// Component is one resource of a fee report. Declared and Actual are in the
+// component's own unit; the fees are in stroops.
+type Component struct {
+	Name        string `json:"name"`
+	Unit        string `json:"unit"`
+	Declared    uint64 `json:"declared"`
+	Actual      uint64 `json:"actual"`
+	DeclaredFee int64  `json:"declared_fee"`
+	ActualFee   int64  `json:"actual_fee"`
+	// Limit is set when Declared caps usage, so exceeding it fails the
+	// transaction
+	Limit bool `json:"limit"`
+}
+
+// Exceeded reports whether actual usage is over a declared limit
+func (c Component) Exceeded() bool {
+	return c.Limit && c.Actual > c.Declared
+}
+
+// Report compares the resources and fee a transaction declares with what it
+// needs
+type Report struct {
+	Components []Component `json:"components"`
+	// DeclaredFee is the resource fee the transaction offers
+	DeclaredFee int64 `json:"declared_resource_fee"`
+	// RequiredFee is the resource fee the actual usage costs, rent included
+	RequiredFee int64 `json:"required_resource_fee"`
+	Rent        int64 `json:"rent"`
+	// Culprit names the component that contributes most to the difference
+	// between the declared and required fees
+	Culprit string   `json:"culprit,omitempty"`
+	Charged *Charged `json:"charged,omitempty"`
+}
+
+// Compare builds the report for a transaction declaring declared resources
+// and declaredFee that uses actual resources and rent under c
+func Compare(declared, actual Resources, declaredFee, rent int64, c Config) *Report {
+	df, af := Compute(declared, c), Compute(actual, c)
+
+	// The refundable part of the declared fee is whatever the non-refundable
+	// part of the declared resources leaves over
+	refundable := declaredFee - df.NonRefundable()
+	if refundable < 0 {
+		refundable = 0
+	}
+
+	r := &Report{
+		Components: []Component{
+			{Name: "instructions", Unit: "instructions", Declared: declared.Instructions, Actual: actual.Instructions, DeclaredFee: df.Compute, ActualFee: af.Compute, Limit: true},
+			{Name: "disk_read_entries", Unit: "entries", Declared: uint64(declared.DiskReadEntries), Actual: uint64(actual.DiskReadEntries), DeclaredFee: df.DiskReadEntries, ActualFee: af.DiskReadEntries, Limit: true},
+			{Name: "write_entries", Unit: "entries", Declared: uint64(declared.WriteEntries), Actual: uint64(actual.WriteEntries), DeclaredFee: df.WriteEntries, ActualFee: af.WriteEntries, Limit: true},
+			{Name: "disk_read_bytes", Unit: "bytes", Declared: uint64(declared.DiskReadBytes), Actual: uint64(actual.DiskReadBytes), DeclaredFee: df.DiskReadBytes, ActualFee: af.DiskReadBytes, Limit: true},
+			{Name: "write_bytes", Unit: "bytes", Declared: uint64(declared.WriteBytes), Actual: uint64(actual.WriteBytes), DeclaredFee: df.WriteBytes, ActualFee: af.WriteBytes, Limit: true},
+			{Name: "transaction_size", Unit: "bytes", Declared: uint64(declared.TransactionSize), Actual: uint64(actual.TransactionSize), DeclaredFee: df.Historical + df.Bandwidth, ActualFee: af.Historical + af.Bandwidth},
+			{Name: "refundable", Unit: "stroops", Declared: uint64(refundable), Actual: uint64(af.Events + rent), DeclaredFee: refundable, ActualFee: af.Events + rent, Limit: true},
+		},
+		DeclaredFee: declaredFee,
+		RequiredFee: af.NonRefundable() + af.Events + rent,
+		Rent:        rent,
+	}
+	r.Culprit = r.culprit()
+	return r
+}
+
+// Surplus is how much the declared fee exceeds the required one; negative
+// when the transaction under-declares its fee
+func (r *Report) Surplus() int64 {
+	return r.DeclaredFee - r.RequiredFee
+}
+
+// Exceeded returns the components whose declared limit is too low
+func (r *Report) Exceeded() []Component {
+	var out []Component
+	for _, c := range r.Components {
+		if c.Exceeded() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// culprit returns the component behind the fee difference: the exceeded
+// limit, or the component whose fee moves furthest in the direction of the
+// overall surplus or shortfall
+func (r *Report) culprit() string {
+	candidates, under := r.Exceeded(), true
+	if len(candidates) == 0 {
+		candidates, under = r.Components, r.Surplus() < 0
+		if r.Surplus() == 0 {
+			return ""
+		}
+	}
+
+	name, best := candidates[0].Name, int64(0)
+	for _, c := range candidates {
+		delta := c.DeclaredFee - c.ActualFee
+		if under {
+			delta = -delta
+		}
+		if delta > best {
+			name, best = c.Name, delta
+		}
+	}
+	return name
+}
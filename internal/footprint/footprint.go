@@ -0,0 +1,255 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package footprint compares the ledger keys a Soroban transaction declares
+// in its footprint with the keys its simulation actually accessed.
+package footprint
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Access modes of a key, as declared or as used
+const (
+	ReadOnly  = "read_only"
+	ReadWrite = "read_write"
+)
+
+// Problems flagged on a key
+const (
+	// ProblemUnused is a declared key the transaction never accessed
+	ProblemUnused = "unused"
+	// ProblemUndeclared is a key the transaction accessed without declaring it
+	ProblemUndeclared = "undeclared"
+	// ProblemUndeclaredWrite is a key declared read-only that was written
+	ProblemUndeclaredWrite = "undeclared_write"
+)
+
+// Key is one ledger key of the footprint or of the simulation's accesses
+type Key struct {
+	Key  string `json:"key"` // base64 LedgerKey
+	Type string `json:"type"`
+	// Owner is the contract or account the entry belongs to, or the Wasm
+	// hash of contract code
+	Owner string `json:"owner"`
+	// Label describes the entry within its owner, e.g. a storage key
+	Label    string `json:"label,omitempty"`
+	Declared string `json:"declared,omitempty"` // ReadOnly, ReadWrite or empty
+	Accessed string `json:"accessed,omitempty"` // ReadOnly, ReadWrite or empty
+	Problem  string `json:"problem,omitempty"`
+}
+
+// Group is a set of keys sharing an owner or an entry type
+type Group struct {
+	Name string `json:"name"`
+	Keys []Key  `json:"keys"`
+}
+
+// View is the declared footprint of a transaction set against its accesses
+type View struct {
+	Keys []Key `json:"keys"`
+	// AccessKnown is false when the simulator did not report the keys it
+	// accessed; only writes are known then, and unused keys are not flagged
+	AccessKnown bool `json:"access_known"`
+	// CausedFailure is set when the transaction failed and accessed keys
+	// outside its footprint
+	CausedFailure bool `json:"caused_failure"`
+}
+
+// Analyze compares the footprint declared in envelopeXdr with the keys resp
+// accessed. failed reports whether the transaction failed.
+func Analyze(envelopeXdr string, resp *simulator.SimulationResponse, failed bool) (*View, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid transaction envelope: %w", err)
+	}
+
+	var declared xdr.LedgerFootprint
+	if data := rpc.SorobanDataFromEnvelope(envelope); data != nil {
+		declared = data.Resources.Footprint
+	}
+
+	view := &View{AccessKnown: len(resp.StorageAccesses) > 0}
+	index := make(map[string]int)
+	add := func(key xdr.LedgerKey, encoded string) *Key {
+		if i, ok := index[encoded]; ok {
+			return &view.Keys[i]
+		}
+		k := Describe(key)
+		k.Key = encoded
+		index[encoded] = len(view.Keys)
+		view.Keys = append(view.Keys, k)
+		return &view.Keys[len(view.Keys)-1]
+	}
+
+	for _, group := range []struct {
+		keys []xdr.LedgerKey
+		mode string
+	}{{declared.ReadOnly, ReadOnly}, {declared.ReadWrite, ReadWrite}} {
+		for _, key := range group.keys {
+			encoded, err := rpc.EncodeLedgerKey(key)
+			if err != nil {
+				return nil, err
+			}
+			add(key, encoded).Declared = group.mode
+		}
+	}
+
+	accessed := make(map[string]string, len(resp.StorageAccesses))
+	for _, a := range resp.StorageAccesses {
+		if a.ReadWrite {
+			accessed[a.Key] = ReadWrite
+		} else {
+			accessed[a.Key] = ReadOnly
+		}
+	}
+	if !view.AccessKnown {
+		for _, w := range resp.StorageWrites {
+			accessed[w.Key] = ReadWrite
+		}
+	}
+
+	// Undeclared keys follow the declared ones in a stable order
+	extra := make([]string, 0, len(accessed))
+	for encoded := range accessed {
+		if _, ok := index[encoded]; !ok {
+			extra = append(extra, encoded)
+		}
+	}
+	sort.Strings(extra)
+	for _, encoded := range extra {
+		var key xdr.LedgerKey
+		if err := xdr.SafeUnmarshalBase64(encoded, &key); err != nil {
+			return nil, fmt.Errorf("invalid ledger key: %w", err)
+		}
+		add(key, encoded)
+	}
+
+	for i := range view.Keys {
+		k := &view.Keys[i]
+		k.Accessed = accessed[k.Key]
+		switch {
+		case k.Declared == "":
+			k.Problem = ProblemUndeclared
+		case k.Declared == ReadOnly && k.Accessed == ReadWrite:
+			k.Problem = ProblemUndeclaredWrite
+		case k.Accessed == "" && view.AccessKnown:
+			k.Problem = ProblemUnused
+		}
+		if failed && (k.Problem == ProblemUndeclared || k.Problem == ProblemUndeclaredWrite) {
+			view.CausedFailure = true
+		}
+	}
+	return view, nil
+}
+
+// Problems returns the keys with a flagged problem
+func (v *View) Problems() []Key {
+	var out []Key
+	for _, k := range v.Keys {
+		if k.Problem != "" {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// ByOwner groups the keys by the contract or account they belong to
+func (v *View) ByOwner() []Group {
+	return v.group(func(k Key) string { return k.Owner })
+}
+
+// ByType groups the keys by ledger entry type
+func (v *View) ByType() []Group {
+	return v.group(func(k Key) string { return k.Type })
+}
+
+func (v *View) group(name func(Key) string) []Group {
+	var groups []Group
+	index := make(map[string]int)
+	for _, k := range v.Keys {
+		n := name(k)
+		i, ok := index[n]
+		if !ok {
+			i = len(groups)
+			index[n] = i
+			groups = append(groups, Group{Name: n})
+		}
+		groups[i].Keys = append(groups[i].Keys, k)
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// Describe fills in the type, owner and label of a key
+func Describe(key xdr.LedgerKey) Key {
+	switch key.Type {
+	case xdr.LedgerEntryTypeAccount:
+		return Key{Type: "account", Owner: key.Account.AccountId.Address()}
+	case xdr.LedgerEntryTypeTrustline:
+		return Key{Type: "trustline", Owner: key.TrustLine.AccountId.Address(), Label: assetString(key.TrustLine.Asset)}
+	case xdr.LedgerEntryTypeOffer:
+		return Key{Type: "offer", Owner: key.Offer.SellerId.Address(), Label: fmt.Sprintf("offer %d", key.Offer.OfferId)}
+	case xdr.LedgerEntryTypeData:
+		return Key{Type: "data", Owner: key.Data.AccountId.Address(), Label: string(key.Data.DataName)}
+	case xdr.LedgerEntryTypeClaimableBalance:
+		id, _ := xdr.MarshalHex(key.ClaimableBalance.BalanceId)
+		return Key{Type: "claimable_balance", Owner: id}
+	case xdr.LedgerEntryTypeLiquidityPool:
+		return Key{Type: "liquidity_pool", Owner: hex.EncodeToString(key.LiquidityPool.LiquidityPoolId[:])}
+	case xdr.LedgerEntryTypeContractData:
+		label := "instance"
+		if key.ContractData.Key.Type != xdr.ScValTypeScvLedgerKeyContractInstance {
+			label = decoder.FormatScVal(key.ContractData.Key)
+		}
+		durability := "persistent"
+		if key.ContractData.Durability == xdr.ContractDataDurabilityTemporary {
+			durability = "temporary"
+		}
+		return Key{Type: "contract_data", Owner: addressString(key.ContractData.Contract), Label: durability + " " + label}
+	case xdr.LedgerEntryTypeContractCode:
+		return Key{Type: "contract_code", Owner: "wasm:" + hex.EncodeToString(key.ContractCode.Hash[:])}
+	case xdr.LedgerEntryTypeConfigSetting:
+		return Key{Type: "config_setting", Owner: "network", Label: key.ConfigSetting.ConfigSettingId.String()}
+	case xdr.LedgerEntryTypeTtl:
+		return Key{Type: "ttl", Owner: hex.EncodeToString(key.Ttl.KeyHash[:])}
+	}
+	return Key{Type: key.Type.String(), Owner: key.Type.String()}
+}
+
+func addressString(address xdr.ScAddress) string {
+	if address.Type == xdr.ScAddressTypeScAddressTypeContract && address.ContractId != nil {
+		if id, err := strkey.Encode(strkey.VersionByteContract, address.ContractId[:]); err == nil {
+			return id
+		}
+	}
+	if address.Type == xdr.ScAddressTypeScAddressTypeAccount && address.AccountId != nil {
+		return address.AccountId.Address()
+	}
+	return address.Type.String()
+}
+
+func assetString(asset xdr.TrustLineAsset) string {
+	switch asset.Type {
+	case xdr.AssetTypeAssetTypeNative:
+		return "native"
+	case xdr.AssetTypeAssetTypePoolShare:
+		if asset.LiquidityPoolId != nil {
+			return "pool:" + hex.EncodeToString(asset.LiquidityPoolId[:])
+		}
+	default:
+		var code, issuer string
+		if err := asset.ToAsset().Extract(nil, &code, &issuer); err == nil {
+			return code + ":" + issuer
+		}
+	}
+	return asset.Type.String()
+}
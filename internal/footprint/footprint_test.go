@@ -0,0 +1,126 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package footprint
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccount = "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+
+func dataKey(id byte, key xdr.ScVal) xdr.LedgerKey {
+	contractID := xdr.ContractId{id}
+	return xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+			Key:        key,
+			Durability: xdr.ContractDataDurabilityPersistent,
+		},
+	}
+}
+
+func encode(t *testing.T, key xdr.LedgerKey) string {
+	t.Helper()
+	s, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	return s
+}
+
+func envelopeWith(t *testing.T, readOnly, readWrite []xdr.LedgerKey) string {
+	t.Helper()
+	s, err := xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(testAccount),
+			Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+				Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadOnly: readOnly, ReadWrite: readWrite}},
+			}},
+		}},
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func TestAnalyze(t *testing.T) {
+	sym := xdr.ScSymbol("Admin")
+	instance := dataKey(1, xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance})
+	admin := dataKey(1, xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym})
+	code := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{9}}}
+	account := xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress(testAccount)}}
+	other := dataKey(2, xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance})
+
+	envelope := envelopeWith(t, []xdr.LedgerKey{instance, code, admin}, []xdr.LedgerKey{account})
+	resp := &simulator.SimulationResponse{
+		Status: "success",
+		StorageAccesses: []simulator.StorageAccess{
+			{Key: encode(t, instance)},
+			{Key: encode(t, admin), ReadWrite: true},
+			{Key: encode(t, other)},
+		},
+	}
+
+	view, err := Analyze(envelope, resp, true)
+	require.NoError(t, err)
+	assert.True(t, view.AccessKnown)
+	assert.True(t, view.CausedFailure)
+	require.Len(t, view.Keys, 5)
+
+	byKey := map[string]Key{}
+	for _, k := range view.Keys {
+		byKey[k.Key] = k
+	}
+	assert.Empty(t, byKey[encode(t, instance)].Problem)
+	assert.Equal(t, "persistent instance", byKey[encode(t, instance)].Label)
+	assert.Equal(t, ProblemUndeclaredWrite, byKey[encode(t, admin)].Problem)
+	assert.Equal(t, "persistent Admin", byKey[encode(t, admin)].Label)
+	assert.Equal(t, ProblemUnused, byKey[encode(t, code)].Problem)
+	assert.Equal(t, ProblemUnused, byKey[encode(t, account)].Problem)
+	assert.Equal(t, testAccount, byKey[encode(t, account)].Owner)
+	assert.Equal(t, ProblemUndeclared, byKey[encode(t, other)].Problem)
+	assert.Equal(t, encode(t, other), view.Keys[4].Key, "undeclared keys come last")
+	assert.Len(t, view.Problems(), 4)
+
+	owners := view.ByOwner()
+	require.Len(t, owners, 4)
+	var contract Group
+	for _, g := range owners {
+		if g.Name == byKey[encode(t, instance)].Owner {
+			contract = g
+		}
+	}
+	assert.Len(t, contract.Keys, 2, "instance and Admin belong to the same contract")
+
+	types := view.ByType()
+	require.Len(t, types, 3)
+	assert.Equal(t, "account", types[0].Name)
+	assert.Equal(t, "contract_code", types[1].Name)
+	assert.Len(t, types[2].Keys, 3)
+
+	// A successful transaction is not blamed on its footprint
+	view, err = Analyze(envelope, resp, false)
+	require.NoError(t, err)
+	assert.False(t, view.CausedFailure)
+}
+
+func TestAnalyze_WritesOnly(t *testing.T) {
+	sym := xdr.ScSymbol("Counter")
+	counter := dataKey(1, xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym})
+	code := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{9}}}
+
+	// Without reported accesses only writes are compared
+	view, err := Analyze(envelopeWith(t, []xdr.LedgerKey{code}, []xdr.LedgerKey{counter}), &simulator.SimulationResponse{
+		StorageWrites: []simulator.StorageWrite{{Key: encode(t, counter)}},
+	}, true)
+	require.NoError(t, err)
+	assert.False(t, view.AccessKnown)
+	assert.Empty(t, view.Problems())
+	assert.False(t, view.CausedFailure)
+	assert.Equal(t, ReadWrite, view.Keys[1].Accessed)
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package heuristic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// Suggestions returns the potential fixes the suggestion engine finds for a
+// simulation. For a failed one, rules matching the failure as a whole come
+// first, with the evidence they found. callTree holds the decoded events and
+// may be nil.
+func Suggestions(in Input, callTree *decoder.CallNode) []decoder.Suggestion {
+	engine := decoder.NewSuggestionEngine()
+	if in.Status == "success" {
+		return engine.AnalyzeCallTree(callTree)
+	}
+	return engine.AnalyzeFailure(failure(in), callTree)
+}
+
+// failure collects the signals of a failed simulation that suggestion rules
+// look for beyond individual events
+func failure(in Input) decoder.Failure {
+	f := decoder.Failure{Text: failureText(in)}
+	if in.Archival != nil {
+		for _, e := range in.Archival.Entries {
+			if e.Status == simulator.TTLArchived || e.Status == simulator.TTLExpired {
+				f.ExpiredEntries = append(f.ExpiredEntries, fmt.Sprintf("%s %s entry", e.Status, e.Type))
+			}
+		}
+	}
+	if u := in.BudgetUsage; u != nil && (u.CPUUsagePercent >= 100 || u.MemoryUsagePercent >= 100) {
+		f.ExhaustedBudget = fmt.Sprintf("CPU %.0f%%, memory %.0f%% of the budget", u.CPUUsagePercent, u.MemoryUsagePercent)
+	}
+	return f
+}
+
+// failureText lowercases every signal the rules search: the error, logs and
+// the rendered topics and data of each event
+func failureText(in Input) string {
+	parts := []string{in.Error}
+	parts = append(parts, in.Events...)
+	parts = append(parts, in.Logs...)
+	for _, e := range in.DiagnosticEvents {
+		parts = append(parts, decoder.RenderScVals(e.TopicsXdr, e.Topics)...)
+		parts = append(parts, decoder.RenderScVal(e.DataXdr, e.Data))
+	}
+	return strings.ToLower(strings.Join(parts, "\n"))
+}
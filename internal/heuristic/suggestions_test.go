@@ -0,0 +1,111 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package heuristic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+func suggestionRules(suggestions []decoder.Suggestion) []string {
+	rules := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		rules[i] = s.Rule
+	}
+	return rules
+}
+
+func TestSuggestions_MissingTrustline(t *testing.T) {
+	in := Input{
+		Status: "error",
+		Error:  "HostError: Error(Contract, #13)",
+		DiagnosticEvents: []simulator.DiagnosticEvent{
+			{EventType: "diagnostic", Topics: []string{"error"}, Data: `"trustline entry is missing for account"`},
+		},
+	}
+	suggestions := Suggestions(in, nil)
+	if len(suggestions) != 1 || suggestions[0].Rule != "missing_trustline" {
+		t.Fatalf("expected missing_trustline, got %v", suggestionRules(suggestions))
+	}
+	if len(suggestions[0].NextSteps) == 0 {
+		t.Fatal("expected next steps")
+	}
+}
+
+func TestSuggestions_ExpiredEntry(t *testing.T) {
+	in := Input{
+		Status: "error",
+		Error:  "HostError: Error(Storage, MissingValue)",
+		Archival: &simulator.ArchivalReport{
+			CausedFailure: true,
+			Entries: []simulator.EntryTTL{
+				{Type: "contract_data", Status: simulator.TTLArchived},
+				{Type: "contract_code", Status: simulator.TTLLive},
+			},
+		},
+	}
+	suggestions := Suggestions(in, nil)
+	if len(suggestions) != 1 || suggestions[0].Rule != "expired_entry" {
+		t.Fatalf("expected expired_entry, got %v", suggestionRules(suggestions))
+	}
+	if suggestions[0].Evidence != "archived contract_data entry" {
+		t.Fatalf("unexpected evidence: %s", suggestions[0].Evidence)
+	}
+}
+
+func TestSuggestions_InsufficientResources(t *testing.T) {
+	in := Input{
+		Status:      "error",
+		Error:       "HostError: Error(Budget, ExceededLimit)",
+		BudgetUsage: &simulator.BudgetUsage{CPUUsagePercent: 100, MemoryUsagePercent: 40},
+	}
+	suggestions := Suggestions(in, nil)
+	if len(suggestions) != 1 || suggestions[0].Rule != "resource_limit_exceeded" {
+		t.Fatalf("expected resource_limit_exceeded, got %v", suggestionRules(suggestions))
+	}
+	if !strings.Contains(suggestions[0].Evidence, "CPU 100%") {
+		t.Fatalf("unexpected evidence: %s", suggestions[0].Evidence)
+	}
+}
+
+func TestSuggestions_BadAuthNonce(t *testing.T) {
+	suggestions := Suggestions(Input{Status: "error", Error: "HostError: Error(Auth, ExistingValue)"}, nil)
+	if len(suggestions) != 1 || suggestions[0].Rule != "bad_auth_nonce" {
+		t.Fatalf("expected bad_auth_nonce, got %v", suggestionRules(suggestions))
+	}
+
+	// A nonce mentioned outside an auth failure is not evidence
+	suggestions = Suggestions(Input{Status: "error", Error: "HostError: Error(Contract, #1)", Logs: []string{"nonce 42"}}, nil)
+	if len(suggestions) != 0 {
+		t.Fatalf("expected no suggestions, got %v", suggestionRules(suggestions))
+	}
+}
+
+func TestSuggestions_Success(t *testing.T) {
+	in := Input{Status: "success", Logs: []string{"trustline entry is missing"}}
+	if suggestions := Suggestions(in, nil); len(suggestions) != 0 {
+		t.Fatalf("expected no suggestions for a successful simulation, got %v", suggestionRules(suggestions))
+	}
+}
+
+func TestSuggestions_FailureRulesFirst(t *testing.T) {
+	in := Input{Status: "error", Error: "HostError: Error(Auth, ExistingValue)"}
+	tree := &decoder.CallNode{Events: []decoder.DecodedEvent{{ContractID: "CABC", Topics: []string{"unauthorized"}}}}
+	suggestions := Suggestions(in, tree)
+	if got := strings.Join(suggestionRules(suggestions), ","); got != "bad_auth_nonce,missing_authorization" {
+		t.Fatalf("expected the failure rule before the event rule, got %s", got)
+	}
+	out := decoder.FormatSuggestions(suggestions)
+	for _, want := range []string{"Potential Fixes", "Evidence: error(auth, existingvalue)", "Next steps:"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "Potential Fixes") != 1 {
+		t.Fatalf("expected a single block:\n%s", out)
+	}
+}
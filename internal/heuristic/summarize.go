@@ -20,6 +20,7 @@ type Input struct {
 	Logs             []string
 	DiagnosticEvents []simulator.DiagnosticEvent
 	BudgetUsage      *simulator.BudgetUsage
+	Archival         *simulator.ArchivalReport
 }
 
 // Summarize returns a single-paragraph plain-English explanation of why the
@@ -0,0 +1,440 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/trace"
+	"github.com/dotandev/hintents/internal/visualizer"
+)
+
+const clearScreen = "\033[H\033[2J"
+
+// Rows the browser draws around the tree: the title, filter and separator
+// above it, the tree renderer's scroll line, and the status and key lines
+// below it
+const (
+	headerRows = 3
+	footerRows = 3
+)
+
+// nodeFlamegraph is the type of the tree node that writes a session's
+// flamegraph when opened
+const nodeFlamegraph = "flamegraph"
+
+// Browser is a full-screen terminal interface for exploring saved sessions.
+// Sessions are shown as a tree built on the trace tree UI: each one expands
+// into the events, logs, and flamegraph stored with it, and the list is
+// filtered incrementally as a filter is typed.
+type Browser struct {
+	sessions []*session.SessionData
+	filtered []*session.SessionData
+	filter   string
+	editing  bool
+
+	root     *trace.TraceNode
+	owners   map[*trace.TraceNode]*session.SessionData
+	renderer *trace.TreeRenderer
+	width    int
+	status   string
+	help     bool
+
+	in  io.Reader
+	out io.Writer
+}
+
+// NewBrowser creates a browser over the given sessions reading keys from in
+// and drawing a width by height screen to out. in is expected to be a
+// terminal in raw mode, see MakeRaw.
+func NewBrowser(sessions []*session.SessionData, in io.Reader, out io.Writer, width, height int) *Browser {
+	b := &Browser{
+		sessions: sessions,
+		renderer: trace.NewTreeRenderer(width, height-footerRows),
+		width:    width,
+		in:       in,
+		out:      out,
+	}
+	b.applyFilter("")
+	return b
+}
+
+// Run draws the browser and handles keys until the user quits or input ends
+func (b *Browser) Run() error {
+	b.render()
+	buf := make([]byte, 256)
+	for {
+		n, err := b.in.Read(buf)
+		if n > 0 {
+			for _, key := range splitKeys(buf[:n]) {
+				if b.handleKey(key) {
+					fmt.Fprint(b.out, clearScreen)
+					return nil
+				}
+			}
+			b.render()
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+	}
+}
+
+// splitKeys splits a chunk of terminal input into keys: escape sequences
+// are kept whole and everything else is split into characters
+func splitKeys(input []byte) []string {
+	var keys []string
+	for len(input) > 0 {
+		n := keyLen(input)
+		keys = append(keys, string(input[:n]))
+		input = input[n:]
+	}
+	return keys
+}
+
+func keyLen(input []byte) int {
+	if input[0] != 0x1b {
+		_, size := utf8.DecodeRune(input)
+		return size
+	}
+	if len(input) < 3 || (input[1] != '[' && input[1] != 'O') {
+		return 1
+	}
+	// A basic mouse report carries three raw bytes after ESC [ M
+	if input[1] == '[' && input[2] == 'M' {
+		return min(len(input), 6)
+	}
+	// Other sequences end with a byte in the range @ to ~
+	for i := 2; i < len(input); i++ {
+		if input[i] >= 0x40 && input[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(input)
+}
+
+// handleKey processes one key and returns true if exit is requested
+func (b *Browser) handleKey(key string) bool {
+	b.status = ""
+	if b.help {
+		b.help = false
+		return false
+	}
+	if strings.HasPrefix(key, "\x1b[<") || strings.HasPrefix(key, "\x1b[M") {
+		b.handleMouse(key)
+		return false
+	}
+	if b.editing {
+		b.handleFilterKey(key)
+		return false
+	}
+
+	switch key {
+	case "q", "\x03":
+		return true
+	case "\x1b[A", "\x1bOA", "k":
+		b.renderer.SelectUp()
+	case "\x1b[B", "\x1bOB", "j":
+		b.renderer.SelectDown()
+	case "\x1b[C", "\x1bOC", "l":
+		if node := b.renderer.GetSelectedNode(); node != nil && !node.IsLeaf() && !node.Expanded {
+			b.toggle(node)
+		}
+	case "\x1b[D", "\x1bOD", "h":
+		b.collapseOrSelectParent()
+	case "\r", "\n", " ":
+		if node := b.renderer.GetSelectedNode(); node != nil && node.Type == nodeFlamegraph {
+			b.writeFlamegraph(b.owners[node])
+		} else if node != nil && !node.IsLeaf() {
+			b.toggle(node)
+		}
+	case "/":
+		b.editing = true
+	case "g":
+		b.writeFlamegraph(b.selectedSession())
+	case "e":
+		b.root.ExpandAll()
+		b.renderer.RenderTree(b.root)
+	case "c":
+		b.root.CollapseAll()
+		b.root.Expanded = true
+		b.renderer.RenderTree(b.root)
+		b.renderer.SelectRow(0)
+	case "?":
+		b.help = true
+	}
+	return false
+}
+
+// handleFilterKey edits the filter, which is applied as it is typed. Enter
+// keeps the filter and Esc clears it.
+func (b *Browser) handleFilterKey(key string) {
+	switch key {
+	case "\r", "\n":
+		b.editing = false
+	case "\x1b", "\x03":
+		b.editing = false
+		b.applyFilter("")
+	case "\x7f", "\b":
+		if b.filter != "" {
+			_, size := utf8.DecodeLastRuneInString(b.filter)
+			b.applyFilter(b.filter[:len(b.filter)-size])
+		}
+	default:
+		if r, _ := utf8.DecodeRuneInString(key); len(key) == utf8.RuneLen(r) && r >= ' ' && r != 0x7f {
+			b.applyFilter(b.filter + key)
+		}
+	}
+}
+
+// handleMouse scrolls on the wheel and selects or toggles the clicked row
+func (b *Browser) handleMouse(key string) {
+	sequence := key[2:]
+	if strings.HasPrefix(key, "\x1b[M") {
+		sequence = key[3:]
+	}
+	evt, err := trace.ParseMouseEvent(sequence)
+	if err != nil {
+		return
+	}
+	switch {
+	case evt.Button == trace.ScrollUp:
+		b.renderer.SelectUp()
+	case evt.Button == trace.ScrollDown:
+		b.renderer.SelectDown()
+	case evt.IsClickEvent():
+		// Rows start below the header and after the selection marker
+		if b.renderer.HandleMouseClick(evt.Col-2, evt.Row-headerRows) {
+			b.renderer.RenderTree(b.root)
+		}
+	}
+}
+
+// toggle expands or collapses node, which stays selected
+func (b *Browser) toggle(node *trace.TraceNode) {
+	node.ToggleExpanded()
+	b.renderer.RenderTree(b.root)
+}
+
+// collapseOrSelectParent collapses the selected node, or moves to its parent
+// when there is nothing to collapse
+func (b *Browser) collapseOrSelectParent() {
+	node := b.renderer.GetSelectedNode()
+	if node == nil {
+		return
+	}
+	if !node.IsLeaf() && node.Expanded && node != b.root {
+		b.toggle(node)
+		return
+	}
+	for i, ui := range b.renderer.GetAllNodes() {
+		if ui.Node == node.Parent {
+			b.renderer.SelectRow(i)
+			return
+		}
+	}
+}
+
+// selectedSession returns the session the selected node belongs to
+func (b *Browser) selectedSession() *session.SessionData {
+	for node := b.renderer.GetSelectedNode(); node != nil; node = node.Parent {
+		if s, ok := b.owners[node]; ok {
+			return s
+		}
+	}
+	return nil
+}
+
+// applyFilter narrows the session list to entries matching every word of
+// the filter, case-insensitively, and rebuilds the tree
+func (b *Browser) applyFilter(filter string) {
+	b.filter = filter
+	b.filtered = b.filtered[:0]
+	for _, s := range b.sessions {
+		if matchesFilter(s, b.filter) {
+			b.filtered = append(b.filtered, s)
+		}
+	}
+	b.buildTree()
+}
+
+func matchesFilter(s *session.SessionData, filter string) bool {
+	if strings.TrimSpace(filter) == "" {
+		return true
+	}
+
+	haystack := strings.ToLower(strings.Join([]string{
+		s.ID, s.TxHash, s.Network, s.Status, sessionError(s),
+	}, " "))
+
+	for _, term := range strings.Fields(strings.ToLower(filter)) {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// sessionError extracts the simulation error stored with a session, if any
+func sessionError(s *session.SessionData) string {
+	resp, err := s.ToSimulationResponse()
+	if err != nil {
+		return ""
+	}
+	return resp.Error
+}
+
+// buildTree lays the filtered sessions out as collapsed nodes under a root,
+// selecting the first session
+func (b *Browser) buildTree() {
+	b.root = trace.NewTraceNode("sessions", "sessions")
+	if b.filter != "" {
+		b.root.Type = fmt.Sprintf("%d of %d sessions", len(b.filtered), len(b.sessions))
+	} else {
+		b.root.Type = fmt.Sprintf("%d sessions", len(b.sessions))
+	}
+	b.owners = make(map[*trace.TraceNode]*session.SessionData)
+
+	for _, s := range b.filtered {
+		node := sessionNode(s)
+		b.root.AddChild(node)
+		b.owners[node] = s
+		for _, child := range node.Children {
+			if child.Type == nodeFlamegraph {
+				b.owners[child] = s
+			}
+		}
+	}
+
+	// Select the first session, or the root when there is none
+	b.renderer.RenderTree(b.root)
+	b.renderer.SelectRow(0)
+	b.renderer.SelectRow(1)
+}
+
+// sessionNode builds the subtree of one session: its transaction, and the
+// events, logs, and flamegraph of the simulation stored with it
+func sessionNode(s *session.SessionData) *trace.TraceNode {
+	node := trace.NewTraceNode(s.ID, s.Network)
+	node.Function = fmt.Sprintf("%s  %s", s.LastAccessAt.Format("2006-01-02 15:04"), s.ID)
+	node.Expanded = false
+
+	leaf := func(parent *trace.TraceNode, kind, text string) {
+		child := trace.NewTraceNode(fmt.Sprintf("%s/%s/%d", parent.ID, kind, len(parent.Children)), kind)
+		child.Function = text
+		parent.AddChild(child)
+	}
+	leaf(node, "transaction", s.TxHash)
+	leaf(node, "status", s.Status)
+	leaf(node, "created", s.CreatedAt.Format("2006-01-02 15:04:05"))
+
+	resp, err := s.ToSimulationResponse()
+	if err != nil {
+		leaf(node, "simulation", "no simulation results stored")
+		return node
+	}
+	node.Error = resp.Error
+	leaf(node, "simulation", resp.Status)
+
+	group := func(kind string, items []string, format func(string) string) {
+		if len(items) == 0 {
+			return
+		}
+		g := trace.NewTraceNode(fmt.Sprintf("%s/%s", node.ID, kind), fmt.Sprintf("%d %s", len(items), kind))
+		g.Expanded = false
+		node.AddChild(g)
+		for _, item := range items {
+			leaf(g, strings.TrimSuffix(kind, "s"), format(item))
+		}
+	}
+	group("events", resp.Events, decoder.FormatEvent)
+	group("logs", resp.Logs, func(l string) string { return l })
+
+	if resp.Flamegraph != "" {
+		leaf(node, nodeFlamegraph, flamegraphPath(s))
+	}
+	return node
+}
+
+func (b *Browser) render() {
+	var out strings.Builder
+	out.WriteString(clearScreen)
+	fmt.Fprintf(&out, "%s ERST Session History\n", visualizer.Symbol("magnify"))
+	switch {
+	case b.editing:
+		fmt.Fprintf(&out, "Filter: %s_\n", b.filter)
+	case b.filter != "":
+		fmt.Fprintf(&out, "Filter: %q (%d of %d sessions, / to edit)\n", b.filter, len(b.filtered), len(b.sessions))
+	default:
+		out.WriteString("Press / to filter by ID, hash, network, status or error\n")
+	}
+	out.WriteString(strings.Repeat("─", min(b.width, 60)) + "\n")
+
+	if b.help {
+		out.WriteString(helpText)
+	} else {
+		out.WriteString(b.renderer.Render())
+		if len(b.filtered) == 0 {
+			out.WriteString("No matching sessions.\n")
+		}
+	}
+
+	fmt.Fprintf(&out, "\n%s\n", b.status)
+	out.WriteString("↑↓ navigate  →← expand/collapse  Enter toggle  / filter  g flamegraph  ? help  q quit")
+	fmt.Fprint(b.out, out.String())
+}
+
+const helpText = `Keys:
+  ↑ ↓ / k j      Move the selection
+  → / l          Expand the selected node
+  ← / h          Collapse the selected node, or go to its parent
+  Enter, Space   Toggle the selected node, or write the selected flamegraph
+  e / c          Expand or collapse every session
+  /              Filter sessions as you type; Enter keeps it, Esc clears it
+  g              Write the flamegraph of the selected session
+  q, Ctrl+C      Exit
+
+Mouse: click a row to select it or its arrow to toggle it, scroll to move.
+
+Press any key to continue.
+`
+
+// flamegraphPath is where a session's flamegraph is written, in the working
+// directory so it can be opened in a browser
+func flamegraphPath(s *session.SessionData) string {
+	return filepath.Clean(s.ID + ".svg")
+}
+
+// writeFlamegraph saves the session's flamegraph SVG
+func (b *Browser) writeFlamegraph(s *session.SessionData) {
+	if s == nil {
+		return
+	}
+	var resp *simulator.SimulationResponse
+	if r, err := s.ToSimulationResponse(); err == nil {
+		resp = r
+	}
+	if resp == nil || resp.Flamegraph == "" {
+		b.status = "No flamegraph recorded for this session (re-run debug with --profile)."
+		return
+	}
+
+	path := flamegraphPath(s)
+	if err := os.WriteFile(path, []byte(resp.Flamegraph), 0644); err != nil {
+		b.status = fmt.Sprintf("%s failed to write flamegraph: %v", visualizer.Error(), err)
+		return
+	}
+	b.status = fmt.Sprintf("Flamegraph written to %s", path)
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package history
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/session"
+)
+
+func testSessions() []*session.SessionData {
+	now := time.Now()
+	return []*session.SessionData{
+		{
+			ID:              "abc12345-1",
+			TxHash:          "abc12345deadbeef",
+			Network:         "testnet",
+			Status:          "saved",
+			LastAccessAt:    now,
+			SimResponseJSON: `{"status":"error","error":"HostError: insufficient balance","events":["ev1","ev2"],"logs":["log1"]}`,
+		},
+		{
+			ID:              "ffee0011-2",
+			TxHash:          "ffee0011cafebabe",
+			Network:         "mainnet",
+			Status:          "saved",
+			LastAccessAt:    now,
+			SimResponseJSON: `{"status":"success"}`,
+		},
+	}
+}
+
+func TestMatchesFilter(t *testing.T) {
+	sessions := testSessions()
+
+	tests := []struct {
+		filter string
+		want   []bool
+	}{
+		{"", []bool{true, true}},
+		{"testnet", []bool{true, false}},
+		{"MAINNET", []bool{false, true}},
+		{"insufficient", []bool{true, false}},
+		{"testnet balance", []bool{true, false}},
+		{"testnet cafebabe", []bool{false, false}},
+	}
+
+	for _, tt := range tests {
+		for i, s := range sessions {
+			if got := matchesFilter(s, tt.filter); got != tt.want[i] {
+				t.Errorf("matchesFilter(%q, %q) = %v, want %v", s.ID, tt.filter, got, tt.want[i])
+			}
+		}
+	}
+}
+
+// keyReader returns one chunk of keys per Read, as a terminal in raw mode
+// delivers them
+type keyReader struct {
+	chunks []string
+}
+
+func (r *keyReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	return n, nil
+}
+
+// lastFrame returns the screen the browser drew last
+func lastFrame(out string) string {
+	return out[strings.LastIndex(out, clearScreen)+len(clearScreen):]
+}
+
+func TestSplitKeys(t *testing.T) {
+	got := splitKeys([]byte("j\x1b[A\x1b[<0;5;7M/é\x1b"))
+	want := []string{"j", "\x1b[A", "\x1b[<0;5;7M", "/", "é", "\x1b"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("splitKeys = %q, want %q", got, want)
+	}
+}
+
+func TestBrowserFilterAndExpand(t *testing.T) {
+	var out bytes.Buffer
+	keys := &keyReader{}
+	b := NewBrowser(testSessions(), keys, &out, 100, 30)
+
+	// The filter applies as it is typed
+	keys.chunks = []string{"/", "i", "nsufficient"}
+	if err := b.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	frame := lastFrame(out.String())
+	if !strings.Contains(frame, "Filter: insufficient_") || !strings.Contains(frame, "[1 of 2 sessions]") {
+		t.Errorf("filtered frame:\n%s", frame)
+	}
+	if strings.Contains(frame, "ffee0011-2") {
+		t.Error("expected the mainnet session to be filtered out")
+	}
+
+	// Enter keeps the filter; the right arrow expands the session, and
+	// Enter on the events opens them
+	keys.chunks = []string{"\r", "\x1b[C", "jjjjj", "\r"}
+	if err := b.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	frame = lastFrame(out.String())
+	for _, want := range []string{
+		`Filter: "insufficient" (1 of 2 sessions`,
+		"abc12345-1 (testnet) [ERROR: HostError: insufficient balance]",
+		"abc12345deadbeef (transaction)",
+		"[2 events]",
+		"ev2 (event)",
+		"[1 logs]",
+	} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("expected the frame to contain %q:\n%s", want, frame)
+		}
+	}
+	if strings.Contains(frame, "log1") {
+		t.Error("expected the logs to stay collapsed")
+	}
+	if node := b.renderer.GetSelectedNode(); node == nil || node.Type != "2 events" {
+		t.Errorf("selected %+v, want the events", node)
+	}
+
+	// Left collapses the events and goes back up to the session, and Esc
+	// in the filter clears it
+	keys.chunks = []string{"\x1b[D\x1b[D", "/\x1b", "q", "j"}
+	if err := b.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(keys.chunks) != 1 {
+		t.Error("expected q to quit")
+	}
+	if b.filter != "" || len(b.filtered) != 2 {
+		t.Errorf("filter = %q with %d sessions, want it cleared", b.filter, len(b.filtered))
+	}
+}
+
+func TestBrowserFlamegraph(t *testing.T) {
+	t.Chdir(t.TempDir())
+	sessions := testSessions()
+	sessions[1].SimResponseJSON = `{"status":"success","flamegraph":"<svg/>"}`
+
+	var out bytes.Buffer
+	keys := &keyReader{chunks: []string{"g", "j", "g"}}
+	b := NewBrowser(sessions, keys, &out, 100, 30)
+	if err := b.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "No flamegraph recorded for this session") {
+		t.Error("expected a message for the session without a flamegraph")
+	}
+	if !strings.Contains(lastFrame(out.String()), "Flamegraph written to ffee0011-2.svg") {
+		t.Errorf("frame:\n%s", lastFrame(out.String()))
+	}
+	if data, err := os.ReadFile("ffee0011-2.svg"); err != nil || string(data) != "<svg/>" {
+		t.Errorf("flamegraph = %q, %v", data, err)
+	}
+}
+
+func TestBrowserMouse(t *testing.T) {
+	var out bytes.Buffer
+	// Click the arrow of the second session, on the fifth screen row
+	keys := &keyReader{chunks: []string{"\x1b[<0;5;6M"}}
+	b := NewBrowser(testSessions(), keys, &out, 100, 30)
+	if err := b.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if node := b.root.Children[1]; !node.Expanded {
+		t.Error("expected the click to expand the second session")
+	}
+	if b.root.Children[0].Expanded {
+		t.Error("expected the first session to stay collapsed")
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package history
+
+import "os"
+
+// MakeRaw leaves the terminal as it is on this platform, so keys reach the
+// browser once Enter is pressed
+func MakeRaw(f *os.File) (func(), error) {
+	return func() {}, nil
+}
+
+// TerminalSize returns the default 80 by 24 screen on this platform
+func TerminalSize(f *os.File) (int, int) {
+	return 80, 24
+}
@@ -0,0 +1,168 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package hooks runs user-supplied post-simulation scripts. A hook is an
+// executable or a WebAssembly module: it reads an Input as JSON and may
+// write a Result as JSON to annotate the simulation, print extra output or
+// fail the command. Executables use stdin and stdout; modules run sandboxed,
+// see runWASM.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// APIVersion is the version of the Input and Result documents
+const APIVersion = "1.0.0"
+
+// DefaultTimeout bounds a single hook run
+const DefaultTimeout = 30 * time.Second
+
+// Annotation levels
+const (
+	LevelInfo    = "info"
+	LevelWarning = "warning"
+	LevelError   = "error"
+)
+
+// Input is the document a hook reads on stdin
+type Input struct {
+	APIVersion  string                        `json:"api_version"`
+	Command     string                        `json:"command"`
+	Network     string                        `json:"network"`
+	TxHash      string                        `json:"tx_hash"`
+	EnvelopeXdr string                        `json:"envelope_xdr,omitempty"`
+	Invocations []contractspec.Invocation     `json:"invocations,omitempty"`
+	Simulation  *simulator.SimulationResponse `json:"simulation"`
+}
+
+// Annotation is a note a hook attaches to the simulation
+type Annotation struct {
+	// Hook is the file name of the hook that added it, set by Run
+	Hook    string `json:"hook,omitempty"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// Result is what a hook writes on stdout. Every field is optional and an
+// empty stdout is the same as an empty Result.
+type Result struct {
+	Annotations []Annotation `json:"annotations,omitempty"`
+	// Output is printed after the simulation summary
+	Output string `json:"output,omitempty"`
+	// Fail makes the command exit with the check-failed status, giving Reason
+	Fail   bool   `json:"fail,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Hook is one post-simulation executable, or module when Path ends in .wasm
+type Hook struct {
+	Path    string
+	Timeout time.Duration
+}
+
+// Name is the hook's file name, used to attribute its annotations
+func (h Hook) Name() string {
+	return filepath.Base(h.Path)
+}
+
+// Run executes the hook on in. A hook that exits non-zero or traps, times
+// out or writes something other than a Result is an error.
+func (h Hook) Run(ctx context.Context, in Input) (*Result, error) {
+	in.APIVersion = APIVersion
+	input, err := json.Marshal(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode hook input: %w", err)
+	}
+
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	run := runExecutable
+	if isWASM(h.Path) {
+		run = runWASM
+	}
+	output, diagnostics, err := run(ctx, h.Path, input)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("hook %s timed out after %s", h.Name(), timeout)
+		}
+		if msg := strings.TrimSpace(diagnostics); msg != "" {
+			return nil, fmt.Errorf("hook %s failed: %w: %s", h.Name(), err, msg)
+		}
+		return nil, fmt.Errorf("hook %s failed: %w", h.Name(), err)
+	}
+
+	result := &Result{}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return result, nil
+	}
+	if err := json.Unmarshal(output, result); err != nil {
+		return nil, fmt.Errorf("hook %s wrote invalid output: %w", h.Name(), err)
+	}
+	for i := range result.Annotations {
+		a := &result.Annotations[i]
+		a.Hook = h.Name()
+		switch a.Level {
+		case LevelInfo, LevelWarning, LevelError:
+		default:
+			a.Level = LevelInfo
+		}
+	}
+	return result, nil
+}
+
+// runExecutable runs the executable at path with input on stdin and returns
+// its stdout and stderr
+func runExecutable(ctx context.Context, path string, input []byte) ([]byte, string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.String(), err
+}
+
+// RunAll runs the hooks in order and merges their results: annotations and
+// output are concatenated, and the command fails if any hook fails it. The
+// first hook error stops the run.
+func RunAll(ctx context.Context, hooks []Hook, in Input) (*Result, error) {
+	merged := &Result{}
+	var outputs, reasons []string
+	for _, h := range hooks {
+		res, err := h.Run(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		merged.Annotations = append(merged.Annotations, res.Annotations...)
+		if res.Output != "" {
+			outputs = append(outputs, strings.TrimRight(res.Output, "\n"))
+		}
+		if res.Fail {
+			merged.Fail = true
+			reason := res.Reason
+			if reason == "" {
+				reason = "failed by " + h.Name()
+			}
+			reasons = append(reasons, reason)
+		}
+	}
+	merged.Output = strings.Join(outputs, "\n")
+	merged.Reason = strings.Join(reasons, "; ")
+	return merged, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+func writeHook(t *testing.T, name, script string) Hook {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return Hook{Path: path}
+}
+
+func TestRunAll(t *testing.T) {
+	// Fails the transaction when the simulation errored
+	triage := writeHook(t, "triage.sh", `read input
+case "$input" in
+  *'"status":"error"'*) echo '{"annotations":[{"level":"error","message":"owned by payments"},{"level":"bogus","message":"x"}],"fail":true,"reason":"payments regression"}' ;;
+  *) echo '{"annotations":[{"level":"info","message":"ok"}]}' ;;
+esac
+`)
+	extra := writeHook(t, "extra.sh", "cat >/dev/null\nprintf '%s\\n' '{\"output\":\"see runbook\\n\"}'\n")
+	silent := writeHook(t, "silent.sh", "cat >/dev/null\n")
+
+	ctx := context.Background()
+	in := Input{Command: "debug", Network: "testnet", TxHash: "abc", Simulation: &simulator.SimulationResponse{Status: "error", Error: "boom"}}
+	res, err := RunAll(ctx, []Hook{triage, extra, silent}, in)
+	if err != nil {
+		t.Fatalf("RunAll: %v", err)
+	}
+	if !res.Fail || res.Reason != "payments regression" || res.Output != "see runbook" {
+		t.Errorf("result = %+v", res)
+	}
+	if len(res.Annotations) != 2 || res.Annotations[0].Hook != "triage.sh" || res.Annotations[0].Level != LevelError ||
+		res.Annotations[1].Level != LevelInfo {
+		t.Errorf("annotations = %+v", res.Annotations)
+	}
+
+	in.Simulation = &simulator.SimulationResponse{Status: "success"}
+	res, err = RunAll(ctx, []Hook{triage}, in)
+	if err != nil {
+		t.Fatalf("RunAll: %v", err)
+	}
+	if res.Fail || len(res.Annotations) != 1 || res.Annotations[0].Message != "ok" {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	ctx := context.Background()
+	in := Input{Simulation: &simulator.SimulationResponse{Status: "success"}}
+
+	crash := writeHook(t, "crash.sh", "echo 'no such team' >&2\nexit 3\n")
+	if _, err := crash.Run(ctx, in); err == nil || !strings.Contains(err.Error(), "no such team") {
+		t.Errorf("crash error = %v", err)
+	}
+
+	garbage := writeHook(t, "garbage.sh", "cat >/dev/null\necho 'not json'\n")
+	if _, err := garbage.Run(ctx, in); err == nil || !strings.Contains(err.Error(), "invalid output") {
+		t.Errorf("garbage error = %v", err)
+	}
+
+	slow := writeHook(t, "slow.sh", "exec sleep 5\n")
+	slow.Timeout = 100 * time.Millisecond
+	if _, err := slow.Run(ctx, in); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("slow error = %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/wasmvm"
+)
+
+// WASM hooks run in an embedded interpreter with no access to the file
+// system, network or environment. The module has a memory and exports:
+//
+//	erst_alloc(len: i32) -> i32             memory for the input
+//	erst_hook(ptr: i32, len: i32) -> i64    the result as ptr<<32 | len
+//
+// erst_hook receives the Input JSON and returns the location of the Result
+// JSON in memory, or 0 for an empty result. The module may import
+// erst.log(ptr: i32, len: i32), whose messages are reported if the hook
+// fails, like the stderr of an executable hook.
+const (
+	wasmAlloc = "erst_alloc"
+	wasmHook  = "erst_hook"
+)
+
+// isWASM reports whether the hook at path is a WebAssembly module
+func isWASM(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".wasm")
+}
+
+// runWASM runs the module at path on input and returns the result it
+// writes, with its log messages
+func runWASM(ctx context.Context, path string, input []byte) ([]byte, string, error) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	m, err := wasmvm.Decode(code)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid module: %w", err)
+	}
+	for _, export := range []struct {
+		name string
+		typ  wasmvm.FuncType
+	}{
+		{wasmAlloc, wasmvm.FuncType{Params: []byte{wasmvm.I32}, Results: []byte{wasmvm.I32}}},
+		{wasmHook, wasmvm.FuncType{Params: []byte{wasmvm.I32, wasmvm.I32}, Results: []byte{wasmvm.I64}}},
+	} {
+		if t, ok := m.ExportedFunc(export.name); !ok || t.String() != export.typ.String() {
+			return nil, "", fmt.Errorf("module must export %s %s", export.name, export.typ)
+		}
+	}
+
+	var logs bytes.Buffer
+	inst, err := wasmvm.Instantiate(ctx, m, wasmvm.Config{Imports: map[string]wasmvm.HostFunc{
+		"erst.log": {
+			Type: wasmvm.FuncType{Params: []byte{wasmvm.I32, wasmvm.I32}},
+			Fn: func(_ context.Context, inst *wasmvm.Instance, args []uint64) ([]uint64, error) {
+				msg, ok := inst.Read(uint32(args[0]), uint32(args[1]))
+				if !ok {
+					return nil, errors.New("erst.log: message is out of bounds")
+				}
+				logs.Write(msg)
+				logs.WriteByte('\n')
+				return nil, nil
+			},
+		},
+	}})
+	if err != nil {
+		return nil, "", err
+	}
+
+	res, err := inst.Call(ctx, wasmAlloc, uint64(len(input)))
+	if err != nil {
+		return nil, logs.String(), err
+	}
+	ptr := uint32(res[0])
+	if !inst.Write(ptr, input) {
+		return nil, logs.String(), fmt.Errorf("%s returned memory out of bounds", wasmAlloc)
+	}
+	res, err = inst.Call(ctx, wasmHook, uint64(ptr), uint64(len(input)))
+	if err != nil {
+		return nil, logs.String(), err
+	}
+	if res[0] == 0 {
+		return nil, logs.String(), nil
+	}
+	out, ok := inst.Read(uint32(res[0]>>32), uint32(res[0]))
+	if !ok {
+		return nil, logs.String(), fmt.Errorf("%s returned a result out of bounds", wasmHook)
+	}
+	return out, logs.String(), nil
+}
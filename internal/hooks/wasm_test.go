@@ -0,0 +1,126 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+func leb(v uint64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if v == 0 {
+			return out
+		}
+	}
+}
+
+// sleb encodes a positive value as a signed LEB128 number
+func sleb(v uint64) []byte {
+	out := leb(v)
+	if out[len(out)-1]&0x40 != 0 {
+		out[len(out)-1] |= 0x80
+		out = append(out, 0)
+	}
+	return out
+}
+
+func wasmSection(id byte, items ...[]byte) []byte {
+	var payload []byte
+	payload = append(payload, leb(uint64(len(items)))...)
+	for _, item := range items {
+		payload = append(payload, item...)
+	}
+	return append(append([]byte{id}, leb(uint64(len(payload)))...), payload...)
+}
+
+func wasmName(s string) []byte {
+	return append(leb(uint64(len(s))), s...)
+}
+
+// writeWASMHook writes a module whose erst_hook logs "checking", runs body
+// and returns result, which is stored in memory at 1024
+func writeWASMHook(t *testing.T, name string, result string, body ...byte) Hook {
+	t.Helper()
+	resultAt := uint64(1024)<<32 | uint64(len(result))
+	hook := append([]byte{0x00, 0x41, 0x80, 0x10, 0x41, 8, 0x10, 0}, body...)
+	hook = append(append(append(hook, 0x42), sleb(resultAt)...), 0x0b)
+	alloc := []byte{0x00, 0x41, 0x80, 0x20, 0x0b}
+
+	wasm := []byte("\x00asm\x01\x00\x00\x00")
+	wasm = append(wasm, wasmSection(1,
+		[]byte{0x60, 1, 0x7f, 1, 0x7f},
+		[]byte{0x60, 2, 0x7f, 0x7f, 1, 0x7e},
+		[]byte{0x60, 2, 0x7f, 0x7f, 0},
+	)...)
+	wasm = append(wasm, wasmSection(2, append(append(wasmName("erst"), wasmName("log")...), 0, 2))...)
+	wasm = append(wasm, wasmSection(3, []byte{0}, []byte{1})...)
+	wasm = append(wasm, wasmSection(5, []byte{0, 1})...)
+	wasm = append(wasm, wasmSection(7,
+		append(wasmName("erst_alloc"), 0, 1),
+		append(wasmName("erst_hook"), 0, 2),
+	)...)
+	wasm = append(wasm, wasmSection(10,
+		append(leb(uint64(len(alloc))), alloc...),
+		append(leb(uint64(len(hook))), hook...),
+	)...)
+	wasm = append(wasm, wasmSection(11,
+		append([]byte{0, 0x41, 0x80, 0x08, 0x0b}, wasmName(result)...),
+		append([]byte{0, 0x41, 0x80, 0x10, 0x0b}, wasmName("checking")...),
+	)...)
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, wasm, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Hook{Path: path}
+}
+
+func TestRunWASM(t *testing.T) {
+	ctx := context.Background()
+	in := Input{Simulation: &simulator.SimulationResponse{Status: "error", Error: "boom"}}
+
+	triage := writeWASMHook(t, "triage.wasm", `{"annotations":[{"level":"warning","message":"from wasm"}],"fail":true,"reason":"wasm says no"}`)
+	res, err := triage.Run(ctx, in)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !res.Fail || res.Reason != "wasm says no" || len(res.Annotations) != 1 || res.Annotations[0].Hook != "triage.wasm" {
+		t.Errorf("result = %+v", res)
+	}
+
+	// unreachable traps, and the log is reported with the error
+	crash := writeWASMHook(t, "crash.wasm", "{}", 0x00)
+	if _, err := crash.Run(ctx, in); err == nil || !strings.Contains(err.Error(), "unreachable") || !strings.Contains(err.Error(), "checking") {
+		t.Errorf("crash error = %v", err)
+	}
+
+	// An endless loop is stopped by the timeout
+	spin := writeWASMHook(t, "spin.wasm", "{}", 0x03, 0x40, 0x0c, 0, 0x0b)
+	spin.Timeout = 100 * time.Millisecond
+	if _, err := spin.Run(ctx, in); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("spin error = %v", err)
+	}
+
+	bogus := filepath.Join(t.TempDir(), "bogus.wasm")
+	if err := os.WriteFile(bogus, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (Hook{Path: bogus}).Run(ctx, in); err == nil || !strings.Contains(err.Error(), "invalid module") {
+		t.Errorf("bogus error = %v", err)
+	}
+}
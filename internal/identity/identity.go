@@ -0,0 +1,259 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package identity reads the identities managed by the stellar CLI
+// (`stellar keys generate alice`) so accounts can be referred to by name.
+package identity
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/strkey"
+)
+
+// Identity is a named account from the stellar CLI configuration
+type Identity struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	// Path is the identity file the account was read from
+	Path string `json:"path"`
+}
+
+// Dirs returns the identity directories in precedence order: the project's
+// .stellar (or legacy .soroban) directory nearest to the working directory,
+// then the global stellar and soroban config directories. Directories that do
+// not exist are included; Load skips them.
+func Dirs() []string {
+	var dirs []string
+	if wd, err := os.Getwd(); err == nil {
+		for dir := wd; ; dir = filepath.Dir(dir) {
+			local := ""
+			for _, name := range []string{".stellar", ".soroban"} {
+				if info, err := os.Stat(filepath.Join(dir, name)); err == nil && info.IsDir() {
+					local = filepath.Join(dir, name, "identity")
+					break
+				}
+			}
+			if local != "" {
+				dirs = append(dirs, local)
+				break
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+
+	if home := os.Getenv("STELLAR_CONFIG_HOME"); home != "" {
+		return append(dirs, filepath.Join(home, "identity"))
+	}
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return dirs
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return append(dirs,
+		filepath.Join(configHome, "stellar", "identity"),
+		filepath.Join(configHome, "soroban", "identity"),
+	)
+}
+
+// List returns every identity found in Dirs, sorted by name. An identity
+// defined in several directories is taken from the first, and files that
+// cannot be read are skipped with a warning.
+func List() ([]Identity, error) {
+	return listDirs(Dirs())
+}
+
+func listDirs(dirs []string) ([]Identity, error) {
+	seen := map[string]bool{}
+	var out []Identity
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range files {
+			name := strings.TrimSuffix(filepath.Base(path), ".toml")
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			id, err := loadFile(name, path)
+			if err != nil {
+				logger.Logger.Warn("Skipping unreadable identity", "error", err)
+				continue
+			}
+			out = append(out, *id)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Load reads the identity with the given name
+func Load(name string) (*Identity, error) {
+	return loadDirs(Dirs(), name)
+}
+
+func loadDirs(dirs []string, name string) (*Identity, error) {
+	path, err := findFile(dirs, name)
+	if err != nil {
+		return nil, err
+	}
+	return loadFile(name, path)
+}
+
+// Keypair loads the signing key of the identity with the given name. Only
+// identities stored as a secret_key or seed_phrase can sign.
+func Keypair(name string) (*keypair.Full, error) {
+	return keypairDirs(Dirs(), name)
+}
+
+func keypairDirs(dirs []string, name string) (*keypair.Full, error) {
+	path, err := findFile(dirs, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := parseTOML(string(data))
+
+	if secret := values["secret_key"]; secret != "" {
+		kp, err := keypair.ParseFull(secret)
+		if err != nil {
+			return nil, fmt.Errorf("identity %q (%s): invalid secret_key: %w", name, path, err)
+		}
+		return kp, nil
+	}
+	if values["seed_phrase"] != "" {
+		kp, err := seedPhraseKeypair(values)
+		if err != nil {
+			return nil, fmt.Errorf("identity %q (%s): %w", name, path, err)
+		}
+		return kp, nil
+	}
+	return nil, fmt.Errorf("identity %q (%s) has no secret key; keys in the OS secure store cannot be read", name, path)
+}
+
+func findFile(dirs []string, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid identity name %q", name)
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(dir, name+".toml")
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("identity %q not found in %s", name, strings.Join(dirs, ", "))
+}
+
+// Resolve returns the account address for s, which is either an account
+// address (G... or M...) or the name of an identity
+func Resolve(s string) (string, error) {
+	if strkey.IsValidEd25519PublicKey(s) || strkey.IsValidMuxedAccountEd25519PublicKey(s) {
+		return s, nil
+	}
+	id, err := Load(s)
+	if err != nil {
+		return "", err
+	}
+	return id.Address, nil
+}
+
+// Names maps the address of every identity to its name, for labelling
+// addresses in output. Identities that cannot be read are left out.
+func Names() map[string]string {
+	ids, err := List()
+	if err != nil {
+		return map[string]string{}
+	}
+	names := make(map[string]string, len(ids))
+	for _, id := range ids {
+		names[id.Address] = id.Name
+	}
+	return names
+}
+
+// loadFile parses an identity file. The stellar CLI writes one of
+// public_key, secret_key or seed_phrase (with an optional hd_path index);
+// keys held in the OS secure store also record their public_key.
+func loadFile(name, path string) (*Identity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := parseTOML(string(data))
+
+	address, err := addressFromValues(values)
+	if err != nil {
+		return nil, fmt.Errorf("identity %q (%s): %w", name, path, err)
+	}
+	return &Identity{Name: name, Address: address, Path: path}, nil
+}
+
+func addressFromValues(values map[string]string) (string, error) {
+	if pub := values["public_key"]; pub != "" {
+		if !strkey.IsValidEd25519PublicKey(pub) {
+			return "", fmt.Errorf("invalid public_key %q", pub)
+		}
+		return pub, nil
+	}
+	if secret := values["secret_key"]; secret != "" {
+		kp, err := keypair.ParseFull(secret)
+		if err != nil {
+			return "", fmt.Errorf("invalid secret_key: %w", err)
+		}
+		return kp.Address(), nil
+	}
+	if values["seed_phrase"] != "" {
+		kp, err := seedPhraseKeypair(values)
+		if err != nil {
+			return "", err
+		}
+		return kp.Address(), nil
+	}
+	return "", fmt.Errorf("no public_key, secret_key or seed_phrase")
+}
+
+func seedPhraseKeypair(values map[string]string) (*keypair.Full, error) {
+	var index uint32
+	if raw := values["hd_path"]; raw != "" {
+		n, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hd_path %q", raw)
+		}
+		index = uint32(n)
+	}
+	return FromSeedPhrase(values["seed_phrase"], index)
+}
+
+// parseTOML reads the flat key = "value" pairs of an identity file
+func parseTOML(content string) map[string]string {
+	values := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		values[strings.TrimSpace(parts[0])] = strings.Trim(strings.TrimSpace(parts[1]), "\"'")
+	}
+	return values
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SEP-5 test vector 1
+const testPhrase = "illness spike retreat truth genius clock brain pass fit cave bargain toe"
+
+func writeIdentity(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".toml"), []byte(content), 0o600))
+}
+
+func TestFromSeedPhrase(t *testing.T) {
+	kp, err := FromSeedPhrase(testPhrase, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "GDRXE2BQUC3AZNPVFSCEZ76NJ3WWL25FYFK6RGZGIEKWE4SOOHSUJUJ6", kp.Address())
+
+	kp, err = FromSeedPhrase(testPhrase, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "GBAW5XGWORWVFE2XTJYDTLDHXTY2Q2MO73HYCGB3XMFMQ562Q2W2GJQX", kp.Address())
+
+	_, err = FromSeedPhrase("too short", 0)
+	assert.Error(t, err)
+}
+
+func TestLoad_KeyFormats(t *testing.T) {
+	dir := t.TempDir()
+	full := keypair.MustRandom()
+	pub := keypair.MustRandom().Address()
+
+	writeIdentity(t, dir, "alice", "secret_key = \""+full.Seed()+"\"\n")
+	writeIdentity(t, dir, "bob", "# watched account\npublic_key = \""+pub+"\"\n")
+	writeIdentity(t, dir, "carol", "seed_phrase = \""+testPhrase+"\"\nhd_path = 1\n")
+	writeIdentity(t, dir, "broken", "network = \"testnet\"\n")
+
+	id, err := loadDirs([]string{dir}, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, full.Address(), id.Address)
+	assert.Equal(t, filepath.Join(dir, "alice.toml"), id.Path)
+
+	id, err = loadDirs([]string{dir}, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, pub, id.Address)
+
+	id, err = loadDirs([]string{dir}, "carol")
+	require.NoError(t, err)
+	assert.Equal(t, "GBAW5XGWORWVFE2XTJYDTLDHXTY2Q2MO73HYCGB3XMFMQ562Q2W2GJQX", id.Address)
+
+	kp, err := keypairDirs([]string{dir}, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, full.Seed(), kp.Seed())
+	kp, err = keypairDirs([]string{dir}, "carol")
+	require.NoError(t, err)
+	assert.Equal(t, "GBAW5XGWORWVFE2XTJYDTLDHXTY2Q2MO73HYCGB3XMFMQ562Q2W2GJQX", kp.Address())
+	_, err = keypairDirs([]string{dir}, "bob")
+	assert.ErrorContains(t, err, "no secret key")
+
+	_, err = loadDirs([]string{dir}, "broken")
+	assert.ErrorContains(t, err, "no public_key")
+
+	_, err = loadDirs([]string{dir}, "dave")
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = loadDirs([]string{dir}, "../alice")
+	assert.Error(t, err)
+}
+
+func TestList_Precedence(t *testing.T) {
+	local, global := t.TempDir(), t.TempDir()
+	a, b := keypair.MustRandom(), keypair.MustRandom()
+	writeIdentity(t, local, "alice", "public_key = \""+a.Address()+"\"\n")
+	writeIdentity(t, global, "alice", "public_key = \""+b.Address()+"\"\n")
+	writeIdentity(t, global, "bob", "public_key = \""+b.Address()+"\"\n")
+	writeIdentity(t, global, "broken", "public_key = \"nope\"\n")
+
+	ids, err := listDirs([]string{local, global, filepath.Join(global, "missing")})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	assert.Equal(t, "alice", ids[0].Name)
+	assert.Equal(t, a.Address(), ids[0].Address, "the local identity wins")
+	assert.Equal(t, "bob", ids[1].Name, "an unreadable identity is skipped")
+}
+
+func TestResolve(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("STELLAR_CONFIG_HOME", home)
+	t.Chdir(t.TempDir())
+
+	kp := keypair.MustRandom()
+	writeIdentity(t, filepath.Join(home, "identity"), "alice", "secret_key = \""+kp.Seed()+"\"\n")
+
+	addr, err := Resolve("alice")
+	require.NoError(t, err)
+	assert.Equal(t, kp.Address(), addr)
+
+	other := keypair.MustRandom().Address()
+	addr, err = Resolve(other)
+	require.NoError(t, err)
+	assert.Equal(t, other, addr, "addresses pass through")
+
+	_, err = Resolve("nobody")
+	assert.Error(t, err)
+
+	assert.Equal(t, map[string]string{kp.Address(): "alice"}, Names())
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+)
+
+// FromSeedPhrase derives the SEP-5 key m/44'/148'/index' from a BIP-39
+// mnemonic, as the stellar CLI does for seed_phrase identities. The phrase
+// is used as written; the CLI only generates ASCII English mnemonics.
+func FromSeedPhrase(phrase string, index uint32) (*keypair.Full, error) {
+	words := strings.Fields(phrase)
+	if len(words) < 12 {
+		return nil, fmt.Errorf("seed_phrase has %d words, expected at least 12", len(words))
+	}
+	seed, err := pbkdf2.Key(sha512.New, strings.Join(words, " "), []byte("mnemonic"), 2048, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive seed: %w", err)
+	}
+
+	// SLIP-10 ed25519 derivation; every level is hardened
+	mac := hmac.New(sha512.New, []byte("ed25519 seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chain := sum[:32], sum[32:]
+	for _, i := range []uint32{44, 148, index} {
+		data := make([]byte, 0, 37)
+		data = append(data, 0)
+		data = append(data, key...)
+		data = binary.BigEndian.AppendUint32(data, i|0x80000000)
+		mac := hmac.New(sha512.New, chain)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+		key, chain = sum[:32], sum[32:]
+	}
+
+	var raw [32]byte
+	copy(raw[:], key)
+	return keypair.FromRawSeed(raw)
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package localnet sets up accounts on standalone networks, which have no
+// friendbot. Every network's root account holds the initial lumen supply and
+// its key is derived from the passphrase, so on a local network it can fund
+// accounts directly.
+package localnet
+
+import (
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// StroopsPerLumen converts lumen amounts to the stroops used on the ledger
+const StroopsPerLumen = 10_000_000
+
+// DefaultStartingBalance is the balance given to each new account, in lumens
+const DefaultStartingBalance = 10_000
+
+// BaseFee is the fee paid per operation, in stroops
+const BaseFee = 100
+
+// Root returns the root account of the network with passphrase
+func Root(passphrase string) *keypair.Full {
+	return keypair.Root(passphrase)
+}
+
+// CreateAccounts returns an unsigned transaction in which source, at
+// sequence number seq, creates each account with startingBalance stroops
+func CreateAccounts(source xdr.AccountId, seq int64, accounts []xdr.AccountId, startingBalance int64) (xdr.TransactionEnvelope, error) {
+	if len(accounts) == 0 {
+		return xdr.TransactionEnvelope{}, fmt.Errorf("no accounts to create")
+	}
+	if startingBalance <= 0 {
+		return xdr.TransactionEnvelope{}, fmt.Errorf("starting balance must be positive")
+	}
+
+	ops := make([]xdr.Operation, len(accounts))
+	for i, account := range accounts {
+		ops[i] = xdr.Operation{Body: xdr.OperationBody{
+			Type: xdr.OperationTypeCreateAccount,
+			CreateAccountOp: &xdr.CreateAccountOp{
+				Destination:     account,
+				StartingBalance: xdr.Int64(startingBalance),
+			},
+		}}
+	}
+
+	return xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: source.ToMuxedAccount(),
+			Fee:           xdr.Uint32(BaseFee * len(ops)),
+			SeqNum:        xdr.SequenceNumber(seq),
+			Cond:          xdr.Preconditions{Type: xdr.PreconditionTypePrecondNone},
+			Operations:    ops,
+		}},
+	}, nil
+}
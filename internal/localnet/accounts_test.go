@@ -0,0 +1,42 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package localnet
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestRoot(t *testing.T) {
+	// The root account of the quickstart image's standalone network
+	root := Root("Standalone Network ; February 2017")
+	if root.Address() != "GBZXN7PIRZGNMHGA7MUUUF4GWPY5AYPV6LY4UV2GL6VJGIQRXFDNMADI" {
+		t.Errorf("root = %s", root.Address())
+	}
+}
+
+func TestCreateAccounts(t *testing.T) {
+	root := Root("Test Local Network")
+	alice := xdr.MustAddress(keypair.MustRandom().Address())
+	bob := xdr.MustAddress(keypair.MustRandom().Address())
+
+	env, err := CreateAccounts(xdr.MustAddress(root.Address()), 8, []xdr.AccountId{alice, bob}, DefaultStartingBalance*StroopsPerLumen)
+	if err != nil {
+		t.Fatalf("CreateAccounts: %v", err)
+	}
+	if env.SeqNum() != 8 || env.Fee() != 2*BaseFee || env.SourceAccount().ToAccountId().Address() != root.Address() {
+		t.Errorf("unexpected transaction: seq %d, fee %d", env.SeqNum(), env.Fee())
+	}
+	ops := env.Operations()
+	if len(ops) != 2 || ops[1].Body.CreateAccountOp.Destination.Address() != bob.Address() ||
+		ops[0].Body.CreateAccountOp.StartingBalance != 10_000*StroopsPerLumen {
+		t.Errorf("unexpected operations: %+v", ops)
+	}
+
+	if _, err := CreateAccounts(xdr.MustAddress(root.Address()), 8, nil, 1); err == nil {
+		t.Error("expected an error for no accounts")
+	}
+}
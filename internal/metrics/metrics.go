@@ -0,0 +1,199 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics exposes Prometheus metrics for long-running erst modes such
+// as serve, daemon and watch.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "erst"
+
+var registry = prometheus.NewRegistry()
+
+var (
+	simulationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "simulations_total",
+		Help:      "Simulations run, by result status (success, error or crash).",
+	}, []string{"status"})
+
+	simulationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "simulation_failures_total",
+		Help:      "Failed simulations, by Soroban host error class.",
+	}, []string{"class"})
+
+	simulatorDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "simulator_duration_seconds",
+		Help:      "Wall-clock runtime of the simulator binary.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	})
+
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rpc_request_duration_seconds",
+		Help:      "Latency of HTTP requests to Soroban RPC and Horizon, by host and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"host", "code"})
+
+	sessionSessions = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "session_db", "sessions"),
+		"Number of sessions in the session store.", nil, nil)
+
+	sessionBytes = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "session_db", "bytes"),
+		"Estimated size of the sessions in the session store.", nil, nil)
+
+	sessions = &sessionCollector{}
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		simulationsTotal,
+		simulationFailures,
+		simulatorDuration,
+		rpcDuration,
+		sessions,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format. A failing
+// collector, such as an unreachable session store, only drops its own metrics.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
+
+// Serve serves /metrics on addr until ctx is cancelled
+func Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", Handler())
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return srv.Shutdown(context.Background())
+	}
+}
+
+// ObserveSimulation records one simulator run. status is the response status,
+// or "crash" when the simulator produced no response.
+func ObserveSimulation(elapsed time.Duration, status, errMsg string) {
+	simulatorDuration.Observe(elapsed.Seconds())
+	simulationsTotal.WithLabelValues(status).Inc()
+	if status != "success" {
+		simulationFailures.WithLabelValues(ErrorClass(errMsg)).Inc()
+	}
+}
+
+// scErrorClasses maps Soroban ScErrorType names to failure class labels
+var scErrorClasses = map[string]string{
+	"contract": "contract",
+	"wasmvm":   "wasm_vm",
+	"context":  "context",
+	"storage":  "storage",
+	"object":   "object",
+	"crypto":   "crypto",
+	"events":   "events",
+	"budget":   "budget",
+	"value":    "value",
+	"auth":     "auth",
+}
+
+// ErrorClass returns the failure class for a simulation error, taken from the
+// type of the host error (e.g. "Error(Budget, ExceededLimit)" is "budget").
+// Unrecognised errors are "other" so the label set stays bounded.
+func ErrorClass(errMsg string) string {
+	if _, errType, _, ok := decoder.ParseScError(errMsg); ok {
+		if class, ok := scErrorClasses[strings.ToLower(errType)]; ok {
+			return class
+		}
+	}
+	return "other"
+}
+
+// InstrumentTransport wraps next so every request records its latency in
+// erst_rpc_request_duration_seconds
+func InstrumentTransport(next http.RoundTripper) http.RoundTripper {
+	return &instrumentedTransport{next: next}
+}
+
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	rpcDuration.WithLabelValues(req.URL.Host, code).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+// SessionStatsFunc reports the number and total size of stored sessions
+type SessionStatsFunc func(ctx context.Context) (sessions int, bytes int64, err error)
+
+// SetSessionStats sets the source of the session_db gauges. They are omitted
+// until a source is set.
+func SetSessionStats(fn SessionStatsFunc) {
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	sessions.stats = fn
+}
+
+// sessionCollector queries the session store on each scrape
+type sessionCollector struct {
+	mu    sync.Mutex
+	stats SessionStatsFunc
+}
+
+func (c *sessionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sessionSessions
+	ch <- sessionBytes
+}
+
+func (c *sessionCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	stats := c.stats
+	c.mu.Unlock()
+	if stats == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	count, size, err := stats(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(sessionSessions, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(sessionSessions, prometheus.GaugeValue, float64(count))
+	ch <- prometheus.MustNewConstMetric(sessionBytes, prometheus.GaugeValue, float64(size))
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape returned %d: %s", rec.Code, rec.Body.String())
+	}
+	return rec.Body.String()
+}
+
+func TestErrorClass(t *testing.T) {
+	cases := map[string]string{
+		"HostError: Error(Budget, ExceededLimit)":             "budget",
+		"HostError: Error(Auth, InvalidAction)":               "auth",
+		"Error(WasmVm, InvalidAction): wasm trap unreachable": "wasm_vm",
+		"host invocation failed: Error(Contract, #3)":         "contract",
+		"Error(Unknown, Thing)":                               "other",
+		"simulator crashed":                                   "other",
+		"":                                                    "other",
+	}
+	for msg, want := range cases {
+		if got := ErrorClass(msg); got != want {
+			t.Errorf("ErrorClass(%q) = %q; want %q", msg, got, want)
+		}
+	}
+}
+
+func TestObserveSimulation(t *testing.T) {
+	ObserveSimulation(200*time.Millisecond, "success", "")
+	ObserveSimulation(time.Second, "error", "HostError: Error(Storage, MissingValue)")
+
+	out := scrape(t)
+	for _, want := range []string{
+		`erst_simulations_total{status="success"}`,
+		`erst_simulations_total{status="error"}`,
+		`erst_simulation_failures_total{class="storage"}`,
+		`erst_simulator_duration_seconds_count`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output missing %s", want)
+		}
+	}
+	if strings.Contains(out, `erst_simulation_failures_total{class="other"}`) {
+		t.Error("a successful simulation should not count as a failure")
+	}
+}
+
+func TestInstrumentTransport(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer backend.Close()
+
+	client := &http.Client{Transport: InstrumentTransport(http.DefaultTransport)}
+	resp, err := client.Get(backend.URL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	host := strings.TrimPrefix(backend.URL, "http://")
+	want := `erst_rpc_request_duration_seconds_count{code="418",host="` + host + `"} 1`
+	if out := scrape(t); !strings.Contains(out, want) {
+		t.Errorf("metrics output missing %s", want)
+	}
+}
+
+func TestSessionStats(t *testing.T) {
+	t.Cleanup(func() { SetSessionStats(nil) })
+
+	if strings.Contains(scrape(t), "erst_session_db_sessions") {
+		t.Error("session gauges should be omitted without a source")
+	}
+
+	SetSessionStats(func(ctx context.Context) (int, int64, error) { return 3, 4096, nil })
+	out := scrape(t)
+	if !strings.Contains(out, "erst_session_db_sessions 3") || !strings.Contains(out, "erst_session_db_bytes 4096") {
+		t.Errorf("unexpected session gauges:\n%s", out)
+	}
+
+	SetSessionStats(func(ctx context.Context) (int, int64, error) { return 0, 0, errors.New("database is locked") })
+	out = scrape(t)
+	if strings.Contains(out, "erst_session_db_sessions") || !strings.Contains(out, "go_goroutines") {
+		t.Errorf("a failing store should only drop the session gauges:\n%s", out)
+	}
+}
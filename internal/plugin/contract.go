@@ -0,0 +1,220 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Kinds of value a contract decoder is asked to decode
+const (
+	KindEvent      = "event"
+	KindInvocation = "invocation"
+)
+
+// DefaultDecoderTimeout bounds a single call to an executable decoder
+const DefaultDecoderTimeout = 5 * time.Second
+
+// DecodeRequest is what a contract decoder is given: an event emitted by, or
+// a call to, one of its contracts. ScVals are base64 XDR.
+type DecodeRequest struct {
+	APIVersion string `json:"api_version"`
+	Kind       string `json:"kind"`
+	ContractID string `json:"contract_id"`
+	Network    string `json:"network,omitempty"`
+
+	// Set for events
+	Topics []string `json:"topics,omitempty"`
+	Data   string   `json:"data,omitempty"`
+
+	// Set for invocations
+	Function string   `json:"function,omitempty"`
+	Args     []string `json:"args,omitempty"`
+}
+
+// Decoded is a decoder's description of an event or invocation
+type Decoded struct {
+	// Summary is the one-line description shown in the debug output
+	Summary string `json:"summary"`
+	// Fields carries any structured detail, passed through to JSON output
+	Fields json.RawMessage `json:"fields,omitempty"`
+}
+
+// ContractEventType is the event type under which decoders for a contract
+// are found: a plugin registered for a contract's events and invocation
+// arguments reports true from CanDecode(ContractEventType(contractID)) and is
+// given a DecodeRequest as JSON.
+func ContractEventType(contractID string) string {
+	return contractEventPrefix + contractID
+}
+
+const contractEventPrefix = "contract:"
+
+// ContextDecoder is implemented by plugins whose decoding can be cancelled,
+// such as those running an external program
+type ContextDecoder interface {
+	DecodeContext(ctx context.Context, data []byte) (json.RawMessage, error)
+}
+
+// DecoderManifest registers an executable decoder. Manifests are JSON files
+// in the decoders directory, e.g. ~/.erst/decoders/mytoken.json:
+//
+//	{"name": "mytoken", "command": "./mytoken-decoder", "contracts": ["CA..."]}
+//
+// The command reads one DecodeRequest as JSON on stdin and writes one Decoded
+// as JSON on stdout. An empty stdout means it has nothing to add.
+type DecoderManifest struct {
+	Name string `json:"name"`
+	// Version is the decoder's own version (default 0.0.0)
+	Version     string   `json:"version,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Command     string   `json:"command"`
+	Args        []string `json:"args,omitempty"`
+	Contracts   []string `json:"contracts"`
+	// Timeout is a Go duration such as "2s" (default 5s)
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// ExecDecoder is a DecoderPlugin that runs an external program for each value
+// it decodes
+type ExecDecoder struct {
+	manifest  DecoderManifest
+	command   string
+	args      []string
+	contracts map[string]bool
+	timeout   time.Duration
+}
+
+// NewExecDecoder validates a manifest. A relative command is resolved against
+// dir, the directory holding the manifest.
+func NewExecDecoder(m DecoderManifest, dir string) (*ExecDecoder, error) {
+	if m.Name == "" {
+		return nil, fmt.Errorf("decoder name cannot be empty")
+	}
+	if m.Command == "" {
+		return nil, fmt.Errorf("decoder %s has no command", m.Name)
+	}
+	if len(m.Contracts) == 0 {
+		return nil, fmt.Errorf("decoder %s is not registered for any contract", m.Name)
+	}
+
+	if m.Version == "" {
+		m.Version = "0.0.0"
+	}
+
+	timeout := DefaultDecoderTimeout
+	if m.Timeout != "" {
+		d, err := time.ParseDuration(m.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("decoder %s has invalid timeout %q", m.Name, m.Timeout)
+		}
+		timeout = d
+	}
+
+	command := m.Command
+	if !filepath.IsAbs(command) && strings.ContainsRune(command, filepath.Separator) {
+		command = filepath.Join(dir, command)
+	}
+
+	contracts := make(map[string]bool, len(m.Contracts))
+	for _, id := range m.Contracts {
+		contracts[strings.TrimSpace(id)] = true
+	}
+	return &ExecDecoder{manifest: m, command: command, args: m.Args, contracts: contracts, timeout: timeout}, nil
+}
+
+// Name returns the manifest name
+func (d *ExecDecoder) Name() string {
+	return d.manifest.Name
+}
+
+// Version returns the manifest version
+func (d *ExecDecoder) Version() string {
+	return d.manifest.Version
+}
+
+// CanDecode returns true for the contracts listed in the manifest
+func (d *ExecDecoder) CanDecode(eventType string) bool {
+	id, ok := strings.CutPrefix(eventType, contractEventPrefix)
+	return ok && d.contracts[id]
+}
+
+// Metadata lists the contracts the decoder is registered for as event types
+func (d *ExecDecoder) Metadata() PluginMetadata {
+	types := make([]string, 0, len(d.manifest.Contracts))
+	for _, id := range d.manifest.Contracts {
+		types = append(types, ContractEventType(strings.TrimSpace(id)))
+	}
+	return PluginMetadata{
+		Name:        d.manifest.Name,
+		Version:     d.manifest.Version,
+		APIVersion:  Version,
+		EventTypes:  types,
+		Description: d.manifest.Description,
+	}
+}
+
+// Decode runs the command with data on stdin
+func (d *ExecDecoder) Decode(data []byte) (json.RawMessage, error) {
+	return d.DecodeContext(context.Background(), data)
+}
+
+// DecodeContext runs the command with data on stdin, killing it when ctx is
+// done or the manifest timeout passes. Empty output is returned as nil.
+func (d *ExecDecoder) DecodeContext(ctx context.Context, data []byte) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, d.command, d.args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("decoder %s failed: %w: %s", d.Name(), err, msg)
+		}
+		return nil, fmt.Errorf("decoder %s failed: %w", d.Name(), err)
+	}
+
+	out := bytes.TrimSpace(stdout.Bytes())
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return json.RawMessage(out), nil
+}
+
+// DefaultDecoderDir returns ~/.erst/decoders
+func DefaultDecoderDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".erst", "decoders"), nil
+}
+
+// LoadManifest reads a decoder manifest and creates its ExecDecoder
+func LoadManifest(path string) (*ExecDecoder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var m DecoderManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	d, err := NewExecDecoder(m, filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+const tokenContract = "CAAQCAIBAEAQCAIBAEAQCAIBAEAQCAIBAEAQCAIBAEAQCAIBAEAQC526"
+
+// writeDecoder writes an executable decoder script and its manifest to dir
+func writeDecoder(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name+".sh"), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `{"name": "` + name + `", "command": "./` + name + `.sh", "contracts": ["` + tokenContract + `"]}`
+	if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistryExecDecoder(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("decoder scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	// Echo the request kind and function back so the test can check stdin
+	writeDecoder(t, dir, "mytoken", `read req
+case "$req" in
+  *'"kind":"invocation"'*'"function":"mint"'*) echo '{"summary":"mint 5 MTK","fields":{"amount":5}}' ;;
+  *) ;;
+esac
+`)
+
+	r := NewRegistry()
+	if err := r.LoadFromDirectory(dir); err != nil {
+		t.Fatalf("LoadFromDirectory: %v", err)
+	}
+	plugins := r.ListPlugins()
+	if len(plugins) != 1 || plugins[0].Name != "mytoken" || plugins[0].EventTypes[0] != ContractEventType(tokenContract) {
+		t.Fatalf("ListPlugins = %+v, want the mytoken decoder", plugins)
+	}
+
+	ctx := context.Background()
+	decoded, name, err := r.DecodeContract(ctx, DecodeRequest{Kind: KindInvocation, ContractID: tokenContract, Function: "mint"})
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if decoded == nil || decoded.Summary != "mint 5 MTK" || name != "mytoken" || string(decoded.Fields) != `{"amount":5}` {
+		t.Errorf("Decode = %+v from %q", decoded, name)
+	}
+
+	// Empty output keeps the built-in rendering
+	decoded, _, err = r.DecodeContract(ctx, DecodeRequest{Kind: KindEvent, ContractID: tokenContract})
+	if err != nil || decoded != nil {
+		t.Errorf("event Decode = %+v, %v; want nothing", decoded, err)
+	}
+
+	// Other contracts are not sent to the decoder
+	decoded, name, err = r.DecodeContract(ctx, DecodeRequest{Kind: KindInvocation, ContractID: "COTHER", Function: "mint"})
+	if err != nil || decoded != nil || name != "" {
+		t.Errorf("unmatched Decode = %+v from %q, %v", decoded, name, err)
+	}
+}
+
+func TestRegistryExecDecoderFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("decoder scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	writeDecoder(t, dir, "broken", "echo 'unknown schema' >&2\nexit 1\n")
+
+	r := NewRegistry()
+	if err := r.LoadFromDirectory(dir); err != nil {
+		t.Fatalf("LoadFromDirectory: %v", err)
+	}
+	_, _, err := r.DecodeContract(context.Background(), DecodeRequest{Kind: KindEvent, ContractID: tokenContract})
+	if err == nil || !strings.Contains(err.Error(), "unknown schema") {
+		t.Errorf("Decode error = %v, want the decoder's stderr", err)
+	}
+}
+
+func TestExecDecoderContextCancelled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("decoder scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	writeDecoder(t, dir, "slow", "sleep 5\n")
+
+	r := NewRegistry()
+	if err := r.LoadFromDirectory(dir); err != nil {
+		t.Fatalf("LoadFromDirectory: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if _, _, err := r.DecodeContract(ctx, DecodeRequest{Kind: KindEvent, ContractID: tokenContract}); err == nil {
+		t.Error("expected the cancelled decoder to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("decoder ran for %v after its context was cancelled", elapsed)
+	}
+}
+
+func TestLoadDirectoryInvalidManifest(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"name": "bad", "command": "bad"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRegistry()
+	err := r.LoadFromDirectory(dir)
+	if err == nil || !strings.Contains(err.Error(), "not registered for any contract") {
+		t.Errorf("LoadFromDirectory error = %v", err)
+	}
+	if n := len(r.ListPlugins()); n != 0 {
+		t.Errorf("%d plugins loaded, want 0", n)
+	}
+
+	if err := NewRegistry().LoadFromDirectory(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("missing directory: %v", err)
+	}
+}
@@ -6,6 +6,7 @@ package plugin
 import (
 	"fmt"
 	"plugin"
+	"sort"
 	"sync"
 )
 
@@ -55,6 +56,22 @@ func (l *Loader) Load(path string) error {
 	return nil
 }
 
+// Register adds a plugin created in process, such as an ExecDecoder
+func (l *Loader) Register(p DecoderPlugin) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := validatePlugin(p); err != nil {
+		return fmt.Errorf("plugin %s validation failed: %w", p.Name(), err)
+	}
+	if _, ok := l.plugins[p.Name()]; ok {
+		return fmt.Errorf("plugin %s is already loaded", p.Name())
+	}
+
+	l.plugins[p.Name()] = p
+	return nil
+}
+
 // Get retrieves a loaded plugin by name
 func (l *Loader) Get(name string) (DecoderPlugin, bool) {
 	l.mu.RLock()
@@ -75,13 +92,20 @@ func (l *Loader) List() []string {
 	return names
 }
 
-// FindForEvent returns the first plugin that can decode the event type
+// FindForEvent returns the first plugin, by name, that can decode the event
+// type
 func (l *Loader) FindForEvent(eventType string) (DecoderPlugin, bool) {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
-	for _, p := range l.plugins {
-		if p.CanDecode(eventType) {
+	names := make([]string, 0, len(l.plugins))
+	for name := range l.plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if p := l.plugins[name]; p.CanDecode(eventType) {
 			return p, true
 		}
 	}
@@ -4,9 +4,11 @@
 package plugin
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"sync"
 )
 
@@ -25,31 +27,52 @@ func NewRegistry() *Registry {
 	}
 }
 
-// LoadFromDirectory scans and loads all plugins from a directory
+// LoadFromDirectory scans and loads all plugins from a directory: shared
+// libraries (*.so) and executable decoder manifests (*.json). A missing
+// directory loads nothing.
 func (r *Registry) LoadFromDirectory(dir string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	pattern := filepath.Join(dir, "*.so")
-	matches, err := filepath.Glob(pattern)
+	libraries, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return fmt.Errorf("failed to scan plugin directory: %w", err)
+	}
+	manifests, err := filepath.Glob(filepath.Join(dir, "*.json"))
 	if err != nil {
 		return fmt.Errorf("failed to scan plugin directory: %w", err)
 	}
 
-	var loadErrors []error
-	for _, path := range matches {
+	var loadErrors []string
+	for _, path := range libraries {
 		if err := r.loader.Load(path); err != nil {
-			loadErrors = append(loadErrors, err)
+			loadErrors = append(loadErrors, err.Error())
+		}
+	}
+	for _, path := range manifests {
+		d, err := LoadManifest(path)
+		if err == nil {
+			err = r.loader.Register(d)
+		}
+		if err != nil {
+			loadErrors = append(loadErrors, err.Error())
 		}
 	}
 
 	if len(loadErrors) > 0 {
-		return fmt.Errorf("encountered %d plugin loading errors", len(loadErrors))
+		return fmt.Errorf("encountered %d plugin loading errors: %s", len(loadErrors), strings.Join(loadErrors, "; "))
 	}
 
 	return nil
 }
 
+// Register adds a plugin created in process
+func (r *Registry) Register(p DecoderPlugin) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.loader.Register(p)
+}
+
 // Decode uses a plugin to decode an event
 func (r *Registry) Decode(pluginName string, eventType string, data []byte) (json.RawMessage, error) {
 	r.mu.RLock()
@@ -77,6 +100,12 @@ func (r *Registry) Decode(pluginName string, eventType string, data []byte) (jso
 
 // FindAndDecode searches for a capable plugin and decodes the event
 func (r *Registry) FindAndDecode(eventType string, data []byte) (json.RawMessage, string, error) {
+	return r.FindAndDecodeContext(context.Background(), eventType, data)
+}
+
+// FindAndDecodeContext is FindAndDecode for plugins that can be cancelled
+// through ctx; other plugins are called as FindAndDecode calls them
+func (r *Registry) FindAndDecodeContext(ctx context.Context, eventType string, data []byte) (json.RawMessage, string, error) {
 	r.mu.RLock()
 	p, ok := r.loader.FindForEvent(eventType)
 	r.mu.RUnlock()
@@ -85,7 +114,13 @@ func (r *Registry) FindAndDecode(eventType string, data []byte) (json.RawMessage
 		return nil, "", fmt.Errorf("no plugin available for event type %s", eventType)
 	}
 
-	result, err := p.Decode(data)
+	var result json.RawMessage
+	var err error
+	if cd, ok := p.(ContextDecoder); ok {
+		result, err = cd.DecodeContext(ctx, data)
+	} else {
+		result, err = p.Decode(data)
+	}
 	if err != nil {
 		return nil, "", err
 	}
@@ -93,6 +128,43 @@ func (r *Registry) FindAndDecode(eventType string, data []byte) (json.RawMessage
 	return result, p.Name(), nil
 }
 
+// CanDecode returns true if a loaded plugin can decode the event type
+func (r *Registry) CanDecode(eventType string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.loader.FindForEvent(eventType)
+	return ok
+}
+
+// DecodeContract asks the plugin registered for req.ContractID to describe
+// it, returning the result with the plugin's name. Both are empty when no
+// plugin handles the contract or it has nothing to add.
+func (r *Registry) DecodeContract(ctx context.Context, req DecodeRequest) (*Decoded, string, error) {
+	eventType := ContractEventType(req.ContractID)
+	if !r.CanDecode(eventType) {
+		return nil, "", nil
+	}
+
+	req.APIVersion = Version
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode request: %w", err)
+	}
+	output, name, err := r.FindAndDecodeContext(ctx, eventType, input)
+	if err != nil || len(output) == 0 {
+		return nil, "", err
+	}
+
+	var decoded Decoded
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		return nil, "", fmt.Errorf("decoder %s wrote invalid output: %w", name, err)
+	}
+	if decoded.Summary == "" && len(decoded.Fields) == 0 {
+		return nil, "", nil
+	}
+	return &decoded, name, nil
+}
+
 // ListPlugins returns information about all loaded plugins
 func (r *Registry) ListPlugins() []PluginMetadata {
 	r.mu.RLock()
@@ -0,0 +1,46 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package profile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteDiffFolded writes before and after as differential folded stacks,
+// "root;child;leaf <before> <after>" per line, the input of flamegraph.pl and
+// inferno-flamegraph for a differential flamegraph. Every stack found in
+// either profile gets a line; it is 0 on the side that lacks it.
+func WriteDiffFolded(w io.Writer, before, after []FoldedSample) error {
+	type weights struct{ before, after int64 }
+	stacks := make(map[string]*weights)
+	add := func(samples []FoldedSample, set func(*weights, int64)) {
+		for _, s := range samples {
+			key := strings.Join(s.Stack, ";")
+			if stacks[key] == nil {
+				stacks[key] = &weights{}
+			}
+			set(stacks[key], s.Value)
+		}
+	}
+	add(before, func(v *weights, n int64) { v.before += n })
+	add(after, func(v *weights, n int64) { v.after += n })
+
+	keys := make([]string, 0, len(stacks))
+	for key := range stacks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	bw := bufio.NewWriter(w)
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(bw, "%s %d %d\n", key, stacks[key].before, stacks[key].after); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
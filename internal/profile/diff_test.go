@@ -0,0 +1,28 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package profile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDiffFolded(t *testing.T) {
+	before := []FoldedSample{
+		{Stack: []string{"swap", "transfer"}, Value: 100},
+		{Stack: []string{"swap", "audit"}, Value: 40},
+		{Stack: []string{"swap", "transfer"}, Value: 20},
+	}
+	after := []FoldedSample{
+		{Stack: []string{"swap", "transfer"}, Value: 300},
+		{Stack: []string{"swap", "log"}, Value: 5},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDiffFolded(&buf, before, after))
+	assert.Equal(t, "swap;audit 40 0\nswap;log 0 5\nswap;transfer 120 300\n", buf.String())
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package profile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/pprof/profile"
+)
+
+// SampleTypeCost is the pprof sample type for simulator resource cost.
+const SampleTypeCost = "cost"
+
+// FoldedSample is one line of folded-stack output: a root-first call stack
+// and the weight attributed to it.
+type FoldedSample struct {
+	Stack []string
+	Value int64
+}
+
+// ParseFolded reads folded stacks in the format used by flamegraph.pl and
+// inferno: "root;child;leaf <weight>" per line.
+func ParseFolded(r io.Reader) ([]FoldedSample, error) {
+	var samples []FoldedSample
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		sep := strings.LastIndexByte(line, ' ')
+		if sep <= 0 {
+			return nil, fmt.Errorf("line %d: missing sample weight", lineNo)
+		}
+		value, err := strconv.ParseInt(line[sep+1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid sample weight: %w", lineNo, err)
+		}
+		samples = append(samples, FoldedSample{
+			Stack: strings.Split(strings.TrimSpace(line[:sep]), ";"),
+			Value: value,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// FoldedToPprof converts folded stacks into a pprof profile with one sample
+// per stack.
+func FoldedToPprof(samples []FoldedSample) (*profile.Profile, error) {
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: SampleTypeCost, Unit: SampleUnitCount},
+		},
+		DefaultSampleType: SampleTypeCost,
+		Mapping: []*profile.Mapping{
+			{ID: 1, Start: 0, Limit: 0, File: "soroban", HasFunctions: true},
+		},
+	}
+	mapping := p.Mapping[0]
+	locByName := make(map[string]*profile.Location)
+
+	for _, s := range samples {
+		// pprof stores stacks leaf first
+		locs := make([]*profile.Location, 0, len(s.Stack))
+		for i := len(s.Stack) - 1; i >= 0; i-- {
+			name := s.Stack[i]
+			loc, ok := locByName[name]
+			if !ok {
+				fn := &profile.Function{ID: uint64(len(p.Function) + 1), Name: name, SystemName: name}
+				p.Function = append(p.Function, fn)
+				loc = &profile.Location{
+					ID:      uint64(len(p.Location) + 1),
+					Mapping: mapping,
+					Line:    []profile.Line{{Function: fn}},
+				}
+				p.Location = append(p.Location, loc)
+				locByName[name] = loc
+			}
+			locs = append(locs, loc)
+		}
+		p.Sample = append(p.Sample, &profile.Sample{Location: locs, Value: []int64{s.Value}})
+	}
+
+	if err := p.CheckValid(); err != nil {
+		return nil, fmt.Errorf("profile validation failed: %w", err)
+	}
+	return p, nil
+}
+
+// WriteFoldedAsPprof converts folded-stack text to a pprof profile and writes
+// it to w (gzip-compressed protobuf).
+func WriteFoldedAsPprof(folded string, w io.Writer) error {
+	samples, err := ParseFolded(strings.NewReader(folded))
+	if err != nil {
+		return err
+	}
+	p, err := FoldedToPprof(samples)
+	if err != nil {
+		return err
+	}
+	return p.Write(w)
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package profile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFolded(t *testing.T) {
+	samples, err := ParseFolded(strings.NewReader("Total;CPU 1200\n\nTotal;Memory 300\n"))
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	assert.Equal(t, []string{"Total", "CPU"}, samples[0].Stack)
+	assert.Equal(t, int64(1200), samples[0].Value)
+
+	_, err = ParseFolded(strings.NewReader("Total;CPU\n"))
+	assert.Error(t, err)
+	_, err = ParseFolded(strings.NewReader("Total;CPU lots\n"))
+	assert.Error(t, err)
+}
+
+func TestWriteFoldedAsPprof_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteFoldedAsPprof("Total;CPU 1200\nTotal;Memory 300\n", &buf))
+
+	p, err := profile.Parse(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, SampleTypeCost, p.SampleType[0].Type)
+	require.Len(t, p.Sample, 2)
+	assert.Equal(t, int64(1200), p.Sample[0].Value[0])
+	// Leaf first, and the shared root is one location
+	assert.Equal(t, "CPU", p.Sample[0].Location[0].Line[0].Function.Name)
+	assert.Same(t, p.Sample[0].Location[1], p.Sample[1].Location[1])
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package profile
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// SpeedscopeSchema is the $schema of speedscope's file format
+const SpeedscopeSchema = "https://www.speedscope.app/file-format-schema.json"
+
+// Speedscope is a speedscope file: frames shared by its profiles, and the
+// profiles themselves
+type Speedscope struct {
+	Schema   string              `json:"$schema"`
+	Shared   SpeedscopeShared    `json:"shared"`
+	Profiles []SpeedscopeProfile `json:"profiles"`
+	Name     string              `json:"name,omitempty"`
+	Exporter string              `json:"exporter,omitempty"`
+}
+
+// SpeedscopeShared holds the frames referenced by index from samples
+type SpeedscopeShared struct {
+	Frames []SpeedscopeFrame `json:"frames"`
+}
+
+// SpeedscopeFrame is one function name in a speedscope file
+type SpeedscopeFrame struct {
+	Name string `json:"name"`
+}
+
+// SpeedscopeProfile is a speedscope "sampled" profile: each sample is a
+// root-first stack of frame indexes with the weight at the same position
+type SpeedscopeProfile struct {
+	Type       string  `json:"type"`
+	Name       string  `json:"name"`
+	Unit       string  `json:"unit"`
+	StartValue int64   `json:"startValue"`
+	EndValue   int64   `json:"endValue"`
+	Samples    [][]int `json:"samples"`
+	Weights    []int64 `json:"weights"`
+}
+
+// FoldedToSpeedscope converts folded stacks into a speedscope file with one
+// sampled profile named name. The weights are simulator cost, which has no
+// unit speedscope knows, so they are shown as plain numbers.
+func FoldedToSpeedscope(samples []FoldedSample, name string) *Speedscope {
+	out := &Speedscope{
+		Schema:   SpeedscopeSchema,
+		Shared:   SpeedscopeShared{Frames: []SpeedscopeFrame{}},
+		Name:     name,
+		Exporter: "erst",
+	}
+	p := SpeedscopeProfile{
+		Type:    "sampled",
+		Name:    name,
+		Unit:    "none",
+		Samples: make([][]int, 0, len(samples)),
+		Weights: make([]int64, 0, len(samples)),
+	}
+
+	frameByName := make(map[string]int)
+	for _, s := range samples {
+		stack := make([]int, 0, len(s.Stack))
+		for _, fn := range s.Stack {
+			idx, ok := frameByName[fn]
+			if !ok {
+				idx = len(out.Shared.Frames)
+				out.Shared.Frames = append(out.Shared.Frames, SpeedscopeFrame{Name: fn})
+				frameByName[fn] = idx
+			}
+			stack = append(stack, idx)
+		}
+		p.Samples = append(p.Samples, stack)
+		p.Weights = append(p.Weights, s.Value)
+		p.EndValue += s.Value
+	}
+
+	out.Profiles = []SpeedscopeProfile{p}
+	return out
+}
+
+// WriteFoldedAsSpeedscope converts folded-stack text to a speedscope file
+// and writes it to w as JSON, ready to open at https://www.speedscope.app.
+func WriteFoldedAsSpeedscope(folded, name string, w io.Writer) error {
+	samples, err := ParseFolded(strings.NewReader(folded))
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(FoldedToSpeedscope(samples, name))
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package profile
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFoldedAsSpeedscope(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteFoldedAsSpeedscope("Total;CPU 1200\nTotal;Memory 300\n", "abc123", &buf))
+
+	var file Speedscope
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &file))
+	assert.Equal(t, SpeedscopeSchema, file.Schema)
+	assert.Equal(t, []SpeedscopeFrame{{Name: "Total"}, {Name: "CPU"}, {Name: "Memory"}}, file.Shared.Frames)
+
+	require.Len(t, file.Profiles, 1)
+	p := file.Profiles[0]
+	assert.Equal(t, "sampled", p.Type)
+	assert.Equal(t, "abc123", p.Name)
+	// Root first, sharing the Total frame
+	assert.Equal(t, [][]int{{0, 1}, {0, 2}}, p.Samples)
+	assert.Equal(t, []int64{1200, 300}, p.Weights)
+	assert.Equal(t, int64(1500), p.EndValue)
+
+	assert.Error(t, WriteFoldedAsSpeedscope("Total;CPU\n", "bad", &buf))
+}
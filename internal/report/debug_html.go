@@ -0,0 +1,315 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// DebugReport holds everything shown in a single-transaction HTML or
+// Markdown report
+type DebugReport struct {
+	TxHash      string
+	Network     string
+	SessionID   string
+	ErstVersion string
+	GeneratedAt time.Time
+	EnvelopeXdr string
+	Invocations []contractspec.Invocation
+	Simulation  *simulator.SimulationResponse
+
+	// Analysis shown by the Markdown report
+	Operations []decoder.OperationSummary
+	// ResultCode and ResultExplanation describe transactions with only
+	// classic operations, which are decoded rather than simulated
+	ResultCode        string
+	ResultExplanation string
+	Suggestions       []decoder.Suggestion
+	TokenFlows        []string
+}
+
+type debugEventRow struct {
+	Index    int
+	Type     string
+	Contract string
+	Topics   string
+	Data     string
+	Failed   bool
+}
+
+type debugOperationRow struct {
+	Index  int
+	Type   string
+	Source string
+}
+
+type debugEnvelopeView struct {
+	Type       string
+	Source     string
+	Fee        int64
+	Operations []debugOperationRow
+	Inner      *debugEnvelopeView
+}
+
+type debugReportView struct {
+	*DebugReport
+	Title       string
+	GeneratedAt time.Time
+	Status      string
+	Error       string
+	Envelope    *debugEnvelopeView
+	EnvelopeErr string
+	Events      []debugEventRow
+	Logs        []string
+	Budget      *simulator.BudgetUsage
+	Flamegraph  htmltemplate.URL
+}
+
+// RenderDebugHTML renders a self-contained HTML page: styles are inline and
+// the flamegraph is embedded as a data URI, so the file can be attached to a
+// bug report as-is.
+func RenderDebugHTML(r *DebugReport) ([]byte, error) {
+	view := buildDebugView(r)
+
+	tmpl, err := htmltemplate.New("debug-report").Funcs(htmltemplate.FuncMap{
+		"formatTime":  formatTime,
+		"statusClass": statusClass,
+		"percentBar":  percentBar,
+	}).Parse(debugHTMLTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, view); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func buildDebugView(r *DebugReport) *debugReportView {
+	view := &debugReportView{
+		DebugReport: r,
+		Title:       "Transaction Debug Report",
+		GeneratedAt: r.GeneratedAt,
+		Status:      "unknown",
+	}
+	if view.GeneratedAt.IsZero() {
+		view.GeneratedAt = time.Now()
+	}
+
+	if r.EnvelopeXdr != "" {
+		env, err := decoder.AnalyzeEnvelope(r.EnvelopeXdr)
+		if err != nil {
+			view.EnvelopeErr = err.Error()
+		} else {
+			view.Envelope = envelopeView(env)
+		}
+	}
+
+	sim := r.Simulation
+	if sim == nil {
+		return view
+	}
+	view.Status = sim.Status
+	view.Error = sim.Error
+	view.Logs = sim.Logs
+	view.Budget = sim.BudgetUsage
+
+	for i, ev := range sim.DiagnosticEvents {
+		row := debugEventRow{
+			Index:  i + 1,
+			Type:   ev.EventType,
+			Topics: strings.Join(decoder.RenderScVals(ev.TopicsXdr, ev.Topics), ", "),
+			Data:   decoder.RenderScVal(ev.DataXdr, ev.Data),
+			Failed: !ev.InSuccessfulContractCall,
+		}
+		if ev.ContractID != nil {
+			row.Contract = *ev.ContractID
+		}
+		view.Events = append(view.Events, row)
+	}
+	// Older simulator builds only report raw event strings
+	if len(view.Events) == 0 {
+		for i, raw := range sim.Events {
+			view.Events = append(view.Events, debugEventRow{Index: i + 1, Data: decoder.FormatEvent(raw)})
+		}
+	}
+
+	if sim.Flamegraph != "" {
+		// The SVG is loaded through <img>, which keeps any script it carries inert
+		view.Flamegraph = htmltemplate.URL("data:image/svg+xml;base64," +
+			base64.StdEncoding.EncodeToString([]byte(sim.Flamegraph)))
+	}
+	return view
+}
+
+func envelopeView(env *decoder.DecodedEnvelope) *debugEnvelopeView {
+	v := &debugEnvelopeView{Type: env.Type, Source: decoder.DisplayAddress(env.Source), Fee: env.Fee}
+	for i, op := range env.Operations {
+		row := debugOperationRow{Index: i, Type: strings.TrimPrefix(op.Body.Type.String(), "OperationType")}
+		if op.SourceAccount != nil {
+			row.Source = decoder.DisplayAddress(op.SourceAccount.Address())
+		}
+		v.Operations = append(v.Operations, row)
+	}
+	if env.InnerTx != nil {
+		v.Inner = envelopeView(env.InnerTx)
+	}
+	return v
+}
+
+func percentBar(pct float64) string {
+	switch {
+	case pct >= 95:
+		return "bar-critical"
+	case pct >= 80:
+		return "bar-warning"
+	default:
+		return "bar-ok"
+	}
+}
+
+const debugHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1.0">
+	<title>{{ .Title }} - {{ .TxHash }}</title>
+	<style>
+		* { margin: 0; padding: 0; box-sizing: border-box; }
+		body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; color: #333; background: #f5f5f5; line-height: 1.6; }
+		.container { max-width: 1200px; margin: 0 auto; background: white; box-shadow: 0 0 10px rgba(0,0,0,0.1); }
+		header { background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); color: white; padding: 30px; }
+		header h1 { font-size: 2em; margin-bottom: 6px; }
+		.header-meta { font-size: 0.9em; opacity: 0.9; word-break: break-all; }
+		.toc { background: #f9f9f9; border-bottom: 1px solid #e0e0e0; padding: 16px 30px; display: flex; gap: 24px; flex-wrap: wrap; }
+		.toc a { color: #667eea; text-decoration: none; font-weight: 500; }
+		section { padding: 30px; border-bottom: 1px solid #e0e0e0; }
+		h2 { color: #667eea; font-size: 1.5em; margin-bottom: 16px; padding-bottom: 8px; border-bottom: 2px solid #667eea; }
+		h3 { color: #764ba2; font-size: 1.1em; margin: 16px 0 8px 0; }
+		table { width: 100%; border-collapse: collapse; margin: 12px 0; font-size: 0.95em; }
+		th, td { padding: 8px 12px; text-align: left; border-bottom: 1px solid #e0e0e0; vertical-align: top; }
+		thead { background: #f5f5f5; }
+		code, .mono { font-family: SFMono-Regular, Menlo, Consolas, monospace; font-size: 0.9em; word-break: break-all; }
+		.status-success { color: #388e3c; font-weight: bold; }
+		.status-error { color: #d32f2f; font-weight: bold; }
+		.status-warning { color: #f57c00; font-weight: bold; }
+		.status-unknown { color: #9e9e9e; font-weight: bold; }
+		.alert-danger { background: #ffebee; border-left: 4px solid #d32f2f; color: #c62828; padding: 12px 16px; margin: 12px 0; }
+		.failed { background: #fff5f5; }
+		.bar { background: #eee; border-radius: 4px; height: 10px; overflow: hidden; margin-top: 4px; }
+		.bar > div { height: 100%; }
+		.bar-ok { background: #388e3c; } .bar-warning { background: #f57c00; } .bar-critical { background: #d32f2f; }
+		.logs { background: #1e1e1e; color: #ddd; padding: 12px; border-radius: 4px; max-height: 400px; overflow: auto; white-space: pre-wrap; }
+		.flamegraph img { max-width: 100%; border: 1px solid #e0e0e0; }
+		footer { background: #f5f5f5; padding: 16px 30px; text-align: center; color: #999; font-size: 0.9em; }
+	</style>
+</head>
+<body>
+	<div class="container">
+		<header>
+			<h1>{{ .Title }}</h1>
+			<div class="header-meta">Transaction <code>{{ .TxHash }}</code>{{ if .Network }} on {{ .Network }}{{ end }}</div>
+			<div class="header-meta">Generated on {{ formatTime .GeneratedAt }}{{ if .SessionID }} from session {{ .SessionID }}{{ end }}</div>
+		</header>
+		<div class="toc">
+			<a href="#summary">Summary</a>
+			<a href="#envelope">Envelope</a>
+			<a href="#resources">Resources</a>
+			<a href="#events">Events</a>
+			<a href="#logs">Logs</a>
+			{{ if .Flamegraph }}<a href="#flamegraph">Flamegraph</a>{{ end }}
+		</div>
+		<section id="summary">
+			<h2>Summary</h2>
+			<p>Status: <span class="{{ statusClass .Status }}">{{ .Status }}</span></p>
+			{{ if .Error }}<div class="alert-danger">{{ .Error }}</div>{{ end }}
+			{{ if .Invocations }}
+			<h3>Contract Invocations</h3>
+			<table>
+				<thead><tr><th>Contract</th><th>Call</th></tr></thead>
+				<tbody>
+					{{ range .Invocations }}<tr><td class="mono">{{ .ContractID }}</td><td class="mono">{{ .String }}</td></tr>{{ end }}
+				</tbody>
+			</table>
+			{{ end }}
+		</section>
+		<section id="envelope">
+			<h2>Decoded Envelope</h2>
+			{{ if .EnvelopeErr }}<div class="alert-danger">Failed to decode envelope: {{ .EnvelopeErr }}</div>{{ end }}
+			{{ with .Envelope }}{{ template "envelope" . }}{{ end }}
+		</section>
+		<section id="resources">
+			<h2>Resource Usage</h2>
+			{{ with .Budget }}
+			<table>
+				<tr><th>CPU instructions</th><td>{{ .CPUInstructions }} / {{ .CPULimit }} ({{ printf "%.2f" .CPUUsagePercent }}%)
+					<div class="bar"><div class="{{ percentBar .CPUUsagePercent }}" style="width: {{ printf "%.0f" .CPUUsagePercent }}%"></div></div></td></tr>
+				<tr><th>Memory bytes</th><td>{{ .MemoryBytes }} / {{ .MemoryLimit }} ({{ printf "%.2f" .MemoryUsagePercent }}%)
+					<div class="bar"><div class="{{ percentBar .MemoryUsagePercent }}" style="width: {{ printf "%.0f" .MemoryUsagePercent }}%"></div></div></td></tr>
+				<tr><th>Operations</th><td>{{ .OperationsCount }}</td></tr>
+			</table>
+			{{ else }}<p>No resource usage recorded.</p>{{ end }}
+		</section>
+		<section id="events">
+			<h2>Events Timeline</h2>
+			{{ if .Events }}
+			<table>
+				<thead><tr><th>#</th><th>Type</th><th>Contract</th><th>Topics</th><th>Data</th></tr></thead>
+				<tbody>
+					{{ range .Events }}
+					<tr{{ if .Failed }} class="failed"{{ end }}>
+						<td>{{ .Index }}</td>
+						<td>{{ .Type }}</td>
+						<td class="mono">{{ .Contract }}</td>
+						<td class="mono">{{ .Topics }}</td>
+						<td class="mono">{{ .Data }}</td>
+					</tr>
+					{{ end }}
+				</tbody>
+			</table>
+			{{ else }}<p>No events recorded.</p>{{ end }}
+		</section>
+		<section id="logs">
+			<h2>Logs</h2>
+			{{ if .Logs }}<div class="logs mono">{{ range .Logs }}{{ . }}
+{{ end }}</div>{{ else }}<p>No logs recorded.</p>{{ end }}
+		</section>
+		{{ if .Flamegraph }}
+		<section id="flamegraph" class="flamegraph">
+			<h2>Flamegraph</h2>
+			<img src="{{ .Flamegraph }}" alt="Resource flamegraph">
+		</section>
+		{{ end }}
+		<footer><p>Generated by erst {{ .ErstVersion }}</p></footer>
+	</div>
+</body>
+</html>
+{{ define "envelope" }}
+<table>
+	<tr><th>Type</th><td>{{ .Type }}</td></tr>
+	<tr><th>Source</th><td class="mono">{{ .Source }}</td></tr>
+	<tr><th>Fee</th><td>{{ .Fee }} stroops</td></tr>
+</table>
+{{ if .Operations }}
+<h3>Operations</h3>
+<table>
+	<thead><tr><th>#</th><th>Type</th><th>Source</th></tr></thead>
+	<tbody>
+		{{ range .Operations }}<tr><td>{{ .Index }}</td><td>{{ .Type }}</td><td class="mono">{{ if .Source }}{{ .Source }}{{ else }}-{{ end }}</td></tr>{{ end }}
+	</tbody>
+</table>
+{{ end }}
+{{ with .Inner }}<h3>Inner Transaction</h3>{{ template "envelope" . }}{{ end }}
+{{ end }}`
@@ -0,0 +1,78 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+func TestRenderDebugHTML(t *testing.T) {
+	contract := "CABC"
+	r := &DebugReport{
+		TxHash:      "deadbeef",
+		Network:     "testnet",
+		ErstVersion: "v1.2.3",
+		GeneratedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Simulation: &simulator.SimulationResponse{
+			Status: "error",
+			Error:  "HostError: Error(Contract, #3)",
+			Logs:   []string{"first log", "second log"},
+			DiagnosticEvents: []simulator.DiagnosticEvent{
+				{EventType: "contract", ContractID: &contract, Topics: []string{"transfer"}, Data: "42"},
+			},
+			BudgetUsage: &simulator.BudgetUsage{CPUInstructions: 900, CPULimit: 1000, CPUUsagePercent: 90},
+			Flamegraph:  "<svg><script>alert(1)</script></svg>",
+		},
+	}
+
+	out, err := RenderDebugHTML(r)
+	if err != nil {
+		t.Fatalf("RenderDebugHTML failed: %v", err)
+	}
+	html := string(out)
+
+	for _, want := range []string{"deadbeef", "testnet", "v1.2.3", "2025-01-02 03:04:05", "first log", "transfer", "CABC", "bar-warning", "data:image/svg"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected report to contain %q", want)
+		}
+	}
+	if strings.Contains(html, "<script>") {
+		t.Error("flamegraph markup must not be inlined into the page")
+	}
+}
+
+func TestRenderDebugHTMLEscapesContent(t *testing.T) {
+	r := &DebugReport{
+		TxHash: "tx",
+		Simulation: &simulator.SimulationResponse{
+			Status: "success",
+			Logs:   []string{"<b>bold</b>"},
+		},
+	}
+
+	out, err := RenderDebugHTML(r)
+	if err != nil {
+		t.Fatalf("RenderDebugHTML failed: %v", err)
+	}
+	if strings.Contains(string(out), "<b>bold</b>") {
+		t.Error("expected log output to be escaped")
+	}
+	if !strings.Contains(string(out), "No events recorded.") {
+		t.Error("expected empty events placeholder")
+	}
+}
+
+func TestRenderDebugHTMLBadEnvelope(t *testing.T) {
+	out, err := RenderDebugHTML(&DebugReport{TxHash: "tx", EnvelopeXdr: "not-xdr"})
+	if err != nil {
+		t.Fatalf("RenderDebugHTML failed: %v", err)
+	}
+	if !strings.Contains(string(out), "Failed to decode envelope") {
+		t.Error("expected envelope decode error in report")
+	}
+}
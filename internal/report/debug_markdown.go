@@ -0,0 +1,197 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/decoder"
+)
+
+// maxMarkdownEvents caps the events table so the report stays pasteable
+const maxMarkdownEvents = 10
+
+// RenderDebugMarkdown renders a GitHub-flavoured Markdown summary of a
+// debugged transaction, sized to be pasted into an issue or chat message.
+// Long output such as logs is folded into <details> blocks.
+func RenderDebugMarkdown(r *DebugReport) []byte {
+	view := buildDebugView(r)
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s\n\n", view.Title)
+	b.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| **Transaction** | %s |\n", mdCode(r.TxHash))
+	if r.Network != "" {
+		fmt.Fprintf(&b, "| **Network** | %s |\n", mdCell(r.Network))
+	}
+	if r.Simulation == nil && r.ResultCode != "" {
+		fmt.Fprintf(&b, "| **Result** | %s |\n", mdCode(r.ResultCode))
+	} else {
+		fmt.Fprintf(&b, "| **Status** | %s |\n", mdCell(view.Status))
+	}
+	if view.Envelope != nil {
+		fmt.Fprintf(&b, "| **Source** | %s |\n", mdCode(view.Envelope.Source))
+		fmt.Fprintf(&b, "| **Fee** | %d stroops |\n", view.Envelope.Fee)
+	}
+	if r.SessionID != "" {
+		fmt.Fprintf(&b, "| **Session** | %s |\n", mdCode(r.SessionID))
+	}
+
+	var exp *decoder.ErrorExplanation
+	if r.Simulation != nil {
+		exp = r.Simulation.ErrorExplanation
+	}
+	if r.ResultExplanation != "" {
+		fmt.Fprintf(&b, "\n%s\n", r.ResultExplanation)
+	}
+	if view.Error != "" || exp != nil {
+		b.WriteString("\n### Error\n\n")
+		if view.Error != "" {
+			fmt.Fprintf(&b, "```\n%s\n```\n", strings.TrimSpace(view.Error))
+		}
+		if exp != nil {
+			b.WriteString("\n")
+			switch {
+			case exp.ContractError != "":
+				fmt.Fprintf(&b, "**%s**", exp.ContractError)
+				if exp.ContractErrorDoc != "" {
+					fmt.Fprintf(&b, ": %s", exp.ContractErrorDoc)
+				}
+				b.WriteString("\n\n")
+			case exp.Summary != "":
+				fmt.Fprintf(&b, "**%s**\n\n", exp.Summary)
+			}
+			if exp.Explanation != "" {
+				fmt.Fprintf(&b, "%s\n", exp.Explanation)
+			}
+		}
+	}
+
+	if len(r.Invocations) > 0 {
+		b.WriteString("\n### Contract calls\n\n")
+		for _, inv := range r.Invocations {
+			fmt.Fprintf(&b, "- %s on %s\n", mdCode(inv.String()), mdCode(inv.ContractID))
+			if inv.Summary != "" {
+				fmt.Fprintf(&b, "  - %s\n", inv.Summary)
+			}
+		}
+	}
+
+	// A lone Soroban operation is already covered by the contract calls
+	if len(r.Operations) > 1 || (len(r.Operations) == 1 && !r.Operations[0].Soroban) {
+		b.WriteString("\n### Operations\n\n")
+		for _, op := range r.Operations {
+			fmt.Fprintf(&b, "%d. %s: %s", op.Index+1, op.Type, op.Description)
+			if op.Result != "" {
+				fmt.Fprintf(&b, " (%s)", mdCode(op.Result))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if events := keyEvents(view.Events); len(events) > 0 {
+		b.WriteString("\n### Key events\n\n")
+		b.WriteString("| # | Type | Contract | Topics | Data |\n|---|---|---|---|---|\n")
+		for _, ev := range events {
+			failed := ""
+			if ev.Failed {
+				failed = " (failed call)"
+			}
+			fmt.Fprintf(&b, "| %d | %s%s | %s | %s | %s |\n",
+				ev.Index, mdCell(ev.Type), failed, mdCode(ev.Contract), mdCode(ev.Topics), mdCode(ev.Data))
+		}
+		if len(view.Events) > len(events) {
+			fmt.Fprintf(&b, "\n_%d of %d events shown._\n", len(events), len(view.Events))
+		}
+	}
+
+	if len(r.Suggestions) > 0 {
+		b.WriteString("\n### Suggested cause\n\n")
+		for _, s := range r.Suggestions {
+			fmt.Fprintf(&b, "- %s (%s confidence)", s.Description, s.Confidence)
+			if s.Evidence != "" {
+				fmt.Fprintf(&b, ": %s", s.Evidence)
+			}
+			b.WriteString("\n")
+			for _, step := range s.NextSteps {
+				fmt.Fprintf(&b, "  - %s\n", step)
+			}
+		}
+	}
+
+	if len(r.TokenFlows) > 0 {
+		b.WriteString("\n### Token flows\n\n")
+		for _, line := range r.TokenFlows {
+			fmt.Fprintf(&b, "- %s\n", mdCode(line))
+		}
+	}
+
+	if view.Budget != nil {
+		b.WriteString("\n### Resources\n\n")
+		fmt.Fprintf(&b, "- CPU: %d / %d instructions (%.1f%%)\n",
+			view.Budget.CPUInstructions, view.Budget.CPULimit, view.Budget.CPUUsagePercent)
+		fmt.Fprintf(&b, "- Memory: %d / %d bytes (%.1f%%)\n",
+			view.Budget.MemoryBytes, view.Budget.MemoryLimit, view.Budget.MemoryUsagePercent)
+	}
+
+	if len(view.Logs) > 0 {
+		fmt.Fprintf(&b, "\n<details>\n<summary>Logs (%d)</summary>\n\n```\n", len(view.Logs))
+		for _, log := range view.Logs {
+			b.WriteString(log + "\n")
+		}
+		b.WriteString("```\n\n</details>\n")
+	}
+
+	b.WriteString("\n---\n")
+	generated := view.GeneratedAt.UTC().Format(time.RFC3339)
+	if r.ErstVersion != "" {
+		fmt.Fprintf(&b, "_Generated by erst %s at %s_\n", r.ErstVersion, generated)
+	} else {
+		fmt.Fprintf(&b, "_Generated by erst at %s_\n", generated)
+	}
+	return []byte(b.String())
+}
+
+// keyEvents picks the events worth pasting: contract events and anything
+// from a failed call, falling back to the first events when none qualify
+func keyEvents(events []debugEventRow) []debugEventRow {
+	var key []debugEventRow
+	for _, ev := range events {
+		if ev.Type == "contract" || ev.Failed {
+			key = append(key, ev)
+		}
+	}
+	if len(key) == 0 {
+		key = events
+	}
+	if len(key) > maxMarkdownEvents {
+		key = key[:maxMarkdownEvents]
+	}
+	return key
+}
+
+// mdCell makes s safe inside a table cell
+func mdCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// mdCode renders s as inline code, using a longer fence when s itself
+// contains backticks
+func mdCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = mdCell(s)
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+func TestRenderDebugMarkdown(t *testing.T) {
+	contract := "CABC"
+	r := &DebugReport{
+		TxHash:      "deadbeef",
+		Network:     "testnet",
+		ErstVersion: "v1.2.3",
+		GeneratedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Simulation: &simulator.SimulationResponse{
+			Status: "error",
+			Error:  "HostError: Error(Contract, #3)",
+			Logs:   []string{"first log"},
+			DiagnosticEvents: []simulator.DiagnosticEvent{
+				{EventType: "diagnostic", Topics: []string{"fn_call"}, Data: "x", InSuccessfulContractCall: true},
+				{EventType: "contract", ContractID: &contract, Topics: []string{"transfer"}, Data: "a|b", InSuccessfulContractCall: true},
+			},
+			ErrorExplanation: &decoder.ErrorExplanation{
+				Error:            "Error(Contract, #3)",
+				ContractError:    "Error::InsufficientBalance",
+				ContractErrorDoc: "The sender cannot cover the amount",
+				Explanation:      "The contract rejected the call.",
+			},
+		},
+		Suggestions: []decoder.Suggestion{
+			{Description: "Insufficient balance", Confidence: "high", Evidence: "balance", NextSteps: []string{"Fund the sender"}},
+		},
+		TokenFlows: []string{"G... -> 10 XLM -> G..."},
+	}
+
+	md := string(RenderDebugMarkdown(r))
+
+	for _, want := range []string{
+		"## Transaction Debug Report",
+		"| **Transaction** | `deadbeef` |",
+		"| **Network** | testnet |",
+		"| **Status** | error |",
+		"```\nHostError: Error(Contract, #3)\n```",
+		"**Error::InsufficientBalance**: The sender cannot cover the amount",
+		"| 2 | contract | `CABC` | `transfer` | `a\\|b` |",
+		"- Insufficient balance (high confidence): balance",
+		"  - Fund the sender",
+		"- `G... -> 10 XLM -> G...`",
+		"<summary>Logs (1)</summary>",
+		"_Generated by erst v1.2.3 at 2025-01-02T03:04:05Z_",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected markdown to contain %q, got:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "fn_call") {
+		t.Error("expected only key events in the events table")
+	}
+}
+
+func TestRenderDebugMarkdownNoSimulation(t *testing.T) {
+	md := string(RenderDebugMarkdown(&DebugReport{TxHash: "tx"}))
+	if !strings.Contains(md, "| **Status** | unknown |") {
+		t.Errorf("expected unknown status, got:\n%s", md)
+	}
+	if strings.Contains(md, "### Error") {
+		t.Error("expected no error section without a simulation")
+	}
+}
+
+func TestMdCode(t *testing.T) {
+	tests := map[string]string{
+		"":      "",
+		"plain": "`plain`",
+		"a`b":   "``a`b``",
+		"`a":    "`` `a ``",
+	}
+	for in, want := range tests {
+		if got := mdCode(in); got != want {
+			t.Errorf("mdCode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
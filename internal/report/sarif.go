@@ -0,0 +1,298 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifSourceRoot is the base the artifact URIs are relative to; CI tools
+	// resolve it to the repository checkout
+	sarifSourceRoot = "%SRCROOT%"
+)
+
+// SARIFLog is a SARIF 2.1.0 log, the subset of the format code scanning
+// tools need to annotate a repository
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+type SARIFRule struct {
+	ID               string        `json:"id"`
+	ShortDescription SARIFMessage  `json:"shortDescription"`
+	FullDescription  *SARIFMessage `json:"fullDescription,omitempty"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFResult struct {
+	RuleID              string                 `json:"ruleId"`
+	RuleIndex           int                    `json:"ruleIndex"`
+	Level               string                 `json:"level"`
+	Message             SARIFMessage           `json:"message"`
+	Locations           []SARIFLocation        `json:"locations,omitempty"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation *SARIFPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+type SARIFArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type SARIFRegion struct {
+	StartLine   uint32 `json:"startLine"`
+	StartColumn uint32 `json:"startColumn,omitempty"`
+	EndColumn   uint32 `json:"endColumn,omitempty"`
+}
+
+type SARIFLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+// sourceLocation is the DWARF location the simulator reports, as JSON, for
+// a failure in a contract built with debug info
+type sourceLocation struct {
+	File      string  `json:"file"`
+	Line      uint32  `json:"line"`
+	Column    uint32  `json:"column"`
+	ColumnEnd *uint32 `json:"column_end,omitempty"`
+}
+
+// BuildSARIF turns the failed transactions among reports into SARIF results,
+// one per transaction; successful ones contribute nothing. Source files under
+// sourceRoot, normally the repository root, are made relative to it so code
+// scanning can match them to the checkout.
+func BuildSARIF(reports []*DebugReport, sourceRoot string) *SARIFLog {
+	driver := SARIFDriver{
+		Name:           "erst",
+		InformationURI: "https://github.com/dotandev/hintents",
+		Rules:          []SARIFRule{},
+	}
+	results := []SARIFResult{}
+	ruleIndex := map[string]int{}
+
+	for _, r := range reports {
+		if driver.Version == "" {
+			driver.Version = r.ErstVersion
+		}
+		rule, ok := sarifRule(r)
+		if !ok {
+			continue
+		}
+		idx, seen := ruleIndex[rule.ID]
+		if !seen {
+			idx = len(driver.Rules)
+			ruleIndex[rule.ID] = idx
+			driver.Rules = append(driver.Rules, rule)
+		}
+
+		result := SARIFResult{
+			RuleID:    rule.ID,
+			RuleIndex: idx,
+			Level:     "error",
+			Message:   SARIFMessage{Text: sarifMessage(r)},
+			Properties: map[string]interface{}{
+				"txHash":  r.TxHash,
+				"network": r.Network,
+			},
+		}
+		if r.TxHash != "" {
+			result.PartialFingerprints = map[string]string{"transactionHash/v1": r.TxHash}
+		}
+		if loc, ok := sarifLocation(r, sourceRoot); ok {
+			result.Locations = []SARIFLocation{loc}
+		}
+		results = append(results, result)
+	}
+
+	return &SARIFLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []SARIFRun{{Tool: SARIFTool{Driver: driver}, Results: results}},
+	}
+}
+
+// sarifRule names the failure of r, e.g. "soroban/Budget/ExceededLimit" or
+// "stellar/tx_bad_seq". It reports false when r did not fail.
+func sarifRule(r *DebugReport) (SARIFRule, bool) {
+	sim := r.Simulation
+	if sim == nil {
+		if r.ResultCode == "" || r.ResultCode == "tx_success" {
+			return SARIFRule{}, false
+		}
+		return SARIFRule{
+			ID:               "stellar/" + r.ResultCode,
+			ShortDescription: SARIFMessage{Text: "Transaction failed with " + r.ResultCode},
+			FullDescription:  sarifText(r.ResultExplanation),
+		}, true
+	}
+	if sim.Status != "error" {
+		return SARIFRule{}, false
+	}
+
+	exp := sim.ErrorExplanation
+	switch {
+	case exp == nil:
+		return SARIFRule{
+			ID:               "soroban/simulation-failure",
+			ShortDescription: SARIFMessage{Text: "Simulation failed"},
+		}, true
+	case exp.Trap != "":
+		return SARIFRule{
+			ID:               "soroban/WasmVm/" + exp.Trap,
+			ShortDescription: SARIFMessage{Text: exp.Summary},
+			FullDescription:  sarifText(exp.Explanation),
+		}, true
+	case exp.ContractError != "":
+		// The same contract error always means the same thing, whatever
+		// its number
+		return SARIFRule{
+			ID:               "soroban/Contract/" + exp.ContractError,
+			ShortDescription: SARIFMessage{Text: exp.ContractError},
+			FullDescription:  sarifText(exp.ContractErrorDoc),
+		}, true
+	default:
+		return SARIFRule{
+			ID:               "soroban/" + exp.Type + "/" + strings.TrimPrefix(exp.Code, "#"),
+			ShortDescription: SARIFMessage{Text: exp.Summary},
+			FullDescription:  sarifText(exp.Explanation),
+		}, true
+	}
+}
+
+func sarifMessage(r *DebugReport) string {
+	var b strings.Builder
+	if r.Simulation == nil {
+		fmt.Fprintf(&b, "Transaction %s failed with %s.", r.TxHash, r.ResultCode)
+		if r.ResultExplanation != "" {
+			b.WriteString(" " + r.ResultExplanation)
+		}
+		return b.String()
+	}
+
+	sim := r.Simulation
+	fmt.Fprintf(&b, "Simulation of transaction %s", r.TxHash)
+	if r.Network != "" {
+		fmt.Fprintf(&b, " on %s", r.Network)
+	}
+	b.WriteString(" failed")
+	if sim.Error != "" {
+		fmt.Fprintf(&b, ": %s", strings.TrimSpace(sim.Error))
+	}
+	b.WriteString(".")
+	if exp := sim.ErrorExplanation; exp != nil {
+		if exp.ContractError != "" && exp.ContractErrorDoc != "" {
+			fmt.Fprintf(&b, " %s: %s", exp.ContractError, exp.ContractErrorDoc)
+		}
+		if exp.Explanation != "" {
+			b.WriteString(" " + exp.Explanation)
+		}
+	}
+	if len(r.Suggestions) > 0 && r.Suggestions[0].Evidence != "" {
+		// Only rules that matched the failure itself have evidence
+		fmt.Fprintf(&b, " %s", r.Suggestions[0].Description)
+	}
+	return b.String()
+}
+
+// sarifLocation points at the source line of the failure when the simulator
+// mapped it, and otherwise at the contract function it happened in
+func sarifLocation(r *DebugReport, sourceRoot string) (SARIFLocation, bool) {
+	var loc SARIFLocation
+	if r.Simulation == nil {
+		return loc, false
+	}
+
+	var src sourceLocation
+	if r.Simulation.SourceLocation != "" && json.Unmarshal([]byte(r.Simulation.SourceLocation), &src) == nil && src.File != "" {
+		region := &SARIFRegion{StartLine: src.Line, StartColumn: src.Column}
+		if src.ColumnEnd != nil {
+			region.EndColumn = *src.ColumnEnd
+		}
+		if region.StartLine == 0 {
+			region = nil
+		}
+		loc.PhysicalLocation = &SARIFPhysicalLocation{
+			ArtifactLocation: sarifArtifact(src.File, sourceRoot),
+			Region:           region,
+		}
+	}
+
+	contract, function := "", ""
+	if path := simulator.FailurePath(r.Simulation.CallTree); len(path) > 0 {
+		origin := path[len(path)-1]
+		contract, function = origin.Contract, origin.Function
+	} else if len(r.Invocations) > 0 {
+		contract, function = r.Invocations[0].ContractID, r.Invocations[0].Function
+	}
+	if function != "" {
+		loc.LogicalLocations = []SARIFLogicalLocation{{
+			Name:               function,
+			FullyQualifiedName: contract + "::" + function,
+			Kind:               "function",
+		}}
+	}
+
+	return loc, loc.PhysicalLocation != nil || loc.LogicalLocations != nil
+}
+
+// sarifText is nil for empty text, which SARIF does not allow
+func sarifText(text string) *SARIFMessage {
+	if text == "" {
+		return nil
+	}
+	return &SARIFMessage{Text: text}
+}
+
+// sarifArtifact makes file relative to sourceRoot when it lies inside it
+func sarifArtifact(file, sourceRoot string) SARIFArtifactLocation {
+	if filepath.IsAbs(file) && sourceRoot != "" {
+		if rel, err := filepath.Rel(sourceRoot, file); err == nil && !strings.HasPrefix(rel, "..") {
+			return SARIFArtifactLocation{URI: filepath.ToSlash(rel), URIBaseID: sarifSourceRoot}
+		}
+		return SARIFArtifactLocation{URI: "file://" + filepath.ToSlash(file)}
+	}
+	return SARIFArtifactLocation{URI: filepath.ToSlash(file), URIBaseID: sarifSourceRoot}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+func TestBuildSARIF(t *testing.T) {
+	failed := &DebugReport{
+		TxHash:      "deadbeef",
+		Network:     "testnet",
+		ErstVersion: "v1.2.3",
+		Simulation: &simulator.SimulationResponse{
+			Status:           "error",
+			Error:            "HostError: Error(Budget, ExceededLimit)",
+			ErrorExplanation: decoder.ExplainError("Error(Budget, ExceededLimit)"),
+			SourceLocation:   `{"file":"/repo/contracts/token/src/lib.rs","line":42,"column":9}`,
+			CallTree: []*simulator.CallNode{
+				{Contract: "CABC", Function: "transfer", Status: simulator.CallFailed},
+			},
+		},
+	}
+	passed := &DebugReport{TxHash: "cafe", Simulation: &simulator.SimulationResponse{Status: "success"}}
+	again := &DebugReport{
+		TxHash: "f00d",
+		Simulation: &simulator.SimulationResponse{
+			Status:           "error",
+			ErrorExplanation: decoder.ExplainError("Error(Budget, ExceededLimit)"),
+		},
+	}
+
+	log := BuildSARIF([]*DebugReport{failed, passed, again}, "/repo")
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log header: %+v", log)
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Version != "v1.2.3" {
+		t.Errorf("driver version = %q", run.Tool.Driver.Version)
+	}
+	if len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].ID != "soroban/Budget/ExceededLimit" {
+		t.Fatalf("expected one shared budget rule, got %+v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("expected a result per failed transaction, got %d", len(run.Results))
+	}
+
+	res := run.Results[0]
+	if res.Level != "error" || res.RuleIndex != 0 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	loc := res.Locations[0]
+	if loc.PhysicalLocation == nil {
+		t.Fatal("expected a physical location from the source mapping")
+	}
+	if got := loc.PhysicalLocation.ArtifactLocation; got.URI != "contracts/token/src/lib.rs" || got.URIBaseID != "%SRCROOT%" {
+		t.Errorf("artifact location = %+v", got)
+	}
+	if loc.PhysicalLocation.Region.StartLine != 42 {
+		t.Errorf("start line = %d", loc.PhysicalLocation.Region.StartLine)
+	}
+	if len(loc.LogicalLocations) != 1 || loc.LogicalLocations[0].FullyQualifiedName != "CABC::transfer" {
+		t.Errorf("logical locations = %+v", loc.LogicalLocations)
+	}
+	if len(run.Results[1].Locations) != 0 {
+		t.Errorf("expected no location without source mapping or calls, got %+v", run.Results[1].Locations)
+	}
+
+	out, err := json.Marshal(log)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if doc["$schema"] == nil {
+		t.Error("expected $schema in the log")
+	}
+}
+
+func TestBuildSARIFClassicResult(t *testing.T) {
+	log := BuildSARIF([]*DebugReport{
+		{TxHash: "a", ResultCode: "tx_bad_seq", ResultExplanation: "The sequence number is wrong."},
+		{TxHash: "b", ResultCode: "tx_success"},
+	}, "")
+
+	results := log.Runs[0].Results
+	if len(results) != 1 || results[0].RuleID != "stellar/tx_bad_seq" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
+
+func TestSARIFArtifact(t *testing.T) {
+	tests := []struct {
+		file, root string
+		want       SARIFArtifactLocation
+	}{
+		{"src/lib.rs", "/repo", SARIFArtifactLocation{URI: "src/lib.rs", URIBaseID: "%SRCROOT%"}},
+		{"/repo/src/lib.rs", "/repo", SARIFArtifactLocation{URI: "src/lib.rs", URIBaseID: "%SRCROOT%"}},
+		{"/elsewhere/lib.rs", "/repo", SARIFArtifactLocation{URI: "file:///elsewhere/lib.rs"}},
+	}
+	for _, tt := range tests {
+		if got := sarifArtifact(tt.file, tt.root); got != tt.want {
+			t.Errorf("sarifArtifact(%q, %q) = %+v, want %+v", tt.file, tt.root, got, tt.want)
+		}
+	}
+}
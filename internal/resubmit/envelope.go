@@ -0,0 +1,213 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package resubmit repairs and re-signs a transaction envelope so that a
+// failed transaction can be sent again.
+package resubmit
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Change records one field a repair modified
+type Change struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Field, c.From, c.To)
+}
+
+// transaction returns the transaction of a v1 envelope. Fee-bump envelopes
+// wrap a transaction signed by someone else and v0 envelopes predate Soroban,
+// so neither is repaired.
+func transaction(env *xdr.TransactionEnvelope) (*xdr.Transaction, error) {
+	switch env.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		return &env.V1.Tx, nil
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		return nil, fmt.Errorf("fee-bump envelopes are not supported; resubmit the inner transaction")
+	default:
+		return nil, fmt.Errorf("unsupported envelope type %s", env.Type)
+	}
+}
+
+// SourceAccount returns the account whose sequence number the transaction
+// consumes
+func SourceAccount(env *xdr.TransactionEnvelope) (xdr.AccountId, error) {
+	tx, err := transaction(env)
+	if err != nil {
+		return xdr.AccountId{}, err
+	}
+	return tx.SourceAccount.ToAccountId(), nil
+}
+
+// IsSoroban reports whether the transaction invokes a contract or manages
+// contract state, and so needs resources from preflight
+func IsSoroban(env *xdr.TransactionEnvelope) bool {
+	tx, err := transaction(env)
+	if err != nil {
+		return false
+	}
+	for _, op := range tx.Operations {
+		switch op.Body.Type {
+		case xdr.OperationTypeInvokeHostFunction, xdr.OperationTypeExtendFootprintTtl, xdr.OperationTypeRestoreFootprint:
+			return true
+		}
+	}
+	return false
+}
+
+// SetSequence sets the transaction's sequence number. It returns nil when
+// the number is unchanged.
+func SetSequence(env *xdr.TransactionEnvelope, seq int64) (*Change, error) {
+	tx, err := transaction(env)
+	if err != nil {
+		return nil, err
+	}
+	if int64(tx.SeqNum) == seq {
+		return nil, nil
+	}
+	c := &Change{Field: "sequence", From: strconv.FormatInt(int64(tx.SeqNum), 10), To: strconv.FormatInt(seq, 10)}
+	tx.SeqNum = xdr.SequenceNumber(seq)
+	return c, nil
+}
+
+// SetFee sets the transaction's total fee in stroops
+func SetFee(env *xdr.TransactionEnvelope, fee uint32) (*Change, error) {
+	tx, err := transaction(env)
+	if err != nil {
+		return nil, err
+	}
+	if uint32(tx.Fee) == fee {
+		return nil, nil
+	}
+	c := &Change{Field: "fee", From: strconv.FormatUint(uint64(tx.Fee), 10), To: strconv.FormatUint(uint64(fee), 10)}
+	tx.Fee = xdr.Uint32(fee)
+	return c, nil
+}
+
+// ExtendTimeBounds moves an expired upper time bound to now+timeout. A
+// transaction without an upper bound, or one that has not expired, is left
+// alone.
+func ExtendTimeBounds(env *xdr.TransactionEnvelope, now time.Time, timeout time.Duration) (*Change, error) {
+	tx, err := transaction(env)
+	if err != nil {
+		return nil, err
+	}
+	var bounds *xdr.TimeBounds
+	switch tx.Cond.Type {
+	case xdr.PreconditionTypePrecondTime:
+		bounds = tx.Cond.TimeBounds
+	case xdr.PreconditionTypePrecondV2:
+		bounds = tx.Cond.V2.TimeBounds
+	}
+	if bounds == nil || bounds.MaxTime == 0 || int64(bounds.MaxTime) >= now.Unix() {
+		return nil, nil
+	}
+
+	maxTime := now.Add(timeout).Unix()
+	c := &Change{
+		Field: "max_time",
+		From:  time.Unix(int64(bounds.MaxTime), 0).UTC().Format(time.RFC3339),
+		To:    time.Unix(maxTime, 0).UTC().Format(time.RFC3339),
+	}
+	bounds.MaxTime = xdr.TimePoint(maxTime)
+	return c, nil
+}
+
+// ApplyPreflight replaces the transaction's Soroban resources with those from
+// an RPC simulateTransaction result and adjusts the fee by the difference in
+// resource fee, keeping the inclusion fee the transaction already bid
+func ApplyPreflight(env *xdr.TransactionEnvelope, transactionData string) ([]Change, error) {
+	tx, err := transaction(env)
+	if err != nil {
+		return nil, err
+	}
+	var data xdr.SorobanTransactionData
+	if err := xdr.SafeUnmarshalBase64(transactionData, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode preflight transaction data: %w", err)
+	}
+
+	var oldResourceFee int64
+	var changes []Change
+	if old, ok := tx.Ext.GetSorobanData(); ok {
+		oldResourceFee = int64(old.ResourceFee)
+		if old.Resources.Instructions != data.Resources.Instructions {
+			changes = append(changes, Change{Field: "instructions", From: fmt.Sprint(old.Resources.Instructions), To: fmt.Sprint(data.Resources.Instructions)})
+		}
+		if old.Resources.DiskReadBytes != data.Resources.DiskReadBytes {
+			changes = append(changes, Change{Field: "read_bytes", From: fmt.Sprint(old.Resources.DiskReadBytes), To: fmt.Sprint(data.Resources.DiskReadBytes)})
+		}
+		if old.Resources.WriteBytes != data.Resources.WriteBytes {
+			changes = append(changes, Change{Field: "write_bytes", From: fmt.Sprint(old.Resources.WriteBytes), To: fmt.Sprint(data.Resources.WriteBytes)})
+		}
+		if len(old.Resources.Footprint.ReadOnly) != len(data.Resources.Footprint.ReadOnly) ||
+			len(old.Resources.Footprint.ReadWrite) != len(data.Resources.Footprint.ReadWrite) {
+			changes = append(changes, Change{
+				Field: "footprint",
+				From:  fmt.Sprintf("%d read-only, %d read-write", len(old.Resources.Footprint.ReadOnly), len(old.Resources.Footprint.ReadWrite)),
+				To:    fmt.Sprintf("%d read-only, %d read-write", len(data.Resources.Footprint.ReadOnly), len(data.Resources.Footprint.ReadWrite)),
+			})
+		}
+	} else {
+		changes = append(changes, Change{Field: "soroban_data", From: "none", To: "from preflight"})
+	}
+	if oldResourceFee != int64(data.ResourceFee) {
+		changes = append(changes, Change{Field: "resource_fee", From: fmt.Sprint(oldResourceFee), To: fmt.Sprint(data.ResourceFee)})
+	}
+	tx.Ext = xdr.TransactionExt{V: 1, SorobanData: &data}
+
+	inclusionFee := int64(tx.Fee) - oldResourceFee
+	if minInclusion := int64(len(tx.Operations)) * 100; inclusionFee < minInclusion {
+		inclusionFee = minInclusion
+	}
+	fee := inclusionFee + int64(data.ResourceFee)
+	if fee > math.MaxUint32 {
+		return nil, fmt.Errorf("fee %d exceeds the maximum of %d stroops", fee, uint32(math.MaxUint32))
+	}
+	if c, _ := SetFee(env, uint32(fee)); c != nil {
+		changes = append(changes, *c)
+	}
+	return changes, nil
+}
+
+// StripSignatures removes the envelope's signatures, which no longer match
+// once the transaction is modified
+func StripSignatures(env *xdr.TransactionEnvelope) error {
+	if _, err := transaction(env); err != nil {
+		return err
+	}
+	env.V1.Signatures = nil
+	return nil
+}
+
+// Sign replaces the envelope's signatures with ones from signers and returns
+// the transaction hash
+func Sign(env *xdr.TransactionEnvelope, passphrase string, signers ...*keypair.Full) (string, error) {
+	if err := StripSignatures(env); err != nil {
+		return "", err
+	}
+	hash, err := network.HashTransactionInEnvelope(*env, passphrase)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash transaction: %w", err)
+	}
+	for _, kp := range signers {
+		sig, err := kp.SignDecorated(hash[:])
+		if err != nil {
+			return "", fmt.Errorf("failed to sign with %s: %w", kp.Address(), err)
+		}
+		env.V1.Signatures = append(env.V1.Signatures, sig)
+	}
+	return hex.EncodeToString(hash[:]), nil
+}
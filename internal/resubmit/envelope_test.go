@@ -0,0 +1,137 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package resubmit
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func invokeEnvelope(t *testing.T, source *keypair.Full, resourceFee int64) *xdr.TransactionEnvelope {
+	t.Helper()
+	account, err := xdr.AddressToMuxedAccount(source.Address())
+	require.NoError(t, err)
+
+	var contract xdr.ContractId
+	contractAddr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contract}
+	op := xdr.Operation{Body: xdr.OperationBody{
+		Type: xdr.OperationTypeInvokeHostFunction,
+		InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{HostFunction: xdr.HostFunction{
+			Type:           xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+			InvokeContract: &xdr.InvokeContractArgs{ContractAddress: contractAddr, FunctionName: "transfer"},
+		}},
+	}}
+
+	data := xdr.SorobanTransactionData{
+		Resources:   xdr.SorobanResources{Instructions: 1000},
+		ResourceFee: xdr.Int64(resourceFee),
+	}
+	return &xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: account,
+				Fee:           xdr.Uint32(100 + resourceFee),
+				SeqNum:        10,
+				Cond: xdr.Preconditions{
+					Type:       xdr.PreconditionTypePrecondTime,
+					TimeBounds: &xdr.TimeBounds{MaxTime: 1000},
+				},
+				Operations: []xdr.Operation{op},
+				Ext:        xdr.TransactionExt{V: 1, SorobanData: &data},
+			},
+			Signatures: []xdr.DecoratedSignature{{}},
+		},
+	}
+}
+
+func TestSetSequenceAndFee(t *testing.T) {
+	env := invokeEnvelope(t, keypair.MustRandom(), 500)
+	assert.True(t, IsSoroban(env))
+
+	c, err := SetSequence(env, 42)
+	require.NoError(t, err)
+	assert.Equal(t, "sequence: 10 -> 42", c.String())
+	c, err = SetSequence(env, 42)
+	require.NoError(t, err)
+	assert.Nil(t, c, "unchanged values are not reported")
+
+	c, err = SetFee(env, 9000)
+	require.NoError(t, err)
+	assert.Equal(t, "600", c.From)
+	assert.Equal(t, xdr.Uint32(9000), env.V1.Tx.Fee)
+}
+
+func TestExtendTimeBounds(t *testing.T) {
+	env := invokeEnvelope(t, keypair.MustRandom(), 0)
+	now := time.Unix(5000, 0)
+
+	c, err := ExtendTimeBounds(env, now, 5*time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	assert.Equal(t, xdr.TimePoint(5300), env.V1.Tx.Cond.TimeBounds.MaxTime)
+
+	c, err = ExtendTimeBounds(env, now, 5*time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, c, "bounds still in the future are kept")
+}
+
+func TestApplyPreflight(t *testing.T) {
+	env := invokeEnvelope(t, keypair.MustRandom(), 500)
+	env.V1.Tx.Fee = 300 + 500 // inclusion fee of 300
+
+	data := xdr.SorobanTransactionData{
+		Resources:   xdr.SorobanResources{Instructions: 5000, WriteBytes: 64},
+		ResourceFee: 2000,
+	}
+	b64, err := xdr.MarshalBase64(data)
+	require.NoError(t, err)
+
+	changes, err := ApplyPreflight(env, b64)
+	require.NoError(t, err)
+	assert.Equal(t, xdr.Uint32(300+2000), env.V1.Tx.Fee, "the inclusion fee is kept")
+	got, ok := env.V1.Tx.Ext.GetSorobanData()
+	require.True(t, ok)
+	assert.Equal(t, xdr.Uint32(5000), got.Resources.Instructions)
+
+	fields := map[string]bool{}
+	for _, c := range changes {
+		fields[c.Field] = true
+	}
+	assert.True(t, fields["instructions"])
+	assert.True(t, fields["write_bytes"])
+	assert.True(t, fields["resource_fee"])
+	assert.True(t, fields["fee"])
+
+	_, err = ApplyPreflight(env, "not-xdr")
+	assert.Error(t, err)
+}
+
+func TestSign(t *testing.T) {
+	source := keypair.MustRandom()
+	env := invokeEnvelope(t, source, 0)
+
+	hash, err := Sign(env, network.TestNetworkPassphrase, source)
+	require.NoError(t, err)
+	require.Len(t, env.V1.Signatures, 1, "old signatures are replaced")
+
+	raw, err := hex.DecodeString(hash)
+	require.NoError(t, err)
+	assert.NoError(t, source.Verify(raw, env.V1.Signatures[0].Signature))
+	assert.Equal(t, source.Hint(), [4]byte(env.V1.Signatures[0].Hint))
+}
+
+func TestFeeBumpRejected(t *testing.T) {
+	env := &xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump}
+	_, err := SetSequence(env, 1)
+	assert.ErrorContains(t, err, "fee-bump")
+	assert.False(t, IsSoroban(env))
+}
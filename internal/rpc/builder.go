@@ -18,18 +18,29 @@ type ClientOption func(*clientBuilder) error
 type clientBuilder struct {
 	network      Network
 	token        string
+	headers      http.Header
 	horizonURL   string
 	sorobanURL   string
 	altURLs      []string
+	archiveURLs  []string
 	cacheEnabled bool
 	config       *NetworkConfig
+	passphrase   string
 	httpClient   *http.Client
+	retry        RetryConfig
+	transport    transportOptions
+	timeout      time.Duration
+	statePath    string
+	wasmCache    *WasmCache
+	batchSize    int
+	batchWorkers int
 }
 
 func newBuilder() *clientBuilder {
 	return &clientBuilder{
 		network:      Mainnet,
 		cacheEnabled: true,
+		retry:        DefaultRetryConfig(),
 	}
 }
 
@@ -50,6 +61,41 @@ func WithToken(token string) ClientOption {
 	}
 }
 
+// WithHeaders adds headers sent with every Horizon and Soroban RPC request,
+// including requests to failover and archive endpoints. Managed providers use
+// these for API keys; an Authorization header here takes precedence over
+// WithToken. Headers are not applied to a client supplied with WithHTTPClient.
+func WithHeaders(headers map[string]string) ClientOption {
+	return func(b *clientBuilder) error {
+		for name, value := range headers {
+			if err := validateHeader(name, value); err != nil {
+				return err
+			}
+			if b.headers == nil {
+				b.headers = make(http.Header)
+			}
+			b.headers.Set(name, value)
+		}
+		return nil
+	}
+}
+
+// WithHeaderLines is WithHeaders for "Name: value" specifications, as given
+// on the command line
+func WithHeaderLines(lines []string) ClientOption {
+	return func(b *clientBuilder) error {
+		headers := make(map[string]string, len(lines))
+		for _, line := range lines {
+			name, value, err := ParseHeader(line)
+			if err != nil {
+				return err
+			}
+			headers[name] = value
+		}
+		return WithHeaders(headers)(b)
+	}
+}
+
 func WithHorizonURL(url string) ClientOption {
 	return func(b *clientBuilder) error {
 		if url != "" {
@@ -78,6 +124,20 @@ func WithAltURLs(urls []string) ClientOption {
 	}
 }
 
+// WithArchiveURLs adds Horizon-compatible full-history endpoints used when the
+// configured Soroban RPC and Horizon nodes no longer have a transaction
+func WithArchiveURLs(urls []string) ClientOption {
+	return func(b *clientBuilder) error {
+		for _, url := range urls {
+			if err := isValidURL(url); err != nil {
+				return errors.WrapValidationError(fmt.Sprintf("invalid archive URL: %v", err))
+			}
+		}
+		b.archiveURLs = urls
+		return nil
+	}
+}
+
 func WithSorobanURL(url string) ClientOption {
 	return func(b *clientBuilder) error {
 		if url != "" {
@@ -103,6 +163,15 @@ func WithNetworkConfig(cfg NetworkConfig) ClientOption {
 	}
 }
 
+// WithNetworkPassphrase overrides the passphrase of the network's
+// configuration, for local networks started with a custom one
+func WithNetworkPassphrase(passphrase string) ClientOption {
+	return func(b *clientBuilder) error {
+		b.passphrase = passphrase
+		return nil
+	}
+}
+
 func WithCacheEnabled(enabled bool) ClientOption {
 	return func(b *clientBuilder) error {
 		b.cacheEnabled = enabled
@@ -117,6 +186,141 @@ func WithHTTPClient(client *http.Client) ClientOption {
 	}
 }
 
+// WithRetryConfig sets how transient failures (timeouts, connection resets,
+// 429 and 5xx responses) are retried. Individual calls can override it with
+// a context from ContextWithRetryConfig.
+func WithRetryConfig(cfg RetryConfig) ClientOption {
+	return func(b *clientBuilder) error {
+		if cfg.MaxRetries < 0 {
+			return errors.WrapValidationError("max retries cannot be negative")
+		}
+		if cfg.InitialBackoff < 0 || cfg.MaxBackoff < 0 {
+			return errors.WrapValidationError("retry backoff cannot be negative")
+		}
+		b.retry = cfg
+		return nil
+	}
+}
+
+// WithRateLimit limits the client to requestsPerSecond requests, with bursts
+// of up to burst requests (see NewRateLimiter), so that batch and watch modes
+// stay within a public provider's limits. A rate of 0 leaves the client
+// unlimited. The limit is not applied to a client supplied with WithHTTPClient.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(b *clientBuilder) error {
+		if requestsPerSecond == 0 {
+			b.transport.rateLimiter = nil
+			return nil
+		}
+		limiter, err := NewRateLimiter(requestsPerSecond, burst)
+		if err != nil {
+			return err
+		}
+		b.transport.rateLimiter = limiter
+		return nil
+	}
+}
+
+// WithRateLimiter is WithRateLimit for a limiter shared with other clients
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(b *clientBuilder) error {
+		b.transport.rateLimiter = limiter
+		return nil
+	}
+}
+
+// WithMaxConcurrentRequests caps the requests the client has in flight at
+// once; 0 leaves it uncapped. The cap is not applied to a client supplied with
+// WithHTTPClient.
+func WithMaxConcurrentRequests(max int) ClientOption {
+	return func(b *clientBuilder) error {
+		if max == 0 {
+			b.transport.concurrency = nil
+			return nil
+		}
+		limiter, err := NewConcurrencyLimiter(max)
+		if err != nil {
+			return err
+		}
+		b.transport.concurrency = limiter
+		return nil
+	}
+}
+
+// WithConcurrencyLimiter is WithMaxConcurrentRequests for a limiter shared
+// with other clients
+func WithConcurrencyLimiter(limiter *ConcurrencyLimiter) ClientOption {
+	return func(b *clientBuilder) error {
+		b.transport.concurrency = limiter
+		return nil
+	}
+}
+
+// WithRequestTimeout bounds each RPC call, retries included, to timeout.
+// A timeout of 0 leaves calls bounded only by their context. The timeout is
+// not applied to a client supplied with WithHTTPClient.
+func WithRequestTimeout(timeout time.Duration) ClientOption {
+	return func(b *clientBuilder) error {
+		if timeout < 0 {
+			return errors.WrapValidationError("RPC timeout cannot be negative")
+		}
+		b.timeout = timeout
+		return nil
+	}
+}
+
+// WithProxy sends requests through an HTTP, HTTPS or SOCKS5 proxy such as
+// "socks5h://127.0.0.1:9050", instead of the proxies named by HTTPS_PROXY,
+// HTTP_PROXY and ALL_PROXY. An empty proxy keeps the environment's. The proxy
+// is not applied to a client supplied with WithHTTPClient.
+func WithProxy(proxy string) ClientOption {
+	return func(b *clientBuilder) error {
+		if proxy == "" {
+			b.transport.proxy = nil
+			return nil
+		}
+		fn, err := NewProxyFunc(proxy)
+		if err != nil {
+			return err
+		}
+		b.transport.proxy = fn
+		return nil
+	}
+}
+
+// WithWasmCache sets where fetched contract code is cached. Without it the
+// client uses DefaultWasmCache. WithCacheEnabled(false) disables both caches.
+func WithWasmCache(cache *WasmCache) ClientOption {
+	return func(b *clientBuilder) error {
+		b.wasmCache = cache
+		return nil
+	}
+}
+
+// WithEndpointStateFile persists endpoint health to path so that later clients
+// for the same network skip endpoints whose circuit is open and start on the
+// endpoint that last worked. See DefaultEndpointStatePath.
+func WithEndpointStateFile(path string) ClientOption {
+	return func(b *clientBuilder) error {
+		b.statePath = path
+		return nil
+	}
+}
+
+// WithLedgerEntriesBatching splits getLedgerEntries lookups into requests of
+// at most size keys, of which parallel are sent at once. A size or parallel
+// of 0 keeps DefaultLedgerEntriesBatchSize or DefaultLedgerEntriesParallelism.
+func WithLedgerEntriesBatching(size, parallel int) ClientOption {
+	return func(b *clientBuilder) error {
+		if size < 0 || parallel < 0 {
+			return errors.WrapValidationError("ledger entry batch size and parallelism cannot be negative")
+		}
+		b.batchSize = size
+		b.batchWorkers = parallel
+		return nil
+	}
+}
+
 func NewClient(opts ...ClientOption) (*Client, error) {
 	builder := newBuilder()
 
@@ -142,6 +346,12 @@ func (b *clientBuilder) validate() error {
 		b.network = Mainnet
 	}
 
+	// A local node given by URL serves Soroban RPC on that URL too, rather
+	// than on the quickstart default
+	if b.sorobanURL == "" && b.network == Local && b.horizonURL != "" {
+		b.sorobanURL = b.horizonURL
+	}
+
 	if b.horizonURL == "" && b.sorobanURL == "" {
 		b.horizonURL = b.getDefaultHorizonURL(b.network)
 	}
@@ -155,6 +365,8 @@ func (b *clientBuilder) getDefaultHorizonURL(net Network) string {
 		return TestnetHorizonURL
 	case Futurenet:
 		return FuturenetHorizonURL
+	case Local:
+		return LocalSorobanURL
 	default:
 		return MainnetHorizonURL
 	}
@@ -166,6 +378,8 @@ func (b *clientBuilder) getDefaultSorobanURL(net Network) string {
 		return TestnetSorobanURL
 	case Futurenet:
 		return FuturenetSorobanURL
+	case Local:
+		return LocalSorobanURL
 	default:
 		return MainnetSorobanURL
 	}
@@ -177,6 +391,8 @@ func (b *clientBuilder) getConfig(net Network) NetworkConfig {
 		return TestnetConfig
 	case Futurenet:
 		return FuturenetConfig
+	case Local:
+		return LocalConfig
 	default:
 		return MainnetConfig
 	}
@@ -191,9 +407,13 @@ func (b *clientBuilder) build() (*Client, error) {
 		cfg := b.getConfig(b.network)
 		b.config = &cfg
 	}
+	if b.passphrase != "" {
+		b.config.NetworkPassphrase = b.passphrase
+	}
 
 	if b.httpClient == nil {
-		b.httpClient = createHTTPClient(b.token)
+		b.httpClient = createHTTPClient(b.token, b.headers, b.retry, b.transport)
+		b.httpClient.Timeout = b.timeout
 	}
 
 	if len(b.altURLs) == 0 && b.horizonURL != "" {
@@ -208,7 +428,7 @@ func (b *clientBuilder) build() (*Client, error) {
 		b.altURLs = []string{b.horizonURL}
 	}
 
-	return &Client{
+	client := &Client{
 		HorizonURL: b.horizonURL,
 		Horizon: &horizonclient.Client{
 			HorizonURL: b.horizonURL,
@@ -217,10 +437,23 @@ func (b *clientBuilder) build() (*Client, error) {
 		Network:      b.network,
 		SorobanURL:   b.sorobanURL,
 		AltURLs:      b.altURLs,
+		ArchiveURLs:  b.archiveURLs,
 		token:        b.token,
+		headers:      b.headers,
+		httpClient:   b.httpClient,
 		Config:       *b.config,
 		CacheEnabled: b.cacheEnabled,
 		failures:     make(map[string]int),
 		lastFailure:  make(map[string]time.Time),
-	}, nil
+		lastSuccess:  make(map[string]time.Time),
+		statePath:    b.statePath,
+		wasmCache:    b.wasmCache,
+
+		ledgerBatchSize:     b.batchSize,
+		ledgerBatchParallel: b.batchWorkers,
+	}
+	if client.statePath != "" {
+		client.loadEndpointState()
+	}
+	return client, nil
 }
@@ -4,8 +4,11 @@
 package rpc
 
 import (
+	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
 )
@@ -49,6 +52,147 @@ func TestWithInvalidHorizonURL(t *testing.T) {
 	}
 }
 
+func TestWithRetryConfig(t *testing.T) {
+	cfg := RetryConfig{MaxRetries: 5, InitialBackoff: 1, MaxBackoff: 2}
+	client, err := NewClient(WithRetryConfig(cfg))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	rt, ok := client.httpClient.Transport.(*RetryTransport)
+	if !ok {
+		t.Fatalf("expected retrying transport, got %T", client.httpClient.Transport)
+	}
+	if rt.config.MaxRetries != 5 {
+		t.Errorf("expected MaxRetries=5, got %d", rt.config.MaxRetries)
+	}
+}
+
+func TestWithInvalidRetryConfig(t *testing.T) {
+	if _, err := NewClient(WithRetryConfig(RetryConfig{MaxRetries: -1})); err == nil {
+		t.Fatal("expected error for negative max retries")
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	client, err := NewClient(WithRateLimit(5, 2))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	rt, ok := client.httpClient.Transport.(*RetryTransport)
+	if !ok {
+		t.Fatalf("expected retrying transport, got %T", client.httpClient.Transport)
+	}
+	if _, ok := rt.transport.(*rateLimitTransport); !ok {
+		t.Errorf("expected retries to go through the rate limiter, got %T", rt.transport)
+	}
+
+	if _, err := NewClient(WithRateLimit(-1, 0)); err == nil {
+		t.Error("expected error for a negative rate")
+	}
+	if _, err := NewClient(WithRateLimit(1, -1)); err == nil {
+		t.Error("expected error for a negative burst")
+	}
+}
+
+func TestWithRequestTimeout(t *testing.T) {
+	client, err := NewClient(WithRequestTimeout(30 * time.Second))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if client.httpClient.Timeout != 30*time.Second {
+		t.Errorf("expected a 30s timeout, got %v", client.httpClient.Timeout)
+	}
+	if _, err := NewClient(WithRequestTimeout(-time.Second)); err == nil {
+		t.Error("expected error for a negative timeout")
+	}
+}
+
+func TestWithHeaders(t *testing.T) {
+	var seen []http.Header
+	handler := func(healthy bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			seen = append(seen, r.Header.Clone())
+			switch {
+			case r.Method == http.MethodPost:
+				w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"NOT_FOUND"}}`))
+			case healthy:
+				w.Write([]byte(`{"hash":"abc","envelope_xdr":"env"}`))
+			default:
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}
+	}
+	failing := httptest.NewServer(handler(false))
+	defer failing.Close()
+	healthy := httptest.NewServer(handler(true))
+	defer healthy.Close()
+
+	client, err := NewClient(
+		WithNetwork(Testnet),
+		WithToken("secret"),
+		WithAltURLs([]string{failing.URL, healthy.URL}),
+		WithSorobanURL(failing.URL),
+		WithHeaders(map[string]string{"x-api-key": "key-123", "X-Org": "erst"}),
+		WithRetryConfig(NoRetryConfig()),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	resp, err := client.GetTransaction(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("expected failover to succeed, got %v", err)
+	}
+	if resp.EnvelopeXdr != "env" {
+		t.Errorf("expected envelope from failover endpoint, got %q", resp.EnvelopeXdr)
+	}
+	// Soroban RPC, the failing Horizon endpoint, then the healthy one
+	if len(seen) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(seen))
+	}
+	for i, h := range seen {
+		if h.Get("X-Api-Key") != "key-123" || h.Get("X-Org") != "erst" {
+			t.Errorf("request %d missing custom headers: %v", i, h)
+		}
+		if h.Get("Authorization") != "Bearer secret" {
+			t.Errorf("request %d missing bearer token: %v", i, h)
+		}
+	}
+}
+
+func TestWithHeadersOverridesToken(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"status":"healthy"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithSorobanURL(server.URL),
+		WithToken("secret"),
+		WithHeaders(map[string]string{"Authorization": "Basic dXNlcjpwYXNz"}),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := client.GetHealth(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if auth != "Basic dXNlcjpwYXNz" {
+		t.Errorf("expected explicit Authorization header to win, got %q", auth)
+	}
+}
+
+func TestWithInvalidHeaders(t *testing.T) {
+	if _, err := NewClient(WithHeaders(map[string]string{"Bad Name": "v"})); err == nil {
+		t.Error("expected error for header name with a space")
+	}
+	if _, err := NewClient(WithHeaders(map[string]string{"X-Key": "a\r\nX-Injected: b"})); err == nil {
+		t.Error("expected error for header value with a line break")
+	}
+}
+
 func TestWithAltURLs(t *testing.T) {
 	urls := []string{"https://horizon-testnet.stellar.org/", "https://horizon-futurenet.stellar.org/"}
 	client, err := NewClient(WithAltURLs(urls))
@@ -248,6 +392,36 @@ func TestFuturenetDefaults(t *testing.T) {
 	}
 }
 
+func TestLocalDefaults(t *testing.T) {
+	client, err := NewClient(WithNetwork(Local))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if client.HorizonURL != LocalSorobanURL || client.SorobanURL != LocalSorobanURL {
+		t.Errorf("expected quickstart URLs, got %s and %s", client.HorizonURL, client.SorobanURL)
+	}
+	if client.GetNetworkPassphrase() != LocalPassphrase {
+		t.Errorf("expected standalone passphrase, got %q", client.GetNetworkPassphrase())
+	}
+}
+
+func TestLocalCustomNode(t *testing.T) {
+	client, err := NewClient(
+		WithNetwork(Local),
+		WithAltURLs([]string{"http://10.0.0.5:8000/rpc"}),
+		WithNetworkPassphrase("My Dev Network"),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if client.SorobanURL != "http://10.0.0.5:8000/rpc" {
+		t.Errorf("expected the node URL to serve Soroban RPC, got %s", client.SorobanURL)
+	}
+	if client.GetNetworkPassphrase() != "My Dev Network" {
+		t.Errorf("expected custom passphrase, got %q", client.GetNetworkPassphrase())
+	}
+}
+
 func TestAltURLsAsFailover(t *testing.T) {
 	urls := []string{
 		"https://horizon-testnet.stellar.org/",
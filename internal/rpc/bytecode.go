@@ -70,6 +70,11 @@ func ContractCodeHashFromInstanceEntry(entryXDR string) (xdr.Hash, error) {
 	}
 }
 
+// ParseContractID decodes a contract ID given as a strkey (C...) or 32-byte hex.
+func ParseContractID(contractIDStr string) (xdr.ContractId, error) {
+	return decodeContractID(contractIDStr)
+}
+
 // decodeContractID decodes a contract ID from strkey (C...) or 32-byte hex.
 func decodeContractID(contractIDStr string) (xdr.ContractId, error) {
 	s := strings.TrimSpace(contractIDStr)
@@ -186,3 +191,81 @@ func FetchBytecodeForTraceContractCalls(ctx context.Context, c *Client, contract
 	}
 	return existingMap, nil
 }
+
+// FetchContractWasm returns the WASM bytecode of a deployed contract.
+// contractIDStr can be a strkey (C...) or 32-byte hex.
+func FetchContractWasm(ctx context.Context, c *Client, contractIDStr string) ([]byte, error) {
+	entries, err := FetchContractBytecode(ctx, c, contractIDStr)
+	if err != nil {
+		return nil, err
+	}
+	for _, entryXDR := range entries {
+		var entry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshalBase64(entryXDR, &entry); err != nil {
+			continue
+		}
+		if code := entry.Data.ContractCode; code != nil {
+			return code.Code, nil
+		}
+	}
+	return nil, fmt.Errorf("contract code not found for %s", contractIDStr)
+}
+
+// StellarAssetName returns the asset a Stellar Asset Contract wraps, "native"
+// or "CODE:ISSUER", read from its instance storage. ok is false when the
+// contract runs WASM instead.
+func StellarAssetName(ctx context.Context, c *Client, contractIDStr string) (string, bool, error) {
+	cid, err := decodeContractID(contractIDStr)
+	if err != nil {
+		return "", false, err
+	}
+	instanceKey, err := LedgerKeyForContractInstance(cid)
+	if err != nil {
+		return "", false, fmt.Errorf("build instance key: %w", err)
+	}
+	instanceKeyB64, err := EncodeLedgerKey(instanceKey)
+	if err != nil {
+		return "", false, fmt.Errorf("encode instance key: %w", err)
+	}
+	entries, err := c.GetLedgerEntries(ctx, []string{instanceKeyB64})
+	if err != nil {
+		return "", false, fmt.Errorf("get ledger entries (instance): %w", err)
+	}
+	entryXDR, ok := entries[instanceKeyB64]
+	if !ok {
+		return "", false, fmt.Errorf("contract instance not found for %s", contractIDStr)
+	}
+
+	// getLedgerEntries returns the entry data, the cache may hold full entries
+	var data xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(entryXDR, &data); err != nil {
+		var entry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshalBase64(entryXDR, &entry); err != nil {
+			return "", false, fmt.Errorf("unmarshal instance entry: %w", err)
+		}
+		data = entry.Data
+	}
+	if data.ContractData == nil || data.ContractData.Val.Instance == nil {
+		return "", false, fmt.Errorf("contract data is not a contract instance")
+	}
+	instance := data.ContractData.Val.Instance
+	if instance.Executable.Type != xdr.ContractExecutableTypeContractExecutableStellarAsset {
+		return "", false, nil
+	}
+	if instance.Storage != nil {
+		for _, item := range *instance.Storage {
+			if item.Key.Type != xdr.ScValTypeScvSymbol || item.Key.Sym == nil || *item.Key.Sym != "METADATA" {
+				continue
+			}
+			if item.Val.Map == nil || *item.Val.Map == nil {
+				break
+			}
+			for _, field := range **item.Val.Map {
+				if field.Key.Sym != nil && *field.Key.Sym == "name" && field.Val.Str != nil {
+					return string(*field.Val.Str), true, nil
+				}
+			}
+		}
+	}
+	return "", true, fmt.Errorf("stellar asset contract %s has no asset metadata", contractIDStr)
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/metrics"
 
 	"github.com/dotandev/hintents/internal/telemetry"
 	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
@@ -31,6 +32,9 @@ const (
 	Testnet   Network = "testnet"
 	Mainnet   Network = "mainnet"
 	Futurenet Network = "futurenet"
+	// Local is a standalone network run by the developer, such as the
+	// stellar/quickstart docker image with --local
+	Local Network = "local"
 )
 
 // Horizon URLs for each network
@@ -45,20 +49,36 @@ const (
 	TestnetSorobanURL   = "https://soroban-testnet.stellar.org"
 	MainnetSorobanURL   = "https://mainnet.stellar.validationcloud.io/v1/soroban-rpc-demo" // Public demo endpoint
 	FuturenetSorobanURL = "https://rpc-futurenet.stellar.org"
+	// LocalSorobanURL is where the quickstart image serves Soroban RPC. A
+	// local network is reached through it for every request.
+	LocalSorobanURL = "http://localhost:8000/rpc"
 )
 
+// LocalPassphrase is the passphrase of a quickstart standalone network
+const LocalPassphrase = "Standalone Network ; February 2017"
+
 // authTransport is a custom HTTP RoundTripper that adds authentication headers
 type authTransport struct {
 	token     string
+	headers   http.Header // custom headers; an explicit Authorization overrides token
 	transport http.RoundTripper
 }
 
 // RoundTrip implements http.RoundTripper interface
 func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.token == "" && len(t.headers) == 0 {
+		return t.transport.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
 	if t.token != "" {
 		// Add Bearer token to Authorization header
 		req.Header.Set("Authorization", "Bearer "+t.token)
 	}
+	for name, values := range t.headers {
+		req.Header[name] = append([]string(nil), values...)
+	}
 	return t.transport.RoundTrip(req)
 }
 
@@ -92,6 +112,13 @@ var (
 		NetworkPassphrase: "Test SDF Future Network ; October 2022",
 		SorobanRPCURL:     FuturenetSorobanURL,
 	}
+
+	LocalConfig = NetworkConfig{
+		Name:              "local",
+		HorizonURL:        LocalSorobanURL,
+		NetworkPassphrase: LocalPassphrase,
+		SorobanRPCURL:     LocalSorobanURL,
+	}
 )
 
 // Client handles interactions with the Stellar Network
@@ -101,13 +128,22 @@ type Client struct {
 	Network      Network
 	SorobanURL   string
 	AltURLs      []string
+	ArchiveURLs  []string // Horizon-compatible full-history endpoints for pruned transactions
 	currIndex    int
 	mu           sync.RWMutex
 	token        string // stored for reference, not logged
+	headers      http.Header
 	Config       NetworkConfig
 	CacheEnabled bool
 	failures     map[string]int
 	lastFailure  map[string]time.Time
+	lastSuccess  map[string]time.Time
+	httpClient   *http.Client
+	statePath    string // persisted endpoint health, empty to keep it in memory
+	wasmCache    *WasmCache
+	// getLedgerEntries batching, see WithLedgerEntriesBatching
+	ledgerBatchSize     int
+	ledgerBatchParallel int
 }
 
 // NodeFailure records a failure for a specific RPC URL
@@ -151,7 +187,6 @@ func (c *Client) isHealthyLocked(url string) bool {
 
 func (c *Client) markFailure(url string) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	if c.failures == nil {
 		c.failures = make(map[string]int)
 	}
@@ -160,15 +195,29 @@ func (c *Client) markFailure(url string) {
 	}
 	c.failures[url]++
 	c.lastFailure[url] = time.Now()
+	c.mu.Unlock()
+
+	c.saveEndpointState(url)
 }
 
 func (c *Client) markSuccess(url string) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	if c.failures == nil {
 		c.failures = make(map[string]int)
 	}
+	if c.lastSuccess == nil {
+		c.lastSuccess = make(map[string]time.Time)
+	}
+	// A success only changes the endpoint's health when it was failing or
+	// had never been used, so repeated successes do not rewrite the file
+	changed := c.failures[url] != 0 || c.lastSuccess[url].IsZero()
 	c.failures[url] = 0
+	c.lastSuccess[url] = time.Now()
+	c.mu.Unlock()
+
+	if changed {
+		c.saveEndpointState(url)
+	}
 }
 
 // NewClientDefault creates a new RPC client with sensible defaults
@@ -227,29 +276,67 @@ func (c *Client) rotateURL() bool {
 		}
 	}
 
-	c.HorizonURL = c.AltURLs[c.currIndex]
+	c.setEndpointLocked(c.currIndex)
+
+	logger.Logger.Warn("RPC failover triggered", "new_url", c.HorizonURL)
+	return true
+}
+
+// setEndpointLocked makes AltURLs[i] the active endpoint
+func (c *Client) setEndpointLocked(i int) {
+	c.currIndex = i
+	c.HorizonURL = c.AltURLs[i]
 	c.Horizon = &horizonclient.Client{
 		HorizonURL: c.HorizonURL,
-		HTTP:       createHTTPClient(c.token),
+		HTTP:       c.httpClientLocked(),
 	}
+}
 
-	logger.Logger.Warn("RPC failover triggered", "new_url", c.HorizonURL)
-	return true
+// getHTTPClient returns the client used for Horizon and Soroban requests.
+// Clients built without NewClient fall back to a retrying default.
+func (c *Client) getHTTPClient() *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.httpClientLocked()
 }
 
-// createHTTPClient creates an HTTP client with optional authentication
-func createHTTPClient(token string) *http.Client {
-	cfg := DefaultRetryConfig()
+func (c *Client) httpClientLocked() *http.Client {
+	if c.httpClient == nil {
+		c.httpClient = createHTTPClient(c.token, c.headers, DefaultRetryConfig(), transportOptions{})
+	}
+	return c.httpClient
+}
+
+// transportOptions are the optional limits and proxy of createHTTPClient
+type transportOptions struct {
+	// rateLimiter paces every attempt, so retries count against the rate
+	// limit too
+	rateLimiter *RateLimiter
+	// concurrency caps the attempts in flight
+	concurrency *ConcurrencyLimiter
+	// proxy routes requests, defaulting to the proxies of the environment
+	proxy ProxyFunc
+}
 
-	var baseTransport http.RoundTripper = http.DefaultTransport
+// createHTTPClient creates an HTTP client with optional authentication and
+// custom headers that retries transient failures according to cfg
+func createHTTPClient(token string, headers http.Header, cfg RetryConfig, opts transportOptions) *http.Client {
+	baseTransport := metrics.InstrumentTransport(NewProxyTransport(opts.proxy))
 
 	var transport http.RoundTripper = baseTransport
-	if token != "" {
+	if token != "" || len(headers) > 0 {
 		transport = &authTransport{
 			token:     token,
+			headers:   headers,
 			transport: baseTransport,
 		}
 	}
+	if opts.rateLimiter != nil {
+		transport = &rateLimitTransport{limiter: opts.rateLimiter, transport: transport}
+	}
+	if opts.concurrency != nil {
+		transport = &concurrencyTransport{limiter: opts.concurrency, transport: transport}
+	}
 
 	transport = NewRetryTransport(cfg, transport)
 
@@ -305,9 +392,41 @@ type GetHealthResponse struct {
 	} `json:"error,omitempty"`
 }
 
-// GetTransaction fetches the transaction details and full XDR data
+// GetTransaction fetches the transaction details and full XDR data.
+//
+// Soroban RPC is asked first. It only retains recent ledgers, so when it reports
+// the transaction as not found, or cannot be reached, the Horizon endpoints are
+// tried in turn, followed by any archive URLs configured with WithArchiveURLs.
 func (c *Client) GetTransaction(ctx context.Context, hash string) (*TransactionResponse, error) {
+	if c.SorobanURL != "" {
+		resp, err := c.getTransactionFromRPC(ctx, hash)
+		if err == nil {
+			return resp, nil
+		}
+		if IsTransactionNotFound(err) {
+			logger.Logger.Info("Transaction not in Soroban RPC history, falling back to Horizon", "hash", hash)
+		} else {
+			logger.Logger.Debug("Soroban RPC getTransaction failed, falling back to Horizon", "hash", hash, "error", err)
+		}
+	}
+
+	resp, err := c.getTransactionFromHorizon(ctx, hash)
+	if err == nil || len(c.ArchiveURLs) == 0 {
+		return resp, err
+	}
+
+	logger.Logger.Info("Transaction not available from Horizon, trying archive", "hash", hash)
+	if archived, archiveErr := c.getTransactionFromArchives(ctx, hash); archiveErr == nil {
+		return archived, nil
+	}
+	return nil, err
+}
+
+// getTransactionFromHorizon queries the Horizon endpoints with failover. An
+// endpoint that answers "not found" is healthy, so it is not penalised.
+func (c *Client) getTransactionFromHorizon(ctx context.Context, hash string) (*TransactionResponse, error) {
 	var failures []NodeFailure
+	notFound := 0
 	for attempt := 0; attempt < len(c.AltURLs); attempt++ {
 		resp, err := c.getTransactionAttempt(ctx, hash)
 		if err == nil {
@@ -315,7 +434,11 @@ func (c *Client) GetTransaction(ctx context.Context, hash string) (*TransactionR
 			return resp, nil
 		}
 
-		c.markFailure(c.HorizonURL)
+		if IsTransactionNotFound(err) {
+			notFound++
+		} else {
+			c.markFailure(c.HorizonURL)
+		}
 
 		failures = append(failures, NodeFailure{URL: c.HorizonURL, Reason: err})
 
@@ -327,6 +450,9 @@ func (c *Client) GetTransaction(ctx context.Context, hash string) (*TransactionR
 			}
 		}
 	}
+	if notFound > 0 && notFound == len(failures) {
+		return nil, failures[len(failures)-1].Reason
+	}
 	return nil, &AllNodesFailedError{Failures: failures}
 }
 
@@ -345,6 +471,10 @@ func (c *Client) getTransactionAttempt(ctx context.Context, hash string) (*Trans
 	tx, err := c.Horizon.TransactionDetail(hash)
 	if err != nil {
 		span.RecordError(err)
+		if horizonclient.IsNotFoundError(err) {
+			logger.Logger.Debug("Transaction not found", "hash", hash, "url", c.HorizonURL)
+			return nil, errors.WrapTransactionNotFound(err)
+		}
 		logger.Logger.Error("Failed to fetch transaction", "hash", hash, "error", err, "url", c.HorizonURL)
 		return nil, errors.WrapRPCConnectionFailed(err)
 	}
@@ -520,6 +650,11 @@ func IsLedgerArchived(err error) bool {
 	return errors.Is(err, errors.ErrLedgerArchived)
 }
 
+// IsTransactionNotFound checks if error indicates the transaction is unknown to the endpoint
+func IsTransactionNotFound(err error) bool {
+	return errors.Is(err, errors.ErrTransactionNotFound)
+}
+
 // IsRateLimitError checks if error is a rate limit error
 func IsRateLimitError(err error) bool {
 	return errors.Is(err, errors.ErrRateLimitExceeded)
@@ -550,6 +685,9 @@ func (c *Client) GetLedgerEntries(ctx context.Context, keys []string) (map[strin
 			if hit {
 				entries[key] = val
 				logger.Logger.Debug("Cache hit", "key", key)
+			} else if code, ok := c.cachedContractCode(key); ok {
+				entries[key] = code
+				logger.Logger.Debug("WASM cache hit", "key", key)
 			} else {
 				keysToFetch = append(keysToFetch, key)
 			}
@@ -564,6 +702,15 @@ func (c *Client) GetLedgerEntries(ctx context.Context, keys []string) (map[strin
 		return entries, nil
 	}
 
+	tracer := telemetry.GetTracer()
+	ctx, span := tracer.Start(ctx, "rpc_get_ledger_entries")
+	span.SetAttributes(
+		attribute.String("network", string(c.Network)),
+		attribute.Int("ledger.keys", len(keysToFetch)),
+		attribute.Int("ledger.cached", len(entries)),
+	)
+	defer span.End()
+
 	logger.Logger.Debug("Fetching ledger entries from RPC", "count", len(keysToFetch), "url", c.SorobanURL)
 	var failures []NodeFailure
 	for attempt := 0; attempt < len(c.AltURLs); attempt++ {
@@ -588,10 +735,14 @@ func (c *Client) GetLedgerEntries(ctx context.Context, keys []string) (map[strin
 			continue
 		}
 	}
-	return nil, &AllNodesFailedError{Failures: failures}
+	err := &AllNodesFailedError{Failures: failures}
+	span.RecordError(err)
+	return nil, err
 }
 
-func (c *Client) getLedgerEntriesAttempt(ctx context.Context, keysToFetch []string) (map[string]string, error) {
+// postLedgerEntries sends a single getLedgerEntries request, returning the
+// raw response along with the URL it was sent to
+func (c *Client) postLedgerEntries(ctx context.Context, keysToFetch []string) (*GetLedgerEntriesResponse, string, error) {
 	logger.Logger.Debug("Fetching ledger entries", "count", len(keysToFetch), "url", c.HorizonURL)
 	reqBody := GetLedgerEntriesRequest{
 		Jsonrpc: "2.0",
@@ -602,7 +753,7 @@ func (c *Client) getLedgerEntriesAttempt(ctx context.Context, keysToFetch []stri
 
 	bodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, errors.WrapMarshalFailed(err)
+		return nil, "", errors.WrapMarshalFailed(err)
 	}
 
 	targetURL := c.HorizonURL
@@ -614,32 +765,41 @@ func (c *Client) getLedgerEntriesAttempt(ctx context.Context, keysToFetch []stri
 
 	req, err := http.NewRequestWithContext(ctx, "POST", targetURL, bytes.NewBuffer(bodyBytes))
 	if err != nil {
-		return nil, errors.WrapRPCConnectionFailed(err)
+		return nil, targetURL, errors.WrapRPCConnectionFailed(err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.getHTTPClient().Do(req)
 	if err != nil {
-		return nil, errors.WrapRPCConnectionFailed(err)
+		return nil, targetURL, errors.WrapRPCConnectionFailed(err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusRequestEntityTooLarge {
-		return nil, errors.WrapRPCResponseTooLarge(targetURL)
+		return nil, targetURL, errors.WrapRPCResponseTooLarge(targetURL)
 	}
 
 	respBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, errors.WrapUnmarshalFailed(err, "body read error")
+		return nil, targetURL, errors.WrapUnmarshalFailed(err, "body read error")
 	}
 
 	var rpcResp GetLedgerEntriesResponse
 	if err := json.Unmarshal(respBytes, &rpcResp); err != nil {
-		return nil, errors.WrapUnmarshalFailed(err, string(respBytes))
+		return nil, targetURL, errors.WrapUnmarshalFailed(err, string(respBytes))
 	}
 
 	if rpcResp.Error != nil {
-		return nil, errors.WrapRPCError(targetURL, rpcResp.Error.Message, rpcResp.Error.Code)
+		return nil, targetURL, errors.WrapRPCError(targetURL, rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+
+	return &rpcResp, targetURL, nil
+}
+
+func (c *Client) getLedgerEntriesAttempt(ctx context.Context, keysToFetch []string) (map[string]string, error) {
+	rpcResp, targetURL, err := c.queryLedgerEntries(ctx, keysToFetch)
+	if err != nil {
+		return nil, err
 	}
 
 	entries := make(map[string]string)
@@ -653,6 +813,7 @@ func (c *Client) getLedgerEntriesAttempt(ctx context.Context, keysToFetch []stri
 			if err := Set(entry.Key, entry.Xdr); err != nil {
 				logger.Logger.Warn("Failed to cache entry", "key", entry.Key, "error", err)
 			}
+			c.storeContractCode(entry.Key, entry.Xdr)
 		}
 	}
 
@@ -672,6 +833,52 @@ func (c *Client) getLedgerEntriesAttempt(ctx context.Context, keysToFetch []stri
 	return entries, nil
 }
 
+// getWasmCache returns the client's WASM cache, defaulting to ~/.erst/cache/wasm
+func (c *Client) getWasmCache() *WasmCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.wasmCache == nil {
+		cache, err := DefaultWasmCache()
+		if err != nil {
+			logger.Logger.Debug("WASM cache unavailable", "error", err)
+			return nil
+		}
+		c.wasmCache = cache
+	}
+	return c.wasmCache
+}
+
+// cachedContractCode looks up a ContractCode ledger key in the WASM cache
+func (c *Client) cachedContractCode(keyB64 string) (string, bool) {
+	hash, ok := contractCodeHash(keyB64)
+	if !ok {
+		return "", false
+	}
+	cache := c.getWasmCache()
+	if cache == nil {
+		return "", false
+	}
+	entry, hit, err := cache.Get(hash)
+	if err != nil {
+		logger.Logger.Warn("WASM cache read failed", "error", err)
+	}
+	return entry, hit
+}
+
+// storeContractCode adds a fetched ContractCode entry to the WASM cache
+func (c *Client) storeContractCode(keyB64, entryXDR string) {
+	if _, ok := contractCodeHash(keyB64); !ok {
+		return
+	}
+	cache := c.getWasmCache()
+	if cache == nil {
+		return
+	}
+	if _, err := cache.Put(entryXDR); err != nil {
+		logger.Logger.Warn("Failed to cache contract code", "key", keyB64, "error", err)
+	}
+}
+
 type TransactionSummary struct {
 	Hash      string
 	Status    string
@@ -734,6 +941,16 @@ type SimulateTransactionResponse struct {
 			CpuInsns_ int64 `json:"cpu_insns,omitempty"`
 			MemBytes_ int64 `json:"mem_bytes,omitempty"`
 		} `json:"cost,omitempty"`
+		// Error is set when the simulated transaction itself fails
+		Error string `json:"error,omitempty"`
+		// Events are base64 XDR DiagnosticEvents
+		Events []string `json:"events,omitempty"`
+		// Results holds the host function's return value as base64 XDR
+		Results []struct {
+			Xdr  string   `json:"xdr"`
+			Auth []string `json:"auth,omitempty"`
+		} `json:"results,omitempty"`
+		LatestLedger uint32 `json:"latestLedger,omitempty"`
 	} `json:"result"`
 	Error *struct {
 		Code    int    `json:"code"`
@@ -742,7 +959,9 @@ type SimulateTransactionResponse struct {
 }
 
 // SimulateTransaction calls Soroban RPC simulateTransaction using a base64 TransactionEnvelope XDR.
+// Fee-bump envelopes are simulated as their inner transaction.
 func (c *Client) SimulateTransaction(ctx context.Context, envelopeXdr string) (*SimulateTransactionResponse, error) {
+	envelopeXdr = InnerEnvelope(envelopeXdr)
 	var failures []NodeFailure
 	for attempt := 0; attempt < len(c.AltURLs); attempt++ {
 		resp, err := c.simulateTransactionAttempt(ctx, envelopeXdr)
@@ -786,7 +1005,7 @@ func (c *Client) simulateTransactionAttempt(ctx context.Context, envelopeXdr str
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.getHTTPClient().Do(req)
 	if err != nil {
 		return nil, errors.WrapRPCConnectionFailed(err)
 	}
@@ -814,9 +1033,14 @@ func (c *Client) simulateTransactionAttempt(ctx context.Context, envelopeXdr str
 }
 
 // GetHealth checks the health of the Soroban RPC endpoint.
+//
+// Each endpoint is probed once: an unhealthy node is failed over from
+// immediately rather than retried with backoff.
 func (c *Client) GetHealth(ctx context.Context) (*GetHealthResponse, error) {
+	ctx = ContextWithRetryConfig(ctx, NoRetryConfig())
+	targetURL := c.SorobanURL
 	for attempt := 0; attempt < len(c.AltURLs); attempt++ {
-		resp, err := c.getHealthAttempt(ctx)
+		resp, err := c.getHealthAttempt(ctx, targetURL)
 		if err == nil {
 			return resp, nil
 		}
@@ -826,6 +1050,7 @@ func (c *Client) GetHealth(ctx context.Context) (*GetHealthResponse, error) {
 			if !c.rotateURL() {
 				break
 			}
+			targetURL = c.HorizonURL
 			continue
 		}
 		return nil, err
@@ -833,8 +1058,8 @@ func (c *Client) GetHealth(ctx context.Context) (*GetHealthResponse, error) {
 	return nil, fmt.Errorf("all Soroban RPC endpoints failed for GetHealth")
 }
 
-func (c *Client) getHealthAttempt(ctx context.Context) (*GetHealthResponse, error) {
-	logger.Logger.Debug("Checking Soroban RPC health", "url", c.SorobanURL)
+func (c *Client) getHealthAttempt(ctx context.Context, targetURL string) (*GetHealthResponse, error) {
+	logger.Logger.Debug("Checking Soroban RPC health", "url", targetURL)
 
 	reqBody := GetHealthRequest{
 		Jsonrpc: "2.0",
@@ -847,14 +1072,13 @@ func (c *Client) getHealthAttempt(ctx context.Context) (*GetHealthResponse, erro
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	targetURL := c.SorobanURL
 	req, err := http.NewRequestWithContext(ctx, "POST", targetURL, bytes.NewBuffer(bodyBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.getHTTPClient().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request to %s: %w", targetURL, err)
 	}
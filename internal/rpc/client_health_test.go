@@ -8,7 +8,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -82,7 +84,9 @@ func TestGetHealth_Error(t *testing.T) {
 }
 
 func TestGetHealth_Failover(t *testing.T) {
+	var server1Hits atomic.Int32
 	server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server1Hits.Add(1)
 		w.WriteHeader(http.StatusInternalServerError)
 	}))
 	defer server1.Close()
@@ -111,8 +115,13 @@ func TestGetHealth_Failover(t *testing.T) {
 	// Manually set SorobanURL to server1.URL for the first attempt
 	client.SorobanURL = server1.URL
 
+	start := time.Now()
 	resp, err := client.GetHealth(context.Background())
+	elapsed := time.Since(start)
 	assert.NoError(t, err)
-	assert.NotNil(t, resp)
-	assert.Equal(t, "healthy", resp.Result.Status)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, "healthy", resp.Result.Status)
+	}
+	assert.Equal(t, int32(1), server1Hits.Load(), "failing endpoint should be probed once")
+	assert.Less(t, elapsed, time.Second, "failover should not wait on retry backoff")
 }
@@ -0,0 +1,155 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// EndpointStateFileName is the file under ~/.erst that remembers endpoint health
+const EndpointStateFileName = "rpc_endpoints.json"
+
+// endpointRecord is the persisted health of a single RPC endpoint
+type endpointRecord struct {
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last_failure,omitempty"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+}
+
+// endpointState maps network name to endpoint URL to its health record
+type endpointState map[string]map[string]endpointRecord
+
+// DefaultEndpointStatePath returns ~/.erst/rpc_endpoints.json
+func DefaultEndpointStatePath() (string, error) {
+	dir, err := GetCachePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, EndpointStateFileName), nil
+}
+
+func readEndpointState(path string) endpointState {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return endpointState{}
+	}
+	var state endpointState
+	if err := json.Unmarshal(data, &state); err != nil || state == nil {
+		logger.Logger.Debug("Ignoring unreadable endpoint state", "path", path, "error", err)
+		return endpointState{}
+	}
+	return state
+}
+
+// writeEndpointState replaces the file atomically so concurrent erst
+// processes never observe a partially written file
+func writeEndpointState(path string, state endpointState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), DirPerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), EndpointStateFileName+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// loadEndpointState seeds the client's circuit breaker from a previous run and
+// starts on the endpoint most likely to work: the one that succeeded most
+// recently without failing since, otherwise the first healthy one in order.
+func (c *Client) loadEndpointState() {
+	records := readEndpointState(c.statePath)[string(c.Network)]
+	if len(records) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, url := range c.AltURLs {
+		rec, ok := records[url]
+		if !ok {
+			continue
+		}
+		c.failures[url] = rec.Failures
+		if !rec.LastFailure.IsZero() {
+			c.lastFailure[url] = rec.LastFailure
+		}
+		if !rec.LastSuccess.IsZero() {
+			c.lastSuccess[url] = rec.LastSuccess
+		}
+	}
+
+	best := -1
+	var bestAt time.Time
+	for i, url := range c.AltURLs {
+		rec, ok := records[url]
+		if !ok || rec.Failures > 0 || rec.LastSuccess.IsZero() {
+			continue
+		}
+		if best == -1 || rec.LastSuccess.After(bestAt) {
+			best, bestAt = i, rec.LastSuccess
+		}
+	}
+	if best == -1 {
+		for i, url := range c.AltURLs {
+			if c.isHealthyLocked(url) {
+				best = i
+				break
+			}
+		}
+	}
+	if best <= 0 {
+		return
+	}
+
+	c.setEndpointLocked(best)
+	logger.Logger.Debug("Starting on last known healthy RPC endpoint", "url", c.HorizonURL)
+}
+
+// saveEndpointState persists the health of url, keeping records for other
+// networks and endpoints intact. It is called when the health changes, on a
+// failure or on the first success after one, not on every request.
+func (c *Client) saveEndpointState(url string) {
+	if c.statePath == "" {
+		return
+	}
+
+	c.mu.RLock()
+	rec := endpointRecord{
+		Failures:    c.failures[url],
+		LastFailure: c.lastFailure[url],
+		LastSuccess: c.lastSuccess[url],
+	}
+	c.mu.RUnlock()
+
+	state := readEndpointState(c.statePath)
+	network := string(c.Network)
+	if state[network] == nil {
+		state[network] = make(map[string]endpointRecord)
+	}
+	state[network][url] = rec
+
+	if err := writeEndpointState(c.statePath, state); err != nil {
+		logger.Logger.Debug("Failed to persist endpoint state", "path", c.statePath, "error", err)
+	}
+}
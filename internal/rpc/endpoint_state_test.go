@@ -0,0 +1,128 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointState_StartsOnLastHealthyEndpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EndpointStateFileName)
+	urls := []string{"http://primary.example", "http://backup.example"}
+
+	first, err := NewClient(WithNetwork(Testnet), WithAltURLs(urls), WithEndpointStateFile(path))
+	require.NoError(t, err)
+	first.markFailure(urls[0])
+	first.markSuccess(urls[1])
+
+	second, err := NewClient(WithNetwork(Testnet), WithAltURLs(urls), WithEndpointStateFile(path))
+	require.NoError(t, err)
+	assert.Equal(t, urls[1], second.HorizonURL)
+	assert.Equal(t, 1, second.currIndex)
+	assert.Equal(t, 1, second.failures[urls[0]])
+}
+
+func TestEndpointState_SkipsOpenCircuit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EndpointStateFileName)
+	urls := []string{"http://primary.example", "http://backup.example"}
+
+	first, err := NewClient(WithNetwork(Testnet), WithAltURLs(urls), WithEndpointStateFile(path))
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		first.markFailure(urls[0])
+	}
+
+	second, err := NewClient(WithNetwork(Testnet), WithAltURLs(urls), WithEndpointStateFile(path))
+	require.NoError(t, err)
+	assert.Equal(t, urls[1], second.HorizonURL)
+	assert.False(t, second.isHealthy(urls[0]))
+}
+
+func TestEndpointState_IsPerNetwork(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EndpointStateFileName)
+	urls := []string{"http://primary.example", "http://backup.example"}
+
+	testnet, err := NewClient(WithNetwork(Testnet), WithAltURLs(urls), WithEndpointStateFile(path))
+	require.NoError(t, err)
+	testnet.markSuccess(urls[1])
+
+	mainnet, err := NewClient(WithNetwork(Mainnet), WithAltURLs(urls), WithEndpointStateFile(path))
+	require.NoError(t, err)
+	assert.Equal(t, urls[0], mainnet.HorizonURL)
+}
+
+func TestEndpointState_PrefersPrimaryWhenAllHealthy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EndpointStateFileName)
+	urls := []string{"http://primary.example", "http://backup.example"}
+
+	require.NoError(t, writeEndpointState(path, endpointState{
+		string(Testnet): {
+			urls[0]: {LastSuccess: time.Now()},
+			urls[1]: {LastSuccess: time.Now().Add(-time.Hour)},
+		},
+	}))
+
+	client, err := NewClient(WithNetwork(Testnet), WithAltURLs(urls), WithEndpointStateFile(path))
+	require.NoError(t, err)
+	assert.Equal(t, urls[0], client.HorizonURL)
+}
+
+func TestEndpointState_IgnoresCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EndpointStateFileName)
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0600))
+
+	client, err := NewClient(WithNetwork(Testnet), WithAltURLs([]string{"http://a.example", "http://b.example"}), WithEndpointStateFile(path))
+	require.NoError(t, err)
+	assert.Equal(t, "http://a.example", client.HorizonURL)
+
+	client.markSuccess("http://b.example")
+	state := readEndpointState(path)
+	assert.Contains(t, state[string(Testnet)], "http://b.example")
+}
+
+func TestEndpointState_InMemoryByDefault(t *testing.T) {
+	client, err := NewClient(WithNetwork(Testnet), WithAltURLs([]string{"http://a.example"}))
+	require.NoError(t, err)
+	assert.Empty(t, client.statePath)
+	client.markFailure("http://a.example")
+	assert.Equal(t, 1, client.failures["http://a.example"])
+}
+
+func TestEndpointState_WritesOnlyWhenHealthChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EndpointStateFileName)
+	url := "http://primary.example"
+
+	client, err := NewClient(WithNetwork(Testnet), WithAltURLs([]string{url}), WithEndpointStateFile(path))
+	require.NoError(t, err)
+	client.markSuccess(url)
+	require.FileExists(t, path)
+
+	// Further successes leave the file alone
+	require.NoError(t, os.Remove(path))
+	client.markSuccess(url)
+	assert.NoFileExists(t, path)
+
+	// A failure, and the recovery after it, are written
+	client.markFailure(url)
+	assert.Equal(t, 1, readEndpointState(path)[string(Testnet)][url].Failures)
+	client.markSuccess(url)
+	assert.Equal(t, 0, readEndpointState(path)[string(Testnet)][url].Failures)
+
+	// A client seeded with a healthy record does not rewrite it either
+	require.NoError(t, writeEndpointState(path, endpointState{string(Testnet): {url: {LastSuccess: time.Now()}}}))
+	written := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(path, written, written))
+	next, err := NewClient(WithNetwork(Testnet), WithAltURLs([]string{url}), WithEndpointStateFile(path))
+	require.NoError(t, err)
+	next.markSuccess(url)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(written), "the state file was rewritten")
+}
@@ -0,0 +1,440 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+type GetEventsRequest struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Method  string          `json:"method"`
+	Params  GetEventsParams `json:"params"`
+}
+
+// GetEventsParams selects a page of events. As with getTransactions,
+// StartLedger is ignored by the RPC server when a pagination cursor is
+// supplied.
+type GetEventsParams struct {
+	StartLedger uint32                 `json:"startLedger,omitempty"`
+	Filters     []EventFilter          `json:"filters,omitempty"`
+	Pagination  *TransactionPagination `json:"pagination,omitempty"`
+}
+
+// EventFilter matches events by type, emitting contract and topics. Each
+// topic filter is a list of base64 ScVal segments, "*" matching any single
+// segment.
+type EventFilter struct {
+	Type        string     `json:"type,omitempty"` // "contract", "system" or "diagnostic"
+	ContractIDs []string   `json:"contractIds,omitempty"`
+	Topics      [][]string `json:"topics,omitempty"`
+}
+
+// ContractEvent is a single event as returned by getEvents. Topic and Value
+// are base64 ScVal XDR.
+type ContractEvent struct {
+	Type                     string   `json:"type"`
+	Ledger                   uint32   `json:"ledger"`
+	LedgerClosedAt           string   `json:"ledgerClosedAt"`
+	ContractID               string   `json:"contractId"`
+	ID                       string   `json:"id"`
+	InSuccessfulContractCall bool     `json:"inSuccessfulContractCall"`
+	TxHash                   string   `json:"txHash"`
+	Topic                    []string `json:"topic"`
+	Value                    string   `json:"value"`
+}
+
+type GetEventsResponse struct {
+	Jsonrpc string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Result  struct {
+		Events       []ContractEvent `json:"events"`
+		LatestLedger uint32          `json:"latestLedger"`
+		Cursor       string          `json:"cursor"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// GetEvents fetches a page of events matching filters from Soroban RPC,
+// starting either at startLedger or, when cursor is non-empty, after a
+// previous page.
+func (c *Client) GetEvents(ctx context.Context, startLedger uint32, cursor string, filters []EventFilter, limit int) (*GetEventsResponse, error) {
+	logger.Logger.Debug("Fetching events", "start_ledger", startLedger, "cursor", cursor, "url", c.SorobanURL)
+
+	params := GetEventsParams{
+		Filters:    filters,
+		Pagination: &TransactionPagination{Cursor: cursor, Limit: limit},
+	}
+	if cursor == "" {
+		params.StartLedger = startLedger
+	}
+
+	reqBody := GetEventsRequest{
+		Jsonrpc: "2.0",
+		ID:      1,
+		Method:  "getEvents",
+		Params:  params,
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, errors.WrapMarshalFailed(err)
+	}
+
+	targetURL := c.SorobanURL
+	req, err := http.NewRequestWithContext(ctx, "POST", targetURL, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.getHTTPClient().Do(req)
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		return nil, errors.WrapRPCResponseTooLarge(targetURL)
+	}
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "body read error")
+	}
+
+	var rpcResp GetEventsResponse
+	if err := json.Unmarshal(respBytes, &rpcResp); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, string(respBytes))
+	}
+
+	if rpcResp.Error != nil {
+		return nil, errors.WrapRPCError(targetURL, rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+
+	return &rpcResp, nil
+}
+
+// DefaultEventsPageSize is the number of events PageEvents asks for per
+// request when the query does not set one
+const DefaultEventsPageSize = 100
+
+// getEvents accepts at most this many filters, contract IDs and topic filters
+// per filter, and topic segments per topic filter
+const (
+	maxEventFilters       = 5
+	maxEventContractIDs   = 5
+	maxEventTopicFilters  = 5
+	maxEventTopicSegments = 4
+)
+
+// EventPager fetches a page of events. *Client satisfies it.
+type EventPager interface {
+	GetEvents(ctx context.Context, startLedger uint32, cursor string, filters []EventFilter, limit int) (*GetEventsResponse, error)
+}
+
+// EventQuery selects the events PageEvents goes through
+type EventQuery struct {
+	StartLedger uint32
+	// EndLedger stops before the events of this ledger; 0 goes up to the
+	// latest ledger
+	EndLedger uint32
+	// Cursor resumes after a previous page instead of starting at StartLedger
+	Cursor   string
+	Filters  []EventFilter
+	PageSize int
+	// Limit stops after this many events; 0 for no limit
+	Limit int
+}
+
+// PageEvents calls onEvent for each event matching q in ledger order,
+// following the pagination cursor until the latest ledger, q.EndLedger or
+// q.Limit is reached. It returns the cursor to resume from later. An error
+// returned by onEvent stops it and is returned as is.
+func PageEvents(ctx context.Context, pager EventPager, q EventQuery, onEvent func(ContractEvent) error) (string, error) {
+	if err := ValidateEventFilters(q.Filters); err != nil {
+		return q.Cursor, err
+	}
+	pageSize := q.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultEventsPageSize
+	}
+
+	cursor := q.Cursor
+	seen := 0
+	for {
+		limit := pageSize
+		if q.Limit > 0 && q.Limit-seen < limit {
+			limit = q.Limit - seen
+		}
+		resp, err := pager.GetEvents(ctx, q.StartLedger, cursor, q.Filters, limit)
+		if err != nil {
+			return cursor, err
+		}
+		for _, event := range resp.Result.Events {
+			if q.EndLedger != 0 && event.Ledger >= q.EndLedger {
+				return cursor, nil
+			}
+			if err := onEvent(event); err != nil {
+				return cursor, err
+			}
+			// Event IDs double as paging tokens
+			cursor = event.ID
+			seen++
+		}
+		if resp.Result.Cursor != "" {
+			cursor = resp.Result.Cursor
+		}
+		if len(resp.Result.Events) < limit || (q.Limit > 0 && seen >= q.Limit) {
+			return cursor, nil
+		}
+	}
+}
+
+// GetAllEvents collects the events matching q, paging through them with
+// PageEvents, and returns them with the cursor to resume from
+func (c *Client) GetAllEvents(ctx context.Context, q EventQuery) ([]ContractEvent, string, error) {
+	var events []ContractEvent
+	cursor, err := PageEvents(ctx, c, q, func(event ContractEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, cursor, err
+	}
+	return events, cursor, nil
+}
+
+// ValidateEventFilters checks filters against the limits of getEvents, so
+// that a bad filter is reported before any request is made
+func ValidateEventFilters(filters []EventFilter) error {
+	if len(filters) > maxEventFilters {
+		return errors.WrapValidationError(fmt.Sprintf("getEvents accepts at most %d filters, got %d", maxEventFilters, len(filters)))
+	}
+	for _, f := range filters {
+		switch f.Type {
+		case "", "contract", "system", "diagnostic":
+		default:
+			return errors.WrapValidationError(fmt.Sprintf("unknown event type %q (want contract, system or diagnostic)", f.Type))
+		}
+		if len(f.ContractIDs) > maxEventContractIDs {
+			return errors.WrapValidationError(fmt.Sprintf("an event filter takes at most %d contract IDs, got %d", maxEventContractIDs, len(f.ContractIDs)))
+		}
+		if len(f.Topics) > maxEventTopicFilters {
+			return errors.WrapValidationError(fmt.Sprintf("an event filter takes at most %d topic filters, got %d", maxEventTopicFilters, len(f.Topics)))
+		}
+		for _, topic := range f.Topics {
+			if len(topic) == 0 || len(topic) > maxEventTopicSegments {
+				return errors.WrapValidationError(fmt.Sprintf("a topic filter has 1 to %d segments, got %d", maxEventTopicSegments, len(topic)))
+			}
+			for i, segment := range topic {
+				if segment == "**" && i != len(topic)-1 {
+					return errors.WrapValidationError(`"**" can only be the last segment of a topic filter`)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// SplitEventFilter spreads the contract IDs of f over as many filters as
+// getEvents needs, each matching the same type and topics, so that up to
+// 25 contracts can be followed in one request
+func SplitEventFilter(f EventFilter) ([]EventFilter, error) {
+	if len(f.ContractIDs) <= maxEventContractIDs {
+		return []EventFilter{f}, nil
+	}
+	if max := maxEventFilters * maxEventContractIDs; len(f.ContractIDs) > max {
+		return nil, errors.WrapValidationError(fmt.Sprintf("getEvents matches at most %d contracts, got %d", max, len(f.ContractIDs)))
+	}
+	var filters []EventFilter
+	for start := 0; start < len(f.ContractIDs); start += maxEventContractIDs {
+		end := start + maxEventContractIDs
+		if end > len(f.ContractIDs) {
+			end = len(f.ContractIDs)
+		}
+		filters = append(filters, EventFilter{Type: f.Type, ContractIDs: f.ContractIDs[start:end], Topics: f.Topics})
+	}
+	return filters, nil
+}
+
+// ParseTopicFilter turns a filter expression such as "transfer,*,GABC..."
+// into a getEvents topic filter, with one comma-separated segment per topic.
+// "*" matches any single topic and a trailing "**" any number of remaining
+// topics. A G..., C... or M... address matches that address and any other
+// bare word the symbol of that name. Typed values are written as sym:name,
+// str:text, u32:N, i32:N, u64:N, i64:N, u128:N, i128:N, bool:true or
+// xdr:BASE64 for a raw ScVal.
+func ParseTopicFilter(expr string) ([]string, error) {
+	var topic []string
+	for _, raw := range strings.Split(expr, ",") {
+		segment := strings.TrimSpace(raw)
+		if segment == "*" || segment == "**" {
+			topic = append(topic, segment)
+			continue
+		}
+		val, err := parseTopicSegment(segment)
+		if err != nil {
+			return nil, errors.WrapValidationError(fmt.Sprintf("invalid topic %q in %q: %v", segment, expr, err))
+		}
+		encoded, err := xdr.MarshalBase64(val)
+		if err != nil {
+			return nil, errors.WrapMarshalFailed(err)
+		}
+		topic = append(topic, encoded)
+	}
+	if err := ValidateEventFilters([]EventFilter{{Topics: [][]string{topic}}}); err != nil {
+		return nil, err
+	}
+	return topic, nil
+}
+
+func parseTopicSegment(segment string) (xdr.ScVal, error) {
+	if segment == "" {
+		return xdr.ScVal{}, fmt.Errorf("empty topic")
+	}
+	kind, value, typed := strings.Cut(segment, ":")
+	if !typed {
+		if addr, ok := parseTopicAddress(segment); ok {
+			return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &addr}, nil
+		}
+		kind, value = "sym", segment
+	}
+
+	switch kind {
+	case "sym":
+		if value == "" || len(value) > 32 {
+			return xdr.ScVal{}, fmt.Errorf("symbols are 1 to 32 characters")
+		}
+		for _, r := range value {
+			if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+				return xdr.ScVal{}, fmt.Errorf("symbols may only contain letters, digits and '_'")
+			}
+		}
+		sym := xdr.ScSymbol(value)
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, nil
+	case "str":
+		s := xdr.ScString(value)
+		return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &s}, nil
+	case "bool":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b}, nil
+	case "u32":
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		u := xdr.Uint32(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u}, nil
+	case "i32":
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		i := xdr.Int32(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &i}, nil
+	case "u64":
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		u := xdr.Uint64(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &u}, nil
+	case "i64":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		i := xdr.Int64(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &i}, nil
+	case "u128", "i128":
+		n, ok := new(big.Int).SetString(value, 10)
+		if !ok {
+			return xdr.ScVal{}, fmt.Errorf("%q is not an integer", value)
+		}
+		return int128ScVal(kind, n)
+	case "xdr":
+		var v xdr.ScVal
+		if err := xdr.SafeUnmarshalBase64(value, &v); err != nil {
+			return xdr.ScVal{}, err
+		}
+		return v, nil
+	}
+	return xdr.ScVal{}, fmt.Errorf("unknown type %q", kind)
+}
+
+func parseTopicAddress(s string) (xdr.ScAddress, bool) {
+	switch {
+	case strkey.IsValidEd25519PublicKey(s):
+		account, err := xdr.AddressToAccountId(s)
+		if err != nil {
+			return xdr.ScAddress{}, false
+		}
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &account}, true
+	case strkey.IsValidContractAddress(s):
+		raw, err := strkey.Decode(strkey.VersionByteContract, s)
+		if err != nil {
+			return xdr.ScAddress{}, false
+		}
+		var id xdr.ContractId
+		copy(id[:], raw)
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}, true
+	case strkey.IsValidMuxedAccountEd25519PublicKey(s):
+		muxed, err := xdr.AddressToMuxedAccount(s)
+		if err != nil {
+			return xdr.ScAddress{}, false
+		}
+		med := muxed.MustMed25519()
+		addr, err := xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeMuxedAccount, xdr.MuxedEd25519Account{Id: med.Id, Ed25519: med.Ed25519})
+		return addr, err == nil
+	}
+	return xdr.ScAddress{}, false
+}
+
+// int128ScVal encodes n as a u128 or i128 ScVal
+func int128ScVal(kind string, n *big.Int) (xdr.ScVal, error) {
+	min, max := new(big.Int), new(big.Int).Lsh(big.NewInt(1), 128)
+	if kind == "i128" {
+		min.Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+		max.Lsh(big.NewInt(1), 127)
+	}
+	if n.Cmp(min) < 0 || n.Cmp(max) >= 0 {
+		return xdr.ScVal{}, fmt.Errorf("%s out of range for %s", n, kind)
+	}
+	// Two's complement in 128 bits
+	u := new(big.Int).Set(n)
+	if u.Sign() < 0 {
+		u.Add(u, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+	mask := new(big.Int).SetUint64(math.MaxUint64)
+	lo := new(big.Int).And(u, mask).Uint64()
+	hi := new(big.Int).Rsh(u, 64).Uint64()
+	if kind == "u128" {
+		parts := xdr.UInt128Parts{Hi: xdr.Uint64(hi), Lo: xdr.Uint64(lo)}
+		return xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &parts}, nil
+	}
+	parts := xdr.Int128Parts{Hi: xdr.Int64(int64(hi)), Lo: xdr.Uint64(lo)}
+	return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &parts}, nil
+}
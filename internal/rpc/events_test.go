@@ -0,0 +1,149 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedEvents serves count events, one per ledger from 100, in pages
+type pagedEvents struct {
+	count   int
+	cursors []string
+	limits  []int
+}
+
+func (p *pagedEvents) GetEvents(ctx context.Context, startLedger uint32, cursor string, filters []EventFilter, limit int) (*GetEventsResponse, error) {
+	p.cursors = append(p.cursors, cursor)
+	p.limits = append(p.limits, limit)
+	next := 0
+	if cursor != "" {
+		_, _ = fmt.Sscanf(cursor, "e%d", &next)
+		next++
+	}
+	resp := &GetEventsResponse{}
+	for i := next; i < p.count && len(resp.Result.Events) < limit; i++ {
+		resp.Result.Events = append(resp.Result.Events, ContractEvent{ID: fmt.Sprintf("e%d", i), Ledger: uint32(100 + i)})
+	}
+	resp.Result.Cursor = cursor
+	if n := len(resp.Result.Events); n > 0 {
+		resp.Result.Cursor = resp.Result.Events[n-1].ID
+	}
+	return resp, nil
+}
+
+func pageAll(t *testing.T, pager EventPager, q EventQuery) ([]string, string) {
+	t.Helper()
+	var ids []string
+	cursor, err := PageEvents(context.Background(), pager, q, func(e ContractEvent) error {
+		ids = append(ids, e.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	return ids, cursor
+}
+
+func TestPageEvents(t *testing.T) {
+	pager := &pagedEvents{count: 7}
+	ids, cursor := pageAll(t, pager, EventQuery{StartLedger: 100, PageSize: 3})
+	assert.Len(t, ids, 7)
+	assert.Equal(t, "e6", cursor)
+	assert.Equal(t, []string{"", "e2", "e5"}, pager.cursors)
+
+	// Resuming from the cursor picks up where the last run stopped
+	pager.count = 9
+	ids, _ = pageAll(t, pager, EventQuery{Cursor: cursor, PageSize: 3})
+	assert.Equal(t, []string{"e7", "e8"}, ids)
+}
+
+func TestPageEvents_Bounds(t *testing.T) {
+	pager := &pagedEvents{count: 20}
+	ids, cursor := pageAll(t, pager, EventQuery{PageSize: 4, Limit: 6})
+	assert.Equal(t, []string{"e0", "e1", "e2", "e3", "e4", "e5"}, ids)
+	assert.Equal(t, "e5", cursor)
+	assert.Equal(t, []int{4, 2}, pager.limits, "the last page only asks for what is left")
+
+	ids, cursor = pageAll(t, &pagedEvents{count: 20}, EventQuery{PageSize: 4, EndLedger: 103})
+	assert.Equal(t, []string{"e0", "e1", "e2"}, ids)
+	assert.Equal(t, "e2", cursor)
+}
+
+func TestPageEvents_StopsOnHandlerError(t *testing.T) {
+	stop := fmt.Errorf("stop")
+	cursor, err := PageEvents(context.Background(), &pagedEvents{count: 5}, EventQuery{}, func(e ContractEvent) error {
+		if e.ID == "e2" {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, "e1", cursor)
+}
+
+func TestParseTopicFilter(t *testing.T) {
+	account := "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+	topic, err := ParseTopicFilter("transfer, " + account + ", *, **")
+	require.NoError(t, err)
+	require.Len(t, topic, 4)
+	assert.Equal(t, []string{"*", "**"}, topic[2:])
+
+	var sym xdr.ScVal
+	require.NoError(t, xdr.SafeUnmarshalBase64(topic[0], &sym))
+	assert.Equal(t, xdr.ScSymbol("transfer"), *sym.Sym)
+	var addr xdr.ScVal
+	require.NoError(t, xdr.SafeUnmarshalBase64(topic[1], &addr))
+	got, err := addr.Address.String()
+	require.NoError(t, err)
+	assert.Equal(t, account, got)
+
+	topic, err = ParseTopicFilter("i128:-5,u32:7,str:hello world,bool:true")
+	require.NoError(t, err)
+	var i128 xdr.ScVal
+	require.NoError(t, xdr.SafeUnmarshalBase64(topic[0], &i128))
+	assert.Equal(t, xdr.Int128Parts{Hi: -1, Lo: xdr.Uint64(^uint64(4))}, *i128.I128)
+
+	for _, expr := range []string{"**,transfer", "a,b,c,d,e", "u32:-1", "not a symbol", "", "u128:340282366920938463463374607431768211456", "what:ever"} {
+		_, err := ParseTopicFilter(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestValidateEventFilters(t *testing.T) {
+	assert.NoError(t, ValidateEventFilters([]EventFilter{{Type: "contract", ContractIDs: []string{"C1", "C2"}}}))
+	assert.Error(t, ValidateEventFilters([]EventFilter{{Type: "transfer"}}))
+	assert.Error(t, ValidateEventFilters([]EventFilter{{ContractIDs: []string{"1", "2", "3", "4", "5", "6"}}}))
+	assert.Error(t, ValidateEventFilters(make([]EventFilter, 6)))
+}
+
+func TestSplitEventFilter(t *testing.T) {
+	ids := make([]string, 12)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("C%d", i)
+	}
+	topics := [][]string{{"*"}}
+
+	filters, err := SplitEventFilter(EventFilter{Type: "contract", ContractIDs: ids[:3], Topics: topics})
+	require.NoError(t, err)
+	assert.Len(t, filters, 1)
+
+	filters, err = SplitEventFilter(EventFilter{Type: "contract", ContractIDs: ids, Topics: topics})
+	require.NoError(t, err)
+	require.Len(t, filters, 3)
+	assert.Equal(t, ids[:5], filters[0].ContractIDs)
+	assert.Equal(t, ids[10:], filters[2].ContractIDs)
+	for _, f := range filters {
+		assert.Equal(t, "contract", f.Type)
+		assert.Equal(t, topics, f.Topics)
+	}
+	assert.NoError(t, ValidateEventFilters(filters))
+
+	_, err = SplitEventFilter(EventFilter{ContractIDs: make([]string, 26)})
+	assert.ErrorContains(t, err, "at most 25 contracts")
+}
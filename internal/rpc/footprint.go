@@ -0,0 +1,72 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// SorobanDataFromEnvelope returns the Soroban resources attached to a
+// transaction, unwrapping fee bumps. It returns nil for classic transactions.
+func SorobanDataFromEnvelope(envelope xdr.TransactionEnvelope) *xdr.SorobanTransactionData {
+	switch envelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		if envelope.V1 != nil {
+			return envelope.V1.Tx.Ext.SorobanData
+		}
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		if envelope.FeeBump != nil && envelope.FeeBump.Tx.InnerTx.V1 != nil {
+			return envelope.FeeBump.Tx.InnerTx.V1.Tx.Ext.SorobanData
+		}
+	}
+	return nil
+}
+
+// InnerEnvelope returns the inner transaction of a fee-bump envelope as an
+// envelope of its own. A fee bump only changes who pays for a transaction,
+// so it is the inner one that is simulated. Other envelopes, and ones that
+// cannot be decoded, are returned unchanged.
+func InnerEnvelope(envelopeXdr string) string {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil || !envelope.IsFeeBump() {
+		return envelopeXdr
+	}
+	inner := envelope.FeeBump.Tx.InnerTx.V1
+	if inner == nil {
+		return envelopeXdr
+	}
+	encoded, err := xdr.MarshalBase64(xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: inner})
+	if err != nil {
+		return envelopeXdr
+	}
+	return encoded
+}
+
+// FootprintKeys returns the base64 LedgerKeys declared in a transaction's
+// Soroban footprint, read-only keys first
+func FootprintKeys(envelopeXdr string) ([]string, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "transaction envelope")
+	}
+
+	sorobanData := SorobanDataFromEnvelope(envelope)
+	if sorobanData == nil {
+		return nil, nil
+	}
+
+	footprint := sorobanData.Resources.Footprint
+	keys := make([]string, 0, len(footprint.ReadOnly)+len(footprint.ReadWrite))
+	for _, group := range [][]xdr.LedgerKey{footprint.ReadOnly, footprint.ReadWrite} {
+		for _, key := range group {
+			encoded, err := EncodeLedgerKey(key)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, encoded)
+		}
+	}
+	return keys, nil
+}
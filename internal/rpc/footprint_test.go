@@ -0,0 +1,79 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFootprintKeys(t *testing.T) {
+	contractID := xdr.ContractId{7}
+	dataKey := xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			Durability: xdr.ContractDataDurabilityPersistent,
+		},
+	}
+	codeKey := xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{9}},
+	}
+
+	inner := xdr.TransactionV1Envelope{
+		Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"),
+			Ext: xdr.TransactionExt{
+				V: 1,
+				SorobanData: &xdr.SorobanTransactionData{
+					Resources: xdr.SorobanResources{
+						Footprint: xdr.LedgerFootprint{
+							ReadOnly:  []xdr.LedgerKey{codeKey},
+							ReadWrite: []xdr.LedgerKey{dataKey},
+						},
+					},
+				},
+			},
+		},
+	}
+	envelope := xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: &inner}
+	envelopeXdr, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+
+	codeKeyXdr, _ := EncodeLedgerKey(codeKey)
+	dataKeyXdr, _ := EncodeLedgerKey(dataKey)
+
+	keys, err := FootprintKeys(envelopeXdr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{codeKeyXdr, dataKeyXdr}, keys)
+
+	// Fee bumps are unwrapped to the inner transaction
+	feeBump := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+		FeeBump: &xdr.FeeBumpTransactionEnvelope{
+			Tx: xdr.FeeBumpTransaction{
+				FeeSource: inner.Tx.SourceAccount,
+				InnerTx: xdr.FeeBumpTransactionInnerTx{
+					Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+					V1:   &inner,
+				},
+			},
+		},
+	}
+	assert.NotNil(t, SorobanDataFromEnvelope(feeBump))
+
+	feeBumpXdr, err := xdr.MarshalBase64(feeBump)
+	require.NoError(t, err)
+	assert.Equal(t, envelopeXdr, InnerEnvelope(feeBumpXdr))
+	assert.Equal(t, envelopeXdr, InnerEnvelope(envelopeXdr))
+	assert.Equal(t, "not-xdr", InnerEnvelope("not-xdr"))
+
+	_, err = FootprintKeys("not-xdr")
+	assert.Error(t, err)
+}
@@ -0,0 +1,230 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// historyPageSize is the number of transactions requested per
+// getTransactions page while rewinding ledger state
+const historyPageSize = 200
+
+// historyMaxPages bounds the getTransactions pages scanned by one rewind, so
+// entries changed by a busy contract or long gone do not page through the
+// whole retention window
+const historyMaxPages = 50
+
+// GetLedgerEntriesAt returns ledger entries as they stood at the start of
+// ledger, before any of its transactions were applied. Entries that have not
+// changed since are taken from the current state; the rest are rewound to
+// the value they had before the first transaction that touched them at or
+// after ledger, found by scanning getTransactions. Keys missing from the result
+// did not exist at that point (or were evicted, which meta does not record).
+//
+// Only ledgers within the RPC node's retention window can be rewound, and fee
+// charges, which are not part of transaction meta, are not undone. The scan
+// stops after historyMaxPages pages with a warning, returning what it found.
+func (c *Client) GetLedgerEntriesAt(ctx context.Context, keys []string, ledger uint32) (map[string]string, error) {
+	entries := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return entries, nil
+	}
+
+	current, _, err := c.queryLedgerEntries(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		pending[key] = true
+	}
+	// Changes to an existing entry can be no later than its last modification,
+	// but an entry that is gone now could have been removed at any point
+	var scanUntil uint32
+	for _, e := range current.Result.Entries {
+		lastModified := uint32(e.LastModifiedLedger)
+		encoded, err := fullLedgerEntry(e.Xdr, lastModified)
+		if err != nil {
+			return nil, err
+		}
+		entries[e.Key] = encoded
+		if lastModified < ledger {
+			delete(pending, e.Key)
+		} else if lastModified > scanUntil {
+			scanUntil = lastModified
+		}
+	}
+	missing := len(current.Result.Entries) < len(keys)
+
+	if len(pending) > 0 {
+		health, err := c.GetHealth(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ledger < health.Result.OldestLedger {
+			return nil, errors.WrapLedgerArchived(ledger)
+		}
+		if missing {
+			// An entry gone now may have been removed as late as the latest ledger
+			latest := health.Result.LatestLedger
+			if latest == 0 {
+				latest = math.MaxUint32
+			}
+			if latest > scanUntil {
+				scanUntil = latest
+			}
+		}
+	}
+
+	rewound := 0
+	cursor := ""
+	for pages := 0; len(pending) > 0; pages++ {
+		if pages == historyMaxPages {
+			logger.Logger.Warn("Stopped rewinding ledger entries; those not found in the scanned history keep their current state",
+				"ledger", ledger, "pages", pages, "scanned_to", scanUntil, "unresolved", len(pending))
+			break
+		}
+
+		page, err := c.GetTransactions(ctx, ledger, cursor, historyPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		done := len(page.Result.Transactions) == 0 || page.Result.Cursor == "" || page.Result.Cursor == cursor
+		for _, tx := range page.Result.Transactions {
+			if tx.Ledger > scanUntil {
+				done = true
+				break
+			}
+			n, err := rewindTransaction(tx.ResultMetaXdr, pending, entries)
+			if err != nil {
+				return nil, fmt.Errorf("transaction %s: %w", tx.TxHash, err)
+			}
+			rewound += n
+		}
+		if done {
+			break
+		}
+		cursor = page.Result.Cursor
+	}
+
+	// Entries neither present now nor seen in the scanned transactions were
+	// absent throughout
+	logger.Logger.Info("Rewound ledger entries", "ledger", ledger, "requested", len(keys), "rewound", rewound, "unresolved", len(pending))
+	return entries, nil
+}
+
+// rewindTransaction resolves the pending keys a transaction touched to their
+// value before it ran, returning how many it resolved. The first change to
+// an entry is either its prior State or its creation.
+func rewindTransaction(resultMetaXdr string, pending map[string]bool, entries map[string]string) (int, error) {
+	var meta xdr.TransactionMeta
+	if err := xdr.SafeUnmarshalBase64(resultMetaXdr, &meta); err != nil {
+		return 0, errors.WrapUnmarshalFailed(err, "transaction meta")
+	}
+
+	resolved := 0
+	for _, changes := range metaChanges(meta) {
+		for _, change := range changes {
+			var entry *xdr.LedgerEntry
+			switch change.Type {
+			case xdr.LedgerEntryChangeTypeLedgerEntryState:
+				entry = change.State
+			case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+				entry = change.Created
+			case xdr.LedgerEntryChangeTypeLedgerEntryRestored:
+				entry = change.Restored
+			}
+			if entry == nil {
+				continue
+			}
+			key := ledgerKeyFromEntry(*entry)
+			if key == nil {
+				continue
+			}
+			keyXdr, err := EncodeLedgerKey(*key)
+			if err != nil || !pending[keyXdr] {
+				continue
+			}
+
+			delete(pending, keyXdr)
+			resolved++
+			if change.Type != xdr.LedgerEntryChangeTypeLedgerEntryState {
+				// Created or restored from the archive: not live beforehand
+				delete(entries, keyXdr)
+				continue
+			}
+			encoded, err := EncodeLedgerEntry(*entry)
+			if err != nil {
+				return resolved, err
+			}
+			entries[keyXdr] = encoded
+		}
+	}
+	return resolved, nil
+}
+
+// metaChanges lists a transaction's ledger entry changes in the order they
+// were applied
+func metaChanges(meta xdr.TransactionMeta) []xdr.LedgerEntryChanges {
+	var changes []xdr.LedgerEntryChanges
+	switch meta.V {
+	case 0:
+		if meta.Operations != nil {
+			for _, op := range *meta.Operations {
+				changes = append(changes, op.Changes)
+			}
+		}
+	case 1:
+		if v1 := meta.V1; v1 != nil {
+			changes = append(changes, v1.TxChanges)
+			for _, op := range v1.Operations {
+				changes = append(changes, op.Changes)
+			}
+		}
+	case 2:
+		if v2 := meta.V2; v2 != nil {
+			changes = append(changes, v2.TxChangesBefore)
+			for _, op := range v2.Operations {
+				changes = append(changes, op.Changes)
+			}
+			changes = append(changes, v2.TxChangesAfter)
+		}
+	case 3:
+		if v3 := meta.V3; v3 != nil {
+			changes = append(changes, v3.TxChangesBefore)
+			for _, op := range v3.Operations {
+				changes = append(changes, op.Changes)
+			}
+			changes = append(changes, v3.TxChangesAfter)
+		}
+	case 4:
+		if v4 := meta.V4; v4 != nil {
+			changes = append(changes, v4.TxChangesBefore)
+			for _, op := range v4.Operations {
+				changes = append(changes, op.Changes)
+			}
+			changes = append(changes, v4.TxChangesAfter)
+		}
+	}
+	return changes
+}
+
+// fullLedgerEntry turns the LedgerEntryData returned by getLedgerEntries into
+// the LedgerEntry the simulator and transaction meta use
+func fullLedgerEntry(dataXdr string, lastModified uint32) (string, error) {
+	var data xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(dataXdr, &data); err != nil {
+		return "", errors.WrapUnmarshalFailed(err, "ledger entry data")
+	}
+	return EncodeLedgerEntry(xdr.LedgerEntry{LastModifiedLedgerSeq: xdr.Uint32(lastModified), Data: data})
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func counterEntry(t *testing.T, contract byte, value uint32, lastModified uint32) (xdr.LedgerEntry, string) {
+	t.Helper()
+	id := xdr.ContractId{contract}
+	v := xdr.Uint32(value)
+	key := xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}
+	entry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: xdr.Uint32(lastModified),
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id},
+				Key:        key,
+				Durability: xdr.ContractDataDurabilityPersistent,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v},
+			},
+		},
+	}
+	encodedKey, err := EncodeLedgerKey(*ledgerKeyFromEntry(entry))
+	require.NoError(t, err)
+	return entry, encodedKey
+}
+
+func TestGetLedgerEntriesAt(t *testing.T) {
+	// a: unchanged since ledger 90; b: 1 at ledger 100, updated to 2 in 110;
+	// c: created in 108 and deleted since
+	a, keyA := counterEntry(t, 1, 7, 90)
+	bOld, keyB := counterEntry(t, 2, 1, 95)
+	bNew, _ := counterEntry(t, 2, 2, 110)
+	c, keyC := counterEntry(t, 3, 5, 108)
+
+	meta := func(changes ...xdr.LedgerEntryChange) string {
+		encoded, err := xdr.MarshalBase64(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+			Operations: []xdr.OperationMeta{{Changes: changes}},
+		}})
+		require.NoError(t, err)
+		return encoded
+	}
+	txs := []map[string]interface{}{
+		{"ledger": 108, "txHash": "t1", "resultMetaXdr": meta(xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &c})},
+		{"ledger": 110, "txHash": "t2", "resultMetaXdr": meta(
+			xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &bOld},
+			xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &bNew},
+		)},
+	}
+
+	dataXdr := func(e xdr.LedgerEntry) string {
+		encoded, err := xdr.MarshalBase64(e.Data)
+		require.NoError(t, err)
+		return encoded
+	}
+	oldest := uint32(50)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Params struct {
+				Pagination struct {
+					Cursor string `json:"cursor"`
+				} `json:"pagination"`
+			} `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var result interface{}
+		switch req.Method {
+		case "getLedgerEntries":
+			result = map[string]interface{}{"entries": []map[string]interface{}{
+				{"key": keyA, "xdr": dataXdr(a), "lastModifiedLedgerSeq": 90},
+				{"key": keyB, "xdr": dataXdr(bNew), "lastModifiedLedgerSeq": 110},
+			}}
+		case "getHealth":
+			result = map[string]interface{}{"status": "healthy", "oldestLedger": oldest, "latestLedger": 200}
+		case "getTransactions":
+			page := map[string]interface{}{"transactions": txs, "cursor": "end"}
+			if req.Params.Pagination.Cursor == "end" {
+				page = map[string]interface{}{"transactions": []interface{}{}, "cursor": "end"}
+			}
+			result = page
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	defer server.Close()
+
+	client := &Client{HorizonURL: server.URL, SorobanURL: server.URL, AltURLs: []string{server.URL}}
+	entries, err := client.GetLedgerEntriesAt(context.Background(), []string{keyA, keyB, keyC}, 100)
+	require.NoError(t, err)
+
+	want := func(e xdr.LedgerEntry) string {
+		encoded, err := EncodeLedgerEntry(e)
+		require.NoError(t, err)
+		return encoded
+	}
+	assert.Equal(t, want(a), entries[keyA], "unchanged entries keep their current value")
+	assert.Equal(t, want(bOld), entries[keyB], "modified entries are rewound")
+	assert.NotContains(t, entries, keyC, "entries created later did not exist")
+
+	oldest = 105
+	_, err = client.GetLedgerEntriesAt(context.Background(), []string{keyB}, 100)
+	assert.ErrorContains(t, err, "archived")
+
+	entries, err = client.GetLedgerEntriesAt(context.Background(), []string{keyA}, 100)
+	require.NoError(t, err, "no history is needed when nothing changed")
+	assert.Equal(t, want(a), entries[keyA])
+}
+
+func TestGetLedgerEntriesAt_BoundedScan(t *testing.T) {
+	// The entry was deleted at some point, and every page holds unrelated
+	// transactions
+	_, key := counterEntry(t, 4, 1, 95)
+	meta, err := xdr.MarshalBase64(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}})
+	require.NoError(t, err)
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var result interface{}
+		switch req.Method {
+		case "getLedgerEntries":
+			result = map[string]interface{}{"entries": []interface{}{}}
+		case "getHealth":
+			result = map[string]interface{}{"status": "healthy", "oldestLedger": 50, "latestLedger": 1_000_000}
+		case "getTransactions":
+			pages++
+			result = map[string]interface{}{"transactions": []map[string]interface{}{{"ledger": 100 + pages, "txHash": "t", "resultMetaXdr": meta}}, "cursor": fmt.Sprint(pages)}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	defer server.Close()
+
+	client := &Client{HorizonURL: server.URL, SorobanURL: server.URL, AltURLs: []string{server.URL}}
+	entries, err := client.GetLedgerEntriesAt(context.Background(), []string{key}, 100)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.Equal(t, historyMaxPages, pages)
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+type GetLatestLedgerRequest struct {
+	Jsonrpc string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+}
+
+// LatestLedger is the most recent ledger known to the RPC node
+type LatestLedger struct {
+	ID              string `json:"id"`
+	ProtocolVersion uint32 `json:"protocolVersion"`
+	Sequence        uint32 `json:"sequence"`
+}
+
+type GetLatestLedgerResponse struct {
+	Jsonrpc string       `json:"jsonrpc"`
+	ID      int          `json:"id"`
+	Result  LatestLedger `json:"result"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// GetLatestLedger fetches the latest ledger from Soroban RPC, whose protocol
+// version is the one the network currently runs
+func (c *Client) GetLatestLedger(ctx context.Context) (*LatestLedger, error) {
+	logger.Logger.Debug("Fetching latest ledger", "url", c.SorobanURL)
+
+	reqBody := GetLatestLedgerRequest{
+		Jsonrpc: "2.0",
+		ID:      1,
+		Method:  "getLatestLedger",
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, errors.WrapMarshalFailed(err)
+	}
+
+	targetURL := c.SorobanURL
+	req, err := http.NewRequestWithContext(ctx, "POST", targetURL, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.getHTTPClient().Do(req)
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "body read error")
+	}
+
+	var rpcResp GetLatestLedgerResponse
+	if err := json.Unmarshal(respBytes, &rpcResp); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, string(respBytes))
+	}
+
+	if rpcResp.Error != nil {
+		return nil, errors.WrapRPCError(targetURL, rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+
+	return &rpcResp.Result, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+const (
+	// DefaultLedgerEntriesBatchSize is the most keys stellar-rpc accepts in
+	// one getLedgerEntries request out of the box
+	DefaultLedgerEntriesBatchSize = 200
+	// DefaultLedgerEntriesParallelism is how many getLedgerEntries batches
+	// are in flight at once
+	DefaultLedgerEntriesParallelism = 4
+)
+
+// queryLedgerEntries fetches keys with as few getLedgerEntries requests as
+// the server allows: batches of up to the batch size, several at a time,
+// merged into one response. A batch the server refuses as too large is split
+// in half and retried. The URL of the last batch is returned.
+func (c *Client) queryLedgerEntries(ctx context.Context, keys []string) (*GetLedgerEntriesResponse, string, error) {
+	size, parallel := c.ledgerBatching()
+	if len(keys) <= size {
+		return c.queryLedgerEntriesBatch(ctx, keys)
+	}
+
+	var batches [][]string
+	for start := 0; start < len(keys); start += size {
+		end := start + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batches = append(batches, keys[start:end])
+	}
+	logger.Logger.Debug("Fetching ledger entries in batches", "keys", len(keys), "batches", len(batches), "parallel", parallel)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses := make([]*GetLedgerEntriesResponse, len(batches))
+	var (
+		mu        sync.Mutex
+		firstErr  error
+		targetURL string
+		wg        sync.WaitGroup
+	)
+	sem := make(chan struct{}, parallel)
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch []string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			resp, url, err := c.queryLedgerEntriesBatch(ctx, batch)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				// The other batches are abandoned with the first failure
+				if firstErr == nil {
+					firstErr = err
+					targetURL = url
+					cancel()
+				}
+				return
+			}
+			responses[i] = resp
+			if firstErr == nil {
+				targetURL = url
+			}
+		}(i, batch)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, targetURL, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, targetURL, err
+	}
+	return mergeLedgerEntries(responses...), targetURL, nil
+}
+
+// queryLedgerEntriesBatch sends one batch, halving it for as long as the
+// server refuses it as too large
+func (c *Client) queryLedgerEntriesBatch(ctx context.Context, keys []string) (*GetLedgerEntriesResponse, string, error) {
+	resp, url, err := c.postLedgerEntries(ctx, keys)
+	if err == nil || len(keys) < 2 || !batchTooLarge(err) {
+		return resp, url, err
+	}
+
+	half := len(keys) / 2
+	logger.Logger.Debug("Splitting ledger entry batch", "keys", len(keys), "error", err)
+	first, _, err := c.queryLedgerEntriesBatch(ctx, keys[:half])
+	if err != nil {
+		return nil, url, err
+	}
+	second, url, err := c.queryLedgerEntriesBatch(ctx, keys[half:])
+	if err != nil {
+		return nil, url, err
+	}
+	return mergeLedgerEntries(first, second), url, nil
+}
+
+// batchTooLarge reports whether the server refused a request for having too
+// many keys, or a response that would be too big
+func batchTooLarge(err error) bool {
+	if IsResponseTooLarge(err) {
+		return true
+	}
+	// stellar-rpc: "key count (250) exceeds maximum supported (200)"
+	return strings.Contains(err.Error(), "exceeds maximum")
+}
+
+// mergeLedgerEntries combines the responses of several batches. The oldest
+// latestLedger among them is kept, being the one all entries are at least as
+// recent as.
+func mergeLedgerEntries(responses ...*GetLedgerEntriesResponse) *GetLedgerEntriesResponse {
+	merged := &GetLedgerEntriesResponse{Jsonrpc: "2.0", ID: 1}
+	for i, resp := range responses {
+		merged.Result.Entries = append(merged.Result.Entries, resp.Result.Entries...)
+		if i == 0 || resp.Result.LatestLedger < merged.Result.LatestLedger {
+			merged.Result.LatestLedger = resp.Result.LatestLedger
+		}
+	}
+	return merged
+}
+
+// ledgerBatching returns the batch size and parallelism, applying defaults
+func (c *Client) ledgerBatching() (size, parallel int) {
+	size, parallel = c.ledgerBatchSize, c.ledgerBatchParallel
+	if size <= 0 {
+		size = DefaultLedgerEntriesBatchSize
+	}
+	if parallel <= 0 {
+		parallel = DefaultLedgerEntriesParallelism
+	}
+	return size, parallel
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ledgerBatchServer echoes the requested keys, refusing requests of more than
+// max keys the way stellar-rpc does
+func ledgerBatchServer(t *testing.T, max int, requests *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		var req struct {
+			Params [][]string `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		keys := req.Params[0]
+		if len(keys) > max {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "error": map[string]interface{}{
+				"code": -32602, "message": fmt.Sprintf("key count (%d) exceeds maximum supported (%d)", len(keys), max),
+			}})
+			return
+		}
+		entries := []map[string]interface{}{}
+		for _, k := range keys {
+			entries = append(entries, map[string]interface{}{"key": k, "xdr": "entry-" + k})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": map[string]interface{}{
+			"entries": entries, "latestLedger": 100,
+		}})
+	}))
+}
+
+func batchKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%03d", i)
+	}
+	return keys
+}
+
+func TestQueryLedgerEntries_Batches(t *testing.T) {
+	var requests int32
+	server := ledgerBatchServer(t, 10, &requests)
+	defer server.Close()
+
+	client := &Client{HorizonURL: server.URL, AltURLs: []string{server.URL}, ledgerBatchSize: 10, ledgerBatchParallel: 3}
+	resp, _, err := client.queryLedgerEntries(context.Background(), batchKeys(45))
+	require.NoError(t, err)
+	assert.Len(t, resp.Result.Entries, 45)
+	assert.Equal(t, 100, resp.Result.LatestLedger)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&requests))
+}
+
+func TestQueryLedgerEntries_SplitsRefusedBatches(t *testing.T) {
+	var requests int32
+	server := ledgerBatchServer(t, 8, &requests)
+	defer server.Close()
+
+	// The server allows fewer keys than the batch size
+	client := &Client{HorizonURL: server.URL, AltURLs: []string{server.URL}, ledgerBatchSize: 20}
+	resp, _, err := client.queryLedgerEntries(context.Background(), batchKeys(20))
+	require.NoError(t, err)
+	got := make(map[string]bool)
+	for _, e := range resp.Result.Entries {
+		got[e.Key] = true
+	}
+	assert.Len(t, got, 20)
+	// 20 keys refused, then 2 × 10 refused, then 4 × 5
+	assert.Equal(t, int32(7), atomic.LoadInt32(&requests))
+}
+
+func TestWithLedgerEntriesBatching(t *testing.T) {
+	client, err := NewClient(WithNetwork(Testnet), WithLedgerEntriesBatching(50, 2))
+	require.NoError(t, err)
+	size, parallel := client.ledgerBatching()
+	assert.Equal(t, 50, size)
+	assert.Equal(t, 2, parallel)
+
+	_, err = NewClient(WithLedgerEntriesBatching(-1, 0))
+	assert.Error(t, err)
+
+	size, parallel = (&Client{}).ledgerBatching()
+	assert.Equal(t, DefaultLedgerEntriesBatchSize, size)
+	assert.Equal(t, DefaultLedgerEntriesParallelism, parallel)
+}
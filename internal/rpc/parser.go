@@ -10,6 +10,7 @@ type TransactionResponse struct {
 	EnvelopeXdr   string
 	ResultXdr     string
 	ResultMetaXdr string
+	Ledger        uint32 // ledger sequence the transaction was included in, 0 if unknown
 }
 
 // ParseTransactionResponse converts a Horizon transaction into a TransactionResponse
@@ -18,6 +19,7 @@ func ParseTransactionResponse(tx hProtocol.Transaction) *TransactionResponse {
 		EnvelopeXdr:   tx.EnvelopeXdr,
 		ResultXdr:     tx.ResultXdr,
 		ResultMetaXdr: tx.ResultMetaXdr,
+		Ledger:        uint32(tx.Ledger),
 	}
 }
 
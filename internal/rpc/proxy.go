@@ -0,0 +1,87 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyFunc selects the proxy for a request, as http.Transport.Proxy does
+type ProxyFunc func(*http.Request) (*url.URL, error)
+
+// NewProxyFunc returns the proxy selection for RPC requests. An explicit
+// proxy, such as "http://proxy.corp:3128" or "socks5h://127.0.0.1:9050" for
+// Tor, is used for every endpoint. Without one, HTTPS_PROXY and HTTP_PROXY are
+// honored and ALL_PROXY covers any scheme they leave unset. NO_PROXY applies
+// either way.
+func NewProxyFunc(proxy string) (ProxyFunc, error) {
+	cfg := httpproxy.FromEnvironment()
+	if proxy != "" {
+		u, err := parseProxyURL(proxy)
+		if err != nil {
+			return nil, err
+		}
+		cfg.HTTPProxy, cfg.HTTPSProxy = u.String(), u.String()
+	} else if all := getenvAny("ALL_PROXY", "all_proxy"); all != "" {
+		if cfg.HTTPProxy == "" {
+			cfg.HTTPProxy = all
+		}
+		if cfg.HTTPSProxy == "" {
+			cfg.HTTPSProxy = all
+		}
+	}
+	fn := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return fn(req.URL)
+	}, nil
+}
+
+// parseProxyURL accepts http, https, socks5 and socks5h proxies; a bare
+// host:port is taken to be an HTTP proxy
+func parseProxyURL(proxy string) (*url.URL, error) {
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("invalid proxy URL: %v", err))
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, errors.WrapValidationError(fmt.Sprintf("unsupported proxy scheme %q: use http, https, socks5 or socks5h", u.Scheme))
+	}
+	if u.Host == "" {
+		return nil, errors.WrapValidationError("invalid proxy URL: missing host")
+	}
+	return u, nil
+}
+
+// NewProxyTransport is http.DefaultTransport routed through proxy, or through
+// the proxies of the environment when proxy is nil
+func NewProxyTransport(proxy ProxyFunc) *http.Transport {
+	if proxy == nil {
+		// Without an explicit proxy the environment cannot fail to parse
+		proxy, _ = NewProxyFunc("")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	return transport
+}
+
+func getenvAny(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
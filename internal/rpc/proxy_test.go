@@ -0,0 +1,104 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func clearProxyEnv(t *testing.T) {
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy", "ALL_PROXY", "all_proxy", "NO_PROXY", "no_proxy", "REQUEST_METHOD"} {
+		t.Setenv(name, "")
+	}
+}
+
+func proxyFor(t *testing.T, fn ProxyFunc, target string) string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	u, err := fn(req)
+	if err != nil {
+		t.Fatalf("proxy for %s: %v", target, err)
+	}
+	if u == nil {
+		return ""
+	}
+	return u.String()
+}
+
+func TestNewProxyFuncExplicit(t *testing.T) {
+	clearProxyEnv(t)
+	t.Setenv("HTTPS_PROXY", "http://env-proxy:8080")
+	t.Setenv("NO_PROXY", "internal.example")
+
+	fn, err := NewProxyFunc("socks5h://127.0.0.1:9050")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := proxyFor(t, fn, "https://soroban-testnet.stellar.org"); got != "socks5h://127.0.0.1:9050" {
+		t.Errorf("expected the explicit proxy over HTTPS_PROXY, got %q", got)
+	}
+	if got := proxyFor(t, fn, "https://internal.example/rpc"); got != "" {
+		t.Errorf("expected NO_PROXY to be honored, got %q", got)
+	}
+}
+
+func TestNewProxyFuncEnvironment(t *testing.T) {
+	clearProxyEnv(t)
+	t.Setenv("HTTP_PROXY", "http://http-proxy:3128")
+	t.Setenv("ALL_PROXY", "socks5://all-proxy:1080")
+
+	fn, err := NewProxyFunc("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := proxyFor(t, fn, "http://rpc.example"); got != "http://http-proxy:3128" {
+		t.Errorf("expected HTTP_PROXY for http, got %q", got)
+	}
+	if got := proxyFor(t, fn, "https://rpc.example"); got != "socks5://all-proxy:1080" {
+		t.Errorf("expected ALL_PROXY to cover https, got %q", got)
+	}
+}
+
+func TestNewProxyFuncInvalid(t *testing.T) {
+	for _, proxy := range []string{"ftp://proxy:21", "http://", "socks5://%zz"} {
+		if _, err := NewProxyFunc(proxy); err == nil {
+			t.Errorf("expected %q to be rejected", proxy)
+		}
+	}
+	if u, err := parseProxyURL("proxy.corp:3128"); err != nil || u.String() != "http://proxy.corp:3128" {
+		t.Errorf("expected a bare host:port to be an HTTP proxy, got %v, %v", u, err)
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	clearProxyEnv(t)
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute target URL
+		proxied = append(proxied, r.URL.String())
+	}))
+	defer proxy.Close()
+
+	client, err := NewClient(WithProxy(proxy.URL), WithRetryConfig(NoRetryConfig()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := client.httpClient.Get("http://rpc.invalid/health")
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+	if len(proxied) != 1 || proxied[0] != "http://rpc.invalid/health" {
+		t.Errorf("expected the request to go through the proxy, got %v", proxied)
+	}
+
+	if _, err := NewClient(WithProxy("gopher://proxy")); err == nil {
+		t.Error("expected error for an unsupported proxy scheme")
+	}
+}
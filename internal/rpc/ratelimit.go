@@ -0,0 +1,173 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+// RateLimiter is a token bucket that spaces out requests to an RPC provider.
+// It holds up to burst tokens and refills at rate tokens per second; each
+// request takes one token, waiting for it when the bucket is empty. A
+// RateLimiter is safe for concurrent use and may be shared by several clients
+// so that their combined traffic stays under the provider's limit.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewRateLimiter returns a limiter allowing rate requests per second with
+// bursts of up to burst requests. A burst below 1 defaults to rate rounded
+// up, so one second's worth of requests can go out at once.
+func NewRateLimiter(rate float64, burst int) (*RateLimiter, error) {
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return nil, errors.WrapValidationError("rate limit must be a positive number of requests per second")
+	}
+	if burst < 0 {
+		return nil, errors.WrapValidationError("rate limit burst cannot be negative")
+	}
+	if burst == 0 {
+		burst = int(math.Ceil(rate))
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}, nil
+}
+
+// Wait blocks until a request may be sent or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	if err := waitWithContext(ctx, delay); err != nil {
+		l.cancel()
+		return err
+	}
+	return nil
+}
+
+// reserve takes a token, letting the bucket go into debt when it is empty,
+// and returns how long the caller has to wait until the token is due
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns the token of a reservation that was given up
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = math.Min(l.burst, l.tokens+1)
+}
+
+// rateLimitTransport waits for the limiter before every request, retries
+// included
+type rateLimitTransport struct {
+	limiter   *RateLimiter
+	transport http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.transport.RoundTrip(req)
+}
+
+// ConcurrencyLimiter caps how many requests are in flight at once. A request
+// holds its slot until its response body is closed. Like RateLimiter it may
+// be shared by several clients.
+type ConcurrencyLimiter struct {
+	slots chan struct{}
+}
+
+// NewConcurrencyLimiter returns a limiter allowing max requests in flight
+func NewConcurrencyLimiter(max int) (*ConcurrencyLimiter, error) {
+	if max < 1 {
+		return nil, errors.WrapValidationError("RPC concurrency must be at least 1")
+	}
+	return &ConcurrencyLimiter{slots: make(chan struct{}, max)}, nil
+}
+
+// Acquire blocks until a slot is free or ctx is done
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire
+func (l *ConcurrencyLimiter) Release() {
+	<-l.slots
+}
+
+// concurrencyTransport holds a slot of the limiter for each request
+type concurrencyTransport struct {
+	limiter   *ConcurrencyLimiter
+	transport http.RoundTripper
+}
+
+func (t *concurrencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Acquire(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		t.limiter.Release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: t.limiter.Release}
+	return resp, nil
+}
+
+// releasingBody releases its request's slot once, when it is fully read or
+// closed
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.once.Do(b.release)
+	}
+	return n, err
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	l, err := NewRateLimiter(2, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if d := l.reserve(); d != 0 {
+			t.Fatalf("request %d within the burst waited %v", i, d)
+		}
+	}
+	if d := l.reserve(); d != 500*time.Millisecond {
+		t.Errorf("expected the fourth request to wait 500ms, got %v", d)
+	}
+	if d := l.reserve(); d != time.Second {
+		t.Errorf("expected the fifth request to wait 1s, got %v", d)
+	}
+
+	// A long idle period refills the bucket, but never past the burst
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		if d := l.reserve(); d != 0 {
+			t.Fatalf("request %d after refill waited %v", i, d)
+		}
+	}
+	if d := l.reserve(); d == 0 {
+		t.Error("expected the bucket to hold no more than the burst")
+	}
+}
+
+func TestNewRateLimiterDefaultBurst(t *testing.T) {
+	l, err := NewRateLimiter(2.5, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l.burst != 3 {
+		t.Errorf("expected burst to default to 3, got %v", l.burst)
+	}
+	if _, err := NewRateLimiter(0, 1); err == nil {
+		t.Error("expected error for a zero rate")
+	}
+}
+
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	l, err := NewRateLimiter(1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Fatal("expected the canceled wait to fail")
+	}
+	if l.tokens < -0.5 {
+		t.Errorf("expected the canceled reservation to be returned, tokens = %v", l.tokens)
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	l, err := NewRateLimiter(20, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := createHTTPClient("", nil, NoRetryConfig(), transportOptions{rateLimiter: l})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		resp.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected three requests at 20/s with no burst to take ~100ms, took %v", elapsed)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestConcurrencyTransport(t *testing.T) {
+	var (
+		mu              sync.Mutex
+		active, maxSeen int
+		release         = make(chan struct{})
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		if active > maxSeen {
+			maxSeen = active
+		}
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		active--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	l, err := NewConcurrencyLimiter(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := createHTTPClient("", nil, NoRetryConfig(), transportOptions{concurrency: l})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Errorf("request: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if maxSeen != 2 {
+		t.Errorf("expected at most 2 requests in flight, saw %d", maxSeen)
+	}
+	if len(l.slots) != 0 {
+		t.Errorf("expected every slot to be released, %d still held", len(l.slots))
+	}
+	if _, err := NewConcurrencyLimiter(0); err == nil {
+		t.Error("expected error for a zero cap")
+	}
+}
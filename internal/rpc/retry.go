@@ -5,11 +5,15 @@ package rpc
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
+	"net"
 	"net/http"
 	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/dotandev/hintents/internal/errors"
@@ -32,10 +36,83 @@ func DefaultRetryConfig() RetryConfig {
 		InitialBackoff:     1 * time.Second,
 		MaxBackoff:         10 * time.Second,
 		JitterFraction:     0.1,
-		StatusCodesToRetry: []int{429, 503, 504},
+		StatusCodesToRetry: []int{429, 500, 502, 503, 504},
 	}
 }
 
+// NoRetryConfig returns a configuration that makes a single attempt
+func NoRetryConfig() RetryConfig {
+	return RetryConfig{}
+}
+
+type retryConfigKey struct{}
+
+// ContextWithRetryConfig returns a context whose RPC calls use cfg instead of the
+// client's retry configuration, e.g. to fail fast on a health probe or to be
+// more patient with a large getLedgerEntries request.
+func ContextWithRetryConfig(ctx context.Context, cfg RetryConfig) context.Context {
+	return context.WithValue(ctx, retryConfigKey{}, cfg)
+}
+
+// retryConfigFromContext returns the per-call override in ctx, or fallback
+func retryConfigFromContext(ctx context.Context, fallback RetryConfig) RetryConfig {
+	if cfg, ok := ctx.Value(retryConfigKey{}).(RetryConfig); ok {
+		return cfg
+	}
+	return fallback
+}
+
+// isRetryableError reports whether a transport error of a request made with
+// ctx is likely transient. Nothing is retried once ctx is canceled or past
+// its deadline, but a timeout of one attempt, such as a dial or TLS
+// handshake timeout, is. Malformed requests are not retried.
+func isRetryableError(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	if stderrors.Is(err, context.Canceled) {
+		return false
+	}
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if stderrors.Is(err, io.EOF) || stderrors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	if stderrors.Is(err, syscall.ECONNRESET) || stderrors.Is(err, syscall.ECONNREFUSED) ||
+		stderrors.Is(err, syscall.ECONNABORTED) || stderrors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return stderrors.As(err, &opErr)
+}
+
+// rewindRequest prepares req for another attempt, restoring its body.
+// Requests whose body cannot be replayed are not retried.
+func rewindRequest(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	next := req.Clone(req.Context())
+	next.Body = body
+	return next, true
+}
+
 // Retrier handles HTTP request retries with exponential backoff and jitter
 type Retrier struct {
 	config RetryConfig
@@ -55,12 +132,13 @@ func NewRetrier(config RetryConfig, client *http.Client) *Retrier {
 
 // Do executes an HTTP request with retry logic
 func (r *Retrier) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	cfg := retryConfigFromContext(ctx, r.config)
 	var lastErr error
-	backoff := r.config.InitialBackoff
+	backoff := cfg.InitialBackoff
 
-	for attempt := 0; attempt <= r.config.MaxRetries; attempt++ {
+	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
 		if attempt > 0 {
-			if err := r.waitWithContext(ctx, backoff); err != nil {
+			if err := waitWithContext(ctx, backoff); err != nil {
 				return nil, errors.WrapRPCTimeout(err)
 			}
 		}
@@ -68,10 +146,13 @@ func (r *Retrier) Do(ctx context.Context, req *http.Request) (*http.Response, er
 		resp, err := r.client.Do(req.Clone(ctx))
 		if err != nil {
 			lastErr = err
-			if attempt < r.config.MaxRetries {
+			if !isRetryableError(ctx, err) {
+				break
+			}
+			if attempt < cfg.MaxRetries {
 				logger.Logger.Debug("Request failed, will retry", "attempt", attempt+1, "error", err)
 			}
-			backoff = r.nextBackoff(backoff)
+			backoff = nextBackoff(cfg, backoff)
 			continue
 		}
 
@@ -82,9 +163,9 @@ func (r *Retrier) Do(ctx context.Context, req *http.Request) (*http.Response, er
 		}
 
 		// Check if response status is retryable
-		if r.shouldRetry(resp.StatusCode) {
+		if shouldRetryStatus(cfg, resp.StatusCode) {
 			lastErr = fmt.Errorf("status code %d", resp.StatusCode)
-			retryAfter := r.getRetryAfter(resp)
+			retryAfter := parseRetryAfter(resp)
 
 			logger.Logger.Warn("Rate limited or temporary failure, will retry",
 				"attempt", attempt+1,
@@ -97,10 +178,10 @@ func (r *Retrier) Do(ctx context.Context, req *http.Request) (*http.Response, er
 			if retryAfter > 0 {
 				backoff = retryAfter
 			} else {
-				backoff = r.nextBackoff(backoff)
+				backoff = nextBackoff(cfg, backoff)
 			}
 
-			if attempt < r.config.MaxRetries {
+			if attempt < cfg.MaxRetries {
 				continue
 			}
 			// If we've exhausted retries on a retryable error, return error
@@ -116,68 +197,17 @@ func (r *Retrier) Do(ctx context.Context, req *http.Request) (*http.Response, er
 
 // shouldRetry determines if the response status code warrants a retry
 func (r *Retrier) shouldRetry(statusCode int) bool {
-	for _, code := range r.config.StatusCodesToRetry {
-		if statusCode == code {
-			return true
-		}
-	}
-	return false
+	return shouldRetryStatus(r.config, statusCode)
 }
 
 // getRetryAfter parses the Retry-After header and returns the duration
-// Supports both "seconds" and "HTTP-date" formats (RFC 7231)
 func (r *Retrier) getRetryAfter(resp *http.Response) time.Duration {
-	retryAfter := resp.Header.Get("Retry-After")
-	if retryAfter == "" {
-		return 0
-	}
-
-	// Try parsing as seconds (integer)
-	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
-		return time.Duration(seconds) * time.Second
-	}
-
-	// Try parsing as HTTP-date
-	if t, err := time.Parse(time.RFC1123, retryAfter); err == nil {
-		dur := time.Until(t)
-		if dur > 0 {
-			return dur
-		}
-	}
-
-	return 0
+	return parseRetryAfter(resp)
 }
 
 // nextBackoff calculates the next backoff duration with exponential backoff and jitter
 func (r *Retrier) nextBackoff(current time.Duration) time.Duration {
-	// Exponential backoff: double the current duration
-	next := time.Duration(float64(current) * 2)
-	if next > r.config.MaxBackoff {
-		next = r.config.MaxBackoff
-	}
-
-	// Add jitter: ±JitterFraction of the duration
-	if r.config.JitterFraction > 0 {
-		jitterAmount := float64(next) * r.config.JitterFraction
-		jitterRange := math.Round(jitterAmount)
-		jitter := time.Duration(rand.Int63n(int64(jitterRange)*2) - int64(jitterRange))
-		next = next + jitter
-		if next < 0 {
-			next = 0
-		}
-	}
-
-	return next
-}
-
-// waitWithContext waits for the specified duration or until context is cancelled
-func (r *Retrier) waitWithContext(ctx context.Context, duration time.Duration) error {
-	select {
-	case <-time.After(duration):
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
-	}
+	return nextBackoff(r.config, current)
 }
 
 // RetryTransport is an http.RoundTripper that adds retry logic to requests
@@ -197,25 +227,36 @@ func NewRetryTransport(config RetryConfig, transport http.RoundTripper) *RetryTr
 	}
 }
 
-// RoundTrip implements http.RoundTripper interface with retry logic
+// RoundTrip implements http.RoundTripper interface with retry logic.
+// A RetryConfig attached to the request context with ContextWithRetryConfig takes
+// precedence over the transport's own configuration.
 func (rt *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cfg := retryConfigFromContext(req.Context(), rt.config)
 	var lastErr error
-	backoff := rt.config.InitialBackoff
+	backoff := cfg.InitialBackoff
 
-	for attempt := 0; attempt <= rt.config.MaxRetries; attempt++ {
+	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
 		if attempt > 0 {
-			if err := rt.waitWithContext(req.Context(), backoff); err != nil {
+			if err := waitWithContext(req.Context(), backoff); err != nil {
 				return nil, errors.WrapRPCTimeout(err)
 			}
+			next, ok := rewindRequest(req)
+			if !ok {
+				break
+			}
+			req = next
 		}
 
 		resp, err := rt.transport.RoundTrip(req)
 		if err != nil {
 			lastErr = err
-			if attempt < rt.config.MaxRetries {
+			if !isRetryableError(req.Context(), err) {
+				break
+			}
+			if attempt < cfg.MaxRetries {
 				logger.Logger.Debug("RoundTrip failed, will retry", "attempt", attempt+1, "error", err)
 			}
-			backoff = rt.nextBackoff(backoff)
+			backoff = nextBackoff(cfg, backoff)
 			continue
 		}
 
@@ -226,9 +267,9 @@ func (rt *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		}
 
 		// Check if response status is retryable
-		if rt.shouldRetry(resp.StatusCode) {
+		if shouldRetryStatus(cfg, resp.StatusCode) {
 			lastErr = fmt.Errorf("status code %d", resp.StatusCode)
-			retryAfter := rt.getRetryAfter(resp)
+			retryAfter := parseRetryAfter(resp)
 
 			logger.Logger.Warn("Rate limited or temporary failure, will retry",
 				"attempt", attempt+1,
@@ -241,10 +282,10 @@ func (rt *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 			if retryAfter > 0 {
 				backoff = retryAfter
 			} else {
-				backoff = rt.nextBackoff(backoff)
+				backoff = nextBackoff(cfg, backoff)
 			}
 
-			if attempt < rt.config.MaxRetries {
+			if attempt < cfg.MaxRetries {
 				continue
 			}
 			// If we've exhausted retries on a retryable error, return error
@@ -260,7 +301,21 @@ func (rt *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 // shouldRetry determines if the response status code warrants a retry
 func (rt *RetryTransport) shouldRetry(statusCode int) bool {
-	for _, code := range rt.config.StatusCodesToRetry {
+	return shouldRetryStatus(rt.config, statusCode)
+}
+
+// getRetryAfter parses the Retry-After header and returns the duration
+func (rt *RetryTransport) getRetryAfter(resp *http.Response) time.Duration {
+	return parseRetryAfter(resp)
+}
+
+// nextBackoff calculates the next backoff duration with exponential backoff and jitter
+func (rt *RetryTransport) nextBackoff(current time.Duration) time.Duration {
+	return nextBackoff(rt.config, current)
+}
+
+func shouldRetryStatus(cfg RetryConfig, statusCode int) bool {
+	for _, code := range cfg.StatusCodesToRetry {
 		if statusCode == code {
 			return true
 		}
@@ -268,8 +323,9 @@ func (rt *RetryTransport) shouldRetry(statusCode int) bool {
 	return false
 }
 
-// getRetryAfter parses the Retry-After header and returns the duration
-func (rt *RetryTransport) getRetryAfter(resp *http.Response) time.Duration {
+// parseRetryAfter parses the Retry-After header and returns the duration
+// Supports both "seconds" and "HTTP-date" formats (RFC 7231)
+func parseRetryAfter(resp *http.Response) time.Duration {
 	retryAfter := resp.Header.Get("Retry-After")
 	if retryAfter == "" {
 		return 0
@@ -291,20 +347,19 @@ func (rt *RetryTransport) getRetryAfter(resp *http.Response) time.Duration {
 	return 0
 }
 
-// nextBackoff calculates the next backoff duration with exponential backoff and jitter
-func (rt *RetryTransport) nextBackoff(current time.Duration) time.Duration {
-	// Exponential backoff: double the current duration
+// nextBackoff doubles current up to MaxBackoff and applies ±JitterFraction
+// so that concurrent clients do not retry in lockstep
+func nextBackoff(cfg RetryConfig, current time.Duration) time.Duration {
 	next := time.Duration(float64(current) * 2)
-	if next > rt.config.MaxBackoff {
-		next = rt.config.MaxBackoff
+	if cfg.MaxBackoff > 0 && next > cfg.MaxBackoff {
+		next = cfg.MaxBackoff
 	}
 
-	// Add jitter: ±JitterFraction of the duration
-	if rt.config.JitterFraction > 0 {
-		jitterAmount := float64(next) * rt.config.JitterFraction
-		jitterRange := math.Round(jitterAmount)
-		jitter := time.Duration(rand.Int63n(int64(jitterRange)*2) - int64(jitterRange))
-		next = next + jitter
+	if cfg.JitterFraction > 0 {
+		jitterRange := int64(math.Round(float64(next) * cfg.JitterFraction))
+		if jitterRange > 0 {
+			next += time.Duration(rand.Int63n(jitterRange*2) - jitterRange)
+		}
 		if next < 0 {
 			next = 0
 		}
@@ -314,9 +369,11 @@ func (rt *RetryTransport) nextBackoff(current time.Duration) time.Duration {
 }
 
 // waitWithContext waits for the specified duration or until context is cancelled
-func (rt *RetryTransport) waitWithContext(ctx context.Context, duration time.Duration) error {
+func waitWithContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
 	select {
-	case <-time.After(duration):
+	case <-timer.C:
 		return nil
 	case <-ctx.Done():
 		return ctx.Err()
@@ -6,9 +6,13 @@ package rpc
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -528,6 +532,196 @@ func searchString(s, sub string) bool {
 	return false
 }
 
+func TestRetryTransportReplaysBody(t *testing.T) {
+	attempts := 0
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := DefaultRetryConfig()
+	cfg.InitialBackoff = 10 * time.Millisecond
+	client := &http.Client{Transport: NewRetryTransport(cfg, http.DefaultTransport)}
+
+	req, err := http.NewRequest("POST", server.URL, bytes.NewBufferString(`{"method":"getHealth"}`))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+	for i, body := range bodies {
+		if body != `{"method":"getHealth"}` {
+			t.Errorf("attempt %d sent body %q", i+1, body)
+		}
+	}
+}
+
+func TestRetryTransportContextOverride(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := DefaultRetryConfig()
+	cfg.InitialBackoff = 10 * time.Millisecond
+	client := &http.Client{Transport: NewRetryTransport(cfg, http.DefaultTransport)}
+
+	ctx := ContextWithRetryConfig(context.Background(), NoRetryConfig())
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("expected the 503 response to be returned, got %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", resp.StatusCode)
+	}
+	if attempts != 1 {
+		t.Errorf("expected a single attempt with retries disabled, got %d", attempts)
+	}
+}
+
+func TestRetryTransportDoesNotRetryCancelled(t *testing.T) {
+	attempts := 0
+	transport := NewRetryTransport(DefaultRetryConfig(), roundTripFunc(func(*http.Request) (*http.Response, error) {
+		attempts++
+		return nil, context.Canceled
+	}))
+
+	req, _ := http.NewRequest("GET", "http://example.invalid", nil)
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 {
+		t.Errorf("expected cancelled request not to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestRetryTransportRetriesConnectionReset(t *testing.T) {
+	attempts := 0
+	transport := NewRetryTransport(RetryConfig{MaxRetries: 2, InitialBackoff: time.Millisecond},
+		roundTripFunc(func(*http.Request) (*http.Response, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}))
+
+	req, _ := http.NewRequest("GET", "http://example.invalid", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("expected success after resets, got %v", err)
+	}
+	resp.Body.Close()
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryTransportRetriesAttemptTimeout(t *testing.T) {
+	attempts := 0
+	transport := NewRetryTransport(RetryConfig{MaxRetries: 1, InitialBackoff: time.Millisecond},
+		roundTripFunc(func(*http.Request) (*http.Response, error) {
+			attempts++
+			if attempts == 1 {
+				// A TLS handshake or response header timeout of the transport
+				return nil, fmt.Errorf("net/http: TLS handshake timeout: %w", context.DeadlineExceeded)
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}))
+
+	req, _ := http.NewRequest("GET", "http://example.invalid", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("expected success after a timed out attempt, got %v", err)
+	}
+	resp.Body.Close()
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"cancelled", context.Canceled, false},
+		{"attempt deadline", context.DeadlineExceeded, true},
+		{"attempt timeout", &net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}, true},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"connection reset", &net.OpError{Op: "read", Err: syscall.ECONNRESET}, true},
+		{"connection refused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{"unknown host", &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}, false},
+		{"dns timeout", &net.DNSError{Err: "timeout", IsTimeout: true}, true},
+		{"other", fmt.Errorf("unsupported protocol scheme"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(context.Background(), tt.err); got != tt.want {
+				t.Errorf("isRetryableError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	// Once the caller's context is done, even transient errors are final
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	for _, err := range []error{context.DeadlineExceeded, io.ErrUnexpectedEOF} {
+		if isRetryableError(expired, err) {
+			t.Errorf("isRetryableError(expired, %v) = true, want false", err)
+		}
+	}
+}
+
+func TestNextBackoffSmallDurationWithJitter(t *testing.T) {
+	cfg := RetryConfig{MaxBackoff: time.Second, JitterFraction: 0.1}
+	// A jitter range that rounds to zero must not panic
+	if got := nextBackoff(cfg, 1); got != 2 {
+		t.Errorf("expected 2ns, got %v", got)
+	}
+}
+
+func TestDefaultRetryConfigRetriesServerErrors(t *testing.T) {
+	transport := NewRetryTransport(DefaultRetryConfig(), http.DefaultTransport)
+	for _, code := range []int{429, 500, 502, 503, 504} {
+		if !transport.shouldRetry(code) {
+			t.Errorf("expected %d to be retryable by default", code)
+		}
+	}
+	if transport.shouldRetry(http.StatusBadRequest) {
+		t.Errorf("expected 400 to not be retryable")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
 func BenchmarkRetryerSuccess(b *testing.B) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
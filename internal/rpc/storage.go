@@ -0,0 +1,67 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// ContractDataEntry is the live state of one contract storage entry
+type ContractDataEntry struct {
+	LedgerKey    string // base64 LedgerKey
+	Durability   xdr.ContractDataDurability
+	Key          xdr.ScVal
+	Val          xdr.ScVal
+	LastModified uint32
+	LiveUntil    uint32
+}
+
+// LedgerKeyForContractData builds the LedgerKey of a contract storage entry
+func LedgerKeyForContractData(contractID xdr.ContractId, key xdr.ScVal, durability xdr.ContractDataDurability) xdr.LedgerKey {
+	return xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+			Key:        key,
+			Durability: durability,
+		},
+	}
+}
+
+// GetContractData fetches contract storage entries by ledger key along with
+// their TTL. Unlike GetLedgerEntries it always asks the RPC node, so repeated
+// calls observe live changes. Keys without an entry are left out, and keys
+// that are not contract data are ignored.
+func (c *Client) GetContractData(ctx context.Context, keys []string) ([]ContractDataEntry, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	resp, _, err := c.queryLedgerEntries(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]ContractDataEntry, 0, len(resp.Result.Entries))
+	for _, e := range resp.Result.Entries {
+		var data xdr.LedgerEntryData
+		if err := xdr.SafeUnmarshalBase64(e.Xdr, &data); err != nil {
+			return nil, errors.WrapUnmarshalFailed(err, "ledger entry data")
+		}
+		if data.ContractData == nil {
+			continue
+		}
+		entries = append(entries, ContractDataEntry{
+			LedgerKey:    e.Key,
+			Durability:   data.ContractData.Durability,
+			Key:          data.ContractData.Key,
+			Val:          data.ContractData.Val,
+			LastModified: uint32(e.LastModifiedLedger),
+			LiveUntil:    uint32(e.LiveUntilLedger),
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetContractData(t *testing.T) {
+	entry, key := counterEntry(t, 1, 7, 90)
+	dataXdr, err := xdr.MarshalBase64(entry.Data)
+	require.NoError(t, err)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		result := map[string]interface{}{"entries": []map[string]interface{}{
+			{"key": key, "xdr": dataXdr, "lastModifiedLedgerSeq": 90, "liveUntilLedgerSeq": 5000},
+		}}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	defer server.Close()
+
+	client := &Client{HorizonURL: server.URL, SorobanURL: server.URL, AltURLs: []string{server.URL}, CacheEnabled: true}
+	for i := 0; i < 2; i++ {
+		entries, err := client.GetContractData(context.Background(), []string{key})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, key, entries[0].LedgerKey)
+		assert.Equal(t, xdr.Uint32(7), *entries[0].Val.U32)
+		assert.Equal(t, uint32(90), entries[0].LastModified)
+		assert.Equal(t, uint32(5000), entries[0].LiveUntil)
+	}
+	assert.Equal(t, 2, requests, "contract data is never served from the cache")
+
+	key2, err := EncodeLedgerKey(LedgerKeyForContractData(xdr.ContractId{1}, xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}, xdr.ContractDataDurabilityPersistent))
+	require.NoError(t, err)
+	assert.Equal(t, key, key2)
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Submission statuses reported by Soroban RPC sendTransaction
+const (
+	SendStatusPending       = "PENDING"
+	SendStatusDuplicate     = "DUPLICATE"
+	SendStatusTryAgainLater = "TRY_AGAIN_LATER"
+	SendStatusError         = "ERROR"
+)
+
+type SendTransactionRequest struct {
+	Jsonrpc string                `json:"jsonrpc"`
+	ID      int                   `json:"id"`
+	Method  string                `json:"method"`
+	Params  SendTransactionParams `json:"params"`
+}
+
+type SendTransactionParams struct {
+	Transaction string `json:"transaction"`
+}
+
+// SendTransactionResult is the outcome of handing a transaction to the RPC
+// node; it is not the result of applying it
+type SendTransactionResult struct {
+	Status       string `json:"status"`
+	Hash         string `json:"hash"`
+	LatestLedger uint32 `json:"latestLedger"`
+	// ErrorResultXdr is a TransactionResult explaining an ERROR status
+	ErrorResultXdr      string   `json:"errorResultXdr,omitempty"`
+	DiagnosticEventsXdr []string `json:"diagnosticEventsXdr,omitempty"`
+}
+
+type SendTransactionResponse struct {
+	Jsonrpc string                `json:"jsonrpc"`
+	ID      int                   `json:"id"`
+	Result  SendTransactionResult `json:"result"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// SendTransaction submits a signed base64 TransactionEnvelope with Soroban
+// RPC sendTransaction. It is not retried on other endpoints; a transaction
+// rejected with TRY_AGAIN_LATER can be sent again by the caller.
+func (c *Client) SendTransaction(ctx context.Context, envelopeXdr string) (*SendTransactionResult, error) {
+	logger.Logger.Debug("Submitting transaction", "url", c.SorobanURL)
+
+	reqBody := SendTransactionRequest{
+		Jsonrpc: "2.0",
+		ID:      1,
+		Method:  "sendTransaction",
+		Params:  SendTransactionParams{Transaction: envelopeXdr},
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, errors.WrapMarshalFailed(err)
+	}
+
+	targetURL := c.SorobanURL
+	req, err := http.NewRequestWithContext(ctx, "POST", targetURL, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.getHTTPClient().Do(req)
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "body read error")
+	}
+
+	var rpcResp SendTransactionResponse
+	if err := json.Unmarshal(respBytes, &rpcResp); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, string(respBytes))
+	}
+
+	if rpcResp.Error != nil {
+		return nil, errors.WrapRPCError(targetURL, rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+
+	logger.Logger.Info("Transaction submitted", "hash", rpcResp.Result.Hash, "status", rpcResp.Result.Status)
+	return &rpcResp.Result, nil
+}
+
+// WaitForTransaction polls getTransaction every interval until the
+// transaction is included in a ledger or ctx is done
+func (c *Client) WaitForTransaction(ctx context.Context, hash string, interval time.Duration) (*TransactionResponse, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		tx, err := c.getTransactionFromRPC(ctx, hash)
+		if err == nil {
+			return tx, nil
+		}
+		if !errors.Is(err, errors.ErrTransactionNotFound) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("transaction %s was not included: %w", hash, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetAccountSequence returns the current sequence number of an account. The
+// entry is always read from RPC and never written to the ledger entry cache,
+// since a cached sequence number is stale once the account submits a
+// transaction.
+func (c *Client) GetAccountSequence(ctx context.Context, accountID xdr.AccountId) (int64, error) {
+	key, err := EncodeLedgerKey(xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: accountID},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	resp, _, err := c.queryLedgerEntries(ctx, []string{key})
+	if err != nil {
+		return 0, err
+	}
+	var raw string
+	for _, entry := range resp.Result.Entries {
+		if entry.Key == key {
+			raw = entry.Xdr
+		}
+	}
+	if raw == "" {
+		return 0, fmt.Errorf("account %s not found", accountID.Address())
+	}
+	if err := VerifyLedgerEntries([]string{key}, map[string]string{key: raw}); err != nil {
+		return 0, fmt.Errorf("ledger entry verification failed: %w", err)
+	}
+
+	var data xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(raw, &data); err != nil {
+		return 0, errors.WrapUnmarshalFailed(err, "LedgerEntryData")
+	}
+	if data.Account == nil {
+		return 0, fmt.Errorf("ledger entry for %s is not an account", accountID.Address())
+	}
+	return int64(data.Account.SeqNum), nil
+}
+
+// AccountExists reports whether an account has been created. Unlike
+// GetLedgerEntries, a missing entry is an answer rather than an error.
+func (c *Client) AccountExists(ctx context.Context, accountID xdr.AccountId) (bool, error) {
+	key, err := EncodeLedgerKey(xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: accountID},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	resp, _, err := c.queryLedgerEntries(ctx, []string{key})
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range resp.Result.Entries {
+		if entry.Key == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
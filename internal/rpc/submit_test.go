@@ -0,0 +1,128 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendTransaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SendTransactionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "sendTransaction", req.Method)
+		assert.Equal(t, "AAAA", req.Params.Transaction)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": map[string]interface{}{
+			"status": SendStatusPending, "hash": "abc", "latestLedger": 7,
+		}})
+	}))
+	defer server.Close()
+
+	c := &Client{SorobanURL: server.URL}
+	res, err := c.SendTransaction(context.Background(), "AAAA")
+	require.NoError(t, err)
+	assert.Equal(t, SendStatusPending, res.Status)
+	assert.Equal(t, "abc", res.Hash)
+}
+
+func TestWaitForTransaction(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		result := map[string]interface{}{"status": TxStatusNotFound}
+		if calls == 3 {
+			result = map[string]interface{}{"status": TxStatusSuccess, "ledger": 9, "envelopeXdr": "env", "resultXdr": "res"}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	defer server.Close()
+
+	c := &Client{SorobanURL: server.URL}
+	tx, err := c.WaitForTransaction(context.Background(), "abc", time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(9), tx.Ledger)
+	assert.Equal(t, 3, calls)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	calls = -100
+	_, err = c.WaitForTransaction(ctx, "abc", time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestGetAccountSequence(t *testing.T) {
+	kp := keypair.MustRandom()
+	accountID := xdr.MustAddress(kp.Address())
+	entry, err := xdr.MarshalBase64(xdr.LedgerEntryData{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{AccountId: accountID, SeqNum: 1234},
+	})
+	require.NoError(t, err)
+	key, err := EncodeLedgerKey(xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: accountID}})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": map[string]interface{}{
+			"entries": []map[string]interface{}{{"key": key, "xdr": entry}},
+		}})
+	}))
+	defer server.Close()
+
+	// A stale entry in the cache is neither read nor replaced
+	t.Setenv("HOME", t.TempDir())
+	stale, err := xdr.MarshalBase64(xdr.LedgerEntryData{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{AccountId: accountID, SeqNum: 1000},
+	})
+	require.NoError(t, err)
+	require.NoError(t, Set(key, stale))
+
+	c := &Client{HorizonURL: server.URL, SorobanURL: server.URL, AltURLs: []string{server.URL}, CacheEnabled: true}
+	seq, err := c.GetAccountSequence(context.Background(), accountID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234), seq)
+
+	cached, ok, err := Get(key)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, stale, cached)
+}
+
+func TestAccountExists(t *testing.T) {
+	existing := xdr.MustAddress(keypair.MustRandom().Address())
+	key, err := EncodeLedgerKey(xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: existing}})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params [][]string `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		entries := []map[string]interface{}{}
+		if req.Params[0][0] == key {
+			entries = append(entries, map[string]interface{}{"key": key, "xdr": "AAAA"})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": map[string]interface{}{"entries": entries}})
+	}))
+	defer server.Close()
+
+	c := &Client{HorizonURL: server.URL, SorobanURL: server.URL, AltURLs: []string{server.URL}}
+	ok, err := c.AccountExists(context.Background(), existing)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = c.AccountExists(context.Background(), xdr.MustAddress(keypair.MustRandom().Address()))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,249 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+)
+
+// Transaction statuses reported by Soroban RPC getTransaction(s)
+const (
+	TxStatusSuccess  = "SUCCESS"
+	TxStatusFailed   = "FAILED"
+	TxStatusNotFound = "NOT_FOUND"
+)
+
+type GetTransactionRequest struct {
+	Jsonrpc string               `json:"jsonrpc"`
+	ID      int                  `json:"id"`
+	Method  string               `json:"method"`
+	Params  GetTransactionParams `json:"params"`
+}
+
+type GetTransactionParams struct {
+	Hash string `json:"hash"`
+}
+
+type GetTransactionResponse struct {
+	Jsonrpc string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Result  struct {
+		Status        string `json:"status"`
+		LatestLedger  uint32 `json:"latestLedger"`
+		OldestLedger  uint32 `json:"oldestLedger"`
+		Ledger        uint32 `json:"ledger"`
+		EnvelopeXdr   string `json:"envelopeXdr"`
+		ResultXdr     string `json:"resultXdr"`
+		ResultMetaXdr string `json:"resultMetaXdr"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type GetTransactionsRequest struct {
+	Jsonrpc string                `json:"jsonrpc"`
+	ID      int                   `json:"id"`
+	Method  string                `json:"method"`
+	Params  GetTransactionsParams `json:"params"`
+}
+
+// GetTransactionsParams selects a page of transactions. StartLedger is
+// ignored by the RPC server when a pagination cursor is supplied.
+type GetTransactionsParams struct {
+	StartLedger uint32                 `json:"startLedger,omitempty"`
+	Pagination  *TransactionPagination `json:"pagination,omitempty"`
+}
+
+type TransactionPagination struct {
+	Cursor string `json:"cursor,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// LedgerTransaction is a single transaction as returned by getTransactions
+type LedgerTransaction struct {
+	Status           string `json:"status"`
+	TxHash           string `json:"txHash"`
+	ApplicationOrder int    `json:"applicationOrder"`
+	FeeBump          bool   `json:"feeBump"`
+	EnvelopeXdr      string `json:"envelopeXdr"`
+	ResultXdr        string `json:"resultXdr"`
+	ResultMetaXdr    string `json:"resultMetaXdr"`
+	Ledger           uint32 `json:"ledger"`
+	CreatedAt        int64  `json:"createdAt"`
+}
+
+type GetTransactionsResponse struct {
+	Jsonrpc string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Result  struct {
+		Transactions          []LedgerTransaction `json:"transactions"`
+		LatestLedger          uint32              `json:"latestLedger"`
+		LatestLedgerCloseTime int64               `json:"latestLedgerCloseTimestamp"`
+		OldestLedger          uint32              `json:"oldestLedger"`
+		Cursor                string              `json:"cursor"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// GetTransactions fetches a page of transactions from Soroban RPC, starting
+// either at startLedger or, when cursor is non-empty, after a previous page.
+func (c *Client) GetTransactions(ctx context.Context, startLedger uint32, cursor string, limit int) (*GetTransactionsResponse, error) {
+	logger.Logger.Debug("Fetching transactions", "start_ledger", startLedger, "cursor", cursor, "url", c.SorobanURL)
+
+	params := GetTransactionsParams{
+		Pagination: &TransactionPagination{Cursor: cursor, Limit: limit},
+	}
+	if cursor == "" {
+		params.StartLedger = startLedger
+	}
+
+	reqBody := GetTransactionsRequest{
+		Jsonrpc: "2.0",
+		ID:      1,
+		Method:  "getTransactions",
+		Params:  params,
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, errors.WrapMarshalFailed(err)
+	}
+
+	targetURL := c.SorobanURL
+	req, err := http.NewRequestWithContext(ctx, "POST", targetURL, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.getHTTPClient().Do(req)
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		return nil, errors.WrapRPCResponseTooLarge(targetURL)
+	}
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "body read error")
+	}
+
+	var rpcResp GetTransactionsResponse
+	if err := json.Unmarshal(respBytes, &rpcResp); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, string(respBytes))
+	}
+
+	if rpcResp.Error != nil {
+		return nil, errors.WrapRPCError(targetURL, rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+
+	return &rpcResp, nil
+}
+
+// getTransactionFromRPC looks the transaction up with Soroban RPC getTransaction.
+// RPC nodes only retain a window of recent ledgers, so a NOT_FOUND status is
+// reported as ErrTransactionNotFound for the caller to try older sources.
+func (c *Client) getTransactionFromRPC(ctx context.Context, hash string) (*TransactionResponse, error) {
+	logger.Logger.Debug("Fetching transaction from Soroban RPC", "hash", hash, "url", c.SorobanURL)
+
+	reqBody := GetTransactionRequest{
+		Jsonrpc: "2.0",
+		ID:      1,
+		Method:  "getTransaction",
+		Params:  GetTransactionParams{Hash: hash},
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, errors.WrapMarshalFailed(err)
+	}
+
+	targetURL := c.SorobanURL
+	req, err := http.NewRequestWithContext(ctx, "POST", targetURL, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.getHTTPClient().Do(req)
+	if err != nil {
+		return nil, errors.WrapRPCConnectionFailed(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		return nil, errors.WrapRPCResponseTooLarge(targetURL)
+	}
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, "body read error")
+	}
+
+	var rpcResp GetTransactionResponse
+	if err := json.Unmarshal(respBytes, &rpcResp); err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, string(respBytes))
+	}
+
+	if rpcResp.Error != nil {
+		return nil, errors.WrapRPCError(targetURL, rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+
+	result := rpcResp.Result
+	if result.Status == TxStatusNotFound || result.EnvelopeXdr == "" {
+		return nil, errors.WrapTransactionNotFound(fmt.Errorf("%s is not in Soroban RPC history (ledgers %d-%d)",
+			hash, result.OldestLedger, result.LatestLedger))
+	}
+
+	logger.Logger.Info("Transaction fetched", "hash", hash, "envelope_size", len(result.EnvelopeXdr), "url", targetURL)
+
+	return &TransactionResponse{
+		EnvelopeXdr:   result.EnvelopeXdr,
+		ResultXdr:     result.ResultXdr,
+		ResultMetaXdr: result.ResultMetaXdr,
+		Ledger:        result.Ledger,
+	}, nil
+}
+
+// getTransactionFromArchives tries each archive URL, which must serve the
+// Horizon transactions endpoint over full history
+func (c *Client) getTransactionFromArchives(ctx context.Context, hash string) (*TransactionResponse, error) {
+	var failures []NodeFailure
+	for _, url := range c.ArchiveURLs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		archive := &horizonclient.Client{HorizonURL: url, HTTP: c.getHTTPClient()}
+		tx, err := archive.TransactionDetail(hash)
+		if err != nil {
+			if horizonclient.IsNotFoundError(err) {
+				err = errors.WrapTransactionNotFound(err)
+			}
+			failures = append(failures, NodeFailure{URL: url, Reason: err})
+			continue
+		}
+
+		logger.Logger.Info("Transaction recovered from archive", "hash", hash, "url", url)
+		return ParseTransactionResponse(tx), nil
+	}
+	return nil, &AllNodesFailedError{Failures: failures}
+}
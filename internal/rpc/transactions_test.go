@@ -0,0 +1,133 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+	"github.com/stellar/go-stellar-sdk/support/render/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sorobanGetTransactionServer(t *testing.T, result map[string]interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GetTransactionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "getTransaction", req.Method)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func horizonNotFound() error {
+	return &horizonclient.Error{Problem: problem.P{Type: "https://stellar.org/horizon-errors/not_found", Status: 404}}
+}
+
+func TestGetTransaction_FromSorobanRPC(t *testing.T) {
+	server := sorobanGetTransactionServer(t, map[string]interface{}{
+		"status":        TxStatusFailed,
+		"ledger":        1234,
+		"envelopeXdr":   "env",
+		"resultXdr":     "res",
+		"resultMetaXdr": "meta",
+	})
+
+	c := &Client{
+		Horizon: &mockHorizonClient{TransactionDetailFunc: func(string) (hProtocol.Transaction, error) {
+			t.Fatal("Horizon should not be queried when Soroban RPC has the transaction")
+			return hProtocol.Transaction{}, nil
+		}},
+		HorizonURL: "https://horizon.example",
+		AltURLs:    []string{"https://horizon.example"},
+		SorobanURL: server.URL,
+	}
+
+	resp, err := c.GetTransaction(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "env", resp.EnvelopeXdr)
+	assert.Equal(t, "meta", resp.ResultMetaXdr)
+	assert.Equal(t, uint32(1234), resp.Ledger)
+}
+
+func TestGetTransaction_FallsBackToHorizonWhenPruned(t *testing.T) {
+	server := sorobanGetTransactionServer(t, map[string]interface{}{
+		"status":       TxStatusNotFound,
+		"oldestLedger": 5000,
+		"latestLedger": 6000,
+	})
+
+	c := &Client{
+		Horizon: &mockHorizonClient{TransactionDetailFunc: func(hash string) (hProtocol.Transaction, error) {
+			return hProtocol.Transaction{EnvelopeXdr: "old-env", ResultMetaXdr: "old-meta", Ledger: 10}, nil
+		}},
+		HorizonURL: "https://horizon.example",
+		AltURLs:    []string{"https://horizon.example"},
+		SorobanURL: server.URL,
+	}
+
+	resp, err := c.GetTransaction(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "old-env", resp.EnvelopeXdr)
+	assert.Equal(t, uint32(10), resp.Ledger)
+}
+
+func TestGetTransaction_FallsBackToArchive(t *testing.T) {
+	rpcServer := sorobanGetTransactionServer(t, map[string]interface{}{"status": TxStatusNotFound})
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/transactions/abc", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"hash":            "abc",
+			"ledger":          7,
+			"envelope_xdr":    "archived-env",
+			"result_xdr":      "archived-res",
+			"result_meta_xdr": "archived-meta",
+		})
+	}))
+	defer archive.Close()
+
+	c := &Client{
+		Horizon: &mockHorizonClient{TransactionDetailFunc: func(string) (hProtocol.Transaction, error) {
+			return hProtocol.Transaction{}, horizonNotFound()
+		}},
+		HorizonURL:  "https://horizon.example",
+		AltURLs:     []string{"https://horizon.example"},
+		ArchiveURLs: []string{archive.URL},
+		SorobanURL:  rpcServer.URL,
+	}
+
+	resp, err := c.GetTransaction(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "archived-env", resp.EnvelopeXdr)
+	assert.Equal(t, "archived-meta", resp.ResultMetaXdr)
+	assert.Equal(t, uint32(7), resp.Ledger)
+}
+
+func TestGetTransaction_NotFoundEverywhere(t *testing.T) {
+	rpcServer := sorobanGetTransactionServer(t, map[string]interface{}{"status": TxStatusNotFound})
+
+	c := &Client{
+		Horizon: &mockHorizonClient{TransactionDetailFunc: func(string) (hProtocol.Transaction, error) {
+			return hProtocol.Transaction{}, horizonNotFound()
+		}},
+		HorizonURL: "https://horizon.example",
+		AltURLs:    []string{"https://horizon.example"},
+		SorobanURL: rpcServer.URL,
+	}
+
+	_, err := c.GetTransaction(context.Background(), "abc")
+	require.Error(t, err)
+	assert.True(t, IsTransactionNotFound(err))
+	// A "not found" answer means the endpoint is up
+	assert.True(t, c.isHealthy("https://horizon.example"))
+	assert.Zero(t, c.failures["https://horizon.example"])
+}
@@ -6,6 +6,7 @@ package rpc
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/dotandev/hintents/internal/errors"
 )
@@ -35,6 +36,32 @@ func isValidURL(urlStr string) error {
 	return nil
 }
 
+// ParseHeader splits a "Name: value" header specification
+func ParseHeader(raw string) (string, string, error) {
+	name, value, ok := strings.Cut(raw, ":")
+	if !ok {
+		return "", "", errors.WrapValidationError(fmt.Sprintf("invalid header %q, expected \"Name: value\"", raw))
+	}
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if err := validateHeader(name, value); err != nil {
+		return "", "", err
+	}
+	return name, value, nil
+}
+
+func validateHeader(name, value string) error {
+	if name == "" {
+		return errors.WrapValidationError("header name cannot be empty")
+	}
+	if strings.ContainsAny(name, " \t\r\n:") {
+		return errors.WrapValidationError(fmt.Sprintf("invalid header name %q", name))
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return errors.WrapValidationError(fmt.Sprintf("header %s contains a line break", name))
+	}
+	return nil
+}
+
 func ValidateNetworkConfig(config NetworkConfig) error {
 	if config.Name == "" {
 		return errors.WrapValidationError("network name is required")
@@ -169,3 +169,19 @@ func BenchmarkIsValidURL(b *testing.B) {
 		isValidURL(url)
 	}
 }
+
+func TestParseHeader(t *testing.T) {
+	name, value, err := ParseHeader("X-Api-Key:  abc:def ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if name != "X-Api-Key" || value != "abc:def" {
+		t.Errorf("unexpected header %q: %q", name, value)
+	}
+
+	for _, raw := range []string{"no-colon", ": value", "Bad Name: v"} {
+		if _, _, err := ParseHeader(raw); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+}
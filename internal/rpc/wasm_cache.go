@@ -0,0 +1,131 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// WasmCacheDirName is the directory under ~/.erst/cache holding contract code
+const WasmCacheDirName = "wasm"
+
+// WasmCache is a content-addressed store of contract code entries keyed by the
+// SHA-256 of their WASM. Code for a given hash never changes, so entries do not
+// expire; they are evicted by `erst cache clean` like any other cached file.
+//
+// The whole ContractCode LedgerEntry is stored rather than the bare WASM so that
+// cost inputs in the entry extension survive a cache round-trip.
+type WasmCache struct {
+	dir string
+}
+
+// NewWasmCache returns a cache rooted at dir
+func NewWasmCache(dir string) *WasmCache {
+	return &WasmCache{dir: dir}
+}
+
+// DefaultWasmCache returns the cache at ~/.erst/cache/wasm
+func DefaultWasmCache() (*WasmCache, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to get user home directory: %v", err))
+	}
+	return NewWasmCache(filepath.Join(home, CacheDirName, "cache", WasmCacheDirName)), nil
+}
+
+// Dir returns the directory backing the cache
+func (w *WasmCache) Dir() string {
+	return w.dir
+}
+
+func (w *WasmCache) path(hash xdr.Hash) string {
+	return filepath.Join(w.dir, hex.EncodeToString(hash[:])+".xdr")
+}
+
+// Get returns the base64 ContractCode LedgerEntry for hash. Entries whose code
+// no longer matches their hash are treated as misses and removed.
+func (w *WasmCache) Get(hash xdr.Hash) (string, bool, error) {
+	path := w.path(hash)
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	var entry xdr.LedgerEntry
+	if err := entry.UnmarshalBinary(raw); err != nil || entry.Data.ContractCode == nil ||
+		xdr.Hash(sha256.Sum256(entry.Data.ContractCode.Code)) != hash {
+		os.Remove(path)
+		return "", false, nil
+	}
+
+	// Cache cleanup is LRU by modification time, so mark the entry as used
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	encoded, err := xdr.MarshalBase64(entry)
+	if err != nil {
+		return "", false, err
+	}
+	return encoded, true, nil
+}
+
+// Put stores a base64 ContractCode LedgerEntry under the hash of its WASM and
+// returns that hash
+func (w *WasmCache) Put(entryXDR string) (xdr.Hash, error) {
+	var entry xdr.LedgerEntry
+	if err := xdr.SafeUnmarshalBase64(entryXDR, &entry); err != nil {
+		return xdr.Hash{}, err
+	}
+	if entry.Data.ContractCode == nil {
+		return xdr.Hash{}, fmt.Errorf("ledger entry is %s, not contract code", entry.Data.Type)
+	}
+	hash := xdr.Hash(sha256.Sum256(entry.Data.ContractCode.Code))
+
+	path := w.path(hash)
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+
+	raw, err := entry.MarshalBinary()
+	if err != nil {
+		return xdr.Hash{}, err
+	}
+	if err := os.MkdirAll(w.dir, DirPerm); err != nil {
+		return xdr.Hash{}, err
+	}
+	tmp, err := os.CreateTemp(w.dir, ".tmp-*")
+	if err != nil {
+		return xdr.Hash{}, err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return xdr.Hash{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return xdr.Hash{}, err
+	}
+	return hash, os.Rename(tmp.Name(), path)
+}
+
+// contractCodeHash returns the code hash of a base64 ContractCode LedgerKey
+func contractCodeHash(keyB64 string) (xdr.Hash, bool) {
+	var key xdr.LedgerKey
+	if err := xdr.SafeUnmarshalBase64(keyB64, &key); err != nil || key.ContractCode == nil {
+		return xdr.Hash{}, false
+	}
+	return key.ContractCode.Hash, true
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testContractCodeEntry(t *testing.T, code []byte) (keyB64, entryB64 string, hash xdr.Hash) {
+	t.Helper()
+	hash = xdr.Hash(sha256.Sum256(code))
+
+	key := xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: hash},
+	}
+	keyB64, err := EncodeLedgerKey(key)
+	require.NoError(t, err)
+
+	entry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 42,
+		Data: xdr.LedgerEntryData{
+			Type:         xdr.LedgerEntryTypeContractCode,
+			ContractCode: &xdr.ContractCodeEntry{Hash: hash, Code: code},
+		},
+	}
+	entryB64, err = xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+	return keyB64, entryB64, hash
+}
+
+func TestWasmCache_PutGet(t *testing.T) {
+	cache := NewWasmCache(t.TempDir())
+	_, entryB64, hash := testContractCodeEntry(t, []byte("\x00asm\x01\x00\x00\x00"))
+
+	_, hit, err := cache.Get(hash)
+	require.NoError(t, err)
+	assert.False(t, hit)
+
+	stored, err := cache.Put(entryB64)
+	require.NoError(t, err)
+	assert.Equal(t, hash, stored)
+
+	got, hit, err := cache.Get(hash)
+	require.NoError(t, err)
+	assert.True(t, hit)
+	assert.Equal(t, entryB64, got)
+}
+
+func TestWasmCache_RejectsNonCodeEntry(t *testing.T) {
+	cache := NewWasmCache(t.TempDir())
+	entry := xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type:    xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{AccountId: xdr.MustAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7")},
+		},
+	}
+	entryB64, err := xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+
+	_, err = cache.Put(entryB64)
+	assert.Error(t, err)
+}
+
+func TestWasmCache_DropsTamperedEntry(t *testing.T) {
+	cache := NewWasmCache(t.TempDir())
+	_, entryB64, hash := testContractCodeEntry(t, []byte("original"))
+	_, otherB64, _ := testContractCodeEntry(t, []byte("tampered"))
+
+	_, err := cache.Put(entryB64)
+	require.NoError(t, err)
+
+	var other xdr.LedgerEntry
+	require.NoError(t, xdr.SafeUnmarshalBase64(otherB64, &other))
+	raw, err := other.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cache.path(hash), raw, 0600))
+
+	_, hit, err := cache.Get(hash)
+	require.NoError(t, err)
+	assert.False(t, hit)
+	_, err = os.Stat(cache.path(hash))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGetLedgerEntries_UsesWasmCache(t *testing.T) {
+	setupTestCacheDB(t)
+	keyB64, entryB64, _ := testContractCodeEntry(t, []byte("\x00asm contract"))
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result": map[string]interface{}{
+				"entries": []map[string]interface{}{{"key": keyB64, "xdr": entryB64}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	wasmCache := NewWasmCache(t.TempDir())
+	newClient := func() *Client {
+		c, err := NewClient(WithNetwork(Testnet), WithHorizonURL(server.URL), WithWasmCache(wasmCache))
+		require.NoError(t, err)
+		return c
+	}
+
+	entries, err := newClient().GetLedgerEntries(context.Background(), []string{keyB64})
+	require.NoError(t, err)
+	assert.Equal(t, entryB64, entries[keyB64])
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// A fresh ledger entry cache, as after its TTL expires, still finds the code
+	setupTestCacheDB(t)
+	entries, err = newClient().GetLedgerEntries(context.Background(), []string{keyB64})
+	require.NoError(t, err)
+	assert.Equal(t, entryB64, entries[keyB64])
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
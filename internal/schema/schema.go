@@ -0,0 +1,231 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schema derives JSON Schema definitions from the Go types erst
+// encodes its structured output with, and validates documents against them.
+package schema
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Draft is the JSON Schema version of generated schemas
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// BaseID prefixes the $id of every published schema
+const BaseID = "https://github.com/dotandev/hintents/schemas/"
+
+// FileExtension is appended to a schema's name to form its file name
+const FileExtension = ".schema.json"
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Generate returns the schema, named name, of the JSON encoding of values
+// with v's type. Named structs become definitions, so recursive types are
+// described once. Objects accept properties they do not list, so that
+// consumers keep working when a later erst adds fields.
+func Generate(name, description string, v interface{}) ([]byte, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("schema %s: no type given", name)
+	}
+	g := &generator{definitions: map[string]interface{}{}}
+	root := g.schemaFor(t)
+
+	doc := map[string]interface{}{
+		"$schema":     Draft,
+		"$id":         BaseID + name + FileExtension,
+		"title":       name,
+		"description": description,
+		// $ref siblings are ignored in draft-07, so the root refers to its
+		// definition through allOf
+		"allOf": []interface{}{root},
+	}
+	if len(g.definitions) > 0 {
+		doc["definitions"] = g.definitions
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("schema %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// generator collects the definitions of the named structs it meets
+type generator struct {
+	definitions map[string]interface{}
+}
+
+// schemaFor describes the JSON encoding of t
+func (g *generator) schemaFor(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]interface{}{}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		// Encoded by its own method, which the schema cannot see into
+		return map[string]interface{}{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Pointer:
+		return g.schemaFor(t.Elem())
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as base64
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.field(t.Elem(), false)}
+	case reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.field(t.Elem(), false), "minItems": t.Len(), "maxItems": t.Len()}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.field(t.Elem(), false)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		if _, ok := g.definitions[name]; !ok {
+			// Reserve the name first, so recursive fields refer to it
+			g.definitions[name] = nil
+			g.definitions[name] = g.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/definitions/" + name}
+	default:
+		// Interfaces hold any value
+		return map[string]interface{}{}
+	}
+}
+
+// field describes a value of type t in a struct field, array or map, which
+// encoding/json writes as null when it is a nil pointer, slice, map or
+// interface, unless omitted
+func (g *generator) field(t reflect.Type, omitted bool) map[string]interface{} {
+	s := g.schemaFor(t)
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+	default:
+		return s
+	}
+	if omitted || t.Kind() == reflect.Interface {
+		return s
+	}
+	if typ, ok := s["type"].(string); ok {
+		s["type"] = []string{typ, "null"}
+		return s
+	}
+	return map[string]interface{}{"anyOf": []interface{}{s, map[string]interface{}{"type": "null"}}}
+}
+
+// object describes a struct by its encoded fields. Fields without
+// omitempty are always present and therefore required.
+func (g *generator) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	g.addFields(t, properties, &required, true)
+
+	s := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// addFields adds the encoded fields of struct t, promoting those of embedded
+// structs as encoding/json does. Fields of embedded pointers are only
+// present when the pointer is set, so they are never required.
+func (g *generator) addFields(t reflect.Type, properties map[string]interface{}, required *[]string, present bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			embedded := ft
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(embedded, properties, required, present && ft.Kind() != reflect.Pointer)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		omitempty := hasOption(opts, "omitempty")
+		s := g.field(ft, omitempty)
+		if hasOption(opts, "string") {
+			// ,string quotes numbers and booleans
+			s = map[string]interface{}{"type": "string"}
+		}
+		properties[name] = s
+		if !omitempty && present {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks doc against schema and returns every violation found
+func Validate(schema, doc []byte) error {
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewBytesLoader(doc))
+	if err != nil {
+		return fmt.Errorf("failed to validate: %w", err)
+	}
+	if result.Valid() {
+		return nil
+	}
+	var problems []string
+	for _, e := range result.Errors() {
+		problems = append(problems, e.String())
+	}
+	return fmt.Errorf("document does not match the schema:\n  %s", strings.Join(problems, "\n  "))
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type node struct {
+	Name     string  `json:"name"`
+	Children []*node `json:"children,omitempty"`
+}
+
+type base struct {
+	Version int `json:"version"`
+}
+
+type output struct {
+	base
+	ID       string            `json:"id"`
+	Count    uint32            `json:"count,omitempty"`
+	Ratio    float64           `json:"ratio"`
+	When     time.Time         `json:"when"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Root     *node             `json:"root"`
+	Extra    interface{}       `json:"extra,omitempty"`
+	Big      int64             `json:"big,string"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func generate(t *testing.T) []byte {
+	t.Helper()
+	s, err := Generate("output", "Test output", output{})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	return s
+}
+
+func TestGenerate(t *testing.T) {
+	var doc struct {
+		Schema      string                     `json:"$schema"`
+		ID          string                     `json:"$id"`
+		Definitions map[string]json.RawMessage `json:"definitions"`
+	}
+	if err := json.Unmarshal(generate(t), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Schema != Draft || doc.ID != BaseID+"output.schema.json" {
+		t.Errorf("$schema = %q, $id = %q", doc.Schema, doc.ID)
+	}
+
+	var out struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Required   []string                   `json:"required"`
+	}
+	if err := json.Unmarshal(doc.Definitions["schema.output"], &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(out.Required, ","); got != "version,id,ratio,when,tags,root,big" {
+		t.Errorf("required = %s", got)
+	}
+	for _, name := range []string{"Ignored", "internal", "-"} {
+		if _, ok := out.Properties[name]; ok {
+			t.Errorf("unexpected property %s", name)
+		}
+	}
+	want := map[string]string{
+		"count": `{"minimum":0,"type":"integer"}`,
+		"when":  `{"format":"date-time","type":"string"}`,
+		"tags":  `{"items":{"type":"string"},"type":["array","null"]}`,
+		"root":  `{"anyOf":[{"$ref":"#/definitions/schema.node"},{"type":"null"}]}`,
+		"big":   `{"type":"string"}`,
+		"extra": `{}`,
+	}
+	for name, schema := range want {
+		var compact strings.Builder
+		if err := json.NewEncoder(&compact).Encode(json.RawMessage(out.Properties[name])); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(compact.String()); got != schema {
+			t.Errorf("%s: got %s, want %s", name, got, schema)
+		}
+	}
+	if _, ok := doc.Definitions["schema.node"]; !ok {
+		t.Error("recursive type is not defined")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := generate(t)
+	valid, err := json.Marshal(output{
+		base: base{Version: 1},
+		ID:   "a",
+		When: time.Now(),
+		Root: &node{Name: "root", Children: []*node{{Name: "leaf"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(s, valid); err != nil {
+		t.Errorf("encoded value rejected: %v", err)
+	}
+
+	invalid := []string{
+		`{"version":1,"id":"a","ratio":1,"when":"2025-01-01T00:00:00Z","tags":null,"root":null}`,
+		`{"version":"1","id":"a","ratio":1,"when":"2025-01-01T00:00:00Z","tags":null,"root":null,"big":"1"}`,
+		`{"version":1,"id":"a","ratio":1,"when":"2025-01-01T00:00:00Z","tags":null,"root":{"name":3},"big":"1"}`,
+	}
+	for _, doc := range invalid {
+		if err := Validate(s, []byte(doc)); err == nil {
+			t.Errorf("expected %s to be rejected", doc)
+		}
+	}
+}
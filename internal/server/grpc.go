@@ -0,0 +1,253 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strings"
+
+	erstv1 "github.com/dotandev/hintents/api/erst/v1"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcService implements erstv1.DebugServiceServer on top of the same store,
+// debug pipeline and simulation slots as the REST API
+type grpcService struct {
+	erstv1.UnimplementedDebugServiceServer
+	s *Server
+}
+
+// GRPCServer returns a gRPC server with the DebugService registered
+func (s *Server) GRPCServer() *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(s.unaryAuth),
+		grpc.StreamInterceptor(s.streamAuth),
+	)
+	erstv1.RegisterDebugServiceServer(srv, &grpcService{s: s})
+	return srv
+}
+
+// ServeGRPC serves the gRPC API on addr until ctx is cancelled
+func (s *Server) ServeGRPC(ctx context.Context, addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	srv := s.GRPCServer()
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Logger.Info("Starting gRPC server", "addr", addr)
+		errCh <- srv.Serve(lis)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		logger.Logger.Info("Shutting down gRPC server")
+		srv.GracefulStop()
+		return nil
+	}
+}
+
+func (s *Server) authorize(ctx context.Context) error {
+	if s.authToken == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var token string
+	if values := md.Get("authorization"); len(values) > 0 {
+		token = strings.TrimPrefix(values[0], "Bearer ")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.authToken)) != 1 {
+		return grpcError(errors.WrapUnauthorized(""))
+	}
+	return nil
+}
+
+func (s *Server) unaryAuth(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) streamAuth(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorize(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+func (g *grpcService) Simulate(ctx context.Context, req *erstv1.SimulateRequest) (*erstv1.Session, error) {
+	txHash := strings.TrimSpace(req.GetTxHash())
+	if txHash == "" {
+		return nil, grpcError(errors.WrapValidationError("tx_hash is required"))
+	}
+	data, err := g.s.runDebug(ctx, DebugRequest{TxHash: txHash, Network: req.GetNetwork()})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return protoSession(data), nil
+}
+
+func (g *grpcService) GetSession(ctx context.Context, req *erstv1.GetSessionRequest) (*erstv1.Session, error) {
+	data, err := g.s.store.Load(ctx, req.GetId())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return protoSession(data), nil
+}
+
+func (g *grpcService) SearchSessions(ctx context.Context, req *erstv1.SearchSessionsRequest) (*erstv1.SearchSessionsResponse, error) {
+	if req.GetLimit() < 0 {
+		return nil, grpcError(errors.WrapValidationError("limit must not be negative"))
+	}
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	sessions, err := g.s.store.Search(ctx, session.SearchFilter{
+		TxHash:     req.GetTxHash(),
+		ErrorRegex: req.GetErrorRegex(),
+		EventRegex: req.GetEventRegex(),
+		Text:       req.GetText(),
+		Tags:       req.GetTags(),
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, grpcError(errors.WrapValidationError(err.Error()))
+	}
+
+	resp := &erstv1.SearchSessionsResponse{}
+	for _, data := range sessions {
+		summary := &erstv1.SessionSummary{
+			Id:           data.ID,
+			CreatedAt:    timestamppb.New(data.CreatedAt),
+			LastAccessAt: timestamppb.New(data.LastAccessAt),
+			Network:      data.Network,
+			TxHash:       data.TxHash,
+			Tags:         data.Tags,
+		}
+		if sim, err := data.ToSimulationResponse(); err == nil {
+			summary.Status = sim.Status
+			summary.Error = sim.Error
+		}
+		resp.Sessions = append(resp.Sessions, summary)
+	}
+	return resp, nil
+}
+
+func (g *grpcService) StreamEvents(req *erstv1.StreamEventsRequest, stream grpc.ServerStreamingServer[erstv1.Event]) error {
+	data, err := g.s.store.Load(stream.Context(), req.GetSessionId())
+	if err != nil {
+		return grpcError(err)
+	}
+	sim, err := data.ToSimulationResponse()
+	if err != nil {
+		return grpcError(err)
+	}
+
+	var events []*erstv1.Event
+	if len(sim.DiagnosticEvents) > 0 {
+		for _, e := range sim.DiagnosticEvents {
+			events = append(events, &erstv1.Event{Kind: &erstv1.Event_Diagnostic{Diagnostic: protoDiagnosticEvent(e)}})
+		}
+	} else {
+		for _, e := range sim.Events {
+			events = append(events, &erstv1.Event{Kind: &erstv1.Event_Raw{Raw: e}})
+		}
+	}
+	for _, line := range sim.Logs {
+		events = append(events, &erstv1.Event{Kind: &erstv1.Event_Log{Log: line}})
+	}
+
+	for _, e := range events {
+		if err := stream.Send(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func protoSession(data *session.SessionData) *erstv1.Session {
+	out := &erstv1.Session{
+		Id:            data.ID,
+		CreatedAt:     timestamppb.New(data.CreatedAt),
+		LastAccessAt:  timestamppb.New(data.LastAccessAt),
+		Network:       data.Network,
+		HorizonUrl:    data.HorizonURL,
+		TxHash:        data.TxHash,
+		EnvelopeXdr:   data.EnvelopeXdr,
+		ResultXdr:     data.ResultXdr,
+		ResultMetaXdr: data.ResultMetaXdr,
+		ErstVersion:   data.ErstVersion,
+		Tags:          data.Tags,
+	}
+	for _, n := range data.Notes {
+		out.Notes = append(out.Notes, &erstv1.Note{CreatedAt: timestamppb.New(n.CreatedAt), Text: n.Text})
+	}
+	if sim, err := data.ToSimulationResponse(); err == nil {
+		result := &erstv1.SimulationResult{
+			Status:       sim.Status,
+			Error:        sim.Error,
+			Logs:         sim.Logs,
+			ReturnValues: sim.ReturnValues,
+			ResponseJson: data.SimResponseJSON,
+		}
+		for _, e := range sim.DiagnosticEvents {
+			result.DiagnosticEvents = append(result.DiagnosticEvents, protoDiagnosticEvent(e))
+		}
+		out.Simulation = result
+	}
+	return out
+}
+
+func protoDiagnosticEvent(e simulator.DiagnosticEvent) *erstv1.DiagnosticEvent {
+	out := &erstv1.DiagnosticEvent{
+		EventType:                e.EventType,
+		Topics:                   e.Topics,
+		Data:                     e.Data,
+		InSuccessfulContractCall: e.InSuccessfulContractCall,
+	}
+	if e.ContractID != nil {
+		out.ContractId = *e.ContractID
+	}
+	return out
+}
+
+// grpcError converts an erst error to a gRPC status using the same
+// categories as the REST API
+func grpcError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	code := codes.Internal
+	switch statusFor(err) {
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusBadGateway:
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Error())
+}
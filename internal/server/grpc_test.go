@@ -0,0 +1,126 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	erstv1 "github.com/dotandev/hintents/api/erst/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestGRPCClient(t *testing.T, token string) erstv1.DebugServiceClient {
+	t.Helper()
+	api, _ := newTestAPI(t, token)
+
+	lis := bufconn.Listen(1 << 20)
+	srv := api.GRPCServer()
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return erstv1.NewDebugServiceClient(conn)
+}
+
+func TestGRPC_SimulateAndBrowse(t *testing.T) {
+	client := newTestGRPCClient(t, "")
+	ctx := context.Background()
+
+	created, err := client.Simulate(ctx, &erstv1.SimulateRequest{TxHash: "abc123", Network: "testnet"})
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if created.GetId() != "sess-abc123" || created.GetSimulation().GetError() != "HostError: trapped" {
+		t.Errorf("unexpected session %v", created)
+	}
+	if len(created.GetSimulation().GetDiagnosticEvents()) != 1 {
+		t.Errorf("expected 1 diagnostic event, got %d", len(created.GetSimulation().GetDiagnosticEvents()))
+	}
+
+	got, err := client.GetSession(ctx, &erstv1.GetSessionRequest{Id: "sess-abc123"})
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if got.GetTxHash() != "abc123" || got.GetNetwork() != "testnet" {
+		t.Errorf("unexpected session %v", got)
+	}
+
+	found, err := client.SearchSessions(ctx, &erstv1.SearchSessionsRequest{TxHash: "abc123"})
+	if err != nil {
+		t.Fatalf("SearchSessions failed: %v", err)
+	}
+	if len(found.GetSessions()) != 1 || found.GetSessions()[0].GetStatus() != "error" {
+		t.Errorf("unexpected search result %v", found)
+	}
+
+	stream, err := client.StreamEvents(ctx, &erstv1.StreamEventsRequest{SessionId: "sess-abc123"})
+	if err != nil {
+		t.Fatalf("StreamEvents failed: %v", err)
+	}
+	var events []*erstv1.Event
+	for {
+		e, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		events = append(events, e)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected an event and a log, got %d messages", len(events))
+	}
+	if events[0].GetDiagnostic().GetTopics()[0] != "transfer" || events[1].GetLog() != "log1" {
+		t.Errorf("unexpected events %v", events)
+	}
+}
+
+func TestGRPC_Errors(t *testing.T) {
+	client := newTestGRPCClient(t, "secret")
+	authed := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+
+	_, err := client.GetSession(context.Background(), &erstv1.GetSessionRequest{Id: "nope"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated without a token, got %v", err)
+	}
+
+	stream, err := client.StreamEvents(context.Background(), &erstv1.StreamEventsRequest{SessionId: "nope"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated stream without a token, got %v", err)
+	}
+
+	_, err = client.GetSession(authed, &erstv1.GetSessionRequest{Id: "nope"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+
+	_, err = client.Simulate(authed, &erstv1.SimulateRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+
+	_, err = client.Simulate(authed, &erstv1.SimulateRequest{TxHash: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for an unknown transaction, got %v", err)
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package server exposes debugging and session history over a REST API so
+// dashboards and bots can trigger simulations without shelling out to the CLI.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/metrics"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+const (
+	// DefaultMaxConcurrentDebug bounds simultaneous simulations
+	DefaultMaxConcurrentDebug = 4
+
+	// defaultListLimit is used when GET /sessions has no limit parameter
+	defaultListLimit = 50
+
+	// maxRequestBody bounds POST /debug bodies
+	maxRequestBody = 1 << 20
+)
+
+// DebugRequest is the body of POST /debug
+type DebugRequest struct {
+	TxHash  string `json:"tx_hash"`
+	Network string `json:"network,omitempty"`
+}
+
+// DebugFunc fetches and simulates a transaction, saves it as a session and
+// returns that session
+type DebugFunc func(ctx context.Context, req DebugRequest) (*session.SessionData, error)
+
+// Config holds the server dependencies
+type Config struct {
+	Store session.Store
+	Debug DebugFunc
+	// AuthToken, when set, must be sent as "Authorization: Bearer <token>"
+	AuthToken string
+	// MaxConcurrentDebug bounds simultaneous POST /debug simulations;
+	// further requests wait for a free slot
+	MaxConcurrentDebug int
+}
+
+// Server serves the REST API
+type Server struct {
+	store     session.Store
+	debug     DebugFunc
+	authToken string
+	slots     chan struct{}
+}
+
+// SessionSummary is one entry of GET /sessions
+type SessionSummary struct {
+	ID           string    `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastAccessAt time.Time `json:"last_access_at"`
+	Network      string    `json:"network"`
+	TxHash       string    `json:"tx_hash"`
+	Status       string    `json:"status,omitempty"` // simulation status
+	Error        string    `json:"error,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+}
+
+// SessionDetail is a full session with its simulator response decoded
+type SessionDetail struct {
+	*session.SessionData
+	Simulation *simulator.SimulationResponse `json:"simulation,omitempty"`
+}
+
+// New creates a server for cfg
+func New(cfg Config) *Server {
+	slots := cfg.MaxConcurrentDebug
+	if slots <= 0 {
+		slots = DefaultMaxConcurrentDebug
+	}
+	return &Server{
+		store:     cfg.Store,
+		debug:     cfg.Debug,
+		authToken: cfg.AuthToken,
+		slots:     make(chan struct{}, slots),
+	}
+}
+
+// Handler returns the HTTP handler for the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("GET /metrics", metrics.Handler())
+	mux.Handle("POST /debug", s.authenticated(s.handleDebug))
+	mux.Handle("GET /sessions", s.authenticated(s.handleListSessions))
+	mux.Handle("GET /sessions/{id}", s.authenticated(s.handleGetSession))
+	return mux
+}
+
+// ListenAndServe serves the API on addr until ctx is cancelled
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Logger.Info("Starting REST API server", "addr", addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		logger.Logger.Info("Shutting down REST API server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+func (s *Server) authenticated(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.authToken != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(s.authToken)) != 1 {
+				writeError(w, errors.WrapUnauthorized(""))
+				return
+			}
+		}
+		next(w, r)
+	})
+}
+
+func (s *Server) handleDebug(w http.ResponseWriter, r *http.Request) {
+	var req DebugRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+		writeError(w, errors.WrapValidationError("invalid request body: "+err.Error()))
+		return
+	}
+	req.TxHash = strings.TrimSpace(req.TxHash)
+	if req.TxHash == "" {
+		writeError(w, errors.WrapValidationError("tx_hash is required"))
+		return
+	}
+
+	data, err := s.runDebug(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/sessions/"+data.ID)
+	writeJSON(w, http.StatusCreated, detail(data))
+}
+
+// runDebug runs the debug pipeline once a simulation slot is free
+func (s *Server) runDebug(ctx context.Context, req DebugRequest) (*session.SessionData, error) {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	logger.Logger.Info("Processing debug request", "hash", req.TxHash, "network", req.Network)
+	return s.debug(ctx, req)
+}
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := defaultListLimit
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeError(w, errors.WrapValidationError("limit must be a positive integer"))
+			return
+		}
+		limit = n
+	}
+
+	sessions, err := s.store.Search(r.Context(), session.SearchFilter{
+		TxHash:     q.Get("tx"),
+		ErrorRegex: q.Get("error"),
+		EventRegex: q.Get("event"),
+		Text:       q.Get("text"),
+		Tags:       q["tag"],
+		Limit:      limit,
+	})
+	if err != nil {
+		writeError(w, errors.WrapValidationError(err.Error()))
+		return
+	}
+
+	summaries := make([]SessionSummary, 0, len(sessions))
+	for _, data := range sessions {
+		summary := SessionSummary{
+			ID:           data.ID,
+			CreatedAt:    data.CreatedAt,
+			LastAccessAt: data.LastAccessAt,
+			Network:      data.Network,
+			TxHash:       data.TxHash,
+			Tags:         data.Tags,
+		}
+		if resp, err := data.ToSimulationResponse(); err == nil {
+			summary.Status = resp.Status
+			summary.Error = resp.Error
+		}
+		summaries = append(summaries, summary)
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	data, err := s.store.Load(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, detail(data))
+}
+
+func detail(data *session.SessionData) SessionDetail {
+	d := SessionDetail{SessionData: data}
+	if resp, err := data.ToSimulationResponse(); err == nil {
+		d.Simulation = resp
+	}
+	return d
+}
+
+// statusFor maps erst error categories to HTTP status codes
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, errors.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, errors.ErrValidationFailed),
+		errors.Is(err, errors.ErrInvalidNetwork),
+		errors.Is(err, errors.ErrArgumentRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, errors.ErrTransactionNotFound),
+		errors.Is(err, errors.ErrSessionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errors.ErrRateLimitExceeded):
+		return http.StatusTooManyRequests
+	case errors.Is(err, errors.ErrRPCConnectionFailed),
+		errors.Is(err, errors.ErrAllRPCFailed),
+		errors.Is(err, errors.ErrRPCError),
+		errors.Is(err, errors.ErrRPCTimeout):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusFor(err), map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Logger.Warn("Failed to write response", "error", err)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/session"
+)
+
+func newTestAPI(t *testing.T, token string) (*Server, session.Store) {
+	t.Helper()
+	store, err := session.OpenStore(session.BackendSQLite, filepath.Join(t.TempDir(), "sessions.db"))
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	debug := func(ctx context.Context, req DebugRequest) (*session.SessionData, error) {
+		if req.TxHash == "missing" {
+			return nil, errors.WrapTransactionNotFound(fmt.Errorf("transaction %s not found", req.TxHash))
+		}
+		data := &session.SessionData{
+			ID:              "sess-" + req.TxHash,
+			CreatedAt:       time.Now(),
+			LastAccessAt:    time.Now(),
+			Status:          "saved",
+			Network:         req.Network,
+			TxHash:          req.TxHash,
+			SimResponseJSON: `{"status":"error","error":"HostError: trapped","diagnostic_events":[{"event_type":"contract","topics":["transfer"],"data":"100","in_successful_contract_call":false}],"logs":["log1"]}`,
+			SchemaVersion:   session.SchemaVersion,
+		}
+		if err := store.Save(ctx, data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	return New(Config{Store: store, Debug: debug, AuthToken: token}), store
+}
+
+func newTestServer(t *testing.T, token string) (*httptest.Server, session.Store) {
+	t.Helper()
+	api, store := newTestAPI(t, token)
+	srv := httptest.NewServer(api.Handler())
+	t.Cleanup(srv.Close)
+	return srv, store
+}
+
+func do(t *testing.T, method, url, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestServer_Health(t *testing.T) {
+	srv, _ := newTestServer(t, "secret")
+
+	resp := do(t, http.MethodGet, srv.URL+"/health", "", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 without a token, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	srv, _ := newTestServer(t, "secret")
+
+	resp := do(t, http.MethodGet, srv.URL+"/metrics", "", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 without a token, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	srv, _ := newTestServer(t, "secret")
+
+	for _, token := range []string{"", "wrong"} {
+		resp := do(t, http.MethodGet, srv.URL+"/sessions", token, "")
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("token %q: expected 401, got %d", token, resp.StatusCode)
+		}
+	}
+	if resp := do(t, http.MethodGet, srv.URL+"/sessions", "secret", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 with the token, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_DebugAndBrowse(t *testing.T) {
+	srv, store := newTestServer(t, "")
+
+	resp := do(t, http.MethodPost, srv.URL+"/debug", "", `{"tx_hash":"abc123","network":"testnet"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if loc := resp.Header.Get("Location"); loc != "/sessions/sess-abc123" {
+		t.Errorf("unexpected Location %q", loc)
+	}
+	var created SessionDetail
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if created.Simulation == nil || created.Simulation.Error != "HostError: trapped" {
+		t.Errorf("expected decoded simulation, got %+v", created.Simulation)
+	}
+
+	do(t, http.MethodPost, srv.URL+"/debug", "", `{"tx_hash":"def456","network":"testnet"}`)
+	if err := store.AddTags(context.Background(), "sess-def456", "incident-7"); err != nil {
+		t.Fatalf("AddTags failed: %v", err)
+	}
+
+	var all []SessionSummary
+	resp = do(t, http.MethodGet, srv.URL+"/sessions", "", "")
+	if err := json.NewDecoder(resp.Body).Decode(&all); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(all))
+	}
+	if all[0].Status != "error" || all[0].Error == "" {
+		t.Errorf("expected simulation status in summary, got %+v", all[0])
+	}
+
+	var tagged []SessionSummary
+	resp = do(t, http.MethodGet, srv.URL+"/sessions?tag=incident-7", "", "")
+	if err := json.NewDecoder(resp.Body).Decode(&tagged); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if len(tagged) != 1 || tagged[0].ID != "sess-def456" {
+		t.Errorf("expected only the tagged session, got %+v", tagged)
+	}
+
+	resp = do(t, http.MethodGet, srv.URL+"/sessions/sess-abc123", "", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var got SessionDetail
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if got.TxHash != "abc123" || got.Network != "testnet" {
+		t.Errorf("unexpected session %+v", got.SessionData)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	srv, _ := newTestServer(t, "")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"missing hash", http.MethodPost, "/debug", `{}`, http.StatusBadRequest},
+		{"malformed body", http.MethodPost, "/debug", `{`, http.StatusBadRequest},
+		{"unknown transaction", http.MethodPost, "/debug", `{"tx_hash":"missing"}`, http.StatusNotFound},
+		{"unknown session", http.MethodGet, "/sessions/nope", "", http.StatusNotFound},
+		{"bad limit", http.MethodGet, "/sessions?limit=0", "", http.StatusBadRequest},
+		{"bad regex", http.MethodGet, "/sessions?error=(", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := do(t, tt.method, srv.URL+tt.path, "", tt.body)
+			if resp.StatusCode != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, resp.StatusCode)
+			}
+			var body map[string]string
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body["error"] == "" {
+				t.Errorf("expected an error body, got %v (%v)", body, err)
+			}
+		})
+	}
+}
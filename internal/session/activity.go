@@ -0,0 +1,66 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/activity"
+)
+
+// activitySchema is the migration adding the activity log. Its rows are
+// never updated or deleted by erst; the dialect's activityGuard makes the
+// database refuse it too.
+const activitySchema = `
+	CREATE TABLE IF NOT EXISTS activity_log (
+		at TIMESTAMP NOT NULL,
+		user_name TEXT NOT NULL,
+		host TEXT NOT NULL,
+		command TEXT NOT NULL,
+		args TEXT NOT NULL,
+		tx_hash TEXT NOT NULL,
+		network TEXT NOT NULL,
+		result TEXT NOT NULL,
+		error TEXT NOT NULL,
+		duration_ms BIGINT NOT NULL,
+		erst_version TEXT NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_activity_log_at ON activity_log(at);
+	`
+
+// activityLog records activity entries in the session database
+type activityLog struct {
+	store *sqlStore
+}
+
+// OpenActivityLog opens the configured session database to record activity
+// entries in its activity_log table
+func OpenActivityLog() (activity.Sink, error) {
+	store, err := NewStore()
+	if err != nil {
+		return nil, err
+	}
+	return &activityLog{store: store.(*sqlStore)}, nil
+}
+
+func (l *activityLog) Append(ctx context.Context, e activity.Entry) error {
+	args, err := json.Marshal(e.Args)
+	if err != nil {
+		return fmt.Errorf("failed to encode activity entry: %w", err)
+	}
+	_, err = l.store.exec(ctx, `INSERT INTO activity_log (at, user_name, host, command, args, tx_hash, network, result, error, duration_ms, erst_version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.Time.UTC(), e.User, e.Host, e.Command, string(args), e.TxHash, e.Network, e.Result, e.Error, e.DurationMS, e.Version)
+	if err != nil {
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+	return nil
+}
+
+func (l *activityLog) Close() error {
+	return l.store.Close()
+}
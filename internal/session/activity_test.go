@@ -0,0 +1,51 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/activity"
+)
+
+func TestActivityLog(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	SetDBPath(path)
+	t.Cleanup(func() { SetDBPath("") })
+
+	log, err := OpenActivityLog()
+	if err != nil {
+		t.Fatalf("OpenActivityLog: %v", err)
+	}
+	defer log.Close()
+	entry := activity.Entry{
+		Time: time.Now(), User: "alice", Host: "ir-box", Command: "erst debug",
+		Args: []string{"abc", "--rpc-token=REDACTED"}, TxHash: "abc", Network: "testnet",
+		Result: activity.ResultError, Error: "transaction not found", DurationMS: 12,
+	}
+	if err := log.Append(ctx, entry); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	db := log.(*activityLog).store.db
+	var user, args, result string
+	if err := db.QueryRow(`SELECT user_name, args, result FROM activity_log`).Scan(&user, &args, &result); err != nil {
+		t.Fatal(err)
+	}
+	if user != "alice" || args != `["abc","--rpc-token=REDACTED"]` || result != activity.ResultError {
+		t.Errorf("stored %q %q %q", user, args, result)
+	}
+
+	// The log is append-only, even for direct SQL
+	if _, err := db.Exec(`DELETE FROM activity_log`); err == nil {
+		t.Error("expected deleting activity entries to be refused")
+	}
+	if _, err := db.Exec(`UPDATE activity_log SET user_name = 'mallory'`); err == nil {
+		t.Error("expected updating activity entries to be refused")
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// BundleFormatVersion is the version of the export bundle layout
+	BundleFormatVersion = 1
+
+	// BundleExtension is the conventional file extension for export bundles
+	BundleExtension = ".erst"
+
+	// maxBundleFileSize bounds each file read from a bundle so a corrupt or
+	// hostile archive cannot exhaust memory
+	maxBundleFileSize = 256 << 20
+)
+
+// Files inside a bundle. The manifest and the XDR/simulator files are what
+// import reads back; events, logs and profile files are extracted copies for
+// reading the bundle without erst.
+const (
+	bundleManifest    = "manifest.json"
+	bundleEnvelope    = "envelope.xdr"
+	bundleResult      = "result.xdr"
+	bundleResultMeta  = "result_meta.xdr"
+	bundleSimRequest  = "simulation_request.json"
+	bundleSimResponse = "simulation_response.json"
+	bundleEvents      = "events.json"
+	bundleLogs        = "logs.txt"
+	bundleProfile     = "profile.folded"
+	bundleFlamegraph  = "flamegraph.svg"
+)
+
+type bundleFile struct {
+	name    string
+	content string
+}
+
+// BundleManifest describes the session stored in a bundle
+type BundleManifest struct {
+	FormatVersion int       `json:"format_version"`
+	ExportedAt    time.Time `json:"exported_at"`
+	ErstVersion   string    `json:"erst_version"`
+	SchemaVersion int       `json:"schema_version"`
+	ID            string    `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	Status        string    `json:"status"`
+	Network       string    `json:"network"`
+	HorizonURL    string    `json:"horizon_url"`
+	TxHash        string    `json:"tx_hash"`
+	Tags          []string  `json:"tags,omitempty"`
+	Notes         []Note    `json:"notes,omitempty"`
+}
+
+// ExportBundle writes a session as a gzipped tar archive to w
+func ExportBundle(w io.Writer, data *SessionData) error {
+	manifest := BundleManifest{
+		FormatVersion: BundleFormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		ErstVersion:   data.ErstVersion,
+		SchemaVersion: data.SchemaVersion,
+		ID:            data.ID,
+		CreatedAt:     data.CreatedAt,
+		Status:        data.Status,
+		Network:       data.Network,
+		HorizonURL:    data.HorizonURL,
+		TxHash:        data.TxHash,
+		Tags:          data.Tags,
+		Notes:         data.Notes,
+	}
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	files := []bundleFile{
+		{bundleManifest, string(manifestJSON)},
+		{bundleEnvelope, data.EnvelopeXdr},
+		{bundleResult, data.ResultXdr},
+		{bundleResultMeta, data.ResultMetaXdr},
+		{bundleSimRequest, data.SimRequestJSON},
+		{bundleSimResponse, data.SimResponseJSON},
+	}
+
+	if resp, err := data.ToSimulationResponse(); err == nil {
+		if len(resp.Events) > 0 || len(resp.DiagnosticEvents) > 0 {
+			events, err := json.MarshalIndent(map[string]interface{}{
+				"events":            resp.Events,
+				"diagnostic_events": resp.DiagnosticEvents,
+			}, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal events: %w", err)
+			}
+			files = append(files, bundleFile{bundleEvents, string(events)})
+		}
+		if len(resp.Logs) > 0 {
+			files = append(files, bundleFile{bundleLogs, strings.Join(resp.Logs, "\n") + "\n"})
+		}
+		files = append(files,
+			bundleFile{bundleProfile, resp.FoldedStacks},
+			bundleFile{bundleFlamegraph, resp.Flamegraph},
+		)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		if f.content == "" {
+			continue
+		}
+		hdr := &tar.Header{
+			Name:    f.name,
+			Mode:    0600,
+			Size:    int64(len(f.content)),
+			ModTime: manifest.ExportedAt,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+		if _, err := io.WriteString(tw, f.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return gz.Close()
+}
+
+// ImportBundle reads a bundle written by ExportBundle
+func ImportBundle(r io.Reader) (*SessionData, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not an erst bundle: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(io.LimitReader(tr, maxBundleFileSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+		if len(content) > maxBundleFileSize {
+			return nil, fmt.Errorf("bundle file %s exceeds %d bytes", hdr.Name, maxBundleFileSize)
+		}
+		files[hdr.Name] = string(content)
+	}
+
+	raw, ok := files[bundleManifest]
+	if !ok {
+		return nil, fmt.Errorf("bundle has no %s", bundleManifest)
+	}
+	var manifest BundleManifest
+	if err := json.Unmarshal([]byte(raw), &manifest); err != nil {
+		return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	if manifest.FormatVersion > BundleFormatVersion {
+		return nil, fmt.Errorf("bundle format version %d is newer than supported version %d", manifest.FormatVersion, BundleFormatVersion)
+	}
+	if manifest.ID == "" {
+		return nil, fmt.Errorf("bundle manifest has no session ID")
+	}
+
+	return &SessionData{
+		ID:              manifest.ID,
+		CreatedAt:       manifest.CreatedAt,
+		Status:          manifest.Status,
+		Network:         manifest.Network,
+		HorizonURL:      manifest.HorizonURL,
+		TxHash:          manifest.TxHash,
+		EnvelopeXdr:     files[bundleEnvelope],
+		ResultXdr:       files[bundleResult],
+		ResultMetaXdr:   files[bundleResultMeta],
+		SimRequestJSON:  files[bundleSimRequest],
+		SimResponseJSON: files[bundleSimResponse],
+		ErstVersion:     manifest.ErstVersion,
+		SchemaVersion:   manifest.SchemaVersion,
+		Tags:            manifest.Tags,
+		Notes:           manifest.Notes,
+	}, nil
+}
+
+// Import saves a session read from a bundle together with its tags and notes,
+// keeping the notes' original timestamps
+func (s *sqlStore) Import(ctx context.Context, data *SessionData) error {
+	if err := s.Save(ctx, data); err != nil {
+		return err
+	}
+	if len(data.Tags) > 0 {
+		if err := s.AddTags(ctx, data.ID, data.Tags...); err != nil {
+			return err
+		}
+	}
+	for _, note := range data.Notes {
+		createdAt := note.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+		query := `INSERT INTO session_notes (session_id, created_at, text) VALUES (?, ?, ?)`
+		if _, err := s.exec(ctx, query, data.ID, createdAt, note.Text); err != nil {
+			return fmt.Errorf("failed to import note: %w", err)
+		}
+	}
+	return nil
+}
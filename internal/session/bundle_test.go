@@ -0,0 +1,123 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testBundleSession() *SessionData {
+	return &SessionData{
+		ID:              "abc12345-1700000000",
+		CreatedAt:       time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Status:          "saved",
+		Network:         "testnet",
+		HorizonURL:      "https://horizon-testnet.stellar.org",
+		TxHash:          "abc123",
+		EnvelopeXdr:     "AAAA-envelope",
+		ResultMetaXdr:   "AAAA-meta",
+		SimRequestJSON:  `{"envelope_xdr":"AAAA-envelope"}`,
+		SimResponseJSON: `{"status":"error","events":["ev1"],"logs":["log1","log2"],"folded_stacks":"main;call 10"}`,
+		ErstVersion:     "v1.2.3",
+		SchemaVersion:   SchemaVersion,
+		Tags:            []string{"incident-42"},
+		Notes:           []Note{{CreatedAt: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), Text: "repro"}},
+	}
+}
+
+func TestBundle_RoundTrip(t *testing.T) {
+	original := testBundleSession()
+
+	var buf bytes.Buffer
+	if err := ExportBundle(&buf, original); err != nil {
+		t.Fatalf("ExportBundle: %v", err)
+	}
+
+	names := bundleNames(t, buf.Bytes())
+	for _, want := range []string{bundleManifest, bundleEnvelope, bundleResultMeta, bundleSimResponse, bundleEvents, bundleLogs, bundleProfile} {
+		if !names[want] {
+			t.Errorf("bundle is missing %s (has %v)", want, names)
+		}
+	}
+	if names[bundleResult] || names[bundleFlamegraph] {
+		t.Errorf("empty files should be omitted, got %v", names)
+	}
+
+	got, err := ImportBundle(&buf)
+	if err != nil {
+		t.Fatalf("ImportBundle: %v", err)
+	}
+	if got.ID != original.ID || got.TxHash != original.TxHash || got.Network != original.Network ||
+		got.EnvelopeXdr != original.EnvelopeXdr || got.ResultMetaXdr != original.ResultMetaXdr ||
+		got.SimRequestJSON != original.SimRequestJSON || got.SimResponseJSON != original.SimResponseJSON ||
+		!got.CreatedAt.Equal(original.CreatedAt) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, original)
+	}
+	if len(got.Tags) != 1 || len(got.Notes) != 1 || got.Notes[0].Text != "repro" {
+		t.Errorf("annotations lost: %+v %+v", got.Tags, got.Notes)
+	}
+}
+
+func TestBundle_ImportIntoStore(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	if err := store.Import(ctx, testBundleSession()); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	data, err := store.Load(ctx, "abc12345-1700000000")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(data.Tags) != 1 || data.Tags[0] != "incident-42" {
+		t.Errorf("unexpected tags: %v", data.Tags)
+	}
+	if len(data.Notes) != 1 || !data.Notes[0].CreatedAt.Equal(time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("note timestamp not preserved: %+v", data.Notes)
+	}
+}
+
+func TestImportBundle_Invalid(t *testing.T) {
+	if _, err := ImportBundle(strings.NewReader("not a bundle")); err == nil {
+		t.Error("expected error for non-gzip input")
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	_ = tw.WriteHeader(&tar.Header{Name: bundleEnvelope, Mode: 0600, Size: 1})
+	_, _ = tw.Write([]byte("x"))
+	tw.Close()
+	gz.Close()
+	if _, err := ImportBundle(&buf); err == nil {
+		t.Error("expected error for bundle without manifest")
+	}
+}
+
+func bundleNames(t *testing.T, raw []byte) map[string]bool {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	names := make(map[string]bool)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names[hdr.Name] = true
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// Values of session_encryption
+const (
+	EncryptionOff      = "off"
+	EncryptionKeychain = "keychain"
+)
+
+const (
+	// SessionKeyEnv holds a base64 AES-256 key that encrypts stored sessions,
+	// taking precedence over the OS keychain
+	SessionKeyEnv = "ERST_SESSION_KEY"
+
+	// encryptedPrefix marks a column value sealed with the session key; other
+	// values are stored in plain text
+	encryptedPrefix = "enc:v1:"
+
+	sessionKeySize  = 32
+	keychainService = "erst"
+	keychainAccount = "session-key"
+)
+
+// sealer encrypts and decrypts session columns with AES-256-GCM. A nil
+// sealer stores values in plain text.
+type sealer struct {
+	aead cipher.AEAD
+}
+
+func newSealer(key []byte) (*sealer, error) {
+	if len(key) != sessionKeySize {
+		return nil, fmt.Errorf("session key must be %d bytes, got %d", sessionKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &sealer{aead: aead}, nil
+}
+
+// seal encrypts a column value; empty values are kept empty so that absent
+// data stays recognizable
+func (s *sealer) seal(plain string) (string, error) {
+	if s == nil || plain == "" {
+		return plain, nil
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to encrypt session: %w", err)
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// open decrypts a column value written by seal. Plain-text values, from
+// sessions saved before encryption was enabled, are returned unchanged.
+func (s *sealer) open(stored string) (string, error) {
+	encoded, ok := strings.CutPrefix(stored, encryptedPrefix)
+	if !ok {
+		return stored, nil
+	}
+	if s == nil {
+		return "", fmt.Errorf("session is encrypted; set %s or session_encryption = %s to read it", SessionKeyEnv, EncryptionKeychain)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < s.aead.NonceSize() {
+		return "", fmt.Errorf("corrupt encrypted session data")
+	}
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	plain, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt session: wrong session key")
+	}
+	return string(plain), nil
+}
+
+// openAll decrypts each of fields in place
+func (s *sealer) openAll(fields ...*string) error {
+	for _, f := range fields {
+		plain, err := s.open(*f)
+		if err != nil {
+			return err
+		}
+		*f = plain
+	}
+	return nil
+}
+
+// sessionSealer returns the sealer for the session_encryption setting:
+// ERST_SESSION_KEY when set, the key in the OS keychain for "keychain",
+// created on first use, and nil when encryption is off
+func sessionSealer(mode string) (*sealer, error) {
+	if encoded := os.Getenv(SessionKeyEnv); encoded != "" {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", SessionKeyEnv, err)
+		}
+		return newSealer(key)
+	}
+
+	switch strings.ToLower(mode) {
+	case "", EncryptionOff:
+		return nil, nil
+	case EncryptionKeychain:
+		key, err := keychainKey()
+		if err != nil {
+			return nil, err
+		}
+		return newSealer(key)
+	default:
+		return nil, fmt.Errorf("unknown session_encryption %q (expected %s or %s)", mode, EncryptionOff, EncryptionKeychain)
+	}
+}
+
+var errKeyNotFound = errors.New("session key not found in keychain")
+
+// keychainKey reads the session key from the OS keychain, generating and
+// storing one the first time
+func keychainKey() ([]byte, error) {
+	encoded, err := readKeychain()
+	if errors.Is(err, errKeyNotFound) {
+		key := make([]byte, sessionKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate session key: %w", err)
+		}
+		encoded = base64.StdEncoding.EncodeToString(key)
+		if err := writeKeychain(encoded); err != nil {
+			return nil, err
+		}
+		logger.Logger.Info("Created a session encryption key in the OS keychain", "service", keychainService, "account", keychainAccount)
+		return key, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session key in keychain: %w", err)
+	}
+	return key, nil
+}
+
+// readKeychain uses the macOS security tool or the freedesktop Secret
+// Service's secret-tool
+func readKeychain() (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", keychainAccount, "-w")
+	case "linux", "freebsd", "openbsd":
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "account", keychainAccount)
+	default:
+		return "", fmt.Errorf("the OS keychain is not supported on %s; set %s instead", runtime.GOOS, SessionKeyEnv)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	encoded := strings.TrimSpace(string(out))
+
+	// Only a clean "no such item" answer counts as missing, so that a locked
+	// or unreachable keychain never leads to the key being replaced
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		missing := exitErr.ExitCode() == 44 // errSecItemNotFound
+		if runtime.GOOS != "darwin" {
+			missing = exitErr.ExitCode() == 1 && encoded == "" && stderr.Len() == 0
+		}
+		if missing {
+			return "", errKeyNotFound
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the OS keychain: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if encoded == "" {
+		return "", errKeyNotFound
+	}
+	return encoded, nil
+}
+
+// writeKeychain stores the session key, passing it on stdin so that it
+// never shows up in the process list
+func writeKeychain(encoded string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// security -i reads commands from stdin; base64 needs no escaping
+		// inside the quotes
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -s %s -a %s -w \"%s\"\n", keychainService, keychainAccount, encoded))
+	default:
+		cmd = exec.Command("secret-tool", "store", "--label=erst session key", "service", keychainService, "account", keychainAccount)
+		cmd.Stdin = strings.NewReader(encoded)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to store the session key in the OS keychain: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	// security -i exits successfully even when a command fails, so check
+	// that the key reads back before sessions are encrypted with it
+	if stored, err := readKeychain(); err != nil || stored != encoded {
+		return fmt.Errorf("failed to store the session key in the OS keychain: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testSealer(t *testing.T, fill byte) *sealer {
+	t.Helper()
+	s, err := newSealer(bytes.Repeat([]byte{fill}, sessionKeySize))
+	if err != nil {
+		t.Fatalf("newSealer: %v", err)
+	}
+	return s
+}
+
+func TestSealer_RoundTrip(t *testing.T) {
+	s := testSealer(t, 1)
+
+	sealed, err := s.seal("AAAA-envelope")
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if !strings.HasPrefix(sealed, encryptedPrefix) || strings.Contains(sealed, "AAAA-envelope") {
+		t.Fatalf("sealed = %q", sealed)
+	}
+	if again, _ := s.seal("AAAA-envelope"); again == sealed {
+		t.Error("sealing twice gave the same ciphertext")
+	}
+	if plain, err := s.open(sealed); err != nil || plain != "AAAA-envelope" {
+		t.Errorf("open = %q, %v", plain, err)
+	}
+
+	if empty, _ := s.seal(""); empty != "" {
+		t.Errorf("seal(\"\") = %q", empty)
+	}
+	if plain, err := s.open("AAAA-plain"); err != nil || plain != "AAAA-plain" {
+		t.Errorf("open of plain text = %q, %v", plain, err)
+	}
+
+	if _, err := testSealer(t, 2).open(sealed); err == nil || !strings.Contains(err.Error(), "wrong session key") {
+		t.Errorf("open with another key: %v", err)
+	}
+	var none *sealer
+	if _, err := none.open(sealed); err == nil || !strings.Contains(err.Error(), SessionKeyEnv) {
+		t.Errorf("open without a key: %v", err)
+	}
+}
+
+func TestSessionSealer(t *testing.T) {
+	t.Setenv(SessionKeyEnv, "")
+	if s, err := sessionSealer(""); s != nil || err != nil {
+		t.Errorf("sessionSealer(\"\") = %v, %v", s, err)
+	}
+	if s, err := sessionSealer(EncryptionOff); s != nil || err != nil {
+		t.Errorf("sessionSealer(off) = %v, %v", s, err)
+	}
+	if _, err := sessionSealer("aes"); err == nil {
+		t.Error("unknown mode accepted")
+	}
+
+	t.Setenv(SessionKeyEnv, base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, sessionKeySize)))
+	if s, err := sessionSealer(EncryptionOff); s == nil || err != nil {
+		t.Errorf("sessionSealer with %s = %v, %v", SessionKeyEnv, s, err)
+	}
+
+	t.Setenv(SessionKeyEnv, base64.StdEncoding.EncodeToString([]byte("short")))
+	if _, err := sessionSealer(""); err == nil {
+		t.Error("short key accepted")
+	}
+}
+
+func TestStore_Encrypted(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "sessions.db")
+
+	// A session saved before encryption was turned on stays readable
+	plain, err := OpenStore(BackendSQLite, path)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	old := testBundleSession()
+	old.ID = "old"
+	if err := plain.Save(ctx, old); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	plain.Close()
+
+	store, err := openStore(BackendSQLite, path, testSealer(t, 1))
+	if err != nil {
+		t.Fatalf("openStore: %v", err)
+	}
+	defer store.Close()
+	data := testBundleSession()
+	if err := store.Save(ctx, data); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if data.EnvelopeXdr != "AAAA-envelope" {
+		t.Errorf("Save modified its input: %q", data.EnvelopeXdr)
+	}
+
+	var raw string
+	if err := store.(*sqlStore).db.QueryRow(`SELECT envelope_xdr FROM sessions WHERE id = ?`, data.ID).Scan(&raw); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(raw, encryptedPrefix) {
+		t.Errorf("envelope stored as %q", raw)
+	}
+
+	for _, id := range []string{data.ID, "old"} {
+		got, err := store.Load(ctx, id)
+		if err != nil {
+			t.Fatalf("Load(%s): %v", id, err)
+		}
+		if got.EnvelopeXdr != "AAAA-envelope" || got.SimResponseJSON != data.SimResponseJSON {
+			t.Errorf("Load(%s) = %+v", id, got)
+		}
+	}
+	list, err := store.List(ctx, 10)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	for _, s := range list {
+		if s.EnvelopeXdr != "AAAA-envelope" {
+			t.Errorf("List: %s envelope = %q", s.ID, s.EnvelopeXdr)
+		}
+	}
+
+	// Without the key the session cannot be read
+	locked, err := OpenStore(BackendSQLite, path)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	defer locked.Close()
+	if _, err := locked.Load(ctx, data.ID); err == nil {
+		t.Error("loaded an encrypted session without the key")
+	}
+}
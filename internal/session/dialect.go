@@ -0,0 +1,202 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// Session store backends accepted by OpenStore
+const (
+	BackendSQLite   = "sqlite"
+	BackendPostgres = "postgres"
+)
+
+// dialect captures the SQL differences between backends. Queries are written
+// with ? placeholders and rebound for drivers that number their parameters.
+type dialect struct {
+	name string
+	// schema creates the tables of the first schema migration; later
+	// changes are migrations of their own
+	schema         string
+	numberedParams bool
+
+	// textSchema creates the full-text index over events, logs and errors;
+	// hasTextIndex counts existing index tables so a new index is backfilled
+	textSchema   string
+	hasTextIndex string
+	// textMatch filters session_search rows by one phrase parameter, which
+	// textPhrase prepares from the user's search text
+	textMatch  string
+	textPhrase func(text string) string
+
+	// compact reclaims space freed by deleted sessions, so their data no
+	// longer lingers in the file; empty when the server does it
+	compact string
+	// backup copies the database to the file given as its parameter before
+	// migrations run; empty when the server's own backups cover it
+	backup string
+	// migrationLock serializes migrations between processes sharing the
+	// database, within the migration's transaction
+	migrationLock string
+	// activityGuard makes the database reject updates and deletes of
+	// activity_log rows
+	activityGuard string
+}
+
+var sqliteDialect = dialect{
+	name: BackendSQLite,
+	schema: `
+	CREATE TABLE IF NOT EXISTS sessions (
+		id TEXT PRIMARY KEY,
+		created_at TIMESTAMP NOT NULL,
+		last_access_at TIMESTAMP NOT NULL,
+		status TEXT NOT NULL,
+		network TEXT NOT NULL,
+		horizon_url TEXT NOT NULL,
+		tx_hash TEXT NOT NULL,
+		envelope_xdr TEXT,
+		result_xdr TEXT,
+		result_meta_xdr TEXT,
+		sim_request_json TEXT,
+		sim_response_json TEXT,
+		erst_version TEXT,
+		schema_version INTEGER NOT NULL
+	);
+	
+	CREATE INDEX IF NOT EXISTS idx_last_access ON sessions(last_access_at);
+	CREATE INDEX IF NOT EXISTS idx_tx_hash ON sessions(tx_hash);
+
+	CREATE TABLE IF NOT EXISTS session_tags (
+		session_id TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (session_id, tag)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_session_tags_tag ON session_tags(tag);
+
+	CREATE TABLE IF NOT EXISTS session_notes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		text TEXT NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_session_notes_session ON session_notes(session_id);
+	`,
+	textSchema:   `CREATE VIRTUAL TABLE IF NOT EXISTS session_search USING fts5(session_id UNINDEXED, body)`,
+	hasTextIndex: `SELECT COUNT(*) FROM sqlite_master WHERE name = 'session_search'`,
+	textMatch:    `session_search MATCH ?`,
+	compact:      `VACUUM; PRAGMA wal_checkpoint(TRUNCATE)`,
+	backup:       `VACUUM INTO ?`,
+	activityGuard: `
+	CREATE TRIGGER IF NOT EXISTS activity_log_no_update BEFORE UPDATE ON activity_log
+	BEGIN SELECT RAISE(ABORT, 'activity_log is append-only'); END;
+	CREATE TRIGGER IF NOT EXISTS activity_log_no_delete BEFORE DELETE ON activity_log
+	BEGIN SELECT RAISE(ABORT, 'activity_log is append-only'); END;
+	`,
+	// Quote the text as a single FTS5 phrase so characters such as ':' are
+	// not read as query syntax
+	textPhrase: func(text string) string {
+		return `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+	},
+}
+
+var postgresDialect = dialect{
+	name: BackendPostgres,
+	schema: `
+	CREATE TABLE IF NOT EXISTS sessions (
+		id TEXT PRIMARY KEY,
+		created_at TIMESTAMPTZ NOT NULL,
+		last_access_at TIMESTAMPTZ NOT NULL,
+		status TEXT NOT NULL,
+		network TEXT NOT NULL,
+		horizon_url TEXT NOT NULL,
+		tx_hash TEXT NOT NULL,
+		envelope_xdr TEXT,
+		result_xdr TEXT,
+		result_meta_xdr TEXT,
+		sim_request_json TEXT,
+		sim_response_json TEXT,
+		erst_version TEXT,
+		schema_version INTEGER NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_last_access ON sessions(last_access_at);
+	CREATE INDEX IF NOT EXISTS idx_tx_hash ON sessions(tx_hash);
+
+	CREATE TABLE IF NOT EXISTS session_tags (
+		session_id TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (session_id, tag)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_session_tags_tag ON session_tags(tag);
+
+	CREATE TABLE IF NOT EXISTS session_notes (
+		id BIGSERIAL PRIMARY KEY,
+		session_id TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL,
+		text TEXT NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_session_notes_session ON session_notes(session_id);
+	`,
+	numberedParams: true,
+	textSchema: `
+	CREATE TABLE IF NOT EXISTS session_search (
+		session_id TEXT PRIMARY KEY,
+		body TEXT NOT NULL,
+		document TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', body)) STORED
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_session_search_document ON session_search USING GIN (document);
+	`,
+	hasTextIndex: `SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'session_search'`,
+	textMatch:    `document @@ phraseto_tsquery('simple', ?)`,
+	textPhrase:   func(text string) string { return text },
+	// An arbitrary key shared by every erst migrating the database
+	migrationLock: `SELECT pg_advisory_xact_lock(4937211)`,
+	activityGuard: `
+	CREATE OR REPLACE FUNCTION activity_log_append_only() RETURNS trigger AS $$
+	BEGIN RAISE EXCEPTION 'activity_log is append-only'; END;
+	$$ LANGUAGE plpgsql;
+	CREATE TRIGGER activity_log_append_only BEFORE UPDATE OR DELETE ON activity_log
+	FOR EACH ROW EXECUTE PROCEDURE activity_log_append_only();
+	`,
+}
+
+// rebind rewrites ? placeholders as $1, $2, ... for dialects that need it
+func (d dialect) rebind(query string) string {
+	if !d.numberedParams {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (s *sqlStore) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return s.db.ExecContext(ctx, s.dialect.rebind(query), args...)
+}
+
+func (s *sqlStore) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return s.db.QueryContext(ctx, s.dialect.rebind(query), args...)
+}
+
+func (s *sqlStore) queryRow(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return s.db.QueryRowContext(ctx, s.dialect.rebind(query), args...)
+}
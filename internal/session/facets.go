@@ -0,0 +1,88 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// facetsSchema is the migration adding the columns sessions are sorted by
+// that only exist inside their simulator response and envelope
+const facetsSchema = `
+	CREATE TABLE IF NOT EXISTS session_facets (
+		session_id TEXT PRIMARY KEY,
+		error_message TEXT NOT NULL,
+		contract_id TEXT NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_session_facets_error ON session_facets(error_message);
+	CREATE INDEX IF NOT EXISTS idx_session_facets_contract ON session_facets(contract_id);
+	`
+
+// backfillFacets records the sort facets of sessions saved before the facet
+// table existed, or by an older erst
+func (s *sqlStore) backfillFacets(ctx context.Context) error {
+	rows, err := s.query(ctx, `SELECT id, envelope_xdr, sim_response_json FROM sessions
+		WHERE id NOT IN (SELECT session_id FROM session_facets)`)
+	if err != nil {
+		return fmt.Errorf("failed to read sessions for sort facets: %w", err)
+	}
+	var pending []*SessionData
+	for rows.Next() {
+		var data SessionData
+		var envelope, response *string
+		if err := rows.Scan(&data.ID, &envelope, &response); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan session: %w", err)
+		}
+		if envelope != nil {
+			data.EnvelopeXdr = *envelope
+		}
+		if response != nil {
+			data.SimResponseJSON = *response
+		}
+		pending = append(pending, &data)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating sessions: %w", err)
+	}
+
+	for _, data := range pending {
+		if err := s.indexFacets(ctx, data); err != nil {
+			return err
+		}
+	}
+	if len(pending) > 0 {
+		logger.Logger.Debug("Recorded sort facets of existing sessions", "count", len(pending))
+	}
+	return nil
+}
+
+// indexFacets replaces the row holding the error and invoked contract a
+// session is sorted by. Like the search index it would hold them in plain
+// text, so encrypted sessions get empty facets.
+func (s *sqlStore) indexFacets(ctx context.Context, data *SessionData) error {
+	var errorMessage, contractID string
+	if s.sealer == nil {
+		if resp, err := data.ToSimulationResponse(); err == nil {
+			errorMessage = resp.Error
+		}
+		if contract := invokedContract(data.EnvelopeXdr); contract != UnknownContract {
+			contractID = contract
+		}
+	}
+
+	if _, err := s.exec(ctx, `DELETE FROM session_facets WHERE session_id = ?`, data.ID); err != nil {
+		return fmt.Errorf("failed to update sort facets: %w", err)
+	}
+	if _, err := s.exec(ctx, `INSERT INTO session_facets (session_id, error_message, contract_id) VALUES (?, ?, ?)`,
+		data.ID, errorMessage, contractID); err != nil {
+		return fmt.Errorf("failed to update sort facets: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// UnknownContract groups sessions whose envelope does not invoke a contract
+const UnknownContract = "(none)"
+
+// HistoryFilter selects the sessions aggregated by History
+type HistoryFilter struct {
+	// Since excludes sessions created before it, when set
+	Since time.Time
+	// Network restricts the sessions to one network, when set
+	Network string
+	// Bucket is the width of each period of the timeline (default one day)
+	Bucket time.Duration
+	// TopErrors caps the number of error messages listed (default 10)
+	TopErrors int
+}
+
+// HistoryStats aggregates the simulation results of saved sessions
+type HistoryStats struct {
+	Sessions    int     `json:"sessions"`
+	Failures    int     `json:"failures"`
+	FailureRate float64 `json:"failure_rate"`
+	// AvgSimulationMs averages the sessions that recorded a simulation time;
+	// sessions saved by older versions did not
+	AvgSimulationMs float64         `json:"avg_simulation_ms"`
+	TimedSessions   int             `json:"timed_sessions"`
+	Contracts       []ContractStats `json:"contracts"`
+	TopErrors       []ErrorCount    `json:"top_errors"`
+	Timeline        []PeriodStats   `json:"timeline"`
+}
+
+// ContractStats counts the sessions and failures of one invoked contract
+type ContractStats struct {
+	Contract    string  `json:"contract"`
+	Sessions    int     `json:"sessions"`
+	Failures    int     `json:"failures"`
+	FailureRate float64 `json:"failure_rate"`
+}
+
+// ErrorCount is how often one simulation error occurred
+type ErrorCount struct {
+	Error string `json:"error"`
+	Count int    `json:"count"`
+}
+
+// PeriodStats counts the sessions and failures created in one period of the
+// timeline, which starts at Start
+type PeriodStats struct {
+	Start       time.Time `json:"start"`
+	Sessions    int       `json:"sessions"`
+	Failures    int       `json:"failures"`
+	FailureRate float64   `json:"failure_rate"`
+}
+
+// historyRecord is the part of a session that History aggregates
+type historyRecord struct {
+	CreatedAt time.Time
+	Contract  string
+	Status    string
+	Error     string
+	// DurationMs is zero when the session did not record it
+	DurationMs int64
+}
+
+func (r historyRecord) failed() bool {
+	return r.Status != "success" || r.Error != ""
+}
+
+// History aggregates the sessions matching filter. Sessions without a stored
+// simulator response are skipped.
+func (s *sqlStore) History(ctx context.Context, filter HistoryFilter) (*HistoryStats, error) {
+	query := `SELECT created_at, network, envelope_xdr, sim_response_json FROM sessions`
+	var args []interface{}
+	if filter.Network != "" {
+		query += ` WHERE network = ?`
+		args = append(args, filter.Network)
+	}
+
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session history: %w", err)
+	}
+	defer rows.Close()
+
+	var records []historyRecord
+	for rows.Next() {
+		var createdAt, network, envelopeXdr, simResponseJSON string
+		if err := rows.Scan(&createdAt, &network, &envelopeXdr, &simResponseJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		if err := s.sealer.openAll(&envelopeXdr, &simResponseJSON); err != nil {
+			return nil, err
+		}
+		created, err := time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		if !filter.Since.IsZero() && created.Before(filter.Since) {
+			continue
+		}
+		var resp simulator.SimulationResponse
+		if simResponseJSON == "" || json.Unmarshal([]byte(simResponseJSON), &resp) != nil {
+			continue
+		}
+		records = append(records, historyRecord{
+			CreatedAt:  created,
+			Contract:   invokedContract(envelopeXdr),
+			Status:     resp.Status,
+			Error:      resp.Error,
+			DurationMs: resp.DurationMs,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sessions: %w", err)
+	}
+	return summarizeHistory(records, filter), nil
+}
+
+// summarizeHistory aggregates records. Contracts are listed by failures,
+// errors by count and the timeline oldest first, leaving out empty periods.
+func summarizeHistory(records []historyRecord, filter HistoryFilter) *HistoryStats {
+	bucket := filter.Bucket
+	if bucket <= 0 {
+		bucket = 24 * time.Hour
+	}
+	topErrors := filter.TopErrors
+	if topErrors <= 0 {
+		topErrors = 10
+	}
+
+	stats := &HistoryStats{
+		Contracts: []ContractStats{},
+		TopErrors: []ErrorCount{},
+		Timeline:  []PeriodStats{},
+	}
+	contracts := make(map[string]*ContractStats)
+	errorCounts := make(map[string]int)
+	periods := make(map[time.Time]*PeriodStats)
+	var totalMs int64
+
+	for _, r := range records {
+		failed := r.failed()
+
+		c := contracts[r.Contract]
+		if c == nil {
+			c = &ContractStats{Contract: r.Contract}
+			contracts[r.Contract] = c
+		}
+		start := r.CreatedAt.UTC().Truncate(bucket)
+		p := periods[start]
+		if p == nil {
+			p = &PeriodStats{Start: start}
+			periods[start] = p
+		}
+
+		stats.Sessions++
+		c.Sessions++
+		p.Sessions++
+		if failed {
+			stats.Failures++
+			c.Failures++
+			p.Failures++
+			if r.Error != "" {
+				errorCounts[r.Error]++
+			}
+		}
+		if r.DurationMs > 0 {
+			stats.TimedSessions++
+			totalMs += r.DurationMs
+		}
+	}
+
+	stats.FailureRate = rate(stats.Failures, stats.Sessions)
+	if stats.TimedSessions > 0 {
+		stats.AvgSimulationMs = float64(totalMs) / float64(stats.TimedSessions)
+	}
+
+	for _, c := range contracts {
+		c.FailureRate = rate(c.Failures, c.Sessions)
+		stats.Contracts = append(stats.Contracts, *c)
+	}
+	sort.Slice(stats.Contracts, func(i, j int) bool {
+		a, b := stats.Contracts[i], stats.Contracts[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		if a.Sessions != b.Sessions {
+			return a.Sessions > b.Sessions
+		}
+		return a.Contract < b.Contract
+	})
+
+	for msg, count := range errorCounts {
+		stats.TopErrors = append(stats.TopErrors, ErrorCount{Error: msg, Count: count})
+	}
+	sort.Slice(stats.TopErrors, func(i, j int) bool {
+		if stats.TopErrors[i].Count != stats.TopErrors[j].Count {
+			return stats.TopErrors[i].Count > stats.TopErrors[j].Count
+		}
+		return stats.TopErrors[i].Error < stats.TopErrors[j].Error
+	})
+	if len(stats.TopErrors) > topErrors {
+		stats.TopErrors = stats.TopErrors[:topErrors]
+	}
+
+	for _, p := range periods {
+		p.FailureRate = rate(p.Failures, p.Sessions)
+		stats.Timeline = append(stats.Timeline, *p)
+	}
+	sort.Slice(stats.Timeline, func(i, j int) bool {
+		return stats.Timeline[i].Start.Before(stats.Timeline[j].Start)
+	})
+	return stats
+}
+
+func rate(failures, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(failures) / float64(total)
+}
+
+// invokedContract returns the strkey of the first contract the envelope
+// invokes, or UnknownContract
+func invokedContract(envelopeXdr string) string {
+	var env xdr.TransactionEnvelope
+	if envelopeXdr == "" || xdr.SafeUnmarshalBase64(envelopeXdr, &env) != nil {
+		return UnknownContract
+	}
+	for _, op := range env.Operations() {
+		invoke, ok := op.Body.GetInvokeHostFunctionOp()
+		if !ok || invoke.HostFunction.Type != xdr.HostFunctionTypeHostFunctionTypeInvokeContract {
+			continue
+		}
+		address := invoke.HostFunction.InvokeContract.ContractAddress
+		if address.Type != xdr.ScAddressTypeScAddressTypeContract || address.ContractId == nil {
+			continue
+		}
+		if id, err := strkey.Encode(strkey.VersionByteContract, address.ContractId[:]); err == nil {
+			return id
+		}
+	}
+	return UnknownContract
+}
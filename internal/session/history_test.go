@@ -0,0 +1,111 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func invokeEnvelope(t *testing.T, target xdr.ContractId) string {
+	t.Helper()
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress("GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"),
+				Operations: []xdr.Operation{{
+					Body: xdr.OperationBody{
+						Type: xdr.OperationTypeInvokeHostFunction,
+						InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+							HostFunction: xdr.HostFunction{
+								Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+								InvokeContract: &xdr.InvokeContractArgs{
+									ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &target},
+									FunctionName:    "transfer",
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+	b64, err := xdr.MarshalBase64(envelope)
+	if err != nil {
+		t.Fatalf("failed to marshal envelope: %v", err)
+	}
+	return b64
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	token := invokeEnvelope(t, xdr.ContractId{1})
+	tokenID, _ := strkey.Encode(strkey.VersionByteContract, (&xdr.ContractId{1})[:])
+
+	sessions := []struct {
+		created  time.Time
+		network  string
+		envelope string
+		response string
+	}{
+		{day.Add(time.Hour), "testnet", token, `{"status":"error","error":"HostError: Error(Contract, #1)","duration_ms":120}`},
+		{day.Add(2 * time.Hour), "testnet", token, `{"status":"error","error":"HostError: Error(Contract, #1)","duration_ms":80}`},
+		{day.Add(26 * time.Hour), "testnet", token, `{"status":"success"}`},
+		{day.Add(27 * time.Hour), "testnet", "", `{"status":"error","error":"out of budget","duration_ms":100}`},
+		{day.Add(28 * time.Hour), "mainnet", token, `{"status":"error","error":"out of budget"}`},
+		{day.Add(-48 * time.Hour), "testnet", token, `{"status":"error","error":"too old"}`},
+	}
+	for i, s := range sessions {
+		data := &SessionData{
+			ID:              fmt.Sprintf("session-%d", i),
+			CreatedAt:       s.created,
+			Status:          "saved",
+			Network:         s.network,
+			EnvelopeXdr:     s.envelope,
+			SimResponseJSON: s.response,
+		}
+		if err := store.Save(ctx, data); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	stats, err := store.History(ctx, HistoryFilter{Since: day, Network: "testnet"})
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if stats.Sessions != 4 || stats.Failures != 3 || stats.FailureRate != 0.75 {
+		t.Errorf("totals = %d sessions, %d failures, rate %v", stats.Sessions, stats.Failures, stats.FailureRate)
+	}
+	if stats.TimedSessions != 3 || stats.AvgSimulationMs != 100 {
+		t.Errorf("average simulation time = %vms over %d sessions", stats.AvgSimulationMs, stats.TimedSessions)
+	}
+	if len(stats.Contracts) != 2 || stats.Contracts[0].Contract != tokenID || stats.Contracts[0].Failures != 2 ||
+		stats.Contracts[1].Contract != UnknownContract {
+		t.Errorf("contracts = %+v", stats.Contracts)
+	}
+	if len(stats.TopErrors) != 2 || stats.TopErrors[0].Error != "HostError: Error(Contract, #1)" || stats.TopErrors[0].Count != 2 {
+		t.Errorf("top errors = %+v", stats.TopErrors)
+	}
+	if len(stats.Timeline) != 2 || !stats.Timeline[0].Start.Equal(day) ||
+		stats.Timeline[0].FailureRate != 1 || stats.Timeline[1].FailureRate != 0.5 {
+		t.Errorf("timeline = %+v", stats.Timeline)
+	}
+
+	stats, err = store.History(ctx, HistoryFilter{TopErrors: 1})
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if stats.Sessions != 6 || len(stats.TopErrors) != 1 {
+		t.Errorf("unfiltered history = %d sessions, %d errors", stats.Sessions, len(stats.TopErrors))
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// migration is one versioned change to the session database schema. Applied
+// migrations are recorded in schema_migrations, so each runs once per
+// database; a migration must never be edited once released, only followed
+// by a new one.
+type migration struct {
+	version int
+	name    string
+	// up returns the statements of the migration for a backend
+	up func(d dialect) string
+}
+
+// migrations lists every schema change in order. The first creates the
+// tables of databases older than the migrations themselves, which is a no-op
+// for them, as all its statements are IF NOT EXISTS.
+var migrations = []migration{
+	{1, "create sessions, tags and notes", func(d dialect) string { return d.schema }},
+	{2, "add sort facets", func(dialect) string { return facetsSchema }},
+	{3, "add activity log", func(d dialect) string { return activitySchema + d.activityGuard }},
+}
+
+const migrationsSchema = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`
+
+// latestMigration is the schema version this erst migrates databases to
+func latestMigration() int {
+	return migrations[len(migrations)-1].version
+}
+
+// schemaVersion returns the version of the latest migration applied to the
+// database, or 0 when none is
+func (s *sqlStore) schemaVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := s.queryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// migrate applies the migrations the database has not had yet, each in its
+// own transaction, backing up a SQLite database that holds sessions first. A
+// database migrated by a newer erst is refused rather than written with an
+// older schema.
+func (s *sqlStore) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, migrationsSchema); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	current, err := s.schemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if current > latestMigration() {
+		return fmt.Errorf("session database schema version %d is newer than this erst supports (%d); upgrade erst to use it", current, latestMigration())
+	}
+	if current == latestMigration() {
+		return nil
+	}
+
+	if err := s.backupBeforeMigration(ctx, current); err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := s.applyMigration(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigration runs one migration and records it, unless another process
+// applied it first
+func (s *sqlStore) applyMigration(ctx context.Context, m migration) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start migration %d: %w", m.version, err)
+	}
+	defer tx.Rollback()
+
+	if s.dialect.migrationLock != "" {
+		if _, err := tx.ExecContext(ctx, s.dialect.migrationLock); err != nil {
+			return fmt.Errorf("failed to lock schema_migrations: %w", err)
+		}
+	}
+	var applied int
+	if err := tx.QueryRowContext(ctx, s.dialect.rebind(`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`), m.version).Scan(&applied); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if applied > 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, m.up(s.dialect)); err != nil {
+		return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+	}
+	if _, err := tx.ExecContext(ctx, s.dialect.rebind(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`),
+		m.version, m.name, time.Now()); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", m.version, err)
+	}
+	logger.Logger.Debug("Migrated session database", "version", m.version, "migration", m.name)
+	return nil
+}
+
+// backupBeforeMigration copies a SQLite database holding sessions to
+// <file>.v<version>.bak before it is migrated from version. New databases
+// have nothing to lose and are not backed up.
+func (s *sqlStore) backupBeforeMigration(ctx context.Context, version int) error {
+	if s.dialect.backup == "" || s.path == "" {
+		return nil
+	}
+	var sessions int
+	if err := s.queryRow(ctx, `SELECT COUNT(*) FROM sessions`).Scan(&sessions); err != nil || sessions == 0 {
+		// No sessions table yet, or nothing in it
+		return nil
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", s.path, version)
+	if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace session database backup: %w", err)
+	}
+	if _, err := s.exec(ctx, s.dialect.backup, backup); err != nil {
+		return fmt.Errorf("failed to back up session database before migrating: %w", err)
+	}
+	if err := os.Chmod(backup, 0600); err != nil {
+		logger.Logger.Warn("Failed to set backup permissions", "error", err)
+	}
+	logger.Logger.Info("Backed up session database before migrating", "backup", backup, "from_version", version, "to_version", latestMigration())
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrate_NewDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	store, err := OpenStore(BackendSQLite, path)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	defer store.Close()
+
+	version, err := store.(*sqlStore).schemaVersion(context.Background())
+	if err != nil || version != latestMigration() {
+		t.Errorf("schemaVersion() = %d, %v, want %d", version, err, latestMigration())
+	}
+	if _, err := os.Stat(path + ".v0.bak"); !os.IsNotExist(err) {
+		t.Error("a new database should not be backed up")
+	}
+}
+
+func TestMigrate_ExistingDatabase(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "sessions.db")
+
+	// A database written before migrations existed
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(sqliteDialect.schema); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO sessions (id, created_at, last_access_at, status, network, horizon_url, tx_hash,
+		envelope_xdr, result_xdr, result_meta_xdr, sim_request_json, sim_response_json, erst_version, schema_version)
+		VALUES ('old', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z', 'saved', 'testnet', '', 'tx-old', '', '', '', '', '', '', 1)`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	store, err := OpenStore(BackendSQLite, path)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	if _, err := store.Load(ctx, "old"); err != nil {
+		t.Errorf("existing session lost in migration: %v", err)
+	}
+	if version, _ := store.(*sqlStore).schemaVersion(ctx); version != latestMigration() {
+		t.Errorf("schemaVersion() = %d, want %d", version, latestMigration())
+	}
+	if n, err := store.Count(ctx, SearchFilter{Sort: SortError}); err != nil || n != 1 {
+		t.Errorf("Count() = %d, %v after migration", n, err)
+	}
+	store.Close()
+
+	backup, err := sql.Open("sqlite", path+".v0.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backup.Close()
+	var sessions int
+	if err := backup.QueryRow(`SELECT COUNT(*) FROM sessions`).Scan(&sessions); err != nil || sessions != 1 {
+		t.Errorf("backup holds %d sessions, %v", sessions, err)
+	}
+
+	// Opening again applies nothing
+	store, err = OpenStore(BackendSQLite, path)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	store.Close()
+}
+
+func TestMigrate_Failure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	store, err := OpenStore(BackendSQLite, path)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	if err := store.Save(context.Background(), &SessionData{ID: "s1", Status: "saved", Network: "testnet"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	store.Close()
+
+	saved := migrations
+	t.Cleanup(func() { migrations = saved })
+	migrations = append(append([]migration{}, saved...),
+		migration{latestMigration() + 1, "broken", func(dialect) string {
+			return `ALTER TABLE sessions ADD COLUMN profile TEXT; ALTER TABLE no_such_table ADD COLUMN x TEXT`
+		}})
+
+	if _, err := OpenStore(BackendSQLite, path); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("expected the broken migration to fail, got %v", err)
+	}
+
+	// The failed migration left no trace and the previous version is intact
+	migrations = saved
+	store, err = OpenStore(BackendSQLite, path)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	defer store.Close()
+	if _, err := store.(*sqlStore).db.Exec(`ALTER TABLE sessions ADD COLUMN profile TEXT`); err != nil {
+		t.Errorf("column of the failed migration was kept: %v", err)
+	}
+	if _, err := store.Load(context.Background(), "s1"); err != nil {
+		t.Errorf("session lost after failed migration: %v", err)
+	}
+}
+
+func TestMigrate_NewerDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	store, err := OpenStore(BackendSQLite, path)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	if _, err := store.(*sqlStore).db.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, 'from the future', CURRENT_TIMESTAMP)`, latestMigration()+1); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	if _, err := OpenStore(BackendSQLite, path); err == nil || !strings.Contains(err.Error(), "newer than this erst supports") {
+		t.Errorf("expected a newer database to be refused, got %v", err)
+	}
+}
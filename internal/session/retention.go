@@ -0,0 +1,254 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// RetentionPolicy bounds how much session history is kept. Zero fields are
+// not enforced. Sessions are ranked by last access, so the most recently used
+// ones survive.
+type RetentionPolicy struct {
+	MaxAge      time.Duration
+	MaxSessions int
+	// MaxBytes limits the stored envelopes, metadata and simulator I/O
+	MaxBytes int64
+	// DryRun reports what would be removed without deleting anything
+	DryRun bool
+	// Compact reclaims the freed space afterwards, which rewrites the whole
+	// SQLite file and is therefore only done for explicit prunes
+	Compact bool
+}
+
+// PruneResult reports the sessions removed by Prune
+type PruneResult struct {
+	IDs        []string `json:"ids"`
+	Expired    int      `json:"expired"`
+	Excess     int      `json:"excess"`
+	Oversize   int      `json:"oversize"`
+	FreedBytes int64    `json:"freed_bytes"`
+	Remaining  int      `json:"remaining"`
+}
+
+// DefaultRetention is the policy applied when none is configured
+func DefaultRetention() RetentionPolicy {
+	return RetentionPolicy{MaxAge: DefaultTTL, MaxSessions: DefaultMaxSessions}
+}
+
+// RetentionFromConfig returns DefaultRetention overridden by the
+// session_max_age, session_max_sessions and session_max_db_size settings
+func RetentionFromConfig(cfg *config.Config) (RetentionPolicy, error) {
+	policy := DefaultRetention()
+	if cfg.SessionMaxAge != "" {
+		age, err := ParseAge(cfg.SessionMaxAge)
+		if err != nil {
+			return policy, fmt.Errorf("invalid session_max_age: %w", err)
+		}
+		policy.MaxAge = age
+	}
+	if cfg.SessionMaxSessions > 0 {
+		policy.MaxSessions = cfg.SessionMaxSessions
+	}
+	if cfg.SessionMaxDBSize != "" {
+		size, err := ParseSize(cfg.SessionMaxDBSize)
+		if err != nil {
+			return policy, fmt.Errorf("invalid session_max_db_size: %w", err)
+		}
+		policy.MaxBytes = size
+	}
+	return policy, nil
+}
+
+// ConfiguredRetention is RetentionFromConfig for the loaded config, falling
+// back to DefaultRetention when the config cannot be read
+func ConfiguredRetention() RetentionPolicy {
+	cfg, err := config.Load()
+	if err != nil {
+		return DefaultRetention()
+	}
+	policy, err := RetentionFromConfig(cfg)
+	if err != nil {
+		logger.Logger.Warn("Ignoring invalid session retention setting", "error", err)
+	}
+	return policy
+}
+
+// ParseAge parses a retention age such as "30d", "2w" or "12h"
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.ParseFloat(n, 64)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(v * float64(unit)), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 30d, 2w or 12h)", s)
+	}
+	return d, nil
+}
+
+// ParseSize parses a size such as "500MB", "2GiB" or "1048576"
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	upper := strings.ToUpper(s)
+	units := []struct {
+		suffix string
+		scale  int64
+	}{
+		{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30},
+		{"KB", 1000}, {"MB", 1000 * 1000}, {"GB", 1000 * 1000 * 1000},
+		{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30},
+		{"B", 1},
+	}
+	for _, u := range units {
+		if n, ok := strings.CutSuffix(upper, u.suffix); ok {
+			v, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid size %q", s)
+			}
+			return int64(v * float64(u.scale)), nil
+		}
+	}
+	v, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 500MB or 2GiB)", s)
+	}
+	return v, nil
+}
+
+// sessionSizeExpr estimates the stored size of a session row
+const sessionSizeExpr = `COALESCE(LENGTH(envelope_xdr), 0) + COALESCE(LENGTH(result_xdr), 0) +
+	COALESCE(LENGTH(result_meta_xdr), 0) + COALESCE(LENGTH(sim_request_json), 0) +
+	COALESCE(LENGTH(sim_response_json), 0)`
+
+// Stats summarises the contents of a session store
+type Stats struct {
+	Sessions int   `json:"sessions"`
+	Bytes    int64 `json:"bytes"`
+}
+
+// Stats returns the number of saved sessions and their estimated size
+func (s *sqlStore) Stats(ctx context.Context) (*Stats, error) {
+	stats := &Stats{}
+	err := s.queryRow(ctx, `SELECT COUNT(*), COALESCE(SUM(`+sessionSizeExpr+`), 0) FROM sessions`).Scan(&stats.Sessions, &stats.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session stats: %w", err)
+	}
+	return stats, nil
+}
+
+// Prune removes sessions outside policy. Sessions are kept newest first until
+// the first limit is reached; everything older than that is removed.
+func (s *sqlStore) Prune(ctx context.Context, policy RetentionPolicy) (*PruneResult, error) {
+	rows, err := s.query(ctx, `SELECT id, last_access_at, `+sessionSizeExpr+` FROM sessions ORDER BY last_access_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions for pruning: %w", err)
+	}
+	defer rows.Close()
+
+	result := &PruneResult{}
+	cutoff := time.Now().Add(-policy.MaxAge)
+	var keptBytes int64
+	overSize := false
+	for rows.Next() {
+		var id, lastAccess string
+		var size int64
+		if err := rows.Scan(&id, &lastAccess, &size); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		accessed, err := time.Parse(time.RFC3339, lastAccess)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse last_access_at: %w", err)
+		}
+
+		switch {
+		case policy.MaxAge > 0 && accessed.Before(cutoff):
+			result.Expired++
+		case policy.MaxSessions > 0 && result.Remaining >= policy.MaxSessions:
+			result.Excess++
+		case policy.MaxBytes > 0 && (overSize || keptBytes+size > policy.MaxBytes):
+			overSize = true
+			result.Oversize++
+		default:
+			result.Remaining++
+			keptBytes += size
+			continue
+		}
+		result.IDs = append(result.IDs, id)
+		result.FreedBytes += size
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sessions: %w", err)
+	}
+	rows.Close()
+
+	if policy.DryRun || len(result.IDs) == 0 {
+		return result, nil
+	}
+
+	for _, id := range result.IDs {
+		if _, err := s.exec(ctx, `DELETE FROM sessions WHERE id = ?`, id); err != nil {
+			return nil, fmt.Errorf("failed to delete session %s: %w", id, err)
+		}
+	}
+	if err := s.deleteAnnotations(ctx); err != nil {
+		return nil, err
+	}
+	logger.Logger.Debug("Pruned sessions", "expired", result.Expired, "excess", result.Excess, "oversize", result.Oversize)
+
+	if policy.Compact {
+		if err := s.Compact(ctx); err != nil {
+			logger.Logger.Warn("Failed to compact session database", "error", err)
+		}
+	}
+	return result, nil
+}
+
+// Compact rewrites the database to give back the space of deleted sessions.
+// Until then SQLite keeps their pages, contents included, in the file.
+func (s *sqlStore) Compact(ctx context.Context) error {
+	if s.dialect.compact == "" {
+		return nil
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.compact); err != nil {
+		return fmt.Errorf("failed to compact session database: %w", err)
+	}
+	return nil
+}
+
+// RemoveBackups deletes the copies of a SQLite database made before
+// migrations, which still hold every session deleted since, and returns
+// their paths
+func (s *sqlStore) RemoveBackups(_ context.Context) ([]string, error) {
+	if s.dialect.backup == "" || s.path == "" {
+		return nil, nil
+	}
+	backups, err := filepath.Glob(s.path + ".v*.bak")
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, backup := range backups {
+		if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("failed to remove session database backup: %w", err)
+		}
+		removed = append(removed, backup)
+	}
+	return removed, nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+)
+
+func TestParseAge(t *testing.T) {
+	cases := map[string]time.Duration{
+		"30d":  30 * 24 * time.Hour,
+		"2w":   14 * 24 * time.Hour,
+		"12h":  12 * time.Hour,
+		"1.5d": 36 * time.Hour,
+	}
+	for in, want := range cases {
+		got, err := ParseAge(in)
+		if err != nil || got != want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "soon", "-3d", "d"} {
+		if _, err := ParseAge(bad); err == nil {
+			t.Errorf("ParseAge(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	cases := map[string]int64{
+		"500MB":  500 * 1000 * 1000,
+		"2GiB":   2 << 30,
+		"10k":    10 << 10,
+		"1024":   1024,
+		"64 KiB": 64 << 10,
+	}
+	for in, want := range cases {
+		got, err := ParseSize(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "big", "-1MB"} {
+		if _, err := ParseSize(bad); err == nil {
+			t.Errorf("ParseSize(%q) should fail", bad)
+		}
+	}
+}
+
+func TestRetentionFromConfig(t *testing.T) {
+	policy, err := RetentionFromConfig(&config.Config{SessionMaxAge: "7d", SessionMaxDBSize: "1MB"})
+	if err != nil {
+		t.Fatalf("RetentionFromConfig: %v", err)
+	}
+	if policy.MaxAge != 7*24*time.Hour || policy.MaxSessions != DefaultMaxSessions || policy.MaxBytes != 1000*1000 {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+	if _, err := RetentionFromConfig(&config.Config{SessionMaxAge: "forever"}); err == nil {
+		t.Error("expected invalid age to be rejected")
+	}
+}
+
+// seedSessions saves n sessions of roughly size bytes each, session-0 being
+// the most recently accessed and each next one a day older
+func seedSessions(t *testing.T, store Store, n, size int) {
+	t.Helper()
+	ctx := context.Background()
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("session-%d", i)
+		err := store.Save(ctx, &SessionData{ID: id, Status: "saved", Network: "testnet", TxHash: id,
+			EnvelopeXdr: strings.Repeat("A", size)})
+		if err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		accessed := time.Now().Add(-time.Duration(i) * 24 * time.Hour)
+		if _, err := store.(*sqlStore).db.Exec(`UPDATE sessions SET last_access_at = ? WHERE id = ?`, accessed, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func remainingIDs(t *testing.T, store Store) string {
+	t.Helper()
+	sessions, err := store.List(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var ids []string
+	for _, s := range sessions {
+		ids = append(ids, s.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("age", func(t *testing.T) {
+		store := newTestStore(t)
+		seedSessions(t, store, 5, 10)
+		result, err := store.Prune(ctx, RetentionPolicy{MaxAge: 36 * time.Hour})
+		if err != nil {
+			t.Fatalf("Prune: %v", err)
+		}
+		if result.Expired != 3 || result.Remaining != 2 {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if got := remainingIDs(t, store); got != "session-0,session-1" {
+			t.Errorf("remaining = %s", got)
+		}
+	})
+
+	t.Run("count", func(t *testing.T) {
+		store := newTestStore(t)
+		seedSessions(t, store, 5, 10)
+		result, err := store.Prune(ctx, RetentionPolicy{MaxSessions: 3})
+		if err != nil {
+			t.Fatalf("Prune: %v", err)
+		}
+		if result.Excess != 2 || strings.Join(result.IDs, ",") != "session-3,session-4" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("size", func(t *testing.T) {
+		store := newTestStore(t)
+		seedSessions(t, store, 5, 1000)
+		result, err := store.Prune(ctx, RetentionPolicy{MaxBytes: 2500, Compact: true})
+		if err != nil {
+			t.Fatalf("Prune: %v", err)
+		}
+		if result.Oversize != 3 || result.FreedBytes != 3000 {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if got := remainingIDs(t, store); got != "session-0,session-1" {
+			t.Errorf("remaining = %s", got)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		store := newTestStore(t)
+		seedSessions(t, store, 3, 10)
+		result, err := store.Prune(ctx, RetentionPolicy{MaxSessions: 1, DryRun: true})
+		if err != nil {
+			t.Fatalf("Prune: %v", err)
+		}
+		if len(result.IDs) != 2 {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if got := remainingIDs(t, store); got != "session-0,session-1,session-2" {
+			t.Errorf("dry run deleted sessions: %s", got)
+		}
+	})
+}
+
+func TestStats(t *testing.T) {
+	store := newTestStore(t)
+	stats, err := store.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Sessions != 0 || stats.Bytes != 0 {
+		t.Errorf("empty store stats = %+v", stats)
+	}
+
+	seedSessions(t, store, 3, 1000)
+	stats, err = store.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Sessions != 3 || stats.Bytes != 3000 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	store, err := OpenStore(BackendSQLite, path)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	defer store.Close()
+
+	secret := strings.Repeat("do-not-keep-", 200)
+	if err := store.Save(ctx, &SessionData{ID: "secret", Status: "saved", Network: "testnet", EnvelopeXdr: secret}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := store.Delete(ctx, "secret"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Compact(ctx); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	backup := path + ".v1.bak"
+	if err := os.WriteFile(backup, []byte(secret), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := store.RemoveBackups(ctx); err != nil || len(removed) != 1 || removed[0] != backup {
+		t.Errorf("RemoveBackups = %v, %v", removed, err)
+	}
+
+	for _, file := range []string{path, path + "-wal", backup} {
+		contents, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(contents), "do-not-keep-do-not-keep-") {
+			t.Errorf("%s still holds the deleted session", filepath.Base(file))
+		}
+	}
+}
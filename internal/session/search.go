@@ -0,0 +1,285 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// searchBatch is how many candidate sessions Search reads per query when
+// results are filtered by pattern after loading
+const searchBatch = 100
+
+// Search orders accepted in SearchFilter.Sort
+const (
+	SortTimestamp = "timestamp"
+	SortNetwork   = "network"
+	SortError     = "error"
+	SortContract  = "contract"
+)
+
+// sortColumns maps each search order to the column it sorts by
+var sortColumns = map[string]string{
+	SortTimestamp: "s.created_at",
+	SortNetwork:   "s.network",
+	SortError:     "COALESCE(f.error_message, '')",
+	SortContract:  "COALESCE(f.contract_id, '')",
+}
+
+// SearchFilter defines the criteria for searching saved sessions
+type SearchFilter struct {
+	TxHash     string
+	ErrorRegex string
+	EventRegex string
+	// Text is a phrase matched against the full-text index of errors, events
+	// and logs
+	Text string
+	// Tags restricts results to sessions carrying every listed tag
+	Tags []string
+	// Since and Until restrict results to sessions created in [Since, Until),
+	// when set
+	Since time.Time
+	Until time.Time
+	// Sort orders results by creation time, network, error or invoked
+	// contract, ascending unless Descending is set. Without it results are
+	// the most recently accessed first.
+	Sort       string
+	Descending bool
+	// Limit caps the number of results, after skipping the first Offset
+	Limit  int
+	Offset int
+}
+
+// responseFilter holds the patterns matched against stored simulator
+// responses rather than in the database
+type responseFilter struct {
+	errorRe, eventRe *regexp.Regexp
+}
+
+func (f responseFilter) empty() bool {
+	return f.errorRe == nil && f.eventRe == nil
+}
+
+// searchQuery is the database part of a search: the FROM and WHERE clauses
+// selecting the sessions that match a filter, their order, and the patterns
+// left to match against each session
+type searchQuery struct {
+	from     string
+	args     []interface{}
+	order    string
+	patterns responseFilter
+}
+
+// ids is the query selecting the IDs of matching sessions in order, ending in
+// LIMIT and OFFSET placeholders
+func (q *searchQuery) ids() string {
+	return `SELECT s.id` + q.from + ` ORDER BY ` + q.order + `, s.id LIMIT ? OFFSET ?`
+}
+
+// newSearchQuery translates filter into a searchQuery
+func (s *sqlStore) newSearchQuery(filter SearchFilter) (*searchQuery, error) {
+	var patterns responseFilter
+	var err error
+	if filter.ErrorRegex != "" {
+		if patterns.errorRe, err = regexp.Compile(filter.ErrorRegex); err != nil {
+			return nil, fmt.Errorf("invalid error regex: %w", err)
+		}
+	}
+	if filter.EventRegex != "" {
+		if patterns.eventRe, err = regexp.Compile(filter.EventRegex); err != nil {
+			return nil, fmt.Errorf("invalid event regex: %w", err)
+		}
+	}
+	if filter.Limit < 0 || filter.Offset < 0 {
+		return nil, fmt.Errorf("limit and offset must not be negative")
+	}
+	order := "s.last_access_at DESC"
+	if filter.Sort != "" {
+		column, ok := sortColumns[filter.Sort]
+		if !ok {
+			return nil, fmt.Errorf("unsupported sort %q (use %s, %s, %s or %s)", filter.Sort, SortTimestamp, SortNetwork, SortError, SortContract)
+		}
+		direction := "ASC"
+		if filter.Descending {
+			direction = "DESC"
+		}
+		order = column + " " + direction + ", " + order
+	}
+
+	query := ` FROM sessions s LEFT JOIN session_facets f ON f.session_id = s.id WHERE 1=1`
+	var args []interface{}
+	// Timestamps are stored in local time, so bounds are compared in it too
+	if !filter.Since.IsZero() {
+		query += ` AND s.created_at >= ?`
+		args = append(args, filter.Since.Local())
+	}
+	if !filter.Until.IsZero() {
+		query += ` AND s.created_at < ?`
+		args = append(args, filter.Until.Local())
+	}
+	if filter.TxHash != "" {
+		query += ` AND s.tx_hash = ?`
+		args = append(args, filter.TxHash)
+	}
+	for _, tag := range filter.Tags {
+		normalized, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		query += ` AND s.id IN (SELECT session_id FROM session_tags WHERE tag = ?)`
+		args = append(args, normalized)
+	}
+	if text := strings.TrimSpace(filter.Text); text != "" {
+		query += ` AND s.id IN (SELECT session_id FROM session_search WHERE ` + s.dialect.textMatch + `)`
+		args = append(args, s.dialect.textPhrase(text))
+	}
+	return &searchQuery{from: query, args: args, order: order, patterns: patterns}, nil
+}
+
+// Search returns saved sessions matching filter in the order it asks for.
+// Everything but the error and event patterns is filtered and sorted in the
+// database, which also pages through the results; the patterns are matched
+// against the stored simulator response, reading candidates in batches until
+// the page is full.
+func (s *sqlStore) Search(ctx context.Context, filter SearchFilter) ([]*SessionData, error) {
+	q, err := s.newSearchQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = math.MaxInt32
+	}
+	if q.patterns.empty() {
+		ids, err := s.searchIDs(ctx, q.ids(), q.args, limit, filter.Offset)
+		if err != nil {
+			return nil, err
+		}
+		var results []*SessionData
+		for _, id := range ids {
+			data, err := s.get(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, data)
+		}
+		return results, nil
+	}
+
+	var results []*SessionData
+	skip := filter.Offset
+	err = s.scanMatches(ctx, q, func(data *SessionData) bool {
+		if skip > 0 {
+			skip--
+			return true
+		}
+		results = append(results, data)
+		return len(results) < limit
+	})
+	return results, err
+}
+
+// Count returns the number of saved sessions matching filter, ignoring its
+// order, limit and offset. Without error and event patterns the database
+// counts them.
+func (s *sqlStore) Count(ctx context.Context, filter SearchFilter) (int, error) {
+	filter.Sort, filter.Limit, filter.Offset = "", 0, 0
+	q, err := s.newSearchQuery(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	if q.patterns.empty() {
+		var count int
+		if err := s.queryRow(ctx, `SELECT COUNT(*)`+q.from, q.args...).Scan(&count); err != nil {
+			return 0, fmt.Errorf("failed to count sessions: %w", err)
+		}
+		return count, nil
+	}
+
+	count := 0
+	err = s.scanMatches(ctx, q, func(*SessionData) bool {
+		count++
+		return true
+	})
+	return count, err
+}
+
+// scanMatches reads the sessions selected by q in batches and calls fn with
+// each one matching its patterns until fn returns false
+func (s *sqlStore) scanMatches(ctx context.Context, q *searchQuery, fn func(*SessionData) bool) error {
+	for offset := 0; ; offset += searchBatch {
+		ids, err := s.searchIDs(ctx, q.ids(), q.args, searchBatch, offset)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			data, err := s.get(ctx, id)
+			if err != nil {
+				return err
+			}
+			if matchesResponse(data, q.patterns.errorRe, q.patterns.eventRe) && !fn(data) {
+				return nil
+			}
+		}
+		if len(ids) < searchBatch {
+			return nil
+		}
+	}
+}
+
+// searchIDs runs a search query ending in LIMIT and OFFSET placeholders and
+// returns the matching session IDs
+func (s *sqlStore) searchIDs(ctx context.Context, query string, args []interface{}, limit, offset int) ([]string, error) {
+	rows, err := s.query(ctx, query, append(args[:len(args):len(args)], limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sessions: %w", err)
+	}
+	return ids, nil
+}
+
+// matchesResponse reports whether the stored simulator response matches the
+// error and event patterns that are set
+func matchesResponse(data *SessionData, errorRe, eventRe *regexp.Regexp) bool {
+	resp, err := data.ToSimulationResponse()
+	if err != nil {
+		return false
+	}
+	if errorRe != nil && !errorRe.MatchString(resp.Error) {
+		return false
+	}
+	if eventRe != nil {
+		for _, e := range resp.Events {
+			if eventRe.MatchString(e) {
+				return true
+			}
+		}
+		for _, e := range resp.DiagnosticEvents {
+			if eventRe.MatchString(e.EventType) || eventRe.MatchString(strings.Join(e.Topics, " ")) || eventRe.MatchString(e.Data) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// maxShareResponseSize bounds the body read back from a share endpoint
+const maxShareResponseSize = 64 << 10
+
+// Redact returns a copy of data fit for sharing outside the team: notes are
+// dropped unless keepNotes is set, the RPC URL is cut down to its scheme and
+// host, since providers put API keys in paths and queries, and local paths
+// (the --wasm file, home directories in source locations and logs) are
+// removed from the simulator request and response
+func Redact(data *SessionData, keepNotes bool) *SessionData {
+	out := *data
+	out.Tags = append([]string(nil), data.Tags...)
+	if keepNotes {
+		out.Notes = append([]Note(nil), data.Notes...)
+	} else {
+		out.Notes = nil
+	}
+	out.HorizonURL = redactURL(data.HorizonURL)
+
+	if out.SimRequestJSON != "" {
+		var req map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(out.SimRequestJSON), &req); err == nil {
+			delete(req, "wasm_path")
+			if raw, err := json.Marshal(req); err == nil {
+				out.SimRequestJSON = string(raw)
+			}
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil && len(home) > 1 {
+		out.SimRequestJSON = strings.ReplaceAll(out.SimRequestJSON, home, "~")
+		out.SimResponseJSON = strings.ReplaceAll(out.SimResponseJSON, home, "~")
+	}
+	return &out
+}
+
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// Share uploads data as a bundle to the storage endpoint with an HTTP PUT to
+// <endpoint>/<id>.erst and returns the URL it can be fetched from: the "url"
+// of a JSON reply, a URL given as the plain-text reply or Location header,
+// or else the upload URL itself. token, when set, is sent as a bearer token.
+func Share(ctx context.Context, client *http.Client, endpoint, token string, data *SessionData) (string, error) {
+	var bundle bytes.Buffer
+	if err := ExportBundle(&bundle, data); err != nil {
+		return "", err
+	}
+	target := strings.TrimRight(endpoint, "/") + "/" + url.PathEscape(data.ID) + BundleExtension
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, &bundle)
+	if err != nil {
+		return "", fmt.Errorf("invalid share endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxShareResponseSize))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("upload failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var reply struct {
+		URL string `json:"url"`
+	}
+	if json.Unmarshal(body, &reply) == nil && isHTTPURL(reply.URL) {
+		return reply.URL, nil
+	}
+	if text := strings.TrimSpace(string(body)); isHTTPURL(text) {
+		return text, nil
+	}
+	if location := resp.Header.Get("Location"); location != "" {
+		if u, err := resp.Request.URL.Parse(location); err == nil {
+			return u.String(), nil
+		}
+	}
+	return target, nil
+}
+
+// ShareURL resolves what 'erst import --from-url' is given: a URL is used as
+// is, and anything else is taken as a share ID under endpoint
+func ShareURL(endpoint, ref string) (string, error) {
+	if isHTTPURL(ref) {
+		return ref, nil
+	}
+	if endpoint == "" {
+		return "", fmt.Errorf("%q is not a URL, and no share endpoint is configured to look it up in", ref)
+	}
+	if !strings.HasSuffix(ref, BundleExtension) {
+		ref += BundleExtension
+	}
+	return strings.TrimRight(endpoint, "/") + "/" + url.PathEscape(ref), nil
+}
+
+// Fetch downloads and reads back a bundle uploaded with Share
+func Fetch(ctx context.Context, client *http.Client, bundleURL, token string) (*SessionData, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bundleURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle URL: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed: %s", resp.Status)
+	}
+	return ImportBundle(resp.Body)
+}
+
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
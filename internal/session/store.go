@@ -10,10 +10,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/errors"
 	"github.com/dotandev/hintents/internal/logger"
 	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/telemetry"
+	_ "github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	_ "modernc.org/sqlite"
 )
 
@@ -48,26 +56,120 @@ type SessionData struct {
 	// Metadata
 	ErstVersion   string `json:"erst_version"`
 	SchemaVersion int    `json:"schema_version"`
+
+	// Annotations, managed with AddTags, RemoveTags and AddNote
+	Tags  []string `json:"tags,omitempty"`
+	Notes []Note   `json:"notes,omitempty"`
+}
+
+// Note is a free-form annotation attached to a session
+type Note struct {
+	CreatedAt time.Time `json:"created_at"`
+	Text      string    `json:"text"`
+}
+
+// Store persists debug sessions. SQLite keeps a private database under
+// ~/.erst; Postgres lets a team share one central session history.
+type Store interface {
+	Save(ctx context.Context, data *SessionData) error
+	Load(ctx context.Context, sessionID string) (*SessionData, error)
+	List(ctx context.Context, limit int) ([]*SessionData, error)
+	Search(ctx context.Context, filter SearchFilter) ([]*SessionData, error)
+	Count(ctx context.Context, filter SearchFilter) (int, error)
+	Delete(ctx context.Context, sessionID string) error
+	Cleanup(ctx context.Context, ttl time.Duration, maxSessions int) error
+	Prune(ctx context.Context, policy RetentionPolicy) (*PruneResult, error)
+	Compact(ctx context.Context) error
+	RemoveBackups(ctx context.Context) ([]string, error)
+	Stats(ctx context.Context) (*Stats, error)
+	History(ctx context.Context, filter HistoryFilter) (*HistoryStats, error)
+
+	AddTags(ctx context.Context, sessionID string, tags ...string) error
+	RemoveTags(ctx context.Context, sessionID string, tags ...string) error
+	AddNote(ctx context.Context, sessionID, text string) error
+	Import(ctx context.Context, data *SessionData) error
+
+	Close() error
 }
 
-// Store manages session persistence in SQLite
-type Store struct {
-	db *sql.DB
+// sqlStore implements Store on a database/sql connection
+type sqlStore struct {
+	db      *sql.DB
+	dialect dialect
+	// sealer encrypts the transaction and simulator columns; nil when
+	// session encryption is off
+	sealer *sealer
+	// path is the SQLite database file, where migrations back it up
+	path string
 }
 
-// NewStore creates or opens the session database
-func NewStore() (*Store, error) {
-	homeDir, err := os.UserHomeDir()
+// dbPathOverride is the session database given with --db
+var dbPathOverride string
+
+// SetDBPath makes NewStore open the database at path, a SQLite file or
+// Postgres connection URL, in place of session_db_url. An empty path
+// restores the configured location.
+func SetDBPath(path string) {
+	dbPathOverride = path
+}
+
+// NewStore opens the session store selected by session_store in config,
+// defaulting to SQLite at ~/.erst/sessions.db, encrypting sessions when
+// ERST_SESSION_KEY or session_encryption is set
+func NewStore() (Store, error) {
+	cfg, err := config.Load()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+	sealer, err := sessionSealer(cfg.SessionEncryption)
+	if err != nil {
+		return nil, err
+	}
+	dsn := cfg.SessionDBURL
+	if dbPathOverride != "" {
+		dsn = dbPathOverride
+	}
+	return openStore(cfg.SessionStore, dsn, sealer)
+}
+
+// OpenStore opens a session store for backend ("sqlite" or "postgres"). For
+// SQLite dsn is the database file and defaults to ~/.erst/sessions.db; for
+// Postgres it is a connection URL.
+func OpenStore(backend, dsn string) (Store, error) {
+	return openStore(backend, dsn, nil)
+}
+
+func openStore(backend, dsn string, sealer *sealer) (Store, error) {
+	switch strings.ToLower(backend) {
+	case "", BackendSQLite:
+		return openSQLite(dsn, sealer)
+	case BackendPostgres:
+		return openPostgres(dsn, sealer)
+	default:
+		return nil, fmt.Errorf("unknown session store %q (expected %s or %s)", backend, BackendSQLite, BackendPostgres)
+	}
+}
 
-	erstDir := filepath.Join(homeDir, ".erst")
-	if err := os.MkdirAll(erstDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create .erst directory: %w", err)
+func openSQLite(dbPath string, sealer *sealer) (Store, error) {
+	if dbPath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dbPath = filepath.Join(homeDir, ".erst", "sessions.db")
+	} else if rest, ok := strings.CutPrefix(dbPath, "~/"); ok {
+		// Paths from config files and environment variables are not
+		// expanded by a shell
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dbPath = filepath.Join(homeDir, rest)
 	}
 
-	dbPath := filepath.Join(erstDir, "sessions.db")
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(dbPath), err)
+	}
 
 	// Open SQLite database
 	db, err := sql.Open("sqlite", dbPath+"?_journal_mode=WAL")
@@ -75,7 +177,7 @@ func NewStore() (*Store, error) {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	store := &Store{db: db}
+	store := &sqlStore{db: db, dialect: sqliteDialect, sealer: sealer, path: dbPath}
 
 	// Initialize schema
 	if err := store.initSchema(); err != nil {
@@ -91,39 +193,54 @@ func NewStore() (*Store, error) {
 	return store, nil
 }
 
-// initSchema creates the sessions table if it doesn't exist
-func (s *Store) initSchema() error {
-	query := `
-	CREATE TABLE IF NOT EXISTS sessions (
-		id TEXT PRIMARY KEY,
-		created_at TIMESTAMP NOT NULL,
-		last_access_at TIMESTAMP NOT NULL,
-		status TEXT NOT NULL,
-		network TEXT NOT NULL,
-		horizon_url TEXT NOT NULL,
-		tx_hash TEXT NOT NULL,
-		envelope_xdr TEXT,
-		result_xdr TEXT,
-		result_meta_xdr TEXT,
-		sim_request_json TEXT,
-		sim_response_json TEXT,
-		erst_version TEXT,
-		schema_version INTEGER NOT NULL
-	);
-	
-	CREATE INDEX IF NOT EXISTS idx_last_access ON sessions(last_access_at);
-	CREATE INDEX IF NOT EXISTS idx_tx_hash ON sessions(tx_hash);
-	`
+func openPostgres(dsn string, sealer *sealer) (Store, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("session_db_url is required for the %s session store", BackendPostgres)
+	}
 
-	if _, err := s.db.Exec(query); err != nil {
-		return fmt.Errorf("failed to create schema: %w", err)
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to session database: %w", err)
 	}
 
-	return nil
+	store := &sqlStore{db: db, dialect: postgresDialect, sealer: sealer}
+	if err := store.initSchema(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+
+	return store, nil
+}
+
+// initSchema creates the session tables or migrates them to the current
+// schema
+func (s *sqlStore) initSchema() error {
+	if err := s.migrate(context.Background()); err != nil {
+		return err
+	}
+
+	if err := s.initTextIndex(context.Background()); err != nil {
+		return err
+	}
+	return s.backfillFacets(context.Background())
 }
 
 // Save persists a session to the database
-func (s *Store) Save(ctx context.Context, data *SessionData) error {
+func (s *sqlStore) Save(ctx context.Context, data *SessionData) error {
+	ctx, span := s.startSpan(ctx, "session_save", data.ID)
+	defer span.End()
+	if err := s.save(ctx, data); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func (s *sqlStore) save(ctx context.Context, data *SessionData) error {
 	if data.ID == "" {
 		return fmt.Errorf("session ID is required")
 	}
@@ -156,11 +273,19 @@ func (s *Store) Save(ctx context.Context, data *SessionData) error {
 		schema_version = excluded.schema_version
 	`
 
-	_, err := s.db.ExecContext(ctx, query,
+	sealed := make([]interface{}, 0, 5)
+	for _, field := range []string{data.EnvelopeXdr, data.ResultXdr, data.ResultMetaXdr, data.SimRequestJSON, data.SimResponseJSON} {
+		value, err := s.sealer.seal(field)
+		if err != nil {
+			return err
+		}
+		sealed = append(sealed, value)
+	}
+
+	_, err := s.exec(ctx, query,
 		data.ID, data.CreatedAt, data.LastAccessAt, data.Status,
 		data.Network, data.HorizonURL, data.TxHash,
-		data.EnvelopeXdr, data.ResultXdr, data.ResultMetaXdr,
-		data.SimRequestJSON, data.SimResponseJSON,
+		sealed[0], sealed[1], sealed[2], sealed[3], sealed[4],
 		data.ErstVersion, data.SchemaVersion,
 	)
 
@@ -168,12 +293,49 @@ func (s *Store) Save(ctx context.Context, data *SessionData) error {
 		return fmt.Errorf("failed to save session: %w", err)
 	}
 
+	if err := s.indexText(ctx, data); err != nil {
+		return err
+	}
+	if err := s.indexFacets(ctx, data); err != nil {
+		return err
+	}
+
 	logger.Logger.Debug("Session saved", "id", data.ID, "tx_hash", data.TxHash)
 	return nil
 }
 
 // Load retrieves a session by ID
-func (s *Store) Load(ctx context.Context, sessionID string) (*SessionData, error) {
+func (s *sqlStore) Load(ctx context.Context, sessionID string) (*SessionData, error) {
+	ctx, span := s.startSpan(ctx, "session_load", sessionID)
+	defer span.End()
+
+	data, err := s.get(ctx, sessionID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	// Update last_access_at on load
+	data.LastAccessAt = time.Now()
+	updateQuery := `UPDATE sessions SET last_access_at = ? WHERE id = ?`
+	if _, err := s.exec(ctx, updateQuery, data.LastAccessAt, sessionID); err != nil {
+		logger.Logger.Warn("Failed to update last_access_at", "error", err)
+	}
+
+	return data, nil
+}
+
+func (s *sqlStore) startSpan(ctx context.Context, name, sessionID string) (context.Context, oteltrace.Span) {
+	ctx, span := telemetry.GetTracer().Start(ctx, name)
+	span.SetAttributes(
+		attribute.String("db.system", s.dialect.name),
+		attribute.String("session.id", sessionID),
+	)
+	return ctx, span
+}
+
+// get reads a session and its annotations without touching last_access_at
+func (s *sqlStore) get(ctx context.Context, sessionID string) (*SessionData, error) {
 	query := `
 	SELECT id, created_at, last_access_at, status, network, horizon_url, tx_hash,
 	       envelope_xdr, result_xdr, result_meta_xdr,
@@ -185,7 +347,7 @@ func (s *Store) Load(ctx context.Context, sessionID string) (*SessionData, error
 	var data SessionData
 	var createdAt, lastAccessAt string
 
-	err := s.db.QueryRowContext(ctx, query, sessionID).Scan(
+	err := s.queryRow(ctx, query, sessionID).Scan(
 		&data.ID, &createdAt, &lastAccessAt, &data.Status,
 		&data.Network, &data.HorizonURL, &data.TxHash,
 		&data.EnvelopeXdr, &data.ResultXdr, &data.ResultMetaXdr,
@@ -194,11 +356,14 @@ func (s *Store) Load(ctx context.Context, sessionID string) (*SessionData, error
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("session not found: %s", sessionID)
+		return nil, errors.WrapSessionNotFound(sessionID)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to load session: %w", err)
 	}
+	if err := s.decrypt(&data); err != nil {
+		return nil, fmt.Errorf("failed to load session %s: %w", sessionID, err)
+	}
 
 	// Parse timestamps
 	if data.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
@@ -208,18 +373,15 @@ func (s *Store) Load(ctx context.Context, sessionID string) (*SessionData, error
 		return nil, fmt.Errorf("failed to parse last_access_at: %w", err)
 	}
 
-	// Update last_access_at on load
-	data.LastAccessAt = time.Now()
-	updateQuery := `UPDATE sessions SET last_access_at = ? WHERE id = ?`
-	if _, err := s.db.ExecContext(ctx, updateQuery, data.LastAccessAt, sessionID); err != nil {
-		logger.Logger.Warn("Failed to update last_access_at", "error", err)
+	if err := s.loadAnnotations(ctx, &data); err != nil {
+		return nil, err
 	}
 
 	return &data, nil
 }
 
 // List returns recent sessions, ordered by last_access_at descending
-func (s *Store) List(ctx context.Context, limit int) ([]*SessionData, error) {
+func (s *sqlStore) List(ctx context.Context, limit int) ([]*SessionData, error) {
 	if limit <= 0 {
 		limit = 50
 	}
@@ -233,7 +395,7 @@ func (s *Store) List(ctx context.Context, limit int) ([]*SessionData, error) {
 	LIMIT ?
 	`
 
-	rows, err := s.db.QueryContext(ctx, query, limit)
+	rows, err := s.query(ctx, query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list sessions: %w", err)
 	}
@@ -254,6 +416,9 @@ func (s *Store) List(ctx context.Context, limit int) ([]*SessionData, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan session: %w", err)
 		}
+		if err := s.decrypt(&data); err != nil {
+			return nil, fmt.Errorf("failed to load session %s: %w", data.ID, err)
+		}
 
 		// Parse timestamps
 		if data.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
@@ -269,14 +434,26 @@ func (s *Store) List(ctx context.Context, limit int) ([]*SessionData, error) {
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating sessions: %w", err)
 	}
+	rows.Close()
+
+	for _, data := range sessions {
+		if err := s.loadAnnotations(ctx, data); err != nil {
+			return nil, err
+		}
+	}
 
 	return sessions, nil
 }
 
+// decrypt opens the encrypted columns of a session read from the database
+func (s *sqlStore) decrypt(data *SessionData) error {
+	return s.sealer.openAll(&data.EnvelopeXdr, &data.ResultXdr, &data.ResultMetaXdr, &data.SimRequestJSON, &data.SimResponseJSON)
+}
+
 // Delete removes a session by ID
-func (s *Store) Delete(ctx context.Context, sessionID string) error {
+func (s *sqlStore) Delete(ctx context.Context, sessionID string) error {
 	query := `DELETE FROM sessions WHERE id = ?`
-	result, err := s.db.ExecContext(ctx, query, sessionID)
+	result, err := s.exec(ctx, query, sessionID)
 	if err != nil {
 		return fmt.Errorf("failed to delete session: %w", err)
 	}
@@ -287,7 +464,11 @@ func (s *Store) Delete(ctx context.Context, sessionID string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("session not found: %s", sessionID)
+		return errors.WrapSessionNotFound(sessionID)
+	}
+
+	if err := s.deleteAnnotations(ctx); err != nil {
+		return err
 	}
 
 	logger.Logger.Debug("Session deleted", "id", sessionID)
@@ -295,57 +476,171 @@ func (s *Store) Delete(ctx context.Context, sessionID string) error {
 }
 
 // Cleanup removes expired sessions and enforces max session limit
-func (s *Store) Cleanup(ctx context.Context, ttl time.Duration, maxSessions int) error {
-	now := time.Now()
-	cutoff := now.Add(-ttl)
+func (s *sqlStore) Cleanup(ctx context.Context, ttl time.Duration, maxSessions int) error {
+	_, err := s.Prune(ctx, RetentionPolicy{MaxAge: ttl, MaxSessions: maxSessions})
+	return err
+}
+
+// NormalizeTag trims and lower-cases a tag, rejecting tags that are empty or
+// contain whitespace or commas
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", fmt.Errorf("tag must not be empty")
+	}
+	if strings.ContainsAny(tag, " \t\r\n,") {
+		return "", fmt.Errorf("invalid tag %q: tags must not contain whitespace or commas", tag)
+	}
+	return tag, nil
+}
+
+// AddTags attaches tags to a session. Tags already on the session are ignored.
+func (s *sqlStore) AddTags(ctx context.Context, sessionID string, tags ...string) error {
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	if err := s.requireSession(ctx, sessionID); err != nil {
+		return err
+	}
+
+	for _, tag := range normalized {
+		query := `INSERT INTO session_tags (session_id, tag) VALUES (?, ?) ON CONFLICT DO NOTHING`
+		if _, err := s.exec(ctx, query, sessionID, tag); err != nil {
+			return fmt.Errorf("failed to tag session: %w", err)
+		}
+	}
+	return nil
+}
+
+// RemoveTags detaches tags from a session
+func (s *sqlStore) RemoveTags(ctx context.Context, sessionID string, tags ...string) error {
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	if err := s.requireSession(ctx, sessionID); err != nil {
+		return err
+	}
+
+	for _, tag := range normalized {
+		query := `DELETE FROM session_tags WHERE session_id = ? AND tag = ?`
+		if _, err := s.exec(ctx, query, sessionID, tag); err != nil {
+			return fmt.Errorf("failed to untag session: %w", err)
+		}
+	}
+	return nil
+}
+
+// AddNote appends a free-form note to a session
+func (s *sqlStore) AddNote(ctx context.Context, sessionID, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("note must not be empty")
+	}
+	if err := s.requireSession(ctx, sessionID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO session_notes (session_id, created_at, text) VALUES (?, ?, ?)`
+	if _, err := s.exec(ctx, query, sessionID, time.Now(), text); err != nil {
+		return fmt.Errorf("failed to add note: %w", err)
+	}
+	return nil
+}
 
-	// Delete expired sessions
-	deleteExpired := `DELETE FROM sessions WHERE last_access_at < ?`
-	result, err := s.db.ExecContext(ctx, deleteExpired, cutoff)
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("at least one tag is required")
+	}
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		t, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, t)
+	}
+	return normalized, nil
+}
+
+func (s *sqlStore) requireSession(ctx context.Context, sessionID string) error {
+	var exists int
+	err := s.queryRow(ctx, `SELECT 1 FROM sessions WHERE id = ?`, sessionID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return errors.WrapSessionNotFound(sessionID)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to delete expired sessions: %w", err)
+		return fmt.Errorf("failed to look up session: %w", err)
 	}
+	return nil
+}
 
-	expiredCount, _ := result.RowsAffected()
-	if expiredCount > 0 {
-		logger.Logger.Debug("Cleaned up expired sessions", "count", expiredCount)
+// loadAnnotations fills in the tags and notes of a session
+func (s *sqlStore) loadAnnotations(ctx context.Context, data *SessionData) error {
+	rows, err := s.query(ctx, `SELECT tag FROM session_tags WHERE session_id = ?`, data.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load tags: %w", err)
 	}
+	defer rows.Close()
 
-	// Enforce max sessions limit
-	if maxSessions > 0 {
-		countQuery := `SELECT COUNT(*) FROM sessions`
-		var count int
-		if err := s.db.QueryRowContext(ctx, countQuery).Scan(&count); err != nil {
-			return fmt.Errorf("failed to count sessions: %w", err)
+	data.Tags = nil
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return fmt.Errorf("failed to scan tag: %w", err)
 		}
+		data.Tags = append(data.Tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating tags: %w", err)
+	}
+	sort.Strings(data.Tags)
 
-		if count > maxSessions {
-			excess := count - maxSessions
-			deleteOldest := `
-				DELETE FROM sessions
-				WHERE id IN (
-					SELECT id FROM sessions
-					ORDER BY last_access_at ASC
-					LIMIT ?
-				)
-			`
-			result, err := s.db.ExecContext(ctx, deleteOldest, excess)
-			if err != nil {
-				return fmt.Errorf("failed to delete oldest sessions: %w", err)
-			}
-
-			deletedCount, _ := result.RowsAffected()
-			if deletedCount > 0 {
-				logger.Logger.Debug("Cleaned up excess sessions", "count", deletedCount)
-			}
+	noteRows, err := s.query(ctx,
+		`SELECT created_at, text FROM session_notes WHERE session_id = ? ORDER BY created_at, id`, data.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load notes: %w", err)
+	}
+	defer noteRows.Close()
+
+	data.Notes = nil
+	for noteRows.Next() {
+		var note Note
+		var createdAt string
+		if err := noteRows.Scan(&createdAt, &note.Text); err != nil {
+			return fmt.Errorf("failed to scan note: %w", err)
+		}
+		if note.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return fmt.Errorf("failed to parse note created_at: %w", err)
 		}
+		data.Notes = append(data.Notes, note)
 	}
+	if err := noteRows.Err(); err != nil {
+		return fmt.Errorf("error iterating notes: %w", err)
+	}
+
+	return nil
+}
 
+// deleteAnnotations removes tags, notes, search index and facet rows whose
+// session no longer exists
+func (s *sqlStore) deleteAnnotations(ctx context.Context) error {
+	for _, query := range []string{
+		`DELETE FROM session_tags WHERE session_id NOT IN (SELECT id FROM sessions)`,
+		`DELETE FROM session_notes WHERE session_id NOT IN (SELECT id FROM sessions)`,
+		`DELETE FROM session_search WHERE session_id NOT IN (SELECT id FROM sessions)`,
+		`DELETE FROM session_facets WHERE session_id NOT IN (SELECT id FROM sessions)`,
+	} {
+		if _, err := s.exec(ctx, query); err != nil {
+			return fmt.Errorf("failed to delete session annotations: %w", err)
+		}
+	}
 	return nil
 }
 
 // Close closes the database connection
-func (s *Store) Close() error {
+func (s *sqlStore) Close() error {
 	return s.db.Close()
 }
 
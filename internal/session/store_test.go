@@ -0,0 +1,473 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestStore(t *testing.T) Store {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestStore_TagsAndNotes(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	if err := store.Save(ctx, &SessionData{ID: "s1", Status: "saved", Network: "testnet", TxHash: "abc"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := store.AddTags(ctx, "s1", "Incident-42", "frontend", "frontend"); err != nil {
+		t.Fatalf("AddTags: %v", err)
+	}
+	if err := store.AddNote(ctx, "s1", "  fails only with the new router  "); err != nil {
+		t.Fatalf("AddNote: %v", err)
+	}
+
+	data, err := store.Load(ctx, "s1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(data.Tags) != 2 || data.Tags[0] != "frontend" || data.Tags[1] != "incident-42" {
+		t.Errorf("unexpected tags: %v", data.Tags)
+	}
+	if len(data.Notes) != 1 || data.Notes[0].Text != "fails only with the new router" {
+		t.Errorf("unexpected notes: %v", data.Notes)
+	}
+
+	if err := store.RemoveTags(ctx, "s1", "frontend"); err != nil {
+		t.Fatalf("RemoveTags: %v", err)
+	}
+	sessions, err := store.List(ctx, 10)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(sessions) != 1 || len(sessions[0].Tags) != 1 || sessions[0].Tags[0] != "incident-42" {
+		t.Errorf("unexpected listed sessions: %+v", sessions)
+	}
+
+	if err := store.Delete(ctx, "s1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	var count int
+	if err := store.(*sqlStore).db.QueryRow(`SELECT COUNT(*) FROM session_tags`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("tags survived session deletion: %d", count)
+	}
+}
+
+func TestStore_AnnotateUnknownSession(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	if err := store.AddTags(ctx, "missing", "x"); err == nil {
+		t.Error("expected error tagging an unknown session")
+	}
+	if err := store.AddNote(ctx, "missing", "note"); err == nil {
+		t.Error("expected error annotating an unknown session")
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	for _, bad := range []string{"", "  ", "two words", "a,b"} {
+		if _, err := NormalizeTag(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+	if got, err := NormalizeTag(" Incident-42 "); err != nil || got != "incident-42" {
+		t.Errorf("NormalizeTag = %q, %v", got, err)
+	}
+}
+
+func TestStore_Search(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	sessions := []*SessionData{
+		{ID: "a", Status: "saved", Network: "testnet", TxHash: "tx-a",
+			SimResponseJSON: `{"status":"error","error":"insufficient balance","events":["transfer"]}`},
+		{ID: "b", Status: "saved", Network: "testnet", TxHash: "tx-b",
+			SimResponseJSON: `{"status":"error","error":"panic in contract","events":["mint"]}`},
+	}
+	for _, s := range sessions {
+		if err := store.Save(ctx, s); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if err := store.AddTags(ctx, "b", "incident-42", "frontend"); err != nil {
+		t.Fatalf("AddTags: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		filter SearchFilter
+		want   []string
+	}{
+		{"tag", SearchFilter{Tags: []string{"Incident-42"}}, []string{"b"}},
+		{"all tags must match", SearchFilter{Tags: []string{"incident-42", "backend"}}, nil},
+		{"tx", SearchFilter{TxHash: "tx-a"}, []string{"a"}},
+		{"error", SearchFilter{ErrorRegex: "balance"}, []string{"a"}},
+		{"event", SearchFilter{EventRegex: "mint|burn"}, []string{"b"}},
+		{"tag and error", SearchFilter{Tags: []string{"frontend"}, ErrorRegex: "balance"}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := store.Search(ctx, tc.filter)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			var ids []string
+			for _, r := range results {
+				ids = append(ids, r.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("got %v, want %v", ids, tc.want)
+			}
+		})
+	}
+}
+
+func TestStore_SearchPaging(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	// Saved oldest first, so they are listed s4 to s0
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		resp := `{"status":"success"}`
+		if i%2 == 0 {
+			resp = `{"status":"error","error":"panic in contract"}`
+		}
+		data := &SessionData{ID: "s" + strconv.Itoa(i), Status: "saved", Network: "testnet", TxHash: "tx",
+			CreatedAt: now.Add(time.Duration(i-5) * 24 * time.Hour), SimResponseJSON: resp}
+		if err := store.Save(ctx, data); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	cases := []struct {
+		name   string
+		filter SearchFilter
+		want   string
+	}{
+		{"limit", SearchFilter{Limit: 2}, "s4,s3"},
+		{"offset", SearchFilter{Limit: 2, Offset: 2}, "s2,s1"},
+		{"offset past the end", SearchFilter{Offset: 5}, ""},
+		{"since", SearchFilter{Since: now.Add(-3 * 24 * time.Hour)}, "s4,s3,s2"},
+		{"until", SearchFilter{Until: now.Add(-3 * 24 * time.Hour)}, "s1,s0"},
+		{"range", SearchFilter{Since: now.Add(-4*24*time.Hour - time.Hour), Until: now.Add(-2 * 24 * time.Hour)}, "s2,s1"},
+		{"pattern with offset", SearchFilter{ErrorRegex: "panic", Limit: 1, Offset: 1}, "s2"},
+		{"pattern with range", SearchFilter{ErrorRegex: "panic", Until: now.Add(-3 * 24 * time.Hour)}, "s0"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := store.Search(ctx, tc.filter)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			var ids []string
+			for _, r := range results {
+				ids = append(ids, r.ID)
+			}
+			if got := strings.Join(ids, ","); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := store.Search(ctx, SearchFilter{Offset: -1}); err == nil {
+		t.Error("expected a negative offset to fail")
+	}
+}
+
+func TestStore_SearchSortAndCount(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	first, second := xdr.ContractId{1}, xdr.ContractId{2}
+	now := time.Now()
+	sessions := []*SessionData{
+		{ID: "a", Network: "testnet", EnvelopeXdr: invokeEnvelope(t, first),
+			SimResponseJSON: `{"status":"error","error":"b error"}`},
+		{ID: "b", Network: "mainnet", EnvelopeXdr: invokeEnvelope(t, second),
+			SimResponseJSON: `{"status":"error","error":"a error"}`},
+		{ID: "c", Network: "futurenet", SimResponseJSON: `{"status":"success"}`},
+	}
+	for i, data := range sessions {
+		data.Status = "saved"
+		data.CreatedAt = now.Add(time.Duration(i) * time.Hour)
+		if err := store.Save(ctx, data); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	firstID, _ := strkey.Encode(strkey.VersionByteContract, first[:])
+	secondID, _ := strkey.Encode(strkey.VersionByteContract, second[:])
+	byContract := "c,a,b"
+	if secondID < firstID {
+		byContract = "c,b,a"
+	}
+
+	cases := []struct {
+		name   string
+		filter SearchFilter
+		want   string
+	}{
+		{"timestamp", SearchFilter{Sort: SortTimestamp}, "a,b,c"},
+		{"timestamp descending", SearchFilter{Sort: SortTimestamp, Descending: true}, "c,b,a"},
+		{"network", SearchFilter{Sort: SortNetwork}, "c,b,a"},
+		{"error", SearchFilter{Sort: SortError}, "c,b,a"},
+		{"error descending", SearchFilter{Sort: SortError, Descending: true, Limit: 2}, "a,b"},
+		{"contract", SearchFilter{Sort: SortContract}, byContract},
+		{"sorted page with pattern", SearchFilter{Sort: SortNetwork, ErrorRegex: "error", Offset: 1}, "a"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := store.Search(ctx, tc.filter)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			var ids []string
+			for _, r := range results {
+				ids = append(ids, r.ID)
+			}
+			if got := strings.Join(ids, ","); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+	if _, err := store.Search(ctx, SearchFilter{Sort: "size"}); err == nil {
+		t.Error("expected an unknown sort to fail")
+	}
+
+	for filter, want := range map[*SearchFilter]int{
+		{}:                            3,
+		{Limit: 1, Offset: 1}:         3,
+		{ErrorRegex: "error"}:         2,
+		{Since: now.Add(time.Minute)}: 2,
+	} {
+		got, err := store.Count(ctx, *filter)
+		if err != nil {
+			t.Fatalf("Count: %v", err)
+		}
+		if got != want {
+			t.Errorf("Count(%+v) = %d, want %d", *filter, got, want)
+		}
+	}
+
+	// Sessions saved before the facets existed are backfilled on open
+	if _, err := store.(*sqlStore).exec(ctx, `DELETE FROM session_facets`); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.(*sqlStore).backfillFacets(ctx); err != nil {
+		t.Fatalf("backfillFacets: %v", err)
+	}
+	results, err := store.Search(ctx, SearchFilter{Sort: SortError, Descending: true, Limit: 1})
+	if err != nil || len(results) != 1 || results[0].ID != "a" {
+		t.Errorf("sort after backfill = %v, %v", results, err)
+	}
+}
+
+func TestOpenStore_Backends(t *testing.T) {
+	store, err := OpenStore(BackendSQLite, filepath.Join(t.TempDir(), "team.db"))
+	if err != nil {
+		t.Fatalf("OpenStore(sqlite): %v", err)
+	}
+	store.Close()
+
+	if _, err := OpenStore(BackendPostgres, ""); err == nil {
+		t.Error("expected postgres without session_db_url to fail")
+	}
+	if _, err := OpenStore("mysql", ""); err == nil {
+		t.Error("expected unknown backend to fail")
+	}
+}
+
+func TestNewStore_DBPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ERST_DB_PATH", "")
+
+	SetDBPath("~/project/sessions.db")
+	defer SetDBPath("")
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	store.Close()
+	if _, err := os.Stat(filepath.Join(home, "project", "sessions.db")); err != nil {
+		t.Errorf("database not created at the --db path: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".erst", "sessions.db")); err == nil {
+		t.Error("default database created despite --db")
+	}
+}
+
+func TestDialectRebind(t *testing.T) {
+	query := "SELECT id FROM sessions WHERE tx_hash = ? AND id IN (SELECT session_id FROM session_tags WHERE tag = ?)"
+	if got := sqliteDialect.rebind(query); got != query {
+		t.Errorf("sqlite rebind changed query: %s", got)
+	}
+	want := "SELECT id FROM sessions WHERE tx_hash = $1 AND id IN (SELECT session_id FROM session_tags WHERE tag = $2)"
+	if got := postgresDialect.rebind(query); got != want {
+		t.Errorf("postgres rebind = %s", got)
+	}
+}
+
+// TestPostgresStore runs against a real database when ERST_TEST_POSTGRES_URL
+// is set, e.g. postgres://postgres@localhost/erst_test?sslmode=disable
+func TestPostgresStore(t *testing.T) {
+	dsn := os.Getenv("ERST_TEST_POSTGRES_URL")
+	if dsn == "" {
+		t.Skip("ERST_TEST_POSTGRES_URL not set")
+	}
+	ctx := context.Background()
+	store, err := OpenStore(BackendPostgres, dsn)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	defer store.Close()
+
+	id := "pg-test-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := store.Save(ctx, &SessionData{ID: id, Status: "saved", Network: "testnet", TxHash: "abc",
+		SimResponseJSON: `{"status":"error","logs":["wasm trap: unreachable"]}`}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	defer store.Delete(ctx, id)
+
+	if err := store.AddTags(ctx, id, "shared", "shared"); err != nil {
+		t.Fatalf("AddTags: %v", err)
+	}
+	if err := store.AddNote(ctx, id, "from postgres"); err != nil {
+		t.Fatalf("AddNote: %v", err)
+	}
+	results, err := store.Search(ctx, SearchFilter{TxHash: "abc", Tags: []string{"shared"}, Text: "trap: unreachable"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	found := false
+	for _, r := range results {
+		if r.ID == id {
+			found = len(r.Tags) == 1 && len(r.Notes) == 1
+		}
+	}
+	if !found {
+		t.Errorf("tagged session not found in %+v", results)
+	}
+}
+
+func TestStore_FullTextSearch(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	store, err := OpenStore(BackendSQLite, path)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+
+	sessions := []*SessionData{
+		{ID: "trap", Status: "saved", Network: "testnet", TxHash: "tx-1",
+			SimResponseJSON: `{"status":"error","error":"HostError: Error(WasmVm, InvalidAction)","logs":["wasm trap: unreachable"]}`},
+		{ID: "auth", Status: "saved", Network: "testnet", TxHash: "tx-2",
+			SimResponseJSON: `{"status":"error","diagnostic_events":[{"event_type":"diagnostic","topics":["error","auth"],"data":"missing signature"}]}`},
+	}
+	for _, s := range sessions {
+		if err := store.Save(ctx, s); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	search := func(store Store, text string) []string {
+		t.Helper()
+		results, err := store.Search(ctx, SearchFilter{Text: text})
+		if err != nil {
+			t.Fatalf("Search(%q): %v", text, err)
+		}
+		var ids []string
+		for _, r := range results {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	if got := search(store, "trap: unreachable"); strings.Join(got, ",") != "trap" {
+		t.Errorf("log phrase: got %v", got)
+	}
+	if got := search(store, "missing signature"); strings.Join(got, ",") != "auth" {
+		t.Errorf("event phrase: got %v", got)
+	}
+	if got := search(store, `unreachable "quoted`); len(got) != 0 {
+		t.Errorf("unexpected match: %v", got)
+	}
+
+	// Re-saving replaces the indexed text
+	sessions[0].SimResponseJSON = `{"status":"success"}`
+	if err := store.Save(ctx, sessions[0]); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got := search(store, "unreachable"); len(got) != 0 {
+		t.Errorf("stale index entry: %v", got)
+	}
+
+	// A database from before the index existed is backfilled on open
+	if _, err := store.(*sqlStore).db.Exec(`DROP TABLE session_search`); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+	store, err = OpenStore(BackendSQLite, path)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	defer store.Close()
+	if got := search(store, "missing signature"); strings.Join(got, ",") != "auth" {
+		t.Errorf("backfilled index: got %v", got)
+	}
+}
+
+func TestStore_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	store := newTestStore(t)
+	ctx := context.Background()
+	if err := store.Save(ctx, &SessionData{ID: "traced", Network: "testnet", TxHash: "abc"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := store.Load(ctx, "missing"); err == nil {
+		t.Fatal("Load of an unknown session should fail")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Name() != "session_save" || spans[1].Name() != "session_load" {
+		t.Fatalf("unexpected spans: %v", spans)
+	}
+	if len(spans[0].Events()) != 0 {
+		t.Error("successful save should not record an error")
+	}
+	if len(spans[1].Events()) != 1 || spans[1].Events()[0].Name != "exception" {
+		t.Errorf("failed load should record its error, got %v", spans[1].Events())
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// initTextIndex creates the full-text index. When the index is new, sessions
+// saved before it existed are indexed so search covers the whole history.
+func (s *sqlStore) initTextIndex(ctx context.Context) error {
+	var existing int
+	if err := s.queryRow(ctx, s.dialect.hasTextIndex).Scan(&existing); err != nil {
+		return fmt.Errorf("failed to check search index: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, s.dialect.textSchema); err != nil {
+		return fmt.Errorf("failed to create search index: %w", err)
+	}
+	if existing > 0 {
+		return nil
+	}
+	return s.backfillTextIndex(ctx)
+}
+
+func (s *sqlStore) backfillTextIndex(ctx context.Context) error {
+	if s.sealer != nil {
+		return nil
+	}
+	rows, err := s.query(ctx, `SELECT id, sim_response_json FROM sessions`)
+	if err != nil {
+		return fmt.Errorf("failed to read sessions for search index: %w", err)
+	}
+	var pending []*SessionData
+	for rows.Next() {
+		var data SessionData
+		var response *string
+		if err := rows.Scan(&data.ID, &response); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan session: %w", err)
+		}
+		if response != nil {
+			data.SimResponseJSON = *response
+		}
+		pending = append(pending, &data)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating sessions: %w", err)
+	}
+
+	for _, data := range pending {
+		if err := s.indexText(ctx, data); err != nil {
+			return err
+		}
+	}
+	if len(pending) > 0 {
+		logger.Logger.Debug("Indexed existing sessions for full-text search", "count", len(pending))
+	}
+	return nil
+}
+
+// indexText replaces the search index row of a session. The index would
+// hold errors and logs in plain text, so encrypted sessions are left out.
+func (s *sqlStore) indexText(ctx context.Context, data *SessionData) error {
+	if _, err := s.exec(ctx, `DELETE FROM session_search WHERE session_id = ?`, data.ID); err != nil {
+		return fmt.Errorf("failed to update search index: %w", err)
+	}
+	body := searchableText(data)
+	if body == "" || s.sealer != nil {
+		return nil
+	}
+	if _, err := s.exec(ctx, `INSERT INTO session_search (session_id, body) VALUES (?, ?)`, data.ID, body); err != nil {
+		return fmt.Errorf("failed to update search index: %w", err)
+	}
+	return nil
+}
+
+// searchableText collects the error, events and log lines of a session's
+// simulator response, one per line
+func searchableText(data *SessionData) string {
+	resp, err := data.ToSimulationResponse()
+	if err != nil {
+		return ""
+	}
+
+	var lines []string
+	if resp.Error != "" {
+		lines = append(lines, resp.Error)
+	}
+	lines = append(lines, resp.Events...)
+	for _, e := range resp.DiagnosticEvents {
+		parts := append([]string{e.EventType}, e.Topics...)
+		if e.ContractID != nil {
+			parts = append(parts, *e.ContractID)
+		}
+		lines = append(lines, strings.Join(append(parts, e.Data), " "))
+	}
+	lines = append(lines, resp.Logs...)
+	return strings.Join(lines, "\n")
+}
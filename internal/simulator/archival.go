@@ -0,0 +1,243 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// LowTTLThreshold is how many ledgers of remaining TTL (about a day) make an
+// entry worth extending
+const LowTTLThreshold = 17280
+
+// TTL status of a footprint entry
+const (
+	TTLLive     = "live"
+	TTLExpiring = "expiring" // live, but within LowTTLThreshold of archival
+	TTLArchived = "archived" // persistent entry past its TTL; can be restored
+	TTLExpired  = "expired"  // temporary entry past its TTL; gone for good
+	TTLMissing  = "missing"  // no entry in ledger state
+)
+
+// ArchivalReport describes the TTLs of the Soroban entries in a transaction's
+// footprint, and what would be needed to bring archived ones back
+type ArchivalReport struct {
+	LedgerSequence uint32     `json:"ledger_sequence"`
+	Entries        []EntryTTL `json:"entries"`
+	// CausedFailure is set when the simulation failed and a footprint entry
+	// was archived or expired, or a read-only one was missing
+	CausedFailure bool `json:"caused_failure"`
+	// Restore lists the archived entries a RestoreFootprint operation must
+	// put in its read-write footprint
+	Restore *FootprintOperation `json:"restore,omitempty"`
+	// Extend lists the entries an ExtendFootprintTTL operation should cover
+	Extend *FootprintOperation `json:"extend,omitempty"`
+}
+
+// EntryTTL is the TTL state of one footprint entry
+type EntryTTL struct {
+	Key             string `json:"key"` // base64 LedgerKey
+	Type            string `json:"type"`
+	Durability      string `json:"durability,omitempty"`
+	ReadWrite       bool   `json:"read_write"`
+	LiveUntilLedger uint32 `json:"live_until_ledger,omitempty"`
+	// LedgersLeft is negative once the entry is past its TTL
+	LedgersLeft int64  `json:"ledgers_left"`
+	Status      string `json:"status"`
+}
+
+// FootprintOperation is a suggested RestoreFootprint or ExtendFootprintTTL
+// operation
+type FootprintOperation struct {
+	Operation string   `json:"operation"`
+	Keys      []string `json:"keys"`
+	// ExtendTo is the TTL, in ledgers, for ExtendFootprintTTL
+	ExtendTo uint32 `json:"extend_to,omitempty"`
+}
+
+// NeedsAttention reports whether any entry is archived, expired, missing or
+// close to archival
+func (r *ArchivalReport) NeedsAttention() bool {
+	for _, e := range r.Entries {
+		if e.Status != TTLLive {
+			return true
+		}
+	}
+	return false
+}
+
+// TTLKey returns the base64 LedgerKey of the TTL entry tracking keyXdr
+func TTLKey(keyXdr string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(keyXdr)
+	if err != nil {
+		return "", fmt.Errorf("invalid ledger key: %w", err)
+	}
+	hash := sha256.Sum256(raw)
+	return xdr.MarshalBase64(xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeTtl,
+		Ttl:  &xdr.LedgerKeyTtl{KeyHash: xdr.Hash(hash)},
+	})
+}
+
+// SorobanFootprintKeys returns the base64 keys of the contract data and code
+// entries in an envelope's footprint, and whether each is read-write
+func SorobanFootprintKeys(envelopeXdr string) (map[string]bool, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid transaction envelope: %w", err)
+	}
+
+	var tx *xdr.Transaction
+	switch envelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		if envelope.V1 != nil {
+			tx = &envelope.V1.Tx
+		}
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		if envelope.FeeBump != nil && envelope.FeeBump.Tx.InnerTx.V1 != nil {
+			tx = &envelope.FeeBump.Tx.InnerTx.V1.Tx
+		}
+	}
+	if tx == nil || tx.Ext.SorobanData == nil {
+		return nil, nil
+	}
+
+	keys := make(map[string]bool)
+	footprint := tx.Ext.SorobanData.Resources.Footprint
+	for _, group := range []struct {
+		keys      []xdr.LedgerKey
+		readWrite bool
+	}{{footprint.ReadOnly, false}, {footprint.ReadWrite, true}} {
+		for _, key := range group.keys {
+			if key.Type != xdr.LedgerEntryTypeContractData && key.Type != xdr.LedgerEntryTypeContractCode {
+				continue
+			}
+			encoded, err := xdr.MarshalBase64(key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode ledger key: %w", err)
+			}
+			keys[encoded] = group.readWrite
+		}
+	}
+	return keys, nil
+}
+
+// AnalyzeArchival reports the TTL of every Soroban footprint entry of req at
+// ledger seq. entries holds ledger state, TTL entries included, as base64
+// LedgerEntry or LedgerEntryData keyed by base64 LedgerKey.
+func AnalyzeArchival(req *SimulationRequest, resp *SimulationResponse, entries map[string]string, seq uint32) (*ArchivalReport, error) {
+	keys, err := SorobanFootprintKeys(req.EnvelopeXdr)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	report := &ArchivalReport{LedgerSequence: seq}
+	var restore, extend []string
+	for _, keyXdr := range sortedKeys(keys) {
+		var key xdr.LedgerKey
+		if err := xdr.SafeUnmarshalBase64(keyXdr, &key); err != nil {
+			return nil, fmt.Errorf("invalid ledger key: %w", err)
+		}
+		entry := EntryTTL{Key: keyXdr, ReadWrite: keys[keyXdr]}
+		persistent := true
+		switch key.Type {
+		case xdr.LedgerEntryTypeContractCode:
+			entry.Type = "contract_code"
+		case xdr.LedgerEntryTypeContractData:
+			entry.Type = "contract_data"
+			if key.ContractData.Durability == xdr.ContractDataDurabilityTemporary {
+				entry.Durability, persistent = "temporary", false
+			} else {
+				entry.Durability = "persistent"
+			}
+		}
+
+		ttlKey, err := TTLKey(keyXdr)
+		if err != nil {
+			return nil, err
+		}
+		liveUntil, hasTTL := decodeTTL(entries[ttlKey])
+		_, hasEntry := entries[keyXdr]
+
+		switch {
+		case !hasTTL && !hasEntry:
+			entry.Status = TTLMissing
+		case !hasTTL:
+			// State without a TTL, e.g. from an override, is taken as live
+			entry.Status = TTLLive
+		default:
+			entry.LiveUntilLedger = liveUntil
+			entry.LedgersLeft = int64(liveUntil) - int64(seq)
+			switch {
+			case entry.LedgersLeft < 0 && persistent:
+				entry.Status = TTLArchived
+				restore = append(restore, keyXdr)
+			case entry.LedgersLeft < 0:
+				entry.Status = TTLExpired
+			case entry.LedgersLeft < LowTTLThreshold:
+				entry.Status = TTLExpiring
+				extend = append(extend, keyXdr)
+			default:
+				entry.Status = TTLLive
+			}
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+
+	if len(restore) > 0 {
+		report.Restore = &FootprintOperation{Operation: "RestoreFootprint", Keys: restore}
+	}
+	if len(extend) > 0 {
+		report.Extend = &FootprintOperation{Operation: "ExtendFootprintTTL", Keys: extend, ExtendTo: LowTTLThreshold}
+	}
+	if resp != nil && resp.Status != "success" {
+		for _, e := range report.Entries {
+			// A missing read-write entry may simply be about to be created
+			if e.Status == TTLArchived || e.Status == TTLExpired || (e.Status == TTLMissing && !e.ReadWrite) {
+				report.CausedFailure = true
+				break
+			}
+		}
+	}
+	return report, nil
+}
+
+// decodeTTL returns the live-until ledger of a TTL entry
+func decodeTTL(entryXdr string) (uint32, bool) {
+	if entryXdr == "" {
+		return 0, false
+	}
+	var entry xdr.LedgerEntry
+	if err := xdr.SafeUnmarshalBase64(entryXdr, &entry); err == nil && entry.Data.Ttl != nil {
+		return uint32(entry.Data.Ttl.LiveUntilLedgerSeq), true
+	}
+	var data xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(entryXdr, &data); err == nil && data.Ttl != nil {
+		return uint32(data.Ttl.LiveUntilLedgerSeq), true
+	}
+	return 0, false
+}
+
+// sortedKeys orders read-only keys before read-write ones, each group sorted
+func sortedKeys(readWrite map[string]bool) []string {
+	keys := make([]string, 0, len(readWrite))
+	for k := range readWrite {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if readWrite[keys[i]] != readWrite[keys[j]] {
+			return !readWrite[keys[i]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
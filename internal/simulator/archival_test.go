@@ -0,0 +1,136 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func contractDataKey(t *testing.T, id byte, durability xdr.ContractDataDurability) xdr.LedgerKey {
+	t.Helper()
+	contractID := xdr.ContractId{id}
+	return xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			Durability: durability,
+		},
+	}
+}
+
+func ttlEntry(t *testing.T, key xdr.LedgerKey, liveUntil uint32) (string, string) {
+	t.Helper()
+	keyXdr, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	ttlKey, err := TTLKey(keyXdr)
+	require.NoError(t, err)
+	var decoded xdr.LedgerKey
+	require.NoError(t, xdr.SafeUnmarshalBase64(ttlKey, &decoded))
+	entry, err := xdr.MarshalBase64(xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeTtl,
+		Ttl:  &xdr.TtlEntry{KeyHash: decoded.Ttl.KeyHash, LiveUntilLedgerSeq: xdr.Uint32(liveUntil)},
+	})
+	require.NoError(t, err)
+	return ttlKey, entry
+}
+
+func TestAnalyzeArchival(t *testing.T) {
+	live := contractDataKey(t, 1, xdr.ContractDataDurabilityPersistent)
+	archived := contractDataKey(t, 2, xdr.ContractDataDurabilityPersistent)
+	expired := contractDataKey(t, 3, xdr.ContractDataDurabilityTemporary)
+	expiring := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{4}}}
+	created := contractDataKey(t, 5, xdr.ContractDataDurabilityPersistent)
+	account := xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7")}}
+
+	envelope, err := xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"),
+			Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+				Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{
+					ReadOnly:  []xdr.LedgerKey{live, expiring, account},
+					ReadWrite: []xdr.LedgerKey{archived, expired, created},
+				}},
+			}},
+		}},
+	})
+	require.NoError(t, err)
+
+	const seq = 100_000
+	entries := map[string]string{}
+	for key, liveUntil := range map[*xdr.LedgerKey]uint32{
+		&live:     seq + 2*LowTTLThreshold,
+		&archived: seq - 10,
+		&expired:  seq - 1,
+		&expiring: seq + 50,
+	} {
+		k, v := ttlEntry(t, *key, liveUntil)
+		entries[k] = v
+	}
+
+	req := &SimulationRequest{EnvelopeXdr: envelope}
+	report, err := AnalyzeArchival(req, &SimulationResponse{Status: "error"}, entries, seq)
+	require.NoError(t, err)
+	require.Len(t, report.Entries, 5, "classic entries are not subject to archival")
+	assert.True(t, report.NeedsAttention())
+	assert.True(t, report.CausedFailure)
+
+	status := map[string]EntryTTL{}
+	for _, e := range report.Entries {
+		status[e.Key] = e
+	}
+	enc := func(k xdr.LedgerKey) string {
+		s, err := xdr.MarshalBase64(k)
+		require.NoError(t, err)
+		return s
+	}
+	assert.Equal(t, TTLLive, status[enc(live)].Status)
+	assert.Equal(t, TTLExpiring, status[enc(expiring)].Status)
+	assert.Equal(t, int64(50), status[enc(expiring)].LedgersLeft)
+	assert.Equal(t, TTLArchived, status[enc(archived)].Status)
+	assert.Equal(t, int64(-10), status[enc(archived)].LedgersLeft)
+	assert.Equal(t, TTLExpired, status[enc(expired)].Status)
+	assert.Equal(t, "temporary", status[enc(expired)].Durability)
+	assert.Equal(t, TTLMissing, status[enc(created)].Status)
+	assert.True(t, status[enc(created)].ReadWrite)
+
+	require.NotNil(t, report.Restore)
+	assert.Equal(t, []string{enc(archived)}, report.Restore.Keys, "temporary entries cannot be restored")
+	require.NotNil(t, report.Extend)
+	assert.Equal(t, []string{enc(expiring)}, report.Extend.Keys)
+
+	// A successful run is never blamed on archival
+	report, err = AnalyzeArchival(req, &SimulationResponse{Status: "success"}, entries, seq)
+	require.NoError(t, err)
+	assert.False(t, report.CausedFailure)
+
+	// Only a missing read-write entry, which the transaction may create
+	k, v := ttlEntry(t, archived, seq+2*LowTTLThreshold)
+	entries[k] = v
+	k, v = ttlEntry(t, expired, seq+2*LowTTLThreshold)
+	entries[k] = v
+	report, err = AnalyzeArchival(req, &SimulationResponse{Status: "error"}, entries, seq)
+	require.NoError(t, err)
+	assert.False(t, report.CausedFailure)
+	assert.Nil(t, report.Restore)
+}
+
+func TestAnalyzeArchival_NoSorobanData(t *testing.T) {
+	envelope, err := xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress("GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"),
+		}},
+	})
+	require.NoError(t, err)
+
+	report, err := AnalyzeArchival(&SimulationRequest{EnvelopeXdr: envelope}, nil, nil, 1)
+	require.NoError(t, err)
+	assert.Nil(t, report)
+}
@@ -23,6 +23,7 @@ type SimulationRequestBuilder struct {
 	envelopeXdr   string
 	resultMetaXdr string
 	ledgerEntries map[string]string
+	overrides     map[string]string
 	errors        []string
 }
 
@@ -30,6 +31,7 @@ type SimulationRequestBuilder struct {
 func NewSimulationRequestBuilder() *SimulationRequestBuilder {
 	return &SimulationRequestBuilder{
 		ledgerEntries: make(map[string]string),
+		overrides:     make(map[string]string),
 		errors:        make([]string, 0),
 	}
 }
@@ -87,6 +89,17 @@ func (b *SimulationRequestBuilder) WithLedgerEntries(entries map[string]string)
 	return b
 }
 
+// WithLedgerEntryOverride replaces (or injects) a ledger entry on top of the
+// snapshot. The entry must be stored under the given key.
+func (b *SimulationRequestBuilder) WithLedgerEntryOverride(key, value string) *SimulationRequestBuilder {
+	if err := ValidateLedgerOverride(key, value); err != nil {
+		b.errors = append(b.errors, err.Error())
+		return b
+	}
+	b.overrides[key] = value
+	return b
+}
+
 // Build constructs and validates the final SimulationRequest.
 // Returns an error if required fields are missing or validation fails.
 func (b *SimulationRequestBuilder) Build() (*SimulationRequest, error) {
@@ -114,6 +127,9 @@ func (b *SimulationRequestBuilder) Build() (*SimulationRequest, error) {
 	if len(b.ledgerEntries) > 0 {
 		req.LedgerEntries = b.ledgerEntries
 	}
+	if len(b.overrides) > 0 {
+		req.LedgerEntryOverrides = b.overrides
+	}
 
 	return req, nil
 }
@@ -133,6 +149,7 @@ func (b *SimulationRequestBuilder) Reset() *SimulationRequestBuilder {
 	b.envelopeXdr = ""
 	b.resultMetaXdr = ""
 	b.ledgerEntries = make(map[string]string)
+	b.overrides = make(map[string]string)
 	b.errors = make([]string, 0)
 	return b
 }
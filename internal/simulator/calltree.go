@@ -0,0 +1,310 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Call statuses
+const (
+	CallOK     = "ok"
+	CallFailed = "failed"
+)
+
+// CallNode is one contract call rebuilt from fn_call/fn_return diagnostic
+// events
+type CallNode struct {
+	Contract string   `json:"contract"`
+	Function string   `json:"function"`
+	Args     []string `json:"args,omitempty"`
+	Return   string   `json:"return,omitempty"`
+	Status   string   `json:"status"`
+	Error    string   `json:"error,omitempty"`
+	// Events counts the contract events the call emitted itself
+	Events int `json:"events,omitempty"`
+	// BudgetKnown is set when CPUInsns and MemBytes were matched from the
+	// simulator's frame budgets; both include sub-calls
+	BudgetKnown bool   `json:"budget_known"`
+	CPUInsns    uint64 `json:"cpu_insns,omitempty"`
+	MemBytes    uint64 `json:"mem_bytes,omitempty"`
+	// HostFnCalls counts the host functions the call made itself
+	HostFnCalls uint64 `json:"host_fn_calls,omitempty"`
+	// HostAllocs and LinearMemBytes are set under memory profiling
+	HostAllocs     []HostAlloc `json:"host_allocs,omitempty"`
+	LinearMemBytes *uint64     `json:"linear_mem_bytes,omitempty"`
+	SubCalls       []*CallNode `json:"sub_calls,omitempty"`
+
+	parent *CallNode
+	depth  int
+}
+
+// BuildCallTree rebuilds the contract call tree from diagnostic events and
+// attaches per-call budgets from frames when their shape matches. Events
+// without XDR topics, from older simulators, yield no tree.
+func BuildCallTree(events []DiagnosticEvent, frames []FrameBudget) []*CallNode {
+	var roots, order []*CallNode
+	var current *CallNode
+
+	for _, e := range events {
+		if len(e.TopicsXdr) == 0 {
+			continue
+		}
+		switch symbolTopic(e.TopicsXdr[0]) {
+		case "fn_call":
+			node := &CallNode{Status: CallFailed, parent: current}
+			if len(e.TopicsXdr) > 1 {
+				node.Contract = contractTopic(e.TopicsXdr[1])
+			}
+			if len(e.TopicsXdr) > 2 {
+				node.Function = symbolTopic(e.TopicsXdr[2])
+			}
+			node.Args = callArgs(e.DataXdr)
+			if current == nil {
+				roots = append(roots, node)
+			} else {
+				node.depth = current.depth + 1
+				current.SubCalls = append(current.SubCalls, node)
+			}
+			order = append(order, node)
+			current = node
+
+		case "fn_return":
+			fn := ""
+			if len(e.TopicsXdr) > 1 {
+				fn = symbolTopic(e.TopicsXdr[1])
+			}
+			// Calls that returned no event failed; unwind to the one returning
+			returning := current
+			for returning != nil && returning.Function != fn {
+				returning = returning.parent
+			}
+			if returning == nil {
+				continue
+			}
+			returning.Status = CallOK
+			returning.Return = renderScVal(e.DataXdr)
+			current = returning.parent
+
+		case "error":
+			if current != nil && current.Error == "" {
+				current.Error = errorMessage(e)
+			}
+
+		default:
+			if current != nil && e.EventType == "contract" {
+				current.Events++
+			}
+		}
+	}
+
+	attachBudgets(order, frames)
+	return roots
+}
+
+// attachBudgets matches frames to calls. The host pushes one frame per
+// contract call, plus one for the invoked host function itself.
+func attachBudgets(calls []*CallNode, frames []FrameBudget) {
+	if len(frames) == len(calls)+1 && len(frames) > 0 && frames[0].Depth == 0 {
+		frames = frames[1:]
+		shifted := make([]FrameBudget, len(frames))
+		for i, f := range frames {
+			f.Depth--
+			shifted[i] = f
+		}
+		frames = shifted
+	}
+	if len(frames) != len(calls) {
+		return
+	}
+	for i, c := range calls {
+		if frames[i].Depth != c.depth {
+			return
+		}
+	}
+	for i, c := range calls {
+		c.BudgetKnown = true
+		c.CPUInsns = frames[i].CPUInsns
+		c.MemBytes = frames[i].MemBytes
+		c.HostFnCalls = frames[i].HostFnCalls
+		c.HostAllocs = frames[i].HostAllocs
+		c.LinearMemBytes = frames[i].LinearMemBytes
+	}
+}
+
+// FailurePath returns the chain of failed calls from a root down to the
+// deepest one, where the failure originated
+func FailurePath(roots []*CallNode) []*CallNode {
+	var path []*CallNode
+	nodes := roots
+	for {
+		var failed *CallNode
+		for _, n := range nodes {
+			if n.Status == CallFailed {
+				failed = n
+			}
+		}
+		if failed == nil {
+			return path
+		}
+		path = append(path, failed)
+		nodes = failed.SubCalls
+	}
+}
+
+// RenderCallTree draws the call tree with each call's status and budget
+func RenderCallTree(roots []*CallNode) string {
+	var b strings.Builder
+	for _, root := range roots {
+		renderCall(&b, root, "", "")
+	}
+	return b.String()
+}
+
+func renderCall(b *strings.Builder, n *CallNode, prefix, childPrefix string) {
+	status := "ok"
+	if n.Status == CallFailed {
+		status = "FAILED"
+	}
+	line := fmt.Sprintf("%s%s.%s(%s)  %s", prefix, ShortID(n.Contract), n.Function, strings.Join(n.Args, ", "), status)
+	if n.BudgetKnown {
+		line += fmt.Sprintf("  cpu %d  mem %d", n.CPUInsns, n.MemBytes)
+	}
+	b.WriteString(line + "\n")
+	if n.Error != "" {
+		fmt.Fprintf(b, "%s   error: %s\n", childPrefix, n.Error)
+	}
+	for i, sub := range n.SubCalls {
+		if i == len(n.SubCalls)-1 {
+			renderCall(b, sub, childPrefix+"└─ ", childPrefix+"   ")
+		} else {
+			renderCall(b, sub, childPrefix+"├─ ", childPrefix+"│  ")
+		}
+	}
+}
+
+// ShortID abbreviates a contract or account ID to its first and last four
+// characters, or shows its alias or identity name when it has one. A muxed
+// address is shown as its account and ID, e.g. "GAAZ…CWN7#42".
+func ShortID(id string) string {
+	if name, ok := decoder.AddressName(id); ok {
+		return name
+	}
+	if account, muxedID, ok := decoder.SplitMuxedAddress(id); ok {
+		return fmt.Sprintf("%s#%d", ShortID(account), muxedID)
+	}
+	if len(id) <= 12 {
+		return id
+	}
+	return id[:4] + "…" + id[len(id)-4:]
+}
+
+func decodeScVal(b64 string) (xdr.ScVal, bool) {
+	var v xdr.ScVal
+	if b64 == "" || xdr.SafeUnmarshalBase64(b64, &v) != nil {
+		return v, false
+	}
+	return v, true
+}
+
+func renderScVal(b64 string) string {
+	v, ok := decodeScVal(b64)
+	if !ok {
+		return ""
+	}
+	return decoder.FormatScVal(v)
+}
+
+func symbolTopic(b64 string) string {
+	v, ok := decodeScVal(b64)
+	if !ok {
+		return ""
+	}
+	if sym, ok := v.GetSym(); ok {
+		return string(sym)
+	}
+	return decoder.FormatScVal(v)
+}
+
+// TopicNames returns the topics of e the way they are written in filters and
+// rules: symbols by their text and other values rendered. Events without
+// topic XDR, and topics the simulator could not encode, keep the simulator's
+// rendering.
+func (e DiagnosticEvent) TopicNames() []string {
+	if len(e.TopicsXdr) == 0 {
+		return e.Topics
+	}
+	names := make([]string, len(e.TopicsXdr))
+	for i, b64 := range e.TopicsXdr {
+		names[i] = symbolTopic(b64)
+		if b64 == "" && i < len(e.Topics) {
+			names[i] = e.Topics[i]
+		}
+	}
+	return names
+}
+
+// contractTopic renders the called contract of a fn_call event, which the
+// host emits as the raw contract ID bytes
+func contractTopic(b64 string) string {
+	v, ok := decodeScVal(b64)
+	if !ok {
+		return ""
+	}
+	if raw, ok := v.GetBytes(); ok && len(raw) == 32 {
+		if id, err := strkey.Encode(strkey.VersionByteContract, raw); err == nil {
+			return id
+		}
+	}
+	return decoder.FormatScVal(v)
+}
+
+// callArgs splits fn_call data, a vec of arguments or a single one
+func callArgs(b64 string) []string {
+	v, ok := decodeScVal(b64)
+	if !ok || v.Type == xdr.ScValTypeScvVoid {
+		return nil
+	}
+	if vec, ok := v.GetVec(); ok && vec != nil {
+		if len(*vec) == 0 {
+			return nil
+		}
+		args := make([]string, len(*vec))
+		for i, a := range *vec {
+			args[i] = decoder.FormatScVal(a)
+		}
+		return args
+	}
+	return []string{decoder.FormatScVal(v)}
+}
+
+// errorMessage renders an error diagnostic event as "<error>: <message>"
+func errorMessage(e DiagnosticEvent) string {
+	code := ""
+	if len(e.TopicsXdr) > 1 {
+		code = renderScVal(e.TopicsXdr[1])
+	}
+	msg := renderScVal(e.DataXdr)
+	if v, ok := decodeScVal(e.DataXdr); ok {
+		if vec, ok := v.GetVec(); ok && vec != nil && len(*vec) > 0 {
+			parts := make([]string, len(*vec))
+			for i, a := range *vec {
+				parts[i] = decoder.FormatScVal(a)
+			}
+			msg = strings.Join(parts, " ")
+		}
+	}
+	switch {
+	case code == "":
+		return msg
+	case msg == "":
+		return code
+	}
+	return code + ": " + msg
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scvB64(t *testing.T, v xdr.ScVal) string {
+	t.Helper()
+	s, err := xdr.MarshalBase64(v)
+	require.NoError(t, err)
+	return s
+}
+
+func scvSym(s string) xdr.ScVal {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+}
+
+func scvU32(n uint32) xdr.ScVal {
+	u := xdr.Uint32(n)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u}
+}
+
+func fnCall(t *testing.T, id byte, fn string, args ...xdr.ScVal) DiagnosticEvent {
+	raw := xdr.ScBytes(make([]byte, 32))
+	raw[0] = id
+	vec := xdr.ScVec(args)
+	vecPtr := &vec
+	return DiagnosticEvent{
+		EventType: "diagnostic",
+		TopicsXdr: []string{
+			scvB64(t, scvSym("fn_call")),
+			scvB64(t, xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &raw}),
+			scvB64(t, scvSym(fn)),
+		},
+		DataXdr: scvB64(t, xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vecPtr}),
+	}
+}
+
+func fnReturn(t *testing.T, fn string, v xdr.ScVal) DiagnosticEvent {
+	return DiagnosticEvent{
+		EventType: "diagnostic",
+		TopicsXdr: []string{scvB64(t, scvSym("fn_return")), scvB64(t, scvSym(fn))},
+		DataXdr:   scvB64(t, v),
+	}
+}
+
+func contractID(t *testing.T, id byte) string {
+	raw := make([]byte, 32)
+	raw[0] = id
+	s, err := strkey.Encode(strkey.VersionByteContract, raw)
+	require.NoError(t, err)
+	return s
+}
+
+func TestBuildCallTree(t *testing.T) {
+	six := xdr.Uint32(6)
+	contractErr := xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &six}}
+	msg := xdr.ScString("insufficient balance")
+
+	events := []DiagnosticEvent{
+		fnCall(t, 1, "swap", scvU32(100)),
+		fnCall(t, 2, "price"),
+		fnReturn(t, "price", scvU32(7)),
+		{EventType: "contract", TopicsXdr: []string{scvB64(t, scvSym("swap"))}},
+		fnCall(t, 3, "transfer", scvU32(100)),
+		{EventType: "diagnostic", TopicsXdr: []string{scvB64(t, scvSym("error")), scvB64(t, contractErr)}, DataXdr: scvB64(t, xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &msg})},
+	}
+	frames := []FrameBudget{
+		{Depth: 0, CPUInsns: 1000, MemBytes: 100}, // the host function frame
+		{Depth: 1, CPUInsns: 900, MemBytes: 90},
+		{Depth: 2, CPUInsns: 200, MemBytes: 20},
+		{Depth: 2, CPUInsns: 300, MemBytes: 30},
+	}
+
+	roots := BuildCallTree(events, frames)
+	require.Len(t, roots, 1)
+	swap := roots[0]
+	assert.Equal(t, contractID(t, 1), swap.Contract)
+	assert.Equal(t, "swap", swap.Function)
+	assert.Equal(t, []string{"100"}, swap.Args)
+	assert.Equal(t, CallFailed, swap.Status)
+	assert.Equal(t, 1, swap.Events)
+	assert.True(t, swap.BudgetKnown)
+	assert.Equal(t, uint64(900), swap.CPUInsns)
+
+	require.Len(t, swap.SubCalls, 2)
+	price, transfer := swap.SubCalls[0], swap.SubCalls[1]
+	assert.Equal(t, CallOK, price.Status)
+	assert.Equal(t, "7", price.Return)
+	assert.Nil(t, price.Args)
+	assert.Equal(t, uint64(200), price.CPUInsns)
+	assert.Equal(t, CallFailed, transfer.Status)
+	assert.Contains(t, transfer.Error, "insufficient balance")
+	assert.Equal(t, uint64(30), transfer.MemBytes)
+
+	path := FailurePath(roots)
+	require.Len(t, path, 2)
+	assert.Equal(t, transfer, path[1])
+
+	rendered := RenderCallTree(roots)
+	lines := strings.Split(strings.TrimRight(rendered, "\n"), "\n")
+	require.Len(t, lines, 4, rendered)
+	assert.Contains(t, lines[0], ".swap(100)  FAILED  cpu 900  mem 90")
+	assert.True(t, strings.HasPrefix(lines[1], "├─ "), rendered)
+	assert.True(t, strings.HasPrefix(lines[2], "└─ "), rendered)
+	assert.Contains(t, lines[3], "error:")
+}
+
+func TestBuildCallTree_UnwindsMissingReturns(t *testing.T) {
+	// inner fails and outer recovers (try_call), so only outer returns
+	roots := BuildCallTree([]DiagnosticEvent{
+		fnCall(t, 1, "outer"),
+		fnCall(t, 2, "inner"),
+		fnReturn(t, "outer", xdr.ScVal{Type: xdr.ScValTypeScvVoid}),
+		fnCall(t, 3, "after"),
+		fnReturn(t, "after", xdr.ScVal{Type: xdr.ScValTypeScvVoid}),
+	}, []FrameBudget{{Depth: 0}})
+
+	require.Len(t, roots, 2)
+	assert.Equal(t, CallOK, roots[0].Status)
+	assert.Equal(t, CallFailed, roots[0].SubCalls[0].Status)
+	assert.Equal(t, "after", roots[1].Function)
+	assert.False(t, roots[0].BudgetKnown, "frames that do not match the calls are ignored")
+	assert.Empty(t, FailurePath(roots))
+}
+
+func TestBuildCallTree_NoXdr(t *testing.T) {
+	assert.Empty(t, BuildCallTree([]DiagnosticEvent{{EventType: "diagnostic", Topics: []string{"fn_call"}}}, nil))
+}
+
+func TestTopicNames_KeepsRenderingForUnencodedTopics(t *testing.T) {
+	e := DiagnosticEvent{
+		Topics:    []string{"Symbol(transfer)", "Bytes(...)", "U32(7)"},
+		TopicsXdr: []string{scvB64(t, scvSym("transfer")), "", scvB64(t, scvU32(7))},
+	}
+	assert.Equal(t, []string{"transfer", "Bytes(...)", "7"}, e.TopicNames())
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import "context"
+
+// ChainFetcher returns the current ledger entries for keys; keys without an
+// entry on the ledger are left out of the result
+type ChainFetcher func(ctx context.Context, keys []string) (map[string]string, error)
+
+// Chain simulates dependent transactions in order against an evolving
+// in-memory ledger state: each transaction sees the entries written by the
+// successful transactions before it, and entries the chain has not touched
+// yet are fetched when first needed.
+type Chain struct {
+	runner RunnerInterface
+	fetch  ChainFetcher
+
+	// state holds every entry the chain knows, keyed by LedgerKey XDR. A key
+	// mapped to "" was deleted by an earlier transaction.
+	state map[string]string
+}
+
+// NewChain returns a chain that simulates with runner, starting from the
+// given entries (such as ledger overrides) on top of what fetch returns
+func NewChain(runner RunnerInterface, fetch ChainFetcher, initial map[string]string) *Chain {
+	state := make(map[string]string, len(initial))
+	for k, v := range initial {
+		state[k] = v
+	}
+	return &Chain{runner: runner, fetch: fetch, state: state}
+}
+
+// Run simulates req against the chain's state for footprint keys. Entries
+// the chain does not know yet are fetched first; req.LedgerEntries is then
+// replaced with the chain's view of the footprint. The transaction's writes
+// are applied to the state only when the simulation succeeds, since a failed
+// transaction changes nothing on the ledger.
+func (c *Chain) Run(ctx context.Context, req *SimulationRequest, keys []string) (*SimulationResponse, error) {
+	var missing []string
+	for _, k := range keys {
+		if _, ok := c.state[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 && c.fetch != nil {
+		fetched, err := c.fetch(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range missing {
+			// Absent on the ledger too: remember that rather than fetching again
+			c.state[k] = fetched[k]
+		}
+	}
+
+	entries := make(map[string]string, len(keys))
+	for _, k := range keys {
+		if v := c.state[k]; v != "" {
+			entries[k] = v
+		}
+	}
+	req.LedgerEntries = entries
+
+	resp, err := RunTraced(ctx, c.runner, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status != "error" {
+		c.apply(resp.StorageWrites)
+	}
+	return resp, nil
+}
+
+func (c *Chain) apply(writes []StorageWrite) {
+	for _, w := range writes {
+		c.state[w.Key] = w.Entry
+	}
+}
+
+// State returns the entries the chain holds, without deleted entries
+func (c *Chain) State() map[string]string {
+	out := make(map[string]string, len(c.state))
+	for k, v := range c.state {
+		if v != "" {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain_Run(t *testing.T) {
+	var fetched [][]string
+	fetch := func(ctx context.Context, keys []string) (map[string]string, error) {
+		fetched = append(fetched, keys)
+		out := map[string]string{}
+		for _, k := range keys {
+			if k != "absent" {
+				out[k] = "ledger-" + k
+			}
+		}
+		return out, nil
+	}
+
+	var seen []map[string]string
+	runner := NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		seen = append(seen, req.LedgerEntries)
+		switch req.EnvelopeXdr {
+		case "approve":
+			return &SimulationResponse{Status: "success", StorageWrites: []StorageWrite{
+				{Key: "allowance", Entry: "approved"},
+			}}, nil
+		case "swap":
+			return &SimulationResponse{Status: "success", StorageWrites: []StorageWrite{
+				{Key: "allowance"},
+				{Key: "balance", Entry: "swapped"},
+			}}, nil
+		default:
+			return &SimulationResponse{Status: "error", Error: "claim failed", StorageWrites: []StorageWrite{
+				{Key: "balance", Entry: "claimed"},
+			}}, nil
+		}
+	})
+
+	chain := NewChain(runner, fetch, map[string]string{"override": "seeded"})
+	ctx := context.Background()
+
+	_, err := chain.Run(ctx, &SimulationRequest{EnvelopeXdr: "approve"}, []string{"allowance", "override", "absent"})
+	require.NoError(t, err)
+	_, err = chain.Run(ctx, &SimulationRequest{EnvelopeXdr: "swap"}, []string{"allowance", "balance", "absent"})
+	require.NoError(t, err)
+	resp, err := chain.Run(ctx, &SimulationRequest{EnvelopeXdr: "claim"}, []string{"allowance", "balance"})
+	require.NoError(t, err)
+	assert.Equal(t, "error", resp.Status)
+
+	assert.Equal(t, [][]string{{"allowance", "absent"}, {"balance"}}, fetched, "known and absent keys are not fetched again")
+	assert.Equal(t, map[string]string{"allowance": "ledger-allowance", "override": "seeded"}, seen[0])
+	assert.Equal(t, map[string]string{"allowance": "approved", "balance": "ledger-balance"}, seen[1])
+	assert.Equal(t, map[string]string{"balance": "swapped"}, seen[2], "deleted entries stay deleted")
+
+	assert.Equal(t, map[string]string{"override": "seeded", "balance": "swapped"}, chain.State(), "a failed step writes nothing")
+}
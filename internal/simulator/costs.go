@@ -0,0 +1,139 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"sort"
+	"strings"
+)
+
+// FunctionCost is the cost of every call to one contract function, summed
+// over the call tree
+type FunctionCost struct {
+	Contract string `json:"contract"`
+	Function string `json:"function"`
+	Calls    int    `json:"calls"`
+	// CPUInsns and MemBytes include sub-calls. A recursive call is counted
+	// once, at its outermost invocation.
+	CPUInsns uint64 `json:"cpu_insns"`
+	MemBytes uint64 `json:"mem_bytes"`
+	// SelfCPUInsns and SelfMemBytes exclude sub-calls
+	SelfCPUInsns uint64 `json:"self_cpu_insns"`
+	SelfMemBytes uint64 `json:"self_mem_bytes"`
+	HostFnCalls  uint64 `json:"host_fn_calls"`
+	// MemoryProfiled is set when the simulation ran with ProfileMemory, in
+	// which case SelfMemBytes is split into HostAllocs and LinearMemBytes
+	MemoryProfiled bool        `json:"memory_profiled,omitempty"`
+	HostAllocs     []HostAlloc `json:"host_allocs,omitempty"`
+	LinearMemBytes uint64      `json:"linear_mem_bytes,omitempty"`
+}
+
+type functionKey struct{ contract, function string }
+
+// FunctionCosts aggregates the call tree per contract function, most
+// expensive first by the CPU each function used itself. It returns nil when
+// the tree carries no budgets.
+func FunctionCosts(roots []*CallNode) []FunctionCost {
+	costs := make(map[functionKey]*FunctionCost)
+	var order []functionKey
+	known := false
+
+	var walk func(n *CallNode, active map[functionKey]int)
+	walk = func(n *CallNode, active map[functionKey]int) {
+		if !n.BudgetKnown {
+			return
+		}
+		known = true
+		key := functionKey{n.Contract, n.Function}
+		c, ok := costs[key]
+		if !ok {
+			c = &FunctionCost{Contract: n.Contract, Function: n.Function}
+			costs[key] = c
+			order = append(order, key)
+		}
+
+		c.Calls++
+		c.HostFnCalls += n.HostFnCalls
+		if active[key] == 0 {
+			c.CPUInsns += n.CPUInsns
+			c.MemBytes += n.MemBytes
+		}
+
+		selfCPU, selfMem := n.CPUInsns, n.MemBytes
+		for _, sub := range n.SubCalls {
+			selfCPU -= min(selfCPU, sub.CPUInsns)
+			selfMem -= min(selfMem, sub.MemBytes)
+		}
+		c.SelfCPUInsns += selfCPU
+		c.SelfMemBytes += selfMem
+		if n.LinearMemBytes != nil {
+			c.MemoryProfiled = true
+			c.LinearMemBytes += *n.LinearMemBytes
+			c.HostAllocs = mergeAllocs(c.HostAllocs, n.HostAllocs)
+		}
+
+		active[key]++
+		for _, sub := range n.SubCalls {
+			walk(sub, active)
+		}
+		active[key]--
+	}
+
+	for _, root := range roots {
+		walk(root, make(map[functionKey]int))
+	}
+	if !known {
+		return nil
+	}
+
+	out := make([]FunctionCost, len(order))
+	for i, key := range order {
+		out[i] = *costs[key]
+		sort.SliceStable(out[i].HostAllocs, func(a, b int) bool {
+			return out[i].HostAllocs[a].MemBytes > out[i].HostAllocs[b].MemBytes
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].SelfCPUInsns > out[j].SelfCPUInsns
+	})
+	return out
+}
+
+func mergeAllocs(into, from []HostAlloc) []HostAlloc {
+	for _, a := range from {
+		merged := false
+		for i := range into {
+			if into[i].Name == a.Name {
+				into[i].Calls += a.Calls
+				into[i].MemBytes += a.MemBytes
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			into = append(into, a)
+		}
+	}
+	return into
+}
+
+// RankByMemory orders costs by the memory each function used itself, most
+// first
+func RankByMemory(costs []FunctionCost) []FunctionCost {
+	out := append([]FunctionCost(nil), costs...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].SelfMemBytes > out[j].SelfMemBytes
+	})
+	return out
+}
+
+// MemoryExhausted reports whether the simulation failed by running out of
+// memory budget rather than CPU
+func MemoryExhausted(resp *SimulationResponse) bool {
+	if resp == nil || resp.BudgetUsage == nil || !strings.Contains(resp.Error, "ExceededLimit") {
+		return false
+	}
+	u := resp.BudgetUsage
+	return u.MemoryUsagePercent >= 100 || u.MemoryUsagePercent > u.CPUUsagePercent
+}
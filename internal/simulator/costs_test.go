@@ -0,0 +1,94 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func call(contract, fn string, cpu, mem, hostFns uint64, subs ...*CallNode) *CallNode {
+	return &CallNode{
+		Contract: contract, Function: fn, BudgetKnown: true,
+		CPUInsns: cpu, MemBytes: mem, HostFnCalls: hostFns, SubCalls: subs,
+	}
+}
+
+func TestFunctionCosts(t *testing.T) {
+	roots := []*CallNode{
+		call("CPOOL", "swap", 1000, 100, 3,
+			call("CTOKEN", "transfer", 300, 30, 5),
+			call("CPOOL", "swap", 200, 20, 1), // recursive
+			call("CTOKEN", "transfer", 400, 40, 5),
+		),
+	}
+
+	costs := FunctionCosts(roots)
+	require.Len(t, costs, 2)
+
+	transfer, swap := costs[0], costs[1]
+	assert.Equal(t, FunctionCost{
+		Contract: "CTOKEN", Function: "transfer", Calls: 2,
+		CPUInsns: 700, MemBytes: 70, SelfCPUInsns: 700, SelfMemBytes: 70, HostFnCalls: 10,
+	}, transfer)
+
+	assert.Equal(t, 2, swap.Calls)
+	assert.Equal(t, uint64(1000), swap.CPUInsns, "the recursive call is inside the outer one")
+	assert.Equal(t, uint64(100+200), swap.SelfCPUInsns)
+	assert.Equal(t, uint64(4), swap.HostFnCalls)
+}
+
+func TestFunctionCosts_NoBudgets(t *testing.T) {
+	assert.Nil(t, FunctionCosts([]*CallNode{{Contract: "C", Function: "f"}}))
+	assert.Nil(t, FunctionCosts(nil))
+}
+
+func TestBuildCallTree_HostFnCalls(t *testing.T) {
+	roots := BuildCallTree([]DiagnosticEvent{
+		fnCall(t, 1, "f"),
+		fnReturn(t, "f", scvU32(1)),
+	}, []FrameBudget{{Depth: 0}, {Depth: 1, CPUInsns: 10, HostFnCalls: 4}})
+	require.Len(t, roots, 1)
+	assert.Equal(t, uint64(4), roots[0].HostFnCalls)
+}
+
+func TestFunctionCosts_Memory(t *testing.T) {
+	linear := func(n uint64) *uint64 { return &n }
+	inner := call("CTOKEN", "transfer", 100, 300, 2)
+	inner.HostAllocs = []HostAlloc{{Name: "vec_new", Calls: 1, MemBytes: 100}, {Name: "map_put", Calls: 1, MemBytes: 150}}
+	inner.LinearMemBytes = linear(50)
+	again := call("CTOKEN", "transfer", 100, 200, 1)
+	again.HostAllocs = []HostAlloc{{Name: "vec_new", Calls: 2, MemBytes: 200}}
+	again.LinearMemBytes = linear(0)
+	outer := call("CPOOL", "swap", 400, 600, 1, inner, again)
+	outer.LinearMemBytes = linear(100)
+
+	costs := RankByMemory(FunctionCosts([]*CallNode{outer}))
+	require.Len(t, costs, 2)
+	transfer := costs[0]
+	assert.Equal(t, "transfer", transfer.Function)
+	assert.True(t, transfer.MemoryProfiled)
+	assert.Equal(t, uint64(500), transfer.SelfMemBytes)
+	assert.Equal(t, uint64(50), transfer.LinearMemBytes)
+	assert.Equal(t, []HostAlloc{{Name: "vec_new", Calls: 3, MemBytes: 300}, {Name: "map_put", Calls: 1, MemBytes: 150}}, transfer.HostAllocs)
+	assert.Equal(t, uint64(100), costs[1].SelfMemBytes)
+
+	// the node's own allocations are not modified by aggregation
+	assert.Equal(t, uint64(100), inner.HostAllocs[0].MemBytes)
+}
+
+func TestMemoryExhausted(t *testing.T) {
+	resp := &SimulationResponse{
+		Status:      "error",
+		Error:       "HostError: Error(Budget, ExceededLimit)",
+		BudgetUsage: &BudgetUsage{CPUUsagePercent: 40, MemoryUsagePercent: 100},
+	}
+	assert.True(t, MemoryExhausted(resp))
+
+	resp.BudgetUsage = &BudgetUsage{CPUUsagePercent: 100, MemoryUsagePercent: 30}
+	assert.False(t, MemoryExhausted(resp))
+	assert.False(t, MemoryExhausted(&SimulationResponse{Error: "trap"}))
+}
@@ -0,0 +1,30 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import "github.com/dotandev/hintents/internal/decoder"
+
+// ExplainError explains why a simulation failed, preferring the trap kind of
+// the WASM stack trace over what the error text says. It returns nil for a
+// successful simulation or an unrecognised error.
+func ExplainError(resp *SimulationResponse) *decoder.ErrorExplanation {
+	if resp == nil || resp.Error == "" {
+		return nil
+	}
+	exp := decoder.ExplainError(resp.Error)
+	if resp.StackTrace == nil {
+		return exp
+	}
+	// Unit trap kinds serialize as a plain string, the others as an object
+	if kind, ok := resp.StackTrace.TrapKind.(string); ok {
+		if exp == nil {
+			exp = &decoder.ErrorExplanation{}
+		}
+		exp.SetTrap(kind)
+		if exp.Summary == "" {
+			return nil
+		}
+	}
+	return exp
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainError_StackTraceTrap(t *testing.T) {
+	resp := &SimulationResponse{
+		Status:     "error",
+		Error:      "HostError: Error(WasmVm, InvalidAction)",
+		StackTrace: &WasmStackTrace{TrapKind: "IntegerDivisionByZero"},
+	}
+	exp := ExplainError(resp)
+	require.NotNil(t, exp)
+	assert.Equal(t, "IntegerDivisionByZero", exp.Trap)
+	assert.Equal(t, "Division by zero", exp.Summary)
+}
+
+func TestExplainError_HostErrorTrapKind(t *testing.T) {
+	resp := &SimulationResponse{
+		Status:     "error",
+		Error:      "HostError: Error(Storage, MissingValue)",
+		StackTrace: &WasmStackTrace{TrapKind: map[string]interface{}{"HostError": "Error(Storage, MissingValue)"}},
+	}
+	exp := ExplainError(resp)
+	require.NotNil(t, exp)
+	assert.Equal(t, "Ledger entry not found", exp.Summary)
+	assert.Empty(t, exp.Trap)
+}
+
+func TestExplainError_Success(t *testing.T) {
+	assert.Nil(t, ExplainError(&SimulationResponse{Status: "success"}))
+	assert.Nil(t, ExplainError(nil))
+}
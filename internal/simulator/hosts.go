@@ -0,0 +1,236 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+// erst-sim can be built against any soroban-env-host major version, which is
+// the protocol its rules implement. Builds for other hosts than the default
+// one live side by side as erst-sim-p<version> so that old transactions can be
+// replayed with the host that originally ran them.
+
+// MinHostVersion is the oldest soroban-env-host erst-sim builds against
+const MinHostVersion uint32 = 21
+
+// ReleasedHostVersions are the host versions the release workflow builds
+// erst-sim for, and therefore the ones that can be installed
+var ReleasedHostVersions = []uint32{21, 22}
+
+// ReleaseDownloadURL is the base of the release assets of each erst version,
+// as <url>/<tag>/erst-sim-p<version>-<os>-<arch>
+const ReleaseDownloadURL = "https://github.com/dotandev/hintents/releases/download"
+
+// releaseTag matches the version of an erst built from a release tag, as
+// opposed to "dev" or a git describe of an untagged commit
+var releaseTag = regexp.MustCompile(`^v\d+\.\d+\.\d+(-(alpha|beta|rc)\.?\d*)?$`)
+
+// HostDownloadURL returns where the host builds released with an erst
+// version are downloaded from. Builds are only compatible with the erst they
+// were released with, so there is none for development versions and ""
+// is returned.
+func HostDownloadURL(erstVersion string) string {
+	if !releaseTag.MatchString(erstVersion) {
+		return ""
+	}
+	return ReleaseDownloadURL + "/" + erstVersion
+}
+
+// ValidateHostVersion checks that erst-sim is built for version
+func ValidateHostVersion(version uint32) error {
+	if version < MinHostVersion {
+		return errors.WrapValidationError(fmt.Sprintf("host version %d is not supported; erst-sim needs soroban-env-host %d or later", version, MinHostVersion))
+	}
+	if err := Validate(version); err != nil {
+		return err
+	}
+	for _, v := range ReleasedHostVersions {
+		if v == version {
+			return nil
+		}
+	}
+	return errors.WrapValidationError(fmt.Sprintf("no erst-sim build is released for host version %d (available: %s)", version, HostVersionList()))
+}
+
+// HostVersionList returns ReleasedHostVersions for messages, e.g. "21, 22"
+func HostVersionList() string {
+	names := make([]string, len(ReleasedHostVersions))
+	for i, v := range ReleasedHostVersions {
+		names[i] = strconv.FormatUint(uint64(v), 10)
+	}
+	return strings.Join(names, ", ")
+}
+
+// HostBinaryName is the file name of the erst-sim build for a host version
+func HostBinaryName(version uint32) string {
+	name := fmt.Sprintf("erst-sim-p%d", version)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// HostsDir is where erst-sim host builds are installed: ERST_SIM_HOSTS_DIR,
+// or ~/.erst/simulators
+func HostsDir() (string, error) {
+	if dir := os.Getenv("ERST_SIM_HOSTS_DIR"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".erst", "simulators"), nil
+}
+
+// FindHostBinary returns the erst-sim build for a host version, looking in
+// HostsDir and then on PATH
+func FindHostBinary(version uint32) (string, error) {
+	name := HostBinaryName(version)
+	if dir, err := HostsDir(); err == nil {
+		if p := filepath.Join(dir, name); isExecutable(p) {
+			return p, nil
+		}
+	}
+	if p, err := exec.LookPath(name); err == nil {
+		return p, nil
+	}
+	return "", errors.WrapSimulatorNotFound(fmt.Sprintf("%s not found (install it with 'erst simulator install %d')", name, version))
+}
+
+// InstalledHosts lists the host versions found in HostsDir
+func InstalledHosts() ([]uint32, error) {
+	dir, err := HostsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []uint32
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".exe")
+		v, err := strconv.ParseUint(strings.TrimPrefix(name, "erst-sim-p"), 10, 32)
+		if err != nil || !strings.HasPrefix(name, "erst-sim-p") || !isExecutable(filepath.Join(dir, e.Name())) {
+			continue
+		}
+		versions = append(versions, uint32(v))
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions, nil
+}
+
+// InstallHost downloads the erst-sim build for a host version and this
+// platform from baseURL, usually HostDownloadURL, into HostsDir. A mirror set
+// in ERST_SIM_DOWNLOAD_URL takes its place.
+//
+// The download is checked against the SHA-256 sum published next to it. As
+// both come from the same server, this catches corrupt and truncated
+// downloads, but does not prove who built the binary.
+func InstallHost(ctx context.Context, client *http.Client, version uint32, baseURL string) (string, error) {
+	if err := ValidateHostVersion(version); err != nil {
+		return "", err
+	}
+	if mirror := os.Getenv("ERST_SIM_DOWNLOAD_URL"); mirror != "" {
+		baseURL = mirror
+	}
+	if baseURL == "" {
+		return "", fmt.Errorf("this erst is not a release build, so it has no erst-sim builds to download; set ERST_SIM_DOWNLOAD_URL or install %s on PATH", HostBinaryName(version))
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	asset := fmt.Sprintf("erst-sim-p%d-%s-%s", version, runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		asset += ".exe"
+	}
+	url := strings.TrimRight(baseURL, "/") + "/" + asset
+
+	sum, err := download(ctx, client, url+".sha256")
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(sum))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty checksum file at %s.sha256", url)
+	}
+	want := strings.ToLower(fields[0])
+
+	binary, err := download(ctx, client, url)
+	if err != nil {
+		return "", err
+	}
+	got := sha256.Sum256(binary)
+	if hex.EncodeToString(got[:]) != want {
+		return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", asset, want, hex.EncodeToString(got[:]))
+	}
+
+	dir, err := HostsDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	// Written under a temporary name so a failed install never leaves a
+	// truncated binary to be picked up
+	tmp, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, HostBinaryName(version))
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func download(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
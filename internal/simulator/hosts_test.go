@@ -0,0 +1,110 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestInstallHost(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("checks the executable bit")
+	}
+	t.Setenv("ERST_SIM_HOSTS_DIR", t.TempDir())
+	t.Setenv("PATH", "")
+
+	binary := []byte("#!/bin/sh\necho '{}'\n")
+	sum := sha256.Sum256(binary)
+	asset := fmt.Sprintf("/erst-sim-p21-%s-%s", runtime.GOOS, runtime.GOARCH)
+	corrupt := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case asset:
+			if corrupt {
+				_, _ = w.Write([]byte("truncated"))
+				return
+			}
+			_, _ = w.Write(binary)
+		case asset + ".sha256":
+			fmt.Fprintf(w, "%s  erst-sim-p21\n", hex.EncodeToString(sum[:]))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	if _, err := FindHostBinary(21); err == nil || !strings.Contains(err.Error(), "erst simulator install 21") {
+		t.Fatalf("FindHostBinary before install = %v", err)
+	}
+
+	path, err := InstallHost(context.Background(), server.Client(), 21, server.URL)
+	if err != nil {
+		t.Fatalf("InstallHost: %v", err)
+	}
+	if found, err := FindHostBinary(21); err != nil || found != path {
+		t.Errorf("FindHostBinary = %q, %v; want %q", found, err, path)
+	}
+	if got, _ := os.ReadFile(path); string(got) != string(binary) {
+		t.Errorf("installed binary = %q", got)
+	}
+	if versions, err := InstalledHosts(); err != nil || len(versions) != 1 || versions[0] != 21 {
+		t.Errorf("InstalledHosts = %v, %v", versions, err)
+	}
+
+	corrupt = true
+	if _, err := InstallHost(context.Background(), server.Client(), 21, server.URL); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("corrupt download error = %v", err)
+	}
+	if _, err := InstallHost(context.Background(), server.Client(), 22, server.URL); err == nil {
+		t.Error("expected an error for a missing release asset")
+	}
+}
+
+func TestValidateHostVersion(t *testing.T) {
+	if err := ValidateHostVersion(22); err != nil {
+		t.Errorf("ValidateHostVersion(22) = %v", err)
+	}
+	for _, v := range []uint32{20, 99} {
+		if err := ValidateHostVersion(v); err == nil {
+			t.Errorf("ValidateHostVersion(%d) succeeded", v)
+		}
+	}
+}
+
+func TestHostDownloadURL(t *testing.T) {
+	if got := HostDownloadURL("v1.4.0"); got != ReleaseDownloadURL+"/v1.4.0" {
+		t.Errorf("HostDownloadURL(v1.4.0) = %q", got)
+	}
+	for _, v := range []string{"dev", "", "v1.4.0-3-g1a2b3c4", "v1.4.0-dirty"} {
+		if got := HostDownloadURL(v); got != "" {
+			t.Errorf("HostDownloadURL(%q) = %q, want no release", v, got)
+		}
+	}
+
+	t.Setenv("ERST_SIM_HOSTS_DIR", t.TempDir())
+	t.Setenv("ERST_SIM_DOWNLOAD_URL", "")
+	if _, err := InstallHost(context.Background(), nil, 21, HostDownloadURL("dev")); err == nil || !strings.Contains(err.Error(), "ERST_SIM_DOWNLOAD_URL") {
+		t.Errorf("InstallHost for a development build = %v", err)
+	}
+}
+
+func TestReleasedHostVersionsMatchWorkflow(t *testing.T) {
+	workflow, err := os.ReadFile("../../.github/workflows/release.yml")
+	if err != nil {
+		t.Skipf("release workflow not found: %v", err)
+	}
+	want := "host: [" + HostVersionList() + "]"
+	if !strings.Contains(string(workflow), want) {
+		t.Errorf("release.yml does not build the released hosts; want %q in its matrix", want)
+	}
+}
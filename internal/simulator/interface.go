@@ -3,11 +3,38 @@
 
 package simulator
 
+import (
+	"context"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
 // RunnerInterface defines the contract for simulator execution
 type RunnerInterface interface {
 	Run(req *SimulationRequest) (*SimulationResponse, error)
 }
 
+// ContextRunner is a RunnerInterface that can abandon a simulation when its
+// context is done
+type ContextRunner interface {
+	RunnerInterface
+	RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error)
+}
+
+// RunWithContext runs req on runner, stopping it when ctx is done if runner
+// is a ContextRunner. Other runners are expected to return promptly.
+func RunWithContext(ctx context.Context, runner RunnerInterface, req *SimulationRequest) (*SimulationResponse, error) {
+	if cr, ok := runner.(ContextRunner); ok {
+		return cr.RunContext(ctx, req)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.WrapSimCanceled(err)
+	}
+	return runner.Run(req)
+}
+
 // NewRunnerInterface creates a RunnerInterface implementation
 // This allows for easy swapping between real and mock implementations
 func NewRunnerInterface() (RunnerInterface, error) {
@@ -20,3 +47,25 @@ func ExampleUsage(runner RunnerInterface, req *SimulationRequest) (*SimulationRe
 	// This enables easy testing with mocks and flexible production usage
 	return runner.Run(req)
 }
+
+// RunTraced runs req on runner inside a "simulate" span that records the
+// result status
+func RunTraced(ctx context.Context, runner RunnerInterface, req *SimulationRequest) (*SimulationResponse, error) {
+	ctx, span := telemetry.GetTracer().Start(ctx, "simulate")
+	span.SetAttributes(
+		attribute.Int("simulation.ledger_entries", len(req.LedgerEntries)),
+		attribute.Int("ledger.sequence", int(req.LedgerSequence)),
+	)
+	defer span.End()
+
+	resp, err := RunWithContext(ctx, runner, req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("simulation.status", resp.Status))
+	if resp.Error != "" {
+		span.SetAttributes(attribute.String("simulation.error", resp.Error))
+	}
+	return resp, nil
+}
@@ -4,8 +4,14 @@
 package simulator
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
+	"github.com/dotandev/hintents/internal/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -58,3 +64,44 @@ func (m *mockRunnerForTest) Run(req *SimulationRequest) (*SimulationResponse, er
 		Events: []string{"mock-event"},
 	}, nil
 }
+
+func TestRunTraced(t *testing.T) {
+	resp, err := RunTraced(context.Background(), &mockRunnerForTest{}, &SimulationRequest{EnvelopeXdr: "test-envelope"})
+	assert.NoError(t, err)
+	assert.Equal(t, "success", resp.Status)
+
+	failing := NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		return nil, assert.AnError
+	})
+	_, err = RunTraced(context.Background(), failing, &SimulationRequest{})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestRunWithContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := RunWithContext(ctx, &mockRunnerForTest{}, &SimulationRequest{})
+	assert.ErrorIs(t, err, errors.ErrSimCanceled)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRunnerRunContextStopsSimulator(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the simulator")
+	}
+	bin := filepath.Join(t.TempDir(), "erst-sim")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\nexec sleep 30\n"), 0o755); err != nil {
+		t.Fatalf("write fake simulator: %v", err)
+	}
+	runner := &Runner{BinaryPath: bin}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := runner.RunContext(ctx, &SimulationRequest{})
+
+	assert.ErrorIs(t, err, errors.ErrSimCanceled)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 10*time.Second, "expected the simulator to be stopped")
+}
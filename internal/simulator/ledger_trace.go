@@ -0,0 +1,91 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"github.com/dotandev/hintents/internal/decoder"
+)
+
+// Access modes of a LedgerAccess
+const (
+	LedgerReadOnly  = "read_only"
+	LedgerReadWrite = "read_write"
+)
+
+// Changes a simulation made to a read-write ledger entry
+const (
+	LedgerCreated   = "created"
+	LedgerUpdated   = "updated"
+	LedgerDeleted   = "deleted"
+	LedgerUnchanged = "unchanged"
+)
+
+// LedgerAccess is one ledger entry the host read or wrote, with its state
+// before and after the simulation
+type LedgerAccess struct {
+	Key    string `json:"key"`    // Base64 XDR LedgerKey
+	Access string `json:"access"` // LedgerReadOnly or LedgerReadWrite
+	// Change is set for read-write entries when the simulator reported their
+	// final state
+	Change string `json:"change,omitempty"`
+	Before string `json:"before,omitempty"` // Base64 XDR LedgerEntry; empty when absent
+	After  string `json:"after,omitempty"`  // Base64 XDR LedgerEntry; empty when absent or read-only
+	// BeforeValue and AfterValue are Before and After decoded
+	BeforeValue string `json:"before_value,omitempty"`
+	AfterValue  string `json:"after_value,omitempty"`
+}
+
+// BuildLedgerTrace lists every entry the host accessed, in the simulator's
+// order, with its value from the ledger state the simulation started from
+// and, for read-write entries, the value it left. Simulators that do not
+// report accesses only yield their writes.
+func BuildLedgerTrace(state map[string]string, accesses []StorageAccess, writes []StorageWrite) []LedgerAccess {
+	after := make(map[string]StorageWrite, len(writes))
+	for _, w := range writes {
+		after[w.Key] = w
+	}
+	if len(accesses) == 0 {
+		for _, w := range writes {
+			accesses = append(accesses, StorageAccess{Key: w.Key, ReadWrite: true})
+		}
+	}
+
+	trace := make([]LedgerAccess, 0, len(accesses))
+	for _, a := range accesses {
+		la := LedgerAccess{Key: a.Key, Access: LedgerReadOnly, Before: state[a.Key]}
+		la.BeforeValue = ledgerEntryValue(la.Before)
+		if a.ReadWrite {
+			la.Access = LedgerReadWrite
+			if w, ok := after[a.Key]; ok {
+				la.After = w.Entry
+				la.AfterValue = ledgerEntryValue(la.After)
+				la.Change = ledgerChange(la.Before, la.After)
+			}
+		}
+		trace = append(trace, la)
+	}
+	return trace
+}
+
+func ledgerChange(before, after string) string {
+	switch {
+	case before == "" && after != "":
+		return LedgerCreated
+	case before != "" && after == "":
+		return LedgerDeleted
+	case before != after:
+		return LedgerUpdated
+	}
+	return LedgerUnchanged
+}
+
+func ledgerEntryValue(entry string) string {
+	if entry == "" {
+		return ""
+	}
+	if value, err := decoder.FormatLedgerEntryBase64(entry); err == nil {
+		return value
+	}
+	return entry
+}
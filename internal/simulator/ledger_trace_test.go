@@ -0,0 +1,67 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func contractDataFixture(t *testing.T, name string, value uint32) (key, entry string) {
+	t.Helper()
+	contract := xdr.ContractId{7}
+	data := xdr.ContractDataEntry{
+		Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contract},
+		Key:        xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: symPtr(xdr.ScSymbol(name))},
+		Durability: xdr.ContractDataDurabilityPersistent,
+		Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: u32Ptr(xdr.Uint32(value))},
+	}
+	ledgerEntry := xdr.LedgerEntry{Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeContractData, ContractData: &data}}
+	ledgerKey, err := ledgerEntry.LedgerKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key, err = xdr.MarshalBase64(ledgerKey); err != nil {
+		t.Fatal(err)
+	}
+	if entry, err = xdr.MarshalBase64(ledgerEntry); err != nil {
+		t.Fatal(err)
+	}
+	return key, entry
+}
+
+func symPtr(s xdr.ScSymbol) *xdr.ScSymbol { return &s }
+func u32Ptr(v xdr.Uint32) *xdr.Uint32     { return &v }
+
+func TestBuildLedgerTrace(t *testing.T) {
+	admin, adminEntry := contractDataFixture(t, "Admin", 1)
+	balance, balanceBefore := contractDataFixture(t, "Balance", 100)
+	_, balanceAfter := contractDataFixture(t, "Balance", 50)
+	counter, counterEntry := contractDataFixture(t, "Counter", 3)
+
+	state := map[string]string{admin: adminEntry, balance: balanceBefore}
+	accesses := []StorageAccess{{Key: admin}, {Key: balance, ReadWrite: true}, {Key: counter, ReadWrite: true}}
+	writes := []StorageWrite{{Key: balance, Entry: balanceAfter}, {Key: counter, Entry: counterEntry}}
+
+	trace := BuildLedgerTrace(state, accesses, writes)
+	if len(trace) != 3 {
+		t.Fatalf("trace = %+v", trace)
+	}
+	if trace[0].Access != LedgerReadOnly || trace[0].Change != "" || trace[0].BeforeValue != "1" || trace[0].After != "" {
+		t.Errorf("read = %+v", trace[0])
+	}
+	if trace[1].Change != LedgerUpdated || trace[1].BeforeValue != "100" || trace[1].AfterValue != "50" {
+		t.Errorf("update = %+v", trace[1])
+	}
+	if trace[2].Change != LedgerCreated || trace[2].Before != "" || trace[2].AfterValue != "3" {
+		t.Errorf("create = %+v", trace[2])
+	}
+
+	// Without reported accesses only the writes are known
+	trace = BuildLedgerTrace(state, nil, []StorageWrite{{Key: balance}})
+	if len(trace) != 1 || trace[0].Change != LedgerDeleted {
+		t.Errorf("writes only = %+v", trace)
+	}
+}
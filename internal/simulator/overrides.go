@@ -0,0 +1,56 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// ValidateLedgerOverride checks that an override pairs a decodable LedgerKey
+// with a LedgerEntry stored under that same key
+func ValidateLedgerOverride(keyXdr, entryXdr string) error {
+	var key xdr.LedgerKey
+	if err := xdr.SafeUnmarshalBase64(keyXdr, &key); err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("override key is not a valid LedgerKey: %v", err))
+	}
+
+	var entry xdr.LedgerEntry
+	if err := xdr.SafeUnmarshalBase64(entryXdr, &entry); err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("override value is not a valid LedgerEntry: %v", err))
+	}
+
+	entryKey, err := entry.LedgerKey()
+	if err != nil {
+		return errors.WrapValidationError(fmt.Sprintf("cannot derive key for override entry: %v", err))
+	}
+	if !key.Equals(entryKey) {
+		return errors.WrapValidationError("override entry does not belong to the given ledger key")
+	}
+	return nil
+}
+
+// applyLedgerOverrides merges LedgerEntryOverrides into LedgerEntries so the
+// simulator sees the overridden state. The original map is copied rather than
+// mutated because callers may share it between requests.
+func (req *SimulationRequest) applyLedgerOverrides() error {
+	if len(req.LedgerEntryOverrides) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]string, len(req.LedgerEntries)+len(req.LedgerEntryOverrides))
+	for k, v := range req.LedgerEntries {
+		merged[k] = v
+	}
+	for k, v := range req.LedgerEntryOverrides {
+		if err := ValidateLedgerOverride(k, v); err != nil {
+			return err
+		}
+		merged[k] = v
+	}
+	req.LedgerEntries = merged
+	return nil
+}
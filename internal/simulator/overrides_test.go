@@ -0,0 +1,74 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func accountOverride(t *testing.T, address string, balance int64) (string, string) {
+	t.Helper()
+
+	accountID := xdr.MustAddress(address)
+	entry := xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{
+				AccountId: accountID,
+				Balance:   xdr.Int64(balance),
+			},
+		},
+	}
+	key, err := entry.LedgerKey()
+	require.NoError(t, err)
+
+	keyXdr, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	entryXdr, err := xdr.MarshalBase64(entry)
+	require.NoError(t, err)
+	return keyXdr, entryXdr
+}
+
+const (
+	overrideAccountA = "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+	overrideAccountB = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+)
+
+func TestValidateLedgerOverride(t *testing.T) {
+	keyA, entryA := accountOverride(t, overrideAccountA, 100)
+	keyB, _ := accountOverride(t, overrideAccountB, 100)
+
+	assert.NoError(t, ValidateLedgerOverride(keyA, entryA))
+	assert.Error(t, ValidateLedgerOverride(keyB, entryA), "entry stored under a different key")
+	assert.Error(t, ValidateLedgerOverride("not-xdr", entryA))
+	assert.Error(t, ValidateLedgerOverride(keyA, "not-xdr"))
+}
+
+func TestApplyLedgerOverrides(t *testing.T) {
+	keyA, entryA := accountOverride(t, overrideAccountA, 100)
+	_, entryA2 := accountOverride(t, overrideAccountA, 999)
+	keyB, entryB := accountOverride(t, overrideAccountB, 5)
+
+	original := map[string]string{keyA: entryA}
+	req := &SimulationRequest{
+		LedgerEntries:        original,
+		LedgerEntryOverrides: map[string]string{keyA: entryA2, keyB: entryB},
+	}
+
+	require.NoError(t, req.applyLedgerOverrides())
+	assert.Equal(t, map[string]string{keyA: entryA2, keyB: entryB}, req.LedgerEntries)
+	assert.Equal(t, entryA, original[keyA], "caller's map must not be mutated")
+}
+
+func TestApplyLedgerOverrides_Invalid(t *testing.T) {
+	keyA, _ := accountOverride(t, overrideAccountA, 100)
+	_, entryB := accountOverride(t, overrideAccountB, 5)
+
+	req := &SimulationRequest{LedgerEntryOverrides: map[string]string{keyA: entryB}}
+	assert.Error(t, req.applyLedgerOverrides())
+}
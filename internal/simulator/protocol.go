@@ -147,3 +147,18 @@ func MergeFeatures(version uint32, custom map[string]interface{}) map[string]int
 	}
 	return result
 }
+
+// ProtocolSkew describes how a simulation with protocol simVersion differs
+// from a network running networkVersion, or returns "" when they match
+func ProtocolSkew(networkVersion, simVersion uint32) string {
+	if networkVersion == simVersion {
+		return ""
+	}
+	if err := Validate(networkVersion); err != nil {
+		supported := Supported()
+		return fmt.Sprintf("the network runs protocol %d, which the simulator does not support (supported: %d-%d); simulating with protocol %d",
+			networkVersion, supported[0], supported[len(supported)-1], simVersion)
+	}
+	return fmt.Sprintf("the network runs protocol %d but the simulation uses protocol %d; pass --protocol-version %d to match",
+		networkVersion, simVersion, networkVersion)
+}
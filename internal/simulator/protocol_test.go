@@ -4,6 +4,7 @@
 package simulator
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -121,6 +122,18 @@ func TestSupported(t *testing.T) {
 	}
 }
 
+func TestProtocolSkew(t *testing.T) {
+	if msg := ProtocolSkew(22, 22); msg != "" {
+		t.Errorf("matching versions reported skew: %s", msg)
+	}
+	if msg := ProtocolSkew(21, 22); !strings.Contains(msg, "--protocol-version 21") {
+		t.Errorf("supported network version: %s", msg)
+	}
+	if msg := ProtocolSkew(99, 22); !strings.Contains(msg, "does not support") || !strings.Contains(msg, "20-22") {
+		t.Errorf("unsupported network version: %s", msg)
+	}
+}
+
 func TestMergeFeatures(t *testing.T) {
 	custom := map[string]interface{}{
 		"custom_limit":      999999,
@@ -0,0 +1,205 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// ResultCacheDirName is the directory under ~/.erst/cache holding simulation
+// results
+const ResultCacheDirName = "simulations"
+
+// ResultCache stores simulation responses on disk so that an envelope that
+// was already simulated against the same ledger state is not simulated again.
+// Entries are keyed by the SHA-256 of the envelope and a snapshot ID covering
+// everything else the simulator is given. Like other cached files they are
+// evicted by `erst cache clean`.
+//
+// Layout: <dir>/<envelope-hash>/<snapshot-id>.json
+type ResultCache struct {
+	dir string
+}
+
+// NewResultCache returns a cache rooted at dir
+func NewResultCache(dir string) *ResultCache {
+	return &ResultCache{dir: dir}
+}
+
+// DefaultResultCache returns the cache at ~/.erst/cache/simulations
+func DefaultResultCache() (*ResultCache, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, errors.WrapValidationError(fmt.Sprintf("failed to get user home directory: %v", err))
+	}
+	return NewResultCache(filepath.Join(home, ".erst", "cache", ResultCacheDirName)), nil
+}
+
+// Dir returns the directory backing the cache
+func (c *ResultCache) Dir() string {
+	return c.dir
+}
+
+// ResultCacheKey returns the envelope hash and snapshot ID for req. The
+// snapshot ID hashes the ledger entries, overrides, ledger sequence,
+// timestamp, protocol and every other option of the request, so any change to
+// the state or settings is a miss. simulator identifies the binary that runs
+// the request, so that upgrading it invalidates earlier results.
+//
+// Requests replaying a local WASM file are not cached, since the file can
+// change between runs; ok is false for them.
+func ResultCacheKey(req *SimulationRequest, simulator string) (envelopeHash, snapshotID string, ok bool) {
+	if req.EnvelopeXdr == "" || req.WasmPath != nil {
+		return "", "", false
+	}
+	envelope := sha256.Sum256([]byte(req.EnvelopeXdr))
+
+	state := *req
+	state.EnvelopeXdr = ""
+	raw, err := json.Marshal(struct {
+		Request   SimulationRequest `json:"request"`
+		Simulator string            `json:"simulator,omitempty"`
+	}{state, simulator})
+	if err != nil {
+		return "", "", false
+	}
+	snapshot := sha256.Sum256(raw)
+	return hex.EncodeToString(envelope[:]), hex.EncodeToString(snapshot[:]), true
+}
+
+func (c *ResultCache) path(envelopeHash, snapshotID string) string {
+	return filepath.Join(c.dir, envelopeHash, snapshotID+".json")
+}
+
+// Get returns the cached response for a key. Unreadable entries are treated
+// as misses and removed.
+func (c *ResultCache) Get(envelopeHash, snapshotID string) (*SimulationResponse, bool, error) {
+	path := c.path(envelopeHash, snapshotID)
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var resp SimulationResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		os.Remove(path)
+		return nil, false, nil
+	}
+
+	// Cache cleanup is LRU by modification time, so mark the entry as used
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return &resp, true, nil
+}
+
+// Put stores resp under a key, replacing any previous entry
+func (c *ResultCache) Put(envelopeHash, snapshotID string, resp *SimulationResponse) error {
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		return errors.WrapMarshalFailed(err)
+	}
+	dir := filepath.Dir(c.path(envelopeHash, snapshotID))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	// Write through a temporary file so that concurrent batch workers never
+	// read a partial entry
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(envelopeHash, snapshotID))
+}
+
+// CachingRunner is a RunnerInterface that answers repeated requests from a
+// ResultCache and only runs the wrapped runner on a miss. Errors are never
+// cached, so a crashed or canceled simulation is retried next time.
+type CachingRunner struct {
+	Runner RunnerInterface
+	Cache  *ResultCache
+}
+
+var _ ContextRunner = (*CachingRunner)(nil)
+
+// NewCachingRunner wraps runner with cache
+func NewCachingRunner(runner RunnerInterface, cache *ResultCache) *CachingRunner {
+	return &CachingRunner{Runner: runner, Cache: cache}
+}
+
+func (r *CachingRunner) Run(req *SimulationRequest) (*SimulationResponse, error) {
+	return r.RunContext(context.Background(), req)
+}
+
+// RunContext is Run passing ctx on to the wrapped runner
+func (r *CachingRunner) RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
+	// The key must be taken before running, as the runner rewrites req
+	envelopeHash, snapshotID, ok := ResultCacheKey(r.effectiveRequest(req), r.simulatorID())
+	if ok {
+		resp, hit, err := r.Cache.Get(envelopeHash, snapshotID)
+		if err != nil {
+			logger.Logger.Warn("Ignoring unreadable cached simulation result", "error", err)
+		} else if hit {
+			logger.Logger.Debug("Simulation result loaded from cache", "envelope", envelopeHash, "snapshot", snapshotID)
+			return resp, nil
+		}
+	}
+
+	resp, err := RunWithContext(ctx, r.Runner, req)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if err := r.Cache.Put(envelopeHash, snapshotID, resp); err != nil {
+			logger.Logger.Warn("Failed to cache simulation result", "error", err)
+		}
+	}
+	return resp, nil
+}
+
+// effectiveRequest returns req as the wrapped runner will see it
+func (r *CachingRunner) effectiveRequest(req *SimulationRequest) *SimulationRequest {
+	if runner, ok := r.Runner.(*Runner); ok && runner.MockTime != 0 {
+		copied := *req
+		copied.Timestamp = runner.MockTime
+		return &copied
+	}
+	return req
+}
+
+// simulatorID identifies the erst-sim binary by path, size and modification
+// time, which change whenever it is rebuilt or upgraded
+func (r *CachingRunner) simulatorID() string {
+	runner, ok := r.Runner.(*Runner)
+	if !ok {
+		return ""
+	}
+	info, err := os.Stat(runner.BinaryPath)
+	if err != nil {
+		return runner.BinaryPath
+	}
+	return fmt.Sprintf("%s:%d:%d", runner.BinaryPath, info.Size(), info.ModTime().UnixNano())
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCachingRunner(t *testing.T) {
+	runs := 0
+	mock := NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		runs++
+		return &SimulationResponse{Status: "success", Events: []string{req.EnvelopeXdr}}, nil
+	})
+	runner := NewCachingRunner(mock, NewResultCache(t.TempDir()))
+
+	req := func() *SimulationRequest {
+		return &SimulationRequest{
+			EnvelopeXdr:    "AAAA",
+			LedgerEntries:  map[string]string{"key": "entry"},
+			LedgerSequence: 100,
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := runner.Run(req())
+		if err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+		if len(resp.Events) != 1 || resp.Events[0] != "AAAA" {
+			t.Fatalf("run %d: unexpected response %+v", i, resp)
+		}
+	}
+	if runs != 1 {
+		t.Errorf("expected the identical request to be served from cache, simulated %d times", runs)
+	}
+
+	changed := req()
+	changed.LedgerEntries["key"] = "other"
+	if _, err := runner.Run(changed); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 2 {
+		t.Errorf("expected different ledger state to miss the cache, simulated %d times", runs)
+	}
+
+	wasm := "contract.wasm"
+	local := req()
+	local.WasmPath = &wasm
+	for i := 0; i < 2; i++ {
+		if _, err := runner.Run(local); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if runs != 4 {
+		t.Errorf("expected local WASM replays to bypass the cache, simulated %d times", runs)
+	}
+}
+
+func TestCachingRunnerDoesNotCacheErrors(t *testing.T) {
+	runs := 0
+	mock := NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		runs++
+		return nil, errors.New("simulator crashed")
+	})
+	runner := NewCachingRunner(mock, NewResultCache(t.TempDir()))
+
+	for i := 0; i < 2; i++ {
+		if _, err := runner.Run(&SimulationRequest{EnvelopeXdr: "AAAA"}); err == nil {
+			t.Fatal("expected the error to be returned")
+		}
+	}
+	if runs != 2 {
+		t.Errorf("expected a failed simulation to be retried, simulated %d times", runs)
+	}
+}
+
+func TestResultCacheKeyMockTime(t *testing.T) {
+	req := &SimulationRequest{EnvelopeXdr: "AAAA", Timestamp: 1}
+	plain := NewCachingRunner(&Runner{}, nil)
+	mocked := NewCachingRunner(&Runner{MockTime: 2}, nil)
+
+	_, a, _ := ResultCacheKey(plain.effectiveRequest(req), "")
+	_, b, _ := ResultCacheKey(mocked.effectiveRequest(req), "")
+	if a == b {
+		t.Error("expected --mock-time to change the snapshot ID")
+	}
+	if req.Timestamp != 1 {
+		t.Error("expected the request to be left unchanged")
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"time"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Compile-time check to ensure RPCRunner implements ContextRunner
+var _ ContextRunner = (*RPCRunner)(nil)
+
+// RPCRunner simulates with the RPC node's simulateTransaction instead of the
+// local erst-sim binary, for machines where the simulator cannot run. The node
+// simulates against its current ledger state under the network's protocol,
+// so the request's ledger entries, overrides, timestamp and protocol version
+// are not used, and the response carries no flamegraph, per-frame budgets or
+// storage writes.
+type RPCRunner struct {
+	Client *rpc.Client
+}
+
+// NewRPCRunner creates an RPCRunner that simulates through client
+func NewRPCRunner(client *rpc.Client) *RPCRunner {
+	return &RPCRunner{Client: client}
+}
+
+// Run implements RunnerInterface
+func (r *RPCRunner) Run(req *SimulationRequest) (*SimulationResponse, error) {
+	return r.RunContext(context.Background(), req)
+}
+
+// RunContext implements ContextRunner
+func (r *RPCRunner) RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
+	start := time.Now()
+	preflight, err := r.Client.SimulateTransaction(ctx, req.EnvelopeXdr)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.WrapSimCanceled(ctx.Err())
+		}
+		return nil, err
+	}
+	resp := FromRPCSimulation(preflight)
+	resp.DurationMs = time.Since(start).Milliseconds()
+	return resp, nil
+}
+
+// FromRPCSimulation converts a simulateTransaction result into the response
+// the local simulator would give
+func FromRPCSimulation(preflight *rpc.SimulateTransactionResponse) *SimulationResponse {
+	res := preflight.Result
+	resp := &SimulationResponse{Status: "success", Error: res.Error, Events: res.Events}
+	if res.Error != "" {
+		resp.Status = "error"
+	}
+	cpu, mem := RPCCost(preflight)
+	resp.BudgetUsage = &BudgetUsage{CPUInstructions: cpu, MemoryBytes: mem, OperationsCount: len(res.Results)}
+	for _, result := range res.Results {
+		resp.ReturnValues = append(resp.ReturnValues, result.Xdr)
+	}
+	for _, raw := range res.Events {
+		if event, ok := diagnosticEventFromXdr(raw); ok {
+			resp.DiagnosticEvents = append(resp.DiagnosticEvents, event)
+		}
+	}
+	return resp
+}
+
+// RPCCost returns the CPU instructions and memory bytes a simulateTransaction
+// result reports. Nodes that no longer report cost give the CPU instructions
+// through the transaction data's resources, and no memory figure.
+func RPCCost(preflight *rpc.SimulateTransactionResponse) (cpu, mem uint64) {
+	res := preflight.Result
+	c, m := res.Cost.CpuInsns, res.Cost.MemBytes
+	if c == 0 {
+		c = res.Cost.CpuInsns_
+	}
+	if m == 0 {
+		m = res.Cost.MemBytes_
+	}
+	if c == 0 && res.TransactionData != "" {
+		var data xdr.SorobanTransactionData
+		if err := xdr.SafeUnmarshalBase64(res.TransactionData, &data); err == nil {
+			c = int64(data.Resources.Instructions)
+		}
+	}
+	return uint64(max(c, 0)), uint64(max(m, 0))
+}
+
+func diagnosticEventFromXdr(raw string) (DiagnosticEvent, bool) {
+	var diag xdr.DiagnosticEvent
+	if err := xdr.SafeUnmarshalBase64(raw, &diag); err != nil || diag.Event.Body.V0 == nil {
+		return DiagnosticEvent{}, false
+	}
+	event := DiagnosticEvent{InSuccessfulContractCall: diag.InSuccessfulContractCall}
+	switch diag.Event.Type {
+	case xdr.ContractEventTypeContract:
+		event.EventType = "contract"
+	case xdr.ContractEventTypeSystem:
+		event.EventType = "system"
+	default:
+		event.EventType = "diagnostic"
+	}
+	if diag.Event.ContractId != nil {
+		if id, err := strkey.Encode(strkey.VersionByteContract, diag.Event.ContractId[:]); err == nil {
+			event.ContractID = &id
+		}
+	}
+
+	body := diag.Event.Body.V0
+	// TopicsXdr lines up with Topics, empty for a topic that cannot be encoded
+	for _, topic := range body.Topics {
+		b64, err := xdr.MarshalBase64(topic)
+		if err != nil {
+			b64 = ""
+		}
+		event.TopicsXdr = append(event.TopicsXdr, b64)
+		event.Topics = append(event.Topics, decoder.RenderScVal(b64, topic.String()))
+	}
+	if b64, err := xdr.MarshalBase64(body.Data); err == nil {
+		event.DataXdr = b64
+		event.Data = decoder.RenderScVal(b64, b64)
+	}
+	return event, true
+}
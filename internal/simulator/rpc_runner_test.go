@@ -0,0 +1,88 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestRPCRunner(t *testing.T) {
+	contractID := xdr.ContractId{1, 2, 3}
+	event, err := xdr.MarshalBase64(xdr.DiagnosticEvent{
+		InSuccessfulContractCall: true,
+		Event: xdr.ContractEvent{
+			ContractId: &contractID,
+			Type:       xdr.ContractEventTypeContract,
+			Body: xdr.ContractEventBody{V0: &xdr.ContractEventV0{
+				Topics: []xdr.ScVal{{Type: xdr.ScValTypeScvBool, B: boolPtr(true)}},
+				Data:   xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var params []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string   `json:"method"`
+			Params []string `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		params = req.Params
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result": map[string]interface{}{
+				"cost":    map[string]int64{"cpuInsns": 1200, "memBytes": 3400},
+				"events":  []string{event},
+				"results": []map[string]string{{"xdr": "AAAAAQ=="}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := rpc.NewClient(rpc.WithNetwork(rpc.Testnet), rpc.WithHorizonURL(server.URL), rpc.WithSorobanURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := RunWithContext(context.Background(), NewRPCRunner(client), &SimulationRequest{EnvelopeXdr: "ENVELOPE"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(params) != 1 || params[0] != "ENVELOPE" {
+		t.Errorf("simulateTransaction params = %v", params)
+	}
+	if resp.Status != "success" || resp.BudgetUsage.CPUInstructions != 1200 || resp.BudgetUsage.MemoryBytes != 3400 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if len(resp.ReturnValues) != 1 || resp.ReturnValues[0] != "AAAAAQ==" {
+		t.Errorf("return values = %v", resp.ReturnValues)
+	}
+	if len(resp.DiagnosticEvents) != 1 {
+		t.Fatalf("diagnostic events = %+v", resp.DiagnosticEvents)
+	}
+	got := resp.DiagnosticEvents[0]
+	if got.EventType != "contract" || got.ContractID == nil || (*got.ContractID)[0] != 'C' || len(got.TopicsXdr) != 1 || got.DataXdr == "" {
+		t.Errorf("diagnostic event = %+v", got)
+	}
+}
+
+func TestFromRPCSimulation_Error(t *testing.T) {
+	var preflight rpc.SimulateTransactionResponse
+	preflight.Result.Error = "HostError: Error(Contract, #3)"
+	resp := FromRPCSimulation(&preflight)
+	if resp.Status != "error" || resp.Error != preflight.Result.Error {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
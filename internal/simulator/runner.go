@@ -5,14 +5,19 @@ package simulator
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/dotandev/hintents/internal/errors"
 	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/metrics"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/snapshot"
 )
 
 // Runner handles the execution of the Rust simulator binary
@@ -23,7 +28,14 @@ type Runner struct {
 }
 
 // Compile-time check to ensure Runner implements RunnerInterface
-var _ RunnerInterface = (*Runner)(nil)
+var (
+	_ RunnerInterface = (*Runner)(nil)
+	_ ContextRunner   = (*Runner)(nil)
+)
+
+// simulatorStopGrace is how long a canceled simulator is given to exit after
+// an interrupt before it is killed
+const simulatorStopGrace = 2 * time.Second
 
 // NewRunner creates a new simulator runner.
 // Search order:
@@ -141,51 +153,96 @@ func abs(path string) string {
 // -------------------- Execution --------------------
 
 func (r *Runner) Run(req *SimulationRequest) (*SimulationResponse, error) {
-	proto := GetOrDefault(req.ProtocolVersion)
-
-	if req.ProtocolVersion != nil {
-		if err := Validate(*req.ProtocolVersion); err != nil {
-			return nil, err
-		}
-	}
-
-	if err := r.applyProtocolConfig(req, proto); err != nil {
-		return nil, err
-	}
-
-	if r.MockTime != 0 {
-		req.Timestamp = r.MockTime
-	}
+	return r.RunContext(context.Background(), req)
+}
 
-	inputBytes, err := json.Marshal(req)
+// RunContext is Run stopping the simulator when ctx is done. The process is
+// interrupted first and killed if it has not exited after a grace period.
+func (r *Runner) RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
+	proto, inputBytes, err := r.prepareRequest(req)
 	if err != nil {
-		logger.Logger.Error("Failed to marshal simulation request", "error", err)
-		return nil, errors.WrapMarshalFailed(err)
+		return nil, err
 	}
 
-	cmd := exec.Command(r.BinaryPath)
+	cmd := exec.CommandContext(ctx, r.BinaryPath)
 	cmd.Stdin = bytes.NewReader(inputBytes)
+	cmd.Cancel = func() error {
+		// Interrupts are not supported on Windows
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = simulatorStopGrace
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	start := time.Now()
 	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			metrics.ObserveSimulation(time.Since(start), "canceled", "")
+			logger.Logger.Debug("Simulation canceled", "error", ctxErr)
+			return nil, errors.WrapSimCanceled(ctxErr)
+		}
+		metrics.ObserveSimulation(time.Since(start), "crash", stderr.String())
 		logger.Logger.Error("Simulator execution failed", "error", err, "stderr", stderr.String())
 		return nil, errors.WrapSimCrash(err, stderr.String())
 	}
 
 	var resp SimulationResponse
 	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		metrics.ObserveSimulation(time.Since(start), "crash", "")
 		logger.Logger.Error("Failed to unmarshal response", "error", err)
 		return nil, errors.WrapUnmarshalFailed(err, stdout.String())
 	}
+	elapsed := time.Since(start)
+	metrics.ObserveSimulation(elapsed, resp.Status, resp.Error)
 
+	resp.DurationMs = elapsed.Milliseconds()
 	resp.ProtocolVersion = &proto.Version
+	resp.CallTree = BuildCallTree(resp.DiagnosticEvents, resp.CallBudgets)
+	resp.FunctionCosts = FunctionCosts(resp.CallTree)
+	resp.LedgerTrace = BuildLedgerTrace(req.LedgerEntries, resp.StorageAccesses, resp.StorageWrites)
+	resp.Snapshot = snapshot.NewPin(req.LedgerSequence, req.LedgerEntries)
+	resp.ErrorExplanation = ExplainError(&resp)
 
 	return &resp, nil
 }
 
+// prepareRequest applies protocol, override and mock-time settings to req and
+// returns the JSON payload for the simulator
+func (r *Runner) prepareRequest(req *SimulationRequest) (*Protocol, []byte, error) {
+	proto := GetOrDefault(req.ProtocolVersion)
+
+	if req.ProtocolVersion != nil {
+		if err := Validate(*req.ProtocolVersion); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := r.applyProtocolConfig(req, proto); err != nil {
+		return nil, nil, err
+	}
+
+	if err := req.applyLedgerOverrides(); err != nil {
+		return nil, nil, err
+	}
+
+	if r.MockTime != 0 {
+		req.Timestamp = r.MockTime
+	}
+	req.EnvelopeXdr = rpc.InnerEnvelope(req.EnvelopeXdr)
+
+	inputBytes, err := json.Marshal(req)
+	if err != nil {
+		logger.Logger.Error("Failed to marshal simulation request", "error", err)
+		return nil, nil, errors.WrapMarshalFailed(err)
+	}
+	return proto, inputBytes, nil
+}
+
 func (r *Runner) applyProtocolConfig(req *SimulationRequest, proto *Protocol) error {
 	if req.CustomAuthCfg == nil {
 		req.CustomAuthCfg = make(map[string]interface{})
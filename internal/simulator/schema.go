@@ -4,13 +4,9 @@
 package simulator
 
 import (
-	"database/sql"
-	"os"
-	"path/filepath"
-	"time"
-
 	"github.com/dotandev/hintents/internal/authtrace"
-	_ "modernc.org/sqlite"
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/snapshot"
 )
 
 // SimulationRequest is the JSON object passed to the Rust binary via Stdin
@@ -23,11 +19,21 @@ type SimulationRequest struct {
 	WasmPath        *string           `json:"wasm_path,omitempty"`
 	MockArgs        *[]string         `json:"mock_args,omitempty"`
 	Profile         bool              `json:"profile,omitempty"`
+	ProfileMemory   bool              `json:"profile_memory,omitempty"` // Split frame memory into host allocations and linear memory
 	ProtocolVersion *uint32           `json:"protocol_version,omitempty"`
 
 	AuthTraceOpts       *AuthTraceOptions      `json:"auth_trace_opts,omitempty"`
 	CustomAuthCfg       map[string]interface{} `json:"custom_auth_config,omitempty"`
 	ResourceCalibration *ResourceCalibration   `json:"resource_calibration,omitempty"`
+
+	// LedgerEntryOverrides replaces or injects entries on top of LedgerEntries
+	// before simulation. Keys are base64 LedgerKey XDR, values base64 LedgerEntry XDR.
+	LedgerEntryOverrides map[string]string `json:"ledger_entry_overrides,omitempty"`
+
+	// CPULimit and MemoryLimit replace the host's instruction and memory
+	// budgets when set
+	CPULimit    *uint64 `json:"cpu_limit,omitempty"`
+	MemoryLimit *uint64 `json:"mem_limit,omitempty"`
 }
 
 type ResourceCalibration struct {
@@ -52,6 +58,12 @@ type DiagnosticEvent struct {
 	Topics                   []string `json:"topics"`
 	Data                     string   `json:"data"`
 	InSuccessfulContractCall bool     `json:"in_successful_contract_call"`
+
+	// Base64 XDR of each topic and of the data, when the simulator provides
+	// them. TopicsXdr lines up with Topics, with "" for a topic the simulator
+	// could not encode.
+	TopicsXdr []string `json:"topics_xdr,omitempty"`
+	DataXdr   string   `json:"data_xdr,omitempty"`
 }
 
 // BudgetUsage represents resource consumption during simulation
@@ -72,13 +84,61 @@ type SimulationResponse struct {
 	DiagnosticEvents  []DiagnosticEvent    `json:"diagnostic_events,omitempty"` // Structured diagnostic events
 	Logs              []string             `json:"logs,omitempty"`              // Host debug logs
 	Flamegraph        string               `json:"flamegraph,omitempty"`        // SVG flamegraph
+	FoldedStacks      string               `json:"folded_stacks,omitempty"`     // Folded stacks behind the flamegraph
 	AuthTrace         *authtrace.AuthTrace `json:"auth_trace,omitempty"`
-	BudgetUsage       *BudgetUsage         `json:"budget_usage,omitempty"` // Resource consumption metrics
+	BudgetUsage       *BudgetUsage         `json:"budget_usage,omitempty"`     // Resource consumption metrics
+	DurationMs        int64                `json:"duration_ms,omitempty"`      // Wall-clock time the simulator took
+	ReturnValues      []string             `json:"return_values,omitempty"`    // Base64 XDR ScVal per invoked host function
+	StorageWrites     []StorageWrite       `json:"storage_writes,omitempty"`   // Final state of read-write footprint entries
+	StorageAccesses   []StorageAccess      `json:"storage_accesses,omitempty"` // Every ledger key the host accessed
+	LedgerTrace       []LedgerAccess       `json:"ledger_trace,omitempty"`     // Accessed entries with their values before and after
+	CallBudgets       []FrameBudget        `json:"call_budgets,omitempty"`     // Budget per host frame, in push order
+	CallTree          []*CallNode          `json:"call_tree,omitempty"`        // Contract calls rebuilt from diagnostic events
+	FunctionCosts     []FunctionCost       `json:"function_costs,omitempty"`   // Cost per contract function, most expensive first
 	CategorizedEvents []CategorizedEvent   `json:"categorized_events,omitempty"`
 	ProtocolVersion   *uint32              `json:"protocol_version,omitempty"` // Protocol version used
 	StackTrace        *WasmStackTrace      `json:"stack_trace,omitempty"`      // Enhanced WASM stack trace on traps
 	SourceLocation    string               `json:"source_location,omitempty"`
 	WasmOffset        *uint64              `json:"wasm_offset,omitempty"`
+	Archival          *ArchivalReport      `json:"archival,omitempty"` // TTL state of footprint entries
+	// Snapshot pins the ledger state the simulation ran against, so a replay
+	// can check it uses the same entries
+	Snapshot *snapshot.Pin `json:"snapshot,omitempty"`
+	// ErrorExplanation describes Error in plain words, with the contract's
+	// own name for a contract error when its spec is available
+	ErrorExplanation *decoder.ErrorExplanation `json:"error_explanation,omitempty"`
+}
+
+// FrameBudget is the budget one host frame consumed, sub-calls included.
+// HostFnCalls counts only the host functions the frame called itself.
+type FrameBudget struct {
+	Depth       int    `json:"depth"`
+	CPUInsns    uint64 `json:"cpu_insns"`
+	MemBytes    uint64 `json:"mem_bytes"`
+	HostFnCalls uint64 `json:"host_fn_calls"`
+	// HostAllocs and LinearMemBytes split the frame's own memory when the
+	// request enabled ProfileMemory
+	HostAllocs     []HostAlloc `json:"host_allocs,omitempty"`
+	LinearMemBytes *uint64     `json:"linear_mem_bytes,omitempty"`
+}
+
+// HostAlloc is the memory a frame's calls to one host function allocated
+type HostAlloc struct {
+	Name     string `json:"name"`
+	Calls    uint64 `json:"calls"`
+	MemBytes uint64 `json:"mem_bytes"`
+}
+
+// StorageAccess is one ledger key the host accessed during execution
+type StorageAccess struct {
+	Key       string `json:"key"` // Base64 XDR LedgerKey
+	ReadWrite bool   `json:"read_write"`
+}
+
+// StorageWrite is the post-execution state of one entry the transaction could write
+type StorageWrite struct {
+	Key   string `json:"key"`             // Base64 XDR LedgerKey
+	Entry string `json:"entry,omitempty"` // Base64 XDR LedgerEntry; empty when deleted
 }
 
 type CategorizedEvent struct {
@@ -96,17 +156,6 @@ type SecurityViolation struct {
 	Details     map[string]interface{} `json:"details,omitempty"`
 }
 
-// Session represents a stored simulation result
-type Session struct {
-	ID        int64     `json:"id"`
-	TxHash    string    `json:"tx_hash"`
-	Network   string    `json:"network"`
-	Timestamp time.Time `json:"timestamp"`
-	Error     string    `json:"error,omitempty"`
-	Events    string    `json:"events,omitempty"` // JSON string
-	Logs      string    `json:"logs,omitempty"`   // JSON string
-}
-
 // WasmStackTrace holds a structured WASM call stack captured on a trap.
 // This bypasses Soroban Host abstractions to expose the raw Wasmi call stack.
 type WasmStackTrace struct {
@@ -125,117 +174,10 @@ type StackFrame struct {
 	Module     *string `json:"module,omitempty"`      // Module name from name section
 }
 
-type DB struct {
-	conn *sql.DB
-}
-
-func OpenDB() (*DB, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
-	}
-	dbPath := filepath.Join(home, ".erst", "sessions.db")
-
-	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
-		return nil, err
-	}
-
-	conn, err := sql.Open("sqlite", dbPath)
-	if err != nil {
-		return nil, err
-	}
-
-	db := &DB{conn: conn}
-	if err := db.init(); err != nil {
-		return nil, err
-	}
-
-	return db, nil
-}
-
-func (db *DB) init() error {
-	query := `
-	CREATE TABLE IF NOT EXISTS sessions (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		tx_hash TEXT NOT NULL,
-		network TEXT NOT NULL,
-		timestamp DATETIME NOT NULL,
-		error TEXT,
-		events TEXT,
-		logs TEXT
-	);
-	CREATE INDEX IF NOT EXISTS idx_tx_hash ON sessions(tx_hash);
-	CREATE INDEX IF NOT EXISTS idx_error ON sessions(error);
-	`
-	_, err := db.conn.Exec(query)
-	return err
-}
-
-func (db *DB) SaveSession(s *Session) error {
-	query := "INSERT INTO sessions (tx_hash, network, timestamp, error, events, logs) VALUES (?, ?, ?, ?, ?, ?)"
-	_, err := db.conn.Exec(query, s.TxHash, s.Network, s.Timestamp, s.Error, s.Events, s.Logs)
-	return err
-}
-
-type SearchFilters struct {
-	Error    string
-	Event    string
-	Contract string
-	UseRegex bool
-}
-
-func (db *DB) SearchSessions(filters SearchFilters) ([]Session, error) {
-	query := "SELECT id, tx_hash, network, timestamp, error, events, logs FROM sessions WHERE 1=1"
-	var args []interface{}
-
-	if filters.Error != "" {
-		if filters.UseRegex {
-			query += " AND error REGEXP ?"
-		} else {
-			query += " AND error LIKE ?"
-			filters.Error = "%" + filters.Error + "%"
-		}
-		args = append(args, filters.Error)
-	}
-
-	if filters.Event != "" {
-		if filters.UseRegex {
-			query += " AND events REGEXP ?"
-		} else {
-			query += " AND events LIKE ?"
-			filters.Event = "%" + filters.Event + "%"
-		}
-		args = append(args, filters.Event)
-	}
-
-	if filters.Contract != "" {
-		if filters.UseRegex {
-			query += " AND (events REGEXP ? OR logs REGEXP ?)"
-			args = append(args, filters.Contract, filters.Contract)
-		} else {
-			query += " AND (events LIKE ? OR logs LIKE ?)"
-			match := "%" + filters.Contract + "%"
-			args = append(args, match, match)
-		}
-	}
-
-	query += " ORDER BY timestamp DESC"
-
-	rows, err := db.conn.Query(query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var sessions []Session
-	for rows.Next() {
-		var s Session
-		err := rows.Scan(&s.ID, &s.TxHash, &s.Network, &s.Timestamp, &s.Error, &s.Events, &s.Logs)
-		if err != nil {
-			return nil, err
-		}
-		sessions = append(sessions, s)
-	}
-
-	return sessions, nil
+// SourceLocation (baseline-fix placeholder)
+type SourceLocation struct {
+	File      string  `json:"file"`
+	Line      uint32  `json:"line"`
+	Column    uint32  `json:"column"`
+	ColumnEnd *uint32 `json:"column_end,omitempty"`
 }
@@ -0,0 +1,201 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/stellar/go-stellar-sdk/strkey"
+)
+
+// StepFDsEnv tells the simulator which inherited descriptors carry the step
+// protocol: "<events>,<commands>". ExtraFiles start at fd 3 in the child.
+const StepFDsEnv = "ERST_STEP_FDS"
+
+// Kinds of pause reported by the simulator in step mode
+const (
+	StepKindCall   = "call"    // a new contract frame was pushed
+	StepKindReturn = "return"  // a contract frame is about to be popped
+	StepKindHostFn = "host_fn" // the guest called a host function
+)
+
+// StepEvent is one pause point reported by the simulator
+type StepEvent struct {
+	Type  string `json:"type"`
+	Seq   uint64 `json:"seq"`
+	Kind  string `json:"kind"`
+	Depth int    `json:"depth"`
+	// Name is the host function, or the contract function a call enters
+	Name string `json:"name,omitempty"`
+	// Contract is the leading bytes, in hex, of the ID of the contract a
+	// call enters, which is all the host renders of it
+	Contract string   `json:"contract,omitempty"`
+	Args     []string `json:"args,omitempty"`
+}
+
+// Matches reports whether a breakpoint pattern applies to the event. The
+// pattern is compared case-insensitively against the function name, the
+// contract a call enters and the rendered arguments of host functions, so
+// function symbols, contract IDs (C...) and hex contract hashes all work.
+func (e StepEvent) Matches(pattern string) bool {
+	if hash := contractHash(pattern); hash != "" && e.Contract != "" {
+		return strings.HasPrefix(hash, strings.ToLower(e.Contract))
+	}
+	pattern = strings.ToLower(pattern)
+	if pattern == "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(e.Name), pattern) || strings.Contains(strings.ToLower(e.Contract), pattern) {
+		return true
+	}
+	for _, arg := range e.Args {
+		if strings.Contains(strings.ToLower(arg), pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// contractHash returns the hex hash a contract ID or hash pattern names, or
+// "" when the pattern is neither
+func contractHash(pattern string) string {
+	if id, err := strkey.Decode(strkey.VersionByteContract, pattern); err == nil {
+		return hex.EncodeToString(id)
+	}
+	if b, err := hex.DecodeString(pattern); err == nil && len(b) == 32 {
+		return strings.ToLower(pattern)
+	}
+	return ""
+}
+
+// StepAction is the answer sent back to a paused simulator
+type StepAction string
+
+const (
+	StepResume StepAction = "resume"
+	StepAbort  StepAction = "abort"
+)
+
+// StepController decides what to do at each pause point
+type StepController interface {
+	OnPause(event StepEvent) StepAction
+}
+
+// StepControllerFunc adapts a function to StepController
+type StepControllerFunc func(event StepEvent) StepAction
+
+func (f StepControllerFunc) OnPause(event StepEvent) StepAction { return f(event) }
+
+// RunStepping runs a simulation with the step protocol enabled, handing every
+// pause point to ctrl. The returned bool reports whether the controller
+// aborted execution; the simulator still sends a response in that case.
+func (r *Runner) RunStepping(req *SimulationRequest, ctrl StepController) (*SimulationResponse, bool, error) {
+	proto, inputBytes, err := r.prepareRequest(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	eventsR, eventsW, err := os.Pipe()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create step event pipe: %w", err)
+	}
+	defer eventsR.Close()
+	commandsR, commandsW, err := os.Pipe()
+	if err != nil {
+		eventsW.Close()
+		return nil, false, fmt.Errorf("failed to create step command pipe: %w", err)
+	}
+	defer commandsW.Close()
+
+	cmd := exec.Command(r.BinaryPath)
+	cmd.Stdin = bytes.NewReader(inputBytes)
+	cmd.ExtraFiles = []*os.File{eventsW, commandsR}
+	cmd.Env = append(os.Environ(), StepFDsEnv+"=3,4")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	startErr := cmd.Start()
+	// The child holds its own copies; closing ours lets EOF propagate
+	eventsW.Close()
+	commandsR.Close()
+	if startErr != nil {
+		return nil, false, errors.WrapSimCrash(startErr, "")
+	}
+
+	aborted, stepErr := serveStepEvents(eventsR, commandsW, ctrl)
+	// If the protocol broke off early, closing both ends fails the child's
+	// next read or write instead of leaving it blocked
+	commandsW.Close()
+	eventsR.Close()
+
+	if err := cmd.Wait(); err != nil {
+		logger.Logger.Error("Simulator execution failed", "error", err, "stderr", stderr.String())
+		return nil, aborted, errors.WrapSimCrash(err, stderr.String())
+	}
+	if stepErr != nil {
+		logger.Logger.Warn("Step protocol ended early", "error", stepErr)
+	}
+
+	var resp SimulationResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		logger.Logger.Error("Failed to unmarshal response", "error", err)
+		return nil, aborted, errors.WrapUnmarshalFailed(err, stdout.String())
+	}
+
+	resp.ProtocolVersion = &proto.Version
+	resp.CallTree = BuildCallTree(resp.DiagnosticEvents, resp.CallBudgets)
+	resp.FunctionCosts = FunctionCosts(resp.CallTree)
+	resp.LedgerTrace = BuildLedgerTrace(req.LedgerEntries, resp.StorageAccesses, resp.StorageWrites)
+	resp.Snapshot = snapshot.NewPin(req.LedgerSequence, req.LedgerEntries)
+	resp.ErrorExplanation = ExplainError(&resp)
+
+	return &resp, aborted, nil
+}
+
+// serveStepEvents reads pause events until the simulator closes the channel,
+// answering each one with the controller's decision. Once aborted, further
+// pauses raised while the host unwinds are aborted without asking.
+func serveStepEvents(events io.Reader, commands io.Writer, ctrl StepController) (bool, error) {
+	aborted := false
+	scanner := bufio.NewScanner(events)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var event StepEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			return aborted, errors.WrapUnmarshalFailed(err, string(line))
+		}
+
+		action := StepAbort
+		if !aborted {
+			action = ctrl.OnPause(event)
+		}
+		if action == StepAbort {
+			aborted = true
+		}
+
+		if _, err := fmt.Fprintln(commands, string(action)); err != nil {
+			return aborted, fmt.Errorf("failed to send step command: %w", err)
+		}
+	}
+	return aborted, scanner.Err()
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeStepEvents_ResumesEachPause(t *testing.T) {
+	events := strings.NewReader(
+		`{"type":"pause","seq":1,"kind":"call","depth":1}` + "\n" +
+			`{"type":"pause","seq":2,"kind":"host_fn","depth":1,"name":"put_contract_data","args":["Symbol(count)"]}` + "\n" +
+			"\n" +
+			`{"type":"pause","seq":3,"kind":"return","depth":1}` + "\n")
+	var commands bytes.Buffer
+	var seen []StepEvent
+
+	aborted, err := serveStepEvents(events, &commands, StepControllerFunc(func(ev StepEvent) StepAction {
+		seen = append(seen, ev)
+		return StepResume
+	}))
+	require.NoError(t, err)
+	assert.False(t, aborted)
+	assert.Equal(t, "resume\nresume\nresume\n", commands.String())
+	require.Len(t, seen, 3)
+	assert.Equal(t, StepKindHostFn, seen[1].Kind)
+	assert.Equal(t, "put_contract_data", seen[1].Name)
+	assert.Equal(t, []string{"Symbol(count)"}, seen[1].Args)
+}
+
+func TestServeStepEvents_AbortStopsAsking(t *testing.T) {
+	events := strings.NewReader(
+		`{"type":"pause","seq":1,"kind":"call","depth":1}` + "\n" +
+			`{"type":"pause","seq":2,"kind":"call","depth":2}` + "\n" +
+			`{"type":"pause","seq":3,"kind":"return","depth":2}` + "\n")
+	var commands bytes.Buffer
+	calls := 0
+
+	aborted, err := serveStepEvents(events, &commands, StepControllerFunc(func(ev StepEvent) StepAction {
+		calls++
+		if ev.Depth == 2 {
+			return StepAbort
+		}
+		return StepResume
+	}))
+	require.NoError(t, err)
+	assert.True(t, aborted)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "resume\nabort\nabort\n", commands.String())
+}
+
+func TestServeStepEvents_InvalidEvent(t *testing.T) {
+	var commands bytes.Buffer
+	_, err := serveStepEvents(strings.NewReader("not json\n"), &commands, StepControllerFunc(func(StepEvent) StepAction {
+		return StepResume
+	}))
+	assert.Error(t, err)
+	assert.Empty(t, commands.String())
+}
+
+func TestStepEventMatches(t *testing.T) {
+	ev := StepEvent{Kind: StepKindHostFn, Name: "call", Args: []string{"Object(Address(obj#3))", "Symbol(transfer)"}}
+
+	assert.True(t, ev.Matches("transfer"))
+	assert.True(t, ev.Matches("CALL"))
+	assert.False(t, ev.Matches("mint"))
+	assert.False(t, ev.Matches(""))
+
+	contractID := "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	hash, err := strkey.Decode(strkey.VersionByteContract, contractID)
+	require.NoError(t, err)
+	call := StepEvent{Kind: StepKindCall, Name: "transfer", Contract: hex.EncodeToString(hash[:4])}
+	assert.True(t, call.Matches(contractID))
+	assert.True(t, call.Matches(hex.EncodeToString(hash)))
+	assert.True(t, call.Matches("transfer"))
+	assert.False(t, call.Matches("CAS3J7GYLGXMF6TDJBBYYSE3HQ6BBSMLNUQ34T6TZMYMW2EVH34XOWMA"))
+}
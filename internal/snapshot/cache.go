@@ -0,0 +1,71 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Cache persists per-transaction ledger snapshots on disk, grouped by network
+// and ledger sequence, so a transaction can be re-simulated offline with the
+// state captured the first time it was debugged.
+//
+// Layout: <dir>/<network>/<ledger-sequence>/<tx-hash>.json
+type Cache struct {
+	dir string
+}
+
+// NewCache creates a cache rooted at dir
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// DefaultCacheDir returns ~/.erst/snapshots
+func DefaultCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".erst", "snapshots"), nil
+}
+
+// NewDefaultCache creates a cache in the default location
+func NewDefaultCache() (*Cache, error) {
+	dir, err := DefaultCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewCache(dir), nil
+}
+
+// Path returns where the snapshot for a transaction is stored
+func (c *Cache) Path(network string, ledger uint32, txHash string) string {
+	return filepath.Join(c.dir, network, strconv.FormatUint(uint64(ledger), 10), txHash+".json")
+}
+
+// Get loads a cached snapshot. The boolean is false when none is stored.
+func (c *Cache) Get(network string, ledger uint32, txHash string) (*Snapshot, bool, error) {
+	path := c.Path(network, ledger, txHash)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, false, nil
+	}
+
+	snap, err := Load(path)
+	if err != nil {
+		return nil, false, err
+	}
+	return snap, true, nil
+}
+
+// Put stores a snapshot, replacing any previous one for the transaction
+func (c *Cache) Put(network string, ledger uint32, txHash string, snap *Snapshot) error {
+	path := c.Path(network, ledger, txHash)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot cache directory: %w", err)
+	}
+	return Save(path, snap)
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCache(dir)
+
+	if _, ok, err := cache.Get("testnet", 42, "abc"); err != nil || ok {
+		t.Fatalf("expected empty cache, got ok=%v err=%v", ok, err)
+	}
+
+	entries := map[string]string{"k2": "v2", "k1": "v1"}
+	if err := cache.Put("testnet", 42, "abc", FromMap(entries)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	want := filepath.Join(dir, "testnet", "42", "abc.json")
+	if got := cache.Path("testnet", 42, "abc"); got != want {
+		t.Errorf("Path() = %s, want %s", got, want)
+	}
+
+	snap, ok, err := cache.Get("testnet", 42, "abc")
+	if err != nil || !ok {
+		t.Fatalf("expected cached snapshot, got ok=%v err=%v", ok, err)
+	}
+	got := snap.ToMap()
+	if len(got) != 2 || got["k1"] != "v1" || got["k2"] != "v2" {
+		t.Errorf("unexpected entries: %v", got)
+	}
+
+	// Same transaction hash on another network or ledger is a separate entry
+	if _, ok, _ := cache.Get("mainnet", 42, "abc"); ok {
+		t.Error("expected miss for different network")
+	}
+	if _, ok, _ := cache.Get("testnet", 43, "abc"); ok {
+		t.Error("expected miss for different ledger")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// Pin identifies the exact ledger state a simulation ran against: its ledger
+// sequence and the SHA-256 of every entry. Two states with the same ID hold
+// the same entries byte for byte.
+type Pin struct {
+	ID             string `json:"id"`
+	LedgerSequence uint32 `json:"ledger_sequence"`
+	// EntryHashes maps each LedgerKey XDR to the hex SHA-256 of its entry XDR
+	EntryHashes map[string]string `json:"entry_hashes"`
+}
+
+// NewPin pins the given ledger entries at ledgerSeq
+func NewPin(ledgerSeq uint32, entries map[string]string) *Pin {
+	hashes := make(map[string]string, len(entries))
+	for k, v := range entries {
+		sum := sha256.Sum256([]byte(v))
+		hashes[k] = hex.EncodeToString(sum[:])
+	}
+	return &Pin{ID: pinID(ledgerSeq, hashes), LedgerSequence: ledgerSeq, EntryHashes: hashes}
+}
+
+// pinID hashes the ledger sequence and the sorted key and entry hash pairs
+func pinID(ledgerSeq uint32, hashes map[string]string) string {
+	keys := make([]string, 0, len(hashes))
+	for k := range hashes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	h.Write([]byte(strconv.FormatUint(uint64(ledgerSeq), 10) + "\n"))
+	for _, k := range keys {
+		h.Write([]byte(k + "=" + hashes[k] + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Diff returns the keys whose entries differ from the pinned state: changed,
+// missing or not pinned at all. It is empty when entries match the pin.
+func (p *Pin) Diff(entries map[string]string) []string {
+	other := NewPin(p.LedgerSequence, entries)
+	if other.ID == p.ID {
+		return nil
+	}
+
+	var keys []string
+	for k, hash := range p.EntryHashes {
+		if other.EntryHashes[k] != hash {
+			keys = append(keys, k)
+		}
+	}
+	for k := range other.EntryHashes {
+		if _, ok := p.EntryHashes[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Matches reports whether entries at ledgerSeq are exactly the pinned state
+func (p *Pin) Matches(ledgerSeq uint32, entries map[string]string) bool {
+	return ledgerSeq == p.LedgerSequence && NewPin(ledgerSeq, entries).ID == p.ID
+}
+
+// PinnedPath returns where the snapshot with a pin ID is stored. Pinned
+// snapshots are content-addressed, so they are never replaced.
+func (c *Cache) PinnedPath(id string) string {
+	return filepath.Join(c.dir, "pinned", id+".json")
+}
+
+// PutPinned stores the snapshot of pin's entries under its ID, unless it is
+// already stored
+func (c *Cache) PutPinned(pin *Pin, entries map[string]string) error {
+	if !pin.Matches(pin.LedgerSequence, entries) {
+		return fmt.Errorf("entries do not match snapshot %s", pin.ID)
+	}
+	path := c.PinnedPath(pin.ID)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot cache directory: %w", err)
+	}
+	return Save(path, FromMap(entries))
+}
+
+// GetPinned loads the entries pinned by pin, checking them against its entry
+// hashes. The boolean is false when the snapshot is not stored.
+func (c *Cache) GetPinned(pin *Pin) (map[string]string, bool, error) {
+	path := c.PinnedPath(pin.ID)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, false, nil
+	}
+
+	snap, err := Load(path)
+	if err != nil {
+		return nil, false, err
+	}
+	entries := snap.ToMap()
+	if !pin.Matches(pin.LedgerSequence, entries) {
+		return nil, false, fmt.Errorf("pinned snapshot %s is corrupt: %d entries differ", pin.ID, len(pin.Diff(entries)))
+	}
+	return entries, true, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPin(t *testing.T) {
+	entries := map[string]string{"k1": "v1", "k2": "v2"}
+	pin := NewPin(42, entries)
+
+	if again := NewPin(42, map[string]string{"k2": "v2", "k1": "v1"}); again.ID != pin.ID {
+		t.Errorf("pin ID depends on map order: %s vs %s", again.ID, pin.ID)
+	}
+	if NewPin(43, entries).ID == pin.ID {
+		t.Error("pin ID should cover the ledger sequence")
+	}
+	if !pin.Matches(42, entries) || pin.Matches(43, entries) {
+		t.Error("Matches should require the same entries and ledger")
+	}
+
+	changed := map[string]string{"k1": "v1", "k2": "other", "k3": "v3"}
+	if got := pin.Diff(changed); !reflect.DeepEqual(got, []string{"k2", "k3"}) {
+		t.Errorf("Diff() = %v", got)
+	}
+	if got := pin.Diff(map[string]string{"k1": "v1"}); !reflect.DeepEqual(got, []string{"k2"}) {
+		t.Errorf("Diff() = %v, want the missing key", got)
+	}
+	if got := pin.Diff(entries); got != nil {
+		t.Errorf("Diff() = %v for the pinned entries", got)
+	}
+}
+
+func TestCachePinned(t *testing.T) {
+	cache := NewCache(t.TempDir())
+	entries := map[string]string{"k1": "v1", "k2": "v2"}
+	pin := NewPin(42, entries)
+
+	if _, ok, err := cache.GetPinned(pin); err != nil || ok {
+		t.Fatalf("expected no pinned snapshot, got ok=%v err=%v", ok, err)
+	}
+	if err := cache.PutPinned(pin, map[string]string{"k1": "v1"}); err == nil {
+		t.Error("PutPinned accepted entries that do not match the pin")
+	}
+	if err := cache.PutPinned(pin, entries); err != nil {
+		t.Fatalf("PutPinned() error = %v", err)
+	}
+
+	got, ok, err := cache.GetPinned(pin)
+	if err != nil || !ok {
+		t.Fatalf("expected pinned snapshot, got ok=%v err=%v", ok, err)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("GetPinned() = %v", got)
+	}
+
+	// A pinned snapshot edited on disk no longer matches its ID
+	if err := Save(cache.PinnedPath(pin.ID), FromMap(map[string]string{"k1": "v1"})); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := cache.GetPinned(pin); err == nil {
+		t.Error("GetPinned accepted a snapshot that does not match the pin")
+	}
+}
@@ -18,6 +18,12 @@ type LedgerEntryTuple []string
 // strict schema compatibility: "ledgerEntries" key containing list of tuples.
 type Snapshot struct {
 	LedgerEntries []LedgerEntryTuple `json:"ledgerEntries"`
+
+	// Where the state was captured, recorded by 'erst snapshot export' so
+	// another machine can cache it for the same transaction
+	Network        string `json:"network,omitempty"`
+	LedgerSequence uint32 `json:"ledgerSequence,omitempty"`
+	TxHash         string `json:"txHash,omitempty"`
 }
 
 // FromMap converts the internal map representation to a Snapshot.
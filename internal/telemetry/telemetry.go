@@ -5,6 +5,7 @@ package telemetry
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -17,9 +18,14 @@ import (
 
 // Config holds OpenTelemetry configuration
 type Config struct {
-	Enabled     bool
-	ExporterURL string
-	ServiceName string
+	Enabled bool
+	// ExporterURL is the OTLP/HTTP endpoint, either a URL such as
+	// "https://otel.example.com:4318" or a plain host:port, which is sent
+	// over plain HTTP. When empty the standard OTEL_EXPORTER_OTLP_* environment
+	// variables are used.
+	ExporterURL    string
+	ServiceName    string
+	ServiceVersion string
 }
 
 // Init initializes OpenTelemetry with the given configuration
@@ -29,19 +35,21 @@ func Init(ctx context.Context, config Config) (func(), error) {
 	}
 
 	// Create OTLP HTTP exporter
-	exporter, err := otlptracehttp.New(ctx,
-		otlptracehttp.WithEndpoint(config.ExporterURL),
-		otlptracehttp.WithInsecure(), // Use HTTP instead of HTTPS for local development
-	)
+	exporter, err := otlptracehttp.New(ctx, exporterOptions(config.ExporterURL)...)
 	if err != nil {
 		return nil, err
 	}
 
+	version := config.ServiceVersion
+	if version == "" {
+		version = "dev"
+	}
+
 	// Create resource
 	res, err := resource.New(ctx,
 		resource.WithAttributes(
 			semconv.ServiceNameKey.String(config.ServiceName),
-			semconv.ServiceVersionKey.String("dev"),
+			semconv.ServiceVersionKey.String(version),
 		),
 	)
 	if err != nil {
@@ -64,6 +72,18 @@ func Init(ctx context.Context, config Config) (func(), error) {
 	}, nil
 }
 
+func exporterOptions(url string) []otlptracehttp.Option {
+	switch {
+	case url == "":
+		return nil
+	case strings.Contains(url, "://"):
+		// The scheme decides between HTTP and HTTPS
+		return []otlptracehttp.Option{otlptracehttp.WithEndpointURL(url)}
+	default:
+		return []otlptracehttp.Option{otlptracehttp.WithEndpoint(url), otlptracehttp.WithInsecure()}
+	}
+}
+
 // GetTracer returns the global tracer instance
 func GetTracer() oteltrace.Tracer {
 	return otel.Tracer("erst")
@@ -5,6 +5,10 @@ package telemetry
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -57,3 +61,39 @@ func TestGetTracer(t *testing.T) {
 	_, span := tracer.Start(ctx, "test-span")
 	span.End()
 }
+
+func TestInitExportsToURL(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	for _, url := range []string{collector.URL, strings.TrimPrefix(collector.URL, "http://")} {
+		mu.Lock()
+		paths = nil
+		mu.Unlock()
+
+		cleanup, err := Init(context.Background(), Config{
+			Enabled:     true,
+			ExporterURL: url,
+			ServiceName: "test-service",
+		})
+		if err != nil {
+			t.Fatalf("Init(%q) failed: %v", url, err)
+		}
+		_, span := GetTracer().Start(context.Background(), "test-span")
+		span.End()
+		cleanup()
+
+		mu.Lock()
+		if len(paths) == 0 || paths[0] != "/v1/traces" {
+			t.Errorf("Init(%q): expected spans posted to /v1/traces, got %v", url, paths)
+		}
+		mu.Unlock()
+	}
+}
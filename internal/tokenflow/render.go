@@ -8,6 +8,8 @@ import (
 	"math/big"
 	"regexp"
 	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
 )
 
 // SummaryLines produces human-readable summaries like:
@@ -16,7 +18,7 @@ import (
 func (r *Report) SummaryLines() []string {
 	var lines []string
 	for _, t := range r.Agg {
-		lines = append(lines, fmt.Sprintf("%s -> %s %s -> %s", t.From, formatAmount(t), t.Token.Display(), t.To))
+		lines = append(lines, fmt.Sprintf("%s -> %s %s -> %s", decoder.DisplayAddress(t.From), formatAmount(t), t.Token.Display(), decoder.DisplayAddress(t.To)))
 	}
 	return lines
 }
@@ -35,7 +37,7 @@ func (r *Report) MermaidFlowchart() string {
 		next++
 		id := fmt.Sprintf("n%d", next)
 		nodeID[label] = id
-		b.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", id, escapeMermaidLabel(label)))
+		b.WriteString(fmt.Sprintf("  %s[\"%s\"]\n", id, escapeMermaidLabel(decoder.DisplayAddress(label))))
 		return id
 	}
 
@@ -56,7 +58,10 @@ func formatAmount(t Transfer) string {
 	if t.Token.Symbol == "XLM" && t.Token.ID == "" {
 		return formatStroopsAsXLM(t.Amount)
 	}
-	// For SAC tokens we don't know decimals here; show raw integer.
+	if t.Token.Decimals > 0 {
+		return decoder.FormatTokenAmount(t.Amount, t.Token.Decimals)
+	}
+	// Other tokens' decimals are unknown here; show raw integer.
 	return t.Amount.String()
 }
 
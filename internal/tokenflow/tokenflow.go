@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/dotandev/hintents/internal/decoder"
 	"github.com/stellar/go-stellar-sdk/strkey"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
@@ -20,14 +21,18 @@ type Kind string
 const (
 	KindTransfer Kind = "transfer"
 	KindMint     Kind = "mint"
+	KindBurn     Kind = "burn"
 )
 
 // Token identifies an asset.
 // - XLM: Symbol="XLM", ID=""
-// - SAC: Symbol="SAC" (best-effort), ID="C...." (contract id)
+// - SAC: Symbol="USDC" (the asset code), ID="C...." (contract id), Decimals=7
+// - other token events: Symbol="SAC" (best-effort), ID="C...." (contract id)
 type Token struct {
 	Symbol string
 	ID     string
+	// Decimals is the token's precision when known; 0 shows raw amounts
+	Decimals int
 }
 
 func (t Token) Display() string {
@@ -50,7 +55,7 @@ func (t Token) Display() string {
 	return t.Symbol + "(" + id + ")"
 }
 
-// Transfer is a single token movement (or mint).
+// Transfer is a single token movement (or mint or burn).
 type Transfer struct {
 	From   string
 	To     string
@@ -202,6 +207,14 @@ func extractSACTransfersAndMints(resultMetaXdrB64 string) ([]Transfer, error) {
 			continue
 		}
 
+		// Stellar Asset Contract events name their asset in the last topic
+		if sac, ok := decoder.DecodeSACEvent(contractStr, body.Topics, body.Data, ""); ok {
+			if t, ok := sacTransfer(sac, contractStr, body.Data); ok {
+				out = append(out, t)
+			}
+			continue
+		}
+
 		switch op {
 		case "transfer":
 			// Expected topics: ["transfer", from, to], data: amount
@@ -253,6 +266,39 @@ func extractSACTransfersAndMints(resultMetaXdrB64 string) ([]Transfer, error) {
 	return out, nil
 }
 
+// sacTransfer turns a decoded Stellar Asset Contract event into a movement
+// of its asset; approvals and admin changes move nothing
+func sacTransfer(sac *decoder.SACAction, contractID string, data xdr.ScVal) (Transfer, bool) {
+	if data.Type == xdr.ScValTypeScvMap && data.Map != nil && *data.Map != nil {
+		// Muxed transfers carry {amount, to_muxed_id}
+		for _, e := range **data.Map {
+			if sym, ok := scValSymbol(e.Key); ok && sym == "amount" {
+				data = e.Val
+			}
+		}
+	}
+	amt, ok := scValAmount(data)
+	if !ok || amt.Sign() < 0 {
+		return Transfer{}, false
+	}
+
+	t := Transfer{
+		Token:  Token{Symbol: decoder.AssetCode(sac.Asset), ID: contractID, Decimals: decoder.StellarAssetDecimals},
+		Amount: amt,
+	}
+	switch sac.Action {
+	case "transfer":
+		t.From, t.To, t.Kind = sac.From, sac.Destination(), KindTransfer
+	case "mint":
+		t.From, t.To, t.Kind = "MINT", sac.Destination(), KindMint
+	case "burn", "clawback":
+		t.From, t.To, t.Kind = sac.From, "BURN", KindBurn
+	default:
+		return Transfer{}, false
+	}
+	return t, true
+}
+
 func extractDiagnosticEvents(tm xdr.TransactionMeta) []xdr.DiagnosticEvent {
 	switch tm.V {
 	case 3:
@@ -338,11 +384,12 @@ func aggregate(in []Transfer) []Transfer {
 		kind Kind
 		sym  string
 		id   string
+		dec  int
 	}
 
 	m := map[key]*big.Int{}
 	for _, t := range in {
-		k := key{from: t.From, to: t.To, kind: t.Kind, sym: t.Token.Symbol, id: t.Token.ID}
+		k := key{from: t.From, to: t.To, kind: t.Kind, sym: t.Token.Symbol, id: t.Token.ID, dec: t.Token.Decimals}
 		if m[k] == nil {
 			m[k] = new(big.Int)
 		}
@@ -355,7 +402,7 @@ func aggregate(in []Transfer) []Transfer {
 			From:   k.from,
 			To:     k.to,
 			Kind:   k.kind,
-			Token:  Token{Symbol: k.sym, ID: k.id},
+			Token:  Token{Symbol: k.sym, ID: k.id, Decimals: k.dec},
 			Amount: new(big.Int).Set(v),
 		})
 	}
@@ -64,6 +64,50 @@ func TestBuildReport_SACTransferAndMint_FromResultMeta(t *testing.T) {
 	require.Equal(t, big.NewInt(7), r.Agg[1].Amount)
 }
 
+func TestBuildReport_StellarAssetContractEvents(t *testing.T) {
+	cid := xdr.ContractId(bytes32(0xBB))
+	contractStr, err := strkey.Encode(strkey.VersionByteContract, cid[:])
+	require.NoError(t, err)
+
+	issuerKey := bytes32(0x09)
+	issuer, err := strkey.Encode(strkey.VersionByteAccountID, issuerKey[:])
+	require.NoError(t, err)
+	asset := scString("USDC:" + issuer)
+
+	fromAddr := scAddressAccount(bytes32(0x01))
+	toAddr := scAddressAccount(bytes32(0x02))
+
+	transferEvent := diagnosticEvent(
+		cid,
+		[]xdr.ScVal{scSymbol("transfer"), scAddress(fromAddr), scAddress(toAddr), asset},
+		scI128(105_000_000),
+		true,
+	)
+	burnEvent := diagnosticEvent(
+		cid,
+		[]xdr.ScVal{scSymbol("burn"), scAddress(fromAddr), asset},
+		scI128(5_000_000),
+		true,
+	)
+
+	rmB64 := encodeResultMetaWithDiagnosticEvents(t, []xdr.DiagnosticEvent{transferEvent, burnEvent})
+
+	r, err := BuildReport("", rmB64)
+	require.NoError(t, err)
+	require.Len(t, r.Agg, 2)
+
+	// Sorted by destination, so the burn comes first
+	require.Equal(t, KindBurn, r.Agg[0].Kind)
+	require.Equal(t, "BURN", r.Agg[0].To)
+	require.Equal(t, "0.5", formatAmount(r.Agg[0]))
+
+	require.Equal(t, KindTransfer, r.Agg[1].Kind)
+	require.Equal(t, Token{Symbol: "USDC", ID: contractStr, Decimals: 7}, r.Agg[1].Token)
+	require.Equal(t, addrString(fromAddr), r.Agg[1].From)
+	require.Equal(t, addrString(toAddr), r.Agg[1].To)
+	require.Equal(t, "10.5", formatAmount(r.Agg[1]))
+}
+
 func TestBuildReport_NativeXLMPayment_FromEnvelope(t *testing.T) {
 	src := bytes32(0x10)
 	dst := bytes32(0x20)
@@ -206,6 +250,16 @@ func scU128(v uint64) xdr.ScVal {
 	return xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &parts}
 }
 
+func scString(s string) xdr.ScVal {
+	str := xdr.ScString(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}
+}
+
+func scI128(v int64) xdr.ScVal {
+	parts := xdr.Int128Parts{Hi: 0, Lo: xdr.Uint64(v)}
+	return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &parts}
+}
+
 func scAddressAccount(pk [32]byte) xdr.ScAddress {
 	acc, err := xdr.NewAccountId(xdr.PublicKeyTypePublicKeyTypeEd25519, xdr.Uint256(pk))
 	if err != nil {
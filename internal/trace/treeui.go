@@ -188,6 +188,7 @@ func (tr *TreeRenderer) Render() string {
 		scrollPos := int(float64(tr.scrollOffset) / float64(len(tr.nodes)-visibleRows) * float64(visibleRows))
 		scrollLine := fmt.Sprintf("─ Showing %d-%d of %d lines (↑↓ navigate, click [+/-] to expand) ─",
 			startRow+1, endRow, len(tr.nodes))
+		_ = scrollPos
 		output.WriteString(scrollLine)
 	}
 
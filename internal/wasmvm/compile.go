@@ -0,0 +1,297 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmvm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Opcodes with immediates or special handling. Numeric instructions without
+// immediates are interpreted by opcode directly.
+const (
+	opUnreachable  = 0x00
+	opNop          = 0x01
+	opBlock        = 0x02
+	opLoop         = 0x03
+	opIf           = 0x04
+	opElse         = 0x05
+	opEnd          = 0x0b
+	opBr           = 0x0c
+	opBrIf         = 0x0d
+	opBrTable      = 0x0e
+	opReturn       = 0x0f
+	opCall         = 0x10
+	opCallIndirect = 0x11
+	opDrop         = 0x1a
+	opSelect       = 0x1b
+	opSelectT      = 0x1c
+	opLocalGet     = 0x20
+	opLocalSet     = 0x21
+	opLocalTee     = 0x22
+	opGlobalGet    = 0x23
+	opGlobalSet    = 0x24
+	opTableGet     = 0x25
+	opTableSet     = 0x26
+	opMemorySize   = 0x3f
+	opMemoryGrow   = 0x40
+	opI32Const     = 0x41
+	opI64Const     = 0x42
+	opF32Const     = 0x43
+	opF64Const     = 0x44
+	opRefNull      = 0xd0
+	opRefIsNull    = 0xd1
+	opRefFunc      = 0xd2
+	opPrefix       = 0xfc
+)
+
+// Instructions after the 0xfc prefix are compiled to prefixBase plus their
+// sub-opcode
+const prefixBase = 0x100
+
+const (
+	opMemoryInit = prefixBase + 8
+	opDataDrop   = prefixBase + 9
+	opMemoryCopy = prefixBase + 10
+	opMemoryFill = prefixBase + 11
+	opTableInit  = prefixBase + 12
+	opElemDrop   = prefixBase + 13
+	opTableCopy  = prefixBase + 14
+	opTableGrow  = prefixBase + 15
+	opTableSize  = prefixBase + 16
+	opTableFill  = prefixBase + 17
+)
+
+// instr is a decoded instruction. Control instructions carry the positions
+// they jump to, so execution never scans the code.
+type instr struct {
+	op uint16
+	// x is the index immediate: local, global, function, type, table,
+	// segment or branch depth. Blocks keep the position of their end here.
+	x uint32
+	// y is a second index: the table of call_indirect, table.init and
+	// table.copy, the position after else for if
+	y uint32
+	// imm is a constant or a memory offset
+	imm uint64
+	// Block parameter and result counts
+	params, results uint32
+	// targets are the depths of br_table, the default last
+	targets []uint32
+}
+
+// compile decodes the body of f
+func compile(m *Module, f *function) ([]instr, error) {
+	t := m.Types[f.typ]
+	nlocals := uint32(len(t.Params) + len(f.locals))
+	nfuncs := uint32(len(m.Imports) + len(m.funcs))
+
+	r := &reader{data: f.body}
+	var code []instr
+	// open holds the positions of the blocks not yet ended
+	var open []int
+	for {
+		if r.done() {
+			if r.err != nil {
+				return nil, r.err
+			}
+			return nil, errors.New("function body does not end")
+		}
+		in := instr{op: uint16(r.byte())}
+		switch in.op {
+		case opBlock, opLoop, opIf:
+			in.params, in.results = r.blockType(m)
+			open = append(open, len(code))
+		case opElse:
+			if len(open) == 0 || code[open[len(open)-1]].op != opIf {
+				return nil, errors.New("else outside if")
+			}
+			code[open[len(open)-1]].y = uint32(len(code) + 1)
+		case opEnd:
+			if len(open) == 0 {
+				code = append(code, in)
+				if !r.done() {
+					return nil, errors.New("code after the end of the function")
+				}
+				return code, nil
+			}
+			block := open[len(open)-1]
+			open = open[:len(open)-1]
+			code[block].x = uint32(len(code))
+			if code[block].op == opIf {
+				if code[block].y == 0 {
+					// Without else, a false condition goes to the end
+					code[block].y = uint32(len(code))
+				} else {
+					// The end of the true branch skips the else branch
+					code[code[block].y-1].x = uint32(len(code))
+				}
+			}
+		case opBr, opBrIf:
+			in.x = r.u32()
+			if int(in.x) > len(open) {
+				return nil, fmt.Errorf("unknown label %d", in.x)
+			}
+		case opBrTable:
+			n := r.count()
+			for i := uint32(0); i <= n && r.err == nil; i++ {
+				depth := r.u32()
+				if int(depth) > len(open) {
+					return nil, fmt.Errorf("unknown label %d", depth)
+				}
+				in.targets = append(in.targets, depth)
+			}
+		case opCall, opRefFunc:
+			in.x = r.u32()
+			if in.x >= nfuncs {
+				return nil, fmt.Errorf("unknown function %d", in.x)
+			}
+		case opCallIndirect:
+			in.x, in.y = r.u32(), r.u32()
+			if int(in.x) >= len(m.Types) || int(in.y) >= len(m.tables) {
+				return nil, errors.New("call_indirect: unknown type or table")
+			}
+		case opSelectT:
+			r.valTypes()
+			in.op = opSelect
+		case opLocalGet, opLocalSet, opLocalTee:
+			in.x = r.u32()
+			if in.x >= nlocals {
+				return nil, fmt.Errorf("unknown local %d", in.x)
+			}
+		case opGlobalGet, opGlobalSet:
+			in.x = r.u32()
+			if int(in.x) >= len(m.globals) {
+				return nil, fmt.Errorf("unknown global %d", in.x)
+			}
+			if in.op == opGlobalSet && !m.globals[in.x].mutable {
+				return nil, fmt.Errorf("global %d is immutable", in.x)
+			}
+		case opTableGet, opTableSet:
+			in.x = r.u32()
+			if int(in.x) >= len(m.tables) {
+				return nil, fmt.Errorf("unknown table %d", in.x)
+			}
+		case opMemorySize, opMemoryGrow:
+			r.byte()
+			if m.memory == nil {
+				return nil, errors.New("memory instruction without a memory")
+			}
+		case opI32Const:
+			in.imm = uint64(uint32(r.sleb(32)))
+		case opI64Const:
+			in.imm = uint64(r.sleb(64))
+		case opF32Const:
+			if b := r.bytes(4); b != nil {
+				in.imm = uint64(binary.LittleEndian.Uint32(b))
+			}
+		case opF64Const:
+			if b := r.bytes(8); b != nil {
+				in.imm = binary.LittleEndian.Uint64(b)
+			}
+		case opRefNull:
+			r.byte()
+		case opPrefix:
+			sub := r.u32()
+			if sub > 17 {
+				return nil, fmt.Errorf("unsupported instruction 0xfc %d", sub)
+			}
+			in.op = uint16(prefixBase + sub)
+			if err := r.prefixImmediates(m, &in); err != nil {
+				return nil, err
+			}
+		case opUnreachable, opNop, opReturn, opDrop, opSelect, opRefIsNull:
+		default:
+			switch {
+			case in.op >= 0x28 && in.op <= 0x3e:
+				// Loads and stores: alignment, then offset
+				r.u32()
+				in.imm = uint64(r.u32())
+				if m.memory == nil {
+					return nil, errors.New("memory instruction without a memory")
+				}
+			case in.op >= 0x45 && in.op <= 0xc4:
+				// Numeric instructions have no immediates
+			default:
+				return nil, fmt.Errorf("unsupported instruction 0x%x", in.op)
+			}
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		code = append(code, in)
+	}
+}
+
+// blockType reads the type of a block and returns its parameter and result
+// counts
+func (r *reader) blockType(m *Module) (params, results uint32) {
+	if r.pos < len(r.data) {
+		switch r.data[r.pos] {
+		case 0x40:
+			r.pos++
+			return 0, 0
+		case I32, I64, F32, F64, FuncRef, ExternRef:
+			r.pos++
+			return 0, 1
+		}
+	}
+	idx := r.sleb(33)
+	if r.err != nil {
+		return 0, 0
+	}
+	if idx < 0 || int(idx) >= len(m.Types) {
+		r.fail(fmt.Errorf("unknown block type %d", idx))
+		return 0, 0
+	}
+	t := m.Types[idx]
+	return uint32(len(t.Params)), uint32(len(t.Results))
+}
+
+// prefixImmediates reads the immediates of an 0xfc instruction
+func (r *reader) prefixImmediates(m *Module, in *instr) error {
+	switch in.op {
+	case opMemoryInit:
+		in.x = r.u32()
+		r.byte()
+		if int(in.x) >= len(m.data) || m.memory == nil {
+			return fmt.Errorf("memory.init: unknown data segment %d", in.x)
+		}
+	case opDataDrop:
+		in.x = r.u32()
+		if int(in.x) >= len(m.data) {
+			return fmt.Errorf("data.drop: unknown data segment %d", in.x)
+		}
+	case opMemoryCopy, opMemoryFill:
+		r.byte()
+		if in.op == opMemoryCopy {
+			r.byte()
+		}
+		if m.memory == nil {
+			return errors.New("memory instruction without a memory")
+		}
+	case opTableInit:
+		in.x, in.y = r.u32(), r.u32()
+		if int(in.x) >= len(m.elements) || int(in.y) >= len(m.tables) {
+			return errors.New("table.init: unknown segment or table")
+		}
+	case opElemDrop:
+		in.x = r.u32()
+		if int(in.x) >= len(m.elements) {
+			return fmt.Errorf("elem.drop: unknown segment %d", in.x)
+		}
+	case opTableCopy:
+		in.x, in.y = r.u32(), r.u32()
+		if int(in.x) >= len(m.tables) || int(in.y) >= len(m.tables) {
+			return errors.New("table.copy: unknown table")
+		}
+	case opTableGrow, opTableSize, opTableFill:
+		in.x = r.u32()
+		if int(in.x) >= len(m.tables) {
+			return fmt.Errorf("unknown table %d", in.x)
+		}
+	}
+	return nil
+}
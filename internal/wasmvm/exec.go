@@ -0,0 +1,773 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmvm
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+// label is an entered block. A branch to it keeps arity values on top of the
+// stack, drops the rest down to height and continues at target.
+type label struct {
+	height int
+	arity  int
+	target int
+}
+
+// call runs function fn on the arguments on top of the stack, leaving its
+// results in their place
+func (i *Instance) call(fn uint32) {
+	m := i.module
+	if int(fn) < len(i.hosts) {
+		i.callHost(fn)
+		return
+	}
+	f := &m.funcs[int(fn)-len(i.hosts)]
+	t := m.Types[f.typ]
+
+	i.depth++
+	if i.depth > maxCallDepth {
+		trap("call stack exhausted")
+	}
+	base := i.sp - len(t.Params)
+	i.reserve(len(f.locals))
+	for range f.locals {
+		i.stack[i.sp] = 0
+		i.sp++
+	}
+
+	code := f.code
+	labels := make([]label, 0, 8)
+	pc := 0
+	for {
+		in := &code[pc]
+		pc++
+
+		i.steps++
+		if i.steps == checkInterval {
+			i.steps = 0
+			if err := i.ctx.Err(); err != nil {
+				panic(abort{err: err})
+			}
+		}
+
+		switch in.op {
+		case opUnreachable:
+			trap("unreachable")
+		case opNop:
+		case opBlock:
+			labels = append(labels, label{height: i.sp - int(in.params), arity: int(in.results), target: int(in.x) + 1})
+		case opLoop:
+			labels = append(labels, label{height: i.sp - int(in.params), arity: int(in.params), target: pc - 1})
+		case opIf:
+			cond := uint32(i.pop())
+			labels = append(labels, label{height: i.sp - int(in.params), arity: int(in.results), target: int(in.x) + 1})
+			if cond == 0 {
+				pc = int(in.y)
+			}
+		case opElse:
+			pc = int(in.x)
+		case opEnd:
+			if len(labels) == 0 {
+				i.ret(base, len(t.Results))
+				return
+			}
+			labels = labels[:len(labels)-1]
+		case opBr:
+			if !i.branch(&labels, &pc, int(in.x)) {
+				i.ret(base, len(t.Results))
+				return
+			}
+		case opBrIf:
+			if uint32(i.pop()) != 0 && !i.branch(&labels, &pc, int(in.x)) {
+				i.ret(base, len(t.Results))
+				return
+			}
+		case opBrTable:
+			idx := uint32(i.pop())
+			depth := in.targets[len(in.targets)-1]
+			if int(idx) < len(in.targets)-1 {
+				depth = in.targets[idx]
+			}
+			if !i.branch(&labels, &pc, int(depth)) {
+				i.ret(base, len(t.Results))
+				return
+			}
+		case opReturn:
+			i.ret(base, len(t.Results))
+			return
+		case opCall:
+			i.call(in.x)
+		case opCallIndirect:
+			idx := uint32(i.pop())
+			table := i.tables[in.y]
+			if int(idx) >= len(table) {
+				trap("undefined element")
+			}
+			ref := table[idx]
+			if ref == nullRef {
+				trap("uninitialized element")
+			}
+			if ft, _ := m.funcType(uint32(ref)); !ft.equal(m.Types[in.x]) {
+				trap("indirect call type mismatch")
+			}
+			i.call(uint32(ref))
+		case opDrop:
+			i.sp--
+		case opSelect:
+			cond := uint32(i.pop())
+			b := i.pop()
+			if cond == 0 {
+				i.stack[i.sp-1] = b
+			}
+		case opLocalGet:
+			i.push(i.stack[base+int(in.x)])
+		case opLocalSet:
+			i.stack[base+int(in.x)] = i.pop()
+		case opLocalTee:
+			i.stack[base+int(in.x)] = i.stack[i.sp-1]
+		case opGlobalGet:
+			i.push(i.globals[in.x])
+		case opGlobalSet:
+			i.globals[in.x] = i.pop()
+		case opTableGet:
+			idx := uint32(i.pop())
+			if int(idx) >= len(i.tables[in.x]) {
+				trap("out of bounds table access")
+			}
+			i.push(i.tables[in.x][idx])
+		case opTableSet:
+			v, idx := i.pop(), uint32(i.pop())
+			if int(idx) >= len(i.tables[in.x]) {
+				trap("out of bounds table access")
+			}
+			i.tables[in.x][idx] = v
+		case opMemorySize:
+			i.push(uint64(len(i.memory) / PageSize))
+		case opMemoryGrow:
+			i.push(uint64(i.growMemory(uint32(i.pop()))))
+		case opI32Const, opI64Const, opF32Const, opF64Const:
+			i.push(in.imm)
+		case opRefNull:
+			i.push(nullRef)
+		case opRefIsNull:
+			i.stack[i.sp-1] = b2u(i.stack[i.sp-1] == nullRef)
+		case opRefFunc:
+			i.push(uint64(in.x))
+		default:
+			switch {
+			case in.op >= 0x28 && in.op <= 0x3e:
+				i.memoryAccess(in)
+			case in.op >= prefixBase:
+				i.prefixed(in)
+			default:
+				i.numeric(in.op)
+			}
+		}
+	}
+}
+
+// branch leaves depth+1 blocks and jumps to the target of the last one. It
+// returns false when the branch targets the function itself.
+func (i *Instance) branch(labels *[]label, pc *int, depth int) bool {
+	ls := *labels
+	if depth >= len(ls) {
+		return false
+	}
+	l := ls[len(ls)-1-depth]
+	copy(i.stack[l.height:], i.stack[i.sp-l.arity:i.sp])
+	i.sp = l.height + l.arity
+	*labels = ls[:len(ls)-1-depth]
+	*pc = l.target
+	return true
+}
+
+// ret moves the results of the function whose locals start at base in place
+// of its arguments
+func (i *Instance) ret(base, results int) {
+	copy(i.stack[base:], i.stack[i.sp-results:i.sp])
+	i.sp = base + results
+	i.depth--
+}
+
+func (i *Instance) callHost(fn uint32) {
+	h := i.hosts[fn]
+	args := append([]uint64(nil), i.stack[i.sp-len(h.Type.Params):i.sp]...)
+	i.sp -= len(args)
+	results, err := h.Fn(i.ctx, i, args)
+	if err != nil {
+		panic(abort{err: err})
+	}
+	if len(results) != len(h.Type.Results) {
+		trap("host function returned the wrong number of results")
+	}
+	for _, r := range results {
+		i.push(r)
+	}
+}
+
+// growMemory adds delta pages, returning the previous size in pages or -1
+// when memory cannot grow that far
+func (i *Instance) growMemory(delta uint32) uint32 {
+	pages := uint32(len(i.memory) / PageSize)
+	if i.module.memory == nil || uint64(pages)+uint64(delta) > uint64(i.maxPages) {
+		return math.MaxUint32
+	}
+	if delta > 0 {
+		memory := make([]byte, int(pages+delta)*PageSize)
+		copy(memory, i.memory)
+		i.memory = memory
+	}
+	return pages
+}
+
+// address returns the effective address of an access of size bytes
+func (i *Instance) address(in *instr, size uint64) uint64 {
+	ea := uint64(uint32(i.pop())) + in.imm
+	if ea+size > uint64(len(i.memory)) {
+		trap("out of bounds memory access")
+	}
+	return ea
+}
+
+func (i *Instance) memoryAccess(in *instr) {
+	le := binary.LittleEndian
+	if in.op >= 0x36 {
+		v := i.pop()
+		switch in.op {
+		case 0x36, 0x38: // i32.store, f32.store
+			le.PutUint32(i.memory[i.address(in, 4):], uint32(v))
+		case 0x37, 0x39: // i64.store, f64.store
+			le.PutUint64(i.memory[i.address(in, 8):], v)
+		case 0x3a, 0x3c: // store8
+			i.memory[i.address(in, 1)] = byte(v)
+		case 0x3b, 0x3d: // store16
+			le.PutUint16(i.memory[i.address(in, 2):], uint16(v))
+		case 0x3e: // i64.store32
+			le.PutUint32(i.memory[i.address(in, 4):], uint32(v))
+		}
+		return
+	}
+
+	var v uint64
+	switch in.op {
+	case 0x28, 0x2a: // i32.load, f32.load
+		v = uint64(le.Uint32(i.memory[i.address(in, 4):]))
+	case 0x29, 0x2b: // i64.load, f64.load
+		v = le.Uint64(i.memory[i.address(in, 8):])
+	case 0x2c: // i32.load8_s
+		v = uint64(uint32(int32(int8(i.memory[i.address(in, 1)]))))
+	case 0x2d, 0x31: // load8_u
+		v = uint64(i.memory[i.address(in, 1)])
+	case 0x2e: // i32.load16_s
+		v = uint64(uint32(int32(int16(le.Uint16(i.memory[i.address(in, 2):])))))
+	case 0x2f, 0x33: // load16_u
+		v = uint64(le.Uint16(i.memory[i.address(in, 2):]))
+	case 0x30: // i64.load8_s
+		v = uint64(int64(int8(i.memory[i.address(in, 1)])))
+	case 0x32: // i64.load16_s
+		v = uint64(int64(int16(le.Uint16(i.memory[i.address(in, 2):]))))
+	case 0x34: // i64.load32_s
+		v = uint64(int64(int32(le.Uint32(i.memory[i.address(in, 4):]))))
+	case 0x35: // i64.load32_u
+		v = uint64(le.Uint32(i.memory[i.address(in, 4):]))
+	}
+	i.push(v)
+}
+
+// prefixed runs an 0xfc instruction
+func (i *Instance) prefixed(in *instr) {
+	switch in.op {
+	case opMemoryInit:
+		n, s, d := uint64(uint32(i.pop())), uint64(uint32(i.pop())), uint64(uint32(i.pop()))
+		data := i.data[in.x]
+		if s+n > uint64(len(data)) || d+n > uint64(len(i.memory)) {
+			trap("out of bounds memory access")
+		}
+		copy(i.memory[d:], data[s:s+n])
+	case opDataDrop:
+		i.data[in.x] = nil
+	case opMemoryCopy:
+		n, s, d := uint64(uint32(i.pop())), uint64(uint32(i.pop())), uint64(uint32(i.pop()))
+		if s+n > uint64(len(i.memory)) || d+n > uint64(len(i.memory)) {
+			trap("out of bounds memory access")
+		}
+		copy(i.memory[d:d+n], i.memory[s:s+n])
+	case opMemoryFill:
+		n, v, d := uint64(uint32(i.pop())), byte(i.pop()), uint64(uint32(i.pop()))
+		if d+n > uint64(len(i.memory)) {
+			trap("out of bounds memory access")
+		}
+		mem := i.memory[d : d+n]
+		for j := range mem {
+			mem[j] = v
+		}
+	case opTableInit:
+		n, s, d := uint64(uint32(i.pop())), uint64(uint32(i.pop())), uint64(uint32(i.pop()))
+		elems, table := i.elements[in.x], i.tables[in.y]
+		if s+n > uint64(len(elems)) || d+n > uint64(len(table)) {
+			trap("out of bounds table access")
+		}
+		copy(table[d:], elems[s:s+n])
+	case opElemDrop:
+		i.elements[in.x] = nil
+	case opTableCopy:
+		n, s, d := uint64(uint32(i.pop())), uint64(uint32(i.pop())), uint64(uint32(i.pop()))
+		dst, src := i.tables[in.x], i.tables[in.y]
+		if s+n > uint64(len(src)) || d+n > uint64(len(dst)) {
+			trap("out of bounds table access")
+		}
+		copy(dst[d:d+n], src[s:s+n])
+	case opTableGrow:
+		n, v := uint32(i.pop()), i.pop()
+		table := i.tables[in.x]
+		limit := uint64(maxTableSize)
+		if t := i.module.tables[in.x]; t.hasMax && uint64(t.max) < limit {
+			limit = uint64(t.max)
+		}
+		if uint64(len(table))+uint64(n) > limit {
+			i.push(math.MaxUint32)
+			break
+		}
+		for j := uint32(0); j < n; j++ {
+			table = append(table, v)
+		}
+		i.push(uint64(len(i.tables[in.x])))
+		i.tables[in.x] = table
+	case opTableSize:
+		i.push(uint64(len(i.tables[in.x])))
+	case opTableFill:
+		n, v, d := uint64(uint32(i.pop())), i.pop(), uint64(uint32(i.pop()))
+		table := i.tables[in.x]
+		if d+n > uint64(len(table)) {
+			trap("out of bounds table access")
+		}
+		for j := d; j < d+n; j++ {
+			table[j] = v
+		}
+	default:
+		// Saturating truncations, 0xfc 0 to 7
+		v := i.pop()
+		x := floatOperand(in.op-prefixBase < 2 || in.op-prefixBase == 4 || in.op-prefixBase == 5, v)
+		switch in.op - prefixBase {
+		case 0, 2:
+			i.push(uint64(uint32(satInt(x, math.MinInt32, math.MaxInt32))))
+		case 1, 3:
+			i.push(uint64(satUint(x, math.MaxUint32)))
+		case 4, 6:
+			i.push(uint64(satInt(x, math.MinInt64, math.MaxInt64)))
+		case 5, 7:
+			i.push(satUint(x, math.MaxUint64))
+		}
+	}
+}
+
+// floatOperand decodes an f32 or f64 operand as a float64, which holds every
+// f32 exactly
+func floatOperand(isF32 bool, v uint64) float64 {
+	if isF32 {
+		return float64(math.Float32frombits(uint32(v)))
+	}
+	return math.Float64frombits(v)
+}
+
+func satInt(x float64, lo, hi int64) int64 {
+	switch {
+	case x != x:
+		return 0
+	case x <= float64(lo):
+		return lo
+	case x >= float64(hi):
+		return hi
+	}
+	return int64(x)
+}
+
+func satUint(x float64, hi uint64) uint64 {
+	switch {
+	case x != x || x <= 0:
+		return 0
+	case x >= float64(hi):
+		return hi
+	}
+	return uint64(x)
+}
+
+// truncInt truncates x to a signed integer in [lo, hi], trapping when it is
+// NaN or out of range. lo and hi are powers of two, exact as floats.
+func truncInt(x float64, lo, hiExclusive float64) int64 {
+	if x != x {
+		trap("invalid conversion to integer")
+	}
+	x = math.Trunc(x)
+	if x < lo || x >= hiExclusive {
+		trap("integer overflow")
+	}
+	return int64(x)
+}
+
+func truncUint(x float64, hiExclusive float64) uint64 {
+	if x != x {
+		trap("invalid conversion to integer")
+	}
+	x = math.Trunc(x)
+	if x <= -1 || x >= hiExclusive {
+		trap("integer overflow")
+	}
+	if x >= 1<<63 {
+		return uint64(x-(1<<63)) + 1<<63
+	}
+	return uint64(x)
+}
+
+func b2u(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func f32(v uint64) float32 {
+	return math.Float32frombits(uint32(v))
+}
+
+func f32u(f float32) uint64 {
+	return uint64(math.Float32bits(f))
+}
+
+func f64(v uint64) float64 {
+	return math.Float64frombits(v)
+}
+
+func f64u(f float64) uint64 {
+	return math.Float64bits(f)
+}
+
+// numeric runs the instructions 0x45 to 0xc4, which take their operands
+// from the stack and have no immediates
+func (i *Instance) numeric(op uint16) {
+	switch {
+	case op == 0x45 || op == 0x50 || (op >= 0x67 && op <= 0x69) || (op >= 0x79 && op <= 0x7b) ||
+		(op >= 0x8b && op <= 0x91) || (op >= 0x99 && op <= 0x9f) || op >= 0xa7:
+		i.stack[i.sp-1] = unary(op, i.stack[i.sp-1])
+	default:
+		b := i.pop()
+		i.stack[i.sp-1] = binaryOp(op, i.stack[i.sp-1], b)
+	}
+}
+
+func unary(op uint16, v uint64) uint64 {
+	a32, a64 := uint32(v), v
+	switch op {
+	case 0x45: // i32.eqz
+		return b2u(a32 == 0)
+	case 0x50: // i64.eqz
+		return b2u(a64 == 0)
+	case 0x67:
+		return uint64(bits.LeadingZeros32(a32))
+	case 0x68:
+		return uint64(bits.TrailingZeros32(a32))
+	case 0x69:
+		return uint64(bits.OnesCount32(a32))
+	case 0x79:
+		return uint64(bits.LeadingZeros64(a64))
+	case 0x7a:
+		return uint64(bits.TrailingZeros64(a64))
+	case 0x7b:
+		return uint64(bits.OnesCount64(a64))
+
+	case 0x8b: // f32.abs
+		return uint64(a32 &^ (1 << 31))
+	case 0x8c: // f32.neg
+		return uint64(a32 ^ 1<<31)
+	case 0x8d:
+		return f32u(float32(math.Ceil(float64(f32(v)))))
+	case 0x8e:
+		return f32u(float32(math.Floor(float64(f32(v)))))
+	case 0x8f:
+		return f32u(float32(math.Trunc(float64(f32(v)))))
+	case 0x90:
+		return f32u(float32(math.RoundToEven(float64(f32(v)))))
+	case 0x91:
+		return f32u(float32(math.Sqrt(float64(f32(v)))))
+	case 0x99: // f64.abs
+		return a64 &^ (1 << 63)
+	case 0x9a: // f64.neg
+		return a64 ^ 1<<63
+	case 0x9b:
+		return f64u(math.Ceil(f64(v)))
+	case 0x9c:
+		return f64u(math.Floor(f64(v)))
+	case 0x9d:
+		return f64u(math.Trunc(f64(v)))
+	case 0x9e:
+		return f64u(math.RoundToEven(f64(v)))
+	case 0x9f:
+		return f64u(math.Sqrt(f64(v)))
+
+	case 0xa7: // i32.wrap_i64
+		return uint64(a32)
+	case 0xa8: // i32.trunc_f32_s
+		return uint64(uint32(truncInt(float64(f32(v)), math.MinInt32, 1<<31)))
+	case 0xa9: // i32.trunc_f32_u
+		return truncUint(float64(f32(v)), 1<<32)
+	case 0xaa: // i32.trunc_f64_s
+		return uint64(uint32(truncInt(f64(v), math.MinInt32, 1<<31)))
+	case 0xab: // i32.trunc_f64_u
+		return truncUint(f64(v), 1<<32)
+	case 0xac: // i64.extend_i32_s
+		return uint64(int64(int32(a32)))
+	case 0xad: // i64.extend_i32_u
+		return uint64(a32)
+	case 0xae: // i64.trunc_f32_s
+		return uint64(truncInt(float64(f32(v)), math.MinInt64, 1<<63))
+	case 0xaf: // i64.trunc_f32_u
+		return truncUint(float64(f32(v)), 1<<64)
+	case 0xb0: // i64.trunc_f64_s
+		return uint64(truncInt(f64(v), math.MinInt64, 1<<63))
+	case 0xb1: // i64.trunc_f64_u
+		return truncUint(f64(v), 1<<64)
+	case 0xb2: // f32.convert_i32_s
+		return f32u(float32(int32(a32)))
+	case 0xb3: // f32.convert_i32_u
+		return f32u(float32(a32))
+	case 0xb4: // f32.convert_i64_s
+		return f32u(float32(int64(a64)))
+	case 0xb5: // f32.convert_i64_u
+		return f32u(float32(a64))
+	case 0xb6: // f32.demote_f64
+		return f32u(float32(f64(v)))
+	case 0xb7: // f64.convert_i32_s
+		return f64u(float64(int32(a32)))
+	case 0xb8: // f64.convert_i32_u
+		return f64u(float64(a32))
+	case 0xb9: // f64.convert_i64_s
+		return f64u(float64(int64(a64)))
+	case 0xba: // f64.convert_i64_u
+		return f64u(float64(a64))
+	case 0xbb: // f64.promote_f32
+		return f64u(float64(f32(v)))
+	case 0xbc, 0xbe: // i32.reinterpret_f32, f32.reinterpret_i32
+		return uint64(a32)
+	case 0xbd, 0xbf: // i64.reinterpret_f64, f64.reinterpret_i64
+		return a64
+	case 0xc0: // i32.extend8_s
+		return uint64(uint32(int32(int8(a32))))
+	case 0xc1: // i32.extend16_s
+		return uint64(uint32(int32(int16(a32))))
+	case 0xc2: // i64.extend8_s
+		return uint64(int64(int8(a64)))
+	case 0xc3: // i64.extend16_s
+		return uint64(int64(int16(a64)))
+	case 0xc4: // i64.extend32_s
+		return uint64(int64(int32(a64)))
+	}
+	trap("unknown instruction")
+	return 0
+}
+
+func binaryOp(op uint16, x, y uint64) uint64 {
+	a32, b32 := uint32(x), uint32(y)
+	s32a, s32b := int32(a32), int32(b32)
+	s64a, s64b := int64(x), int64(y)
+	switch op {
+	case 0x46:
+		return b2u(a32 == b32)
+	case 0x47:
+		return b2u(a32 != b32)
+	case 0x48:
+		return b2u(s32a < s32b)
+	case 0x49:
+		return b2u(a32 < b32)
+	case 0x4a:
+		return b2u(s32a > s32b)
+	case 0x4b:
+		return b2u(a32 > b32)
+	case 0x4c:
+		return b2u(s32a <= s32b)
+	case 0x4d:
+		return b2u(a32 <= b32)
+	case 0x4e:
+		return b2u(s32a >= s32b)
+	case 0x4f:
+		return b2u(a32 >= b32)
+
+	case 0x51:
+		return b2u(x == y)
+	case 0x52:
+		return b2u(x != y)
+	case 0x53:
+		return b2u(s64a < s64b)
+	case 0x54:
+		return b2u(x < y)
+	case 0x55:
+		return b2u(s64a > s64b)
+	case 0x56:
+		return b2u(x > y)
+	case 0x57:
+		return b2u(s64a <= s64b)
+	case 0x58:
+		return b2u(x <= y)
+	case 0x59:
+		return b2u(s64a >= s64b)
+	case 0x5a:
+		return b2u(x >= y)
+
+	case 0x5b:
+		return b2u(f32(x) == f32(y))
+	case 0x5c:
+		return b2u(f32(x) != f32(y))
+	case 0x5d:
+		return b2u(f32(x) < f32(y))
+	case 0x5e:
+		return b2u(f32(x) > f32(y))
+	case 0x5f:
+		return b2u(f32(x) <= f32(y))
+	case 0x60:
+		return b2u(f32(x) >= f32(y))
+	case 0x61:
+		return b2u(f64(x) == f64(y))
+	case 0x62:
+		return b2u(f64(x) != f64(y))
+	case 0x63:
+		return b2u(f64(x) < f64(y))
+	case 0x64:
+		return b2u(f64(x) > f64(y))
+	case 0x65:
+		return b2u(f64(x) <= f64(y))
+	case 0x66:
+		return b2u(f64(x) >= f64(y))
+
+	case 0x6a:
+		return uint64(a32 + b32)
+	case 0x6b:
+		return uint64(a32 - b32)
+	case 0x6c:
+		return uint64(a32 * b32)
+	case 0x6d: // i32.div_s
+		if b32 == 0 {
+			trap("integer divide by zero")
+		}
+		if s32a == math.MinInt32 && s32b == -1 {
+			trap("integer overflow")
+		}
+		return uint64(uint32(s32a / s32b))
+	case 0x6e:
+		if b32 == 0 {
+			trap("integer divide by zero")
+		}
+		return uint64(a32 / b32)
+	case 0x6f: // i32.rem_s
+		if b32 == 0 {
+			trap("integer divide by zero")
+		}
+		if s32b == -1 {
+			return 0
+		}
+		return uint64(uint32(s32a % s32b))
+	case 0x70:
+		if b32 == 0 {
+			trap("integer divide by zero")
+		}
+		return uint64(a32 % b32)
+	case 0x71:
+		return uint64(a32 & b32)
+	case 0x72:
+		return uint64(a32 | b32)
+	case 0x73:
+		return uint64(a32 ^ b32)
+	case 0x74:
+		return uint64(a32 << (b32 & 31))
+	case 0x75:
+		return uint64(uint32(s32a >> (b32 & 31)))
+	case 0x76:
+		return uint64(a32 >> (b32 & 31))
+	case 0x77:
+		return uint64(bits.RotateLeft32(a32, int(b32&31)))
+	case 0x78:
+		return uint64(bits.RotateLeft32(a32, -int(b32&31)))
+
+	case 0x7c:
+		return x + y
+	case 0x7d:
+		return x - y
+	case 0x7e:
+		return x * y
+	case 0x7f: // i64.div_s
+		if y == 0 {
+			trap("integer divide by zero")
+		}
+		if s64a == math.MinInt64 && s64b == -1 {
+			trap("integer overflow")
+		}
+		return uint64(s64a / s64b)
+	case 0x80:
+		if y == 0 {
+			trap("integer divide by zero")
+		}
+		return x / y
+	case 0x81: // i64.rem_s
+		if y == 0 {
+			trap("integer divide by zero")
+		}
+		if s64b == -1 {
+			return 0
+		}
+		return uint64(s64a % s64b)
+	case 0x82:
+		if y == 0 {
+			trap("integer divide by zero")
+		}
+		return x % y
+	case 0x83:
+		return x & y
+	case 0x84:
+		return x | y
+	case 0x85:
+		return x ^ y
+	case 0x86:
+		return x << (y & 63)
+	case 0x87:
+		return uint64(s64a >> (y & 63))
+	case 0x88:
+		return x >> (y & 63)
+	case 0x89:
+		return bits.RotateLeft64(x, int(y&63))
+	case 0x8a:
+		return bits.RotateLeft64(x, -int(y&63))
+
+	case 0x92:
+		return f32u(f32(x) + f32(y))
+	case 0x93:
+		return f32u(f32(x) - f32(y))
+	case 0x94:
+		return f32u(f32(x) * f32(y))
+	case 0x95:
+		return f32u(f32(x) / f32(y))
+	case 0x96:
+		return f32u(float32(math.Min(float64(f32(x)), float64(f32(y)))))
+	case 0x97:
+		return f32u(float32(math.Max(float64(f32(x)), float64(f32(y)))))
+	case 0x98: // f32.copysign
+		return uint64(a32&^(1<<31) | b32&(1<<31))
+	case 0xa0:
+		return f64u(f64(x) + f64(y))
+	case 0xa1:
+		return f64u(f64(x) - f64(y))
+	case 0xa2:
+		return f64u(f64(x) * f64(y))
+	case 0xa3:
+		return f64u(f64(x) / f64(y))
+	case 0xa4:
+		return f64u(math.Min(f64(x), f64(y)))
+	case 0xa5:
+		return f64u(math.Max(f64(x), f64(y)))
+	case 0xa6: // f64.copysign
+		return x&^(1<<63) | y&(1<<63)
+	}
+	trap("unknown instruction")
+	return 0
+}
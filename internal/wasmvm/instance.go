@@ -0,0 +1,287 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// DefaultMaxMemoryPages bounds memory when Config does not: 64 MiB
+const DefaultMaxMemoryPages = 1024
+
+const (
+	// maxCallDepth bounds recursion in the module
+	maxCallDepth = 10000
+	// maxStack bounds the values on the operand stack
+	maxStack = 1 << 20
+	// maxTableSize bounds the elements of a table
+	maxTableSize = 1 << 20
+	// checkInterval is the number of instructions between context checks
+	checkInterval = 1 << 14
+)
+
+// HostFunc is a function the host provides to a module. Values are passed as
+// their bit patterns: i32 and f32 in the low 32 bits. Host functions may
+// access the instance's memory but not call into it.
+type HostFunc struct {
+	Type FuncType
+	Fn   func(ctx context.Context, inst *Instance, args []uint64) ([]uint64, error)
+}
+
+// Config configures an instance
+type Config struct {
+	// Imports are the host functions, keyed by "module.name"
+	Imports map[string]HostFunc
+	// MaxMemoryPages bounds memory growth, DefaultMaxMemoryPages if zero
+	MaxMemoryPages uint32
+}
+
+// Trap is a runtime error of the module, such as an out-of-bounds memory
+// access or unreachable
+type Trap struct {
+	Message string
+}
+
+func (t *Trap) Error() string {
+	return "wasm trap: " + t.Message
+}
+
+func trap(msg string) {
+	panic(&Trap{Message: msg})
+}
+
+// abort carries an error that stops execution, such as a cancelled context
+// or a failed host function, through the interpreter
+type abort struct {
+	err error
+}
+
+// Instance is an instantiated module. It is not safe for concurrent use.
+type Instance struct {
+	module   *Module
+	hosts    []HostFunc
+	memory   []byte
+	maxPages uint32
+	globals  []uint64
+	tables   [][]uint64
+	// data and elements are the segments not yet dropped
+	data     [][]byte
+	elements [][]uint64
+
+	ctx   context.Context
+	stack []uint64
+	sp    int
+	depth int
+	steps int
+}
+
+// Instantiate links m with the host functions, initializes its memory,
+// tables and globals and runs its start function
+func Instantiate(ctx context.Context, m *Module, cfg Config) (*Instance, error) {
+	i := &Instance{module: m, maxPages: cfg.MaxMemoryPages, stack: make([]uint64, 1024)}
+	if i.maxPages == 0 {
+		i.maxPages = DefaultMaxMemoryPages
+	}
+
+	for _, imp := range m.Imports {
+		key := imp.Module + "." + imp.Name
+		h, ok := cfg.Imports[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown import %s", key)
+		}
+		if !h.Type.equal(m.Types[imp.Type]) {
+			return nil, fmt.Errorf("import %s has a different signature", key)
+		}
+		i.hosts = append(i.hosts, h)
+	}
+
+	if m.memory != nil {
+		if m.memory.min > i.maxPages {
+			return nil, fmt.Errorf("module needs %d memory pages, more than the limit of %d", m.memory.min, i.maxPages)
+		}
+		if m.memory.hasMax && m.memory.max < i.maxPages {
+			i.maxPages = m.memory.max
+		}
+		i.memory = make([]byte, int(m.memory.min)*PageSize)
+	}
+	for _, g := range m.globals {
+		i.globals = append(i.globals, g.init.value)
+	}
+	for _, t := range m.tables {
+		if t.min > maxTableSize {
+			return nil, fmt.Errorf("table of %d elements is too large", t.min)
+		}
+		elems := make([]uint64, t.min)
+		for j := range elems {
+			elems[j] = nullRef
+		}
+		i.tables = append(i.tables, elems)
+	}
+	for _, e := range m.elements {
+		values := make([]uint64, len(e.init))
+		for j, init := range e.init {
+			values[j] = init.value
+		}
+		i.elements = append(i.elements, values)
+	}
+	for _, d := range m.data {
+		i.data = append(i.data, d.init)
+	}
+
+	for idx, e := range m.elements {
+		switch e.mode {
+		case segmentActive:
+			offset, err := i.offset(e.offset)
+			if err != nil {
+				return nil, err
+			}
+			t := i.tables[e.table]
+			if uint64(offset)+uint64(len(e.init)) > uint64(len(t)) {
+				return nil, errors.New("element segment does not fit its table")
+			}
+			copy(t[offset:], i.elements[idx])
+			i.elements[idx] = nil
+		case segmentDeclarative:
+			i.elements[idx] = nil
+		}
+	}
+	for idx, d := range m.data {
+		if d.mode != segmentActive {
+			continue
+		}
+		offset, err := i.offset(d.offset)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(offset)+uint64(len(d.init)) > uint64(len(i.memory)) {
+			return nil, errors.New("data segment does not fit memory")
+		}
+		copy(i.memory[offset:], d.init)
+		i.data[idx] = nil
+	}
+
+	if m.start != nil {
+		if _, err := i.invoke(ctx, *m.start, nil); err != nil {
+			return nil, err
+		}
+	}
+	return i, nil
+}
+
+// offset evaluates the offset of an active segment
+func (i *Instance) offset(e constExpr) (uint32, error) {
+	switch e.op {
+	case opI32Const:
+		return uint32(e.value), nil
+	case opGlobalGet:
+		if int(e.value) >= len(i.globals) {
+			return 0, fmt.Errorf("segment offset refers to unknown global %d", e.value)
+		}
+		return uint32(i.globals[e.value]), nil
+	}
+	return 0, errors.New("segment offset is not an i32")
+}
+
+// Call runs the exported function name with args, returning its results.
+// A trap is returned as a *Trap; when ctx is done, execution stops and
+// ctx's error is returned.
+func (i *Instance) Call(ctx context.Context, name string, args ...uint64) ([]uint64, error) {
+	for _, e := range i.module.Exports {
+		if e.Name == name && e.Kind == ExternFunc {
+			return i.invoke(ctx, e.Index, args)
+		}
+	}
+	return nil, fmt.Errorf("module does not export function %s", name)
+}
+
+func (i *Instance) invoke(ctx context.Context, fn uint32, args []uint64) (results []uint64, err error) {
+	t, _ := i.module.funcType(fn)
+	if len(args) != len(t.Params) {
+		return nil, fmt.Errorf("function takes %d arguments, got %d", len(t.Params), len(args))
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	i.ctx, i.sp, i.depth, i.steps = ctx, 0, 0, 0
+	defer func() {
+		i.ctx = nil
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case *Trap:
+				err = r
+			case abort:
+				err = r.err
+			case runtime.Error:
+				// Code the compiler does not check, such as an operand
+				// stack underflow, fails the call
+				err = fmt.Errorf("invalid module: %v", r)
+			default:
+				panic(r)
+			}
+		}
+	}()
+	for _, a := range args {
+		i.push(a)
+	}
+	i.call(fn)
+	return append([]uint64(nil), i.stack[:len(t.Results)]...), nil
+}
+
+// Memory returns the module's memory. The slice is replaced when memory
+// grows.
+func (i *Instance) Memory() []byte {
+	return i.memory
+}
+
+// Read returns n bytes of memory at ptr, or false when they are out of
+// bounds
+func (i *Instance) Read(ptr, n uint32) ([]byte, bool) {
+	end := uint64(ptr) + uint64(n)
+	if end > uint64(len(i.memory)) {
+		return nil, false
+	}
+	return i.memory[ptr:end], true
+}
+
+// Write copies b to memory at ptr, returning false when it does not fit
+func (i *Instance) Write(ptr uint32, b []byte) bool {
+	if uint64(ptr)+uint64(len(b)) > uint64(len(i.memory)) {
+		return false
+	}
+	copy(i.memory[ptr:], b)
+	return true
+}
+
+func (i *Instance) push(v uint64) {
+	if i.sp == len(i.stack) {
+		i.grow()
+	}
+	i.stack[i.sp] = v
+	i.sp++
+}
+
+func (i *Instance) pop() uint64 {
+	i.sp--
+	return i.stack[i.sp]
+}
+
+func (i *Instance) grow() {
+	if len(i.stack) >= maxStack {
+		trap("operand stack exhausted")
+	}
+	stack := make([]uint64, 2*len(i.stack))
+	copy(stack, i.stack)
+	i.stack = stack
+}
+
+// reserve makes room for n more values
+func (i *Instance) reserve(n int) {
+	for i.sp+n > len(i.stack) {
+		i.grow()
+	}
+}
@@ -0,0 +1,636 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package wasmvm is a small interpreter for WebAssembly modules, used to run
+// user-supplied hooks in a sandbox. Modules can only reach the host through
+// the functions they are given, memory is bounded and execution stops when
+// the context is done.
+//
+// It implements the WebAssembly 2.0 instruction set except SIMD, threads and
+// tail calls, which toolchains only emit when asked to.
+package wasmvm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Value types
+const (
+	I32       byte = 0x7f
+	I64       byte = 0x7e
+	F32       byte = 0x7d
+	F64       byte = 0x7c
+	FuncRef   byte = 0x70
+	ExternRef byte = 0x6f
+)
+
+// External kinds of imports and exports
+const (
+	ExternFunc   byte = 0
+	ExternTable  byte = 1
+	ExternMemory byte = 2
+	ExternGlobal byte = 3
+)
+
+// PageSize is the size of a memory page
+const PageSize = 65536
+
+// FuncType is a function signature
+type FuncType struct {
+	Params  []byte
+	Results []byte
+}
+
+// String renders the signature as WAT param and result clauses
+func (t FuncType) String() string {
+	var parts []string
+	if len(t.Params) > 0 {
+		parts = append(parts, "(param "+typeNames(t.Params)+")")
+	}
+	if len(t.Results) > 0 {
+		parts = append(parts, "(result "+typeNames(t.Results)+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+func typeNames(types []byte) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		switch t {
+		case I32:
+			names[i] = "i32"
+		case I64:
+			names[i] = "i64"
+		case F32:
+			names[i] = "f32"
+		case F64:
+			names[i] = "f64"
+		case FuncRef:
+			names[i] = "funcref"
+		case ExternRef:
+			names[i] = "externref"
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+func (t FuncType) equal(o FuncType) bool {
+	return string(t.Params) == string(o.Params) && string(t.Results) == string(o.Results)
+}
+
+// Import is a function the module expects the host to provide. Only function
+// imports are supported.
+type Import struct {
+	Module string
+	Name   string
+	Type   uint32
+}
+
+// Export is a named item of the module
+type Export struct {
+	Name  string
+	Kind  byte
+	Index uint32
+}
+
+type limits struct {
+	min    uint32
+	max    uint32
+	hasMax bool
+}
+
+type table struct {
+	elem byte
+	limits
+}
+
+type global struct {
+	typ     byte
+	mutable bool
+	init    constExpr
+}
+
+// constExpr is an initializer: a constant, or the value of a global or
+// function reference
+type constExpr struct {
+	op    byte
+	value uint64
+}
+
+type elemSegment struct {
+	mode   byte // segmentActive, segmentPassive or segmentDeclarative
+	table  uint32
+	offset constExpr
+	// init holds one initializer per element
+	init []constExpr
+}
+
+type dataSegment struct {
+	mode   byte
+	offset constExpr
+	init   []byte
+}
+
+const (
+	segmentActive byte = iota
+	segmentPassive
+	segmentDeclarative
+)
+
+type function struct {
+	typ    uint32
+	locals []byte
+	body   []byte
+	code   []instr
+}
+
+// Module is a decoded module, ready to instantiate
+type Module struct {
+	Types   []FuncType
+	Imports []Import
+	Exports []Export
+
+	funcs    []function
+	tables   []table
+	memory   *limits
+	globals  []global
+	start    *uint32
+	elements []elemSegment
+	data     []dataSegment
+}
+
+// funcType returns the signature of function idx, counting imports first
+func (m *Module) funcType(idx uint32) (FuncType, bool) {
+	if int(idx) < len(m.Imports) {
+		return m.Types[m.Imports[idx].Type], true
+	}
+	idx -= uint32(len(m.Imports))
+	if int(idx) >= len(m.funcs) {
+		return FuncType{}, false
+	}
+	return m.Types[m.funcs[idx].typ], true
+}
+
+// ExportedFunc returns the signature of the exported function name
+func (m *Module) ExportedFunc(name string) (FuncType, bool) {
+	for _, e := range m.Exports {
+		if e.Name == name && e.Kind == ExternFunc {
+			return m.funcType(e.Index)
+		}
+	}
+	return FuncType{}, false
+}
+
+// Decode parses and compiles a module in the binary format
+func Decode(data []byte) (*Module, error) {
+	if len(data) < 8 || string(data[:4]) != "\x00asm" {
+		return nil, errors.New("not a WebAssembly module")
+	}
+	if v := binary.LittleEndian.Uint32(data[4:8]); v != 1 {
+		return nil, fmt.Errorf("unsupported WebAssembly version %d", v)
+	}
+
+	m := &Module{}
+	r := &reader{data: data, pos: 8}
+	var funcTypes []uint32
+	for !r.done() {
+		id := r.byte()
+		size := r.u32()
+		if r.err != nil {
+			break
+		}
+		if uint64(r.pos)+uint64(size) > uint64(len(r.data)) {
+			return nil, errors.New("section extends past the end of the module")
+		}
+		s := &reader{data: r.data[:r.pos+int(size)], pos: r.pos}
+		r.pos += int(size)
+
+		switch id {
+		case 0, 12:
+			// Custom sections and the data count are not needed
+		case 1:
+			for n := s.count(); n > 0 && s.err == nil; n-- {
+				if form := s.byte(); form != 0x60 {
+					return nil, fmt.Errorf("unsupported type form 0x%x", form)
+				}
+				m.Types = append(m.Types, FuncType{Params: s.valTypes(), Results: s.valTypes()})
+			}
+		case 2:
+			for n := s.count(); n > 0 && s.err == nil; n-- {
+				mod, name, kind := s.name(), s.name(), s.byte()
+				if kind != ExternFunc {
+					return nil, fmt.Errorf("import %s.%s: only function imports are supported", mod, name)
+				}
+				m.Imports = append(m.Imports, Import{Module: mod, Name: name, Type: s.u32()})
+			}
+		case 3:
+			for n := s.count(); n > 0 && s.err == nil; n-- {
+				funcTypes = append(funcTypes, s.u32())
+			}
+		case 4:
+			for n := s.count(); n > 0 && s.err == nil; n-- {
+				m.tables = append(m.tables, table{elem: s.byte(), limits: s.limits()})
+			}
+		case 5:
+			n := s.count()
+			if n > 1 {
+				return nil, errors.New("multiple memories are not supported")
+			}
+			if n == 1 {
+				l := s.limits()
+				m.memory = &l
+			}
+		case 6:
+			for n := s.count(); n > 0 && s.err == nil; n-- {
+				g := global{typ: s.byte(), mutable: s.byte() == 1}
+				g.init = s.constExpr()
+				m.globals = append(m.globals, g)
+			}
+		case 7:
+			for n := s.count(); n > 0 && s.err == nil; n-- {
+				m.Exports = append(m.Exports, Export{Name: s.name(), Kind: s.byte(), Index: s.u32()})
+			}
+		case 8:
+			idx := s.u32()
+			m.start = &idx
+		case 9:
+			for n := s.count(); n > 0 && s.err == nil; n-- {
+				m.elements = append(m.elements, s.elemSegment())
+			}
+		case 10:
+			n := s.count()
+			if int(n) != len(funcTypes) {
+				return nil, errors.New("function and code section sizes differ")
+			}
+			for i := 0; i < int(n) && s.err == nil; i++ {
+				size := s.u32()
+				if s.err != nil || uint64(s.pos)+uint64(size) > uint64(len(s.data)) {
+					return nil, errors.New("function body extends past its section")
+				}
+				b := &reader{data: s.data[:s.pos+int(size)], pos: s.pos}
+				s.pos += int(size)
+				f := function{typ: funcTypes[i]}
+				var total uint64
+				for groups := b.count(); groups > 0 && b.err == nil; groups-- {
+					count, typ := b.u32(), b.byte()
+					total += uint64(count)
+					if total > maxLocals {
+						return nil, errors.New("too many locals")
+					}
+					for j := uint32(0); j < count; j++ {
+						f.locals = append(f.locals, typ)
+					}
+				}
+				if b.err != nil {
+					return nil, b.err
+				}
+				f.body = b.data[b.pos:]
+				m.funcs = append(m.funcs, f)
+			}
+		case 11:
+			for n := s.count(); n > 0 && s.err == nil; n-- {
+				m.data = append(m.data, s.dataSegment())
+			}
+		default:
+			return nil, fmt.Errorf("unknown section %d", id)
+		}
+		if s.err != nil {
+			return nil, fmt.Errorf("section %d: %w", id, s.err)
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(funcTypes) != len(m.funcs) {
+		return nil, errors.New("function and code section sizes differ")
+	}
+
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+	for i := range m.funcs {
+		code, err := compile(m, &m.funcs[i])
+		if err != nil {
+			return nil, fmt.Errorf("function %d: %w", len(m.Imports)+i, err)
+		}
+		m.funcs[i].code = code
+	}
+	return m, nil
+}
+
+// check verifies the indexes between sections, so that instantiation and
+// execution can rely on them
+func (m *Module) check() error {
+	for _, imp := range m.Imports {
+		if int(imp.Type) >= len(m.Types) {
+			return fmt.Errorf("import %s.%s: unknown type %d", imp.Module, imp.Name, imp.Type)
+		}
+	}
+	for i, f := range m.funcs {
+		if int(f.typ) >= len(m.Types) {
+			return fmt.Errorf("function %d: unknown type %d", len(m.Imports)+i, f.typ)
+		}
+	}
+	nfuncs := uint32(len(m.Imports) + len(m.funcs))
+	for _, e := range m.Exports {
+		var n uint32
+		switch e.Kind {
+		case ExternFunc:
+			n = nfuncs
+		case ExternTable:
+			n = uint32(len(m.tables))
+		case ExternMemory:
+			if m.memory != nil {
+				n = 1
+			}
+		case ExternGlobal:
+			n = uint32(len(m.globals))
+		}
+		if e.Index >= n {
+			return fmt.Errorf("export %s: unknown index %d", e.Name, e.Index)
+		}
+	}
+	if m.start != nil {
+		if *m.start >= nfuncs {
+			return fmt.Errorf("unknown start function %d", *m.start)
+		}
+		if t, _ := m.funcType(*m.start); len(t.Params) > 0 || len(t.Results) > 0 {
+			return errors.New("start function must take and return nothing")
+		}
+	}
+	if m.memory != nil {
+		if m.memory.min > maxPages || (m.memory.hasMax && m.memory.max < m.memory.min) {
+			return errors.New("invalid memory limits")
+		}
+	}
+	for _, e := range m.elements {
+		if e.mode == segmentActive && int(e.table) >= len(m.tables) {
+			return fmt.Errorf("element segment: unknown table %d", e.table)
+		}
+		for _, init := range e.init {
+			if init.op == opRefFunc && init.value >= uint64(nfuncs) {
+				return fmt.Errorf("element segment: unknown function %d", init.value)
+			}
+		}
+	}
+	for _, d := range m.data {
+		if d.mode == segmentActive && m.memory == nil {
+			return errors.New("data segment without a memory")
+		}
+	}
+	for _, g := range m.globals {
+		if g.init.op == opGlobalGet {
+			// Without global imports, initializers cannot read globals
+			return errors.New("global initializer refers to a global")
+		}
+	}
+	return nil
+}
+
+// maxLocals bounds the locals of a function, so that a small module cannot
+// declare gigabytes of them
+const maxLocals = 50000
+
+// maxPages is the largest memory the format allows
+const maxPages = 65536
+
+// reader decodes the primitive encodings. The first error sticks and makes
+// further reads return zero.
+type reader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+var errEOF = errors.New("unexpected end of data")
+
+func (r *reader) done() bool {
+	return r.err != nil || r.pos >= len(r.data)
+}
+
+func (r *reader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *reader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if r.pos >= len(r.data) {
+		r.fail(errEOF)
+		return 0
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *reader) bytes(n uint32) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if uint64(r.pos)+uint64(n) > uint64(len(r.data)) {
+		r.fail(errEOF)
+		return nil
+	}
+	b := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b
+}
+
+// uleb reads an unsigned LEB128 number of at most bits bits
+func (r *reader) uleb(bits uint) uint64 {
+	var v uint64
+	for shift := uint(0); ; shift += 7 {
+		b := r.byte()
+		if r.err != nil {
+			return 0
+		}
+		if shift+7 > bits && b&0x7f>>(bits-shift) != 0 {
+			r.fail(errors.New("integer too large"))
+			return 0
+		}
+		v |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return v
+		}
+		if shift+7 >= bits {
+			r.fail(errors.New("integer representation too long"))
+			return 0
+		}
+	}
+}
+
+// sleb reads a signed LEB128 number of at most bits bits
+func (r *reader) sleb(bits uint) int64 {
+	var v int64
+	var shift uint
+	for {
+		b := r.byte()
+		if r.err != nil {
+			return 0
+		}
+		v |= int64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			if shift < 64 && b&0x40 != 0 {
+				v |= -1 << shift
+			}
+			if bits < 64 && (v < -1<<(bits-1) || v >= 1<<(bits-1)) {
+				r.fail(errors.New("integer too large"))
+				return 0
+			}
+			return v
+		}
+		if shift >= bits {
+			r.fail(errors.New("integer representation too long"))
+			return 0
+		}
+	}
+}
+
+func (r *reader) u32() uint32 {
+	return uint32(r.uleb(32))
+}
+
+// count reads a vector length, which cannot exceed the bytes left
+func (r *reader) count() uint32 {
+	n := r.u32()
+	if r.err == nil && uint64(n) > uint64(len(r.data)-r.pos) {
+		r.fail(errEOF)
+		return 0
+	}
+	return n
+}
+
+func (r *reader) name() string {
+	b := r.bytes(r.u32())
+	if r.err == nil && !utf8.Valid(b) {
+		r.fail(errors.New("name is not valid UTF-8"))
+	}
+	return string(b)
+}
+
+func (r *reader) valType() byte {
+	t := r.byte()
+	switch t {
+	case I32, I64, F32, F64, FuncRef, ExternRef:
+	default:
+		r.fail(fmt.Errorf("unsupported value type 0x%x", t))
+	}
+	return t
+}
+
+func (r *reader) valTypes() []byte {
+	n := r.count()
+	types := make([]byte, 0, n)
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		types = append(types, r.valType())
+	}
+	return types
+}
+
+func (r *reader) limits() limits {
+	var l limits
+	switch flag := r.byte(); flag {
+	case 0:
+		l.min = r.u32()
+	case 1:
+		l.min, l.max, l.hasMax = r.u32(), r.u32(), true
+	default:
+		r.fail(fmt.Errorf("unsupported limits flag 0x%x", flag))
+	}
+	return l
+}
+
+// constExpr reads a single-instruction initializer and its end
+func (r *reader) constExpr() constExpr {
+	e := constExpr{op: r.byte()}
+	switch e.op {
+	case opI32Const:
+		e.value = uint64(uint32(r.sleb(32)))
+	case opI64Const:
+		e.value = uint64(r.sleb(64))
+	case opF32Const:
+		e.value = uint64(binary.LittleEndian.Uint32(r.bytes(4)))
+	case opF64Const:
+		e.value = binary.LittleEndian.Uint64(r.bytes(8))
+	case opGlobalGet, opRefFunc:
+		e.value = uint64(r.u32())
+	case opRefNull:
+		r.byte()
+		e.value = nullRef
+	default:
+		r.fail(fmt.Errorf("unsupported constant expression 0x%x", e.op))
+	}
+	if r.byte() != opEnd {
+		r.fail(errors.New("constant expression is not a single instruction"))
+	}
+	return e
+}
+
+func (r *reader) elemSegment() elemSegment {
+	var e elemSegment
+	flags := r.u32()
+	if flags > 7 {
+		r.fail(fmt.Errorf("unsupported element segment flags %d", flags))
+		return e
+	}
+	switch {
+	case flags&1 == 0:
+		e.mode = segmentActive
+	case flags&2 == 0:
+		e.mode = segmentPassive
+	default:
+		e.mode = segmentDeclarative
+	}
+	if e.mode == segmentActive {
+		if flags&2 != 0 {
+			e.table = r.u32()
+		}
+		e.offset = r.constExpr()
+	}
+	if flags&3 != 0 {
+		// An element kind or reference type
+		r.byte()
+	}
+	n := r.count()
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		if flags&4 != 0 {
+			e.init = append(e.init, r.constExpr())
+		} else {
+			e.init = append(e.init, constExpr{op: opRefFunc, value: uint64(r.u32())})
+		}
+	}
+	return e
+}
+
+func (r *reader) dataSegment() dataSegment {
+	var d dataSegment
+	switch flags := r.u32(); flags {
+	case 0:
+		d.offset = r.constExpr()
+	case 1:
+		d.mode = segmentPassive
+	case 2:
+		if r.u32() != 0 {
+			r.fail(errors.New("data segment for an unknown memory"))
+		}
+		d.offset = r.constExpr()
+	default:
+		r.fail(fmt.Errorf("unsupported data segment flags %d", flags))
+	}
+	d.init = r.bytes(r.u32())
+	return d
+}
+
+// nullRef is the value of a null reference. Function references are the
+// function index.
+const nullRef = math.MaxUint64
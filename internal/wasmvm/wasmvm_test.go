@@ -0,0 +1,244 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmvm
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func uleb(v uint64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if v == 0 {
+			return out
+		}
+	}
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func vec(items ...[]byte) []byte {
+	return concat(uleb(uint64(len(items))), concat(items...))
+}
+
+func sized(b []byte) []byte {
+	return concat(uleb(uint64(len(b))), b)
+}
+
+func section(id byte, items ...[]byte) []byte {
+	return concat([]byte{id}, sized(vec(items...)))
+}
+
+func name(s string) []byte {
+	return sized([]byte(s))
+}
+
+// body encodes a function body with locals given as (count, type) groups
+func body(locals []byte, code ...byte) []byte {
+	return sized(concat(locals, code))
+}
+
+func module(sections ...[]byte) []byte {
+	return concat([]byte("\x00asm\x01\x00\x00\x00"), concat(sections...))
+}
+
+func instantiate(t *testing.T, wasm []byte, cfg Config) *Instance {
+	t.Helper()
+	m, err := Decode(wasm)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	inst, err := Instantiate(context.Background(), m, cfg)
+	if err != nil {
+		t.Fatalf("Instantiate: %v", err)
+	}
+	return inst
+}
+
+func call(t *testing.T, inst *Instance, fn string, args ...uint64) uint64 {
+	t.Helper()
+	res, err := inst.Call(context.Background(), fn, args...)
+	if err != nil {
+		t.Fatalf("%s: %v", fn, err)
+	}
+	if len(res) != 1 {
+		t.Fatalf("%s returned %d values", fn, len(res))
+	}
+	return res[0]
+}
+
+func TestControlFlowAndCalls(t *testing.T) {
+	wasm := module(
+		section(1,
+			[]byte{0x60, 1, I64, 1, I64},
+			[]byte{0x60, 1, I32, 1, I32},
+			[]byte{0x60, 0, 1, I32},
+		),
+		section(3, uleb(0), uleb(1), uleb(1), uleb(2), uleb(2)),
+		section(4, []byte{FuncRef, 0, 2}),
+		section(7,
+			concat(name("fac"), []byte{ExternFunc, 0}),
+			concat(name("sum"), []byte{ExternFunc, 1}),
+			concat(name("dispatch"), []byte{ExternFunc, 2}),
+		),
+		section(9, concat([]byte{0, opI32Const, 0, opEnd}, vec(uleb(3), uleb(4)))),
+		section(10,
+			// fac(n) = n == 0 ? 1 : n * fac(n - 1)
+			body(vec(), 0x20, 0, 0x50, 0x04, I64, 0x42, 1, 0x05, 0x20, 0, 0x20, 0, 0x42, 1, 0x7d, 0x10, 0, 0x7e, 0x0b, 0x0b),
+			// sum(n) adds n down to 1 in a loop
+			body(vec([]byte{1, I32}),
+				0x02, 0x40, 0x03, 0x40,
+				0x20, 0, 0x45, 0x0d, 1,
+				0x20, 1, 0x20, 0, 0x6a, 0x21, 1,
+				0x20, 0, 0x41, 1, 0x6b, 0x21, 0,
+				0x0c, 0, 0x0b, 0x0b,
+				0x20, 1, 0x0b),
+			// dispatch(i) calls table element i
+			body(vec(), 0x20, 0, 0x11, 2, 0, 0x0b),
+			body(vec(), 0x41, 10, 0x0b),
+			body(vec(), 0x41, 20, 0x0b),
+		),
+	)
+	inst := instantiate(t, wasm, Config{})
+
+	if got := call(t, inst, "fac", 20); got != 2432902008176640000 {
+		t.Errorf("fac(20) = %d", got)
+	}
+	if got := call(t, inst, "sum", 100); got != 5050 {
+		t.Errorf("sum(100) = %d", got)
+	}
+	if got := call(t, inst, "dispatch", 1); got != 20 {
+		t.Errorf("dispatch(1) = %d", got)
+	}
+	var trap *Trap
+	if _, err := inst.Call(context.Background(), "dispatch", 2); !errors.As(err, &trap) || trap.Message != "undefined element" {
+		t.Errorf("dispatch(2) error = %v", err)
+	}
+}
+
+func TestTraps(t *testing.T) {
+	wasm := module(
+		section(1, []byte{0x60, 2, I32, I32, 1, I32}, []byte{0x60, 1, I32, 1, I32}),
+		section(3, uleb(0), uleb(1)),
+		section(5, []byte{0, 1}),
+		section(7, concat(name("div"), []byte{ExternFunc, 0}), concat(name("load"), []byte{ExternFunc, 1})),
+		section(10,
+			body(vec(), 0x20, 0, 0x20, 1, 0x6d, 0x0b),
+			body(vec(), 0x20, 0, 0x28, 2, 0, 0x0b),
+		),
+	)
+	inst := instantiate(t, wasm, Config{})
+
+	if got := call(t, inst, "div", uint64(uint32(math.MaxUint32-6)), 2); int32(got) != -3 {
+		t.Errorf("div(-7, 2) = %d", int32(got))
+	}
+	if got := call(t, inst, "load", PageSize-4); got != 0 {
+		t.Errorf("load = %d", got)
+	}
+	cases := []struct {
+		fn   string
+		args []uint64
+		want string
+	}{
+		{"div", []uint64{1, 0}, "integer divide by zero"},
+		{"div", []uint64{1 << 31, math.MaxUint32}, "integer overflow"},
+		{"load", []uint64{PageSize - 3}, "out of bounds memory access"},
+	}
+	for _, tc := range cases {
+		_, err := inst.Call(context.Background(), tc.fn, tc.args...)
+		var trap *Trap
+		if !errors.As(err, &trap) || trap.Message != tc.want {
+			t.Errorf("%s%v error = %v, want %s", tc.fn, tc.args, err, tc.want)
+		}
+	}
+}
+
+func TestHostFunctionsAndMemory(t *testing.T) {
+	wasm := module(
+		section(1, []byte{0x60, 2, I32, I32, 0}, []byte{0x60, 0, 1, I32}),
+		section(2, concat(name("env"), name("log"), []byte{ExternFunc, 0})),
+		section(3, uleb(1)),
+		section(5, []byte{1, 1, 2}),
+		section(7, concat(name("run"), []byte{ExternFunc, 1})),
+		section(10,
+			// log(16, 5), then try to grow memory past its maximum
+			body(vec(), 0x41, 16, 0x41, 5, 0x10, 0, 0x41, 3, 0x40, 0, 0x0b),
+		),
+		section(11, concat([]byte{0, opI32Const, 16, opEnd}, name("hello"))),
+	)
+	var logged string
+	inst := instantiate(t, wasm, Config{Imports: map[string]HostFunc{
+		"env.log": {
+			Type: FuncType{Params: []byte{I32, I32}},
+			Fn: func(_ context.Context, inst *Instance, args []uint64) ([]uint64, error) {
+				b, ok := inst.Read(uint32(args[0]), uint32(args[1]))
+				if !ok {
+					return nil, errors.New("out of bounds")
+				}
+				logged = string(b)
+				return nil, nil
+			},
+		},
+	}})
+
+	if got := call(t, inst, "run"); uint32(got) != math.MaxUint32 {
+		t.Errorf("memory.grow past the maximum = %d, want -1", int32(got))
+	}
+	if logged != "hello" {
+		t.Errorf("logged %q", logged)
+	}
+
+	m, err := Decode(wasm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Instantiate(context.Background(), m, Config{}); err == nil {
+		t.Error("expected an error for a missing import")
+	}
+}
+
+func TestContextStopsExecution(t *testing.T) {
+	wasm := module(
+		section(1, []byte{0x60, 0, 0}),
+		section(3, uleb(0)),
+		section(7, concat(name("spin"), []byte{ExternFunc, 0})),
+		section(10, body(vec(), 0x03, 0x40, 0x0c, 0, 0x0b, 0x0b)),
+	)
+	inst := instantiate(t, wasm, Config{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := inst.Call(ctx, "spin"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestDecodeRejectsInvalidModules(t *testing.T) {
+	for _, wasm := range [][]byte{
+		[]byte("not wasm"),
+		module(section(1, []byte{0x60, 0, 0}), section(3, uleb(0))),
+		module(section(1, []byte{0x60, 0, 0}), section(3, uleb(0)), section(10, body(vec(), 0x20, 0, 0x0b))),
+		module(section(1, []byte{0x60, 0, 0}), section(3, uleb(0)), section(10, body(vec(), 0x08, 0x0b))),
+	} {
+		if _, err := Decode(wasm); err == nil {
+			t.Errorf("expected %x to be rejected", wasm)
+		}
+	}
+}
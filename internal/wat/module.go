@@ -0,0 +1,577 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package wat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Export kinds.
+const (
+	ExportFunc   byte = 0
+	ExportTable  byte = 1
+	ExportMemory byte = 2
+	ExportGlobal byte = 3
+)
+
+// FuncType is a function signature from the type section.
+type FuncType struct {
+	Params  []string
+	Results []string
+}
+
+// String renders the signature as WAT param and result clauses.
+func (t FuncType) String() string {
+	var parts []string
+	if len(t.Params) > 0 {
+		parts = append(parts, "(param "+strings.Join(t.Params, " ")+")")
+	}
+	if len(t.Results) > 0 {
+		parts = append(parts, "(result "+strings.Join(t.Results, " ")+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// Import is one entry of the import section.
+type Import struct {
+	Module string
+	Name   string
+	Kind   byte
+	// TypeIndex is set for function imports.
+	TypeIndex uint32
+}
+
+// Export is one entry of the export section.
+type Export struct {
+	Name  string
+	Kind  byte
+	Index uint32
+}
+
+// Function is a function defined in the module's code section.
+type Function struct {
+	// Index is the function index, counting imported functions first.
+	Index uint32
+	// Name comes from the export section or, failing that, the name section.
+	Name   string
+	Type   FuncType
+	Locals []string
+	// Offset is the module offset of the function body.
+	Offset uint64
+	// Size is the length of the function body in bytes.
+	Size int
+
+	codeStart, codeEnd int
+}
+
+// Module is the structure of a WASM module: its signatures, imports,
+// exports and function bodies.
+type Module struct {
+	Types     []FuncType
+	Imports   []Import
+	Exports   []Export
+	Functions []Function
+
+	data      []byte
+	funcNames map[uint32]string
+}
+
+// ParseModule decodes the sections of a WASM module needed to list its
+// exports and disassemble individual functions.
+func ParseModule(wasmBytes []byte) (*Module, error) {
+	if !NewDisassembler(wasmBytes).IsValidWasm() {
+		return nil, fmt.Errorf("not a valid WASM module")
+	}
+
+	m := &Module{data: wasmBytes, funcNames: make(map[uint32]string)}
+	var funcTypes []uint32
+	var bodies []Function
+	names := map[uint32]string{}
+
+	r := &reader{data: wasmBytes, pos: 8}
+	for r.pos < len(r.data) {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		end := r.pos + int(size)
+		if end > len(r.data) {
+			return nil, fmt.Errorf("section %d at offset 0x%x overruns module", id, r.pos)
+		}
+		section := &reader{data: r.data[:end], pos: r.pos}
+
+		switch id {
+		case SectionType:
+			m.Types, err = readTypes(section)
+		case SectionImport:
+			m.Imports, err = readImports(section)
+		case SectionFunction:
+			funcTypes, err = readU32Vec(section)
+		case SectionExport:
+			m.Exports, err = readExports(section)
+		case SectionCode:
+			bodies, err = readBodies(section)
+		case SectionCustom:
+			// The name section is optional; a malformed one is ignored
+			if name, nerr := section.name(); nerr == nil && name == "name" {
+				names = readFunctionNames(section)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode section %d: %w", id, err)
+		}
+		r.pos = end
+	}
+
+	if len(bodies) != len(funcTypes) {
+		return nil, fmt.Errorf("function section declares %d functions but code section has %d bodies", len(funcTypes), len(bodies))
+	}
+
+	for idx, name := range names {
+		m.funcNames[idx] = name
+	}
+	for _, exp := range m.Exports {
+		if exp.Kind == ExportFunc {
+			m.funcNames[exp.Index] = exp.Name
+		}
+	}
+
+	imported := uint32(0)
+	for i, imp := range m.Imports {
+		if imp.Kind != ExportFunc {
+			continue
+		}
+		if _, ok := m.funcNames[imported]; !ok {
+			m.funcNames[imported] = m.Imports[i].Module + "." + m.Imports[i].Name
+		}
+		imported++
+	}
+
+	for i, body := range bodies {
+		body.Index = imported + uint32(i)
+		body.Name = m.funcNames[body.Index]
+		if int(funcTypes[i]) < len(m.Types) {
+			body.Type = m.Types[funcTypes[i]]
+		}
+		m.Functions = append(m.Functions, body)
+	}
+	return m, nil
+}
+
+// ExportedFunctions returns the function exports in declaration order.
+func (m *Module) ExportedFunctions() []Export {
+	var out []Export
+	for _, exp := range m.Exports {
+		if exp.Kind == ExportFunc {
+			out = append(out, exp)
+		}
+	}
+	return out
+}
+
+// Function looks up a defined function by its exported or debug name.
+func (m *Module) Function(name string) (*Function, bool) {
+	for i := range m.Functions {
+		if m.Functions[i].Name == name {
+			return &m.Functions[i], true
+		}
+	}
+	return nil, false
+}
+
+// FunctionAt returns the defined function whose body contains offset.
+func (m *Module) FunctionAt(offset uint64) (*Function, bool) {
+	for i := range m.Functions {
+		f := &m.Functions[i]
+		if offset >= f.Offset && offset < f.Offset+uint64(f.Size) {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// Instructions decodes the body of f.
+func (m *Module) Instructions(f *Function) []Instruction {
+	var instructions []Instruction
+	pos := f.codeStart
+	for pos < f.codeEnd {
+		opcode := m.data[pos]
+		mnemonic, operands, consumed := decodeOpcode(opcode, m.data[pos+1:f.codeEnd])
+		if opcode == 0x10 {
+			if idx, _ := decodeULEB128(m.data[pos+1 : f.codeEnd]); m.funcNames[uint32(idx)] != "" {
+				operands = "$" + m.funcNames[uint32(idx)]
+			}
+		}
+		instructions = append(instructions, Instruction{
+			Offset:   uint64(pos),
+			Opcode:   opcode,
+			Mnemonic: mnemonic,
+			Operands: operands,
+			Size:     1 + consumed,
+		})
+		pos += 1 + consumed
+	}
+	return instructions
+}
+
+// FormatFunction renders f as a WAT function, indenting nested blocks and
+// prefixing each instruction with its module offset.
+func (m *Module) FormatFunction(f *Function) string {
+	var b strings.Builder
+	header := fmt.Sprintf("(func $%s (;%d;)", funcLabel(f), f.Index)
+	if sig := f.Type.String(); sig != "" {
+		header += " " + sig
+	}
+	b.WriteString(header + "\n")
+	if len(f.Locals) > 0 {
+		fmt.Fprintf(&b, "  (local %s)\n", strings.Join(f.Locals, " "))
+	}
+
+	depth := 1
+	for _, inst := range m.Instructions(f) {
+		indent := depth
+		switch inst.Mnemonic {
+		case "block", "loop", "if":
+			depth++
+		case "else":
+			indent--
+		case "end":
+			depth--
+			indent = depth
+		}
+		if indent < 1 {
+			indent = 1
+		}
+		// The final end closes the function itself
+		if depth == 0 {
+			break
+		}
+		fmt.Fprintf(&b, "%s0x%04x: %s\n", strings.Repeat("  ", indent), inst.Offset, inst.String())
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
+
+// Format renders every defined function in the module.
+func (m *Module) Format() string {
+	var b strings.Builder
+	for i := range m.Functions {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(m.FormatFunction(&m.Functions[i]))
+	}
+	return b.String()
+}
+
+func funcLabel(f *Function) string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return fmt.Sprintf("func%d", f.Index)
+}
+
+// =============================================================================
+// Section decoding
+// =============================================================================
+
+// reader is a bounds-checked cursor over module bytes.
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, fmt.Errorf("unexpected end of data at offset 0x%x", r.pos)
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) u32() (uint32, error) {
+	var result uint32
+	var shift uint
+	for i := 0; i < 5; i++ {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		result |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return result, nil
+		}
+		shift += 7
+	}
+	return 0, fmt.Errorf("LEB128 value too long at offset 0x%x", r.pos)
+}
+
+// count reads the length of a vector. Every element takes at least one
+// byte, so a length beyond the bytes left is rejected before anything is
+// allocated for it.
+func (r *reader) count() (uint32, error) {
+	n, err := r.u32()
+	if err != nil {
+		return 0, err
+	}
+	if int64(n) > int64(len(r.data)-r.pos) {
+		return 0, fmt.Errorf("vector of %d elements at offset 0x%x overruns section", n, r.pos)
+	}
+	return n, nil
+}
+
+func (r *reader) name() (string, error) {
+	n, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	if r.pos+int(n) > len(r.data) {
+		return "", fmt.Errorf("name at offset 0x%x overruns section", r.pos)
+	}
+	s := string(r.data[r.pos : r.pos+int(n)])
+	r.pos += int(n)
+	return s, nil
+}
+
+func (r *reader) limits() error {
+	flags, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if _, err := r.u32(); err != nil {
+		return err
+	}
+	if flags&0x01 != 0 {
+		_, err = r.u32()
+	}
+	return err
+}
+
+func valueTypeName(b byte) string {
+	switch b {
+	case 0x7f:
+		return "i32"
+	case 0x7e:
+		return "i64"
+	case 0x7d:
+		return "f32"
+	case 0x7c:
+		return "f64"
+	case 0x7b:
+		return "v128"
+	case 0x70:
+		return "funcref"
+	case 0x6f:
+		return "externref"
+	default:
+		return fmt.Sprintf("type_0x%02x", b)
+	}
+}
+
+func readValueTypes(r *reader) ([]string, error) {
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	var types []string
+	for i := uint32(0); i < n; i++ {
+		b, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, valueTypeName(b))
+	}
+	return types, nil
+}
+
+func readTypes(r *reader) ([]FuncType, error) {
+	n, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	types := make([]FuncType, 0, n)
+	for i := uint32(0); i < n; i++ {
+		form, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if form != 0x60 {
+			return nil, fmt.Errorf("unsupported type form 0x%02x", form)
+		}
+		var t FuncType
+		if t.Params, err = readValueTypes(r); err != nil {
+			return nil, err
+		}
+		if t.Results, err = readValueTypes(r); err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+func readImports(r *reader) ([]Import, error) {
+	n, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	imports := make([]Import, 0, n)
+	for i := uint32(0); i < n; i++ {
+		var imp Import
+		if imp.Module, err = r.name(); err != nil {
+			return nil, err
+		}
+		if imp.Name, err = r.name(); err != nil {
+			return nil, err
+		}
+		if imp.Kind, err = r.byte(); err != nil {
+			return nil, err
+		}
+		switch imp.Kind {
+		case ExportFunc:
+			imp.TypeIndex, err = r.u32()
+		case ExportTable:
+			if _, err = r.byte(); err == nil {
+				err = r.limits()
+			}
+		case ExportMemory:
+			err = r.limits()
+		case ExportGlobal:
+			if _, err = r.byte(); err == nil {
+				_, err = r.byte()
+			}
+		default:
+			err = fmt.Errorf("unknown import kind 0x%02x", imp.Kind)
+		}
+		if err != nil {
+			return nil, err
+		}
+		imports = append(imports, imp)
+	}
+	return imports, nil
+}
+
+func readU32Vec(r *reader) ([]uint32, error) {
+	n, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]uint32, 0, n)
+	for i := uint32(0); i < n; i++ {
+		v, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func readExports(r *reader) ([]Export, error) {
+	n, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	exports := make([]Export, 0, n)
+	for i := uint32(0); i < n; i++ {
+		var exp Export
+		if exp.Name, err = r.name(); err != nil {
+			return nil, err
+		}
+		if exp.Kind, err = r.byte(); err != nil {
+			return nil, err
+		}
+		if exp.Index, err = r.u32(); err != nil {
+			return nil, err
+		}
+		exports = append(exports, exp)
+	}
+	return exports, nil
+}
+
+func readBodies(r *reader) ([]Function, error) {
+	n, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+	bodies := make([]Function, 0, n)
+	for i := uint32(0); i < n; i++ {
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		start := r.pos
+		end := start + int(size)
+		if end > len(r.data) {
+			return nil, fmt.Errorf("body %d at offset 0x%x overruns section", i, start)
+		}
+		body := &reader{data: r.data[:end], pos: start}
+
+		f := Function{Offset: uint64(start), Size: int(size), codeEnd: end}
+		groups, err := body.u32()
+		if err != nil {
+			return nil, err
+		}
+		for g := uint32(0); g < groups; g++ {
+			count, err := body.u32()
+			if err != nil {
+				return nil, err
+			}
+			t, err := body.byte()
+			if err != nil {
+				return nil, err
+			}
+			for c := uint32(0); c < count && c < 1024; c++ {
+				f.Locals = append(f.Locals, valueTypeName(t))
+			}
+		}
+		f.codeStart = body.pos
+		bodies = append(bodies, f)
+		r.pos = end
+	}
+	return bodies, nil
+}
+
+// readFunctionNames decodes the function-names subsection of the "name"
+// custom section emitted for unstripped builds.
+func readFunctionNames(r *reader) map[uint32]string {
+	names := map[uint32]string{}
+	for r.pos < len(r.data) {
+		id, err := r.byte()
+		if err != nil {
+			return names
+		}
+		size, err := r.u32()
+		if err != nil || r.pos+int(size) > len(r.data) {
+			return names
+		}
+		end := r.pos + int(size)
+		if id == 1 {
+			sub := &reader{data: r.data[:end], pos: r.pos}
+			n, err := sub.u32()
+			if err != nil {
+				return names
+			}
+			for i := uint32(0); i < n; i++ {
+				idx, err := sub.u32()
+				if err != nil {
+					return names
+				}
+				name, err := sub.name()
+				if err != nil {
+					return names
+				}
+				names[idx] = name
+			}
+		}
+		r.pos = end
+	}
+	return names
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package wat
+
+import (
+	"strings"
+	"testing"
+)
+
+func wasmSection(id byte, payload []byte) []byte {
+	out := append([]byte{id}, encodeULEB128(uint64(len(payload)))...)
+	return append(out, payload...)
+}
+
+func wasmName(s string) []byte {
+	return append(encodeULEB128(uint64(len(s))), s...)
+}
+
+// buildContractWasm builds a module importing one host function and
+// defining two: an unexported helper and an exported "transfer" that
+// branches and calls both.
+func buildContractWasm() []byte {
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+	// Types: 0 = (i64) -> (i64), 1 = () -> ()
+	module = append(module, wasmSection(SectionType, []byte{
+		0x02,
+		0x60, 0x01, 0x7e, 0x01, 0x7e,
+		0x60, 0x00, 0x00,
+	})...)
+
+	imports := []byte{0x02}
+	imports = append(imports, wasmName("x")...)
+	imports = append(imports, wasmName("_")...)
+	imports = append(imports, ExportFunc, 0x00)
+	imports = append(imports, wasmName("env")...)
+	imports = append(imports, wasmName("memory")...)
+	imports = append(imports, ExportMemory, 0x00, 0x10)
+	module = append(module, wasmSection(SectionImport, imports)...)
+
+	module = append(module, wasmSection(SectionFunction, []byte{0x02, 0x01, 0x00})...)
+
+	exports := []byte{0x02}
+	exports = append(exports, wasmName("transfer")...)
+	exports = append(exports, ExportFunc, 0x02)
+	exports = append(exports, wasmName("memory")...)
+	exports = append(exports, ExportMemory, 0x00)
+	module = append(module, wasmSection(SectionExport, exports)...)
+
+	helper := []byte{0x00, 0x01, 0x0b} // no locals; nop; end
+	transfer := []byte{
+		0x01, 0x01, 0x7f, // one i32 local
+		0x02, 0x40, // block
+		0x41, 0x01, // i32.const 1
+		0x0d, 0x00, // br_if 0
+		0x10, 0x01, // call $func1
+		0x0b,       // end
+		0x20, 0x00, // local.get 0
+		0x10, 0x00, // call x._
+		0x00, // unreachable
+		0x0b, // end
+	}
+	code := []byte{0x02}
+	code = append(code, encodeULEB128(uint64(len(helper)))...)
+	code = append(code, helper...)
+	code = append(code, encodeULEB128(uint64(len(transfer)))...)
+	code = append(code, transfer...)
+	module = append(module, wasmSection(SectionCode, code)...)
+
+	return module
+}
+
+func TestParseModule(t *testing.T) {
+	m, err := ParseModule(buildContractWasm())
+	if err != nil {
+		t.Fatalf("ParseModule: %v", err)
+	}
+
+	if len(m.Imports) != 2 || m.Imports[0].Module != "x" || m.Imports[1].Kind != ExportMemory {
+		t.Errorf("unexpected imports: %+v", m.Imports)
+	}
+	exports := m.ExportedFunctions()
+	if len(exports) != 1 || exports[0].Name != "transfer" || exports[0].Index != 2 {
+		t.Errorf("unexpected exported functions: %+v", exports)
+	}
+	if len(m.Functions) != 2 {
+		t.Fatalf("expected 2 defined functions, got %d", len(m.Functions))
+	}
+	if m.Functions[0].Index != 1 || m.Functions[0].Name != "" {
+		t.Errorf("helper should be unnamed function 1, got %+v", m.Functions[0])
+	}
+
+	f, ok := m.Function("transfer")
+	if !ok {
+		t.Fatal("transfer not found")
+	}
+	if f.Type.String() != "(param i64) (result i64)" {
+		t.Errorf("unexpected signature %q", f.Type.String())
+	}
+	if len(f.Locals) != 1 || f.Locals[0] != "i32" {
+		t.Errorf("unexpected locals %v", f.Locals)
+	}
+
+	insts := m.Instructions(f)
+	if len(insts) != 9 {
+		t.Fatalf("expected 9 instructions, got %d", len(insts))
+	}
+	if insts[0].Mnemonic != "block" || insts[7].Mnemonic != "unreachable" {
+		t.Errorf("unexpected instructions: %v, %v", insts[0].String(), insts[7].String())
+	}
+	if insts[6].String() != "call $x._" {
+		t.Errorf("import call should carry its name, got %q", insts[6].String())
+	}
+
+	at, ok := m.FunctionAt(insts[7].Offset)
+	if !ok || at.Name != "transfer" {
+		t.Errorf("FunctionAt should resolve the trap offset to transfer")
+	}
+}
+
+func TestModule_FormatFunction(t *testing.T) {
+	m, err := ParseModule(buildContractWasm())
+	if err != nil {
+		t.Fatalf("ParseModule: %v", err)
+	}
+	f, _ := m.Function("transfer")
+	out := m.FormatFunction(f)
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+
+	if lines[0] != "(func $transfer (;2;) (param i64) (result i64)" {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if lines[1] != "  (local i32)" {
+		t.Errorf("unexpected locals line %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[3], "    0x") || !strings.HasSuffix(lines[3], "i32.const 1") {
+		t.Errorf("block body should be indented, got %q", lines[3])
+	}
+	if !strings.HasSuffix(lines[5], "call $func1") {
+		t.Errorf("unnamed callee should keep its index, got %q", lines[5])
+	}
+	if lines[len(lines)-1] != ")" || strings.Contains(lines[len(lines)-2], "end") {
+		t.Errorf("function end should close the form:\n%s", out)
+	}
+
+	whole := m.Format()
+	if !strings.Contains(whole, "(func $func1 (;1;)") || !strings.Contains(whole, "(func $transfer") {
+		t.Errorf("Format should render every function:\n%s", whole)
+	}
+}
+
+func TestParseModule_NameSection(t *testing.T) {
+	names := []byte{0x01}
+	names = append(names, encodeULEB128(1)...)
+	names = append(names, wasmName("helper")...)
+	payload := wasmName("name")
+	payload = append(payload, 0x01)
+	payload = append(payload, encodeULEB128(uint64(len(names)))...)
+	payload = append(payload, names...)
+	wasm := append(buildContractWasm(), wasmSection(SectionCustom, payload)...)
+
+	m, err := ParseModule(wasm)
+	if err != nil {
+		t.Fatalf("ParseModule: %v", err)
+	}
+	if _, ok := m.Function("helper"); !ok {
+		t.Error("expected the name section to name function 1")
+	}
+}
+
+func TestParseModule_Invalid(t *testing.T) {
+	if _, err := ParseModule([]byte("not wasm")); err == nil {
+		t.Error("expected error for non-WASM input")
+	}
+
+	wasm := buildContractWasm()
+	if _, err := ParseModule(wasm[:len(wasm)-3]); err == nil {
+		t.Error("expected error for truncated module")
+	}
+}
+
+func TestParseModule_HugeVectorCount(t *testing.T) {
+	header := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	// A count of 2^32-1 in a section holding a single element
+	huge := []byte{0xff, 0xff, 0xff, 0xff, 0x0f, 0x00}
+
+	for _, id := range []byte{SectionType, SectionImport, SectionFunction, SectionExport, SectionCode} {
+		module := append(append([]byte{}, header...), wasmSection(id, huge)...)
+		if _, err := ParseModule(module); err == nil || !strings.Contains(err.Error(), "overruns section") {
+			t.Errorf("section %d: expected an overrun error, got %v", id, err)
+		}
+	}
+}
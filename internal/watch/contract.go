@@ -0,0 +1,139 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// TransactionSource pages through ledger transactions. *rpc.Client
+// satisfies it.
+type TransactionSource interface {
+	GetTransactions(ctx context.Context, startLedger uint32, cursor string, limit int) (*rpc.GetTransactionsResponse, error)
+}
+
+type ContractWatcherConfig struct {
+	ContractID   xdr.ContractId
+	StartLedger  uint32
+	PollInterval time.Duration
+	PageSize     int
+}
+
+// ContractWatcher follows the ledger and reports every failed transaction
+// that invokes a contract or touches its storage.
+type ContractWatcher struct {
+	source TransactionSource
+	config ContractWatcherConfig
+	cursor string
+}
+
+func NewContractWatcher(source TransactionSource, config ContractWatcherConfig) *ContractWatcher {
+	if config.PollInterval == 0 {
+		config.PollInterval = 5 * time.Second
+	}
+	if config.PageSize == 0 {
+		config.PageSize = 100
+	}
+	return &ContractWatcher{source: source, config: config}
+}
+
+// Run polls until ctx is cancelled, calling onFailed for each matching
+// transaction in ledger order. RPC errors are logged and retried on the next
+// tick; an error returned by onFailed stops the watcher.
+func (w *ContractWatcher) Run(ctx context.Context, onFailed func(tx rpc.LedgerTransaction) error) error {
+	for {
+		caughtUp, err := w.poll(ctx, onFailed)
+		if err != nil {
+			return err
+		}
+
+		if !caughtUp {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(w.config.PollInterval):
+		}
+	}
+}
+
+// poll fetches one page and reports whether the watcher has reached the
+// ledger tip
+func (w *ContractWatcher) poll(ctx context.Context, onFailed func(tx rpc.LedgerTransaction) error) (bool, error) {
+	resp, err := w.source.GetTransactions(ctx, w.config.StartLedger, w.cursor, w.config.PageSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Logger.Warn("Failed to fetch transactions, retrying", "error", err)
+		}
+		return true, nil
+	}
+
+	for _, tx := range resp.Result.Transactions {
+		if tx.Status != rpc.TxStatusFailed {
+			continue
+		}
+		touches, err := TouchesContract(tx.EnvelopeXdr, w.config.ContractID)
+		if err != nil {
+			logger.Logger.Warn("Skipping undecodable transaction", "tx", tx.TxHash, "error", err)
+			continue
+		}
+		if !touches {
+			continue
+		}
+		if err := onFailed(tx); err != nil {
+			return true, err
+		}
+	}
+
+	if resp.Result.Cursor != "" {
+		w.cursor = resp.Result.Cursor
+	}
+	return len(resp.Result.Transactions) < w.config.PageSize, nil
+}
+
+// TouchesContract reports whether a transaction envelope invokes the given
+// contract or declares any of its storage in the Soroban footprint
+func TouchesContract(envelopeXdr string, contractID xdr.ContractId) (bool, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+		return false, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+
+	for _, op := range envelope.Operations() {
+		hostFn := op.Body.InvokeHostFunctionOp
+		if hostFn == nil || hostFn.HostFunction.Type != xdr.HostFunctionTypeHostFunctionTypeInvokeContract {
+			continue
+		}
+		if args := hostFn.HostFunction.InvokeContract; args != nil && isContract(args.ContractAddress, contractID) {
+			return true, nil
+		}
+	}
+
+	sorobanData := rpc.SorobanDataFromEnvelope(envelope)
+	if sorobanData == nil {
+		return false, nil
+	}
+	footprint := sorobanData.Resources.Footprint
+	for _, keys := range [][]xdr.LedgerKey{footprint.ReadOnly, footprint.ReadWrite} {
+		for _, key := range keys {
+			if key.ContractData != nil && isContract(key.ContractData.Contract, contractID) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func isContract(address xdr.ScAddress, contractID xdr.ContractId) bool {
+	return address.Type == xdr.ScAddressTypeScAddressTypeContract &&
+		address.ContractId != nil && *address.ContractId == contractID
+}
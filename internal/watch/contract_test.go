@@ -0,0 +1,165 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func invokeEnvelope(t *testing.T, target xdr.ContractId, footprint ...xdr.ContractId) string {
+	t.Helper()
+
+	var readOnly []xdr.LedgerKey
+	for _, id := range footprint {
+		cid := id
+		readOnly = append(readOnly, xdr.LedgerKey{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.LedgerKeyContractData{
+				Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &cid},
+				Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+				Durability: xdr.ContractDataDurabilityPersistent,
+			},
+		})
+	}
+
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress("GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"),
+				Operations: []xdr.Operation{{
+					Body: xdr.OperationBody{
+						Type: xdr.OperationTypeInvokeHostFunction,
+						InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+							HostFunction: xdr.HostFunction{
+								Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+								InvokeContract: &xdr.InvokeContractArgs{
+									ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &target},
+									FunctionName:    "transfer",
+								},
+							},
+						},
+					},
+				}},
+				Ext: xdr.TransactionExt{
+					V: 1,
+					SorobanData: &xdr.SorobanTransactionData{
+						Resources: xdr.SorobanResources{
+							Footprint: xdr.LedgerFootprint{ReadOnly: readOnly},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b64, err := xdr.MarshalBase64(envelope)
+	if err != nil {
+		t.Fatalf("failed to marshal envelope: %v", err)
+	}
+	return b64
+}
+
+func TestTouchesContract(t *testing.T) {
+	watched := xdr.ContractId{1}
+	other := xdr.ContractId{2}
+
+	tests := []struct {
+		name     string
+		envelope string
+		want     bool
+	}{
+		{"direct invocation", invokeEnvelope(t, watched), true},
+		{"storage in footprint", invokeEnvelope(t, other, watched), true},
+		{"unrelated contract", invokeEnvelope(t, other, other), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TouchesContract(tt.envelope, watched)
+			if err != nil {
+				t.Fatalf("TouchesContract() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("TouchesContract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := TouchesContract("not-xdr", watched); err == nil {
+		t.Error("expected error for invalid envelope")
+	}
+}
+
+type fakeSource struct {
+	pages   []*rpc.GetTransactionsResponse
+	cursors []string
+}
+
+func (f *fakeSource) GetTransactions(ctx context.Context, startLedger uint32, cursor string, limit int) (*rpc.GetTransactionsResponse, error) {
+	f.cursors = append(f.cursors, cursor)
+	if len(f.pages) == 0 {
+		return &rpc.GetTransactionsResponse{}, nil
+	}
+	page := f.pages[0]
+	f.pages = f.pages[1:]
+	return page, nil
+}
+
+func page(cursor string, txs ...rpc.LedgerTransaction) *rpc.GetTransactionsResponse {
+	resp := &rpc.GetTransactionsResponse{}
+	resp.Result.Transactions = txs
+	resp.Result.Cursor = cursor
+	return resp
+}
+
+func TestContractWatcherReportsMatchingFailures(t *testing.T) {
+	watched := xdr.ContractId{1}
+	other := xdr.ContractId{2}
+
+	source := &fakeSource{pages: []*rpc.GetTransactionsResponse{
+		page("c1",
+			rpc.LedgerTransaction{TxHash: "ok", Status: rpc.TxStatusSuccess, EnvelopeXdr: invokeEnvelope(t, watched)},
+			rpc.LedgerTransaction{TxHash: "hit1", Status: rpc.TxStatusFailed, EnvelopeXdr: invokeEnvelope(t, watched)},
+		),
+		page("c2",
+			rpc.LedgerTransaction{TxHash: "miss", Status: rpc.TxStatusFailed, EnvelopeXdr: invokeEnvelope(t, other)},
+			rpc.LedgerTransaction{TxHash: "hit2", Status: rpc.TxStatusFailed, EnvelopeXdr: invokeEnvelope(t, other, watched)},
+		),
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watcher := NewContractWatcher(source, ContractWatcherConfig{
+		ContractID:   watched,
+		StartLedger:  100,
+		PollInterval: time.Millisecond,
+		PageSize:     10,
+	})
+
+	var seen []string
+	err := watcher.Run(ctx, func(tx rpc.LedgerTransaction) error {
+		seen = append(seen, tx.TxHash)
+		if len(seen) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(seen) != 2 || seen[0] != "hit1" || seen[1] != "hit2" {
+		t.Errorf("expected [hit1 hit2], got %v", seen)
+	}
+	if len(source.cursors) < 2 || source.cursors[0] != "" || source.cursors[1] != "c1" {
+		t.Errorf("expected watcher to page with returned cursors, got %v", source.cursors)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+)
+
+// EventSource pages through contract events. *rpc.Client satisfies it.
+type EventSource = rpc.EventPager
+
+type EventFollowerConfig struct {
+	Filters      []rpc.EventFilter
+	StartLedger  uint32
+	PollInterval time.Duration
+	PageSize     int
+	// Follow keeps polling for new events once the ledger tip is reached
+	// instead of returning
+	Follow bool
+}
+
+// EventFollower pages through the events matching a set of filters, and
+// optionally keeps tailing them as new ledgers close.
+type EventFollower struct {
+	source EventSource
+	config EventFollowerConfig
+	cursor string
+}
+
+func NewEventFollower(source EventSource, config EventFollowerConfig) *EventFollower {
+	if config.PollInterval == 0 {
+		config.PollInterval = 5 * time.Second
+	}
+	if config.PageSize == 0 {
+		config.PageSize = 100
+	}
+	return &EventFollower{source: source, config: config}
+}
+
+// Run calls onEvent for each matching event in ledger order until the ledger
+// tip is reached or, when following, until ctx is cancelled. While following,
+// RPC errors are logged and retried on the next tick; otherwise they are
+// returned. An error returned by onEvent stops the follower.
+func (f *EventFollower) Run(ctx context.Context, onEvent func(event rpc.ContractEvent) error) error {
+	query := rpc.EventQuery{StartLedger: f.config.StartLedger, Filters: f.config.Filters, PageSize: f.config.PageSize}
+	for {
+		var handlerErr error
+		query.Cursor = f.cursor
+		cursor, err := rpc.PageEvents(ctx, f.source, query, func(event rpc.ContractEvent) error {
+			handlerErr = onEvent(event)
+			return handlerErr
+		})
+		f.cursor = cursor
+		if handlerErr != nil {
+			return handlerErr
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !f.config.Follow {
+				return err
+			}
+			logger.Logger.Warn("Failed to fetch events, retrying", "error", err)
+		}
+
+		if !f.config.Follow {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(f.config.PollInterval):
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/rpc"
+)
+
+type fakeEventSource struct {
+	pages   []*rpc.GetEventsResponse
+	cursors []string
+}
+
+func (f *fakeEventSource) GetEvents(ctx context.Context, startLedger uint32, cursor string, filters []rpc.EventFilter, limit int) (*rpc.GetEventsResponse, error) {
+	f.cursors = append(f.cursors, cursor)
+	if len(f.pages) == 0 {
+		resp := &rpc.GetEventsResponse{}
+		resp.Result.Cursor = cursor
+		return resp, nil
+	}
+	page := f.pages[0]
+	f.pages = f.pages[1:]
+	return page, nil
+}
+
+func eventPage(cursor string, ids ...string) *rpc.GetEventsResponse {
+	resp := &rpc.GetEventsResponse{}
+	for _, id := range ids {
+		resp.Result.Events = append(resp.Result.Events, rpc.ContractEvent{ID: id})
+	}
+	resp.Result.Cursor = cursor
+	return resp
+}
+
+func TestEventFollowerStopsAtTip(t *testing.T) {
+	source := &fakeEventSource{pages: []*rpc.GetEventsResponse{
+		eventPage("c1", "e1", "e2"),
+		eventPage("c2", "e3"),
+	}}
+
+	follower := NewEventFollower(source, EventFollowerConfig{StartLedger: 100, PageSize: 2})
+
+	var seen []string
+	err := follower.Run(context.Background(), func(event rpc.ContractEvent) error {
+		seen = append(seen, event.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(seen) != 3 || seen[0] != "e1" || seen[2] != "e3" {
+		t.Errorf("expected [e1 e2 e3], got %v", seen)
+	}
+	if len(source.cursors) != 2 || source.cursors[0] != "" || source.cursors[1] != "c1" {
+		t.Errorf("expected follower to page with returned cursors, got %v", source.cursors)
+	}
+}
+
+func TestEventFollowerFollowsNewEvents(t *testing.T) {
+	source := &fakeEventSource{pages: []*rpc.GetEventsResponse{
+		eventPage("c1", "e1"),
+		eventPage("c1"),
+		eventPage("c2", "e2"),
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	follower := NewEventFollower(source, EventFollowerConfig{
+		StartLedger:  100,
+		PollInterval: time.Millisecond,
+		PageSize:     10,
+		Follow:       true,
+	})
+
+	var seen []string
+	err := follower.Run(ctx, func(event rpc.ContractEvent) error {
+		seen = append(seen, event.ID)
+		if len(seen) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(seen) != 2 || seen[1] != "e2" {
+		t.Errorf("expected [e1 e2], got %v", seen)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// FailureKind names why a failed transaction failed from its result and
+// meta: the transaction result code, or the code of its first failed
+// operation. A trapped contract invocation is named by the host error it
+// raised, e.g. "Error(Contract, #3)", when the meta carries diagnostic events.
+func FailureKind(resultXdr, resultMetaXdr string) string {
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXdr, &result); err != nil {
+		return "unknown"
+	}
+	code := result.Result.Code
+	results := result.Result.Results
+	if inner, ok := result.Result.GetInnerResultPair(); ok {
+		// The fee bump only wraps the outcome of the inner transaction
+		code = inner.Result.Result.Code
+		results = inner.Result.Result.Results
+	}
+	if code != xdr.TransactionResultCodeTxFailed {
+		return decoder.DecodeTransactionResultCode(code).Code
+	}
+
+	if results != nil {
+		for _, op := range *results {
+			info, ok := decoder.DecodeOperationResult(op)
+			if ok {
+				continue
+			}
+			if strings.HasSuffix(info.Code, "_trapped") {
+				if hostErr, found := hostError(resultMetaXdr); found {
+					return hostErr
+				}
+			}
+			return info.Code
+		}
+	}
+	return decoder.DecodeTransactionResultCode(code).Code
+}
+
+// hostError returns the error of the first error diagnostic event in the
+// transaction meta
+func hostError(resultMetaXdr string) (string, bool) {
+	if resultMetaXdr == "" {
+		return "", false
+	}
+	var meta xdr.TransactionMeta
+	if err := xdr.SafeUnmarshalBase64(resultMetaXdr, &meta); err != nil {
+		return "", false
+	}
+
+	var events []xdr.DiagnosticEvent
+	switch {
+	case meta.V3 != nil && meta.V3.SorobanMeta != nil:
+		events = meta.V3.SorobanMeta.DiagnosticEvents
+	case meta.V4 != nil:
+		events = meta.V4.DiagnosticEvents
+	}
+	for _, event := range events {
+		if event.Event.Body.V0 == nil {
+			continue
+		}
+		topics := event.Event.Body.V0.Topics
+		if len(topics) < 2 || topics[0].Type != xdr.ScValTypeScvSymbol || topics[0].Sym == nil || *topics[0].Sym != "error" {
+			continue
+		}
+		if topics[1].Type == xdr.ScValTypeScvError {
+			return decoder.FormatScVal(topics[1]), true
+		}
+	}
+	return "", false
+}
+
+// Failure is one failed transaction counted by FailureStats
+type Failure struct {
+	TxHash string    `json:"tx_hash"`
+	Ledger uint32    `json:"ledger"`
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+}
+
+// KindCount is the number of failures of one kind within the window
+type KindCount struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+// FailureSummary is a point-in-time view of the failures recorded by
+// FailureStats
+type FailureSummary struct {
+	Total    int           `json:"total"`
+	Window   time.Duration `json:"window"`
+	InWindow int           `json:"in_window"`
+	// Rates are failures per minute over the last minute, the last five
+	// minutes and the whole window
+	RateLastMinute  float64     `json:"rate_last_minute"`
+	RateLast5Minute float64     `json:"rate_last_5_minutes"`
+	RateWindow      float64     `json:"rate_window"`
+	Kinds           []KindCount `json:"kinds"`
+	Latest          []Failure   `json:"latest"`
+	LastLedger      uint32      `json:"last_ledger,omitempty"`
+}
+
+// FailureStats aggregates failed transactions over a sliding window. It is
+// safe for concurrent use.
+type FailureStats struct {
+	mu       sync.Mutex
+	window   time.Duration
+	failures []Failure
+	total    int
+	ledger   uint32
+}
+
+// NewFailureStats keeps the failures of the last window
+func NewFailureStats(window time.Duration) *FailureStats {
+	if window <= 0 {
+		window = 15 * time.Minute
+	}
+	return &FailureStats{window: window}
+}
+
+// Record counts a failed transaction of the given kind. The failure is
+// timed by the ledger close time, or now when the transaction has none.
+func (s *FailureStats) Record(tx rpc.LedgerTransaction, kind string, now time.Time) {
+	at := now
+	if tx.CreatedAt > 0 {
+		at = time.Unix(tx.CreatedAt, 0)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, Failure{TxHash: tx.TxHash, Ledger: tx.Ledger, Time: at, Kind: kind})
+	s.total++
+	if tx.Ledger > s.ledger {
+		s.ledger = tx.Ledger
+	}
+	s.prune(now)
+}
+
+// prune drops failures older than the window
+func (s *FailureStats) prune(now time.Time) {
+	cutoff := now.Add(-s.window)
+	i := 0
+	for i < len(s.failures) && s.failures[i].Time.Before(cutoff) {
+		i++
+	}
+	s.failures = s.failures[i:]
+}
+
+// Snapshot summarises the failures in the window as of now, listing up to
+// latest of the most recent ones, newest first
+func (s *FailureStats) Snapshot(now time.Time, latest int) FailureSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(now)
+
+	summary := FailureSummary{
+		Total:      s.total,
+		Window:     s.window,
+		InWindow:   len(s.failures),
+		LastLedger: s.ledger,
+		Kinds:      []KindCount{},
+		Latest:     []Failure{},
+	}
+
+	var lastMinute, last5 int
+	counts := make(map[string]int)
+	for _, f := range s.failures {
+		age := now.Sub(f.Time)
+		if age <= time.Minute {
+			lastMinute++
+		}
+		if age <= 5*time.Minute {
+			last5++
+		}
+		counts[f.Kind]++
+	}
+	summary.RateLastMinute = float64(lastMinute)
+	summary.RateLast5Minute = float64(last5) / 5
+	summary.RateWindow = float64(len(s.failures)) / s.window.Minutes()
+
+	for kind, count := range counts {
+		summary.Kinds = append(summary.Kinds, KindCount{Kind: kind, Count: count})
+	}
+	sort.Slice(summary.Kinds, func(i, j int) bool {
+		if summary.Kinds[i].Count != summary.Kinds[j].Count {
+			return summary.Kinds[i].Count > summary.Kinds[j].Count
+		}
+		return summary.Kinds[i].Kind < summary.Kinds[j].Kind
+	})
+
+	for i := len(s.failures) - 1; i >= 0 && len(summary.Latest) < latest; i-- {
+		summary.Latest = append(summary.Latest, s.failures[i])
+	}
+	return summary
+}
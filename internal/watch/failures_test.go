@@ -0,0 +1,126 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package watch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func failedResult(t *testing.T, code xdr.TransactionResultCode, ops ...xdr.OperationResult) string {
+	t.Helper()
+	result := xdr.TransactionResult{Result: xdr.TransactionResultResult{Code: code}}
+	if code == xdr.TransactionResultCodeTxFailed {
+		result.Result.Results = &ops
+	}
+	encoded, err := xdr.MarshalBase64(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+func trappedOp() xdr.OperationResult {
+	return xdr.OperationResult{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type: xdr.OperationTypeInvokeHostFunction,
+			InvokeHostFunctionResult: &xdr.InvokeHostFunctionResult{
+				Code: xdr.InvokeHostFunctionResultCodeInvokeHostFunctionTrapped,
+			},
+		},
+	}
+}
+
+func errorMeta(t *testing.T, code uint32) string {
+	t.Helper()
+	sym := xdr.ScSymbol("error")
+	contractCode := xdr.Uint32(code)
+	meta := xdr.TransactionMeta{
+		V: 3,
+		V3: &xdr.TransactionMetaV3{
+			SorobanMeta: &xdr.SorobanTransactionMeta{
+				ReturnValue: xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+				DiagnosticEvents: []xdr.DiagnosticEvent{{
+					Event: xdr.ContractEvent{
+						Type: xdr.ContractEventTypeDiagnostic,
+						Body: xdr.ContractEventBody{V0: &xdr.ContractEventV0{
+							Topics: []xdr.ScVal{
+								{Type: xdr.ScValTypeScvSymbol, Sym: &sym},
+								{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &contractCode}},
+							},
+							Data: xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+						}},
+					},
+				}},
+			},
+		},
+	}
+	encoded, err := xdr.MarshalBase64(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+func TestFailureKind(t *testing.T) {
+	tests := []struct {
+		name   string
+		result string
+		meta   string
+		want   string
+	}{
+		{"transaction code", failedResult(t, xdr.TransactionResultCodeTxBadSeq), "", "tx_bad_seq"},
+		{"trapped without meta", failedResult(t, xdr.TransactionResultCodeTxFailed, trappedOp()), "", "invoke_host_function_trapped"},
+		{"trapped with host error", failedResult(t, xdr.TransactionResultCodeTxFailed, trappedOp()), errorMeta(t, 3), "Error(Contract, #3)"},
+		{"undecodable", "not-xdr", "", "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FailureKind(tt.result, tt.meta); got != tt.want {
+				t.Errorf("FailureKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailureStats(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	stats := NewFailureStats(10 * time.Minute)
+
+	record := func(hash string, ledger uint32, ago time.Duration, kind string) {
+		stats.Record(rpc.LedgerTransaction{TxHash: hash, Ledger: ledger, CreatedAt: now.Add(-ago).Unix()}, kind, now)
+	}
+	record("old", 1, 20*time.Minute, "tx_bad_seq")
+	record("a", 2, 8*time.Minute, "Error(Contract, #3)")
+	record("b", 3, 3*time.Minute, "tx_bad_seq")
+	record("c", 4, 30*time.Second, "Error(Contract, #3)")
+	record("d", 5, 10*time.Second, "Error(Contract, #3)")
+
+	summary := stats.Snapshot(now, 2)
+	if summary.Total != 5 || summary.InWindow != 4 {
+		t.Errorf("Total = %d, InWindow = %d, want 5 and 4", summary.Total, summary.InWindow)
+	}
+	if summary.RateLastMinute != 2 || summary.RateLast5Minute != 0.6 || summary.RateWindow != 0.4 {
+		t.Errorf("rates = %v, %v, %v", summary.RateLastMinute, summary.RateLast5Minute, summary.RateWindow)
+	}
+	if len(summary.Kinds) != 2 || summary.Kinds[0] != (KindCount{Kind: "Error(Contract, #3)", Count: 3}) {
+		t.Errorf("Kinds = %+v", summary.Kinds)
+	}
+	if len(summary.Latest) != 2 || summary.Latest[0].TxHash != "d" || summary.Latest[1].TxHash != "c" {
+		t.Errorf("Latest = %+v, want d then c", summary.Latest)
+	}
+	if summary.LastLedger != 5 {
+		t.Errorf("LastLedger = %d, want 5", summary.LastLedger)
+	}
+
+	// Failures age out of the window
+	summary = stats.Snapshot(now.Add(9*time.Minute), 10)
+	if summary.InWindow != 2 || summary.RateLastMinute != 0 {
+		t.Errorf("InWindow = %d, RateLastMinute = %v after 9 minutes", summary.InWindow, summary.RateLastMinute)
+	}
+}
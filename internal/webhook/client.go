@@ -19,6 +19,7 @@ import (
 type WebhookType string
 
 const (
+	JSONWebhook    WebhookType = "json"
 	SlackWebhook   WebhookType = "slack"
 	DiscordWebhook WebhookType = "discord"
 )
@@ -68,6 +69,8 @@ func (c *Client) Send(report ReportData) error {
 	var payload interface{}
 
 	switch c.config.Type {
+	case JSONWebhook:
+		payload = FormatJSONMessage(report)
 	case SlackWebhook:
 		payload = FormatSlackMessage(report)
 	case DiscordWebhook:
@@ -21,6 +21,31 @@ type ReportData struct {
 	AuditLogURL      string
 	DiagnosticEvents []simulator.DiagnosticEvent
 	Logs             []string
+	// Source is the mode that ran the simulation, e.g. "watch" or "daemon"
+	Source string
+	// SessionID is the saved session holding the full simulation, if any
+	SessionID string
+	Ledger    uint32
+	// Rule is the alerting rule that sent the report, if any
+	Rule string
+}
+
+// JSONMessage is the payload of generic JSON webhooks
+type JSONMessage struct {
+	Event            string                      `json:"event"`
+	Source           string                      `json:"source,omitempty"`
+	Rule             string                      `json:"rule,omitempty"`
+	TraceID          string                      `json:"trace_id"`
+	TxHash           string                      `json:"tx_hash"`
+	Network          string                      `json:"network"`
+	Ledger           uint32                      `json:"ledger,omitempty"`
+	Status           string                      `json:"status"`
+	Error            string                      `json:"error,omitempty"`
+	SessionID        string                      `json:"session_id,omitempty"`
+	Timestamp        time.Time                   `json:"timestamp"`
+	AuditLogURL      string                      `json:"audit_log_url,omitempty"`
+	DiagnosticEvents []simulator.DiagnosticEvent `json:"diagnostic_events,omitempty"`
+	Logs             []string                    `json:"logs,omitempty"`
 }
 
 // SlackMessage represents Slack webhook payload
@@ -58,6 +83,30 @@ type DiscordEmbedFooter struct {
 	Text string `json:"text"`
 }
 
+// FormatJSONMessage creates the payload for a generic JSON webhook
+func FormatJSONMessage(report ReportData) JSONMessage {
+	event := "simulation.failed"
+	if report.Status == "success" {
+		event = "simulation.succeeded"
+	}
+	return JSONMessage{
+		Event:            event,
+		Source:           report.Source,
+		Rule:             report.Rule,
+		TraceID:          report.TraceID,
+		TxHash:           report.TxHash,
+		Network:          report.Network,
+		Ledger:           report.Ledger,
+		Status:           report.Status,
+		Error:            report.Error,
+		SessionID:        report.SessionID,
+		Timestamp:        report.Timestamp,
+		AuditLogURL:      report.AuditLogURL,
+		DiagnosticEvents: report.DiagnosticEvents,
+		Logs:             report.Logs,
+	}
+}
+
 // FormatSlackMessage creates a formatted Slack webhook message
 func FormatSlackMessage(report ReportData) SlackMessage {
 	headerSection := map[string]interface{}{
@@ -105,6 +154,16 @@ func FormatSlackMessage(report ReportData) SlackMessage {
 	}
 	blocks = append(blocks, txBlock)
 
+	if report.Rule != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("*Alert rule:* %s", report.Rule),
+			},
+		})
+	}
+
 	// Add error details if present
 	if report.Error != "" {
 		errorBlock := map[string]interface{}{
@@ -205,6 +264,14 @@ func FormatDiscordMessage(report ReportData) DiscordMessage {
 		},
 	}
 
+	if report.Rule != "" {
+		fields = append(fields, DiscordEmbedField{
+			Name:   "Alert Rule",
+			Value:  report.Rule,
+			Inline: false,
+		})
+	}
+
 	// Add error if present
 	if report.Error != "" {
 		fields = append(fields, DiscordEmbedField{
@@ -5,6 +5,7 @@ package webhook
 
 import (
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/dotandev/hintents/internal/logger"
@@ -16,6 +17,7 @@ type SimulatorNotifier struct {
 	clients   []*Client
 	enabled   bool
 	errorOnly bool
+	filters   []*regexp.Regexp
 }
 
 // NotifierConfig contains configuration for the notifier
@@ -23,6 +25,9 @@ type NotifierConfig struct {
 	Enabled   bool
 	ErrorOnly bool
 	Webhooks  []Config
+	// ErrorPatterns, when set, limits notifications to reports whose error
+	// matches at least one of the regular expressions
+	ErrorPatterns []string
 }
 
 // NewSimulatorNotifier creates a notifier for simulator session events
@@ -33,6 +38,15 @@ func NewSimulatorNotifier(config NotifierConfig) (*SimulatorNotifier, error) {
 		}, nil
 	}
 
+	filters := make([]*regexp.Regexp, 0, len(config.ErrorPatterns))
+	for _, pattern := range config.ErrorPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook error filter %q: %w", pattern, err)
+		}
+		filters = append(filters, re)
+	}
+
 	clients := make([]*Client, 0, len(config.Webhooks))
 	for _, whConfig := range config.Webhooks {
 		client, err := NewClient(whConfig)
@@ -55,6 +69,7 @@ func NewSimulatorNotifier(config NotifierConfig) (*SimulatorNotifier, error) {
 		clients:   clients,
 		enabled:   true,
 		errorOnly: config.ErrorOnly,
+		filters:   filters,
 	}, nil
 }
 
@@ -70,13 +85,7 @@ func (sn *SimulatorNotifier) NotifyResponse(
 		return
 	}
 
-	// Skip if error-only mode and status is success
-	if sn.errorOnly && resp.Status == "success" {
-		return
-	}
-
-	report := sn.buildReportData(req, resp, txHash, network, auditLogURL)
-	sn.notifyAll(report)
+	sn.Notify(sn.buildReportData(req, resp, txHash, network, auditLogURL))
 }
 
 // NotifyError sends an error notification directly
@@ -100,9 +109,38 @@ func (sn *SimulatorNotifier) NotifyError(
 		AuditLogURL: auditLogURL,
 	}
 
+	sn.Notify(report)
+}
+
+// Notify sends report to all webhooks unless it is filtered out
+func (sn *SimulatorNotifier) Notify(report ReportData) {
+	if !sn.enabled {
+		return
+	}
+
+	// Skip if error-only mode and status is success
+	if sn.errorOnly && report.Status == "success" {
+		return
+	}
+	if !sn.matchesFilters(report.Error) {
+		return
+	}
+
 	sn.notifyAll(report)
 }
 
+func (sn *SimulatorNotifier) matchesFilters(errorMsg string) bool {
+	if len(sn.filters) == 0 {
+		return true
+	}
+	for _, re := range sn.filters {
+		if re.MatchString(errorMsg) {
+			return true
+		}
+	}
+	return false
+}
+
 // buildReportData constructs the ReportData from simulator response
 func (sn *SimulatorNotifier) buildReportData(
 	req *simulator.SimulationRequest,
@@ -111,19 +149,23 @@ func (sn *SimulatorNotifier) buildReportData(
 	network string,
 	auditLogURL string,
 ) ReportData {
-	report := ReportData{
+	report := NewReport(resp, txHash, network)
+	report.AuditLogURL = auditLogURL
+	return report
+}
+
+// NewReport builds the report for a simulation of txHash
+func NewReport(resp *simulator.SimulationResponse, txHash string, network string) ReportData {
+	return ReportData{
 		TraceID:          "trace-" + fmt.Sprintf("%d", time.Now().Unix()),
 		TxHash:           txHash,
 		Network:          network,
 		Status:           resp.Status,
 		Error:            resp.Error,
 		Timestamp:        time.Now(),
-		AuditLogURL:      auditLogURL,
 		DiagnosticEvents: resp.DiagnosticEvents,
 		Logs:             resp.Logs,
 	}
-
-	return report
 }
 
 // notifyAll sends the report to all configured webhooks
@@ -304,6 +304,93 @@ func TestSimulatorNotifierErrorOnly(t *testing.T) {
 	notifier.NotifyResponse(nil, errResp, "0xtest", "testnet", "")
 }
 
+func TestJSONWebhookSend(t *testing.T) {
+	received := make(chan JSONMessage, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg JSONMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("Failed to decode payload: %v", err)
+		}
+		received <- msg
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{Type: JSONWebhook, URL: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	report := ReportData{
+		TraceID:   "trace-json",
+		TxHash:    "0xjson",
+		Network:   "testnet",
+		Status:    "error",
+		Error:     "HostError: Error(Contract, #3)",
+		Timestamp: time.Now(),
+		Source:    "watch",
+		SessionID: "abc-123",
+		Ledger:    42,
+	}
+	if err := client.Send(report); err != nil {
+		t.Fatalf("Failed to send webhook: %v", err)
+	}
+
+	msg := <-received
+	if msg.Event != "simulation.failed" {
+		t.Errorf("Expected simulation.failed event, got %q", msg.Event)
+	}
+	if msg.TxHash != "0xjson" || msg.SessionID != "abc-123" || msg.Source != "watch" || msg.Ledger != 42 {
+		t.Errorf("Unexpected payload: %+v", msg)
+	}
+}
+
+func TestSimulatorNotifierErrorPatterns(t *testing.T) {
+	received := make(chan JSONMessage, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg JSONMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		received <- msg
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier, err := NewSimulatorNotifier(NotifierConfig{
+		Enabled:       true,
+		ErrorOnly:     true,
+		Webhooks:      []Config{{Type: JSONWebhook, URL: server.URL, Timeout: 5 * time.Second}},
+		ErrorPatterns: []string{"Budget", `Contract, #\d+`},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create notifier: %v", err)
+	}
+
+	notifier.Notify(ReportData{TxHash: "0xskip", Status: "error", Error: "WasmVm trap"})
+	notifier.Notify(ReportData{TxHash: "0xmatch", Status: "error", Error: "HostError: Error(Contract, #3)"})
+
+	select {
+	case msg := <-received:
+		if msg.TxHash != "0xmatch" {
+			t.Errorf("Expected only the matching failure, got %s", msg.TxHash)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a notification for the matching failure")
+	}
+	select {
+	case msg := <-received:
+		t.Errorf("Unexpected notification for %s", msg.TxHash)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if _, err := NewSimulatorNotifier(NotifierConfig{
+		Enabled:       true,
+		Webhooks:      []Config{{Type: JSONWebhook, URL: server.URL}},
+		ErrorPatterns: []string{"("},
+	}); err == nil {
+		t.Error("Expected error for invalid filter pattern")
+	}
+}
+
 func TestColorMapping(t *testing.T) {
 	tests := []struct {
 		status string
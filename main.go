@@ -5,8 +5,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"log/slog"
+	_ "log/slog"
 	"net/http"
 	"os"
 	"runtime/debug"
@@ -65,7 +66,7 @@ func main() {
 			stack := debug.Stack()
 			_ = reporter.Send(ctx, execErr, stack, "erst")
 		}
-		fmt.Fprintf(os.Stderr, "Error: %v\n", execErr)
-		os.Exit(1)
+		cmd.PrintError(execErr)
+		os.Exit(cmd.ExitCode(execErr))
 	}
 }
\ No newline at end of file
@@ -0,0 +1,169 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+//! Per-frame budget accounting.
+//!
+//! A trace hook records the CPU instructions and memory consumed between each
+//! frame push and its matching pop, and counts the host functions each frame
+//! calls. Frames are listed in the order they were pushed, so the CLI can
+//! attach them to the call tree it rebuilds from diagnostic events.
+//!
+//! With memory profiling enabled, each frame's own memory is further split
+//! into what each host function allocated and what was charged outside host
+//! calls, which is linear memory growth and VM instantiation.
+
+use crate::types::{FrameBudget, HostAlloc};
+use soroban_env_host::{Host, TraceEvent};
+use std::cell::RefCell;
+use std::rc::Rc;
+
+struct OpenFrame {
+    index: usize,
+    cpu: u64,
+    mem: u64,
+    // Memory consumed by sub-frames that already closed
+    child_mem: u64,
+}
+
+struct PendingCall {
+    frame: usize,
+    name: &'static str,
+    mem: u64,
+    child_mem: u64,
+}
+
+#[derive(Default)]
+pub struct Recorder {
+    frames: Vec<FrameBudget>,
+    open: Vec<OpenFrame>,
+    profile_memory: bool,
+    // Host calls awaiting their EnvRet, innermost last
+    pending: Vec<PendingCall>,
+}
+
+impl Recorder {
+    fn close(&mut self, host: &Host) {
+        if let Some(frame) = self.open.pop() {
+            let (now_cpu, now_mem) = consumed(host);
+            let mem = now_mem.saturating_sub(frame.mem);
+            let budget = &mut self.frames[frame.index];
+            budget.cpu_insns = now_cpu.saturating_sub(frame.cpu);
+            budget.mem_bytes = mem;
+            if self.profile_memory {
+                let own = mem.saturating_sub(frame.child_mem);
+                let host_allocs: u64 = budget.host_allocs.iter().map(|a| a.mem_bytes).sum();
+                budget.linear_mem_bytes = Some(own.saturating_sub(host_allocs));
+            }
+            if let Some(parent) = self.open.last_mut() {
+                parent.child_mem += mem;
+            }
+        }
+    }
+
+    fn env_call(&mut self, host: &Host, name: &'static str) {
+        let Some(frame) = self.open.last() else {
+            return;
+        };
+        self.frames[frame.index].host_fn_calls += 1;
+        if self.profile_memory {
+            let (_, mem) = consumed(host);
+            let pending = PendingCall {
+                frame: frame.index,
+                name,
+                mem,
+                child_mem: frame.child_mem,
+            };
+            self.pending.push(pending);
+        }
+    }
+
+    /// Attributes the memory a host call allocated, less any frames it
+    /// pushed, to the calling frame.
+    fn env_ret(&mut self, host: &Host) {
+        let Some(call) = self.pending.pop() else {
+            return;
+        };
+        let Some(frame) = self.open.last().filter(|f| f.index == call.frame) else {
+            return;
+        };
+        let (_, now_mem) = consumed(host);
+        let bytes = now_mem
+            .saturating_sub(call.mem)
+            .saturating_sub(frame.child_mem.saturating_sub(call.child_mem));
+        let allocs = &mut self.frames[frame.index].host_allocs;
+        match allocs.iter_mut().find(|a| a.name == call.name) {
+            Some(alloc) => {
+                alloc.calls += 1;
+                alloc.mem_bytes += bytes;
+            }
+            None => allocs.push(HostAlloc {
+                name: call.name.to_string(),
+                calls: 1,
+                mem_bytes: bytes,
+            }),
+        }
+    }
+}
+
+fn consumed(host: &Host) -> (u64, u64) {
+    let budget = host.budget_cloned();
+    (
+        budget.get_cpu_insns_consumed().unwrap_or(0),
+        budget.get_mem_bytes_consumed().unwrap_or(0),
+    )
+}
+
+/// Installs the budget hook. Only one trace hook can be installed, so this is
+/// skipped in step mode.
+pub fn install(host: &Host, profile_memory: bool) -> Result<Rc<RefCell<Recorder>>, String> {
+    let recorder = Rc::new(RefCell::new(Recorder {
+        profile_memory,
+        ..Recorder::default()
+    }));
+    let hook = recorder.clone();
+    host.set_trace_hook(Some(Rc::new(move |host: &Host, event: &TraceEvent| {
+        let mut rec = hook.borrow_mut();
+        match event {
+            TraceEvent::PushCtx(_) => {
+                let (cpu, mem) = consumed(host);
+                let depth = rec.open.len();
+                rec.frames.push(FrameBudget {
+                    depth,
+                    cpu_insns: 0,
+                    mem_bytes: 0,
+                    host_fn_calls: 0,
+                    host_allocs: vec![],
+                    linear_mem_bytes: None,
+                });
+                let index = rec.frames.len() - 1;
+                rec.open.push(OpenFrame {
+                    index,
+                    cpu,
+                    mem,
+                    child_mem: 0,
+                });
+            }
+            TraceEvent::PopCtx(_, _) => rec.close(host),
+            TraceEvent::EnvCall(name, _) => rec.env_call(host, name),
+            TraceEvent::EnvRet(_, _) => rec.env_ret(host),
+            _ => {}
+        }
+        Ok(())
+    })))
+    .map_err(|e| format!("failed to install budget hook: {:?}", e))?;
+    Ok(recorder)
+}
+
+/// Returns the recorded frames. Frames and host calls a failure left open are
+/// closed with the budget consumed so far.
+pub fn finish(host: &Host, recorder: &Rc<RefCell<Recorder>>) -> Vec<FrameBudget> {
+    let mut rec = recorder.borrow_mut();
+    while let Some(frame) = rec.open.last().map(|f| f.index) {
+        while rec.pending.last().is_some_and(|c| c.frame == frame) {
+            rec.env_ret(host);
+        }
+        rec.close(host);
+    }
+    rec.pending.clear();
+    std::mem::take(&mut rec.frames)
+}
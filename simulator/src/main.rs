@@ -3,12 +3,14 @@
 
 #![allow(unused_imports, unused_variables, clippy::useless_format)]
 
+mod calls;
 mod config;
 mod gas_optimizer;
 mod runner;
 mod source_map_cache;
 mod source_mapper;
 mod stack_trace;
+mod step;
 mod vm;
 mod types;
 mod wasm;
@@ -19,7 +21,7 @@ use crate::stack_trace::{decode_error, WasmStackTrace};
 use crate::types::*;
 use base64::Engine as _;
 use serde::{Deserialize, Serialize};
-use soroban_env_host::xdr::ReadXdr;
+use soroban_env_host::xdr::{ReadXdr, WriteXdr};
 use soroban_env_host::{
     xdr::{Operation, OperationBody},
     Host, HostError,
@@ -63,8 +65,13 @@ fn send_error(msg: String) {
         categorized_events: vec![],
         logs: vec![],
         flamegraph: None,
+        folded_stacks: None,
         optimization_report: None,
         budget_usage: None,
+        return_values: vec![],
+        storage_writes: vec![],
+        storage_accesses: vec![],
+        call_budgets: vec![],
         source_location: None,
         stack_trace: Some(trace),
         wasm_offset: None,
@@ -73,14 +80,49 @@ fn send_error(msg: String) {
     std::process::exit(1);
 }
 
-fn execute_operations(host: &Host, operations: &[Operation]) -> Result<Vec<String>, HostError> {
+/// Base64 XDR of each event topic, so the CLI can render SCVals itself. The
+/// list lines up with `topics`: a topic that cannot be encoded is left empty
+/// rather than dropped, so the CLI falls back to its debug rendering.
+fn event_topics_xdr(body: &soroban_env_host::xdr::ContractEventBody) -> Vec<String> {
+    match body {
+        soroban_env_host::xdr::ContractEventBody::V0(v0) => v0
+            .topics
+            .iter()
+            .map(|t| {
+                t.to_xdr_base64(soroban_env_host::xdr::Limits::none())
+                    .unwrap_or_default()
+            })
+            .collect(),
+    }
+}
+
+/// Base64 XDR of the event data.
+fn event_data_xdr(body: &soroban_env_host::xdr::ContractEventBody) -> Option<String> {
+    match body {
+        soroban_env_host::xdr::ContractEventBody::V0(v0) => v0
+            .data
+            .to_xdr_base64(soroban_env_host::xdr::Limits::none())
+            .ok(),
+    }
+}
+
+/// Runs each operation, returning the logs and the base64 XDR return value
+/// of every invoked host function.
+fn execute_operations(
+    host: &Host,
+    operations: &[Operation],
+) -> Result<(Vec<String>, Vec<String>), HostError> {
     let mut logs = Vec::new();
+    let mut return_values = Vec::new();
     for op in operations {
         match &op.body {
             OperationBody::InvokeHostFunction(invoke_op) => {
                 logs.push("Executing InvokeHostFunction...".to_string());
                 let val = host.invoke_function(invoke_op.host_function.clone())?;
                 logs.push(format!("Result: {val:?}"));
+                if let Ok(xdr) = val.to_xdr_base64(soroban_env_host::xdr::Limits::none()) {
+                    return_values.push(xdr);
+                }
             }
             _ => {
                 logs.push(format!(
@@ -90,7 +132,64 @@ fn execute_operations(host: &Host, operations: &[Operation]) -> Result<Vec<Strin
             }
         }
     }
-    Ok(logs)
+    Ok((logs, return_values))
+}
+
+/// Post-execution state of every entry in the read-write footprint.
+fn storage_writes(host: &Host) -> Vec<StorageWrite> {
+    let budget = host.budget_cloned();
+    host.with_mut_storage(|storage| {
+        let mut writes = Vec::new();
+        for (key, access) in storage.footprint.0.iter(&budget)? {
+            if *access != soroban_env_host::storage::AccessType::ReadWrite {
+                continue;
+            }
+            let entry = match storage
+                .map
+                .get::<std::rc::Rc<soroban_env_host::xdr::LedgerKey>>(key, &budget)?
+            {
+                Some(Some((entry, _))) => entry
+                    .to_xdr_base64(soroban_env_host::xdr::Limits::none())
+                    .ok(),
+                _ => None,
+            };
+            if let Ok(key) = key.to_xdr_base64(soroban_env_host::xdr::Limits::none()) {
+                writes.push(StorageWrite { key, entry });
+            }
+        }
+        Ok(writes)
+    })
+    .unwrap_or_default()
+}
+
+/// Per-frame budget, when the budget hook could be installed.
+fn frame_budgets(
+    host: &Host,
+    recorder: &Option<std::rc::Rc<std::cell::RefCell<calls::Recorder>>>,
+) -> Vec<FrameBudget> {
+    recorder
+        .as_ref()
+        .map(|r| calls::finish(host, r))
+        .unwrap_or_default()
+}
+
+/// Every key in the host's storage footprint, i.e. every entry the
+/// transaction read or wrote.
+fn storage_accesses(host: &Host) -> Vec<StorageAccess> {
+    let budget = host.budget_cloned();
+    host.with_mut_storage(|storage| {
+        let mut accesses = Vec::new();
+        for (key, access) in storage.footprint.0.iter(&budget)? {
+            if let Ok(key) = key.to_xdr_base64(soroban_env_host::xdr::Limits::none()) {
+                accesses.push(StorageAccess {
+                    key,
+                    read_write: *access == soroban_env_host::storage::AccessType::ReadWrite,
+                });
+            }
+        }
+        Ok(accesses)
+    })
+    .unwrap_or_default()
 }
 
 fn categorize_events(events: &soroban_env_host::events::Events) -> Vec<CategorizedEvent> {
@@ -130,6 +229,8 @@ fn categorize_events(events: &soroban_env_host::events::Events) -> Vec<Categoriz
                     contract_id,
                     topics,
                     data,
+                    topics_xdr: event_topics_xdr(&e.event.body),
+                    data_xdr: event_data_xdr(&e.event.body),
                     // failed_call=true means the call that emitted this event
                     // actually failed; so a successful call is the inverse.
                     in_successful_contract_call: !e.failed_call,
@@ -172,8 +273,13 @@ fn main() {
             categorized_events: vec![],
             logs: vec![],
             flamegraph: None,
+            folded_stacks: None,
             optimization_report: None,
             budget_usage: None,
+            return_values: vec![],
+            storage_writes: vec![],
+            storage_accesses: vec![],
+            call_budgets: vec![],
             source_location: None,
             stack_trace: None,
         };
@@ -195,8 +301,13 @@ fn main() {
                 categorized_events: vec![],
                 logs: vec![],
                 flamegraph: None,
+                folded_stacks: None,
                 optimization_report: None,
                 budget_usage: None,
+                return_values: vec![],
+                storage_writes: vec![],
+                storage_accesses: vec![],
+                call_budgets: vec![],
                 source_location: None,
                 stack_trace: None,
                 wasm_offset: None,
@@ -285,9 +396,39 @@ fn main() {
     };
 
     // Initialize Host
-    let sim_host = runner::SimHost::new(None, request.resource_calibration.clone());
+    let cpu_limit = request.cpu_limit.unwrap_or(CPU_LIMIT);
+    let memory_limit = request.mem_limit.unwrap_or(MEMORY_LIMIT);
+    let budget_limits = (request.cpu_limit.is_some() || request.mem_limit.is_some())
+        .then_some((cpu_limit, memory_limit));
+    let sim_host = match runner::SimHost::new(budget_limits, request.resource_calibration.clone()) {
+        Ok(sim_host) => sim_host,
+        Err(e) => {
+            send_error(format!(
+                "Failed to set budget limits (cpu {cpu_limit}, memory {memory_limit}): {e:?}"
+            ));
+            return;
+        }
+    };
     let host = sim_host.inner;
 
+    let budget_recorder = match step::install_from_env(&host) {
+        Ok(true) => {
+            eprintln!("Step mode enabled");
+            None
+        }
+        Ok(false) => match calls::install(&host, request.profile_memory.unwrap_or(false)) {
+            Ok(recorder) => Some(recorder),
+            Err(e) => {
+                eprintln!("Warning: {}", e);
+                None
+            }
+        },
+        Err(e) => {
+            send_error(e);
+            return;
+        }
+    };
+
     // --- START: Local WASM Loading Integration (Issue #70) ---
     if let Some(path) = &request.wasm_path {
         match wasm::load_wasm_from_path(path) {
@@ -371,15 +512,15 @@ fn main() {
     let cpu_insns = budget.get_cpu_insns_consumed().unwrap_or(0);
     let mem_bytes = budget.get_mem_bytes_consumed().unwrap_or(0);
 
-    let cpu_usage_percent = (cpu_insns as f64 / CPU_LIMIT as f64) * 100.0;
-    let memory_usage_percent = (mem_bytes as f64 / MEMORY_LIMIT as f64) * 100.0;
+    let cpu_usage_percent = (cpu_insns as f64 / cpu_limit as f64) * 100.0;
+    let memory_usage_percent = (mem_bytes as f64 / memory_limit as f64) * 100.0;
 
     let budget_usage = BudgetUsage {
         cpu_instructions: cpu_insns,
         memory_bytes: mem_bytes,
         operations_count: operations.len(),
-        cpu_limit: CPU_LIMIT,
-        memory_limit: MEMORY_LIMIT,
+        cpu_limit,
+        memory_limit,
         cpu_usage_percent,
         memory_usage_percent,
     };
@@ -397,9 +538,11 @@ fn main() {
     };
 
     let mut flamegraph_svg = None;
+    let mut folded_stacks = None;
     if request.profile.unwrap_or(false) {
         // Simple simulated flamegraph for demonstration
         let folded_data = format!("Total;CPU {}\nTotal;Memory {}\n", cpu_insns, mem_bytes);
+        folded_stacks = Some(folded_data.clone());
         let mut result_vec = Vec::new();
         let mut options = inferno::flamegraph::Options::default();
         options.title = "Soroban Resource Consumption".to_string();
@@ -414,7 +557,7 @@ fn main() {
     }
 
     match result {
-        Ok(Ok(exec_logs)) => {
+        Ok(Ok((exec_logs, return_values))) => {
             // Extract both raw event strings and structured diagnostic events
             let (events, diagnostic_events): (Vec<String>, Vec<DiagnosticEvent>) =
                 match host.get_events() {
@@ -457,6 +600,8 @@ fn main() {
                                     contract_id,
                                     topics,
                                     data,
+                                    topics_xdr: event_topics_xdr(&event.event.body),
+                                    data_xdr: event_data_xdr(&event.event.body),
                                     // failed_call=true means the call failed;
                                     // negate to get "was this a successful call?".
                                     in_successful_contract_call: !event.failed_call,
@@ -486,6 +631,8 @@ fn main() {
                                 contract_id,
                                 topics,
                                 data,
+                                topics_xdr: event_topics_xdr(&event.event.body),
+                                data_xdr: event_data_xdr(&event.event.body),
                                 in_successful_contract_call: event.failed_call,
                             }
                         })
@@ -521,8 +668,13 @@ fn main() {
                 categorized_events,
                 logs: final_logs,
                 flamegraph: flamegraph_svg,
+                folded_stacks,
                 optimization_report,
                 budget_usage: Some(budget_usage),
+                return_values,
+                storage_writes: storage_writes(&host),
+                storage_accesses: storage_accesses(&host),
+                call_budgets: frame_budgets(&host, &budget_recorder),
                 source_location: None,
                 stack_trace: None,
                 // If a WASM with debug symbols was provided, expose the first
@@ -592,6 +744,8 @@ fn main() {
                                     contract_id,
                                     topics,
                                     data,
+                                    topics_xdr: event_topics_xdr(&event.event.body),
+                                    data_xdr: event_data_xdr(&event.event.body),
                                     in_successful_contract_call: event.failed_call,
                                 }
                             })
@@ -686,8 +840,13 @@ fn main() {
                 categorized_events,
                 logs: vec![],
                 flamegraph: None,
+                folded_stacks: None,
                 optimization_report: None,
                 budget_usage: None,
+                return_values: vec![],
+                storage_writes: vec![],
+                storage_accesses: storage_accesses(&host),
+                call_budgets: frame_budgets(&host, &budget_recorder),
                 source_location: None,
                 stack_trace: Some(wasm_trace),
                 wasm_offset,
@@ -713,8 +872,13 @@ fn main() {
                 categorized_events: vec![],
                 logs: vec![format!("PANIC: {}", panic_msg)],
                 flamegraph: None,
+                folded_stacks: None,
                 optimization_report: None,
                 budget_usage: None,
+                return_values: vec![],
+                storage_writes: vec![],
+                storage_accesses: vec![],
+                call_budgets: vec![],
                 source_location: None,
                 stack_trace: Some(wasm_trace),
                 wasm_offset: None,
@@ -894,6 +1058,33 @@ mod tests {
         assert_eq!(cats[2].event.event_type, "diagnostic");
     }
 
+    /// `topics_xdr` lines up with `topics`, one entry per topic.
+    #[test]
+    fn test_event_topics_xdr_lines_up_with_topics() {
+        use soroban_env_host::xdr::{ContractEventBody, ContractEventV0, ScSymbol, ScVal};
+
+        let topics = vec![
+            ScVal::Symbol(ScSymbol("transfer".try_into().unwrap())),
+            ScVal::Void,
+            ScVal::U32(7),
+        ];
+        let body = ContractEventBody::V0(ContractEventV0 {
+            topics: topics.clone().try_into().unwrap(),
+            data: ScVal::Void,
+        });
+
+        let encoded = event_topics_xdr(&body);
+        assert_eq!(encoded.len(), topics.len());
+        for (b64, topic) in encoded.iter().zip(&topics) {
+            assert_eq!(
+                b64,
+                &topic
+                    .to_xdr_base64(soroban_env_host::xdr::Limits::none())
+                    .unwrap()
+            );
+        }
+    }
+
     /// SourceMapper without debug symbols must return None for source locations,
     /// and the `source_location` field stays absent in serialized JSON.
     #[test]
@@ -19,7 +19,9 @@ pub struct SimHost {
 #[allow(dead_code)]
 impl SimHost {
     /// Initialize a new Host with optional budget settings and resource calibration.
-    pub fn new(budget_limits: Option<(u64, u64)>, calibration: Option<crate::types::ResourceCalibration>) -> Self {
+    /// Fails when the budget limits cannot be applied, as simulating with the
+    /// default budget would report results for limits the caller did not ask for.
+    pub fn new(budget_limits: Option<(u64, u64)>, calibration: Option<crate::types::ResourceCalibration>) -> Result<Self, HostError> {
         let budget = Budget::default();
         
         if let Some(calib) = calibration {
@@ -48,9 +50,8 @@ impl SimHost {
             let _ = budget.set_model(ContractCostType::VerifyEd25519Sig, ed25519_model);
         }
 
-        if let Some((_cpu, _mem)) = budget_limits {
-            // Budget customization requires testutils feature or extended API
-            // Using default mainnet budget settings
+        if let Some((cpu, mem)) = budget_limits {
+            budget.reset_limits(cpu, mem)?;
         }
 
         // Host::with_storage_and_budget is available in recent versions
@@ -60,11 +61,11 @@ impl SimHost {
         host.set_diagnostic_level(DiagnosticLevel::Debug)
             .expect("failed to set diagnostic level");
 
-        Self {
+        Ok(Self {
             inner: host,
             contract_id: None,
             fn_name: None,
-        }
+        })
     }
 
     /// Set the contract ID for execution context.
@@ -98,14 +99,14 @@ mod tests {
 
     #[test]
     fn test_host_initialization() {
-        let host = SimHost::new(None, None);
+        let host = SimHost::new(None, None).expect("failed to create host");
         // Basic assertion that host is functional
         assert!(host.inner.budget_cloned().get_cpu_insns_consumed().is_ok());
     }
 
     #[test]
     fn test_configuration() {
-        let mut host = SimHost::new(None, None);
+        let mut host = SimHost::new(None, None).expect("failed to create host");
         // Test setting contract ID (dummy hash)
         let hash = Hash([0u8; 32]);
         host.set_contract_id(hash);
@@ -119,7 +120,7 @@ mod tests {
 
     #[test]
     fn test_simple_value_handling() {
-        let host = SimHost::new(None, None);
+        let host = SimHost::new(None, None).expect("failed to create host");
 
         let a = 10u32;
         let b = 20u32;
@@ -0,0 +1,180 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+//! Step-through control channel.
+//!
+//! When the CLI runs the simulator with `ERST_STEP_FDS=<events>,<commands>`
+//! it passes two extra pipes. Every frame push, frame pop and host function
+//! call is reported as one JSON line on the events pipe, and execution blocks
+//! until the CLI answers with `resume` or `abort` on the commands pipe.
+//! Stdin and stdout keep carrying the request and the final response, so the
+//! regular protocol is unchanged.
+
+use serde::Serialize;
+use soroban_env_host::xdr::{ScErrorCode, ScErrorType};
+use soroban_env_host::{Error, Host, HostError, TraceEvent};
+use std::cell::RefCell;
+use std::fs::File;
+use std::io::{BufRead, BufReader, Write};
+use std::os::unix::io::FromRawFd;
+use std::rc::Rc;
+
+pub const STEP_FDS_ENV: &str = "ERST_STEP_FDS";
+
+#[derive(Debug, Serialize)]
+struct PauseEvent<'a> {
+    #[serde(rename = "type")]
+    kind_tag: &'static str,
+    seq: u64,
+    kind: &'static str,
+    depth: usize,
+    #[serde(skip_serializing_if = "str::is_empty")]
+    name: &'a str,
+    #[serde(skip_serializing_if = "str::is_empty")]
+    contract: &'a str,
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    args: Vec<String>,
+}
+
+struct Channel {
+    events: File,
+    commands: BufReader<File>,
+    seq: u64,
+    depth: usize,
+}
+
+impl Channel {
+    /// Reports one pause and blocks for the CLI's answer. Returns false when
+    /// the session was aborted or the channel closed.
+    fn pause(&mut self, kind: &'static str, name: &str, contract: &str, args: Vec<String>) -> bool {
+        self.seq += 1;
+        let event = PauseEvent {
+            kind_tag: "pause",
+            seq: self.seq,
+            kind,
+            depth: self.depth,
+            name,
+            contract,
+            args,
+        };
+        let line = match serde_json::to_string(&event) {
+            Ok(l) => l,
+            Err(_) => return true,
+        };
+        if writeln!(self.events, "{}", line).is_err() || self.events.flush().is_err() {
+            return false;
+        }
+
+        let mut reply = String::new();
+        match self.commands.read_line(&mut reply) {
+            Ok(0) | Err(_) => false,
+            Ok(_) => reply.trim() != "abort",
+        }
+    }
+}
+
+/// Splits the host's rendering of a pushed frame, e.g.
+/// `push VM:d7a4c1b2:transfer(...)`, into the callee's contract and function.
+/// The host only renders the leading bytes of the contract ID in hex; frames
+/// without a contract, such as host functions, yield empty strings.
+fn frame_target(rendered: &str) -> (String, String) {
+    let frame = rendered.strip_prefix("push ").unwrap_or(rendered);
+    let mut parts = frame.splitn(3, ':');
+    match (parts.next(), parts.next(), parts.next()) {
+        (Some(_kind), Some(contract), Some(call)) => {
+            let function = call.split('(').next().unwrap_or_default();
+            (contract.to_string(), function.to_string())
+        }
+        _ => (String::new(), String::new()),
+    }
+}
+
+fn parse_fds(value: &str) -> Option<(i32, i32)> {
+    let (events, commands) = value.split_once(',')?;
+    Some((events.trim().parse().ok()?, commands.trim().parse().ok()?))
+}
+
+fn aborted() -> HostError {
+    Error::from_type_and_code(ScErrorType::Context, ScErrorCode::InternalError).into()
+}
+
+/// Installs the stepping trace hook when the CLI asked for it. Does nothing
+/// when `ERST_STEP_FDS` is unset.
+pub fn install_from_env(host: &Host) -> Result<bool, String> {
+    let value = match std::env::var(STEP_FDS_ENV) {
+        Ok(v) => v,
+        Err(_) => return Ok(false),
+    };
+    let (events_fd, commands_fd) =
+        parse_fds(&value).ok_or_else(|| format!("invalid {}: {:?}", STEP_FDS_ENV, value))?;
+
+    // SAFETY: the descriptors are inherited pipes handed to us by the CLI and
+    // owned exclusively by this channel.
+    let channel = unsafe {
+        Channel {
+            events: File::from_raw_fd(events_fd),
+            commands: BufReader::new(File::from_raw_fd(commands_fd)),
+            seq: 0,
+            depth: 0,
+        }
+    };
+    let channel = Rc::new(RefCell::new(channel));
+
+    host.set_trace_hook(Some(Rc::new(move |_host: &Host, event: &TraceEvent| {
+        let mut ch = channel.borrow_mut();
+        let resume = match event {
+            TraceEvent::PushCtx(_) => {
+                ch.depth += 1;
+                let (contract, function) = frame_target(&event.to_string());
+                ch.pause("call", &function, &contract, Vec::new())
+            }
+            TraceEvent::PopCtx(_, _) => {
+                let resume = ch.pause("return", "", "", Vec::new());
+                ch.depth = ch.depth.saturating_sub(1);
+                resume
+            }
+            TraceEvent::EnvCall(name, args) => {
+                let args = args.iter().map(|a| format!("{:?}", a)).collect();
+                ch.pause("host_fn", name, "", args)
+            }
+            _ => true,
+        };
+        if resume {
+            Ok(())
+        } else {
+            Err(aborted())
+        }
+    })))
+    .map_err(|e| format!("failed to install step hook: {:?}", e))?;
+
+    Ok(true)
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_parse_fds() {
+        assert_eq!(parse_fds("3,4"), Some((3, 4)));
+        assert_eq!(parse_fds(" 3 , 4 "), Some((3, 4)));
+        assert_eq!(parse_fds("3"), None);
+        assert_eq!(parse_fds("a,4"), None);
+    }
+
+    #[test]
+    fn test_frame_target() {
+        assert_eq!(
+            frame_target("push VM:d7a4c1b2:transfer(Address(obj#3), I128(10))"),
+            ("d7a4c1b2".to_string(), "transfer".to_string())
+        );
+        assert_eq!(
+            frame_target("push SAC:0badf00d:balance()"),
+            ("0badf00d".to_string(), "balance".to_string())
+        );
+        assert_eq!(
+            frame_target("push HostFunction(InvokeContract)"),
+            (String::new(), String::new())
+        );
+    }
+}
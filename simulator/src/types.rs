@@ -17,6 +17,12 @@ pub struct SimulationRequest {
     pub wasm_path: Option<String>, // Added for local loading
     pub enable_optimization_advisor: bool,
     pub profile: Option<bool>,
+    /// Attribute each frame's memory to host allocations and linear memory
+    pub profile_memory: Option<bool>,
+    /// Replace the host's CPU instruction budget
+    pub cpu_limit: Option<u64>,
+    /// Replace the host's memory budget, in bytes
+    pub mem_limit: Option<u64>,
     /// RFC 3339 timestamp supplied by the caller.  Preserved for future use
     /// (e.g. time-locked contract logic); not yet consumed by the simulator.
     #[allow(dead_code)]
@@ -43,8 +49,23 @@ pub struct SimulationResponse {
     pub categorized_events: Vec<CategorizedEvent>,
     pub logs: Vec<String>,
     pub flamegraph: Option<String>,
+    /// Folded stacks ("frame;frame weight" per line) behind the flamegraph
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub folded_stacks: Option<String>,
     pub optimization_report: Option<OptimizationReport>,
     pub budget_usage: Option<BudgetUsage>,
+    /// Base64 XDR ScVal returned by each invoked host function
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    pub return_values: Vec<String>,
+    /// Post-execution state of every entry in the read-write footprint
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    pub storage_writes: Vec<StorageWrite>,
+    /// Every ledger key the host accessed and how
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    pub storage_accesses: Vec<StorageAccess>,
+    /// Budget consumed by each frame, in the order frames were pushed
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    pub call_budgets: Vec<FrameBudget>,
     #[serde(skip_serializing_if = "Option::is_none")]
     pub source_location: Option<String>,
     #[serde(skip_serializing_if = "Option::is_none")]
@@ -58,6 +79,10 @@ pub struct DiagnosticEvent {
     pub contract_id: Option<String>,
     pub topics: Vec<String>,
     pub data: String,
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    pub topics_xdr: Vec<String>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub data_xdr: Option<String>,
     pub in_successful_contract_call: bool,
 }
 
@@ -67,6 +92,49 @@ pub struct CategorizedEvent {
     pub event: DiagnosticEvent,
 }
 
+#[derive(Debug, Serialize)]
+pub struct StorageWrite {
+    /// Base64 XDR LedgerKey
+    pub key: String,
+    /// Base64 XDR LedgerEntry, absent when the entry was deleted
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub entry: Option<String>,
+}
+
+#[derive(Debug, Serialize)]
+pub struct StorageAccess {
+    /// Base64 XDR LedgerKey
+    pub key: String,
+    pub read_write: bool,
+}
+
+#[derive(Debug, Clone, Serialize)]
+pub struct FrameBudget {
+    /// Nesting depth, 0 for the outermost frame
+    pub depth: usize,
+    /// CPU instructions consumed by the frame, sub-calls included
+    pub cpu_insns: u64,
+    /// Memory bytes consumed by the frame, sub-calls included
+    pub mem_bytes: u64,
+    /// Host functions the frame called itself, sub-calls excluded
+    pub host_fn_calls: u64,
+    /// Memory allocated by each host function the frame called, when
+    /// memory profiling is enabled
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    pub host_allocs: Vec<HostAlloc>,
+    /// Memory the frame consumed outside host calls (linear memory growth
+    /// and VM instantiation), when memory profiling is enabled
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub linear_mem_bytes: Option<u64>,
+}
+
+#[derive(Debug, Clone, Serialize)]
+pub struct HostAlloc {
+    pub name: String,
+    pub calls: u64,
+    pub mem_bytes: u64,
+}
+
 #[derive(Debug, Serialize)]
 pub struct BudgetUsage {
     pub cpu_instructions: u64,